@@ -1,6 +1,7 @@
 package cli
 
 import (
+	"context"
 	"fmt"
 	"io/ioutil"
 	"net/http"
@@ -49,7 +50,7 @@ func runAnalyzers(v *viper.Viper, bundlePath string) error {
 		specContent = string(body)
 	}
 
-	analyzeResults, err := analyzer.DownloadAndAnalyze(bundlePath, specContent)
+	analyzeResults, err := analyzer.AnalyzeBundle(context.Background(), bundlePath, specContent)
 	if err != nil {
 		return errors.Wrap(err, "failed to download and analyze bundle")
 	}
@@ -0,0 +1,172 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/troubleshoot/internal/specs"
+	"github.com/replicatedhq/troubleshoot/pkg/constants"
+	"github.com/replicatedhq/troubleshoot/pkg/k8sutil"
+	"github.com/replicatedhq/troubleshoot/pkg/logger"
+	"github.com/replicatedhq/troubleshoot/pkg/preflight"
+	"github.com/replicatedhq/troubleshoot/pkg/registry"
+	"github.com/replicatedhq/troubleshoot/pkg/types"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"k8s.io/client-go/kubernetes"
+)
+
+func AirgapCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "airgap [urls...]",
+		Args:  cobra.MinimumNArgs(1),
+		Short: "Check that preflight specs can run in an air gapped environment, without running them",
+		Long: `Check the air gap readiness of the specs, without running any of their collectors:
+
+  - every image the collectors run and the analyzers pull resolves by digest in the private registry
+  - no collector connects to a host outside the air gap
+  - the uri, uploadResultsTo and notifications of the specs, which fetch updates of the specs and send
+    results, are disabled or point inside the air gap
+
+Private and loopback addresses, names without a domain and cluster domains such as .svc are inside
+the air gap, as well as the private registry and the hosts of --allowed-hosts. The uri of the specs is
+not followed.
+
+The command exits with a non-zero code when the specs are not ready.`,
+		Example: `  # check the specs against a mirror of the images
+  preflight airgap preflight.yaml --registry registry.internal:5000/mirror
+
+  # allow the hosts of a private domain and print the report as JSON
+  preflight airgap preflight.yaml --registry registry.internal:5000 --allowed-hosts .corp.example.com --format json`,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			v := viper.GetViper()
+			v.SetEnvKeyReplacer(strings.NewReplacer("-", "_"))
+			if err := v.BindPFlags(cmd.Flags()); err != nil {
+				return err
+			}
+
+			logger.SetupLogger(v)
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			v := viper.GetViper()
+
+			format := v.GetString("format")
+			if format != "human" && format != "json" {
+				return errors.Errorf("unsupported format %q, must be human or json", format)
+			}
+			if v.GetString("registry") == "" {
+				return errors.New("--registry is required")
+			}
+
+			restConfig, err := k8sutil.GetRESTConfig()
+			if err != nil {
+				return errors.Wrap(err, "failed to convert kube flags to rest config")
+			}
+
+			client, err := kubernetes.NewForConfig(restConfig)
+			if err != nil {
+				return errors.Wrap(err, "failed to create kubernetes client")
+			}
+
+			// the uri of the specs is not followed, an air gapped host could not fetch it
+			ctx := context.Background()
+			kinds, err := specs.LoadFromCLIArgs(ctx, client, args, v)
+			if err != nil {
+				return types.NewExitCodeError(constants.EXIT_CODE_SPEC_ISSUES, err)
+			}
+
+			var registryAuth *registry.AuthConfig
+			if v.GetString("registry-username") != "" {
+				registryAuth = &registry.AuthConfig{
+					Username: v.GetString("registry-username"),
+					Password: v.GetString("registry-password"),
+				}
+			}
+
+			report, err := preflight.CheckAirgap(ctx, kinds, preflight.AirgapOptions{
+				Registry:       v.GetString("registry"),
+				RegistryAuth:   registryAuth,
+				AllowedHosts:   v.GetStringSlice("allowed-hosts"),
+				CollectorImage: v.GetString("collector-image"),
+			})
+			if err != nil {
+				return types.NewExitCodeError(constants.EXIT_CODE_CATCH_ALL, err)
+			}
+
+			if format == "json" {
+				b, err := json.MarshalIndent(report, "", "  ")
+				if err != nil {
+					return errors.Wrap(err, "failed to marshal air gap report")
+				}
+				fmt.Println(string(b))
+			} else if err := printAirgapReport(os.Stdout, report); err != nil {
+				return err
+			}
+
+			if !report.Ready {
+				return types.NewExitCodeError(constants.EXIT_CODE_FAIL, errors.New("specs are not air gap ready"))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().String("registry", "", "private registry the images are mirrored to, with an optional namespace, e.g. registry.internal:5000/mirror")
+	cmd.Flags().String("registry-username", "", "username of the private registry")
+	cmd.Flags().String("registry-password", "", "password of the private registry, also read from the PREFLIGHT_REGISTRY_PASSWORD environment variable")
+	cmd.Flags().StringSlice("allowed-hosts", nil, "hosts inside the air gap besides private addresses and cluster domains, a leading dot allows the subdomains of a domain")
+
+	k8sutil.AddFlags(cmd.Flags())
+
+	// Initialize klog flags
+	logger.InitKlogFlags(cmd)
+
+	return cmd
+}
+
+func printAirgapReport(w io.Writer, report *preflight.AirgapReport) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(tw, "SOURCE\tIMAGE\tMIRROR\tSTATUS")
+	for _, image := range report.Images {
+		status := image.Digest
+		if image.Error != "" {
+			status = fmt.Sprintf("unresolved: %s", image.Error)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", image.Source, image.Image, image.Mirror, status)
+	}
+
+	fmt.Fprintln(tw, "\nCOLLECTOR\tHOST\tSTATUS")
+	for _, egress := range report.Egress {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", egress.Collector, egress.Host, airgapStatus(egress.External))
+	}
+
+	fmt.Fprintln(tw, "\nSPEC\tSETTING\tURL\tSTATUS")
+	for _, endpoint := range report.Endpoints {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", endpoint.Spec, endpoint.Setting, endpoint.URL, airgapStatus(endpoint.External))
+	}
+
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	if report.Ready {
+		fmt.Fprintf(w, "\nThe specs are air gap ready with registry %s.\n", report.Registry)
+	} else {
+		fmt.Fprintf(w, "\nThe specs are not air gap ready with registry %s.\n", report.Registry)
+	}
+	return nil
+}
+
+func airgapStatus(external bool) string {
+	if external {
+		return "external"
+	}
+	return "internal"
+}
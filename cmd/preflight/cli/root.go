@@ -11,6 +11,7 @@ import (
 	"github.com/replicatedhq/troubleshoot/pkg/constants"
 	"github.com/replicatedhq/troubleshoot/pkg/k8sutil"
 	"github.com/replicatedhq/troubleshoot/pkg/logger"
+	"github.com/replicatedhq/troubleshoot/pkg/notify"
 	"github.com/replicatedhq/troubleshoot/pkg/preflight"
 	"github.com/replicatedhq/troubleshoot/pkg/types"
 	"github.com/spf13/cobra"
@@ -24,7 +25,14 @@ func RootCmd() *cobra.Command {
 		Args:  cobra.MinimumNArgs(1),
 		Short: "Run and retrieve preflight checks in a cluster",
 		Long: `A preflight check is a set of validations that can and should be run to ensure
-that a cluster meets the requirements to run an application.`,
+that a cluster meets the requirements to run an application.
+
+In interactive mode, the checks are shown as they run, and Ctrl-C skips the one
+running, or exits when pressed twice. The checks that failed can be run again
+from the results once they are remediated.
+
+The exit code is 0 when all of the checks pass, 3 when some fail, 4 when some
+only warn, 2 when the specs are not valid, and 1 for other errors.`,
 		SilenceUsage:  true,
 		SilenceErrors: true,
 		PreRun: func(cmd *cobra.Command, args []string) {
@@ -40,7 +48,7 @@ that a cluster meets the requirements to run an application.`,
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			v := viper.GetViper()
-			closer, err := traces.ConfigureTracing("preflight")
+			closer, err := traces.ConfigureTracing("preflight", v.GetString("otlp-endpoint"))
 			if err != nil {
 				// Do not fail running preflights if tracing fails
 				klog.Errorf("Failed to initialize open tracing provider: %v", err)
@@ -66,14 +74,18 @@ that a cluster meets the requirements to run an application.`,
 
 	cmd.AddCommand(util.VersionCmd())
 	cmd.AddCommand(OciFetchCmd())
+	cmd.AddCommand(AirgapCmd())
 	preflight.AddFlags(cmd.PersistentFlags())
 
 	// Dry run flag should be in cmd.PersistentFlags() flags made available to all subcommands
 	// Adding here to avoid that
 	cmd.Flags().Bool("dry-run", false, "print the preflight spec without running preflight checks")
 	cmd.Flags().Bool("no-uri", false, "When this flag is used, Preflight does not attempt to retrieve the spec referenced by the uri: field`")
+	cmd.Flags().String("otlp-endpoint", "", "export the traces of the collection and analysis to this OTLP/HTTP collector, e.g. http://localhost:4318")
+	cmd.Flags().Bool("embed-traces", false, "save the traces of the collection and analysis in the preflight bundle, to profile slow collections")
 
 	k8sutil.AddFlags(cmd.Flags())
+	notify.AddFlags(cmd.Flags())
 
 	// Initialize klog flags
 	logger.InitKlogFlags(cmd)
@@ -1,6 +1,7 @@
 package cli
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
@@ -17,27 +18,65 @@ import (
 
 func Analyze() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "analyze [url]",
-		Args:  cobra.MinimumNArgs(1),
+		Use:   "analyze [urls...]",
+		Args:  cobra.ArbitraryArgs,
 		Short: "analyze a support bundle",
-		Long:  `Analyze a support bundle using the Analyzer definitions provided`,
+		Long: `Analyze a support bundle using the Analyzer definitions provided
+
+Analyzers are read from the Analyzer and SupportBundle specs in the [urls...] arguments and the
+--analyzers flag, which are file paths or http(s) URLs. They are run against the bundle that was
+already collected, so that new or improved analyzers can be used without collecting it again.
+When no specs are provided, the default analyzers are run.`,
+		Example: `  # re-run updated analyzers against a previously collected bundle
+  support-bundle analyze --bundle support-bundle.tar.gz --analyzers new-analyzers.yaml`,
 		PreRun: func(cmd *cobra.Command, args []string) {
 			viper.BindPFlags(cmd.Flags())
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			v := viper.GetViper()
 
-			specPath := args[0]
-			analyzerSpec, err := downloadAnalyzerSpec(specPath)
-			if err != nil {
-				return err
+			specs := []string{}
+			for _, specPath := range append(args, v.GetStringSlice("analyzers")...) {
+				analyzerSpec, err := downloadAnalyzerSpec(specPath)
+				if err != nil {
+					return err
+				}
+				specs = append(specs, analyzerSpec)
 			}
 
-			result, err := analyzer.DownloadAndAnalyze(v.GetString("bundle"), analyzerSpec)
+			interactive := v.GetBool("interactive")
+			if interactive {
+				if _, err := os.Stat(v.GetString("bundle")); err != nil {
+					return fmt.Errorf("interactive mode requires a local support bundle archive: %w", err)
+				}
+			}
+
+			result, err := analyzer.AnalyzeBundle(context.Background(), v.GetString("bundle"), specs...)
 			if err != nil {
 				return err
 			}
 
+			if interactive {
+				return showAnalysisBrowser(v.GetString("bundle"), result)
+			}
+
+			switch v.GetString("output") {
+			case "junit":
+				formatted, err := convert.ToJUnit("support-bundle", result)
+				if err != nil {
+					return err
+				}
+				fmt.Printf("%s", formatted)
+				return nil
+			case "sarif":
+				formatted, err := convert.ToSARIF("support-bundle", result)
+				if err != nil {
+					return err
+				}
+				fmt.Printf("%s", formatted)
+				return nil
+			}
+
 			var data interface{}
 			switch v.GetString("compatibility") {
 			case "support-bundle":
@@ -65,9 +104,11 @@ func Analyze() *cobra.Command {
 		},
 	}
 
-	cmd.Flags().String("bundle", "", "filename of the support bundle to analyze")
+	cmd.Flags().String("bundle", "", "filename of the support bundle archive or directory to analyze")
 	cmd.MarkFlagRequired("bundle")
-	cmd.Flags().String("output", "", "output format: json, yaml")
+	cmd.Flags().StringSlice("analyzers", []string{}, "filenames or urls of the analyzer specs to use, the default analyzers are used when none are provided")
+	cmd.Flags().String("output", "", "output format: json, yaml, junit, sarif")
+	cmd.Flags().Bool("interactive", false, "browse the analysis results and the files in the bundle in a terminal UI")
 	cmd.Flags().String("compatibility", "", "output compatibility mode: support-bundle")
 	cmd.Flags().MarkHidden("compatibility")
 	cmd.Flags().Bool("quiet", false, "enable/disable error messaging and only show parseable output")
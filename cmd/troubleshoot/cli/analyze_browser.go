@@ -0,0 +1,335 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	ui "github.com/replicatedhq/termui/v3"
+	"github.com/replicatedhq/termui/v3/widgets"
+	analyzerunner "github.com/replicatedhq/troubleshoot/pkg/analyze"
+	"github.com/replicatedhq/troubleshoot/pkg/supportbundle"
+)
+
+// maxBrowserMatches keeps a search for a common word from filling the terminal with millions of rows.
+const maxBrowserMatches = 10000
+
+// browserRegexpChars are the characters that make a search a regular expression rather than words.
+// Dots are left out, so that names such as kube-proxy.log or 10.0.0.1 are looked up as words.
+const browserRegexpChars = `\^$*+?()[]{}|`
+
+type browserViewKind int
+
+const (
+	browserViewResults browserViewKind = iota
+	browserViewFiles
+	browserViewFile
+	browserViewMatches
+)
+
+type browserView struct {
+	kind    browserViewKind
+	title   string
+	files   []string
+	file    string
+	matches []supportbundle.GrepMatch
+	list    *widgets.List
+}
+
+// bundleBrowser is a terminal UI to browse analyzer results, the files in the bundle and search
+// within them. Views are kept on a stack so that escape returns to the previous one.
+type bundleBrowser struct {
+	bundle  *supportbundle.BundleBrowser
+	results []*analyzerunner.AnalyzeResult
+	views   []*browserView
+
+	prompting bool
+	query     string
+	status    string
+}
+
+func showAnalysisBrowser(archivePath string, analyzeResults []*analyzerunner.AnalyzeResult) error {
+	bundle, err := supportbundle.NewBundleBrowser(archivePath)
+	if err != nil {
+		return errors.Wrap(err, "failed to read support bundle")
+	}
+
+	if err := ui.Init(); err != nil {
+		return errors.Wrap(err, "failed to create terminal ui")
+	}
+	defer ui.Close()
+
+	b := &bundleBrowser{
+		bundle:  bundle,
+		results: analyzeResults,
+	}
+	b.push(b.resultsView())
+	b.draw()
+
+	for e := range ui.PollEvents() {
+		if e.Type != ui.KeyboardEvent && e.ID != "<Resize>" {
+			continue
+		}
+		if quit := b.handle(e.ID); quit {
+			return nil
+		}
+		ui.Clear()
+		b.draw()
+	}
+
+	return nil
+}
+
+func (b *bundleBrowser) handle(key string) bool {
+	if key == "<C-c>" {
+		return true
+	}
+
+	if b.prompting {
+		b.handlePrompt(key)
+		return false
+	}
+
+	current := b.current()
+	switch key {
+	case "q":
+		return true
+	case "<Escape>", "<Backspace>", "<C-<Backspace>>":
+		if len(b.views) > 1 {
+			b.views = b.views[:len(b.views)-1]
+		}
+	case "<Down>", "j":
+		current.list.ScrollDown()
+	case "<Up>", "k":
+		current.list.ScrollUp()
+	case "<PageDown>", "<C-d>":
+		current.list.ScrollPageDown()
+	case "<PageUp>", "<C-u>":
+		current.list.ScrollPageUp()
+	case "g", "<Home>":
+		current.list.ScrollTop()
+	case "G", "<End>":
+		current.list.ScrollBottom()
+	case "f":
+		b.push(b.filesView("All files", b.bundle.Files()))
+	case "/":
+		b.prompting = true
+		b.query = ""
+	case "<Enter>":
+		b.open(current)
+	}
+
+	return false
+}
+
+func (b *bundleBrowser) handlePrompt(key string) {
+	switch key {
+	case "<Escape>":
+		b.prompting = false
+	case "<Enter>":
+		b.prompting = false
+		b.search(b.query)
+	case "<Backspace>", "<C-<Backspace>>":
+		if len(b.query) > 0 {
+			b.query = b.query[:len(b.query)-1]
+		}
+	case "<Space>":
+		b.query += " "
+	default:
+		// special keys are reported as <Name>, everything else is typed text
+		if !strings.HasPrefix(key, "<") || len(key) == 1 {
+			b.query += key
+		}
+	}
+}
+
+// open drills into the selected row of the current view.
+func (b *bundleBrowser) open(current *browserView) {
+	row := current.list.SelectedRow
+	if row < 0 {
+		return
+	}
+
+	switch current.kind {
+	case browserViewResults:
+		if row < len(b.results) {
+			result := b.results[row]
+			b.push(b.filesView(fmt.Sprintf("Files for %s", result.Title), b.bundle.RelatedFiles(result)))
+		}
+	case browserViewFiles:
+		if row < len(current.files) {
+			b.push(b.fileView(current.files[row], 0))
+		}
+	case browserViewMatches:
+		if row < len(current.matches) {
+			match := current.matches[row]
+			b.push(b.fileView(match.File, match.Line-1))
+		}
+	}
+}
+
+func (b *bundleBrowser) search(query string) {
+	if query == "" {
+		return
+	}
+
+	// searching from a file only searches that file
+	filesPattern := ""
+	if current := b.current(); current.kind == browserViewFile {
+		filesPattern = current.file
+	}
+
+	// words are looked up in the index of the bundle, regular expressions and searches of a file
+	// read the files
+	var matches []supportbundle.GrepMatch
+	var err error
+	if filesPattern == "" && !strings.ContainsAny(query, browserRegexpChars) {
+		matches, err = b.bundle.Search(query)
+	} else {
+		matches, err = b.bundle.Grep(query, filesPattern)
+	}
+	if err != nil {
+		b.status = err.Error()
+		return
+	}
+	b.status = fmt.Sprintf("%d matches for %q", len(matches), query)
+	if len(matches) > maxBrowserMatches {
+		b.status = fmt.Sprintf("%s, showing the first %d", b.status, maxBrowserMatches)
+		matches = matches[:maxBrowserMatches]
+	}
+
+	b.push(b.matchesView(fmt.Sprintf("Matches for %q", query), matches))
+}
+
+func (b *bundleBrowser) push(view *browserView) {
+	b.views = append(b.views, view)
+}
+
+func (b *bundleBrowser) current() *browserView {
+	return b.views[len(b.views)-1]
+}
+
+func (b *bundleBrowser) resultsView() *browserView {
+	rows := []string{}
+	for _, result := range b.results {
+		switch {
+		case result.IsPass:
+			rows = append(rows, fmt.Sprintf("[✔  %s](fg:green)", escapeBrowserText(result.Title)))
+		case result.IsWarn:
+			rows = append(rows, fmt.Sprintf("[⚠️  %s](fg:yellow)", escapeBrowserText(result.Title)))
+		case result.IsFail:
+			rows = append(rows, fmt.Sprintf("[✘  %s](fg:red)", escapeBrowserText(result.Title)))
+		default:
+			rows = append(rows, escapeBrowserText(result.Title))
+		}
+	}
+	return &browserView{kind: browserViewResults, title: "Analyzer results", list: newBrowserList(rows)}
+}
+
+func (b *bundleBrowser) filesView(title string, files []string) *browserView {
+	rows := []string{}
+	for _, file := range files {
+		rows = append(rows, escapeBrowserText(file))
+	}
+	return &browserView{kind: browserViewFiles, title: title, files: files, list: newBrowserList(rows)}
+}
+
+func (b *bundleBrowser) fileView(file string, line int) *browserView {
+	content, err := b.bundle.ReadFile(file)
+	if err != nil {
+		b.status = err.Error()
+	}
+
+	rows := []string{}
+	for i, text := range strings.Split(strings.TrimSuffix(string(content), "\n"), "\n") {
+		rows = append(rows, fmt.Sprintf("%5d  %s", i+1, escapeBrowserText(text)))
+	}
+
+	list := newBrowserList(rows)
+	if line > 0 && line < len(rows) {
+		list.SelectedRow = line
+	}
+	return &browserView{kind: browserViewFile, title: file, file: file, list: list}
+}
+
+func (b *bundleBrowser) matchesView(title string, matches []supportbundle.GrepMatch) *browserView {
+	rows := []string{}
+	for _, match := range matches {
+		rows = append(rows, fmt.Sprintf("%s:%d: %s", escapeBrowserText(match.File), match.Line, escapeBrowserText(strings.TrimSpace(match.Text))))
+	}
+	return &browserView{kind: browserViewMatches, title: title, matches: matches, list: newBrowserList(rows)}
+}
+
+func newBrowserList(rows []string) *widgets.List {
+	list := widgets.NewList()
+	list.Rows = rows
+	list.WrapText = false
+	list.SelectedRowStyle = ui.NewStyle(ui.ColorClear, ui.ColorClear, ui.ModifierReverse)
+	return list
+}
+
+// escapeBrowserText keeps text from the bundle from being parsed as termui style markup.
+func escapeBrowserText(text string) string {
+	return strings.NewReplacer("[", "(", "]", ")").Replace(text)
+}
+
+func (b *bundleBrowser) draw() {
+	termWidth, termHeight := ui.TerminalDimensions()
+	current := b.current()
+
+	current.list.Title = current.title
+	if current.kind == browserViewResults {
+		current.list.SetRect(0, 0, termWidth/2, termHeight-2)
+		ui.Render(current.list)
+		b.drawResultDetails(termWidth, termHeight)
+	} else {
+		current.list.SetRect(0, 0, termWidth, termHeight-2)
+		ui.Render(current.list)
+	}
+
+	status := widgets.NewParagraph()
+	status.Border = false
+	status.WrapText = false
+	status.Text = b.status
+	status.SetRect(0, termHeight-2, termWidth, termHeight-1)
+
+	footer := widgets.NewParagraph()
+	footer.Border = false
+	footer.WrapText = false
+	if b.prompting {
+		footer.Text = fmt.Sprintf("search: %s_", escapeBrowserText(b.query))
+	} else {
+		footer.Text = "[q] quit    [↑][↓] scroll    [enter] open    [esc] back    [f] files    [/] search"
+	}
+	footer.SetRect(0, termHeight-1, termWidth, termHeight)
+
+	ui.Render(status, footer)
+}
+
+func (b *bundleBrowser) drawResultDetails(termWidth int, termHeight int) {
+	row := b.current().list.SelectedRow
+	if row < 0 || row >= len(b.results) {
+		return
+	}
+	result := b.results[row]
+
+	lines := []string{result.Title, "", result.Message}
+	if result.URI != "" {
+		lines = append(lines, "", fmt.Sprintf("For more information: %s", result.URI))
+	}
+	if result.Documentation != "" {
+		lines = append(lines, "", strings.TrimSpace(result.Documentation))
+	}
+	if remediation := analyzerunner.RemediationLines(result.Remediation); len(remediation) > 0 {
+		lines = append(append(lines, ""), remediation...)
+	}
+	if obj := result.InvolvedObject; obj != nil {
+		lines = append(lines, "", fmt.Sprintf("Involved object: %s %s/%s", obj.Kind, obj.Namespace, obj.Name))
+	}
+
+	details := widgets.NewParagraph()
+	details.Title = "Details"
+	details.Text = escapeBrowserText(strings.Join(lines, "\n"))
+	details.SetRect(termWidth/2, 0, termWidth, termHeight-2)
+	ui.Render(details)
+}
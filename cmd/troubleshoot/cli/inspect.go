@@ -0,0 +1,113 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/troubleshoot/pkg/supportbundle"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+func Inspect() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "inspect [bundle] [query]",
+		Args:  cobra.RangeArgs(1, 2),
+		Short: "Query the contents of a support bundle archive",
+		Long: `Index the cluster resources and pod logs of a support bundle archive into tables, and query them
+without having to know where they are stored in the bundle. Without a query, the tables and their
+columns are listed.
+
+Queries have the form:
+
+  <table> [where <column> <op> <value> [and ...]] [select <column>,...] [sort <column> [desc]] [limit <n>]
+
+where op is one of = != ~ !~ < <= > >=. ~ and !~ match regular expressions, and the ordering
+operators compare numbers when both sides are numbers. Values with spaces or operator characters
+must be quoted.`,
+		Example: `  # show pods that are not ready
+  support-bundle inspect support-bundle.tar.gz "pods where ready = false"
+
+  # find errors in the logs of the api deployment
+  support-bundle inspect support-bundle.tar.gz "logs where deployment = api and text ~ (?i)error"
+
+  # list images by namespace
+  support-bundle inspect support-bundle.tar.gz "images sort namespace"`,
+		PreRun: func(cmd *cobra.Command, args []string) {
+			viper.BindPFlags(cmd.Flags())
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			v := viper.GetViper()
+
+			output := v.GetString("output")
+			if output != "table" && output != "json" {
+				return errors.Errorf("unsupported output format %q, must be table or json", output)
+			}
+
+			browser, err := supportbundle.NewBundleBrowser(args[0])
+			if err != nil {
+				return errors.Wrap(err, "failed to read support bundle")
+			}
+			index := supportbundle.NewBundleIndex(browser)
+
+			if len(args) == 1 {
+				return printInspectTables(os.Stdout, index.Tables(), output)
+			}
+
+			result, err := index.Query(args[1])
+			if err != nil {
+				return err
+			}
+			return printInspectResult(os.Stdout, result, output)
+		},
+	}
+
+	cmd.Flags().String("output", "table", "output format, one of table or json")
+
+	return cmd
+}
+
+func printInspectTables(w io.Writer, tables []supportbundle.InspectTable, output string) error {
+	if output == "json" {
+		return writeInspectJSON(w, tables)
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "TABLE\tCOLUMNS\tDESCRIPTION")
+	for _, table := range tables {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", table.Name, strings.Join(table.Columns, ","), table.Description)
+	}
+	return tw.Flush()
+}
+
+func printInspectResult(w io.Writer, result *supportbundle.InspectResult, output string) error {
+	if output == "json" {
+		rows := make([]map[string]string, 0, len(result.Rows))
+		for _, values := range result.Rows {
+			row := map[string]string{}
+			for i, column := range result.Columns {
+				row[column] = values[i]
+			}
+			rows = append(rows, row)
+		}
+		return writeInspectJSON(w, rows)
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.ToUpper(strings.Join(result.Columns, "\t")))
+	for _, values := range result.Rows {
+		fmt.Fprintln(tw, strings.Join(values, "\t"))
+	}
+	return tw.Flush()
+}
+
+func writeInspectJSON(w io.Writer, v interface{}) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
@@ -2,6 +2,7 @@ package cli
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/mitchellh/go-wordwrap"
 	"github.com/pkg/errors"
@@ -189,6 +190,17 @@ func drawDetails(analysisResult *analyzerunner.AnalyzeResult) {
 		urlText := wordwrap.WrapString(fmt.Sprintf("For more information: %s", analysisResult.URI), uint(termWidth/2-constants.MESSAGE_TEXT_PADDING))
 		message.Text = message.Text + "\n\n" + urlText
 	}
+	if analysisResult.Documentation != "" && !analysisResult.IsPass {
+		docText := wordwrap.WrapString(strings.TrimSpace(analysisResult.Documentation), uint(termWidth/2-constants.MESSAGE_TEXT_PADDING))
+		message.Text = message.Text + "\n\n" + docText
+	}
+	if remediation := analyzerunner.RemediationLines(analysisResult.Remediation); len(remediation) > 0 && !analysisResult.IsPass {
+		remediationText := ""
+		for _, line := range remediation {
+			remediationText = remediationText + "\n" + wordwrap.WrapString(line, uint(termWidth/2-constants.MESSAGE_TEXT_PADDING))
+		}
+		message.Text = message.Text + "\n" + remediationText
+	}
 	height = util.EstimateNumberOfLines(message.Text) + constants.MESSAGE_TEXT_LINES_MARGIN_TO_BOTTOM
 	message.Border = false
 	message.SetRect(termWidth/2, currentTop, termWidth, currentTop+height)
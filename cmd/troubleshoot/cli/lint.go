@@ -0,0 +1,92 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/troubleshoot/pkg/constants"
+	"github.com/replicatedhq/troubleshoot/pkg/lint"
+	"github.com/replicatedhq/troubleshoot/pkg/types"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+func Lint() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "lint [files...]",
+		Args:  cobra.MinimumNArgs(1),
+		Short: "Check specs for mistakes without collecting anything",
+		Long: `Check support bundle, preflight and redactor specs for mistakes that loading them does not catch:
+
+  - unknown-field: fields the specs do not have, which are ignored
+  - missing-collector: analyzers reading from a collector name the spec does not have
+  - unreachable-outcome: outcomes after an outcome without a when clause, or with the same one
+  - deprecated: deprecated API versions and collectors
+  - missing-redactor: collectors of command output, files or secret values when no redactor is given
+  - invalid-spec: documents that cannot be decoded
+
+Use "-" to read the specs from stdin. Findings with the error severity make the command exit with
+a non-zero code.`,
+		Example: `  # lint a spec and its redactors
+  support-bundle lint support-bundle.yaml redactors.yaml
+
+  # print the findings as json
+  support-bundle lint support-bundle.yaml --output json`,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return viper.BindPFlags(cmd.Flags())
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			v := viper.GetViper()
+
+			output := v.GetString("output")
+			if output != "text" && output != "json" {
+				return errors.Errorf("unsupported output format %q, must be text or json", output)
+			}
+
+			sources := []lint.Source{}
+			for _, arg := range args {
+				var b []byte
+				var err error
+				if arg == "-" {
+					b, err = io.ReadAll(os.Stdin)
+				} else {
+					b, err = os.ReadFile(arg)
+				}
+				if err != nil {
+					return types.NewExitCodeError(constants.EXIT_CODE_SPEC_ISSUES, errors.Wrapf(err, "failed to read %s", arg))
+				}
+				sources = append(sources, lint.Source{Name: arg, Content: string(b)})
+			}
+
+			findings := lint.Lint(sources...)
+			if output == "json" {
+				if err := writeInspectJSON(os.Stdout, findings); err != nil {
+					return err
+				}
+			} else {
+				printLintFindings(os.Stdout, findings)
+			}
+
+			if lint.HasErrors(findings) {
+				return types.NewExitCodeError(constants.EXIT_CODE_SPEC_ISSUES, errors.New("the specs have errors"))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().String("output", "text", "output format, one of text or json")
+
+	return cmd
+}
+
+func printLintFindings(w io.Writer, findings []lint.Finding) {
+	if len(findings) == 0 {
+		fmt.Fprintln(w, "No problems found")
+		return
+	}
+	for _, finding := range findings {
+		fmt.Fprintln(w, finding.String())
+	}
+}
@@ -0,0 +1,103 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/troubleshoot/pkg/supportbundle"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+func Prune() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "prune [dir]",
+		Args:  cobra.ExactArgs(1),
+		Short: "Delete old support bundle archives from a directory",
+		Long: `Delete the support bundle archives in a directory that are past the retention limits, such as the
+bundles that accumulate on nodes or in the volume of a SupportBundleSchedule.
+
+Archives are considered from the most recent to the oldest, and one is deleted as soon as it is
+older than --max-age, past the --max-count most recent archives, or would bring the total size of
+the archives kept above --max-total-size. Only the .tar.gz and .tgz files directly in the directory
+are considered.`,
+		Example: `  # keep the 10 most recent bundles
+  support-bundle prune /var/lib/support-bundles --max-count 10
+
+  # list the bundles older than a week, or past 5Gi in total, without deleting them
+  support-bundle prune /var/lib/support-bundles --max-age 168h --max-total-size 5Gi --dry-run`,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return viper.BindPFlags(cmd.Flags())
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			v := viper.GetViper()
+
+			output := v.GetString("output")
+			if output != "table" && output != "json" {
+				return errors.Errorf("unsupported output format %q, must be table or json", output)
+			}
+
+			maxTotalSize, err := supportbundle.ParseRetentionSize(v.GetString("max-total-size"))
+			if err != nil {
+				return errors.Wrap(err, "invalid --max-total-size")
+			}
+
+			policy := supportbundle.RetentionPolicy{
+				MaxAge:       v.GetDuration("max-age"),
+				MaxCount:     v.GetInt("max-count"),
+				MaxTotalSize: maxTotalSize,
+				DryRun:       v.GetBool("dry-run"),
+			}
+			if !policy.IsLimited() {
+				return errors.New("at least one of --max-age, --max-count or --max-total-size is required")
+			}
+
+			pruned, err := supportbundle.Prune(args[0], policy)
+			if output == "json" {
+				if jsonErr := writeInspectJSON(os.Stdout, pruned); jsonErr != nil {
+					return jsonErr
+				}
+			} else if printErr := printPrunedBundles(os.Stdout, pruned, policy.DryRun); printErr != nil {
+				return printErr
+			}
+			return err
+		},
+	}
+
+	cmd.Flags().Duration("max-age", 0, "delete the archives older than this, e.g. 168h")
+	cmd.Flags().Int("max-count", 0, "number of most recent archives to keep")
+	cmd.Flags().String("max-total-size", "", "total size of the archives to keep, e.g. 10Gi. The most recent archive is always kept")
+	cmd.Flags().Bool("dry-run", false, "list the archives that would be deleted without deleting them")
+	cmd.Flags().String("output", "table", "output format, one of table or json")
+
+	return cmd
+}
+
+func printPrunedBundles(w io.Writer, pruned []supportbundle.BundleArchive, dryRun bool) error {
+	if len(pruned) == 0 {
+		_, err := fmt.Fprintln(w, "No support bundle archives to delete")
+		return err
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ARCHIVE\tBYTES\tMODIFIED")
+	var totalSize int64
+	for _, archive := range pruned {
+		fmt.Fprintf(tw, "%s\t%d\t%s\n", archive.Path, archive.Size, archive.ModTime.Format(time.RFC3339))
+		totalSize += archive.Size
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	verb := "Deleted"
+	if dryRun {
+		verb = "Would delete"
+	}
+	_, err := fmt.Fprintf(w, "\n%s %d support bundle archives (%d bytes)\n", verb, len(pruned), totalSize)
+	return err
+}
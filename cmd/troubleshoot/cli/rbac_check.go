@@ -0,0 +1,124 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/troubleshoot/pkg/k8sutil"
+	"github.com/replicatedhq/troubleshoot/pkg/supportbundle"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"k8s.io/client-go/kubernetes"
+)
+
+func RBACCheck() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rbac-check [urls...]",
+		Args:  cobra.MinimumNArgs(1),
+		Short: "Check the permissions the collectors of a spec need, without collecting anything",
+		Long: `Run the access reviews of every collector in the specs, as "support-bundle" does before it starts
+collecting, and report which collectors will be skipped for lack of permissions.
+
+With --output yaml, the permissions that are missing are printed as a Role for each namespace and a
+ClusterRole, which can be applied with kubectl and bound to the user collecting the bundle.`,
+		Example: `  # list the collectors that are missing permissions
+  support-bundle rbac-check support-bundle.yaml
+
+  # create the roles with the missing permissions
+  support-bundle rbac-check support-bundle.yaml --output yaml | kubectl apply -f -`,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return viper.BindPFlags(cmd.Flags())
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			v := viper.GetViper()
+
+			output := v.GetString("output")
+			if output != "table" && output != "yaml" && output != "json" {
+				return errors.Errorf("unsupported output format %q, must be table, yaml or json", output)
+			}
+
+			restConfig, err := k8sutil.GetRESTConfig()
+			if err != nil {
+				return errors.Wrap(err, "failed to convert kube flags to rest config")
+			}
+
+			client, err := kubernetes.NewForConfig(restConfig)
+			if err != nil {
+				return errors.Wrap(err, "failed to create kubernetes client")
+			}
+
+			ctx := context.Background()
+			mainBundle, _, err := loadSpecs(ctx, args, client)
+			if err != nil {
+				return err
+			}
+
+			report, err := supportbundle.CheckRBAC(ctx, &mainBundle.Spec, supportbundle.SupportBundleCreateOpts{
+				KubernetesRestConfig: restConfig,
+				Namespace:            v.GetString("namespace"),
+			})
+			if err != nil {
+				return err
+			}
+
+			switch output {
+			case "yaml":
+				b, err := report.RequiredRolesYAML(v.GetString("role-name"))
+				if err != nil {
+					return err
+				}
+				_, err = os.Stdout.Write(b)
+				return err
+			case "json":
+				return writeInspectJSON(os.Stdout, report)
+			}
+			return printRBACCheckReport(os.Stdout, report)
+		},
+	}
+
+	cmd.Flags().String("output", "table", "output format, one of table, yaml or json")
+	cmd.Flags().String("role-name", "troubleshoot-support-bundle", "name of the roles printed with --output yaml")
+
+	k8sutil.AddFlags(cmd.Flags())
+
+	return cmd
+}
+
+func printRBACCheckReport(w io.Writer, report *supportbundle.RBACCheckReport) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "COLLECTOR\tSTATUS\tMISSING")
+	for _, result := range report.Collectors {
+		status := "ok"
+		if result.Skipped {
+			status = fmt.Sprintf("skipped: %s", result.Reason)
+		} else if len(result.Missing) > 0 {
+			status = "partial"
+		}
+
+		missing := []string{}
+		for _, permission := range result.Missing {
+			resource := permission.Resource
+			if permission.Group != "" {
+				resource = resource + "." + permission.Group
+			}
+			if permission.Namespace != "" {
+				resource = permission.Namespace + "/" + resource
+			}
+			missing = append(missing, fmt.Sprintf("%s %s", permission.Verb, resource))
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", result.Collector, status, strings.Join(missing, ", "))
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	if report.HasMissingPermissions() {
+		fmt.Fprintln(w, "\nRun with --output yaml to print the roles with the missing permissions.")
+	}
+	return nil
+}
@@ -2,12 +2,15 @@ package cli
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"time"
 
 	"github.com/pkg/errors"
 	analyzer "github.com/replicatedhq/troubleshoot/pkg/analyze"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
 	"github.com/replicatedhq/troubleshoot/pkg/collect"
+	"github.com/replicatedhq/troubleshoot/pkg/redact"
 	"github.com/replicatedhq/troubleshoot/pkg/supportbundle"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -26,6 +29,10 @@ be stored in the current directory in the path provided by the --output flag.
 
 The [urls...] argument is a list of either oci://.., http://.., https://.. or local paths to yaml files.
 
+With --dry-run, nothing is written and a diff of each file that would be redacted is printed instead.
+Redactors can be tried on a directory of sample files with --input, rather than on a bundle. The paths
+of the files relative to the directory are what the file selectors of the redactors match.
+
 For more information on redactors visit https://troubleshoot.sh/docs/redact/
 		`,
 		PreRunE: func(cmd *cobra.Command, args []string) error {
@@ -40,13 +47,28 @@ For more information on redactors visit https://troubleshoot.sh/docs/redact/
 				return err
 			}
 
+			bundle, input := v.GetString("bundle"), v.GetString("input")
+			if (bundle == "") == (input == "") {
+				return errors.New("exactly one of --bundle or --input is required")
+			}
+			if input != "" {
+				if !v.GetBool("dry-run") {
+					return errors.New("--input is only supported with --dry-run")
+				}
+				return previewRedactions(os.Stdout, input, redactors)
+			}
+
 			// 2. Download the bundle and extract it
-			tmpDir, bundleDir, err := analyzer.DownloadAndExtractSupportBundle(v.GetString("bundle"))
+			tmpDir, bundleDir, err := analyzer.DownloadAndExtractSupportBundle(bundle)
 			if err != nil {
 				return err
 			}
 			defer os.RemoveAll(tmpDir)
 
+			if v.GetBool("dry-run") {
+				return previewRedactions(os.Stdout, bundleDir, redactors)
+			}
+
 			// 3. Represent bundle as a CollectorResult
 			collectorResult, err := collect.CollectorResultFromBundle(bundleDir)
 			if err != nil {
@@ -74,9 +96,29 @@ For more information on redactors visit https://troubleshoot.sh/docs/redact/
 	}
 
 	cmd.Flags().String("bundle", "", "file path of the support bundle archive to redact")
-	cmd.MarkFlagRequired("bundle")
+	cmd.Flags().String("input", "", "directory, or file, of sample files to try the redactors on, requires --dry-run")
+	cmd.Flags().Bool("dry-run", false, "print what would be redacted as a diff, without writing anything")
 	cmd.Flags().BoolP("quiet", "q", false, "enable/disable error messaging and only show parseable output")
 	cmd.Flags().StringP("output", "o", "", "file path of where to save the redacted support bundle archive (default \"redacted-support-bundle-YYYY-MM-DDTHH_MM_SS.tar.gz\")")
 
 	return cmd
 }
+
+// previewRedactions prints the diff of each file under root that the redactors would change
+func previewRedactions(w io.Writer, root string, redactors []*troubleshootv1beta2.Redact) error {
+	previews, err := redact.PreviewDir(root, redactors)
+	if err != nil {
+		return errors.Wrap(err, "failed to preview redactions")
+	}
+
+	redacted := 0
+	for _, preview := range previews {
+		if !preview.IsRedacted() {
+			continue
+		}
+		redacted++
+		fmt.Fprintln(w, preview.Diff)
+	}
+	_, err = fmt.Fprintf(w, "%d of %d files would be redacted\n", redacted, len(previews))
+	return err
+}
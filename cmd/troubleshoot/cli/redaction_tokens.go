@@ -0,0 +1,128 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/troubleshoot/pkg/redact"
+	"github.com/replicatedhq/troubleshoot/pkg/supportbundle"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// redactionTokensPassphraseEnv is the environment variable the passphrase of the redaction tokens
+// is read from, so that it does not show in the arguments of the process
+const redactionTokensPassphraseEnv = "TROUBLESHOOT_REDACTION_TOKENS_PASSPHRASE"
+
+// RedactionToken is a redaction token and the value it replaced
+type RedactionToken struct {
+	Token string `json:"token"`
+	Value string `json:"value"`
+}
+
+func RedactionTokens() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "redaction-tokens [file] [tokens...]",
+		Args:  cobra.MinimumNArgs(1),
+		Short: "Look up the values replaced by redaction tokens",
+		Long: `Print the values that redaction tokens replaced in a support bundle collected with --redaction-tokens.
+
+The file written by --redaction-tokens is decrypted with the passphrase in the
+` + redactionTokensPassphraseEnv + ` environment variable. When tokens are given, only
+their values are printed, otherwise every token is. Tokens can be given with or without the
+surrounding asterisks, e.g. ***TOKEN_42*** or TOKEN_42.`,
+		Example: `  # collect a bundle with tokens instead of ***HIDDEN***, and keep the tokens file
+  export ` + redactionTokensPassphraseEnv + `='a long passphrase'
+  support-bundle spec.yaml --redaction-tokens ./redaction-tokens.json
+
+  # when the vendor asks what TOKEN_42 was
+  support-bundle redaction-tokens ./redaction-tokens.json TOKEN_42`,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return viper.BindPFlags(cmd.Flags())
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			v := viper.GetViper()
+
+			output := v.GetString("output")
+			if output != "table" && output != "json" {
+				return errors.Errorf("unsupported output format %q, must be table or json", output)
+			}
+
+			passphrase := os.Getenv(redactionTokensPassphraseEnv)
+			if passphrase == "" {
+				return errors.Errorf("%s must be set to the passphrase of the redaction tokens", redactionTokensPassphraseEnv)
+			}
+
+			mapping, err := supportbundle.ReadRedactionTokens(args[0], passphrase)
+			if err != nil {
+				return err
+			}
+
+			tokens, err := lookupRedactionTokens(mapping, args[1:])
+			if err != nil {
+				return err
+			}
+
+			if output == "json" {
+				return writeInspectJSON(os.Stdout, tokens)
+			}
+			return printRedactionTokens(os.Stdout, tokens)
+		},
+	}
+
+	cmd.Flags().String("output", "table", "output format, one of table or json")
+
+	return cmd
+}
+
+// lookupRedactionTokens returns the given tokens and their values, or all of them, sorted by
+// token number, when no tokens are given
+func lookupRedactionTokens(mapping map[string]string, names []string) ([]RedactionToken, error) {
+	tokens := []RedactionToken{}
+	if len(names) == 0 {
+		for token, value := range mapping {
+			tokens = append(tokens, RedactionToken{Token: token, Value: value})
+		}
+		sort.Slice(tokens, func(i, j int) bool {
+			return redactionTokenNumber(tokens[i].Token) < redactionTokenNumber(tokens[j].Token)
+		})
+		return tokens, nil
+	}
+
+	for _, name := range names {
+		token := name
+		if _, ok := mapping[token]; !ok {
+			token = "***" + name + "***"
+		}
+		value, ok := mapping[token]
+		if !ok {
+			return nil, errors.Errorf("token %s not found", name)
+		}
+		tokens = append(tokens, RedactionToken{Token: token, Value: value})
+	}
+	return tokens, nil
+}
+
+func redactionTokenNumber(token string) int {
+	var number int
+	fmt.Sscanf(token, redact.TOKEN_FORMAT, &number)
+	return number
+}
+
+func printRedactionTokens(w io.Writer, tokens []RedactionToken) error {
+	if len(tokens) == 0 {
+		_, err := fmt.Fprintln(w, "No values were redacted")
+		return err
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "TOKEN\tVALUE")
+	for _, token := range tokens {
+		fmt.Fprintf(tw, "%s\t%s\n", token.Token, token.Value)
+	}
+	return tw.Flush()
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/replicatedhq/troubleshoot/internal/traces"
 	"github.com/replicatedhq/troubleshoot/pkg/k8sutil"
 	"github.com/replicatedhq/troubleshoot/pkg/logger"
+	"github.com/replicatedhq/troubleshoot/pkg/notify"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	"k8s.io/klog/v2"
@@ -56,7 +57,7 @@ If no arguments are provided, specs are automatically loaded from the cluster by
 				}
 			}
 
-			closer, err := traces.ConfigureTracing("support-bundle")
+			closer, err := traces.ConfigureTracing("support-bundle", v.GetString("otlp-endpoint"))
 			if err != nil {
 				// Do not fail running support-bundle if tracing fails
 				klog.Errorf("Failed to initialize open tracing provider: %v", err)
@@ -82,11 +83,21 @@ If no arguments are provided, specs are automatically loaded from the cluster by
 
 	cmd.AddCommand(Analyze())
 	cmd.AddCommand(Redact())
+	cmd.AddCommand(RedactionTokens())
+	cmd.AddCommand(Inspect())
+	cmd.AddCommand(RBACCheck())
+	cmd.AddCommand(Lint())
+	cmd.AddCommand(Prune())
+	cmd.AddCommand(Verify())
+	cmd.AddCommand(Schedule())
+	cmd.AddCommand(Serve())
 	cmd.AddCommand(util.VersionCmd())
 
 	cmd.Flags().StringSlice("redactors", []string{}, "names of the additional redactors to use")
 	cmd.Flags().Bool("redact", true, "enable/disable default redactions")
+	cmd.Flags().String("redaction-tokens", "", "replace redacted values with stable tokens such as ***TOKEN_42***, and write the value of each token to this file, encrypted with the passphrase in "+redactionTokensPassphraseEnv+". The file is not part of the bundle, keep it to look tokens up later")
 	cmd.Flags().Bool("interactive", true, "enable/disable interactive mode")
+	cmd.Flags().Bool("approve-all", false, "run the commands and copy the files of the collectors without asking for confirmation. Required to run them when not interactive")
 	cmd.Flags().Bool("collect-without-permissions", true, "always generate a support bundle, even if it some require additional permissions")
 	cmd.Flags().StringSliceP("selector", "l", []string{"troubleshoot.sh/kind=support-bundle"}, "selector to filter on for loading additional support bundle specs found in secrets within the cluster")
 	cmd.Flags().Bool("load-cluster-specs", false, "enable/disable loading additional troubleshoot specs found within the cluster. Do not load by default unless no specs are provided in the cli args")
@@ -95,6 +106,14 @@ If no arguments are provided, specs are automatically loaded from the cluster by
 	cmd.Flags().StringP("output", "o", "", "specify the output file path for the support bundle")
 	cmd.Flags().Bool("debug", false, "enable debug logging. This is equivalent to --v=0")
 	cmd.Flags().Bool("dry-run", false, "print support bundle spec without collecting anything")
+	cmd.Flags().Bool("in-cluster", false, "collect from within a pod, with the credentials of its service account. The namespace defaults to POD_NAMESPACE or the namespace of the service account")
+	cmd.Flags().String("profile", "", "collection profile, full or metadataOnly, overriding the profile of the specs. metadataOnly replaces logs, command output and the values of configmaps and secrets with their size, line count and sha256")
+	cmd.Flags().String("upload-url", "", "upload the support bundle archive with a PUT request to this URL, such as a pre-signed object storage URL")
+	cmd.Flags().String("otlp-endpoint", "", "export the traces of the collection and analysis to this OTLP/HTTP collector, e.g. http://localhost:4318")
+	cmd.Flags().Bool("embed-traces", false, "save the traces of the collection and analysis in the support bundle, to profile slow collections")
+	cmd.Flags().Bool("per-file-compression", false, "archive the support bundle as a .tar of files compressed one by one, storing files that are compressed already as they are and compressing the others with zstd, to save time and space on log heavy clusters")
+	cmd.Flags().Bool("embed-profiles", false, "save CPU and heap profiles and a Go execution trace of the collection, redaction and analysis in the support bundle, to debug slow or memory hungry collections")
+	cmd.Flags().String("signing-key", "", "sign the manifest of the support bundle with this ed25519 private key, in PEM encoded PKCS #8 form, so that the bundle can be verified with its public key")
 
 	// hidden in favor of the `insecure-skip-tls-verify` flag
 	cmd.Flags().Bool("allow-insecure-connections", false, "when set, do not verify TLS certs when retrieving spec and reporting results")
@@ -105,6 +124,7 @@ If no arguments are provided, specs are automatically loaded from the cluster by
 	cmd.Flags().Bool("no-uri", false, "When this flag is used, Troubleshoot does not attempt to retrieve the spec referenced by the uri: field`")
 
 	k8sutil.AddFlags(cmd.Flags())
+	notify.AddFlags(cmd.Flags())
 
 	// Initialize klog flags
 	logger.InitKlogFlags(cmd)
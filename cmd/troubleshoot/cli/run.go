@@ -2,6 +2,7 @@ package cli
 
 import (
 	"context"
+	"crypto/ed25519"
 	"crypto/tls"
 	"encoding/json"
 	"fmt"
@@ -27,6 +28,7 @@ import (
 	"github.com/replicatedhq/troubleshoot/pkg/httputil"
 	"github.com/replicatedhq/troubleshoot/pkg/k8sutil"
 	"github.com/replicatedhq/troubleshoot/pkg/loader"
+	"github.com/replicatedhq/troubleshoot/pkg/notify"
 	"github.com/replicatedhq/troubleshoot/pkg/supportbundle"
 	"github.com/replicatedhq/troubleshoot/pkg/types"
 	"github.com/spf13/viper"
@@ -40,9 +42,18 @@ import (
 func runTroubleshoot(v *viper.Viper, args []string) error {
 	ctx := context.Background()
 
-	restConfig, err := k8sutil.GetRESTConfig()
+	inCluster := v.GetBool("in-cluster")
+	restConfig, err := getRESTConfig(inCluster)
 	if err != nil {
-		return errors.Wrap(err, "failed to convert kube flags to rest config")
+		return err
+	}
+
+	namespace := v.GetString("namespace")
+	if inCluster && namespace == "" {
+		namespace, err = k8sutil.GetInClusterNamespace()
+		if err != nil {
+			return err
+		}
 	}
 
 	client, err := kubernetes.NewForConfig(restConfig)
@@ -73,21 +84,61 @@ func runTroubleshoot(v *viper.Viper, args []string) error {
 		return nil
 	}
 
-	interactive := v.GetBool("interactive") && isatty.IsTerminal(os.Stdout.Fd())
+	if uploadURL := v.GetString("upload-url"); uploadURL != "" {
+		mainBundle.Spec.AfterCollection = append(mainBundle.Spec.AfterCollection, &troubleshootv1beta2.AfterCollection{
+			UploadResultsTo: &troubleshootv1beta2.ResultRequest{URI: uploadURL, Method: http.MethodPut},
+		})
+	}
+
+	if profile := v.GetString("profile"); profile != "" {
+		mainBundle.Spec.Profile = profile
+	}
+
+	notifications, err := notify.NotificationsFromFlags(v)
+	if err != nil {
+		return err
+	}
+	mainBundle.Spec.Notifications = append(mainBundle.Spec.Notifications, notifications...)
+
+	outputPath, err := getOutputPath(v.GetString("output"))
+	if err != nil {
+		return err
+	}
+
+	var signingKey ed25519.PrivateKey
+	if path := v.GetString("signing-key"); path != "" {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return errors.Wrap(err, "failed to read signing key")
+		}
+		if signingKey, err = supportbundle.ParseSigningKey(b); err != nil {
+			return err
+		}
+	}
+
+	interactive := v.GetBool("interactive") && !inCluster && isatty.IsTerminal(os.Stdout.Fd())
 
 	if interactive {
 		fmt.Print(cursor.Hide())
 		defer fmt.Print(cursor.Show())
 	}
 
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
 	go func() {
 		signalChan := make(chan os.Signal, 1)
 		signal.Notify(signalChan, os.Interrupt)
 		<-signalChan
+		// the collectors stop and delete the pods and other resources they created in the cluster,
+		// unless interrupted again
+		fmt.Fprintln(os.Stderr, "\nStopping the collection, press Ctrl-C again to exit without cleaning up")
+		cancel()
+		<-signalChan
 		if interactive {
 			fmt.Print(cursor.Show())
 		}
-		os.Exit(0)
+		os.Exit(1)
 	}()
 
 	var sinceTime *time.Time
@@ -120,6 +171,23 @@ func runTroubleshoot(v *viper.Viper, args []string) error {
 		}
 	}
 
+	operations := collect.SpecOperations(&mainBundle.Spec, namespace)
+	if err := collect.CheckAllowedOperations(&mainBundle.Spec, operations); err != nil {
+		return err
+	}
+	if len(operations) > 0 && !v.GetBool("approve-all") {
+		if !interactive {
+			return errors.Errorf("%sRun with --approve-all to approve them", collect.FormatOperations(operations))
+		}
+		fmt.Print(cursor.Show())
+		fmt.Print(collect.FormatOperations(operations))
+		if !util.PromptYesNo("Do you want to continue?") {
+			fmt.Println("Exiting...")
+			return nil
+		}
+		fmt.Print(cursor.Hide())
+	}
+
 	var wg sync.WaitGroup
 	collectorCB := func(c chan interface{}, msg string) { c <- msg }
 	progressChan := make(chan interface{})
@@ -178,18 +246,24 @@ func runTroubleshoot(v *viper.Viper, args []string) error {
 		CollectorProgressCallback: collectorCB,
 		CollectWithoutPermissions: v.GetBool("collect-without-permissions"),
 		KubernetesRestConfig:      restConfig,
-		Namespace:                 v.GetString("namespace"),
+		Namespace:                 namespace,
 		ProgressChan:              progressChan,
 		SinceTime:                 sinceTime,
-		OutputPath:                v.GetString("output"),
+		OutputPath:                outputPath,
 		Redact:                    v.GetBool("redact"),
 		FromCLI:                   true,
 		RunHostCollectorsInPod:    mainBundle.Spec.RunHostCollectorsInPod,
+		EmbedTraces:               v.GetBool("embed-traces"),
+		EmbedProfiles:             v.GetBool("embed-profiles"),
+		PerFileCompression:        v.GetBool("per-file-compression"),
+		RedactionTokensPath:       v.GetString("redaction-tokens"),
+		RedactionTokensPassphrase: os.Getenv(redactionTokensPassphraseEnv),
+		SigningKey:                signingKey,
 	}
 
 	nonInteractiveOutput := analysisOutput{}
 
-	response, err := supportbundle.CollectSupportBundleFromSpec(&mainBundle.Spec, additionalRedactors, createOpts)
+	response, err := supportbundle.CollectSupportBundleFromSpecWithContext(ctx, &mainBundle.Spec, additionalRedactors, createOpts)
 	if err != nil {
 		return errors.Wrap(err, "failed to run collect and analyze process")
 	}
@@ -197,6 +271,10 @@ func runTroubleshoot(v *viper.Viper, args []string) error {
 	close(progressChan) // this removes the spinner in interactive mode
 	isProgressChanClosed = true
 
+	if response.RedactionTokensPath != "" {
+		fmt.Fprintf(os.Stderr, "The redacted values were written to %s, encrypted. Keep this file and do not send it with the support bundle.\n", response.RedactionTokensPath)
+	}
+
 	if len(response.AnalyzerResults) > 0 {
 		if interactive {
 			if err := showInteractiveResults(mainBundle.Name, response.AnalyzerResults, response.ArchivePath); err != nil {
@@ -242,6 +320,34 @@ the %s Admin Console to begin analysis.`
 	return nil
 }
 
+// getRESTConfig returns the config of the service account of the pod in in-cluster mode, and the
+// config of the kube flags otherwise.
+func getRESTConfig(inCluster bool) (*rest.Config, error) {
+	if inCluster {
+		return k8sutil.GetInClusterRESTConfig()
+	}
+
+	restConfig, err := k8sutil.GetRESTConfig()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to convert kube flags to rest config")
+	}
+	return restConfig, nil
+}
+
+// getOutputPath returns the path of the bundle archive. When output is a directory, such as a
+// volume mounted in the pod of a Job, the bundle is written in it with its default name.
+func getOutputPath(output string) (string, error) {
+	if output == "" {
+		return "", nil
+	}
+
+	info, err := os.Stat(output)
+	if err != nil || !info.IsDir() {
+		return output, nil
+	}
+	return filepath.Join(output, fmt.Sprintf("support-bundle-%s", time.Now().Format("2006-01-02T15_04_05"))), nil
+}
+
 // loadSupportBundleSpecsFromURIs loads support bundle specs from URIs
 func loadSupportBundleSpecsFromURIs(ctx context.Context, kinds *loader.TroubleshootKinds) error {
 	moreKinds := loader.NewTroubleshootKinds()
@@ -435,3 +435,19 @@ func Test_loadInvalidURISpec(t *testing.T) {
 	assert.Len(t, sb.Spec.Collectors, 3)              // default + clusterInfo + clusterResources
 	assert.NotNil(t, sb.Spec.Collectors[0].ConfigMap) // come from the original spec
 }
+
+func Test_getOutputPath(t *testing.T) {
+	dir := t.TempDir()
+
+	got, err := getOutputPath("")
+	require.NoError(t, err)
+	assert.Equal(t, "", got)
+
+	got, err = getOutputPath(dir + "/bundle.tar.gz")
+	require.NoError(t, err)
+	assert.Equal(t, dir+"/bundle.tar.gz", got)
+
+	got, err = getOutputPath(dir)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(got, dir+"/support-bundle-"), got)
+}
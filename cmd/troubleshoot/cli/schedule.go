@@ -0,0 +1,127 @@
+package cli
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/troubleshoot/pkg/k8sutil"
+	"github.com/replicatedhq/troubleshoot/pkg/metrics"
+	"github.com/replicatedhq/troubleshoot/pkg/schedule"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func Schedule() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "schedule",
+		Short: "Collect support bundles on a schedule inside the cluster",
+		Long: `Collect support bundles on a cron schedule inside the cluster, as defined by SupportBundleSchedule resources.
+
+The controller creates a CronJob for each SupportBundleSchedule. Each job of the CronJob runs
+"support-bundle schedule run", which collects a bundle, stores it in a persistent volume claim or
+object storage, removes the bundles past the retention limit and records them in the status of the
+SupportBundleSchedule.
+
+The controller also watches pods, nodes and events for the triggers of the SupportBundleSchedules,
+and starts a job to collect a bundle when one matches, e.g. when a pod is in CrashLoopBackOff.`,
+	}
+
+	cmd.AddCommand(scheduleController())
+	cmd.AddCommand(scheduleRun())
+
+	k8sutil.AddFlags(cmd.PersistentFlags())
+
+	return cmd
+}
+
+func scheduleController() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "controller",
+		Args:  cobra.NoArgs,
+		Short: "Run the controller that creates a CronJob for each SupportBundleSchedule",
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return viper.BindPFlags(cmd.Flags())
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			v := viper.GetViper()
+
+			restConfig, err := k8sutil.GetRESTConfig()
+			if err != nil {
+				return errors.Wrap(err, "failed to convert kube flags to rest config")
+			}
+
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+
+			return schedule.RunController(ctx, restConfig, schedule.ControllerOptions{
+				Namespace:          v.GetString("watch-namespace"),
+				MetricsBindAddress: v.GetString("metrics-bind-address"),
+				DisableTriggers:    v.GetBool("disable-triggers"),
+			})
+		},
+	}
+
+	cmd.Flags().String("watch-namespace", "", "only reconcile the SupportBundleSchedules in this namespace (default all namespaces)")
+	cmd.Flags().String("metrics-bind-address", "0", "address the metrics endpoint binds to, \"0\" disables it")
+	cmd.Flags().Bool("disable-triggers", false, "do not watch pods, nodes and events for the triggers of SupportBundleSchedules")
+
+	return cmd
+}
+
+func scheduleRun() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "run [name]",
+		Args:  cobra.ExactArgs(1),
+		Short: "Collect and store a support bundle for a SupportBundleSchedule",
+		Long: `Collect a support bundle for a SupportBundleSchedule and store it. This is run by the jobs
+the schedule controller creates, and can be used to collect a bundle outside of the schedule.`,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return viper.BindPFlags(cmd.Flags())
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			v := viper.GetViper()
+
+			restConfig, err := k8sutil.GetRESTConfig()
+			if err != nil {
+				return errors.Wrap(err, "failed to convert kube flags to rest config")
+			}
+
+			namespace := v.GetString("namespace")
+			if namespace == "" {
+				namespace, _, err = k8sutil.GetKubeconfig().Namespace()
+				if err != nil {
+					return errors.Wrap(err, "failed to get namespace")
+				}
+			}
+
+			scheme, err := schedule.NewScheme()
+			if err != nil {
+				return err
+			}
+			c, err := client.New(restConfig, client.Options{Scheme: scheme})
+			if err != nil {
+				return errors.Wrap(err, "failed to create client")
+			}
+
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+
+			// the metrics are served while the bundle is collected, the results of scheduled
+			// collections are exported by the controller
+			if err := metrics.Start(ctx, v.GetString("metrics-bind-address")); err != nil {
+				return err
+			}
+
+			return schedule.Run(ctx, c, restConfig, namespace, args[0], v.GetString("trigger"))
+		},
+	}
+
+	cmd.Flags().String("metrics-bind-address", "0", "address the Prometheus metrics endpoint binds to while the bundle is collected, \"0\" disables it")
+	cmd.Flags().String("trigger", "", "description of the trigger that started the collection, recorded with the bundle")
+
+	return cmd
+}
@@ -0,0 +1,94 @@
+package cli
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/troubleshoot/pkg/k8sutil"
+	"github.com/replicatedhq/troubleshoot/pkg/metrics"
+	"github.com/replicatedhq/troubleshoot/pkg/serve"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+func Serve() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Args:  cobra.NoArgs,
+		Short: "Serve support bundle collection and analysis over a REST API",
+		Long: `Run a long-lived server that exposes support bundle collection and analysis over a REST API,
+so that platforms can embed troubleshoot without running the CLI. Host collectors of submitted
+specs run in pods on the nodes of the cluster, never on the host of the server. Specs whose
+collectors run commands or copy files, and analyzers that run plugins or WASM modules, are
+rejected unless the server runs with --approve-all.
+
+Clients authenticate with the token as a bearer token. The API is:
+
+  POST   /v1/collections               submit specs (YAML) and start a collection
+  GET    /v1/collections               list collections
+  GET    /v1/collections/{id}          get the status and analysis of a collection
+  GET    /v1/collections/{id}/progress stream the progress of a collection as server-sent events
+  POST   /v1/bundles                   upload a support bundle archive
+  GET    /v1/bundles/{id}              download a support bundle archive
+  DELETE /v1/bundles/{id}              delete a support bundle archive
+  POST   /v1/bundles/{id}/analyze      run analyzers (YAML, or the defaults when empty) against a bundle
+  GET    /v1/bundles/{id}/search?q=    find the lines of a bundle with all of the words of q`,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return viper.BindPFlags(cmd.Flags())
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			v := viper.GetViper()
+
+			restConfig, err := k8sutil.GetRESTConfig()
+			if err != nil {
+				return errors.Wrap(err, "failed to convert kube flags to rest config")
+			}
+
+			dataDir := v.GetString("data-dir")
+			if dataDir == "" {
+				dataDir = filepath.Join(os.TempDir(), "troubleshoot-serve")
+			}
+
+			server, err := serve.New(serve.Options{
+				Token:             v.GetString("auth-token"),
+				DataDir:           dataDir,
+				RestConfig:        restConfig,
+				MaxUploadSize:     v.GetInt64("max-upload-size"),
+				MaxCollections:    v.GetInt("max-collections"),
+				MaxBundles:        v.GetInt("max-bundles"),
+				ApproveOperations: v.GetBool("approve-all"),
+			})
+			if err != nil {
+				return err
+			}
+
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+
+			if err := metrics.Start(ctx, v.GetString("metrics-bind-address")); err != nil {
+				return err
+			}
+
+			return serve.ListenAndServe(ctx, server, v.GetString("address"), v.GetString("tls-cert-file"), v.GetString("tls-key-file"))
+		},
+	}
+
+	cmd.Flags().String("address", ":8080", "address the server listens on")
+	cmd.Flags().Bool("approve-all", false, "run the collectors of submitted specs that run commands or copy files, and the analyzers that run plugins or WASM modules, which are rejected otherwise")
+	cmd.Flags().String("auth-token", "", "bearer token clients authenticate with, can also be set with the TROUBLESHOOT_AUTH_TOKEN environment variable")
+	cmd.Flags().String("data-dir", "", "directory where collected and uploaded support bundles are kept (default \"$TMPDIR/troubleshoot-serve\")")
+	cmd.Flags().Int("max-bundles", serve.DefaultMaxBundles, "how many uploaded support bundles are kept, the oldest are deleted beyond it")
+	cmd.Flags().Int("max-collections", serve.DefaultMaxCollections, "how many collections are kept, the bundles of the oldest finished collections are deleted beyond it")
+	cmd.Flags().Int64("max-upload-size", serve.DefaultMaxUploadSize, "largest support bundle archive that can be uploaded, in bytes")
+	cmd.Flags().String("metrics-bind-address", "0", "address the Prometheus metrics endpoint binds to, \"0\" disables it")
+	cmd.Flags().String("tls-cert-file", "", "file path of the TLS certificate, the server uses TLS when it is set with --tls-key-file")
+	cmd.Flags().String("tls-key-file", "", "file path of the TLS private key")
+
+	k8sutil.AddFlags(cmd.Flags())
+
+	return cmd
+}
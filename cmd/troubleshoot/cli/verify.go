@@ -0,0 +1,97 @@
+package cli
+
+import (
+	"crypto/ed25519"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/troubleshoot/pkg/supportbundle"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+func Verify() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "verify [bundle]",
+		Args:  cobra.ExactArgs(1),
+		Short: "Check that a support bundle has not been modified since it was collected",
+		Long: `Check the files of a support bundle archive against the manifest written when it was collected.
+
+Files that were modified, removed or added, and archives that were truncated, make the command
+exit with a non-zero code. When a public key is given, the manifest must have been signed with its
+private key with --signing-key, otherwise anyone modifying the bundle could have written the
+manifest again.`,
+		Example: `  # sign the bundle when it is collected
+  openssl genpkey -algorithm ed25519 -out signing-key.pem
+  openssl pkey -in signing-key.pem -pubout -out signing-key.pub
+  support-bundle spec.yaml --signing-key signing-key.pem
+
+  # verify it when it is received
+  support-bundle verify support-bundle.tar.gz --public-key signing-key.pub`,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return viper.BindPFlags(cmd.Flags())
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			v := viper.GetViper()
+
+			output := v.GetString("output")
+			if output != "text" && output != "json" {
+				return errors.Errorf("unsupported output format %q, must be text or json", output)
+			}
+
+			var publicKey ed25519.PublicKey
+			if path := v.GetString("public-key"); path != "" {
+				b, err := os.ReadFile(path)
+				if err != nil {
+					return errors.Wrap(err, "failed to read public key")
+				}
+				if publicKey, err = supportbundle.ParseVerificationKey(b); err != nil {
+					return err
+				}
+			}
+
+			verification, err := supportbundle.VerifyBundle(args[0], publicKey)
+			if verification != nil {
+				if output == "json" {
+					if err := writeInspectJSON(os.Stdout, verification); err != nil {
+						return err
+					}
+				} else {
+					printVerification(os.Stdout, verification)
+				}
+			}
+			return err
+		},
+	}
+
+	cmd.Flags().String("public-key", "", "ed25519 public key the manifest must be signed with, in PEM encoded PKIX form")
+	cmd.Flags().String("output", "text", "output format, one of text or json")
+
+	return cmd
+}
+
+func printVerification(w io.Writer, verification *supportbundle.BundleVerification) {
+	manifest := verification.Manifest
+	fmt.Fprintf(w, "Collected by troubleshoot %s from %s to %s\n", manifest.ToolVersion, manifest.StartedAt.Format(time.RFC3339), manifest.CompletedAt.Format(time.RFC3339))
+	fmt.Fprintf(w, "Spec sha256: %s\n", manifest.SpecSHA256)
+	if verification.Signed {
+		fmt.Fprintln(w, "Signature: valid")
+	} else {
+		fmt.Fprintln(w, "Signature: not verified")
+	}
+	for _, path := range verification.Modified {
+		fmt.Fprintf(w, "modified: %s\n", path)
+	}
+	for _, path := range verification.Missing {
+		fmt.Fprintf(w, "missing: %s\n", path)
+	}
+	for _, path := range verification.Unexpected {
+		fmt.Fprintf(w, "not in the manifest: %s\n", path)
+	}
+	if verification.Err() == nil {
+		fmt.Fprintf(w, "The %d files of the bundle match its manifest\n", len(manifest.Files))
+	}
+}
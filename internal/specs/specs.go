@@ -14,6 +14,7 @@ import (
 	"github.com/fatih/color"
 	"github.com/pkg/errors"
 	"github.com/replicatedhq/troubleshoot/internal/util"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
 	"github.com/replicatedhq/troubleshoot/pkg/constants"
 	"github.com/replicatedhq/troubleshoot/pkg/k8sutil"
 	"github.com/replicatedhq/troubleshoot/pkg/loader"
@@ -191,7 +192,8 @@ func LoadFromCLIArgs(ctx context.Context, client kubernetes.Interface, args []st
 
 				// load URL spec first to remove URI key from the spec
 				urlSpec, err := loader.LoadSpecs(ctx, loader.LoadOptions{
-					RawSpec: rawURLSpec,
+					RawSpec:      rawURLSpec,
+					FetchInclude: IncludeFetcher(client),
 				})
 				if err != nil {
 					fmt.Println(color.YellowString("failed to load spec from URI %q: %v\n", v, err))
@@ -209,7 +211,8 @@ func LoadFromCLIArgs(ctx context.Context, client kubernetes.Interface, args []st
 	}
 
 	kinds, err := loader.LoadSpecs(ctx, loader.LoadOptions{
-		RawSpecs: rawSpecs,
+		RawSpecs:     rawSpecs,
+		FetchInclude: IncludeFetcher(client),
 	})
 	if err != nil {
 		return nil, err
@@ -233,6 +236,51 @@ func LoadFromCLIArgs(ctx context.Context, client kubernetes.Interface, args []st
 	return kinds, nil
 }
 
+// IncludeFetcher returns a function fetching the specs that specs include, from http(s) and oci
+// URIs and from ConfigMaps of the cluster
+func IncludeFetcher(client kubernetes.Interface) loader.IncludeFetcher {
+	return func(ctx context.Context, include *troubleshootv1beta2.SpecInclude) (string, error) {
+		if cm := include.ConfigMap; cm != nil {
+			if client == nil {
+				return "", errors.New("no kubernetes client to read configmaps with")
+			}
+			data, err := LoadFromConfigMap(ctx, client, cm.Namespace, cm.Name)
+			if err != nil {
+				return "", err
+			}
+			if cm.Key != "" {
+				spec, ok := data[cm.Key]
+				if !ok {
+					return "", errors.Errorf("configmap has no %s key", cm.Key)
+				}
+				return spec, nil
+			}
+			// Some of the data may not be specs, they will be ignored
+			specs := []string{}
+			for _, spec := range data {
+				specs = append(specs, spec)
+			}
+			return strings.Join(specs, "\n---\n"), nil
+		}
+
+		u, err := url.Parse(include.URI)
+		if err != nil {
+			return "", errors.Wrap(err, "failed to parse uri")
+		}
+		if u.Scheme == "oci" {
+			content, err := oci.PullSpecsFromOCI(ctx, include.URI)
+			if err != nil {
+				return "", err
+			}
+			return strings.Join(content, "\n---\n"), nil
+		}
+		if !util.IsURL(include.URI) {
+			return "", errors.Errorf("%s is not a URL", include.URI)
+		}
+		return downloadFromHttpURL(ctx, include.URI, nil)
+	}
+}
+
 func downloadFromHttpURL(ctx context.Context, url string, headers map[string]string) (string, error) {
 	hs := []string{}
 	for k, v := range headers {
@@ -363,7 +411,8 @@ func LoadFromCluster(ctx context.Context, client kubernetes.Interface, selectors
 
 	// Load troubleshoot specs from the raw specs
 	return loader.LoadSpecs(ctx, loader.LoadOptions{
-		RawSpecs: rawSpecs,
+		RawSpecs:     rawSpecs,
+		FetchInclude: IncludeFetcher(client),
 	})
 }
 
@@ -2,11 +2,13 @@ package traces
 
 import (
 	"context"
+	"encoding/json"
 	"sort"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/pkg/errors"
 	"github.com/replicatedhq/troubleshoot/pkg/constants"
 	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/sdk/trace"
@@ -179,6 +181,29 @@ func (e *Exporter) GetSummary() string {
 	return sb.String()
 }
 
+// GetSpans returns the spans exported so far as a JSON array, in the format
+// of the stdout exporter of OpenTelemetry, to embed the trace of an execution
+// in its bundle.
+func (e *Exporter) GetSpans() ([]byte, error) {
+	e.spansMu.Lock()
+	stubs := tracetest.SpanStubsFromReadOnlySpans(e.allSpans)
+	e.spansMu.Unlock()
+
+	// The cache starts with empty entries, which have no span context
+	spans := tracetest.SpanStubs{}
+	for _, stub := range stubs {
+		if stub.SpanContext.IsValid() {
+			spans = append(spans, stub)
+		}
+	}
+
+	b, err := json.MarshalIndent(spans, "", "  ")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal spans")
+	}
+	return b, nil
+}
+
 // summary of collector runtimes
 func collectorsSummary(summary map[string]time.Duration, sb *strings.Builder) {
 	padding, keys := sortedKeysAndPadding(summary)
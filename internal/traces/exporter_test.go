@@ -2,6 +2,7 @@ package traces
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"strings"
 	"testing"
@@ -139,6 +140,28 @@ failed-analyser (F)   : 1ms`,
 ============ Redactors summary =============
 cluster redactor : 1,000ms`,
 		},
+		{
+			name: "with redacted files",
+			spans: tracetest.SpanStubs{
+				tracetest.SpanStub{
+					Name: "cluster redactor", StartTime: time.Now(), EndTime: time.Now().Add(time.Second),
+					Attributes: []attribute.KeyValue{
+						attribute.String("type", "Redactors"),
+					},
+				},
+				tracetest.SpanStub{
+					Name: "cluster-resources/pods/default.json", StartTime: time.Now(), EndTime: time.Now().Add(time.Millisecond),
+					Attributes: []attribute.KeyValue{
+						attribute.String("type", "RedactFile"),
+					},
+				},
+			},
+			want: `
+============ Redactors summary =============
+cluster redactor : 1,000ms
+
+=`,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -185,3 +208,33 @@ func TestExporter_Shutdown(t *testing.T) {
 
 	assert.Len(t, e.allSpans, 0)
 }
+
+func TestExporter_GetSpans(t *testing.T) {
+	e := &Exporter{allSpans: make([]trace.ReadOnlySpan, 2)}
+
+	tp := trace.NewTracerProvider(trace.WithSyncer(e))
+	ctx, root := tp.Tracer(constants.LIB_TRACER_NAME).Start(context.Background(), constants.TROUBLESHOOT_ROOT_SPAN_NAME)
+	_, span := tp.Tracer(constants.LIB_TRACER_NAME).Start(ctx, "cluster-info")
+	span.SetAttributes(attribute.String("type", "*collect.CollectClusterInfo"))
+	span.End()
+	root.End()
+
+	b, err := e.GetSpans()
+	require.NoError(t, err)
+
+	var spans []struct {
+		Name        string
+		SpanContext struct {
+			TraceID string
+		}
+		Parent struct {
+			SpanID string
+		}
+	}
+	require.NoError(t, json.Unmarshal(b, &spans))
+	require.Len(t, spans, 2)
+	assert.Equal(t, "cluster-info", spans[0].Name)
+	assert.Equal(t, constants.TROUBLESHOOT_ROOT_SPAN_NAME, spans[1].Name)
+	assert.Equal(t, spans[1].SpanContext.TraceID, spans[0].SpanContext.TraceID)
+	assert.NotEmpty(t, spans[0].Parent.SpanID)
+}
@@ -2,16 +2,29 @@ package traces
 
 import (
 	"context"
+	"net/url"
+	"os"
+	"strings"
+	"time"
 
+	"github.com/pkg/errors"
 	"github.com/replicatedhq/troubleshoot/pkg/version"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
 	"go.opentelemetry.io/otel/sdk/resource"
 	"go.opentelemetry.io/otel/sdk/trace"
 	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
 	"k8s.io/klog/v2"
 )
 
+const (
+	otlpTracesPath = "/v1/traces"
+	// otlpShutdownTimeout is how long flushing the spans to an OTLP collector
+	// can keep a command from exiting
+	otlpShutdownTimeout = 10 * time.Second
+)
+
 // ConfigureTracing configures the OpenTelemetry trace provider for CLI
 // commands. Projects using troubleshoot as a library would need to register
 // troubleshoot's exporter like so.
@@ -25,7 +38,11 @@ import (
 //
 // The client application is responsible for constructing the trace provider
 // and registering the exporter. Multiple exporters can be registered.
-func ConfigureTracing(processName string) (func(), error) {
+//
+// Spans are also exported to an OTLP collector over HTTP when otlpEndpoint is
+// set, e.g. http://localhost:4318, or when the OTEL_EXPORTER_OTLP_ENDPOINT or
+// OTEL_EXPORTER_OTLP_TRACES_ENDPOINT environment variables are.
+func ConfigureTracing(processName string, otlpEndpoint string) (func(), error) {
 	r, err := resource.Merge(
 		resource.Default(),
 		resource.NewWithAttributes(
@@ -42,19 +59,62 @@ func ConfigureTracing(processName string) (func(), error) {
 
 	// Trace provider for support bundle cli. Each application is required
 	// to have its own trace provider.
-	tp := trace.NewTracerProvider(
+	opts := []trace.TracerProviderOption{
 		trace.WithSampler(trace.AlwaysSample()),
 		trace.WithSyncer(
 			GetExporterInstance(),
 		),
 		trace.WithResource(r),
-	)
+	}
+
+	otlpExporter, err := newOTLPExporter(otlpEndpoint)
+	if err != nil {
+		return nil, err
+	}
+	if otlpExporter != nil {
+		opts = append(opts, trace.WithBatcher(otlpExporter))
+	}
+
+	tp := trace.NewTracerProvider(opts...)
 
 	otel.SetTracerProvider(tp)
 
 	return func() {
-		if err := tp.Shutdown(context.Background()); err != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), otlpShutdownTimeout)
+		defer cancel()
+		if err := tp.Shutdown(ctx); err != nil {
 			klog.Errorf("Failed to shutdown trace provider: %v", err)
 		}
 	}, nil
 }
+
+// newOTLPExporter returns an exporter of spans to the OTLP collector at the
+// endpoint, a base URL the traces path is added to unless it ends with it.
+// Without an endpoint, the exporter is configured with the OTEL_EXPORTER_OTLP_*
+// environment variables, and is nil when they do not set one either.
+func newOTLPExporter(endpoint string) (trace.SpanExporter, error) {
+	opts := []otlptracehttp.Option{}
+	if endpoint != "" {
+		u, err := url.Parse(endpoint)
+		if err != nil || u.Host == "" {
+			return nil, errors.Errorf("invalid OTLP endpoint %q, expected a URL such as http://localhost:4318", endpoint)
+		}
+		opts = append(opts, otlptracehttp.WithEndpoint(u.Host))
+		if u.Scheme != "https" {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+		path := strings.TrimSuffix(u.Path, "/")
+		if !strings.HasSuffix(path, otlpTracesPath) {
+			path += otlpTracesPath
+		}
+		opts = append(opts, otlptracehttp.WithURLPath(path))
+	} else if os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") == "" && os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT") == "" {
+		return nil, nil
+	}
+
+	exporter, err := otlptracehttp.New(context.Background(), opts...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create OTLP exporter")
+	}
+	return exporter, nil
+}
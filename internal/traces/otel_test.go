@@ -0,0 +1,75 @@
+package traces
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/sdk/trace"
+)
+
+func Test_newOTLPExporter(t *testing.T) {
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "")
+	t.Setenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", "")
+
+	paths := make(chan string, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		assert.NotEmpty(t, body)
+		paths <- r.URL.Path
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name     string
+		endpoint string
+		wantPath string
+	}{
+		{
+			name:     "base url",
+			endpoint: server.URL,
+			wantPath: "/v1/traces",
+		},
+		{
+			name:     "base url with a path",
+			endpoint: server.URL + "/otlp/",
+			wantPath: "/otlp/v1/traces",
+		},
+		{
+			name:     "traces url",
+			endpoint: server.URL + "/otlp/v1/traces",
+			wantPath: "/otlp/v1/traces",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exporter, err := newOTLPExporter(tt.endpoint)
+			require.NoError(t, err)
+			require.NotNil(t, exporter)
+
+			tp := trace.NewTracerProvider(trace.WithSyncer(exporter))
+			_, span := tp.Tracer("test").Start(context.Background(), "cluster-info")
+			span.End()
+			require.NoError(t, tp.Shutdown(context.Background()))
+
+			assert.Equal(t, tt.wantPath, <-paths)
+		})
+	}
+}
+
+func Test_newOTLPExporter_NotConfigured(t *testing.T) {
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "")
+	t.Setenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", "")
+
+	exporter, err := newOTLPExporter("")
+	require.NoError(t, err)
+	assert.Nil(t, exporter)
+
+	_, err = newOTLPExporter("localhost:4318")
+	assert.ErrorContains(t, err, "invalid OTLP endpoint")
+}
@@ -36,6 +36,17 @@ type AnalyzeResult struct {
 	IconKey string
 	IconURI string
 
+	Remediation *troubleshootv1beta2.Remediation
+	// Documentation is the markdown of the document of the spec the outcome references, see ResolveDocs.
+	Documentation string
+
+	// Severity is derived from the outcome unless the analyzer sets it, see resultSeverity.
+	Severity   Severity
+	Categories []string
+	// Condition is the when clause of the outcome that matched, if the analyzer records it.
+	Condition   string
+	Measurement *Measurement
+
 	InvolvedObject *corev1.ObjectReference
 }
 
@@ -85,15 +96,29 @@ func HostAnalyze(
 		return nil
 	}
 
+	if dependency := getSkippedHostDependency(hostAnalyzer, getFile); dependency != nil {
+		klog.Infof("skipping %q host analyzer, %s collector was not collected", analyzer.Title(), dependency.Collector)
+		result := newSkippedDependencyResult(analyzer.Title(), dependency)
+		setResultsMeta(result, getAnalyzeMeta(hostAnalyzer))
+		return result
+	}
+
 	result, err := analyzer.Analyze(getFile, findFiles)
 	if err != nil {
-		return NewAnalyzeResultError(analyzer, errors.Wrap(err, "analyze"))
+		result = NewAnalyzeResultError(analyzer, errors.Wrap(err, "analyze"))
 	}
 
+	span.SetAttributes(attribute.Int("results", len(result)))
 	if len(result) == 0 {
 		klog.Errorf("no outcome matched for %q host analyzer", analyzer.Title())
 	}
 
+	if dependency := getMissingHostPrivileges(hostAnalyzer, getFile); dependency != nil {
+		result = append(result, newMissingPrivilegesResult(analyzer.Title(), dependency))
+	}
+
+	setResultsMeta(result, getAnalyzeMeta(hostAnalyzer))
+
 	return result
 }
 
@@ -143,6 +168,13 @@ func Analyze(
 		return nil, nil
 	}
 
+	if dependency := getSkippedDependency(analyzer, getFile); dependency != nil {
+		klog.Infof("skipping %q analyzer, %s collector was not collected", analyzerInst.Title(), dependency.Collector)
+		results := newSkippedDependencyResult(analyzerInst.Title(), dependency)
+		setResultsMeta(results, getAnalyzeMeta(analyzer))
+		return results, nil
+	}
+
 	results, err := analyzerInst.Analyze(getFile, findFiles)
 	if err != nil {
 		span.SetStatus(codes.Error, err.Error())
@@ -153,10 +185,13 @@ func Analyze(
 		results = []*AnalyzeResult{}
 	}
 
+	span.SetAttributes(attribute.Int("results", len(results)))
 	if len(results) == 0 {
 		klog.Errorf("no outcome matched for %q analyzer", analyzerInst.Title())
 	}
 
+	setResultsMeta(results, getAnalyzeMeta(analyzer))
+
 	return results, nil
 }
 
@@ -182,6 +217,34 @@ func GetExcludeFlag(analyzer *troubleshootv1beta2.Analyze) *multitype.BoolOrStri
 	return nil
 }
 
+// getAnalyzeMeta returns the AnalyzeMeta of the analyzer set in an Analyze or HostAnalyze spec.
+func getAnalyzeMeta(spec interface{}) *troubleshootv1beta2.AnalyzeMeta {
+	reflected := reflect.ValueOf(spec)
+	if reflected.Kind() != reflect.Ptr || reflected.IsNil() {
+		return nil
+	}
+
+	reflected = reflected.Elem()
+	for i := 0; i < reflected.NumField(); i++ {
+		field := reflected.Field(i)
+		if field.Kind() != reflect.Ptr || field.IsNil() || field.Elem().Kind() != reflect.Struct {
+			continue
+		}
+
+		metaField := field.Elem().FieldByName("AnalyzeMeta")
+		if !metaField.IsValid() {
+			continue
+		}
+		meta, ok := metaField.Interface().(troubleshootv1beta2.AnalyzeMeta)
+		if !ok {
+			continue
+		}
+		return &meta
+	}
+
+	return nil
+}
+
 type Analyzer interface {
 	Title() string
 	IsExcluded() (bool, error)
@@ -192,6 +255,8 @@ func GetAnalyzer(analyzer *troubleshootv1beta2.Analyze) Analyzer {
 	switch {
 	case analyzer.ClusterVersion != nil:
 		return &AnalyzeClusterVersion{analyzer: analyzer.ClusterVersion}
+	case analyzer.KubernetesUpgrade != nil:
+		return &AnalyzeKubernetesUpgrade{analyzer: analyzer.KubernetesUpgrade}
 	case analyzer.StorageClass != nil:
 		return &AnalyzeStorageClass{analyzer: analyzer.StorageClass}
 	case analyzer.CustomResourceDefinition != nil:
@@ -208,6 +273,8 @@ func GetAnalyzer(analyzer *troubleshootv1beta2.Analyze) Analyzer {
 		return &AnalyzeDeploymentStatus{analyzer: analyzer.DeploymentStatus}
 	case analyzer.StatefulsetStatus != nil:
 		return &AnalyzeStatefulsetStatus{analyzer: analyzer.StatefulsetStatus}
+	case analyzer.StatefulSetVolumes != nil:
+		return &AnalyzeStatefulSetVolumes{analyzer: analyzer.StatefulSetVolumes}
 	case analyzer.JobStatus != nil:
 		return &AnalyzeJobStatus{analyzer: analyzer.JobStatus}
 	case analyzer.ReplicaSetStatus != nil:
@@ -222,12 +289,20 @@ func GetAnalyzer(analyzer *troubleshootv1beta2.Analyze) Analyzer {
 		return &AnalyzeDistribution{analyzer: analyzer.Distribution}
 	case analyzer.NodeResources != nil:
 		return &AnalyzeNodeResources{analyzer: analyzer.NodeResources}
+	case analyzer.ResourceQuota != nil:
+		return &AnalyzeResourceQuota{analyzer: analyzer.ResourceQuota}
+	case analyzer.PodDisruptionBudget != nil:
+		return &AnalyzePodDisruptionBudget{analyzer: analyzer.PodDisruptionBudget}
+	case analyzer.ConfigMapsAndSecrets != nil:
+		return &AnalyzeConfigMapsAndSecrets{analyzer: analyzer.ConfigMapsAndSecrets}
 	case analyzer.TextAnalyze != nil:
 		return &AnalyzeTextAnalyze{analyzer: analyzer.TextAnalyze}
 	case analyzer.YamlCompare != nil:
 		return &AnalyzeYamlCompare{analyzer: analyzer.YamlCompare}
 	case analyzer.JsonCompare != nil:
 		return &AnalyzeJsonCompare{analyzer: analyzer.JsonCompare}
+	case analyzer.JSONQuery != nil:
+		return &AnalyzeJSONQuery{analyzer: analyzer.JSONQuery}
 	case analyzer.Postgres != nil:
 		return &AnalyzePostgres{analyzer: analyzer.Postgres}
 	case analyzer.Mysql != nil:
@@ -236,6 +311,14 @@ func GetAnalyzer(analyzer *troubleshootv1beta2.Analyze) Analyzer {
 		return &AnalyzeMssql{analyzer: analyzer.Mssql}
 	case analyzer.Redis != nil:
 		return &AnalyzeRedis{analyzer: analyzer.Redis}
+	case analyzer.Kafka != nil:
+		return &AnalyzeKafka{analyzer: analyzer.Kafka}
+	case analyzer.RabbitMQ != nil:
+		return &AnalyzeRabbitMQ{analyzer: analyzer.RabbitMQ}
+	case analyzer.LDAP != nil:
+		return &AnalyzeLDAP{analyzer: analyzer.LDAP}
+	case analyzer.OIDC != nil:
+		return &AnalyzeOIDC{analyzer: analyzer.OIDC}
 	case analyzer.CephStatus != nil:
 		return &AnalyzeCephStatus{analyzer: analyzer.CephStatus}
 	case analyzer.Velero != nil:
@@ -244,6 +327,10 @@ func GetAnalyzer(analyzer *troubleshootv1beta2.Analyze) Analyzer {
 		return &AnalyzeLonghorn{analyzer: analyzer.Longhorn}
 	case analyzer.RegistryImages != nil:
 		return &AnalyzeRegistryImages{analyzer: analyzer.RegistryImages}
+	case analyzer.ImagePlatforms != nil:
+		return &AnalyzeImagePlatforms{analyzer: analyzer.ImagePlatforms}
+	case analyzer.MutableImageTags != nil:
+		return &AnalyzeMutableImageTags{analyzer: analyzer.MutableImageTags}
 	case analyzer.WeaveReport != nil:
 		return &AnalyzeWeaveReport{analyzer: analyzer.WeaveReport}
 	case analyzer.Sysctl != nil:
@@ -254,6 +341,12 @@ func GetAnalyzer(analyzer *troubleshootv1beta2.Analyze) Analyzer {
 		return &AnalyzeCertificates{analyzer: analyzer.Certificates}
 	case analyzer.Goldpinger != nil:
 		return &AnalyzeGoldpinger{analyzer: analyzer.Goldpinger}
+	case analyzer.NodeLatency != nil:
+		return &AnalyzeNodeLatency{analyzer: analyzer.NodeLatency}
+	case analyzer.ClusterOperatorStatus != nil:
+		return &AnalyzeClusterOperatorStatus{analyzer: analyzer.ClusterOperatorStatus}
+	case analyzer.MachineConfigPoolStatus != nil:
+		return &AnalyzeMachineConfigPoolStatus{analyzer: analyzer.MachineConfigPoolStatus}
 	case analyzer.Event != nil:
 		return &AnalyzeEvent{analyzer: analyzer.Event}
 	case analyzer.NodeMetrics != nil:
@@ -262,6 +355,34 @@ func GetAnalyzer(analyzer *troubleshootv1beta2.Analyze) Analyzer {
 		return &AnalyzeHTTPAnalyze{analyzer: analyzer.HTTP}
 	case analyzer.ImageSignatures != nil:
 		return &AnalyzeImageSignatures{analyzer: analyzer.ImageSignatures}
+	case analyzer.Plugin != nil:
+		return &AnalyzePlugin{analyzer: analyzer.Plugin}
+	case analyzer.Wasm != nil:
+		return &AnalyzeWasm{analyzer: analyzer.Wasm}
+	case analyzer.Composite != nil:
+		return &AnalyzeComposite{analyzer: analyzer.Composite}
+	case analyzer.Proxy != nil:
+		return &AnalyzeProxy{analyzer: analyzer.Proxy}
+	case analyzer.VeleroReadiness != nil:
+		return &AnalyzeVeleroReadiness{analyzer: analyzer.VeleroReadiness}
+	case analyzer.TrustChains != nil:
+		return &AnalyzeTrustChains{analyzer: analyzer.TrustChains}
+	case analyzer.ImageLayers != nil:
+		return &AnalyzeImageLayers{analyzer: analyzer.ImageLayers}
+	case analyzer.ImageFacts != nil:
+		return &AnalyzeImageFacts{analyzer: analyzer.ImageFacts}
+	case analyzer.KubeletMetrics != nil:
+		return &AnalyzeKubeletMetrics{analyzer: analyzer.KubeletMetrics}
+	case analyzer.Rego != nil:
+		return &AnalyzeRego{analyzer: analyzer.Rego}
+	case analyzer.PolicyViolations != nil:
+		return &AnalyzePolicyViolations{analyzer: analyzer.PolicyViolations}
+	case analyzer.DiskPressure != nil:
+		return &AnalyzeDiskPressure{analyzer: analyzer.DiskPressure}
+	case analyzer.PodSecurity != nil:
+		return &AnalyzePodSecurity{analyzer: analyzer.PodSecurity}
+	case analyzer.ManagedCluster != nil:
+		return &AnalyzeManagedCluster{analyzer: analyzer.ManagedCluster}
 	default:
 		return nil
 	}
@@ -0,0 +1,22 @@
+package analyzer
+
+import (
+	"github.com/replicatedhq/troubleshoot/pkg/collect"
+	"github.com/replicatedhq/troubleshoot/pkg/constants"
+)
+
+// getBundleIndex reads the index of the bundle. Bundles collected before the index was
+// introduced, or collected in memory by preflights, have no index and nil is returned.
+func getBundleIndex(getFile getCollectedFileContents) *collect.BundleIndex {
+	contents, err := getFile(constants.BUNDLE_INDEX_FILENAME)
+	if err != nil || len(contents) == 0 {
+		return nil
+	}
+
+	index, err := collect.ParseBundleIndex(contents)
+	if err != nil {
+		return nil
+	}
+
+	return index
+}
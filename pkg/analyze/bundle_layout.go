@@ -0,0 +1,64 @@
+package analyzer
+
+import (
+	"fmt"
+	"strings"
+
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/constants"
+	"gopkg.in/yaml.v2"
+)
+
+// getBundleLayoutVersion returns the layout version recorded in the version file of the bundle.
+// Bundles created before layouts were versioned, or without a version file, return an empty string.
+func getBundleLayoutVersion(getCollectedFileContents func(string) ([]byte, error)) string {
+	contents, err := getCollectedFileContents(constants.VERSION_FILENAME)
+	if err != nil || len(contents) == 0 {
+		return ""
+	}
+
+	bundleVersion := troubleshootv1beta2.SupportBundleVersion{}
+	if err := yaml.Unmarshal(contents, &bundleVersion); err != nil {
+		return ""
+	}
+
+	return bundleVersion.Spec.LayoutVersion
+}
+
+// nodeFilePaths returns the paths a per-node host collector file may be stored at, in the order
+// they should be read for the given bundle layout version. Paths of the other known layouts are
+// always included so that bundles with an unknown, missing or mixed layout can still be analyzed.
+func nodeFilePaths(layoutVersion string, baseDir string, node string, fileName string) []string {
+	v1Path := fmt.Sprintf("%s/%s/%s", baseDir, node, fileName)
+
+	prefix := constants.HOST_COLLECTORS_DIR + "/"
+	if !strings.HasPrefix(baseDir, prefix) {
+		return []string{v1Path}
+	}
+	v2Path := fmt.Sprintf("%s%s/%s/%s", prefix, node, strings.TrimPrefix(baseDir, prefix), fileName)
+
+	switch layoutVersion {
+	case constants.BUNDLE_LAYOUT_V2:
+		return []string{v2Path, v1Path}
+	default:
+		return []string{v1Path, v2Path}
+	}
+}
+
+// readNodeFile reads a per-node host collector file using the paths of the bundle's layout.
+// If the file cannot be found at any of them, the error of the preferred path is returned.
+func readNodeFile(
+	getCollectedFileContents func(string) ([]byte, error), layoutVersion string, baseDir string, node string, fileName string,
+) ([]byte, error) {
+	var firstErr error
+	for _, path := range nodeFilePaths(layoutVersion, baseDir, node, fileName) {
+		contents, err := getCollectedFileContents(path)
+		if err == nil {
+			return contents, nil
+		}
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+	return nil, firstErr
+}
@@ -0,0 +1,106 @@
+package analyzer
+
+import (
+	"testing"
+
+	"github.com/replicatedhq/troubleshoot/pkg/constants"
+	"github.com/replicatedhq/troubleshoot/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetBundleLayoutVersion(t *testing.T) {
+	tests := []struct {
+		name     string
+		contents string
+		want     string
+	}{
+		{
+			name: "layout version recorded",
+			contents: `apiVersion: troubleshoot.sh/v1beta2
+kind: SupportBundle
+spec:
+  versionNumber: v0.100.0
+  layoutVersion: v2
+`,
+			want: constants.BUNDLE_LAYOUT_V2,
+		},
+		{
+			name: "bundle created before layouts were versioned",
+			contents: `apiVersion: troubleshoot.sh/v1beta2
+kind: SupportBundle
+spec:
+  versionNumber: v0.60.0
+`,
+			want: "",
+		},
+		{
+			name:     "no version file",
+			contents: "",
+			want:     "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			getFile := func(path string) ([]byte, error) {
+				if path == constants.VERSION_FILENAME && tt.contents != "" {
+					return []byte(tt.contents), nil
+				}
+				return nil, &types.NotFoundError{Name: path}
+			}
+			assert.Equal(t, tt.want, getBundleLayoutVersion(getFile))
+		})
+	}
+}
+
+func TestNodeFilePaths(t *testing.T) {
+	tests := []struct {
+		name          string
+		layoutVersion string
+		baseDir       string
+		want          []string
+	}{
+		{
+			name:          "unversioned bundle prefers the v1 layout",
+			layoutVersion: "",
+			baseDir:       "host-collectors/system",
+			want:          []string{"host-collectors/system/node1/cpu.json", "host-collectors/node1/system/cpu.json"},
+		},
+		{
+			name:          "v2 bundle prefers per-node directories",
+			layoutVersion: constants.BUNDLE_LAYOUT_V2,
+			baseDir:       "host-collectors/system",
+			want:          []string{"host-collectors/node1/system/cpu.json", "host-collectors/system/node1/cpu.json"},
+		},
+		{
+			name:          "base dir outside of host collectors",
+			layoutVersion: constants.BUNDLE_LAYOUT_V2,
+			baseDir:       "other",
+			want:          []string{"other/node1/cpu.json"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, nodeFilePaths(tt.layoutVersion, tt.baseDir, "node1", "cpu.json"))
+		})
+	}
+}
+
+func TestReadNodeFile(t *testing.T) {
+	getFile := func(path string) ([]byte, error) {
+		if path == "host-collectors/node1/system/cpu.json" {
+			return []byte("v2 content"), nil
+		}
+		return nil, &types.NotFoundError{Name: path}
+	}
+
+	// an unversioned bundle falls back to the per-node layout
+	contents, err := readNodeFile(getFile, "", "host-collectors/system", "node1", "cpu.json")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("v2 content"), contents)
+
+	// the error of the preferred path is returned when the file is missing
+	_, err = readNodeFile(getFile, "", "host-collectors/system", "node2", "cpu.json")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "host-collectors/system/node2/cpu.json")
+}
@@ -167,6 +167,7 @@ func (a *AnalyzeCephStatus) cephStatus(analyzer *troubleshootv1beta2.CephStatusA
 				analyzeResult.IsFail = true
 				analyzeResult.Message = detailedCephMessage(outcome.Fail.Message, status)
 				analyzeResult.URI = outcome.Fail.URI
+				analyzeResult.Remediation = outcome.Fail.Remediation
 				return analyzeResult, nil
 			}
 		} else if outcome.Warn != nil {
@@ -181,6 +182,7 @@ func (a *AnalyzeCephStatus) cephStatus(analyzer *troubleshootv1beta2.CephStatusA
 				analyzeResult.IsWarn = true
 				analyzeResult.Message = detailedCephMessage(outcome.Warn.Message, status)
 				analyzeResult.URI = outcome.Warn.URI
+				analyzeResult.Remediation = outcome.Warn.Remediation
 				return analyzeResult, nil
 			}
 		} else if outcome.Pass != nil {
@@ -195,6 +197,7 @@ func (a *AnalyzeCephStatus) cephStatus(analyzer *troubleshootv1beta2.CephStatusA
 				analyzeResult.IsPass = true
 				analyzeResult.Message = outcome.Pass.Message
 				analyzeResult.URI = outcome.Pass.URI
+				analyzeResult.Remediation = outcome.Pass.Remediation
 
 				return analyzeResult, nil
 			}
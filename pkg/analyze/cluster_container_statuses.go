@@ -141,16 +141,19 @@ func (a *AnalyzeClusterContainerStatuses) analyzeContainerStatuses(podContainers
 			r.IsFail = true
 			r.Message = outcome.Fail.Message
 			r.URI = outcome.Fail.URI
+			r.Remediation = outcome.Fail.Remediation
 			when = outcome.Fail.When
 		case outcome.Warn != nil:
 			r.IsWarn = true
 			r.Message = outcome.Warn.Message
 			r.URI = outcome.Warn.URI
+			r.Remediation = outcome.Warn.Remediation
 			when = outcome.Warn.When
 		case outcome.Pass != nil:
 			r.IsPass = true
 			r.Message = outcome.Pass.Message
 			r.URI = outcome.Pass.URI
+			r.Remediation = outcome.Pass.Remediation
 			when = outcome.Pass.When
 		default:
 			klog.Warning("unexpected outcome in clusterContainerStatuses analyzer")
@@ -120,16 +120,19 @@ func clusterPodStatuses(analyzer *troubleshootv1beta2.ClusterPodStatuses, getChi
 				r.IsFail = true
 				r.Message = outcome.Fail.Message
 				r.URI = outcome.Fail.URI
+				r.Remediation = outcome.Fail.Remediation
 				when = outcome.Fail.When
 			} else if outcome.Warn != nil {
 				r.IsWarn = true
 				r.Message = outcome.Warn.Message
 				r.URI = outcome.Warn.URI
+				r.Remediation = outcome.Warn.Remediation
 				when = outcome.Warn.When
 			} else if outcome.Pass != nil {
 				r.IsPass = true
 				r.Message = outcome.Pass.Message
 				r.URI = outcome.Pass.URI
+				r.Remediation = outcome.Pass.Remediation
 				when = outcome.Pass.When
 			} else {
 				klog.Error("error: found an empty outcome in a clusterPodStatuses analyzer\n")
@@ -73,6 +73,7 @@ func analyzeClusterVersionResult(k8sVersion semver.Version, outcomes []*troubles
 		when := ""
 		message := ""
 		uri := ""
+		var remediation *troubleshootv1beta2.Remediation
 
 		result := AnalyzeResult{
 			Title:   title(checkName),
@@ -85,16 +86,19 @@ func analyzeClusterVersionResult(k8sVersion semver.Version, outcomes []*troubles
 			when = outcome.Fail.When
 			message = outcome.Fail.Message
 			uri = outcome.Fail.URI
+			remediation = outcome.Fail.Remediation
 		} else if outcome.Warn != nil {
 			result.IsWarn = true
 			when = outcome.Warn.When
 			message = outcome.Warn.Message
 			uri = outcome.Warn.URI
+			remediation = outcome.Warn.Remediation
 		} else if outcome.Pass != nil {
 			result.IsPass = true
 			when = outcome.Pass.When
 			message = outcome.Pass.Message
 			uri = outcome.Pass.URI
+			remediation = outcome.Pass.Remediation
 		} else {
 			return nil, errors.New("empty outcome")
 		}
@@ -103,6 +107,7 @@ func analyzeClusterVersionResult(k8sVersion semver.Version, outcomes []*troubles
 		if when == "" {
 			result.Message = message
 			result.URI = uri
+			result.Remediation = remediation
 
 			return &result, nil
 		}
@@ -115,6 +120,7 @@ func analyzeClusterVersionResult(k8sVersion semver.Version, outcomes []*troubles
 		if whenRange(k8sVersion) {
 			result.Message = message
 			result.URI = uri
+			result.Remediation = remediation
 
 			return &result, nil
 		}
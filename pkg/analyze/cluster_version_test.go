@@ -16,6 +16,10 @@ func Test_analyzeClusterVersionResult(t *testing.T) {
 				When:    "< 1.13.0",
 				Message: "Sentry requires at Kubernetes 1.13.0 or later, and recommends 1.15.0.",
 				URI:     "https://www.kubernetes.io",
+				Remediation: &troubleshootv1beta2.Remediation{
+					DocLink:  "https://kubernetes.io/docs/tasks/administer-cluster/cluster-upgrade/",
+					Severity: troubleshootv1beta2.RemediationSeverityCritical,
+				},
 			},
 		},
 		{
@@ -55,6 +59,10 @@ func Test_analyzeClusterVersionResult(t *testing.T) {
 				Title:   "Check Fail",
 				Message: "Sentry requires at Kubernetes 1.13.0 or later, and recommends 1.15.0.",
 				URI:     "https://www.kubernetes.io",
+				Remediation: &troubleshootv1beta2.Remediation{
+					DocLink:  "https://kubernetes.io/docs/tasks/administer-cluster/cluster-upgrade/",
+					Severity: troubleshootv1beta2.RemediationSeverityCritical,
+				},
 				IconKey: "kubernetes_cluster_version",
 				IconURI: "https://troubleshoot.sh/images/analyzer-icons/kubernetes.svg?w=16&h=16",
 			},
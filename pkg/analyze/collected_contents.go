@@ -2,7 +2,6 @@ package analyzer
 
 import (
 	"encoding/json"
-	"fmt"
 
 	"github.com/pkg/errors"
 	"github.com/replicatedhq/troubleshoot/pkg/constants"
@@ -45,10 +44,10 @@ func retrieveCollectedContents(
 		return nil, errors.Wrap(err, "failed to unmarshal node names")
 	}
 
-	// Collect data for each node
+	// Collect data for each node, reading from wherever the bundle layout placed it
+	layoutVersion := getBundleLayoutVersion(getCollectedFileContents)
 	for _, node := range nodeNames.Nodes {
-		nodeFilePath := fmt.Sprintf("%s/%s/%s", remoteNodeBaseDir, node, remoteFileName)
-		nodeContents, err := getCollectedFileContents(nodeFilePath)
+		nodeContents, err := readNodeFile(getCollectedFileContents, layoutVersion, remoteNodeBaseDir, node, remoteFileName)
 		if err != nil {
 			return nil, errors.Wrapf(err, "failed to retrieve content for node %s", node)
 		}
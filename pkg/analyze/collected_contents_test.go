@@ -69,6 +69,32 @@ func TestRetrieveCollectedContents(t *testing.T) {
 			},
 			expectedError: "",
 		},
+		{
+			name: "retrieve remote node content stored in per-node directories",
+			getCollectedFileContents: func(path string) ([]byte, error) {
+				if path == constants.VERSION_FILENAME {
+					return []byte("spec:\n  layoutVersion: v2\n"), nil
+				}
+				if path == constants.NODE_LIST_FILE {
+					nodeNames := nodeNames{Nodes: []string{"node1"}}
+					return json.Marshal(nodeNames)
+				}
+				if path == "host-collectors/node1/system/cpu.json" {
+					return []byte("remoteContent1"), nil
+				}
+				return nil, &types.NotFoundError{Name: path}
+			},
+			localPath:         "host-collectors/system/cpu.json",
+			remoteNodeBaseDir: "host-collectors/system",
+			remoteFileName:    "cpu.json",
+			expectedResult: []collectedContent{
+				{
+					NodeName: "node1",
+					Data:     []byte("remoteContent1"),
+				},
+			},
+			expectedError: "",
+		},
 		{
 			name: "fail to retrieve local content and node list",
 			getCollectedFileContents: func(path string) ([]byte, error) {
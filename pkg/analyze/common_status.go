@@ -6,9 +6,36 @@ import (
 	"strings"
 
 	"github.com/pkg/errors"
+	"github.com/replicatedhq/troubleshoot/internal/util"
 	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
 )
 
+// workloadStatusTemplateData is passed to the messages of the outcomes of the deployment,
+// statefulset and replicaset status analyzers. Replicas is the number of replicas requested by the
+// spec of the workload, and all the counts are 0 when it was not found.
+type workloadStatusTemplateData struct {
+	Namespace         string
+	Name              string
+	Replicas          int
+	ReadyReplicas     int
+	AvailableReplicas int
+}
+
+// renderResultMessage renders the message of the outcome that matched with the values computed by
+// the analyzer, e.g. "{{ .ReadyReplicas }} of {{ .Replicas }} replicas are ready"
+func renderResultMessage(result *AnalyzeResult, data interface{}) (*AnalyzeResult, error) {
+	if result == nil {
+		return nil, nil
+	}
+
+	message, err := util.RenderTemplate(result.Message, data)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to render message template")
+	}
+	result.Message = message
+	return result, nil
+}
+
 func commonStatus(outcomes []*troubleshootv1beta2.Outcome, name string, iconKey string, iconURI string, readyReplicas int, exists bool, resourceType string) (*AnalyzeResult, error) {
 	result := &AnalyzeResult{
 		Title:   fmt.Sprintf("%s Status", name),
@@ -26,6 +53,7 @@ func commonStatus(outcomes []*troubleshootv1beta2.Outcome, name string, iconKey
 				result.IsFail = true
 				result.Message = fmt.Sprintf("The %s %q was not found", resourceType, name)
 				result.URI = outcome.Fail.URI
+				result.Remediation = outcome.Fail.Remediation
 				return result, nil
 			}
 
@@ -33,6 +61,7 @@ func commonStatus(outcomes []*troubleshootv1beta2.Outcome, name string, iconKey
 				result.IsFail = true
 				result.Message = outcome.Fail.Message
 				result.URI = outcome.Fail.URI
+				result.Remediation = outcome.Fail.Remediation
 
 				return result, nil
 			}
@@ -42,6 +71,7 @@ func commonStatus(outcomes []*troubleshootv1beta2.Outcome, name string, iconKey
 					result.IsFail = true
 					result.Message = outcome.Fail.Message
 					result.URI = outcome.Fail.URI
+					result.Remediation = outcome.Fail.Remediation
 					return result, nil
 				} else {
 					continue
@@ -57,6 +87,7 @@ func commonStatus(outcomes []*troubleshootv1beta2.Outcome, name string, iconKey
 				result.IsFail = true
 				result.Message = outcome.Fail.Message
 				result.URI = outcome.Fail.URI
+				result.Remediation = outcome.Fail.Remediation
 
 				return result, nil
 			}
@@ -66,6 +97,7 @@ func commonStatus(outcomes []*troubleshootv1beta2.Outcome, name string, iconKey
 				result.IsFail = true
 				result.Message = fmt.Sprintf("The %s %q was not found", resourceType, name)
 				result.URI = outcome.Warn.URI
+				result.Remediation = outcome.Warn.Remediation
 				return result, nil
 			}
 
@@ -73,6 +105,7 @@ func commonStatus(outcomes []*troubleshootv1beta2.Outcome, name string, iconKey
 				result.IsWarn = true
 				result.Message = outcome.Warn.Message
 				result.URI = outcome.Warn.URI
+				result.Remediation = outcome.Warn.Remediation
 
 				return result, nil
 			}
@@ -82,6 +115,7 @@ func commonStatus(outcomes []*troubleshootv1beta2.Outcome, name string, iconKey
 					result.IsWarn = true
 					result.Message = outcome.Warn.Message
 					result.URI = outcome.Warn.URI
+					result.Remediation = outcome.Warn.Remediation
 					return result, nil
 				} else {
 					continue
@@ -97,6 +131,7 @@ func commonStatus(outcomes []*troubleshootv1beta2.Outcome, name string, iconKey
 				result.IsWarn = true
 				result.Message = outcome.Warn.Message
 				result.URI = outcome.Warn.URI
+				result.Remediation = outcome.Warn.Remediation
 
 				return result, nil
 			}
@@ -106,6 +141,7 @@ func commonStatus(outcomes []*troubleshootv1beta2.Outcome, name string, iconKey
 				result.IsFail = true
 				result.Message = fmt.Sprintf("The %s %q was not found", resourceType, name)
 				result.URI = outcome.Pass.URI
+				result.Remediation = outcome.Pass.Remediation
 				return result, nil
 			}
 
@@ -113,6 +149,7 @@ func commonStatus(outcomes []*troubleshootv1beta2.Outcome, name string, iconKey
 				result.IsPass = true
 				result.Message = outcome.Pass.Message
 				result.URI = outcome.Pass.URI
+				result.Remediation = outcome.Pass.Remediation
 
 				return result, nil
 			}
@@ -122,6 +159,7 @@ func commonStatus(outcomes []*troubleshootv1beta2.Outcome, name string, iconKey
 					result.IsPass = true
 					result.Message = outcome.Pass.Message
 					result.URI = outcome.Pass.URI
+					result.Remediation = outcome.Pass.Remediation
 					return result, nil
 				} else {
 					continue
@@ -137,6 +175,7 @@ func commonStatus(outcomes []*troubleshootv1beta2.Outcome, name string, iconKey
 				result.IsPass = true
 				result.Message = outcome.Pass.Message
 				result.URI = outcome.Pass.URI
+				result.Remediation = outcome.Pass.Remediation
 
 				return result, nil
 			}
@@ -1,6 +1,11 @@
 package analyzer
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+)
 
 type ComparisonOperator int
 
@@ -32,3 +37,73 @@ func ParseComparisonOperator(s string) (ComparisonOperator, error) {
 
 	return Unknown, fmt.Errorf("unknown operator: %s", s)
 }
+
+// compareEquality applies an "==" or "!=" operator to the result of an equality check
+func compareEquality(equal bool, opString string) (bool, error) {
+	operator, err := ParseComparisonOperator(opString)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to parse comparison operator %q", opString)
+	}
+
+	switch operator {
+	case Equal:
+		return equal, nil
+	case NotEqual:
+		return !equal, nil
+	}
+
+	return false, errors.New(`only supported operators are "==" and "!="`)
+}
+
+// compareDuration compares a duration to the duration in a condition, e.g. "> 24h"
+func compareDuration(actual time.Duration, opString string, expected string) (bool, error) {
+	operator, err := ParseComparisonOperator(opString)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to parse comparison operator %q", opString)
+	}
+	threshold, err := time.ParseDuration(expected)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to parse duration %q", expected)
+	}
+
+	switch operator {
+	case Equal:
+		return actual == threshold, nil
+	case NotEqual:
+		return actual != threshold, nil
+	case LessThan:
+		return actual < threshold, nil
+	case LessThanOrEqual:
+		return actual <= threshold, nil
+	case GreaterThan:
+		return actual > threshold, nil
+	case GreaterThanOrEqual:
+		return actual >= threshold, nil
+	}
+
+	return false, fmt.Errorf("unsupported operator %q", opString)
+}
+
+// compareFloat compares a number to the threshold of a condition
+func compareFloat(actual float64, opString string, threshold float64) (bool, error) {
+	operator, err := ParseComparisonOperator(opString)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to parse comparison operator %q", opString)
+	}
+
+	switch operator {
+	case Equal:
+		return actual == threshold, nil
+	case NotEqual:
+		return actual != threshold, nil
+	case LessThan:
+		return actual < threshold, nil
+	case LessThanOrEqual:
+		return actual <= threshold, nil
+	case GreaterThan:
+		return actual > threshold, nil
+	case GreaterThanOrEqual:
+		return actual >= threshold, nil
+	}
+	return false, fmt.Errorf("unsupported operator %q", opString)
+}
@@ -0,0 +1,250 @@
+package analyzer
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+)
+
+var defaultCompositeOutcomes = []*troubleshootv1beta2.Outcome{
+	{Fail: &troubleshootv1beta2.SingleOutcome{When: "failed == true", Message: "The requirements are not met: {{ .Unmet }}"}},
+	{Warn: &troubleshootv1beta2.SingleOutcome{When: "warned == true", Message: "The requirements are met with warnings: {{ .Unmet }}"}},
+	{Pass: &troubleshootv1beta2.SingleOutcome{Message: "The requirements are met: {{ .Met }}"}},
+}
+
+// compositeState orders the results of analyzers, so that allOf takes the lowest state of its
+// children and anyOf the highest
+type compositeState int
+
+const (
+	compositeFail compositeState = iota
+	compositeWarn
+	compositePass
+)
+
+// compositeTemplateData is passed to the messages of the outcomes
+type compositeTemplateData struct {
+	// Score is the weighted share of the children that are met, in percent
+	Score int
+	// Met and Unmet are the titles and messages of the children that are, and are not, met
+	Met   string
+	Unmet string
+
+	state compositeState
+	// met is the number of children that are met
+	met int
+}
+
+// compositeChildResult is a child analyzer rolled up into a single state
+type compositeChildResult struct {
+	title   string
+	message string
+	state   compositeState
+	weight  int
+}
+
+func (r compositeChildResult) String() string {
+	if r.message == "" {
+		return r.title
+	}
+	return fmt.Sprintf("%s: %s", r.title, r.message)
+}
+
+type AnalyzeComposite struct {
+	analyzer *troubleshootv1beta2.CompositeAnalyze
+}
+
+func (a *AnalyzeComposite) Title() string {
+	if a.analyzer.CheckName != "" {
+		return a.analyzer.CheckName
+	}
+
+	return "Composite"
+}
+
+func (a *AnalyzeComposite) IsExcluded() (bool, error) {
+	return isExcluded(a.analyzer.Exclude)
+}
+
+func (a *AnalyzeComposite) Analyze(getFile getCollectedFileContents, findFiles getChildCollectedFileContents) ([]*AnalyzeResult, error) {
+	if len(a.analyzer.AllOf) == 0 && len(a.analyzer.AnyOf) == 0 && len(a.analyzer.NoneOf) == 0 {
+		return nil, errors.New("composite analyzer requires at least one child in allOf, anyOf or noneOf")
+	}
+
+	data := &compositeTemplateData{state: compositePass}
+	met, unmet := []string{}, []string{}
+	totalWeight, metWeight := 0, 0
+
+	groups := []struct {
+		children []*troubleshootv1beta2.CompositeChild
+		// state rolls the states of the children of the group up
+		state func([]compositeState) compositeState
+		// isMet returns whether a child is met in the group
+		isMet func(compositeState) bool
+	}{
+		{a.analyzer.AllOf, minCompositeState, func(s compositeState) bool { return s == compositePass }},
+		{a.analyzer.AnyOf, maxCompositeState, func(s compositeState) bool { return s == compositePass }},
+		{a.analyzer.NoneOf, func(states []compositeState) compositeState {
+			return compositePass - maxCompositeState(states)
+		}, func(s compositeState) bool { return s == compositeFail }},
+	}
+
+	for _, group := range groups {
+		if len(group.children) == 0 {
+			continue
+		}
+
+		states := []compositeState{}
+		for _, child := range group.children {
+			result, err := analyzeCompositeChild(child, getFile, findFiles)
+			if err != nil {
+				return nil, err
+			}
+			if result == nil {
+				// excluded children don't count
+				continue
+			}
+
+			states = append(states, result.state)
+			totalWeight += result.weight
+			if group.isMet(result.state) {
+				met = append(met, result.String())
+				metWeight += result.weight
+				data.met++
+			} else {
+				unmet = append(unmet, result.String())
+			}
+		}
+		if len(states) == 0 {
+			continue
+		}
+
+		if state := group.state(states); state < data.state {
+			data.state = state
+		}
+	}
+
+	if totalWeight > 0 {
+		data.Score = int(math.Round(float64(metWeight) * 100 / float64(totalWeight)))
+	} else if data.state == compositePass {
+		data.Score = 100
+	}
+	data.Met = strings.Join(met, "; ")
+	data.Unmet = strings.Join(unmet, "; ")
+
+	outcomes := a.analyzer.Outcomes
+	if len(outcomes) == 0 {
+		outcomes = defaultCompositeOutcomes
+	}
+
+	result, err := analyzeTemplatedOutcomes(a.Title(), a.analyzer.Strict.BoolOrDefaultFalse(), outcomes, data, func(when string) (bool, error) {
+		return compareCompositeConditionalToActual(when, data)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		return []*AnalyzeResult{}, nil
+	}
+	return []*AnalyzeResult{result}, nil
+}
+
+// analyzeCompositeChild runs a child analyzer and rolls its results up into the lowest of their
+// states. An analyzer that fails to run, or that matches no outcome, fails. It returns nil when
+// the child is excluded.
+func analyzeCompositeChild(child *troubleshootv1beta2.CompositeChild, getFile getCollectedFileContents, findFiles getChildCollectedFileContents) (*compositeChildResult, error) {
+	weight := 1
+	if child.Weight != nil {
+		if *child.Weight < 0 {
+			return nil, errors.Errorf("weight %d of composite child must not be negative", *child.Weight)
+		}
+		weight = *child.Weight
+	}
+
+	analyzerInst := GetAnalyzer(&child.Analyze)
+	if analyzerInst == nil {
+		return nil, errors.New("composite child has no analyzer")
+	}
+	result := &compositeChildResult{
+		title:  analyzerInst.Title(),
+		state:  compositeFail,
+		weight: weight,
+	}
+
+	results, err := Analyze(context.Background(), &child.Analyze, getFile, findFiles)
+	if err != nil {
+		result.message = fmt.Sprintf("Analyzer Failed: %v", err)
+		return result, nil
+	}
+	if results == nil {
+		return nil, nil
+	}
+	if len(results) == 0 {
+		result.message = "no outcome matched"
+		return result, nil
+	}
+
+	result.state = compositePass
+	for _, r := range results {
+		state := compositeFail
+		switch {
+		case r.IsPass:
+			state = compositePass
+		case r.IsWarn:
+			state = compositeWarn
+		}
+		if state < result.state || result.message == "" {
+			result.state = state
+			result.message = r.Message
+		}
+	}
+	return result, nil
+}
+
+func minCompositeState(states []compositeState) compositeState {
+	lowest := compositePass
+	for _, state := range states {
+		if state < lowest {
+			lowest = state
+		}
+	}
+	return lowest
+}
+
+func maxCompositeState(states []compositeState) compositeState {
+	highest := compositeFail
+	for _, state := range states {
+		if state > highest {
+			highest = state
+		}
+	}
+	return highest
+}
+
+// compareCompositeConditionalToActual evaluates a when clause against the rolled up children.
+// Supported conditions are passed, warned and failed compared to true or false, e.g.
+// "failed == true", and score and met compared to a number, e.g. "score >= 75".
+func compareCompositeConditionalToActual(conditional string, data *compositeTemplateData) (bool, error) {
+	parts := strings.Fields(conditional)
+	if len(parts) != 3 {
+		return false, errors.Errorf("unable to parse conditional %q", conditional)
+	}
+
+	switch parts[0] {
+	case "passed":
+		return compareBoolConditional(parts[1], parts[2], data.state == compositePass)
+	case "warned":
+		return compareBoolConditional(parts[1], parts[2], data.state == compositeWarn)
+	case "failed":
+		return compareBoolConditional(parts[1], parts[2], data.state == compositeFail)
+	case "score":
+		return compareActualToWhen(parts[1]+" "+parts[2], data.Score)
+	case "met":
+		return compareActualToWhen(parts[1]+" "+parts[2], data.met)
+	}
+	return false, errors.Errorf("unknown condition %q, must be one of passed, warned, failed, score or met", parts[0])
+}
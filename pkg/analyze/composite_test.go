@@ -0,0 +1,173 @@
+package analyzer
+
+import (
+	"testing"
+
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/multitype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// statusChild is a text analyzer that passes when the status file of the collector is ok, and
+// fails, or warns with warn set, otherwise
+func statusChild(name string, warn bool, weight *int) *troubleshootv1beta2.CompositeChild {
+	notOk := &troubleshootv1beta2.Outcome{Fail: &troubleshootv1beta2.SingleOutcome{Message: "down"}}
+	if warn {
+		notOk = &troubleshootv1beta2.Outcome{Warn: &troubleshootv1beta2.SingleOutcome{Message: "degraded"}}
+	}
+	return &troubleshootv1beta2.CompositeChild{
+		Analyze: troubleshootv1beta2.Analyze{
+			TextAnalyze: &troubleshootv1beta2.TextAnalyze{
+				AnalyzeMeta:   troubleshootv1beta2.AnalyzeMeta{CheckName: name},
+				CollectorName: name,
+				FileName:      "status.txt",
+				RegexPattern:  "^ok$",
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{Pass: &troubleshootv1beta2.SingleOutcome{Message: "up"}},
+					notOk,
+				},
+			},
+		},
+		Weight: weight,
+	}
+}
+
+func TestAnalyzeComposite(t *testing.T) {
+	files := map[string][]byte{
+		"external-postgres/status.txt": []byte("connection refused"),
+		"embedded-postgres/status.txt": []byte("ok"),
+		"object-store/status.txt":      []byte("ok"),
+		"ha/status.txt":                []byte("single replica"),
+	}
+	three := 3
+
+	tests := []struct {
+		name     string
+		analyzer *troubleshootv1beta2.CompositeAnalyze
+		want     *AnalyzeResult
+		wantErr  string
+	}{
+		{
+			name: "any of",
+			analyzer: &troubleshootv1beta2.CompositeAnalyze{
+				AnalyzeMeta: troubleshootv1beta2.AnalyzeMeta{CheckName: "Postgres"},
+				AnyOf:       []*troubleshootv1beta2.CompositeChild{statusChild("external-postgres", false, nil), statusChild("embedded-postgres", false, nil)},
+			},
+			want: &AnalyzeResult{IsPass: true, Title: "Postgres", Message: "The requirements are met: embedded-postgres: up"},
+		},
+		{
+			name: "all of",
+			analyzer: &troubleshootv1beta2.CompositeAnalyze{
+				AllOf: []*troubleshootv1beta2.CompositeChild{statusChild("external-postgres", false, nil), statusChild("embedded-postgres", false, nil)},
+			},
+			want: &AnalyzeResult{IsFail: true, Title: "Composite", Message: "The requirements are not met: external-postgres: down"},
+		},
+		{
+			name: "none of",
+			analyzer: &troubleshootv1beta2.CompositeAnalyze{
+				NoneOf: []*troubleshootv1beta2.CompositeChild{statusChild("external-postgres", false, nil), statusChild("embedded-postgres", false, nil)},
+			},
+			want: &AnalyzeResult{IsFail: true, Title: "Composite", Message: "The requirements are not met: embedded-postgres: up"},
+		},
+		{
+			name: "warnings",
+			analyzer: &troubleshootv1beta2.CompositeAnalyze{
+				AllOf: []*troubleshootv1beta2.CompositeChild{statusChild("object-store", false, nil), statusChild("ha", true, nil)},
+			},
+			want: &AnalyzeResult{IsWarn: true, Title: "Composite", Message: "The requirements are met with warnings: ha: degraded"},
+		},
+		{
+			name: "all of the groups must be met",
+			analyzer: &troubleshootv1beta2.CompositeAnalyze{
+				AllOf: []*troubleshootv1beta2.CompositeChild{statusChild("object-store", false, nil)},
+				AnyOf: []*troubleshootv1beta2.CompositeChild{statusChild("external-postgres", false, nil)},
+			},
+			want: &AnalyzeResult{IsFail: true, Title: "Composite", Message: "The requirements are not met: external-postgres: down"},
+		},
+		{
+			name: "weighted score",
+			analyzer: &troubleshootv1beta2.CompositeAnalyze{
+				AllOf: []*troubleshootv1beta2.CompositeChild{statusChild("embedded-postgres", false, &three), statusChild("external-postgres", false, nil)},
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{Pass: &troubleshootv1beta2.SingleOutcome{When: "score >= 75", Message: "Score {{ .Score }}"}},
+					{Fail: &troubleshootv1beta2.SingleOutcome{Message: "Score {{ .Score }}"}},
+				},
+			},
+			want: &AnalyzeResult{IsPass: true, Title: "Composite", Message: "Score 75"},
+		},
+		{
+			name: "nested",
+			analyzer: &troubleshootv1beta2.CompositeAnalyze{
+				AllOf: []*troubleshootv1beta2.CompositeChild{
+					statusChild("object-store", false, nil),
+					{Analyze: troubleshootv1beta2.Analyze{Composite: &troubleshootv1beta2.CompositeAnalyze{
+						AnalyzeMeta: troubleshootv1beta2.AnalyzeMeta{CheckName: "Postgres"},
+						AnyOf:       []*troubleshootv1beta2.CompositeChild{statusChild("external-postgres", false, nil), statusChild("embedded-postgres", false, nil)},
+					}}},
+				},
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{Fail: &troubleshootv1beta2.SingleOutcome{When: "passed == false", Message: "{{ .Unmet }}"}},
+					{Pass: &troubleshootv1beta2.SingleOutcome{When: "met == 2", Message: "{{ .Met }}"}},
+				},
+			},
+			want: &AnalyzeResult{IsPass: true, Title: "Composite", Message: "object-store: up; Postgres: The requirements are met: embedded-postgres: up"},
+		},
+		{
+			name: "excluded children don't count",
+			analyzer: &troubleshootv1beta2.CompositeAnalyze{
+				AllOf: []*troubleshootv1beta2.CompositeChild{
+					statusChild("object-store", false, nil),
+					func() *troubleshootv1beta2.CompositeChild {
+						child := statusChild("external-postgres", false, nil)
+						child.TextAnalyze.Exclude = &multitype.BoolOrString{Type: multitype.Bool, BoolVal: true}
+						return child
+					}(),
+				},
+			},
+			want: &AnalyzeResult{IsPass: true, Title: "Composite", Message: "The requirements are met: object-store: up"},
+		},
+		{
+			name:     "no children",
+			analyzer: &troubleshootv1beta2.CompositeAnalyze{},
+			wantErr:  "composite analyzer requires at least one child in allOf, anyOf or noneOf",
+		},
+		{
+			name: "negative weight",
+			analyzer: &troubleshootv1beta2.CompositeAnalyze{
+				AnyOf: []*troubleshootv1beta2.CompositeChild{statusChild("object-store", false, func() *int { w := -1; return &w }())},
+			},
+			wantErr: "weight -1 of composite child must not be negative",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			getFile := func(filename string) ([]byte, error) {
+				return files[filename], nil
+			}
+			findFiles := func(filename string, _ []string) (map[string][]byte, error) {
+				if contents, ok := files[filename]; ok {
+					return map[string][]byte{filename: contents}, nil
+				}
+				return map[string][]byte{}, nil
+			}
+
+			a := AnalyzeComposite{analyzer: tt.analyzer}
+			results, err := a.Analyze(getFile, findFiles)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, results, 1)
+
+			got := results[0]
+			assert.Equal(t, tt.want.IsPass, got.IsPass)
+			assert.Equal(t, tt.want.IsWarn, got.IsWarn)
+			assert.Equal(t, tt.want.IsFail, got.IsFail)
+			assert.Equal(t, tt.want.Title, got.Title)
+			assert.Equal(t, tt.want.Message, got.Message)
+		})
+	}
+}
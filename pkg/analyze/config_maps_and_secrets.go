@@ -0,0 +1,446 @@
+package analyzer
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/troubleshoot/internal/util"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/constants"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// maxConfigObjectSize is the limit of the size of the data of a ConfigMap or Secret, which keeps
+// them within the size of objects etcd accepts
+const maxConfigObjectSize = 1024 * 1024
+
+var defaultConfigMapsAndSecretsOutcomes = []*troubleshootv1beta2.Outcome{
+	{Fail: &troubleshootv1beta2.SingleOutcome{When: "missing == true", Message: "{{ .Kind }} {{ .Namespace }}/{{ .Name }} does not exist but is used by {{ .ReferencedBy }}"}},
+	{Fail: &troubleshootv1beta2.SingleOutcome{When: "missingKeys > 0", Message: "{{ .Kind }} {{ .Namespace }}/{{ .Name }} does not have the keys {{ .MissingKeys }} used by {{ .ReferencedBy }}"}},
+	{Warn: &troubleshootv1beta2.SingleOutcome{When: "usage >= 80%", Message: "{{ .Kind }} {{ .Namespace }}/{{ .Name }} is {{ .Size }}, {{ .Usage }} of the 1MiB limit"}},
+	{Pass: &troubleshootv1beta2.SingleOutcome{Message: "ConfigMaps and Secrets used by pods exist, have the keys they use and are within the size limit"}},
+}
+
+var (
+	// configObjectNotFound matches the errors of pods that use a ConfigMap or Secret that does not
+	// exist, e.g. `secret "db-credentials" not found`
+	configObjectNotFound = regexp.MustCompile(`(?i)\b(secret|configmap) "([^"]+)" not found`)
+	// configObjectKeyNotFound matches the errors of pods that use a key a ConfigMap or Secret does
+	// not have, e.g. "couldn't find key password in Secret default/db-credentials"
+	configObjectKeyNotFound = regexp.MustCompile(`couldn't find key (\S+) in (Secret|ConfigMap) [^/\s]+/([^\s,]+)`)
+)
+
+// configMapsAndSecretsTemplateData is passed to the messages of the outcomes. It is empty when no
+// ConfigMap or Secret has a problem.
+type configMapsAndSecretsTemplateData struct {
+	Kind         string
+	Namespace    string
+	Name         string
+	Size         string
+	Usage        string
+	MissingKeys  string
+	ReferencedBy string
+
+	missing      bool
+	usage        float64
+	missingKeys  []string
+	referencedBy []string
+}
+
+type AnalyzeConfigMapsAndSecrets struct {
+	analyzer *troubleshootv1beta2.ConfigMapsAndSecrets
+}
+
+func (a *AnalyzeConfigMapsAndSecrets) Title() string {
+	if a.analyzer.CheckName != "" {
+		return a.analyzer.CheckName
+	}
+
+	return "ConfigMaps and Secrets"
+}
+
+func (a *AnalyzeConfigMapsAndSecrets) IsExcluded() (bool, error) {
+	return isExcluded(a.analyzer.Exclude)
+}
+
+func (a *AnalyzeConfigMapsAndSecrets) Analyze(getFile getCollectedFileContents, findFiles getChildCollectedFileContents) ([]*AnalyzeResult, error) {
+	objects := map[string]*configMapsAndSecretsTemplateData{}
+	objectData := func(kind string, namespace string, name string) *configMapsAndSecretsTemplateData {
+		key := fmt.Sprintf("%s/%s/%s", namespace, kind, name)
+		if objects[key] == nil {
+			objects[key] = &configMapsAndSecretsTemplateData{Kind: kind, Namespace: namespace, Name: name}
+		}
+		return objects[key]
+	}
+	inScope := func(namespace string) bool {
+		return len(a.analyzer.Namespaces) == 0 || slices.Contains(a.analyzer.Namespaces, namespace)
+	}
+
+	// the ConfigMaps of namespaces whose ConfigMaps were not collected are unknown, not missing
+	configMaps := map[string]map[string]corev1.ConfigMap{}
+	configMapFiles, err := findFiles(filepath.Join(constants.CLUSTER_RESOURCES_DIR, constants.CLUSTER_RESOURCES_CONFIGMAPS, "*.json"), nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read collected configmaps")
+	}
+	for name, content := range configMapFiles {
+		items, err := decodeCollectedItems[corev1.ConfigMap](content)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to unmarshal %s", name)
+		}
+		namespace := strings.TrimSuffix(filepath.Base(name), ".json")
+		configMaps[namespace] = map[string]corev1.ConfigMap{}
+		for _, configMap := range items {
+			configMaps[namespace][configMap.Name] = configMap
+			if !inScope(configMap.Namespace) {
+				continue
+			}
+			size := configMapSize(configMap)
+			if size > 0 {
+				data := objectData("ConfigMap", configMap.Namespace, configMap.Name)
+				data.usage = float64(size) / maxConfigObjectSize
+				data.Size = fmt.Sprintf("%.1fKiB", float64(size)/1024)
+				data.Usage = fmt.Sprintf("%.0f%%", data.usage*100)
+			}
+		}
+	}
+
+	podFiles, err := findFiles(filepath.Join(constants.CLUSTER_RESOURCES_DIR, constants.CLUSTER_RESOURCES_PODS, "*.json"), nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read collected pods")
+	}
+	pendingPods := map[string]map[string]string{}
+	for name, content := range podFiles {
+		pods, err := decodeCollectedItems[corev1.Pod](content)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to unmarshal %s", name)
+		}
+		for _, pod := range pods {
+			if !inScope(pod.Namespace) || pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+				continue
+			}
+			kind, workloadName := podWorkload(pod)
+			workload := fmt.Sprintf("%s %s", kind, workloadName)
+
+			namespaceConfigMaps, collected := configMaps[pod.Namespace]
+			for _, ref := range podConfigObjectRefs(pod) {
+				if ref.kind != "ConfigMap" || !collected || ref.optional {
+					continue
+				}
+				configMap, exists := namespaceConfigMaps[ref.name]
+				switch {
+				case !exists:
+					addConfigObjectProblem(objectData(ref.kind, pod.Namespace, ref.name), workload, "")
+				case ref.key != "" && !configMapHasKey(configMap, ref.key):
+					addConfigObjectProblem(objectData(ref.kind, pod.Namespace, ref.name), workload, ref.key)
+				}
+			}
+
+			// secrets are not collected, they are only known to be missing from the errors of pods
+			for _, status := range append(append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...) {
+				if status.State.Waiting != nil {
+					addConfigObjectErrors(objectData, status.State.Waiting.Message, pod.Namespace, workload, collected)
+				}
+			}
+			if pod.Status.Phase == corev1.PodPending {
+				if pendingPods[pod.Namespace] == nil {
+					pendingPods[pod.Namespace] = map[string]string{}
+				}
+				pendingPods[pod.Namespace][pod.Name] = workload
+			}
+		}
+	}
+
+	// volumes that cannot be mounted are reported in events, not in the statuses of the pods
+	for namespace, workloads := range pendingPods {
+		content, err := getFile(path.Join(constants.CLUSTER_RESOURCES_DIR, constants.CLUSTER_RESOURCES_EVENTS, namespace+".json"))
+		if err != nil || len(content) == 0 {
+			continue
+		}
+		events, err := decodeCollectedItems[corev1.Event](content)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to unmarshal events of namespace %s", namespace)
+		}
+		_, collected := configMaps[namespace]
+		for _, event := range events {
+			workload, ok := workloads[event.InvolvedObject.Name]
+			if !ok || event.InvolvedObject.Kind != "Pod" || event.Reason != "FailedMount" {
+				continue
+			}
+			addConfigObjectErrors(objectData, event.Message, namespace, workload, collected)
+		}
+	}
+
+	outcomes := a.analyzer.Outcomes
+	if len(outcomes) == 0 {
+		outcomes = defaultConfigMapsAndSecretsOutcomes
+	}
+
+	keys := []string{}
+	for key := range objects {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	// only the objects with problems are reported, not every ConfigMap of the cluster
+	results := []*AnalyzeResult{}
+	for _, key := range keys {
+		data := objects[key]
+		sort.Strings(data.missingKeys)
+		sort.Strings(data.referencedBy)
+		data.MissingKeys = strings.Join(data.missingKeys, ", ")
+		data.ReferencedBy = strings.Join(data.referencedBy, ", ")
+
+		result, err := a.analyzeObject(data, outcomes)
+		if err != nil {
+			return nil, err
+		}
+		if result != nil && !result.IsPass {
+			results = append(results, result)
+		}
+	}
+
+	if len(results) == 0 {
+		result, err := a.analyzeObject(&configMapsAndSecretsTemplateData{}, outcomes)
+		if err != nil {
+			return nil, err
+		}
+		if result != nil {
+			results = append(results, result)
+		}
+	}
+
+	return results, nil
+}
+
+// analyzeObject returns the result of the first outcome whose condition matches the ConfigMap or
+// Secret, or nil when none does
+func (a *AnalyzeConfigMapsAndSecrets) analyzeObject(data *configMapsAndSecretsTemplateData, outcomes []*troubleshootv1beta2.Outcome) (*AnalyzeResult, error) {
+	for _, outcome := range outcomes {
+		result := &AnalyzeResult{
+			IconKey: "kubernetes",
+			Strict:  a.analyzer.Strict.BoolOrDefaultFalse(),
+		}
+
+		var singleOutcome *troubleshootv1beta2.SingleOutcome
+		switch {
+		case outcome.Fail != nil:
+			singleOutcome = outcome.Fail
+			result.IsFail = true
+		case outcome.Warn != nil:
+			singleOutcome = outcome.Warn
+			result.IsWarn = true
+		case outcome.Pass != nil:
+			singleOutcome = outcome.Pass
+			result.IsPass = true
+		default:
+			continue
+		}
+
+		if singleOutcome.When != "" {
+			match, err := compareConfigMapsAndSecrets(data, singleOutcome.When)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to evaluate when %q", singleOutcome.When)
+			}
+			if !match {
+				continue
+			}
+		}
+
+		var err error
+		result.Title, err = util.RenderTemplate(a.Title(), data)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to render title template")
+		}
+		result.Message, err = util.RenderTemplate(singleOutcome.Message, data)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to render message template")
+		}
+		result.URI = singleOutcome.URI
+		result.Remediation = singleOutcome.Remediation
+		result.Condition = singleOutcome.When
+		if data.Name != "" {
+			result.InvolvedObject = &corev1.ObjectReference{
+				APIVersion: "v1",
+				Kind:       data.Kind,
+				Namespace:  data.Namespace,
+				Name:       data.Name,
+			}
+		}
+		return result, nil
+	}
+
+	return nil, nil
+}
+
+// compareConfigMapsAndSecrets evaluates a when clause against a ConfigMap or Secret. Supported
+// conditions are:
+//
+//   - "missing == <true|false>", whether pods use the object and it does not exist
+//   - "missingKeys <operator> <n>", the number of keys pods use that the object does not have
+//   - "usage <operator> <n>%", the size of the data of a ConfigMap relative to the 1MiB limit,
+//     e.g. "usage >= 90%"
+func compareConfigMapsAndSecrets(data *configMapsAndSecretsTemplateData, when string) (bool, error) {
+	parts := strings.Fields(when)
+	if len(parts) != 3 {
+		return false, fmt.Errorf("expected 3 parts in when %q, got %d", when, len(parts))
+	}
+	key, opString, expected := parts[0], parts[1], parts[2]
+
+	switch key {
+	case "missing":
+		expectedBool, err := strconv.ParseBool(expected)
+		if err != nil {
+			return false, errors.Wrapf(err, "failed to parse %q", expected)
+		}
+		switch opString {
+		case "==", "=":
+			return data.missing == expectedBool, nil
+		case "!=":
+			return data.missing != expectedBool, nil
+		}
+		return false, fmt.Errorf("unsupported operator %q for missing", opString)
+	case "missingKeys":
+		return compareActualToWhen(opString+" "+expected, len(data.missingKeys))
+	case "usage":
+		threshold, err := strconv.ParseFloat(strings.TrimSuffix(expected, "%"), 64)
+		if err != nil {
+			return false, errors.Wrapf(err, "failed to parse %q", expected)
+		}
+		return compareFloat(data.usage*100, opString, threshold)
+	}
+
+	return false, fmt.Errorf("unsupported condition %q, must be one of missing, missingKeys or usage", key)
+}
+
+// configObjectRef is a use of a ConfigMap or Secret, or of one of its keys, by a pod
+type configObjectRef struct {
+	kind     string
+	name     string
+	key      string
+	optional bool
+}
+
+// podConfigObjectRefs returns the ConfigMaps and Secrets the volumes and containers of the pod use
+func podConfigObjectRefs(pod corev1.Pod) []configObjectRef {
+	isOptional := func(optional *bool) bool {
+		return optional != nil && *optional
+	}
+	refs := []configObjectRef{}
+	addItems := func(kind string, name string, items []corev1.KeyToPath, optional *bool) {
+		refs = append(refs, configObjectRef{kind: kind, name: name, optional: isOptional(optional)})
+		for _, item := range items {
+			refs = append(refs, configObjectRef{kind: kind, name: name, key: item.Key, optional: isOptional(optional)})
+		}
+	}
+
+	for _, volume := range pod.Spec.Volumes {
+		if volume.ConfigMap != nil {
+			addItems("ConfigMap", volume.ConfigMap.Name, volume.ConfigMap.Items, volume.ConfigMap.Optional)
+		}
+		if volume.Secret != nil {
+			addItems("Secret", volume.Secret.SecretName, volume.Secret.Items, volume.Secret.Optional)
+		}
+		if volume.Projected != nil {
+			for _, source := range volume.Projected.Sources {
+				if source.ConfigMap != nil {
+					addItems("ConfigMap", source.ConfigMap.Name, source.ConfigMap.Items, source.ConfigMap.Optional)
+				}
+				if source.Secret != nil {
+					addItems("Secret", source.Secret.Name, source.Secret.Items, source.Secret.Optional)
+				}
+			}
+		}
+	}
+
+	for _, container := range append(append([]corev1.Container{}, pod.Spec.InitContainers...), pod.Spec.Containers...) {
+		for _, envFrom := range container.EnvFrom {
+			if envFrom.ConfigMapRef != nil {
+				refs = append(refs, configObjectRef{kind: "ConfigMap", name: envFrom.ConfigMapRef.Name, optional: isOptional(envFrom.ConfigMapRef.Optional)})
+			}
+			if envFrom.SecretRef != nil {
+				refs = append(refs, configObjectRef{kind: "Secret", name: envFrom.SecretRef.Name, optional: isOptional(envFrom.SecretRef.Optional)})
+			}
+		}
+		for _, env := range container.Env {
+			if env.ValueFrom == nil {
+				continue
+			}
+			if ref := env.ValueFrom.ConfigMapKeyRef; ref != nil {
+				refs = append(refs, configObjectRef{kind: "ConfigMap", name: ref.Name, key: ref.Key, optional: isOptional(ref.Optional)})
+			}
+			if ref := env.ValueFrom.SecretKeyRef; ref != nil {
+				refs = append(refs, configObjectRef{kind: "Secret", name: ref.Name, key: ref.Key, optional: isOptional(ref.Optional)})
+			}
+		}
+	}
+
+	return refs
+}
+
+// addConfigObjectErrors records the ConfigMaps and Secrets, or their keys, that an error of a pod
+// reports missing. ConfigMaps of namespaces whose ConfigMaps were collected are skipped since the
+// pods that use them are checked against what was collected.
+func addConfigObjectErrors(objectData func(string, string, string) *configMapsAndSecretsTemplateData, message string, namespace string, workload string, configMapsCollected bool) {
+	kindOf := func(kind string) string {
+		if strings.EqualFold(kind, "secret") {
+			return "Secret"
+		}
+		return "ConfigMap"
+	}
+
+	for _, match := range configObjectNotFound.FindAllStringSubmatch(message, -1) {
+		kind := kindOf(match[1])
+		if kind == "ConfigMap" && configMapsCollected {
+			continue
+		}
+		addConfigObjectProblem(objectData(kind, namespace, match[2]), workload, "")
+	}
+	for _, match := range configObjectKeyNotFound.FindAllStringSubmatch(message, -1) {
+		kind := kindOf(match[2])
+		if kind == "ConfigMap" && configMapsCollected {
+			continue
+		}
+		addConfigObjectProblem(objectData(kind, namespace, match[3]), workload, match[1])
+	}
+}
+
+// addConfigObjectProblem records that the workload uses the object, or its key when set, and it
+// does not exist
+func addConfigObjectProblem(data *configMapsAndSecretsTemplateData, workload string, key string) {
+	if key == "" {
+		data.missing = true
+	} else if !slices.Contains(data.missingKeys, key) {
+		data.missingKeys = append(data.missingKeys, key)
+	}
+	if !slices.Contains(data.referencedBy, workload) {
+		data.referencedBy = append(data.referencedBy, workload)
+	}
+}
+
+// configMapSize returns the size of the data of the ConfigMap as the API server validates it, the
+// sum of the lengths of its keys and values
+func configMapSize(configMap corev1.ConfigMap) int {
+	size := 0
+	for key, value := range configMap.Data {
+		size += len(key) + len(value)
+	}
+	for key, value := range configMap.BinaryData {
+		size += len(key) + len(value)
+	}
+	return size
+}
+
+func configMapHasKey(configMap corev1.ConfigMap, key string) bool {
+	if _, ok := configMap.Data[key]; ok {
+		return true
+	}
+	_, ok := configMap.BinaryData[key]
+	return ok
+}
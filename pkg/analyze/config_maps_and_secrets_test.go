@@ -0,0 +1,137 @@
+package analyzer
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyzeConfigMapsAndSecrets(t *testing.T) {
+	configMaps := `{"items": [
+	  {"metadata": {"name": "app-config", "namespace": "prod"}, "data": {"LOG_LEVEL": "info"}},
+	  {"metadata": {"name": "dashboards", "namespace": "prod"}, "data": {"dashboards.json": "` + strings.Repeat("x", 900*1024) + `"}}
+	]}`
+	pods := `{"items": [
+	  {
+	    "metadata": {"name": "web-5d4f8b7c9-abcde", "namespace": "prod", "labels": {"pod-template-hash": "5d4f8b7c9"},
+	      "ownerReferences": [{"kind": "ReplicaSet", "name": "web-5d4f8b7c9", "controller": true}]},
+	    "spec": {
+	      "volumes": [
+	        {"name": "config", "configMap": {"name": "app-config", "items": [{"key": "app.yaml", "path": "app.yaml"}]}},
+	        {"name": "extra", "configMap": {"name": "extra-config", "optional": true}},
+	        {"name": "certs", "projected": {"sources": [{"configMap": {"name": "ca-bundle"}}]}}
+	      ],
+	      "containers": [{"name": "web", "env": [
+	        {"name": "LOG_LEVEL", "valueFrom": {"configMapKeyRef": {"name": "app-config", "key": "LOG_LEVEL"}}},
+	        {"name": "DB_PASSWORD", "valueFrom": {"secretKeyRef": {"name": "db", "key": "password"}}}
+	      ]}]
+	    },
+	    "status": {"phase": "Pending", "containerStatuses": [{"name": "web", "state": {"waiting": {
+	      "reason": "CreateContainerConfigError", "message": "couldn't find key password in Secret prod/db"}}}]}
+	  },
+	  {
+	    "metadata": {"name": "worker-0", "namespace": "prod",
+	      "ownerReferences": [{"kind": "StatefulSet", "name": "worker", "controller": true}]},
+	    "spec": {"volumes": [{"name": "creds", "secret": {"secretName": "registry-creds"}}], "containers": [{"name": "worker"}]},
+	    "status": {"phase": "Pending"}
+	  },
+	  {
+	    "metadata": {"name": "done", "namespace": "prod"},
+	    "spec": {"containers": [{"name": "done", "envFrom": [{"configMapRef": {"name": "deleted-config"}}]}]},
+	    "status": {"phase": "Succeeded"}
+	  }
+	]}`
+	events := `{"items": [
+	  {"involvedObject": {"kind": "Pod", "name": "worker-0"}, "reason": "FailedMount",
+	    "message": "MountVolume.SetUp failed for volume \"creds\" : secret \"registry-creds\" not found"}
+	]}`
+
+	tests := []struct {
+		name     string
+		analyzer *troubleshootv1beta2.ConfigMapsAndSecrets
+		want     []*AnalyzeResult
+		wantErr  bool
+	}{
+		{
+			name:     "default outcomes",
+			analyzer: &troubleshootv1beta2.ConfigMapsAndSecrets{},
+			want: []*AnalyzeResult{
+				{IsFail: true, Message: "ConfigMap prod/app-config does not have the keys app.yaml used by Deployment web"},
+				{IsFail: true, Message: "ConfigMap prod/ca-bundle does not exist but is used by Deployment web"},
+				{IsWarn: true, Message: "ConfigMap prod/dashboards is 900.0KiB, 88% of the 1MiB limit"},
+				{IsFail: true, Message: "Secret prod/db does not have the keys password used by Deployment web"},
+				{IsFail: true, Message: "Secret prod/registry-creds does not exist but is used by StatefulSet worker"},
+			},
+		},
+		{
+			name: "custom outcomes",
+			analyzer: &troubleshootv1beta2.ConfigMapsAndSecrets{
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{Warn: &troubleshootv1beta2.SingleOutcome{When: "usage >= 90%", Message: "{{ .Name }} is large"}},
+					{Pass: &troubleshootv1beta2.SingleOutcome{Message: "ok"}},
+				},
+			},
+			want: []*AnalyzeResult{
+				{IsPass: true, Message: "ok"},
+			},
+		},
+		{
+			name:     "other namespace",
+			analyzer: &troubleshootv1beta2.ConfigMapsAndSecrets{Namespaces: []string{"staging"}},
+			want: []*AnalyzeResult{
+				{IsPass: true, Message: "ConfigMaps and Secrets used by pods exist, have the keys they use and are within the size limit"},
+			},
+		},
+		{
+			name: "unsupported condition",
+			analyzer: &troubleshootv1beta2.ConfigMapsAndSecrets{
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{Fail: &troubleshootv1beta2.SingleOutcome{When: "unused > 0"}},
+				},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := require.New(t)
+
+			getFile := func(path string) ([]byte, error) {
+				if path == "cluster-resources/events/prod.json" {
+					return []byte(events), nil
+				}
+				return nil, os.ErrNotExist
+			}
+			findFiles := func(glob string, _ []string) (map[string][]byte, error) {
+				switch glob {
+				case "cluster-resources/configmaps/*.json":
+					return map[string][]byte{"cluster-resources/configmaps/prod.json": []byte(configMaps)}, nil
+				case "cluster-resources/pods/*.json":
+					return map[string][]byte{"cluster-resources/pods/prod.json": []byte(pods)}, nil
+				}
+				return nil, nil
+			}
+
+			a := AnalyzeConfigMapsAndSecrets{analyzer: tt.analyzer}
+			results, err := a.Analyze(getFile, findFiles)
+			if tt.wantErr {
+				req.Error(err)
+				return
+			}
+			req.NoError(err)
+			req.Len(results, len(tt.want))
+
+			for i, want := range tt.want {
+				assert.Equal(t, want.IsPass, results[i].IsPass)
+				assert.Equal(t, want.IsWarn, results[i].IsWarn)
+				assert.Equal(t, want.IsFail, results[i].IsFail)
+				assert.Equal(t, want.Message, results[i].Message)
+			}
+		})
+	}
+}
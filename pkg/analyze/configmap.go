@@ -71,6 +71,7 @@ func (a *AnalyzeConfigMap) analyzeConfigMap(analyzer *troubleshootv1beta2.Analyz
 		result.IsFail = true
 		result.Message = failOutcome.Fail.Message
 		result.URI = failOutcome.Fail.URI
+		result.Remediation = failOutcome.Fail.Remediation
 
 		return &result, nil
 	}
@@ -80,6 +81,7 @@ func (a *AnalyzeConfigMap) analyzeConfigMap(analyzer *troubleshootv1beta2.Analyz
 			result.IsFail = true
 			result.Message = failOutcome.Fail.Message
 			result.URI = failOutcome.Fail.URI
+			result.Remediation = failOutcome.Fail.Remediation
 
 			return &result, nil
 		}
@@ -90,6 +92,7 @@ func (a *AnalyzeConfigMap) analyzeConfigMap(analyzer *troubleshootv1beta2.Analyz
 		if outcome.Pass != nil {
 			result.Message = outcome.Pass.Message
 			result.URI = outcome.Pass.URI
+			result.Remediation = outcome.Pass.Remediation
 		}
 	}
 
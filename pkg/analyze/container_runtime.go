@@ -67,6 +67,7 @@ func (a *AnalyzeContainerRuntime) analyzeContainerRuntime(analyzer *troubleshoot
 				result.IsFail = true
 				result.Message = outcome.Fail.Message
 				result.URI = outcome.Fail.URI
+				result.Remediation = outcome.Fail.Remediation
 
 				return result, nil
 			}
@@ -81,6 +82,7 @@ func (a *AnalyzeContainerRuntime) analyzeContainerRuntime(analyzer *troubleshoot
 					result.IsFail = true
 					result.Message = outcome.Fail.Message
 					result.URI = outcome.Fail.URI
+					result.Remediation = outcome.Fail.Remediation
 
 					return result, nil
 				}
@@ -90,6 +92,7 @@ func (a *AnalyzeContainerRuntime) analyzeContainerRuntime(analyzer *troubleshoot
 				result.IsWarn = true
 				result.Message = outcome.Warn.Message
 				result.URI = outcome.Warn.URI
+				result.Remediation = outcome.Warn.Remediation
 
 				return result, nil
 			}
@@ -104,6 +107,7 @@ func (a *AnalyzeContainerRuntime) analyzeContainerRuntime(analyzer *troubleshoot
 					result.IsWarn = true
 					result.Message = outcome.Warn.Message
 					result.URI = outcome.Warn.URI
+					result.Remediation = outcome.Warn.Remediation
 
 					return result, nil
 				}
@@ -113,6 +117,7 @@ func (a *AnalyzeContainerRuntime) analyzeContainerRuntime(analyzer *troubleshoot
 				result.IsPass = true
 				result.Message = outcome.Pass.Message
 				result.URI = outcome.Pass.URI
+				result.Remediation = outcome.Pass.Remediation
 
 				return result, nil
 			}
@@ -127,6 +132,7 @@ func (a *AnalyzeContainerRuntime) analyzeContainerRuntime(analyzer *troubleshoot
 					result.IsPass = true
 					result.Message = outcome.Pass.Message
 					result.URI = outcome.Pass.URI
+					result.Remediation = outcome.Pass.Remediation
 
 					return result, nil
 				}
@@ -59,6 +59,7 @@ func (a *AnalyzeCustomResourceDefinition) analyzeCustomResourceDefinition(analyz
 				if outcome.Pass != nil {
 					result.Message = outcome.Pass.Message
 					result.URI = outcome.Pass.URI
+					result.Remediation = outcome.Pass.Remediation
 				}
 			}
 
@@ -71,10 +72,12 @@ func (a *AnalyzeCustomResourceDefinition) analyzeCustomResourceDefinition(analyz
 			result.IsFail = true
 			result.Message = outcome.Fail.Message
 			result.URI = outcome.Fail.URI
+			result.Remediation = outcome.Fail.Remediation
 		} else if outcome.Warn != nil {
 			result.IsWarn = true
 			result.Message = outcome.Warn.Message
 			result.URI = outcome.Warn.URI
+			result.Remediation = outcome.Warn.Remediation
 		}
 	}
 
@@ -52,5 +52,55 @@ func compareDatabaseConditionalToActual(conditional string, result *collect.Data
 		return expectedRange(actual), nil
 	}
 
+	if result.Diagnostics != nil {
+		return compareDatabaseDiagnostics(parts[0], parts[1], parts[2], result.Diagnostics)
+	}
+
+	return false, nil
+}
+
+// compareDatabaseDiagnostics evaluates the conditions on the diagnostics of a database, e.g.
+// "replicationLagSeconds < 30" or "connectionUsage >= 80%". A condition on the replication lag is
+// false on primaries and on replicas whose lag is unknown, and one on connectionUsage is false when
+// the maximum number of connections is unknown.
+func compareDatabaseDiagnostics(key string, opString string, expected string, diagnostics *collect.DatabaseDiagnostics) (bool, error) {
+	switch key {
+	case "replica":
+		expectedBool, err := strconv.ParseBool(expected)
+		if err != nil {
+			return false, errors.Wrap(err, "failed to parse bool")
+		}
+		switch opString {
+		case "=", "==", "===":
+			return expectedBool == diagnostics.IsReplica, nil
+		case "!=", "!==":
+			return expectedBool != diagnostics.IsReplica, nil
+		}
+		return false, errors.Errorf("unsupported operator %q for replica", opString)
+	case "replicationLagSeconds":
+		if diagnostics.ReplicationLagSeconds == nil {
+			return false, nil
+		}
+		threshold, err := strconv.ParseFloat(expected, 64)
+		if err != nil {
+			return false, errors.Wrapf(err, "failed to parse %q", expected)
+		}
+		return compareFloat(*diagnostics.ReplicationLagSeconds, opString, threshold)
+	case "connections":
+		return compareActualToWhen(opString+" "+expected, diagnostics.Connections)
+	case "connectionUsage":
+		if diagnostics.MaxConnections == 0 {
+			return false, nil
+		}
+		threshold, err := strconv.ParseFloat(strings.TrimSuffix(expected, "%"), 64)
+		if err != nil {
+			return false, errors.Wrapf(err, "failed to parse %q", expected)
+		}
+		usage := float64(diagnostics.Connections) / float64(diagnostics.MaxConnections) * 100
+		return compareFloat(usage, opString, threshold)
+	case "slowQueries":
+		return compareActualToWhen(opString+" "+expected, int(diagnostics.SlowQueries))
+	}
+
 	return false, nil
 }
@@ -6,6 +6,7 @@ import (
 	"github.com/replicatedhq/troubleshoot/pkg/collect"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"k8s.io/utils/ptr"
 )
 
 func Test_compareDatabaseConditionalToActual(t *testing.T) {
@@ -57,6 +58,68 @@ func Test_compareDatabaseConditionalToActual(t *testing.T) {
 			},
 			expectedMatch: true,
 		},
+		{
+			name:        "replication lag 12s, want < 30",
+			conditional: "replicationLagSeconds < 30",
+			result: collect.DatabaseConnection{
+				IsConnected: true,
+				Diagnostics: &collect.DatabaseDiagnostics{IsReplica: true, ReplicationLagSeconds: ptr.To(12.5)},
+			},
+			expectedMatch: true,
+		},
+		{
+			name:        "replication lag unknown, want >= 30",
+			conditional: "replicationLagSeconds >= 30",
+			result: collect.DatabaseConnection{
+				IsConnected: true,
+				Diagnostics: &collect.DatabaseDiagnostics{IsReplica: true},
+			},
+			expectedMatch: false,
+		},
+		{
+			name:        "primary, want replica",
+			conditional: "replica == true",
+			result: collect.DatabaseConnection{
+				IsConnected: true,
+				Diagnostics: &collect.DatabaseDiagnostics{},
+			},
+			expectedMatch: false,
+		},
+		{
+			name:        "85 of 100 connections, want usage >= 80%",
+			conditional: "connectionUsage >= 80%",
+			result: collect.DatabaseConnection{
+				IsConnected: true,
+				Diagnostics: &collect.DatabaseDiagnostics{Connections: 85, MaxConnections: 100},
+			},
+			expectedMatch: true,
+		},
+		{
+			name:        "unknown max connections, want usage >= 80%",
+			conditional: "connectionUsage >= 80%",
+			result: collect.DatabaseConnection{
+				IsConnected: true,
+				Diagnostics: &collect.DatabaseDiagnostics{Connections: 85},
+			},
+			expectedMatch: false,
+		},
+		{
+			name:        "3 slow queries, want > 0",
+			conditional: "slowQueries > 0",
+			result: collect.DatabaseConnection{
+				IsConnected: true,
+				Diagnostics: &collect.DatabaseDiagnostics{SlowQueries: 3},
+			},
+			expectedMatch: true,
+		},
+		{
+			name:        "no diagnostics, want connections > 10",
+			conditional: "connections > 10",
+			result: collect.DatabaseConnection{
+				IsConnected: true,
+			},
+			expectedMatch: false,
+		},
 	}
 
 	for _, test := range tests {
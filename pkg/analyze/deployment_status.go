@@ -67,31 +67,38 @@ func analyzeOneDeploymentStatus(analyzer *troubleshootv1beta2.DeploymentStatus,
 	var result *AnalyzeResult
 	for _, collected := range files { // only 1 file here
 		var exists bool = true
-		var readyReplicas int
+		data := &workloadStatusTemplateData{Namespace: analyzer.Namespace, Name: analyzer.Name}
 
 		var deployments appsv1.DeploymentList
 		if err := json.Unmarshal(collected, &deployments); err != nil {
 			return nil, errors.Wrap(err, "failed to unmarshal deployment list")
 		}
 
-		var status *appsv1.DeploymentStatus
-		for _, deployment := range deployments.Items {
-			if deployment.Name == analyzer.Name {
-				status = deployment.Status.DeepCopy()
+		var deployment *appsv1.Deployment
+		for _, d := range deployments.Items {
+			if d.Name == analyzer.Name {
+				deployment = d.DeepCopy()
 			}
 		}
 
-		if status == nil {
+		if deployment == nil {
 			exists = false
-			readyReplicas = 0
 		} else {
-			readyReplicas = int(status.ReadyReplicas)
+			data.Replicas = 1 // default is 1
+			if deployment.Spec.Replicas != nil {
+				data.Replicas = int(*deployment.Spec.Replicas)
+			}
+			data.ReadyReplicas = int(deployment.Status.ReadyReplicas)
+			data.AvailableReplicas = int(deployment.Status.AvailableReplicas)
 		}
 
-		result, err = commonStatus(analyzer.Outcomes, analyzer.Name, "kubernetes_deployment_status", "https://troubleshoot.sh/images/analyzer-icons/deployment-status.svg?w=17&h=17", readyReplicas, exists, "deployment")
+		result, err = commonStatus(analyzer.Outcomes, analyzer.Name, "kubernetes_deployment_status", "https://troubleshoot.sh/images/analyzer-icons/deployment-status.svg?w=17&h=17", data.ReadyReplicas, exists, "deployment")
 		if err != nil {
 			return nil, errors.Wrap(err, "failed to process status")
 		}
+		if result, err = renderResultMessage(result, data); err != nil {
+			return nil, err
+		}
 	}
 
 	if result == nil {
@@ -136,6 +136,35 @@ func Test_deploymentStatus(t *testing.T) {
 				"cluster-resources/deployments/kube-system.json": []byte(kubeSystemDeployments),
 			},
 		},
+		{
+			name: "1/1, templated message",
+			analyzer: troubleshootv1beta2.DeploymentStatus{
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{
+						Pass: &troubleshootv1beta2.SingleOutcome{
+							When:    ">= 1",
+							Message: "{{ .Namespace }}/{{ .Name }} has {{ .ReadyReplicas }} of {{ .Replicas }} replicas ready",
+						},
+					},
+				},
+				Namespace: "default",
+				Name:      "kotsadm-api",
+			},
+			expectResult: []*AnalyzeResult{
+				{
+					IsPass:  true,
+					IsWarn:  false,
+					IsFail:  false,
+					Title:   "kotsadm-api Status",
+					Message: "default/kotsadm-api has 1 of 1 replicas ready",
+					IconKey: "kubernetes_deployment_status",
+					IconURI: "https://troubleshoot.sh/images/analyzer-icons/deployment-status.svg?w=17&h=17",
+				},
+			},
+			files: map[string][]byte{
+				"cluster-resources/deployments/default.json": []byte(defaultDeployments),
+			},
+		},
 		{
 			name: "1/1, pass when >= 2, warn when = 1, fail when 0",
 			analyzer: troubleshootv1beta2.DeploymentStatus{
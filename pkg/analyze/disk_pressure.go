@@ -0,0 +1,508 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/collect"
+	"github.com/replicatedhq/troubleshoot/pkg/constants"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	kubeletconfigv1beta1 "k8s.io/kubelet/config/v1beta1"
+	kubeletv1alpha1 "k8s.io/kubelet/pkg/apis/stats/v1alpha1"
+)
+
+const (
+	defaultDiskPressureMarginPercent = 5
+	defaultDiskPressureLargest       = 3
+	// defaultImageGCHighThresholdPercent and defaultImageGCLowThresholdPercent are the thresholds
+	// of the kubelet when its config was not collected
+	defaultImageGCHighThresholdPercent = 85
+	defaultImageGCLowThresholdPercent  = 80
+
+	evictionSignalNodeFsAvailable  = "nodefs.available"
+	evictionSignalImageFsAvailable = "imagefs.available"
+)
+
+// defaultEvictionHard are the hard eviction thresholds of the filesystems of the kubelet when its
+// config was not collected
+var defaultEvictionHard = map[string]string{
+	evictionSignalNodeFsAvailable:  "10%",
+	evictionSignalImageFsAvailable: "15%",
+}
+
+// DiskPressureDefaultOutcomes are evaluated when the analyzer sets no outcomes
+var DiskPressureDefaultOutcomes = []*troubleshootv1beta2.Outcome{
+	{
+		Fail: &troubleshootv1beta2.SingleOutcome{
+			When:    "pressuredNodes > 0",
+			Message: "{{ .PressuredNodes }} are under disk pressure and evict pods. {{ .Details }}",
+		},
+	},
+	{
+		Warn: &troubleshootv1beta2.SingleOutcome{
+			When:    "atRiskNodes > 0",
+			Message: "{{ .AtRiskNodes }} are close to disk pressure. {{ .Details }}",
+		},
+	},
+	{
+		Pass: &troubleshootv1beta2.SingleOutcome{
+			Message: "None of the {{ .Nodes }} nodes is under or close to disk pressure",
+		},
+	},
+}
+
+// diskPressureTemplateData is passed to the messages of the outcomes
+type diskPressureTemplateData struct {
+	// Nodes is the number of nodes
+	Nodes int
+	// PressuredNodes are the nodes under disk pressure, and AtRiskNodes those close to it, comma
+	// separated
+	PressuredNodes string
+	AtRiskNodes    string
+	// Details explain the pressure of each of the nodes under or close to disk pressure, with
+	// their largest images and emptyDir volumes, a sentence per node
+	Details string
+
+	report *diskPressureReport
+}
+
+// diskPressureReport is the disk pressure of the nodes, by name
+type diskPressureReport struct {
+	nodes []*nodeDiskPressure
+}
+
+// nodeDiskPressure is whether a node is under or at risk of disk pressure, the reasons why, and
+// the largest images and emptyDir volumes of the node, the largest first
+type nodeDiskPressure struct {
+	name             string
+	underPressure    bool
+	atRisk           bool
+	reasons          []string
+	largestImages    []diskUsage
+	largestEmptyDirs []diskUsage
+}
+
+type diskUsage struct {
+	name string
+	size int64
+}
+
+// kubeletDiskThresholds are the thresholds of the kubelet of a node for the usage of its
+// filesystems
+type kubeletDiskThresholds struct {
+	imageGCHighPercent int32
+	imageGCLowPercent  int32
+	evictionHard       map[string]string
+}
+
+// kubeletConfigz is the response of the /configz endpoint of the kubelet
+type kubeletConfigz struct {
+	KubeletConfig kubeletconfigv1beta1.KubeletConfiguration `json:"kubeletconfig"`
+}
+
+type AnalyzeDiskPressure struct {
+	analyzer *troubleshootv1beta2.DiskPressureAnalyze
+}
+
+func (a *AnalyzeDiskPressure) Title() string {
+	if a.analyzer.CheckName != "" {
+		return a.analyzer.CheckName
+	}
+	return "Disk Pressure"
+}
+
+func (a *AnalyzeDiskPressure) IsExcluded() (bool, error) {
+	return isExcluded(a.analyzer.Exclude)
+}
+
+func (a *AnalyzeDiskPressure) Analyze(getFile getCollectedFileContents, findFiles getChildCollectedFileContents) ([]*AnalyzeResult, error) {
+	nodesPath := fmt.Sprintf("%s/%s.json", constants.CLUSTER_RESOURCES_DIR, constants.CLUSTER_RESOURCES_NODES)
+	collected, err := getFile(nodesPath)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read collected file name: %s", nodesPath)
+	}
+	nodes := corev1.NodeList{}
+	if err := json.Unmarshal(collected, &nodes); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse %s", nodesPath)
+	}
+
+	summaries, err := findNodeSummaries(findFiles)
+	if err != nil {
+		return nil, err
+	}
+	thresholds, err := findKubeletDiskThresholds(findFiles)
+	if err != nil {
+		return nil, err
+	}
+	emptyDirs, err := findEmptyDirVolumes(findFiles)
+	if err != nil {
+		return nil, err
+	}
+
+	marginPercent := a.analyzer.MarginPercent
+	if marginPercent <= 0 {
+		marginPercent = defaultDiskPressureMarginPercent
+	}
+	largest := a.analyzer.Largest
+	if largest <= 0 {
+		largest = defaultDiskPressureLargest
+	}
+
+	report := &diskPressureReport{}
+	for _, node := range nodes.Items {
+		nodeThresholds, ok := thresholds[node.Name]
+		if !ok {
+			nodeThresholds = defaultKubeletDiskThresholds()
+		}
+		pressure, err := newNodeDiskPressure(node, summaries[node.Name], nodeThresholds, emptyDirs, marginPercent, largest)
+		if err != nil {
+			return nil, err
+		}
+		report.nodes = append(report.nodes, pressure)
+	}
+	data := newDiskPressureTemplateData(report)
+
+	outcomes := a.analyzer.Outcomes
+	if len(outcomes) == 0 {
+		outcomes = DiskPressureDefaultOutcomes
+	}
+
+	result, err := analyzeTemplatedOutcomes(a.Title(), a.analyzer.Strict.BoolOrDefaultFalse(), outcomes, data, func(when string) (bool, error) {
+		return compareDiskPressure(data.report, when)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		return []*AnalyzeResult{}, nil
+	}
+	return []*AnalyzeResult{result}, nil
+}
+
+// findNodeSummaries returns the stats summaries collected by the nodeMetrics collector, by node
+func findNodeSummaries(findFiles getChildCollectedFileContents) (map[string]*kubeletv1alpha1.Summary, error) {
+	collected, err := findFiles(filepath.Join("node-metrics", "*.json"), nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read collected node metrics")
+	}
+
+	summaries := map[string]*kubeletv1alpha1.Summary{}
+	for name, content := range collected {
+		summary := &kubeletv1alpha1.Summary{}
+		if err := json.Unmarshal(content, summary); err != nil {
+			return nil, errors.Wrapf(err, "failed to parse %s", name)
+		}
+		summaries[summary.Node.NodeName] = summary
+	}
+	return summaries, nil
+}
+
+// findKubeletDiskThresholds returns the thresholds of the kubelet configs collected by the
+// kubeletConfig collector, by node
+func findKubeletDiskThresholds(findFiles getChildCollectedFileContents) (map[string]kubeletDiskThresholds, error) {
+	collected, err := findFiles(path.Join(collect.KubeletConfigDir, "*.json"), nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read collected kubelet configs")
+	}
+
+	thresholds := map[string]kubeletDiskThresholds{}
+	for name, content := range collected {
+		if filepath.Base(name) == "errors.json" {
+			continue
+		}
+		configz := kubeletConfigz{}
+		if err := json.Unmarshal(content, &configz); err != nil {
+			return nil, errors.Wrapf(err, "failed to parse %s", name)
+		}
+		thresholds[strings.TrimSuffix(filepath.Base(name), ".json")] = newKubeletDiskThresholds(configz.KubeletConfig)
+	}
+	return thresholds, nil
+}
+
+func defaultKubeletDiskThresholds() kubeletDiskThresholds {
+	return kubeletDiskThresholds{
+		imageGCHighPercent: defaultImageGCHighThresholdPercent,
+		imageGCLowPercent:  defaultImageGCLowThresholdPercent,
+		evictionHard:       defaultEvictionHard,
+	}
+}
+
+// newKubeletDiskThresholds returns the thresholds of a kubelet config. The config reported by the
+// kubelet is defaulted, but the signals missing from a hard eviction map that is set have no
+// threshold.
+func newKubeletDiskThresholds(config kubeletconfigv1beta1.KubeletConfiguration) kubeletDiskThresholds {
+	thresholds := defaultKubeletDiskThresholds()
+	if config.ImageGCHighThresholdPercent != nil {
+		thresholds.imageGCHighPercent = *config.ImageGCHighThresholdPercent
+	}
+	if config.ImageGCLowThresholdPercent != nil {
+		thresholds.imageGCLowPercent = *config.ImageGCLowThresholdPercent
+	}
+	if config.EvictionHard != nil {
+		thresholds.evictionHard = config.EvictionHard
+	}
+	return thresholds
+}
+
+// findEmptyDirVolumes returns the emptyDir volumes of the pods collected by the clusterResources
+// collector, as namespace/pod/volume, or nil when no pods were collected
+func findEmptyDirVolumes(findFiles getChildCollectedFileContents) (map[string]bool, error) {
+	collected, err := findFiles(filepath.Join(constants.CLUSTER_RESOURCES_DIR, constants.CLUSTER_RESOURCES_PODS, "*.json"), nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read collected pods")
+	}
+	if len(collected) == 0 {
+		return nil, nil
+	}
+
+	emptyDirs := map[string]bool{}
+	for name, content := range collected {
+		// pods are saved as a list, or as an array by older versions
+		pods := []corev1.Pod{}
+		podList := corev1.PodList{}
+		if err := json.Unmarshal(content, &podList); err == nil {
+			pods = podList.Items
+		} else if err := json.Unmarshal(content, &pods); err != nil {
+			return nil, errors.Wrapf(err, "failed to parse %s", name)
+		}
+		for _, pod := range pods {
+			for _, volume := range pod.Spec.Volumes {
+				if volume.EmptyDir != nil {
+					emptyDirs[path.Join(pod.Namespace, pod.Name, volume.Name)] = true
+				}
+			}
+		}
+	}
+	return emptyDirs, nil
+}
+
+// newNodeDiskPressure explains the disk pressure of a node from its DiskPressure condition and,
+// when its summary was collected, the usage of its filesystems. The emptyDir volumes are those of
+// the summary that are not claims when emptyDirs is nil.
+func newNodeDiskPressure(node corev1.Node, summary *kubeletv1alpha1.Summary, thresholds kubeletDiskThresholds, emptyDirs map[string]bool, marginPercent int, largest int) (*nodeDiskPressure, error) {
+	pressure := &nodeDiskPressure{name: node.Name}
+
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == corev1.NodeDiskPressure && condition.Status == corev1.ConditionTrue {
+			pressure.underPressure = true
+			reason := "the DiskPressure condition is true"
+			if condition.Message != "" {
+				reason = fmt.Sprintf("%s, %s", reason, condition.Message)
+			}
+			pressure.reasons = append(pressure.reasons, reason)
+		}
+	}
+
+	if summary != nil {
+		if err := pressure.checkEviction("nodefs", summary.Node.Fs, thresholds.evictionHard[evictionSignalNodeFsAvailable], marginPercent); err != nil {
+			return nil, err
+		}
+
+		if summary.Node.Runtime != nil && summary.Node.Runtime.ImageFs != nil {
+			imageFs := summary.Node.Runtime.ImageFs
+			if err := pressure.checkEviction("imagefs", imageFs, thresholds.evictionHard[evictionSignalImageFsAvailable], marginPercent); err != nil {
+				return nil, err
+			}
+			pressure.checkImageGC(imageFs, thresholds)
+		}
+
+		pressure.largestEmptyDirs = largestEmptyDirs(summary, emptyDirs, largest)
+	}
+
+	pressure.largestImages = largestImages(node, largest)
+
+	return pressure, nil
+}
+
+// checkEviction compares the available space of a filesystem with the hard eviction threshold of
+// its signal, a quantity or a percentage of its capacity
+func (p *nodeDiskPressure) checkEviction(fsName string, fs *kubeletv1alpha1.FsStats, threshold string, marginPercent int) error {
+	if fs == nil || fs.CapacityBytes == nil || fs.AvailableBytes == nil || *fs.CapacityBytes == 0 || threshold == "" {
+		return nil
+	}
+	capacity, available := int64(*fs.CapacityBytes), int64(*fs.AvailableBytes)
+
+	thresholdBytes, err := evictionThresholdBytes(threshold, capacity)
+	if err != nil {
+		return errors.Wrapf(err, "failed to parse eviction threshold %q of %s of node %s", threshold, fsName, p.name)
+	}
+	marginBytes := capacity * int64(marginPercent) / 100
+	availablePercent := available * 100 / capacity
+
+	switch {
+	case available < thresholdBytes:
+		p.underPressure = true
+		p.reasons = append(p.reasons, fmt.Sprintf("%s has %s (%d%%) available, below the eviction threshold of %s", fsName, formatImageSize(available), availablePercent, threshold))
+	case available < thresholdBytes+marginBytes:
+		p.atRisk = true
+		p.reasons = append(p.reasons, fmt.Sprintf("%s has %s (%d%%) available, less than %d%% above the eviction threshold of %s", fsName, formatImageSize(available), availablePercent, marginPercent, threshold))
+	}
+	return nil
+}
+
+// checkImageGC compares the usage of the image filesystem with the threshold above which the
+// kubelet deletes unused images
+func (p *nodeDiskPressure) checkImageGC(imageFs *kubeletv1alpha1.FsStats, thresholds kubeletDiskThresholds) {
+	if imageFs.CapacityBytes == nil || imageFs.AvailableBytes == nil || *imageFs.CapacityBytes == 0 {
+		return
+	}
+	// garbage collection is disabled with a threshold of 100
+	if thresholds.imageGCHighPercent >= 100 {
+		return
+	}
+
+	capacity, available := int64(*imageFs.CapacityBytes), int64(*imageFs.AvailableBytes)
+	usedPercent := (capacity - available) * 100 / capacity
+	if usedPercent >= int64(thresholds.imageGCHighPercent) {
+		p.atRisk = true
+		p.reasons = append(p.reasons, fmt.Sprintf("imagefs is %d%% used, above the image garbage collection threshold of %d%%, unused images are deleted until it is %d%% used", usedPercent, thresholds.imageGCHighPercent, thresholds.imageGCLowPercent))
+	}
+}
+
+// evictionThresholdBytes returns the available bytes of a threshold of a filesystem of a capacity
+func evictionThresholdBytes(threshold string, capacity int64) (int64, error) {
+	if percentage, ok := strings.CutSuffix(threshold, "%"); ok {
+		value, err := strconv.ParseFloat(percentage, 64)
+		if err != nil {
+			return 0, err
+		}
+		return int64(value * float64(capacity) / 100), nil
+	}
+
+	quantity, err := resource.ParseQuantity(threshold)
+	if err != nil {
+		return 0, err
+	}
+	return quantity.Value(), nil
+}
+
+// largestImages returns the largest images of a node, named by a tag rather than a digest when
+// they have one
+func largestImages(node corev1.Node, largest int) []diskUsage {
+	images := []diskUsage{}
+	for _, image := range node.Status.Images {
+		if len(image.Names) == 0 {
+			continue
+		}
+		name := image.Names[0]
+		for _, n := range image.Names {
+			if !strings.Contains(n, "@") {
+				name = n
+				break
+			}
+		}
+		images = append(images, diskUsage{name: name, size: image.SizeBytes})
+	}
+	return largestDiskUsages(images, largest)
+}
+
+// largestEmptyDirs returns the largest emptyDir volumes of the pods of a summary
+func largestEmptyDirs(summary *kubeletv1alpha1.Summary, emptyDirs map[string]bool, largest int) []diskUsage {
+	volumes := []diskUsage{}
+	for _, pod := range summary.Pods {
+		for _, volume := range pod.VolumeStats {
+			if volume.UsedBytes == nil || volume.PVCRef != nil {
+				continue
+			}
+			name := path.Join(pod.PodRef.Namespace, pod.PodRef.Name, volume.Name)
+			if emptyDirs != nil && !emptyDirs[name] {
+				continue
+			}
+			volumes = append(volumes, diskUsage{name: name, size: int64(*volume.UsedBytes)})
+		}
+	}
+	return largestDiskUsages(volumes, largest)
+}
+
+func largestDiskUsages(usages []diskUsage, largest int) []diskUsage {
+	sort.SliceStable(usages, func(i, j int) bool {
+		return usages[i].size > usages[j].size
+	})
+	if len(usages) > largest {
+		usages = usages[:largest]
+	}
+	return usages
+}
+
+func newDiskPressureTemplateData(report *diskPressureReport) *diskPressureTemplateData {
+	formatUsages := func(usages []diskUsage) string {
+		formatted := []string{}
+		for _, usage := range usages {
+			formatted = append(formatted, fmt.Sprintf("%s (%s)", usage.name, formatImageSize(usage.size)))
+		}
+		return strings.Join(formatted, ", ")
+	}
+
+	details := []string{}
+	for _, node := range report.nodes {
+		if !node.underPressure && !node.atRisk {
+			continue
+		}
+		detail := fmt.Sprintf("%s: %s.", node.name, strings.Join(node.reasons, "; "))
+		if len(node.largestImages) > 0 {
+			detail += fmt.Sprintf(" Largest images: %s.", formatUsages(node.largestImages))
+		}
+		if len(node.largestEmptyDirs) > 0 {
+			detail += fmt.Sprintf(" Largest emptyDir volumes: %s.", formatUsages(node.largestEmptyDirs))
+		}
+		details = append(details, detail)
+	}
+
+	return &diskPressureTemplateData{
+		Nodes:          len(report.nodes),
+		PressuredNodes: strings.Join(report.pressuredNodes(), ", "),
+		AtRiskNodes:    strings.Join(report.atRiskNodes(), ", "),
+		Details:        strings.Join(details, " "),
+		report:         report,
+	}
+}
+
+// pressuredNodes returns the nodes under disk pressure
+func (r *diskPressureReport) pressuredNodes() []string {
+	nodes := []string{}
+	for _, node := range r.nodes {
+		if node.underPressure {
+			nodes = append(nodes, node.name)
+		}
+	}
+	return nodes
+}
+
+// atRiskNodes returns the nodes close to disk pressure, but not under it
+func (r *diskPressureReport) atRiskNodes() []string {
+	nodes := []string{}
+	for _, node := range r.nodes {
+		if node.atRisk && !node.underPressure {
+			nodes = append(nodes, node.name)
+		}
+	}
+	return nodes
+}
+
+// compareDiskPressure evaluates a when clause against the report. Supported conditions are the
+// counts nodes, pressuredNodes and atRiskNodes, e.g. "pressuredNodes > 0".
+func compareDiskPressure(report *diskPressureReport, when string) (bool, error) {
+	parts := strings.Fields(when)
+	if len(parts) != 3 {
+		return false, fmt.Errorf("expected 3 parts in when %q, got %d", when, len(parts))
+	}
+	key, condition := parts[0], parts[1]+" "+parts[2]
+
+	switch key {
+	case "nodes":
+		return compareActualToWhen(condition, len(report.nodes))
+	case "pressuredNodes":
+		return compareActualToWhen(condition, len(report.pressuredNodes()))
+	case "atRiskNodes":
+		return compareActualToWhen(condition, len(report.atRiskNodes()))
+	}
+	return false, fmt.Errorf("unsupported condition %q, must be one of nodes, pressuredNodes or atRiskNodes", key)
+}
@@ -0,0 +1,166 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"testing"
+
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubeletv1alpha1 "k8s.io/kubelet/pkg/apis/stats/v1alpha1"
+)
+
+const gib = uint64(1 << 30)
+
+func fsStats(capacityGiB uint64, availableGiB uint64) *kubeletv1alpha1.FsStats {
+	capacity, available, used := capacityGiB*gib, availableGiB*gib, (capacityGiB-availableGiB)*gib
+	return &kubeletv1alpha1.FsStats{CapacityBytes: &capacity, AvailableBytes: &available, UsedBytes: &used}
+}
+
+func diskPressureNode(name string, diskPressure bool, images ...corev1.ContainerImage) corev1.Node {
+	status := corev1.ConditionFalse
+	if diskPressure {
+		status = corev1.ConditionTrue
+	}
+	return corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status: corev1.NodeStatus{
+			Conditions: []corev1.NodeCondition{
+				{Type: corev1.NodeDiskPressure, Status: status, Message: "kubelet has disk pressure"},
+			},
+			Images: images,
+		},
+	}
+}
+
+func TestAnalyzeDiskPressure(t *testing.T) {
+	nodes := corev1.NodeList{Items: []corev1.Node{
+		diskPressureNode("node-1", true,
+			corev1.ContainerImage{Names: []string{"docker.io/library/postgres@sha256:0123", "docker.io/library/postgres:16"}, SizeBytes: 450 << 20},
+			corev1.ContainerImage{Names: []string{"registry.example.com/app/api:1.4.0"}, SizeBytes: 2 << 30},
+			corev1.ContainerImage{Names: []string{"registry.k8s.io/pause:3.10"}, SizeBytes: 300 << 10},
+		),
+		diskPressureNode("node-2", false),
+		diskPressureNode("node-3", false),
+	}}
+
+	emptyDirUsed, configMapUsed := 6*gib, 1*gib
+	summaries := []kubeletv1alpha1.Summary{
+		{
+			Node: kubeletv1alpha1.NodeStats{NodeName: "node-1", Fs: fsStats(100, 8)},
+			Pods: []kubeletv1alpha1.PodStats{
+				{
+					PodRef: kubeletv1alpha1.PodReference{Namespace: "app", Name: "api-0"},
+					VolumeStats: []kubeletv1alpha1.VolumeStats{
+						{Name: "cache", FsStats: kubeletv1alpha1.FsStats{UsedBytes: &emptyDirUsed}},
+						{Name: "config", FsStats: kubeletv1alpha1.FsStats{UsedBytes: &configMapUsed}},
+					},
+				},
+			},
+		},
+		{
+			Node: kubeletv1alpha1.NodeStats{NodeName: "node-2", Fs: fsStats(100, 50), Runtime: &kubeletv1alpha1.RuntimeStats{ImageFs: fsStats(100, 18)}},
+		},
+		{
+			Node: kubeletv1alpha1.NodeStats{NodeName: "node-3", Fs: fsStats(100, 50), Runtime: &kubeletv1alpha1.RuntimeStats{ImageFs: fsStats(100, 50)}},
+		},
+	}
+
+	pods := corev1.PodList{Items: []corev1.Pod{
+		{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "app", Name: "api-0"},
+			Spec: corev1.PodSpec{Volumes: []corev1.Volume{
+				{Name: "cache", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}},
+				{Name: "config", VolumeSource: corev1.VolumeSource{ConfigMap: &corev1.ConfigMapVolumeSource{}}},
+			}},
+		},
+	}}
+
+	marshal := func(v interface{}) []byte {
+		b, err := json.Marshal(v)
+		require.NoError(t, err)
+		return b
+	}
+	getFile := func(filename string) ([]byte, error) {
+		require.Equal(t, "cluster-resources/nodes.json", filename)
+		return marshal(nodes), nil
+	}
+	findFiles := func(pattern string, _ []string) (map[string][]byte, error) {
+		switch pattern {
+		case "node-metrics/*.json":
+			files := map[string][]byte{}
+			for _, summary := range summaries {
+				files["node-metrics/"+summary.Node.NodeName+".json"] = marshal(summary)
+			}
+			return files, nil
+		case "kubelet-config/*.json":
+			return map[string][]byte{
+				"kubelet-config/node-2.json": []byte(`{"kubeletconfig": {"imageGCHighThresholdPercent": 80, "imageGCLowThresholdPercent": 70, "evictionHard": {"imagefs.available": "15%", "nodefs.available": "5Gi"}}}`),
+				"kubelet-config/errors.json": []byte(`["failed to get the kubelet config of node node-3"]`),
+			}, nil
+		case "cluster-resources/pods/*.json":
+			return map[string][]byte{"cluster-resources/pods/app.json": marshal(pods)}, nil
+		}
+		return map[string][]byte{}, nil
+	}
+
+	a := AnalyzeDiskPressure{analyzer: &troubleshootv1beta2.DiskPressureAnalyze{Largest: 2}}
+	results, err := a.Analyze(getFile, findFiles)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+
+	assert.True(t, results[0].IsFail)
+	assert.Equal(t, "Disk Pressure", results[0].Title)
+	assert.Equal(t, "node-1 are under disk pressure and evict pods. "+
+		"node-1: the DiskPressure condition is true, kubelet has disk pressure; nodefs has 8.0GiB (8%) available, below the eviction threshold of 10%. "+
+		"Largest images: registry.example.com/app/api:1.4.0 (2.0GiB), docker.io/library/postgres:16 (450.0MiB). "+
+		"Largest emptyDir volumes: app/api-0/cache (6.0GiB). "+
+		"node-2: imagefs has 18.0GiB (18%) available, less than 5% above the eviction threshold of 15%; "+
+		"imagefs is 82% used, above the image garbage collection threshold of 80%, unused images are deleted until it is 70% used.",
+		results[0].Message)
+}
+
+func Test_compareDiskPressure(t *testing.T) {
+	report := &diskPressureReport{nodes: []*nodeDiskPressure{
+		{name: "node-1", underPressure: true, atRisk: true},
+		{name: "node-2", atRisk: true},
+		{name: "node-3"},
+	}}
+
+	tests := []struct {
+		when    string
+		want    bool
+		wantErr bool
+	}{
+		{when: "nodes == 3", want: true},
+		{when: "pressuredNodes == 1", want: true},
+		{when: "atRiskNodes > 1", want: false},
+		{when: "evictions > 0", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.when, func(t *testing.T) {
+			got, err := compareDiskPressure(report, tt.when)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_evictionThresholdBytes(t *testing.T) {
+	got, err := evictionThresholdBytes("10%", 200<<30)
+	require.NoError(t, err)
+	assert.Equal(t, int64(20<<30), got)
+
+	got, err = evictionThresholdBytes("5Gi", 200<<30)
+	require.NoError(t, err)
+	assert.Equal(t, int64(5<<30), got)
+
+	_, err = evictionThresholdBytes("ten%", 200<<30)
+	assert.Error(t, err)
+}
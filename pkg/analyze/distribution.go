@@ -244,6 +244,7 @@ func (a *AnalyzeDistribution) analyzeDistribution(analyzer *troubleshootv1beta2.
 				result.IsFail = true
 				result.Message = outcome.Fail.Message
 				result.URI = outcome.Fail.URI
+				result.Remediation = outcome.Fail.Remediation
 
 				return result, nil
 			}
@@ -257,6 +258,7 @@ func (a *AnalyzeDistribution) analyzeDistribution(analyzer *troubleshootv1beta2.
 				result.IsFail = true
 				result.Message = outcome.Fail.Message
 				result.URI = outcome.Fail.URI
+				result.Remediation = outcome.Fail.Remediation
 
 				return result, nil
 			}
@@ -265,6 +267,7 @@ func (a *AnalyzeDistribution) analyzeDistribution(analyzer *troubleshootv1beta2.
 				result.IsWarn = true
 				result.Message = outcome.Warn.Message
 				result.URI = outcome.Warn.URI
+				result.Remediation = outcome.Warn.Remediation
 
 				return result, nil
 			}
@@ -278,6 +281,7 @@ func (a *AnalyzeDistribution) analyzeDistribution(analyzer *troubleshootv1beta2.
 				result.IsWarn = true
 				result.Message = outcome.Warn.Message
 				result.URI = outcome.Warn.URI
+				result.Remediation = outcome.Warn.Remediation
 
 				return result, nil
 			}
@@ -286,6 +290,7 @@ func (a *AnalyzeDistribution) analyzeDistribution(analyzer *troubleshootv1beta2.
 				result.IsPass = true
 				result.Message = outcome.Pass.Message
 				result.URI = outcome.Pass.URI
+				result.Remediation = outcome.Pass.Remediation
 
 				return result, nil
 			}
@@ -299,6 +304,7 @@ func (a *AnalyzeDistribution) analyzeDistribution(analyzer *troubleshootv1beta2.
 				result.IsPass = true
 				result.Message = outcome.Pass.Message
 				result.URI = outcome.Pass.URI
+				result.Remediation = outcome.Pass.Remediation
 
 				return result, nil
 			}
@@ -0,0 +1,110 @@
+package analyzer
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/constants"
+)
+
+// DocURIPrefix is the prefix of the URIs, and remediation doc links, of outcomes that reference
+// the documentation shipped with the spec, e.g. doc:postgres-connections
+const DocURIPrefix = "doc:"
+
+var docIDRegexp = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// DocPath is the path of a document in the bundle
+func DocPath(id string) string {
+	return path.Join(constants.DOCS_DIR, id+".md")
+}
+
+// DocFiles returns the documents of the spec as markdown files keyed by their path in the bundle.
+// Documents with a title start with it as a heading.
+func DocFiles(docs []*troubleshootv1beta2.Documentation) (map[string][]byte, error) {
+	files := map[string][]byte{}
+	for i, doc := range docs {
+		if doc == nil {
+			continue
+		}
+		if !docIDRegexp.MatchString(doc.ID) {
+			return nil, errors.Errorf("documentation %d: invalid id %q", i, doc.ID)
+		}
+		docPath := DocPath(doc.ID)
+		if _, ok := files[docPath]; ok {
+			return nil, errors.Errorf("documentation %d: duplicate id %q", i, doc.ID)
+		}
+
+		markdown := doc.Markdown
+		if doc.Title != "" {
+			markdown = fmt.Sprintf("# %s\n\n%s", doc.Title, markdown)
+		}
+		if !strings.HasSuffix(markdown, "\n") {
+			markdown += "\n"
+		}
+		files[docPath] = []byte(markdown)
+	}
+	return files, nil
+}
+
+// ResolveDocs replaces the doc: URIs and remediation doc links of the results with the path of
+// the document in the bundle, and sets the documentation of the results to its markdown, so that
+// the guidance is available without following external URLs. References to documents the spec
+// does not ship are left as they are and returned in the error.
+func ResolveDocs(results []*AnalyzeResult, docs []*troubleshootv1beta2.Documentation) error {
+	byID := map[string]*troubleshootv1beta2.Documentation{}
+	for _, doc := range docs {
+		if doc != nil {
+			byID[doc.ID] = doc
+		}
+	}
+
+	unknown := []string{}
+	resolve := func(uri string) (string, *troubleshootv1beta2.Documentation) {
+		if !strings.HasPrefix(uri, DocURIPrefix) {
+			return uri, nil
+		}
+		id := strings.TrimPrefix(uri, DocURIPrefix)
+		doc, ok := byID[id]
+		if !ok {
+			if !slices.Contains(unknown, id) {
+				unknown = append(unknown, id)
+			}
+			return uri, nil
+		}
+		return DocPath(id), doc
+	}
+
+	for _, result := range results {
+		if result == nil {
+			continue
+		}
+
+		uri, doc := resolve(result.URI)
+		result.URI = uri
+		if result.Remediation != nil {
+			// the remediation is shared with the outcome of the spec, it is copied before it changes
+			docLink, remediationDoc := resolve(result.Remediation.DocLink)
+			if docLink != result.Remediation.DocLink {
+				remediation := *result.Remediation
+				remediation.DocLink = docLink
+				result.Remediation = &remediation
+			}
+			if doc == nil {
+				doc = remediationDoc
+			}
+		}
+		if doc != nil {
+			result.Documentation = doc.Markdown
+		}
+	}
+
+	if len(unknown) > 0 {
+		return errors.Errorf("outcomes reference documentation the spec does not ship: %s", strings.Join(unknown, ", "))
+	}
+	return nil
+}
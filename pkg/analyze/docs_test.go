@@ -0,0 +1,60 @@
+package analyzer
+
+import (
+	"testing"
+
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDocFiles(t *testing.T) {
+	files, err := DocFiles([]*troubleshootv1beta2.Documentation{
+		{ID: "postgres-connections", Title: "Too many connections", Markdown: "Raise `max_connections`."},
+		{ID: "disk.full", Markdown: "Free space on the volume.\n"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]byte{
+		"docs/postgres-connections.md": []byte("# Too many connections\n\nRaise `max_connections`.\n"),
+		"docs/disk.full.md":            []byte("Free space on the volume.\n"),
+	}, files)
+
+	_, err = DocFiles([]*troubleshootv1beta2.Documentation{{ID: "../etc/passwd"}})
+	assert.EqualError(t, err, `documentation 0: invalid id "../etc/passwd"`)
+
+	_, err = DocFiles([]*troubleshootv1beta2.Documentation{{ID: "disk"}, {ID: "disk"}})
+	assert.EqualError(t, err, `documentation 1: duplicate id "disk"`)
+}
+
+func TestResolveDocs(t *testing.T) {
+	docs := []*troubleshootv1beta2.Documentation{
+		{ID: "postgres-connections", Markdown: "Raise `max_connections`."},
+		{ID: "disk-full", Markdown: "Free space on the volume."},
+	}
+	remediation := &troubleshootv1beta2.Remediation{Command: "df -h", DocLink: "doc:disk-full"}
+	results := []*AnalyzeResult{
+		{IsFail: true, Title: "Postgres", URI: "doc:postgres-connections"},
+		{IsWarn: true, Title: "Disk", URI: "https://example.com/disk", Remediation: remediation},
+		{IsPass: true, Title: "Version", URI: "https://example.com/version"},
+		{IsFail: true, Title: "Redis", URI: "doc:redis-memory"},
+		{IsFail: true, Title: "Redis replica", URI: "doc:redis-memory"},
+	}
+
+	err := ResolveDocs(results, docs)
+	assert.EqualError(t, err, "outcomes reference documentation the spec does not ship: redis-memory")
+
+	assert.Equal(t, "docs/postgres-connections.md", results[0].URI)
+	assert.Equal(t, "Raise `max_connections`.", results[0].Documentation)
+
+	assert.Equal(t, "https://example.com/disk", results[1].URI)
+	assert.Equal(t, &troubleshootv1beta2.Remediation{Command: "df -h", DocLink: "docs/disk-full.md"}, results[1].Remediation)
+	assert.Equal(t, "Free space on the volume.", results[1].Documentation)
+	// the remediation of the outcome is left as it is
+	assert.Equal(t, "doc:disk-full", remediation.DocLink)
+
+	assert.Equal(t, "https://example.com/version", results[2].URI)
+	assert.Empty(t, results[2].Documentation)
+
+	assert.Equal(t, "doc:redis-memory", results[3].URI)
+	assert.Empty(t, results[3].Documentation)
+}
@@ -2,7 +2,6 @@ package analyzer
 
 import (
 	"archive/tar"
-	"compress/gzip"
 	"context"
 	"io"
 	"io/fs"
@@ -13,8 +12,10 @@ import (
 	"github.com/pkg/errors"
 	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
 	troubleshootscheme "github.com/replicatedhq/troubleshoot/pkg/client/troubleshootclientset/scheme"
+	"github.com/replicatedhq/troubleshoot/pkg/collect"
 	"github.com/replicatedhq/troubleshoot/pkg/constants"
 	"github.com/replicatedhq/troubleshoot/pkg/docrewrite"
+	"github.com/replicatedhq/troubleshoot/pkg/loader"
 	"github.com/replicatedhq/troubleshoot/pkg/types"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/klog/v2"
@@ -90,6 +91,57 @@ func DownloadAndAnalyze(bundleURL string, analyzersSpec string) ([]*AnalyzeResul
 	return AnalyzeLocal(context.Background(), rootDir, analyzers, hostAnalyzers)
 }
 
+// AnalyzeBundle runs the analyzers of the specs against a support bundle that was collected
+// earlier, so that new or improved analyzers can be evaluated without collecting the bundle
+// again. The bundle is an archive, a URL to one, or an extracted bundle directory. Specs are
+// YAML documents of Analyzer or SupportBundle kinds, or Secrets and ConfigMaps holding them,
+// and their analyzers are merged. The default analyzers are run when there are no specs.
+func AnalyzeBundle(ctx context.Context, bundlePath string, specs ...string) ([]*AnalyzeResult, error) {
+	analyzers, hostAnalyzers, err := loadAnalyzers(ctx, specs)
+	if err != nil {
+		return nil, err
+	}
+
+	if info, err := os.Stat(bundlePath); err == nil && info.IsDir() {
+		return AnalyzeLocal(ctx, bundlePath, analyzers, hostAnalyzers)
+	}
+
+	tmpDir, rootDir, err := DownloadAndExtractSupportBundle(bundlePath)
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	return AnalyzeLocal(ctx, rootDir, analyzers, hostAnalyzers)
+}
+
+func loadAnalyzers(ctx context.Context, specs []string) ([]*troubleshootv1beta2.Analyze, []*troubleshootv1beta2.HostAnalyze, error) {
+	if len(specs) == 0 {
+		return getDefaultAnalyzers()
+	}
+
+	kinds, err := loader.LoadSpecs(ctx, loader.LoadOptions{RawSpecs: specs, Strict: true})
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to load analyzer specs")
+	}
+
+	analyzers := []*troubleshootv1beta2.Analyze{}
+	hostAnalyzers := []*troubleshootv1beta2.HostAnalyze{}
+	for _, sb := range kinds.SupportBundlesV1Beta2 {
+		analyzers = append(analyzers, sb.Spec.Analyzers...)
+		hostAnalyzers = append(hostAnalyzers, sb.Spec.HostAnalyzers...)
+	}
+	for _, a := range kinds.AnalyzersV1Beta2 {
+		analyzers = append(analyzers, a.Spec.Analyzers...)
+		hostAnalyzers = append(hostAnalyzers, a.Spec.HostAnalyzers...)
+	}
+	if len(analyzers) == 0 && len(hostAnalyzers) == 0 {
+		return nil, nil, errors.New("no analyzers found in the specs")
+	}
+
+	return DedupAnalyzers(analyzers), hostAnalyzers, nil
+}
+
 func DownloadAndExtractSupportBundle(bundleURL string) (string, string, error) {
 	tmpDir, err := os.MkdirTemp("", "troubleshoot-k8s")
 	if err != nil {
@@ -160,12 +212,11 @@ func downloadTroubleshootBundle(bundleURL string, destDir string) error {
 func ExtractTroubleshootBundle(reader io.Reader, destDir string) error {
 	// TODO: Move to separate package e.g support bundle package, or sbutils
 	// if there are cyclic dependencies
-	gzReader, err := gzip.NewReader(reader)
+	tarReader, err := collect.NewBundleArchiveReader(reader)
 	if err != nil {
-		return errors.Wrap(err, "failed to create gzip reader")
+		return err
 	}
-
-	tarReader := tar.NewReader(gzReader)
+	defer tarReader.Close()
 	for {
 		header, err := tarReader.Next()
 		if err == io.EOF {
@@ -1,12 +1,14 @@
 package analyzer
 
 import (
+	"context"
 	"os"
 	"path/filepath"
 	"testing"
 
 	"github.com/replicatedhq/troubleshoot/internal/testutils"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestDownloadAndExtractSupportBundle(t *testing.T) {
@@ -47,3 +49,58 @@ func TestDownloadAndExtractSupportBundle(t *testing.T) {
 		})
 	}
 }
+
+func TestAnalyzeBundle(t *testing.T) {
+	bundleDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(bundleDir, "version.yaml"), []byte("apiVersion: troubleshoot.sh/v1beta2\nkind: SupportBundle\n"), 0644))
+	require.NoError(t, os.MkdirAll(filepath.Join(bundleDir, "image-signatures"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(bundleDir, "image-signatures", "signatures.json"), []byte(`{
+		"images": [
+			{"image": "example/api:1.0", "signatures": [{"verified": true, "signature": "MEUCIQ"}]},
+			{"image": "example/worker:1.0", "signatures": [{"verified": false, "error": "no signatures found for this image"}]}
+		]
+	}`), 0644))
+
+	spec := `apiVersion: troubleshoot.sh/v1beta2
+kind: Analyzer
+metadata:
+  name: image-signatures
+spec:
+  analyzers:
+    - imageSignatures:
+        collectorName: signatures
+        outcomes:
+          - fail:
+              when: "unsigned > 0"
+              message: "{{ .UnsignedImages }} is not signed"
+          - pass:
+              message: All images are signed
+---
+apiVersion: troubleshoot.sh/v1beta2
+kind: Redactor
+metadata:
+  name: ignored
+spec:
+  redactors: []
+`
+
+	results, err := AnalyzeBundle(context.Background(), bundleDir, spec)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "Image Signatures", results[0].Title)
+	assert.True(t, results[0].IsFail)
+	assert.Equal(t, "example/worker:1.0 is not signed", results[0].Message)
+
+	_, err = AnalyzeBundle(context.Background(), bundleDir, `apiVersion: troubleshoot.sh/v1beta2
+kind: Redactor
+metadata:
+  name: redactors-only
+spec:
+  redactors: []
+`)
+	assert.EqualError(t, err, "no analyzers found in the specs")
+
+	results, err = AnalyzeBundle(context.Background(), filepath.Join(testutils.FileDir(), "../../testdata/supportbundle/support-bundle.tar.gz"))
+	require.NoError(t, err)
+	assert.NotEmpty(t, results)
+}
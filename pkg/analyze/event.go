@@ -158,6 +158,7 @@ func analyzeEventResult(event *corev1.Event, outcomes []*troubleshootv1beta2.Out
 				result.IsFail = true
 				result.Message = decorateMessage(o.Fail.Message, event)
 				result.URI = o.Fail.URI
+				result.Remediation = o.Fail.Remediation
 				break
 			}
 		}
@@ -171,6 +172,7 @@ func analyzeEventResult(event *corev1.Event, outcomes []*troubleshootv1beta2.Out
 				result.IsWarn = true
 				result.Message = decorateMessage(o.Warn.Message, event)
 				result.URI = o.Warn.URI
+				result.Remediation = o.Warn.Remediation
 				break
 			}
 		}
@@ -184,6 +186,7 @@ func analyzeEventResult(event *corev1.Event, outcomes []*troubleshootv1beta2.Out
 				result.IsPass = true
 				result.Message = decorateMessage(o.Pass.Message, event)
 				result.URI = o.Pass.URI
+				result.Remediation = o.Pass.Remediation
 				break
 			}
 		}
@@ -0,0 +1,138 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/collect"
+)
+
+// Ensure `AnalyzeHostActivityCapture` implements `HostAnalyzer` interface at compile time.
+var _ HostAnalyzer = (*AnalyzeHostActivityCapture)(nil)
+
+type AnalyzeHostActivityCapture struct {
+	hostAnalyzer *troubleshootv1beta2.ActivityCaptureAnalyze
+}
+
+func (a *AnalyzeHostActivityCapture) Title() string {
+	return hostAnalyzerTitleOrDefault(a.hostAnalyzer.AnalyzeMeta, "Activity Capture")
+}
+
+func (a *AnalyzeHostActivityCapture) IsExcluded() (bool, error) {
+	return isExcluded(a.hostAnalyzer.Exclude)
+}
+
+func (a *AnalyzeHostActivityCapture) Analyze(
+	getCollectedFileContents func(string) ([]byte, error), findFiles getChildCollectedFileContents,
+) ([]*AnalyzeResult, error) {
+	result := AnalyzeResult{Title: a.Title()}
+
+	collectedContents, err := retrieveCollectedContents(
+		getCollectedFileContents,
+		collect.HostActivityCapturePath,
+		collect.NodeInfoBaseDir,
+		collect.HostActivityCaptureFileName,
+	)
+	if err != nil {
+		return []*AnalyzeResult{&result}, err
+	}
+
+	results, err := analyzeMeasuredHostCollectorResults(collectedContents, a.hostAnalyzer.Outcomes, a.CheckCondition, a.measure, a.Title())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to analyze activity capture")
+	}
+
+	return results, nil
+}
+
+// CheckCondition evaluates a when clause against the collected activity. Supported conditions
+// compare a percentage of the CPU time over the samples of the window:
+//
+//   - "maxSteal <operator> <n>", the highest steal time of a sample, e.g. "maxSteal > 10"
+//   - "avgSteal <operator> <n>", the average steal time of the samples
+//   - "maxIOWait <operator> <n>", the highest iowait time of a sample, e.g. "maxIOWait > 20"
+//   - "avgIOWait <operator> <n>", the average iowait time of the samples
+func (a *AnalyzeHostActivityCapture) CheckCondition(when string, data []byte) (bool, error) {
+	info := collect.ActivityCaptureInfo{}
+	if err := json.Unmarshal(data, &info); err != nil {
+		return false, errors.Wrap(err, "failed to unmarshal activity capture info")
+	}
+
+	parts := strings.Fields(when)
+	if len(parts) != 3 {
+		return false, fmt.Errorf("expected 3 parts in when %q, got %d", when, len(parts))
+	}
+
+	observed, err := activityCaptureMetric(info, parts[0])
+	if err != nil {
+		return false, err
+	}
+	threshold, err := strconv.ParseFloat(parts[2], 64)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to parse %q", parts[2])
+	}
+	return compareFloat(observed, parts[1], threshold)
+}
+
+// measure reports the percentage a condition compares, and the value in the condition as the
+// threshold.
+func (a *AnalyzeHostActivityCapture) measure(condition string, data []byte) (*Measurement, error) {
+	info := collect.ActivityCaptureInfo{}
+	if err := json.Unmarshal(data, &info); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal activity capture info")
+	}
+
+	parts := strings.Fields(condition)
+	if len(parts) != 3 {
+		return nil, nil
+	}
+
+	observed, err := activityCaptureMetric(info, parts[0])
+	if err != nil {
+		return nil, err
+	}
+	threshold, err := strconv.ParseFloat(parts[2], 64)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse %q", parts[2])
+	}
+	return &Measurement{
+		Observed:  observed,
+		Threshold: &threshold,
+		Unit:      "%",
+	}, nil
+}
+
+// activityCaptureMetric returns the highest or the average steal or iowait percentage of the
+// samples
+func activityCaptureMetric(info collect.ActivityCaptureInfo, metric string) (float64, error) {
+	var value func(collect.ActivitySample) float64
+	switch metric {
+	case "maxSteal", "avgSteal":
+		value = func(s collect.ActivitySample) float64 { return s.CPU.Steal }
+	case "maxIOWait", "avgIOWait":
+		value = func(s collect.ActivitySample) float64 { return s.CPU.IOWait }
+	default:
+		return 0, fmt.Errorf("unsupported metric %q, must be one of maxSteal, avgSteal, maxIOWait or avgIOWait", metric)
+	}
+
+	if len(info.Samples) == 0 {
+		return 0, errors.New("no activity samples were collected")
+	}
+
+	max, sum := 0.0, 0.0
+	for _, sample := range info.Samples {
+		v := value(sample)
+		sum += v
+		if v > max {
+			max = v
+		}
+	}
+	if strings.HasPrefix(metric, "max") {
+		return max, nil
+	}
+	return sum / float64(len(info.Samples)), nil
+}
@@ -0,0 +1,85 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"testing"
+
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/collect"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var activityCaptureInfo = collect.ActivityCaptureInfo{
+	IntervalSeconds: 1,
+	DurationSeconds: 3,
+	Samples: []collect.ActivitySample{
+		{CPU: collect.ActivityCPU{User: 40, System: 10, Idle: 45, IOWait: 3, Steal: 2}},
+		{CPU: collect.ActivityCPU{User: 30, System: 10, Idle: 20, IOWait: 25, Steal: 15}},
+		{CPU: collect.ActivityCPU{User: 35, System: 10, Idle: 53, IOWait: 2, Steal: 1}},
+	},
+}
+
+func TestAnalyzeHostActivityCapture_CheckCondition(t *testing.T) {
+	tests := []struct {
+		when    string
+		want    bool
+		wantErr string
+	}{
+		{when: "maxSteal > 10", want: true},
+		{when: "avgSteal > 10", want: false},
+		{when: "avgSteal >= 6", want: true},
+		{when: "maxIOWait > 20", want: true},
+		{when: "avgIOWait <= 10", want: true},
+		{when: "maxIOWait > high", wantErr: `failed to parse "high"`},
+		{when: "maxUser > 50", wantErr: `unsupported metric "maxUser"`},
+		{when: "maxSteal", wantErr: `expected 3 parts in when "maxSteal", got 1`},
+	}
+
+	data, err := json.Marshal(activityCaptureInfo)
+	require.NoError(t, err)
+
+	a := AnalyzeHostActivityCapture{}
+	for _, tt := range tests {
+		t.Run(tt.when, func(t *testing.T) {
+			got, err := a.CheckCondition(tt.when, data)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	_, err = a.CheckCondition("maxSteal > 10", []byte(`{"samples": []}`))
+	assert.EqualError(t, err, "no activity samples were collected")
+}
+
+func TestAnalyzeHostActivityCapture(t *testing.T) {
+	data, err := json.Marshal(activityCaptureInfo)
+	require.NoError(t, err)
+
+	a := AnalyzeHostActivityCapture{&troubleshootv1beta2.ActivityCaptureAnalyze{
+		Outcomes: []*troubleshootv1beta2.Outcome{
+			{Warn: &troubleshootv1beta2.SingleOutcome{When: "maxSteal > 10", Message: "steal spike"}},
+			{Pass: &troubleshootv1beta2.SingleOutcome{Message: "no steal"}},
+		},
+	}}
+	results, err := a.Analyze(func(path string) ([]byte, error) {
+		require.Equal(t, collect.HostActivityCapturePath, path)
+		return data, nil
+	}, nil)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+
+	threshold := float64(10)
+	assert.Equal(t, &AnalyzeResult{
+		Title:       "Activity Capture",
+		IsWarn:      true,
+		Message:     "steal spike",
+		Condition:   "maxSteal > 10",
+		Measurement: &Measurement{Observed: 15, Threshold: &threshold, Unit: "%"},
+	}, results[0])
+}
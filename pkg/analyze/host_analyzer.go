@@ -67,6 +67,38 @@ func GetHostAnalyzer(analyzer *troubleshootv1beta2.HostAnalyze) (HostAnalyzer, b
 		return &AnalyzeHostNetworkNamespaceConnectivity{analyzer.NetworkNamespaceConnectivity}, true
 	case analyzer.Sysctl != nil:
 		return &AnalyzeHostSysctl{analyzer.Sysctl}, true
+	case analyzer.KernelSnapshot != nil:
+		return &AnalyzeHostKernelSnapshot{analyzer.KernelSnapshot}, true
+	case analyzer.SystemdUnits != nil:
+		return &AnalyzeHostSystemdUnits{analyzer.SystemdUnits}, true
+	case analyzer.NetworkConfig != nil:
+		return &AnalyzeHostNetworkConfig{analyzer.NetworkConfig}, true
+	case analyzer.GPU != nil:
+		return &AnalyzeHostGPU{analyzer.GPU}, true
+	case analyzer.TimeSync != nil:
+		return &AnalyzeHostTimeSync{analyzer.TimeSync}, true
+	case analyzer.KubernetesDistribution != nil:
+		return &AnalyzeHostKubernetesDistribution{analyzer.KubernetesDistribution}, true
+	case analyzer.Benchmark != nil:
+		return &AnalyzeHostBenchmark{analyzer.Benchmark}, true
+	case analyzer.Paths != nil:
+		return &AnalyzeHostPaths{analyzer.Paths}, true
+	case analyzer.Security != nil:
+		return &AnalyzeHostSecurity{analyzer.Security}, true
+	case analyzer.Proxy != nil:
+		return &AnalyzeHostProxy{analyzer.Proxy}, true
+	case analyzer.ActivityCapture != nil:
+		return &AnalyzeHostActivityCapture{analyzer.ActivityCapture}, true
+	case analyzer.KernelLogs != nil:
+		return &AnalyzeHostKernelLogs{analyzer.KernelLogs}, true
+	case analyzer.ConnectionStats != nil:
+		return &AnalyzeHostConnectionStats{analyzer.ConnectionStats}, true
+	case analyzer.KubeletConfig != nil:
+		return &AnalyzeHostKubeletConfig{analyzer.KubeletConfig}, true
+	case analyzer.Metrics != nil:
+		return &AnalyzeHostMetrics{analyzer.Metrics}, true
+	case analyzer.CRIRuntime != nil:
+		return &AnalyzeHostCRIRuntime{analyzer.CRIRuntime}, true
 	default:
 		return nil, false
 	}
@@ -114,7 +146,45 @@ func analyzeHostCollectorResults(collectedContent []collectedContent, outcomes [
 	return results, nil
 }
 
+// measureFunc returns the value a condition compares against the collected data, and the
+// threshold in the condition. It returns nil when the condition does not compare a number.
+type measureFunc func(condition string, data []byte) (*Measurement, error)
+
+// analyzeMeasuredHostCollectorResults is analyzeHostCollectorResults for analyzers that can
+// report the value they observed on each node alongside the result.
+func analyzeMeasuredHostCollectorResults(collectedContent []collectedContent, outcomes []*troubleshootv1beta2.Outcome, checkCondition func(string, []byte) (bool, error), measure measureFunc, title string) ([]*AnalyzeResult, error) {
+	var results []*AnalyzeResult
+	for _, content := range collectedContent {
+		currentTitle := title
+		if content.NodeName != "" {
+			currentTitle = fmt.Sprintf("%s - Node %s", title, content.NodeName)
+		}
+
+		nodeResults, condition, err := matchOutcomes(outcomes, checkCondition, content.Data, currentTitle)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to evaluate outcomes")
+		}
+		for _, result := range nodeResults {
+			measurement, err := measure(condition, content.Data)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to measure %s", condition)
+			}
+			result.Condition = condition
+			result.Measurement = measurement
+		}
+		results = append(results, nodeResults...)
+	}
+	return results, nil
+}
+
 func evaluateOutcomes(outcomes []*troubleshootv1beta2.Outcome, checkCondition func(string, []byte) (bool, error), data []byte, title string) ([]*AnalyzeResult, error) {
+	results, _, err := matchOutcomes(outcomes, checkCondition, data, title)
+	return results, err
+}
+
+// matchOutcomes evaluates the outcomes in order, and returns the result of the first one that
+// matches along with its when clause.
+func matchOutcomes(outcomes []*troubleshootv1beta2.Outcome, checkCondition func(string, []byte) (bool, error), data []byte, title string) ([]*AnalyzeResult, string, error) {
 	var results []*AnalyzeResult
 
 	for _, outcome := range outcomes {
@@ -128,21 +198,23 @@ func evaluateOutcomes(outcomes []*troubleshootv1beta2.Outcome, checkCondition fu
 				result.IsFail = true
 				result.Message = outcome.Fail.Message
 				result.URI = outcome.Fail.URI
+				result.Remediation = outcome.Fail.Remediation
 				results = append(results, &result)
-				return results, nil
+				return results, outcome.Fail.When, nil
 			}
 
 			isMatch, err := checkCondition(outcome.Fail.When, data)
 			if err != nil {
-				return []*AnalyzeResult{&result}, errors.Wrapf(err, "failed to compare %s", outcome.Fail.When)
+				return []*AnalyzeResult{&result}, "", errors.Wrapf(err, "failed to compare %s", outcome.Fail.When)
 			}
 
 			if isMatch {
 				result.IsFail = true
 				result.Message = outcome.Fail.Message
 				result.URI = outcome.Fail.URI
+				result.Remediation = outcome.Fail.Remediation
 				results = append(results, &result)
-				return results, nil
+				return results, outcome.Fail.When, nil
 			}
 
 		case outcome.Warn != nil:
@@ -150,21 +222,23 @@ func evaluateOutcomes(outcomes []*troubleshootv1beta2.Outcome, checkCondition fu
 				result.IsWarn = true
 				result.Message = outcome.Warn.Message
 				result.URI = outcome.Warn.URI
+				result.Remediation = outcome.Warn.Remediation
 				results = append(results, &result)
-				return results, nil
+				return results, outcome.Warn.When, nil
 			}
 
 			isMatch, err := checkCondition(outcome.Warn.When, data)
 			if err != nil {
-				return []*AnalyzeResult{&result}, errors.Wrapf(err, "failed to compare %s", outcome.Warn.When)
+				return []*AnalyzeResult{&result}, "", errors.Wrapf(err, "failed to compare %s", outcome.Warn.When)
 			}
 
 			if isMatch {
 				result.IsWarn = true
 				result.Message = outcome.Warn.Message
 				result.URI = outcome.Warn.URI
+				result.Remediation = outcome.Warn.Remediation
 				results = append(results, &result)
-				return results, nil
+				return results, outcome.Warn.When, nil
 			}
 
 		case outcome.Pass != nil:
@@ -172,24 +246,26 @@ func evaluateOutcomes(outcomes []*troubleshootv1beta2.Outcome, checkCondition fu
 				result.IsPass = true
 				result.Message = outcome.Pass.Message
 				result.URI = outcome.Pass.URI
+				result.Remediation = outcome.Pass.Remediation
 				results = append(results, &result)
-				return results, nil
+				return results, outcome.Pass.When, nil
 			}
 
 			isMatch, err := checkCondition(outcome.Pass.When, data)
 			if err != nil {
-				return []*AnalyzeResult{&result}, errors.Wrapf(err, "failed to compare %s", outcome.Pass.When)
+				return []*AnalyzeResult{&result}, "", errors.Wrapf(err, "failed to compare %s", outcome.Pass.When)
 			}
 
 			if isMatch {
 				result.IsPass = true
 				result.Message = outcome.Pass.Message
 				result.URI = outcome.Pass.URI
+				result.Remediation = outcome.Pass.Remediation
 				results = append(results, &result)
-				return results, nil
+				return results, outcome.Pass.When, nil
 			}
 		}
 	}
 
-	return nil, nil
+	return nil, "", nil
 }
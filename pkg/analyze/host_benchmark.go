@@ -0,0 +1,122 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/collect"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// Ensure `AnalyzeHostBenchmark` implements `HostAnalyzer` interface at compile time.
+var _ HostAnalyzer = (*AnalyzeHostBenchmark)(nil)
+
+type AnalyzeHostBenchmark struct {
+	hostAnalyzer *troubleshootv1beta2.BenchmarkAnalyze
+}
+
+func (a *AnalyzeHostBenchmark) Title() string {
+	return hostAnalyzerTitleOrDefault(a.hostAnalyzer.AnalyzeMeta, "Benchmark")
+}
+
+func (a *AnalyzeHostBenchmark) IsExcluded() (bool, error) {
+	return isExcluded(a.hostAnalyzer.Exclude)
+}
+
+func (a *AnalyzeHostBenchmark) Analyze(
+	getCollectedFileContents func(string) ([]byte, error), findFiles getChildCollectedFileContents,
+) ([]*AnalyzeResult, error) {
+	result := AnalyzeResult{Title: a.Title()}
+
+	collectedContents, err := retrieveCollectedContents(
+		getCollectedFileContents,
+		collect.HostBenchmarkPath,
+		collect.NodeInfoBaseDir,
+		collect.HostBenchmarkFileName,
+	)
+	if err != nil {
+		return []*AnalyzeResult{&result}, err
+	}
+
+	results, err := analyzeMeasuredHostCollectorResults(collectedContents, a.hostAnalyzer.Outcomes, a.CheckCondition, a.measure, a.Title())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to analyze benchmark")
+	}
+
+	return results, nil
+}
+
+// CheckCondition evaluates a when clause against the collected benchmark. Supported conditions are:
+//
+//   - "cpuSingleThread <operator> <n>", compared against the events per second of the single-threaded CPU benchmark
+//   - "cpuMultiThread <operator> <n>", compared against the events per second of the multi-threaded CPU benchmark
+//   - "memoryBandwidth <operator> <quantity>", compared against the bytes copied per second, e.g. "memoryBandwidth < 5Gi"
+func (a *AnalyzeHostBenchmark) CheckCondition(when string, data []byte) (bool, error) {
+	info := collect.BenchmarkInfo{}
+	if err := json.Unmarshal(data, &info); err != nil {
+		return false, errors.Wrap(err, "failed to unmarshal benchmark info")
+	}
+
+	parts := strings.Fields(when)
+	if len(parts) != 3 {
+		return false, fmt.Errorf("expected 3 parts in when %q, got %d", when, len(parts))
+	}
+
+	observed, threshold, _, err := benchmarkMetric(info, parts[0], parts[2])
+	if err != nil {
+		return false, err
+	}
+	return compareFloat(observed, parts[1], threshold)
+}
+
+// measure reports the benchmark result a condition compares, and the value in the condition as
+// the threshold.
+func (a *AnalyzeHostBenchmark) measure(condition string, data []byte) (*Measurement, error) {
+	info := collect.BenchmarkInfo{}
+	if err := json.Unmarshal(data, &info); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal benchmark info")
+	}
+
+	parts := strings.Fields(condition)
+	if len(parts) != 3 {
+		return nil, nil
+	}
+
+	observed, threshold, unit, err := benchmarkMetric(info, parts[0], parts[2])
+	if err != nil {
+		return nil, err
+	}
+	return &Measurement{
+		Observed:  observed,
+		Threshold: &threshold,
+		Unit:      unit,
+	}, nil
+}
+
+// benchmarkMetric returns the result of the benchmark a condition refers to, the value of the
+// condition and their unit
+func benchmarkMetric(info collect.BenchmarkInfo, metric string, value string) (float64, float64, string, error) {
+	switch metric {
+	case "cpuSingleThread", "cpuMultiThread":
+		threshold, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return 0, 0, "", errors.Wrapf(err, "failed to parse %q", value)
+		}
+		if metric == "cpuSingleThread" {
+			return info.SingleThreadEventsPerSecond, threshold, "events/s", nil
+		}
+		return info.MultiThreadEventsPerSecond, threshold, "events/s", nil
+	case "memoryBandwidth":
+		quantity, err := resource.ParseQuantity(value)
+		if err != nil {
+			return 0, 0, "", errors.Wrapf(err, "could not parse quantity %q", value)
+		}
+		return info.MemoryBytesPerSecond, quantity.AsApproximateFloat64(), "bytes/s", nil
+	}
+
+	return 0, 0, "", fmt.Errorf("unsupported benchmark %q, must be one of cpuSingleThread, cpuMultiThread or memoryBandwidth", metric)
+}
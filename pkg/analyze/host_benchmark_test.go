@@ -0,0 +1,81 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"testing"
+
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/collect"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var benchmarkInfo = collect.BenchmarkInfo{
+	DurationSeconds:             2,
+	Threads:                     4,
+	SingleThreadEventsPerSecond: 1150.5,
+	MultiThreadEventsPerSecond:  4480,
+	MemoryBytesPerSecond:        8 * 1024 * 1024 * 1024,
+}
+
+func TestAnalyzeHostBenchmark_CheckCondition(t *testing.T) {
+	tests := []struct {
+		when    string
+		want    bool
+		wantErr string
+	}{
+		{when: "cpuSingleThread >= 1000", want: true},
+		{when: "cpuSingleThread < 1150.5", want: false},
+		{when: "cpuMultiThread < 5000", want: true},
+		{when: "memoryBandwidth >= 8Gi", want: true},
+		{when: "memoryBandwidth < 5G", want: false},
+		{when: "cpuSingleThread >= fast", wantErr: `failed to parse "fast"`},
+		{when: "memoryBandwidth >= lots", wantErr: `could not parse quantity "lots"`},
+		{when: "diskBandwidth > 1Gi", wantErr: `unsupported benchmark "diskBandwidth"`},
+		{when: "cpuMultiThread", wantErr: `expected 3 parts in when "cpuMultiThread", got 1`},
+	}
+
+	data, err := json.Marshal(benchmarkInfo)
+	require.NoError(t, err)
+
+	a := AnalyzeHostBenchmark{}
+	for _, tt := range tests {
+		t.Run(tt.when, func(t *testing.T) {
+			got, err := a.CheckCondition(tt.when, data)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestAnalyzeHostBenchmark(t *testing.T) {
+	data, err := json.Marshal(benchmarkInfo)
+	require.NoError(t, err)
+
+	a := AnalyzeHostBenchmark{&troubleshootv1beta2.BenchmarkAnalyze{
+		Outcomes: []*troubleshootv1beta2.Outcome{
+			{Fail: &troubleshootv1beta2.SingleOutcome{When: "cpuMultiThread < 5000", Message: "too slow"}},
+			{Pass: &troubleshootv1beta2.SingleOutcome{Message: "fast enough"}},
+		},
+	}}
+	results, err := a.Analyze(func(path string) ([]byte, error) {
+		require.Equal(t, collect.HostBenchmarkPath, path)
+		return data, nil
+	}, nil)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+
+	threshold := float64(5000)
+	assert.Equal(t, &AnalyzeResult{
+		Title:       "Benchmark",
+		IsFail:      true,
+		Message:     "too slow",
+		Condition:   "cpuMultiThread < 5000",
+		Measurement: &Measurement{Observed: 4480, Threshold: &threshold, Unit: "events/s"},
+	}, results[0])
+}
@@ -0,0 +1,139 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/collect"
+)
+
+// Ensure `AnalyzeHostConnectionStats` implements `HostAnalyzer` interface at compile time.
+var _ HostAnalyzer = (*AnalyzeHostConnectionStats)(nil)
+
+type AnalyzeHostConnectionStats struct {
+	hostAnalyzer *troubleshootv1beta2.ConnectionStatsAnalyze
+}
+
+func (a *AnalyzeHostConnectionStats) Title() string {
+	return hostAnalyzerTitleOrDefault(a.hostAnalyzer.AnalyzeMeta, "Connection Stats")
+}
+
+func (a *AnalyzeHostConnectionStats) IsExcluded() (bool, error) {
+	return isExcluded(a.hostAnalyzer.Exclude)
+}
+
+func (a *AnalyzeHostConnectionStats) Analyze(
+	getCollectedFileContents func(string) ([]byte, error), findFiles getChildCollectedFileContents,
+) ([]*AnalyzeResult, error) {
+	result := AnalyzeResult{Title: a.Title()}
+
+	collectedContents, err := retrieveCollectedContents(
+		getCollectedFileContents,
+		collect.HostConnectionStatsPath,
+		collect.NodeInfoBaseDir,
+		collect.HostConnectionStatsFileName,
+	)
+	if err != nil {
+		return []*AnalyzeResult{&result}, err
+	}
+
+	results, err := analyzeMeasuredHostCollectorResults(collectedContents, a.hostAnalyzer.Outcomes, a.CheckCondition, a.measure, a.Title())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to analyze connection stats")
+	}
+
+	return results, nil
+}
+
+// CheckCondition evaluates a when clause against the collected stats. Supported conditions
+// compare a statistic to a number:
+//
+//   - "conntrackUsage <operator> <n>", the percentage of nf_conntrack_max in use, 0 when conntrack
+//     is not available, e.g. "conntrackUsage > 80"
+//   - "conntrackDrops <operator> <n>", the packets dropped and the failed inserts since boot as the
+//     table was full
+//   - "ephemeralPortUsage <operator> <n>", the percentage of the local port range used by the
+//     connections to the busiest destination, e.g. "ephemeralPortUsage > 80"
+//   - "timeWait <operator> <n>", the TCP sockets in TIME_WAIT
+//   - "orphans <operator> <n>", the TCP sockets not attached to a process
+//   - "listenOverflows <operator> <n>", the connections dropped since boot as the accept queue of
+//     a listening socket was full
+func (a *AnalyzeHostConnectionStats) CheckCondition(when string, data []byte) (bool, error) {
+	info := collect.ConnectionStatsInfo{}
+	if err := json.Unmarshal(data, &info); err != nil {
+		return false, errors.Wrap(err, "failed to unmarshal connection stats")
+	}
+
+	parts := strings.Fields(when)
+	if len(parts) != 3 {
+		return false, fmt.Errorf("expected 3 parts in when %q, got %d", when, len(parts))
+	}
+
+	observed, _, err := connectionStatsMetric(info, parts[0])
+	if err != nil {
+		return false, err
+	}
+	threshold, err := strconv.ParseFloat(parts[2], 64)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to parse %q", parts[2])
+	}
+	return compareFloat(observed, parts[1], threshold)
+}
+
+// measure reports the statistic a condition compares, and the value in the condition as the
+// threshold.
+func (a *AnalyzeHostConnectionStats) measure(condition string, data []byte) (*Measurement, error) {
+	info := collect.ConnectionStatsInfo{}
+	if err := json.Unmarshal(data, &info); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal connection stats")
+	}
+
+	parts := strings.Fields(condition)
+	if len(parts) != 3 {
+		return nil, nil
+	}
+
+	observed, unit, err := connectionStatsMetric(info, parts[0])
+	if err != nil {
+		return nil, err
+	}
+	threshold, err := strconv.ParseFloat(parts[2], 64)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse %q", parts[2])
+	}
+	return &Measurement{
+		Observed:  observed,
+		Threshold: &threshold,
+		Unit:      unit,
+	}, nil
+}
+
+// connectionStatsMetric returns the value of a statistic and its unit
+func connectionStatsMetric(info collect.ConnectionStatsInfo, metric string) (float64, string, error) {
+	switch metric {
+	case "conntrackUsage":
+		if !info.Conntrack.Available || info.Conntrack.Max <= 0 {
+			return 0, "%", nil
+		}
+		return float64(info.Conntrack.Count) * 100 / float64(info.Conntrack.Max), "%", nil
+	case "conntrackDrops":
+		return float64(info.Conntrack.Drop + info.Conntrack.EarlyDrop + info.Conntrack.InsertFailed), "", nil
+	case "ephemeralPortUsage":
+		size := info.EphemeralPorts.Size()
+		if size == 0 {
+			return 0, "", errors.New("the local port range was not collected")
+		}
+		return float64(info.EphemeralPorts.MaxPerDestination) * 100 / float64(size), "%", nil
+	case "timeWait":
+		return float64(info.Sockets.TCPTimeWait), "", nil
+	case "orphans":
+		return float64(info.Sockets.TCPOrphan), "", nil
+	case "listenOverflows":
+		return float64(info.TCPExt["ListenOverflows"]), "", nil
+	}
+	return 0, "", fmt.Errorf("unsupported metric %q, must be one of conntrackUsage, conntrackDrops, ephemeralPortUsage, timeWait, orphans or listenOverflows", metric)
+}
@@ -0,0 +1,95 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"testing"
+
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/collect"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var connectionStatsInfo = collect.ConnectionStatsInfo{
+	Conntrack: collect.ConntrackStats{Available: true, Count: 90000, Max: 100000, Drop: 12, InsertFailed: 3},
+	Sockets:   collect.SocketStats{Used: 900, TCPInUse: 400, TCPOrphan: 2, TCPTimeWait: 25000},
+	EphemeralPorts: collect.EphemeralPortStats{
+		RangeStart:         32768,
+		RangeEnd:           60999,
+		InUse:              14200,
+		MaxPerDestination:  14116,
+		BusiestDestination: "10.96.0.10:53",
+	},
+	TCPExt: map[string]uint64{"ListenOverflows": 0},
+}
+
+func TestAnalyzeHostConnectionStats_CheckCondition(t *testing.T) {
+	tests := []struct {
+		when    string
+		want    bool
+		wantErr string
+	}{
+		{when: "conntrackUsage > 80", want: true},
+		{when: "conntrackUsage > 90", want: false},
+		{when: "conntrackDrops >= 15", want: true},
+		{when: "ephemeralPortUsage >= 50", want: true},
+		{when: "ephemeralPortUsage > 50", want: false},
+		{when: "timeWait > 20000", want: true},
+		{when: "orphans == 2", want: true},
+		{when: "listenOverflows > 0", want: false},
+		{when: "conntrackUsage > high", wantErr: `failed to parse "high"`},
+		{when: "sockets > 50", wantErr: `unsupported metric "sockets"`},
+		{when: "timeWait", wantErr: `expected 3 parts in when "timeWait", got 1`},
+	}
+
+	data, err := json.Marshal(connectionStatsInfo)
+	require.NoError(t, err)
+
+	a := AnalyzeHostConnectionStats{}
+	for _, tt := range tests {
+		t.Run(tt.when, func(t *testing.T) {
+			got, err := a.CheckCondition(tt.when, data)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	got, err := a.CheckCondition("conntrackUsage > 0", []byte(`{"conntrack": {"available": false}, "ephemeralPorts": {}}`))
+	require.NoError(t, err)
+	assert.False(t, got)
+
+	_, err = a.CheckCondition("ephemeralPortUsage > 80", []byte(`{"ephemeralPorts": {}}`))
+	assert.EqualError(t, err, "the local port range was not collected")
+}
+
+func TestAnalyzeHostConnectionStats(t *testing.T) {
+	data, err := json.Marshal(connectionStatsInfo)
+	require.NoError(t, err)
+
+	a := AnalyzeHostConnectionStats{&troubleshootv1beta2.ConnectionStatsAnalyze{
+		Outcomes: []*troubleshootv1beta2.Outcome{
+			{Warn: &troubleshootv1beta2.SingleOutcome{When: "conntrackUsage > 80", Message: "conntrack table is nearly full"}},
+			{Pass: &troubleshootv1beta2.SingleOutcome{Message: "conntrack table has room"}},
+		},
+	}}
+	results, err := a.Analyze(func(path string) ([]byte, error) {
+		require.Equal(t, collect.HostConnectionStatsPath, path)
+		return data, nil
+	}, nil)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+
+	threshold := float64(80)
+	assert.Equal(t, &AnalyzeResult{
+		Title:       "Connection Stats",
+		IsWarn:      true,
+		Message:     "conntrack table is nearly full",
+		Condition:   "conntrackUsage > 80",
+		Measurement: &Measurement{Observed: 90, Threshold: &threshold, Unit: "%"},
+	}, results[0])
+}
@@ -61,7 +61,7 @@ func (a *AnalyzeHostCPU) Analyze(
 		return []*AnalyzeResult{&result}, err
 	}
 
-	results, err := analyzeHostCollectorResults(collectedContents, a.hostAnalyzer.Outcomes, a.CheckCondition, a.Title())
+	results, err := analyzeMeasuredHostCollectorResults(collectedContents, a.hostAnalyzer.Outcomes, a.CheckCondition, a.measure, a.Title())
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to analyze OS version")
 	}
@@ -70,6 +70,49 @@ func (a *AnalyzeHostCPU) Analyze(
 
 }
 
+// measure reports the number of CPUs a count condition compares, and the count in the condition
+// as the threshold. Conditions on the architecture or CPU flags are not measured.
+func (a *AnalyzeHostCPU) measure(condition string, data []byte) (*Measurement, error) {
+	cpuInfo := collect.CPUInfo{}
+	if err := json.Unmarshal(data, &cpuInfo); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal data into CPUInfo")
+	}
+
+	observed := cpuInfo.LogicalCount
+	if cpuInfo.PhysicalCount > observed {
+		observed = cpuInfo.PhysicalCount
+	}
+	desired := ""
+
+	parts := strings.Split(condition, " ")
+	switch {
+	case len(parts) == 3 && strings.ToLower(parts[0]) == "logical":
+		observed, desired = cpuInfo.LogicalCount, parts[2]
+	case len(parts) == 3 && strings.ToLower(parts[0]) == "physical":
+		observed, desired = cpuInfo.PhysicalCount, parts[2]
+	case len(parts) == 3 && strings.ToLower(parts[0]) == "count":
+		desired = parts[2]
+	case len(parts) == 2 && strings.ToLower(parts[0]) != "supports" && strings.ToLower(parts[0]) != "hasflags":
+		desired = parts[1]
+	case condition != "":
+		return nil, nil
+	}
+
+	measurement := &Measurement{
+		Observed: float64(observed),
+		Unit:     "cpus",
+	}
+	if desired != "" {
+		threshold, err := strconv.ParseFloat(desired, 64)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse %q", desired)
+		}
+		measurement.Threshold = &threshold
+	}
+
+	return measurement, nil
+}
+
 func doCompareHostCPUMicroArchitecture(microarch string, flags []string) (res bool, err error) {
 	specifics := make([]string, 0)
 	switch microarch {
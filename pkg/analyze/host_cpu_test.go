@@ -214,9 +214,57 @@ func TestHostCpuAnalyze(t *testing.T) {
 			},
 			results: []*AnalyzeResult{
 				{
-					IsPass:  true,
-					Message: "it passed",
-					Title:   "Number of CPUs",
+					IsPass:      true,
+					Message:     "it passed",
+					Title:       "Number of CPUs",
+					Measurement: &Measurement{Observed: 16, Unit: "cpus"},
+				},
+			},
+		},
+		{
+			name: "logical count below threshold",
+			cpuInfo: collect.CPUInfo{
+				LogicalCount:  4,
+				PhysicalCount: 2,
+			},
+			outcomes: []*troubleshootv1beta2.Outcome{
+				{
+					Fail: &troubleshootv1beta2.SingleOutcome{
+						When:    "logical < 8",
+						Message: "at least 8 CPUs are required",
+					},
+				},
+			},
+			results: []*AnalyzeResult{
+				{
+					IsFail:      true,
+					Message:     "at least 8 CPUs are required",
+					Title:       "Number of CPUs",
+					Condition:   "logical < 8",
+					Measurement: &Measurement{Observed: 4, Threshold: float64Ptr(8), Unit: "cpus"},
+				},
+			},
+		},
+		{
+			name: "flags are not measured",
+			cpuInfo: collect.CPUInfo{
+				LogicalCount: 4,
+				Flags:        []string{"avx"},
+			},
+			outcomes: []*troubleshootv1beta2.Outcome{
+				{
+					Pass: &troubleshootv1beta2.SingleOutcome{
+						When:    "hasFlags avx",
+						Message: "avx is supported",
+					},
+				},
+			},
+			results: []*AnalyzeResult{
+				{
+					IsPass:    true,
+					Message:   "avx is supported",
+					Title:     "Number of CPUs",
+					Condition: "hasFlags avx",
 				},
 			},
 		},
@@ -0,0 +1,121 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/collect"
+)
+
+// Ensure `AnalyzeHostCRIRuntime` implements `HostAnalyzer` interface at compile time.
+var _ HostAnalyzer = (*AnalyzeHostCRIRuntime)(nil)
+
+type AnalyzeHostCRIRuntime struct {
+	hostAnalyzer *troubleshootv1beta2.CRIRuntimeAnalyze
+}
+
+func (a *AnalyzeHostCRIRuntime) Title() string {
+	return hostAnalyzerTitleOrDefault(a.hostAnalyzer.AnalyzeMeta, "CRI Runtime")
+}
+
+func (a *AnalyzeHostCRIRuntime) IsExcluded() (bool, error) {
+	return isExcluded(a.hostAnalyzer.Exclude)
+}
+
+func (a *AnalyzeHostCRIRuntime) Analyze(
+	getCollectedFileContents func(string) ([]byte, error), findFiles getChildCollectedFileContents,
+) ([]*AnalyzeResult, error) {
+	result := AnalyzeResult{Title: a.Title()}
+
+	collectedContents, err := retrieveCollectedContents(
+		getCollectedFileContents,
+		collect.HostCRIRuntimePath,
+		collect.NodeInfoBaseDir,
+		collect.HostCRIRuntimeFileName,
+	)
+	if err != nil {
+		return []*AnalyzeResult{&result}, err
+	}
+
+	results, err := analyzeHostCollectorResults(collectedContents, a.hostAnalyzer.Outcomes, a.CheckCondition, a.Title())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to analyze cri runtime")
+	}
+
+	return results, nil
+}
+
+// CheckCondition evaluates a when clause against the collected runtime status. Supported conditions are:
+//
+//   - "runtimeReady <operator> <true|false>", the RuntimeReady condition of the runtime
+//   - "networkReady <operator> <true|false>", the NetworkReady condition of the runtime, false until a CNI plugin is
+//     initialized
+//   - "imageFsUsedPercentage <operator> <number>", the highest usage of the filesystems of the image filesystems of
+//     the runtime, e.g. "imageFsUsedPercentage >= 85". The kubelet garbage collects images from 85% by default.
+//
+// Operators of the conditions are == and !=, and of imageFsUsedPercentage also <, <=, > and >=. A condition the
+// runtime did not report is false.
+func (a *AnalyzeHostCRIRuntime) CheckCondition(when string, data []byte) (bool, error) {
+	info := collect.CRIRuntimeInfo{}
+	if err := json.Unmarshal(data, &info); err != nil {
+		return false, errors.Wrap(err, "failed to unmarshal cri runtime info")
+	}
+
+	parts := strings.Fields(when)
+	if len(parts) != 3 {
+		return false, fmt.Errorf("expected 3 parts in when %q, got %d", when, len(parts))
+	}
+	setting, operator, value := parts[0], parts[1], parts[2]
+
+	var conditionType string
+	switch setting {
+	case "runtimeReady":
+		conditionType = collect.CRIConditionRuntimeReady
+	case "networkReady":
+		conditionType = collect.CRIConditionNetworkReady
+	case "imageFsUsedPercentage":
+		usedPercentage, ok := imageFsUsedPercentage(info)
+		if !ok {
+			return false, errors.New("the usage of the image filesystems was not collected")
+		}
+		threshold, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return false, errors.Wrapf(err, "failed to parse %q", value)
+		}
+		return compareFloat(usedPercentage, operator, threshold)
+	default:
+		return false, fmt.Errorf("unsupported setting %q", setting)
+	}
+
+	actual := false
+	for _, condition := range info.Conditions {
+		if condition.Type == conditionType {
+			actual = condition.Status
+		}
+	}
+	expected, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to parse %q", value)
+	}
+	return compareEquality(actual == expected, operator)
+}
+
+// imageFsUsedPercentage returns the highest used percentage of the filesystems the image
+// filesystems are on, and false when the usage of none was collected
+func imageFsUsedPercentage(info collect.CRIRuntimeInfo) (float64, bool) {
+	highest, found := 0.0, false
+	for _, imageFs := range info.ImageFilesystems {
+		if imageFs.TotalBytes == 0 {
+			continue
+		}
+		usedPercentage := float64(imageFs.TotalBytes-imageFs.FreeBytes) / float64(imageFs.TotalBytes) * 100
+		if !found || usedPercentage > highest {
+			highest, found = usedPercentage, true
+		}
+	}
+	return highest, found
+}
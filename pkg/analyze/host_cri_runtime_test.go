@@ -0,0 +1,60 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"testing"
+
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/collect"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyzeHostCRIRuntime_CheckCondition(t *testing.T) {
+	notReady := collect.CRIRuntimeInfo{
+		Conditions: []collect.CRIRuntimeCondition{
+			{Type: collect.CRIConditionRuntimeReady, Status: true},
+			{Type: collect.CRIConditionNetworkReady, Status: false, Reason: "NetworkPluginNotReady"},
+		},
+		ImageFilesystems: []collect.CRIImageFilesystem{
+			{Mountpoint: "/var/lib/containerd", TotalBytes: 100, FreeBytes: 40},
+			{Mountpoint: "/var/lib/images", TotalBytes: 200, FreeBytes: 20},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		info    collect.CRIRuntimeInfo
+		when    string
+		want    bool
+		wantErr string
+	}{
+		{name: "runtime ready", info: notReady, when: "runtimeReady == true", want: true},
+		{name: "network not ready", info: notReady, when: "networkReady == false", want: true},
+		{name: "unreported condition", info: collect.CRIRuntimeInfo{}, when: "runtimeReady != true", want: true},
+		{name: "highest image filesystem usage", info: notReady, when: "imageFsUsedPercentage >= 90", want: true},
+		{name: "image filesystem usage below", info: notReady, when: "imageFsUsedPercentage > 90", want: false},
+		{name: "image filesystem usage not collected", info: collect.CRIRuntimeInfo{}, when: "imageFsUsedPercentage > 85", wantErr: "was not collected"},
+		{name: "invalid percentage", info: notReady, when: "imageFsUsedPercentage > high", wantErr: `failed to parse "high"`},
+		{name: "invalid boolean", info: notReady, when: "runtimeReady == yes", wantErr: `failed to parse "yes"`},
+		{name: "unsupported setting", info: notReady, when: "runtimeName == containerd", wantErr: `unsupported setting "runtimeName"`},
+		{name: "invalid when", info: notReady, when: "runtimeReady", wantErr: `expected 3 parts in when "runtimeReady", got 1`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(tt.info)
+			require.NoError(t, err)
+
+			a := AnalyzeHostCRIRuntime{&troubleshootv1beta2.CRIRuntimeAnalyze{}}
+			got, err := a.CheckCondition(tt.when, data)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
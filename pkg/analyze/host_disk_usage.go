@@ -3,6 +3,7 @@ package analyzer
 import (
 	"encoding/json"
 	"fmt"
+	"math"
 	"strconv"
 	"strings"
 
@@ -137,6 +138,30 @@ func doCompareHostDiskUsagePercent(operator string, desired string, actual float
 	return false, errors.New("unknown operator")
 }
 
+// doCompareHostDiskUsageDays compares a number of days, such as the days until the disk is full,
+// with a plain number of days
+func doCompareHostDiskUsageDays(operator string, desired string, actual float64) (bool, error) {
+	desiredDays, err := strconv.ParseFloat(desired, 64)
+	if err != nil {
+		return false, errors.Wrapf(err, "could not parse number of days %q", desired)
+	}
+
+	switch operator {
+	case "<":
+		return actual < desiredDays, nil
+	case "<=":
+		return actual <= desiredDays, nil
+	case ">":
+		return actual > desiredDays, nil
+	case ">=":
+		return actual >= desiredDays, nil
+	case "=", "==", "===":
+		return actual == desiredDays, nil
+	}
+
+	return false, errors.New("unknown operator")
+}
+
 func (a *AnalyzeHostDiskUsage) CheckCondition(when string, data []byte) (bool, error) {
 
 	var diskUsageInfo collect.DiskUsageInfo
@@ -144,5 +169,95 @@ func (a *AnalyzeHostDiskUsage) CheckCondition(when string, data []byte) (bool, e
 		return false, fmt.Errorf("failed to unmarshal data into DiskUsageInfo: %v", err)
 	}
 
+	if parts := strings.Split(when, " "); len(parts) == 3 && diskUsageForecastStats[strings.ToLower(parts[0])] {
+		return compareHostDiskUsageForecastToActual(when, diskUsageInfo)
+	}
+
 	return compareHostDiskUsageConditionalToActual(when, diskUsageInfo.TotalBytes, diskUsageInfo.UsedBytes)
 }
+
+var diskUsageForecastStats = map[string]bool{
+	"daysuntilfull": true,
+	"growthperday":  true,
+	"journal":       true,
+	"imagefs":       true,
+}
+
+// compareHostDiskUsageForecastToActual evaluates conditionals on the usage forecast, e.g.
+// "daysUntilFull < 7", "growthPerDay > 10Gi", "journal > 4Gi" or "imageFS > 50Gi".
+func compareHostDiskUsageForecastToActual(conditional string, info collect.DiskUsageInfo) (bool, error) {
+	parts := strings.Split(conditional, " ")
+	stat := strings.ToLower(parts[0])
+	comparator := parts[1]
+	desired := parts[2]
+
+	forecast := info.Forecast
+	if forecast == nil {
+		return false, fmt.Errorf("disk usage statistic %q requires the collector forecast to be enabled", parts[0])
+	}
+
+	switch stat {
+	case "daysuntilfull":
+		return doCompareHostDiskUsageDays(comparator, desired, diskUsageDaysUntilFull(info))
+	case "growthperday":
+		growth := math.Max(diskUsageGrowthPerSecond(forecast.Samples)*24*60*60, 0)
+		return doCompareHostDiskUsage(comparator, desired, uint64(growth))
+	case "journal":
+		if forecast.JournalBytes == nil {
+			return false, errors.New("journal disk usage was not collected")
+		}
+		return doCompareHostDiskUsage(comparator, desired, *forecast.JournalBytes)
+	case "imagefs":
+		if forecast.ImageFSBytes == nil {
+			return false, errors.New("image filesystem usage was not collected")
+		}
+		return doCompareHostDiskUsage(comparator, desired, *forecast.ImageFSBytes)
+	}
+	return false, fmt.Errorf("unknown disk usage statistic %q", stat)
+}
+
+// diskUsageDaysUntilFull predicts the days until the disk is full at the sampled growth rate.
+// The disk is never predicted to fill up if usage did not grow while sampling.
+func diskUsageDaysUntilFull(info collect.DiskUsageInfo) float64 {
+	growth := diskUsageGrowthPerSecond(info.Forecast.Samples)
+	if growth <= 0 {
+		return math.Inf(1)
+	}
+
+	used := info.UsedBytes
+	if len(info.Forecast.Samples) > 0 {
+		used = info.Forecast.Samples[len(info.Forecast.Samples)-1].UsedBytes
+	}
+	if used >= info.TotalBytes {
+		return 0
+	}
+
+	return float64(info.TotalBytes-used) / growth / (24 * 60 * 60)
+}
+
+// diskUsageGrowthPerSecond fits a line through the samples with least squares and returns its slope,
+// which is less sensitive to a single burst of writes than comparing the first and last sample.
+func diskUsageGrowthPerSecond(samples []collect.DiskUsageSample) float64 {
+	if len(samples) < 2 {
+		return 0
+	}
+
+	start := samples[0].Time
+	n := float64(len(samples))
+	var sumX, sumY, sumXY, sumXX float64
+	for _, sample := range samples {
+		x := sample.Time.Sub(start).Seconds()
+		// relative to the first sample to keep the sums small
+		y := float64(sample.UsedBytes) - float64(samples[0].UsedBytes)
+		sumX += x
+		sumY += y
+		sumXY += x * y
+		sumXX += x * x
+	}
+
+	denominator := n*sumXX - sumX*sumX
+	if denominator == 0 {
+		return 0
+	}
+	return (n*sumXY - sumX*sumY) / denominator
+}
@@ -3,6 +3,7 @@ package analyzer
 import (
 	"encoding/json"
 	"testing"
+	"time"
 
 	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
 	"github.com/replicatedhq/troubleshoot/pkg/collect"
@@ -423,3 +424,68 @@ func TestAnalyzeHostDiskUsage(t *testing.T) {
 		})
 	}
 }
+
+func TestAnalyzeHostDiskUsage_Forecast(t *testing.T) {
+	start := time.Date(2023, 11, 14, 22, 13, 20, 0, time.UTC)
+	journal := uint64(6 * 1024 * 1024 * 1024)
+
+	// 100Gi disk with 10Gi available, growing by 1Mi every 10s, which is ~8.4Gi per day
+	growing := collect.DiskUsageInfo{
+		TotalBytes: 100 * 1024 * 1024 * 1024,
+		UsedBytes:  90 * 1024 * 1024 * 1024,
+		Forecast: &collect.DiskUsageForecastInfo{
+			Samples: []collect.DiskUsageSample{
+				{Time: start, UsedBytes: 90 * 1024 * 1024 * 1024},
+				{Time: start.Add(10 * time.Second), UsedBytes: 90*1024*1024*1024 + 1024*1024},
+				{Time: start.Add(20 * time.Second), UsedBytes: 90*1024*1024*1024 + 2*1024*1024},
+			},
+			JournalBytes: &journal,
+		},
+	}
+	steady := collect.DiskUsageInfo{
+		TotalBytes: 100,
+		UsedBytes:  50,
+		Forecast: &collect.DiskUsageForecastInfo{
+			Samples: []collect.DiskUsageSample{
+				{Time: start, UsedBytes: 50},
+				{Time: start.Add(10 * time.Second), UsedBytes: 50},
+			},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		info    collect.DiskUsageInfo
+		when    string
+		want    bool
+		wantErr bool
+	}{
+		{name: "fills up within a week", info: growing, when: "daysUntilFull < 7", want: true},
+		{name: "fills up within a day", info: growing, when: "daysUntilFull < 1", want: false},
+		{name: "growth per day", info: growing, when: "growthPerDay > 8Gi", want: true},
+		{name: "steady usage never fills", info: steady, when: "daysUntilFull < 10000", want: false},
+		{name: "journal", info: growing, when: "journal > 4Gi", want: true},
+		{name: "image fs not collected", info: growing, when: "imageFS > 50Gi", wantErr: true},
+		{name: "days are not a percentage", info: growing, when: "daysUntilFull < 50%", wantErr: true},
+		{name: "forecast not collected", info: collect.DiskUsageInfo{TotalBytes: 100, UsedBytes: 50}, when: "daysUntilFull < 7", wantErr: true},
+		{name: "existing statistics still work", info: growing, when: "available < 20Gi", want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := require.New(t)
+
+			data, err := json.Marshal(tt.info)
+			req.NoError(err)
+
+			a := AnalyzeHostDiskUsage{}
+			got, err := a.CheckCondition(tt.when, data)
+			if tt.wantErr {
+				req.Error(err)
+				return
+			}
+			req.NoError(err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
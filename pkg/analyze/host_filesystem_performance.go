@@ -53,18 +53,21 @@ func (a *AnalyzeHostFilesystemPerformance) Analyze(
 				result.IsFail = true
 				result.Message = renderFSPerfOutcome(hostAnalyzer.Outcomes[0].Fail.Message, collect.FSPerfResults{})
 				result.URI = hostAnalyzer.Outcomes[0].Fail.URI
+				result.Remediation = hostAnalyzer.Outcomes[0].Fail.Remediation
 				return []*AnalyzeResult{result}, nil
 			}
 			if hostAnalyzer.Outcomes[0].Warn != nil && hostAnalyzer.Outcomes[0].Warn.When == FILE_NOT_COLLECTED {
 				result.IsWarn = true
 				result.Message = renderFSPerfOutcome(hostAnalyzer.Outcomes[0].Warn.Message, collect.FSPerfResults{})
 				result.URI = hostAnalyzer.Outcomes[0].Warn.URI
+				result.Remediation = hostAnalyzer.Outcomes[0].Warn.Remediation
 				return []*AnalyzeResult{result}, nil
 			}
 			if hostAnalyzer.Outcomes[0].Pass != nil && hostAnalyzer.Outcomes[0].Pass.When == FILE_NOT_COLLECTED {
 				result.IsPass = true
 				result.Message = renderFSPerfOutcome(hostAnalyzer.Outcomes[0].Pass.Message, collect.FSPerfResults{})
 				result.URI = hostAnalyzer.Outcomes[0].Pass.URI
+				result.Remediation = hostAnalyzer.Outcomes[0].Pass.Remediation
 				return []*AnalyzeResult{result}, nil
 			}
 			return nil, errors.Wrapf(err, "failed to get collected file %s", localPath)
@@ -214,6 +217,7 @@ func (a *AnalyzeHostFilesystemPerformance) analyzeSingleNode(content collectedCo
 				result.IsFail = true
 				result.Message = renderFSPerfOutcome(outcome.Fail.Message, fsPerf)
 				result.URI = outcome.Fail.URI
+				result.Remediation = outcome.Fail.Remediation
 
 				return []*AnalyzeResult{result}, nil
 			}
@@ -227,6 +231,7 @@ func (a *AnalyzeHostFilesystemPerformance) analyzeSingleNode(content collectedCo
 				result.IsFail = true
 				result.Message = renderFSPerfOutcome(outcome.Fail.Message, fsPerf)
 				result.URI = outcome.Fail.URI
+				result.Remediation = outcome.Fail.Remediation
 
 				return []*AnalyzeResult{result}, nil
 			}
@@ -235,6 +240,7 @@ func (a *AnalyzeHostFilesystemPerformance) analyzeSingleNode(content collectedCo
 				result.IsWarn = true
 				result.Message = renderFSPerfOutcome(outcome.Warn.Message, fsPerf)
 				result.URI = outcome.Warn.URI
+				result.Remediation = outcome.Warn.Remediation
 
 				return []*AnalyzeResult{result}, nil
 			}
@@ -248,6 +254,7 @@ func (a *AnalyzeHostFilesystemPerformance) analyzeSingleNode(content collectedCo
 				result.IsWarn = true
 				result.Message = renderFSPerfOutcome(outcome.Warn.Message, fsPerf)
 				result.URI = outcome.Warn.URI
+				result.Remediation = outcome.Warn.Remediation
 
 				return []*AnalyzeResult{result}, nil
 			}
@@ -256,6 +263,7 @@ func (a *AnalyzeHostFilesystemPerformance) analyzeSingleNode(content collectedCo
 				result.IsPass = true
 				result.Message = renderFSPerfOutcome(outcome.Pass.Message, fsPerf)
 				result.URI = outcome.Pass.URI
+				result.Remediation = outcome.Pass.Remediation
 
 				return []*AnalyzeResult{result}, nil
 			}
@@ -269,6 +277,7 @@ func (a *AnalyzeHostFilesystemPerformance) analyzeSingleNode(content collectedCo
 				result.IsPass = true
 				result.Message = renderFSPerfOutcome(outcome.Pass.Message, fsPerf)
 				result.URI = outcome.Pass.URI
+				result.Remediation = outcome.Pass.Remediation
 
 				return []*AnalyzeResult{result}, nil
 			}
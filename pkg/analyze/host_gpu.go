@@ -0,0 +1,160 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/blang/semver/v4"
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/collect"
+)
+
+// Ensure `AnalyzeHostGPU` implements `HostAnalyzer` interface at compile time.
+var _ HostAnalyzer = (*AnalyzeHostGPU)(nil)
+
+type AnalyzeHostGPU struct {
+	hostAnalyzer *troubleshootv1beta2.GPUAnalyze
+}
+
+func (a *AnalyzeHostGPU) Title() string {
+	return hostAnalyzerTitleOrDefault(a.hostAnalyzer.AnalyzeMeta, "GPU")
+}
+
+func (a *AnalyzeHostGPU) IsExcluded() (bool, error) {
+	return isExcluded(a.hostAnalyzer.Exclude)
+}
+
+func (a *AnalyzeHostGPU) Analyze(
+	getCollectedFileContents func(string) ([]byte, error), findFiles getChildCollectedFileContents,
+) ([]*AnalyzeResult, error) {
+	result := AnalyzeResult{Title: a.Title()}
+
+	collectedContents, err := retrieveCollectedContents(
+		getCollectedFileContents,
+		collect.HostGPUPath,
+		collect.NodeInfoBaseDir,
+		collect.HostGPUFileName,
+	)
+	if err != nil {
+		return []*AnalyzeResult{&result}, err
+	}
+
+	results, err := analyzeHostCollectorResults(collectedContents, a.hostAnalyzer.Outcomes, a.CheckCondition, a.Title())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to analyze gpu inventory")
+	}
+
+	return results, nil
+}
+
+// CheckCondition evaluates a when clause against the collected GPU inventory. Supported conditions are:
+//
+//   - "count <operator> <n>", optionally prefixed with a vendor, e.g. "nvidia count >= 1"
+//   - "nvidia driver <operator> <version>" or "amd driver <operator> <version>", e.g. "nvidia driver >= 535"
+//   - "cuda <operator> <version>", e.g. "cuda >= 12.0"
+//   - "mig enabled" or "mig disabled"
+//
+// A driver or CUDA version that was not detected is treated as version 0.
+func (a *AnalyzeHostGPU) CheckCondition(when string, data []byte) (bool, error) {
+	inventory := collect.GPUInventory{}
+	if err := json.Unmarshal(data, &inventory); err != nil {
+		return false, errors.Wrap(err, "failed to unmarshal gpu inventory")
+	}
+
+	parts := strings.Fields(when)
+	switch {
+	case len(parts) == 3 && parts[0] == "count":
+		return compareActualToWhen(parts[1]+" "+parts[2], countGPUs(inventory, ""))
+	case len(parts) == 4 && parts[1] == "count":
+		return compareActualToWhen(parts[2]+" "+parts[3], countGPUs(inventory, parts[0]))
+	case len(parts) == 4 && parts[0] == collect.GPUVendorNVIDIA && parts[1] == "driver":
+		version := ""
+		if inventory.NVIDIA != nil {
+			version = inventory.NVIDIA.DriverVersion
+		}
+		return compareGPUVersion(version, parts[2], parts[3])
+	case len(parts) == 4 && parts[0] == collect.GPUVendorAMD && parts[1] == "driver":
+		version := ""
+		if inventory.AMD != nil {
+			version = inventory.AMD.DriverVersion
+		}
+		return compareGPUVersion(version, parts[2], parts[3])
+	case len(parts) == 3 && parts[0] == "cuda":
+		version := ""
+		if inventory.NVIDIA != nil {
+			version = inventory.NVIDIA.CUDAVersion
+		}
+		return compareGPUVersion(version, parts[1], parts[2])
+	case len(parts) == 2 && parts[0] == "mig" && (parts[1] == "enabled" || parts[1] == "disabled"):
+		enabled := false
+		if inventory.NVIDIA != nil {
+			for _, gpu := range inventory.NVIDIA.GPUs {
+				if strings.EqualFold(gpu.MIGMode, "enabled") {
+					enabled = true
+				}
+			}
+		}
+		return enabled == (parts[1] == "enabled"), nil
+	}
+
+	return false, fmt.Errorf("unsupported when %q", when)
+}
+
+// countGPUs counts the GPUs of a vendor, or of all vendors if vendor is empty. nvidia-smi is
+// used as a fallback for NVIDIA GPUs when lspci is not available on the host.
+func countGPUs(inventory collect.GPUInventory, vendor string) int {
+	count := 0
+	nvidia := 0
+	for _, device := range inventory.Devices {
+		if device.Vendor == collect.GPUVendorNVIDIA {
+			nvidia++
+		}
+		if vendor == "" || device.Vendor == vendor {
+			count++
+		}
+	}
+
+	if inventory.NVIDIA != nil && len(inventory.NVIDIA.GPUs) > nvidia && (vendor == "" || vendor == collect.GPUVendorNVIDIA) {
+		count += len(inventory.NVIDIA.GPUs) - nvidia
+	}
+
+	return count
+}
+
+func compareGPUVersion(actual string, opString string, expected string) (bool, error) {
+	operator, err := ParseComparisonOperator(opString)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to parse comparison operator %q", opString)
+	}
+
+	if actual == "" {
+		actual = "0"
+	}
+	actualVersion, err := semver.ParseTolerant(actual)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to parse collected version %q", actual)
+	}
+	expectedVersion, err := semver.ParseTolerant(expected)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to parse expected version %q", expected)
+	}
+
+	switch operator {
+	case Equal:
+		return actualVersion.EQ(expectedVersion), nil
+	case NotEqual:
+		return actualVersion.NE(expectedVersion), nil
+	case LessThan:
+		return actualVersion.LT(expectedVersion), nil
+	case LessThanOrEqual:
+		return actualVersion.LTE(expectedVersion), nil
+	case GreaterThan:
+		return actualVersion.GT(expectedVersion), nil
+	case GreaterThanOrEqual:
+		return actualVersion.GTE(expectedVersion), nil
+	}
+
+	return false, fmt.Errorf("unsupported operator %q", opString)
+}
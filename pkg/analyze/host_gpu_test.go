@@ -0,0 +1,87 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/replicatedhq/troubleshoot/pkg/collect"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyzeHostGPU_CheckCondition(t *testing.T) {
+	nvidiaHost := collect.GPUInventory{
+		Devices: []collect.GPUDevice{
+			{Slot: "01:00.0", Vendor: collect.GPUVendorNVIDIA, Name: "GA100 [A100 SXM4 40GB]"},
+			{Slot: "02:00.0", Vendor: collect.GPUVendorNVIDIA, Name: "GA100 [A100 SXM4 40GB]"},
+		},
+		NVIDIA: &collect.NVIDIAGPUInfo{
+			DriverVersion: "535.104.05",
+			CUDAVersion:   "12.2",
+			GPUs: []collect.NVIDIAGPU{
+				{Index: 0, Name: "NVIDIA A100-SXM4-40GB", MIGMode: "Enabled", MIGDevices: 2},
+				{Index: 1, Name: "NVIDIA A100-SXM4-40GB", MIGMode: "Disabled"},
+			},
+		},
+	}
+
+	amdHost := collect.GPUInventory{
+		Devices: []collect.GPUDevice{
+			{Slot: "03:00.0", Vendor: collect.GPUVendorAMD, Name: "Aldebaran/MI200 [Instinct MI210]"},
+		},
+		AMD: &collect.AMDGPUInfo{DriverVersion: "6.3.6"},
+	}
+
+	tests := []struct {
+		name      string
+		inventory collect.GPUInventory
+		when      string
+		want      bool
+		wantErr   bool
+	}{
+		{name: "count", inventory: nvidiaHost, when: "count >= 1", want: true},
+		{name: "no gpus", inventory: collect.GPUInventory{}, when: "count == 0", want: true},
+		{name: "vendor count", inventory: amdHost, when: "nvidia count >= 1", want: false},
+		{name: "amd count", inventory: amdHost, when: "amd count == 1", want: true},
+		{name: "nvidia driver new enough", inventory: nvidiaHost, when: "nvidia driver >= 535", want: true},
+		{name: "nvidia driver too old", inventory: nvidiaHost, when: "nvidia driver < 550.54.14", want: true},
+		{name: "nvidia driver missing", inventory: amdHost, when: "nvidia driver < 535", want: true},
+		{name: "amd driver", inventory: amdHost, when: "amd driver >= 6.2", want: true},
+		{name: "cuda", inventory: nvidiaHost, when: "cuda >= 12.0", want: true},
+		{name: "mig enabled", inventory: nvidiaHost, when: "mig enabled", want: true},
+		{name: "mig disabled", inventory: amdHost, when: "mig disabled", want: true},
+		{name: "invalid version", inventory: nvidiaHost, when: "cuda >= latest", wantErr: true},
+		{name: "unsupported condition", inventory: nvidiaHost, when: "memory > 40GB", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := require.New(t)
+
+			data, err := json.Marshal(tt.inventory)
+			req.NoError(err)
+
+			a := AnalyzeHostGPU{}
+			got, err := a.CheckCondition(tt.when, data)
+			if tt.wantErr {
+				req.Error(err)
+				return
+			}
+			req.NoError(err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_countGPUs(t *testing.T) {
+	// lspci is not available in some minimal images, nvidia-smi still reports the gpus
+	inventory := collect.GPUInventory{
+		NVIDIA: &collect.NVIDIAGPUInfo{
+			GPUs: []collect.NVIDIAGPU{{Index: 0}, {Index: 1}},
+		},
+	}
+
+	assert.Equal(t, 2, countGPUs(inventory, ""))
+	assert.Equal(t, 2, countGPUs(inventory, collect.GPUVendorNVIDIA))
+	assert.Equal(t, 0, countGPUs(inventory, collect.GPUVendorAMD))
+}
@@ -3,7 +3,10 @@ package analyzer
 import (
 	"encoding/json"
 	"fmt"
+	"regexp"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/casbin/govaluate"
 	"github.com/pkg/errors"
@@ -11,6 +14,13 @@ import (
 	"github.com/replicatedhq/troubleshoot/pkg/collect"
 )
 
+// statusCodeClass matches conditions on a class of status codes, e.g. "statusCode == 5xx"
+var statusCodeClass = regexp.MustCompile(`statusCode\s*(==|!=)\s*([1-5])xx\b`)
+
+// latencyDuration matches conditions on the latency that compare it to a duration, e.g.
+// "latency > 500ms"
+var latencyDuration = regexp.MustCompile(`latency\s*(==|!=|>=|<=|>|<)\s*([0-9.]+(ns|us|µs|ms|s|m|h))\b`)
+
 type httpResult struct {
 	Error    *collect.HTTPError
 	Response *collect.HTTPResponse
@@ -66,6 +76,10 @@ func compareHostHTTPConditionalToActual(conditional string, result *httpResult)
 
 	conditional = strings.ReplaceAll(conditional, " = ", " == ")
 	conditional = strings.ReplaceAll(conditional, " === ", " == ")
+	conditional, err = expandHTTPConditional(conditional)
+	if err != nil {
+		return false, err
+	}
 
 	expression, err := govaluate.NewEvaluableExpression(conditional)
 	if err != nil {
@@ -78,6 +92,9 @@ func compareHostHTTPConditionalToActual(conditional string, result *httpResult)
 
 	parameters := make(map[string]interface{}, 8)
 	parameters["statusCode"] = result.Response.Status
+	parameters["latency"] = result.Response.LatencyMilliseconds
+	parameters["body"] = result.Response.Body
+	parameters["attempts"] = result.Response.Attempts
 
 	comparisonResult, err := expression.Evaluate(parameters)
 
@@ -93,6 +110,32 @@ func compareHostHTTPConditionalToActual(conditional string, result *httpResult)
 	return boolResult, nil
 }
 
+// expandHTTPConditional rewrites the shorthands of a condition into expressions: status code
+// classes into ranges, e.g. "statusCode == 2xx" into "(statusCode >= 200 && statusCode < 300)", and
+// latencies given as durations into milliseconds, e.g. "latency > 1.5s" into "latency > 1500".
+func expandHTTPConditional(conditional string) (string, error) {
+	conditional = statusCodeClass.ReplaceAllStringFunc(conditional, func(match string) string {
+		parts := statusCodeClass.FindStringSubmatch(match)
+		class, _ := strconv.Atoi(parts[2])
+		if parts[1] == "!=" {
+			return fmt.Sprintf("(statusCode < %d || statusCode >= %d)", class*100, (class+1)*100)
+		}
+		return fmt.Sprintf("(statusCode >= %d && statusCode < %d)", class*100, (class+1)*100)
+	})
+
+	var err error
+	conditional = latencyDuration.ReplaceAllStringFunc(conditional, func(match string) string {
+		parts := latencyDuration.FindStringSubmatch(match)
+		duration, parseErr := time.ParseDuration(parts[2])
+		if parseErr != nil {
+			err = errors.Wrapf(parseErr, "failed to parse latency %q", parts[2])
+			return match
+		}
+		return fmt.Sprintf("latency %s %s", parts[1], strconv.FormatFloat(float64(duration)/float64(time.Millisecond), 'f', -1, 64))
+	})
+	return conditional, err
+}
+
 func analyzeHTTPResult(analyzer *troubleshootv1beta2.HTTPAnalyze, fileName string, getCollectedFileContents getCollectedFileContents, title string) ([]*AnalyzeResult, error) {
 	contents, err := getCollectedFileContents(fileName)
 	if err != nil {
@@ -114,6 +157,7 @@ func analyzeHTTPResult(analyzer *troubleshootv1beta2.HTTPAnalyze, fileName strin
 				result.IsFail = true
 				result.Message = outcome.Fail.Message
 				result.URI = outcome.Fail.URI
+				result.Remediation = outcome.Fail.Remediation
 
 				return []*AnalyzeResult{result}, nil
 			}
@@ -127,6 +171,7 @@ func analyzeHTTPResult(analyzer *troubleshootv1beta2.HTTPAnalyze, fileName strin
 				result.IsFail = true
 				result.Message = outcome.Fail.Message
 				result.URI = outcome.Fail.URI
+				result.Remediation = outcome.Fail.Remediation
 
 				return []*AnalyzeResult{result}, nil
 			}
@@ -135,6 +180,7 @@ func analyzeHTTPResult(analyzer *troubleshootv1beta2.HTTPAnalyze, fileName strin
 				result.IsWarn = true
 				result.Message = outcome.Warn.Message
 				result.URI = outcome.Warn.URI
+				result.Remediation = outcome.Warn.Remediation
 
 				return []*AnalyzeResult{result}, nil
 			}
@@ -148,6 +194,7 @@ func analyzeHTTPResult(analyzer *troubleshootv1beta2.HTTPAnalyze, fileName strin
 				result.IsWarn = true
 				result.Message = outcome.Warn.Message
 				result.URI = outcome.Warn.URI
+				result.Remediation = outcome.Warn.Remediation
 
 				return []*AnalyzeResult{result}, nil
 			}
@@ -156,6 +203,7 @@ func analyzeHTTPResult(analyzer *troubleshootv1beta2.HTTPAnalyze, fileName strin
 				result.IsPass = true
 				result.Message = outcome.Pass.Message
 				result.URI = outcome.Pass.URI
+				result.Remediation = outcome.Pass.Remediation
 
 				return []*AnalyzeResult{result}, nil
 			}
@@ -169,6 +217,7 @@ func analyzeHTTPResult(analyzer *troubleshootv1beta2.HTTPAnalyze, fileName strin
 				result.IsPass = true
 				result.Message = outcome.Pass.Message
 				result.URI = outcome.Pass.URI
+				result.Remediation = outcome.Pass.Remediation
 
 				return []*AnalyzeResult{result}, nil
 			}
@@ -259,7 +259,10 @@ func TestAnalyzeHostHTTP(t *testing.T) {
 func TestAnalyzeHostHTTPHTTPCodesAndCompareOperators(t *testing.T) {
 	httpResult := &httpResult{
 		Response: &collect.HTTPResponse{
-			Status: 200,
+			Status:              200,
+			Body:                `{"status": "healthy"}`,
+			LatencyMilliseconds: 120,
+			Attempts:            2,
 		},
 	}
 
@@ -299,6 +302,27 @@ func TestAnalyzeHostHTTPHTTPCodesAndCompareOperators(t *testing.T) {
 		{
 			name: "statusCode < 201 || statusCode > 199 && statusCode == 200",
 		},
+		{
+			name: "statusCode == 2xx",
+		},
+		{
+			name: "statusCode != 5xx && statusCode != 4xx",
+		},
+		{
+			name: "latency < 500ms",
+		},
+		{
+			name: "latency >= 0.1s && latency < 1s",
+		},
+		{
+			name: "latency == 120",
+		},
+		{
+			name: "body =~ 'status.+healthy'",
+		},
+		{
+			name: "body !~ 'unhealthy' && attempts > 1",
+		},
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
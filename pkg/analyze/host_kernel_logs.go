@@ -0,0 +1,284 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/troubleshoot/internal/util"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/collect"
+)
+
+// Ensure `AnalyzeHostKernelLogs` implements `HostAnalyzer` interface at compile time.
+var _ HostAnalyzer = (*AnalyzeHostKernelLogs)(nil)
+
+// kernelLogClass is a class of errors reported in the kernel logs, matched by any of its patterns
+type kernelLogClass struct {
+	name     string
+	title    string
+	isWarn   bool
+	patterns []*regexp.Regexp
+}
+
+// kernelLogClasses is the library of the classes of errors the analyzer reports
+var kernelLogClasses = []kernelLogClass{
+	{
+		name:   "oomKiller",
+		title:  "OOM killer",
+		isWarn: true,
+		patterns: []*regexp.Regexp{
+			regexp.MustCompile(`invoked oom-killer`),
+			regexp.MustCompile(`Out of memory: Kill(ed)? process`),
+			regexp.MustCompile(`Memory cgroup out of memory`),
+		},
+	},
+	{
+		name:  "ioError",
+		title: "I/O errors",
+		patterns: []*regexp.Regexp{
+			regexp.MustCompile(`I/O error, dev \S+`),
+			regexp.MustCompile(`Buffer I/O error on dev`),
+			regexp.MustCompile(`critical (medium|target|nexus|space allocation) error`),
+			regexp.MustCompile(`end_request: I/O error`),
+		},
+	},
+	{
+		name:  "xfsError",
+		title: "XFS errors",
+		patterns: []*regexp.Regexp{
+			regexp.MustCompile(`XFS \(\S+\): .*(Corruption|corruption|metadata I/O error|Internal error|Filesystem has been shut down|Log I/O Error)`),
+		},
+	},
+	{
+		name:  "ext4Error",
+		title: "ext4 errors",
+		patterns: []*regexp.Regexp{
+			regexp.MustCompile(`EXT4-fs error`),
+			regexp.MustCompile(`EXT4-fs \(\S+\): Remounting filesystem read-only`),
+			regexp.MustCompile(`EXT4-fs \(\S+\): error count since last fsck`),
+		},
+	},
+	{
+		name:  "conntrackFull",
+		title: "Conntrack table full",
+		patterns: []*regexp.Regexp{
+			regexp.MustCompile(`nf_conntrack: (nf_conntrack: )?table full, dropping packet`),
+		},
+	},
+	{
+		name:   "nicFlap",
+		title:  "NIC link flaps",
+		isWarn: true,
+		patterns: []*regexp.Regexp{
+			regexp.MustCompile(`(?i)\bNIC Link is Down\b`),
+			regexp.MustCompile(`\S+: [Ll]ink is [Dd]own\b`),
+			regexp.MustCompile(`\bcarrier lost\b`),
+		},
+	},
+}
+
+// kernelLogsTemplateData is passed to the messages of the outcomes, for each class of errors found
+type kernelLogsTemplateData struct {
+	Class string
+	Title string
+	// Count is the number of lines matching the class
+	Count int
+	// Sources are the names of the logs with matching lines, comma separated
+	Sources string
+	// Last is the most recent matching line
+	Last string
+}
+
+type AnalyzeHostKernelLogs struct {
+	hostAnalyzer *troubleshootv1beta2.KernelLogsAnalyze
+}
+
+func (a *AnalyzeHostKernelLogs) Title() string {
+	return hostAnalyzerTitleOrDefault(a.hostAnalyzer.AnalyzeMeta, "Kernel Logs")
+}
+
+func (a *AnalyzeHostKernelLogs) IsExcluded() (bool, error) {
+	return isExcluded(a.hostAnalyzer.Exclude)
+}
+
+// Analyze classifies the lines of the collected logs and returns a result for every class of
+// errors found, using the first fail or warn outcome, or the severity of the class when there
+// is none. The outcome message may reference the class with the fields of
+// kernelLogsTemplateData, e.g. "{{ .Count }} lines match {{ .Title }}". When no class is found
+// the pass outcome is returned.
+func (a *AnalyzeHostKernelLogs) Analyze(
+	getCollectedFileContents func(string) ([]byte, error), findFiles getChildCollectedFileContents,
+) ([]*AnalyzeResult, error) {
+	classes, err := a.classes()
+	if err != nil {
+		return nil, err
+	}
+
+	collectedContents, err := retrieveCollectedContents(
+		getCollectedFileContents,
+		collect.HostKernelLogsPath,
+		collect.NodeInfoBaseDir,
+		collect.HostKernelLogsFileName,
+	)
+	if err != nil {
+		return []*AnalyzeResult{{Title: a.Title()}}, err
+	}
+
+	var results []*AnalyzeResult
+	for _, content := range collectedContents {
+		currentTitle := a.Title()
+		if content.NodeName != "" {
+			currentTitle = fmt.Sprintf("%s - Node %s", a.Title(), content.NodeName)
+		}
+
+		info := collect.KernelLogsInfo{}
+		if err := json.Unmarshal(content.Data, &info); err != nil {
+			return nil, errors.Wrapf(err, "failed to unmarshal kernel logs for %s", currentTitle)
+		}
+
+		classResults, err := a.classResults(classifyKernelLogs(info, classes), currentTitle)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, classResults...)
+	}
+
+	return results, nil
+}
+
+// classes returns the classes of the library the analyzer is restricted to
+func (a *AnalyzeHostKernelLogs) classes() ([]kernelLogClass, error) {
+	if len(a.hostAnalyzer.Classes) == 0 {
+		return kernelLogClasses, nil
+	}
+
+	classes := []kernelLogClass{}
+	for _, name := range a.hostAnalyzer.Classes {
+		found := false
+		for _, class := range kernelLogClasses {
+			if class.name == name {
+				classes = append(classes, class)
+				found = true
+				break
+			}
+		}
+		if !found {
+			names := []string{}
+			for _, class := range kernelLogClasses {
+				names = append(names, class.name)
+			}
+			return nil, errors.Errorf("unsupported kernel log class %q, must be one of %s", name, strings.Join(names, ", "))
+		}
+	}
+	return classes, nil
+}
+
+// kernelLogMatches are the lines of the logs matching a class
+type kernelLogMatches struct {
+	class   kernelLogClass
+	count   int
+	sources []string
+	last    string
+}
+
+// classifyKernelLogs returns the matches of each class found in the logs, in the order of the
+// classes. A line is counted once per class.
+func classifyKernelLogs(info collect.KernelLogsInfo, classes []kernelLogClass) []kernelLogMatches {
+	found := []kernelLogMatches{}
+	for _, class := range classes {
+		matches := kernelLogMatches{class: class}
+		for _, source := range info.Sources {
+			matched := false
+			for _, line := range source.Lines {
+				for _, pattern := range class.patterns {
+					if pattern.MatchString(line) {
+						matches.count++
+						matches.last = line
+						matched = true
+						break
+					}
+				}
+			}
+			if matched {
+				matches.sources = append(matches.sources, source.Name)
+			}
+		}
+		if matches.count > 0 {
+			found = append(found, matches)
+		}
+	}
+	return found
+}
+
+func (a *AnalyzeHostKernelLogs) classResults(found []kernelLogMatches, title string) ([]*AnalyzeResult, error) {
+	strict := a.hostAnalyzer.Strict.BoolOrDefaultFalse()
+
+	if len(found) == 0 {
+		for _, outcome := range a.hostAnalyzer.Outcomes {
+			if outcome.Pass != nil {
+				return []*AnalyzeResult{{
+					Title:       title,
+					IsPass:      true,
+					Message:     outcome.Pass.Message,
+					URI:         outcome.Pass.URI,
+					Remediation: outcome.Pass.Remediation,
+					Strict:      strict,
+				}}, nil
+			}
+		}
+		return []*AnalyzeResult{{
+			Title:   title,
+			IsPass:  true,
+			Message: "No kernel errors were found",
+			Strict:  strict,
+		}}, nil
+	}
+
+	var singleOutcome *troubleshootv1beta2.SingleOutcome
+	isWarn := false
+	for _, outcome := range a.hostAnalyzer.Outcomes {
+		if outcome.Fail != nil {
+			singleOutcome = outcome.Fail
+			break
+		}
+		if outcome.Warn != nil {
+			singleOutcome, isWarn = outcome.Warn, true
+			break
+		}
+	}
+
+	results := []*AnalyzeResult{}
+	for _, matches := range found {
+		data := kernelLogsTemplateData{
+			Class:   matches.class.name,
+			Title:   matches.class.title,
+			Count:   matches.count,
+			Sources: strings.Join(matches.sources, ", "),
+			Last:    matches.last,
+		}
+		result := &AnalyzeResult{
+			Title:   fmt.Sprintf("%s: %s", title, matches.class.title),
+			IsFail:  !matches.class.isWarn,
+			IsWarn:  matches.class.isWarn,
+			Message: fmt.Sprintf("%s: %d lines in %s, the last one is %q", data.Title, data.Count, data.Sources, data.Last),
+			Strict:  strict,
+		}
+		if singleOutcome != nil {
+			result.IsFail, result.IsWarn = !isWarn, isWarn
+			result.URI, result.Remediation = singleOutcome.URI, singleOutcome.Remediation
+			if singleOutcome.Message != "" {
+				message, err := util.RenderTemplate(singleOutcome.Message, data)
+				if err != nil {
+					return nil, errors.Wrap(err, "failed to render message template")
+				}
+				result.Message = message
+			}
+		}
+		results = append(results, result)
+	}
+
+	return results, nil
+}
@@ -0,0 +1,137 @@
+package analyzer
+
+import (
+	"testing"
+
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/collect"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnalyzeHostKernelLogs(t *testing.T) {
+	logs := `{
+		"since": "-24h",
+		"sources": [
+			{
+				"name": "dmesg",
+				"filtered": true,
+				"lines": [
+					"2024-01-01T00:00:00+0000 node kernel: java invoked oom-killer: gfp_mask=0x100cca(GFP_HIGHUSER_MOVABLE), order=0",
+					"2024-01-01T00:00:00+0000 node kernel: Out of memory: Killed process 42 (java) total-vm:8000000kB",
+					"2024-01-01T00:01:00+0000 node kernel: nf_conntrack: nf_conntrack: table full, dropping packet",
+					"2024-01-01T00:02:00+0000 node kernel: eth0: Link is Up - 10Gbps/Full"
+				]
+			},
+			{
+				"name": "kubelet.service",
+				"filtered": true,
+				"lines": [
+					"2024-01-01T00:03:00+0000 node kubelet[100]: nf_conntrack: table full, dropping packet"
+				]
+			}
+		]
+	}`
+	healthy := `{"sources": [{"name": "dmesg", "lines": ["2024-01-01T00:02:00+0000 node kernel: eth0: Link is Up - 10Gbps/Full"]}]}`
+
+	tests := []struct {
+		name      string
+		logs      string
+		analyzer  *troubleshootv1beta2.KernelLogsAnalyze
+		results   []*AnalyzeResult
+		expectErr bool
+	}{
+		{
+			name:     "one result per class with their severity",
+			logs:     logs,
+			analyzer: &troubleshootv1beta2.KernelLogsAnalyze{},
+			results: []*AnalyzeResult{
+				{
+					Title:   "Kernel Logs: OOM killer",
+					IsWarn:  true,
+					Message: `OOM killer: 2 lines in dmesg, the last one is "2024-01-01T00:00:00+0000 node kernel: Out of memory: Killed process 42 (java) total-vm:8000000kB"`,
+				},
+				{
+					Title:   "Kernel Logs: Conntrack table full",
+					IsFail:  true,
+					Message: `Conntrack table full: 2 lines in dmesg, kubelet.service, the last one is "2024-01-01T00:03:00+0000 node kubelet[100]: nf_conntrack: table full, dropping packet"`,
+				},
+			},
+		},
+		{
+			name: "outcome message and restricted classes",
+			logs: logs,
+			analyzer: &troubleshootv1beta2.KernelLogsAnalyze{
+				Classes: []string{"conntrackFull", "nicFlap"},
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{Warn: &troubleshootv1beta2.SingleOutcome{Message: "{{ .Class }} found {{ .Count }} times in {{ .Sources }}", URI: "https://example.com"}},
+					{Pass: &troubleshootv1beta2.SingleOutcome{Message: "kernel logs are clean"}},
+				},
+			},
+			results: []*AnalyzeResult{
+				{Title: "Kernel Logs: Conntrack table full", IsWarn: true, URI: "https://example.com", Message: "conntrackFull found 2 times in dmesg, kubelet.service"},
+			},
+		},
+		{
+			name: "pass outcome",
+			logs: healthy,
+			analyzer: &troubleshootv1beta2.KernelLogsAnalyze{
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{Fail: &troubleshootv1beta2.SingleOutcome{Message: "{{ .Title }}"}},
+					{Pass: &troubleshootv1beta2.SingleOutcome{Message: "kernel logs are clean"}},
+				},
+			},
+			results: []*AnalyzeResult{
+				{Title: "Kernel Logs", IsPass: true, Message: "kernel logs are clean"},
+			},
+		},
+		{
+			name:     "default pass",
+			logs:     healthy,
+			analyzer: &troubleshootv1beta2.KernelLogsAnalyze{},
+			results: []*AnalyzeResult{
+				{Title: "Kernel Logs", IsPass: true, Message: "No kernel errors were found"},
+			},
+		},
+		{
+			name:      "unsupported class",
+			logs:      logs,
+			analyzer:  &troubleshootv1beta2.KernelLogsAnalyze{Classes: []string{"segfault"}},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			getCollectedFileContents := func(_ string) ([]byte, error) {
+				return []byte(tt.logs), nil
+			}
+
+			analyzer := AnalyzeHostKernelLogs{hostAnalyzer: tt.analyzer}
+			results, err := analyzer.Analyze(getCollectedFileContents, nil)
+
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.results, results)
+			}
+		})
+	}
+}
+
+func Test_classifyKernelLogs(t *testing.T) {
+	lines := map[string]string{
+		"ioError":   "blk_update_request: I/O error, dev sdb, sector 2048 op 0x0:(READ)",
+		"xfsError":  "XFS (dm-0): metadata I/O error in \"xfs_trans_read_buf_map\" at daddr 0x2 len 1 error 5",
+		"ext4Error": "EXT4-fs error (device sda1): ext4_lookup:1601: inode #2: comm ls: deleted inode referenced: 12",
+		"nicFlap":   "ixgbe 0000:01:00.0 eth1: NIC Link is Down",
+	}
+
+	for class, line := range lines {
+		info := collect.KernelLogsInfo{Sources: []collect.KernelLogSource{{Name: "dmesg", Lines: []string{line}}}}
+		found := classifyKernelLogs(info, kernelLogClasses)
+		if assert.Len(t, found, 1, line) {
+			assert.Equal(t, class, found[0].class.name)
+		}
+	}
+}
@@ -69,6 +69,7 @@ func (a *AnalyzeHostKernelModules) Analyze(
 				result.IsFail = true
 				result.Message = outcome.Fail.Message
 				result.URI = outcome.Fail.URI
+				result.Remediation = outcome.Fail.Remediation
 
 				coll.push(result)
 				failed = true
@@ -83,6 +84,7 @@ func (a *AnalyzeHostKernelModules) Analyze(
 				result.IsWarn = true
 				result.Message = outcome.Warn.Message
 				result.URI = outcome.Warn.URI
+				result.Remediation = outcome.Warn.Remediation
 
 				coll.push(result)
 			}
@@ -96,6 +98,7 @@ func (a *AnalyzeHostKernelModules) Analyze(
 				result.IsPass = true
 				result.Message = outcome.Pass.Message
 				result.URI = outcome.Pass.URI
+				result.Remediation = outcome.Pass.Remediation
 
 				coll.push(result)
 				passed = true
@@ -110,6 +113,7 @@ func (a *AnalyzeHostKernelModules) Analyze(
 			result.IsFail = true
 			result.Message = outcome.Fail.Message
 			result.URI = outcome.Fail.URI
+			result.Remediation = outcome.Fail.Remediation
 
 			coll.push(result)
 			break
@@ -117,6 +121,7 @@ func (a *AnalyzeHostKernelModules) Analyze(
 			result.IsWarn = true
 			result.Message = outcome.Warn.Message
 			result.URI = outcome.Warn.URI
+			result.Remediation = outcome.Warn.Remediation
 
 			coll.push(result)
 			break
@@ -124,6 +129,7 @@ func (a *AnalyzeHostKernelModules) Analyze(
 			result.IsPass = true
 			result.Message = outcome.Pass.Message
 			result.URI = outcome.Pass.URI
+			result.Remediation = outcome.Pass.Remediation
 
 			coll.push(result)
 			break
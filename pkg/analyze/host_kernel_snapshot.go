@@ -0,0 +1,232 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/collect"
+)
+
+// Ensure `AnalyzeHostKernelSnapshot` implements `HostAnalyzer` interface at compile time.
+var _ HostAnalyzer = (*AnalyzeHostKernelSnapshot)(nil)
+
+// <1:operator> <2:value>
+var kernelBaselineRX = regexp.MustCompile(`^\s*(==|=|!=|>=|<=|>|<)?\s*(.*?)\s*$`)
+
+type AnalyzeHostKernelSnapshot struct {
+	hostAnalyzer *troubleshootv1beta2.KernelSnapshotAnalyze
+}
+
+func (a *AnalyzeHostKernelSnapshot) Title() string {
+	return hostAnalyzerTitleOrDefault(a.hostAnalyzer.AnalyzeMeta, "Kernel Snapshot")
+}
+
+func (a *AnalyzeHostKernelSnapshot) IsExcluded() (bool, error) {
+	return isExcluded(a.hostAnalyzer.Exclude)
+}
+
+// Analyze compares the collected kernel snapshot against the baseline in the spec.
+//
+// A result is returned for every deviation from the baseline, using the first fail
+// or warn outcome. The outcome message may reference the deviation with
+// "{{ .Deviation }}"; an empty message is replaced by a description of the deviation.
+// When there are no deviations the pass outcome, if any, is returned.
+func (a *AnalyzeHostKernelSnapshot) Analyze(
+	getCollectedFileContents func(string) ([]byte, error), findFiles getChildCollectedFileContents,
+) ([]*AnalyzeResult, error) {
+	collectedContents, err := retrieveCollectedContents(
+		getCollectedFileContents,
+		collect.HostKernelSnapshotPath,
+		collect.NodeInfoBaseDir,
+		collect.HostKernelSnapshotFileName,
+	)
+	if err != nil {
+		return []*AnalyzeResult{{Title: a.Title()}}, err
+	}
+
+	var results []*AnalyzeResult
+	for _, content := range collectedContents {
+		currentTitle := a.Title()
+		if content.NodeName != "" {
+			currentTitle = fmt.Sprintf("%s - Node %s", a.Title(), content.NodeName)
+		}
+
+		snapshot := collect.KernelSnapshot{}
+		if err := json.Unmarshal(content.Data, &snapshot); err != nil {
+			return nil, errors.Wrapf(err, "failed to unmarshal kernel snapshot for %s", currentTitle)
+		}
+
+		deviations, err := a.findDeviations(snapshot)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to compare kernel snapshot for %s", currentTitle)
+		}
+
+		results = append(results, a.deviationResults(deviations, currentTitle)...)
+	}
+
+	return results, nil
+}
+
+// findDeviations returns a description of every way the snapshot differs from the baseline
+func (a *AnalyzeHostKernelSnapshot) findDeviations(snapshot collect.KernelSnapshot) ([]string, error) {
+	var deviations []string
+
+	params := make([]string, 0, len(a.hostAnalyzer.Sysctl))
+	for param := range a.hostAnalyzer.Sysctl {
+		params = append(params, param)
+	}
+	sort.Strings(params)
+
+	for _, param := range params {
+		expected := a.hostAnalyzer.Sysctl[param]
+		actual, ok := snapshot.Sysctl[param]
+		if !ok {
+			deviations = append(deviations, fmt.Sprintf("kernel parameter %s is not set, expected %s", param, expected))
+			continue
+		}
+
+		matches, err := compareKernelParameter(actual, expected)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to compare kernel parameter %s", param)
+		}
+		if !matches {
+			deviations = append(deviations, fmt.Sprintf("kernel parameter %s is %s, expected %s", param, actual, expected))
+		}
+	}
+
+	for _, module := range a.hostAnalyzer.Modules {
+		info, ok := snapshot.Modules[module]
+		if !ok || info.Status != collect.KernelModuleLoaded {
+			deviations = append(deviations, fmt.Sprintf("kernel module %s is not loaded", module))
+		}
+	}
+
+	args := strings.Fields(snapshot.Cmdline)
+	for _, arg := range a.hostAnalyzer.Cmdline {
+		found := false
+		for _, actual := range args {
+			if actual == arg {
+				found = true
+				break
+			}
+		}
+		if !found {
+			deviations = append(deviations, fmt.Sprintf("kernel command line does not contain %s", arg))
+		}
+	}
+
+	return deviations, nil
+}
+
+// kernelSnapshotTemplateData is the data the messages of the fail and warn outcomes are rendered
+// with, once for each deviation from the baseline.
+type kernelSnapshotTemplateData struct {
+	Deviation string
+}
+
+func (a *AnalyzeHostKernelSnapshot) deviationResults(deviations []string, title string) []*AnalyzeResult {
+	var results []*AnalyzeResult
+
+	if len(deviations) == 0 {
+		for _, outcome := range a.hostAnalyzer.Outcomes {
+			if outcome.Pass != nil {
+				return []*AnalyzeResult{{
+					Title:       title,
+					IsPass:      true,
+					Message:     outcome.Pass.Message,
+					URI:         outcome.Pass.URI,
+					Remediation: outcome.Pass.Remediation,
+					Strict:      a.hostAnalyzer.Strict.BoolOrDefaultFalse(),
+				}}
+			}
+		}
+		return nil
+	}
+
+	isWarn := false
+	message, uri := "", ""
+	var remediation *troubleshootv1beta2.Remediation
+	for _, outcome := range a.hostAnalyzer.Outcomes {
+		if outcome.Fail != nil {
+			message, uri, remediation = outcome.Fail.Message, outcome.Fail.URI, outcome.Fail.Remediation
+			break
+		}
+		if outcome.Warn != nil {
+			isWarn = true
+			message, uri, remediation = outcome.Warn.Message, outcome.Warn.URI, outcome.Warn.Remediation
+			break
+		}
+	}
+
+	for _, deviation := range deviations {
+		result := &AnalyzeResult{
+			Title:       title,
+			IsFail:      !isWarn,
+			IsWarn:      isWarn,
+			Message:     deviation,
+			URI:         uri,
+			Remediation: remediation,
+			Strict:      a.hostAnalyzer.Strict.BoolOrDefaultFalse(),
+		}
+		if message != "" {
+			result.Message = renderTemplate(message, kernelSnapshotTemplateData{Deviation: deviation})
+		}
+		results = append(results, result)
+	}
+
+	return results
+}
+
+// compareKernelParameter checks a collected kernel parameter against an expected value,
+// optionally prefixed by a comparison operator. Inequalities are only supported for integers.
+func compareKernelParameter(actual string, expected string) (bool, error) {
+	matches := kernelBaselineRX.FindStringSubmatch(expected)
+	opString, value := matches[1], matches[2]
+	if opString == "" {
+		opString = "="
+	}
+
+	operator, err := ParseComparisonOperator(opString)
+	if err != nil {
+		return false, err
+	}
+
+	// multi-value parameters such as net.ipv4.tcp_rmem are tab separated
+	actual = strings.Join(strings.Fields(actual), " ")
+	value = strings.Join(strings.Fields(value), " ")
+
+	switch operator {
+	case Equal:
+		return actual == value, nil
+	case NotEqual:
+		return actual != value, nil
+	}
+
+	actualInt, err := strconv.Atoi(actual)
+	if err != nil {
+		return false, errors.Errorf("collected value %q cannot be used with operator %q", actual, opString)
+	}
+	expectedInt, err := strconv.Atoi(value)
+	if err != nil {
+		return false, errors.Errorf("expected value %q cannot be used with operator %q", value, opString)
+	}
+
+	switch operator {
+	case LessThan:
+		return actualInt < expectedInt, nil
+	case LessThanOrEqual:
+		return actualInt <= expectedInt, nil
+	case GreaterThan:
+		return actualInt > expectedInt, nil
+	case GreaterThanOrEqual:
+		return actualInt >= expectedInt, nil
+	default:
+		return false, errors.Errorf("unsupported operator %q", opString)
+	}
+}
@@ -0,0 +1,130 @@
+package analyzer
+
+import (
+	"testing"
+
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnalyzeHostKernelSnapshot(t *testing.T) {
+	snapshot := `{
+		"kernelRelease": "6.1.0-18-amd64",
+		"sysctl": {
+			"vm.max_map_count": "65530",
+			"net.ipv4.ip_forward": "1",
+			"net.ipv4.tcp_rmem": "4096\t131072\t6291456",
+			"kernel.hostname": "node-1"
+		},
+		"modules": {
+			"overlay": {"size": 151552, "instances": 1, "status": "loaded"},
+			"br_netfilter": {"size": 0, "instances": 0, "status": "loadable"}
+		},
+		"cmdline": "BOOT_IMAGE=/vmlinuz root=/dev/sda1 ro cgroup_no_v1=all"
+	}`
+
+	tests := []struct {
+		name      string
+		analyzer  *troubleshootv1beta2.KernelSnapshotAnalyze
+		results   []*AnalyzeResult
+		expectErr bool
+	}{
+		{
+			name: "matches baseline",
+			analyzer: &troubleshootv1beta2.KernelSnapshotAnalyze{
+				Sysctl: map[string]string{
+					"vm.max_map_count":    ">= 65530",
+					"net.ipv4.ip_forward": "1",
+					"net.ipv4.tcp_rmem":   "4096 131072 6291456",
+					"kernel.hostname":     "!= localhost",
+				},
+				Modules: []string{"overlay"},
+				Cmdline: []string{"cgroup_no_v1=all"},
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{Fail: &troubleshootv1beta2.SingleOutcome{Message: "{{ .Deviation }}"}},
+					{Pass: &troubleshootv1beta2.SingleOutcome{Message: "kernel matches baseline"}},
+				},
+			},
+			results: []*AnalyzeResult{
+				{Title: "Kernel Snapshot", IsPass: true, Message: "kernel matches baseline"},
+			},
+		},
+		{
+			name: "each deviation is reported",
+			analyzer: &troubleshootv1beta2.KernelSnapshotAnalyze{
+				Sysctl: map[string]string{
+					"vm.max_map_count":     ">= 262144",
+					"net.ipv4.ip_forward":  "1",
+					"fs.inotify.max_users": "8192",
+				},
+				Modules: []string{"overlay", "br_netfilter"},
+				Cmdline: []string{"ro", "systemd.unified_cgroup_hierarchy=1"},
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{Fail: &troubleshootv1beta2.SingleOutcome{Message: "baseline deviation: {{ .Deviation }}", URI: "https://example.com"}},
+					{Pass: &troubleshootv1beta2.SingleOutcome{Message: "kernel matches baseline"}},
+				},
+			},
+			results: []*AnalyzeResult{
+				{Title: "Kernel Snapshot", IsFail: true, URI: "https://example.com", Message: "baseline deviation: kernel parameter fs.inotify.max_users is not set, expected 8192"},
+				{Title: "Kernel Snapshot", IsFail: true, URI: "https://example.com", Message: "baseline deviation: kernel parameter vm.max_map_count is 65530, expected >= 262144"},
+				{Title: "Kernel Snapshot", IsFail: true, URI: "https://example.com", Message: "baseline deviation: kernel module br_netfilter is not loaded"},
+				{Title: "Kernel Snapshot", IsFail: true, URI: "https://example.com", Message: "baseline deviation: kernel command line does not contain systemd.unified_cgroup_hierarchy=1"},
+			},
+		},
+		{
+			name: "deviations as warnings with default message",
+			analyzer: &troubleshootv1beta2.KernelSnapshotAnalyze{
+				Sysctl: map[string]string{
+					"vm.max_map_count": "> 65530",
+				},
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{Warn: &troubleshootv1beta2.SingleOutcome{}},
+				},
+			},
+			results: []*AnalyzeResult{
+				{Title: "Kernel Snapshot", IsWarn: true, Message: "kernel parameter vm.max_map_count is 65530, expected > 65530"},
+			},
+		},
+		{
+			name: "deviation message rendered as a template",
+			analyzer: &troubleshootv1beta2.KernelSnapshotAnalyze{
+				Sysctl: map[string]string{
+					"vm.max_map_count": "> 65530",
+				},
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{Warn: &troubleshootv1beta2.SingleOutcome{Message: "{{.Deviation | upper}}"}},
+				},
+			},
+			results: []*AnalyzeResult{
+				{Title: "Kernel Snapshot", IsWarn: true, Message: "KERNEL PARAMETER VM.MAX_MAP_COUNT IS 65530, EXPECTED > 65530"},
+			},
+		},
+		{
+			name: "inequality on a non-numeric parameter",
+			analyzer: &troubleshootv1beta2.KernelSnapshotAnalyze{
+				Sysctl: map[string]string{
+					"kernel.hostname": ">= 1",
+				},
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			getCollectedFileContents := func(_ string) ([]byte, error) {
+				return []byte(snapshot), nil
+			}
+
+			analyzer := AnalyzeHostKernelSnapshot{hostAnalyzer: tt.analyzer}
+			results, err := analyzer.Analyze(getCollectedFileContents, nil)
+
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.results, results)
+			}
+		})
+	}
+}
@@ -0,0 +1,142 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/blang/semver/v4"
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/collect"
+)
+
+// Ensure `AnalyzeHostKubeletConfig` implements `HostAnalyzer` interface at compile time.
+var _ HostAnalyzer = (*AnalyzeHostKubeletConfig)(nil)
+
+var (
+	// cgroupV2MinVersion is the first version of Kubernetes with general availability of cgroup v2
+	cgroupV2MinVersion = semver.MustParse("1.25.0")
+	// cgroupV1FailVersion is the first version of Kubernetes whose kubelet refuses to start on
+	// cgroup v1 unless failCgroupV1 is false
+	cgroupV1FailVersion = semver.MustParse("1.35.0")
+)
+
+type AnalyzeHostKubeletConfig struct {
+	hostAnalyzer *troubleshootv1beta2.KubeletConfigAnalyze
+}
+
+func (a *AnalyzeHostKubeletConfig) Title() string {
+	return hostAnalyzerTitleOrDefault(a.hostAnalyzer.AnalyzeMeta, "Kubelet Config")
+}
+
+func (a *AnalyzeHostKubeletConfig) IsExcluded() (bool, error) {
+	return isExcluded(a.hostAnalyzer.Exclude)
+}
+
+func (a *AnalyzeHostKubeletConfig) Analyze(
+	getCollectedFileContents func(string) ([]byte, error), findFiles getChildCollectedFileContents,
+) ([]*AnalyzeResult, error) {
+	result := AnalyzeResult{Title: a.Title()}
+
+	collectedContents, err := retrieveCollectedContents(
+		getCollectedFileContents,
+		collect.HostKubeletConfigPath,
+		collect.NodeInfoBaseDir,
+		collect.HostKubeletConfigFileName,
+	)
+	if err != nil {
+		return []*AnalyzeResult{&result}, err
+	}
+
+	results, err := analyzeHostCollectorResults(collectedContents, a.hostAnalyzer.Outcomes, a.CheckCondition, a.Title())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to analyze kubelet config")
+	}
+
+	return results, nil
+}
+
+// CheckCondition evaluates a when clause against the collected kubelet config. Supported conditions are:
+//
+//   - "cgroupVersion <operator> <v1|v2>", the cgroup version of the host
+//   - "cgroupDriver <operator> <systemd|cgroupfs>", the cgroup driver of the kubelet
+//   - "runtimeCgroupDriver <operator> <systemd|cgroupfs>", the cgroup driver of the container runtime
+//   - "cgroupDriverMismatch <operator> <true|false>", whether the kubelet and the container runtime use different
+//     cgroup drivers. It is false when the driver of the runtime is not known.
+//   - "cgroupVersionSupported <operator> <true|false>", whether the version of Kubernetes of the analyzer, or of the
+//     kubelet, supports the cgroup version of the host. cgroup v2 requires Kubernetes 1.25, and the kubelet refuses
+//     to start on cgroup v1 from Kubernetes 1.35 unless its config sets failCgroupV1 to false.
+//
+// Operators are == and !=.
+func (a *AnalyzeHostKubeletConfig) CheckCondition(when string, data []byte) (bool, error) {
+	info := collect.KubeletConfigInfo{}
+	if err := json.Unmarshal(data, &info); err != nil {
+		return false, errors.Wrap(err, "failed to unmarshal kubelet config")
+	}
+
+	parts := strings.Fields(when)
+	if len(parts) != 3 {
+		return false, fmt.Errorf("expected 3 parts in when %q, got %d", when, len(parts))
+	}
+	setting, operator, value := parts[0], parts[1], parts[2]
+
+	runtimeCgroupDriver := ""
+	if info.ContainerRuntime != nil {
+		runtimeCgroupDriver = info.ContainerRuntime.CgroupDriver
+	}
+
+	var actual bool
+	switch setting {
+	case "cgroupVersion":
+		return compareEquality(info.CgroupVersion == value, operator)
+	case "cgroupDriver":
+		return compareEquality(info.CgroupDriver == value, operator)
+	case "runtimeCgroupDriver":
+		return compareEquality(runtimeCgroupDriver == value, operator)
+	case "cgroupDriverMismatch":
+		actual = runtimeCgroupDriver != "" && runtimeCgroupDriver != info.CgroupDriver
+	case "cgroupVersionSupported":
+		supported, err := a.cgroupVersionSupported(info)
+		if err != nil {
+			return false, err
+		}
+		actual = supported
+	default:
+		return false, fmt.Errorf("unsupported setting %q", setting)
+	}
+
+	expected, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to parse %q", value)
+	}
+	return compareEquality(actual == expected, operator)
+}
+
+// cgroupVersionSupported returns whether the Kubernetes version of the analyzer, or the version
+// of the kubelet when it is not set, supports the cgroup version of the host
+func (a *AnalyzeHostKubeletConfig) cgroupVersionSupported(info collect.KubeletConfigInfo) (bool, error) {
+	version := a.hostAnalyzer.KubernetesVersion
+	if version == "" {
+		version = info.KubeletVersion
+	}
+	if version == "" {
+		return false, errors.New("the kubelet version was not collected, set kubernetesVersion on the analyzer")
+	}
+	parsed, err := semver.ParseTolerant(version)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to parse kubernetes version %q", version)
+	}
+	// compare the release only, pre-releases of a version have its support
+	parsed.Pre, parsed.Build = nil, nil
+
+	switch info.CgroupVersion {
+	case "v2":
+		return parsed.GTE(cgroupV2MinVersion), nil
+	case "v1":
+		failCgroupV1 := info.FailCgroupV1 == nil || *info.FailCgroupV1
+		return parsed.LT(cgroupV1FailVersion) || !failCgroupV1, nil
+	}
+	return false, errors.New("the cgroup version was not collected")
+}
@@ -0,0 +1,99 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"testing"
+
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/collect"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/utils/ptr"
+)
+
+func TestAnalyzeHostKubeletConfig_CheckCondition(t *testing.T) {
+	mismatch := collect.KubeletConfigInfo{
+		KubeletVersion: "v1.30.2",
+		CgroupVersion:  "v2",
+		CgroupDriver:   "cgroupfs",
+		ContainerRuntime: &collect.ContainerRuntimeCgroups{
+			Name:         "containerd",
+			CgroupDriver: "systemd",
+		},
+	}
+	v1 := collect.KubeletConfigInfo{
+		KubeletVersion: "v1.35.0-rc.1",
+		CgroupVersion:  "v1",
+		CgroupDriver:   "systemd",
+	}
+
+	tests := []struct {
+		name              string
+		info              collect.KubeletConfigInfo
+		kubernetesVersion string
+		when              string
+		want              bool
+		wantErr           string
+	}{
+		{name: "cgroup version", info: mismatch, when: "cgroupVersion == v2", want: true},
+		{name: "cgroup driver", info: mismatch, when: "cgroupDriver != systemd", want: true},
+		{name: "runtime cgroup driver", info: mismatch, when: "runtimeCgroupDriver == systemd", want: true},
+		{name: "driver mismatch", info: mismatch, when: "cgroupDriverMismatch == true", want: true},
+		{name: "unknown runtime driver", info: v1, when: "cgroupDriverMismatch == true", want: false},
+		{name: "v2 supported", info: mismatch, when: "cgroupVersionSupported == true", want: true},
+		{name: "v2 before 1.25", info: mismatch, kubernetesVersion: "1.24.17", when: "cgroupVersionSupported == false", want: true},
+		{name: "v1 from 1.35", info: v1, when: "cgroupVersionSupported == false", want: true},
+		{name: "v1 before 1.35", info: v1, kubernetesVersion: "v1.34.3", when: "cgroupVersionSupported == true", want: true},
+		{
+			name: "v1 from 1.35 without failCgroupV1",
+			info: collect.KubeletConfigInfo{KubeletVersion: "v1.35.1", CgroupVersion: "v1", FailCgroupV1: ptr.To(false)},
+			when: "cgroupVersionSupported == true",
+			want: true,
+		},
+		{name: "no version", info: collect.KubeletConfigInfo{CgroupVersion: "v2"}, when: "cgroupVersionSupported == true", wantErr: "the kubelet version was not collected"},
+		{name: "invalid boolean", info: mismatch, when: "cgroupDriverMismatch == yes", wantErr: `failed to parse "yes"`},
+		{name: "unsupported setting", info: mismatch, when: "cgroupRoot == /", wantErr: `unsupported setting "cgroupRoot"`},
+		{name: "invalid when", info: mismatch, when: "cgroupDriverMismatch", wantErr: `expected 3 parts in when "cgroupDriverMismatch", got 1`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(tt.info)
+			require.NoError(t, err)
+
+			a := AnalyzeHostKubeletConfig{&troubleshootv1beta2.KubeletConfigAnalyze{KubernetesVersion: tt.kubernetesVersion}}
+			got, err := a.CheckCondition(tt.when, data)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestAnalyzeHostKubeletConfig(t *testing.T) {
+	data, err := json.Marshal(collect.KubeletConfigInfo{
+		KubeletVersion:   "v1.30.2",
+		CgroupVersion:    "v2",
+		CgroupDriver:     "systemd",
+		ContainerRuntime: &collect.ContainerRuntimeCgroups{Name: "crio", CgroupDriver: "systemd"},
+	})
+	require.NoError(t, err)
+
+	a := AnalyzeHostKubeletConfig{&troubleshootv1beta2.KubeletConfigAnalyze{
+		Outcomes: []*troubleshootv1beta2.Outcome{
+			{Fail: &troubleshootv1beta2.SingleOutcome{When: "cgroupDriverMismatch == true", Message: "cgroup drivers differ"}},
+			{Pass: &troubleshootv1beta2.SingleOutcome{Message: "cgroup drivers match"}},
+		},
+	}}
+	results, err := a.Analyze(func(path string) ([]byte, error) {
+		require.Equal(t, collect.HostKubeletConfigPath, path)
+		return data, nil
+	}, nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, []*AnalyzeResult{{Title: "Kubelet Config", IsPass: true, Message: "cgroup drivers match"}}, results)
+}
@@ -0,0 +1,183 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/collect"
+)
+
+// Ensure `AnalyzeHostKubernetesDistribution` implements `HostAnalyzer` interface at compile time.
+var _ HostAnalyzer = (*AnalyzeHostKubernetesDistribution)(nil)
+
+type AnalyzeHostKubernetesDistribution struct {
+	hostAnalyzer *troubleshootv1beta2.KubernetesDistributionAnalyze
+}
+
+func (a *AnalyzeHostKubernetesDistribution) Title() string {
+	return hostAnalyzerTitleOrDefault(a.hostAnalyzer.AnalyzeMeta, "Kubernetes Distribution")
+}
+
+func (a *AnalyzeHostKubernetesDistribution) IsExcluded() (bool, error) {
+	return isExcluded(a.hostAnalyzer.Exclude)
+}
+
+func (a *AnalyzeHostKubernetesDistribution) Analyze(
+	getCollectedFileContents func(string) ([]byte, error), findFiles getChildCollectedFileContents,
+) ([]*AnalyzeResult, error) {
+	result := AnalyzeResult{Title: a.Title()}
+
+	collectedContents, err := retrieveCollectedContents(
+		getCollectedFileContents,
+		collect.HostKubernetesDistributionPath,
+		collect.NodeInfoBaseDir,
+		collect.HostKubernetesDistributionFileName,
+	)
+	if err != nil {
+		return []*AnalyzeResult{&result}, err
+	}
+
+	results, err := analyzeHostCollectorResults(collectedContents, a.hostAnalyzer.Outcomes, a.CheckCondition, a.Title())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to analyze kubernetes distribution")
+	}
+
+	return results, nil
+}
+
+// CheckCondition evaluates a when clause against the collected distribution files. Supported
+// conditions are:
+//
+//   - "distribution == <k3s|rke2|embedded-cluster|none>" or "distribution != <...>"
+//   - "config.<key> <operator> <value>", where key is a setting of the config file, e.g.
+//     "config.secrets-encryption == true", or a dotted path for nested settings, e.g.
+//     "config.spec.network.provider == calico". A list setting is equal to a value it contains.
+//     Numbers can be compared with <, <=, > and >=.
+//   - "serverToken == <true|false>" and "containerdConfigTemplate == <true|false>"
+//   - "etcdSnapshots <operator> <n>", e.g. "etcdSnapshots == 0"
+//   - "etcdSnapshotAge <operator> <duration>", compared against the age of the most recent
+//     snapshot, e.g. "etcdSnapshotAge > 24h". It is false when there are no snapshots.
+func (a *AnalyzeHostKubernetesDistribution) CheckCondition(when string, data []byte) (bool, error) {
+	info := collect.KubernetesDistributionInfo{}
+	if err := json.Unmarshal(data, &info); err != nil {
+		return false, errors.Wrap(err, "failed to unmarshal kubernetes distribution info")
+	}
+
+	parts := strings.Fields(when)
+	if len(parts) < 3 {
+		return false, fmt.Errorf("expected at least 3 parts in when %q, got %d", when, len(parts))
+	}
+	key, opString, expected := parts[0], parts[1], strings.Join(parts[2:], " ")
+
+	switch {
+	case key == "distribution":
+		distribution := info.Distribution
+		if distribution == "" {
+			distribution = "none"
+		}
+		return compareEquality(distribution == expected, opString)
+	case key == "serverToken" || key == "containerdConfigTemplate":
+		want, err := strconv.ParseBool(expected)
+		if err != nil {
+			return false, errors.Wrapf(err, "failed to parse %q", expected)
+		}
+		actual := info.ServerToken
+		if key == "containerdConfigTemplate" {
+			actual = info.ContainerdConfigTemplate
+		}
+		return compareEquality(actual == want, opString)
+	case key == "etcdSnapshots":
+		return compareActualToWhen(opString+" "+expected, len(info.EtcdSnapshots))
+	case key == "etcdSnapshotAge":
+		if len(info.EtcdSnapshots) == 0 {
+			return false, nil
+		}
+		latest := info.EtcdSnapshots[0].ModTime
+		for _, snapshot := range info.EtcdSnapshots {
+			if snapshot.ModTime.After(latest) {
+				latest = snapshot.ModTime
+			}
+		}
+		return compareDuration(time.Since(latest), opString, expected)
+	case strings.HasPrefix(key, "config."):
+		value, found := lookupDistributionConfig(info.Config, strings.TrimPrefix(key, "config."))
+		return compareDistributionConfig(value, found, opString, expected)
+	}
+
+	return false, fmt.Errorf("unsupported when %q", when)
+}
+
+// lookupDistributionConfig returns the setting of the config with the key, trying the whole key
+// first since the settings of k3s and RKE2 are flat, then the dotted path through nested settings
+func lookupDistributionConfig(config map[string]interface{}, key string) (interface{}, bool) {
+	if value, ok := config[key]; ok {
+		return value, true
+	}
+
+	var current interface{} = config
+	for _, part := range strings.Split(key, ".") {
+		m, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		current, ok = m[part]
+		if !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+func compareDistributionConfig(value interface{}, found bool, opString string, expected string) (bool, error) {
+	operator, err := ParseComparisonOperator(opString)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to parse comparison operator %q", opString)
+	}
+
+	switch operator {
+	case Equal, NotEqual:
+		equal := false
+		if found {
+			if list, ok := value.([]interface{}); ok {
+				for _, item := range list {
+					if fmt.Sprint(item) == expected {
+						equal = true
+						break
+					}
+				}
+			} else {
+				equal = fmt.Sprint(value) == expected
+			}
+		}
+		return compareEquality(equal, opString)
+	}
+
+	if !found {
+		return false, nil
+	}
+	actual, err := strconv.ParseFloat(fmt.Sprint(value), 64)
+	if err != nil {
+		return false, errors.Errorf("setting with value %v is not a number", value)
+	}
+	threshold, err := strconv.ParseFloat(expected, 64)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to parse %q", expected)
+	}
+
+	switch operator {
+	case LessThan:
+		return actual < threshold, nil
+	case LessThanOrEqual:
+		return actual <= threshold, nil
+	case GreaterThan:
+		return actual > threshold, nil
+	case GreaterThanOrEqual:
+		return actual >= threshold, nil
+	}
+	return false, fmt.Errorf("unsupported operator %q", opString)
+}
@@ -0,0 +1,82 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/replicatedhq/troubleshoot/pkg/collect"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyzeHostKubernetesDistribution_CheckCondition(t *testing.T) {
+	k3s := collect.KubernetesDistributionInfo{
+		Distribution: collect.KubernetesDistributionK3s,
+		Config: map[string]interface{}{
+			"cluster-init":            true,
+			"etcd-snapshot-retention": 10,
+			"tls-san":                 []interface{}{"k3s.example.com", "10.0.0.10"},
+		},
+		ServerToken: true,
+		EtcdSnapshots: []collect.EtcdSnapshotFile{
+			{Name: "etcd-snapshot-2", ModTime: time.Now().Add(-2 * time.Hour)},
+			{Name: "etcd-snapshot-1", ModTime: time.Now().Add(-14 * time.Hour)},
+		},
+	}
+	embeddedCluster := collect.KubernetesDistributionInfo{
+		Distribution: collect.KubernetesDistributionEmbeddedCluster,
+		Config: map[string]interface{}{
+			"spec": map[string]interface{}{
+				"network": map[string]interface{}{"provider": "calico"},
+			},
+		},
+		EtcdSnapshots: []collect.EtcdSnapshotFile{},
+	}
+	none := collect.KubernetesDistributionInfo{EtcdSnapshots: []collect.EtcdSnapshotFile{}}
+
+	tests := []struct {
+		name    string
+		info    collect.KubernetesDistributionInfo
+		when    string
+		want    bool
+		wantErr bool
+	}{
+		{name: "distribution", info: k3s, when: "distribution == k3s", want: true},
+		{name: "other distribution", info: k3s, when: "distribution != rke2", want: true},
+		{name: "no distribution", info: none, when: "distribution == none", want: true},
+		{name: "bool setting", info: k3s, when: "config.cluster-init == true", want: true},
+		{name: "number setting", info: k3s, when: "config.etcd-snapshot-retention < 5", want: false},
+		{name: "list setting", info: k3s, when: "config.tls-san == 10.0.0.10", want: true},
+		{name: "missing setting", info: k3s, when: "config.secrets-encryption != true", want: true},
+		{name: "nested setting", info: embeddedCluster, when: "config.spec.network.provider == calico", want: true},
+		{name: "server token", info: k3s, when: "serverToken == true", want: true},
+		{name: "containerd template", info: k3s, when: "containerdConfigTemplate == false", want: true},
+		{name: "snapshots", info: k3s, when: "etcdSnapshots >= 2", want: true},
+		{name: "no snapshots", info: embeddedCluster, when: "etcdSnapshots == 0", want: true},
+		{name: "recent snapshot", info: k3s, when: "etcdSnapshotAge > 12h", want: false},
+		{name: "stale snapshot", info: k3s, when: "etcdSnapshotAge > 1h", want: true},
+		{name: "snapshot age without snapshots", info: none, when: "etcdSnapshotAge > 1h", want: false},
+		{name: "non-numeric setting", info: k3s, when: "config.tls-san > 1", wantErr: true},
+		{name: "invalid operator", info: k3s, when: "distribution > k3s", wantErr: true},
+		{name: "unsupported condition", info: k3s, when: "nodes > 3", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := require.New(t)
+
+			data, err := json.Marshal(tt.info)
+			req.NoError(err)
+
+			a := AnalyzeHostKubernetesDistribution{}
+			got, err := a.CheckCondition(tt.when, data)
+			if tt.wantErr {
+				req.Error(err)
+				return
+			}
+			req.NoError(err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
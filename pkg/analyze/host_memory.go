@@ -39,7 +39,7 @@ func (a *AnalyzeHostMemory) Analyze(
 		return []*AnalyzeResult{&result}, err
 	}
 
-	results, err := analyzeHostCollectorResults(collectedContents, a.hostAnalyzer.Outcomes, a.CheckCondition, a.Title())
+	results, err := analyzeMeasuredHostCollectorResults(collectedContents, a.hostAnalyzer.Outcomes, a.CheckCondition, a.measure, a.Title())
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to analyze OS version")
 	}
@@ -90,3 +90,28 @@ func (a *AnalyzeHostMemory) CheckCondition(when string, data []byte) (bool, erro
 	}
 
 }
+
+// measure reports the total memory in bytes, and the quantity in the condition as the threshold.
+func (a *AnalyzeHostMemory) measure(condition string, data []byte) (*Measurement, error) {
+	var memInfo collect.MemoryInfo
+	if err := json.Unmarshal(data, &memInfo); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal data into MemoryInfo")
+	}
+
+	measurement := &Measurement{
+		Observed: float64(memInfo.Total),
+		Unit:     "bytes",
+	}
+
+	parts := strings.Split(condition, " ")
+	if len(parts) == 2 {
+		quantity, err := resource.ParseQuantity(parts[1])
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not parse quantity %q", parts[1])
+		}
+		threshold := quantity.AsApproximateFloat64()
+		measurement.Threshold = &threshold
+	}
+
+	return measurement, nil
+}
@@ -128,9 +128,11 @@ func TestAnalyzeHostMemory(t *testing.T) {
 			},
 			expectedResults: []*AnalyzeResult{
 				{
-					Title:   "Amount of Memory",
-					IsPass:  true,
-					Message: "System has at least 4Gi of memory",
+					Title:       "Amount of Memory",
+					IsPass:      true,
+					Message:     "System has at least 4Gi of memory",
+					Condition:   ">= 4Gi",
+					Measurement: &Measurement{Observed: 8 * 1024 * 1024 * 1024, Threshold: float64Ptr(4 * 1024 * 1024 * 1024), Unit: "bytes"},
 				},
 			},
 			expectedError: "",
@@ -163,9 +165,11 @@ func TestAnalyzeHostMemory(t *testing.T) {
 			},
 			expectedResults: []*AnalyzeResult{
 				{
-					Title:   "Amount of Memory - Node node1",
-					IsFail:  true,
-					Message: "System requires at least 16Gi of memory",
+					Title:       "Amount of Memory - Node node1",
+					IsFail:      true,
+					Message:     "System requires at least 16Gi of memory",
+					Condition:   "< 16Gi",
+					Measurement: &Measurement{Observed: 8 * 1024 * 1024 * 1024, Threshold: float64Ptr(16 * 1024 * 1024 * 1024), Unit: "bytes"},
 				},
 			},
 			expectedError: "",
@@ -198,9 +202,11 @@ func TestAnalyzeHostMemory(t *testing.T) {
 			},
 			expectedResults: []*AnalyzeResult{
 				{
-					Title:   "Amount of Memory - Node node1",
-					IsWarn:  true,
-					Message: "System performs best with more than 8Gi of memory",
+					Title:       "Amount of Memory - Node node1",
+					IsWarn:      true,
+					Message:     "System performs best with more than 8Gi of memory",
+					Condition:   "<= 8Gi",
+					Measurement: &Measurement{Observed: 8 * 1024 * 1024 * 1024, Threshold: float64Ptr(8 * 1024 * 1024 * 1024), Unit: "bytes"},
 				},
 			},
 			expectedError: "",
@@ -235,9 +241,10 @@ func TestAnalyzeHostMemory(t *testing.T) {
 			},
 			expectedResults: []*AnalyzeResult{
 				{
-					Title:   "Amount of Memory",
-					IsPass:  true,
-					Message: "Memory is sufficient",
+					Title:       "Amount of Memory",
+					IsPass:      true,
+					Message:     "Memory is sufficient",
+					Measurement: &Measurement{Observed: 16 * 1024 * 1024 * 1024, Unit: "bytes"},
 				},
 			},
 			expectedError: "",
@@ -272,9 +279,10 @@ func TestAnalyzeHostMemory(t *testing.T) {
 			},
 			expectedResults: []*AnalyzeResult{
 				{
-					Title:   "Amount of Memory",
-					IsPass:  true,
-					Message: "it passed",
+					Title:       "Amount of Memory",
+					IsPass:      true,
+					Message:     "it passed",
+					Measurement: &Measurement{Observed: 16 * 1024 * 1024 * 1024, Unit: "bytes"},
 				},
 			},
 			expectedError: "",
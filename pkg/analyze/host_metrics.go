@@ -0,0 +1,272 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/collect"
+)
+
+// Ensure `AnalyzeHostMetrics` implements `HostAnalyzer` interface at compile time.
+var _ HostAnalyzer = (*AnalyzeHostMetrics)(nil)
+
+type AnalyzeHostMetrics struct {
+	hostAnalyzer *troubleshootv1beta2.MetricsAnalyze
+}
+
+func (a *AnalyzeHostMetrics) Title() string {
+	return hostAnalyzerTitleOrDefault(a.hostAnalyzer.AnalyzeMeta, "Metrics")
+}
+
+func (a *AnalyzeHostMetrics) IsExcluded() (bool, error) {
+	return isExcluded(a.hostAnalyzer.Exclude)
+}
+
+func (a *AnalyzeHostMetrics) Analyze(
+	getCollectedFileContents func(string) ([]byte, error), findFiles getChildCollectedFileContents,
+) ([]*AnalyzeResult, error) {
+	result := AnalyzeResult{Title: a.Title()}
+
+	collectedContents, err := retrieveCollectedContents(
+		getCollectedFileContents,
+		collect.HostMetricsPath,
+		collect.NodeInfoBaseDir,
+		collect.HostMetricsFileName,
+	)
+	if err != nil {
+		return []*AnalyzeResult{&result}, err
+	}
+
+	results, err := analyzeMeasuredHostCollectorResults(collectedContents, a.hostAnalyzer.Outcomes, a.CheckCondition, a.measure, a.Title())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to analyze metrics")
+	}
+
+	return results, nil
+}
+
+// CheckCondition evaluates a when clause against the collected series. Conditions are
+// "<aggregate>(<series>) <operator> <n>", e.g. "p95(cpu.usage) > 90", where the aggregate is one
+// of min, max, avg, p95 or last, and the series one of:
+//
+//   - cpu.usage, cpu.iowait and cpu.steal, percentages of the CPU time
+//   - memory.usage and memory.swapUsage, percentages, and memory.availableBytes
+//   - disk.<name>.readBytesPerSecond, disk.<name>.writeBytesPerSecond and
+//     disk.<name>.utilization, a percentage
+//   - network.<name>.receiveBytesPerSecond, network.<name>.transmitBytesPerSecond,
+//     network.<name>.errorsPerSecond and network.<name>.dropsPerSecond
+//
+// A name of * compares the highest aggregate of the disks or interfaces, e.g.
+// "max(disk.*.utilization) > 90" is true when any disk was busier than 90%.
+func (a *AnalyzeHostMetrics) CheckCondition(when string, data []byte) (bool, error) {
+	info := collect.MetricsInfo{}
+	if err := json.Unmarshal(data, &info); err != nil {
+		return false, errors.Wrap(err, "failed to unmarshal metrics info")
+	}
+
+	parts := strings.Fields(when)
+	if len(parts) != 3 {
+		return false, fmt.Errorf("expected 3 parts in when %q, got %d", when, len(parts))
+	}
+
+	observed, _, err := hostMetric(info, parts[0])
+	if err != nil {
+		return false, err
+	}
+	threshold, err := strconv.ParseFloat(parts[2], 64)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to parse %q", parts[2])
+	}
+	return compareFloat(observed, parts[1], threshold)
+}
+
+// measure reports the aggregate a condition compares, and the value in the condition as the
+// threshold.
+func (a *AnalyzeHostMetrics) measure(condition string, data []byte) (*Measurement, error) {
+	info := collect.MetricsInfo{}
+	if err := json.Unmarshal(data, &info); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal metrics info")
+	}
+
+	parts := strings.Fields(condition)
+	if len(parts) != 3 {
+		return nil, nil
+	}
+
+	observed, unit, err := hostMetric(info, parts[0])
+	if err != nil {
+		return nil, err
+	}
+	threshold, err := strconv.ParseFloat(parts[2], 64)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse %q", parts[2])
+	}
+	return &Measurement{
+		Observed:  observed,
+		Threshold: &threshold,
+		Unit:      unit,
+	}, nil
+}
+
+// hostMetric returns the aggregate of a series, e.g. p95(cpu.usage), and its unit
+func hostMetric(info collect.MetricsInfo, metric string) (float64, string, error) {
+	open := strings.Index(metric, "(")
+	if open <= 0 || !strings.HasSuffix(metric, ")") {
+		return 0, "", fmt.Errorf("expected <aggregate>(<series>) in %q", metric)
+	}
+	aggregate, name := metric[:open], metric[open+1:len(metric)-1]
+	switch aggregate {
+	case "min", "max", "avg", "p95", "last":
+	default:
+		return 0, "", fmt.Errorf("unsupported aggregate %q, must be one of min, max, avg, p95 or last", aggregate)
+	}
+
+	series, unit, err := metricSeries(info, name)
+	if err != nil {
+		return 0, "", err
+	}
+	if len(info.Timestamps) == 0 {
+		return 0, "", errors.New("no metrics samples were collected")
+	}
+
+	// the highest aggregate of the devices of a wildcard series
+	observed := math.Inf(-1)
+	for _, s := range series {
+		if v := aggregateSeries(aggregate, s); v > observed {
+			observed = v
+		}
+	}
+	return observed, unit, nil
+}
+
+// metricSeries returns the series of a name, one per device for the disks and interfaces of a
+// wildcard name, and their unit
+func metricSeries(info collect.MetricsInfo, name string) ([][]float64, string, error) {
+	switch name {
+	case "cpu.usage":
+		return [][]float64{info.CPU.Usage}, "%", nil
+	case "cpu.iowait":
+		return [][]float64{info.CPU.IOWait}, "%", nil
+	case "cpu.steal":
+		return [][]float64{info.CPU.Steal}, "%", nil
+	case "memory.usage":
+		return [][]float64{info.Memory.Usage}, "%", nil
+	case "memory.swapUsage":
+		return [][]float64{info.Memory.SwapUsage}, "%", nil
+	case "memory.availableBytes":
+		available := make([]float64, len(info.Memory.AvailableBytes))
+		for i, v := range info.Memory.AvailableBytes {
+			available[i] = float64(v)
+		}
+		return [][]float64{available}, "bytes", nil
+	}
+
+	first, last := strings.Index(name, "."), strings.LastIndex(name, ".")
+	if first < 0 || first == last {
+		return nil, "", fmt.Errorf("unsupported series %q", name)
+	}
+	kind, device, field := name[:first], name[first+1:last], name[last+1:]
+
+	var sampled []string
+	var get func(device string) ([]float64, string, bool)
+	switch kind {
+	case "disk":
+		for n := range info.Disks {
+			sampled = append(sampled, n)
+		}
+		get = func(device string) ([]float64, string, bool) { return diskSeries(info.Disks[device], field) }
+	case "network":
+		for n := range info.Network {
+			sampled = append(sampled, n)
+		}
+		get = func(device string) ([]float64, string, bool) { return networkSeries(info.Network[device], field) }
+	default:
+		return nil, "", fmt.Errorf("unsupported series %q", name)
+	}
+	sort.Strings(sampled)
+
+	_, unit, ok := get("")
+	if !ok {
+		return nil, "", fmt.Errorf("unsupported series %q", name)
+	}
+
+	names := []string{device}
+	if device == "*" {
+		if len(sampled) == 0 {
+			return nil, "", fmt.Errorf("no %s was sampled", kind)
+		}
+		names = sampled
+	}
+
+	series := [][]float64{}
+	for _, n := range names {
+		i := sort.SearchStrings(sampled, n)
+		if i == len(sampled) || sampled[i] != n {
+			return nil, "", fmt.Errorf("%s %q was not sampled", kind, n)
+		}
+		s, _, _ := get(n)
+		series = append(series, s)
+	}
+	return series, unit, nil
+}
+
+func diskSeries(disk collect.DiskMetrics, field string) ([]float64, string, bool) {
+	switch field {
+	case "readBytesPerSecond":
+		return disk.ReadBytesPerSecond, "bytes/s", true
+	case "writeBytesPerSecond":
+		return disk.WriteBytesPerSecond, "bytes/s", true
+	case "utilization":
+		return disk.Utilization, "%", true
+	}
+	return nil, "", false
+}
+
+func networkSeries(network collect.NetworkMetrics, field string) ([]float64, string, bool) {
+	switch field {
+	case "receiveBytesPerSecond":
+		return network.ReceiveBytesPerSecond, "bytes/s", true
+	case "transmitBytesPerSecond":
+		return network.TransmitBytesPerSecond, "bytes/s", true
+	case "errorsPerSecond":
+		return network.ErrorsPerSecond, "/s", true
+	case "dropsPerSecond":
+		return network.DropsPerSecond, "/s", true
+	}
+	return nil, "", false
+}
+
+// aggregateSeries returns the aggregate of the values of a series, 0 for an empty series. The
+// 95th percentile is the nearest rank.
+func aggregateSeries(aggregate string, series []float64) float64 {
+	if len(series) == 0 {
+		return 0
+	}
+
+	switch aggregate {
+	case "last":
+		return series[len(series)-1]
+	case "avg":
+		sum := 0.0
+		for _, v := range series {
+			sum += v
+		}
+		return sum / float64(len(series))
+	}
+
+	sorted := append([]float64{}, series...)
+	sort.Float64s(sorted)
+	switch aggregate {
+	case "min":
+		return sorted[0]
+	case "p95":
+		return sorted[int(math.Ceil(0.95*float64(len(sorted))))-1]
+	}
+	return sorted[len(sorted)-1]
+}
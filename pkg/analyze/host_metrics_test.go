@@ -0,0 +1,112 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"testing"
+
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/collect"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var metricsInfo = collect.MetricsInfo{
+	IntervalSeconds: 5,
+	DurationSeconds: 20,
+	Timestamps:      []int64{1700000005, 1700000010, 1700000015, 1700000020},
+	CPU: collect.CPUMetrics{
+		Usage:  []float64{40, 95, 50, 45},
+		IOWait: []float64{1, 2, 1, 0},
+		Steal:  []float64{0, 0, 0, 0},
+	},
+	Memory: collect.MemoryMetrics{
+		Usage:          []float64{60, 80, 70, 80},
+		AvailableBytes: []uint64{2000000000, 1000000000, 1500000000, 1000000000},
+		SwapUsage:      []float64{0, 0, 0, 0},
+	},
+	Disks: map[string]collect.DiskMetrics{
+		"sda":     {ReadBytesPerSecond: []float64{0, 0, 0, 0}, WriteBytesPerSecond: []float64{1000, 2000, 3000, 4000}, Utilization: []float64{10, 20, 30, 40}},
+		"nvme0n1": {ReadBytesPerSecond: []float64{0, 0, 0, 0}, WriteBytesPerSecond: []float64{0, 0, 0, 0}, Utilization: []float64{90, 10, 5, 5}},
+	},
+	Network: map[string]collect.NetworkMetrics{
+		"eth0": {ReceiveBytesPerSecond: []float64{100, 200, 300, 400}, TransmitBytesPerSecond: []float64{0, 0, 0, 0}, ErrorsPerSecond: []float64{0, 0, 2, 0}, DropsPerSecond: []float64{0, 0, 0, 0}},
+	},
+}
+
+func TestAnalyzeHostMetrics_CheckCondition(t *testing.T) {
+	tests := []struct {
+		when    string
+		want    bool
+		wantErr string
+	}{
+		{when: "max(cpu.usage) > 90", want: true},
+		{when: "p95(cpu.usage) >= 95", want: true},
+		{when: "avg(cpu.usage) == 57.5", want: true},
+		{when: "min(cpu.usage) < 40", want: false},
+		{when: "last(memory.usage) >= 80", want: true},
+		{when: "min(memory.availableBytes) < 1073741824", want: true},
+		{when: "max(disk.*.utilization) > 80", want: true},
+		{when: "avg(disk.*.utilization) > 27", want: true},
+		{when: "last(disk.sda.utilization) == 40", want: true},
+		{when: "max(disk.sda.writeBytesPerSecond) > 5000", want: false},
+		{when: "max(network.eth0.errorsPerSecond) > 0", want: true},
+		{when: "max(network.*.receiveBytesPerSecond) == 400", want: true},
+		{when: "max(disk.sdb.utilization) > 80", wantErr: `disk "sdb" was not sampled`},
+		{when: "max(disk.sda.queueDepth) > 1", wantErr: `unsupported series "disk.sda.queueDepth"`},
+		{when: "max(cpu.user) > 1", wantErr: `unsupported series "cpu.user"`},
+		{when: "median(cpu.usage) > 1", wantErr: `unsupported aggregate "median"`},
+		{when: "cpu.usage > 90", wantErr: `expected <aggregate>(<series>) in "cpu.usage"`},
+		{when: "max(cpu.usage) > high", wantErr: `failed to parse "high"`},
+		{when: "max(cpu.usage)", wantErr: `expected 3 parts in when "max(cpu.usage)", got 1`},
+	}
+
+	data, err := json.Marshal(metricsInfo)
+	require.NoError(t, err)
+
+	a := AnalyzeHostMetrics{}
+	for _, tt := range tests {
+		t.Run(tt.when, func(t *testing.T) {
+			got, err := a.CheckCondition(tt.when, data)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	_, err = a.CheckCondition("max(cpu.usage) > 90", []byte(`{"timestamps": []}`))
+	assert.EqualError(t, err, "no metrics samples were collected")
+
+	_, err = a.CheckCondition("max(network.*.dropsPerSecond) > 0", []byte(`{"timestamps": [1700000005]}`))
+	assert.EqualError(t, err, "no network was sampled")
+}
+
+func TestAnalyzeHostMetrics(t *testing.T) {
+	data, err := json.Marshal(metricsInfo)
+	require.NoError(t, err)
+
+	a := AnalyzeHostMetrics{&troubleshootv1beta2.MetricsAnalyze{
+		Outcomes: []*troubleshootv1beta2.Outcome{
+			{Warn: &troubleshootv1beta2.SingleOutcome{When: "max(disk.*.utilization) > 80", Message: "a disk is saturated"}},
+			{Pass: &troubleshootv1beta2.SingleOutcome{Message: "the disks are not saturated"}},
+		},
+	}}
+	results, err := a.Analyze(func(path string) ([]byte, error) {
+		require.Equal(t, collect.HostMetricsPath, path)
+		return data, nil
+	}, nil)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+
+	threshold := float64(80)
+	assert.Equal(t, &AnalyzeResult{
+		Title:       "Metrics",
+		IsWarn:      true,
+		Message:     "a disk is saturated",
+		Condition:   "max(disk.*.utilization) > 80",
+		Measurement: &Measurement{Observed: 90, Threshold: &threshold, Unit: "%"},
+	}, results[0])
+}
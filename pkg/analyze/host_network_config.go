@@ -0,0 +1,170 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/collect"
+)
+
+// Ensure `AnalyzeHostNetworkConfig` implements `HostAnalyzer` interface at compile time.
+var _ HostAnalyzer = (*AnalyzeHostNetworkConfig)(nil)
+
+// encapsulationOverhead is the number of bytes each kind of virtual interface adds to a packet.
+// Overlay interfaces must leave room for this overhead below the MTU of the uplink.
+var encapsulationOverhead = map[string]int{
+	"bridge":    0,
+	"vxlan":     50,
+	"geneve":    50,
+	"ipip":      20,
+	"wireguard": 80,
+}
+
+type AnalyzeHostNetworkConfig struct {
+	hostAnalyzer *troubleshootv1beta2.NetworkConfigAnalyze
+}
+
+func (a *AnalyzeHostNetworkConfig) Title() string {
+	return hostAnalyzerTitleOrDefault(a.hostAnalyzer.AnalyzeMeta, "Network Config")
+}
+
+func (a *AnalyzeHostNetworkConfig) IsExcluded() (bool, error) {
+	return isExcluded(a.hostAnalyzer.Exclude)
+}
+
+func (a *AnalyzeHostNetworkConfig) Analyze(
+	getCollectedFileContents func(string) ([]byte, error), findFiles getChildCollectedFileContents,
+) ([]*AnalyzeResult, error) {
+	result := AnalyzeResult{Title: a.Title()}
+
+	collectedContents, err := retrieveCollectedContents(
+		getCollectedFileContents,
+		collect.HostNetworkConfigPath,
+		collect.NodeInfoBaseDir,
+		collect.HostNetworkConfigFileName,
+	)
+	if err != nil {
+		return []*AnalyzeResult{&result}, err
+	}
+
+	results, err := analyzeHostCollectorResults(collectedContents, a.hostAnalyzer.Outcomes, a.CheckCondition, a.Title())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to analyze network config")
+	}
+
+	return results, nil
+}
+
+// CheckCondition evaluates a when clause against the collected network config. Supported conditions are:
+//
+//   - "no default route": there is no IPv4 or IPv6 default route
+//   - "mtu mismatch": a bridge or overlay interface has an MTU that does not fit within the MTU
+//     of the interface used by the default route, once encapsulation overhead is accounted for
+//   - "interface <name> mtu <operator> <value>", e.g. "interface eth0 mtu < 1500"
+func (a *AnalyzeHostNetworkConfig) CheckCondition(when string, data []byte) (bool, error) {
+	info := collect.NetworkConfigInfo{}
+	if err := json.Unmarshal(data, &info); err != nil {
+		return false, errors.Wrap(err, "failed to unmarshal network config")
+	}
+
+	parts := strings.Fields(when)
+	condition := strings.Join(parts, " ")
+	switch {
+	case condition == "no default route":
+		return findDefaultRoute(info.Routes) == nil, nil
+	case condition == "mtu mismatch":
+		return len(findMTUMismatches(info)) > 0, nil
+	case len(parts) == 5 && parts[0] == "interface" && parts[2] == "mtu":
+		return compareInterfaceMTU(info.Interfaces, parts[1], parts[3], parts[4])
+	}
+
+	return false, fmt.Errorf("unsupported when %q", when)
+}
+
+// findDefaultRoute returns the default route, preferring IPv4
+func findDefaultRoute(routes []collect.NetworkRoute) *collect.NetworkRoute {
+	var found *collect.NetworkRoute
+	for i := range routes {
+		if routes[i].Destination != "default" {
+			continue
+		}
+		if routes[i].Family == "inet" {
+			return &routes[i]
+		}
+		if found == nil {
+			found = &routes[i]
+		}
+	}
+	return found
+}
+
+// findMTUMismatches returns the names of the interfaces whose MTU is too large for the uplink
+func findMTUMismatches(info collect.NetworkConfigInfo) []string {
+	route := findDefaultRoute(info.Routes)
+	if route == nil {
+		return nil
+	}
+
+	uplinkMTU := 0
+	for _, iface := range info.Interfaces {
+		if iface.Name == route.Device {
+			uplinkMTU = iface.MTU
+		}
+	}
+	if uplinkMTU == 0 {
+		return nil
+	}
+
+	var mismatches []string
+	for _, iface := range info.Interfaces {
+		if iface.Name == route.Device || iface.State == "down" {
+			continue
+		}
+		overhead, ok := encapsulationOverhead[iface.Kind]
+		if !ok {
+			continue
+		}
+		if iface.MTU+overhead > uplinkMTU {
+			mismatches = append(mismatches, iface.Name)
+		}
+	}
+
+	return mismatches
+}
+
+func compareInterfaceMTU(interfaces []collect.NetworkInterfaceInfo, name string, opString string, value string) (bool, error) {
+	operator, err := ParseComparisonOperator(opString)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to parse comparison operator %q", opString)
+	}
+	expected, err := strconv.Atoi(value)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to parse mtu %q", value)
+	}
+
+	for _, iface := range interfaces {
+		if iface.Name != name {
+			continue
+		}
+		switch operator {
+		case Equal:
+			return iface.MTU == expected, nil
+		case NotEqual:
+			return iface.MTU != expected, nil
+		case LessThan:
+			return iface.MTU < expected, nil
+		case LessThanOrEqual:
+			return iface.MTU <= expected, nil
+		case GreaterThan:
+			return iface.MTU > expected, nil
+		case GreaterThanOrEqual:
+			return iface.MTU >= expected, nil
+		}
+	}
+
+	return false, fmt.Errorf("interface %q was not collected", name)
+}
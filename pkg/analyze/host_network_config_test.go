@@ -0,0 +1,81 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/replicatedhq/troubleshoot/pkg/collect"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyzeHostNetworkConfig_CheckCondition(t *testing.T) {
+	healthy := collect.NetworkConfigInfo{
+		Interfaces: []collect.NetworkInterfaceInfo{
+			{Name: "lo", MTU: 65536, State: "unknown", Addresses: []string{"127.0.0.1/8"}},
+			{Name: "eth0", MTU: 1500, State: "up", Addresses: []string{"10.0.0.10/24"}},
+			{Name: "cni0", MTU: 1450, State: "up", Kind: "bridge"},
+			{Name: "flannel.1", MTU: 1450, State: "unknown", Kind: "vxlan"},
+		},
+		Routes: []collect.NetworkRoute{
+			{Family: "inet", Destination: "default", Gateway: "10.0.0.1", Device: "eth0"},
+			{Family: "inet", Destination: "10.0.0.0/24", Device: "eth0"},
+		},
+	}
+
+	overlayTooLarge := collect.NetworkConfigInfo{
+		Interfaces: []collect.NetworkInterfaceInfo{
+			{Name: "eth0", MTU: 1500, State: "up"},
+			{Name: "vxlan.calico", MTU: 1500, State: "unknown", Kind: "vxlan"},
+		},
+		Routes: []collect.NetworkRoute{
+			{Family: "inet", Destination: "default", Gateway: "10.0.0.1", Device: "eth0"},
+		},
+	}
+
+	noDefaultRoute := collect.NetworkConfigInfo{
+		Interfaces: []collect.NetworkInterfaceInfo{
+			{Name: "eth0", MTU: 9000, State: "up"},
+		},
+		Routes: []collect.NetworkRoute{
+			{Family: "inet", Destination: "10.0.0.0/24", Device: "eth0"},
+			{Family: "inet6", Destination: "fe80::/64", Device: "eth0"},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		info    collect.NetworkConfigInfo
+		when    string
+		want    bool
+		wantErr bool
+	}{
+		{name: "default route present", info: healthy, when: "no default route", want: false},
+		{name: "default route missing", info: noDefaultRoute, when: "no default route", want: true},
+		{name: "overlay fits in uplink", info: healthy, when: "mtu mismatch", want: false},
+		{name: "overlay larger than uplink", info: overlayTooLarge, when: "mtu mismatch", want: true},
+		{name: "mtu mismatch without default route", info: noDefaultRoute, when: "mtu mismatch", want: false},
+		{name: "interface mtu", info: noDefaultRoute, when: "interface eth0 mtu >= 9000", want: true},
+		{name: "interface mtu not matching", info: healthy, when: "interface eth0 mtu < 1500", want: false},
+		{name: "unknown interface", info: healthy, when: "interface eth1 mtu < 1500", wantErr: true},
+		{name: "unsupported condition", info: healthy, when: "routes > 1", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := require.New(t)
+
+			data, err := json.Marshal(tt.info)
+			req.NoError(err)
+
+			a := AnalyzeHostNetworkConfig{}
+			got, err := a.CheckCondition(tt.when, data)
+			if tt.wantErr {
+				req.Error(err)
+				return
+			}
+			req.NoError(err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
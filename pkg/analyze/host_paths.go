@@ -0,0 +1,147 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/collect"
+)
+
+// Ensure `AnalyzeHostPaths` implements `HostAnalyzer` interface at compile time.
+var _ HostAnalyzer = (*AnalyzeHostPaths)(nil)
+
+type AnalyzeHostPaths struct {
+	hostAnalyzer *troubleshootv1beta2.PathsAnalyze
+}
+
+func (a *AnalyzeHostPaths) Title() string {
+	return hostAnalyzerTitleOrDefault(a.hostAnalyzer.AnalyzeMeta, "Paths")
+}
+
+func (a *AnalyzeHostPaths) IsExcluded() (bool, error) {
+	return isExcluded(a.hostAnalyzer.Exclude)
+}
+
+func (a *AnalyzeHostPaths) Analyze(
+	getCollectedFileContents func(string) ([]byte, error), findFiles getChildCollectedFileContents,
+) ([]*AnalyzeResult, error) {
+	result := AnalyzeResult{Title: a.Title()}
+
+	collectedContents, err := retrieveCollectedContents(
+		getCollectedFileContents,
+		collect.HostPathsPath,
+		collect.NodeInfoBaseDir,
+		collect.HostPathsFileName,
+	)
+	if err != nil {
+		return []*AnalyzeResult{&result}, err
+	}
+
+	results, err := analyzeHostCollectorResults(collectedContents, a.hostAnalyzer.Outcomes, a.CheckCondition, a.Title())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to analyze paths")
+	}
+
+	return results, nil
+}
+
+// CheckCondition evaluates a when clause against the collected paths. Conditions are of the form
+// "<path> <field> <operator> <value>", where the supported fields and operators are:
+//
+//   - exists == <true|false>, e.g. "/etc/kubernetes exists == false"
+//   - type, owner, group, mountPoint and fsType == or != a value, e.g. "/var/lib/etcd owner != etcd"
+//   - mode == or != an octal mode, or has the bits of an octal mode, e.g. "/etc/kubernetes/admin.conf mode has 0044"
+//   - selinux == or != a context, or contains a part of it, e.g. "/var/lib/kubelet selinux contains container_file_t"
+//   - mountOptions contains an option, e.g. "/var/lib/kubelet mountOptions contains noexec"
+//
+// Owner and group match either the name or the id. The mount of a path that does not exist is the
+// mount of its closest existing parent.
+func (a *AnalyzeHostPaths) CheckCondition(when string, data []byte) (bool, error) {
+	var paths []collect.PathInfo
+	if err := json.Unmarshal(data, &paths); err != nil {
+		return false, errors.Wrap(err, "failed to unmarshal paths")
+	}
+
+	parts := strings.Fields(when)
+	if len(parts) != 4 {
+		return false, fmt.Errorf("expected 4 parts in when %q, got %d", when, len(parts))
+	}
+	field, operator, value := parts[1], parts[2], parts[3]
+
+	info, err := findPath(paths, parts[0])
+	if err != nil {
+		return false, err
+	}
+
+	switch field {
+	case "exists":
+		expected, err := strconv.ParseBool(value)
+		if err != nil {
+			return false, errors.Wrapf(err, "failed to parse %q", value)
+		}
+		return compareEquality(info.Exists == expected, operator)
+	case "type":
+		return compareEquality(info.Type == value, operator)
+	case "owner":
+		return compareEquality(info.Owner == value || (info.UID != nil && strconv.Itoa(*info.UID) == value), operator)
+	case "group":
+		return compareEquality(info.Group == value || (info.GID != nil && strconv.Itoa(*info.GID) == value), operator)
+	case "mountPoint":
+		return compareEquality(info.MountPoint == value, operator)
+	case "fsType":
+		return compareEquality(info.FSType == value, operator)
+	case "mode":
+		return comparePathMode(info.Mode, operator, value)
+	case "selinux":
+		if operator == "contains" {
+			return strings.Contains(info.SELinuxContext, value), nil
+		}
+		return compareEquality(info.SELinuxContext == value, operator)
+	case "mountOptions":
+		if operator != "contains" {
+			return false, fmt.Errorf("unsupported operator %q for mountOptions, must be contains", operator)
+		}
+		for _, option := range info.MountOptions {
+			if option == value {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+
+	return false, fmt.Errorf("unsupported when %q", when)
+}
+
+func findPath(paths []collect.PathInfo, path string) (collect.PathInfo, error) {
+	for _, info := range paths {
+		if info.Path == path {
+			return info, nil
+		}
+	}
+	return collect.PathInfo{}, fmt.Errorf("path %q was not collected", path)
+}
+
+// comparePathMode compares the octal mode of a path to the mode of a condition. A path that does
+// not exist has no mode, and matches none.
+func comparePathMode(actual string, operator string, expected string) (bool, error) {
+	expectedMode, err := strconv.ParseUint(expected, 8, 32)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to parse mode %q", expected)
+	}
+	if actual == "" {
+		return false, nil
+	}
+	actualMode, err := strconv.ParseUint(actual, 8, 32)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to parse collected mode %q", actual)
+	}
+
+	if operator == "has" {
+		return actualMode&expectedMode == expectedMode, nil
+	}
+	return compareEquality(actualMode == expectedMode, operator)
+}
@@ -0,0 +1,94 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/replicatedhq/troubleshoot/pkg/collect"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyzeHostPaths_CheckCondition(t *testing.T) {
+	root, etcd := 0, 998
+	paths := []collect.PathInfo{
+		{
+			Path:           "/var/lib/kubelet",
+			Exists:         true,
+			Type:           "directory",
+			Owner:          "root",
+			UID:            &root,
+			Group:          "root",
+			GID:            &root,
+			Mode:           "0755",
+			SELinuxContext: "system_u:object_r:container_var_lib_t:s0",
+			MountPoint:     "/var/lib",
+			FSType:         "xfs",
+			MountOptions:   []string{"rw", "nosuid", "nodev", "noexec"},
+		},
+		{
+			Path:   "/etc/kubernetes/admin.conf",
+			Exists: true,
+			Type:   "file",
+			Owner:  "998",
+			UID:    &etcd,
+			Group:  "root",
+			GID:    &root,
+			Mode:   "0644",
+		},
+		{
+			Path:         "/var/lib/etcd",
+			MountPoint:   "/",
+			FSType:       "ext4",
+			MountOptions: []string{"rw", "relatime"},
+		},
+	}
+
+	tests := []struct {
+		when    string
+		want    bool
+		wantErr string
+	}{
+		{when: "/var/lib/kubelet exists == true", want: true},
+		{when: "/var/lib/etcd exists == false", want: true},
+		{when: "/var/lib/etcd exists != false", want: false},
+		{when: "/var/lib/kubelet type == directory", want: true},
+		{when: "/var/lib/kubelet owner == root", want: true},
+		{when: "/var/lib/kubelet owner == 0", want: true},
+		{when: "/etc/kubernetes/admin.conf owner != root", want: true},
+		{when: "/etc/kubernetes/admin.conf group == 0", want: true},
+		{when: "/etc/kubernetes/admin.conf mode == 0644", want: true},
+		{when: "/etc/kubernetes/admin.conf mode == 644", want: true},
+		{when: "/etc/kubernetes/admin.conf mode has 0044", want: true},
+		{when: "/var/lib/kubelet mode has 0002", want: false},
+		{when: "/var/lib/etcd mode == 0700", want: false},
+		{when: "/var/lib/kubelet selinux contains container_var_lib_t", want: true},
+		{when: "/var/lib/kubelet selinux == system_u:object_r:container_var_lib_t:s0", want: true},
+		{when: "/var/lib/kubelet mountOptions contains noexec", want: true},
+		{when: "/var/lib/etcd mountOptions contains noexec", want: false},
+		{when: "/var/lib/etcd mountPoint == /", want: true},
+		{when: "/var/lib/kubelet fsType != xfs", want: false},
+		{when: "/var/lib/kubelet mountOptions == rw", wantErr: `unsupported operator "==" for mountOptions`},
+		{when: "/var/lib/kubelet mode == rwx", wantErr: `failed to parse mode "rwx"`},
+		{when: "/var/lib/kubelet size > 0", wantErr: `unsupported when`},
+		{when: "/opt exists == true", wantErr: `path "/opt" was not collected`},
+		{when: "/var/lib/kubelet exists", wantErr: `expected 4 parts`},
+	}
+
+	data, err := json.Marshal(paths)
+	require.NoError(t, err)
+
+	a := AnalyzeHostPaths{}
+	for _, tt := range tests {
+		t.Run(tt.when, func(t *testing.T) {
+			got, err := a.CheckCondition(tt.when, data)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
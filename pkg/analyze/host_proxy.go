@@ -0,0 +1,92 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/collect"
+)
+
+// Ensure `AnalyzeHostProxy` implements `HostAnalyzer` interface at compile time.
+var _ HostAnalyzer = (*AnalyzeHostProxy)(nil)
+
+type AnalyzeHostProxy struct {
+	hostAnalyzer *troubleshootv1beta2.HostProxyAnalyze
+}
+
+func (a *AnalyzeHostProxy) Title() string {
+	return hostAnalyzerTitleOrDefault(a.hostAnalyzer.AnalyzeMeta, "Proxy")
+}
+
+func (a *AnalyzeHostProxy) IsExcluded() (bool, error) {
+	return isExcluded(a.hostAnalyzer.Exclude)
+}
+
+func (a *AnalyzeHostProxy) Analyze(
+	getCollectedFileContents func(string) ([]byte, error), findFiles getChildCollectedFileContents,
+) ([]*AnalyzeResult, error) {
+	result := AnalyzeResult{Title: a.Title()}
+
+	collectedContents, err := retrieveCollectedContents(
+		getCollectedFileContents,
+		collect.HostProxyPath,
+		collect.NodeInfoBaseDir,
+		collect.HostProxyFileName,
+	)
+	if err != nil {
+		return []*AnalyzeResult{&result}, err
+	}
+
+	results, err := analyzeHostCollectorResults(collectedContents, a.hostAnalyzer.Outcomes, a.CheckCondition, a.Title())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to analyze proxy")
+	}
+
+	return results, nil
+}
+
+// CheckCondition evaluates a when clause against the collected proxy settings. Supported conditions are:
+//
+//   - "proxy <operator> <configured|unconfigured>", configured when any source sets a proxy
+//   - "<source> proxy <operator> <configured|unconfigured>", where source is one of environment,
+//     /etc/environment, containerd or kubelet, e.g. "containerd proxy == unconfigured"
+//   - "missingNoProxy <operator> <n>", the number of CIDRs missing from the NO_PROXY of any source
+//   - "inconsistent <operator> <true|false>", whether the sources that set a proxy differ
+//   - "unreachable <operator> <n>", the number of endpoints that did not respond
+func (a *AnalyzeHostProxy) CheckCondition(when string, data []byte) (bool, error) {
+	info := collect.HostProxyInfo{}
+	if err := json.Unmarshal(data, &info); err != nil {
+		return false, errors.Wrap(err, "failed to unmarshal proxy info")
+	}
+
+	parts := strings.Fields(when)
+	switch {
+	case len(parts) == 3 && parts[0] == "unreachable":
+		threshold, err := strconv.Atoi(parts[2])
+		if err != nil {
+			return false, errors.Wrapf(err, "failed to parse %q", parts[2])
+		}
+		unreachable := 0
+		for _, endpoint := range info.Endpoints {
+			if !endpoint.Reachable {
+				unreachable++
+			}
+		}
+		return compareFloat(float64(unreachable), parts[1], float64(threshold))
+	case len(parts) == 3:
+		settings := []collect.ProxySettings{}
+		for _, s := range info.Sources {
+			settings = append(settings, s)
+		}
+		return compareProxyCondition(settings, parts[0], parts[1], parts[2])
+	case len(parts) == 4 && parts[1] == "proxy":
+		// a source that could not be collected has no proxy
+		return compareEquality(configuredOrUnconfigured(info.Sources[parts[0]].IsConfigured()) == parts[3], parts[2])
+	}
+
+	return false, fmt.Errorf("unsupported when %q", when)
+}
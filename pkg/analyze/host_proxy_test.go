@@ -0,0 +1,73 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/replicatedhq/troubleshoot/pkg/collect"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyzeHostProxy_CheckCondition(t *testing.T) {
+	info := collect.HostProxyInfo{
+		Sources: map[string]collect.ProxySettings{
+			collect.ProxySourceEnvironment: {},
+			collect.ProxySourceEtcEnvironment: {
+				HTTPSProxy:     "http://proxy:3128",
+				NoProxy:        "localhost,10.96.0.0/12",
+				MissingNoProxy: []string{"10.244.0.0/16"},
+			},
+			collect.ProxySourceContainerdUnit: {
+				HTTPSProxy:     "http://proxy:3128",
+				NoProxy:        "localhost",
+				MissingNoProxy: []string{"10.96.0.0/12", "10.244.0.0/16"},
+			},
+			collect.ProxySourceKubeletUnit: {},
+		},
+		EndpointsSource: collect.ProxySourceEtcEnvironment,
+		Endpoints: []collect.ProxyEndpointResult{
+			{URL: "https://registry.example.com", Proxy: "http://proxy:3128", Reachable: true, StatusCode: 200},
+			{URL: "https://api.example.com", Proxy: "http://proxy:3128", Error: "proxyconnect tcp: dial tcp: connection refused"},
+		},
+	}
+
+	tests := []struct {
+		when    string
+		want    bool
+		wantErr string
+	}{
+		{when: "proxy == configured", want: true},
+		{when: "proxy == unconfigured", want: false},
+		{when: "containerd proxy == configured", want: true},
+		{when: "kubelet proxy == unconfigured", want: true},
+		{when: "environment proxy != unconfigured", want: false},
+		{when: "/etc/environment proxy == configured", want: true},
+		{when: "missingNoProxy > 0", want: true},
+		{when: "missingNoProxy == 2", want: true},
+		{when: "inconsistent == true", want: true},
+		{when: "unreachable > 0", want: true},
+		{when: "unreachable >= 2", want: false},
+		{when: "missingNoProxy > some", wantErr: `failed to parse "some"`},
+		{when: "inconsistent > true", wantErr: `only supported operators are "==" and "!="`},
+		{when: "proxies == configured", wantErr: `unsupported proxy condition "proxies"`},
+		{when: "proxy configured", wantErr: `unsupported when "proxy configured"`},
+	}
+
+	data, err := json.Marshal(info)
+	require.NoError(t, err)
+
+	a := AnalyzeHostProxy{}
+	for _, tt := range tests {
+		t.Run(tt.when, func(t *testing.T) {
+			got, err := a.CheckCondition(tt.when, data)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
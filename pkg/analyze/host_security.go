@@ -0,0 +1,183 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/collect"
+)
+
+// Ensure `AnalyzeHostSecurity` implements `HostAnalyzer` interface at compile time.
+var _ HostAnalyzer = (*AnalyzeHostSecurity)(nil)
+
+type AnalyzeHostSecurity struct {
+	hostAnalyzer *troubleshootv1beta2.SecurityAnalyze
+}
+
+func (a *AnalyzeHostSecurity) Title() string {
+	return hostAnalyzerTitleOrDefault(a.hostAnalyzer.AnalyzeMeta, "Security")
+}
+
+func (a *AnalyzeHostSecurity) IsExcluded() (bool, error) {
+	return isExcluded(a.hostAnalyzer.Exclude)
+}
+
+func (a *AnalyzeHostSecurity) Analyze(
+	getCollectedFileContents func(string) ([]byte, error), findFiles getChildCollectedFileContents,
+) ([]*AnalyzeResult, error) {
+	result := AnalyzeResult{Title: a.Title()}
+
+	collectedContents, err := retrieveCollectedContents(
+		getCollectedFileContents,
+		collect.HostSecurityPath,
+		collect.NodeInfoBaseDir,
+		collect.HostSecurityFileName,
+	)
+	if err != nil {
+		return []*AnalyzeResult{&result}, err
+	}
+
+	results, err := analyzeHostCollectorResults(collectedContents, a.hostAnalyzer.Outcomes, a.CheckCondition, a.Title())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to analyze security")
+	}
+
+	return results, nil
+}
+
+// CheckCondition evaluates a when clause against the collected security settings. Supported conditions are:
+//
+//   - "selinux <operator> <enforcing|permissive|disabled>", the current SELinux mode
+//   - "selinuxConfig <operator> <mode>" and "selinuxPolicy <operator> <policy>", from /etc/selinux/config
+//   - "selinuxBoolean <name> <operator> <on|off>", e.g. "selinuxBoolean container_manage_cgroup == on"
+//   - "apparmor <operator> <enabled|disabled>"
+//   - "apparmorProfile <name> <operator> <enforce|complain|unloaded>", e.g. "apparmorProfile cri-containerd.apparmor.d == enforce"
+//   - "seccomp <operator> <available|unavailable>"
+//   - "kubeletSeccompDefault <operator> <true|false>"
+//   - "<containerd|crio> <configured|selinux|apparmor> <operator> <true|false>", e.g. "containerd selinux == false"
+//   - "<containerd|crio> <apparmorProfile|seccompProfile> <operator> <profile>"
+//
+// Operators are == and !=. A boolean that is not set, e.g. when SELinux is disabled, is off.
+func (a *AnalyzeHostSecurity) CheckCondition(when string, data []byte) (bool, error) {
+	info := collect.SecurityInfo{}
+	if err := json.Unmarshal(data, &info); err != nil {
+		return false, errors.Wrap(err, "failed to unmarshal security info")
+	}
+
+	parts := strings.Fields(when)
+	switch {
+	case len(parts) == 3:
+		return compareSecurityCondition(info, parts[0], parts[1], parts[2])
+	case len(parts) == 4:
+		return compareNamedSecurityCondition(info, parts[0], parts[1], parts[2], parts[3])
+	}
+
+	return false, fmt.Errorf("expected 3 or 4 parts in when %q, got %d", when, len(parts))
+}
+
+func compareSecurityCondition(info collect.SecurityInfo, setting string, operator string, value string) (bool, error) {
+	switch setting {
+	case "selinux":
+		return compareEquality(info.SELinux.Mode == value, operator)
+	case "selinuxConfig":
+		return compareEquality(info.SELinux.ConfiguredMode == value, operator)
+	case "selinuxPolicy":
+		return compareEquality(info.SELinux.Policy == value, operator)
+	case "apparmor":
+		return compareEquality(enabledOrDisabled(info.AppArmor.Enabled) == value, operator)
+	case "seccomp":
+		available := "unavailable"
+		if info.Seccomp.Available {
+			available = "available"
+		}
+		return compareEquality(available == value, operator)
+	case "kubeletSeccompDefault":
+		expected, err := strconv.ParseBool(value)
+		if err != nil {
+			return false, errors.Wrapf(err, "failed to parse %q", value)
+		}
+		actual := info.Seccomp.KubeletDefault != nil && *info.Seccomp.KubeletDefault
+		return compareEquality(actual == expected, operator)
+	}
+
+	return false, fmt.Errorf("unsupported setting %q", setting)
+}
+
+func compareNamedSecurityCondition(info collect.SecurityInfo, setting string, name string, operator string, value string) (bool, error) {
+	switch setting {
+	case "selinuxBoolean":
+		expected, err := parseOnOff(value)
+		if err != nil {
+			return false, err
+		}
+		return compareEquality(info.SELinux.Booleans[name] == expected, operator)
+	case "apparmorProfile":
+		mode, ok := info.AppArmor.Profiles[name]
+		if !ok {
+			mode = "unloaded"
+		}
+		return compareEquality(mode == value, operator)
+	case "containerd":
+		return compareContainerRuntimeSecurity(info.Containerd, name, operator, value)
+	case "crio":
+		return compareContainerRuntimeSecurity(info.CRIO, name, operator, value)
+	}
+
+	return false, fmt.Errorf("unsupported setting %q", setting)
+}
+
+// compareContainerRuntimeSecurity compares a setting of a container runtime, which has the zero
+// value of each setting when it is not configured
+func compareContainerRuntimeSecurity(runtime *collect.ContainerRuntimeSecurity, setting string, operator string, value string) (bool, error) {
+	configured := runtime != nil
+	if runtime == nil {
+		runtime = &collect.ContainerRuntimeSecurity{}
+	}
+
+	var actual bool
+	switch setting {
+	case "apparmorProfile":
+		return compareEquality(runtime.AppArmorProfile == value, operator)
+	case "seccompProfile":
+		return compareEquality(runtime.SeccompProfile == value, operator)
+	case "configured":
+		actual = configured
+	case "selinux":
+		actual = runtime.SELinux
+	case "apparmor":
+		actual = runtime.AppArmor
+	default:
+		return false, fmt.Errorf("unsupported container runtime setting %q", setting)
+	}
+
+	expected, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to parse %q", value)
+	}
+	return compareEquality(actual == expected, operator)
+}
+
+func enabledOrDisabled(enabled bool) string {
+	if enabled {
+		return "enabled"
+	}
+	return "disabled"
+}
+
+func parseOnOff(value string) (bool, error) {
+	switch value {
+	case "on", "1":
+		return true, nil
+	case "off", "0":
+		return false, nil
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, errors.Errorf("failed to parse %q, must be on or off", value)
+	}
+	return b, nil
+}
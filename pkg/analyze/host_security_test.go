@@ -0,0 +1,78 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/replicatedhq/troubleshoot/pkg/collect"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyzeHostSecurity_CheckCondition(t *testing.T) {
+	seccompDefault := true
+	info := collect.SecurityInfo{
+		SELinux: collect.SELinuxInfo{
+			Mode:           collect.SELinuxModeEnforcing,
+			ConfiguredMode: "enforcing",
+			Policy:         "targeted",
+			Booleans:       map[string]bool{"container_manage_cgroup": true, "virt_use_nfs": false},
+		},
+		AppArmor: collect.AppArmorInfo{
+			Enabled:  true,
+			Profiles: map[string]string{"cri-containerd.apparmor.d": "enforce"},
+		},
+		Seccomp: collect.SeccompInfo{Available: true, KubeletDefault: &seccompDefault},
+		Containerd: &collect.ContainerRuntimeSecurity{
+			ConfigPaths: []string{"/etc/containerd/config.toml"},
+			SELinux:     true,
+			AppArmor:    true,
+		},
+	}
+
+	tests := []struct {
+		when    string
+		want    bool
+		wantErr string
+	}{
+		{when: "selinux == enforcing", want: true},
+		{when: "selinux != permissive", want: true},
+		{when: "selinuxConfig == disabled", want: false},
+		{when: "selinuxPolicy == targeted", want: true},
+		{when: "selinuxBoolean container_manage_cgroup == on", want: true},
+		{when: "selinuxBoolean virt_use_nfs == true", want: false},
+		{when: "selinuxBoolean container_use_cephfs == off", want: true},
+		{when: "apparmor == enabled", want: true},
+		{when: "apparmorProfile cri-containerd.apparmor.d == enforce", want: true},
+		{when: "apparmorProfile crio-default == unloaded", want: true},
+		{when: "seccomp == unavailable", want: false},
+		{when: "kubeletSeccompDefault == true", want: true},
+		{when: "containerd configured == true", want: true},
+		{when: "containerd selinux == true", want: true},
+		{when: "containerd seccompProfile == unconfined", want: false},
+		{when: "crio configured == false", want: true},
+		{when: "crio selinux == true", want: false},
+		{when: "selinuxBoolean container_manage_cgroup == maybe", wantErr: `failed to parse "maybe", must be on or off`},
+		{when: "selinux > enforcing", wantErr: `only supported operators are "==" and "!="`},
+		{when: "containerd privileged == true", wantErr: `unsupported container runtime setting "privileged"`},
+		{when: "docker selinux == true", wantErr: `unsupported setting "docker"`},
+		{when: "selinux enforcing", wantErr: `expected 3 or 4 parts`},
+	}
+
+	data, err := json.Marshal(info)
+	require.NoError(t, err)
+
+	a := AnalyzeHostSecurity{}
+	for _, tt := range tests {
+		t.Run(tt.when, func(t *testing.T) {
+			got, err := a.CheckCondition(tt.when, data)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
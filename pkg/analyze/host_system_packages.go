@@ -190,16 +190,19 @@ func (a *AnalyzeHostSystemPackages) analyzeSingleNode(content collectedContent,
 				r.IsFail = true
 				r.Message = outcome.Fail.Message
 				r.URI = outcome.Fail.URI
+				r.Remediation = outcome.Fail.Remediation
 				when = outcome.Fail.When
 			case outcome.Warn != nil:
 				r.IsWarn = true
 				r.Message = outcome.Warn.Message
 				r.URI = outcome.Warn.URI
+				r.Remediation = outcome.Warn.Remediation
 				when = outcome.Warn.When
 			case outcome.Pass != nil:
 				r.IsPass = true
 				r.Message = outcome.Pass.Message
 				r.URI = outcome.Pass.URI
+				r.Remediation = outcome.Pass.Remediation
 				when = outcome.Pass.When
 			default:
 				return nil, errors.New("invalid outcome")
@@ -0,0 +1,121 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/collect"
+)
+
+// Ensure `AnalyzeHostSystemdUnits` implements `HostAnalyzer` interface at compile time.
+var _ HostAnalyzer = (*AnalyzeHostSystemdUnits)(nil)
+
+type AnalyzeHostSystemdUnits struct {
+	hostAnalyzer *troubleshootv1beta2.SystemdUnitsAnalyze
+}
+
+func (a *AnalyzeHostSystemdUnits) Title() string {
+	return hostAnalyzerTitleOrDefault(a.hostAnalyzer.AnalyzeMeta, "Systemd Units")
+}
+
+func (a *AnalyzeHostSystemdUnits) IsExcluded() (bool, error) {
+	return isExcluded(a.hostAnalyzer.Exclude)
+}
+
+func (a *AnalyzeHostSystemdUnits) Analyze(
+	getCollectedFileContents func(string) ([]byte, error), findFiles getChildCollectedFileContents,
+) ([]*AnalyzeResult, error) {
+	result := AnalyzeResult{Title: a.Title()}
+
+	collectedContents, err := retrieveCollectedContents(
+		getCollectedFileContents,
+		collect.HostSystemdUnitsPath,
+		collect.NodeInfoBaseDir,
+		collect.HostSystemdUnitsFileName,
+	)
+	if err != nil {
+		return []*AnalyzeResult{&result}, err
+	}
+
+	results, err := analyzeHostCollectorResults(collectedContents, a.hostAnalyzer.Outcomes, a.CheckCondition, a.Title())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to analyze systemd units")
+	}
+
+	return results, nil
+}
+
+// CheckCondition evaluates a when clause against the collected units. Supported conditions are:
+//
+//   - <unit> is <state>, e.g. "containerd is active" or "kubelet is failed"
+//   - <unit> is not <state>, e.g. "firewalld is not active"
+//   - <unit> restarts <operator> <count>, e.g. "kubelet restarts > 3"
+//
+// States are compared to the unit's active state (active, inactive, failed, activating, deactivating).
+func (a *AnalyzeHostSystemdUnits) CheckCondition(when string, data []byte) (bool, error) {
+	var units []collect.SystemdUnitInfo
+	if err := json.Unmarshal(data, &units); err != nil {
+		return false, errors.Wrap(err, "failed to unmarshal systemd units")
+	}
+
+	parts := strings.Fields(when)
+	if len(parts) < 3 {
+		return false, fmt.Errorf("expected at least 3 parts in when %q", when)
+	}
+
+	unit, err := findSystemdUnit(units, parts[0])
+	if err != nil {
+		return false, err
+	}
+
+	switch {
+	case parts[1] == "is" && len(parts) == 3:
+		return unit.ActiveState == parts[2], nil
+	case parts[1] == "is" && len(parts) == 4 && parts[2] == "not":
+		return unit.ActiveState != parts[3], nil
+	case parts[1] == "restarts" && len(parts) == 4:
+		operator, err := ParseComparisonOperator(parts[2])
+		if err != nil {
+			return false, errors.Wrapf(err, "failed to parse comparison operator %q", parts[2])
+		}
+		expected, err := strconv.Atoi(parts[3])
+		if err != nil {
+			return false, errors.Wrapf(err, "failed to parse restart count %q", parts[3])
+		}
+		return compareSystemdUnitRestarts(unit.Restarts, operator, expected)
+	}
+
+	return false, fmt.Errorf("unsupported when %q", when)
+}
+
+func findSystemdUnit(units []collect.SystemdUnitInfo, name string) (collect.SystemdUnitInfo, error) {
+	unitName := collect.SystemdUnitName(name)
+	for _, unit := range units {
+		if unit.Unit == unitName {
+			return unit, nil
+		}
+	}
+	return collect.SystemdUnitInfo{}, fmt.Errorf("unit %q was not collected", unitName)
+}
+
+func compareSystemdUnitRestarts(actual int, operator ComparisonOperator, expected int) (bool, error) {
+	switch operator {
+	case Equal:
+		return actual == expected, nil
+	case NotEqual:
+		return actual != expected, nil
+	case LessThan:
+		return actual < expected, nil
+	case LessThanOrEqual:
+		return actual <= expected, nil
+	case GreaterThan:
+		return actual > expected, nil
+	case GreaterThanOrEqual:
+		return actual >= expected, nil
+	}
+	return false, fmt.Errorf("unsupported operator %v", operator)
+}
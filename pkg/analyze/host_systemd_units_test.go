@@ -0,0 +1,96 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"testing"
+
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/collect"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyzeHostSystemdUnits(t *testing.T) {
+	units := []collect.SystemdUnitInfo{
+		{Unit: "containerd.service", LoadState: "loaded", ActiveState: "active", SubState: "running"},
+		{Unit: "kubelet.service", LoadState: "loaded", ActiveState: "active", SubState: "running", Restarts: 5},
+		{Unit: "firewalld.service", LoadState: "not-found", ActiveState: "inactive", SubState: "dead"},
+	}
+
+	tests := []struct {
+		name      string
+		outcomes  []*troubleshootv1beta2.Outcome
+		result    []*AnalyzeResult
+		expectErr bool
+	}{
+		{
+			name: "containerd is active",
+			outcomes: []*troubleshootv1beta2.Outcome{
+				{Fail: &troubleshootv1beta2.SingleOutcome{When: "containerd is not active", Message: "containerd is not running"}},
+				{Pass: &troubleshootv1beta2.SingleOutcome{When: "containerd is active", Message: "containerd is running"}},
+			},
+			result: []*AnalyzeResult{
+				{Title: "Systemd Units", IsPass: true, Message: "containerd is running"},
+			},
+		},
+		{
+			name: "firewalld is inactive",
+			outcomes: []*troubleshootv1beta2.Outcome{
+				{Fail: &troubleshootv1beta2.SingleOutcome{When: "firewalld.service is active", Message: "firewalld must be disabled"}},
+				{Pass: &troubleshootv1beta2.SingleOutcome{When: "firewalld is inactive", Message: "firewalld is disabled"}},
+			},
+			result: []*AnalyzeResult{
+				{Title: "Systemd Units", IsPass: true, Message: "firewalld is disabled"},
+			},
+		},
+		{
+			name: "kubelet restarting",
+			outcomes: []*troubleshootv1beta2.Outcome{
+				{Warn: &troubleshootv1beta2.SingleOutcome{When: "kubelet restarts > 3", Message: "kubelet has restarted more than 3 times"}},
+				{Pass: &troubleshootv1beta2.SingleOutcome{Message: "kubelet is stable"}},
+			},
+			result: []*AnalyzeResult{
+				{Title: "Systemd Units", IsWarn: true, Message: "kubelet has restarted more than 3 times"},
+			},
+		},
+		{
+			name: "unit not collected",
+			outcomes: []*troubleshootv1beta2.Outcome{
+				{Fail: &troubleshootv1beta2.SingleOutcome{When: "docker is active", Message: "docker is running"}},
+			},
+			expectErr: true,
+		},
+		{
+			name: "unsupported condition",
+			outcomes: []*troubleshootv1beta2.Outcome{
+				{Fail: &troubleshootv1beta2.SingleOutcome{When: "containerd was active", Message: "containerd is running"}},
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := require.New(t)
+
+			b, err := json.Marshal(units)
+			req.NoError(err)
+
+			getCollectedFileContents := func(filename string) ([]byte, error) {
+				return b, nil
+			}
+
+			a := AnalyzeHostSystemdUnits{
+				hostAnalyzer: &troubleshootv1beta2.SystemdUnitsAnalyze{Outcomes: test.outcomes},
+			}
+			result, err := a.Analyze(getCollectedFileContents, nil)
+			if test.expectErr {
+				req.Error(err)
+				return
+			}
+			req.NoError(err)
+
+			assert.Equal(t, test.result, result)
+		})
+	}
+}
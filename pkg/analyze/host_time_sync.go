@@ -0,0 +1,126 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/collect"
+)
+
+// Ensure `AnalyzeHostTimeSync` implements `HostAnalyzer` interface at compile time.
+var _ HostAnalyzer = (*AnalyzeHostTimeSync)(nil)
+
+type AnalyzeHostTimeSync struct {
+	hostAnalyzer *troubleshootv1beta2.TimeSyncAnalyze
+}
+
+func (a *AnalyzeHostTimeSync) Title() string {
+	return hostAnalyzerTitleOrDefault(a.hostAnalyzer.AnalyzeMeta, "Time Sync")
+}
+
+func (a *AnalyzeHostTimeSync) IsExcluded() (bool, error) {
+	return isExcluded(a.hostAnalyzer.Exclude)
+}
+
+func (a *AnalyzeHostTimeSync) Analyze(
+	getCollectedFileContents func(string) ([]byte, error), findFiles getChildCollectedFileContents,
+) ([]*AnalyzeResult, error) {
+	result := AnalyzeResult{Title: a.Title()}
+
+	collectedContents, err := retrieveCollectedContents(
+		getCollectedFileContents,
+		collect.HostTimeSyncPath,
+		collect.NodeInfoBaseDir,
+		collect.HostTimeSyncFileName,
+	)
+	if err != nil {
+		return []*AnalyzeResult{&result}, err
+	}
+
+	results, err := analyzeHostCollectorResults(collectedContents, a.hostAnalyzer.Outcomes, a.CheckCondition, a.Title())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to analyze time sync")
+	}
+
+	return results, nil
+}
+
+// CheckCondition evaluates a when clause against the collected time sync status. Supported conditions are:
+//
+//   - "offset <operator> <duration>", compared against the absolute clock offset, e.g. "offset > 500ms"
+//   - "synchronized == <true|false>" or "synchronized != <true|false>"
+//   - "source == <chrony|ntpd|timesyncd|none>" or "source != <...>"
+//   - "servers <operator> <n>", e.g. "servers == 0"
+//
+// An offset condition is false when the offset could not be collected.
+func (a *AnalyzeHostTimeSync) CheckCondition(when string, data []byte) (bool, error) {
+	info := collect.TimeSyncInfo{}
+	if err := json.Unmarshal(data, &info); err != nil {
+		return false, errors.Wrap(err, "failed to unmarshal time sync info")
+	}
+
+	parts := strings.Fields(when)
+	if len(parts) != 3 {
+		return false, fmt.Errorf("expected 3 parts in when %q, got %d", when, len(parts))
+	}
+
+	switch parts[0] {
+	case "offset":
+		return compareTimeSyncOffset(info.OffsetSeconds, parts[1], parts[2])
+	case "synchronized":
+		expected, err := strconv.ParseBool(parts[2])
+		if err != nil {
+			return false, errors.Wrapf(err, "failed to parse %q", parts[2])
+		}
+		return compareEquality(info.Synchronized == expected, parts[1])
+	case "source":
+		source := info.Source
+		if source == "" {
+			source = "none"
+		}
+		return compareEquality(source == parts[2], parts[1])
+	case "servers":
+		return compareActualToWhen(parts[1]+" "+parts[2], len(info.Servers))
+	}
+
+	return false, fmt.Errorf("unsupported when %q", when)
+}
+
+func compareTimeSyncOffset(offsetSeconds *float64, opString string, expected string) (bool, error) {
+	operator, err := ParseComparisonOperator(opString)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to parse comparison operator %q", opString)
+	}
+	threshold, err := time.ParseDuration(expected)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to parse duration %q", expected)
+	}
+
+	if offsetSeconds == nil {
+		return false, nil
+	}
+	actual := math.Abs(*offsetSeconds)
+
+	switch operator {
+	case Equal:
+		return actual == threshold.Seconds(), nil
+	case NotEqual:
+		return actual != threshold.Seconds(), nil
+	case LessThan:
+		return actual < threshold.Seconds(), nil
+	case LessThanOrEqual:
+		return actual <= threshold.Seconds(), nil
+	case GreaterThan:
+		return actual > threshold.Seconds(), nil
+	case GreaterThanOrEqual:
+		return actual >= threshold.Seconds(), nil
+	}
+
+	return false, fmt.Errorf("unsupported operator %q", opString)
+}
@@ -0,0 +1,65 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/replicatedhq/troubleshoot/pkg/collect"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyzeHostTimeSync_CheckCondition(t *testing.T) {
+	offset := func(seconds float64) *float64 { return &seconds }
+
+	chrony := collect.TimeSyncInfo{
+		Source:        collect.TimeSyncSourceChrony,
+		Synchronized:  true,
+		OffsetSeconds: offset(-1.5),
+		Servers:       []string{"0.pool.ntp.org", "1.pool.ntp.org"},
+	}
+	none := collect.TimeSyncInfo{
+		Servers: []string{},
+		Errors:  map[string]string{"chronyc": "exec: not found"},
+	}
+
+	tests := []struct {
+		name    string
+		info    collect.TimeSyncInfo
+		when    string
+		want    bool
+		wantErr bool
+	}{
+		{name: "skew exceeds threshold", info: chrony, when: "offset > 1s", want: true},
+		{name: "skew within threshold", info: chrony, when: "offset > 2s", want: false},
+		{name: "skew in milliseconds", info: chrony, when: "offset <= 1500ms", want: true},
+		{name: "unknown offset", info: none, when: "offset > 500ms", want: false},
+		{name: "synchronized", info: chrony, when: "synchronized == true", want: true},
+		{name: "not synchronized", info: none, when: "synchronized != true", want: true},
+		{name: "source", info: chrony, when: "source == chrony", want: true},
+		{name: "no source", info: none, when: "source == none", want: true},
+		{name: "servers", info: chrony, when: "servers >= 2", want: true},
+		{name: "no servers", info: none, when: "servers == 0", want: true},
+		{name: "invalid duration", info: chrony, when: "offset > 1 second", wantErr: true},
+		{name: "invalid operator", info: chrony, when: "source > chrony", wantErr: true},
+		{name: "unsupported condition", info: chrony, when: "stratum < 4", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := require.New(t)
+
+			data, err := json.Marshal(tt.info)
+			req.NoError(err)
+
+			a := AnalyzeHostTimeSync{}
+			got, err := a.CheckCondition(tt.when, data)
+			if tt.wantErr {
+				req.Error(err)
+				return
+			}
+			req.NoError(err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,204 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/collect"
+)
+
+const defaultImageMaxAgeDays = 365
+
+// ImageFactsDefaultOutcomes are evaluated when the analyzer sets no outcomes
+var ImageFactsDefaultOutcomes = []*troubleshootv1beta2.Outcome{
+	{
+		Warn: &troubleshootv1beta2.SingleOutcome{
+			When:    "failedImages > 0",
+			Message: "The configs of {{ .FailedImages }} could not be read",
+		},
+	},
+	{
+		Warn: &troubleshootv1beta2.SingleOutcome{
+			When:    "rootImages > 0",
+			Message: "{{ .RootImages }} run as root unless their pods set another user",
+		},
+	},
+	{
+		Warn: &troubleshootv1beta2.SingleOutcome{
+			When:    "oldImages > 0",
+			Message: "{{ .OldImages }} were built more than {{ .MaxAgeDays }} days ago",
+		},
+	},
+	{
+		Pass: &troubleshootv1beta2.SingleOutcome{
+			Message: "None of the {{ .Images }} images run as root or were built more than {{ .MaxAgeDays }} days ago",
+		},
+	},
+}
+
+// imageFactsTemplateData is passed to the messages of the outcomes
+type imageFactsTemplateData struct {
+	// Images is the number of images whose configs were read
+	Images int
+	// FailedImages are the images whose configs could not be read, comma separated
+	FailedImages string
+	// RootImages are the images that run as root, comma separated
+	RootImages string
+	// OldImages are the images built more than MaxAgeDays ago with their ages, the oldest first
+	OldImages       string
+	OldestImage     string
+	OldestImageDays int
+	MaxAgeDays      int
+
+	report *imageFactsReport
+}
+
+// imageFactsReport is the users and ages of the collected images. Images without a creation date
+// are not old.
+type imageFactsReport struct {
+	images       int
+	failedImages []string
+	rootImages   []string
+	oldImages    []imageAge
+	oldest       imageAge
+}
+
+type imageAge struct {
+	image string
+	days  int
+}
+
+type AnalyzeImageFacts struct {
+	analyzer *troubleshootv1beta2.ImageFactsAnalyze
+}
+
+func (a *AnalyzeImageFacts) Title() string {
+	if a.analyzer.CheckName != "" {
+		return a.analyzer.CheckName
+	}
+	return "Image Facts"
+}
+
+func (a *AnalyzeImageFacts) IsExcluded() (bool, error) {
+	return isExcluded(a.analyzer.Exclude)
+}
+
+func (a *AnalyzeImageFacts) Analyze(getFile getCollectedFileContents, findFiles getChildCollectedFileContents) ([]*AnalyzeResult, error) {
+	fullPath := collect.ImageFactsOutputPath(a.analyzer.CollectorName)
+	collected, err := getFile(fullPath)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read collected file name: %s", fullPath)
+	}
+
+	info := collect.ImageFactsInfo{}
+	if err := json.Unmarshal(collected, &info); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse %s", fullPath)
+	}
+
+	maxAgeDays := a.analyzer.MaxAgeDays
+	if maxAgeDays <= 0 {
+		maxAgeDays = defaultImageMaxAgeDays
+	}
+	data := newImageFactsTemplateData(newImageFactsReport(info, maxAgeDays, time.Now()), maxAgeDays)
+
+	outcomes := a.analyzer.Outcomes
+	if len(outcomes) == 0 {
+		outcomes = ImageFactsDefaultOutcomes
+	}
+
+	result, err := analyzeTemplatedOutcomes(a.Title(), a.analyzer.Strict.BoolOrDefaultFalse(), outcomes, data, func(when string) (bool, error) {
+		return compareImageFacts(data.report, when)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		return []*AnalyzeResult{}, nil
+	}
+	return []*AnalyzeResult{result}, nil
+}
+
+// newImageFactsReport lists the images that run as root, and those created more than maxAgeDays
+// before now
+func newImageFactsReport(info collect.ImageFactsInfo, maxAgeDays int, now time.Time) *imageFactsReport {
+	report := &imageFactsReport{}
+
+	for _, image := range info.Images {
+		if image.Error != "" {
+			report.failedImages = append(report.failedImages, image.Image)
+			continue
+		}
+		report.images++
+
+		if image.RunsAsRoot {
+			report.rootImages = append(report.rootImages, image.Image)
+		}
+
+		if image.Created == nil {
+			continue
+		}
+		age := imageAge{image: image.Image, days: int(now.Sub(*image.Created).Hours() / 24)}
+		if report.oldest.image == "" || age.days > report.oldest.days {
+			report.oldest = age
+		}
+		if age.days > maxAgeDays {
+			report.oldImages = append(report.oldImages, age)
+		}
+	}
+
+	sort.SliceStable(report.oldImages, func(i, j int) bool {
+		return report.oldImages[i].days > report.oldImages[j].days
+	})
+
+	return report
+}
+
+func newImageFactsTemplateData(report *imageFactsReport, maxAgeDays int) *imageFactsTemplateData {
+	data := &imageFactsTemplateData{
+		Images:          report.images,
+		FailedImages:    strings.Join(report.failedImages, ", "),
+		RootImages:      strings.Join(report.rootImages, ", "),
+		OldestImage:     report.oldest.image,
+		OldestImageDays: report.oldest.days,
+		MaxAgeDays:      maxAgeDays,
+		report:          report,
+	}
+
+	oldImages := []string{}
+	for _, age := range report.oldImages {
+		oldImages = append(oldImages, fmt.Sprintf("%s (%d days)", age.image, age.days))
+	}
+	data.OldImages = strings.Join(oldImages, ", ")
+
+	return data
+}
+
+// compareImageFacts evaluates a when clause against the report. Supported conditions are the
+// counts images, failedImages, rootImages and oldImages, and oldestImageDays, the age in days
+// of the oldest image, e.g. "oldestImageDays > 730".
+func compareImageFacts(report *imageFactsReport, when string) (bool, error) {
+	parts := strings.Fields(when)
+	if len(parts) != 3 {
+		return false, fmt.Errorf("expected 3 parts in when %q, got %d", when, len(parts))
+	}
+	key, condition := parts[0], parts[1]+" "+parts[2]
+
+	switch key {
+	case "images":
+		return compareActualToWhen(condition, report.images)
+	case "failedImages":
+		return compareActualToWhen(condition, len(report.failedImages))
+	case "rootImages":
+		return compareActualToWhen(condition, len(report.rootImages))
+	case "oldImages":
+		return compareActualToWhen(condition, len(report.oldImages))
+	case "oldestImageDays":
+		return compareActualToWhen(condition, report.oldest.days)
+	}
+	return false, fmt.Errorf("unsupported condition %q, must be one of images, failedImages, rootImages, oldImages or oldestImageDays", key)
+}
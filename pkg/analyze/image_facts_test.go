@@ -0,0 +1,114 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/collect"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func daysAgo(now time.Time, days int) *time.Time {
+	created := now.AddDate(0, 0, -days).Add(-time.Hour)
+	return &created
+}
+
+func imageFactsInfo(now time.Time) collect.ImageFactsInfo {
+	return collect.ImageFactsInfo{
+		Platform: "linux/amd64",
+		Images: []collect.ImageFactsData{
+			{Image: "registry.example.com/app/api:1.0", Created: daysAgo(now, 30), User: "1000"},
+			{Image: "registry.example.com/app/worker:1.0", Created: daysAgo(now, 400), RunsAsRoot: true},
+			{Image: "postgres:12", Created: daysAgo(now, 900), User: "postgres"},
+			{Image: "registry.example.com/app/legacy:1.0", RunsAsRoot: true},
+			{Image: "registry.example.com/app/missing:1.0", Error: "failed to get config: manifest unknown"},
+		},
+	}
+}
+
+func Test_newImageFactsReport(t *testing.T) {
+	now := time.Now()
+	report := newImageFactsReport(imageFactsInfo(now), 365, now)
+
+	assert.Equal(t, 4, report.images)
+	assert.Equal(t, []string{"registry.example.com/app/missing:1.0"}, report.failedImages)
+	assert.Equal(t, []string{"registry.example.com/app/worker:1.0", "registry.example.com/app/legacy:1.0"}, report.rootImages)
+	assert.Equal(t, []imageAge{
+		{image: "postgres:12", days: 900},
+		{image: "registry.example.com/app/worker:1.0", days: 400},
+	}, report.oldImages)
+	assert.Equal(t, imageAge{image: "postgres:12", days: 900}, report.oldest)
+
+	data := newImageFactsTemplateData(report, 365)
+	assert.Equal(t, "postgres:12 (900 days), registry.example.com/app/worker:1.0 (400 days)", data.OldImages)
+	assert.Equal(t, "registry.example.com/app/worker:1.0, registry.example.com/app/legacy:1.0", data.RootImages)
+	assert.Equal(t, 900, data.OldestImageDays)
+}
+
+func Test_compareImageFacts(t *testing.T) {
+	now := time.Now()
+	report := newImageFactsReport(imageFactsInfo(now), 365, now)
+
+	tests := []struct {
+		when    string
+		want    bool
+		wantErr bool
+	}{
+		{when: "images == 4", want: true},
+		{when: "failedImages > 0", want: true},
+		{when: "rootImages == 0", want: false},
+		{when: "oldImages >= 2", want: true},
+		{when: "oldestImageDays > 730", want: true},
+		{when: "users > 1", wantErr: true},
+		{when: "rootImages", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.when, func(t *testing.T) {
+			got, err := compareImageFacts(report, tt.when)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestAnalyzeImageFacts(t *testing.T) {
+	info := imageFactsInfo(time.Now())
+	info.Images = info.Images[:1]
+	data, err := json.Marshal(info)
+	require.NoError(t, err)
+
+	a := AnalyzeImageFacts{analyzer: &troubleshootv1beta2.ImageFactsAnalyze{CollectorName: "app", MaxAgeDays: 90}}
+	results, err := a.Analyze(func(path string) ([]byte, error) {
+		require.Equal(t, "registry/app-facts.json", path)
+		return data, nil
+	}, nil)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.True(t, results[0].IsPass)
+	assert.Equal(t, "Image Facts", results[0].Title)
+	assert.Equal(t, "None of the 1 images run as root or were built more than 90 days ago", results[0].Message)
+
+	info = imageFactsInfo(time.Now())
+	data, err = json.Marshal(info)
+	require.NoError(t, err)
+	a = AnalyzeImageFacts{analyzer: &troubleshootv1beta2.ImageFactsAnalyze{
+		Outcomes: []*troubleshootv1beta2.Outcome{
+			{Fail: &troubleshootv1beta2.SingleOutcome{When: "rootImages > 0", Message: "{{ .RootImages }} run as root"}},
+			{Pass: &troubleshootv1beta2.SingleOutcome{Message: "No image runs as root"}},
+		},
+	}}
+	results, err = a.Analyze(func(path string) ([]byte, error) {
+		return data, nil
+	}, nil)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.True(t, results[0].IsFail)
+	assert.Equal(t, "registry.example.com/app/worker:1.0, registry.example.com/app/legacy:1.0 run as root", results[0].Message)
+}
@@ -0,0 +1,301 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/collect"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+const defaultLargestImages = 5
+
+// ImageLayersDefaultOutcomes are evaluated when the analyzer sets no outcomes
+var ImageLayersDefaultOutcomes = []*troubleshootv1beta2.Outcome{
+	{
+		Warn: &troubleshootv1beta2.SingleOutcome{
+			When:    "failedImages > 0",
+			Message: "The layers of {{ .FailedImages }} could not be read, they are not included in the sizes",
+		},
+	},
+	{
+		Warn: &troubleshootv1beta2.SingleOutcome{
+			When:    "duplicatedLayers > 0",
+			Message: "{{ .Images }} images use {{ .UniqueSize }}, of which {{ .DuplicatedSize }} are layers with the same content compressed differently: {{ .DuplicatedLayers }}",
+		},
+	},
+	{
+		Pass: &troubleshootv1beta2.SingleOutcome{
+			Message: "{{ .Images }} images use {{ .UniqueSize }}, {{ .TotalSize }} without shared layers. The largest are {{ .LargestImages }}",
+		},
+	},
+}
+
+// imageLayersTemplateData is passed to the messages of the outcomes. Sizes are compressed sizes,
+// formatted with binary units.
+type imageLayersTemplateData struct {
+	// Images is the number of images whose layers were read
+	Images int
+	// FailedImages are the images whose layers could not be read, comma separated
+	FailedImages string
+	// TotalSize is the sum of the sizes of the images, counting the layers they share once per
+	// image and the tags of a manifest once, and UniqueSize the size of the distinct configs and
+	// layers, the storage they use
+	TotalSize      string
+	UniqueSize     string
+	DuplicatedSize string
+	// LargestImages are the largest images with their sizes, the largest first
+	LargestImages string
+	// DuplicatedLayers are the layers stored under several digests with the images they are in,
+	// separated by semicolons
+	DuplicatedLayers string
+
+	report *imageLayersReport
+}
+
+// imageLayersReport is the storage used by the collected images, in bytes
+type imageLayersReport struct {
+	images       []collect.ImageLayersData
+	failedImages []string
+	totalSize    int64
+	uniqueSize   int64
+	// duplicatedSize is the size of the copies of the duplicated layers beyond the largest
+	duplicatedSize   int64
+	largestImageSize int64
+	duplicatedLayers []duplicatedLayer
+}
+
+// duplicatedLayer is the content of a layer that is stored under several compressed digests
+type duplicatedLayer struct {
+	diffID string
+	images []string
+	size   int64
+}
+
+type AnalyzeImageLayers struct {
+	analyzer *troubleshootv1beta2.ImageLayersAnalyze
+}
+
+func (a *AnalyzeImageLayers) Title() string {
+	if a.analyzer.CheckName != "" {
+		return a.analyzer.CheckName
+	}
+	return "Image Layers"
+}
+
+func (a *AnalyzeImageLayers) IsExcluded() (bool, error) {
+	return isExcluded(a.analyzer.Exclude)
+}
+
+func (a *AnalyzeImageLayers) Analyze(getFile getCollectedFileContents, findFiles getChildCollectedFileContents) ([]*AnalyzeResult, error) {
+	fullPath := collect.ImageLayersOutputPath(a.analyzer.CollectorName)
+	collected, err := getFile(fullPath)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read collected file name: %s", fullPath)
+	}
+
+	info := collect.ImageLayersInfo{}
+	if err := json.Unmarshal(collected, &info); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse %s", fullPath)
+	}
+
+	largest := a.analyzer.Largest
+	if largest <= 0 {
+		largest = defaultLargestImages
+	}
+	data := newImageLayersTemplateData(newImageLayersReport(info), largest)
+
+	outcomes := a.analyzer.Outcomes
+	if len(outcomes) == 0 {
+		outcomes = ImageLayersDefaultOutcomes
+	}
+
+	result, err := analyzeTemplatedOutcomes(a.Title(), a.analyzer.Strict.BoolOrDefaultFalse(), outcomes, data, func(when string) (bool, error) {
+		return compareImageLayers(data.report, when)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		return []*AnalyzeResult{}, nil
+	}
+	return []*AnalyzeResult{result}, nil
+}
+
+// newImageLayersReport sums the sizes of the images. Tags of the same manifest are counted once,
+// and layers shared by images, with the same digest, are counted once in the unique size. Layers
+// with the same diff ID but different digests have the same content but are stored and pulled
+// once per digest.
+func newImageLayersReport(info collect.ImageLayersInfo) *imageLayersReport {
+	report := &imageLayersReport{}
+
+	manifests := map[string]bool{}
+	layers := map[string]int64{}
+	layerImages := map[string][]string{}
+	diffIDDigests := map[string][]string{}
+	for _, image := range info.Images {
+		if image.Error != "" {
+			report.failedImages = append(report.failedImages, image.Image)
+			continue
+		}
+		report.images = append(report.images, image)
+		if image.Size > report.largestImageSize {
+			report.largestImageSize = image.Size
+		}
+
+		if manifests[image.Digest] {
+			continue
+		}
+		manifests[image.Digest] = true
+		report.totalSize += image.Size
+		if image.ConfigSize > 0 {
+			report.uniqueSize += image.ConfigSize
+		}
+
+		for _, layer := range image.Layers {
+			layerImages[layer.Digest] = append(layerImages[layer.Digest], image.Image)
+			if _, ok := layers[layer.Digest]; ok {
+				continue
+			}
+			layers[layer.Digest] = layer.Size
+			if layer.Size > 0 {
+				report.uniqueSize += layer.Size
+			}
+			if layer.DiffID != "" {
+				diffIDDigests[layer.DiffID] = append(diffIDDigests[layer.DiffID], layer.Digest)
+			}
+		}
+	}
+
+	for diffID, digests := range diffIDDigests {
+		if len(digests) < 2 {
+			continue
+		}
+		duplicated := duplicatedLayer{diffID: diffID}
+		largestCopy := int64(0)
+		for _, digest := range digests {
+			size := layers[digest]
+			if size > 0 {
+				duplicated.size += size
+			}
+			if size > largestCopy {
+				largestCopy = size
+			}
+			for _, image := range layerImages[digest] {
+				if !slices.Contains(duplicated.images, image) {
+					duplicated.images = append(duplicated.images, image)
+				}
+			}
+		}
+		duplicated.size -= largestCopy
+		report.duplicatedSize += duplicated.size
+		report.duplicatedLayers = append(report.duplicatedLayers, duplicated)
+	}
+	sort.Slice(report.duplicatedLayers, func(i, j int) bool {
+		if report.duplicatedLayers[i].size != report.duplicatedLayers[j].size {
+			return report.duplicatedLayers[i].size > report.duplicatedLayers[j].size
+		}
+		return report.duplicatedLayers[i].diffID < report.duplicatedLayers[j].diffID
+	})
+
+	sort.SliceStable(report.images, func(i, j int) bool {
+		return report.images[i].Size > report.images[j].Size
+	})
+
+	return report
+}
+
+func newImageLayersTemplateData(report *imageLayersReport, largest int) *imageLayersTemplateData {
+	data := &imageLayersTemplateData{
+		Images:         len(report.images),
+		FailedImages:   strings.Join(report.failedImages, ", "),
+		TotalSize:      formatImageSize(report.totalSize),
+		UniqueSize:     formatImageSize(report.uniqueSize),
+		DuplicatedSize: formatImageSize(report.duplicatedSize),
+		report:         report,
+	}
+
+	largestImages := []string{}
+	for i := 0; i < len(report.images) && i < largest; i++ {
+		largestImages = append(largestImages, fmt.Sprintf("%s (%s)", report.images[i].Image, formatImageSize(report.images[i].Size)))
+	}
+	data.LargestImages = strings.Join(largestImages, ", ")
+
+	duplicatedLayers := []string{}
+	for _, layer := range report.duplicatedLayers {
+		duplicatedLayers = append(duplicatedLayers, fmt.Sprintf("%s in %s (%s)", shortDigest(layer.diffID), strings.Join(layer.images, ", "), formatImageSize(layer.size)))
+	}
+	data.DuplicatedLayers = strings.Join(duplicatedLayers, "; ")
+
+	return data
+}
+
+// compareImageLayers evaluates a when clause against the report. Supported conditions are the
+// counts images, failedImages and duplicatedLayers, and the sizes totalSize, uniqueSize,
+// duplicatedSize and largestImageSize, compared to quantities, e.g. "uniqueSize > 20Gi".
+func compareImageLayers(report *imageLayersReport, when string) (bool, error) {
+	parts := strings.Fields(when)
+	if len(parts) != 3 {
+		return false, fmt.Errorf("expected 3 parts in when %q, got %d", when, len(parts))
+	}
+	key, condition := parts[0], parts[1]+" "+parts[2]
+
+	switch key {
+	case "images":
+		return compareActualToWhen(condition, len(report.images))
+	case "failedImages":
+		return compareActualToWhen(condition, len(report.failedImages))
+	case "duplicatedLayers":
+		return compareActualToWhen(condition, len(report.duplicatedLayers))
+	}
+
+	var observed int64
+	switch key {
+	case "totalSize":
+		observed = report.totalSize
+	case "uniqueSize":
+		observed = report.uniqueSize
+	case "duplicatedSize":
+		observed = report.duplicatedSize
+	case "largestImageSize":
+		observed = report.largestImageSize
+	default:
+		return false, fmt.Errorf("unsupported condition %q, must be one of images, failedImages, duplicatedLayers, totalSize, uniqueSize, duplicatedSize or largestImageSize", key)
+	}
+
+	threshold, err := resource.ParseQuantity(parts[2])
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to parse quantity %q", parts[2])
+	}
+	return compareFloat(float64(observed), parts[1], float64(threshold.Value()))
+}
+
+// formatImageSize formats a size in bytes with the largest binary unit it is at least one of
+func formatImageSize(size int64) string {
+	units := []string{"KiB", "MiB", "GiB", "TiB"}
+	if size < 1024 {
+		return fmt.Sprintf("%dB", size)
+	}
+	value := float64(size) / 1024
+	unit := 0
+	for value >= 1024 && unit < len(units)-1 {
+		value /= 1024
+		unit++
+	}
+	return fmt.Sprintf("%.1f%s", value, units[unit])
+}
+
+// shortDigest returns the first 12 characters of the hex of a digest, as container runtimes
+// print image IDs
+func shortDigest(digest string) string {
+	hex := digest[strings.Index(digest, ":")+1:]
+	if len(hex) > 12 {
+		return hex[:12]
+	}
+	return hex
+}
@@ -0,0 +1,144 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"testing"
+
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/collect"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const mib = 1024 * 1024
+
+var imageLayersInfo = collect.ImageLayersInfo{
+	Platform: "linux/amd64",
+	Images: []collect.ImageLayersData{
+		{
+			Image:      "registry.example.com/app/api:1.0",
+			Digest:     "sha256:m1",
+			Size:       301*mib + 1024,
+			ConfigSize: 1024,
+			Layers: []collect.ImageLayer{
+				{Digest: "sha256:base-gzip", DiffID: "sha256:0123456789abcdef0", Size: 30 * mib},
+				{Digest: "sha256:api", DiffID: "sha256:api", Size: 271 * mib},
+			},
+		},
+		{
+			// the same manifest by another tag
+			Image:      "registry.example.com/app/api:latest",
+			Digest:     "sha256:m1",
+			Size:       301*mib + 1024,
+			ConfigSize: 1024,
+			Layers: []collect.ImageLayer{
+				{Digest: "sha256:base-gzip", DiffID: "sha256:0123456789abcdef0", Size: 30 * mib},
+				{Digest: "sha256:api", DiffID: "sha256:api", Size: 271 * mib},
+			},
+		},
+		{
+			Image:      "registry.example.com/app/worker:1.0",
+			Digest:     "sha256:m2",
+			Size:       130*mib + 1024,
+			ConfigSize: 1024,
+			Layers: []collect.ImageLayer{
+				{Digest: "sha256:base-gzip", DiffID: "sha256:0123456789abcdef0", Size: 30 * mib},
+				{Digest: "sha256:worker", DiffID: "sha256:worker", Size: 100 * mib},
+			},
+		},
+		{
+			// the same base layer compressed with zstd
+			Image:      "registry.example.com/app/web:1.0",
+			Digest:     "sha256:m3",
+			Size:       48*mib + 1024,
+			ConfigSize: 1024,
+			Layers: []collect.ImageLayer{
+				{Digest: "sha256:base-zstd", DiffID: "sha256:0123456789abcdef0", Size: 28 * mib},
+				{Digest: "sha256:web", DiffID: "sha256:web", Size: 20 * mib},
+			},
+		},
+		{
+			Image: "registry.example.com/app/missing:1.0",
+			Error: "failed to get layers: manifest unknown",
+		},
+	},
+}
+
+func Test_newImageLayersReport(t *testing.T) {
+	report := newImageLayersReport(imageLayersInfo)
+
+	assert.Equal(t, []string{"registry.example.com/app/missing:1.0"}, report.failedImages)
+	assert.Len(t, report.images, 4)
+	assert.Equal(t, int64(479*mib+3*1024), report.totalSize)
+	assert.Equal(t, int64(449*mib+3*1024), report.uniqueSize)
+	assert.Equal(t, int64(28*mib), report.duplicatedSize)
+	assert.Equal(t, int64(301*mib+1024), report.largestImageSize)
+	assert.Equal(t, []duplicatedLayer{
+		{
+			diffID: "sha256:0123456789abcdef0",
+			images: []string{"registry.example.com/app/api:1.0", "registry.example.com/app/worker:1.0", "registry.example.com/app/web:1.0"},
+			size:   28 * mib,
+		},
+	}, report.duplicatedLayers)
+
+	data := newImageLayersTemplateData(report, 2)
+	assert.Equal(t, "registry.example.com/app/api:1.0 (301.0MiB), registry.example.com/app/api:latest (301.0MiB)", data.LargestImages)
+	assert.Equal(t, "0123456789ab in registry.example.com/app/api:1.0, registry.example.com/app/worker:1.0, registry.example.com/app/web:1.0 (28.0MiB)", data.DuplicatedLayers)
+	assert.Equal(t, "449.0MiB", data.UniqueSize)
+}
+
+func Test_compareImageLayers(t *testing.T) {
+	report := newImageLayersReport(imageLayersInfo)
+
+	tests := []struct {
+		when    string
+		want    bool
+		wantErr bool
+	}{
+		{when: "images == 4", want: true},
+		{when: "failedImages > 0", want: true},
+		{when: "duplicatedLayers >= 2", want: false},
+		{when: "uniqueSize > 400Mi", want: true},
+		{when: "totalSize < 1Gi", want: true},
+		{when: "duplicatedSize > 30Mi", want: false},
+		{when: "largestImageSize >= 300Mi", want: true},
+		{when: "layers > 1", wantErr: true},
+		{when: "uniqueSize > big", wantErr: true},
+		{when: "uniqueSize", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.when, func(t *testing.T) {
+			got, err := compareImageLayers(report, tt.when)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestAnalyzeImageLayers(t *testing.T) {
+	info := imageLayersInfo
+	info.Images = info.Images[:3]
+	data, err := json.Marshal(info)
+	require.NoError(t, err)
+
+	a := AnalyzeImageLayers{analyzer: &troubleshootv1beta2.ImageLayersAnalyze{CollectorName: "app"}}
+	results, err := a.Analyze(func(path string) ([]byte, error) {
+		require.Equal(t, "registry/app-layers.json", path)
+		return data, nil
+	}, nil)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.True(t, results[0].IsPass)
+	assert.Equal(t, "Image Layers", results[0].Title)
+	assert.Equal(t, "3 images use 401.0MiB, 431.0MiB without shared layers. The largest are registry.example.com/app/api:1.0 (301.0MiB), registry.example.com/app/api:latest (301.0MiB), registry.example.com/app/worker:1.0 (130.0MiB)", results[0].Message)
+}
+
+func Test_formatImageSize(t *testing.T) {
+	assert.Equal(t, "512B", formatImageSize(512))
+	assert.Equal(t, "1.5KiB", formatImageSize(1536))
+	assert.Equal(t, "2.0GiB", formatImageSize(2*1024*mib))
+}
@@ -0,0 +1,226 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/troubleshoot/internal/util"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/collect"
+	"github.com/replicatedhq/troubleshoot/pkg/constants"
+	corev1 "k8s.io/api/core/v1"
+)
+
+var defaultImagePlatformsOutcomes = []*troubleshootv1beta2.Outcome{
+	{Fail: &troubleshootv1beta2.SingleOutcome{When: "missingPlatforms > 0", Message: "Image {{ .Image }} is not available for {{ .MissingPlatforms }}, only for {{ .Platforms }}"}},
+	{Pass: &troubleshootv1beta2.SingleOutcome{Message: "Image {{ .Image }} is available for {{ .RequiredPlatforms }}"}},
+}
+
+// imagePlatformsTemplateData is passed to the messages of the outcomes
+type imagePlatformsTemplateData struct {
+	Image             string
+	ManifestList      bool
+	Platforms         string
+	RequiredPlatforms string
+	MissingPlatforms  string
+
+	missing int
+}
+
+type AnalyzeImagePlatforms struct {
+	analyzer *troubleshootv1beta2.ImagePlatformsAnalyze
+}
+
+func (a *AnalyzeImagePlatforms) Title() string {
+	if a.analyzer.CheckName != "" {
+		return a.analyzer.CheckName
+	}
+
+	return "Image Platforms"
+}
+
+func (a *AnalyzeImagePlatforms) IsExcluded() (bool, error) {
+	return isExcluded(a.analyzer.Exclude)
+}
+
+func (a *AnalyzeImagePlatforms) Analyze(getFile getCollectedFileContents, findFiles getChildCollectedFileContents) ([]*AnalyzeResult, error) {
+	collectorName := a.analyzer.CollectorName
+	if collectorName == "" {
+		collectorName = "images"
+	}
+
+	fullPath := path.Join("registry", fmt.Sprintf("%s.json", collectorName))
+	collected, err := getFile(fullPath)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read collected file name: %s", fullPath)
+	}
+
+	registryInfo := collect.RegistryInfo{}
+	if err := json.Unmarshal(collected, &registryInfo); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal registry images")
+	}
+
+	required := a.analyzer.Platforms
+	if len(required) == 0 {
+		required, err = nodePlatforms(getFile)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	images := []string{}
+	for image, registryImage := range registryInfo.Images {
+		// the registryImages analyzer reports images that do not exist or could not be checked
+		if len(registryImage.Platforms) > 0 {
+			images = append(images, image)
+		}
+	}
+	sort.Strings(images)
+
+	outcomes := a.analyzer.Outcomes
+	if len(outcomes) == 0 {
+		outcomes = defaultImagePlatformsOutcomes
+	}
+
+	results := []*AnalyzeResult{}
+	for _, image := range images {
+		registryImage := registryInfo.Images[image]
+
+		missing := []string{}
+		for _, platform := range required {
+			if !slices.ContainsFunc(registryImage.Platforms, func(available string) bool {
+				return platformMatches(platform, available)
+			}) {
+				missing = append(missing, platform)
+			}
+		}
+
+		data := &imagePlatformsTemplateData{
+			Image:             image,
+			ManifestList:      registryImage.ManifestList,
+			Platforms:         strings.Join(registryImage.Platforms, ", "),
+			RequiredPlatforms: strings.Join(required, ", "),
+			MissingPlatforms:  strings.Join(missing, ", "),
+			missing:           len(missing),
+		}
+
+		result, err := a.analyzeImage(data, outcomes)
+		if err != nil {
+			return nil, err
+		}
+		if result != nil {
+			results = append(results, result)
+		}
+	}
+
+	return results, nil
+}
+
+// analyzeImage returns the result of the first outcome whose condition matches the image, or nil
+// when none does
+func (a *AnalyzeImagePlatforms) analyzeImage(data *imagePlatformsTemplateData, outcomes []*troubleshootv1beta2.Outcome) (*AnalyzeResult, error) {
+	for _, outcome := range outcomes {
+		result := &AnalyzeResult{
+			IconKey: "kubernetes_registry_analyze",
+			IconURI: "https://troubleshoot.sh/images/analyzer-icons/registry-analyze.svg",
+			Strict:  a.analyzer.Strict.BoolOrDefaultFalse(),
+		}
+
+		var singleOutcome *troubleshootv1beta2.SingleOutcome
+		switch {
+		case outcome.Fail != nil:
+			singleOutcome = outcome.Fail
+			result.IsFail = true
+		case outcome.Warn != nil:
+			singleOutcome = outcome.Warn
+			result.IsWarn = true
+		case outcome.Pass != nil:
+			singleOutcome = outcome.Pass
+			result.IsPass = true
+		default:
+			continue
+		}
+
+		if singleOutcome.When != "" {
+			parts := strings.Fields(singleOutcome.When)
+			if len(parts) != 3 || parts[0] != "missingPlatforms" {
+				return nil, errors.Errorf("unsupported condition %q, must be \"missingPlatforms <operator> <n>\"", singleOutcome.When)
+			}
+			match, err := compareActualToWhen(parts[1]+" "+parts[2], data.missing)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to evaluate when %q", singleOutcome.When)
+			}
+			if !match {
+				continue
+			}
+		}
+
+		var err error
+		result.Title, err = util.RenderTemplate(a.Title(), data)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to render title template")
+		}
+		result.Message, err = util.RenderTemplate(singleOutcome.Message, data)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to render message template")
+		}
+		result.URI = singleOutcome.URI
+		result.Remediation = singleOutcome.Remediation
+		result.Condition = singleOutcome.When
+		return result, nil
+	}
+
+	return nil, nil
+}
+
+// nodePlatforms returns the os/architecture of the nodes of the cluster
+func nodePlatforms(getFile getCollectedFileContents) ([]string, error) {
+	content, err := getFile(path.Join(constants.CLUSTER_RESOURCES_DIR, fmt.Sprintf("%s.json", constants.CLUSTER_RESOURCES_NODES)))
+	if err != nil || len(content) == 0 {
+		return nil, errors.New("no platforms are required and the nodes of the cluster were not collected")
+	}
+	nodes, err := decodeCollectedItems[corev1.Node](content)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal nodes")
+	}
+
+	platforms := []string{}
+	for _, node := range nodes {
+		platform := fmt.Sprintf("%s/%s", node.Status.NodeInfo.OperatingSystem, node.Status.NodeInfo.Architecture)
+		if !slices.Contains(platforms, platform) {
+			platforms = append(platforms, platform)
+		}
+	}
+	sort.Strings(platforms)
+	return platforms, nil
+}
+
+// platformMatches returns true when an image available for a platform runs on a required platform.
+// A required platform without a variant, such as those of nodes, accepts any variant, and arm64 is
+// arm64/v8 when the variant is omitted.
+func platformMatches(required string, available string) bool {
+	normalize := func(platform string) []string {
+		parts := strings.SplitN(platform, "/", 3)
+		if len(parts) == 2 && parts[1] == "arm64" {
+			parts = append(parts, "v8")
+		}
+		return parts
+	}
+
+	requiredParts, availableParts := normalize(required), normalize(available)
+	if len(requiredParts) < 2 || len(availableParts) < 2 {
+		return required == available
+	}
+	if requiredParts[0] != availableParts[0] || requiredParts[1] != availableParts[1] {
+		return false
+	}
+	if len(requiredParts) == 2 {
+		return true
+	}
+	return len(availableParts) == 3 && requiredParts[2] == availableParts[2]
+}
@@ -0,0 +1,130 @@
+package analyzer
+
+import (
+	"os"
+	"testing"
+
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyzeImagePlatforms(t *testing.T) {
+	images := `{"images": {
+	  "nginx:1.27": {"exists": true, "manifestList": true, "platforms": ["linux/amd64", "linux/arm/v7", "linux/arm64/v8"]},
+	  "registry.example.com/app:1.0": {"exists": true, "platforms": ["linux/amd64"]},
+	  "registry.example.com/missing:1.0": {"exists": false},
+	  "registry.example.com/private:1.0": {"error": "failed to get auth config"}
+	}}`
+	mixedNodes := `{"items": [
+	  {"metadata": {"name": "amd64-1"}, "status": {"nodeInfo": {"operatingSystem": "linux", "architecture": "amd64"}}},
+	  {"metadata": {"name": "arm64-1"}, "status": {"nodeInfo": {"operatingSystem": "linux", "architecture": "arm64"}}},
+	  {"metadata": {"name": "arm64-2"}, "status": {"nodeInfo": {"operatingSystem": "linux", "architecture": "arm64"}}}
+	]}`
+
+	tests := []struct {
+		name     string
+		analyzer *troubleshootv1beta2.ImagePlatformsAnalyze
+		nodes    string
+		want     []*AnalyzeResult
+		wantErr  bool
+	}{
+		{
+			name:     "platforms of the nodes",
+			analyzer: &troubleshootv1beta2.ImagePlatformsAnalyze{},
+			nodes:    mixedNodes,
+			want: []*AnalyzeResult{
+				{IsPass: true, Message: "Image nginx:1.27 is available for linux/amd64, linux/arm64"},
+				{IsFail: true, Message: "Image registry.example.com/app:1.0 is not available for linux/arm64, only for linux/amd64"},
+			},
+		},
+		{
+			name: "required platforms",
+			analyzer: &troubleshootv1beta2.ImagePlatformsAnalyze{
+				AnalyzeMeta: troubleshootv1beta2.AnalyzeMeta{CheckName: "Platforms of {{ .Image }}"},
+				Platforms:   []string{"linux/amd64", "linux/arm/v6"},
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{Warn: &troubleshootv1beta2.SingleOutcome{When: "missingPlatforms >= 1", Message: "{{ .MissingPlatforms }}"}},
+					{Pass: &troubleshootv1beta2.SingleOutcome{Message: "ok"}},
+				},
+			},
+			want: []*AnalyzeResult{
+				{IsWarn: true, Title: "Platforms of nginx:1.27", Message: "linux/arm/v6"},
+				{IsWarn: true, Title: "Platforms of registry.example.com/app:1.0", Message: "linux/arm/v6"},
+			},
+		},
+		{
+			name:     "nodes not collected",
+			analyzer: &troubleshootv1beta2.ImagePlatformsAnalyze{},
+			wantErr:  true,
+		},
+		{
+			name: "unsupported condition",
+			analyzer: &troubleshootv1beta2.ImagePlatformsAnalyze{
+				Platforms: []string{"linux/amd64"},
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{Fail: &troubleshootv1beta2.SingleOutcome{When: "manifestList == false"}},
+				},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := require.New(t)
+
+			getFile := func(path string) ([]byte, error) {
+				switch {
+				case path == "registry/images.json":
+					return []byte(images), nil
+				case path == "cluster-resources/nodes.json" && tt.nodes != "":
+					return []byte(tt.nodes), nil
+				}
+				return nil, os.ErrNotExist
+			}
+
+			a := AnalyzeImagePlatforms{analyzer: tt.analyzer}
+			results, err := a.Analyze(getFile, nil)
+			if tt.wantErr {
+				req.Error(err)
+				return
+			}
+			req.NoError(err)
+			req.Len(results, len(tt.want))
+
+			for i, want := range tt.want {
+				assert.Equal(t, want.IsPass, results[i].IsPass)
+				assert.Equal(t, want.IsWarn, results[i].IsWarn)
+				assert.Equal(t, want.IsFail, results[i].IsFail)
+				assert.Equal(t, want.Message, results[i].Message)
+				if want.Title != "" {
+					assert.Equal(t, want.Title, results[i].Title)
+				}
+			}
+		})
+	}
+}
+
+func Test_platformMatches(t *testing.T) {
+	tests := []struct {
+		required  string
+		available string
+		want      bool
+	}{
+		{required: "linux/amd64", available: "linux/amd64", want: true},
+		{required: "linux/arm64", available: "linux/arm64/v8", want: true},
+		{required: "linux/arm64/v8", available: "linux/arm64", want: true},
+		{required: "linux/arm", available: "linux/arm/v7", want: true},
+		{required: "linux/arm/v7", available: "linux/arm/v6", want: false},
+		{required: "linux/arm/v7", available: "linux/arm", want: false},
+		{required: "linux/arm64", available: "linux/amd64", want: false},
+		{required: "windows/amd64", available: "linux/amd64", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.required+" "+tt.available, func(t *testing.T) {
+			assert.Equal(t, tt.want, platformMatches(tt.required, tt.available))
+		})
+	}
+}
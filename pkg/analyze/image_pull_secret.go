@@ -61,6 +61,7 @@ func (a *AnalyzeImagePullSecret) analyzeImagePullSecret(analyzer *troubleshootv1
 	if failOutcome != nil {
 		result.Message = failOutcome.Message
 		result.URI = failOutcome.URI
+		result.Remediation = failOutcome.Remediation
 	}
 
 	for _, v := range imagePullSecrets {
@@ -76,6 +77,7 @@ func (a *AnalyzeImagePullSecret) analyzeImagePullSecret(analyzer *troubleshootv1
 				if passOutcome != nil {
 					result.Message = passOutcome.Message
 					result.URI = passOutcome.URI
+					result.Remediation = passOutcome.Remediation
 				}
 			}
 		}
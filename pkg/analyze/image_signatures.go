@@ -26,12 +26,25 @@ func (a *AnalyzeImageSignatures) IsExcluded() (bool, error) {
 }
 
 func (a *AnalyzeImageSignatures) Analyze(getFile getCollectedFileContents, findFiles getChildCollectedFileContents) ([]*AnalyzeResult, error) {
-	result, err := a.analyzeImageSignatures(getFile, findFiles)
-	if err != nil {
-		return nil, err
+	var results []*AnalyzeResult
+	if a.analyzer.DetailedResults {
+		detailed, err := a.analyzeImageSignaturesDetailed(getFile, findFiles)
+		if err != nil {
+			return nil, err
+		}
+		results = detailed
+	} else {
+		result, err := a.analyzeImageSignatures(getFile, findFiles)
+		if err != nil {
+			return nil, err
+		}
+		results = []*AnalyzeResult{result}
 	}
-	result.Strict = a.analyzer.Strict.BoolOrDefaultFalse()
-	return []*AnalyzeResult{result}, nil
+
+	for _, result := range results {
+		result.Strict = a.analyzer.Strict.BoolOrDefaultFalse()
+	}
+	return results, nil
 }
 
 // ImageSignatureData represents the collected signature data for a single image
@@ -53,15 +66,131 @@ type ImageSignaturesInfo struct {
 	Images []ImageSignatureData `json:"images"`
 }
 
+// imageSignaturesTemplateData is passed to the messages of the outcomes. The images are comma
+// separated, e.g. "{{ .Unsigned }} of {{ .Total }} images are unsigned: {{ .UnsignedImages }}". It
+// is empty when no signatures were collected.
+type imageSignaturesTemplateData struct {
+	Total          int
+	Signed         int
+	Unsigned       int
+	Errors         int
+	SignedImages   string
+	UnsignedImages string
+	ErrorImages    string
+}
+
+func newImageSignaturesTemplateData(signaturesInfo *ImageSignaturesInfo) *imageSignaturesTemplateData {
+	var signed, unsigned, errored []string
+	for _, imageData := range signaturesInfo.Images {
+		switch imageSignatureStatus(imageData) {
+		case imageSignatureSigned:
+			signed = append(signed, imageData.Image)
+		case imageSignatureUnsigned:
+			unsigned = append(unsigned, imageData.Image)
+		default:
+			errored = append(errored, imageData.Image)
+		}
+	}
+
+	return &imageSignaturesTemplateData{
+		Total:          len(signaturesInfo.Images),
+		Signed:         len(signed),
+		Unsigned:       len(unsigned),
+		Errors:         len(errored),
+		SignedImages:   strings.Join(signed, ", "),
+		UnsignedImages: strings.Join(unsigned, ", "),
+		ErrorImages:    strings.Join(errored, ", "),
+	}
+}
+
 func (a *AnalyzeImageSignatures) analyzeImageSignatures(getFile getCollectedFileContents, findFiles getChildCollectedFileContents) (*AnalyzeResult, error) {
+	signaturesInfo, err := a.collectedImageSignatures(getFile, findFiles)
+	if err != nil {
+		return nil, err
+	}
+	if signaturesInfo == nil {
+		return a.missingSignatureDataResult()
+	}
+
+	// Evaluate outcomes based on the signature analysis
+	return a.evaluateSignatureOutcomes(newImageSignaturesTemplateData(signaturesInfo))
+}
+
+// analyzeImageSignaturesDetailed returns a result for each image, in the order they were collected
+func (a *AnalyzeImageSignatures) analyzeImageSignaturesDetailed(getFile getCollectedFileContents, findFiles getChildCollectedFileContents) ([]*AnalyzeResult, error) {
+	signaturesInfo, err := a.collectedImageSignatures(getFile, findFiles)
+	if err != nil {
+		return nil, err
+	}
+	if signaturesInfo == nil {
+		result, err := a.missingSignatureDataResult()
+		if err != nil {
+			return nil, err
+		}
+		return []*AnalyzeResult{result}, nil
+	}
+
+	results := []*AnalyzeResult{}
+	for _, imageData := range signaturesInfo.Images {
+		result := &AnalyzeResult{
+			Title:   fmt.Sprintf("%s - %s", a.Title(), imageData.Image),
+			IconKey: "kubernetes_image_signatures",
+		}
+
+		switch imageSignatureStatus(imageData) {
+		case imageSignatureSigned:
+			result.IsPass = true
+			result.Message = fmt.Sprintf("Image %s has a verified signature", imageData.Image)
+		case imageSignatureUnsigned:
+			result.IsFail = true
+			result.Message = fmt.Sprintf("Image %s is not signed", imageData.Image)
+		default:
+			result.IsWarn = true
+			result.Message = fmt.Sprintf("Failed to check the signatures of image %s: %s", imageData.Image, imageData.Error)
+		}
+
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+const (
+	imageSignatureSigned   = "signed"
+	imageSignatureUnsigned = "unsigned"
+	imageSignatureError    = "error"
+)
+
+// imageSignatureStatus returns whether an image has a verified signature, is unsigned, or could
+// not be checked
+func imageSignatureStatus(imageData ImageSignatureData) string {
+	if imageData.Error != "" {
+		return imageSignatureError
+	}
+	for _, sig := range imageData.Signatures {
+		if sig.Verified && sig.Error == "" {
+			return imageSignatureSigned
+		}
+	}
+	return imageSignatureUnsigned
+}
+
+// collectedImageSignatures returns the signatures collected by the image signatures collector, or
+// nil when none were collected
+func (a *AnalyzeImageSignatures) collectedImageSignatures(getFile getCollectedFileContents, findFiles getChildCollectedFileContents) (*ImageSignaturesInfo, error) {
 	// First try to get data from a specific collector name if provided
 	var collectedData []byte
 	var err error
 	
 	if a.analyzer.CollectorName != "" {
 		collectedData, err = getFile(fmt.Sprintf("image-signatures/%s.json", a.analyzer.CollectorName))
+	} else if index := getBundleIndex(getFile); index != nil {
+		// Use the first file of an image signatures collector, in the order of the index
+		if paths := index.FindFiles("image-signatures", "", false); len(paths) > 0 {
+			collectedData, err = getFile(paths[0])
+		}
 	} else {
-		// Fallback to looking for any image signatures file
+		// Fallback to looking for any image signatures file in bundles without an index
 		files, findErr := findFiles("image-signatures/*.json", nil)
 		if findErr != nil {
 			return nil, errors.Wrap(findErr, "failed to find image signatures files")
@@ -75,25 +204,7 @@ func (a *AnalyzeImageSignatures) analyzeImageSignatures(getFile getCollectedFile
 	}
 
 	if err != nil || len(collectedData) == 0 {
-		// No data found, return error result
-		result := &AnalyzeResult{
-			Title:   a.Title(),
-			IconKey: "kubernetes_image_signatures",
-		}
-		
-		for _, outcome := range a.analyzer.Outcomes {
-			if outcome.Fail != nil {
-				result.IsFail = true
-				result.Message = outcome.Fail.Message
-				result.URI = outcome.Fail.URI
-				return result, nil
-			}
-		}
-		
-		// Default error message if no fail outcome is defined
-		result.IsFail = true
-		result.Message = "No image signature data was collected"
-		return result, nil
+		return nil, nil
 	}
 
 	// Parse the collected signature data
@@ -102,34 +213,30 @@ func (a *AnalyzeImageSignatures) analyzeImageSignatures(getFile getCollectedFile
 		return nil, errors.Wrap(err, "failed to unmarshal image signatures result")
 	}
 
-	// Count signed, unsigned, and error states
-	numSigned := 0
-	numUnsigned := 0
-	numErrors := 0
+	return signaturesInfo, nil
+}
 
-	for _, imageData := range signaturesInfo.Images {
-		if imageData.Error != "" {
-			numErrors++
-			continue
-		}
-		
-		hasValidSignature := false
-		for _, sig := range imageData.Signatures {
-			if sig.Verified && sig.Error == "" {
-				hasValidSignature = true
-				break
-			}
-		}
-		
-		if hasValidSignature {
-			numSigned++
-		} else {
-			numUnsigned++
+// missingSignatureDataResult returns the first fail outcome, when no signatures were collected
+func (a *AnalyzeImageSignatures) missingSignatureDataResult() (*AnalyzeResult, error) {
+	result := &AnalyzeResult{
+		Title:   a.Title(),
+		IconKey: "kubernetes_image_signatures",
+	}
+	
+	for _, outcome := range a.analyzer.Outcomes {
+		if outcome.Fail != nil {
+			result.IsFail = true
+			result.Message = outcome.Fail.Message
+			result.URI = outcome.Fail.URI
+			result.Remediation = outcome.Fail.Remediation
+			return renderResultMessage(result, &imageSignaturesTemplateData{})
 		}
 	}
-
-	// Evaluate outcomes based on the signature analysis
-	return a.evaluateSignatureOutcomes(numSigned, numUnsigned, numErrors)
+	
+	// Default error message if no fail outcome is defined
+	result.IsFail = true
+	result.Message = "No image signature data was collected"
+	return result, nil
 }
 
 func (a *AnalyzeImageSignatures) processSignatureData(data []byte) (*ImageSignaturesInfo, error) {
@@ -145,7 +252,7 @@ func (a *AnalyzeImageSignatures) processSignatureData(data []byte) (*ImageSignat
 	return &signaturesInfo, nil
 }
 
-func (a *AnalyzeImageSignatures) evaluateSignatureOutcomes(numSigned, numUnsigned, numErrors int) (*AnalyzeResult, error) {
+func (a *AnalyzeImageSignatures) evaluateSignatureOutcomes(data *imageSignaturesTemplateData) (*AnalyzeResult, error) {
 	result := &AnalyzeResult{
 		Title:   a.Title(),
 		IconKey: "kubernetes_image_signatures",
@@ -154,35 +261,38 @@ func (a *AnalyzeImageSignatures) evaluateSignatureOutcomes(numSigned, numUnsigne
 	// Check outcomes in order: fail, warn, pass
 	for _, outcome := range a.analyzer.Outcomes {
 		if outcome.Fail != nil {
-			if match, err := a.compareSignatureConditional(outcome.Fail.When, numSigned, numUnsigned, numErrors); err != nil {
+			if match, err := a.compareSignatureConditional(outcome.Fail.When, data); err != nil {
 				return nil, errors.Wrap(err, "failed to compare signature conditional")
 			} else if match {
 				result.IsFail = true
 				result.Message = outcome.Fail.Message
 				result.URI = outcome.Fail.URI
-				return result, nil
+				result.Remediation = outcome.Fail.Remediation
+				return renderResultMessage(result, data)
 			}
 		}
 		
 		if outcome.Warn != nil {
-			if match, err := a.compareSignatureConditional(outcome.Warn.When, numSigned, numUnsigned, numErrors); err != nil {
+			if match, err := a.compareSignatureConditional(outcome.Warn.When, data); err != nil {
 				return nil, errors.Wrap(err, "failed to compare signature conditional")
 			} else if match {
 				result.IsWarn = true
 				result.Message = outcome.Warn.Message
 				result.URI = outcome.Warn.URI
-				return result, nil
+				result.Remediation = outcome.Warn.Remediation
+				return renderResultMessage(result, data)
 			}
 		}
 		
 		if outcome.Pass != nil {
-			if match, err := a.compareSignatureConditional(outcome.Pass.When, numSigned, numUnsigned, numErrors); err != nil {
+			if match, err := a.compareSignatureConditional(outcome.Pass.When, data); err != nil {
 				return nil, errors.Wrap(err, "failed to compare signature conditional")
 			} else if match {
 				result.IsPass = true
 				result.Message = outcome.Pass.Message
 				result.URI = outcome.Pass.URI
-				return result, nil
+				result.Remediation = outcome.Pass.Remediation
+				return renderResultMessage(result, data)
 			}
 		}
 	}
@@ -190,11 +300,11 @@ func (a *AnalyzeImageSignatures) evaluateSignatureOutcomes(numSigned, numUnsigne
 	// Default to a passing result if no outcomes matched
 	result.IsPass = true
 	result.Message = fmt.Sprintf("Analyzed %d images: %d signed, %d unsigned, %d errors", 
-		numSigned+numUnsigned+numErrors, numSigned, numUnsigned, numErrors)
+		data.Total, data.Signed, data.Unsigned, data.Errors)
 	return result, nil
 }
 
-func (a *AnalyzeImageSignatures) compareSignatureConditional(conditional string, numSigned, numUnsigned, numErrors int) (bool, error) {
+func (a *AnalyzeImageSignatures) compareSignatureConditional(conditional string, data *imageSignaturesTemplateData) (bool, error) {
 	if conditional == "" {
 		return true, nil
 	}
@@ -213,11 +323,11 @@ func (a *AnalyzeImageSignatures) compareSignatureConditional(conditional string,
 	var actualValue int
 	switch field {
 	case "signed":
-		actualValue = numSigned
+		actualValue = data.Signed
 	case "unsigned":
-		actualValue = numUnsigned
+		actualValue = data.Unsigned
 	case "errors":
-		actualValue = numErrors
+		actualValue = data.Errors
 	default:
 		return false, errors.Errorf("unknown field in conditional: %s", field)
 	}
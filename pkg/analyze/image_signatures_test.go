@@ -1,9 +1,11 @@
 package analyzer
 
 import (
+	"reflect"
 	"testing"
 
 	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/constants"
 	"github.com/replicatedhq/troubleshoot/pkg/multitype"
 )
 
@@ -191,6 +193,32 @@ func TestAnalyzeImageSignatures_Analyze(t *testing.T) {
 			wantFail:    true,
 			wantMessage: "No signatures found for images",
 		},
+		{
+			name: "templated_message",
+			analyzer: &troubleshootv1beta2.ImageSignaturesAnalyze{
+				AnalyzeMeta: troubleshootv1beta2.AnalyzeMeta{
+					CheckName: "signature-check",
+				},
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{
+						Fail: &troubleshootv1beta2.SingleOutcome{
+							When:    "unsigned > 0",
+							Message: "{{ .Unsigned }} of {{ .Total }} images are unsigned: {{ .UnsignedImages }}",
+						},
+					},
+				},
+			},
+			collectedData: `{
+				"images": [
+					{"image": "nginx:1.27", "signatures": [{"verified": true}]},
+					{"image": "redis:7"},
+					{"image": "postgres:16", "signatures": [{"verified": false}]}
+				]
+			}`,
+			want:        1,
+			wantFail:    true,
+			wantMessage: "2 of 3 images are unsigned: redis:7, postgres:16",
+		},
 		{
 			name: "missing_collected_data",
 			analyzer: &troubleshootv1beta2.ImageSignaturesAnalyze{
@@ -264,3 +292,93 @@ func TestAnalyzeImageSignatures_Analyze(t *testing.T) {
 	}
 }
 
+
+func TestAnalyzeImageSignatures_BundleIndex(t *testing.T) {
+	a := &AnalyzeImageSignatures{
+		analyzer: &troubleshootv1beta2.ImageSignaturesAnalyze{},
+	}
+
+	getFile := func(filename string) ([]byte, error) {
+		switch filename {
+		case constants.BUNDLE_INDEX_FILENAME:
+			return []byte(`{"files": [
+				{"path": "image-signatures/other.json", "collector": "configmap"},
+				{"path": "image-signatures/release.json", "collector": "image-signatures", "name": "release"}
+			]}`), nil
+		case "image-signatures/release.json":
+			return []byte(`{"images": [{"image": "nginx:latest", "signatures": [{"verified": true}]}]}`), nil
+		}
+		return nil, nil
+	}
+
+	findFiles := func(pattern string, excludePatterns []string) (map[string][]byte, error) {
+		t.Errorf("unexpected search for %q in a bundle with an index", pattern)
+		return nil, nil
+	}
+
+	result, err := a.analyzeImageSignatures(getFile, findFiles)
+	if err != nil {
+		t.Fatalf("analyzeImageSignatures() error = %v", err)
+	}
+	if !result.IsPass {
+		t.Errorf("Expected pass result, got %+v", result)
+	}
+	if want := "Analyzed 1 images: 1 signed, 0 unsigned, 0 errors"; result.Message != want {
+		t.Errorf("Expected message %q, got %q", want, result.Message)
+	}
+}
+
+func TestAnalyzeImageSignatures_DetailedResults(t *testing.T) {
+	a := &AnalyzeImageSignatures{
+		analyzer: &troubleshootv1beta2.ImageSignaturesAnalyze{
+			CollectorName:   "release",
+			DetailedResults: true,
+			AnalyzeMeta: troubleshootv1beta2.AnalyzeMeta{
+				Strict: &multitype.BoolOrString{Type: multitype.Bool, BoolVal: true},
+			},
+		},
+	}
+
+	getFile := func(filename string) ([]byte, error) {
+		if filename == "image-signatures/release.json" {
+			return []byte(`{"images": [
+				{"image": "nginx:1.27", "signatures": [{"verified": false, "error": "bad signature"}, {"verified": true}]},
+				{"image": "redis:7", "signatures": [{"verified": false, "error": "no signatures found for this image"}]},
+				{"image": "private/app:1.0", "error": "unauthorized"}
+			]}`), nil
+		}
+		return nil, nil
+	}
+
+	results, err := a.Analyze(getFile, nil)
+	if err != nil {
+		t.Fatalf("AnalyzeImageSignatures.Analyze() error = %v", err)
+	}
+
+	want := []*AnalyzeResult{
+		{
+			Title:   "Image Signatures - nginx:1.27",
+			IsPass:  true,
+			Strict:  true,
+			Message: "Image nginx:1.27 has a verified signature",
+			IconKey: "kubernetes_image_signatures",
+		},
+		{
+			Title:   "Image Signatures - redis:7",
+			IsFail:  true,
+			Strict:  true,
+			Message: "Image redis:7 is not signed",
+			IconKey: "kubernetes_image_signatures",
+		},
+		{
+			Title:   "Image Signatures - private/app:1.0",
+			IsWarn:  true,
+			Strict:  true,
+			Message: "Failed to check the signatures of image private/app:1.0: unauthorized",
+			IconKey: "kubernetes_image_signatures",
+		},
+	}
+	if !reflect.DeepEqual(results, want) {
+		t.Errorf("AnalyzeImageSignatures.Analyze() = %+v, want %+v", results, want)
+	}
+}
@@ -60,6 +60,7 @@ func (a *AnalyzeIngress) analyzeIngress(analyzer *troubleshootv1beta2.Ingress, g
 				if outcome.Pass != nil {
 					result.Message = outcome.Pass.Message
 					result.URI = outcome.Pass.URI
+					result.Remediation = outcome.Pass.Remediation
 				}
 			}
 
@@ -72,6 +73,7 @@ func (a *AnalyzeIngress) analyzeIngress(analyzer *troubleshootv1beta2.Ingress, g
 		if outcome.Fail != nil {
 			result.Message = outcome.Fail.Message
 			result.URI = outcome.Fail.URI
+			result.Remediation = outcome.Fail.Remediation
 		}
 	}
 
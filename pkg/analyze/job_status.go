@@ -95,6 +95,9 @@ func analyzeOneJobStatus(analyzer *troubleshootv1beta2.JobStatus, getFileContent
 			if err != nil {
 				return nil, errors.Wrap(err, "failed to process status")
 			}
+			if result, err = renderResultMessage(result, newJobStatusTemplateData(job)); err != nil {
+				return nil, err
+			}
 		} else {
 			result = getDefaultJobResult(job)
 		}
@@ -147,6 +150,32 @@ func analyzeAllJobStatuses(analyzer *troubleshootv1beta2.JobStatus, getFileConte
 	return results, nil
 }
 
+// jobStatusTemplateData is passed to the messages of the outcomes. Completions is the number of
+// pods of the spec of the job that must succeed.
+type jobStatusTemplateData struct {
+	Namespace   string
+	Name        string
+	Completions int
+	Succeeded   int
+	Failed      int
+	Active      int
+}
+
+func newJobStatusTemplateData(job *batchv1.Job) *jobStatusTemplateData {
+	data := &jobStatusTemplateData{
+		Namespace:   job.Namespace,
+		Name:        job.Name,
+		Completions: 1, // default is 1
+		Succeeded:   int(job.Status.Succeeded),
+		Failed:      int(job.Status.Failed),
+		Active:      int(job.Status.Active),
+	}
+	if job.Spec.Completions != nil {
+		data.Completions = int(*job.Spec.Completions)
+	}
+	return data
+}
+
 func jobStatus(outcomes []*troubleshootv1beta2.Outcome, job *batchv1.Job) (*AnalyzeResult, error) {
 	result := &AnalyzeResult{
 		Title:   fmt.Sprintf("%s Status", job.Name),
@@ -161,6 +190,7 @@ func jobStatus(outcomes []*troubleshootv1beta2.Outcome, job *batchv1.Job) (*Anal
 				result.IsFail = true
 				result.Message = outcome.Fail.Message
 				result.URI = outcome.Fail.URI
+				result.Remediation = outcome.Fail.Remediation
 
 				return result, nil
 			}
@@ -174,6 +204,7 @@ func jobStatus(outcomes []*troubleshootv1beta2.Outcome, job *batchv1.Job) (*Anal
 				result.IsFail = true
 				result.Message = outcome.Fail.Message
 				result.URI = outcome.Fail.URI
+				result.Remediation = outcome.Fail.Remediation
 
 				return result, nil
 			}
@@ -182,6 +213,7 @@ func jobStatus(outcomes []*troubleshootv1beta2.Outcome, job *batchv1.Job) (*Anal
 				result.IsWarn = true
 				result.Message = outcome.Warn.Message
 				result.URI = outcome.Warn.URI
+				result.Remediation = outcome.Warn.Remediation
 
 				return result, nil
 			}
@@ -195,6 +227,7 @@ func jobStatus(outcomes []*troubleshootv1beta2.Outcome, job *batchv1.Job) (*Anal
 				result.IsWarn = true
 				result.Message = outcome.Warn.Message
 				result.URI = outcome.Warn.URI
+				result.Remediation = outcome.Warn.Remediation
 
 				return result, nil
 			}
@@ -203,6 +236,7 @@ func jobStatus(outcomes []*troubleshootv1beta2.Outcome, job *batchv1.Job) (*Anal
 				result.IsPass = true
 				result.Message = outcome.Pass.Message
 				result.URI = outcome.Pass.URI
+				result.Remediation = outcome.Pass.Remediation
 
 				return result, nil
 			}
@@ -216,6 +250,7 @@ func jobStatus(outcomes []*troubleshootv1beta2.Outcome, job *batchv1.Job) (*Anal
 				result.IsPass = true
 				result.Message = outcome.Pass.Message
 				result.URI = outcome.Pass.URI
+				result.Remediation = outcome.Pass.Remediation
 
 				return result, nil
 			}
@@ -50,6 +50,35 @@ func Test_JobStatus(t *testing.T) {
 				"cluster-resources/jobs/projectcontour.json": []byte(projectcontourJobs),
 			},
 		},
+		{
+			name: "failed job, templated message",
+			analyzer: troubleshootv1beta2.JobStatus{
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{
+						Fail: &troubleshootv1beta2.SingleOutcome{
+							When:    "failed > 0",
+							Message: "{{ .Name }} failed {{ .Failed }} times and succeeded {{ .Succeeded }} of {{ .Completions }} times",
+						},
+					},
+				},
+				Namespace: "test",
+				Name:      "post-install-job",
+			},
+			expectResult: []*AnalyzeResult{
+				{
+					IsPass:  false,
+					IsWarn:  false,
+					IsFail:  true,
+					Title:   "post-install-job Status",
+					Message: "post-install-job failed 1 times and succeeded 0 of 1 times",
+					IconKey: "kubernetes_deployment_status",
+					IconURI: "https://troubleshoot.sh/images/analyzer-icons/deployment-status.svg?w=17&h=17",
+				},
+			},
+			files: map[string][]byte{
+				"cluster-resources/jobs/test.json": []byte(testJobs),
+			},
+		},
 		{
 			name: "1/1, fail when < 2",
 			analyzer: troubleshootv1beta2.JobStatus{
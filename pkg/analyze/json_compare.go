@@ -70,30 +70,9 @@ func analyzeJsonCompare(analyzer *troubleshootv1beta2.JsonCompare, getCollectedF
 			return nil, errors.Wrapf(err, "failed to get object at path: %s", analyzer.Path)
 		}
 	} else if analyzer.JsonPath != "" {
-		jsp := jsonpath.New(analyzer.CheckName)
-		jsp.AllowMissingKeys(true).EnableJSONOutput(true)
-		err = jsp.Parse(analyzer.JsonPath)
+		actual, err = evaluateJSONPath(analyzer.CheckName, analyzer.JsonPath, actual)
 		if err != nil {
-			return nil, errors.Wrapf(err, "failed to parse jsonpath: %s", analyzer.JsonPath)
-		}
-
-		var data bytes.Buffer
-		err = jsp.Execute(&data, actual)
-		if err != nil {
-			return nil, errors.Wrap(err, "failed to execute jsonpath")
-		}
-
-		err = json.NewDecoder(&data).Decode(&actual)
-		if err != nil {
-			return nil, errors.Wrap(err, "failed to decode jsonpath result")
-		}
-
-		// If we get back a single result in a slice unwrap it.
-		// Technically this doesn't strictly follow jsonpath, but it makes
-		// things easier downstream. Basically we don't want to require
-		// users to wrap a single result with [].
-		if a, ok := actual.([]interface{}); ok && len(a) == 1 {
-			actual = a[0]
+			return nil, err
 		}
 	}
 
@@ -131,6 +110,7 @@ func analyzeJsonCompare(analyzer *troubleshootv1beta2.JsonCompare, getCollectedF
 				result.IsFail = true
 				result.Message = outcome.Fail.Message
 				result.URI = outcome.Fail.URI
+				result.Remediation = outcome.Fail.Remediation
 
 				return result, nil
 			}
@@ -152,6 +132,7 @@ func analyzeJsonCompare(analyzer *troubleshootv1beta2.JsonCompare, getCollectedF
 				result.IsWarn = true
 				result.Message = outcome.Warn.Message
 				result.URI = outcome.Warn.URI
+				result.Remediation = outcome.Warn.Remediation
 
 				return result, nil
 			}
@@ -173,6 +154,7 @@ func analyzeJsonCompare(analyzer *troubleshootv1beta2.JsonCompare, getCollectedF
 				result.IsPass = true
 				result.Message = outcome.Pass.Message
 				result.URI = outcome.Pass.URI
+				result.Remediation = outcome.Pass.Remediation
 
 				return result, nil
 			}
@@ -188,6 +170,37 @@ func analyzeJsonCompare(analyzer *troubleshootv1beta2.JsonCompare, getCollectedF
 	}, nil
 }
 
+// evaluateJSONPath returns the result of a JSONPath over data decoded from JSON
+func evaluateJSONPath(name string, expression string, actual interface{}) (interface{}, error) {
+	jsp := jsonpath.New(name)
+	jsp.AllowMissingKeys(true).EnableJSONOutput(true)
+	err := jsp.Parse(expression)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse jsonpath: %s", expression)
+	}
+
+	var data bytes.Buffer
+	err = jsp.Execute(&data, actual)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to execute jsonpath")
+	}
+
+	err = json.NewDecoder(&data).Decode(&actual)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode jsonpath result")
+	}
+
+	// If we get back a single result in a slice unwrap it.
+	// Technically this doesn't strictly follow jsonpath, but it makes
+	// things easier downstream. Basically we don't want to require
+	// users to wrap a single result with [].
+	if a, ok := actual.([]interface{}); ok && len(a) == 1 {
+		actual = a[0]
+	}
+
+	return actual, nil
+}
+
 // deepEqualWithSlicesSorted compares two interfaces and returns true if they contain the same values
 // If the interfaces are slices, they are sorted before comparison to ensure order does not matter
 // If the interfaces are not slices, reflect.DeepEqual is used
@@ -0,0 +1,259 @@
+package analyzer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/blang/semver/v4"
+	"github.com/itchyny/gojq"
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+)
+
+// jqTimeout bounds the evaluation of a jq expression, which can loop forever
+const jqTimeout = 10 * time.Second
+
+// jsonQueryTemplateData is passed to the messages of the outcomes
+type jsonQueryTemplateData struct {
+	// Value is the result of the query, strings as they are and other values as JSON
+	Value string
+	// Result is the result of the query as decoded from JSON, e.g. for {{ .Result.name }}
+	Result interface{}
+}
+
+type AnalyzeJSONQuery struct {
+	analyzer *troubleshootv1beta2.JSONQueryAnalyze
+}
+
+func (a *AnalyzeJSONQuery) Title() string {
+	if a.analyzer.CheckName != "" {
+		return a.analyzer.CheckName
+	}
+	if a.analyzer.CollectorName != "" {
+		return a.analyzer.CollectorName
+	}
+
+	return "JSON Query"
+}
+
+func (a *AnalyzeJSONQuery) IsExcluded() (bool, error) {
+	return isExcluded(a.analyzer.Exclude)
+}
+
+func (a *AnalyzeJSONQuery) Analyze(getFile getCollectedFileContents, findFiles getChildCollectedFileContents) ([]*AnalyzeResult, error) {
+	if (a.analyzer.JQ == "") == (a.analyzer.JSONPath == "") {
+		return nil, errors.New("json query analyzer requires one of jq or jsonPath")
+	}
+
+	fullPath := filepath.Join(a.analyzer.CollectorName, a.analyzer.FileName)
+	collected, err := getFile(fullPath)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read collected file name: %s", fullPath)
+	}
+
+	var input interface{}
+	if err := json.Unmarshal(collected, &input); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse %s as json", fullPath)
+	}
+
+	var actual interface{}
+	if a.analyzer.JQ != "" {
+		actual, err = evaluateJQ(a.analyzer.JQ, input)
+	} else {
+		actual, err = evaluateJSONPath(a.Title(), a.analyzer.JSONPath, input)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// results are compared as decoded from JSON, so that e.g. the integers of jq are float64
+	actual, err = normalizeJSON(actual)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to normalize query result")
+	}
+
+	data := &jsonQueryTemplateData{Result: actual}
+	data.Value, err = formatJSONQueryValue(actual)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to format query result")
+	}
+
+	result, err := analyzeTemplatedOutcomes(a.Title(), a.analyzer.Strict.BoolOrDefaultFalse(), a.analyzer.Outcomes, data, func(when string) (bool, error) {
+		return compareJSONQueryConditionalToActual(when, actual)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		return []*AnalyzeResult{}, nil
+	}
+	return []*AnalyzeResult{result}, nil
+}
+
+// evaluateJQ returns the output of a jq expression, null when it has none and an array when it
+// has more than one
+func evaluateJQ(expression string, input interface{}) (interface{}, error) {
+	query, err := gojq.Parse(expression)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse jq expression: %s", expression)
+	}
+	code, err := gojq.Compile(query)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to compile jq expression: %s", expression)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), jqTimeout)
+	defer cancel()
+
+	outputs := []interface{}{}
+	iter := code.RunWithContext(ctx, input)
+	for {
+		output, ok := iter.Next()
+		if !ok {
+			break
+		}
+		if err, ok := output.(error); ok {
+			return nil, errors.Wrap(err, "failed to evaluate jq expression")
+		}
+		outputs = append(outputs, output)
+	}
+
+	switch len(outputs) {
+	case 0:
+		return nil, nil
+	case 1:
+		return outputs[0], nil
+	default:
+		return outputs, nil
+	}
+}
+
+func normalizeJSON(value interface{}) (interface{}, error) {
+	b, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var normalized interface{}
+	if err := json.Unmarshal(b, &normalized); err != nil {
+		return nil, err
+	}
+	return normalized, nil
+}
+
+func formatJSONQueryValue(value interface{}) (string, error) {
+	if s, ok := value.(string); ok {
+		return s, nil
+	}
+	b, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// compareJSONQueryConditionalToActual compares the result of a query with the value of a when
+// clause, e.g. ">= 3". The value is parsed as JSON, e.g. "== true" or `== "a b"`, and is a string
+// otherwise. Numbers are ordered as numbers and strings as versions. contains checks a substring
+// of a string, an element of an array or a key of an object, and matches a regular expression.
+func compareJSONQueryConditionalToActual(conditional string, actual interface{}) (bool, error) {
+	parts := strings.SplitN(strings.TrimSpace(conditional), " ", 2)
+	if len(parts) != 2 {
+		return false, errors.Errorf("unable to parse conditional %q", conditional)
+	}
+	operator, value := parts[0], strings.TrimSpace(parts[1])
+
+	if operator == "matches" {
+		re, err := regexp.Compile(value)
+		if err != nil {
+			return false, errors.Wrapf(err, "failed to compile regex %q", value)
+		}
+		s, err := formatJSONQueryValue(actual)
+		if err != nil {
+			return false, errors.Wrap(err, "failed to format query result")
+		}
+		return re.MatchString(s), nil
+	}
+
+	var expected interface{}
+	if err := json.Unmarshal([]byte(value), &expected); err != nil {
+		expected = value
+	}
+
+	switch operator {
+	case "=", "==", "===":
+		return deepEqualWithSlicesSorted(actual, expected), nil
+	case "!=", "!==":
+		return !deepEqualWithSlicesSorted(actual, expected), nil
+	case "<", "<=", ">", ">=":
+		cmp, err := compareJSONQueryOrder(actual, expected)
+		if err != nil {
+			return false, err
+		}
+		switch operator {
+		case "<":
+			return cmp < 0, nil
+		case "<=":
+			return cmp <= 0, nil
+		case ">":
+			return cmp > 0, nil
+		default:
+			return cmp >= 0, nil
+		}
+	case "contains":
+		return jsonQueryContains(actual, expected), nil
+	}
+	return false, errors.Errorf("unknown operator %q, must be one of ==, !=, <, <=, >, >=, contains or matches", operator)
+}
+
+// compareJSONQueryOrder returns -1, 0 or 1 when the actual value is lower than, equal to or
+// greater than the expected one
+func compareJSONQueryOrder(actual interface{}, expected interface{}) (int, error) {
+	switch a := actual.(type) {
+	case float64:
+		e, ok := expected.(float64)
+		if !ok {
+			return 0, errors.Errorf("cannot compare number %v with %v", a, expected)
+		}
+		switch {
+		case a < e:
+			return -1, nil
+		case a > e:
+			return 1, nil
+		}
+		return 0, nil
+	case string:
+		e := fmt.Sprintf("%v", expected)
+		actualVersion, err := semver.ParseTolerant(a)
+		if err != nil {
+			return 0, errors.Wrapf(err, "cannot compare %q, it is not a number or a version", a)
+		}
+		expectedVersion, err := semver.ParseTolerant(e)
+		if err != nil {
+			return 0, errors.Wrapf(err, "cannot compare with %q, it is not a version", e)
+		}
+		return actualVersion.Compare(expectedVersion), nil
+	}
+	return 0, errors.Errorf("cannot compare %v, it is not a number or a version", actual)
+}
+
+func jsonQueryContains(actual interface{}, expected interface{}) bool {
+	switch a := actual.(type) {
+	case string:
+		return strings.Contains(a, fmt.Sprintf("%v", expected))
+	case []interface{}:
+		for _, element := range a {
+			if deepEqualWithSlicesSorted(element, expected) {
+				return true
+			}
+		}
+	case map[string]interface{}:
+		_, ok := a[fmt.Sprintf("%v", expected)]
+		return ok
+	}
+	return false
+}
@@ -0,0 +1,125 @@
+package analyzer
+
+import (
+	"testing"
+
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyzeJSONQuery(t *testing.T) {
+	collected := []byte(`{
+  "version": "v1.4.2",
+  "status": "ready",
+  "replicas": 2,
+  "users": [
+    {"name": "admin", "roles": ["admin"]},
+    {"name": "app", "roles": ["read", "write"]}
+  ]
+}`)
+
+	outcomes := func(when string) []*troubleshootv1beta2.Outcome {
+		return []*troubleshootv1beta2.Outcome{
+			{Pass: &troubleshootv1beta2.SingleOutcome{When: when, Message: "matched {{ .Value }}"}},
+			{Fail: &troubleshootv1beta2.SingleOutcome{Message: "not matched {{ .Value }}"}},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		analyzer *troubleshootv1beta2.JSONQueryAnalyze
+		want     *AnalyzeResult
+		wantErr  string
+	}{
+		{
+			name:     "jq number",
+			analyzer: &troubleshootv1beta2.JSONQueryAnalyze{JQ: ".users | length", Outcomes: outcomes(">= 2")},
+			want:     &AnalyzeResult{IsPass: true, Message: "matched 2"},
+		},
+		{
+			name:     "jq string",
+			analyzer: &troubleshootv1beta2.JSONQueryAnalyze{JQ: ".status", Outcomes: outcomes("== ready")},
+			want:     &AnalyzeResult{IsPass: true, Message: "matched ready"},
+		},
+		{
+			name:     "jq quoted string",
+			analyzer: &troubleshootv1beta2.JSONQueryAnalyze{JQ: ".status", Outcomes: outcomes(`!= "ready"`)},
+			want:     &AnalyzeResult{IsFail: true, Message: "not matched ready"},
+		},
+		{
+			name:     "jq multiple outputs",
+			analyzer: &troubleshootv1beta2.JSONQueryAnalyze{JQ: ".users[].name", Outcomes: outcomes("contains admin")},
+			want:     &AnalyzeResult{IsPass: true, Message: `matched ["admin","app"]`},
+		},
+		{
+			name:     "jq no output",
+			analyzer: &troubleshootv1beta2.JSONQueryAnalyze{JQ: ".users[] | select(.name == \"root\")", Outcomes: outcomes("== null")},
+			want:     &AnalyzeResult{IsPass: true, Message: "matched null"},
+		},
+		{
+			name:     "version",
+			analyzer: &troubleshootv1beta2.JSONQueryAnalyze{JQ: ".version", Outcomes: outcomes(">= 1.5.0")},
+			want:     &AnalyzeResult{IsFail: true, Message: "not matched v1.4.2"},
+		},
+		{
+			name:     "matches",
+			analyzer: &troubleshootv1beta2.JSONQueryAnalyze{JQ: ".version", Outcomes: outcomes(`matches ^v1\.4\.`)},
+			want:     &AnalyzeResult{IsPass: true, Message: "matched v1.4.2"},
+		},
+		{
+			name:     "jsonPath",
+			analyzer: &troubleshootv1beta2.JSONQueryAnalyze{JSONPath: "{.users[?(@.name==\"app\")].roles}", Outcomes: outcomes("contains write")},
+			want:     &AnalyzeResult{IsPass: true, Message: `matched ["read","write"]`},
+		},
+		{
+			name: "result template",
+			analyzer: &troubleshootv1beta2.JSONQueryAnalyze{JQ: ".users[0]", Outcomes: []*troubleshootv1beta2.Outcome{
+				{Warn: &troubleshootv1beta2.SingleOutcome{When: "contains roles", Message: "{{ .Result.name }} has roles"}},
+			}},
+			want: &AnalyzeResult{IsWarn: true, Message: "admin has roles"},
+		},
+		{
+			name:     "both jq and jsonPath",
+			analyzer: &troubleshootv1beta2.JSONQueryAnalyze{JQ: ".status", JSONPath: "{.status}"},
+			wantErr:  "json query analyzer requires one of jq or jsonPath",
+		},
+		{
+			name:     "invalid jq",
+			analyzer: &troubleshootv1beta2.JSONQueryAnalyze{JQ: ".users[", Outcomes: outcomes("== 1")},
+			wantErr:  "failed to parse jq expression: .users[",
+		},
+		{
+			name:     "unordered comparison",
+			analyzer: &troubleshootv1beta2.JSONQueryAnalyze{JQ: ".users", Outcomes: outcomes("> 1")},
+			wantErr:  "failed to evaluate when \"> 1\"",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.analyzer.CollectorName = "app-status"
+			tt.analyzer.FileName = "status.json"
+			getFile := func(filename string) ([]byte, error) {
+				require.Equal(t, "app-status/status.json", filename)
+				return collected, nil
+			}
+
+			a := AnalyzeJSONQuery{analyzer: tt.analyzer}
+			results, err := a.Analyze(getFile, nil)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, results, 1)
+
+			assert.Equal(t, tt.want.IsPass, results[0].IsPass)
+			assert.Equal(t, tt.want.IsWarn, results[0].IsWarn)
+			assert.Equal(t, tt.want.IsFail, results[0].IsFail)
+			assert.Equal(t, "app-status", results[0].Title)
+			assert.Equal(t, tt.want.Message, results[0].Message)
+		})
+	}
+}
@@ -20,7 +20,8 @@ type AnalyzeNodeMetrics struct {
 }
 
 type nodeMetricsComparisonResults struct {
-	PVC pvcTemplateData
+	PVC     pvcTemplateData
+	ImageFs imageFsTemplateData
 }
 
 type pvcTemplateData struct {
@@ -29,6 +30,11 @@ type pvcTemplateData struct {
 	Names             []string
 }
 
+type imageFsTemplateData struct {
+	ConcatenatedNames string
+	Names             []string
+}
+
 type pvcUsageStats struct {
 	PvcName string
 	Used    float64
@@ -92,6 +98,7 @@ func (a *AnalyzeNodeMetrics) compareCollectedMetricsWithOutcomes(summaries []kub
 				result.IsFail = true
 				result.Message = outcome.Fail.Message
 				result.URI = outcome.Fail.URI
+				result.Remediation = outcome.Fail.Remediation
 
 				return result, nil
 			} else {
@@ -104,6 +111,7 @@ func (a *AnalyzeNodeMetrics) compareCollectedMetricsWithOutcomes(summaries []kub
 					result.IsFail = true
 					result.Message = renderTemplate(outcome.Fail.Message, out)
 					result.URI = outcome.Fail.URI
+					result.Remediation = outcome.Fail.Remediation
 
 					return result, nil
 				}
@@ -114,6 +122,7 @@ func (a *AnalyzeNodeMetrics) compareCollectedMetricsWithOutcomes(summaries []kub
 				result.IsWarn = true
 				result.Message = outcome.Warn.Message
 				result.URI = outcome.Warn.URI
+				result.Remediation = outcome.Warn.Remediation
 
 				return result, nil
 			} else {
@@ -126,6 +135,7 @@ func (a *AnalyzeNodeMetrics) compareCollectedMetricsWithOutcomes(summaries []kub
 					result.IsWarn = true
 					result.Message = renderTemplate(outcome.Warn.Message, out)
 					result.URI = outcome.Warn.URI
+					result.Remediation = outcome.Warn.Remediation
 
 					return result, nil
 				}
@@ -135,6 +145,7 @@ func (a *AnalyzeNodeMetrics) compareCollectedMetricsWithOutcomes(summaries []kub
 				result.IsPass = true
 				result.Message = outcome.Pass.Message
 				result.URI = outcome.Pass.URI
+				result.Remediation = outcome.Pass.Remediation
 
 				return result, nil
 			} else {
@@ -147,6 +158,7 @@ func (a *AnalyzeNodeMetrics) compareCollectedMetricsWithOutcomes(summaries []kub
 					result.IsPass = true
 					result.Message = renderTemplate(outcome.Pass.Message, out)
 					result.URI = outcome.Pass.URI
+					result.Remediation = outcome.Pass.Remediation
 
 					return result, nil
 				}
@@ -219,7 +231,8 @@ func (a *AnalyzeNodeMetrics) findPVCUsageStats(summaries []kubeletv1alpha1.Summa
 }
 
 // compareNodeMetricConditionalsToStats compares the conditional with the collected node metrics
-// and returns true if the conditional is met. At the moment we only support comparing PVC usage
+// and returns true if the conditional is met. At the moment we support comparing PVC usage and the
+// usage of the image filesystems of the container runtimes of the nodes
 func (a *AnalyzeNodeMetrics) compareNodeMetricConditionalsToStats(conditional string, summaries []kubeletv1alpha1.Summary) (bool, nodeMetricsComparisonResults, error) {
 	klog.V(2).Infof("Comparing node metrics with conditional: %s", conditional)
 	parts := strings.Split(strings.TrimSpace(conditional), " ")
@@ -288,6 +301,44 @@ func (a *AnalyzeNodeMetrics) compareNodeMetricConditionalsToStats(conditional st
 			ConcatenatedNames: strings.Join(matchedPVCs, ", "),
 		}
 		return len(matchedPVCs) > 0, out, nil
+	case "imageFsUsedPercentage":
+		// e.g imageFsUsedPercentage >= 85
+
+		klog.V(2).Infof("Analyzing image filesystem usage stats for nodes")
+
+		expected, err := strconv.ParseFloat(parts[2], 64)
+		if err != nil {
+			return false, out, errors.Wrap(err, "failed to parse float")
+		}
+
+		matchedNodes := []string{}
+		for _, summary := range summaries {
+			if summary.Node.Runtime == nil || summary.Node.Runtime.ImageFs == nil {
+				klog.V(2).Infof("Missing image filesystem stats for node %s", summary.Node.NodeName)
+				continue
+			}
+			imageFs := summary.Node.Runtime.ImageFs
+			if imageFs.UsedBytes == nil || imageFs.CapacityBytes == nil || *imageFs.CapacityBytes == 0 {
+				klog.V(2).Infof("Missing capacity or used bytes for the image filesystem of node %s", summary.Node.NodeName)
+				continue
+			}
+
+			imageFsUsedPercentage := float64(*imageFs.UsedBytes) / float64(*imageFs.CapacityBytes) * 100
+			klog.V(2).Infof("Image filesystem usage for %s: %0.2f%%", summary.Node.NodeName, imageFsUsedPercentage)
+			isMatch, err := compareFloat(imageFsUsedPercentage, parts[1], expected)
+			if err != nil {
+				return false, out, errors.Wrap(err, "failed to compare image filesystem usage")
+			}
+			if isMatch {
+				matchedNodes = append(matchedNodes, summary.Node.NodeName)
+			}
+		}
+
+		out.ImageFs = imageFsTemplateData{
+			Names:             matchedNodes,
+			ConcatenatedNames: strings.Join(matchedNodes, ", "),
+		}
+		return len(matchedNodes) > 0, out, nil
 	}
 
 	return false, out, errors.New("unknown node metric conditional")
@@ -267,6 +267,42 @@ func TestAnalyzeNodeMetrics_Analyze(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "image filesystem usage",
+			analyzer: troubleshootv1beta2.NodeMetricsAnalyze{
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{
+						Warn: &troubleshootv1beta2.SingleOutcome{
+							When:    "imageFsUsedPercentage >= 85",
+							Message: "The image filesystems of nodes [{{ .ImageFs.ConcatenatedNames }}] are nearly full",
+						},
+					},
+					{
+						Pass: &troubleshootv1beta2.SingleOutcome{
+							Message: "No image filesystem is nearly full",
+						},
+					},
+				},
+			},
+			nodeMetrics: `{
+				"node": {
+				  "nodeName": "node-1",
+				  "runtime": {
+					"imageFs": {
+					  "capacityBytes": 100,
+					  "usedBytes": 90
+					}
+				  }
+				}
+			  }`,
+			want: []*AnalyzeResult{
+				{
+					Title:   "Node Metrics",
+					IsWarn:  true,
+					Message: "The image filesystems of nodes [node-1] are nearly full",
+				},
+			},
+		},
 	}
 
 	for _, tt := range tests {
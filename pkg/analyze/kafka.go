@@ -0,0 +1,203 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/troubleshoot/internal/util"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/collect"
+)
+
+var defaultKafkaOutcomes = []*troubleshootv1beta2.Outcome{
+	{Fail: &troubleshootv1beta2.SingleOutcome{When: "connected == false", Message: "Cannot connect to Kafka: {{ .Error }}"}},
+	{Fail: &troubleshootv1beta2.SingleOutcome{When: "offlinePartitions > 0", Message: "{{ .OfflinePartitions }} Kafka partitions have no leader"}},
+	{Warn: &troubleshootv1beta2.SingleOutcome{When: "underReplicatedPartitions > 0", Message: "{{ .UnderReplicatedPartitions }} Kafka partitions are under-replicated"}},
+	{Warn: &troubleshootv1beta2.SingleOutcome{When: "consumerLag > 10000", Message: "Kafka consumer group {{ .ConsumerGroup }} is {{ .ConsumerLag }} messages behind"}},
+	{Pass: &troubleshootv1beta2.SingleOutcome{Message: "Kafka has {{ .Brokers }} brokers and all partitions are in sync"}},
+}
+
+// kafkaTemplateData is passed to the messages of the outcomes
+type kafkaTemplateData struct {
+	Error                     string
+	Brokers                   int
+	Partitions                int
+	UnderReplicatedPartitions int
+	OfflinePartitions         int
+	// ConsumerGroup is the consumer group that lags behind the most, by ConsumerLag messages
+	ConsumerGroup string
+	ConsumerLag   int64
+
+	connected bool
+}
+
+type AnalyzeKafka struct {
+	analyzer *troubleshootv1beta2.KafkaAnalyze
+}
+
+func (a *AnalyzeKafka) Title() string {
+	if a.analyzer.CheckName != "" {
+		return a.analyzer.CheckName
+	}
+
+	return "Kafka"
+}
+
+func (a *AnalyzeKafka) IsExcluded() (bool, error) {
+	return isExcluded(a.analyzer.Exclude)
+}
+
+func (a *AnalyzeKafka) collectorName() string {
+	if a.analyzer.CollectorName != "" {
+		return a.analyzer.CollectorName
+	}
+	return "kafka"
+}
+
+func (a *AnalyzeKafka) Analyze(getFile getCollectedFileContents, findFiles getChildCollectedFileContents) ([]*AnalyzeResult, error) {
+	fullPath := path.Join("kafka", fmt.Sprintf("%s.json", a.collectorName()))
+	collected, err := getFile(fullPath)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read collected file name: %s", fullPath)
+	}
+
+	cluster := collect.KafkaCluster{}
+	if err := json.Unmarshal(collected, &cluster); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal kafka cluster")
+	}
+
+	data := &kafkaTemplateData{
+		Error:                     cluster.Error,
+		Brokers:                   len(cluster.Brokers),
+		Partitions:                cluster.Partitions,
+		UnderReplicatedPartitions: len(cluster.UnderReplicatedPartitions),
+		OfflinePartitions:         len(cluster.OfflinePartitions),
+		connected:                 cluster.IsConnected,
+	}
+	for _, group := range cluster.ConsumerGroups {
+		if data.ConsumerGroup == "" || group.Lag > data.ConsumerLag {
+			data.ConsumerGroup = group.GroupID
+			data.ConsumerLag = group.Lag
+		}
+	}
+
+	outcomes := a.analyzer.Outcomes
+	if len(outcomes) == 0 {
+		outcomes = defaultKafkaOutcomes
+	}
+
+	result, err := analyzeTemplatedOutcomes(a.Title(), a.analyzer.Strict.BoolOrDefaultFalse(), outcomes, data, func(when string) (bool, error) {
+		return compareKafkaConditionalToActual(when, data)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		return []*AnalyzeResult{}, nil
+	}
+	return []*AnalyzeResult{result}, nil
+}
+
+// compareKafkaConditionalToActual evaluates a when clause against a Kafka cluster. Supported
+// conditions are "connected == <true|false>", and brokers, partitions, underReplicatedPartitions,
+// offlinePartitions and consumerLag, the lag of the consumer group furthest behind, compared to a
+// number, e.g. "consumerLag > 1000".
+func compareKafkaConditionalToActual(conditional string, data *kafkaTemplateData) (bool, error) {
+	parts := strings.Fields(conditional)
+	if len(parts) != 3 {
+		return false, errors.Errorf("unable to parse conditional %q", conditional)
+	}
+
+	var actual int
+	switch parts[0] {
+	case "connected":
+		return compareBoolConditional(parts[1], parts[2], data.connected)
+	case "brokers":
+		actual = data.Brokers
+	case "partitions":
+		actual = data.Partitions
+	case "underReplicatedPartitions":
+		actual = data.UnderReplicatedPartitions
+	case "offlinePartitions":
+		actual = data.OfflinePartitions
+	case "consumerLag":
+		actual = int(data.ConsumerLag)
+	default:
+		return false, errors.Errorf("unknown condition %q, must be one of connected, brokers, partitions, underReplicatedPartitions, offlinePartitions or consumerLag", parts[0])
+	}
+
+	return compareActualToWhen(parts[1]+" "+parts[2], actual)
+}
+
+// compareBoolConditional compares a boolean to the expected value of a condition, e.g. "== true"
+func compareBoolConditional(operator string, expected string, actual bool) (bool, error) {
+	expectedBool, err := strconv.ParseBool(expected)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to parse %q", expected)
+	}
+
+	switch operator {
+	case "=", "==", "===":
+		return actual == expectedBool, nil
+	case "!=", "!==":
+		return actual != expectedBool, nil
+	}
+	return false, errors.Errorf("unsupported operator %q", operator)
+}
+
+// analyzeTemplatedOutcomes returns the result of the first outcome whose condition matches, or nil
+// when none does. The title and messages of the outcomes are rendered with the data.
+func analyzeTemplatedOutcomes(title string, strict bool, outcomes []*troubleshootv1beta2.Outcome, data interface{}, compare func(when string) (bool, error)) (*AnalyzeResult, error) {
+	for _, outcome := range outcomes {
+		result := &AnalyzeResult{
+			IconKey: "kubernetes_text_analyze",
+			IconURI: "https://troubleshoot.sh/images/analyzer-icons/text-analyze.svg",
+			Strict:  strict,
+		}
+
+		var singleOutcome *troubleshootv1beta2.SingleOutcome
+		switch {
+		case outcome.Fail != nil:
+			singleOutcome = outcome.Fail
+			result.IsFail = true
+		case outcome.Warn != nil:
+			singleOutcome = outcome.Warn
+			result.IsWarn = true
+		case outcome.Pass != nil:
+			singleOutcome = outcome.Pass
+			result.IsPass = true
+		default:
+			continue
+		}
+
+		if singleOutcome.When != "" {
+			match, err := compare(singleOutcome.When)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to evaluate when %q", singleOutcome.When)
+			}
+			if !match {
+				continue
+			}
+		}
+
+		var err error
+		result.Title, err = util.RenderTemplate(title, data)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to render title template")
+		}
+		result.Message, err = util.RenderTemplate(singleOutcome.Message, data)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to render message template")
+		}
+		result.URI = singleOutcome.URI
+		result.Remediation = singleOutcome.Remediation
+		result.Condition = singleOutcome.When
+		return result, nil
+	}
+
+	return nil, nil
+}
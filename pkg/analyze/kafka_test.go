@@ -0,0 +1,93 @@
+package analyzer
+
+import (
+	"testing"
+
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyzeKafka(t *testing.T) {
+	tests := []struct {
+		name     string
+		analyzer *troubleshootv1beta2.KafkaAnalyze
+		cluster  string
+		want     *AnalyzeResult
+		wantErr  bool
+	}{
+		{
+			name:     "not connected",
+			analyzer: &troubleshootv1beta2.KafkaAnalyze{},
+			cluster:  `{"isConnected": false, "error": "dial tcp: connection refused"}`,
+			want:     &AnalyzeResult{IsFail: true, Message: "Cannot connect to Kafka: dial tcp: connection refused"},
+		},
+		{
+			name:     "offline partitions",
+			analyzer: &troubleshootv1beta2.KafkaAnalyze{},
+			cluster: `{"isConnected": true, "brokers": [{"id": 1}], "partitions": 4,
+			  "underReplicatedPartitions": [{"topic": "orders", "partition": 0, "leader": -1, "replicas": 2, "inSyncReplicas": 0}],
+			  "offlinePartitions": [{"topic": "orders", "partition": 0, "leader": -1, "replicas": 2, "inSyncReplicas": 0}]}`,
+			want: &AnalyzeResult{IsFail: true, Message: "1 Kafka partitions have no leader"},
+		},
+		{
+			name:     "consumer lag",
+			analyzer: &troubleshootv1beta2.KafkaAnalyze{},
+			cluster: `{"isConnected": true, "brokers": [{"id": 1}, {"id": 2}, {"id": 3}], "partitions": 4,
+			  "consumerGroups": [{"groupId": "billing", "lag": 15000}, {"groupId": "shipping", "lag": 30}]}`,
+			want: &AnalyzeResult{IsWarn: true, Message: "Kafka consumer group billing is 15000 messages behind"},
+		},
+		{
+			name:     "healthy",
+			analyzer: &troubleshootv1beta2.KafkaAnalyze{},
+			cluster:  `{"isConnected": true, "brokers": [{"id": 1}, {"id": 2}, {"id": 3}], "partitions": 4}`,
+			want:     &AnalyzeResult{IsPass: true, Message: "Kafka has 3 brokers and all partitions are in sync"},
+		},
+		{
+			name: "custom outcomes",
+			analyzer: &troubleshootv1beta2.KafkaAnalyze{
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{Fail: &troubleshootv1beta2.SingleOutcome{When: "brokers < 3", Message: "{{ .Brokers }} brokers"}},
+					{Pass: &troubleshootv1beta2.SingleOutcome{Message: "ok"}},
+				},
+			},
+			cluster: `{"isConnected": true, "brokers": [{"id": 1}], "partitions": 4}`,
+			want:    &AnalyzeResult{IsFail: true, Message: "1 brokers"},
+		},
+		{
+			name: "unknown condition",
+			analyzer: &troubleshootv1beta2.KafkaAnalyze{
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{Fail: &troubleshootv1beta2.SingleOutcome{When: "topics > 0"}},
+				},
+			},
+			cluster: `{"isConnected": true}`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := require.New(t)
+
+			getFile := func(path string) ([]byte, error) {
+				req.Equal("kafka/kafka.json", path)
+				return []byte(tt.cluster), nil
+			}
+
+			a := AnalyzeKafka{analyzer: tt.analyzer}
+			results, err := a.Analyze(getFile, nil)
+			if tt.wantErr {
+				req.Error(err)
+				return
+			}
+			req.NoError(err)
+			req.Len(results, 1)
+
+			assert.Equal(t, tt.want.IsPass, results[0].IsPass)
+			assert.Equal(t, tt.want.IsWarn, results[0].IsWarn)
+			assert.Equal(t, tt.want.IsFail, results[0].IsFail)
+			assert.Equal(t, tt.want.Message, results[0].Message)
+		})
+	}
+}
@@ -178,12 +178,14 @@ func analyzeWhenField(actual interface{}, outcomes []*troubleshootv1beta2.Outcom
 					result.IsFail = true
 					result.Message = outcome.Fail.Message
 					result.URI = outcome.Fail.URI
+					result.Remediation = outcome.Fail.Remediation
 					return result, nil
 				}
 			} else {
 				result.IsFail = true
 				result.Message = outcome.Fail.Message
 				result.URI = outcome.Fail.URI
+				result.Remediation = outcome.Fail.Remediation
 
 				return result, nil
 			}
@@ -199,12 +201,14 @@ func analyzeWhenField(actual interface{}, outcomes []*troubleshootv1beta2.Outcom
 					result.IsWarn = true
 					result.Message = outcome.Warn.Message
 					result.URI = outcome.Warn.URI
+					result.Remediation = outcome.Warn.Remediation
 					return result, nil
 				}
 			} else {
 				result.IsWarn = true
 				result.Message = outcome.Warn.Message
 				result.URI = outcome.Warn.URI
+				result.Remediation = outcome.Warn.Remediation
 
 				return result, nil
 			}
@@ -221,12 +225,14 @@ func analyzeWhenField(actual interface{}, outcomes []*troubleshootv1beta2.Outcom
 					result.IsPass = true
 					result.Message = outcome.Pass.Message
 					result.URI = outcome.Pass.URI
+					result.Remediation = outcome.Pass.Remediation
 					return result, nil
 				}
 			} else {
 				result.IsPass = true
 				result.Message = outcome.Pass.Message
 				result.URI = outcome.Pass.URI
+				result.Remediation = outcome.Pass.Remediation
 
 				return result, nil
 			}
@@ -295,7 +301,7 @@ func (a *AnalyzeClusterResource) analyzeResource(analyzer *troubleshootv1beta2.C
 			return result, nil
 		}
 	} else if analyzer.RegexPattern != "" {
-		result, err := analyzeRegexPattern(analyzer.RegexPattern, actualYAML, analyzer.Outcomes, a.Title())
+		result, err := analyzeRegexPattern(analyzer.RegexPattern, actualYAML, analyzer.Outcomes, a.Title(), "")
 		if err != nil {
 			return nil, err
 		}
@@ -303,7 +309,7 @@ func (a *AnalyzeClusterResource) analyzeResource(analyzer *troubleshootv1beta2.C
 			return result, nil
 		}
 	} else if analyzer.RegexGroups != "" {
-		result, err := analyzeRegexGroups(analyzer.RegexGroups, actualYAML, analyzer.Outcomes, a.Title())
+		result, err := analyzeRegexGroups(analyzer.RegexGroups, actualYAML, analyzer.Outcomes, a.Title(), "")
 		if err != nil {
 			return nil, err
 		}
@@ -355,6 +361,7 @@ func analyzeValue(expected interface{}, actual interface{}, outcomes []*troubles
 				result.IsFail = true
 				result.Message = outcome.Fail.Message
 				result.URI = outcome.Fail.URI
+				result.Remediation = outcome.Fail.Remediation
 				return result, nil
 			}
 		} else if outcome.Warn != nil {
@@ -370,6 +377,7 @@ func analyzeValue(expected interface{}, actual interface{}, outcomes []*troubles
 				result.IsWarn = true
 				result.Message = outcome.Warn.Message
 				result.URI = outcome.Warn.URI
+				result.Remediation = outcome.Warn.Remediation
 				return result, nil
 			}
 		} else if outcome.Pass != nil {
@@ -385,6 +393,7 @@ func analyzeValue(expected interface{}, actual interface{}, outcomes []*troubles
 				result.IsPass = true
 				result.Message = outcome.Pass.Message
 				result.URI = outcome.Pass.URI
+				result.Remediation = outcome.Pass.Remediation
 				return result, nil
 			}
 		}
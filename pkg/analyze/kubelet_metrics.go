@@ -0,0 +1,278 @@
+package analyzer
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"math"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/collect"
+)
+
+const (
+	plegRelistDurationMetric = "kubelet_pleg_relist_duration_seconds"
+	plegRelistIntervalMetric = "kubelet_pleg_relist_interval_seconds"
+)
+
+// kubeletMetricsTemplateData is passed to the messages of the outcomes. The latencies are
+// estimated from the histograms of the kubelet, since it started.
+type kubeletMetricsTemplateData struct {
+	Node                  string
+	PLEGRelistP50         time.Duration
+	PLEGRelistP99         time.Duration
+	PLEGRelistIntervalP99 time.Duration
+}
+
+// histogramBucket is a bucket of a Prometheus histogram, the count of the observations less than
+// or equal to its upper bound
+type histogramBucket struct {
+	upperBound float64
+	count      float64
+}
+
+type AnalyzeKubeletMetrics struct {
+	analyzer *troubleshootv1beta2.KubeletMetricsAnalyze
+}
+
+func (a *AnalyzeKubeletMetrics) Title() string {
+	if a.analyzer.CheckName != "" {
+		return a.analyzer.CheckName
+	}
+	return "Kubelet Metrics"
+}
+
+func (a *AnalyzeKubeletMetrics) IsExcluded() (bool, error) {
+	return isExcluded(a.analyzer.Exclude)
+}
+
+func (a *AnalyzeKubeletMetrics) Analyze(getFile getCollectedFileContents, findFiles getChildCollectedFileContents) ([]*AnalyzeResult, error) {
+	collected, err := findFiles(filepath.Join(collect.KubeletMetricsDir, "*.txt"), nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read collected kubelet metrics")
+	}
+
+	filenames := make([]string, 0, len(collected))
+	for filename := range collected {
+		filenames = append(filenames, filename)
+	}
+	sort.Strings(filenames)
+
+	results := []*AnalyzeResult{}
+	for _, filename := range filenames {
+		node := strings.TrimSuffix(path.Base(filename), ".txt")
+
+		histograms, err := parseHistogramBuckets(collected[filename], plegRelistDurationMetric, plegRelistIntervalMetric)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse the kubelet metrics of node %s", node)
+		}
+		if len(histograms[plegRelistDurationMetric]) == 0 {
+			results = append(results, &AnalyzeResult{
+				Title:   a.Title(),
+				IsWarn:  true,
+				Message: fmt.Sprintf("The kubelet metrics of node %s have no %s histogram", node, plegRelistDurationMetric),
+				IconKey: "kubernetes_text_analyze",
+				IconURI: "https://troubleshoot.sh/images/analyzer-icons/text-analyze.svg",
+				Strict:  a.analyzer.Strict.BoolOrDefaultFalse(),
+			})
+			continue
+		}
+
+		data := kubeletMetricsTemplateData{
+			Node:                  node,
+			PLEGRelistP50:         secondsToDuration(histogramQuantile(0.5, histograms[plegRelistDurationMetric])),
+			PLEGRelistP99:         secondsToDuration(histogramQuantile(0.99, histograms[plegRelistDurationMetric])),
+			PLEGRelistIntervalP99: secondsToDuration(histogramQuantile(0.99, histograms[plegRelistIntervalMetric])),
+		}
+
+		result, err := analyzeTemplatedOutcomes(a.Title(), a.analyzer.Strict.BoolOrDefaultFalse(), a.analyzer.Outcomes, data, func(when string) (bool, error) {
+			return compareKubeletMetrics(data, when)
+		})
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to analyze the kubelet metrics of node %s", node)
+		}
+		if result != nil {
+			results = append(results, result)
+		}
+	}
+
+	return results, nil
+}
+
+// compareKubeletMetrics evaluates a when clause against the metrics of a node. Supported conditions
+// are plegRelistP50, plegRelistP99 and plegRelistIntervalP99 compared to durations, e.g.
+// "plegRelistP99 > 3s".
+func compareKubeletMetrics(data kubeletMetricsTemplateData, when string) (bool, error) {
+	parts := strings.Fields(when)
+	if len(parts) != 3 {
+		return false, fmt.Errorf("expected 3 parts in when %q, got %d", when, len(parts))
+	}
+
+	switch parts[0] {
+	case "plegRelistP50":
+		return compareDuration(data.PLEGRelistP50, parts[1], parts[2])
+	case "plegRelistP99":
+		return compareDuration(data.PLEGRelistP99, parts[1], parts[2])
+	case "plegRelistIntervalP99":
+		return compareDuration(data.PLEGRelistIntervalP99, parts[1], parts[2])
+	}
+	return false, fmt.Errorf("unsupported condition %q, must be one of plegRelistP50, plegRelistP99 or plegRelistIntervalP99", parts[0])
+}
+
+// parseHistogramBuckets reads the buckets of the histograms with the given names from metrics in
+// the Prometheus text format. The buckets of the series of a histogram with other labels are
+// summed by upper bound, and returned sorted by upper bound.
+func parseHistogramBuckets(metrics []byte, names ...string) (map[string][]histogramBucket, error) {
+	counts := map[string]map[float64]float64{}
+	for _, name := range names {
+		counts[name] = map[float64]float64{}
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(metrics))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		name, labels, value, ok := parseMetricLine(line)
+		if !ok || !strings.HasSuffix(name, "_bucket") {
+			continue
+		}
+		buckets, ok := counts[strings.TrimSuffix(name, "_bucket")]
+		if !ok {
+			continue
+		}
+
+		le, ok := labels["le"]
+		if !ok {
+			continue
+		}
+		upperBound, err := strconv.ParseFloat(le, 64)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse bucket bound %q of %s", le, name)
+		}
+		count, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse bucket count %q of %s", value, name)
+		}
+		buckets[upperBound] += count
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrap(err, "failed to read metrics")
+	}
+
+	histograms := map[string][]histogramBucket{}
+	for name, buckets := range counts {
+		histogram := []histogramBucket{}
+		for upperBound, count := range buckets {
+			histogram = append(histogram, histogramBucket{upperBound: upperBound, count: count})
+		}
+		sort.Slice(histogram, func(i, j int) bool {
+			return histogram[i].upperBound < histogram[j].upperBound
+		})
+		histograms[name] = histogram
+	}
+	return histograms, nil
+}
+
+// parseMetricLine splits a sample line, e.g. `name{label="value"} 1 1700000000000`, into its
+// metric name, labels and value
+func parseMetricLine(line string) (string, map[string]string, string, bool) {
+	labels := map[string]string{}
+	name := line
+	rest := ""
+	if i := strings.IndexAny(line, "{ "); i >= 0 {
+		name, rest = line[:i], line[i:]
+	}
+
+	if strings.HasPrefix(rest, "{") {
+		end := strings.LastIndex(rest, "}")
+		if end < 0 {
+			return "", nil, "", false
+		}
+		for _, pair := range splitLabels(rest[1:end]) {
+			key, value, ok := strings.Cut(pair, "=")
+			if !ok {
+				continue
+			}
+			unquoted, err := strconv.Unquote(strings.TrimSpace(value))
+			if err != nil {
+				continue
+			}
+			labels[strings.TrimSpace(key)] = unquoted
+		}
+		rest = rest[end+1:]
+	}
+
+	fields := strings.Fields(rest)
+	if len(fields) == 0 {
+		return "", nil, "", false
+	}
+	return name, labels, fields[0], true
+}
+
+// splitLabels splits label pairs on the commas outside of quoted values
+func splitLabels(s string) []string {
+	pairs := []string{}
+	quoted, escaped := false, false
+	start := 0
+	for i, r := range s {
+		switch {
+		case escaped:
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == '"':
+			quoted = !quoted
+		case r == ',' && !quoted:
+			pairs = append(pairs, s[start:i])
+			start = i + 1
+		}
+	}
+	if strings.TrimSpace(s[start:]) != "" {
+		pairs = append(pairs, s[start:])
+	}
+	return pairs
+}
+
+// histogramQuantile estimates the quantile of the observations of a histogram as Prometheus'
+// histogram_quantile does, interpolating linearly within the bucket of the quantile. Quantiles in
+// the +Inf bucket are the largest finite upper bound.
+func histogramQuantile(q float64, buckets []histogramBucket) float64 {
+	if len(buckets) == 0 {
+		return 0
+	}
+	total := buckets[len(buckets)-1].count
+	if total == 0 {
+		return 0
+	}
+
+	rank := q * total
+	lowerBound, lowerCount := 0.0, 0.0
+	for _, bucket := range buckets {
+		if bucket.count >= rank {
+			if math.IsInf(bucket.upperBound, 1) {
+				return lowerBound
+			}
+			if bucket.count == lowerCount {
+				return bucket.upperBound
+			}
+			return lowerBound + (bucket.upperBound-lowerBound)*(rank-lowerCount)/(bucket.count-lowerCount)
+		}
+		lowerBound, lowerCount = bucket.upperBound, bucket.count
+	}
+	return lowerBound
+}
+
+func secondsToDuration(seconds float64) time.Duration {
+	return time.Duration(seconds * float64(time.Second)).Round(time.Millisecond)
+}
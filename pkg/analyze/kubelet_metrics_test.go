@@ -0,0 +1,117 @@
+package analyzer
+
+import (
+	"testing"
+	"time"
+
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testKubeletMetrics = `# HELP kubelet_pleg_relist_duration_seconds [ALPHA] Duration in seconds for relisting pods in PLEG.
+# TYPE kubelet_pleg_relist_duration_seconds histogram
+kubelet_pleg_relist_duration_seconds_bucket{le="0.005"} 0
+kubelet_pleg_relist_duration_seconds_bucket{le="0.01"} 50
+kubelet_pleg_relist_duration_seconds_bucket{le="0.025"} 90
+kubelet_pleg_relist_duration_seconds_bucket{le="0.05"} 98
+kubelet_pleg_relist_duration_seconds_bucket{le="1"} 100
+kubelet_pleg_relist_duration_seconds_bucket{le="+Inf"} 100
+kubelet_pleg_relist_duration_seconds_sum 1.5
+kubelet_pleg_relist_duration_seconds_count 100
+# HELP kubelet_pleg_relist_interval_seconds [ALPHA] Interval in seconds between relisting in PLEG.
+# TYPE kubelet_pleg_relist_interval_seconds histogram
+kubelet_pleg_relist_interval_seconds_bucket{le="1"} 0
+kubelet_pleg_relist_interval_seconds_bucket{le="2"} 99
+kubelet_pleg_relist_interval_seconds_bucket{le="+Inf"} 100
+kubelet_pleg_relist_interval_seconds_sum 102
+kubelet_pleg_relist_interval_seconds_count 100
+# HELP kubelet_running_pods [ALPHA] Number of pods that have a running pod sandbox
+# TYPE kubelet_running_pods gauge
+kubelet_running_pods 12
+`
+
+func Test_parseHistogramBuckets(t *testing.T) {
+	metrics := `# a comment
+http_request_duration_seconds_bucket{code="200",path="/a,b",le="0.1"} 3 1700000000000
+http_request_duration_seconds_bucket{code="500",path="/",le="0.1"} 1
+http_request_duration_seconds_bucket{code="200",path="/a,b",le="+Inf"} 4
+http_request_duration_seconds_bucket{code="500",path="/",le="+Inf"} 2
+http_request_duration_seconds_count{code="200"} 4
+`
+	histograms, err := parseHistogramBuckets([]byte(metrics), "http_request_duration_seconds", "missing")
+	require.NoError(t, err)
+	require.Len(t, histograms["http_request_duration_seconds"], 2)
+	assert.Equal(t, histogramBucket{upperBound: 0.1, count: 4}, histograms["http_request_duration_seconds"][0])
+	assert.Equal(t, float64(6), histograms["http_request_duration_seconds"][1].count)
+	assert.Empty(t, histograms["missing"])
+
+	_, err = parseHistogramBuckets([]byte(`http_request_duration_seconds_bucket{le="a"} 1`), "http_request_duration_seconds")
+	assert.Error(t, err)
+}
+
+func Test_histogramQuantile(t *testing.T) {
+	histograms, err := parseHistogramBuckets([]byte(testKubeletMetrics), plegRelistDurationMetric, plegRelistIntervalMetric)
+	require.NoError(t, err)
+
+	assert.InDelta(t, 0.01, histogramQuantile(0.5, histograms[plegRelistDurationMetric]), 1e-9)
+	assert.InDelta(t, 0.525, histogramQuantile(0.99, histograms[plegRelistDurationMetric]), 1e-9)
+	// in the +Inf bucket
+	assert.InDelta(t, 2.0, histogramQuantile(0.999, histograms[plegRelistIntervalMetric]), 1e-9)
+	assert.Equal(t, float64(0), histogramQuantile(0.99, nil))
+	assert.Equal(t, float64(0), histogramQuantile(0.99, []histogramBucket{{upperBound: 1, count: 0}}))
+}
+
+func Test_compareKubeletMetrics(t *testing.T) {
+	data := kubeletMetricsTemplateData{
+		PLEGRelistP50:         10 * time.Millisecond,
+		PLEGRelistP99:         4 * time.Second,
+		PLEGRelistIntervalP99: 5 * time.Second,
+	}
+
+	tests := []struct {
+		when    string
+		want    bool
+		wantErr bool
+	}{
+		{when: "plegRelistP50 < 100ms", want: true},
+		{when: "plegRelistP99 > 3s", want: true},
+		{when: "plegRelistIntervalP99 >= 10s", want: false},
+		{when: "plegRelistP99 > 3", wantErr: true},
+		{when: "runningPods > 3", wantErr: true},
+		{when: "plegRelistP99", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.when, func(t *testing.T) {
+			got, err := compareKubeletMetrics(data, tt.when)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestAnalyzeKubeletMetrics(t *testing.T) {
+	a := AnalyzeKubeletMetrics{analyzer: &troubleshootv1beta2.KubeletMetricsAnalyze{
+		Outcomes: []*troubleshootv1beta2.Outcome{
+			{Fail: &troubleshootv1beta2.SingleOutcome{When: "plegRelistP99 > 3s", Message: "PLEG relists of {{ .Node }} are slow, p99 {{ .PLEGRelistP99 }}"}},
+			{Pass: &troubleshootv1beta2.SingleOutcome{Message: "PLEG relists of {{ .Node }} take {{ .PLEGRelistP99 }} at p99"}},
+		},
+	}}
+	results, err := a.Analyze(nil, func(pattern string, _ []string) (map[string][]byte, error) {
+		require.Equal(t, "kubelet-metrics/*.txt", pattern)
+		return map[string][]byte{
+			"kubelet-metrics/node-2.txt": []byte("kubelet_running_pods 12\n"),
+			"kubelet-metrics/node-1.txt": []byte(testKubeletMetrics),
+		}, nil
+	})
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.True(t, results[0].IsPass)
+	assert.Equal(t, "PLEG relists of node-1 take 525ms at p99", results[0].Message)
+	assert.True(t, results[1].IsWarn)
+	assert.Equal(t, "The kubelet metrics of node node-2 have no kubelet_pleg_relist_duration_seconds histogram", results[1].Message)
+}
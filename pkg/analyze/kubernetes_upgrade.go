@@ -0,0 +1,272 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/blang/semver/v4"
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/troubleshoot/internal/util"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/collect"
+	"github.com/replicatedhq/troubleshoot/pkg/constants"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// removedAPI is a built-in API that Kubernetes stops serving in a minor version. An empty kind
+// stands for every kind of the group version.
+type removedAPI struct {
+	groupVersion string
+	kind         string
+	removedIn    uint64
+	replacement  string
+}
+
+// removedAPIs follows https://kubernetes.io/docs/reference/using-api/deprecation-guide/
+var removedAPIs = []removedAPI{
+	{"extensions/v1beta1", "DaemonSet", 16, "apps/v1"},
+	{"extensions/v1beta1", "Deployment", 16, "apps/v1"},
+	{"extensions/v1beta1", "ReplicaSet", 16, "apps/v1"},
+	{"extensions/v1beta1", "NetworkPolicy", 16, "networking.k8s.io/v1"},
+	{"extensions/v1beta1", "PodSecurityPolicy", 16, "policy/v1beta1"},
+	{"apps/v1beta1", "", 16, "apps/v1"},
+	{"apps/v1beta2", "", 16, "apps/v1"},
+	{"extensions/v1beta1", "Ingress", 22, "networking.k8s.io/v1"},
+	{"networking.k8s.io/v1beta1", "", 22, "networking.k8s.io/v1"},
+	{"admissionregistration.k8s.io/v1beta1", "", 22, "admissionregistration.k8s.io/v1"},
+	{"apiextensions.k8s.io/v1beta1", "", 22, "apiextensions.k8s.io/v1"},
+	{"apiregistration.k8s.io/v1beta1", "", 22, "apiregistration.k8s.io/v1"},
+	{"authentication.k8s.io/v1beta1", "", 22, "authentication.k8s.io/v1"},
+	{"authorization.k8s.io/v1beta1", "", 22, "authorization.k8s.io/v1"},
+	{"certificates.k8s.io/v1beta1", "", 22, "certificates.k8s.io/v1"},
+	{"coordination.k8s.io/v1beta1", "", 22, "coordination.k8s.io/v1"},
+	{"rbac.authorization.k8s.io/v1beta1", "", 22, "rbac.authorization.k8s.io/v1"},
+	{"scheduling.k8s.io/v1beta1", "", 22, "scheduling.k8s.io/v1"},
+	{"storage.k8s.io/v1beta1", "CSIDriver", 22, "storage.k8s.io/v1"},
+	{"storage.k8s.io/v1beta1", "CSINode", 22, "storage.k8s.io/v1"},
+	{"storage.k8s.io/v1beta1", "StorageClass", 22, "storage.k8s.io/v1"},
+	{"storage.k8s.io/v1beta1", "VolumeAttachment", 22, "storage.k8s.io/v1"},
+	{"batch/v1beta1", "", 25, "batch/v1"},
+	{"discovery.k8s.io/v1beta1", "", 25, "discovery.k8s.io/v1"},
+	{"events.k8s.io/v1beta1", "", 25, "events.k8s.io/v1"},
+	{"autoscaling/v2beta1", "", 25, "autoscaling/v2"},
+	{"policy/v1beta1", "PodDisruptionBudget", 25, "policy/v1"},
+	{"policy/v1beta1", "PodSecurityPolicy", 25, "Pod Security Admission"},
+	{"node.k8s.io/v1beta1", "", 25, "node.k8s.io/v1"},
+	{"autoscaling/v2beta2", "", 26, "autoscaling/v2"},
+	{"flowcontrol.apiserver.k8s.io/v1beta1", "", 26, "flowcontrol.apiserver.k8s.io/v1beta3"},
+	{"storage.k8s.io/v1beta1", "CSIStorageCapacity", 27, "storage.k8s.io/v1"},
+	{"flowcontrol.apiserver.k8s.io/v1beta2", "", 29, "flowcontrol.apiserver.k8s.io/v1"},
+	{"flowcontrol.apiserver.k8s.io/v1beta3", "", 32, "flowcontrol.apiserver.k8s.io/v1"},
+}
+
+var defaultKubernetesUpgradeOutcomes = []*troubleshootv1beta2.Outcome{
+	{Fail: &troubleshootv1beta2.SingleOutcome{When: "minorVersionJump > 1", Message: "Upgrading from {{ .CurrentVersion }} to {{ .TargetVersion }} skips minor versions, the control plane must be upgraded one minor version at a time"}},
+	{Fail: &troubleshootv1beta2.SingleOutcome{When: "skewedNodes > 0", Message: "The kubelets of {{ .SkewedNodes }} are not supported with a {{ .TargetVersion }} control plane, upgrade them first"}},
+	{Warn: &troubleshootv1beta2.SingleOutcome{When: "removedAPIs > 0", Message: "The cluster serves APIs that are removed in {{ .TargetVersion }}: {{ .RemovedAPIs }}. Migrate manifests and clients before upgrading"}},
+	{Pass: &troubleshootv1beta2.SingleOutcome{Message: "The cluster can be upgraded from {{ .CurrentVersion }} to {{ .TargetVersion }}"}},
+}
+
+// kubernetesUpgradeTemplateData is passed to the messages of the outcomes
+type kubernetesUpgradeTemplateData struct {
+	CurrentVersion   string
+	TargetVersion    string
+	MinorVersionJump int
+	RemovedAPIs      string
+	SkewedNodes      string
+}
+
+type AnalyzeKubernetesUpgrade struct {
+	analyzer *troubleshootv1beta2.KubernetesUpgrade
+}
+
+func (a *AnalyzeKubernetesUpgrade) Title() string {
+	if a.analyzer.CheckName != "" {
+		return a.analyzer.CheckName
+	}
+
+	return "Kubernetes Upgrade"
+}
+
+func (a *AnalyzeKubernetesUpgrade) IsExcluded() (bool, error) {
+	return isExcluded(a.analyzer.Exclude)
+}
+
+func (a *AnalyzeKubernetesUpgrade) Analyze(getFile getCollectedFileContents, findFiles getChildCollectedFileContents) ([]*AnalyzeResult, error) {
+	clusterInfo, err := getFile("cluster-info/cluster_version.json")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get contents of cluster_version.json")
+	}
+	clusterVersion := collect.ClusterVersion{}
+	if err := json.Unmarshal(clusterInfo, &clusterVersion); err != nil {
+		return nil, errors.Wrap(err, "failed to parse cluster_version.json")
+	}
+	current, err := parseK8sVersionString(clusterVersion.String)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse semver from cluster_version.json")
+	}
+
+	target := semver.Version{Major: current.Major, Minor: current.Minor + 1}
+	if a.analyzer.TargetVersion != "" {
+		target, err = semver.ParseTolerant(a.analyzer.TargetVersion)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse target version %q", a.analyzer.TargetVersion)
+		}
+		if target.Major != current.Major {
+			return nil, errors.Errorf("target version %q is not a %d.x version", a.analyzer.TargetVersion, current.Major)
+		}
+	}
+
+	collected, err := getFile(fmt.Sprintf("%s/%s.json", constants.CLUSTER_RESOURCES_DIR, constants.CLUSTER_RESOURCES_NODES))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get contents of nodes.json")
+	}
+	var nodes corev1.NodeList
+	if err := json.Unmarshal(collected, &nodes); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal node list")
+	}
+
+	// clusters that do not serve a removed API have nothing to migrate, so a missing file only
+	// means removed APIs are not reported
+	var apiResources []*metav1.APIResourceList
+	collected, err = getFile(fmt.Sprintf("%s/%s.json", constants.CLUSTER_RESOURCES_DIR, constants.CLUSTER_RESOURCES_RESOURCES))
+	if err == nil {
+		if err := json.Unmarshal(collected, &apiResources); err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal api resource list")
+		}
+	}
+
+	removed := findRemovedAPIs(apiResources, target.Minor)
+	skewed := findSkewedNodes(nodes.Items, target)
+	data := kubernetesUpgradeTemplateData{
+		CurrentVersion:   fmt.Sprintf("%d.%d", current.Major, current.Minor),
+		TargetVersion:    fmt.Sprintf("%d.%d", target.Major, target.Minor),
+		MinorVersionJump: int(target.Minor) - int(current.Minor),
+		RemovedAPIs:      strings.Join(removed, ", "),
+		SkewedNodes:      strings.Join(skewed, ", "),
+	}
+
+	outcomes := a.analyzer.Outcomes
+	if len(outcomes) == 0 {
+		outcomes = defaultKubernetesUpgradeOutcomes
+	}
+	counts := map[string]int{
+		"minorVersionJump": data.MinorVersionJump,
+		"removedAPIs":      len(removed),
+		"skewedNodes":      len(skewed),
+	}
+
+	for _, outcome := range outcomes {
+		result := &AnalyzeResult{
+			Title:   a.Title(),
+			IconKey: "kubernetes_cluster_version",
+			IconURI: "https://troubleshoot.sh/images/analyzer-icons/kubernetes.svg?w=16&h=16",
+			Strict:  a.analyzer.Strict.BoolOrDefaultFalse(),
+		}
+
+		var singleOutcome *troubleshootv1beta2.SingleOutcome
+		switch {
+		case outcome.Fail != nil:
+			singleOutcome = outcome.Fail
+			result.IsFail = true
+		case outcome.Warn != nil:
+			singleOutcome = outcome.Warn
+			result.IsWarn = true
+		case outcome.Pass != nil:
+			singleOutcome = outcome.Pass
+			result.IsPass = true
+		default:
+			continue
+		}
+
+		if singleOutcome.When != "" {
+			parts := strings.Fields(singleOutcome.When)
+			if len(parts) != 3 {
+				return nil, errors.Errorf("expected 3 parts in when %q, got %d", singleOutcome.When, len(parts))
+			}
+			count, ok := counts[parts[0]]
+			if !ok {
+				return nil, errors.Errorf("unsupported condition %q, must be one of minorVersionJump, removedAPIs or skewedNodes", parts[0])
+			}
+			match, err := compareActualToWhen(parts[1]+" "+parts[2], count)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to evaluate when %q", singleOutcome.When)
+			}
+			if !match {
+				continue
+			}
+		}
+
+		result.Message, err = util.RenderTemplate(singleOutcome.Message, data)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to render message template")
+		}
+		result.URI = singleOutcome.URI
+		result.Remediation = singleOutcome.Remediation
+		result.Condition = singleOutcome.When
+
+		return []*AnalyzeResult{result}, nil
+	}
+
+	return []*AnalyzeResult{}, nil
+}
+
+// findRemovedAPIs returns the served APIs that are removed in the target minor version or
+// before, with their replacements
+func findRemovedAPIs(apiResources []*metav1.APIResourceList, targetMinor uint64) []string {
+	found := map[string]struct{}{}
+	for _, list := range apiResources {
+		if list == nil {
+			continue
+		}
+		for _, resource := range list.APIResources {
+			// subresources such as deployments/scale are not served on their own
+			if strings.Contains(resource.Name, "/") {
+				continue
+			}
+			for _, api := range removedAPIs {
+				if api.groupVersion != list.GroupVersion || (api.kind != "" && api.kind != resource.Kind) {
+					continue
+				}
+				if api.removedIn <= targetMinor {
+					found[fmt.Sprintf("%s %s (use %s)", list.GroupVersion, resource.Kind, api.replacement)] = struct{}{}
+				}
+				break
+			}
+		}
+	}
+
+	removed := make([]string, 0, len(found))
+	for api := range found {
+		removed = append(removed, api)
+	}
+	sort.Strings(removed)
+	return removed
+}
+
+// findSkewedNodes returns the nodes whose kubelets are not supported with a control plane of the
+// target version, being newer or more minor versions older than the version skew policy allows
+func findSkewedNodes(nodes []corev1.Node, target semver.Version) []string {
+	// kubelets could be three minor versions older than the API server starting with 1.28, and
+	// two before that
+	maxSkew := uint64(2)
+	if target.Minor >= 28 {
+		maxSkew = 3
+	}
+
+	skewed := []string{}
+	for _, node := range nodes {
+		kubeletVersion := node.Status.NodeInfo.KubeletVersion
+		version, err := parseK8sVersionString(kubeletVersion)
+		if err != nil {
+			continue
+		}
+		if version.Major != target.Major || version.Minor > target.Minor || target.Minor-version.Minor > maxSkew {
+			skewed = append(skewed, fmt.Sprintf("%s (%s)", node.Name, kubeletVersion))
+		}
+	}
+	return skewed
+}
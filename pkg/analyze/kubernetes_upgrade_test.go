@@ -0,0 +1,153 @@
+package analyzer
+
+import (
+	"testing"
+
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestAnalyzeKubernetesUpgrade(t *testing.T) {
+	clusterVersion := `{"info": {"major": "1", "minor": "24"}, "string": "v1.24.17"}`
+	nodes := `{"items": [
+	  {"metadata": {"name": "control-plane"}, "status": {"nodeInfo": {"kubeletVersion": "v1.24.17"}}},
+	  {"metadata": {"name": "worker-1"}, "status": {"nodeInfo": {"kubeletVersion": "v1.23.9+k3s1"}}},
+	  {"metadata": {"name": "worker-2"}, "status": {"nodeInfo": {"kubeletVersion": "v1.22.4"}}}
+	]}`
+	resources := `[
+	  {"groupVersion": "apps/v1", "resources": [{"name": "deployments", "kind": "Deployment"}]},
+	  {"groupVersion": "batch/v1beta1", "resources": [{"name": "cronjobs", "kind": "CronJob"}]},
+	  {"groupVersion": "policy/v1beta1", "resources": [
+	    {"name": "poddisruptionbudgets", "kind": "PodDisruptionBudget"},
+	    {"name": "poddisruptionbudgets/status", "kind": "PodDisruptionBudget"},
+	    {"name": "podsecuritypolicies", "kind": "PodSecurityPolicy"}
+	  ]},
+	  {"groupVersion": "flowcontrol.apiserver.k8s.io/v1beta2", "resources": [{"name": "flowschemas", "kind": "FlowSchema"}]}
+	]`
+
+	tests := []struct {
+		name        string
+		analyzer    *troubleshootv1beta2.KubernetesUpgrade
+		isFail      bool
+		isWarn      bool
+		isPass      bool
+		wantMessage string
+		wantErr     bool
+	}{
+		{
+			name:        "skewed nodes with the next minor version",
+			analyzer:    &troubleshootv1beta2.KubernetesUpgrade{},
+			isFail:      true,
+			wantMessage: "The kubelets of worker-2 (v1.22.4) are not supported with a 1.25 control plane, upgrade them first",
+		},
+		{
+			name: "removed apis",
+			analyzer: &troubleshootv1beta2.KubernetesUpgrade{
+				TargetVersion: "v1.25",
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{Warn: &troubleshootv1beta2.SingleOutcome{When: "removedAPIs > 0", Message: "{{ .RemovedAPIs }}"}},
+					{Pass: &troubleshootv1beta2.SingleOutcome{Message: "ok"}},
+				},
+			},
+			isWarn:      true,
+			wantMessage: "batch/v1beta1 CronJob (use batch/v1), policy/v1beta1 PodDisruptionBudget (use policy/v1), policy/v1beta1 PodSecurityPolicy (use Pod Security Admission)",
+		},
+		{
+			name: "minor version jump",
+			analyzer: &troubleshootv1beta2.KubernetesUpgrade{
+				TargetVersion: "1.26",
+			},
+			isFail:      true,
+			wantMessage: "Upgrading from 1.24 to 1.26 skips minor versions, the control plane must be upgraded one minor version at a time",
+		},
+		{
+			name: "no skewed nodes",
+			analyzer: &troubleshootv1beta2.KubernetesUpgrade{
+				TargetVersion: "1.24",
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{Fail: &troubleshootv1beta2.SingleOutcome{When: "skewedNodes > 0", Message: "skewed"}},
+					{Pass: &troubleshootv1beta2.SingleOutcome{Message: "{{ .CurrentVersion }} to {{ .TargetVersion }}"}},
+				},
+			},
+			isPass:      true,
+			wantMessage: "1.24 to 1.24",
+		},
+		{
+			name: "unsupported condition",
+			analyzer: &troubleshootv1beta2.KubernetesUpgrade{
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{Fail: &troubleshootv1beta2.SingleOutcome{When: "deprecatedAPIs > 0"}},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name:     "major version",
+			analyzer: &troubleshootv1beta2.KubernetesUpgrade{TargetVersion: "2.0"},
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := require.New(t)
+
+			files := map[string]string{
+				"cluster-info/cluster_version.json": clusterVersion,
+				"cluster-resources/nodes.json":      nodes,
+				"cluster-resources/resources.json":  resources,
+			}
+			getFile := func(path string) ([]byte, error) {
+				return []byte(files[path]), nil
+			}
+
+			a := AnalyzeKubernetesUpgrade{analyzer: tt.analyzer}
+			results, err := a.Analyze(getFile, nil)
+			if tt.wantErr {
+				req.Error(err)
+				return
+			}
+			req.NoError(err)
+			req.Len(results, 1)
+
+			assert.Equal(t, tt.isFail, results[0].IsFail)
+			assert.Equal(t, tt.isWarn, results[0].IsWarn)
+			assert.Equal(t, tt.isPass, results[0].IsPass)
+			assert.Equal(t, tt.wantMessage, results[0].Message)
+		})
+	}
+}
+
+func Test_findSkewedNodes(t *testing.T) {
+	tests := []struct {
+		kubelet string
+		target  string
+		skewed  bool
+	}{
+		{kubelet: "v1.27.1", target: "1.30", skewed: false},
+		{kubelet: "v1.26.1", target: "1.30", skewed: true},
+		{kubelet: "v1.25.1", target: "1.27", skewed: false},
+		{kubelet: "v1.24.1", target: "1.27", skewed: true},
+		{kubelet: "v1.31.0", target: "1.30", skewed: true},
+		{kubelet: "v1.29.3-eks-5e0fdde", target: "1.30", skewed: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.kubelet+" "+tt.target, func(t *testing.T) {
+			target, err := parseK8sVersionString(tt.target + ".0")
+			require.NoError(t, err)
+
+			node := corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{Name: "node"},
+				Status: corev1.NodeStatus{
+					NodeInfo: corev1.NodeSystemInfo{KubeletVersion: tt.kubelet},
+				},
+			}
+			skewed := findSkewedNodes([]corev1.Node{node}, target)
+			assert.Equal(t, tt.skewed, len(skewed) == 1)
+		})
+	}
+}
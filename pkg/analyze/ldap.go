@@ -0,0 +1,153 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/collect"
+)
+
+var defaultLDAPOutcomes = []*troubleshootv1beta2.Outcome{
+	{Fail: &troubleshootv1beta2.SingleOutcome{When: "connected == false", Message: "Cannot connect to the LDAP server: {{ .Error }}"}},
+	{Fail: &troubleshootv1beta2.SingleOutcome{When: "trusted == false", Message: "The certificate of the LDAP server is not trusted: {{ .CertificateError }}"}},
+	{Fail: &troubleshootv1beta2.SingleOutcome{When: "bound == false", Message: "Cannot bind to the LDAP server: {{ .Error }}"}},
+	{Fail: &troubleshootv1beta2.SingleOutcome{When: "searched == false", Message: "Cannot search the LDAP server: {{ .Error }}"}},
+	{Warn: &troubleshootv1beta2.SingleOutcome{When: "tls == false", Message: "The connection to the LDAP server is not encrypted and credentials are sent in plain text"}},
+	{Pass: &troubleshootv1beta2.SingleOutcome{Message: "The LDAP server is trusted and can be searched"}},
+}
+
+// ldapTemplateData is passed to the messages of the outcomes
+type ldapTemplateData struct {
+	Error            string
+	CertificateError string
+	// Latency is the time it took to connect and bind, in milliseconds
+	Latency int64
+	Entries int
+	// Subject and Issuer are of the certificate of the server, and NotAfter when it expires
+	Subject  string
+	Issuer   string
+	NotAfter string
+
+	connected bool
+	tls       bool
+	bound     bool
+	searched  bool
+}
+
+type AnalyzeLDAP struct {
+	analyzer *troubleshootv1beta2.LDAPAnalyze
+}
+
+func (a *AnalyzeLDAP) Title() string {
+	if a.analyzer.CheckName != "" {
+		return a.analyzer.CheckName
+	}
+
+	return "LDAP"
+}
+
+func (a *AnalyzeLDAP) IsExcluded() (bool, error) {
+	return isExcluded(a.analyzer.Exclude)
+}
+
+func (a *AnalyzeLDAP) collectorName() string {
+	if a.analyzer.CollectorName != "" {
+		return a.analyzer.CollectorName
+	}
+	return "ldap"
+}
+
+func (a *AnalyzeLDAP) Analyze(getFile getCollectedFileContents, findFiles getChildCollectedFileContents) ([]*AnalyzeResult, error) {
+	fullPath := path.Join("ldap", fmt.Sprintf("%s.json", a.collectorName()))
+	collected, err := getFile(fullPath)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read collected file name: %s", fullPath)
+	}
+
+	connection := collect.LDAPConnection{}
+	if err := json.Unmarshal(collected, &connection); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal ldap connection")
+	}
+
+	data := &ldapTemplateData{
+		Error:            connection.Error,
+		CertificateError: connection.CertificateError,
+		Latency:          connection.LatencyMilliseconds,
+		Entries:          connection.Entries,
+		connected:        connection.IsConnected,
+		tls:              connection.TLS != nil,
+		bound:            connection.IsBound,
+		searched:         connection.IsSearched,
+	}
+	data.Subject, data.Issuer, data.NotAfter = serverCertificate(connection.TLS)
+
+	outcomes := a.analyzer.Outcomes
+	if len(outcomes) == 0 {
+		outcomes = defaultLDAPOutcomes
+	}
+
+	result, err := analyzeTemplatedOutcomes(a.Title(), a.analyzer.Strict.BoolOrDefaultFalse(), outcomes, data, func(when string) (bool, error) {
+		return compareLDAPConditionalToActual(when, data)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		return []*AnalyzeResult{}, nil
+	}
+	return []*AnalyzeResult{result}, nil
+}
+
+// compareLDAPConditionalToActual evaluates a when clause against an LDAP server. Supported
+// conditions are connected, trusted, tls, bound and searched compared to true or false, e.g.
+// "trusted == false", entries compared to a number, and latency compared to a number of
+// milliseconds or a duration, e.g. "latency > 2s".
+func compareLDAPConditionalToActual(conditional string, data *ldapTemplateData) (bool, error) {
+	parts := strings.Fields(conditional)
+	if len(parts) != 3 {
+		return false, errors.Errorf("unable to parse conditional %q", conditional)
+	}
+
+	switch parts[0] {
+	case "connected":
+		return compareBoolConditional(parts[1], parts[2], data.connected)
+	case "trusted":
+		return compareBoolConditional(parts[1], parts[2], data.connected && data.CertificateError == "")
+	case "tls":
+		return compareBoolConditional(parts[1], parts[2], data.tls)
+	case "bound":
+		return compareBoolConditional(parts[1], parts[2], data.bound)
+	case "searched":
+		return compareBoolConditional(parts[1], parts[2], data.searched)
+	case "entries":
+		return compareActualToWhen(parts[1]+" "+parts[2], data.Entries)
+	case "latency":
+		return compareLatencyConditional(parts[1], parts[2], data.Latency)
+	}
+	return false, errors.Errorf("unknown condition %q, must be one of connected, trusted, tls, bound, searched, entries or latency", parts[0])
+}
+
+// compareLatencyConditional compares a latency in milliseconds to the value of a condition, a
+// number of milliseconds or a duration, e.g. "> 500" or "> 1.5s"
+func compareLatencyConditional(operator string, value string, actualMilliseconds int64) (bool, error) {
+	if duration, err := time.ParseDuration(value); err == nil {
+		value = strconv.FormatInt(duration.Milliseconds(), 10)
+	}
+	return compareActualToWhen(operator+" "+value, int(actualMilliseconds))
+}
+
+// serverCertificate returns the subject, the issuer and the expiry of the certificate a server
+// presented in a handshake, empty without one
+func serverCertificate(handshake *collect.HTTPTLS) (subject string, issuer string, notAfter string) {
+	if handshake == nil || len(handshake.PeerCertificates) == 0 {
+		return "", "", ""
+	}
+	cert := handshake.PeerCertificates[0]
+	return cert.Subject, cert.Issuer, cert.NotAfter.Format(time.RFC3339)
+}
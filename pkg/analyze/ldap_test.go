@@ -0,0 +1,99 @@
+package analyzer
+
+import (
+	"testing"
+
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyzeLDAP(t *testing.T) {
+	tests := []struct {
+		name       string
+		analyzer   *troubleshootv1beta2.LDAPAnalyze
+		connection string
+		want       *AnalyzeResult
+		wantErr    bool
+	}{
+		{
+			name:       "not connected",
+			analyzer:   &troubleshootv1beta2.LDAPAnalyze{},
+			connection: `{"isConnected": false, "error": "failed to connect: dial tcp: connection refused"}`,
+			want:       &AnalyzeResult{IsFail: true, Message: "Cannot connect to the LDAP server: failed to connect: dial tcp: connection refused"},
+		},
+		{
+			name:     "certificate not trusted",
+			analyzer: &troubleshootv1beta2.LDAPAnalyze{},
+			connection: `{"isConnected": true, "certificateError": "x509: certificate signed by unknown authority",
+			  "tls": {"version": "TLS 1.3", "peerCertificates": [{"subject": "CN=ldap.example.com", "issuer": "CN=Example CA"}]}}`,
+			want: &AnalyzeResult{IsFail: true, Message: "The certificate of the LDAP server is not trusted: x509: certificate signed by unknown authority"},
+		},
+		{
+			name:       "bind fails",
+			analyzer:   &troubleshootv1beta2.LDAPAnalyze{},
+			connection: `{"isConnected": true, "tls": {"version": "TLS 1.3"}, "error": "failed to bind: LDAP Result Code 49 \"Invalid Credentials\""}`,
+			want:       &AnalyzeResult{IsFail: true, Message: "Cannot bind to the LDAP server: failed to bind: LDAP Result Code 49 \"Invalid Credentials\""},
+		},
+		{
+			name:       "plain text",
+			analyzer:   &troubleshootv1beta2.LDAPAnalyze{},
+			connection: `{"isConnected": true, "isBound": true, "isSearched": true, "entries": 1}`,
+			want:       &AnalyzeResult{IsWarn: true, Message: "The connection to the LDAP server is not encrypted and credentials are sent in plain text"},
+		},
+		{
+			name:       "healthy",
+			analyzer:   &troubleshootv1beta2.LDAPAnalyze{},
+			connection: `{"isConnected": true, "tls": {"version": "TLS 1.3"}, "isBound": true, "isSearched": true, "entries": 1}`,
+			want:       &AnalyzeResult{IsPass: true, Message: "The LDAP server is trusted and can be searched"},
+		},
+		{
+			name: "custom outcomes",
+			analyzer: &troubleshootv1beta2.LDAPAnalyze{
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{Fail: &troubleshootv1beta2.SingleOutcome{When: "entries == 0", Message: "User not found"}},
+					{Warn: &troubleshootv1beta2.SingleOutcome{When: "latency > 1s", Message: "Took {{ .Latency }}ms, certificate of {{ .Subject }} expires {{ .NotAfter }}"}},
+					{Pass: &troubleshootv1beta2.SingleOutcome{Message: "ok"}},
+				},
+			},
+			connection: `{"isConnected": true, "isBound": true, "isSearched": true, "entries": 1, "latencyMilliseconds": 1500,
+			  "tls": {"version": "TLS 1.3", "peerCertificates": [{"subject": "CN=ldap.example.com", "notAfter": "2027-01-02T03:04:05Z"}]}}`,
+			want: &AnalyzeResult{IsWarn: true, Message: "Took 1500ms, certificate of CN=ldap.example.com expires 2027-01-02T03:04:05Z"},
+		},
+		{
+			name: "unknown condition",
+			analyzer: &troubleshootv1beta2.LDAPAnalyze{
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{Fail: &troubleshootv1beta2.SingleOutcome{When: "users == 0"}},
+				},
+			},
+			connection: `{"isConnected": true}`,
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := require.New(t)
+
+			getFile := func(path string) ([]byte, error) {
+				req.Equal("ldap/ldap.json", path)
+				return []byte(tt.connection), nil
+			}
+
+			a := AnalyzeLDAP{analyzer: tt.analyzer}
+			results, err := a.Analyze(getFile, nil)
+			if tt.wantErr {
+				req.Error(err)
+				return
+			}
+			req.NoError(err)
+			req.Len(results, 1)
+
+			assert.Equal(t, tt.want.IsPass, results[0].IsPass)
+			assert.Equal(t, tt.want.IsWarn, results[0].IsWarn)
+			assert.Equal(t, tt.want.IsFail, results[0].IsFail)
+			assert.Equal(t, tt.want.Message, results[0].Message)
+		})
+	}
+}
@@ -0,0 +1,230 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/blang/semver/v4"
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/collect"
+)
+
+// ManagedClusterDefaultOutcomes are evaluated when the analyzer sets no outcomes
+var ManagedClusterDefaultOutcomes = []*troubleshootv1beta2.Outcome{
+	{
+		Pass: &troubleshootv1beta2.SingleOutcome{
+			When:    "managed == false",
+			Message: "The cluster is not managed by EKS, GKE or AKS",
+		},
+	},
+	{
+		Fail: &troubleshootv1beta2.SingleOutcome{
+			When:    "failures > 0",
+			Message: "The {{ .Provider }} cluster is misconfigured: {{ .Failures }}",
+		},
+	},
+	{
+		Warn: &troubleshootv1beta2.SingleOutcome{
+			When:    "warnings > 0",
+			Message: "The configuration of the {{ .Provider }} cluster needs attention: {{ .Warnings }}",
+		},
+	},
+	{
+		Pass: &troubleshootv1beta2.SingleOutcome{
+			Message: "No misconfiguration of the {{ .Provider }} cluster was found",
+		},
+	},
+}
+
+// minPrefixDelegationCNIVersion is the first version of the Amazon VPC CNI that assigns prefixes
+// to the network interfaces of nodes
+var minPrefixDelegationCNIVersion = semver.MustParse("1.9.0")
+
+// kubenetMaxNodes is the number of nodes of a kubenet cluster on AKS, limited by the number of
+// routes of a route table
+const kubenetMaxNodes = 400
+
+// managedClusterTemplateData is passed to the messages of the outcomes
+type managedClusterTemplateData struct {
+	// Provider is EKS, GKE or AKS
+	Provider string
+	// Failures and Warnings are the misconfigurations that were found, semicolon separated
+	Failures string
+	Warnings string
+
+	report *managedClusterReport
+}
+
+// managedClusterReport is the misconfigurations of the provider of the cluster
+type managedClusterReport struct {
+	managed  bool
+	failures []string
+	warnings []string
+}
+
+type AnalyzeManagedCluster struct {
+	analyzer *troubleshootv1beta2.ManagedClusterAnalyze
+}
+
+func (a *AnalyzeManagedCluster) Title() string {
+	if a.analyzer.CheckName != "" {
+		return a.analyzer.CheckName
+	}
+	return "Managed Cluster"
+}
+
+func (a *AnalyzeManagedCluster) IsExcluded() (bool, error) {
+	return isExcluded(a.analyzer.Exclude)
+}
+
+func (a *AnalyzeManagedCluster) Analyze(getFile getCollectedFileContents, findFiles getChildCollectedFileContents) ([]*AnalyzeResult, error) {
+	fullPath := collect.ManagedClusterOutputPath(a.analyzer.CollectorName)
+	collected, err := getFile(fullPath)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read collected file name: %s", fullPath)
+	}
+
+	info := collect.ManagedClusterInfo{}
+	if err := json.Unmarshal(collected, &info); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse %s", fullPath)
+	}
+
+	report := newManagedClusterReport(info)
+	data := &managedClusterTemplateData{
+		Provider: strings.ToUpper(info.Provider),
+		Failures: strings.Join(report.failures, "; "),
+		Warnings: strings.Join(report.warnings, "; "),
+		report:   report,
+	}
+
+	outcomes := a.analyzer.Outcomes
+	if len(outcomes) == 0 {
+		outcomes = ManagedClusterDefaultOutcomes
+	}
+
+	result, err := analyzeTemplatedOutcomes(a.Title(), a.analyzer.Strict.BoolOrDefaultFalse(), outcomes, data, func(when string) (bool, error) {
+		return compareManagedCluster(data.report, when)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		return []*AnalyzeResult{}, nil
+	}
+	return []*AnalyzeResult{result}, nil
+}
+
+// newManagedClusterReport flags the misconfigurations of the provider of the cluster
+func newManagedClusterReport(info collect.ManagedClusterInfo) *managedClusterReport {
+	report := &managedClusterReport{
+		managed:  info.Provider != "",
+		failures: []string{},
+		warnings: []string{},
+	}
+	fail := func(format string, args ...interface{}) {
+		report.failures = append(report.failures, fmt.Sprintf(format, args...))
+	}
+	warn := func(format string, args ...interface{}) {
+		report.warnings = append(report.warnings, fmt.Sprintf(format, args...))
+	}
+
+	if eks := info.EKS; eks != nil {
+		if cni := eks.CNI; cni != nil {
+			if cni.Unavailable > 0 {
+				fail("%d of %d aws-node pods of the Amazon VPC CNI are unavailable, pods on their nodes get no IP addresses", cni.Unavailable, cni.Desired)
+			}
+			if cni.Env["ENABLE_PREFIX_DELEGATION"] == "true" {
+				if version, ok := imageTagVersion(cni.Image); ok && version.LT(minPrefixDelegationCNIVersion) {
+					fail("prefix delegation requires the Amazon VPC CNI %s or later, aws-node runs %s", minPrefixDelegationCNIVersion, cni.Image)
+				}
+			}
+		}
+		if agent := eks.PodIdentityAgent; agent != nil && agent.Unavailable > 0 {
+			fail("%d of %d eks-pod-identity-agent pods are unavailable, pods on their nodes get no credentials of EKS Pod Identity", agent.Unavailable, agent.Desired)
+		}
+		if len(eks.RoleServiceAccounts) > 0 {
+			serviceAccounts := strings.Join(eks.RoleServiceAccounts, ", ")
+			if !eks.PodIdentityWebhook {
+				fail("the service accounts %s are annotated with IAM roles but the pod-identity-webhook is not registered, their pods get no credentials", serviceAccounts)
+			}
+			if eks.OIDCIssuer != "" && !strings.HasPrefix(eks.OIDCIssuer, "https://oidc.eks.") {
+				fail("the service accounts %s are annotated with IAM roles but the service account issuer %s is not the IAM OIDC provider of EKS", serviceAccounts, eks.OIDCIssuer)
+			}
+		}
+	}
+
+	if gke := info.GKE; gke != nil {
+		if len(gke.WorkloadIdentityServiceAccounts) > 0 && !gke.Autopilot {
+			serviceAccounts := strings.Join(gke.WorkloadIdentityServiceAccounts, ", ")
+			pools := []string{}
+			for _, pool := range gke.NodePools {
+				if !slices.Contains(gke.WorkloadIdentityNodePools, pool) {
+					pools = append(pools, pool)
+				}
+			}
+			if len(gke.WorkloadIdentityNodePools) == 0 {
+				fail("the service accounts %s are annotated with Google service accounts but no node pool runs the GKE metadata server, Workload Identity is not enabled", serviceAccounts)
+			} else if len(pools) > 0 {
+				warn("the node pools %s do not run the GKE metadata server, pods scheduled on them get no credentials of Workload Identity", strings.Join(pools, ", "))
+			}
+		}
+		for _, rejection := range gke.AutopilotRejections {
+			fail("Autopilot rejects the pods of %s %s/%s: %s", rejection.Kind, rejection.Namespace, rejection.Name, rejection.Message)
+		}
+	}
+
+	if aks := info.AKS; aks != nil {
+		if aks.NetworkPlugin == collect.AKSNetworkPluginKubenet {
+			if aks.Nodes > kubenetMaxNodes {
+				fail("kubenet supports at most %d nodes, the routes of a route table, the cluster has %d", kubenetMaxNodes, aks.Nodes)
+			}
+			warn("kubenet is retired on 31 March 2028, the cluster must be migrated to Azure CNI Overlay")
+		}
+		if cns := aks.CNS; cns != nil && cns.Unavailable > 0 {
+			fail("%d of %d azure-cns pods are unavailable, pods on their nodes get no IP addresses", cns.Unavailable, cns.Desired)
+		}
+	}
+
+	return report
+}
+
+// imageTagVersion returns the version of the tag of an image, e.g. v1.18.3-eksbuild.1, without the
+// suffix of the build which is not a prerelease
+func imageTagVersion(image string) (semver.Version, bool) {
+	name := image[strings.LastIndex(image, "/")+1:]
+	name, _, _ = strings.Cut(name, "@")
+	_, tag, found := strings.Cut(name, ":")
+	if !found {
+		return semver.Version{}, false
+	}
+	version, err := semver.ParseTolerant(tag)
+	if err != nil {
+		return semver.Version{}, false
+	}
+	version.Pre = nil
+	return version, true
+}
+
+// compareManagedCluster evaluates a when clause against the report. Supported conditions are
+// whether the cluster is managed, e.g. "managed == true", and the counts failures and warnings,
+// e.g. "failures > 0".
+func compareManagedCluster(report *managedClusterReport, when string) (bool, error) {
+	parts := strings.Fields(when)
+	if len(parts) != 3 {
+		return false, fmt.Errorf("expected 3 parts in when %q, got %d", when, len(parts))
+	}
+	key, condition := parts[0], parts[1]+" "+parts[2]
+
+	switch key {
+	case "managed":
+		return compareBoolConditional(parts[1], parts[2], report.managed)
+	case "failures":
+		return compareActualToWhen(condition, len(report.failures))
+	case "warnings":
+		return compareActualToWhen(condition, len(report.warnings))
+	}
+	return false, fmt.Errorf("unsupported condition %q, must be one of managed, failures or warnings", key)
+}
@@ -0,0 +1,135 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"testing"
+
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/collect"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyzeManagedCluster(t *testing.T) {
+	tests := []struct {
+		name string
+		info collect.ManagedClusterInfo
+		want *AnalyzeResult
+	}{
+		{
+			name: "not managed",
+			info: collect.ManagedClusterInfo{},
+			want: &AnalyzeResult{IsPass: true, Message: "The cluster is not managed by EKS, GKE or AKS"},
+		},
+		{
+			name: "eks",
+			info: collect.ManagedClusterInfo{
+				Provider: "eks",
+				EKS: &collect.EKSInfo{
+					CNI: &collect.ManagedDaemonSet{
+						Name:    "aws-node",
+						Image:   "602401143452.dkr.ecr.us-west-2.amazonaws.com/amazon-k8s-cni:v1.8.0-eksbuild.1",
+						Env:     map[string]string{"ENABLE_PREFIX_DELEGATION": "true"},
+						Desired: 3,
+						Ready:   3,
+					},
+					OIDCIssuer:          "https://kubernetes.default.svc",
+					PodIdentityWebhook:  true,
+					RoleServiceAccounts: []string{"app/uploader"},
+				},
+			},
+			want: &AnalyzeResult{IsFail: true, Message: "The EKS cluster is misconfigured: " +
+				"prefix delegation requires the Amazon VPC CNI 1.9.0 or later, aws-node runs 602401143452.dkr.ecr.us-west-2.amazonaws.com/amazon-k8s-cni:v1.8.0-eksbuild.1; " +
+				"the service accounts app/uploader are annotated with IAM roles but the service account issuer https://kubernetes.default.svc is not the IAM OIDC provider of EKS"},
+		},
+		{
+			name: "eks without misconfigurations",
+			info: collect.ManagedClusterInfo{
+				Provider: "eks",
+				EKS: &collect.EKSInfo{
+					CNI:                 &collect.ManagedDaemonSet{Name: "aws-node", Image: "amazon-k8s-cni:v1.9.0-eksbuild.1", Env: map[string]string{"ENABLE_PREFIX_DELEGATION": "true"}},
+					OIDCIssuer:          "https://oidc.eks.us-west-2.amazonaws.com/id/EXAMPLED539D4633E53DE1B71EXAMPLE",
+					PodIdentityWebhook:  true,
+					RoleServiceAccounts: []string{"app/uploader"},
+				},
+			},
+			want: &AnalyzeResult{IsPass: true, Message: "No misconfiguration of the EKS cluster was found"},
+		},
+		{
+			name: "gke node pools without workload identity",
+			info: collect.ManagedClusterInfo{
+				Provider: "gke",
+				GKE: &collect.GKEInfo{
+					NodePools:                       []string{"default-pool", "gpu"},
+					WorkloadIdentityNodePools:       []string{"default-pool"},
+					WorkloadIdentityServiceAccounts: []string{"app/uploader"},
+				},
+			},
+			want: &AnalyzeResult{IsWarn: true, Message: "The configuration of the GKE cluster needs attention: " +
+				"the node pools gpu do not run the GKE metadata server, pods scheduled on them get no credentials of Workload Identity"},
+		},
+		{
+			name: "gke autopilot rejections",
+			info: collect.ManagedClusterInfo{
+				Provider: "gke",
+				GKE: &collect.GKEInfo{
+					Autopilot: true,
+					AutopilotRejections: []collect.ManagedClusterRejection{
+						{Kind: "DaemonSet", Namespace: "app", Name: "node-agent", Message: "GKE Warden rejected the request"},
+					},
+				},
+			},
+			want: &AnalyzeResult{IsFail: true, Message: "The GKE cluster is misconfigured: Autopilot rejects the pods of DaemonSet app/node-agent: GKE Warden rejected the request"},
+		},
+		{
+			name: "aks kubenet",
+			info: collect.ManagedClusterInfo{
+				Provider: "aks",
+				AKS:      &collect.AKSInfo{NetworkPlugin: "kubenet", Nodes: 12},
+			},
+			want: &AnalyzeResult{IsWarn: true, Message: "The configuration of the AKS cluster needs attention: " +
+				"kubenet is retired on 31 March 2028, the cluster must be migrated to Azure CNI Overlay"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := json.Marshal(tt.info)
+			require.NoError(t, err)
+			getFile := func(filename string) ([]byte, error) {
+				require.Equal(t, "managed-cluster/managed-cluster.json", filename)
+				return b, nil
+			}
+
+			a := AnalyzeManagedCluster{analyzer: &troubleshootv1beta2.ManagedClusterAnalyze{}}
+			results, err := a.Analyze(getFile, nil)
+			require.NoError(t, err)
+			require.Len(t, results, 1)
+
+			assert.Equal(t, tt.want.IsPass, results[0].IsPass)
+			assert.Equal(t, tt.want.IsWarn, results[0].IsWarn)
+			assert.Equal(t, tt.want.IsFail, results[0].IsFail)
+			assert.Equal(t, "Managed Cluster", results[0].Title)
+			assert.Equal(t, tt.want.Message, results[0].Message)
+		})
+	}
+}
+
+func Test_compareManagedCluster(t *testing.T) {
+	report := &managedClusterReport{managed: true, failures: []string{"kubenet supports at most 400 nodes"}, warnings: []string{}}
+
+	got, err := compareManagedCluster(report, "managed == true")
+	require.NoError(t, err)
+	assert.True(t, got)
+
+	got, err = compareManagedCluster(report, "failures > 0")
+	require.NoError(t, err)
+	assert.True(t, got)
+
+	got, err = compareManagedCluster(report, "warnings > 0")
+	require.NoError(t, err)
+	assert.False(t, got)
+
+	_, err = compareManagedCluster(report, "provider == eks")
+	assert.Error(t, err)
+}
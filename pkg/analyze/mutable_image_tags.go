@@ -0,0 +1,308 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/troubleshoot/internal/util"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/collect"
+	"github.com/replicatedhq/troubleshoot/pkg/constants"
+	corev1 "k8s.io/api/core/v1"
+)
+
+var defaultMutableImageTagsOutcomes = []*troubleshootv1beta2.Outcome{
+	{Warn: &troubleshootv1beta2.SingleOutcome{When: "driftedTags > 0", Message: "The tags of images of {{ .Kind }} {{ .Namespace }}/{{ .Name }} have moved since its pods started: {{ .DriftedImages }}"}},
+	{Warn: &troubleshootv1beta2.SingleOutcome{When: "mutableTags > 0", Message: "{{ .Kind }} {{ .Namespace }}/{{ .Name }} runs images by mutable tags: {{ .MutableImages }}. Pin the images by digest or full version."}},
+	{Pass: &troubleshootv1beta2.SingleOutcome{Message: "No workloads run images by mutable tags"}},
+}
+
+// floatingVersionTag matches tags that name a major or minor version, which move with each release
+// of a patch, e.g. 1, v1.27 or 1.27-alpine
+var floatingVersionTag = regexp.MustCompile(`^v?\d+(\.\d+)?(-[a-z][a-z0-9.]*)?$`)
+
+// channelTags are tags that conventionally follow a release channel or branch
+var channelTags = []string{"latest", "stable", "edge", "nightly", "main", "master"}
+
+// mutableImageTagsTemplateData is passed to the messages of the outcomes. It is empty when no
+// workload runs images by mutable tags.
+type mutableImageTagsTemplateData struct {
+	Kind          string
+	Namespace     string
+	Name          string
+	MutableImages string
+	DriftedImages string
+
+	mutable []string
+	drifted []string
+}
+
+type AnalyzeMutableImageTags struct {
+	analyzer *troubleshootv1beta2.MutableImageTags
+}
+
+func (a *AnalyzeMutableImageTags) Title() string {
+	if a.analyzer.CheckName != "" {
+		return a.analyzer.CheckName
+	}
+
+	return "Mutable Image Tags"
+}
+
+func (a *AnalyzeMutableImageTags) IsExcluded() (bool, error) {
+	return isExcluded(a.analyzer.Exclude)
+}
+
+func (a *AnalyzeMutableImageTags) Analyze(getFile getCollectedFileContents, findFiles getChildCollectedFileContents) ([]*AnalyzeResult, error) {
+	resolvedDigests, err := a.resolvedDigests(getFile)
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := findFiles(filepath.Join(constants.CLUSTER_RESOURCES_DIR, constants.CLUSTER_RESOURCES_PODS, "*.json"), nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read collected pods")
+	}
+
+	workloads := map[string]*mutableImageTagsTemplateData{}
+	for name, content := range files {
+		pods, err := decodeCollectedItems[corev1.Pod](content)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to unmarshal %s", name)
+		}
+		for _, pod := range pods {
+			if len(a.analyzer.Namespaces) > 0 && !slices.Contains(a.analyzer.Namespaces, pod.Namespace) {
+				continue
+			}
+
+			kind, workloadName := podWorkload(pod)
+			key := fmt.Sprintf("%s/%s/%s", pod.Namespace, kind, workloadName)
+			data, ok := workloads[key]
+			if !ok {
+				data = &mutableImageTagsTemplateData{Kind: kind, Namespace: pod.Namespace, Name: workloadName}
+				workloads[key] = data
+			}
+			addMutableImages(data, pod, resolvedDigests)
+		}
+	}
+
+	keys := []string{}
+	for key, data := range workloads {
+		if len(data.mutable) > 0 {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	outcomes := a.analyzer.Outcomes
+	if len(outcomes) == 0 {
+		outcomes = defaultMutableImageTagsOutcomes
+	}
+
+	results := []*AnalyzeResult{}
+	for _, key := range keys {
+		data := workloads[key]
+		sort.Strings(data.mutable)
+		sort.Strings(data.drifted)
+		data.MutableImages = strings.Join(data.mutable, ", ")
+		data.DriftedImages = strings.Join(data.drifted, ", ")
+
+		result, err := a.analyzeWorkload(data, outcomes)
+		if err != nil {
+			return nil, err
+		}
+		if result != nil {
+			result.InvolvedObject = &corev1.ObjectReference{
+				Kind:      data.Kind,
+				Namespace: data.Namespace,
+				Name:      data.Name,
+			}
+			results = append(results, result)
+		}
+	}
+
+	if len(results) == 0 {
+		result, err := a.analyzeWorkload(&mutableImageTagsTemplateData{}, outcomes)
+		if err != nil {
+			return nil, err
+		}
+		if result != nil {
+			results = append(results, result)
+		}
+	}
+
+	return results, nil
+}
+
+// resolvedDigests returns the digests the registryImages collector resolved, by image. The
+// collector is optional unless it is named.
+func (a *AnalyzeMutableImageTags) resolvedDigests(getFile getCollectedFileContents) (map[string]string, error) {
+	collectorName := a.analyzer.CollectorName
+	if collectorName == "" {
+		collectorName = "images"
+	}
+
+	fullPath := path.Join("registry", fmt.Sprintf("%s.json", collectorName))
+	collected, err := getFile(fullPath)
+	if err != nil || len(collected) == 0 {
+		if a.analyzer.CollectorName != "" {
+			return nil, errors.Wrapf(err, "failed to read collected file name: %s", fullPath)
+		}
+		return nil, nil
+	}
+
+	registryInfo := collect.RegistryInfo{}
+	if err := json.Unmarshal(collected, &registryInfo); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal registry images")
+	}
+
+	digests := map[string]string{}
+	for image, registryImage := range registryInfo.Images {
+		if registryImage.Digest != "" {
+			digests[image] = registryImage.Digest
+		}
+	}
+	return digests, nil
+}
+
+// analyzeWorkload returns the result of the first outcome whose condition matches the workload,
+// or nil when none does
+func (a *AnalyzeMutableImageTags) analyzeWorkload(data *mutableImageTagsTemplateData, outcomes []*troubleshootv1beta2.Outcome) (*AnalyzeResult, error) {
+	for _, outcome := range outcomes {
+		result := &AnalyzeResult{
+			IconKey: "kubernetes_registry_analyze",
+			IconURI: "https://troubleshoot.sh/images/analyzer-icons/registry-analyze.svg",
+			Strict:  a.analyzer.Strict.BoolOrDefaultFalse(),
+		}
+
+		var singleOutcome *troubleshootv1beta2.SingleOutcome
+		switch {
+		case outcome.Fail != nil:
+			singleOutcome = outcome.Fail
+			result.IsFail = true
+		case outcome.Warn != nil:
+			singleOutcome = outcome.Warn
+			result.IsWarn = true
+		case outcome.Pass != nil:
+			singleOutcome = outcome.Pass
+			result.IsPass = true
+		default:
+			continue
+		}
+
+		if singleOutcome.When != "" {
+			match, err := compareMutableImageTagsConditionalToActual(singleOutcome.When, data)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to evaluate when %q", singleOutcome.When)
+			}
+			if !match {
+				continue
+			}
+		}
+
+		var err error
+		result.Title, err = util.RenderTemplate(a.Title(), data)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to render title template")
+		}
+		result.Message, err = util.RenderTemplate(singleOutcome.Message, data)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to render message template")
+		}
+		result.URI = singleOutcome.URI
+		result.Remediation = singleOutcome.Remediation
+		result.Condition = singleOutcome.When
+		return result, nil
+	}
+
+	return nil, nil
+}
+
+func compareMutableImageTagsConditionalToActual(conditional string, data *mutableImageTagsTemplateData) (bool, error) {
+	parts := strings.Fields(conditional)
+	if len(parts) != 3 {
+		return false, errors.Errorf("unable to parse conditional %q", conditional)
+	}
+
+	var actual int
+	switch parts[0] {
+	case "mutableTags":
+		actual = len(data.mutable)
+	case "driftedTags":
+		actual = len(data.drifted)
+	default:
+		return false, errors.Errorf("unknown condition %q, must be one of mutableTags or driftedTags", parts[0])
+	}
+
+	return compareActualToWhen(parts[1]+" "+parts[2], actual)
+}
+
+// addMutableImages records the images of the containers of the pod that are run by a mutable tag,
+// and those whose tag resolved to a digest other than the one the container runs
+func addMutableImages(data *mutableImageTagsTemplateData, pod corev1.Pod, resolvedDigests map[string]string) {
+	containers := append(append([]corev1.Container{}, pod.Spec.InitContainers...), pod.Spec.Containers...)
+	statuses := append(append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+
+	for _, container := range containers {
+		if !isMutableImageTag(container.Image) {
+			continue
+		}
+		if !slices.Contains(data.mutable, container.Image) {
+			data.mutable = append(data.mutable, container.Image)
+		}
+
+		resolved, ok := resolvedDigests[container.Image]
+		if !ok || slices.Contains(data.drifted, container.Image) {
+			continue
+		}
+		for _, status := range statuses {
+			if status.Name != container.Name {
+				continue
+			}
+			// the image ID is the repository digest the image was pulled by, e.g.
+			// docker.io/library/nginx@sha256:...
+			_, running, found := strings.Cut(status.ImageID, "@")
+			if found && running != resolved {
+				data.drifted = append(data.drifted, container.Image)
+			}
+		}
+	}
+}
+
+// isMutableImageTag returns true when the image is not pinned by digest and its tag is omitted,
+// follows a release channel such as latest, or names a major or minor version
+func isMutableImageTag(image string) bool {
+	if strings.Contains(image, "@") {
+		return false
+	}
+
+	// the last path component holds the tag, the registry host may have a port
+	name := image[strings.LastIndex(image, "/")+1:]
+	_, tag, found := strings.Cut(name, ":")
+	if !found {
+		return true
+	}
+	return slices.Contains(channelTags, tag) || floatingVersionTag.MatchString(tag)
+}
+
+// podWorkload returns the kind and name of the workload that owns the pod, the Deployment for pods
+// of its ReplicaSets, or the pod itself when it has no owner
+func podWorkload(pod corev1.Pod) (string, string) {
+	for _, owner := range pod.OwnerReferences {
+		if owner.Controller == nil || !*owner.Controller {
+			continue
+		}
+		if hash, ok := pod.Labels["pod-template-hash"]; ok && owner.Kind == "ReplicaSet" && strings.HasSuffix(owner.Name, "-"+hash) {
+			return "Deployment", strings.TrimSuffix(owner.Name, "-"+hash)
+		}
+		return owner.Kind, owner.Name
+	}
+	return "Pod", pod.Name
+}
@@ -0,0 +1,152 @@
+package analyzer
+
+import (
+	"os"
+	"testing"
+
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyzeMutableImageTags(t *testing.T) {
+	pods := `{"items": [
+	  {
+	    "metadata": {"name": "web-5d4f8b7c9-abcde", "namespace": "prod", "labels": {"pod-template-hash": "5d4f8b7c9"},
+	      "ownerReferences": [{"kind": "ReplicaSet", "name": "web-5d4f8b7c9", "controller": true}]},
+	    "spec": {"containers": [{"name": "web", "image": "nginx:1.27"}, {"name": "sidecar", "image": "registry.example.com/proxy:2.4.1"}]},
+	    "status": {"containerStatuses": [{"name": "web", "imageID": "docker.io/library/nginx@sha256:aaaa"}]}
+	  },
+	  {
+	    "metadata": {"name": "web-5d4f8b7c9-fghij", "namespace": "prod", "labels": {"pod-template-hash": "5d4f8b7c9"},
+	      "ownerReferences": [{"kind": "ReplicaSet", "name": "web-5d4f8b7c9", "controller": true}]},
+	    "spec": {"containers": [{"name": "web", "image": "nginx:1.27"}, {"name": "sidecar", "image": "registry.example.com/proxy:2.4.1"}]},
+	    "status": {"containerStatuses": [{"name": "web", "imageID": "docker.io/library/nginx@sha256:aaaa"}]}
+	  },
+	  {
+	    "metadata": {"name": "db-0", "namespace": "prod",
+	      "ownerReferences": [{"kind": "StatefulSet", "name": "db", "controller": true}]},
+	    "spec": {"initContainers": [{"name": "init", "image": "busybox"}], "containers": [{"name": "db", "image": "postgres@sha256:bbbb"}]}
+	  },
+	  {
+	    "metadata": {"name": "debug", "namespace": "dev"},
+	    "spec": {"containers": [{"name": "debug", "image": "localhost:5000/tools:latest"}]}
+	  }
+	]}`
+
+	tests := []struct {
+		name     string
+		analyzer *troubleshootv1beta2.MutableImageTags
+		images   string
+		want     []*AnalyzeResult
+		wantErr  bool
+	}{
+		{
+			name:     "mutable tags",
+			analyzer: &troubleshootv1beta2.MutableImageTags{},
+			want: []*AnalyzeResult{
+				{IsWarn: true, Message: "Pod dev/debug runs images by mutable tags: localhost:5000/tools:latest. Pin the images by digest or full version."},
+				{IsWarn: true, Message: "Deployment prod/web runs images by mutable tags: nginx:1.27. Pin the images by digest or full version."},
+				{IsWarn: true, Message: "StatefulSet prod/db runs images by mutable tags: busybox. Pin the images by digest or full version."},
+			},
+		},
+		{
+			name:     "drifted tags",
+			analyzer: &troubleshootv1beta2.MutableImageTags{Namespaces: []string{"prod"}},
+			images:   `{"images": {"nginx:1.27": {"exists": true, "digest": "sha256:cccc", "resolvedAt": "2026-01-01T00:00:00Z"}}}`,
+			want: []*AnalyzeResult{
+				{IsWarn: true, Message: "The tags of images of Deployment prod/web have moved since its pods started: nginx:1.27"},
+				{IsWarn: true, Message: "StatefulSet prod/db runs images by mutable tags: busybox. Pin the images by digest or full version."},
+			},
+		},
+		{
+			name:     "tag has not moved",
+			analyzer: &troubleshootv1beta2.MutableImageTags{Namespaces: []string{"prod"}},
+			images:   `{"images": {"nginx:1.27": {"exists": true, "digest": "sha256:aaaa"}}}`,
+			want: []*AnalyzeResult{
+				{IsWarn: true, Message: "Deployment prod/web runs images by mutable tags: nginx:1.27. Pin the images by digest or full version."},
+				{IsWarn: true, Message: "StatefulSet prod/db runs images by mutable tags: busybox. Pin the images by digest or full version."},
+			},
+		},
+		{
+			name:     "no mutable tags",
+			analyzer: &troubleshootv1beta2.MutableImageTags{Namespaces: []string{"staging"}},
+			want: []*AnalyzeResult{
+				{IsPass: true, Message: "No workloads run images by mutable tags"},
+			},
+		},
+		{
+			name:     "named collector not collected",
+			analyzer: &troubleshootv1beta2.MutableImageTags{CollectorName: "digests"},
+			wantErr:  true,
+		},
+		{
+			name: "unknown condition",
+			analyzer: &troubleshootv1beta2.MutableImageTags{
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{Fail: &troubleshootv1beta2.SingleOutcome{When: "latestTags > 0"}},
+				},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := require.New(t)
+
+			getFile := func(path string) ([]byte, error) {
+				if path == "registry/images.json" && tt.images != "" {
+					return []byte(tt.images), nil
+				}
+				return nil, os.ErrNotExist
+			}
+			findFiles := func(glob string, _ []string) (map[string][]byte, error) {
+				return map[string][]byte{"cluster-resources/pods/all.json": []byte(pods)}, nil
+			}
+
+			a := AnalyzeMutableImageTags{analyzer: tt.analyzer}
+			results, err := a.Analyze(getFile, findFiles)
+			if tt.wantErr {
+				req.Error(err)
+				return
+			}
+			req.NoError(err)
+			req.Len(results, len(tt.want))
+
+			for i, want := range tt.want {
+				assert.Equal(t, want.IsPass, results[i].IsPass)
+				assert.Equal(t, want.IsWarn, results[i].IsWarn)
+				assert.Equal(t, want.IsFail, results[i].IsFail)
+				assert.Equal(t, want.Message, results[i].Message)
+			}
+		})
+	}
+}
+
+func Test_isMutableImageTag(t *testing.T) {
+	tests := []struct {
+		image string
+		want  bool
+	}{
+		{image: "nginx", want: true},
+		{image: "nginx:latest", want: true},
+		{image: "nginx:stable", want: true},
+		{image: "nginx:1", want: true},
+		{image: "nginx:1.27", want: true},
+		{image: "nginx:v1.27", want: true},
+		{image: "nginx:1.27-alpine", want: true},
+		{image: "registry.example.com:5000/app", want: true},
+		{image: "nginx:1.27.3", want: false},
+		{image: "nginx:1.27.3-alpine", want: false},
+		{image: "registry.example.com:5000/app:4f2c9a1", want: false},
+		{image: "nginx@sha256:aaaa", want: false},
+		{image: "nginx:latest@sha256:aaaa", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.image, func(t *testing.T) {
+			assert.Equal(t, tt.want, isMutableImageTag(tt.image))
+		})
+	}
+}
@@ -0,0 +1,192 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/collect"
+)
+
+type AnalyzeNodeLatency struct {
+	analyzer *troubleshootv1beta2.NodeLatencyAnalyze
+}
+
+// nodeLatencyTemplateData is passed to the messages of the outcomes. The pair is the worst one
+// for the metric of the condition that matched.
+type nodeLatencyTemplateData struct {
+	From       string
+	To         string
+	Latency    string
+	PacketLoss string
+}
+
+func (a *AnalyzeNodeLatency) Title() string {
+	title := a.analyzer.CheckName
+	if title == "" {
+		title = "Node Latency"
+	}
+
+	return title
+}
+
+func (a *AnalyzeNodeLatency) IsExcluded() (bool, error) {
+	return isExcluded(a.analyzer.Exclude)
+}
+
+func (a *AnalyzeNodeLatency) Analyze(getFile getCollectedFileContents, findFiles getChildCollectedFileContents) ([]*AnalyzeResult, error) {
+	path := collect.NodeLatencyPath(a.analyzer.CollectorName)
+	collected, err := getFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read collected file %s", path)
+	}
+
+	matrix := collect.NodeLatencyResult{}
+	if err := json.Unmarshal(collected, &matrix); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal node latency result")
+	}
+
+	result, err := a.analyzeMatrix(matrix)
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		return []*AnalyzeResult{}, nil
+	}
+	result.Strict = a.analyzer.Strict.BoolOrDefaultFalse()
+	result.IconKey = "kubernetes"
+
+	return []*AnalyzeResult{result}, nil
+}
+
+// analyzeMatrix returns the result of the first outcome whose condition matches the matrix, or
+// nil when none does
+func (a *AnalyzeNodeLatency) analyzeMatrix(matrix collect.NodeLatencyResult) (*AnalyzeResult, error) {
+	for _, outcome := range a.analyzer.Outcomes {
+		result := &AnalyzeResult{Title: a.Title()}
+
+		var singleOutcome *troubleshootv1beta2.SingleOutcome
+		switch {
+		case outcome.Fail != nil:
+			singleOutcome = outcome.Fail
+			result.IsFail = true
+		case outcome.Warn != nil:
+			singleOutcome = outcome.Warn
+			result.IsWarn = true
+		case outcome.Pass != nil:
+			singleOutcome = outcome.Pass
+			result.IsPass = true
+		default:
+			continue
+		}
+
+		var data any
+		if singleOutcome.When != "" {
+			isMatch, measurement, worst, err := compareNodeLatency(singleOutcome.When, matrix)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to compare node latency with %q", singleOutcome.When)
+			}
+			if !isMatch {
+				continue
+			}
+			result.Condition = singleOutcome.When
+			result.Measurement = measurement
+			data = worst
+		}
+
+		result.Message = renderTemplate(singleOutcome.Message, data)
+		result.URI = singleOutcome.URI
+		result.Remediation = singleOutcome.Remediation
+		return result, nil
+	}
+
+	return nil, nil
+}
+
+// compareNodeLatency evaluates a when clause against the worst pair of the matrix. Supported
+// conditions are:
+//
+//   - "latency <operator> <duration>", compared against the highest average round trip time
+//     of the pairs that answered, e.g. "latency > 50ms"
+//   - "packetLoss <operator> <percent>", compared against the highest packet loss, e.g.
+//     "packetLoss > 5%". Pairs that could not be measured have a packet loss of 100%.
+//
+// A matrix without pairs, e.g. of a single node, has a latency and packet loss of 0.
+func compareNodeLatency(when string, matrix collect.NodeLatencyResult) (bool, *Measurement, *nodeLatencyTemplateData, error) {
+	parts := strings.Fields(when)
+	if len(parts) != 3 {
+		return false, nil, nil, fmt.Errorf("expected 3 parts in when %q, got %d", when, len(parts))
+	}
+
+	operator, err := ParseComparisonOperator(parts[1])
+	if err != nil {
+		return false, nil, nil, errors.Wrapf(err, "failed to parse comparison operator %q", parts[1])
+	}
+
+	var worst *collect.NodeLatencyPair
+	var observed, threshold float64
+	var unit string
+
+	switch parts[0] {
+	case "latency":
+		duration, err := time.ParseDuration(parts[2])
+		if err != nil {
+			return false, nil, nil, errors.Wrapf(err, "failed to parse duration %q", parts[2])
+		}
+		threshold = float64(duration) / float64(time.Millisecond)
+		unit = "ms"
+		for i, pair := range matrix.Pairs {
+			if pair.AvgRTT != nil && (worst == nil || *pair.AvgRTT > observed) {
+				worst = &matrix.Pairs[i]
+				observed = *pair.AvgRTT
+			}
+		}
+	case "packetLoss":
+		threshold, err = strconv.ParseFloat(strings.TrimSuffix(parts[2], "%"), 64)
+		if err != nil {
+			return false, nil, nil, errors.Wrapf(err, "failed to parse percentage %q", parts[2])
+		}
+		unit = "%"
+		for i, pair := range matrix.Pairs {
+			if worst == nil || pair.PacketLoss > observed {
+				worst = &matrix.Pairs[i]
+				observed = pair.PacketLoss
+			}
+		}
+	default:
+		return false, nil, nil, fmt.Errorf("unsupported when %q", when)
+	}
+
+	var isMatch bool
+	switch operator {
+	case Equal:
+		isMatch = observed == threshold
+	case NotEqual:
+		isMatch = observed != threshold
+	case LessThan:
+		isMatch = observed < threshold
+	case LessThanOrEqual:
+		isMatch = observed <= threshold
+	case GreaterThan:
+		isMatch = observed > threshold
+	case GreaterThanOrEqual:
+		isMatch = observed >= threshold
+	}
+
+	measurement := &Measurement{Observed: observed, Threshold: &threshold, Unit: unit}
+	data := &nodeLatencyTemplateData{}
+	if worst != nil {
+		data.From = worst.From
+		data.To = worst.To
+		data.PacketLoss = fmt.Sprintf("%g%%", worst.PacketLoss)
+		if worst.AvgRTT != nil {
+			data.Latency = fmt.Sprintf("%gms", *worst.AvgRTT)
+		}
+	}
+
+	return isMatch, measurement, data, nil
+}
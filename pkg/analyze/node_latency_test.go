@@ -0,0 +1,109 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"testing"
+
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/collect"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyzeNodeLatency(t *testing.T) {
+	rtt := func(ms float64) *float64 { return &ms }
+
+	matrix := collect.NodeLatencyResult{
+		Nodes: []string{"node-a", "node-b", "node-c"},
+		Pairs: []collect.NodeLatencyPair{
+			{From: "node-a", To: "node-b", Sent: 10, Received: 10, AvgRTT: rtt(0.4)},
+			{From: "node-a", To: "node-c", Sent: 10, Received: 9, PacketLoss: 10, AvgRTT: rtt(72.5)},
+			{From: "node-b", To: "node-a", Sent: 10, Received: 10, AvgRTT: rtt(0.5)},
+			{From: "node-b", To: "node-c", Sent: 10, Received: 10, AvgRTT: rtt(1.2)},
+			{From: "node-c", To: "node-a", Sent: 10, Received: 10, AvgRTT: rtt(0.9)},
+			{From: "node-c", To: "node-b", Sent: 10, Received: 10, AvgRTT: rtt(1.1)},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		outcomes    []*troubleshootv1beta2.Outcome
+		wantFail    bool
+		wantWarn    bool
+		wantPass    bool
+		wantMessage string
+		wantObserve float64
+		wantErr     bool
+	}{
+		{
+			name: "packet loss above threshold",
+			outcomes: []*troubleshootv1beta2.Outcome{
+				{Fail: &troubleshootv1beta2.SingleOutcome{When: "packetLoss > 5%", Message: "{{ .PacketLoss }} loss from {{ .From }} to {{ .To }}"}},
+				{Pass: &troubleshootv1beta2.SingleOutcome{Message: "ok"}},
+			},
+			wantFail:    true,
+			wantMessage: "10% loss from node-a to node-c",
+			wantObserve: 10,
+		},
+		{
+			name: "latency above threshold",
+			outcomes: []*troubleshootv1beta2.Outcome{
+				{Fail: &troubleshootv1beta2.SingleOutcome{When: "latency > 100ms", Message: "too slow"}},
+				{Warn: &troubleshootv1beta2.SingleOutcome{When: "latency > 50ms", Message: "{{ .Latency }} from {{ .From }} to {{ .To }}"}},
+				{Pass: &troubleshootv1beta2.SingleOutcome{Message: "ok"}},
+			},
+			wantWarn:    true,
+			wantMessage: "72.5ms from node-a to node-c",
+			wantObserve: 72.5,
+		},
+		{
+			name: "within thresholds",
+			outcomes: []*troubleshootv1beta2.Outcome{
+				{Fail: &troubleshootv1beta2.SingleOutcome{When: "latency > 100ms", Message: "too slow"}},
+				{Pass: &troubleshootv1beta2.SingleOutcome{Message: "ok"}},
+			},
+			wantPass:    true,
+			wantMessage: "ok",
+		},
+		{
+			name: "unsupported condition",
+			outcomes: []*troubleshootv1beta2.Outcome{
+				{Fail: &troubleshootv1beta2.SingleOutcome{When: "jitter > 5ms"}},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := require.New(t)
+
+			data, err := json.Marshal(matrix)
+			req.NoError(err)
+
+			a := AnalyzeNodeLatency{analyzer: &troubleshootv1beta2.NodeLatencyAnalyze{Outcomes: tt.outcomes}}
+			getFile := func(path string) ([]byte, error) {
+				assert.Equal(t, "node-latency/node-latency.json", path)
+				return data, nil
+			}
+
+			results, err := a.Analyze(getFile, nil)
+			if tt.wantErr {
+				req.Error(err)
+				return
+			}
+			req.NoError(err)
+			req.Len(results, 1)
+
+			result := results[0]
+			assert.Equal(t, tt.wantFail, result.IsFail)
+			assert.Equal(t, tt.wantWarn, result.IsWarn)
+			assert.Equal(t, tt.wantPass, result.IsPass)
+			assert.Equal(t, tt.wantMessage, result.Message)
+			if tt.wantObserve != 0 {
+				req.NotNil(result.Measurement)
+				assert.Equal(t, tt.wantObserve, result.Measurement.Observed)
+			}
+		})
+	}
+}
@@ -98,6 +98,7 @@ func (a *AnalyzeNodeResources) analyzeNodeResources(analyzer *troubleshootv1beta
 					return nil, errors.Wrap(err, "failed to render message template")
 				}
 				result.URI = outcome.Fail.URI
+				result.Remediation = outcome.Fail.Remediation
 				return result, nil
 			}
 		} else if outcome.Warn != nil {
@@ -113,6 +114,7 @@ func (a *AnalyzeNodeResources) analyzeNodeResources(analyzer *troubleshootv1beta
 					return nil, errors.Wrap(err, "failed to render message template")
 				}
 				result.URI = outcome.Warn.URI
+				result.Remediation = outcome.Warn.Remediation
 
 				return result, nil
 			}
@@ -129,6 +131,7 @@ func (a *AnalyzeNodeResources) analyzeNodeResources(analyzer *troubleshootv1beta
 					return nil, errors.Wrap(err, "failed to render message template")
 				}
 				result.URI = outcome.Pass.URI
+				result.Remediation = outcome.Pass.Remediation
 
 				return result, nil
 			}
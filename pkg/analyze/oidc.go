@@ -0,0 +1,142 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/collect"
+)
+
+var defaultOIDCOutcomes = []*troubleshootv1beta2.Outcome{
+	{Fail: &troubleshootv1beta2.SingleOutcome{When: "connected == false", Message: "Cannot reach the OpenID Connect provider: {{ .Error }}"}},
+	{Fail: &troubleshootv1beta2.SingleOutcome{When: "trusted == false", Message: "The certificate of the OpenID Connect provider is not trusted: {{ .CertificateError }}"}},
+	{Fail: &troubleshootv1beta2.SingleOutcome{When: "discovered == false", Message: "Cannot read the configuration of the OpenID Connect provider: {{ .Error }}"}},
+	{Fail: &troubleshootv1beta2.SingleOutcome{When: "signingKeys == 0", Message: "The OpenID Connect provider {{ .Issuer }} has no signing keys{{ with .Error }}: {{ . }}{{ end }}"}},
+	{Fail: &troubleshootv1beta2.SingleOutcome{When: "tokenFailed == true", Message: "The OpenID Connect provider {{ .Issuer }} did not issue a token to the client: {{ .TokenError }}"}},
+	{Pass: &troubleshootv1beta2.SingleOutcome{Message: "The OpenID Connect provider {{ .Issuer }} is trusted and reachable"}},
+}
+
+// oidcTemplateData is passed to the messages of the outcomes
+type oidcTemplateData struct {
+	Error            string
+	CertificateError string
+	Issuer           string
+	// Latency is the time it took to read the discovery document, in milliseconds
+	Latency     int64
+	SigningKeys int
+	TokenError  string
+	// Subject and CertificateIssuer are of the certificate of the issuer, and NotAfter when it
+	// expires
+	Subject           string
+	CertificateIssuer string
+	NotAfter          string
+
+	connected   bool
+	tls         bool
+	discovered  bool
+	tokenIssued bool
+	tokenFailed bool
+}
+
+type AnalyzeOIDC struct {
+	analyzer *troubleshootv1beta2.OIDCAnalyze
+}
+
+func (a *AnalyzeOIDC) Title() string {
+	if a.analyzer.CheckName != "" {
+		return a.analyzer.CheckName
+	}
+
+	return "OpenID Connect"
+}
+
+func (a *AnalyzeOIDC) IsExcluded() (bool, error) {
+	return isExcluded(a.analyzer.Exclude)
+}
+
+func (a *AnalyzeOIDC) collectorName() string {
+	if a.analyzer.CollectorName != "" {
+		return a.analyzer.CollectorName
+	}
+	return "oidc"
+}
+
+func (a *AnalyzeOIDC) Analyze(getFile getCollectedFileContents, findFiles getChildCollectedFileContents) ([]*AnalyzeResult, error) {
+	fullPath := path.Join("oidc", fmt.Sprintf("%s.json", a.collectorName()))
+	collected, err := getFile(fullPath)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read collected file name: %s", fullPath)
+	}
+
+	provider := collect.OIDCProvider{}
+	if err := json.Unmarshal(collected, &provider); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal oidc provider")
+	}
+
+	data := &oidcTemplateData{
+		Error:            provider.Error,
+		CertificateError: provider.CertificateError,
+		Issuer:           provider.Issuer,
+		Latency:          provider.LatencyMilliseconds,
+		SigningKeys:      provider.SigningKeys,
+		TokenError:       provider.TokenError,
+		connected:        provider.IsConnected,
+		tls:              provider.TLS != nil,
+		discovered:       provider.IsDiscovered,
+		tokenIssued:      provider.TokenIssued,
+		tokenFailed:      provider.TokenRequested && !provider.TokenIssued,
+	}
+	data.Subject, data.CertificateIssuer, data.NotAfter = serverCertificate(provider.TLS)
+
+	outcomes := a.analyzer.Outcomes
+	if len(outcomes) == 0 {
+		outcomes = defaultOIDCOutcomes
+	}
+
+	result, err := analyzeTemplatedOutcomes(a.Title(), a.analyzer.Strict.BoolOrDefaultFalse(), outcomes, data, func(when string) (bool, error) {
+		return compareOIDCConditionalToActual(when, data)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		return []*AnalyzeResult{}, nil
+	}
+	return []*AnalyzeResult{result}, nil
+}
+
+// compareOIDCConditionalToActual evaluates a when clause against an OpenID Connect provider.
+// Supported conditions are connected, trusted, tls, discovered, the discovery document was read,
+// tokenIssued and tokenFailed, a token was requested but not issued, compared to true or false,
+// e.g. "trusted == false", signingKeys compared to a number, and latency compared to a number of
+// milliseconds or a duration, e.g. "latency > 2s".
+func compareOIDCConditionalToActual(conditional string, data *oidcTemplateData) (bool, error) {
+	parts := strings.Fields(conditional)
+	if len(parts) != 3 {
+		return false, errors.Errorf("unable to parse conditional %q", conditional)
+	}
+
+	switch parts[0] {
+	case "connected":
+		return compareBoolConditional(parts[1], parts[2], data.connected)
+	case "trusted":
+		return compareBoolConditional(parts[1], parts[2], data.connected && data.CertificateError == "")
+	case "tls":
+		return compareBoolConditional(parts[1], parts[2], data.tls)
+	case "discovered":
+		return compareBoolConditional(parts[1], parts[2], data.discovered)
+	case "tokenIssued":
+		return compareBoolConditional(parts[1], parts[2], data.tokenIssued)
+	case "tokenFailed":
+		return compareBoolConditional(parts[1], parts[2], data.tokenFailed)
+	case "signingKeys":
+		return compareActualToWhen(parts[1]+" "+parts[2], data.SigningKeys)
+	case "latency":
+		return compareLatencyConditional(parts[1], parts[2], data.Latency)
+	}
+	return false, errors.Errorf("unknown condition %q, must be one of connected, trusted, tls, discovered, tokenIssued, tokenFailed, signingKeys or latency", parts[0])
+}
@@ -0,0 +1,102 @@
+package analyzer
+
+import (
+	"testing"
+
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyzeOIDC(t *testing.T) {
+	tests := []struct {
+		name     string
+		analyzer *troubleshootv1beta2.OIDCAnalyze
+		provider string
+		want     *AnalyzeResult
+		wantErr  bool
+	}{
+		{
+			name:     "not connected",
+			analyzer: &troubleshootv1beta2.OIDCAnalyze{},
+			provider: `{"isConnected": false, "error": "failed to read discovery document: dial tcp: no such host"}`,
+			want:     &AnalyzeResult{IsFail: true, Message: "Cannot reach the OpenID Connect provider: failed to read discovery document: dial tcp: no such host"},
+		},
+		{
+			name:     "certificate not trusted",
+			analyzer: &troubleshootv1beta2.OIDCAnalyze{},
+			provider: `{"isConnected": true, "certificateError": "x509: certificate has expired or is not yet valid"}`,
+			want:     &AnalyzeResult{IsFail: true, Message: "The certificate of the OpenID Connect provider is not trusted: x509: certificate has expired or is not yet valid"},
+		},
+		{
+			name:     "issuer mismatch",
+			analyzer: &troubleshootv1beta2.OIDCAnalyze{},
+			provider: `{"isConnected": true, "issuer": "https://login.example.com", "error": "discovery document is of issuer \"https://login.example.com\", not \"https://sso.example.com\""}`,
+			want:     &AnalyzeResult{IsFail: true, Message: "Cannot read the configuration of the OpenID Connect provider: discovery document is of issuer \"https://login.example.com\", not \"https://sso.example.com\""},
+		},
+		{
+			name:     "no signing keys",
+			analyzer: &troubleshootv1beta2.OIDCAnalyze{},
+			provider: `{"isConnected": true, "isDiscovered": true, "issuer": "https://sso.example.com", "error": "failed to read signing keys: unexpected status 404 Not Found"}`,
+			want:     &AnalyzeResult{IsFail: true, Message: "The OpenID Connect provider https://sso.example.com has no signing keys: failed to read signing keys: unexpected status 404 Not Found"},
+		},
+		{
+			name:     "token refused",
+			analyzer: &troubleshootv1beta2.OIDCAnalyze{},
+			provider: `{"isConnected": true, "isDiscovered": true, "issuer": "https://sso.example.com", "signingKeys": 2, "tokenRequested": true, "tokenError": "invalid_client"}`,
+			want:     &AnalyzeResult{IsFail: true, Message: "The OpenID Connect provider https://sso.example.com did not issue a token to the client: invalid_client"},
+		},
+		{
+			name:     "healthy without client",
+			analyzer: &troubleshootv1beta2.OIDCAnalyze{},
+			provider: `{"isConnected": true, "isDiscovered": true, "issuer": "https://sso.example.com", "signingKeys": 2}`,
+			want:     &AnalyzeResult{IsPass: true, Message: "The OpenID Connect provider https://sso.example.com is trusted and reachable"},
+		},
+		{
+			name: "custom outcomes",
+			analyzer: &troubleshootv1beta2.OIDCAnalyze{
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{Warn: &troubleshootv1beta2.SingleOutcome{When: "latency >= 500ms", Message: "{{ .Issuer }} is slow"}},
+					{Pass: &troubleshootv1beta2.SingleOutcome{When: "tokenIssued == true", Message: "ok"}},
+				},
+			},
+			provider: `{"isConnected": true, "isDiscovered": true, "issuer": "https://sso.example.com", "latencyMilliseconds": 20, "tokenRequested": true, "tokenIssued": true}`,
+			want:     &AnalyzeResult{IsPass: true, Message: "ok"},
+		},
+		{
+			name: "unknown condition",
+			analyzer: &troubleshootv1beta2.OIDCAnalyze{
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{Fail: &troubleshootv1beta2.SingleOutcome{When: "scopes == 0"}},
+				},
+			},
+			provider: `{"isConnected": true}`,
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := require.New(t)
+
+			getFile := func(path string) ([]byte, error) {
+				req.Equal("oidc/oidc.json", path)
+				return []byte(tt.provider), nil
+			}
+
+			a := AnalyzeOIDC{analyzer: tt.analyzer}
+			results, err := a.Analyze(getFile, nil)
+			if tt.wantErr {
+				req.Error(err)
+				return
+			}
+			req.NoError(err)
+			req.Len(results, 1)
+
+			assert.Equal(t, tt.want.IsPass, results[0].IsPass)
+			assert.Equal(t, tt.want.IsWarn, results[0].IsWarn)
+			assert.Equal(t, tt.want.IsFail, results[0].IsFail)
+			assert.Equal(t, tt.want.Message, results[0].Message)
+		})
+	}
+}
@@ -0,0 +1,277 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/troubleshoot/internal/util"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/collect"
+	"github.com/replicatedhq/troubleshoot/pkg/constants"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// openShiftObject is the part of a ClusterOperator or MachineConfigPool that outcomes are
+// evaluated against
+type openShiftObject struct {
+	Metadata metav1.ObjectMeta `json:"metadata"`
+	Spec     struct {
+		Paused bool `json:"paused"`
+	} `json:"spec"`
+	Status struct {
+		Conditions              []metav1.Condition `json:"conditions"`
+		MachineCount            int                `json:"machineCount"`
+		ReadyMachineCount       int                `json:"readyMachineCount"`
+		UpdatedMachineCount     int                `json:"updatedMachineCount"`
+		UnavailableMachineCount int                `json:"unavailableMachineCount"`
+		DegradedMachineCount    int                `json:"degradedMachineCount"`
+	} `json:"status"`
+}
+
+// openShiftTemplateData is passed to the titles and messages of the outcomes. Reason and Message
+// are those of the condition named by the when clause of the outcome.
+type openShiftTemplateData struct {
+	Name    string
+	Reason  string
+	Message string
+}
+
+// openShiftKind describes the resource an OpenShift analyzer evaluates
+type openShiftKind struct {
+	kind       string
+	apiVersion string
+	resource   string
+	// fields is whether the spec and machine counts of pools can be used in when clauses
+	fields bool
+}
+
+var clusterOperatorKind = openShiftKind{
+	kind:       "ClusterOperator",
+	apiVersion: "config.openshift.io/v1",
+	resource:   constants.OPENSHIFT_CLUSTER_OPERATORS,
+}
+
+var machineConfigPoolKind = openShiftKind{
+	kind:       "MachineConfigPool",
+	apiVersion: "machineconfiguration.openshift.io/v1",
+	resource:   constants.OPENSHIFT_MACHINE_CONFIG_POOLS,
+	fields:     true,
+}
+
+var defaultClusterOperatorOutcomes = []*troubleshootv1beta2.Outcome{
+	{Fail: &troubleshootv1beta2.SingleOutcome{When: "Degraded == True", Message: "ClusterOperator {{ .Name }} is degraded: {{ .Message }}"}},
+	{Fail: &troubleshootv1beta2.SingleOutcome{When: "Available == False", Message: "ClusterOperator {{ .Name }} is not available: {{ .Message }}"}},
+	{Warn: &troubleshootv1beta2.SingleOutcome{When: "Progressing == True", Message: "ClusterOperator {{ .Name }} is progressing: {{ .Message }}"}},
+	{Pass: &troubleshootv1beta2.SingleOutcome{Message: "ClusterOperator {{ .Name }} is available"}},
+}
+
+var defaultMachineConfigPoolOutcomes = []*troubleshootv1beta2.Outcome{
+	{Fail: &troubleshootv1beta2.SingleOutcome{When: "paused == true", Message: "MachineConfigPool {{ .Name }} is paused, its nodes do not receive configuration updates"}},
+	{Fail: &troubleshootv1beta2.SingleOutcome{When: "Degraded == True", Message: "MachineConfigPool {{ .Name }} is degraded: {{ .Message }}"}},
+	{Warn: &troubleshootv1beta2.SingleOutcome{When: "Updating == True", Message: "MachineConfigPool {{ .Name }} is updating"}},
+	{Pass: &troubleshootv1beta2.SingleOutcome{Message: "MachineConfigPool {{ .Name }} is up to date"}},
+}
+
+type AnalyzeClusterOperatorStatus struct {
+	analyzer *troubleshootv1beta2.ClusterOperatorStatus
+}
+
+func (a *AnalyzeClusterOperatorStatus) Title() string {
+	if a.analyzer.CheckName != "" {
+		return a.analyzer.CheckName
+	}
+
+	return "ClusterOperator {{ .Name }}"
+}
+
+func (a *AnalyzeClusterOperatorStatus) IsExcluded() (bool, error) {
+	return isExcluded(a.analyzer.Exclude)
+}
+
+func (a *AnalyzeClusterOperatorStatus) Analyze(getFile getCollectedFileContents, findFiles getChildCollectedFileContents) ([]*AnalyzeResult, error) {
+	outcomes := a.analyzer.Outcomes
+	if len(outcomes) == 0 {
+		outcomes = defaultClusterOperatorOutcomes
+	}
+
+	results, err := analyzeOpenShiftObjects(getFile, clusterOperatorKind, a.analyzer.CollectorName, a.analyzer.Operators, outcomes, a.Title())
+	if err != nil {
+		return nil, err
+	}
+	for i := range results {
+		results[i].Strict = a.analyzer.Strict.BoolOrDefaultFalse()
+	}
+	return results, nil
+}
+
+type AnalyzeMachineConfigPoolStatus struct {
+	analyzer *troubleshootv1beta2.MachineConfigPoolStatus
+}
+
+func (a *AnalyzeMachineConfigPoolStatus) Title() string {
+	if a.analyzer.CheckName != "" {
+		return a.analyzer.CheckName
+	}
+
+	return "MachineConfigPool {{ .Name }}"
+}
+
+func (a *AnalyzeMachineConfigPoolStatus) IsExcluded() (bool, error) {
+	return isExcluded(a.analyzer.Exclude)
+}
+
+func (a *AnalyzeMachineConfigPoolStatus) Analyze(getFile getCollectedFileContents, findFiles getChildCollectedFileContents) ([]*AnalyzeResult, error) {
+	outcomes := a.analyzer.Outcomes
+	if len(outcomes) == 0 {
+		outcomes = defaultMachineConfigPoolOutcomes
+	}
+
+	results, err := analyzeOpenShiftObjects(getFile, machineConfigPoolKind, a.analyzer.CollectorName, a.analyzer.Pools, outcomes, a.Title())
+	if err != nil {
+		return nil, err
+	}
+	for i := range results {
+		results[i].Strict = a.analyzer.Strict.BoolOrDefaultFalse()
+	}
+	return results, nil
+}
+
+// analyzeOpenShiftObjects returns a result for each collected object of the kind, or only those
+// named when names is set, with the first outcome whose condition matches the object
+func analyzeOpenShiftObjects(
+	getFile getCollectedFileContents, kind openShiftKind, collectorName string, names []string,
+	outcomes []*troubleshootv1beta2.Outcome, title string,
+) ([]*AnalyzeResult, error) {
+	path := collect.OpenShiftPath(collectorName, kind.resource)
+	collected, err := getFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read collected file %s", path)
+	}
+
+	objects := []openShiftObject{}
+	if err := json.Unmarshal(collected, &objects); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal %s", kind.resource)
+	}
+
+	results := []*AnalyzeResult{}
+	for _, object := range objects {
+		if len(names) > 0 && !slices.Contains(names, object.Metadata.Name) {
+			continue
+		}
+
+		for _, outcome := range outcomes {
+			result := &AnalyzeResult{}
+
+			var singleOutcome *troubleshootv1beta2.SingleOutcome
+			switch {
+			case outcome.Fail != nil:
+				singleOutcome = outcome.Fail
+				result.IsFail = true
+			case outcome.Warn != nil:
+				singleOutcome = outcome.Warn
+				result.IsWarn = true
+			case outcome.Pass != nil:
+				singleOutcome = outcome.Pass
+				result.IsPass = true
+			default:
+				continue
+			}
+
+			data := openShiftTemplateData{Name: object.Metadata.Name}
+			if singleOutcome.When != "" {
+				match, condition, err := checkOpenShiftCondition(kind, object, singleOutcome.When)
+				if err != nil {
+					return nil, errors.Wrapf(err, "failed to evaluate when %q", singleOutcome.When)
+				}
+				if !match {
+					continue
+				}
+				if condition != nil {
+					data.Reason = condition.Reason
+					data.Message = condition.Message
+				}
+			}
+
+			result.Title, err = util.RenderTemplate(title, data)
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to render title template")
+			}
+			result.Message, err = util.RenderTemplate(singleOutcome.Message, data)
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to render message template")
+			}
+			result.URI = singleOutcome.URI
+			result.Remediation = singleOutcome.Remediation
+			result.IconKey = "kubernetes"
+			result.InvolvedObject = &corev1.ObjectReference{
+				APIVersion: kind.apiVersion,
+				Kind:       kind.kind,
+				Name:       object.Metadata.Name,
+			}
+
+			results = append(results, result)
+			break
+		}
+	}
+
+	return results, nil
+}
+
+// checkOpenShiftCondition evaluates a when clause against an object. Supported conditions are:
+//
+//   - "<condition> == <True|False|Unknown>" or "!=", e.g. "Degraded == True". A condition the
+//     object does not report has the status Unknown. The matched condition is returned.
+//   - for MachineConfigPools, "paused == <true|false>" and "<count> <operator> <n>" where count
+//     is one of machineCount, readyMachineCount, updatedMachineCount, unavailableMachineCount
+//     or degradedMachineCount, e.g. "degradedMachineCount > 0"
+func checkOpenShiftCondition(kind openShiftKind, object openShiftObject, when string) (bool, *metav1.Condition, error) {
+	parts := strings.Fields(when)
+	if len(parts) != 3 {
+		return false, nil, fmt.Errorf("expected 3 parts in when %q, got %d", when, len(parts))
+	}
+	key, opString, expected := parts[0], parts[1], parts[2]
+
+	if unicode.IsUpper(rune(key[0])) {
+		for i := range object.Status.Conditions {
+			condition := &object.Status.Conditions[i]
+			if condition.Type == key {
+				match, err := compareEquality(strings.EqualFold(string(condition.Status), expected), opString)
+				return match, condition, err
+			}
+		}
+		match, err := compareEquality(strings.EqualFold(string(metav1.ConditionUnknown), expected), opString)
+		return match, nil, err
+	}
+
+	if !kind.fields {
+		return false, nil, fmt.Errorf("unsupported condition %q, expected a condition type such as Degraded", key)
+	}
+
+	counts := map[string]int{
+		"machineCount":            object.Status.MachineCount,
+		"readyMachineCount":       object.Status.ReadyMachineCount,
+		"updatedMachineCount":     object.Status.UpdatedMachineCount,
+		"unavailableMachineCount": object.Status.UnavailableMachineCount,
+		"degradedMachineCount":    object.Status.DegradedMachineCount,
+	}
+	if count, ok := counts[key]; ok {
+		match, err := compareActualToWhen(opString+" "+expected, count)
+		return match, nil, err
+	}
+	if key == "paused" {
+		want, err := strconv.ParseBool(expected)
+		if err != nil {
+			return false, nil, errors.Wrapf(err, "failed to parse %q", expected)
+		}
+		match, err := compareEquality(object.Spec.Paused == want, opString)
+		return match, nil, err
+	}
+
+	return false, nil, fmt.Errorf("unsupported condition %q", key)
+}
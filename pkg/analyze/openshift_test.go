@@ -0,0 +1,234 @@
+package analyzer
+
+import (
+	"testing"
+
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testClusterOperators = `[
+  {
+    "apiVersion": "config.openshift.io/v1",
+    "kind": "ClusterOperator",
+    "metadata": {"name": "authentication"},
+    "status": {"conditions": [
+      {"type": "Available", "status": "True", "reason": "AsExpected", "message": ""},
+      {"type": "Degraded", "status": "False", "reason": "AsExpected", "message": ""},
+      {"type": "Progressing", "status": "False", "reason": "AsExpected", "message": ""}
+    ]}
+  },
+  {
+    "apiVersion": "config.openshift.io/v1",
+    "kind": "ClusterOperator",
+    "metadata": {"name": "ingress"},
+    "status": {"conditions": [
+      {"type": "Available", "status": "True", "reason": "AsExpected", "message": ""},
+      {"type": "Degraded", "status": "True", "reason": "IngressDegraded", "message": "2 of 3 router pods are not ready"}
+    ]}
+  },
+  {
+    "apiVersion": "config.openshift.io/v1",
+    "kind": "ClusterOperator",
+    "metadata": {"name": "monitoring"},
+    "status": {"conditions": [
+      {"type": "Available", "status": "True", "reason": "AsExpected", "message": ""},
+      {"type": "Progressing", "status": "True", "reason": "RollOutInProgress", "message": "Rolling out the stack."}
+    ]}
+  }
+]`
+
+const testMachineConfigPools = `[
+  {
+    "apiVersion": "machineconfiguration.openshift.io/v1",
+    "kind": "MachineConfigPool",
+    "metadata": {"name": "master"},
+    "spec": {"paused": false},
+    "status": {
+      "machineCount": 3, "readyMachineCount": 3, "updatedMachineCount": 3, "degradedMachineCount": 0,
+      "conditions": [
+        {"type": "Updated", "status": "True", "reason": "", "message": "All nodes are updated"},
+        {"type": "Updating", "status": "False", "reason": "", "message": ""},
+        {"type": "Degraded", "status": "False", "reason": "", "message": ""}
+      ]
+    }
+  },
+  {
+    "apiVersion": "machineconfiguration.openshift.io/v1",
+    "kind": "MachineConfigPool",
+    "metadata": {"name": "worker"},
+    "spec": {"paused": true},
+    "status": {
+      "machineCount": 3, "readyMachineCount": 2, "updatedMachineCount": 2, "degradedMachineCount": 1,
+      "conditions": [
+        {"type": "Updating", "status": "True", "reason": "", "message": "Updating 1 of 3 nodes"},
+        {"type": "Degraded", "status": "True", "reason": "1 nodes are reporting degraded status on sync", "message": "Node worker-2 is reporting: unexpected on-disk state"}
+      ]
+    }
+  }
+]`
+
+func TestAnalyzeClusterOperatorStatus(t *testing.T) {
+	tests := []struct {
+		name     string
+		analyzer *troubleshootv1beta2.ClusterOperatorStatus
+		want     []*AnalyzeResult
+		wantErr  bool
+	}{
+		{
+			name:     "default outcomes",
+			analyzer: &troubleshootv1beta2.ClusterOperatorStatus{},
+			want: []*AnalyzeResult{
+				{IsPass: true, Title: "ClusterOperator authentication", Message: "ClusterOperator authentication is available"},
+				{IsFail: true, Title: "ClusterOperator ingress", Message: "ClusterOperator ingress is degraded: 2 of 3 router pods are not ready"},
+				{IsWarn: true, Title: "ClusterOperator monitoring", Message: "ClusterOperator monitoring is progressing: Rolling out the stack."},
+			},
+		},
+		{
+			name: "selected operators",
+			analyzer: &troubleshootv1beta2.ClusterOperatorStatus{
+				AnalyzeMeta: troubleshootv1beta2.AnalyzeMeta{CheckName: "Operators"},
+				Operators:   []string{"ingress"},
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{Fail: &troubleshootv1beta2.SingleOutcome{When: "Degraded == True", Message: "{{ .Name }}: {{ .Reason }}"}},
+					{Pass: &troubleshootv1beta2.SingleOutcome{Message: "ok"}},
+				},
+			},
+			want: []*AnalyzeResult{
+				{IsFail: true, Title: "Operators", Message: "ingress: IngressDegraded"},
+			},
+		},
+		{
+			name: "condition not reported",
+			analyzer: &troubleshootv1beta2.ClusterOperatorStatus{
+				Operators: []string{"ingress"},
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{Warn: &troubleshootv1beta2.SingleOutcome{When: "Progressing != False", Message: "unknown"}},
+				},
+			},
+			want: []*AnalyzeResult{
+				{IsWarn: true, Title: "ClusterOperator ingress", Message: "unknown"},
+			},
+		},
+		{
+			name: "pool fields are not supported",
+			analyzer: &troubleshootv1beta2.ClusterOperatorStatus{
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{Fail: &troubleshootv1beta2.SingleOutcome{When: "paused == true"}},
+				},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := require.New(t)
+
+			getFile := func(path string) ([]byte, error) {
+				assert.Equal(t, "openshift/clusteroperators.json", path)
+				return []byte(testClusterOperators), nil
+			}
+
+			a := AnalyzeClusterOperatorStatus{analyzer: tt.analyzer}
+			results, err := a.Analyze(getFile, nil)
+			if tt.wantErr {
+				req.Error(err)
+				return
+			}
+			req.NoError(err)
+			req.Len(results, len(tt.want))
+
+			for i, want := range tt.want {
+				assert.Equal(t, want.IsPass, results[i].IsPass)
+				assert.Equal(t, want.IsWarn, results[i].IsWarn)
+				assert.Equal(t, want.IsFail, results[i].IsFail)
+				assert.Equal(t, want.Title, results[i].Title)
+				assert.Equal(t, want.Message, results[i].Message)
+				assert.Equal(t, "ClusterOperator", results[i].InvolvedObject.Kind)
+			}
+		})
+	}
+}
+
+func TestAnalyzeMachineConfigPoolStatus(t *testing.T) {
+	tests := []struct {
+		name     string
+		analyzer *troubleshootv1beta2.MachineConfigPoolStatus
+		want     []*AnalyzeResult
+		wantErr  bool
+	}{
+		{
+			name:     "default outcomes",
+			analyzer: &troubleshootv1beta2.MachineConfigPoolStatus{},
+			want: []*AnalyzeResult{
+				{IsPass: true, Title: "MachineConfigPool master", Message: "MachineConfigPool master is up to date"},
+				{IsFail: true, Title: "MachineConfigPool worker", Message: "MachineConfigPool worker is paused, its nodes do not receive configuration updates"},
+			},
+		},
+		{
+			name: "machine counts",
+			analyzer: &troubleshootv1beta2.MachineConfigPoolStatus{
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{Fail: &troubleshootv1beta2.SingleOutcome{When: "degradedMachineCount > 0", Message: "{{ .Name }} has degraded machines"}},
+					{Pass: &troubleshootv1beta2.SingleOutcome{Message: "{{ .Name }} is healthy"}},
+				},
+			},
+			want: []*AnalyzeResult{
+				{IsPass: true, Title: "MachineConfigPool master", Message: "master is healthy"},
+				{IsFail: true, Title: "MachineConfigPool worker", Message: "worker has degraded machines"},
+			},
+		},
+		{
+			name: "selected pools",
+			analyzer: &troubleshootv1beta2.MachineConfigPoolStatus{
+				Pools: []string{"worker"},
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{Fail: &troubleshootv1beta2.SingleOutcome{When: "Degraded == True", Message: "{{ .Message }}"}},
+				},
+			},
+			want: []*AnalyzeResult{
+				{IsFail: true, Title: "MachineConfigPool worker", Message: "Node worker-2 is reporting: unexpected on-disk state"},
+			},
+		},
+		{
+			name: "unsupported condition",
+			analyzer: &troubleshootv1beta2.MachineConfigPoolStatus{
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{Fail: &troubleshootv1beta2.SingleOutcome{When: "nodes > 3"}},
+				},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := require.New(t)
+
+			getFile := func(path string) ([]byte, error) {
+				assert.Equal(t, "openshift/machineconfigpools.json", path)
+				return []byte(testMachineConfigPools), nil
+			}
+
+			a := AnalyzeMachineConfigPoolStatus{analyzer: tt.analyzer}
+			results, err := a.Analyze(getFile, nil)
+			if tt.wantErr {
+				req.Error(err)
+				return
+			}
+			req.NoError(err)
+			req.Len(results, len(tt.want))
+
+			for i, want := range tt.want {
+				assert.Equal(t, want.IsPass, results[i].IsPass)
+				assert.Equal(t, want.IsWarn, results[i].IsWarn)
+				assert.Equal(t, want.IsFail, results[i].IsFail)
+				assert.Equal(t, want.Title, results[i].Title)
+				assert.Equal(t, want.Message, results[i].Message)
+				assert.Equal(t, "MachineConfigPool", results[i].InvolvedObject.Kind)
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package analyzer
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/collect"
+	"github.com/replicatedhq/troubleshoot/pkg/plugin"
+)
+
+type AnalyzePlugin struct {
+	analyzer *troubleshootv1beta2.PluginAnalyze
+}
+
+func (a *AnalyzePlugin) Title() string {
+	if a.analyzer.CheckName != "" {
+		return a.analyzer.CheckName
+	}
+	return a.analyzer.Name
+}
+
+func (a *AnalyzePlugin) IsExcluded() (bool, error) {
+	return isExcluded(a.analyzer.Exclude)
+}
+
+func (a *AnalyzePlugin) Analyze(getFile getCollectedFileContents, findFiles getChildCollectedFileContents) ([]*AnalyzeResult, error) {
+	var timeout time.Duration
+	if a.analyzer.Timeout != "" {
+		var err error
+		timeout, err = time.ParseDuration(a.analyzer.Timeout)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to parse timeout")
+		}
+	}
+
+	collectorName := a.analyzer.CollectorName
+	if collectorName == "" {
+		collectorName = a.analyzer.Name
+	}
+	fileName := a.analyzer.FileName
+	if fileName == "" {
+		fileName = "*"
+	}
+	pattern := filepath.Join(collect.PluginOutputDir(collectorName), fileName)
+
+	files, err := findBundleFiles(findFiles, pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	request := plugin.Request{
+		Command: plugin.CommandAnalyze,
+		Config:  a.analyzer.Config,
+		Files:   files,
+	}
+	response := plugin.AnalyzeResponse{}
+	if err := plugin.Run(context.TODO(), a.analyzer.Name, a.analyzer.Args, timeout, request, &response); err != nil {
+		return nil, err
+	}
+
+	return pluginResults(response, a.Title()), nil
+}
+
+// pluginResults converts the results of a plugin or WASM module, titling the untitled ones.
+func pluginResults(response plugin.AnalyzeResponse, title string) []*AnalyzeResult {
+	results := make([]*AnalyzeResult, 0, len(response.Results))
+	for _, r := range response.Results {
+		resultTitle := r.Title
+		if resultTitle == "" {
+			resultTitle = title
+		}
+		results = append(results, &AnalyzeResult{
+			Title:   resultTitle,
+			IsPass:  r.IsPass,
+			IsWarn:  r.IsWarn,
+			IsFail:  r.IsFail,
+			Message: r.Message,
+			URI:     r.URI,
+		})
+	}
+	return results
+}
+
+// findBundleFiles returns the contents of the bundle files matching the glob pattern, keyed by
+// their path relative to the root of the bundle.
+func findBundleFiles(findFiles getChildCollectedFileContents, pattern string) (map[string][]byte, error) {
+	found, err := findFiles(pattern, nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to find files matching %s", pattern)
+	}
+	files := make(map[string][]byte, len(found))
+	for path, data := range found {
+		files[bundleRelativePath(path, pattern)] = data
+	}
+	return files, nil
+}
+
+// bundleRelativePath returns the path of a file matching the glob pattern relative to the
+// root of the bundle. Files read from disk are found with their absolute path, and as globs do
+// not match path separators, the relative path is made of as many trailing elements as the
+// pattern has.
+func bundleRelativePath(path string, pattern string) string {
+	if !filepath.IsAbs(path) {
+		return filepath.ToSlash(path)
+	}
+
+	elements := strings.Split(filepath.ToSlash(path), "/")
+	n := len(strings.Split(filepath.ToSlash(filepath.Clean(pattern)), "/"))
+	if n > len(elements) {
+		return filepath.ToSlash(path)
+	}
+	return strings.Join(elements[len(elements)-n:], "/")
+}
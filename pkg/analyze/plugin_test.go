@@ -0,0 +1,66 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/plugin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyzePlugin_Analyze(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("plugin scripts need a POSIX shell")
+	}
+
+	pluginDir := t.TempDir()
+	script := `#!/bin/sh
+cat > "$(dirname "$0")/request.json"
+echo '{"results":[{"isFail":true,"message":"Queue is backed up","uri":"https://example.com/queues"}]}'
+`
+	require.NoError(t, os.WriteFile(filepath.Join(pluginDir, plugin.ExecutablePrefix+"acme"), []byte(script), 0755))
+	t.Setenv(plugin.PathEnvVar, pluginDir)
+
+	bundleDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(bundleDir, "plugins/acme"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(bundleDir, "plugins/acme/queues.json"), []byte(`{"depth":1000}`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(bundleDir, "plugins/acme/health.txt"), []byte("ok"), 0644))
+	fcp := fileContentProvider{rootDir: bundleDir}
+
+	a := &AnalyzePlugin{
+		analyzer: &troubleshootv1beta2.PluginAnalyze{
+			AnalyzeMeta: troubleshootv1beta2.AnalyzeMeta{CheckName: "Acme queues"},
+			Name:        "acme",
+			FileName:    "*.json",
+			Config:      map[string]string{"maxDepth": "100"},
+		},
+	}
+	results, err := a.Analyze(fcp.getFileContents, fcp.getChildFileContents)
+	require.NoError(t, err)
+	assert.Equal(t, []*AnalyzeResult{
+		{
+			Title:   "Acme queues",
+			IsFail:  true,
+			Message: "Queue is backed up",
+			URI:     "https://example.com/queues",
+		},
+	}, results)
+
+	request := plugin.Request{}
+	data, err := os.ReadFile(filepath.Join(pluginDir, "request.json"))
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(data, &request))
+	assert.Equal(t, plugin.CommandAnalyze, request.Command)
+	assert.Equal(t, map[string]string{"maxDepth": "100"}, request.Config)
+	assert.Equal(t, map[string][]byte{"plugins/acme/queues.json": []byte(`{"depth":1000}`)}, request.Files)
+}
+
+func Test_bundleRelativePath(t *testing.T) {
+	assert.Equal(t, "plugins/acme/queues.json", bundleRelativePath("/tmp/bundle/plugins/acme/queues.json", "plugins/acme/*.json"))
+	assert.Equal(t, "plugins/acme/queues.json", bundleRelativePath("plugins/acme/queues.json", "plugins/acme/*.json"))
+}
@@ -0,0 +1,278 @@
+package analyzer
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/troubleshoot/internal/util"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/constants"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+var defaultPodDisruptionBudgetOutcomes = []*troubleshootv1beta2.Outcome{
+	{Fail: &troubleshootv1beta2.SingleOutcome{When: "blocked == true", Message: "PodDisruptionBudget {{ .Namespace }}/{{ .Name }} prevents nodes from being drained: {{ .Reason }}"}},
+	{Pass: &troubleshootv1beta2.SingleOutcome{When: "expectedPods == 0", Message: "PodDisruptionBudget {{ .Namespace }}/{{ .Name }} does not select any pods"}},
+	{Warn: &troubleshootv1beta2.SingleOutcome{When: "disruptionsAllowed == 0", Message: "PodDisruptionBudget {{ .Namespace }}/{{ .Name }} allows no disruptions, {{ .CurrentHealthy }} of its {{ .ExpectedPods }} pods are healthy and {{ .DesiredHealthy }} must be"}},
+	{Pass: &troubleshootv1beta2.SingleOutcome{Message: "PodDisruptionBudget {{ .Namespace }}/{{ .Name }} allows {{ .DisruptionsAllowed }} disruptions"}},
+}
+
+// podDisruptionBudgetTemplateData is passed to the messages of the outcomes. DesiredHealthy is
+// the number of pods the budget requires to be available when all of its pods are.
+type podDisruptionBudgetTemplateData struct {
+	Namespace          string
+	Name               string
+	MinAvailable       string
+	MaxUnavailable     string
+	ExpectedPods       int
+	CurrentHealthy     int
+	DesiredHealthy     int
+	DisruptionsAllowed int
+	SchedulableNodes   int
+	Reason             string
+
+	blocked bool
+}
+
+type AnalyzePodDisruptionBudget struct {
+	analyzer *troubleshootv1beta2.PodDisruptionBudget
+}
+
+func (a *AnalyzePodDisruptionBudget) Title() string {
+	if a.analyzer.CheckName != "" {
+		return a.analyzer.CheckName
+	}
+
+	return "Pod Disruption Budget {{ .Namespace }}/{{ .Name }}"
+}
+
+func (a *AnalyzePodDisruptionBudget) IsExcluded() (bool, error) {
+	return isExcluded(a.analyzer.Exclude)
+}
+
+func (a *AnalyzePodDisruptionBudget) Analyze(getFile getCollectedFileContents, findFiles getChildCollectedFileContents) ([]*AnalyzeResult, error) {
+	files, err := findFiles(filepath.Join(constants.CLUSTER_RESOURCES_DIR, constants.CLUSTER_RESOURCES_POD_DISRUPTION_BUDGETS, "*.json"), nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read collected pod disruption budgets")
+	}
+
+	budgets := []policyv1.PodDisruptionBudget{}
+	for name, content := range files {
+		items, err := decodeCollectedItems[policyv1.PodDisruptionBudget](content)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to unmarshal %s", name)
+		}
+		for _, pdb := range items {
+			if len(a.analyzer.Namespaces) == 0 || slices.Contains(a.analyzer.Namespaces, pdb.Namespace) {
+				budgets = append(budgets, pdb)
+			}
+		}
+	}
+	sort.Slice(budgets, func(i, j int) bool {
+		if budgets[i].Namespace != budgets[j].Namespace {
+			return budgets[i].Namespace < budgets[j].Namespace
+		}
+		return budgets[i].Name < budgets[j].Name
+	})
+
+	schedulableNodes, err := countSchedulableNodes(getFile)
+	if err != nil {
+		return nil, err
+	}
+
+	outcomes := a.analyzer.Outcomes
+	if len(outcomes) == 0 {
+		outcomes = defaultPodDisruptionBudgetOutcomes
+	}
+
+	results := []*AnalyzeResult{}
+	for _, pdb := range budgets {
+		data, err := podDisruptionBudgetData(pdb, schedulableNodes)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to evaluate pod disruption budget %s/%s", pdb.Namespace, pdb.Name)
+		}
+
+		result, err := a.analyzePodDisruptionBudget(data, outcomes)
+		if err != nil {
+			return nil, err
+		}
+		if result != nil {
+			results = append(results, result)
+		}
+	}
+
+	return results, nil
+}
+
+// analyzePodDisruptionBudget returns the result of the first outcome whose condition matches the
+// budget, or nil when none does
+func (a *AnalyzePodDisruptionBudget) analyzePodDisruptionBudget(data *podDisruptionBudgetTemplateData, outcomes []*troubleshootv1beta2.Outcome) (*AnalyzeResult, error) {
+	for _, outcome := range outcomes {
+		result := &AnalyzeResult{
+			IconKey: "kubernetes",
+			Strict:  a.analyzer.Strict.BoolOrDefaultFalse(),
+		}
+
+		var singleOutcome *troubleshootv1beta2.SingleOutcome
+		switch {
+		case outcome.Fail != nil:
+			singleOutcome = outcome.Fail
+			result.IsFail = true
+		case outcome.Warn != nil:
+			singleOutcome = outcome.Warn
+			result.IsWarn = true
+		case outcome.Pass != nil:
+			singleOutcome = outcome.Pass
+			result.IsPass = true
+		default:
+			continue
+		}
+
+		if singleOutcome.When != "" {
+			match, err := comparePodDisruptionBudget(data, singleOutcome.When)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to evaluate when %q", singleOutcome.When)
+			}
+			if !match {
+				continue
+			}
+		}
+
+		var err error
+		result.Title, err = util.RenderTemplate(a.Title(), data)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to render title template")
+		}
+		result.Message, err = util.RenderTemplate(singleOutcome.Message, data)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to render message template")
+		}
+		result.URI = singleOutcome.URI
+		result.Remediation = singleOutcome.Remediation
+		result.Condition = singleOutcome.When
+		result.InvolvedObject = &corev1.ObjectReference{
+			APIVersion: "policy/v1",
+			Kind:       "PodDisruptionBudget",
+			Namespace:  data.Namespace,
+			Name:       data.Name,
+		}
+		return result, nil
+	}
+
+	return nil, nil
+}
+
+// comparePodDisruptionBudget evaluates a when clause against a budget. Supported conditions are:
+//
+//   - "blocked == <true|false>", whether the budget prevents draining the nodes its pods run on
+//     even when all of its pods are healthy
+//   - "disruptionsAllowed <operator> <n>", the number of pods that can currently be evicted
+//   - "expectedPods <operator> <n>", the number of pods selected by the budget
+func comparePodDisruptionBudget(data *podDisruptionBudgetTemplateData, when string) (bool, error) {
+	parts := strings.Fields(when)
+	if len(parts) != 3 {
+		return false, fmt.Errorf("expected 3 parts in when %q, got %d", when, len(parts))
+	}
+	key, opString, expected := parts[0], parts[1], parts[2]
+
+	switch key {
+	case "blocked":
+		blocked, err := strconv.ParseBool(expected)
+		if err != nil {
+			return false, errors.Wrapf(err, "failed to parse %q", expected)
+		}
+		return compareEquality(data.blocked == blocked, opString)
+	case "disruptionsAllowed":
+		return compareActualToWhen(opString+" "+expected, data.DisruptionsAllowed)
+	case "expectedPods":
+		return compareActualToWhen(opString+" "+expected, data.ExpectedPods)
+	}
+
+	return false, fmt.Errorf("unsupported condition %q, must be one of blocked, disruptionsAllowed or expectedPods", key)
+}
+
+// podDisruptionBudgetData computes the number of pods the budget requires to stay available
+// from its spec rather than its status, so that a budget whose pods are currently unhealthy is
+// not mistaken for one that never allows disruptions. A schedulableNodes of -1 means the nodes
+// were not collected.
+func podDisruptionBudgetData(pdb policyv1.PodDisruptionBudget, schedulableNodes int) (*podDisruptionBudgetTemplateData, error) {
+	data := &podDisruptionBudgetTemplateData{
+		Namespace:          pdb.Namespace,
+		Name:               pdb.Name,
+		ExpectedPods:       int(pdb.Status.ExpectedPods),
+		CurrentHealthy:     int(pdb.Status.CurrentHealthy),
+		DesiredHealthy:     int(pdb.Status.DesiredHealthy),
+		DisruptionsAllowed: int(pdb.Status.DisruptionsAllowed),
+		SchedulableNodes:   schedulableNodes,
+	}
+
+	switch {
+	case pdb.Spec.MaxUnavailable != nil:
+		data.MaxUnavailable = pdb.Spec.MaxUnavailable.String()
+		maxUnavailable, err := intstr.GetScaledValueFromIntOrPercent(pdb.Spec.MaxUnavailable, data.ExpectedPods, true)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to parse maxUnavailable")
+		}
+		data.DesiredHealthy = max(data.ExpectedPods-maxUnavailable, 0)
+	case pdb.Spec.MinAvailable != nil:
+		data.MinAvailable = pdb.Spec.MinAvailable.String()
+		minAvailable, err := intstr.GetScaledValueFromIntOrPercent(pdb.Spec.MinAvailable, data.ExpectedPods, true)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to parse minAvailable")
+		}
+		data.DesiredHealthy = minAvailable
+	}
+
+	if data.ExpectedPods == 0 || data.DesiredHealthy == 0 {
+		return data, nil
+	}
+
+	switch {
+	case data.DesiredHealthy >= data.ExpectedPods && data.MaxUnavailable != "":
+		data.blocked = true
+		data.Reason = fmt.Sprintf("maxUnavailable is %s, none of its %d pods can be evicted", data.MaxUnavailable, data.ExpectedPods)
+	case data.DesiredHealthy >= data.ExpectedPods:
+		data.blocked = true
+		data.Reason = fmt.Sprintf("minAvailable is %s, all of its %d pods must stay available", data.MinAvailable, data.ExpectedPods)
+	case schedulableNodes == 1:
+		data.blocked = true
+		data.Reason = fmt.Sprintf("the cluster has a single schedulable node, pods evicted from it cannot be rescheduled to keep %d of them available", data.DesiredHealthy)
+	}
+
+	return data, nil
+}
+
+// countSchedulableNodes counts the ready nodes that are not cordoned, which are the nodes evicted
+// pods can be rescheduled to. It returns -1 when the nodes were not collected.
+func countSchedulableNodes(getFile getCollectedFileContents) (int, error) {
+	content, err := getFile(path.Join(constants.CLUSTER_RESOURCES_DIR, fmt.Sprintf("%s.json", constants.CLUSTER_RESOURCES_NODES)))
+	if err != nil || len(content) == 0 {
+		return -1, nil
+	}
+	nodes, err := decodeCollectedItems[corev1.Node](content)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to unmarshal nodes")
+	}
+
+	schedulable := 0
+	for _, node := range nodes {
+		if node.Spec.Unschedulable {
+			continue
+		}
+		for _, condition := range node.Status.Conditions {
+			if condition.Type == corev1.NodeReady && condition.Status == corev1.ConditionTrue {
+				schedulable++
+				break
+			}
+		}
+	}
+	return schedulable, nil
+}
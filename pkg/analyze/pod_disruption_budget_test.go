@@ -0,0 +1,139 @@
+package analyzer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyzePodDisruptionBudget(t *testing.T) {
+	budgets := map[string]string{
+		"cluster-resources/pod-disruption-budgets/app.json": `{"items": [
+		  {"metadata": {"name": "api", "namespace": "app"}, "spec": {"minAvailable": 2},
+		   "status": {"expectedPods": 2, "currentHealthy": 2, "desiredHealthy": 2, "disruptionsAllowed": 0}},
+		  {"metadata": {"name": "web", "namespace": "app"}, "spec": {"maxUnavailable": "25%"},
+		   "status": {"expectedPods": 4, "currentHealthy": 4, "desiredHealthy": 3, "disruptionsAllowed": 1}},
+		  {"metadata": {"name": "worker", "namespace": "app"}, "spec": {"minAvailable": 1},
+		   "status": {"expectedPods": 3, "currentHealthy": 1, "desiredHealthy": 1, "disruptionsAllowed": 0}}
+		]}`,
+		"cluster-resources/pod-disruption-budgets/db.json": `[
+		  {"metadata": {"name": "postgres", "namespace": "db"}, "spec": {"maxUnavailable": 0},
+		   "status": {"expectedPods": 3, "currentHealthy": 3, "desiredHealthy": 3, "disruptionsAllowed": 0}},
+		  {"metadata": {"name": "unused", "namespace": "db"}, "spec": {"minAvailable": "50%"},
+		   "status": {"expectedPods": 0}}
+		]`,
+	}
+	twoNodes := `{"items": [
+	  {"metadata": {"name": "node-1"}, "status": {"conditions": [{"type": "Ready", "status": "True"}]}},
+	  {"metadata": {"name": "node-2"}, "status": {"conditions": [{"type": "Ready", "status": "True"}]}},
+	  {"metadata": {"name": "node-3"}, "spec": {"unschedulable": true}, "status": {"conditions": [{"type": "Ready", "status": "True"}]}},
+	  {"metadata": {"name": "node-4"}, "status": {"conditions": [{"type": "Ready", "status": "False"}]}}
+	]}`
+	oneNode := `{"items": [
+	  {"metadata": {"name": "node-1"}, "status": {"conditions": [{"type": "Ready", "status": "True"}]}}
+	]}`
+
+	tests := []struct {
+		name     string
+		analyzer *troubleshootv1beta2.PodDisruptionBudget
+		nodes    string
+		want     []*AnalyzeResult
+		wantErr  bool
+	}{
+		{
+			name:     "default outcomes",
+			analyzer: &troubleshootv1beta2.PodDisruptionBudget{},
+			nodes:    twoNodes,
+			want: []*AnalyzeResult{
+				{IsFail: true, Title: "Pod Disruption Budget app/api", Message: "PodDisruptionBudget app/api prevents nodes from being drained: minAvailable is 2, all of its 2 pods must stay available"},
+				{IsPass: true, Title: "Pod Disruption Budget app/web", Message: "PodDisruptionBudget app/web allows 1 disruptions"},
+				{IsWarn: true, Title: "Pod Disruption Budget app/worker", Message: "PodDisruptionBudget app/worker allows no disruptions, 1 of its 3 pods are healthy and 1 must be"},
+				{IsFail: true, Title: "Pod Disruption Budget db/postgres", Message: "PodDisruptionBudget db/postgres prevents nodes from being drained: maxUnavailable is 0, none of its 3 pods can be evicted"},
+				{IsPass: true, Title: "Pod Disruption Budget db/unused", Message: "PodDisruptionBudget db/unused does not select any pods"},
+			},
+		},
+		{
+			name: "single schedulable node",
+			analyzer: &troubleshootv1beta2.PodDisruptionBudget{
+				AnalyzeMeta: troubleshootv1beta2.AnalyzeMeta{CheckName: "PDB"},
+				Namespaces:  []string{"app"},
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{Fail: &troubleshootv1beta2.SingleOutcome{When: "blocked == true", Message: "{{ .Name }}: {{ .Reason }}"}},
+					{Pass: &troubleshootv1beta2.SingleOutcome{Message: "{{ .Name }} on {{ .SchedulableNodes }} nodes"}},
+				},
+			},
+			nodes: oneNode,
+			want: []*AnalyzeResult{
+				{IsFail: true, Title: "PDB", Message: "api: minAvailable is 2, all of its 2 pods must stay available"},
+				{IsFail: true, Title: "PDB", Message: "web: the cluster has a single schedulable node, pods evicted from it cannot be rescheduled to keep 3 of them available"},
+				{IsFail: true, Title: "PDB", Message: "worker: the cluster has a single schedulable node, pods evicted from it cannot be rescheduled to keep 1 of them available"},
+			},
+		},
+		{
+			name: "nodes not collected",
+			analyzer: &troubleshootv1beta2.PodDisruptionBudget{
+				Namespaces: []string{"app"},
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{Fail: &troubleshootv1beta2.SingleOutcome{When: "blocked != false", Message: "blocked"}},
+					{Warn: &troubleshootv1beta2.SingleOutcome{When: "disruptionsAllowed < 1", Message: "{{ .CurrentHealthy }} healthy"}},
+				},
+			},
+			want: []*AnalyzeResult{
+				{IsFail: true, Title: "Pod Disruption Budget app/api", Message: "blocked"},
+				{IsWarn: true, Title: "Pod Disruption Budget app/worker", Message: "1 healthy"},
+			},
+		},
+		{
+			name: "unsupported condition",
+			analyzer: &troubleshootv1beta2.PodDisruptionBudget{
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{Fail: &troubleshootv1beta2.SingleOutcome{When: "healthyPods < 1"}},
+				},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := require.New(t)
+
+			getFile := func(path string) ([]byte, error) {
+				if path == "cluster-resources/nodes.json" && tt.nodes != "" {
+					return []byte(tt.nodes), nil
+				}
+				return nil, os.ErrNotExist
+			}
+			findFiles := func(pattern string, excluded []string) (map[string][]byte, error) {
+				matches := map[string][]byte{}
+				for name, content := range budgets {
+					if ok, _ := filepath.Match(pattern, name); ok {
+						matches[name] = []byte(content)
+					}
+				}
+				return matches, nil
+			}
+
+			a := AnalyzePodDisruptionBudget{analyzer: tt.analyzer}
+			results, err := a.Analyze(getFile, findFiles)
+			if tt.wantErr {
+				req.Error(err)
+				return
+			}
+			req.NoError(err)
+			req.Len(results, len(tt.want))
+
+			for i, want := range tt.want {
+				assert.Equal(t, want.IsPass, results[i].IsPass)
+				assert.Equal(t, want.IsWarn, results[i].IsWarn)
+				assert.Equal(t, want.IsFail, results[i].IsFail)
+				assert.Equal(t, want.Title, results[i].Title)
+				assert.Equal(t, want.Message, results[i].Message)
+			}
+		})
+	}
+}
@@ -0,0 +1,418 @@
+package analyzer
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/constants"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Pod Security Standard levels, as set by the pod-security.kubernetes.io/enforce label of namespaces
+const (
+	podSecurityLevelPrivileged = "privileged"
+	podSecurityLevelBaseline   = "baseline"
+	podSecurityLevelRestricted = "restricted"
+
+	podSecurityEnforceLabel = "pod-security.kubernetes.io/enforce"
+)
+
+var defaultPodSecurityOutcomes = []*troubleshootv1beta2.Outcome{
+	{Fail: &troubleshootv1beta2.SingleOutcome{When: "violatingWorkloads > 0", Message: "{{ .ViolatingWorkloads }} workloads of namespace {{ .Namespace }} violate the {{ .Level }} Pod Security Standard and their pods would be rejected when it is enforced: {{ .Violations }}"}},
+	{Pass: &troubleshootv1beta2.SingleOutcome{Message: "All workloads comply with the Pod Security Standards of their namespaces"}},
+}
+
+// baselineCapabilities are the capabilities the baseline level allows containers to add
+var baselineCapabilities = []corev1.Capability{
+	"AUDIT_WRITE", "CHOWN", "DAC_OVERRIDE", "FOWNER", "FSETID", "KILL", "MKNOD", "NET_BIND_SERVICE",
+	"SETFCAP", "SETGID", "SETPCAP", "SETUID", "SYS_CHROOT",
+}
+
+// baselineSELinuxTypes are the SELinux types the baseline level allows
+var baselineSELinuxTypes = []string{"", "container_t", "container_init_t", "container_kvm_t", "container_engine_t"}
+
+// baselineSysctls are the sysctls the baseline level allows, those that are namespaced and isolated
+var baselineSysctls = []string{
+	"kernel.shm_rmid_forced",
+	"net.ipv4.ip_local_port_range",
+	"net.ipv4.ip_unprivileged_port_start",
+	"net.ipv4.tcp_syncookies",
+	"net.ipv4.ping_group_range",
+	"net.ipv4.ip_local_reserved_ports",
+	"net.ipv4.tcp_keepalive_time",
+	"net.ipv4.tcp_fin_timeout",
+	"net.ipv4.tcp_keepalive_intvl",
+	"net.ipv4.tcp_keepalive_probes",
+}
+
+// podSecurityTemplateData is passed to the messages of the outcomes. It is empty when no workload
+// violates the Pod Security Standard of its namespace.
+type podSecurityTemplateData struct {
+	Namespace string
+	Level     string
+	// Workloads is the number of workloads of the namespace that were evaluated
+	Workloads int
+	// ViolatingWorkloads is the number of workloads that violate the level
+	ViolatingWorkloads int
+	// Violations are the violating workloads with the checks they fail, e.g.
+	// "Deployment/api (allowPrivilegeEscalation != false, runAsNonRoot != true)", semicolon separated
+	Violations string
+
+	workloads  map[string]bool
+	violations map[string][]string
+}
+
+type AnalyzePodSecurity struct {
+	analyzer *troubleshootv1beta2.PodSecurityAnalyze
+}
+
+func (a *AnalyzePodSecurity) Title() string {
+	if a.analyzer.CheckName != "" {
+		return a.analyzer.CheckName
+	}
+
+	return "Pod Security"
+}
+
+func (a *AnalyzePodSecurity) IsExcluded() (bool, error) {
+	return isExcluded(a.analyzer.Exclude)
+}
+
+func (a *AnalyzePodSecurity) Analyze(getFile getCollectedFileContents, findFiles getChildCollectedFileContents) ([]*AnalyzeResult, error) {
+	if a.analyzer.Level != "" && a.analyzer.Level != podSecurityLevelBaseline && a.analyzer.Level != podSecurityLevelRestricted {
+		return nil, errors.Errorf("invalid level %q, must be baseline or restricted", a.analyzer.Level)
+	}
+
+	levels, err := a.namespaceLevels(getFile)
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := findFiles(filepath.Join(constants.CLUSTER_RESOURCES_DIR, constants.CLUSTER_RESOURCES_PODS, "*.json"), nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read collected pods")
+	}
+
+	namespaces := map[string]*podSecurityTemplateData{}
+	for name, content := range files {
+		pods, err := decodeCollectedItems[corev1.Pod](content)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to unmarshal %s", name)
+		}
+		for _, pod := range pods {
+			if len(a.analyzer.Namespaces) > 0 && !slices.Contains(a.analyzer.Namespaces, pod.Namespace) {
+				continue
+			}
+
+			level := a.analyzer.Level
+			if level == "" {
+				level = levels[pod.Namespace]
+			}
+			if level == "" {
+				level = podSecurityLevelBaseline
+			}
+			if level == podSecurityLevelPrivileged {
+				continue
+			}
+
+			data, ok := namespaces[pod.Namespace]
+			if !ok {
+				data = &podSecurityTemplateData{
+					Namespace:  pod.Namespace,
+					Level:      level,
+					workloads:  map[string]bool{},
+					violations: map[string][]string{},
+				}
+				namespaces[pod.Namespace] = data
+			}
+
+			kind, workloadName := podWorkload(pod)
+			workload := fmt.Sprintf("%s/%s", kind, workloadName)
+			data.workloads[workload] = true
+			for _, check := range checkPodSecurity(pod, level) {
+				if !slices.Contains(data.violations[workload], check) {
+					data.violations[workload] = append(data.violations[workload], check)
+				}
+			}
+		}
+	}
+
+	keys := []string{}
+	for key, data := range namespaces {
+		if len(data.violations) > 0 {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	outcomes := a.analyzer.Outcomes
+	if len(outcomes) == 0 {
+		outcomes = defaultPodSecurityOutcomes
+	}
+
+	results := []*AnalyzeResult{}
+	for _, key := range keys {
+		data := namespaces[key]
+		workloads := []string{}
+		for workload := range data.violations {
+			workloads = append(workloads, workload)
+		}
+		sort.Strings(workloads)
+
+		violations := []string{}
+		for _, workload := range workloads {
+			violations = append(violations, fmt.Sprintf("%s (%s)", workload, strings.Join(data.violations[workload], ", ")))
+		}
+		data.Workloads = len(data.workloads)
+		data.ViolatingWorkloads = len(workloads)
+		data.Violations = strings.Join(violations, "; ")
+
+		result, err := a.analyzeNamespace(data, outcomes)
+		if err != nil {
+			return nil, err
+		}
+		if result != nil {
+			result.InvolvedObject = &corev1.ObjectReference{
+				Kind: "Namespace",
+				Name: data.Namespace,
+			}
+			results = append(results, result)
+		}
+	}
+
+	if len(results) == 0 {
+		result, err := a.analyzeNamespace(&podSecurityTemplateData{}, outcomes)
+		if err != nil {
+			return nil, err
+		}
+		if result != nil {
+			results = append(results, result)
+		}
+	}
+
+	return results, nil
+}
+
+// namespaceLevels returns the levels of the pod-security.kubernetes.io/enforce labels of the
+// collected namespaces, by namespace. The namespaces are not read when a level is set.
+func (a *AnalyzePodSecurity) namespaceLevels(getFile getCollectedFileContents) (map[string]string, error) {
+	if a.analyzer.Level != "" {
+		return nil, nil
+	}
+
+	fullPath := filepath.Join(constants.CLUSTER_RESOURCES_DIR, fmt.Sprintf("%s.json", constants.CLUSTER_RESOURCES_NAMESPACES))
+	collected, err := getFile(fullPath)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read collected file name: %s", fullPath)
+	}
+
+	namespaces, err := decodeCollectedItems[corev1.Namespace](collected)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal %s", fullPath)
+	}
+
+	levels := map[string]string{}
+	for _, namespace := range namespaces {
+		if level, ok := namespace.Labels[podSecurityEnforceLabel]; ok {
+			levels[namespace.Name] = level
+		}
+	}
+	return levels, nil
+}
+
+func (a *AnalyzePodSecurity) analyzeNamespace(data *podSecurityTemplateData, outcomes []*troubleshootv1beta2.Outcome) (*AnalyzeResult, error) {
+	return analyzeTemplatedOutcomes(a.Title(), a.analyzer.Strict.BoolOrDefaultFalse(), outcomes, data, func(when string) (bool, error) {
+		return comparePodSecurity(data, when)
+	})
+}
+
+// comparePodSecurity evaluates a when clause against a namespace. Supported conditions are the
+// counts workloads and violatingWorkloads, e.g. "violatingWorkloads > 0".
+func comparePodSecurity(data *podSecurityTemplateData, when string) (bool, error) {
+	parts := strings.Fields(when)
+	if len(parts) != 3 {
+		return false, fmt.Errorf("expected 3 parts in when %q, got %d", when, len(parts))
+	}
+	key, condition := parts[0], parts[1]+" "+parts[2]
+
+	switch key {
+	case "workloads":
+		return compareActualToWhen(condition, data.Workloads)
+	case "violatingWorkloads":
+		return compareActualToWhen(condition, data.ViolatingWorkloads)
+	}
+	return false, fmt.Errorf("unsupported condition %q, must be one of workloads or violatingWorkloads", key)
+}
+
+// podSecurityContainer is the security relevant part of a container, init container or ephemeral
+// container
+type podSecurityContainer struct {
+	securityContext *corev1.SecurityContext
+	ports           []corev1.ContainerPort
+}
+
+func podSecurityContainers(spec corev1.PodSpec) []podSecurityContainer {
+	containers := []podSecurityContainer{}
+	for _, c := range spec.InitContainers {
+		containers = append(containers, podSecurityContainer{securityContext: c.SecurityContext, ports: c.Ports})
+	}
+	for _, c := range spec.Containers {
+		containers = append(containers, podSecurityContainer{securityContext: c.SecurityContext, ports: c.Ports})
+	}
+	for _, c := range spec.EphemeralContainers {
+		containers = append(containers, podSecurityContainer{securityContext: c.SecurityContext, ports: c.Ports})
+	}
+	return containers
+}
+
+// checkPodSecurity returns the names of the checks of the Pod Security Standard level the pod
+// fails, as Pod Security Admission names them
+func checkPodSecurity(pod corev1.Pod, level string) []string {
+	failed := []string{}
+	fail := func(check string) {
+		if !slices.Contains(failed, check) {
+			failed = append(failed, check)
+		}
+	}
+
+	spec := pod.Spec
+	podContext := spec.SecurityContext
+	if podContext == nil {
+		podContext = &corev1.PodSecurityContext{}
+	}
+	containers := podSecurityContainers(spec)
+
+	if podContext.WindowsOptions != nil && podContext.WindowsOptions.HostProcess != nil && *podContext.WindowsOptions.HostProcess {
+		fail("hostProcess")
+	}
+	if spec.HostNetwork || spec.HostPID || spec.HostIPC {
+		fail("host namespaces")
+	}
+	for _, volume := range spec.Volumes {
+		if volume.HostPath != nil {
+			fail("hostPath volumes")
+		}
+	}
+	for key, value := range pod.Annotations {
+		if strings.HasPrefix(key, corev1.DeprecatedAppArmorBetaContainerAnnotationKeyPrefix) && value == corev1.DeprecatedAppArmorBetaProfileNameUnconfined {
+			fail("AppArmor")
+		}
+	}
+	if podContext.AppArmorProfile != nil && podContext.AppArmorProfile.Type == corev1.AppArmorProfileTypeUnconfined {
+		fail("AppArmor")
+	}
+	if !baselineSELinuxOptions(podContext.SELinuxOptions) {
+		fail("SELinux")
+	}
+	if podContext.SeccompProfile != nil && podContext.SeccompProfile.Type == corev1.SeccompProfileTypeUnconfined {
+		fail("seccompProfile")
+	}
+	for _, sysctl := range podContext.Sysctls {
+		if !slices.Contains(baselineSysctls, sysctl.Name) {
+			fail("forbidden sysctls")
+		}
+	}
+
+	for _, container := range containers {
+		for _, port := range container.ports {
+			if port.HostPort != 0 {
+				fail("hostPort")
+			}
+		}
+
+		sc := container.securityContext
+		if sc == nil {
+			continue
+		}
+		if sc.WindowsOptions != nil && sc.WindowsOptions.HostProcess != nil && *sc.WindowsOptions.HostProcess {
+			fail("hostProcess")
+		}
+		if sc.Privileged != nil && *sc.Privileged {
+			fail("privileged")
+		}
+		if sc.Capabilities != nil {
+			for _, capability := range sc.Capabilities.Add {
+				if !slices.Contains(baselineCapabilities, capability) {
+					fail("non-default capabilities")
+				}
+			}
+		}
+		if sc.AppArmorProfile != nil && sc.AppArmorProfile.Type == corev1.AppArmorProfileTypeUnconfined {
+			fail("AppArmor")
+		}
+		if !baselineSELinuxOptions(sc.SELinuxOptions) {
+			fail("SELinux")
+		}
+		if sc.ProcMount != nil && *sc.ProcMount != corev1.DefaultProcMount {
+			fail("procMount")
+		}
+		if sc.SeccompProfile != nil && sc.SeccompProfile.Type == corev1.SeccompProfileTypeUnconfined {
+			fail("seccompProfile")
+		}
+	}
+
+	if level != podSecurityLevelRestricted {
+		return failed
+	}
+
+	for _, volume := range spec.Volumes {
+		source := volume.VolumeSource
+		if source.ConfigMap == nil && source.CSI == nil && source.DownwardAPI == nil && source.EmptyDir == nil &&
+			source.Ephemeral == nil && source.PersistentVolumeClaim == nil && source.Projected == nil && source.Secret == nil {
+			fail("restricted volume types")
+		}
+	}
+
+	podRunAsNonRoot := podContext.RunAsNonRoot != nil && *podContext.RunAsNonRoot
+	podSeccomp := restrictedSeccompProfile(podContext.SeccompProfile)
+	if podContext.RunAsUser != nil && *podContext.RunAsUser == 0 {
+		fail("runAsUser=0")
+	}
+
+	for _, container := range containers {
+		sc := container.securityContext
+		if sc == nil {
+			sc = &corev1.SecurityContext{}
+		}
+
+		if sc.AllowPrivilegeEscalation == nil || *sc.AllowPrivilegeEscalation {
+			fail("allowPrivilegeEscalation != false")
+		}
+		if (sc.RunAsNonRoot != nil && !*sc.RunAsNonRoot) || (sc.RunAsNonRoot == nil && !podRunAsNonRoot) {
+			fail("runAsNonRoot != true")
+		}
+		if sc.RunAsUser != nil && *sc.RunAsUser == 0 {
+			fail("runAsUser=0")
+		}
+		if (sc.SeccompProfile != nil && !restrictedSeccompProfile(sc.SeccompProfile)) || (sc.SeccompProfile == nil && !podSeccomp) {
+			fail("seccompProfile")
+		}
+		if sc.Capabilities == nil || !slices.Contains(sc.Capabilities.Drop, "ALL") {
+			fail("unrestricted capabilities")
+		} else {
+			for _, capability := range sc.Capabilities.Add {
+				if capability != "NET_BIND_SERVICE" {
+					fail("unrestricted capabilities")
+				}
+			}
+		}
+	}
+
+	return failed
+}
+
+func baselineSELinuxOptions(options *corev1.SELinuxOptions) bool {
+	if options == nil {
+		return true
+	}
+	return slices.Contains(baselineSELinuxTypes, options.Type) && options.User == "" && options.Role == ""
+}
+
+func restrictedSeccompProfile(profile *corev1.SeccompProfile) bool {
+	return profile != nil && (profile.Type == corev1.SeccompProfileTypeRuntimeDefault || profile.Type == corev1.SeccompProfileTypeLocalhost)
+}
@@ -0,0 +1,179 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"testing"
+
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+func restrictedSecurityContext() *corev1.SecurityContext {
+	return &corev1.SecurityContext{
+		AllowPrivilegeEscalation: ptr.To(false),
+		RunAsNonRoot:             ptr.To(true),
+		SeccompProfile:           &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault},
+		Capabilities:             &corev1.Capabilities{Drop: []corev1.Capability{"ALL"}},
+	}
+}
+
+func Test_checkPodSecurity(t *testing.T) {
+	tests := []struct {
+		name           string
+		spec           corev1.PodSpec
+		annotations    map[string]string
+		wantBaseline   []string
+		wantRestricted []string
+	}{
+		{
+			name:           "restricted",
+			spec:           corev1.PodSpec{Containers: []corev1.Container{{Name: "app", SecurityContext: restrictedSecurityContext()}}},
+			wantBaseline:   []string{},
+			wantRestricted: []string{},
+		},
+		{
+			name:           "defaults",
+			spec:           corev1.PodSpec{Containers: []corev1.Container{{Name: "app"}}},
+			wantBaseline:   []string{},
+			wantRestricted: []string{"allowPrivilegeEscalation != false", "runAsNonRoot != true", "seccompProfile", "unrestricted capabilities"},
+		},
+		{
+			name: "pod security context",
+			spec: corev1.PodSpec{
+				SecurityContext: &corev1.PodSecurityContext{
+					RunAsNonRoot:   ptr.To(true),
+					SeccompProfile: &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeLocalhost},
+				},
+				Volumes: []corev1.Volume{{Name: "data", VolumeSource: corev1.VolumeSource{NFS: &corev1.NFSVolumeSource{}}}},
+				Containers: []corev1.Container{{Name: "app", SecurityContext: &corev1.SecurityContext{
+					AllowPrivilegeEscalation: ptr.To(false),
+					RunAsUser:                ptr.To(int64(0)),
+					Capabilities:             &corev1.Capabilities{Drop: []corev1.Capability{"ALL"}, Add: []corev1.Capability{"NET_BIND_SERVICE", "CHOWN"}},
+				}}},
+			},
+			wantBaseline:   []string{},
+			wantRestricted: []string{"restricted volume types", "runAsUser=0", "unrestricted capabilities"},
+		},
+		{
+			name: "privileged",
+			spec: corev1.PodSpec{
+				HostNetwork: true,
+				Volumes:     []corev1.Volume{{Name: "root", VolumeSource: corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{Path: "/"}}}},
+				SecurityContext: &corev1.PodSecurityContext{
+					Sysctls: []corev1.Sysctl{{Name: "net.ipv4.tcp_syncookies"}, {Name: "kernel.msgmax"}},
+				},
+				InitContainers: []corev1.Container{{Name: "init", SecurityContext: &corev1.SecurityContext{
+					Capabilities:   &corev1.Capabilities{Add: []corev1.Capability{"NET_ADMIN"}},
+					SELinuxOptions: &corev1.SELinuxOptions{Type: "spc_t"},
+				}}},
+				Containers: []corev1.Container{{
+					Name:            "agent",
+					Ports:           []corev1.ContainerPort{{ContainerPort: 9100, HostPort: 9100}},
+					SecurityContext: &corev1.SecurityContext{Privileged: ptr.To(true)},
+				}},
+			},
+			annotations:  map[string]string{"container.apparmor.security.beta.kubernetes.io/agent": "unconfined"},
+			wantBaseline: []string{"host namespaces", "hostPath volumes", "AppArmor", "forbidden sysctls", "non-default capabilities", "SELinux", "hostPort", "privileged"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pod := corev1.Pod{ObjectMeta: metav1.ObjectMeta{Annotations: tt.annotations}, Spec: tt.spec}
+			assert.Equal(t, tt.wantBaseline, checkPodSecurity(pod, "baseline"))
+			if tt.wantRestricted != nil {
+				assert.Equal(t, tt.wantRestricted, checkPodSecurity(pod, "restricted"))
+			}
+		})
+	}
+}
+
+func TestAnalyzePodSecurity(t *testing.T) {
+	namespaces := corev1.NamespaceList{Items: []corev1.Namespace{
+		{ObjectMeta: metav1.ObjectMeta{Name: "app", Labels: map[string]string{"pod-security.kubernetes.io/enforce": "restricted"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "monitoring"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "kube-system", Labels: map[string]string{"pod-security.kubernetes.io/enforce": "privileged"}}},
+	}}
+
+	apiPod := func(name string) corev1.Pod {
+		return corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:       "app",
+				Name:            name,
+				Labels:          map[string]string{"pod-template-hash": "7d9f8c"},
+				OwnerReferences: []metav1.OwnerReference{{Kind: "ReplicaSet", Name: "api-7d9f8c", Controller: ptr.To(true)}},
+			},
+			Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "api", SecurityContext: &corev1.SecurityContext{RunAsNonRoot: ptr.To(true)}}}},
+		}
+	}
+	pods := map[string][]corev1.Pod{
+		"app": {
+			apiPod("api-7d9f8c-abcde"),
+			apiPod("api-7d9f8c-fghij"),
+			{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "app", Name: "web-0", OwnerReferences: []metav1.OwnerReference{{Kind: "StatefulSet", Name: "web", Controller: ptr.To(true)}}},
+				Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "web", SecurityContext: restrictedSecurityContext()}}},
+			},
+		},
+		"monitoring": {
+			{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "monitoring", Name: "node-exporter-x7d2q", OwnerReferences: []metav1.OwnerReference{{Kind: "DaemonSet", Name: "node-exporter", Controller: ptr.To(true)}}},
+				Spec:       corev1.PodSpec{HostPID: true, Containers: []corev1.Container{{Name: "node-exporter"}}},
+			},
+		},
+		"kube-system": {
+			{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "kube-system", Name: "kube-proxy-x7d2q"},
+				Spec:       corev1.PodSpec{HostNetwork: true, Containers: []corev1.Container{{Name: "kube-proxy"}}},
+			},
+		},
+	}
+
+	marshal := func(v interface{}) []byte {
+		b, err := json.Marshal(v)
+		require.NoError(t, err)
+		return b
+	}
+	getFile := func(filename string) ([]byte, error) {
+		require.Equal(t, "cluster-resources/namespaces.json", filename)
+		return marshal(namespaces), nil
+	}
+	findFiles := func(pattern string, _ []string) (map[string][]byte, error) {
+		require.Equal(t, "cluster-resources/pods/*.json", pattern)
+		files := map[string][]byte{}
+		for namespace, items := range pods {
+			files["cluster-resources/pods/"+namespace+".json"] = marshal(corev1.PodList{Items: items})
+		}
+		return files, nil
+	}
+
+	a := AnalyzePodSecurity{analyzer: &troubleshootv1beta2.PodSecurityAnalyze{}}
+	results, err := a.Analyze(getFile, findFiles)
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+
+	assert.True(t, results[0].IsFail)
+	assert.Equal(t, "Pod Security", results[0].Title)
+	assert.Equal(t, "1 workloads of namespace app violate the restricted Pod Security Standard and their pods would be rejected when it is enforced: "+
+		"Deployment/api (allowPrivilegeEscalation != false, seccompProfile, unrestricted capabilities)", results[0].Message)
+	assert.Equal(t, &corev1.ObjectReference{Kind: "Namespace", Name: "app"}, results[0].InvolvedObject)
+
+	assert.True(t, results[1].IsFail)
+	assert.Equal(t, "1 workloads of namespace monitoring violate the baseline Pod Security Standard and their pods would be rejected when it is enforced: "+
+		"DaemonSet/node-exporter (host namespaces)", results[1].Message)
+
+	a = AnalyzePodSecurity{analyzer: &troubleshootv1beta2.PodSecurityAnalyze{Level: "baseline", Namespaces: []string{"app"}}}
+	results, err = a.Analyze(nil, findFiles)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.True(t, results[0].IsPass)
+	assert.Equal(t, "All workloads comply with the Pod Security Standards of their namespaces", results[0].Message)
+
+	a = AnalyzePodSecurity{analyzer: &troubleshootv1beta2.PodSecurityAnalyze{Level: "strict"}}
+	_, err = a.Analyze(nil, findFiles)
+	assert.Error(t, err)
+}
@@ -0,0 +1,219 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/collect"
+)
+
+// PolicyViolationsDefaultOutcomes are evaluated when the analyzer sets no outcomes
+var PolicyViolationsDefaultOutcomes = []*troubleshootv1beta2.Outcome{
+	{
+		Warn: &troubleshootv1beta2.SingleOutcome{
+			When:    "engines == 0",
+			Message: "No policy reports of Kyverno or Gatekeeper were found",
+		},
+	},
+	{
+		Fail: &troubleshootv1beta2.SingleOutcome{
+			When:    "violations > 0",
+			Message: "Resources violate the policies {{ .ViolatedPolicies }}",
+		},
+	},
+	{
+		Warn: &troubleshootv1beta2.SingleOutcome{
+			When:    "warnings > 0",
+			Message: "Resources are warned about by the policies {{ .WarnedPolicies }}",
+		},
+	},
+	{
+		Pass: &troubleshootv1beta2.SingleOutcome{
+			Message: "No resource violates the policies of {{ .Engines }}",
+		},
+	},
+}
+
+// policyViolationsTemplateData is passed to the messages of the outcomes
+type policyViolationsTemplateData struct {
+	// Engines are the policy engines whose reports were found, comma separated
+	Engines string
+	// Violations and Warnings are the numbers of violations of the selected policies that are
+	// enforced, and that are only warned about
+	Violations int
+	Warnings   int
+	// ViolatedPolicies and WarnedPolicies are the policies with their number of violations, the
+	// most violated first, comma separated
+	ViolatedPolicies string
+	WarnedPolicies   string
+
+	report *policyViolationsReport
+}
+
+// policyViolationsReport is the violations of the selected policies, by policy
+type policyViolationsReport struct {
+	engines          []string
+	violations       int
+	warnings         int
+	violatedPolicies []policyViolationCount
+	warnedPolicies   []policyViolationCount
+}
+
+type policyViolationCount struct {
+	policy string
+	count  int
+}
+
+type AnalyzePolicyViolations struct {
+	analyzer *troubleshootv1beta2.PolicyViolationsAnalyze
+}
+
+func (a *AnalyzePolicyViolations) Title() string {
+	if a.analyzer.CheckName != "" {
+		return a.analyzer.CheckName
+	}
+	return "Policy Violations"
+}
+
+func (a *AnalyzePolicyViolations) IsExcluded() (bool, error) {
+	return isExcluded(a.analyzer.Exclude)
+}
+
+func (a *AnalyzePolicyViolations) Analyze(getFile getCollectedFileContents, findFiles getChildCollectedFileContents) ([]*AnalyzeResult, error) {
+	fullPath := collect.PolicyViolationsOutputPath(a.analyzer.CollectorName)
+	collected, err := getFile(fullPath)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read collected file name: %s", fullPath)
+	}
+
+	info := collect.PolicyViolationsInfo{}
+	if err := json.Unmarshal(collected, &info); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse %s", fullPath)
+	}
+
+	report, err := newPolicyViolationsReport(info, a.analyzer.Policies)
+	if err != nil {
+		return nil, err
+	}
+	data := newPolicyViolationsTemplateData(report)
+
+	outcomes := a.analyzer.Outcomes
+	if len(outcomes) == 0 {
+		outcomes = PolicyViolationsDefaultOutcomes
+	}
+
+	result, err := analyzeTemplatedOutcomes(a.Title(), a.analyzer.Strict.BoolOrDefaultFalse(), outcomes, data, func(when string) (bool, error) {
+		return comparePolicyViolations(data.report, when)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		return []*AnalyzeResult{}, nil
+	}
+	return []*AnalyzeResult{result}, nil
+}
+
+// newPolicyViolationsReport counts the violations of the policies matching one of the patterns,
+// or of all policies when there are none
+func newPolicyViolationsReport(info collect.PolicyViolationsInfo, patterns []string) (*policyViolationsReport, error) {
+	for _, pattern := range patterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, errors.Wrapf(err, "invalid policy pattern %q", pattern)
+		}
+	}
+
+	report := &policyViolationsReport{engines: info.Engines}
+	violated, warned := map[string]int{}, map[string]int{}
+	for _, violation := range info.Violations {
+		if !policySelected(violation.Policy, patterns) {
+			continue
+		}
+		if violation.Action == collect.PolicyActionDeny {
+			report.violations++
+			violated[violation.Policy]++
+		} else {
+			report.warnings++
+			warned[violation.Policy]++
+		}
+	}
+	report.violatedPolicies = sortPolicyViolationCounts(violated)
+	report.warnedPolicies = sortPolicyViolationCounts(warned)
+
+	return report, nil
+}
+
+func policySelected(policy string, patterns []string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(pattern, policy); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// sortPolicyViolationCounts returns the counts of the policies, the largest first, then by name
+func sortPolicyViolationCounts(counts map[string]int) []policyViolationCount {
+	sorted := []policyViolationCount{}
+	for policy, count := range counts {
+		sorted = append(sorted, policyViolationCount{policy: policy, count: count})
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].count != sorted[j].count {
+			return sorted[i].count > sorted[j].count
+		}
+		return sorted[i].policy < sorted[j].policy
+	})
+	return sorted
+}
+
+func newPolicyViolationsTemplateData(report *policyViolationsReport) *policyViolationsTemplateData {
+	format := func(counts []policyViolationCount) string {
+		policies := []string{}
+		for _, c := range counts {
+			policies = append(policies, fmt.Sprintf("%s (%d)", c.policy, c.count))
+		}
+		return strings.Join(policies, ", ")
+	}
+
+	return &policyViolationsTemplateData{
+		Engines:          strings.Join(report.engines, ", "),
+		Violations:       report.violations,
+		Warnings:         report.warnings,
+		ViolatedPolicies: format(report.violatedPolicies),
+		WarnedPolicies:   format(report.warnedPolicies),
+		report:           report,
+	}
+}
+
+// comparePolicyViolations evaluates a when clause against the report. Supported conditions are the
+// counts engines, violations, warnings, violatedPolicies and warnedPolicies, e.g. "violations > 0".
+func comparePolicyViolations(report *policyViolationsReport, when string) (bool, error) {
+	parts := strings.Fields(when)
+	if len(parts) != 3 {
+		return false, fmt.Errorf("expected 3 parts in when %q, got %d", when, len(parts))
+	}
+	key, condition := parts[0], parts[1]+" "+parts[2]
+
+	switch key {
+	case "engines":
+		return compareActualToWhen(condition, len(report.engines))
+	case "violations":
+		return compareActualToWhen(condition, report.violations)
+	case "warnings":
+		return compareActualToWhen(condition, report.warnings)
+	case "violatedPolicies":
+		return compareActualToWhen(condition, len(report.violatedPolicies))
+	case "warnedPolicies":
+		return compareActualToWhen(condition, len(report.warnedPolicies))
+	}
+	return false, fmt.Errorf("unsupported condition %q, must be one of engines, violations, warnings, violatedPolicies or warnedPolicies", key)
+}
@@ -0,0 +1,99 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"testing"
+
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/collect"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func policyViolationsInfo() collect.PolicyViolationsInfo {
+	return collect.PolicyViolationsInfo{
+		Engines: []string{collect.PolicyEngineKyverno, collect.PolicyEngineGatekeeper},
+		Violations: []collect.PolicyViolation{
+			{Engine: "gatekeeper", Policy: "psp-privileged-container", Action: "deny", Result: "deny", Kind: "Pod", Namespace: "app", Name: "debug"},
+			{Engine: "kyverno", Policy: "require-limits", Action: "deny", Result: "fail", Kind: "Deployment", Namespace: "app", Name: "api"},
+			{Engine: "kyverno", Policy: "require-limits", Action: "deny", Result: "fail", Kind: "Deployment", Namespace: "app", Name: "worker"},
+			{Engine: "kyverno", Policy: "require-probes", Action: "warn", Result: "warn", Kind: "Deployment", Namespace: "app", Name: "api"},
+		},
+	}
+}
+
+func Test_newPolicyViolationsReport(t *testing.T) {
+	report, err := newPolicyViolationsReport(policyViolationsInfo(), nil)
+	require.NoError(t, err)
+	assert.Equal(t, 3, report.violations)
+	assert.Equal(t, 1, report.warnings)
+
+	data := newPolicyViolationsTemplateData(report)
+	assert.Equal(t, "kyverno, gatekeeper", data.Engines)
+	assert.Equal(t, "require-limits (2), psp-privileged-container (1)", data.ViolatedPolicies)
+	assert.Equal(t, "require-probes (1)", data.WarnedPolicies)
+
+	report, err = newPolicyViolationsReport(policyViolationsInfo(), []string{"require-*"})
+	require.NoError(t, err)
+	assert.Equal(t, 2, report.violations)
+	assert.Equal(t, []policyViolationCount{{policy: "require-limits", count: 2}}, report.violatedPolicies)
+
+	_, err = newPolicyViolationsReport(policyViolationsInfo(), []string{"require-["})
+	assert.Error(t, err)
+}
+
+func TestAnalyzePolicyViolations(t *testing.T) {
+	tests := []struct {
+		name     string
+		info     collect.PolicyViolationsInfo
+		analyzer *troubleshootv1beta2.PolicyViolationsAnalyze
+		want     *AnalyzeResult
+	}{
+		{
+			name:     "violations",
+			info:     policyViolationsInfo(),
+			analyzer: &troubleshootv1beta2.PolicyViolationsAnalyze{},
+			want:     &AnalyzeResult{IsFail: true, Message: "Resources violate the policies require-limits (2), psp-privileged-container (1)"},
+		},
+		{
+			name:     "warnings of selected policies",
+			info:     policyViolationsInfo(),
+			analyzer: &troubleshootv1beta2.PolicyViolationsAnalyze{Policies: []string{"require-probes"}},
+			want:     &AnalyzeResult{IsWarn: true, Message: "Resources are warned about by the policies require-probes (1)"},
+		},
+		{
+			name:     "no violations",
+			info:     collect.PolicyViolationsInfo{Engines: []string{collect.PolicyEngineKyverno}},
+			analyzer: &troubleshootv1beta2.PolicyViolationsAnalyze{},
+			want:     &AnalyzeResult{IsPass: true, Message: "No resource violates the policies of kyverno"},
+		},
+		{
+			name:     "no engines",
+			info:     collect.PolicyViolationsInfo{Engines: []string{}},
+			analyzer: &troubleshootv1beta2.PolicyViolationsAnalyze{},
+			want:     &AnalyzeResult{IsWarn: true, Message: "No policy reports of Kyverno or Gatekeeper were found"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := json.Marshal(tt.info)
+			require.NoError(t, err)
+			getFile := func(filename string) ([]byte, error) {
+				require.Equal(t, "policy-violations/policy-violations.json", filename)
+				return b, nil
+			}
+
+			a := AnalyzePolicyViolations{analyzer: tt.analyzer}
+			results, err := a.Analyze(getFile, nil)
+			require.NoError(t, err)
+			require.Len(t, results, 1)
+
+			assert.Equal(t, tt.want.IsPass, results[0].IsPass)
+			assert.Equal(t, tt.want.IsWarn, results[0].IsWarn)
+			assert.Equal(t, tt.want.IsFail, results[0].IsFail)
+			assert.Equal(t, "Policy Violations", results[0].Title)
+			assert.Equal(t, tt.want.Message, results[0].Message)
+		})
+	}
+}
@@ -70,6 +70,7 @@ func (a *AnalyzePostgres) analyzePostgres(analyzer *troubleshootv1beta2.Database
 				result.IsFail = true
 				result.Message = outcome.Fail.Message
 				result.URI = outcome.Fail.URI
+				result.Remediation = outcome.Fail.Remediation
 
 				return result, nil
 			}
@@ -89,6 +90,7 @@ func (a *AnalyzePostgres) analyzePostgres(analyzer *troubleshootv1beta2.Database
 
 				result.IsFail = true
 				result.URI = outcome.Fail.URI
+				result.Remediation = outcome.Fail.Remediation
 
 				return result, nil
 			}
@@ -97,6 +99,7 @@ func (a *AnalyzePostgres) analyzePostgres(analyzer *troubleshootv1beta2.Database
 				result.IsWarn = true
 				result.Message = outcome.Warn.Message
 				result.URI = outcome.Warn.URI
+				result.Remediation = outcome.Warn.Remediation
 
 				return result, nil
 			}
@@ -110,6 +113,7 @@ func (a *AnalyzePostgres) analyzePostgres(analyzer *troubleshootv1beta2.Database
 				result.IsWarn = true
 				result.Message = outcome.Warn.Message
 				result.URI = outcome.Warn.URI
+				result.Remediation = outcome.Warn.Remediation
 
 				return result, nil
 			}
@@ -118,6 +122,7 @@ func (a *AnalyzePostgres) analyzePostgres(analyzer *troubleshootv1beta2.Database
 				result.IsPass = true
 				result.Message = outcome.Pass.Message
 				result.URI = outcome.Pass.URI
+				result.Remediation = outcome.Pass.Remediation
 
 				return result, nil
 			}
@@ -131,6 +136,7 @@ func (a *AnalyzePostgres) analyzePostgres(analyzer *troubleshootv1beta2.Database
 				result.IsPass = true
 				result.Message = outcome.Pass.Message
 				result.URI = outcome.Pass.URI
+				result.Remediation = outcome.Pass.Remediation
 
 				return result, nil
 			}
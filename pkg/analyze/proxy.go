@@ -0,0 +1,153 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/collect"
+)
+
+// proxyTemplateData is passed to the messages of the outcomes
+type proxyTemplateData struct {
+	// MissingNoProxy are the CIDRs missing from NO_PROXY, comma separated
+	MissingNoProxy string
+	// MissingNoProxyContainers are the containers whose NO_PROXY misses CIDRs, as
+	// namespace/pod/container and comma separated
+	MissingNoProxyContainers string
+}
+
+type AnalyzeProxy struct {
+	analyzer *troubleshootv1beta2.ProxyAnalyze
+}
+
+func (a *AnalyzeProxy) Title() string {
+	if a.analyzer.CheckName != "" {
+		return a.analyzer.CheckName
+	}
+	return "Proxy"
+}
+
+func (a *AnalyzeProxy) IsExcluded() (bool, error) {
+	return isExcluded(a.analyzer.Exclude)
+}
+
+func (a *AnalyzeProxy) Analyze(getFile getCollectedFileContents, findFiles getChildCollectedFileContents) ([]*AnalyzeResult, error) {
+	fullPath := collect.ProxyOutputPath(a.analyzer.CollectorName)
+	collected, err := getFile(fullPath)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read collected file name: %s", fullPath)
+	}
+
+	workloads := []collect.WorkloadProxySettings{}
+	if err := json.Unmarshal(collected, &workloads); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse %s", fullPath)
+	}
+
+	settings := []collect.ProxySettings{}
+	containers := []string{}
+	for _, workload := range workloads {
+		settings = append(settings, workload.ProxySettings)
+		if len(workload.MissingNoProxy) > 0 {
+			containers = append(containers, fmt.Sprintf("%s/%s/%s", workload.Namespace, workload.Pod, workload.Container))
+		}
+	}
+
+	data := &proxyTemplateData{
+		MissingNoProxy:           strings.Join(missingNoProxy(settings), ", "),
+		MissingNoProxyContainers: strings.Join(containers, ", "),
+	}
+
+	result, err := analyzeTemplatedOutcomes(a.Title(), a.analyzer.Strict.BoolOrDefaultFalse(), a.analyzer.Outcomes, data, func(when string) (bool, error) {
+		parts := strings.Fields(when)
+		if len(parts) != 3 {
+			return false, fmt.Errorf("expected 3 parts in when %q, got %d", when, len(parts))
+		}
+		return compareProxyCondition(settings, parts[0], parts[1], parts[2])
+	})
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		return []*AnalyzeResult{}, nil
+	}
+	return []*AnalyzeResult{result}, nil
+}
+
+// compareProxyCondition evaluates a condition against a set of proxy settings. Supported conditions are:
+//
+//   - "proxy <operator> <configured|unconfigured>", configured when any of the settings sets a proxy
+//   - "missingNoProxy <operator> <n>", the number of CIDRs missing from the NO_PROXY of any of the settings
+//   - "inconsistent <operator> <true|false>", whether the settings that set a proxy differ
+func compareProxyCondition(settings []collect.ProxySettings, condition string, operator string, value string) (bool, error) {
+	switch condition {
+	case "proxy":
+		configured := false
+		for _, s := range settings {
+			configured = configured || s.IsConfigured()
+		}
+		return compareEquality(configuredOrUnconfigured(configured) == value, operator)
+	case "missingNoProxy":
+		threshold, err := strconv.Atoi(value)
+		if err != nil {
+			return false, errors.Wrapf(err, "failed to parse %q", value)
+		}
+		return compareFloat(float64(len(missingNoProxy(settings))), operator, float64(threshold))
+	case "inconsistent":
+		expected, err := strconv.ParseBool(value)
+		if err != nil {
+			return false, errors.Wrapf(err, "failed to parse %q", value)
+		}
+		return compareEquality(proxySettingsInconsistent(settings) == expected, operator)
+	}
+
+	return false, fmt.Errorf("unsupported proxy condition %q", condition)
+}
+
+// missingNoProxy returns the CIDRs missing from the NO_PROXY of any of the settings, sorted
+func missingNoProxy(settings []collect.ProxySettings) []string {
+	missing := map[string]struct{}{}
+	for _, s := range settings {
+		for _, cidr := range s.MissingNoProxy {
+			missing[cidr] = struct{}{}
+		}
+	}
+
+	cidrs := []string{}
+	for cidr := range missing {
+		cidrs = append(cidrs, cidr)
+	}
+	sort.Strings(cidrs)
+	return cidrs
+}
+
+// proxySettingsInconsistent returns true when the settings that set a proxy do not all set the
+// same proxies and NO_PROXY
+func proxySettingsInconsistent(settings []collect.ProxySettings) bool {
+	var first *collect.ProxySettings
+	for i := range settings {
+		s := settings[i]
+		if !s.IsConfigured() {
+			continue
+		}
+		if first == nil {
+			first = &s
+			continue
+		}
+		if s.HTTPProxy != first.HTTPProxy || s.HTTPSProxy != first.HTTPSProxy || s.NoProxy != first.NoProxy {
+			return true
+		}
+	}
+	return false
+}
+
+func configuredOrUnconfigured(configured bool) string {
+	if configured {
+		return "configured"
+	}
+	return "unconfigured"
+}
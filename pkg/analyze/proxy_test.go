@@ -0,0 +1,106 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"testing"
+
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/collect"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyzeProxy(t *testing.T) {
+	workloads := []collect.WorkloadProxySettings{
+		{
+			Namespace: "app",
+			Pod:       "api-0",
+			Container: "api",
+			ProxySettings: collect.ProxySettings{
+				HTTPSProxy:     "http://proxy:3128",
+				NoProxy:        "localhost,10.96.0.0/12",
+				MissingNoProxy: []string{"10.244.0.0/16"},
+			},
+		},
+		{
+			Namespace: "app",
+			Pod:       "api-0",
+			Container: "sidecar",
+		},
+		{
+			Namespace: "app",
+			Pod:       "worker-0",
+			Container: "worker",
+			ProxySettings: collect.ProxySettings{
+				HTTPSProxy:     "http://proxy:3128",
+				NoProxy:        "localhost",
+				MissingNoProxy: []string{"10.244.0.0/16", "10.96.0.0/12"},
+			},
+		},
+	}
+	collected, err := json.Marshal(workloads)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		outcomes []*troubleshootv1beta2.Outcome
+		want     *AnalyzeResult
+		wantErr  string
+	}{
+		{
+			name: "missing no proxy",
+			outcomes: []*troubleshootv1beta2.Outcome{
+				{Fail: &troubleshootv1beta2.SingleOutcome{When: "missingNoProxy > 0", Message: "NO_PROXY of {{ .MissingNoProxyContainers }} misses {{ .MissingNoProxy }}"}},
+				{Pass: &troubleshootv1beta2.SingleOutcome{Message: "NO_PROXY covers the cluster"}},
+			},
+			want: &AnalyzeResult{IsFail: true, Message: "NO_PROXY of app/api-0/api, app/worker-0/worker misses 10.244.0.0/16, 10.96.0.0/12"},
+		},
+		{
+			name: "inconsistent",
+			outcomes: []*troubleshootv1beta2.Outcome{
+				{Warn: &troubleshootv1beta2.SingleOutcome{When: "inconsistent == true", Message: "inconsistent"}},
+			},
+			want: &AnalyzeResult{IsWarn: true, Message: "inconsistent"},
+		},
+		{
+			name: "configured",
+			outcomes: []*troubleshootv1beta2.Outcome{
+				{Warn: &troubleshootv1beta2.SingleOutcome{When: "proxy == unconfigured", Message: "no proxy"}},
+				{Pass: &troubleshootv1beta2.SingleOutcome{When: "proxy == configured", Message: "proxy"}},
+			},
+			want: &AnalyzeResult{IsPass: true, Message: "proxy"},
+		},
+		{
+			name: "invalid condition",
+			outcomes: []*troubleshootv1beta2.Outcome{
+				{Pass: &troubleshootv1beta2.SingleOutcome{When: "unreachable > 0"}},
+			},
+			wantErr: `unsupported proxy condition "unreachable"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			getFile := func(filename string) ([]byte, error) {
+				require.Equal(t, "proxy/app.json", filename)
+				return collected, nil
+			}
+
+			a := AnalyzeProxy{analyzer: &troubleshootv1beta2.ProxyAnalyze{CollectorName: "app", Outcomes: tt.outcomes}}
+			results, err := a.Analyze(getFile, nil)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, results, 1)
+
+			assert.Equal(t, tt.want.IsPass, results[0].IsPass)
+			assert.Equal(t, tt.want.IsWarn, results[0].IsWarn)
+			assert.Equal(t, tt.want.IsFail, results[0].IsFail)
+			assert.Equal(t, "Proxy", results[0].Title)
+			assert.Equal(t, tt.want.Message, results[0].Message)
+		})
+	}
+}
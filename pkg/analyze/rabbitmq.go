@@ -0,0 +1,153 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/collect"
+)
+
+var defaultRabbitMQOutcomes = []*troubleshootv1beta2.Outcome{
+	{Fail: &troubleshootv1beta2.SingleOutcome{When: "connected == false", Message: "Cannot connect to the RabbitMQ management API: {{ .Error }}"}},
+	{Fail: &troubleshootv1beta2.SingleOutcome{When: "nodesDown > 0", Message: "RabbitMQ nodes are down: {{ .DownNodes }}"}},
+	{Fail: &troubleshootv1beta2.SingleOutcome{When: "partitions > 0", Message: "RabbitMQ nodes are partitioned from the cluster: {{ .PartitionedNodes }}"}},
+	{Warn: &troubleshootv1beta2.SingleOutcome{When: "alarms > 0", Message: "RabbitMQ nodes have raised memory or disk alarms and block publishers: {{ .AlarmedNodes }}"}},
+	{Warn: &troubleshootv1beta2.SingleOutcome{When: "queueDepth > 10000", Message: "RabbitMQ queue {{ .Queue }} of vhost {{ .VHost }} has {{ .QueueDepth }} messages"}},
+	{Pass: &troubleshootv1beta2.SingleOutcome{Message: "RabbitMQ nodes are running and queues are draining"}},
+}
+
+// rabbitMQTemplateData is passed to the messages of the outcomes. Nodes are listed by name,
+// separated by commas.
+type rabbitMQTemplateData struct {
+	Error            string
+	Version          string
+	Nodes            int
+	DownNodes        string
+	PartitionedNodes string
+	AlarmedNodes     string
+	// Queue is the queue with the most messages, in VHost, holding QueueDepth messages
+	Queue      string
+	VHost      string
+	QueueDepth int64
+
+	connected   bool
+	down        []string
+	partitioned []string
+	alarmed     []string
+}
+
+type AnalyzeRabbitMQ struct {
+	analyzer *troubleshootv1beta2.RabbitMQAnalyze
+}
+
+func (a *AnalyzeRabbitMQ) Title() string {
+	if a.analyzer.CheckName != "" {
+		return a.analyzer.CheckName
+	}
+
+	return "RabbitMQ"
+}
+
+func (a *AnalyzeRabbitMQ) IsExcluded() (bool, error) {
+	return isExcluded(a.analyzer.Exclude)
+}
+
+func (a *AnalyzeRabbitMQ) collectorName() string {
+	if a.analyzer.CollectorName != "" {
+		return a.analyzer.CollectorName
+	}
+	return "rabbitmq"
+}
+
+func (a *AnalyzeRabbitMQ) Analyze(getFile getCollectedFileContents, findFiles getChildCollectedFileContents) ([]*AnalyzeResult, error) {
+	fullPath := path.Join("rabbitmq", fmt.Sprintf("%s.json", a.collectorName()))
+	collected, err := getFile(fullPath)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read collected file name: %s", fullPath)
+	}
+
+	cluster := collect.RabbitMQCluster{}
+	if err := json.Unmarshal(collected, &cluster); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal rabbitmq cluster")
+	}
+
+	data := &rabbitMQTemplateData{
+		Error:     cluster.Error,
+		Version:   cluster.Version,
+		Nodes:     len(cluster.Nodes),
+		connected: cluster.IsConnected,
+	}
+	for _, node := range cluster.Nodes {
+		if !node.Running {
+			data.down = append(data.down, node.Name)
+		}
+		if len(node.Partitions) > 0 {
+			data.partitioned = append(data.partitioned, node.Name)
+		}
+		if node.MemoryAlarm || node.DiskFreeAlarm {
+			data.alarmed = append(data.alarmed, node.Name)
+		}
+	}
+	data.DownNodes = strings.Join(data.down, ", ")
+	data.PartitionedNodes = strings.Join(data.partitioned, ", ")
+	data.AlarmedNodes = strings.Join(data.alarmed, ", ")
+
+	for _, queue := range cluster.Queues {
+		if data.Queue == "" || queue.Messages > data.QueueDepth {
+			data.Queue = queue.Name
+			data.VHost = queue.VHost
+			data.QueueDepth = queue.Messages
+		}
+	}
+
+	outcomes := a.analyzer.Outcomes
+	if len(outcomes) == 0 {
+		outcomes = defaultRabbitMQOutcomes
+	}
+
+	result, err := analyzeTemplatedOutcomes(a.Title(), a.analyzer.Strict.BoolOrDefaultFalse(), outcomes, data, func(when string) (bool, error) {
+		return compareRabbitMQConditionalToActual(when, data)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		return []*AnalyzeResult{}, nil
+	}
+	return []*AnalyzeResult{result}, nil
+}
+
+// compareRabbitMQConditionalToActual evaluates a when clause against a RabbitMQ cluster. Supported
+// conditions are "connected == <true|false>", and nodes, nodesDown, partitions, the number of nodes
+// that cannot reach others, alarms, the number of nodes with a memory or disk alarm, and
+// queueDepth, the messages of the deepest queue, compared to a number, e.g. "queueDepth > 1000".
+func compareRabbitMQConditionalToActual(conditional string, data *rabbitMQTemplateData) (bool, error) {
+	parts := strings.Fields(conditional)
+	if len(parts) != 3 {
+		return false, errors.Errorf("unable to parse conditional %q", conditional)
+	}
+
+	var actual int
+	switch parts[0] {
+	case "connected":
+		return compareBoolConditional(parts[1], parts[2], data.connected)
+	case "nodes":
+		actual = data.Nodes
+	case "nodesDown":
+		actual = len(data.down)
+	case "partitions":
+		actual = len(data.partitioned)
+	case "alarms":
+		actual = len(data.alarmed)
+	case "queueDepth":
+		actual = int(data.QueueDepth)
+	default:
+		return false, errors.Errorf("unknown condition %q, must be one of connected, nodes, nodesDown, partitions, alarms or queueDepth", parts[0])
+	}
+
+	return compareActualToWhen(parts[1]+" "+parts[2], actual)
+}
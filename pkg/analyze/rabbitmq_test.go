@@ -0,0 +1,102 @@
+package analyzer
+
+import (
+	"testing"
+
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyzeRabbitMQ(t *testing.T) {
+	tests := []struct {
+		name     string
+		analyzer *troubleshootv1beta2.RabbitMQAnalyze
+		cluster  string
+		want     *AnalyzeResult
+		wantErr  bool
+	}{
+		{
+			name:     "not connected",
+			analyzer: &troubleshootv1beta2.RabbitMQAnalyze{},
+			cluster:  `{"isConnected": false, "error": "failed to get overview: 401 Unauthorized"}`,
+			want:     &AnalyzeResult{IsFail: true, Message: "Cannot connect to the RabbitMQ management API: failed to get overview: 401 Unauthorized"},
+		},
+		{
+			name:     "node down",
+			analyzer: &troubleshootv1beta2.RabbitMQAnalyze{},
+			cluster: `{"isConnected": true, "nodes": [
+			  {"name": "rabbit@rabbitmq-0", "running": true, "partitions": ["rabbit@rabbitmq-1"]},
+			  {"name": "rabbit@rabbitmq-1", "running": false}
+			]}`,
+			want: &AnalyzeResult{IsFail: true, Message: "RabbitMQ nodes are down: rabbit@rabbitmq-1"},
+		},
+		{
+			name:     "alarm",
+			analyzer: &troubleshootv1beta2.RabbitMQAnalyze{},
+			cluster:  `{"isConnected": true, "nodes": [{"name": "rabbit@rabbitmq-0", "running": true, "diskFreeAlarm": true}]}`,
+			want:     &AnalyzeResult{IsWarn: true, Message: "RabbitMQ nodes have raised memory or disk alarms and block publishers: rabbit@rabbitmq-0"},
+		},
+		{
+			name:     "deep queue",
+			analyzer: &troubleshootv1beta2.RabbitMQAnalyze{},
+			cluster: `{"isConnected": true, "nodes": [{"name": "rabbit@rabbitmq-0", "running": true}], "queues": [
+			  {"name": "emails", "vhost": "/", "messages": 10},
+			  {"name": "orders", "vhost": "shop", "messages": 12000}
+			]}`,
+			want: &AnalyzeResult{IsWarn: true, Message: "RabbitMQ queue orders of vhost shop has 12000 messages"},
+		},
+		{
+			name: "custom outcomes",
+			analyzer: &troubleshootv1beta2.RabbitMQAnalyze{
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{Warn: &troubleshootv1beta2.SingleOutcome{When: "queueDepth > 5", Message: "{{ .Queue }} is backing up"}},
+					{Pass: &troubleshootv1beta2.SingleOutcome{Message: "ok"}},
+				},
+			},
+			cluster: `{"isConnected": true, "queues": [{"name": "emails", "vhost": "/", "messages": 10}]}`,
+			want:    &AnalyzeResult{IsWarn: true, Message: "emails is backing up"},
+		},
+		{
+			name:     "healthy",
+			analyzer: &troubleshootv1beta2.RabbitMQAnalyze{},
+			cluster:  `{"isConnected": true, "nodes": [{"name": "rabbit@rabbitmq-0", "running": true}]}`,
+			want:     &AnalyzeResult{IsPass: true, Message: "RabbitMQ nodes are running and queues are draining"},
+		},
+		{
+			name: "unknown condition",
+			analyzer: &troubleshootv1beta2.RabbitMQAnalyze{
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{Fail: &troubleshootv1beta2.SingleOutcome{When: "consumers == 0"}},
+				},
+			},
+			cluster: `{"isConnected": true}`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := require.New(t)
+
+			getFile := func(path string) ([]byte, error) {
+				req.Equal("rabbitmq/rabbitmq.json", path)
+				return []byte(tt.cluster), nil
+			}
+
+			a := AnalyzeRabbitMQ{analyzer: tt.analyzer}
+			results, err := a.Analyze(getFile, nil)
+			if tt.wantErr {
+				req.Error(err)
+				return
+			}
+			req.NoError(err)
+			req.Len(results, 1)
+
+			assert.Equal(t, tt.want.IsPass, results[0].IsPass)
+			assert.Equal(t, tt.want.IsWarn, results[0].IsWarn)
+			assert.Equal(t, tt.want.IsFail, results[0].IsFail)
+			assert.Equal(t, tt.want.Message, results[0].Message)
+		})
+	}
+}
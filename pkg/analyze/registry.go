@@ -85,6 +85,7 @@ func (a *AnalyzeRegistryImages) analyzeRegistry(analyzer *troubleshootv1beta2.Re
 				result.IsFail = true
 				result.Message = outcome.Fail.Message
 				result.URI = outcome.Fail.URI
+				result.Remediation = outcome.Fail.Remediation
 
 				return result, nil
 			}
@@ -98,6 +99,7 @@ func (a *AnalyzeRegistryImages) analyzeRegistry(analyzer *troubleshootv1beta2.Re
 				result.IsFail = true
 				result.Message = outcome.Fail.Message
 				result.URI = outcome.Fail.URI
+				result.Remediation = outcome.Fail.Remediation
 
 				return result, nil
 			}
@@ -106,6 +108,7 @@ func (a *AnalyzeRegistryImages) analyzeRegistry(analyzer *troubleshootv1beta2.Re
 				result.IsWarn = true
 				result.Message = outcome.Warn.Message
 				result.URI = outcome.Warn.URI
+				result.Remediation = outcome.Warn.Remediation
 
 				return result, nil
 			}
@@ -119,6 +122,7 @@ func (a *AnalyzeRegistryImages) analyzeRegistry(analyzer *troubleshootv1beta2.Re
 				result.IsWarn = true
 				result.Message = outcome.Warn.Message
 				result.URI = outcome.Warn.URI
+				result.Remediation = outcome.Warn.Remediation
 
 				return result, nil
 			}
@@ -127,6 +131,7 @@ func (a *AnalyzeRegistryImages) analyzeRegistry(analyzer *troubleshootv1beta2.Re
 				result.IsPass = true
 				result.Message = outcome.Pass.Message
 				result.URI = outcome.Pass.URI
+				result.Remediation = outcome.Pass.Remediation
 
 				return result, nil
 			}
@@ -140,6 +145,7 @@ func (a *AnalyzeRegistryImages) analyzeRegistry(analyzer *troubleshootv1beta2.Re
 				result.IsPass = true
 				result.Message = outcome.Pass.Message
 				result.URI = outcome.Pass.URI
+				result.Remediation = outcome.Pass.Remediation
 
 				return result, nil
 			}
@@ -0,0 +1,251 @@
+package analyzer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/open-policy-agent/opa/v1/ast"
+	"github.com/open-policy-agent/opa/v1/rego"
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+)
+
+const (
+	// regoDenyRule and regoWarnRule are the rules of the package of a policy whose messages are its
+	// violations
+	regoDenyRule = "deny"
+	regoWarnRule = "warn"
+)
+
+// RegoDefaultOutcomes are evaluated when the analyzer sets no outcomes
+var RegoDefaultOutcomes = []*troubleshootv1beta2.Outcome{
+	{
+		Fail: &troubleshootv1beta2.SingleOutcome{
+			When:    "denials > 0",
+			Message: "The policy denied {{ .Denials }}",
+		},
+	},
+	{
+		Warn: &troubleshootv1beta2.SingleOutcome{
+			When:    "warnings > 0",
+			Message: "The policy warned about {{ .Warnings }}",
+		},
+	},
+	{
+		Pass: &troubleshootv1beta2.SingleOutcome{
+			Message: "The {{ .Files }} files comply with the policy",
+		},
+	},
+}
+
+// regoTemplateData is passed to the messages of the outcomes
+type regoTemplateData struct {
+	// Files is the number of files the policy was evaluated against
+	Files int
+	// Denials and Warnings are the messages of the deny and warn rules, prefixed with the files
+	// they were raised for and separated by semicolons
+	Denials  string
+	Warnings string
+
+	report *regoReport
+}
+
+// regoReport is the violations of a policy raised for the files of the bundle
+type regoReport struct {
+	files    int
+	denials  []string
+	warnings []string
+}
+
+// AnalyzeRego evaluates a Rego policy with OPA. Policies cannot reach the network, as no hosts
+// are allowed to the http.send and net builtins.
+type AnalyzeRego struct {
+	analyzer *troubleshootv1beta2.RegoAnalyze
+}
+
+func (a *AnalyzeRego) Title() string {
+	if a.analyzer.CheckName != "" {
+		return a.analyzer.CheckName
+	}
+	if a.analyzer.CollectorName != "" {
+		return a.analyzer.CollectorName
+	}
+	return "Rego Policy"
+}
+
+func (a *AnalyzeRego) IsExcluded() (bool, error) {
+	return isExcluded(a.analyzer.Exclude)
+}
+
+func (a *AnalyzeRego) Analyze(getFile getCollectedFileContents, findFiles getChildCollectedFileContents) ([]*AnalyzeResult, error) {
+	ctx := context.TODO()
+
+	query, err := prepareRegoPolicy(ctx, a.analyzer.Policy)
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := findBundleFiles(findFiles, filepath.Join(a.analyzer.CollectorName, a.analyzer.FileName))
+	if err != nil {
+		return nil, err
+	}
+
+	report, err := evaluateRegoPolicy(ctx, query, files)
+	if err != nil {
+		return nil, err
+	}
+	data := newRegoTemplateData(report)
+
+	outcomes := a.analyzer.Outcomes
+	if len(outcomes) == 0 {
+		outcomes = RegoDefaultOutcomes
+	}
+
+	result, err := analyzeTemplatedOutcomes(a.Title(), a.analyzer.Strict.BoolOrDefaultFalse(), outcomes, data, func(when string) (bool, error) {
+		return compareRego(data.report, when)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		return []*AnalyzeResult{}, nil
+	}
+	return []*AnalyzeResult{result}, nil
+}
+
+// prepareRegoPolicy parses the policy and prepares the query of its package, whose value is an
+// object of the rules of the package
+func prepareRegoPolicy(ctx context.Context, policy string) (rego.PreparedEvalQuery, error) {
+	if strings.TrimSpace(policy) == "" {
+		return rego.PreparedEvalQuery{}, errors.New("policy is required")
+	}
+
+	module, err := ast.ParseModule("policy.rego", policy)
+	if err != nil {
+		return rego.PreparedEvalQuery{}, errors.Wrap(err, "failed to parse policy")
+	}
+
+	capabilities := ast.CapabilitiesForThisVersion()
+	capabilities.AllowNet = []string{}
+
+	query, err := rego.New(
+		rego.Query(module.Package.Path.String()),
+		rego.ParsedModule(module),
+		rego.Capabilities(capabilities),
+		rego.StrictBuiltinErrors(true),
+	).PrepareForEval(ctx)
+	if err != nil {
+		return rego.PreparedEvalQuery{}, errors.Wrap(err, "failed to compile policy")
+	}
+	return query, nil
+}
+
+// evaluateRegoPolicy evaluates the policy against each of the files, by path
+func evaluateRegoPolicy(ctx context.Context, query rego.PreparedEvalQuery, files map[string][]byte) (*regoReport, error) {
+	paths := make([]string, 0, len(files))
+	for path := range files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	report := &regoReport{}
+	for _, path := range paths {
+		var input interface{}
+		if err := json.Unmarshal(files[path], &input); err != nil {
+			return nil, errors.Wrapf(err, "failed to parse %s", path)
+		}
+
+		resultSet, err := query.Eval(ctx, rego.EvalInput(input))
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to evaluate policy against %s", path)
+		}
+		report.files++
+
+		if len(resultSet) == 0 || len(resultSet[0].Expressions) == 0 {
+			continue
+		}
+		rules, ok := resultSet[0].Expressions[0].Value.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for _, msg := range regoViolations(rules[regoDenyRule]) {
+			report.denials = append(report.denials, fmt.Sprintf("%s: %s", path, msg))
+		}
+		for _, msg := range regoViolations(rules[regoWarnRule]) {
+			report.warnings = append(report.warnings, fmt.Sprintf("%s: %s", path, msg))
+		}
+	}
+
+	return report, nil
+}
+
+// regoViolations returns the messages of the value of a deny or warn rule. The rule is either a
+// set or a single value, of strings or of objects with a msg, as the violations of Gatekeeper
+// policies. Other values are reported as JSON.
+func regoViolations(value interface{}) []string {
+	if value == nil {
+		return nil
+	}
+
+	values, ok := value.([]interface{})
+	if !ok {
+		values = []interface{}{value}
+	}
+
+	messages := []string{}
+	for _, v := range values {
+		switch violation := v.(type) {
+		case bool:
+			// a rule that is true without a message
+			if violation {
+				messages = append(messages, "violation")
+			}
+			continue
+		case string:
+			messages = append(messages, violation)
+			continue
+		case map[string]interface{}:
+			if msg, ok := violation["msg"].(string); ok {
+				messages = append(messages, msg)
+				continue
+			}
+		}
+		b, _ := json.Marshal(v)
+		messages = append(messages, string(b))
+	}
+	sort.Strings(messages)
+	return messages
+}
+
+func newRegoTemplateData(report *regoReport) *regoTemplateData {
+	return &regoTemplateData{
+		Files:    report.files,
+		Denials:  strings.Join(report.denials, "; "),
+		Warnings: strings.Join(report.warnings, "; "),
+		report:   report,
+	}
+}
+
+// compareRego evaluates a when clause against the report. Supported conditions are the counts
+// files, denials and warnings, e.g. "denials > 0".
+func compareRego(report *regoReport, when string) (bool, error) {
+	parts := strings.Fields(when)
+	if len(parts) != 3 {
+		return false, fmt.Errorf("expected 3 parts in when %q, got %d", when, len(parts))
+	}
+	key, condition := parts[0], parts[1]+" "+parts[2]
+
+	switch key {
+	case "files":
+		return compareActualToWhen(condition, report.files)
+	case "denials":
+		return compareActualToWhen(condition, len(report.denials))
+	case "warnings":
+		return compareActualToWhen(condition, len(report.warnings))
+	}
+	return false, fmt.Errorf("unsupported condition %q, must be one of files, denials or warnings", key)
+}
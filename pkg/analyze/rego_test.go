@@ -0,0 +1,139 @@
+package analyzer
+
+import (
+	"testing"
+
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyzeRego(t *testing.T) {
+	files := map[string][]byte{
+		"registry/images-facts.json": []byte(`{
+  "images": [
+    {"image": "nginx:1.27", "runsAsRoot": true},
+    {"image": "app:1.0.0", "user": "1000", "runsAsRoot": false, "labels": {"maintainer": "ops"}}
+  ]
+}`),
+		"registry/sidecars-facts.json": []byte(`{
+  "images": [
+    {"image": "envoy:1.32", "user": "101", "runsAsRoot": false}
+  ]
+}`),
+	}
+
+	policy := `package troubleshoot.images
+
+deny contains msg if {
+	some image in input.images
+	image.runsAsRoot
+	msg := sprintf("%s runs as root", [image.image])
+}
+
+warn contains {"msg": msg} if {
+	some image in input.images
+	not image.labels.maintainer
+	msg := sprintf("%s has no maintainer", [image.image])
+}
+`
+
+	tests := []struct {
+		name     string
+		analyzer *troubleshootv1beta2.RegoAnalyze
+		want     *AnalyzeResult
+		wantErr  string
+	}{
+		{
+			name:     "default outcomes",
+			analyzer: &troubleshootv1beta2.RegoAnalyze{Policy: policy},
+			want: &AnalyzeResult{
+				IsFail:  true,
+				Message: "The policy denied registry/images-facts.json: nginx:1.27 runs as root",
+			},
+		},
+		{
+			name: "warnings",
+			analyzer: &troubleshootv1beta2.RegoAnalyze{Policy: policy, Outcomes: []*troubleshootv1beta2.Outcome{
+				{Warn: &troubleshootv1beta2.SingleOutcome{When: "warnings > 1", Message: "{{ .Warnings }}"}},
+				{Pass: &troubleshootv1beta2.SingleOutcome{Message: "{{ .Files }} files"}},
+			}},
+			want: &AnalyzeResult{
+				IsWarn:  true,
+				Message: "registry/images-facts.json: nginx:1.27 has no maintainer; registry/sidecars-facts.json: envoy:1.32 has no maintainer",
+			},
+		},
+		{
+			name: "no violations",
+			analyzer: &troubleshootv1beta2.RegoAnalyze{Policy: `package troubleshoot.images
+
+deny contains msg if {
+	some image in input.images
+	image.user == "0"
+	msg := image.image
+}
+`},
+			want: &AnalyzeResult{IsPass: true, Message: "The 2 files comply with the policy"},
+		},
+		{
+			name:     "invalid policy",
+			analyzer: &troubleshootv1beta2.RegoAnalyze{Policy: "package troubleshoot\n\ndeny[msg] {"},
+			wantErr:  "failed to parse policy",
+		},
+		{
+			name:     "no policy",
+			analyzer: &troubleshootv1beta2.RegoAnalyze{},
+			wantErr:  "policy is required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.analyzer.CollectorName = "registry"
+			tt.analyzer.FileName = "*-facts.json"
+			findFiles := func(pattern string, _ []string) (map[string][]byte, error) {
+				require.Equal(t, "registry/*-facts.json", pattern)
+				return files, nil
+			}
+
+			a := AnalyzeRego{analyzer: tt.analyzer}
+			results, err := a.Analyze(nil, findFiles)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, results, 1)
+
+			assert.Equal(t, tt.want.IsPass, results[0].IsPass)
+			assert.Equal(t, tt.want.IsWarn, results[0].IsWarn)
+			assert.Equal(t, tt.want.IsFail, results[0].IsFail)
+			assert.Equal(t, "registry", results[0].Title)
+			assert.Equal(t, tt.want.Message, results[0].Message)
+		})
+	}
+}
+
+func TestRegoViolations(t *testing.T) {
+	tests := []struct {
+		name  string
+		value interface{}
+		want  []string
+	}{
+		{name: "undefined", value: nil, want: nil},
+		{name: "empty set", value: []interface{}{}, want: []string{}},
+		{name: "messages", value: []interface{}{"b", "a"}, want: []string{"a", "b"}},
+		{name: "objects", value: []interface{}{map[string]interface{}{"msg": "denied", "details": "x"}}, want: []string{"denied"}},
+		{name: "objects without msg", value: []interface{}{map[string]interface{}{"reason": "denied"}}, want: []string{`{"reason":"denied"}`}},
+		{name: "true", value: true, want: []string{"violation"}},
+		{name: "false", value: false, want: []string{}},
+		{name: "single message", value: "denied", want: []string{"denied"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, regoViolations(tt.value))
+		})
+	}
+}
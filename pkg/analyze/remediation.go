@@ -0,0 +1,31 @@
+package analyzer
+
+import (
+	"fmt"
+
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+)
+
+// RemediationLines returns the human readable lines describing a remediation, in the order they
+// should be shown to the user. Empty fields are skipped and a nil remediation returns no lines.
+func RemediationLines(remediation *troubleshootv1beta2.Remediation) []string {
+	if remediation == nil {
+		return nil
+	}
+
+	lines := []string{}
+	if remediation.Command != "" {
+		lines = append(lines, fmt.Sprintf("To fix, run: %s", remediation.Command))
+	}
+	if remediation.DocLink != "" {
+		lines = append(lines, fmt.Sprintf("Documentation: %s", remediation.DocLink))
+	}
+	if remediation.Severity != "" {
+		lines = append(lines, fmt.Sprintf("Severity: %s", remediation.Severity))
+	}
+	if remediation.Automation != "" {
+		lines = append(lines, fmt.Sprintf("Automation: %s", remediation.Automation))
+	}
+
+	return lines
+}
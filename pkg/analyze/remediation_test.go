@@ -0,0 +1,29 @@
+package analyzer
+
+import (
+	"testing"
+
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRemediationLines(t *testing.T) {
+	assert.Nil(t, RemediationLines(nil))
+	assert.Empty(t, RemediationLines(&troubleshootv1beta2.Remediation{}))
+
+	assert.Equal(t, []string{
+		"To fix, run: sudo swapoff -a",
+		"Documentation: https://kubernetes.io/docs/concepts/architecture/nodes/#swap-memory",
+		"Severity: high",
+		"Automation: approval",
+	}, RemediationLines(&troubleshootv1beta2.Remediation{
+		Command:    "sudo swapoff -a",
+		DocLink:    "https://kubernetes.io/docs/concepts/architecture/nodes/#swap-memory",
+		Severity:   troubleshootv1beta2.RemediationSeverityHigh,
+		Automation: troubleshootv1beta2.RemediationAutomationApproval,
+	}))
+
+	assert.Equal(t, []string{"Documentation: https://troubleshoot.sh"}, RemediationLines(&troubleshootv1beta2.Remediation{
+		DocLink: "https://troubleshoot.sh",
+	}))
+}
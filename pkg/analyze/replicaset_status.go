@@ -96,6 +96,9 @@ func analyzeOneReplicaSetStatus(analyzer *troubleshootv1beta2.ReplicaSetStatus,
 			if err != nil {
 				return nil, errors.Wrap(err, "failed to process status")
 			}
+			if result, err = renderResultMessage(result, newReplicasetTemplateData(replicaset)); err != nil {
+				return nil, err
+			}
 		} else {
 			result = getDefaultReplicaSetResult(replicaset)
 		}
@@ -151,6 +154,9 @@ func analyzeAllReplicaSetStatuses(analyzer *troubleshootv1beta2.ReplicaSetStatus
 					if err != nil {
 						return nil, errors.Wrap(err, "failed to process status")
 					}
+					if result, err = renderResultMessage(result, newReplicasetTemplateData(&replicaset)); err != nil {
+						return nil, err
+					}
 				} else {
 					result = getDefaultReplicaSetResult(&replicaset)
 				}
@@ -179,6 +185,7 @@ func replicasetStatus(outcomes []*troubleshootv1beta2.Outcome, replicaset *appsv
 				result.IsFail = true
 				result.Message = outcome.Fail.Message
 				result.URI = outcome.Fail.URI
+				result.Remediation = outcome.Fail.Remediation
 
 				return result, nil
 			}
@@ -192,6 +199,7 @@ func replicasetStatus(outcomes []*troubleshootv1beta2.Outcome, replicaset *appsv
 				result.IsFail = true
 				result.Message = outcome.Fail.Message
 				result.URI = outcome.Fail.URI
+				result.Remediation = outcome.Fail.Remediation
 
 				return result, nil
 			}
@@ -200,6 +208,7 @@ func replicasetStatus(outcomes []*troubleshootv1beta2.Outcome, replicaset *appsv
 				result.IsWarn = true
 				result.Message = outcome.Warn.Message
 				result.URI = outcome.Warn.URI
+				result.Remediation = outcome.Warn.Remediation
 
 				return result, nil
 			}
@@ -213,6 +222,7 @@ func replicasetStatus(outcomes []*troubleshootv1beta2.Outcome, replicaset *appsv
 				result.IsWarn = true
 				result.Message = outcome.Warn.Message
 				result.URI = outcome.Warn.URI
+				result.Remediation = outcome.Warn.Remediation
 
 				return result, nil
 			}
@@ -221,6 +231,7 @@ func replicasetStatus(outcomes []*troubleshootv1beta2.Outcome, replicaset *appsv
 				result.IsPass = true
 				result.Message = outcome.Pass.Message
 				result.URI = outcome.Pass.URI
+				result.Remediation = outcome.Pass.Remediation
 
 				return result, nil
 			}
@@ -234,6 +245,7 @@ func replicasetStatus(outcomes []*troubleshootv1beta2.Outcome, replicaset *appsv
 				result.IsPass = true
 				result.Message = outcome.Pass.Message
 				result.URI = outcome.Pass.URI
+				result.Remediation = outcome.Pass.Remediation
 
 				return result, nil
 			}
@@ -243,6 +255,20 @@ func replicasetStatus(outcomes []*troubleshootv1beta2.Outcome, replicaset *appsv
 	return result, nil
 }
 
+func newReplicasetTemplateData(replicaset *appsv1.ReplicaSet) *workloadStatusTemplateData {
+	data := &workloadStatusTemplateData{
+		Namespace:         replicaset.Namespace,
+		Name:              replicaset.Name,
+		Replicas:          1, // default is 1
+		ReadyReplicas:     int(replicaset.Status.ReadyReplicas),
+		AvailableReplicas: int(replicaset.Status.AvailableReplicas),
+	}
+	if replicaset.Spec.Replicas != nil {
+		data.Replicas = int(*replicaset.Spec.Replicas)
+	}
+	return data
+}
+
 func getDefaultReplicaSetResult(replicaset *appsv1.ReplicaSet) *AnalyzeResult {
 	if replicaset.Spec.Replicas == nil && replicaset.Status.AvailableReplicas == 1 { // default is 1
 		return nil
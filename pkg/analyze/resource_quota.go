@@ -0,0 +1,340 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/troubleshoot/internal/util"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/constants"
+	corev1 "k8s.io/api/core/v1"
+)
+
+var defaultResourceQuotaOutcomes = []*troubleshootv1beta2.Outcome{
+	{Fail: &troubleshootv1beta2.SingleOutcome{When: "rejectedPods > 0", Message: "Pods in namespace {{ .Namespace }} were rejected for exceeding quota: {{ .RejectionMessage }}"}},
+	{Fail: &troubleshootv1beta2.SingleOutcome{When: "usage >= 100%", Message: "Namespace {{ .Namespace }} has used all of its {{ .Resource }} quota ({{ .Used }} of {{ .Hard }})"}},
+	{Warn: &troubleshootv1beta2.SingleOutcome{When: "usage >= 80%", Message: "Namespace {{ .Namespace }} has used {{ .Usage }} of its {{ .Resource }} quota ({{ .Used }} of {{ .Hard }})"}},
+	{Warn: &troubleshootv1beta2.SingleOutcome{When: "unboundedContainers > 0", Message: "Containers in namespace {{ .Namespace }} have no resource requests or limits: {{ .UnboundedContainers }}"}},
+	{Pass: &troubleshootv1beta2.SingleOutcome{Message: "Namespace {{ .Namespace }} is within its quotas and limit ranges"}},
+}
+
+// resourceQuotaTemplateData is passed to the messages of the outcomes. Resource, Used, Hard and
+// Usage are those of the quota resource with the highest usage in the namespace.
+type resourceQuotaTemplateData struct {
+	Namespace           string
+	Quota               string
+	Resource            string
+	Used                string
+	Hard                string
+	Usage               string
+	RejectedPods        int
+	RejectionMessage    string
+	UnboundedContainers string
+
+	usage     float64
+	unbounded int
+	hasLimits bool
+	hasQuota  bool
+}
+
+type AnalyzeResourceQuota struct {
+	analyzer *troubleshootv1beta2.ResourceQuotaAnalyze
+}
+
+func (a *AnalyzeResourceQuota) Title() string {
+	if a.analyzer.CheckName != "" {
+		return a.analyzer.CheckName
+	}
+
+	return "Resource Quota {{ .Namespace }}"
+}
+
+func (a *AnalyzeResourceQuota) IsExcluded() (bool, error) {
+	return isExcluded(a.analyzer.Exclude)
+}
+
+func (a *AnalyzeResourceQuota) Analyze(getFile getCollectedFileContents, findFiles getChildCollectedFileContents) ([]*AnalyzeResult, error) {
+	namespaces := map[string]*resourceQuotaTemplateData{}
+	namespaceData := func(namespace string) *resourceQuotaTemplateData {
+		if namespaces[namespace] == nil {
+			namespaces[namespace] = &resourceQuotaTemplateData{Namespace: namespace}
+		}
+		return namespaces[namespace]
+	}
+
+	quotaFiles, err := findFiles(filepath.Join(constants.CLUSTER_RESOURCES_DIR, constants.CLUSTER_RESOURCES_RESOURCE_QUOTA, "*.json"), nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read collected resource quotas")
+	}
+	for name, content := range quotaFiles {
+		quotas, err := decodeCollectedItems[corev1.ResourceQuota](content)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to unmarshal %s", name)
+		}
+		for _, quota := range quotas {
+			setResourceQuotaUsage(namespaceData(quota.Namespace), quota)
+		}
+	}
+
+	limitRangeFiles, err := findFiles(filepath.Join(constants.CLUSTER_RESOURCES_DIR, constants.CLUSTER_RESOURCES_LIMITRANGES, "*.json"), nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read collected limit ranges")
+	}
+	for name, content := range limitRangeFiles {
+		limitRanges, err := decodeCollectedItems[corev1.LimitRange](content)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to unmarshal %s", name)
+		}
+		for _, limitRange := range limitRanges {
+			namespaceData(limitRange.Namespace).hasLimits = true
+		}
+	}
+
+	names := []string{}
+	for namespace := range namespaces {
+		if len(a.analyzer.Namespaces) == 0 || slices.Contains(a.analyzer.Namespaces, namespace) {
+			names = append(names, namespace)
+		}
+	}
+	sort.Strings(names)
+
+	outcomes := a.analyzer.Outcomes
+	if len(outcomes) == 0 {
+		outcomes = defaultResourceQuotaOutcomes
+	}
+
+	results := []*AnalyzeResult{}
+	for _, namespace := range names {
+		data := namespaces[namespace]
+		if err := setResourceQuotaRejections(data, getFile); err != nil {
+			return nil, err
+		}
+		if data.hasLimits {
+			if err := setUnboundedContainers(data, getFile); err != nil {
+				return nil, err
+			}
+		}
+
+		result, err := a.analyzeNamespace(data, outcomes)
+		if err != nil {
+			return nil, err
+		}
+		if result != nil {
+			results = append(results, result)
+		}
+	}
+
+	return results, nil
+}
+
+// analyzeNamespace returns the result of the first outcome whose condition matches the
+// namespace, or nil when none does
+func (a *AnalyzeResourceQuota) analyzeNamespace(data *resourceQuotaTemplateData, outcomes []*troubleshootv1beta2.Outcome) (*AnalyzeResult, error) {
+	for _, outcome := range outcomes {
+		result := &AnalyzeResult{
+			IconKey: "kubernetes",
+			Strict:  a.analyzer.Strict.BoolOrDefaultFalse(),
+		}
+
+		var singleOutcome *troubleshootv1beta2.SingleOutcome
+		switch {
+		case outcome.Fail != nil:
+			singleOutcome = outcome.Fail
+			result.IsFail = true
+		case outcome.Warn != nil:
+			singleOutcome = outcome.Warn
+			result.IsWarn = true
+		case outcome.Pass != nil:
+			singleOutcome = outcome.Pass
+			result.IsPass = true
+		default:
+			continue
+		}
+
+		if singleOutcome.When != "" {
+			match, err := compareResourceQuota(data, singleOutcome.When)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to evaluate when %q", singleOutcome.When)
+			}
+			if !match {
+				continue
+			}
+		}
+
+		var err error
+		result.Title, err = util.RenderTemplate(a.Title(), data)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to render title template")
+		}
+		result.Message, err = util.RenderTemplate(singleOutcome.Message, data)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to render message template")
+		}
+		result.URI = singleOutcome.URI
+		result.Remediation = singleOutcome.Remediation
+		result.Condition = singleOutcome.When
+		result.InvolvedObject = &corev1.ObjectReference{
+			APIVersion: "v1",
+			Kind:       "Namespace",
+			Name:       data.Namespace,
+		}
+		return result, nil
+	}
+
+	return nil, nil
+}
+
+// compareResourceQuota evaluates a when clause against a namespace. Supported conditions are:
+//
+//   - "usage <operator> <n>%", the highest ratio of used to hard of the resources of the quotas
+//     of the namespace, e.g. "usage >= 90%". It is false in namespaces without quotas.
+//   - "rejectedPods <operator> <n>", the number of times pods were not created because they
+//     would exceed a quota
+//   - "unboundedContainers <operator> <n>", the number of containers without requests or without
+//     limits, only counted in namespaces with a LimitRange
+func compareResourceQuota(data *resourceQuotaTemplateData, when string) (bool, error) {
+	parts := strings.Fields(when)
+	if len(parts) != 3 {
+		return false, fmt.Errorf("expected 3 parts in when %q, got %d", when, len(parts))
+	}
+	key, opString, expected := parts[0], parts[1], parts[2]
+
+	switch key {
+	case "usage":
+		if !data.hasQuota {
+			return false, nil
+		}
+		threshold, err := strconv.ParseFloat(strings.TrimSuffix(expected, "%"), 64)
+		if err != nil {
+			return false, errors.Wrapf(err, "failed to parse %q", expected)
+		}
+		return compareFloat(data.usage*100, opString, threshold)
+	case "rejectedPods":
+		return compareActualToWhen(opString+" "+expected, data.RejectedPods)
+	case "unboundedContainers":
+		return compareActualToWhen(opString+" "+expected, data.unbounded)
+	}
+
+	return false, fmt.Errorf("unsupported condition %q, must be one of usage, rejectedPods or unboundedContainers", key)
+}
+
+// setResourceQuotaUsage records the resource of the quota with the highest usage, if higher than
+// that of the other quotas of the namespace
+func setResourceQuotaUsage(data *resourceQuotaTemplateData, quota corev1.ResourceQuota) {
+	data.hasQuota = true
+
+	resourceNames := []string{}
+	for name := range quota.Status.Hard {
+		resourceNames = append(resourceNames, string(name))
+	}
+	sort.Strings(resourceNames)
+
+	for _, name := range resourceNames {
+		hard := quota.Status.Hard[corev1.ResourceName(name)]
+		used := quota.Status.Used[corev1.ResourceName(name)]
+
+		var usage float64
+		switch {
+		case !hard.IsZero():
+			usage = used.AsApproximateFloat64() / hard.AsApproximateFloat64()
+		case !used.IsZero():
+			usage = 1
+		default:
+			// a zero quota forbids the resource, it is not a resource the namespace runs out of
+			continue
+		}
+
+		if usage > data.usage || data.Resource == "" {
+			data.usage = usage
+			data.Quota = quota.Name
+			data.Resource = name
+			data.Used = used.String()
+			data.Hard = hard.String()
+			data.Usage = fmt.Sprintf("%.0f%%", usage*100)
+		}
+	}
+}
+
+// setResourceQuotaRejections counts the events of controllers that failed to create pods because
+// of a quota, keeping the message of the most recent one
+func setResourceQuotaRejections(data *resourceQuotaTemplateData, getFile getCollectedFileContents) error {
+	content, err := getFile(path.Join(constants.CLUSTER_RESOURCES_DIR, constants.CLUSTER_RESOURCES_EVENTS, data.Namespace+".json"))
+	if err != nil {
+		return nil
+	}
+	events, err := decodeCollectedItems[corev1.Event](content)
+	if err != nil {
+		return errors.Wrapf(err, "failed to unmarshal events of namespace %s", data.Namespace)
+	}
+
+	var latest *corev1.Event
+	for i, event := range events {
+		if event.Reason != "FailedCreate" || !strings.Contains(event.Message, "exceeded quota") {
+			continue
+		}
+		count := int(event.Count)
+		if count == 0 {
+			count = 1
+		}
+		data.RejectedPods += count
+		if latest == nil || event.LastTimestamp.After(latest.LastTimestamp.Time) {
+			latest = &events[i]
+		}
+	}
+	if latest != nil {
+		data.RejectionMessage = latest.Message
+	}
+	return nil
+}
+
+// setUnboundedContainers lists the containers of the running pods of the namespace that have no
+// requests or no limits
+func setUnboundedContainers(data *resourceQuotaTemplateData, getFile getCollectedFileContents) error {
+	content, err := getFile(path.Join(constants.CLUSTER_RESOURCES_DIR, constants.CLUSTER_RESOURCES_PODS, data.Namespace+".json"))
+	if err != nil {
+		return nil
+	}
+	pods, err := decodeCollectedItems[corev1.Pod](content)
+	if err != nil {
+		return errors.Wrapf(err, "failed to unmarshal pods of namespace %s", data.Namespace)
+	}
+
+	unbounded := []string{}
+	for _, pod := range pods {
+		if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		for _, container := range pod.Spec.Containers {
+			if len(container.Resources.Requests) == 0 || len(container.Resources.Limits) == 0 {
+				unbounded = append(unbounded, fmt.Sprintf("%s/%s", pod.Name, container.Name))
+			}
+		}
+	}
+	data.unbounded = len(unbounded)
+	data.UnboundedContainers = strings.Join(unbounded, ", ")
+	return nil
+}
+
+// decodeCollectedItems decodes a collected file of objects, which is either a list or an array
+// of objects depending on the version of troubleshoot that collected it
+func decodeCollectedItems[T any](content []byte) ([]T, error) {
+	var list struct {
+		Items []T `json:"items"`
+	}
+	if err := json.Unmarshal(content, &list); err == nil {
+		return list.Items, nil
+	}
+
+	var items []T
+	if err := json.Unmarshal(content, &items); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
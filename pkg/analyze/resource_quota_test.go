@@ -0,0 +1,127 @@
+package analyzer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyzeResourceQuota(t *testing.T) {
+	files := map[string]string{
+		"cluster-resources/resource-quota/app.json": `{"items": [
+		  {"metadata": {"name": "compute", "namespace": "app"},
+		   "status": {"hard": {"requests.cpu": "4", "requests.memory": "8Gi"}, "used": {"requests.cpu": "2", "requests.memory": "7Gi"}}}
+		]}`,
+		"cluster-resources/resource-quota/batch.json": `[
+		  {"metadata": {"name": "pods", "namespace": "batch"},
+		   "status": {"hard": {"pods": "10"}, "used": {"pods": "10"}}}
+		]`,
+		"cluster-resources/resource-quota/idle.json": `{"items": [
+		  {"metadata": {"name": "compute", "namespace": "idle"},
+		   "status": {"hard": {"pods": "10", "services.loadbalancers": "0"}, "used": {"pods": "1", "services.loadbalancers": "0"}}}
+		]}`,
+		"cluster-resources/limitranges/web.json": `{"items": [
+		  {"metadata": {"name": "defaults", "namespace": "web"}}
+		]}`,
+		"cluster-resources/events/batch.json": `{"items": [
+		  {"reason": "FailedCreate", "count": 3, "lastTimestamp": "2024-05-01T10:00:00Z",
+		   "message": "Error creating: pods \"job-abc\" is forbidden: exceeded quota: pods, requested: pods=1, used: pods=10, limited: pods=10"},
+		  {"reason": "SuccessfulCreate", "message": "Created pod: job-xyz"}
+		]}`,
+		"cluster-resources/pods/web.json": `{"items": [
+		  {"metadata": {"name": "web-1", "namespace": "web"}, "status": {"phase": "Running"},
+		   "spec": {"containers": [
+		     {"name": "app", "resources": {"requests": {"cpu": "100m"}, "limits": {"memory": "128Mi"}}},
+		     {"name": "sidecar", "resources": {}}
+		   ]}},
+		  {"metadata": {"name": "migrate", "namespace": "web"}, "status": {"phase": "Succeeded"},
+		   "spec": {"containers": [{"name": "migrate", "resources": {}}]}}
+		]}`,
+	}
+	getFile := func(path string) ([]byte, error) {
+		content, ok := files[path]
+		if !ok {
+			return nil, os.ErrNotExist
+		}
+		return []byte(content), nil
+	}
+	findFiles := func(pattern string, excluded []string) (map[string][]byte, error) {
+		matches := map[string][]byte{}
+		for name, content := range files {
+			if ok, _ := filepath.Match(pattern, name); ok {
+				matches[name] = []byte(content)
+			}
+		}
+		return matches, nil
+	}
+
+	tests := []struct {
+		name     string
+		analyzer *troubleshootv1beta2.ResourceQuotaAnalyze
+		want     []*AnalyzeResult
+		wantErr  bool
+	}{
+		{
+			name:     "default outcomes",
+			analyzer: &troubleshootv1beta2.ResourceQuotaAnalyze{},
+			want: []*AnalyzeResult{
+				{IsWarn: true, Title: "Resource Quota app", Message: "Namespace app has used 88% of its requests.memory quota (7Gi of 8Gi)"},
+				{IsFail: true, Title: "Resource Quota batch", Message: "Pods in namespace batch were rejected for exceeding quota: Error creating: pods \"job-abc\" is forbidden: exceeded quota: pods, requested: pods=1, used: pods=10, limited: pods=10"},
+				{IsPass: true, Title: "Resource Quota idle", Message: "Namespace idle is within its quotas and limit ranges"},
+				{IsWarn: true, Title: "Resource Quota web", Message: "Containers in namespace web have no resource requests or limits: web-1/sidecar"},
+			},
+		},
+		{
+			name: "thresholds",
+			analyzer: &troubleshootv1beta2.ResourceQuotaAnalyze{
+				AnalyzeMeta: troubleshootv1beta2.AnalyzeMeta{CheckName: "Quota"},
+				Namespaces:  []string{"app", "batch"},
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{Fail: &troubleshootv1beta2.SingleOutcome{When: "usage >= 90%", Message: "{{ .Quota }} {{ .Resource }} at {{ .Usage }}"}},
+					{Warn: &troubleshootv1beta2.SingleOutcome{When: "rejectedPods >= 3", Message: "{{ .RejectedPods }} rejected"}},
+					{Pass: &troubleshootv1beta2.SingleOutcome{Message: "{{ .Usage }}"}},
+				},
+			},
+			want: []*AnalyzeResult{
+				{IsPass: true, Title: "Quota", Message: "88%"},
+				{IsFail: true, Title: "Quota", Message: "pods pods at 100%"},
+			},
+		},
+		{
+			name: "unsupported condition",
+			analyzer: &troubleshootv1beta2.ResourceQuotaAnalyze{
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{Fail: &troubleshootv1beta2.SingleOutcome{When: "quotas > 1"}},
+				},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := require.New(t)
+
+			a := AnalyzeResourceQuota{analyzer: tt.analyzer}
+			results, err := a.Analyze(getFile, findFiles)
+			if tt.wantErr {
+				req.Error(err)
+				return
+			}
+			req.NoError(err)
+			req.Len(results, len(tt.want))
+
+			for i, want := range tt.want {
+				assert.Equal(t, want.IsPass, results[i].IsPass)
+				assert.Equal(t, want.IsWarn, results[i].IsWarn)
+				assert.Equal(t, want.IsFail, results[i].IsFail)
+				assert.Equal(t, want.Title, results[i].Title)
+				assert.Equal(t, want.Message, results[i].Message)
+			}
+		})
+	}
+}
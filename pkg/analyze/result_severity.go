@@ -0,0 +1,50 @@
+package analyzer
+
+import troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+
+type Severity string
+
+const (
+	SeverityInfo     Severity = "info"
+	SeverityWarning  Severity = "warning"
+	SeverityError    Severity = "error"
+	SeverityCritical Severity = "critical"
+)
+
+// Measurement is the numeric value an analyzer observed, and the threshold it was compared to
+// in the condition of the matching outcome.
+type Measurement struct {
+	Observed  float64  `json:"observed" yaml:"observed"`
+	Threshold *float64 `json:"threshold,omitempty" yaml:"threshold,omitempty"`
+	Unit      string   `json:"unit,omitempty" yaml:"unit,omitempty"`
+}
+
+// resultSeverity maps the outcome of a result to a severity. Strict failures block the
+// installation, so they are critical rather than errors.
+func resultSeverity(result *AnalyzeResult) Severity {
+	switch {
+	case result.IsFail && result.Strict:
+		return SeverityCritical
+	case result.IsFail:
+		return SeverityError
+	case result.IsWarn:
+		return SeverityWarning
+	}
+	return SeverityInfo
+}
+
+// setResultsMeta fills in the severity of the results, and the categories of the analyzer that
+// produced them.
+func setResultsMeta(results []*AnalyzeResult, meta *troubleshootv1beta2.AnalyzeMeta) {
+	for _, result := range results {
+		if result == nil {
+			continue
+		}
+		if result.Severity == "" {
+			result.Severity = resultSeverity(result)
+		}
+		if meta != nil && len(result.Categories) == 0 && len(meta.Categories) > 0 {
+			result.Categories = append([]string{}, meta.Categories...)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package analyzer
+
+import (
+	"testing"
+
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func float64Ptr(f float64) *float64 {
+	return &f
+}
+
+func Test_resultSeverity(t *testing.T) {
+	assert.Equal(t, SeverityInfo, resultSeverity(&AnalyzeResult{IsPass: true}))
+	assert.Equal(t, SeverityWarning, resultSeverity(&AnalyzeResult{IsWarn: true}))
+	assert.Equal(t, SeverityError, resultSeverity(&AnalyzeResult{IsFail: true}))
+	assert.Equal(t, SeverityCritical, resultSeverity(&AnalyzeResult{IsFail: true, Strict: true}))
+}
+
+func Test_setResultsMeta(t *testing.T) {
+	results := []*AnalyzeResult{
+		{IsFail: true},
+		{IsWarn: true, Severity: SeverityCritical, Categories: []string{"custom"}},
+		nil,
+	}
+
+	setResultsMeta(results, &troubleshootv1beta2.AnalyzeMeta{Categories: []string{"storage"}})
+
+	assert.Equal(t, SeverityError, results[0].Severity)
+	assert.Equal(t, []string{"storage"}, results[0].Categories)
+	// values set by the analyzer are kept
+	assert.Equal(t, SeverityCritical, results[1].Severity)
+	assert.Equal(t, []string{"custom"}, results[1].Categories)
+}
+
+func Test_getAnalyzeMeta(t *testing.T) {
+	meta := getAnalyzeMeta(&troubleshootv1beta2.Analyze{
+		ClusterVersion: &troubleshootv1beta2.ClusterVersion{
+			AnalyzeMeta: troubleshootv1beta2.AnalyzeMeta{Categories: []string{"kubernetes"}},
+		},
+	})
+	require.NotNil(t, meta)
+	assert.Equal(t, []string{"kubernetes"}, meta.Categories)
+
+	meta = getAnalyzeMeta(&troubleshootv1beta2.HostAnalyze{
+		Memory: &troubleshootv1beta2.MemoryAnalyze{
+			AnalyzeMeta: troubleshootv1beta2.AnalyzeMeta{Categories: []string{"resources"}},
+		},
+	})
+	require.NotNil(t, meta)
+	assert.Equal(t, []string{"resources"}, meta.Categories)
+
+	assert.Nil(t, getAnalyzeMeta(&troubleshootv1beta2.Analyze{}))
+	assert.Nil(t, getAnalyzeMeta(nil))
+}
@@ -71,6 +71,7 @@ func (a *AnalyzeSecret) analyzeSecret(analyzer *troubleshootv1beta2.AnalyzeSecre
 		result.IsFail = true
 		result.Message = failOutcome.Fail.Message
 		result.URI = failOutcome.Fail.URI
+		result.Remediation = failOutcome.Fail.Remediation
 
 		return &result, nil
 	}
@@ -80,6 +81,7 @@ func (a *AnalyzeSecret) analyzeSecret(analyzer *troubleshootv1beta2.AnalyzeSecre
 			result.IsFail = true
 			result.Message = failOutcome.Fail.Message
 			result.URI = failOutcome.Fail.URI
+			result.Remediation = failOutcome.Fail.Remediation
 
 			return &result, nil
 		}
@@ -90,6 +92,7 @@ func (a *AnalyzeSecret) analyzeSecret(analyzer *troubleshootv1beta2.AnalyzeSecre
 		if outcome.Pass != nil {
 			result.Message = outcome.Pass.Message
 			result.URI = outcome.Pass.URI
+			result.Remediation = outcome.Pass.Remediation
 		}
 	}
 
@@ -0,0 +1,212 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/collect"
+	"github.com/replicatedhq/troubleshoot/pkg/constants"
+)
+
+// analyzerCollectorKinds maps in-cluster analyzers, by spec key, to the kind of collector whose output they analyze.
+// Analyzers that can read the output of any collector (e.g. textAnalyze) are not listed.
+var analyzerCollectorKinds = map[string]string{
+	"clusterVersion":           "cluster-info",
+	"kubernetesUpgrade":        "cluster-resources",
+	"storageClass":             "cluster-resources",
+	"customResourceDefinition": "cluster-resources",
+	"ingress":                  "cluster-resources",
+	"imagePullSecret":          "cluster-resources",
+	"deploymentStatus":         "cluster-resources",
+	"statefulsetStatus":        "cluster-resources",
+	"statefulSetVolumes":       "cluster-resources",
+	"jobStatus":                "cluster-resources",
+	"replicasetStatus":         "cluster-resources",
+	"clusterPodStatuses":       "cluster-resources",
+	"clusterContainerStatuses": "cluster-resources",
+	"containerRuntime":         "cluster-resources",
+	"distribution":             "cluster-resources",
+	"nodeResources":            "cluster-resources",
+	"resourceQuota":            "cluster-resources",
+	"podDisruptionBudget":      "cluster-resources",
+	"configMapsAndSecrets":     "cluster-resources",
+	"mutableImageTags":         "cluster-resources",
+	"clusterResource":          "cluster-resources",
+	"event":                    "cluster-resources",
+	"secret":                   "secret",
+	"configMap":                "configmap",
+	"postgres":                 "postgres",
+	"mysql":                    "mysql",
+	"mssql":                    "mssql",
+	"redis":                    "redis",
+	"kafka":                    "kafka",
+	"rabbitmq":                 "rabbitmq",
+	"ldap":                     "ldap",
+	"oidc":                     "oidc",
+	"cephStatus":               "ceph",
+	"longhorn":                 "longhorn",
+	"registryImages":           "registry-images",
+	"imagePlatforms":           "registry-images",
+	"imageSignatures":          "image-signatures",
+	"sysctl":                   "sysctl",
+	"certificates":             "certificates",
+	"goldpinger":               "goldpinger",
+	"nodeLatency":              "node-latency",
+	"clusterOperatorStatus":    "openshift",
+	"machineConfigPoolStatus":  "openshift",
+	"nodeMetrics":              "node-metrics",
+	"http":                     "http",
+	"veleroReadiness":          "velero",
+}
+
+// analyzerClusterResources maps cluster-resources analyzers, by spec key, to the cluster-scoped
+// resource they analyze, which is not collected in namespaced scope.
+var analyzerClusterResources = map[string]string{
+	"storageClass":             constants.CLUSTER_RESOURCES_STORAGE_CLASS,
+	"customResourceDefinition": constants.CLUSTER_RESOURCES_CUSTOM_RESOURCE_DEFINITIONS,
+	"containerRuntime":         constants.CLUSTER_RESOURCES_NODES,
+	"distribution":             constants.CLUSTER_RESOURCES_NODES,
+	"nodeResources":            constants.CLUSTER_RESOURCES_NODES,
+	"kubernetesUpgrade":        constants.CLUSTER_RESOURCES_NODES,
+}
+
+// getSkippedCollectors reads the list of collectors that did not run. A missing
+// or unreadable file means nothing was recorded as skipped.
+func getSkippedCollectors(getFile getCollectedFileContents) collect.SkippedCollectors {
+	contents, err := getFile(constants.SKIPPED_COLLECTORS_FILENAME)
+	if err != nil || len(contents) == 0 {
+		return nil
+	}
+
+	skipped := collect.SkippedCollectors{}
+	if err := json.Unmarshal(contents, &skipped); err != nil {
+		return nil
+	}
+
+	return skipped
+}
+
+// findSkippedDependency returns the skipped collector the analyzer depends on, if any, see
+// matchesSkippedCollector. Collectors that ran but skipped some resources only match when resource
+// is one of them.
+func findSkippedDependency(skipped collect.SkippedCollectors, kind string, name string, object string, resource string, host bool) *collect.SkippedCollector {
+	if kind == "" {
+		return nil
+	}
+
+	for i := range skipped {
+		s := skipped[i]
+		if s.Host != host || s.Collector != kind {
+			continue
+		}
+		if !matchesSkippedCollector(s, name, object) {
+			continue
+		}
+		if len(s.Resources) > 0 && !slices.Contains(s.Resources, resource) {
+			continue
+		}
+		return &s
+	}
+
+	return nil
+}
+
+func getSkippedDependency(analyzer *troubleshootv1beta2.Analyze, getFile getCollectedFileContents) *collect.SkippedCollector {
+	skipped := getSkippedCollectors(getFile)
+	if len(skipped) == 0 {
+		return nil
+	}
+
+	key, name := collect.GetSpecKind(analyzer)
+	return findSkippedDependency(skipped, analyzerCollectorKinds[key], name, analyzerObject(analyzer), analyzerClusterResources[key], false)
+}
+
+// analyzerObject returns the namespace/name of the object the analyzer reads, for the analyzers of
+// a single object
+func analyzerObject(analyzer *troubleshootv1beta2.Analyze) string {
+	switch {
+	case analyzer.Secret != nil:
+		return fmt.Sprintf("%s/%s", analyzer.Secret.Namespace, analyzer.Secret.SecretName)
+	case analyzer.ConfigMap != nil:
+		return fmt.Sprintf("%s/%s", analyzer.ConfigMap.Namespace, analyzer.ConfigMap.ConfigMapName)
+	}
+	return ""
+}
+
+// matchesSkippedCollector reports whether the skipped collector is the one an analyzer with the
+// collector name and object depends on. When both are named, the names have to match. Analyzers of
+// a single object, such as a secret, do not reference collectors by name and match the collector of
+// that object. Host analyzers read the output of the host collector with the same name, or of the
+// unnamed one, so their names always have to match.
+func matchesSkippedCollector(s collect.SkippedCollector, name string, object string) bool {
+	if s.Host {
+		return name == s.Name
+	}
+	if name != "" && s.Name != "" {
+		return name == s.Name
+	}
+	if object != "" {
+		return object == s.Object
+	}
+	return true
+}
+
+func getSkippedHostDependency(hostAnalyzer *troubleshootv1beta2.HostAnalyze, getFile getCollectedFileContents) *collect.SkippedCollector {
+	skipped := getSkippedCollectors(getFile)
+	if len(skipped) == 0 {
+		return nil
+	}
+
+	// host analyzers share spec keys with the host collectors they analyze
+	key, name := collect.GetSpecKind(hostAnalyzer)
+	return findSkippedDependency(skipped, key, name, "", "", true)
+}
+
+// getMissingHostPrivileges returns the record of the host collector the host analyzer depends on
+// when that collector ran without some of the privileges it needs
+func getMissingHostPrivileges(hostAnalyzer *troubleshootv1beta2.HostAnalyze, getFile getCollectedFileContents) *collect.SkippedCollector {
+	skipped := getSkippedCollectors(getFile)
+	if len(skipped) == 0 {
+		return nil
+	}
+
+	key, name := collect.GetSpecKind(hostAnalyzer)
+	for i := range skipped {
+		s := skipped[i]
+		if !s.Host || s.Collector != key || s.Reason != collect.SkipReasonInsufficientPrivileges {
+			continue
+		}
+		if !matchesSkippedCollector(s, name, "") {
+			continue
+		}
+		return &s
+	}
+
+	return nil
+}
+
+// newMissingPrivilegesResult warns that the results of an analyzer may be incomplete because its
+// collector ran without some privileges
+func newMissingPrivilegesResult(title string, dependency *collect.SkippedCollector) *AnalyzeResult {
+	return &AnalyzeResult{
+		IsWarn:  true,
+		Title:   title,
+		Message: fmt.Sprintf("collected without %s (%s), results may be incomplete", strings.Join(dependency.Resources, ", "), dependency.Reason),
+	}
+}
+
+func newSkippedDependencyResult(title string, dependency *collect.SkippedCollector) []*AnalyzeResult {
+	collector := dependency.Collector
+	if dependency.Name != "" {
+		collector = fmt.Sprintf("%s/%s", dependency.Collector, dependency.Name)
+	}
+
+	return []*AnalyzeResult{{
+		IsWarn:  true,
+		Title:   title,
+		Message: fmt.Sprintf("skipped: dependency not collected (collector %q was skipped: %s)", collector, dependency.Reason),
+	}}
+}
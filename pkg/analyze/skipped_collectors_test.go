@@ -0,0 +1,210 @@
+package analyzer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/collect"
+	"github.com/replicatedhq/troubleshoot/pkg/constants"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_findSkippedDependency(t *testing.T) {
+	skipped := collect.SkippedCollectors{
+		{Collector: "postgres", Name: "pg", Reason: collect.SkipReasonInsufficientRBAC},
+		{Collector: "cpu", Host: true, Reason: collect.SkipReasonExcluded},
+		{Collector: "cluster-resources", Resources: []string{"nodes", "storage-classes"}, Reason: collect.SkipReasonClusterScoped},
+		{Collector: "secret", Object: "app/db-creds", Reason: collect.SkipReasonNamespaceOutOfScope},
+		{Collector: "diskUsage", Name: "var-lib", Host: true, Reason: collect.SkipReasonExcluded},
+	}
+
+	tests := []struct {
+		name     string
+		kind     string
+		cn       string
+		object   string
+		resource string
+		host     bool
+		want     *collect.SkippedCollector
+	}{
+		{
+			name: "matching kind and name",
+			kind: "postgres",
+			cn:   "pg",
+			want: &skipped[0],
+		},
+		{
+			name: "different name",
+			kind: "postgres",
+			cn:   "other",
+		},
+		{
+			name: "host collector",
+			kind: "cpu",
+			host: true,
+			want: &skipped[1],
+		},
+		{
+			name: "host collector does not match in-cluster analyzer",
+			kind: "cpu",
+		},
+		{
+			name: "analyzer without a collector dependency",
+			kind: "",
+		},
+		{
+			name:     "skipped resource",
+			kind:     "cluster-resources",
+			resource: "nodes",
+			want:     &skipped[2],
+		},
+		{
+			name: "collector ran without some resources",
+			kind: "cluster-resources",
+		},
+		{
+			name:   "skipped object",
+			kind:   "secret",
+			object: "app/db-creds",
+			want:   &skipped[3],
+		},
+		{
+			name:   "another object of a skipped kind",
+			kind:   "secret",
+			object: "app/api-token",
+		},
+		{
+			name: "matching host collector name",
+			kind: "diskUsage",
+			cn:   "var-lib",
+			host: true,
+			want: &skipped[4],
+		},
+		{
+			name: "unnamed host analyzer of a skipped named collector",
+			kind: "diskUsage",
+			host: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := findSkippedDependency(skipped, tt.kind, tt.cn, tt.object, tt.resource, tt.host)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestAnalyze_SkippedDependency(t *testing.T) {
+	skipped, err := json.Marshal(collect.SkippedCollectors{
+		{Collector: "cluster-info", Reason: collect.SkipReasonExcluded},
+		{Collector: "memory", Host: true, Reason: collect.SkipReasonExcluded},
+	})
+	require.NoError(t, err)
+
+	getFile := func(name string) ([]byte, error) {
+		if name == constants.SKIPPED_COLLECTORS_FILENAME {
+			return skipped, nil
+		}
+		return nil, fmt.Errorf("file %s was not collected", name)
+	}
+
+	results, err := Analyze(context.Background(), &troubleshootv1beta2.Analyze{
+		ClusterVersion: &troubleshootv1beta2.ClusterVersion{
+			AnalyzeMeta: troubleshootv1beta2.AnalyzeMeta{CheckName: "Kubernetes version"},
+		},
+	}, getFile, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []*AnalyzeResult{{
+		IsWarn:   true,
+		Title:    "Kubernetes version",
+		Message:  `skipped: dependency not collected (collector "cluster-info" was skipped: excluded)`,
+		Severity: SeverityWarning,
+	}}, results)
+
+	hostResults := HostAnalyze(context.Background(), &troubleshootv1beta2.HostAnalyze{
+		Memory: &troubleshootv1beta2.MemoryAnalyze{},
+	}, getFile, nil)
+	assert.Equal(t, []*AnalyzeResult{{
+		IsWarn:   true,
+		Title:    "Amount of Memory",
+		Message:  `skipped: dependency not collected (collector "memory" was skipped: excluded)`,
+		Severity: SeverityWarning,
+	}}, hostResults)
+}
+
+func TestHostAnalyze_MissingPrivileges(t *testing.T) {
+	skipped, err := json.Marshal(collect.SkippedCollectors{
+		{Collector: "networkConfig", Host: true, Resources: []string{collect.HostPrivilegeFirewall}, Reason: collect.SkipReasonInsufficientPrivileges},
+	})
+	require.NoError(t, err)
+
+	getFile := func(name string) ([]byte, error) {
+		switch name {
+		case constants.SKIPPED_COLLECTORS_FILENAME:
+			return skipped, nil
+		case collect.HostNetworkConfigPath:
+			return []byte(`{"interfaces":[{"name":"eth0","mtu":1500,"state":"up"}],"routes":[{"family":"inet","destination":"default","device":"eth0"}],"errors":{"iptables-save":"insufficient privileges: firewall access is required"}}`), nil
+		}
+		return nil, fmt.Errorf("file %s was not collected", name)
+	}
+
+	results := HostAnalyze(context.Background(), &troubleshootv1beta2.HostAnalyze{
+		NetworkConfig: &troubleshootv1beta2.NetworkConfigAnalyze{
+			Outcomes: []*troubleshootv1beta2.Outcome{
+				{Fail: &troubleshootv1beta2.SingleOutcome{When: "no default route", Message: "no default route"}},
+				{Pass: &troubleshootv1beta2.SingleOutcome{Message: "default route found"}},
+			},
+		},
+	}, getFile, nil)
+	assert.Equal(t, []*AnalyzeResult{
+		{
+			IsPass:   true,
+			Title:    "Network Config",
+			Message:  "default route found",
+			Severity: SeverityInfo,
+		},
+		{
+			IsWarn:   true,
+			Title:    "Network Config",
+			Message:  "collected without firewall access (insufficient privileges), results may be incomplete",
+			Severity: SeverityWarning,
+		},
+	}, results)
+}
+
+func TestAnalyze_SkippedDependencyOfSameKind(t *testing.T) {
+	// two secret collectors ran, and only the one of app/db-creds was skipped
+	skipped, err := json.Marshal(collect.SkippedCollectors{
+		{Collector: "secret", Object: "app/db-creds", Reason: collect.SkipReasonNamespaceOutOfScope},
+		{Collector: "networkConfig", Name: "other", Host: true, Resources: []string{collect.HostPrivilegeFirewall}, Reason: collect.SkipReasonInsufficientPrivileges},
+	})
+	require.NoError(t, err)
+
+	getFile := func(name string) ([]byte, error) {
+		if name == constants.SKIPPED_COLLECTORS_FILENAME {
+			return skipped, nil
+		}
+		return nil, fmt.Errorf("file %s was not collected", name)
+	}
+	secretAnalyzer := func(name string) *troubleshootv1beta2.Analyze {
+		return &troubleshootv1beta2.Analyze{
+			Secret: &troubleshootv1beta2.AnalyzeSecret{SecretName: name, Namespace: "app"},
+		}
+	}
+
+	assert.Equal(t, &collect.SkippedCollector{Collector: "secret", Object: "app/db-creds", Reason: collect.SkipReasonNamespaceOutOfScope},
+		getSkippedDependency(secretAnalyzer("db-creds"), getFile))
+	assert.Nil(t, getSkippedDependency(secretAnalyzer("api-token"), getFile))
+
+	networkConfig := func(name string) *troubleshootv1beta2.HostAnalyze {
+		return &troubleshootv1beta2.HostAnalyze{
+			NetworkConfig: &troubleshootv1beta2.NetworkConfigAnalyze{CollectorName: name},
+		}
+	}
+	assert.NotNil(t, getMissingHostPrivileges(networkConfig("other"), getFile))
+	assert.Nil(t, getMissingHostPrivileges(networkConfig(""), getFile))
+}
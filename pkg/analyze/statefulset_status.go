@@ -67,7 +67,7 @@ func analyzeOneStatefulsetStatus(analyzer *troubleshootv1beta2.StatefulsetStatus
 	var result *AnalyzeResult
 	for _, collected := range files { // only 1 file here
 		var exists bool = true
-		var readyReplicas int
+		data := &workloadStatusTemplateData{Namespace: analyzer.Namespace, Name: analyzer.Name}
 
 		var statefulsets appsv1.StatefulSetList
 		if err := json.Unmarshal(collected, &statefulsets); err != nil {
@@ -84,15 +84,22 @@ func analyzeOneStatefulsetStatus(analyzer *troubleshootv1beta2.StatefulsetStatus
 
 		if statefulset == nil {
 			exists = false
-			readyReplicas = 0
 		} else {
-			readyReplicas = int(statefulset.Status.ReadyReplicas)
+			data.Replicas = 1 // default is 1
+			if statefulset.Spec.Replicas != nil {
+				data.Replicas = int(*statefulset.Spec.Replicas)
+			}
+			data.ReadyReplicas = int(statefulset.Status.ReadyReplicas)
+			data.AvailableReplicas = int(statefulset.Status.AvailableReplicas)
 		}
 		if len(analyzer.Outcomes) > 0 {
-			result, err = commonStatus(analyzer.Outcomes, analyzer.Name, "kubernetes_statefulset_status", "https://troubleshoot.sh/images/analyzer-icons/statefulset-status.svg?w=23&h=14", readyReplicas, exists, "statefulset")
+			result, err = commonStatus(analyzer.Outcomes, analyzer.Name, "kubernetes_statefulset_status", "https://troubleshoot.sh/images/analyzer-icons/statefulset-status.svg?w=23&h=14", data.ReadyReplicas, exists, "statefulset")
 			if err != nil {
 				return nil, errors.Wrap(err, "failed to process status")
 			}
+			if result, err = renderResultMessage(result, data); err != nil {
+				return nil, err
+			}
 		} else {
 			result = getDefaultStatefulSetResult(statefulset)
 		}
@@ -0,0 +1,369 @@
+package analyzer
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/troubleshoot/internal/util"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/constants"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+)
+
+var defaultStatefulSetVolumesOutcomes = []*troubleshootv1beta2.Outcome{
+	{Fail: &troubleshootv1beta2.SingleOutcome{When: "missingStorageClasses > 0", Message: "StatefulSet {{ .Namespace }}/{{ .Name }} has pods pending on claims for StorageClasses that do not exist: {{ .MissingStorageClasses }}"}},
+	{Fail: &troubleshootv1beta2.SingleOutcome{When: "topologyConflicts > 0", Message: "Pods {{ .TopologyConflicts }} of StatefulSet {{ .Namespace }}/{{ .Name }} cannot be scheduled to the nodes their volumes are available on"}},
+	{Fail: &troubleshootv1beta2.SingleOutcome{When: "unboundClaims > 0", Message: "StatefulSet {{ .Namespace }}/{{ .Name }} has pods pending on unbound PersistentVolumeClaims: {{ .UnboundClaims }}"}},
+	{Pass: &troubleshootv1beta2.SingleOutcome{Message: "The volumes of the pods of all StatefulSets are bound"}},
+}
+
+// statefulSetVolumesTemplateData is passed to the messages of the outcomes. It is empty when no
+// StatefulSet has pods pending on their volumes.
+type statefulSetVolumesTemplateData struct {
+	Namespace             string
+	Name                  string
+	PendingPods           string
+	UnboundClaims         string
+	MissingStorageClasses string
+	TopologyConflicts     string
+
+	pending   []string
+	unbound   []string
+	missing   []string
+	conflicts []string
+}
+
+type AnalyzeStatefulSetVolumes struct {
+	analyzer *troubleshootv1beta2.StatefulSetVolumes
+}
+
+func (a *AnalyzeStatefulSetVolumes) Title() string {
+	if a.analyzer.CheckName != "" {
+		return a.analyzer.CheckName
+	}
+
+	return "StatefulSet Volumes"
+}
+
+func (a *AnalyzeStatefulSetVolumes) IsExcluded() (bool, error) {
+	return isExcluded(a.analyzer.Exclude)
+}
+
+func (a *AnalyzeStatefulSetVolumes) Analyze(getFile getCollectedFileContents, findFiles getChildCollectedFileContents) ([]*AnalyzeResult, error) {
+	files, err := findFiles(filepath.Join(constants.CLUSTER_RESOURCES_DIR, constants.CLUSTER_RESOURCES_STATEFULSETS, "*.json"), nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read collected statefulsets")
+	}
+
+	statefulSets := []appsv1.StatefulSet{}
+	for name, content := range files {
+		items, err := decodeCollectedItems[appsv1.StatefulSet](content)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to unmarshal %s", name)
+		}
+		for _, statefulSet := range items {
+			if len(statefulSet.Spec.VolumeClaimTemplates) == 0 {
+				continue
+			}
+			if len(a.analyzer.Namespaces) == 0 || slices.Contains(a.analyzer.Namespaces, statefulSet.Namespace) {
+				statefulSets = append(statefulSets, statefulSet)
+			}
+		}
+	}
+	sort.Slice(statefulSets, func(i, j int) bool {
+		if statefulSets[i].Namespace != statefulSets[j].Namespace {
+			return statefulSets[i].Namespace < statefulSets[j].Namespace
+		}
+		return statefulSets[i].Name < statefulSets[j].Name
+	})
+
+	storageClasses, err := collectedStorageClasses(getFile)
+	if err != nil {
+		return nil, err
+	}
+
+	outcomes := a.analyzer.Outcomes
+	if len(outcomes) == 0 {
+		outcomes = defaultStatefulSetVolumesOutcomes
+	}
+
+	results := []*AnalyzeResult{}
+	for _, statefulSet := range statefulSets {
+		data, err := statefulSetVolumesData(statefulSet, storageClasses, getFile)
+		if err != nil {
+			return nil, err
+		}
+		if len(data.pending) == 0 {
+			continue
+		}
+
+		result, err := a.analyzeStatefulSet(data, outcomes)
+		if err != nil {
+			return nil, err
+		}
+		if result != nil {
+			results = append(results, result)
+		}
+	}
+
+	if len(results) == 0 {
+		result, err := a.analyzeStatefulSet(&statefulSetVolumesTemplateData{}, outcomes)
+		if err != nil {
+			return nil, err
+		}
+		if result != nil {
+			results = append(results, result)
+		}
+	}
+
+	return results, nil
+}
+
+// analyzeStatefulSet returns the result of the first outcome whose condition matches the
+// StatefulSet, or nil when none does
+func (a *AnalyzeStatefulSetVolumes) analyzeStatefulSet(data *statefulSetVolumesTemplateData, outcomes []*troubleshootv1beta2.Outcome) (*AnalyzeResult, error) {
+	for _, outcome := range outcomes {
+		result := &AnalyzeResult{
+			IconKey: "kubernetes_storage_class",
+			IconURI: "https://troubleshoot.sh/images/analyzer-icons/storage-class.svg?w=12&h=12",
+			Strict:  a.analyzer.Strict.BoolOrDefaultFalse(),
+		}
+
+		var singleOutcome *troubleshootv1beta2.SingleOutcome
+		switch {
+		case outcome.Fail != nil:
+			singleOutcome = outcome.Fail
+			result.IsFail = true
+		case outcome.Warn != nil:
+			singleOutcome = outcome.Warn
+			result.IsWarn = true
+		case outcome.Pass != nil:
+			singleOutcome = outcome.Pass
+			result.IsPass = true
+		default:
+			continue
+		}
+
+		if singleOutcome.When != "" {
+			match, err := compareStatefulSetVolumes(data, singleOutcome.When)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to evaluate when %q", singleOutcome.When)
+			}
+			if !match {
+				continue
+			}
+		}
+
+		var err error
+		result.Title, err = util.RenderTemplate(a.Title(), data)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to render title template")
+		}
+		result.Message, err = util.RenderTemplate(singleOutcome.Message, data)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to render message template")
+		}
+		result.URI = singleOutcome.URI
+		result.Remediation = singleOutcome.Remediation
+		result.Condition = singleOutcome.When
+		if data.Name != "" {
+			result.InvolvedObject = &corev1.ObjectReference{
+				APIVersion: "apps/v1",
+				Kind:       "StatefulSet",
+				Namespace:  data.Namespace,
+				Name:       data.Name,
+			}
+		}
+		return result, nil
+	}
+
+	return nil, nil
+}
+
+// compareStatefulSetVolumes evaluates a when clause against a StatefulSet. Supported conditions
+// all count the pending pods of the StatefulSet or their volume claims:
+//
+//   - "pendingPods <operator> <n>", pods pending on any of the conditions below
+//   - "unboundClaims <operator> <n>", claims that are missing or not bound to a volume
+//   - "missingStorageClasses <operator> <n>", StorageClasses named by claims that do not exist
+//   - "topologyConflicts <operator> <n>", pods that cannot be scheduled to the nodes their bound
+//     volumes are available on
+func compareStatefulSetVolumes(data *statefulSetVolumesTemplateData, when string) (bool, error) {
+	parts := strings.Fields(when)
+	if len(parts) != 3 {
+		return false, fmt.Errorf("expected 3 parts in when %q, got %d", when, len(parts))
+	}
+	key, condition := parts[0], parts[1]+" "+parts[2]
+
+	switch key {
+	case "pendingPods":
+		return compareActualToWhen(condition, len(data.pending))
+	case "unboundClaims":
+		return compareActualToWhen(condition, len(data.unbound))
+	case "missingStorageClasses":
+		return compareActualToWhen(condition, len(data.missing))
+	case "topologyConflicts":
+		return compareActualToWhen(condition, len(data.conflicts))
+	}
+
+	return false, fmt.Errorf("unsupported condition %q, must be one of pendingPods, unboundClaims, missingStorageClasses or topologyConflicts", key)
+}
+
+// statefulSetVolumesData finds the pending pods of the StatefulSet and the claims they are
+// pending on. A claim of a StorageClass that binds volumes on first consumer is only pending on
+// its volume when the scheduler reported a volume problem for the pod, otherwise the pod is
+// pending for another reason and so is the claim.
+func statefulSetVolumesData(statefulSet appsv1.StatefulSet, storageClasses map[string]storagev1.StorageClass, getFile getCollectedFileContents) (*statefulSetVolumesTemplateData, error) {
+	data := &statefulSetVolumesTemplateData{
+		Namespace: statefulSet.Namespace,
+		Name:      statefulSet.Name,
+	}
+
+	pods, err := collectedNamespaceItems[corev1.Pod](getFile, constants.CLUSTER_RESOURCES_PODS, statefulSet.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	claims, err := collectedNamespaceItems[corev1.PersistentVolumeClaim](getFile, constants.CLUSTER_RESOURCES_PVCS, statefulSet.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	events, err := collectedNamespaceItems[corev1.Event](getFile, constants.CLUSTER_RESOURCES_EVENTS, statefulSet.Namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	podsByName := map[string]corev1.Pod{}
+	for _, pod := range pods {
+		podsByName[pod.Name] = pod
+	}
+	claimsByName := map[string]corev1.PersistentVolumeClaim{}
+	for _, claim := range claims {
+		claimsByName[claim.Name] = claim
+	}
+	schedulingEvents := map[string]corev1.Event{}
+	for _, event := range events {
+		if event.Reason != "FailedScheduling" || event.InvolvedObject.Kind != "Pod" {
+			continue
+		}
+		latest, ok := schedulingEvents[event.InvolvedObject.Name]
+		if !ok || !event.LastTimestamp.Before(&latest.LastTimestamp) {
+			schedulingEvents[event.InvolvedObject.Name] = event
+		}
+	}
+
+	replicas := 1
+	if statefulSet.Spec.Replicas != nil {
+		replicas = int(*statefulSet.Spec.Replicas)
+	}
+
+	for i := 0; i < replicas; i++ {
+		podName := fmt.Sprintf("%s-%d", statefulSet.Name, i)
+		pod, ok := podsByName[podName]
+		if !ok || pod.Status.Phase != corev1.PodPending {
+			continue
+		}
+
+		schedulingMessage := strings.ToLower(schedulingEvents[podName].Message)
+		pendingOnVolumes := false
+		if strings.Contains(schedulingMessage, "volume node affinity conflict") {
+			data.conflicts = append(data.conflicts, podName)
+			pendingOnVolumes = true
+		}
+
+		for _, template := range statefulSet.Spec.VolumeClaimTemplates {
+			claimName := fmt.Sprintf("%s-%s", template.Name, podName)
+			claim, ok := claimsByName[claimName]
+			if !ok {
+				data.unbound = append(data.unbound, claimName)
+				pendingOnVolumes = true
+				continue
+			}
+			if claim.Status.Phase == corev1.ClaimBound {
+				continue
+			}
+
+			storageClassName := claimStorageClassName(claim, storageClasses)
+			storageClass, found := storageClasses[storageClassName]
+			if storageClasses != nil && storageClassName != "" && !found {
+				if !slices.Contains(data.missing, storageClassName) {
+					data.missing = append(data.missing, storageClassName)
+				}
+				pendingOnVolumes = true
+				continue
+			}
+			if isWaitForFirstConsumer(storageClass) && !strings.Contains(schedulingMessage, "volume") {
+				continue
+			}
+			data.unbound = append(data.unbound, claimName)
+			pendingOnVolumes = true
+		}
+
+		if pendingOnVolumes {
+			data.pending = append(data.pending, podName)
+		}
+	}
+
+	data.PendingPods = strings.Join(data.pending, ", ")
+	data.UnboundClaims = strings.Join(data.unbound, ", ")
+	data.MissingStorageClasses = strings.Join(data.missing, ", ")
+	data.TopologyConflicts = strings.Join(data.conflicts, ", ")
+	return data, nil
+}
+
+// claimStorageClassName returns the StorageClass of the claim, which is the default StorageClass
+// when the claim does not name one
+func claimStorageClassName(claim corev1.PersistentVolumeClaim, storageClasses map[string]storagev1.StorageClass) string {
+	if claim.Spec.StorageClassName != nil {
+		return *claim.Spec.StorageClassName
+	}
+	for name, storageClass := range storageClasses {
+		if storageClass.Annotations["storageclass.kubernetes.io/is-default-class"] == "true" {
+			return name
+		}
+	}
+	return ""
+}
+
+func isWaitForFirstConsumer(storageClass storagev1.StorageClass) bool {
+	return storageClass.VolumeBindingMode != nil && *storageClass.VolumeBindingMode == storagev1.VolumeBindingWaitForFirstConsumer
+}
+
+// collectedStorageClasses returns the collected StorageClasses by name, or nil when they were not
+// collected
+func collectedStorageClasses(getFile getCollectedFileContents) (map[string]storagev1.StorageClass, error) {
+	content, err := getFile(path.Join(constants.CLUSTER_RESOURCES_DIR, fmt.Sprintf("%s.json", constants.CLUSTER_RESOURCES_STORAGE_CLASS)))
+	if err != nil || len(content) == 0 {
+		return nil, nil
+	}
+	items, err := decodeCollectedItems[storagev1.StorageClass](content)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal storage classes")
+	}
+
+	storageClasses := map[string]storagev1.StorageClass{}
+	for _, storageClass := range items {
+		storageClasses[storageClass.Name] = storageClass
+	}
+	return storageClasses, nil
+}
+
+// collectedNamespaceItems decodes the collected resources of a namespace, which are missing when
+// the namespace was not collected
+func collectedNamespaceItems[T any](getFile getCollectedFileContents, resource string, namespace string) ([]T, error) {
+	content, err := getFile(path.Join(constants.CLUSTER_RESOURCES_DIR, resource, namespace+".json"))
+	if err != nil || len(content) == 0 {
+		return nil, nil
+	}
+	items, err := decodeCollectedItems[T](content)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal %s of namespace %s", resource, namespace)
+	}
+	return items, nil
+}
@@ -0,0 +1,150 @@
+package analyzer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyzeStatefulSetVolumes(t *testing.T) {
+	files := map[string]string{
+		"cluster-resources/storage-classes.json": `{"items": [
+		  {"metadata": {"name": "standard", "annotations": {"storageclass.kubernetes.io/is-default-class": "true"}}, "volumeBindingMode": "WaitForFirstConsumer"},
+		  {"metadata": {"name": "fast"}, "volumeBindingMode": "Immediate"}
+		]}`,
+		"cluster-resources/statefulsets/db.json": `{"items": [
+		  {"metadata": {"name": "postgres", "namespace": "db"},
+		   "spec": {"replicas": 2, "volumeClaimTemplates": [{"metadata": {"name": "data"}}]}},
+		  {"metadata": {"name": "redis", "namespace": "db"},
+		   "spec": {"replicas": 1, "volumeClaimTemplates": [{"metadata": {"name": "data"}}]}},
+		  {"metadata": {"name": "stateless", "namespace": "db"}, "spec": {"replicas": 1}}
+		]}`,
+		"cluster-resources/statefulsets/queue.json": `[
+		  {"metadata": {"name": "kafka", "namespace": "queue"},
+		   "spec": {"replicas": 3, "volumeClaimTemplates": [{"metadata": {"name": "logs"}}]}}
+		]`,
+		"cluster-resources/pods/db.json": `{"items": [
+		  {"metadata": {"name": "postgres-0", "namespace": "db"}, "status": {"phase": "Running"}},
+		  {"metadata": {"name": "postgres-1", "namespace": "db"}, "status": {"phase": "Pending"}},
+		  {"metadata": {"name": "redis-0", "namespace": "db"}, "status": {"phase": "Pending"}},
+		  {"metadata": {"name": "stateless-0", "namespace": "db"}, "status": {"phase": "Pending"}}
+		]}`,
+		"cluster-resources/pvcs/db.json": `{"items": [
+		  {"metadata": {"name": "data-postgres-0", "namespace": "db"}, "spec": {"storageClassName": "fast"}, "status": {"phase": "Bound"}},
+		  {"metadata": {"name": "data-postgres-1", "namespace": "db"}, "spec": {"storageClassName": "premium"}, "status": {"phase": "Pending"}},
+		  {"metadata": {"name": "data-redis-0", "namespace": "db"}, "status": {"phase": "Pending"}}
+		]}`,
+		"cluster-resources/events/db.json": `{"items": [
+		  {"reason": "FailedScheduling", "involvedObject": {"kind": "Pod", "name": "redis-0"}, "lastTimestamp": "2024-05-01T10:00:00Z",
+		   "message": "0/3 nodes are available: 3 Insufficient cpu."}
+		]}`,
+		"cluster-resources/pods/queue.json": `{"items": [
+		  {"metadata": {"name": "kafka-0", "namespace": "queue"}, "status": {"phase": "Running"}},
+		  {"metadata": {"name": "kafka-1", "namespace": "queue"}, "status": {"phase": "Pending"}},
+		  {"metadata": {"name": "kafka-2", "namespace": "queue"}, "status": {"phase": "Pending"}}
+		]}`,
+		"cluster-resources/pvcs/queue.json": `{"items": [
+		  {"metadata": {"name": "logs-kafka-0", "namespace": "queue"}, "status": {"phase": "Bound"}},
+		  {"metadata": {"name": "logs-kafka-1", "namespace": "queue"}, "status": {"phase": "Bound"}}
+		]}`,
+		"cluster-resources/events/queue.json": `{"items": [
+		  {"reason": "FailedScheduling", "involvedObject": {"kind": "Pod", "name": "kafka-1"}, "lastTimestamp": "2024-05-01T09:00:00Z",
+		   "message": "0/3 nodes are available: 3 Insufficient memory."},
+		  {"reason": "FailedScheduling", "involvedObject": {"kind": "Pod", "name": "kafka-1"}, "lastTimestamp": "2024-05-01T10:00:00Z",
+		   "message": "0/3 nodes are available: 1 node(s) had volume node affinity conflict, 2 node(s) didn't match Pod's node affinity/selector."}
+		]}`,
+	}
+	getFile := func(path string) ([]byte, error) {
+		content, ok := files[path]
+		if !ok {
+			return nil, os.ErrNotExist
+		}
+		return []byte(content), nil
+	}
+	findFiles := func(pattern string, excluded []string) (map[string][]byte, error) {
+		matches := map[string][]byte{}
+		for name, content := range files {
+			if ok, _ := filepath.Match(pattern, name); ok {
+				matches[name] = []byte(content)
+			}
+		}
+		return matches, nil
+	}
+
+	tests := []struct {
+		name     string
+		analyzer *troubleshootv1beta2.StatefulSetVolumes
+		want     []*AnalyzeResult
+		wantErr  bool
+	}{
+		{
+			name:     "default outcomes",
+			analyzer: &troubleshootv1beta2.StatefulSetVolumes{},
+			want: []*AnalyzeResult{
+				{IsFail: true, Message: "StatefulSet db/postgres has pods pending on claims for StorageClasses that do not exist: premium"},
+				{IsFail: true, Message: "Pods kafka-1 of StatefulSet queue/kafka cannot be scheduled to the nodes their volumes are available on"},
+			},
+		},
+		{
+			name: "pending pods",
+			analyzer: &troubleshootv1beta2.StatefulSetVolumes{
+				AnalyzeMeta: troubleshootv1beta2.AnalyzeMeta{CheckName: "{{ .Namespace }}/{{ .Name }}"},
+				Namespaces:  []string{"queue"},
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{Warn: &troubleshootv1beta2.SingleOutcome{When: "pendingPods >= 2", Message: "{{ .PendingPods }}"}},
+					{Fail: &troubleshootv1beta2.SingleOutcome{When: "unboundClaims > 0", Message: "{{ .PendingPods }} on {{ .UnboundClaims }}"}},
+				},
+			},
+			want: []*AnalyzeResult{
+				{IsWarn: true, Title: "queue/kafka", Message: "kafka-1, kafka-2"},
+			},
+		},
+		{
+			name: "no pending statefulsets",
+			analyzer: &troubleshootv1beta2.StatefulSetVolumes{
+				Namespaces: []string{"web"},
+			},
+			want: []*AnalyzeResult{
+				{IsPass: true, Message: "The volumes of the pods of all StatefulSets are bound"},
+			},
+		},
+		{
+			name: "unsupported condition",
+			analyzer: &troubleshootv1beta2.StatefulSetVolumes{
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{Fail: &troubleshootv1beta2.SingleOutcome{When: "lostClaims > 0"}},
+				},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := require.New(t)
+
+			a := AnalyzeStatefulSetVolumes{analyzer: tt.analyzer}
+			results, err := a.Analyze(getFile, findFiles)
+			if tt.wantErr {
+				req.Error(err)
+				return
+			}
+			req.NoError(err)
+			req.Len(results, len(tt.want))
+
+			for i, want := range tt.want {
+				assert.Equal(t, want.IsPass, results[i].IsPass)
+				assert.Equal(t, want.IsWarn, results[i].IsWarn)
+				assert.Equal(t, want.IsFail, results[i].IsFail)
+				assert.Equal(t, want.Message, results[i].Message)
+				if want.Title != "" {
+					assert.Equal(t, want.Title, results[i].Title)
+				}
+			}
+		})
+	}
+}
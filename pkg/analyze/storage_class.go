@@ -64,6 +64,7 @@ func (a *AnalyzeStorageClass) analyzeStorageClass(analyzer *troubleshootv1beta2.
 				if outcome.Pass != nil {
 					result.Message = outcome.Pass.Message
 					result.URI = outcome.Pass.URI
+					result.Remediation = outcome.Pass.Remediation
 				}
 			}
 			if analyzer.StorageClassName == "" && result.Message == "" {
@@ -79,6 +80,7 @@ func (a *AnalyzeStorageClass) analyzeStorageClass(analyzer *troubleshootv1beta2.
 		if outcome.Fail != nil {
 			result.Message = outcome.Fail.Message
 			result.URI = outcome.Fail.URI
+			result.Remediation = outcome.Fail.Remediation
 		}
 	}
 	if analyzer.StorageClassName == "" && result.Message == "" {
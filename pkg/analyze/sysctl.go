@@ -144,6 +144,7 @@ func evalSysctlOutcome(nodeParams map[string]map[string]string, outcome *trouble
 	}
 
 	result.URI = singleOutcome.URI
+	result.Remediation = singleOutcome.Remediation
 
 	return result, nil
 }
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -65,9 +66,17 @@ func analyzeTextAnalyze(
 
 	results := []*AnalyzeResult{}
 
+	// there is a result per file, in the order of the files
+	files := make([]string, 0, len(collected))
+	for file := range collected {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+
 	if analyzer.RegexPattern != "" {
-		for _, fileContents := range collected {
-			result, err := analyzeRegexPattern(analyzer.RegexPattern, fileContents, analyzer.Outcomes, title)
+		pattern := regexWithFlags(analyzer.RegexPattern, analyzer.Multiline)
+		for _, file := range files {
+			result, err := analyzeRegexPattern(pattern, collected[file], analyzer.Outcomes, title, file)
 			if err != nil {
 				return nil, err
 			}
@@ -78,8 +87,9 @@ func analyzeTextAnalyze(
 	}
 
 	if analyzer.RegexGroups != "" {
-		for _, fileContents := range collected {
-			result, err := analyzeRegexGroups(analyzer.RegexGroups, fileContents, analyzer.Outcomes, title)
+		pattern := regexWithFlags(analyzer.RegexGroups, analyzer.Multiline)
+		for _, file := range files {
+			result, err := analyzeRegexGroups(pattern, collected[file], analyzer.Outcomes, title, file)
 			if err != nil {
 				return nil, err
 			}
@@ -108,7 +118,56 @@ func analyzeTextAnalyze(
 	}, nil
 }
 
-func analyzeRegexPattern(pattern string, collected []byte, outcomes []*troubleshootv1beta2.Outcome, checkName string) (*AnalyzeResult, error) {
+// regexWithFlags returns the pattern with the m flag set in multiline mode, so that ^ and $ match
+// at the start and end of each line rather than of the file
+func regexWithFlags(pattern string, multiline bool) string {
+	if multiline {
+		return "(?m)" + pattern
+	}
+	return pattern
+}
+
+// regexTemplateData returns the data passed to the messages of the outcomes of a regex: the named
+// capture groups of the first match, the number of matches as Matches and the file as File,
+// unless capture groups have these names
+func regexTemplateData(foundMatches map[string]string, matches int, file string) map[string]string {
+	data := map[string]string{
+		"Matches": strconv.Itoa(matches),
+		"File":    file,
+	}
+	for name, value := range foundMatches {
+		data[name] = value
+	}
+	return data
+}
+
+// regexNamedGroups returns the named capture groups of a match
+func regexNamedGroups(re *regexp.Regexp, match []string) map[string]string {
+	foundMatches := map[string]string{}
+	for i, name := range re.SubexpNames() {
+		if i != 0 && name != "" && len(match) > i {
+			foundMatches[name] = match[i]
+		}
+	}
+	return foundMatches
+}
+
+// compareRegexMatches evaluates the when clause of an outcome of a regex, either true or false
+// compared to whether the regex matched, or the number of matches compared to a number, e.g.
+// "matches >= 5"
+func compareRegexMatches(when string, matches int) (bool, error) {
+	if expected, err := strconv.ParseBool(when); err == nil {
+		return (matches > 0) == expected, nil
+	}
+
+	parts := strings.Fields(when)
+	if len(parts) != 3 || parts[0] != "matches" {
+		return false, errors.Errorf("expected true, false or a number of matches, e.g. \"matches >= 5\", got %q", when)
+	}
+	return compareActualToWhen(parts[1]+" "+parts[2], matches)
+}
+
+func analyzeRegexPattern(pattern string, collected []byte, outcomes []*troubleshootv1beta2.Outcome, checkName string, file string) (*AnalyzeResult, error) {
 	re, err := regexp.Compile(pattern)
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to compile regex: %s", pattern)
@@ -120,7 +179,8 @@ func analyzeRegexPattern(pattern string, collected []byte, outcomes []*troublesh
 		IconURI: "https://troubleshoot.sh/images/analyzer-icons/text-analyze.svg",
 	}
 
-	isMatch := re.MatchString(string(collected))
+	matches := len(re.FindAllStringIndex(string(collected), -1))
+	templateData := regexTemplateData(regexNamedGroups(re, re.FindStringSubmatch(string(collected))), matches, file)
 
 	for _, outcome := range outcomes {
 		if outcome.Fail != nil {
@@ -130,16 +190,21 @@ func analyzeRegexPattern(pattern string, collected []byte, outcomes []*troublesh
 				outcome.Fail.When = "false"
 			}
 
-			failWhen, err := strconv.ParseBool(outcome.Fail.When)
+			isMatch, err := compareRegexMatches(outcome.Fail.When, matches)
 			if err != nil {
 				return nil, errors.Wrapf(err, "failed to process when statement: %s", outcome.Fail.When)
 			}
 
-			if isMatch == failWhen {
+			if isMatch {
+				message, err := util.RenderTemplate(outcome.Fail.Message, templateData)
+				if err != nil {
+					return nil, errors.Wrap(err, "failed to template message in outcome.Fail block")
+				}
 				result.IsFail = true
 				result.IsWarn = false
-				result.Message = outcome.Fail.Message
+				result.Message = message
 				result.URI = outcome.Fail.URI
+				result.Remediation = outcome.Fail.Remediation
 			}
 		} else if outcome.Warn != nil {
 			// if the outcome.Warn.When is not set, default to false
@@ -147,15 +212,20 @@ func analyzeRegexPattern(pattern string, collected []byte, outcomes []*troublesh
 				outcome.Warn.When = "false"
 			}
 
-			warnWhen, err := strconv.ParseBool(outcome.Warn.When)
+			isMatch, err := compareRegexMatches(outcome.Warn.When, matches)
 			if err != nil {
 				return nil, errors.Wrapf(err, "failed to process when statement: %s", outcome.Warn.When)
 			}
 
-			if isMatch == warnWhen {
+			if isMatch {
+				message, err := util.RenderTemplate(outcome.Warn.Message, templateData)
+				if err != nil {
+					return nil, errors.Wrap(err, "failed to template message in outcome.Warn block")
+				}
 				result.IsWarn = true
-				result.Message = outcome.Warn.Message
+				result.Message = message
 				result.URI = outcome.Warn.URI
+				result.Remediation = outcome.Warn.Remediation
 			}
 		} else if outcome.Pass != nil {
 			// if the outcome.Pass.When is not set, default to true
@@ -163,22 +233,27 @@ func analyzeRegexPattern(pattern string, collected []byte, outcomes []*troublesh
 				outcome.Pass.When = "true"
 			}
 
-			passWhen, err := strconv.ParseBool(outcome.Pass.When)
+			isMatch, err := compareRegexMatches(outcome.Pass.When, matches)
 			if err != nil {
 				return nil, errors.Wrapf(err, "failed to process when statement: %s", outcome.Pass.When)
 			}
 
-			if isMatch == passWhen {
+			if isMatch {
+				message, err := util.RenderTemplate(outcome.Pass.Message, templateData)
+				if err != nil {
+					return nil, errors.Wrap(err, "failed to template message in outcome.Pass block")
+				}
 				result.IsPass = true
-				result.Message = outcome.Pass.Message
+				result.Message = message
 				result.URI = outcome.Pass.URI
+				result.Remediation = outcome.Pass.Remediation
 			}
 		}
 	}
 	return &result, nil
 }
 
-func analyzeRegexGroups(pattern string, collected []byte, outcomes []*troubleshootv1beta2.Outcome, checkName string) (*AnalyzeResult, error) {
+func analyzeRegexGroups(pattern string, collected []byte, outcomes []*troubleshootv1beta2.Outcome, checkName string, file string) (*AnalyzeResult, error) {
 	re, err := regexp.Compile(pattern)
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to compile regex: %s", pattern)
@@ -192,63 +267,63 @@ func analyzeRegexGroups(pattern string, collected []byte, outcomes []*troublesho
 		IconURI: "https://troubleshoot.sh/images/analyzer-icons/text-analyze.svg?w=13&h=16",
 	}
 
-	foundMatches := map[string]string{}
-	for i, name := range re.SubexpNames() {
-		if i != 0 && name != "" && len(match) > i {
-			foundMatches[name] = match[i]
-		}
-	}
+	foundMatches := regexNamedGroups(re, match)
+	matches := len(re.FindAllStringIndex(string(collected), -1))
+	templateData := regexTemplateData(foundMatches, matches, file)
 
 	// allow fallthrough
 	for _, outcome := range outcomes {
 		if outcome.Fail != nil {
-			isMatch, err := compareRegex(outcome.Fail.When, foundMatches)
+			isMatch, err := compareRegexGroups(outcome.Fail.When, foundMatches, matches)
 			if err != nil {
 				return result, errors.Wrap(err, "failed to compare regex fail conditional")
 			}
 
 			if isMatch {
 				result.IsFail = true
-				tplMessage, err := util.RenderTemplate(outcome.Fail.Message, foundMatches)
+				tplMessage, err := util.RenderTemplate(outcome.Fail.Message, templateData)
 				if err != nil {
 					return result, errors.Wrap(err, "failed to template message in outcome.Fail block")
 				}
 				result.Message = tplMessage
 				result.URI = outcome.Fail.URI
+				result.Remediation = outcome.Fail.Remediation
 
 				return result, nil
 			}
 		} else if outcome.Warn != nil {
-			isMatch, err := compareRegex(outcome.Warn.When, foundMatches)
+			isMatch, err := compareRegexGroups(outcome.Warn.When, foundMatches, matches)
 			if err != nil {
 				return result, errors.Wrap(err, "failed to compare regex warn conditional")
 			}
 
 			if isMatch {
 				result.IsWarn = true
-				tplMessage, err := util.RenderTemplate(outcome.Warn.Message, foundMatches)
+				tplMessage, err := util.RenderTemplate(outcome.Warn.Message, templateData)
 				if err != nil {
 					return result, errors.Wrap(err, "failed to template message in outcome.Warn block")
 				}
 				result.Message = tplMessage
 				result.URI = outcome.Warn.URI
+				result.Remediation = outcome.Warn.Remediation
 
 				return result, nil
 			}
 		} else if outcome.Pass != nil {
-			isMatch, err := compareRegex(outcome.Pass.When, foundMatches)
+			isMatch, err := compareRegexGroups(outcome.Pass.When, foundMatches, matches)
 			if err != nil {
 				return result, errors.Wrap(err, "failed to compare regex pass conditional")
 			}
 
 			if isMatch {
 				result.IsPass = true
-				tplMessage, err := util.RenderTemplate(outcome.Pass.Message, foundMatches)
+				tplMessage, err := util.RenderTemplate(outcome.Pass.Message, templateData)
 				if err != nil {
 					return result, errors.Wrap(err, "failed to template message in outcome.Pass block")
 				}
 				result.Message = tplMessage
 				result.URI = outcome.Pass.URI
+				result.Remediation = outcome.Pass.Remediation
 
 				return result, nil
 			}
@@ -258,6 +333,18 @@ func analyzeRegexGroups(pattern string, collected []byte, outcomes []*troublesho
 	return result, nil
 }
 
+// compareRegexGroups evaluates the when clause of an outcome of regex groups, which compares either
+// a capture group or, unless a group has that name, the number of matches, e.g. "matches >= 5"
+func compareRegexGroups(conditional string, foundMatches map[string]string, matches int) (bool, error) {
+	parts := strings.Fields(conditional)
+	if len(parts) == 3 && parts[0] == "matches" {
+		if _, ok := foundMatches["matches"]; !ok {
+			return compareActualToWhen(parts[1]+" "+parts[2], matches)
+		}
+	}
+	return compareRegex(conditional, foundMatches)
+}
+
 func compareRegex(conditional string, foundMatches map[string]string) (bool, error) {
 	if conditional == "" {
 		return true, nil
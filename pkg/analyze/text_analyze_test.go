@@ -908,3 +908,123 @@ func Test_NoFilesInBundle(t *testing.T) {
 	assert.Equal(t, "No matching files", actual[0].Message)
 	assert.True(t, actual[0].IsWarn)
 }
+
+func Test_textAnalyzeMatches(t *testing.T) {
+	files := map[string][]byte{
+		"api/api-1.log": []byte("level=info msg=started\nlevel=error msg=\"connection refused\" host=db-1\nlevel=error msg=timeout host=db-2\n"),
+		"api/api-2.log": []byte("level=info msg=started\n"),
+	}
+	getFiles := func(n string, excludeFiles []string) (map[string][]byte, error) {
+		matching := map[string][]byte{}
+		for k, v := range files {
+			if ok, _ := filepath.Match(n, k); ok {
+				matching[k] = v
+			}
+		}
+		return matching, nil
+	}
+
+	tests := []struct {
+		name     string
+		analyzer *troubleshootv1beta2.TextAnalyze
+		want     []string
+		wantFail []bool
+	}{
+		{
+			name: "count matches per file",
+			analyzer: &troubleshootv1beta2.TextAnalyze{
+				CollectorName: "api",
+				FileName:      "*.log",
+				RegexPattern:  `level=error`,
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{Fail: &troubleshootv1beta2.SingleOutcome{When: "matches >= 2", Message: "{{ .Matches }} errors in {{ .File }}"}},
+					{Pass: &troubleshootv1beta2.SingleOutcome{When: "matches < 2", Message: "{{ .Matches }} errors in {{ .File }}"}},
+				},
+			},
+			want:     []string{"2 errors in api/api-1.log", "0 errors in api/api-2.log"},
+			wantFail: []bool{true, false},
+		},
+		{
+			name: "capture groups in the message",
+			analyzer: &troubleshootv1beta2.TextAnalyze{
+				CollectorName: "api",
+				FileName:      "api-1.log",
+				RegexPattern:  `level=error msg="?(?P<Error>[^"]+?)"? host=(?P<Host>\S+)`,
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{Fail: &troubleshootv1beta2.SingleOutcome{When: "true", Message: "{{ .Error }} from {{ .Host }}"}},
+				},
+			},
+			want:     []string{"connection refused from db-1"},
+			wantFail: []bool{true},
+		},
+		{
+			name: "multiline",
+			analyzer: &troubleshootv1beta2.TextAnalyze{
+				CollectorName: "api",
+				FileName:      "api-1.log",
+				RegexPattern:  `^level=error`,
+				Multiline:     true,
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{Fail: &troubleshootv1beta2.SingleOutcome{When: "matches == 2", Message: "{{ .Matches }} errors"}},
+				},
+			},
+			want:     []string{"2 errors"},
+			wantFail: []bool{true},
+		},
+		{
+			name: "without multiline ^ only matches the start of the file",
+			analyzer: &troubleshootv1beta2.TextAnalyze{
+				CollectorName: "api",
+				FileName:      "api-1.log",
+				RegexPattern:  `^level=error`,
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{Pass: &troubleshootv1beta2.SingleOutcome{When: "false", Message: "no errors"}},
+				},
+			},
+			want:     []string{"no errors"},
+			wantFail: []bool{false},
+		},
+		{
+			name: "count matches of regex groups",
+			analyzer: &troubleshootv1beta2.TextAnalyze{
+				CollectorName: "api",
+				FileName:      "api-1.log",
+				RegexGroups:   `host=(?P<Host>\S+)`,
+				Outcomes: []*troubleshootv1beta2.Outcome{
+					{Fail: &troubleshootv1beta2.SingleOutcome{When: "matches > 1", Message: "{{ .Matches }} hosts failed, first {{ .Host }}"}},
+				},
+			},
+			want:     []string{"2 hosts failed, first db-1"},
+			wantFail: []bool{true},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			results, err := analyzeTextAnalyze(test.analyzer, getFiles, "errors")
+			require.NoError(t, err)
+			require.Len(t, results, len(test.want))
+			for i, result := range results {
+				assert.Equal(t, test.want[i], result.Message)
+				assert.Equal(t, test.wantFail[i], result.IsFail)
+			}
+		})
+	}
+}
+
+func Test_compareRegexMatches(t *testing.T) {
+	match, err := compareRegexMatches("true", 3)
+	require.NoError(t, err)
+	assert.True(t, match)
+
+	match, err = compareRegexMatches("false", 0)
+	require.NoError(t, err)
+	assert.True(t, match)
+
+	match, err = compareRegexMatches("matches >= 5", 4)
+	require.NoError(t, err)
+	assert.False(t, match)
+
+	_, err = compareRegexMatches("count > 1", 4)
+	assert.EqualError(t, err, `expected true, false or a number of matches, e.g. "matches >= 5", got "count > 1"`)
+}
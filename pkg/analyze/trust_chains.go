@@ -0,0 +1,365 @@
+package analyzer
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/collect"
+)
+
+// TrustChainsDefaultOutcomes are evaluated against each chain when the analyzer sets no outcomes
+var TrustChainsDefaultOutcomes = []*troubleshootv1beta2.Outcome{
+	{
+		Warn: &troubleshootv1beta2.SingleOutcome{
+			When:    "collected == false",
+			Message: "The certificates of {{ .Kind }} {{ .Name }} were not collected: {{ .Error }}",
+		},
+	},
+	{
+		Fail: &troubleshootv1beta2.SingleOutcome{
+			When:    "unknownCA == true",
+			Message: "The certificate of {{ .Kind }} {{ .Name }} does not lead to a trusted certificate authority, its chain ends at {{ .Root }}",
+		},
+	},
+	{
+		Fail: &troubleshootv1beta2.SingleOutcome{
+			When:    "expiredIntermediate == true",
+			Message: "The chain of {{ .Kind }} {{ .Name }} has expired certificate authorities: {{ .ExpiredIntermediates }}",
+		},
+	},
+	{
+		Warn: &troubleshootv1beta2.SingleOutcome{
+			When:    "sha1Signature == true",
+			Message: "The chain of {{ .Kind }} {{ .Name }} has certificates signed with SHA-1: {{ .SHA1Certificates }}",
+		},
+	},
+	{
+		Pass: &troubleshootv1beta2.SingleOutcome{
+			Message: "The certificate of {{ .Kind }} {{ .Name }} is trusted through {{ .Root }}",
+		},
+	},
+}
+
+// systemCertPool returns the roots of the system the chains of the ingresses and of the webhooks
+// without a caBundle are also trusted with
+var systemCertPool = x509.SystemCertPool
+
+// trustChainTemplateData is passed to the messages of the outcomes
+type trustChainTemplateData struct {
+	Kind     string
+	Name     string
+	Endpoint string
+	Error    string
+	// Subject, Issuer and NotAfter are of the leaf certificate
+	Subject  string
+	Issuer   string
+	NotAfter string
+	// Chain are the subjects of the certificates from the leaf to the last one found, and Root the
+	// subject of the last one
+	Chain string
+	Root  string
+	// ExpiredIntermediates and SHA1Certificates are subjects, separated by semicolons as subjects
+	// have commas
+	ExpiredIntermediates string
+	SHA1Certificates     string
+
+	collected           bool
+	unknownCA           bool
+	expiredIntermediate bool
+	sha1Signature       bool
+}
+
+type AnalyzeTrustChains struct {
+	analyzer *troubleshootv1beta2.TrustChainsAnalyze
+}
+
+func (a *AnalyzeTrustChains) Title() string {
+	if a.analyzer.CheckName != "" {
+		return a.analyzer.CheckName
+	}
+	return "Trust Chains"
+}
+
+func (a *AnalyzeTrustChains) IsExcluded() (bool, error) {
+	return isExcluded(a.analyzer.Exclude)
+}
+
+func (a *AnalyzeTrustChains) Analyze(getFile getCollectedFileContents, findFiles getChildCollectedFileContents) ([]*AnalyzeResult, error) {
+	fullPath := collect.TrustChainsOutputPath(a.analyzer.CollectorName)
+	collected, err := getFile(fullPath)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read collected file name: %s", fullPath)
+	}
+
+	info := collect.TrustChainsInfo{}
+	if err := json.Unmarshal(collected, &info); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse %s", fullPath)
+	}
+
+	clusterCA, err := parseCertificates(info.ClusterCA)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse the cluster CA")
+	}
+	trustedCAs, err := parseCertificates(a.analyzer.TrustedCAs)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse the trusted CAs")
+	}
+	systemRoots, err := systemCertPool()
+	if err != nil {
+		systemRoots = nil
+	}
+
+	outcomes := a.analyzer.Outcomes
+	if len(outcomes) == 0 {
+		outcomes = TrustChainsDefaultOutcomes
+	}
+
+	results := []*AnalyzeResult{}
+	for _, collectError := range info.Errors {
+		results = append(results, &AnalyzeResult{
+			Title:   a.Title(),
+			IsWarn:  true,
+			Message: collectError,
+			IconKey: "kubernetes_text_analyze",
+			IconURI: "https://troubleshoot.sh/images/analyzer-icons/text-analyze.svg",
+		})
+	}
+
+	now := time.Now()
+	for _, chain := range info.Chains {
+		anchors, useSystemRoots := trustAnchors(chain, clusterCA, trustedCAs)
+		roots := systemRoots
+		if !useSystemRoots {
+			roots = nil
+		}
+		data := analyzeTrustChain(chain, anchors, roots, now)
+
+		result, err := analyzeTemplatedOutcomes(a.Title(), a.analyzer.Strict.BoolOrDefaultFalse(), outcomes, data, func(when string) (bool, error) {
+			return compareTrustChainConditionalToActual(when, data)
+		})
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to analyze %s %s", chain.Kind, chain.Name)
+		}
+		if result != nil {
+			results = append(results, result)
+		}
+	}
+
+	return results, nil
+}
+
+// trustAnchors returns the certificates the clients of a chain trust, and whether they also trust
+// the roots of the system. The API server trusts the caBundle of a webhook, or the roots of the
+// system without one, the kubelets and the clients of the API server trust the cluster CA, and
+// the clients of an ingress are assumed to trust the roots of the system, the cluster CA and the
+// ca.crt of its secret.
+func trustAnchors(chain collect.TrustChain, clusterCA []*x509.Certificate, trustedCAs []*x509.Certificate) ([]*x509.Certificate, bool) {
+	anchors := append([]*x509.Certificate{}, trustedCAs...)
+	caBundle, _ := parseCertificates(chain.CABundle)
+
+	switch chain.Kind {
+	case collect.TrustChainKindWebhook:
+		return append(anchors, caBundle...), len(caBundle) == 0
+	case collect.TrustChainKindIngress:
+		anchors = append(anchors, clusterCA...)
+		return append(anchors, caBundle...), true
+	}
+	return append(anchors, clusterCA...), false
+}
+
+// analyzeTrustChain rebuilds the chain of the leaf certificate of a collected chain, from the
+// certificates presented with it and the anchors. Certificates signed with SHA-1 are linked to
+// their issuer, to be reported rather than break the chain as they do when verified by Go.
+func analyzeTrustChain(chain collect.TrustChain, anchors []*x509.Certificate, systemRoots *x509.CertPool, now time.Time) *trustChainTemplateData {
+	data := &trustChainTemplateData{
+		Kind:     chain.Kind,
+		Name:     chain.Name,
+		Endpoint: chain.Endpoint,
+		Error:    chain.Error,
+	}
+
+	presented, err := parseCertificates(chain.Certificates)
+	if err != nil && data.Error == "" {
+		data.Error = err.Error()
+	}
+	if len(presented) == 0 {
+		if data.Error == "" {
+			data.Error = "no certificate was found"
+		}
+		return data
+	}
+	data.collected = true
+
+	leaf := presented[0]
+	data.Subject = leaf.Subject.String()
+	data.Issuer = leaf.Issuer.String()
+	data.NotAfter = leaf.NotAfter.Format(time.RFC3339)
+
+	candidates := append(append([]*x509.Certificate{}, presented[1:]...), anchors...)
+	certs, anchored := buildTrustChain(leaf, candidates, anchors)
+	top := certs[len(certs)-1]
+	if !anchored && systemRoots != nil {
+		anchored = verifiedBySystemRoots(top, systemRoots)
+	}
+	data.unknownCA = !anchored
+
+	subjects := []string{}
+	expired := []string{}
+	sha1 := []string{}
+	for i, cert := range certs {
+		subjects = append(subjects, cert.Subject.String())
+		if i > 0 && (now.After(cert.NotAfter) || now.Before(cert.NotBefore)) {
+			expired = append(expired, cert.Subject.String())
+		}
+		// the signature of a self-signed root is not checked by clients
+		if isSHA1Signature(cert.SignatureAlgorithm) && !isSelfSigned(cert) {
+			sha1 = append(sha1, cert.Subject.String())
+		}
+	}
+	data.Chain = strings.Join(subjects, " -> ")
+	data.Root = top.Subject.String()
+	if !isSelfSigned(top) && !anchored {
+		data.Root = top.Issuer.String()
+	}
+	data.ExpiredIntermediates = strings.Join(expired, "; ")
+	data.expiredIntermediate = len(expired) > 0
+	data.SHA1Certificates = strings.Join(sha1, "; ")
+	data.sha1Signature = len(sha1) > 0
+
+	return data
+}
+
+// buildTrustChain follows the issuers of a certificate among the candidates, until it reaches
+// an anchor, a self-signed certificate or a certificate whose issuer is not a candidate
+func buildTrustChain(leaf *x509.Certificate, candidates []*x509.Certificate, anchors []*x509.Certificate) ([]*x509.Certificate, bool) {
+	chain := []*x509.Certificate{leaf}
+	current := leaf
+	// the length is bounded for chains with cycles, e.g. cross-signed certificates
+	for len(chain) <= len(candidates)+1 {
+		if containsCertificate(anchors, current) {
+			return chain, true
+		}
+		if isSelfSigned(current) {
+			return chain, false
+		}
+
+		issuer := findIssuer(current, candidates)
+		if issuer == nil || containsCertificate(chain, issuer) {
+			return chain, false
+		}
+		chain = append(chain, issuer)
+		current = issuer
+	}
+	return chain, false
+}
+
+func findIssuer(cert *x509.Certificate, candidates []*x509.Certificate) *x509.Certificate {
+	for _, candidate := range candidates {
+		if !bytes.Equal(candidate.RawSubject, cert.RawIssuer) {
+			continue
+		}
+		if len(cert.AuthorityKeyId) > 0 && len(candidate.SubjectKeyId) > 0 && !bytes.Equal(cert.AuthorityKeyId, candidate.SubjectKeyId) {
+			continue
+		}
+		if isSignedBy(cert, candidate) {
+			return candidate
+		}
+	}
+	return nil
+}
+
+// isSignedBy returns true when the signature of a certificate is of the issuer, including with an
+// algorithm Go considers insecure, e.g. SHA-1
+func isSignedBy(cert *x509.Certificate, issuer *x509.Certificate) bool {
+	err := cert.CheckSignatureFrom(issuer)
+	if err == nil {
+		return true
+	}
+	var insecure x509.InsecureAlgorithmError
+	return errors.As(err, &insecure)
+}
+
+func isSelfSigned(cert *x509.Certificate) bool {
+	return bytes.Equal(cert.RawSubject, cert.RawIssuer) && isSignedBy(cert, cert)
+}
+
+func isSHA1Signature(algorithm x509.SignatureAlgorithm) bool {
+	switch algorithm {
+	case x509.SHA1WithRSA, x509.DSAWithSHA1, x509.ECDSAWithSHA1:
+		return true
+	}
+	return false
+}
+
+func containsCertificate(certs []*x509.Certificate, cert *x509.Certificate) bool {
+	for _, c := range certs {
+		if c.Equal(cert) {
+			return true
+		}
+	}
+	return false
+}
+
+// verifiedBySystemRoots returns true when a certificate is issued by a root of the system. It is
+// verified at a time it is valid for its expiry to be reported separately.
+func verifiedBySystemRoots(cert *x509.Certificate, roots *x509.CertPool) bool {
+	_, err := cert.Verify(x509.VerifyOptions{
+		Roots:       roots,
+		CurrentTime: cert.NotBefore.Add(cert.NotAfter.Sub(cert.NotBefore) / 2),
+		KeyUsages:   []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	})
+	return err == nil
+}
+
+// parseCertificates returns the certificates of PEM data, skipping the blocks of other types
+func parseCertificates(data string) ([]*x509.Certificate, error) {
+	certs := []*x509.Certificate{}
+	rest := []byte(data)
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return certs, errors.Wrap(err, "failed to parse certificate")
+		}
+		certs = append(certs, cert)
+	}
+	return certs, nil
+}
+
+// compareTrustChainConditionalToActual evaluates a when clause against a chain. Supported
+// conditions are collected, the certificates were collected, unknownCA, the chain does not lead
+// to a trusted certificate authority, expiredIntermediate, a certificate authority of the chain is
+// expired, and sha1Signature, a certificate of the chain is signed with SHA-1, compared to true or
+// false, e.g. "unknownCA == true".
+func compareTrustChainConditionalToActual(conditional string, data *trustChainTemplateData) (bool, error) {
+	parts := strings.Fields(conditional)
+	if len(parts) != 3 {
+		return false, fmt.Errorf("expected 3 parts in when %q, got %d", conditional, len(parts))
+	}
+
+	switch parts[0] {
+	case "collected":
+		return compareBoolConditional(parts[1], parts[2], data.collected)
+	case "unknownCA":
+		return compareBoolConditional(parts[1], parts[2], data.collected && data.unknownCA)
+	case "expiredIntermediate":
+		return compareBoolConditional(parts[1], parts[2], data.expiredIntermediate)
+	case "sha1Signature":
+		return compareBoolConditional(parts[1], parts[2], data.sha1Signature)
+	}
+	return false, errors.Errorf("unknown condition %q, must be one of collected, unknownCA, expiredIntermediate or sha1Signature", parts[0])
+}
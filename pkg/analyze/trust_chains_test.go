@@ -0,0 +1,189 @@
+package analyzer
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/collect"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testCertificate struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  string
+}
+
+// newTestCertificate returns a certificate of the name signed by the parent, self-signed without
+// one, valid from notBefore for a day
+func newTestCertificate(t *testing.T, name string, parent *testCertificate, notBefore time.Time) *testCertificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             notBefore,
+		NotAfter:              notBefore.Add(24 * time.Hour),
+		IsCA:                  parent == nil || strings.HasSuffix(name, "CA"),
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+	}
+	issuer, signer := template, key
+	if parent != nil {
+		issuer, signer = parent.cert, parent.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, issuer, &key.PublicKey, signer)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return &testCertificate{
+		cert: cert,
+		key:  key,
+		pem:  string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+	}
+}
+
+func TestAnalyzeTrustChains(t *testing.T) {
+	now := time.Now().Add(-time.Hour)
+	clusterCA := newTestCertificate(t, "kubernetes CA", nil, now)
+	otherCA := newTestCertificate(t, "other CA", nil, now)
+	intermediateCA := newTestCertificate(t, "intermediate CA", clusterCA, now)
+	expiredCA := newTestCertificate(t, "expired CA", clusterCA, now.Add(-48*time.Hour))
+
+	apiServer := newTestCertificate(t, "kube-apiserver", clusterCA, now)
+	kubelet := newTestCertificate(t, "node-1", intermediateCA, now)
+	webhook := newTestCertificate(t, "webhook.default.svc", otherCA, now)
+	ingress := newTestCertificate(t, "app.example.com", expiredCA, now)
+
+	origSystemCertPool := systemCertPool
+	defer func() { systemCertPool = origSystemCertPool }()
+	systemCertPool = func() (*x509.CertPool, error) { return x509.NewCertPool(), nil }
+
+	info := collect.TrustChainsInfo{
+		ClusterCA: clusterCA.pem,
+		Chains: []collect.TrustChain{
+			{Kind: collect.TrustChainKindAPIServer, Name: "kubernetes", Certificates: apiServer.pem},
+			{Kind: collect.TrustChainKindKubelet, Name: "node-1", Certificates: kubelet.pem + intermediateCA.pem},
+			{Kind: collect.TrustChainKindWebhook, Name: "validate-pods", Certificates: webhook.pem, CABundle: clusterCA.pem},
+			{Kind: collect.TrustChainKindIngress, Name: "default/app", Certificates: ingress.pem + expiredCA.pem},
+			{Kind: collect.TrustChainKindKubelet, Name: "node-2", Error: "connection refused"},
+		},
+		Errors: []string{"failed to list ingresses: forbidden"},
+	}
+	data, err := json.Marshal(info)
+	require.NoError(t, err)
+
+	a := AnalyzeTrustChains{analyzer: &troubleshootv1beta2.TrustChainsAnalyze{}}
+	results, err := a.Analyze(func(path string) ([]byte, error) {
+		require.Equal(t, "trust-chains/trust-chains.json", path)
+		return data, nil
+	}, nil)
+	require.NoError(t, err)
+	require.Len(t, results, 6)
+
+	assert.True(t, results[0].IsWarn)
+	assert.Equal(t, "failed to list ingresses: forbidden", results[0].Message)
+
+	assert.True(t, results[1].IsPass)
+	assert.Equal(t, "The certificate of apiServer kubernetes is trusted through CN=kubernetes CA", results[1].Message)
+
+	assert.True(t, results[2].IsPass)
+	assert.Equal(t, "The certificate of kubelet node-1 is trusted through CN=kubernetes CA", results[2].Message)
+
+	assert.True(t, results[3].IsFail)
+	assert.Equal(t, "The certificate of webhook validate-pods does not lead to a trusted certificate authority, its chain ends at CN=other CA", results[3].Message)
+
+	assert.True(t, results[4].IsFail)
+	assert.Equal(t, "The chain of ingress default/app has expired certificate authorities: CN=expired CA", results[4].Message)
+
+	assert.True(t, results[5].IsWarn)
+	assert.Equal(t, "The certificates of kubelet node-2 were not collected: connection refused", results[5].Message)
+
+	// the other CA is trusted once configured
+	a.analyzer.TrustedCAs = otherCA.pem
+	results, err = a.Analyze(func(string) ([]byte, error) { return data, nil }, nil)
+	require.NoError(t, err)
+	assert.True(t, results[3].IsPass)
+}
+
+func Test_analyzeTrustChain_sha1(t *testing.T) {
+	caKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ca := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "legacy CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, ca, ca, &caKey.PublicKey, caKey)
+	require.NoError(t, err)
+	ca, err = x509.ParseCertificate(caDER)
+	require.NoError(t, err)
+
+	leafKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	leaf := &x509.Certificate{
+		SerialNumber:       big.NewInt(2),
+		Subject:            pkix.Name{CommonName: "legacy.example.com"},
+		NotBefore:          time.Now().Add(-time.Hour),
+		NotAfter:           time.Now().Add(time.Hour),
+		SignatureAlgorithm: x509.SHA1WithRSA,
+	}
+	leafDER, err := x509.CreateCertificate(rand.Reader, leaf, ca, &leafKey.PublicKey, caKey)
+	if err != nil {
+		t.Skipf("SHA-1 certificates cannot be created: %v", err)
+	}
+
+	chain := collect.TrustChain{
+		Kind:         collect.TrustChainKindIngress,
+		Name:         "default/legacy",
+		Certificates: string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: leafDER})),
+	}
+	data := analyzeTrustChain(chain, []*x509.Certificate{ca}, nil, time.Now())
+
+	assert.True(t, data.collected)
+	assert.False(t, data.unknownCA)
+	assert.False(t, data.expiredIntermediate)
+	assert.True(t, data.sha1Signature)
+	assert.Equal(t, "CN=legacy.example.com", data.SHA1Certificates)
+	assert.Equal(t, "CN=legacy.example.com -> CN=legacy CA", data.Chain)
+}
+
+func Test_compareTrustChainConditionalToActual(t *testing.T) {
+	data := &trustChainTemplateData{collected: true, unknownCA: true}
+
+	got, err := compareTrustChainConditionalToActual("unknownCA == true", data)
+	require.NoError(t, err)
+	assert.True(t, got)
+
+	got, err = compareTrustChainConditionalToActual("sha1Signature == true", data)
+	require.NoError(t, err)
+	assert.False(t, got)
+
+	// a chain that was not collected is not reported as unknown
+	got, err = compareTrustChainConditionalToActual("unknownCA == true", &trustChainTemplateData{unknownCA: true})
+	require.NoError(t, err)
+	assert.False(t, got)
+
+	_, err = compareTrustChainConditionalToActual("selfSigned == true", data)
+	assert.Error(t, err)
+	_, err = compareTrustChainConditionalToActual("unknownCA", data)
+	assert.Error(t, err)
+}
@@ -0,0 +1,188 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/collect"
+	velerov1 "github.com/vmware-tanzu/velero/pkg/apis/velero/v1"
+)
+
+// VeleroReadinessDefaultOutcomes are evaluated when the analyzer sets no outcomes
+var VeleroReadinessDefaultOutcomes = []*troubleshootv1beta2.Outcome{
+	{
+		Fail: &troubleshootv1beta2.SingleOutcome{
+			When:    "storageLocations == 0",
+			Message: "No Velero backup storage location was found in namespace {{ .Namespace }}",
+		},
+	},
+	{
+		Fail: &troubleshootv1beta2.SingleOutcome{
+			When:    "unavailableStorageLocations > 0",
+			Message: "Velero backup storage locations are unavailable: {{ .UnavailableStorageLocations }}",
+		},
+	},
+	{
+		Fail: &troubleshootv1beta2.SingleOutcome{
+			When:    "failedSchedules > 0",
+			Message: "The last backup of Velero schedules failed: {{ .FailedSchedules }}",
+		},
+	},
+	{
+		Pass: &troubleshootv1beta2.SingleOutcome{
+			Message: "Velero backup storage locations are available and the last scheduled backups succeeded",
+		},
+	},
+}
+
+// veleroReadinessTemplateData is passed to the messages of the outcomes
+type veleroReadinessTemplateData struct {
+	Namespace string
+	Version   string
+	// UnavailableStorageLocations, FailedSchedules, FailedBackups and FailedRestores are
+	// the names of the resources, comma separated
+	UnavailableStorageLocations string
+	FailedSchedules             string
+	FailedBackups               string
+	FailedRestores              string
+}
+
+type AnalyzeVeleroReadiness struct {
+	analyzer *troubleshootv1beta2.VeleroReadinessAnalyze
+}
+
+func (a *AnalyzeVeleroReadiness) Title() string {
+	if a.analyzer.CheckName != "" {
+		return a.analyzer.CheckName
+	}
+	return "Velero Readiness"
+}
+
+func (a *AnalyzeVeleroReadiness) IsExcluded() (bool, error) {
+	return isExcluded(a.analyzer.Exclude)
+}
+
+func (a *AnalyzeVeleroReadiness) Analyze(getFile getCollectedFileContents, findFiles getChildCollectedFileContents) ([]*AnalyzeResult, error) {
+	fullPath := collect.VeleroOutputPath(a.analyzer.CollectorName)
+	collected, err := getFile(fullPath)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read collected file name: %s", fullPath)
+	}
+
+	info := collect.VeleroInfo{}
+	if err := json.Unmarshal(collected, &info); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse %s", fullPath)
+	}
+
+	readiness := newVeleroReadiness(info)
+	data := &veleroReadinessTemplateData{
+		Namespace:                   info.Namespace,
+		Version:                     info.Version,
+		UnavailableStorageLocations: strings.Join(readiness.unavailableStorageLocations, ", "),
+		FailedSchedules:             strings.Join(readiness.failedSchedules, ", "),
+		FailedBackups:               strings.Join(readiness.failedBackups, ", "),
+		FailedRestores:              strings.Join(readiness.failedRestores, ", "),
+	}
+
+	outcomes := a.analyzer.Outcomes
+	if len(outcomes) == 0 {
+		outcomes = VeleroReadinessDefaultOutcomes
+	}
+
+	result, err := analyzeTemplatedOutcomes(a.Title(), a.analyzer.Strict.BoolOrDefaultFalse(), outcomes, data, func(when string) (bool, error) {
+		parts := strings.Fields(when)
+		if len(parts) != 3 {
+			return false, fmt.Errorf("expected 3 parts in when %q, got %d", when, len(parts))
+		}
+		return readiness.compare(parts[0], parts[1], parts[2])
+	})
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		return []*AnalyzeResult{}, nil
+	}
+	return []*AnalyzeResult{result}, nil
+}
+
+// veleroReadiness are the names of the collected Velero resources that are not ready
+type veleroReadiness struct {
+	storageLocations            int
+	unavailableStorageLocations []string
+	failedSchedules             []string
+	failedBackups               []string
+	failedRestores              []string
+}
+
+// newVeleroReadiness returns the storage locations that are not available, the schedules that
+// are not paused and whose last backup failed, and the recent backups and restores that failed
+func newVeleroReadiness(info collect.VeleroInfo) veleroReadiness {
+	readiness := veleroReadiness{storageLocations: len(info.BackupStorageLocations)}
+	for _, location := range info.BackupStorageLocations {
+		if location.Phase != string(velerov1.BackupStorageLocationPhaseAvailable) {
+			readiness.unavailableStorageLocations = append(readiness.unavailableStorageLocations, location.Name)
+		}
+	}
+	for _, schedule := range info.Schedules {
+		if !schedule.Paused && schedule.LastBackup != nil && veleroPhaseFailed(schedule.LastBackup.Phase) {
+			readiness.failedSchedules = append(readiness.failedSchedules, schedule.Name)
+		}
+	}
+	for _, backup := range info.Backups {
+		if veleroPhaseFailed(backup.Phase) {
+			readiness.failedBackups = append(readiness.failedBackups, backup.Name)
+		}
+	}
+	for _, restore := range info.Restores {
+		if veleroPhaseFailed(restore.Phase) {
+			readiness.failedRestores = append(readiness.failedRestores, restore.Name)
+		}
+	}
+	return readiness
+}
+
+// compare evaluates a condition against the readiness. Supported conditions compare a number of
+// resources:
+//
+//   - "storageLocations <operator> <n>", the backup storage locations
+//   - "unavailableStorageLocations <operator> <n>", the backup storage locations that are not available
+//   - "failedSchedules <operator> <n>", the schedules that are not paused and whose last backup failed
+//   - "failedBackups <operator> <n>", the recent backups that failed
+//   - "failedRestores <operator> <n>", the recent restores that failed
+func (r veleroReadiness) compare(condition string, operator string, value string) (bool, error) {
+	var observed int
+	switch condition {
+	case "storageLocations":
+		observed = r.storageLocations
+	case "unavailableStorageLocations":
+		observed = len(r.unavailableStorageLocations)
+	case "failedSchedules":
+		observed = len(r.failedSchedules)
+	case "failedBackups":
+		observed = len(r.failedBackups)
+	case "failedRestores":
+		observed = len(r.failedRestores)
+	default:
+		return false, fmt.Errorf("unsupported velero readiness condition %q", condition)
+	}
+
+	threshold, err := strconv.Atoi(value)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to parse %q", value)
+	}
+	return compareFloat(float64(observed), operator, float64(threshold))
+}
+
+// veleroPhaseFailed returns true for the phases of a backup or a restore that did not complete
+// successfully
+func veleroPhaseFailed(phase string) bool {
+	switch phase {
+	case string(velerov1.BackupPhaseFailed), string(velerov1.BackupPhasePartiallyFailed), string(velerov1.BackupPhaseFailedValidation):
+		return true
+	}
+	return false
+}
@@ -0,0 +1,116 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"testing"
+
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/collect"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyzeVeleroReadiness(t *testing.T) {
+	healthy := collect.VeleroInfo{
+		Namespace: "velero",
+		BackupStorageLocations: []collect.VeleroBackupStorageLocation{
+			{Name: "default", Provider: "aws", Phase: "Available"},
+		},
+		Schedules: []collect.VeleroSchedule{
+			{Name: "daily", LastBackup: &collect.VeleroBackup{Name: "daily-2", Phase: "Completed"}},
+			{Name: "weekly", Paused: true, LastBackup: &collect.VeleroBackup{Name: "weekly-1", Phase: "Failed"}},
+		},
+		Backups: []collect.VeleroBackup{
+			{Name: "daily-2", Phase: "Completed"},
+			{Name: "manual", Phase: "PartiallyFailed"},
+		},
+	}
+	unavailable := collect.VeleroInfo{
+		Namespace: "velero",
+		BackupStorageLocations: []collect.VeleroBackupStorageLocation{
+			{Name: "default", Provider: "aws", Phase: "Available"},
+			{Name: "secondary", Provider: "gcp", Phase: "Unavailable"},
+		},
+		Schedules: []collect.VeleroSchedule{
+			{Name: "daily", LastBackup: &collect.VeleroBackup{Name: "daily-2", Phase: "FailedValidation"}},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		info     collect.VeleroInfo
+		outcomes []*troubleshootv1beta2.Outcome
+		want     *AnalyzeResult
+		wantErr  string
+	}{
+		{
+			name: "default outcomes pass",
+			info: healthy,
+			want: &AnalyzeResult{IsPass: true, Message: "Velero backup storage locations are available and the last scheduled backups succeeded"},
+		},
+		{
+			name: "default outcomes without storage location",
+			info: collect.VeleroInfo{Namespace: "velero"},
+			want: &AnalyzeResult{IsFail: true, Message: "No Velero backup storage location was found in namespace velero"},
+		},
+		{
+			name: "default outcomes with unavailable storage location",
+			info: unavailable,
+			want: &AnalyzeResult{IsFail: true, Message: "Velero backup storage locations are unavailable: secondary"},
+		},
+		{
+			name: "failed schedule",
+			info: unavailable,
+			outcomes: []*troubleshootv1beta2.Outcome{
+				{Fail: &troubleshootv1beta2.SingleOutcome{When: "failedSchedules > 0", Message: "Failed schedules: {{ .FailedSchedules }}"}},
+				{Pass: &troubleshootv1beta2.SingleOutcome{Message: "ok"}},
+			},
+			want: &AnalyzeResult{IsFail: true, Message: "Failed schedules: daily"},
+		},
+		{
+			name: "failed backup",
+			info: healthy,
+			outcomes: []*troubleshootv1beta2.Outcome{
+				{Warn: &troubleshootv1beta2.SingleOutcome{When: "failedBackups >= 1", Message: "Failed backups: {{ .FailedBackups }}"}},
+				{Pass: &troubleshootv1beta2.SingleOutcome{Message: "ok"}},
+			},
+			want: &AnalyzeResult{IsWarn: true, Message: "Failed backups: manual"},
+		},
+		{
+			name: "unsupported condition",
+			info: healthy,
+			outcomes: []*troubleshootv1beta2.Outcome{
+				{Fail: &troubleshootv1beta2.SingleOutcome{When: "backups > 0", Message: "fail"}},
+			},
+			wantErr: `unsupported velero readiness condition "backups"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			collected, err := json.Marshal(tt.info)
+			require.NoError(t, err)
+
+			a := &AnalyzeVeleroReadiness{analyzer: &troubleshootv1beta2.VeleroReadinessAnalyze{Outcomes: tt.outcomes}}
+			getFile := func(path string) ([]byte, error) {
+				assert.Equal(t, "velero/velero.json", path)
+				return collected, nil
+			}
+
+			results, err := a.Analyze(getFile, nil)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, results, 1)
+
+			assert.Equal(t, tt.want.IsPass, results[0].IsPass)
+			assert.Equal(t, tt.want.IsWarn, results[0].IsWarn)
+			assert.Equal(t, tt.want.IsFail, results[0].IsFail)
+			assert.Equal(t, "Velero Readiness", results[0].Title)
+			assert.Equal(t, tt.want.Message, results[0].Message)
+		})
+	}
+}
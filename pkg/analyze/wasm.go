@@ -0,0 +1,207 @@
+package analyzer
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/oci"
+	"github.com/replicatedhq/troubleshoot/pkg/plugin"
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/imports/wasi_snapshot_preview1"
+	"github.com/tetratelabs/wazero/sys"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+const (
+	defaultWasmMemoryLimit = 64 << 20
+	defaultWasmTimeout     = 30 * time.Second
+	// maxWasmOutputSize is the largest response a module can write to stdout, 16MiB.
+	maxWasmOutputSize = 16 << 20
+	maxWasmStderrSize = 4 << 10
+	wasmPageSize      = 64 << 10
+	// wasmMaxPages is the most memory a 32-bit module can address, 4GiB.
+	wasmMaxPages = 1 << 16
+)
+
+// AnalyzeWasm runs a WASM module as a WASI command. It gets the same request on stdin, and
+// writes the same response to stdout, as analyzer plugins, see pkg/plugin. The module has no
+// filesystem, network, environment or real clock, and its memory and run time are limited.
+type AnalyzeWasm struct {
+	analyzer *troubleshootv1beta2.WasmAnalyze
+}
+
+func (a *AnalyzeWasm) Title() string {
+	if a.analyzer.CheckName != "" {
+		return a.analyzer.CheckName
+	}
+	if a.analyzer.CollectorName != "" {
+		return a.analyzer.CollectorName
+	}
+	return "WASM Analyzer"
+}
+
+func (a *AnalyzeWasm) IsExcluded() (bool, error) {
+	return isExcluded(a.analyzer.Exclude)
+}
+
+func (a *AnalyzeWasm) Analyze(getFile getCollectedFileContents, findFiles getChildCollectedFileContents) ([]*AnalyzeResult, error) {
+	ctx := context.TODO()
+
+	memoryLimit, timeout, err := parseWasmLimits(a.analyzer.Limits)
+	if err != nil {
+		return nil, err
+	}
+
+	module, err := loadWasmModule(ctx, a.analyzer)
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := findBundleFiles(findFiles, filepath.Join(a.analyzer.CollectorName, a.analyzer.FileName))
+	if err != nil {
+		return nil, err
+	}
+
+	input, err := json.Marshal(plugin.Request{
+		ProtocolVersion: plugin.ProtocolVersion,
+		Command:         plugin.CommandAnalyze,
+		Config:          a.analyzer.Config,
+		Files:           files,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal module request")
+	}
+
+	output, err := runWasmModule(ctx, module, input, memoryLimit, timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	response := plugin.AnalyzeResponse{}
+	if err := json.Unmarshal(output, &response); err != nil {
+		return nil, errors.Wrap(err, "failed to decode output of module")
+	}
+
+	return pluginResults(response, a.Title()), nil
+}
+
+func parseWasmLimits(limits *troubleshootv1beta2.WasmLimits) (int64, time.Duration, error) {
+	memoryLimit := int64(defaultWasmMemoryLimit)
+	timeout := defaultWasmTimeout
+	if limits == nil {
+		return memoryLimit, timeout, nil
+	}
+
+	if limits.Memory != "" {
+		quantity, err := resource.ParseQuantity(limits.Memory)
+		if err != nil {
+			return 0, 0, errors.Wrapf(err, "failed to parse memory limit %q", limits.Memory)
+		}
+		memoryLimit = quantity.Value()
+		if memoryLimit < wasmPageSize || memoryLimit > wasmMaxPages*wasmPageSize {
+			return 0, 0, errors.Errorf("memory limit %q must be between 64Ki and 4Gi", limits.Memory)
+		}
+	}
+
+	if limits.Timeout != "" {
+		d, err := time.ParseDuration(limits.Timeout)
+		if err != nil {
+			return 0, 0, errors.Wrapf(err, "failed to parse timeout %q", limits.Timeout)
+		}
+		timeout = d
+	}
+
+	return memoryLimit, timeout, nil
+}
+
+func loadWasmModule(ctx context.Context, analyzer *troubleshootv1beta2.WasmAnalyze) ([]byte, error) {
+	switch {
+	case analyzer.Module != "" && analyzer.Image != "":
+		return nil, errors.New("only one of module and image can be set")
+	case analyzer.Module != "":
+		module, err := base64.StdEncoding.DecodeString(analyzer.Module)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to decode module")
+		}
+		return module, nil
+	case analyzer.Image != "":
+		module, err := oci.PullWasmModuleFromOCI(ctx, analyzer.Image)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to pull module %s", analyzer.Image)
+		}
+		return module, nil
+	default:
+		return nil, errors.New("module or image is required")
+	}
+}
+
+// runWasmModule runs the _start function of the module with input on stdin and returns what
+// it wrote to stdout.
+func runWasmModule(ctx context.Context, module []byte, input []byte, memoryLimit int64, timeout time.Duration) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	runtimeConfig := wazero.NewRuntimeConfig().
+		WithMemoryLimitPages(uint32(memoryLimit / wasmPageSize)).
+		WithCloseOnContextDone(true)
+	runtime := wazero.NewRuntimeWithConfig(ctx, runtimeConfig)
+	defer runtime.Close(context.Background())
+
+	if _, err := wasi_snapshot_preview1.Instantiate(ctx, runtime); err != nil {
+		return nil, errors.Wrap(err, "failed to instantiate WASI")
+	}
+
+	compiled, err := runtime.CompileModule(ctx, module)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to compile module")
+	}
+
+	stdout := &limitedOutput{max: maxWasmOutputSize}
+	stderr := &limitedOutput{max: maxWasmStderrSize}
+	// modules get no filesystem, environment or arguments, and fake clocks, unless configured
+	moduleConfig := wazero.NewModuleConfig().
+		WithStdin(bytes.NewReader(input)).
+		WithStdout(stdout).
+		WithStderr(stderr)
+
+	_, err = runtime.InstantiateModule(ctx, compiled, moduleConfig)
+	if ctx.Err() == context.DeadlineExceeded {
+		return nil, errors.Errorf("module timed out after %s", timeout)
+	}
+	var exitErr *sys.ExitError
+	if err != nil && !(errors.As(err, &exitErr) && exitErr.ExitCode() == 0) {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, errors.Wrapf(err, "module failed: %s", msg)
+		}
+		return nil, errors.Wrap(err, "module failed")
+	}
+
+	if stdout.truncated {
+		return nil, errors.Errorf("module output is larger than %d bytes", maxWasmOutputSize)
+	}
+	return stdout.Bytes(), nil
+}
+
+// limitedOutput keeps the first max bytes written to it and records whether more were written.
+type limitedOutput struct {
+	bytes.Buffer
+	max       int
+	truncated bool
+}
+
+func (o *limitedOutput) Write(p []byte) (int, error) {
+	n := len(p)
+	if remaining := o.max - o.Len(); n > remaining {
+		o.truncated = true
+		p = p[:max(remaining, 0)]
+	}
+	o.Buffer.Write(p)
+	return n, nil
+}
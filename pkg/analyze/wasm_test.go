@@ -0,0 +1,115 @@
+package analyzer
+
+import (
+	"context"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/replicatedhq/troubleshoot/internal/testutils"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func readWasmModule(t *testing.T, name string) []byte {
+	t.Helper()
+
+	module, err := os.ReadFile(filepath.Join(testutils.FileDir(), "../../testdata/wasm", name))
+	require.NoError(t, err)
+	return module
+}
+
+func TestAnalyzeWasm_Analyze(t *testing.T) {
+	a := &AnalyzeWasm{
+		analyzer: &troubleshootv1beta2.WasmAnalyze{
+			CollectorName: "queues",
+			FileName:      "*.json",
+			Module:        base64.StdEncoding.EncodeToString(readWasmModule(t, "result.wasm")),
+			Limits:        &troubleshootv1beta2.WasmLimits{Memory: "1Mi", Timeout: "5s"},
+		},
+	}
+	findFiles := func(pattern string, excluded []string) (map[string][]byte, error) {
+		assert.Equal(t, "queues/*.json", pattern)
+		return map[string][]byte{"queues/orders.json": []byte(`{"depth":1000}`)}, nil
+	}
+
+	results, err := a.Analyze(nil, findFiles)
+	require.NoError(t, err)
+	assert.Equal(t, []*AnalyzeResult{{Title: "Queue depth", IsWarn: true, Message: "Queue is filling up"}}, results)
+}
+
+func Test_runWasmModule(t *testing.T) {
+	output, err := runWasmModule(context.Background(), readWasmModule(t, "echo.wasm"), []byte(`{"command":"analyze"}`), defaultWasmMemoryLimit, time.Second)
+	require.NoError(t, err)
+	assert.Equal(t, `{"command":"analyze"}`, string(output))
+
+	_, err = runWasmModule(context.Background(), readWasmModule(t, "loop.wasm"), nil, defaultWasmMemoryLimit, 100*time.Millisecond)
+	assert.EqualError(t, err, "module timed out after 100ms")
+
+	_, err = runWasmModule(context.Background(), []byte("not a module"), nil, defaultWasmMemoryLimit, time.Second)
+	assert.ErrorContains(t, err, "failed to compile module")
+}
+
+func Test_parseWasmLimits(t *testing.T) {
+	tests := []struct {
+		name        string
+		limits      *troubleshootv1beta2.WasmLimits
+		wantMemory  int64
+		wantTimeout time.Duration
+		wantErr     bool
+	}{
+		{
+			name:        "defaults",
+			wantMemory:  defaultWasmMemoryLimit,
+			wantTimeout: defaultWasmTimeout,
+		},
+		{
+			name:        "set",
+			limits:      &troubleshootv1beta2.WasmLimits{Memory: "256Mi", Timeout: "1m"},
+			wantMemory:  256 << 20,
+			wantTimeout: time.Minute,
+		},
+		{
+			name:    "memory smaller than a page",
+			limits:  &troubleshootv1beta2.WasmLimits{Memory: "1Ki"},
+			wantErr: true,
+		},
+		{
+			name:    "memory larger than addressable",
+			limits:  &troubleshootv1beta2.WasmLimits{Memory: "5Gi"},
+			wantErr: true,
+		},
+		{
+			name:    "invalid timeout",
+			limits:  &troubleshootv1beta2.WasmLimits{Timeout: "soon"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			memory, timeout, err := parseWasmLimits(tt.limits)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantMemory, memory)
+			assert.Equal(t, tt.wantTimeout, timeout)
+		})
+	}
+}
+
+func Test_loadWasmModule(t *testing.T) {
+	_, err := loadWasmModule(context.Background(), &troubleshootv1beta2.WasmAnalyze{})
+	assert.EqualError(t, err, "module or image is required")
+
+	_, err = loadWasmModule(context.Background(), &troubleshootv1beta2.WasmAnalyze{Module: "AGFzbQ==", Image: "oci://registry.example.com/analyzer"})
+	assert.EqualError(t, err, "only one of module and image can be set")
+
+	module, err := loadWasmModule(context.Background(), &troubleshootv1beta2.WasmAnalyze{Module: "AGFzbQ=="})
+	require.NoError(t, err)
+	assert.Equal(t, []byte("\x00asm"), module)
+}
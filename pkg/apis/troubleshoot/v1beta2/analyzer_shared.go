@@ -11,6 +11,14 @@ type ClusterVersion struct {
 	Outcomes    []*Outcome `json:"outcomes" yaml:"outcomes"`
 }
 
+// KubernetesUpgrade checks that the cluster can be upgraded to TargetVersion, a minor version
+// such as "1.30", by default the minor version after that of the cluster.
+type KubernetesUpgrade struct {
+	AnalyzeMeta   `json:",inline" yaml:",inline"`
+	TargetVersion string     `json:"targetVersion,omitempty" yaml:"targetVersion,omitempty"`
+	Outcomes      []*Outcome `json:"outcomes,omitempty" yaml:"outcomes,omitempty"`
+}
+
 type StorageClass struct {
 	AnalyzeMeta      `json:",inline" yaml:",inline"`
 	Outcomes         []*Outcome `json:"outcomes" yaml:"outcomes"`
@@ -149,12 +157,18 @@ type NodeResourceSelectors struct {
 	MatchExpressions []metav1.LabelSelectorRequirement `json:"matchExpressions,omitempty" yaml:"matchExpressions,omitempty"`
 }
 
+// TextAnalyze matches a regex against the files matching FileName, a glob, in the directory of the
+// collector, with a result per file. The when clauses of the outcomes are true or false, or compare
+// the number of matches, e.g. "matches >= 5", and the messages can use the named capture groups of
+// the first match, the number of matches as {{ .Matches }} and the file as {{ .File }}. With
+// Multiline, ^ and $ match at the start and end of each line.
 type TextAnalyze struct {
 	AnalyzeMeta     `json:",inline" yaml:",inline"`
 	CollectorName   string     `json:"collectorName,omitempty" yaml:"collectorName,omitempty"`
 	FileName        string     `json:"fileName,omitempty" yaml:"fileName,omitempty"`
 	RegexPattern    string     `json:"regex,omitempty" yaml:"regex,omitempty"`
 	RegexGroups     string     `json:"regexGroups,omitempty" yaml:"regexGroups,omitempty"`
+	Multiline       bool       `json:"multiline,omitempty" yaml:"multiline,omitempty"`
 	IgnoreIfNoFiles bool       `json:"ignoreIfNoFiles,omitempty" yaml:"ignoreIfNoFiles,omitempty"`
 	Outcomes        []*Outcome `json:"outcomes" yaml:"outcomes"`
 	ExcludeFiles    []string   `json:"excludeFiles,omitempty" yaml:"excludeFiles,omitempty"`
@@ -179,6 +193,18 @@ type JsonCompare struct {
 	Outcomes      []*Outcome `json:"outcomes" yaml:"outcomes"`
 }
 
+// JSONQueryAnalyze evaluates a jq expression, or a JSONPath, against a JSON file collected by any
+// collector, e.g. an exec or run pod collector. The when clauses of the outcomes compare the
+// result with an operator, e.g. ">= 3", "== ready", "!= null", "contains admin" or "matches ^v1\.".
+type JSONQueryAnalyze struct {
+	AnalyzeMeta   `json:",inline" yaml:",inline"`
+	CollectorName string     `json:"collectorName,omitempty" yaml:"collectorName,omitempty"`
+	FileName      string     `json:"fileName" yaml:"fileName"`
+	JQ            string     `json:"jq,omitempty" yaml:"jq,omitempty"`
+	JSONPath      string     `json:"jsonPath,omitempty" yaml:"jsonPath,omitempty"`
+	Outcomes      []*Outcome `json:"outcomes" yaml:"outcomes"`
+}
+
 type DatabaseAnalyze struct {
 	AnalyzeMeta   `json:",inline" yaml:",inline"`
 	Outcomes      []*Outcome `json:"outcomes" yaml:"outcomes"`
@@ -186,6 +212,42 @@ type DatabaseAnalyze struct {
 	FileName      string     `json:"fileName,omitempty" yaml:"fileName,omitempty"`
 }
 
+// KafkaAnalyze evaluates the outcomes against the Kafka cluster collected by a kafka collector.
+// Without outcomes it fails when the cluster cannot be reached or partitions are offline, and warns
+// when partitions are under-replicated or a consumer group lags behind by more than 10000 messages.
+type KafkaAnalyze struct {
+	AnalyzeMeta   `json:",inline" yaml:",inline"`
+	CollectorName string     `json:"collectorName,omitempty" yaml:"collectorName,omitempty"`
+	Outcomes      []*Outcome `json:"outcomes,omitempty" yaml:"outcomes,omitempty"`
+}
+
+// RabbitMQAnalyze evaluates the outcomes against the RabbitMQ cluster collected by a rabbitmq
+// collector. Without outcomes it fails when the cluster cannot be reached, nodes are down or
+// partitioned, and warns on resource alarms and queues deeper than 10000 messages.
+type RabbitMQAnalyze struct {
+	AnalyzeMeta   `json:",inline" yaml:",inline"`
+	CollectorName string     `json:"collectorName,omitempty" yaml:"collectorName,omitempty"`
+	Outcomes      []*Outcome `json:"outcomes,omitempty" yaml:"outcomes,omitempty"`
+}
+
+// LDAPAnalyze evaluates the outcomes against the LDAP server checked by an ldap collector. Without
+// outcomes it fails when the server cannot be reached, its certificate is not trusted, or the bind
+// or the search fail.
+type LDAPAnalyze struct {
+	AnalyzeMeta   `json:",inline" yaml:",inline"`
+	CollectorName string     `json:"collectorName,omitempty" yaml:"collectorName,omitempty"`
+	Outcomes      []*Outcome `json:"outcomes,omitempty" yaml:"outcomes,omitempty"`
+}
+
+// OIDCAnalyze evaluates the outcomes against the OpenID Connect provider checked by an oidc
+// collector. Without outcomes it fails when the provider cannot be reached, its certificate is not
+// trusted, it publishes no signing keys, or it does not issue a token to the client.
+type OIDCAnalyze struct {
+	AnalyzeMeta   `json:",inline" yaml:",inline"`
+	CollectorName string     `json:"collectorName,omitempty" yaml:"collectorName,omitempty"`
+	Outcomes      []*Outcome `json:"outcomes,omitempty" yaml:"outcomes,omitempty"`
+}
+
 type CollectdAnalyze struct {
 	AnalyzeMeta   `json:",inline" yaml:",inline"`
 	Outcomes      []*Outcome `json:"outcomes" yaml:"outcomes"`
@@ -221,10 +283,35 @@ type RegistryImagesAnalyze struct {
 	CollectorName string     `json:"collectorName" yaml:"collectorName"`
 }
 
+// ImagePlatformsAnalyze evaluates the outcomes against each image of a registryImages collector
+// whose platforms were collected. Platforms are the platforms the images must be available for,
+// e.g. linux/arm64, and default to the platforms of the nodes of the cluster.
+type ImagePlatformsAnalyze struct {
+	AnalyzeMeta   `json:",inline" yaml:",inline"`
+	CollectorName string     `json:"collectorName,omitempty" yaml:"collectorName,omitempty"`
+	Platforms     []string   `json:"platforms,omitempty" yaml:"platforms,omitempty"`
+	Outcomes      []*Outcome `json:"outcomes,omitempty" yaml:"outcomes,omitempty"`
+}
+
+// MutableImageTags evaluates the outcomes against each workload that runs images by a tag that can
+// move, such as latest or a floating minor version, or only those in Namespaces when set. Images
+// whose digests were resolved by the registryImages collector named CollectorName are reported as
+// drifted when the tag no longer resolves to the digest the workload runs.
+type MutableImageTags struct {
+	AnalyzeMeta   `json:",inline" yaml:",inline"`
+	CollectorName string     `json:"collectorName,omitempty" yaml:"collectorName,omitempty"`
+	Namespaces    []string   `json:"namespaces,omitempty" yaml:"namespaces,omitempty"`
+	Outcomes      []*Outcome `json:"outcomes,omitempty" yaml:"outcomes,omitempty"`
+}
+
 type ImageSignaturesAnalyze struct {
 	AnalyzeMeta   `json:",inline" yaml:",inline"`
 	Outcomes      []*Outcome `json:"outcomes" yaml:"outcomes"`
 	CollectorName string     `json:"collectorName" yaml:"collectorName"`
+	// DetailedResults emits a result per image instead of a single result for all of them: a pass
+	// for an image with a verified signature, a fail for an unsigned image and a warning for an
+	// image whose signatures could not be checked. The outcomes are not evaluated.
+	DetailedResults bool `json:"detailedResults,omitempty" yaml:"detailedResults,omitempty"`
 }
 
 type SysctlAnalyze struct {
@@ -237,6 +324,9 @@ type AnalyzeMeta struct {
 	Exclude     *multitype.BoolOrString `json:"exclude,omitempty" yaml:"exclude,omitempty"`
 	Strict      *multitype.BoolOrString `json:"strict,omitempty" yaml:"strict,omitempty"`
 	Annotations map[string]string       `json:"annotations,omitempty" yaml:"annotations,omitempty"`
+	// Categories are free-form tags, e.g. "storage" or "networking", copied to every result of
+	// the analyzer so that results can be grouped and filtered by downstream systems.
+	Categories []string `json:"categories,omitempty" yaml:"categories,omitempty"`
 }
 
 type CertificatesAnalyze struct {
@@ -251,6 +341,64 @@ type GoldpingerAnalyze struct {
 	FilePath      string     `json:"filePath,omitempty" yaml:"filePath,omitempty"`
 }
 
+type NodeLatencyAnalyze struct {
+	AnalyzeMeta   `json:",inline" yaml:",inline"`
+	Outcomes      []*Outcome `json:"outcomes" yaml:"outcomes"`
+	CollectorName string     `json:"collectorName,omitempty" yaml:"collectorName,omitempty"`
+}
+
+// ClusterOperatorStatus evaluates the outcomes against each OpenShift ClusterOperator, or only
+// the named ones when Operators is set.
+type ClusterOperatorStatus struct {
+	AnalyzeMeta   `json:",inline" yaml:",inline"`
+	CollectorName string     `json:"collectorName,omitempty" yaml:"collectorName,omitempty"`
+	Operators     []string   `json:"operators,omitempty" yaml:"operators,omitempty"`
+	Outcomes      []*Outcome `json:"outcomes,omitempty" yaml:"outcomes,omitempty"`
+}
+
+// MachineConfigPoolStatus evaluates the outcomes against each OpenShift MachineConfigPool, or
+// only the named ones when Pools is set.
+type MachineConfigPoolStatus struct {
+	AnalyzeMeta   `json:",inline" yaml:",inline"`
+	CollectorName string     `json:"collectorName,omitempty" yaml:"collectorName,omitempty"`
+	Pools         []string   `json:"pools,omitempty" yaml:"pools,omitempty"`
+	Outcomes      []*Outcome `json:"outcomes,omitempty" yaml:"outcomes,omitempty"`
+}
+
+// ResourceQuotaAnalyze evaluates the outcomes against each namespace with a ResourceQuota or a
+// LimitRange, or only Namespaces when set.
+type ResourceQuotaAnalyze struct {
+	AnalyzeMeta `json:",inline" yaml:",inline"`
+	Namespaces  []string   `json:"namespaces,omitempty" yaml:"namespaces,omitempty"`
+	Outcomes    []*Outcome `json:"outcomes,omitempty" yaml:"outcomes,omitempty"`
+}
+
+// PodDisruptionBudget evaluates the outcomes against each PodDisruptionBudget, or only those in
+// Namespaces when set.
+type PodDisruptionBudget struct {
+	AnalyzeMeta `json:",inline" yaml:",inline"`
+	Namespaces  []string   `json:"namespaces,omitempty" yaml:"namespaces,omitempty"`
+	Outcomes    []*Outcome `json:"outcomes,omitempty" yaml:"outcomes,omitempty"`
+}
+
+// ConfigMapsAndSecrets evaluates the outcomes against each ConfigMap and Secret that is missing,
+// lacks keys that pods use, or approaches the 1MiB size limit, or only those in Namespaces when
+// set. Secrets are not collected by clusterResources, so they are only found missing from the
+// errors of the pods that use them, and their size is not checked.
+type ConfigMapsAndSecrets struct {
+	AnalyzeMeta `json:",inline" yaml:",inline"`
+	Namespaces  []string   `json:"namespaces,omitempty" yaml:"namespaces,omitempty"`
+	Outcomes    []*Outcome `json:"outcomes,omitempty" yaml:"outcomes,omitempty"`
+}
+
+// StatefulSetVolumes evaluates the outcomes against each StatefulSet whose pods are pending on
+// their volume claims, or only those in Namespaces when set.
+type StatefulSetVolumes struct {
+	AnalyzeMeta `json:",inline" yaml:",inline"`
+	Namespaces  []string   `json:"namespaces,omitempty" yaml:"namespaces,omitempty"`
+	Outcomes    []*Outcome `json:"outcomes,omitempty" yaml:"outcomes,omitempty"`
+}
+
 type EventAnalyze struct {
 	AnalyzeMeta   `json:",inline" yaml:",inline"`
 	CollectorName string     `json:"collectorName" yaml:"collectorName"`
@@ -277,8 +425,194 @@ type PVCRef struct {
 	Namespace string `json:"namespace,omitempty" yaml:"namespace,omitempty"`
 }
 
+// PluginAnalyze runs the analyzer plugin named Name with the bundle files matching FileName
+// in the directory of the collector, see pkg/plugin for the protocol.
+type PluginAnalyze struct {
+	AnalyzeMeta   `json:",inline" yaml:",inline"`
+	Name          string            `json:"name" yaml:"name"`
+	CollectorName string            `json:"collectorName,omitempty" yaml:"collectorName,omitempty"`
+	FileName      string            `json:"fileName,omitempty" yaml:"fileName,omitempty"`
+	Args          []string          `json:"args,omitempty" yaml:"args,omitempty"`
+	Config        map[string]string `json:"config,omitempty" yaml:"config,omitempty"`
+	Timeout       string            `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+}
+
+// WasmAnalyze runs a WASM module against the bundle files matching FileName in the directory
+// of the collector. The module is either inline, base64 encoded, or pulled from an OCI
+// registry, and runs in a sandbox with no access to the host.
+type WasmAnalyze struct {
+	AnalyzeMeta   `json:",inline" yaml:",inline"`
+	Module        string            `json:"module,omitempty" yaml:"module,omitempty"`
+	Image         string            `json:"image,omitempty" yaml:"image,omitempty"`
+	CollectorName string            `json:"collectorName,omitempty" yaml:"collectorName,omitempty"`
+	FileName      string            `json:"fileName" yaml:"fileName"`
+	Config        map[string]string `json:"config,omitempty" yaml:"config,omitempty"`
+	Limits        *WasmLimits       `json:"limits,omitempty" yaml:"limits,omitempty"`
+}
+
+// WasmLimits are the resources a WASM module can use, as a quantity of memory (e.g. 64Mi) and
+// a duration.
+type WasmLimits struct {
+	Memory  string `json:"memory,omitempty" yaml:"memory,omitempty"`
+	Timeout string `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+}
+
+// DiskPressureAnalyze explains the DiskPressure of nodes, and the evictions that are imminent, from the nodes collected
+// by the clusterResources collector, the filesystem usage collected by the nodeMetrics collector, and the image
+// garbage collection and eviction thresholds collected by the kubeletConfig collector, or the defaults of the kubelet
+// when they were not collected. Nodes are under pressure when their DiskPressure condition is true or the available
+// space of their node or image filesystem is below its eviction threshold. They are at risk when the available space
+// is less than MarginPercent of the filesystem above the threshold, or when the usage of the image filesystem is
+// above the image garbage collection threshold. The largest images and emptyDir volumes of the nodes are reported.
+type DiskPressureAnalyze struct {
+	AnalyzeMeta `json:",inline" yaml:",inline"`
+	// MarginPercent is the share of a filesystem above its eviction threshold at which a node is at risk. Defaults to 5.
+	MarginPercent int `json:"marginPercent,omitempty" yaml:"marginPercent,omitempty"`
+	// Largest is the number of the largest images and emptyDir volumes reported for each node. Defaults to 3.
+	Largest  int        `json:"largest,omitempty" yaml:"largest,omitempty"`
+	Outcomes []*Outcome `json:"outcomes,omitempty" yaml:"outcomes,omitempty"`
+}
+
+// PodSecurityAnalyze evaluates the pods collected by the clusterResources collector against the baseline or
+// restricted Pod Security Standard, and reports the workloads that violate it in each namespace, i.e. the
+// workloads whose pods Pod Security Admission would reject when the level is enforced. A result is returned for
+// each namespace with violating workloads.
+type PodSecurityAnalyze struct {
+	AnalyzeMeta `json:",inline" yaml:",inline"`
+	// Level is the Pod Security Standard the workloads are evaluated against, baseline or restricted. Defaults to
+	// the level of the pod-security.kubernetes.io/enforce label of each namespace, or baseline when it has none.
+	// Namespaces labelled privileged are not evaluated unless a level is set.
+	Level string `json:"level,omitempty" yaml:"level,omitempty"`
+	// Namespaces are the namespaces whose workloads are evaluated. Defaults to all namespaces.
+	Namespaces []string   `json:"namespaces,omitempty" yaml:"namespaces,omitempty"`
+	Outcomes   []*Outcome `json:"outcomes,omitempty" yaml:"outcomes,omitempty"`
+}
+
+// ManagedClusterAnalyze flags the misconfigurations of the provider of a managed cluster collected by the
+// managedCluster collector: unavailable CNI pods and IAM roles of service accounts that cannot be assumed on EKS,
+// Workload Identity that is not enabled and workloads rejected by Autopilot on GKE, and the limits of kubenet on
+// AKS. The outcomes are evaluated against the number of misconfigurations, e.g. "failures > 0", and whether the
+// cluster is managed, e.g. "managed == false".
+type ManagedClusterAnalyze struct {
+	AnalyzeMeta   `json:",inline" yaml:",inline"`
+	CollectorName string     `json:"collectorName,omitempty" yaml:"collectorName,omitempty"`
+	Outcomes      []*Outcome `json:"outcomes,omitempty" yaml:"outcomes,omitempty"`
+}
+
+// PolicyViolationsAnalyze reports the violations of policies, Kyverno policies or Gatekeeper constraints, collected
+// by a policyViolations collector. The outcomes are evaluated against the number of violations of the selected
+// policies, e.g. "violations > 0", and fail on violations and warn on warnings when none are set.
+type PolicyViolationsAnalyze struct {
+	AnalyzeMeta   `json:",inline" yaml:",inline"`
+	CollectorName string `json:"collectorName,omitempty" yaml:"collectorName,omitempty"`
+	// Policies are the names of the policies whose violations are reported, or glob patterns of them, e.g.
+	// require-*. Defaults to all policies.
+	Policies []string   `json:"policies,omitempty" yaml:"policies,omitempty"`
+	Outcomes []*Outcome `json:"outcomes,omitempty" yaml:"outcomes,omitempty"`
+}
+
+// RegoAnalyze evaluates a Rego policy against the JSON files of the bundle matching FileName in the
+// directory of the collector, e.g. cluster resources, image facts or image signatures. Each file is the
+// input of the policy, and the messages of the deny and warn rules of the package of the policy are its
+// violations. The outcomes are evaluated against the number of violations, e.g. "denials > 0", and fail on
+// denials and warn on warnings when none are set.
+type RegoAnalyze struct {
+	AnalyzeMeta   `json:",inline" yaml:",inline"`
+	CollectorName string `json:"collectorName,omitempty" yaml:"collectorName,omitempty"`
+	FileName      string `json:"fileName" yaml:"fileName"`
+	// Policy is a Rego module using the syntax of OPA v1, e.g. "deny contains msg if { ... }"
+	Policy   string     `json:"policy" yaml:"policy"`
+	Outcomes []*Outcome `json:"outcomes,omitempty" yaml:"outcomes,omitempty"`
+}
+
+// CompositeAnalyze rolls the results of child analyzers up into a single result. AllOf is met
+// when every child passes, AnyOf when at least one does and NoneOf when none does, and all of the
+// groups that are set must be met. The outcomes can also compare the score, the weighted share of
+// the children that are met, e.g. "score >= 75". Without outcomes it fails when a group is not
+// met, and warns when it is only not met because of warnings.
+type CompositeAnalyze struct {
+	AnalyzeMeta `json:",inline" yaml:",inline"`
+	// The children are schemaless as CRD schemas can't describe recursive types.
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:pruning:PreserveUnknownFields
+	AllOf []*CompositeChild `json:"allOf,omitempty" yaml:"allOf,omitempty"`
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:pruning:PreserveUnknownFields
+	AnyOf []*CompositeChild `json:"anyOf,omitempty" yaml:"anyOf,omitempty"`
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:pruning:PreserveUnknownFields
+	NoneOf   []*CompositeChild `json:"noneOf,omitempty" yaml:"noneOf,omitempty"`
+	Outcomes []*Outcome        `json:"outcomes,omitempty" yaml:"outcomes,omitempty"`
+}
+
+// CompositeChild is an analyzer of a composite analyzer, with its weight in the score of the
+// composite. The weight defaults to 1.
+type CompositeChild struct {
+	Analyze `json:",inline" yaml:",inline"`
+	Weight  *int `json:"weight,omitempty" yaml:"weight,omitempty"`
+}
+
+// ProxyAnalyze analyzes the proxy settings of the workloads collected by the proxy collector of the same name.
+type ProxyAnalyze struct {
+	AnalyzeMeta   `json:",inline" yaml:",inline"`
+	CollectorName string     `json:"collectorName,omitempty" yaml:"collectorName,omitempty"`
+	Outcomes      []*Outcome `json:"outcomes" yaml:"outcomes"`
+}
+
+// VeleroReadinessAnalyze evaluates the outcomes against the backup storage locations, schedules, backups and
+// restores collected by the velero collector. The outcomes fail when a backup storage location is unavailable or
+// the last backup of a schedule failed when none are set.
+type VeleroReadinessAnalyze struct {
+	AnalyzeMeta   `json:",inline" yaml:",inline"`
+	CollectorName string     `json:"collectorName,omitempty" yaml:"collectorName,omitempty"`
+	Outcomes      []*Outcome `json:"outcomes" yaml:"outcomes"`
+}
+
+// TrustChainsAnalyze rebuilds the trust chains of the certificates collected by a trustChains collector, and
+// evaluates the outcomes against each chain. The outcomes fail when a chain does not lead to a trusted certificate
+// authority or has an expired intermediate, and warn when it is signed with SHA-1, when none are set.
+type TrustChainsAnalyze struct {
+	AnalyzeMeta   `json:",inline" yaml:",inline"`
+	CollectorName string `json:"collectorName,omitempty" yaml:"collectorName,omitempty"`
+	// TrustedCAs are PEM certificates of authorities trusted in addition to the cluster CA, e.g. the CA of the
+	// company that signs the certificates of the ingresses
+	TrustedCAs string     `json:"trustedCAs,omitempty" yaml:"trustedCAs,omitempty"`
+	Outcomes   []*Outcome `json:"outcomes,omitempty" yaml:"outcomes,omitempty"`
+}
+
+// ImageLayersAnalyze reports the storage the images collected by an imageLayers collector use: their total
+// size, the size of their unique layers, their largest images, and the layers that have the same content as layers
+// of other images but are stored separately, compressed differently. The outcomes are evaluated against the report,
+// and report the sizes and warn about the duplicated layers when none are set.
+type ImageLayersAnalyze struct {
+	AnalyzeMeta   `json:",inline" yaml:",inline"`
+	CollectorName string `json:"collectorName,omitempty" yaml:"collectorName,omitempty"`
+	// Largest is the number of images reported as the largest. It defaults to 5.
+	Largest  int        `json:"largest,omitempty" yaml:"largest,omitempty"`
+	Outcomes []*Outcome `json:"outcomes,omitempty" yaml:"outcomes,omitempty"`
+}
+
+// ImageFactsAnalyze reports the images collected by an imageFacts collector that run as root, unless their pods
+// set another user, and the images created longer ago than MaxAgeDays. The outcomes are evaluated against the
+// images, and warn about the images running as root and the old images when none are set.
+type ImageFactsAnalyze struct {
+	AnalyzeMeta   `json:",inline" yaml:",inline"`
+	CollectorName string `json:"collectorName,omitempty" yaml:"collectorName,omitempty"`
+	// MaxAgeDays is the age in days beyond which images are reported as old. It defaults to 365.
+	MaxAgeDays int        `json:"maxAgeDays,omitempty" yaml:"maxAgeDays,omitempty"`
+	Outcomes   []*Outcome `json:"outcomes,omitempty" yaml:"outcomes,omitempty"`
+}
+
+// KubeletMetricsAnalyze evaluates the outcomes against the metrics of the kubelet of each node collected by the
+// kubeletMetrics collector, e.g. the latency of the relists of the pod lifecycle event generator (PLEG).
+type KubeletMetricsAnalyze struct {
+	AnalyzeMeta `json:",inline" yaml:",inline"`
+	Outcomes    []*Outcome `json:"outcomes" yaml:"outcomes"`
+}
+
 type Analyze struct {
 	ClusterVersion           *ClusterVersion           `json:"clusterVersion,omitempty" yaml:"clusterVersion,omitempty"`
+	KubernetesUpgrade        *KubernetesUpgrade        `json:"kubernetesUpgrade,omitempty" yaml:"kubernetesUpgrade,omitempty"`
 	StorageClass             *StorageClass             `json:"storageClass,omitempty" yaml:"storageClass,omitempty"`
 	CustomResourceDefinition *CustomResourceDefinition `json:"customResourceDefinition,omitempty" yaml:"customResourceDefinition,omitempty"`
 	Ingress                  *Ingress                  `json:"ingress,omitempty" yaml:"ingress,omitempty"`
@@ -287,6 +621,7 @@ type Analyze struct {
 	ImagePullSecret          *ImagePullSecret          `json:"imagePullSecret,omitempty" yaml:"imagePullSecret,omitempty"`
 	DeploymentStatus         *DeploymentStatus         `json:"deploymentStatus,omitempty" yaml:"deploymentStatus,omitempty"`
 	StatefulsetStatus        *StatefulsetStatus        `json:"statefulsetStatus,omitempty" yaml:"statefulsetStatus,omitempty"`
+	StatefulSetVolumes       *StatefulSetVolumes       `json:"statefulSetVolumes,omitempty" yaml:"statefulSetVolumes,omitempty"`
 	JobStatus                *JobStatus                `json:"jobStatus,omitempty" yaml:"jobStatus,omitempty"`
 	ReplicaSetStatus         *ReplicaSetStatus         `json:"replicasetStatus,omitempty" yaml:"replicasetStatus,omitempty"`
 	ClusterPodStatuses       *ClusterPodStatuses       `json:"clusterPodStatuses,omitempty" yaml:"clusterPodStatuses,omitempty"`
@@ -294,24 +629,51 @@ type Analyze struct {
 	ContainerRuntime         *ContainerRuntime         `json:"containerRuntime,omitempty" yaml:"containerRuntime,omitempty"`
 	Distribution             *Distribution             `json:"distribution,omitempty" yaml:"distribution,omitempty"`
 	NodeResources            *NodeResources            `json:"nodeResources,omitempty" yaml:"nodeResources,omitempty"`
+	ResourceQuota            *ResourceQuotaAnalyze     `json:"resourceQuota,omitempty" yaml:"resourceQuota,omitempty"`
+	PodDisruptionBudget      *PodDisruptionBudget      `json:"podDisruptionBudget,omitempty" yaml:"podDisruptionBudget,omitempty"`
+	ConfigMapsAndSecrets     *ConfigMapsAndSecrets     `json:"configMapsAndSecrets,omitempty" yaml:"configMapsAndSecrets,omitempty"`
 	TextAnalyze              *TextAnalyze              `json:"textAnalyze,omitempty" yaml:"textAnalyze,omitempty"`
 	YamlCompare              *YamlCompare              `json:"yamlCompare,omitempty" yaml:"yamlCompare,omitempty"`
 	JsonCompare              *JsonCompare              `json:"jsonCompare,omitempty" yaml:"jsonCompare,omitempty"`
+	JSONQuery                *JSONQueryAnalyze         `json:"jsonQuery,omitempty" yaml:"jsonQuery,omitempty"`
 	Postgres                 *DatabaseAnalyze          `json:"postgres,omitempty" yaml:"postgres,omitempty"`
 	Mssql                    *DatabaseAnalyze          `json:"mssql,omitempty" yaml:"mssql,omitempty"`
 	Mysql                    *DatabaseAnalyze          `json:"mysql,omitempty" yaml:"mysql,omitempty"`
 	Redis                    *DatabaseAnalyze          `json:"redis,omitempty" yaml:"redis,omitempty"`
+	Kafka                    *KafkaAnalyze             `json:"kafka,omitempty" yaml:"kafka,omitempty"`
+	RabbitMQ                 *RabbitMQAnalyze          `json:"rabbitmq,omitempty" yaml:"rabbitmq,omitempty"`
+	LDAP                     *LDAPAnalyze              `json:"ldap,omitempty" yaml:"ldap,omitempty"`
+	OIDC                     *OIDCAnalyze              `json:"oidc,omitempty" yaml:"oidc,omitempty"`
 	CephStatus               *CephStatusAnalyze        `json:"cephStatus,omitempty" yaml:"cephStatus,omitempty"`
 	Velero                   *VeleroAnalyze            `json:"velero,omitempty" yaml:"velero,omitempty"`
 	Longhorn                 *LonghornAnalyze          `json:"longhorn,omitempty" yaml:"longhorn,omitempty"`
 	RegistryImages           *RegistryImagesAnalyze    `json:"registryImages,omitempty" yaml:"registryImages,omitempty"`
+	ImagePlatforms           *ImagePlatformsAnalyze    `json:"imagePlatforms,omitempty" yaml:"imagePlatforms,omitempty"`
+	MutableImageTags         *MutableImageTags         `json:"mutableImageTags,omitempty" yaml:"mutableImageTags,omitempty"`
 	ImageSignatures          *ImageSignaturesAnalyze   `json:"imageSignatures,omitempty" yaml:"imageSignatures,omitempty"`
 	WeaveReport              *WeaveReportAnalyze       `json:"weaveReport,omitempty" yaml:"weaveReport,omitempty"`
 	Sysctl                   *SysctlAnalyze            `json:"sysctl,omitempty" yaml:"sysctl,omitempty"`
 	ClusterResource          *ClusterResource          `json:"clusterResource,omitempty" yaml:"clusterResource,omitempty"`
 	Certificates             *CertificatesAnalyze      `json:"certificates,omitempty" yaml:"certificates,omitempty"`
 	Goldpinger               *GoldpingerAnalyze        `json:"goldpinger,omitempty" yaml:"goldpinger,omitempty"`
+	NodeLatency              *NodeLatencyAnalyze       `json:"nodeLatency,omitempty" yaml:"nodeLatency,omitempty"`
+	ClusterOperatorStatus    *ClusterOperatorStatus    `json:"clusterOperatorStatus,omitempty" yaml:"clusterOperatorStatus,omitempty"`
+	MachineConfigPoolStatus  *MachineConfigPoolStatus  `json:"machineConfigPoolStatus,omitempty" yaml:"machineConfigPoolStatus,omitempty"`
 	Event                    *EventAnalyze             `json:"event,omitempty" yaml:"event,omitempty"`
 	NodeMetrics              *NodeMetricsAnalyze       `json:"nodeMetrics,omitempty" yaml:"nodeMetrics,omitempty"`
 	HTTP                     *HTTPAnalyze              `json:"http,omitempty" yaml:"http,omitempty"`
+	Plugin                   *PluginAnalyze            `json:"plugin,omitempty" yaml:"plugin,omitempty"`
+	Wasm                     *WasmAnalyze              `json:"wasm,omitempty" yaml:"wasm,omitempty"`
+	Composite                *CompositeAnalyze         `json:"composite,omitempty" yaml:"composite,omitempty"`
+	Proxy                    *ProxyAnalyze             `json:"proxy,omitempty" yaml:"proxy,omitempty"`
+	VeleroReadiness          *VeleroReadinessAnalyze   `json:"veleroReadiness,omitempty" yaml:"veleroReadiness,omitempty"`
+	TrustChains              *TrustChainsAnalyze       `json:"trustChains,omitempty" yaml:"trustChains,omitempty"`
+	ImageLayers              *ImageLayersAnalyze       `json:"imageLayers,omitempty" yaml:"imageLayers,omitempty"`
+	ImageFacts               *ImageFactsAnalyze        `json:"imageFacts,omitempty" yaml:"imageFacts,omitempty"`
+	KubeletMetrics           *KubeletMetricsAnalyze    `json:"kubeletMetrics,omitempty" yaml:"kubeletMetrics,omitempty"`
+	Rego                     *RegoAnalyze              `json:"rego,omitempty" yaml:"rego,omitempty"`
+	PolicyViolations         *PolicyViolationsAnalyze  `json:"policyViolations,omitempty" yaml:"policyViolations,omitempty"`
+	DiskPressure             *DiskPressureAnalyze      `json:"diskPressure,omitempty" yaml:"diskPressure,omitempty"`
+	PodSecurity              *PodSecurityAnalyze       `json:"podSecurity,omitempty" yaml:"podSecurity,omitempty"`
+	ManagedCluster           *ManagedClusterAnalyze    `json:"managedCluster,omitempty" yaml:"managedCluster,omitempty"`
 }
@@ -31,6 +31,50 @@ type AfterCollection struct {
 	Callback        *ResultRequest `json:"callback,omitempty" yaml:"callback,omitempty"`
 }
 
+// Notification sends a summary of a completed run, its pass, warn and fail counts and where its
+// bundle is, to one destination. Exactly one of the fields is set.
+type Notification struct {
+	// Webhook posts the summary as JSON.
+	Webhook *WebhookNotification `json:"webhook,omitempty" yaml:"webhook,omitempty"`
+	// Slack posts the summary as text to a Slack compatible incoming webhook.
+	Slack *WebhookNotification `json:"slack,omitempty" yaml:"slack,omitempty"`
+	SMTP  *SMTPNotification    `json:"smtp,omitempty" yaml:"smtp,omitempty"`
+}
+
+type WebhookNotification struct {
+	URI string `json:"uri" yaml:"uri"`
+	// TokenEnv is the environment variable holding a bearer token sent with the request.
+	TokenEnv string `json:"tokenEnv,omitempty" yaml:"tokenEnv,omitempty"`
+}
+
+// SMTPNotification emails the summary through an SMTP server.
+type SMTPNotification struct {
+	// Server is the host and port of the SMTP server, e.g. smtp.example.com:587.
+	Server   string   `json:"server" yaml:"server"`
+	From     string   `json:"from" yaml:"from"`
+	To       []string `json:"to" yaml:"to"`
+	Username string   `json:"username,omitempty" yaml:"username,omitempty"`
+	// PasswordEnv is the environment variable holding the password of the user, defaults to
+	// SMTP_PASSWORD.
+	PasswordEnv string `json:"passwordEnv,omitempty" yaml:"passwordEnv,omitempty"`
+}
+
+// SpecInclude references a spec of the same kind whose collectors, analyzers and settings are
+// loaded before those of the spec that includes it. Exactly one of the fields is set.
+type SpecInclude struct {
+	// URI is an http(s) or oci location of the spec.
+	URI       string            `json:"uri,omitempty" yaml:"uri,omitempty"`
+	ConfigMap *IncludeConfigMap `json:"configMap,omitempty" yaml:"configMap,omitempty"`
+}
+
+// IncludeConfigMap is a ConfigMap holding a spec.
+type IncludeConfigMap struct {
+	Name      string `json:"name" yaml:"name"`
+	Namespace string `json:"namespace" yaml:"namespace"`
+	// Key of the spec in the data of the ConfigMap. When empty, the specs of all keys are loaded.
+	Key string `json:"key,omitempty" yaml:"key,omitempty"`
+}
+
 // CollectorSpec defines the desired state of Collector
 type CollectorSpec struct {
 	Collectors      []*Collect         `json:"collectors,omitempty" yaml:"collectors,omitempty"`
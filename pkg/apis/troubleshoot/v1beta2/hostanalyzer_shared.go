@@ -149,6 +149,124 @@ type HostSysctlAnalyze struct {
 	Outcomes      []*Outcome `json:"outcomes" yaml:"outcomes"`
 }
 
+// KernelSnapshotAnalyze compares a kernel snapshot against a baseline. Each deviation from the
+// baseline is reported as a separate result.
+type KernelSnapshotAnalyze struct {
+	AnalyzeMeta   `json:",inline" yaml:",inline"`
+	CollectorName string `json:"collectorName,omitempty" yaml:"collectorName,omitempty"`
+	// Sysctl maps kernel parameters to their expected value. The value may be prefixed with a
+	// comparison operator, e.g. ">= 262144". Values without an operator must match exactly.
+	Sysctl map[string]string `json:"sysctl,omitempty" yaml:"sysctl,omitempty"`
+	// Modules lists kernel modules that must be loaded.
+	Modules []string `json:"modules,omitempty" yaml:"modules,omitempty"`
+	// Cmdline lists arguments that must be present on the kernel command line, e.g. "cgroup_no_v1=all".
+	Cmdline  []string   `json:"cmdline,omitempty" yaml:"cmdline,omitempty"`
+	Outcomes []*Outcome `json:"outcomes" yaml:"outcomes"`
+}
+
+type SystemdUnitsAnalyze struct {
+	AnalyzeMeta   `json:",inline" yaml:",inline"`
+	CollectorName string     `json:"collectorName,omitempty" yaml:"collectorName,omitempty"`
+	Outcomes      []*Outcome `json:"outcomes" yaml:"outcomes"`
+}
+
+type NetworkConfigAnalyze struct {
+	AnalyzeMeta   `json:",inline" yaml:",inline"`
+	CollectorName string     `json:"collectorName,omitempty" yaml:"collectorName,omitempty"`
+	Outcomes      []*Outcome `json:"outcomes" yaml:"outcomes"`
+}
+
+type GPUAnalyze struct {
+	AnalyzeMeta   `json:",inline" yaml:",inline"`
+	CollectorName string     `json:"collectorName,omitempty" yaml:"collectorName,omitempty"`
+	Outcomes      []*Outcome `json:"outcomes" yaml:"outcomes"`
+}
+
+type TimeSyncAnalyze struct {
+	AnalyzeMeta   `json:",inline" yaml:",inline"`
+	CollectorName string     `json:"collectorName,omitempty" yaml:"collectorName,omitempty"`
+	Outcomes      []*Outcome `json:"outcomes" yaml:"outcomes"`
+}
+
+type KubernetesDistributionAnalyze struct {
+	AnalyzeMeta   `json:",inline" yaml:",inline"`
+	CollectorName string     `json:"collectorName,omitempty" yaml:"collectorName,omitempty"`
+	Outcomes      []*Outcome `json:"outcomes" yaml:"outcomes"`
+}
+
+type BenchmarkAnalyze struct {
+	AnalyzeMeta   `json:",inline" yaml:",inline"`
+	CollectorName string     `json:"collectorName,omitempty" yaml:"collectorName,omitempty"`
+	Outcomes      []*Outcome `json:"outcomes" yaml:"outcomes"`
+}
+
+type ActivityCaptureAnalyze struct {
+	AnalyzeMeta   `json:",inline" yaml:",inline"`
+	CollectorName string     `json:"collectorName,omitempty" yaml:"collectorName,omitempty"`
+	Outcomes      []*Outcome `json:"outcomes" yaml:"outcomes"`
+}
+
+type PathsAnalyze struct {
+	AnalyzeMeta   `json:",inline" yaml:",inline"`
+	CollectorName string     `json:"collectorName,omitempty" yaml:"collectorName,omitempty"`
+	Outcomes      []*Outcome `json:"outcomes" yaml:"outcomes"`
+}
+
+type SecurityAnalyze struct {
+	AnalyzeMeta   `json:",inline" yaml:",inline"`
+	CollectorName string     `json:"collectorName,omitempty" yaml:"collectorName,omitempty"`
+	Outcomes      []*Outcome `json:"outcomes" yaml:"outcomes"`
+}
+
+type HostProxyAnalyze struct {
+	AnalyzeMeta   `json:",inline" yaml:",inline"`
+	CollectorName string     `json:"collectorName,omitempty" yaml:"collectorName,omitempty"`
+	Outcomes      []*Outcome `json:"outcomes" yaml:"outcomes"`
+}
+
+type ConnectionStatsAnalyze struct {
+	AnalyzeMeta   `json:",inline" yaml:",inline"`
+	CollectorName string     `json:"collectorName,omitempty" yaml:"collectorName,omitempty"`
+	Outcomes      []*Outcome `json:"outcomes" yaml:"outcomes"`
+}
+
+// KernelLogsAnalyze classifies the errors in the logs collected by the kernelLogs collector against a library of
+// patterns, and reports a result for each class of errors found.
+type KernelLogsAnalyze struct {
+	AnalyzeMeta   `json:",inline" yaml:",inline"`
+	CollectorName string `json:"collectorName,omitempty" yaml:"collectorName,omitempty"`
+	// Classes restricts the analysis to a list of classes, e.g. "oomKiller" or "conntrackFull". Defaults to all.
+	Classes  []string   `json:"classes,omitempty" yaml:"classes,omitempty"`
+	Outcomes []*Outcome `json:"outcomes,omitempty" yaml:"outcomes,omitempty"`
+}
+
+// KubeletConfigAnalyze checks the cgroup driver of the kubelet against the one of the container runtime, and the
+// cgroup version of the host against the version of Kubernetes.
+type KubeletConfigAnalyze struct {
+	AnalyzeMeta   `json:",inline" yaml:",inline"`
+	CollectorName string `json:"collectorName,omitempty" yaml:"collectorName,omitempty"`
+	// KubernetesVersion is the version to check the cgroup version against, e.g. the version of an upgrade.
+	// Defaults to the version of the kubelet.
+	KubernetesVersion string     `json:"kubernetesVersion,omitempty" yaml:"kubernetesVersion,omitempty"`
+	Outcomes          []*Outcome `json:"outcomes" yaml:"outcomes"`
+}
+
+// MetricsAnalyze thresholds the time series of the metrics collector, e.g. the 95th percentile of the CPU usage
+// or the highest utilization of a disk.
+type MetricsAnalyze struct {
+	AnalyzeMeta   `json:",inline" yaml:",inline"`
+	CollectorName string     `json:"collectorName,omitempty" yaml:"collectorName,omitempty"`
+	Outcomes      []*Outcome `json:"outcomes" yaml:"outcomes"`
+}
+
+// CRIRuntimeAnalyze checks the conditions of the container runtime and the usage of its image filesystems collected
+// by the criRuntime host collector.
+type CRIRuntimeAnalyze struct {
+	AnalyzeMeta   `json:",inline" yaml:",inline"`
+	CollectorName string     `json:"collectorName,omitempty" yaml:"collectorName,omitempty"`
+	Outcomes      []*Outcome `json:"outcomes" yaml:"outcomes"`
+}
+
 type HostAnalyze struct {
 	CPU                          *CPUAnalyze                          `json:"cpu,omitempty" yaml:"cpu,omitempty"`
 	TCPLoadBalancer              *TCPLoadBalancerAnalyze              `json:"tcpLoadBalancer,omitempty" yaml:"tcpLoadBalancer,omitempty"`
@@ -176,4 +294,20 @@ type HostAnalyze struct {
 	JsonCompare                  *JsonCompare                         `json:"jsonCompare,omitempty" yaml:"jsonCompare,omitempty"`
 	NetworkNamespaceConnectivity *NetworkNamespaceConnectivityAnalyze `json:"networkNamespaceConnectivity,omitempty" yaml:"networkNamespaceConnectivity,omitempty"`
 	Sysctl                       *HostSysctlAnalyze                   `json:"sysctl,omitempty" yaml:"sysctl,omitempty"`
+	KernelSnapshot               *KernelSnapshotAnalyze               `json:"kernelSnapshot,omitempty" yaml:"kernelSnapshot,omitempty"`
+	SystemdUnits                 *SystemdUnitsAnalyze                 `json:"systemdUnits,omitempty" yaml:"systemdUnits,omitempty"`
+	NetworkConfig                *NetworkConfigAnalyze                `json:"networkConfig,omitempty" yaml:"networkConfig,omitempty"`
+	GPU                          *GPUAnalyze                          `json:"gpu,omitempty" yaml:"gpu,omitempty"`
+	TimeSync                     *TimeSyncAnalyze                     `json:"timeSync,omitempty" yaml:"timeSync,omitempty"`
+	KubernetesDistribution       *KubernetesDistributionAnalyze       `json:"kubernetesDistribution,omitempty" yaml:"kubernetesDistribution,omitempty"`
+	Benchmark                    *BenchmarkAnalyze                    `json:"benchmark,omitempty" yaml:"benchmark,omitempty"`
+	Paths                        *PathsAnalyze                        `json:"paths,omitempty" yaml:"paths,omitempty"`
+	Security                     *SecurityAnalyze                     `json:"security,omitempty" yaml:"security,omitempty"`
+	Proxy                        *HostProxyAnalyze                    `json:"proxy,omitempty" yaml:"proxy,omitempty"`
+	ActivityCapture              *ActivityCaptureAnalyze              `json:"activityCapture,omitempty" yaml:"activityCapture,omitempty"`
+	KernelLogs                   *KernelLogsAnalyze                   `json:"kernelLogs,omitempty" yaml:"kernelLogs,omitempty"`
+	ConnectionStats              *ConnectionStatsAnalyze              `json:"connectionStats,omitempty" yaml:"connectionStats,omitempty"`
+	KubeletConfig                *KubeletConfigAnalyze                `json:"kubeletConfig,omitempty" yaml:"kubeletConfig,omitempty"`
+	Metrics                      *MetricsAnalyze                      `json:"metrics,omitempty" yaml:"metrics,omitempty"`
+	CRIRuntime                   *CRIRuntimeAnalyze                   `json:"criRuntime,omitempty" yaml:"criRuntime,omitempty"`
 }
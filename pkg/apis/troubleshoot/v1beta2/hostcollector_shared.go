@@ -8,6 +8,9 @@ type HostCollectorMeta struct {
 	CollectorName string `json:"collectorName,omitempty" yaml:"collectorName,omitempty"`
 	// +optional
 	Exclude *multitype.BoolOrString `json:"exclude,omitempty" yaml:"exclude,omitempty"`
+	// MaxSize is the most the files of the collector can take in the bundle, as a quantity (e.g. 100Mi)
+	// +optional
+	MaxSize string `json:"maxSize,omitempty" yaml:"maxSize,omitempty"`
 }
 
 type CPU struct {
@@ -62,6 +65,15 @@ type SubnetAvailable struct {
 type DiskUsage struct {
 	HostCollectorMeta `json:",inline" yaml:",inline"`
 	Path              string `json:"path" yaml:"path"`
+	// Forecast samples usage of the path over a short window to estimate its growth. Disabled when not set.
+	Forecast *DiskUsageForecast `json:"forecast,omitempty" yaml:"forecast,omitempty"`
+}
+
+type DiskUsageForecast struct {
+	// Samples is the number of usage samples to take. Defaults to 5.
+	Samples int `json:"samples,omitempty" yaml:"samples,omitempty"`
+	// Interval is the time between samples. Defaults to 5s.
+	Interval string `json:"interval,omitempty" yaml:"interval,omitempty"`
 }
 
 type HostHTTP struct {
@@ -206,6 +218,9 @@ type HostRun struct {
 	InheritEnvs       []string          `json:"inheritEnvs,omitempty" yaml:"inheritEnvs,omitempty"`
 	IgnoreParentEnvs  bool              `json:"ignoreParentEnvs,omitempty" yaml:"ignoreParentEnvs,omitempty"`
 	Timeout           string            `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+	// Parse normalizes the output of the command to JSON, saved alongside it, for analyzers to query it. One of json,
+	// yaml, keyvalue or table.
+	Parse string `json:"parse,omitempty" yaml:"parse,omitempty"`
 }
 
 type HostKernelConfigs struct {
@@ -235,6 +250,157 @@ type HostSysctl struct {
 	HostCollectorMeta `json:",inline" yaml:",inline"`
 }
 
+// HostKernelSnapshot captures the kernel parameters reported by `sysctl -a`, the loaded
+// kernel modules and the kernel command line in a single file.
+type HostKernelSnapshot struct {
+	HostCollectorMeta `json:",inline" yaml:",inline"`
+}
+
+// HostSystemdUnits collects the state, restart count and most recent journal lines of a list of systemd units.
+type HostSystemdUnits struct {
+	HostCollectorMeta `json:",inline" yaml:",inline"`
+	// Units to collect, e.g. "containerd" or "kubelet.service". A unit without a suffix is assumed to be a service.
+	Units []string `json:"units" yaml:"units"`
+	// JournalLines is the number of recent journal lines to collect for each unit. Defaults to 20, set to -1 to skip.
+	JournalLines int `json:"journalLines,omitempty" yaml:"journalLines,omitempty"`
+}
+
+// HostNetworkConfig collects the host's interfaces, routes and firewall chains.
+type HostNetworkConfig struct {
+	HostCollectorMeta `json:",inline" yaml:",inline"`
+}
+
+// HostGPU collects an inventory of the host's NVIDIA and AMD GPUs, along with driver, CUDA and MIG details when available.
+type HostGPU struct {
+	HostCollectorMeta `json:",inline" yaml:",inline"`
+}
+
+// HostTimeSync collects the clock synchronization status, offset and configured servers from chrony, ntpd or systemd-timesyncd.
+type HostTimeSync struct {
+	HostCollectorMeta `json:",inline" yaml:",inline"`
+}
+
+// HostKubernetesDistribution collects the configuration of k3s, RKE2 or embedded-cluster from the paths where they keep
+// it: the config file, the containerd config, whether a server token exists and the local etcd snapshots. Secrets in the
+// configuration are redacted.
+type HostKubernetesDistribution struct {
+	HostCollectorMeta `json:",inline" yaml:",inline"`
+	// Distribution is one of k3s, rke2 or embedded-cluster. The distribution installed on the host is detected when it is not set.
+	Distribution string `json:"distribution,omitempty" yaml:"distribution,omitempty"`
+}
+
+// HostBenchmark runs a short CPU and memory benchmark on the host, so that the performance of the machine can be
+// compared to a minimum rather than just its number of cores. The CPU benchmark verifies prime numbers like sysbench
+// cpu, once on a single thread and once on all the threads, and the memory benchmark copies a buffer in memory.
+type HostBenchmark struct {
+	HostCollectorMeta `json:",inline" yaml:",inline"`
+	// Duration of each of the single-threaded, multi-threaded and memory benchmarks, e.g. 2s. Defaults to 2s and
+	// must not be longer than 10s.
+	Duration string `json:"duration,omitempty" yaml:"duration,omitempty"`
+	// Threads is the number of threads of the multi-threaded CPU benchmark. Defaults to the number of CPUs.
+	Threads int `json:"threads,omitempty" yaml:"threads,omitempty"`
+}
+
+// HostPaths collects whether each of a list of paths exists, its type, owner, group, mode and SELinux context, and the
+// mount point, filesystem and mount options of the mount it is on.
+type HostPaths struct {
+	HostCollectorMeta `json:",inline" yaml:",inline"`
+	// Paths to collect, e.g. "/var/lib/kubelet". Symbolic links are followed. The mount of a path that does not exist
+	// is the mount of its closest existing parent, so that e.g. noexec can be checked before the directory is created.
+	Paths []string `json:"paths" yaml:"paths"`
+}
+
+// HostSecurity collects the SELinux mode, policy and booleans, whether AppArmor is enabled and the profiles it loaded,
+// whether seccomp is available and the default of the kubelet, and the security settings of containerd and CRI-O.
+type HostSecurity struct {
+	HostCollectorMeta `json:",inline" yaml:",inline"`
+	// ContainerdConfigPath is the config file of containerd. Defaults to /etc/containerd/config.toml.
+	ContainerdConfigPath string `json:"containerdConfigPath,omitempty" yaml:"containerdConfigPath,omitempty"`
+	// KubeletConfigPath is the config file of the kubelet. Defaults to /var/lib/kubelet/config.yaml.
+	KubeletConfigPath string `json:"kubeletConfigPath,omitempty" yaml:"kubeletConfigPath,omitempty"`
+}
+
+// HostProxy collects the HTTP_PROXY, HTTPS_PROXY and NO_PROXY settings of the host from the environment, /etc/environment
+// and the containerd and kubelet units, and tests the connection to a list of endpoints through the proxy.
+type HostProxy struct {
+	HostCollectorMeta `json:",inline" yaml:",inline"`
+	// Endpoints are the URLs to connect to through the proxy, e.g. the registries and APIs the installation requires
+	Endpoints []string `json:"endpoints,omitempty" yaml:"endpoints,omitempty"`
+	// CIDRs that NO_PROXY must cover when a proxy is set, e.g. the pod and service CIDRs of the cluster
+	CIDRs []string `json:"cidrs,omitempty" yaml:"cidrs,omitempty"`
+	// Timeout of the connection to each endpoint. Defaults to 10s.
+	Timeout string `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+}
+
+// HostActivityCapture samples the activity of the host every interval for a short window: the CPU time, including steal
+// and iowait, the load average, the available memory and the throughput of the disks, as vmstat and iostat report
+// them, and the processes using the most CPU, as ps reports them. It is opt-in as it makes the collection as long as
+// its window, which must not be longer than 60s.
+type HostActivityCapture struct {
+	HostCollectorMeta `json:",inline" yaml:",inline"`
+	// Interval between samples, e.g. 1s. Defaults to 1s.
+	Interval string `json:"interval,omitempty" yaml:"interval,omitempty"`
+	// Duration of the window, e.g. 10s. Defaults to 10s and must not be longer than 60s.
+	Duration string `json:"duration,omitempty" yaml:"duration,omitempty"`
+	// Processes is the number of processes using the most CPU to collect in each sample. Defaults to 10.
+	Processes int `json:"processes,omitempty" yaml:"processes,omitempty"`
+}
+
+// HostKernelLogs collects the kernel ring buffer and the journal of a list of systemd units for a time window, for
+// the kernelLogs analyzer to classify the errors they report.
+type HostKernelLogs struct {
+	HostCollectorMeta `json:",inline" yaml:",inline"`
+	// Units whose journal to collect in addition to the kernel's, e.g. "kubelet" or "containerd"
+	Units []string `json:"units,omitempty" yaml:"units,omitempty"`
+	// Since and Until bound the window of the logs, in any format journalctl accepts, e.g. "-24h" or
+	// "2024-01-01 00:00:00". Since defaults to -24h. They are not applied when dmesg is read directly as the
+	// journal is not available.
+	Since string `json:"since,omitempty" yaml:"since,omitempty"`
+	Until string `json:"until,omitempty" yaml:"until,omitempty"`
+	// Lines is the most recent lines to keep of each log. Defaults to 5000.
+	Lines int `json:"lines,omitempty" yaml:"lines,omitempty"`
+}
+
+// HostConnectionStats collects the usage of the conntrack table, the socket counts, the TCP connections by state and
+// the usage of the ephemeral port range of the host.
+type HostConnectionStats struct {
+	HostCollectorMeta `json:",inline" yaml:",inline"`
+}
+
+// HostKubeletConfig collects the config of the kubelet, the cgroup version of the host, the cgroup driver of the
+// kubelet and of the container runtime, and the systemd slices of the cgroup hierarchy.
+type HostKubeletConfig struct {
+	HostCollectorMeta `json:",inline" yaml:",inline"`
+	// KubeletConfigPath is the config file of the kubelet. Defaults to the --config flag of the running kubelet, or
+	// /var/lib/kubelet/config.yaml when it is not running.
+	KubeletConfigPath string `json:"kubeletConfigPath,omitempty" yaml:"kubeletConfigPath,omitempty"`
+	// ContainerdConfigPath is the config file of containerd. Defaults to /etc/containerd/config.toml.
+	ContainerdConfigPath string `json:"containerdConfigPath,omitempty" yaml:"containerdConfigPath,omitempty"`
+}
+
+// HostCRIRuntime collects the status of the container runtime through its CRI socket with crictl: the conditions of
+// the runtime and of its network, the version of the runtime, and the usage of its image filesystems.
+type HostCRIRuntime struct {
+	HostCollectorMeta `json:",inline" yaml:",inline"`
+	// RuntimeEndpoint is the CRI socket of the runtime, e.g. unix:///var/run/crio/crio.sock. Defaults to the container
+	// runtime endpoint of the kubelet.
+	RuntimeEndpoint string `json:"runtimeEndpoint,omitempty" yaml:"runtimeEndpoint,omitempty"`
+}
+
+// HostMetrics samples the CPU, memory, disk and network counters of the host from /proc every interval for a
+// bounded duration, and saves them as time series for analyzers to threshold against. It replaces the collectd
+// collector, which needs collectd running on the host, and makes the collection as long as its duration.
+type HostMetrics struct {
+	HostCollectorMeta `json:",inline" yaml:",inline"`
+	// Interval between samples, e.g. 5s. Defaults to 5s.
+	Interval string `json:"interval,omitempty" yaml:"interval,omitempty"`
+	// Duration of the sampling, e.g. 1m. Defaults to 1m and must not be longer than 10m.
+	Duration string `json:"duration,omitempty" yaml:"duration,omitempty"`
+	// Interfaces are the network interfaces to sample. Defaults to the interfaces backed by a device, which
+	// excludes the loopback, bridges and the virtual interfaces of the pods.
+	Interfaces []string `json:"interfaces,omitempty" yaml:"interfaces,omitempty"`
+}
+
 type HostCollect struct {
 	CPU                          *CPU                              `json:"cpu,omitempty" yaml:"cpu,omitempty"`
 	Memory                       *Memory                           `json:"memory,omitempty" yaml:"memory,omitempty"`
@@ -265,6 +431,22 @@ type HostCollect struct {
 	HostDNS                      *HostDNS                          `json:"dns,omitempty" yaml:"dns,omitempty"`
 	NetworkNamespaceConnectivity *HostNetworkNamespaceConnectivity `json:"networkNamespaceConnectivity,omitempty" yaml:"networkNamespaceConnectivity,omitempty"`
 	HostSysctl                   *HostSysctl                       `json:"sysctl,omitempty" yaml:"sysctl,omitempty"`
+	HostKernelSnapshot           *HostKernelSnapshot               `json:"kernelSnapshot,omitempty" yaml:"kernelSnapshot,omitempty"`
+	HostSystemdUnits             *HostSystemdUnits                 `json:"systemdUnits,omitempty" yaml:"systemdUnits,omitempty"`
+	HostNetworkConfig            *HostNetworkConfig                `json:"networkConfig,omitempty" yaml:"networkConfig,omitempty"`
+	HostGPU                      *HostGPU                          `json:"gpu,omitempty" yaml:"gpu,omitempty"`
+	HostTimeSync                 *HostTimeSync                     `json:"timeSync,omitempty" yaml:"timeSync,omitempty"`
+	HostKubernetesDistribution   *HostKubernetesDistribution       `json:"kubernetesDistribution,omitempty" yaml:"kubernetesDistribution,omitempty"`
+	HostBenchmark                *HostBenchmark                    `json:"benchmark,omitempty" yaml:"benchmark,omitempty"`
+	HostPaths                    *HostPaths                        `json:"paths,omitempty" yaml:"paths,omitempty"`
+	HostSecurity                 *HostSecurity                     `json:"security,omitempty" yaml:"security,omitempty"`
+	HostProxy                    *HostProxy                        `json:"proxy,omitempty" yaml:"proxy,omitempty"`
+	HostActivityCapture          *HostActivityCapture              `json:"activityCapture,omitempty" yaml:"activityCapture,omitempty"`
+	HostKernelLogs               *HostKernelLogs                   `json:"kernelLogs,omitempty" yaml:"kernelLogs,omitempty"`
+	HostConnectionStats          *HostConnectionStats              `json:"connectionStats,omitempty" yaml:"connectionStats,omitempty"`
+	HostKubeletConfig            *HostKubeletConfig                `json:"kubeletConfig,omitempty" yaml:"kubeletConfig,omitempty"`
+	HostMetrics                  *HostMetrics                      `json:"metrics,omitempty" yaml:"metrics,omitempty"`
+	HostCRIRuntime               *HostCRIRuntime                   `json:"criRuntime,omitempty" yaml:"criRuntime,omitempty"`
 }
 
 // GetName gets the name of the collector
@@ -26,6 +26,8 @@ type HostPreflightSpec struct {
 	RemoteCollectors []*RemoteCollect `json:"remoteCollectors,omitempty" yaml:"remoteCollectors,omitempty"`
 	Analyzers        []*HostAnalyze   `json:"analyzers,omitempty" yaml:"analyzers,omitempty"`
 	Uri              string           `json:"uri,omitempty" yaml:"uri,omitempty"`
+	// Notifications are sent when the preflight checks complete.
+	Notifications []*Notification `json:"notifications,omitempty" yaml:"notifications,omitempty"`
 }
 
 // HostPreflightStatus defines the observed state of HostPreflight
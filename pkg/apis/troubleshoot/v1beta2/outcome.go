@@ -1,9 +1,10 @@
 package v1beta2
 
 type SingleOutcome struct {
-	When    string `json:"when,omitempty" yaml:"when,omitempty"`
-	Message string `json:"message,omitempty" yaml:"message,omitempty"`
-	URI     string `json:"uri,omitempty" yaml:"uri,omitempty"`
+	When        string       `json:"when,omitempty" yaml:"when,omitempty"`
+	Message     string       `json:"message,omitempty" yaml:"message,omitempty"`
+	URI         string       `json:"uri,omitempty" yaml:"uri,omitempty"`
+	Remediation *Remediation `json:"remediation,omitempty" yaml:"remediation,omitempty"`
 }
 
 type Outcome struct {
@@ -11,3 +12,28 @@ type Outcome struct {
 	Warn *SingleOutcome `json:"warn,omitempty" yaml:"warn,omitempty"`
 	Pass *SingleOutcome `json:"pass,omitempty" yaml:"pass,omitempty"`
 }
+
+const (
+	RemediationSeverityLow      = "low"
+	RemediationSeverityMedium   = "medium"
+	RemediationSeverityHigh     = "high"
+	RemediationSeverityCritical = "critical"
+
+	RemediationAutomationManual    = "manual"
+	RemediationAutomationApproval  = "approval"
+	RemediationAutomationAutomatic = "automatic"
+)
+
+// Remediation describes how to fix the condition reported by an outcome, so that the message can
+// stay a short description of the problem.
+type Remediation struct {
+	// Command is a shell command that fixes the condition, e.g. "sudo swapoff -a".
+	Command string `json:"command,omitempty" yaml:"command,omitempty"`
+	// DocLink is a link to documentation describing the fix.
+	DocLink string `json:"docLink,omitempty" yaml:"docLink,omitempty"`
+	// Severity is one of low, medium, high or critical.
+	Severity string `json:"severity,omitempty" yaml:"severity,omitempty"`
+	// Automation hints whether the command is safe to run without a person reviewing it. One of
+	// manual, approval or automatic.
+	Automation string `json:"automation,omitempty" yaml:"automation,omitempty"`
+}
@@ -27,6 +27,10 @@ type PreflightSpec struct {
 	RemoteCollectors []*RemoteCollect `json:"remoteCollectors,omitempty" yaml:"remoteCollectors,omitempty"`
 	Analyzers        []*Analyze       `json:"analyzers,omitempty" yaml:"analyzers,omitempty"`
 	Uri              string           `json:"uri,omitempty" yaml:"uri,omitempty"`
+	// Notifications are sent when the preflight checks complete.
+	Notifications []*Notification `json:"notifications,omitempty" yaml:"notifications,omitempty"`
+	// Include loads other preflight specs, in order, as a base the rest of this spec adds to.
+	Include []*SpecInclude `json:"include,omitempty" yaml:"include,omitempty"`
 }
 
 // PreflightStatus defines the observed state of Preflight
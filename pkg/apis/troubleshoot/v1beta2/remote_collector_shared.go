@@ -178,19 +178,44 @@ type RemoteCollect struct {
 }
 
 func (c *RemoteCollect) AccessReviewSpecs(overrideNS string) []authorizationv1.SelfSubjectAccessReviewSpec {
+	// remote collectors run in the pods of a DaemonSet and are executed through the API server
 	return []authorizationv1.SelfSubjectAccessReviewSpec{
 		{
 			ResourceAttributes: &authorizationv1.ResourceAttributes{
 				Namespace:   overrideNS,
 				Verb:        "create,delete",
+				Group:       "apps",
+				Version:     "",
+				Resource:    "daemonsets",
+				Subresource: "",
+				Name:        "",
+			},
+			NonResourceAttributes: nil,
+		},
+		{
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Namespace:   overrideNS,
+				Verb:        "list",
 				Group:       "",
 				Version:     "",
-				Resource:    "pods,configmap",
+				Resource:    "pods",
 				Subresource: "",
 				Name:        "",
 			},
 			NonResourceAttributes: nil,
 		},
+		{
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Namespace:   overrideNS,
+				Verb:        "create",
+				Group:       "",
+				Version:     "",
+				Resource:    "pods",
+				Subresource: "exec",
+				Name:        "",
+			},
+			NonResourceAttributes: nil,
+		},
 	}
 }
 
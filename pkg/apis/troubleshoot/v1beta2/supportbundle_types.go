@@ -30,8 +30,130 @@ type SupportBundleSpec struct {
 	// URI optionally defines a location which is the source of this spec to allow updating of the spec at runtime
 	Uri                    string `json:"uri,omitempty" yaml:"uri,omitempty"`
 	RunHostCollectorsInPod bool   `json:"runHostCollectorsInPod,omitempty" yaml:"runHostCollectorsInPod,omitempty"`
+	// Scope is either cluster, the default, or namespaced. In namespaced scope collectors skip
+	// cluster-scoped resources and only read from Namespaces, so that users who can only access
+	// some namespaces still get a useful bundle. Namespaces defaults to the namespace of the run.
+	Scope      string   `json:"scope,omitempty" yaml:"scope,omitempty"`
+	Namespaces []string `json:"namespaces,omitempty" yaml:"namespaces,omitempty"`
+	// MaxSize is the most the collected files can take in the bundle, before compression, as a
+	// quantity (e.g. 1Gi). It applies after the maxSize of each collector.
+	MaxSize string `json:"maxSize,omitempty" yaml:"maxSize,omitempty"`
+	// Profile is either full, the default, or metadataOnly. The metadataOnly profile replaces
+	// payloads, such as logs, the output of commands and the values of configmaps and secrets,
+	// with their size, line count and sha256, and keeps the specs and statuses of resources.
+	Profile string `json:"profile,omitempty" yaml:"profile,omitempty"`
+	// Notifications are sent when the bundle has been collected and analyzed.
+	Notifications []*Notification `json:"notifications,omitempty" yaml:"notifications,omitempty"`
+	// PostCollection processes the bundle, in order, once it has been collected and analyzed and
+	// before it is archived.
+	PostCollection []*PostCollection `json:"postCollection,omitempty" yaml:"postCollection,omitempty"`
+	// Include loads other support bundle specs, in order, as a base the rest of this spec adds to.
+	Include []*SpecInclude `json:"include,omitempty" yaml:"include,omitempty"`
+	// AllowedOperations declare the commands the collectors run and the files they copy, in pods,
+	// on nodes and on hosts. When set, the collection fails before anything is collected if a
+	// collector runs a command or copies a file that is not declared, so that what the spec
+	// touches can be audited.
+	AllowedOperations []*AllowedOperation `json:"allowedOperations,omitempty" yaml:"allowedOperations,omitempty"`
+	// Documentation is shipped with the spec and embedded in the bundle, so that users without
+	// access to external URLs get remediation guidance. Outcomes reference a document with the
+	// uri or remediation docLink doc:<id>.
+	Documentation []*Documentation `json:"documentation,omitempty" yaml:"documentation,omitempty"`
 }
 
+// Documentation is a markdown snippet analyzer outcomes can reference by its ID.
+type Documentation struct {
+	// ID references the document from outcomes, as doc:<id>. It may contain letters, digits, dots,
+	// dashes and underscores.
+	ID    string `json:"id" yaml:"id"`
+	Title string `json:"title,omitempty" yaml:"title,omitempty"`
+	// Markdown is the content of the document.
+	Markdown string `json:"markdown" yaml:"markdown"`
+}
+
+// AllowedOperation allows the operations of the collectors of a type whose commands, paths and
+// namespaces match. Patterns are globs where * matches any text, e.g. "pg_dump *".
+type AllowedOperation struct {
+	// Type is the type of the collectors, one of exec, run, runPod, runDaemonSet, copy,
+	// copyFromHost, hostRun or hostCopy. Plugin collectors are exec operations whose command is
+	// troubleshoot-plugin-<name> collect followed by their args.
+	Type string `json:"type" yaml:"type"`
+	// Commands are patterns of the commands, with their arguments separated by spaces, the
+	// collectors may run. Defaults to any command.
+	Commands []string `json:"commands,omitempty" yaml:"commands,omitempty"`
+	// Paths are patterns of the files and directories the collectors may copy. Defaults to any
+	// path.
+	Paths []string `json:"paths,omitempty" yaml:"paths,omitempty"`
+	// Namespaces are patterns of the namespaces the collectors may run in. Defaults to any
+	// namespace.
+	Namespaces []string `json:"namespaces,omitempty" yaml:"namespaces,omitempty"`
+}
+
+// PostCollection runs a processor over the files of a bundle. Exactly one of the fields is set.
+type PostCollection struct {
+	// Summary writes a markdown summary of the files and analysis results of the bundle.
+	Summary *PostCollectionSummary `json:"summary,omitempty" yaml:"summary,omitempty"`
+	// Report writes a self-contained report of the analysis results, cluster inventory, warnings
+	// and log excerpts of the bundle, as markdown or HTML.
+	Report *PostCollectionReport `json:"report,omitempty" yaml:"report,omitempty"`
+	// Statistics writes the number and size of the files of the bundle, by directory and extension, as JSON.
+	Statistics *PostCollectionStatistics `json:"statistics,omitempty" yaml:"statistics,omitempty"`
+	// StripFiles removes files from the bundle.
+	StripFiles *PostCollectionStripFiles `json:"stripFiles,omitempty" yaml:"stripFiles,omitempty"`
+	// Processor runs a processor registered by the program that collects the bundle.
+	Processor *PostCollectionProcessor `json:"processor,omitempty" yaml:"processor,omitempty"`
+}
+
+type PostCollectionSummary struct {
+	// Path of the summary in the bundle, defaults to summary.md.
+	Path string `json:"path,omitempty" yaml:"path,omitempty"`
+}
+
+type PostCollectionReport struct {
+	// Format of the report, markdown or html. Defaults to markdown.
+	Format string `json:"format,omitempty" yaml:"format,omitempty"`
+	// Path of the report in the bundle, defaults to report.md or report.html depending on the format.
+	Path string `json:"path,omitempty" yaml:"path,omitempty"`
+}
+
+type PostCollectionStatistics struct {
+	// Path of the statistics in the bundle, defaults to statistics.json.
+	Path string `json:"path,omitempty" yaml:"path,omitempty"`
+}
+
+type PostCollectionStripFiles struct {
+	// Files are glob patterns of the paths to remove, relative to the root of the bundle, e.g.
+	// "cluster-resources/pods/logs/**/*-previous.log".
+	Files []string `json:"files" yaml:"files"`
+}
+
+type PostCollectionProcessor struct {
+	// Name the processor is registered under.
+	Name string `json:"name" yaml:"name"`
+	// Args are passed to the processor.
+	Args map[string]string `json:"args,omitempty" yaml:"args,omitempty"`
+}
+
+const (
+	CollectionScopeCluster    = "cluster"
+	CollectionScopeNamespaced = "namespaced"
+)
+
+const (
+	CollectionProfileFull         = "full"
+	CollectionProfileMetadataOnly = "metadataOnly"
+)
+
+const (
+	OperationTypeExec         = "exec"
+	OperationTypeRun          = "run"
+	OperationTypeRunPod       = "runPod"
+	OperationTypeRunDaemonSet = "runDaemonSet"
+	OperationTypeCopy         = "copy"
+	OperationTypeCopyFromHost = "copyFromHost"
+	OperationTypeHostRun      = "hostRun"
+	OperationTypeHostCopy     = "hostCopy"
+)
+
 // SupportBundleStatus defines the observed state of SupportBundle
 type SupportBundleStatus struct {
 	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
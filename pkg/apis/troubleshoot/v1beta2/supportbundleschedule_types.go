@@ -0,0 +1,157 @@
+/*
+Copyright 2019 Replicated, Inc..
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta2
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SupportBundleScheduleSpec defines the desired state of SupportBundleSchedule
+type SupportBundleScheduleSpec struct {
+	// Schedule is the cron schedule to collect support bundles on, e.g. "0 */6 * * *". It can be
+	// left empty to only collect when one of the triggers matches.
+	Schedule string `json:"schedule,omitempty" yaml:"schedule,omitempty"`
+	// Suspend stops scheduling new collections, bundles that were already collected are kept.
+	Suspend bool `json:"suspend,omitempty" yaml:"suspend,omitempty"`
+	// Retain is the number of most recent bundles to keep, defaults to 5.
+	Retain int `json:"retain,omitempty" yaml:"retain,omitempty"`
+	// RetainFor removes the bundles that were collected longer ago than this, e.g. 168h.
+	RetainFor *metav1.Duration `json:"retainFor,omitempty" yaml:"retainFor,omitempty"`
+	// RetainSize is the total size of the bundles to keep, e.g. 10Gi. The most recent bundle is
+	// kept even when it is larger on its own.
+	RetainSize string `json:"retainSize,omitempty" yaml:"retainSize,omitempty"`
+	// SupportBundle is the spec of the support bundles to collect.
+	SupportBundle SupportBundleSpec      `json:"supportBundle" yaml:"supportBundle"`
+	Storage       ScheduledBundleStorage `json:"storage" yaml:"storage"`
+	// Image is the troubleshoot image that runs the collection, defaults to replicated/troubleshoot:latest.
+	Image string `json:"image,omitempty" yaml:"image,omitempty"`
+	// ServiceAccountName is the service account the collection runs as. It needs permissions to
+	// read the SupportBundleSchedule and update its status, along with those of the collectors.
+	ServiceAccountName string `json:"serviceAccountName,omitempty" yaml:"serviceAccountName,omitempty"`
+	// Triggers start a collection outside of the schedule when a condition is observed in the cluster.
+	Triggers []CollectionTrigger `json:"triggers,omitempty" yaml:"triggers,omitempty"`
+	// TriggerCooldown is the minimum time between triggered collections, defaults to 30m.
+	TriggerCooldown *metav1.Duration `json:"triggerCooldown,omitempty" yaml:"triggerCooldown,omitempty"`
+	// Notification is sent when a collection completes.
+	Notification *ScheduledBundleNotification `json:"notification,omitempty" yaml:"notification,omitempty"`
+}
+
+// CollectionTrigger is a condition that starts a collection. Exactly one of the fields is set.
+type CollectionTrigger struct {
+	CrashLoopBackOff *CrashLoopBackOffTrigger `json:"crashLoopBackOff,omitempty" yaml:"crashLoopBackOff,omitempty"`
+	NodeNotReady     *NodeNotReadyTrigger     `json:"nodeNotReady,omitempty" yaml:"nodeNotReady,omitempty"`
+	Event            *EventTrigger            `json:"event,omitempty" yaml:"event,omitempty"`
+}
+
+// CrashLoopBackOffTrigger matches pods with a container in CrashLoopBackOff.
+type CrashLoopBackOffTrigger struct {
+	// Selector is the labels of the pods to match.
+	Selector []string `json:"selector" yaml:"selector"`
+	// Namespaces to match pods in, defaults to the namespace of the schedule.
+	Namespaces []string `json:"namespaces,omitempty" yaml:"namespaces,omitempty"`
+}
+
+// NodeNotReadyTrigger matches nodes whose Ready condition is not true.
+type NodeNotReadyTrigger struct {
+	// Selector is the labels of the nodes to match, all nodes are matched when it is empty.
+	Selector []string `json:"selector,omitempty" yaml:"selector,omitempty"`
+}
+
+// EventTrigger matches events with one of the reasons.
+type EventTrigger struct {
+	Reasons []string `json:"reasons" yaml:"reasons"`
+	// InvolvedObjectKind limits the events to those about objects of this kind, e.g. Pod.
+	InvolvedObjectKind string `json:"involvedObjectKind,omitempty" yaml:"involvedObjectKind,omitempty"`
+	// Namespaces to match events in, defaults to the namespace of the schedule.
+	Namespaces []string `json:"namespaces,omitempty" yaml:"namespaces,omitempty"`
+}
+
+// ScheduledBundleNotification posts the result of each collection as JSON to a webhook.
+type ScheduledBundleNotification struct {
+	URI string `json:"uri" yaml:"uri"`
+	// TokenSecretRef is a secret key holding a bearer token sent with the request.
+	TokenSecretRef *corev1.SecretKeySelector `json:"tokenSecretRef,omitempty" yaml:"tokenSecretRef,omitempty"`
+}
+
+// ScheduledBundleStorage is where scheduled bundles are kept. Exactly one of the fields is set.
+type ScheduledBundleStorage struct {
+	PersistentVolumeClaim *ScheduledBundlePersistentVolumeClaim `json:"persistentVolumeClaim,omitempty" yaml:"persistentVolumeClaim,omitempty"`
+	ObjectStorage         *ScheduledBundleObjectStorage         `json:"objectStorage,omitempty" yaml:"objectStorage,omitempty"`
+}
+
+type ScheduledBundlePersistentVolumeClaim struct {
+	ClaimName string `json:"claimName" yaml:"claimName"`
+	// SubPath is the directory within the volume to write bundles to.
+	SubPath string `json:"subPath,omitempty" yaml:"subPath,omitempty"`
+}
+
+// ScheduledBundleObjectStorage stores bundles with HTTP PUT requests to a base URL, and removes
+// bundles past the retention limit with DELETE requests.
+type ScheduledBundleObjectStorage struct {
+	URI string `json:"uri" yaml:"uri"`
+	// TokenSecretRef is a secret key holding a bearer token sent with each request.
+	TokenSecretRef *corev1.SecretKeySelector `json:"tokenSecretRef,omitempty" yaml:"tokenSecretRef,omitempty"`
+}
+
+// SupportBundleScheduleStatus defines the observed state of SupportBundleSchedule
+type SupportBundleScheduleStatus struct {
+	LastScheduleTime   *metav1.Time `json:"lastScheduleTime,omitempty"`
+	LastSuccessfulTime *metav1.Time `json:"lastSuccessfulTime,omitempty"`
+	// LastTriggerTime is when a trigger last started a collection.
+	LastTriggerTime *metav1.Time `json:"lastTriggerTime,omitempty"`
+	// LastError is the error of the most recent collection, it is empty when the collection had none.
+	LastError string `json:"lastError,omitempty"`
+	// Bundles are the retained bundles, most recent first.
+	Bundles []ScheduledBundle `json:"bundles,omitempty"`
+}
+
+type ScheduledBundle struct {
+	Name        string      `json:"name"`
+	Location    string      `json:"location"`
+	CollectedAt metav1.Time `json:"collectedAt"`
+	Size        int64       `json:"size,omitempty"`
+	// Trigger describes the condition that started the collection, it is empty for scheduled collections.
+	Trigger string `json:"trigger,omitempty"`
+}
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// SupportBundleSchedule is the Schema for the SupportBundleSchedules API
+// +k8s:openapi-gen=true
+// +kubebuilder:subresource:status
+type SupportBundleSchedule struct {
+	metav1.TypeMeta   `json:",inline" yaml:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty" yaml:"metadata,omitempty"`
+
+	Spec   SupportBundleScheduleSpec   `json:"spec,omitempty" yaml:"spec,omitempty"`
+	Status SupportBundleScheduleStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// SupportBundleScheduleList contains a list of SupportBundleSchedule
+type SupportBundleScheduleList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []SupportBundleSchedule `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&SupportBundleSchedule{}, &SupportBundleScheduleList{})
+}
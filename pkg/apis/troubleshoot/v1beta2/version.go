@@ -2,6 +2,9 @@ package v1beta2
 
 type SupportBundleVersionSpec struct {
 	VersionNumber string `json:"versionNumber" yaml:"versionNumber"`
+	// LayoutVersion is the version of the directory structure of the bundle.
+	// It is empty for bundles created before layouts were versioned.
+	LayoutVersion string `json:"layoutVersion,omitempty" yaml:"layoutVersion,omitempty"`
 }
 
 type SupportBundleVersion struct {
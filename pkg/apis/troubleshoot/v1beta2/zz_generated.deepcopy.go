@@ -22,10 +22,38 @@ package v1beta2
 
 import (
 	"github.com/replicatedhq/troubleshoot/pkg/multitype"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ActivityCaptureAnalyze) DeepCopyInto(out *ActivityCaptureAnalyze) {
+	*out = *in
+	in.AnalyzeMeta.DeepCopyInto(&out.AnalyzeMeta)
+	if in.Outcomes != nil {
+		in, out := &in.Outcomes, &out.Outcomes
+		*out = make([]*Outcome, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(Outcome)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ActivityCaptureAnalyze.
+func (in *ActivityCaptureAnalyze) DeepCopy() *ActivityCaptureAnalyze {
+	if in == nil {
+		return nil
+	}
+	out := new(ActivityCaptureAnalyze)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AfterCollection) DeepCopyInto(out *AfterCollection) {
 	*out = *in
@@ -51,6 +79,36 @@ func (in *AfterCollection) DeepCopy() *AfterCollection {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AllowedOperation) DeepCopyInto(out *AllowedOperation) {
+	*out = *in
+	if in.Commands != nil {
+		in, out := &in.Commands, &out.Commands
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Paths != nil {
+		in, out := &in.Paths, &out.Paths
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AllowedOperation.
+func (in *AllowedOperation) DeepCopy() *AllowedOperation {
+	if in == nil {
+		return nil
+	}
+	out := new(AllowedOperation)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Analyze) DeepCopyInto(out *Analyze) {
 	*out = *in
@@ -59,6 +117,11 @@ func (in *Analyze) DeepCopyInto(out *Analyze) {
 		*out = new(ClusterVersion)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.KubernetesUpgrade != nil {
+		in, out := &in.KubernetesUpgrade, &out.KubernetesUpgrade
+		*out = new(KubernetesUpgrade)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.StorageClass != nil {
 		in, out := &in.StorageClass, &out.StorageClass
 		*out = new(StorageClass)
@@ -99,6 +162,11 @@ func (in *Analyze) DeepCopyInto(out *Analyze) {
 		*out = new(StatefulsetStatus)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.StatefulSetVolumes != nil {
+		in, out := &in.StatefulSetVolumes, &out.StatefulSetVolumes
+		*out = new(StatefulSetVolumes)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.JobStatus != nil {
 		in, out := &in.JobStatus, &out.JobStatus
 		*out = new(JobStatus)
@@ -134,6 +202,21 @@ func (in *Analyze) DeepCopyInto(out *Analyze) {
 		*out = new(NodeResources)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ResourceQuota != nil {
+		in, out := &in.ResourceQuota, &out.ResourceQuota
+		*out = new(ResourceQuotaAnalyze)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PodDisruptionBudget != nil {
+		in, out := &in.PodDisruptionBudget, &out.PodDisruptionBudget
+		*out = new(PodDisruptionBudget)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ConfigMapsAndSecrets != nil {
+		in, out := &in.ConfigMapsAndSecrets, &out.ConfigMapsAndSecrets
+		*out = new(ConfigMapsAndSecrets)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.TextAnalyze != nil {
 		in, out := &in.TextAnalyze, &out.TextAnalyze
 		*out = new(TextAnalyze)
@@ -149,6 +232,11 @@ func (in *Analyze) DeepCopyInto(out *Analyze) {
 		*out = new(JsonCompare)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.JSONQuery != nil {
+		in, out := &in.JSONQuery, &out.JSONQuery
+		*out = new(JSONQueryAnalyze)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Postgres != nil {
 		in, out := &in.Postgres, &out.Postgres
 		*out = new(DatabaseAnalyze)
@@ -169,6 +257,26 @@ func (in *Analyze) DeepCopyInto(out *Analyze) {
 		*out = new(DatabaseAnalyze)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Kafka != nil {
+		in, out := &in.Kafka, &out.Kafka
+		*out = new(KafkaAnalyze)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RabbitMQ != nil {
+		in, out := &in.RabbitMQ, &out.RabbitMQ
+		*out = new(RabbitMQAnalyze)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LDAP != nil {
+		in, out := &in.LDAP, &out.LDAP
+		*out = new(LDAPAnalyze)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.OIDC != nil {
+		in, out := &in.OIDC, &out.OIDC
+		*out = new(OIDCAnalyze)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.CephStatus != nil {
 		in, out := &in.CephStatus, &out.CephStatus
 		*out = new(CephStatusAnalyze)
@@ -189,6 +297,16 @@ func (in *Analyze) DeepCopyInto(out *Analyze) {
 		*out = new(RegistryImagesAnalyze)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ImagePlatforms != nil {
+		in, out := &in.ImagePlatforms, &out.ImagePlatforms
+		*out = new(ImagePlatformsAnalyze)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MutableImageTags != nil {
+		in, out := &in.MutableImageTags, &out.MutableImageTags
+		*out = new(MutableImageTags)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.ImageSignatures != nil {
 		in, out := &in.ImageSignatures, &out.ImageSignatures
 		*out = new(ImageSignaturesAnalyze)
@@ -219,6 +337,21 @@ func (in *Analyze) DeepCopyInto(out *Analyze) {
 		*out = new(GoldpingerAnalyze)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.NodeLatency != nil {
+		in, out := &in.NodeLatency, &out.NodeLatency
+		*out = new(NodeLatencyAnalyze)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ClusterOperatorStatus != nil {
+		in, out := &in.ClusterOperatorStatus, &out.ClusterOperatorStatus
+		*out = new(ClusterOperatorStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MachineConfigPoolStatus != nil {
+		in, out := &in.MachineConfigPoolStatus, &out.MachineConfigPoolStatus
+		*out = new(MachineConfigPoolStatus)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Event != nil {
 		in, out := &in.Event, &out.Event
 		*out = new(EventAnalyze)
@@ -234,6 +367,76 @@ func (in *Analyze) DeepCopyInto(out *Analyze) {
 		*out = new(HTTPAnalyze)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Plugin != nil {
+		in, out := &in.Plugin, &out.Plugin
+		*out = new(PluginAnalyze)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Wasm != nil {
+		in, out := &in.Wasm, &out.Wasm
+		*out = new(WasmAnalyze)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Composite != nil {
+		in, out := &in.Composite, &out.Composite
+		*out = new(CompositeAnalyze)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Proxy != nil {
+		in, out := &in.Proxy, &out.Proxy
+		*out = new(ProxyAnalyze)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.VeleroReadiness != nil {
+		in, out := &in.VeleroReadiness, &out.VeleroReadiness
+		*out = new(VeleroReadinessAnalyze)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TrustChains != nil {
+		in, out := &in.TrustChains, &out.TrustChains
+		*out = new(TrustChainsAnalyze)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ImageLayers != nil {
+		in, out := &in.ImageLayers, &out.ImageLayers
+		*out = new(ImageLayersAnalyze)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ImageFacts != nil {
+		in, out := &in.ImageFacts, &out.ImageFacts
+		*out = new(ImageFactsAnalyze)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.KubeletMetrics != nil {
+		in, out := &in.KubeletMetrics, &out.KubeletMetrics
+		*out = new(KubeletMetricsAnalyze)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Rego != nil {
+		in, out := &in.Rego, &out.Rego
+		*out = new(RegoAnalyze)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PolicyViolations != nil {
+		in, out := &in.PolicyViolations, &out.PolicyViolations
+		*out = new(PolicyViolationsAnalyze)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DiskPressure != nil {
+		in, out := &in.DiskPressure, &out.DiskPressure
+		*out = new(DiskPressureAnalyze)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PodSecurity != nil {
+		in, out := &in.PodSecurity, &out.PodSecurity
+		*out = new(PodSecurityAnalyze)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ManagedCluster != nil {
+		in, out := &in.ManagedCluster, &out.ManagedCluster
+		*out = new(ManagedClusterAnalyze)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Analyze.
@@ -293,6 +496,11 @@ func (in *AnalyzeMeta) DeepCopyInto(out *AnalyzeMeta) {
 			(*out)[key] = val
 		}
 	}
+	if in.Categories != nil {
+		in, out := &in.Categories, &out.Categories
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AnalyzeMeta.
@@ -443,6 +651,33 @@ func (in *AnalyzerStatus) DeepCopy() *AnalyzerStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BenchmarkAnalyze) DeepCopyInto(out *BenchmarkAnalyze) {
+	*out = *in
+	in.AnalyzeMeta.DeepCopyInto(&out.AnalyzeMeta)
+	if in.Outcomes != nil {
+		in, out := &in.Outcomes, &out.Outcomes
+		*out = make([]*Outcome, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(Outcome)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BenchmarkAnalyze.
+func (in *BenchmarkAnalyze) DeepCopy() *BenchmarkAnalyze {
+	if in == nil {
+		return nil
+	}
+	out := new(BenchmarkAnalyze)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BlockDevicesAnalyze) DeepCopyInto(out *BlockDevicesAnalyze) {
 	*out = *in
@@ -513,6 +748,33 @@ func (in *CPUAnalyze) DeepCopy() *CPUAnalyze {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CRIRuntimeAnalyze) DeepCopyInto(out *CRIRuntimeAnalyze) {
+	*out = *in
+	in.AnalyzeMeta.DeepCopyInto(&out.AnalyzeMeta)
+	if in.Outcomes != nil {
+		in, out := &in.Outcomes, &out.Outcomes
+		*out = make([]*Outcome, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(Outcome)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CRIRuntimeAnalyze.
+func (in *CRIRuntimeAnalyze) DeepCopy() *CRIRuntimeAnalyze {
+	if in == nil {
+		return nil
+	}
+	out := new(CRIRuntimeAnalyze)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Ceph) DeepCopyInto(out *Ceph) {
 	*out = *in
@@ -724,6 +986,38 @@ func (in *ClusterInfo) DeepCopy() *ClusterInfo {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterOperatorStatus) DeepCopyInto(out *ClusterOperatorStatus) {
+	*out = *in
+	in.AnalyzeMeta.DeepCopyInto(&out.AnalyzeMeta)
+	if in.Operators != nil {
+		in, out := &in.Operators, &out.Operators
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Outcomes != nil {
+		in, out := &in.Outcomes, &out.Outcomes
+		*out = make([]*Outcome, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(Outcome)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterOperatorStatus.
+func (in *ClusterOperatorStatus) DeepCopy() *ClusterOperatorStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterOperatorStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterPodStatuses) DeepCopyInto(out *ClusterPodStatuses) {
 	*out = *in
@@ -792,6 +1086,16 @@ func (in *ClusterResources) DeepCopyInto(out *ClusterResources) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.IncludeResources != nil {
+		in, out := &in.IncludeResources, &out.IncludeResources
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExcludeResources != nil {
+		in, out := &in.ExcludeResources, &out.ExcludeResources
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterResources.
@@ -924,6 +1228,26 @@ func (in *Collect) DeepCopyInto(out *Collect) {
 		*out = new(Database)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Kafka != nil {
+		in, out := &in.Kafka, &out.Kafka
+		*out = new(Kafka)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RabbitMQ != nil {
+		in, out := &in.RabbitMQ, &out.RabbitMQ
+		*out = new(RabbitMQ)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LDAP != nil {
+		in, out := &in.LDAP, &out.LDAP
+		*out = new(LDAP)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.OIDC != nil {
+		in, out := &in.OIDC, &out.OIDC
+		*out = new(OIDC)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Collectd != nil {
 		in, out := &in.Collectd, &out.Collectd
 		*out = new(Collectd)
@@ -969,6 +1293,16 @@ func (in *Collect) DeepCopyInto(out *Collect) {
 		*out = new(Goldpinger)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.NodeLatency != nil {
+		in, out := &in.NodeLatency, &out.NodeLatency
+		*out = new(NodeLatency)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.OpenShift != nil {
+		in, out := &in.OpenShift, &out.OpenShift
+		*out = new(OpenShift)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Sonobuoy != nil {
 		in, out := &in.Sonobuoy, &out.Sonobuoy
 		*out = new(Sonobuoy)
@@ -989,6 +1323,61 @@ func (in *Collect) DeepCopyInto(out *Collect) {
 		*out = new(Etcd)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Plugin != nil {
+		in, out := &in.Plugin, &out.Plugin
+		*out = new(PluginCollector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Proxy != nil {
+		in, out := &in.Proxy, &out.Proxy
+		*out = new(Proxy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Pprof != nil {
+		in, out := &in.Pprof, &out.Pprof
+		*out = new(Pprof)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Velero != nil {
+		in, out := &in.Velero, &out.Velero
+		*out = new(Velero)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.KubeletConfig != nil {
+		in, out := &in.KubeletConfig, &out.KubeletConfig
+		*out = new(KubeletConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TrustChains != nil {
+		in, out := &in.TrustChains, &out.TrustChains
+		*out = new(TrustChains)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ImageLayers != nil {
+		in, out := &in.ImageLayers, &out.ImageLayers
+		*out = new(ImageLayers)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ImageFacts != nil {
+		in, out := &in.ImageFacts, &out.ImageFacts
+		*out = new(ImageFacts)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.KubeletMetrics != nil {
+		in, out := &in.KubeletMetrics, &out.KubeletMetrics
+		*out = new(KubeletMetrics)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PolicyViolations != nil {
+		in, out := &in.PolicyViolations, &out.PolicyViolations
+		*out = new(PolicyViolations)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ManagedCluster != nil {
+		in, out := &in.ManagedCluster, &out.ManagedCluster
+		*out = new(ManagedCluster)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Collect.
@@ -1049,6 +1438,36 @@ func (in *CollectdAnalyze) DeepCopy() *CollectdAnalyze {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CollectionTrigger) DeepCopyInto(out *CollectionTrigger) {
+	*out = *in
+	if in.CrashLoopBackOff != nil {
+		in, out := &in.CrashLoopBackOff, &out.CrashLoopBackOff
+		*out = new(CrashLoopBackOffTrigger)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NodeNotReady != nil {
+		in, out := &in.NodeNotReady, &out.NodeNotReady
+		*out = new(NodeNotReadyTrigger)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Event != nil {
+		in, out := &in.Event, &out.Event
+		*out = new(EventTrigger)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CollectionTrigger.
+func (in *CollectionTrigger) DeepCopy() *CollectionTrigger {
+	if in == nil {
+		return nil
+	}
+	out := new(CollectionTrigger)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Collector) DeepCopyInto(out *Collector) {
 	*out = *in
@@ -1192,16 +1611,97 @@ func (in *CollectorStatus) DeepCopy() *CollectorStatus {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ConfigMap) DeepCopyInto(out *ConfigMap) {
+func (in *CompositeAnalyze) DeepCopyInto(out *CompositeAnalyze) {
 	*out = *in
-	in.CollectorMeta.DeepCopyInto(&out.CollectorMeta)
-	if in.Selector != nil {
-		in, out := &in.Selector, &out.Selector
-		*out = make([]string, len(*in))
-		copy(*out, *in)
+	in.AnalyzeMeta.DeepCopyInto(&out.AnalyzeMeta)
+	if in.AllOf != nil {
+		in, out := &in.AllOf, &out.AllOf
+		*out = make([]*CompositeChild, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(CompositeChild)
+				(*in).DeepCopyInto(*out)
+			}
+		}
 	}
-}
-
+	if in.AnyOf != nil {
+		in, out := &in.AnyOf, &out.AnyOf
+		*out = make([]*CompositeChild, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(CompositeChild)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+	if in.NoneOf != nil {
+		in, out := &in.NoneOf, &out.NoneOf
+		*out = make([]*CompositeChild, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(CompositeChild)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+	if in.Outcomes != nil {
+		in, out := &in.Outcomes, &out.Outcomes
+		*out = make([]*Outcome, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(Outcome)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CompositeAnalyze.
+func (in *CompositeAnalyze) DeepCopy() *CompositeAnalyze {
+	if in == nil {
+		return nil
+	}
+	out := new(CompositeAnalyze)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CompositeChild) DeepCopyInto(out *CompositeChild) {
+	*out = *in
+	in.Analyze.DeepCopyInto(&out.Analyze)
+	if in.Weight != nil {
+		in, out := &in.Weight, &out.Weight
+		*out = new(int)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CompositeChild.
+func (in *CompositeChild) DeepCopy() *CompositeChild {
+	if in == nil {
+		return nil
+	}
+	out := new(CompositeChild)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigMap) DeepCopyInto(out *ConfigMap) {
+	*out = *in
+	in.CollectorMeta.DeepCopyInto(&out.CollectorMeta)
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigMap.
 func (in *ConfigMap) DeepCopy() *ConfigMap {
 	if in == nil {
@@ -1212,6 +1712,65 @@ func (in *ConfigMap) DeepCopy() *ConfigMap {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigMapsAndSecrets) DeepCopyInto(out *ConfigMapsAndSecrets) {
+	*out = *in
+	in.AnalyzeMeta.DeepCopyInto(&out.AnalyzeMeta)
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Outcomes != nil {
+		in, out := &in.Outcomes, &out.Outcomes
+		*out = make([]*Outcome, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(Outcome)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigMapsAndSecrets.
+func (in *ConfigMapsAndSecrets) DeepCopy() *ConfigMapsAndSecrets {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigMapsAndSecrets)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConnectionStatsAnalyze) DeepCopyInto(out *ConnectionStatsAnalyze) {
+	*out = *in
+	in.AnalyzeMeta.DeepCopyInto(&out.AnalyzeMeta)
+	if in.Outcomes != nil {
+		in, out := &in.Outcomes, &out.Outcomes
+		*out = make([]*Outcome, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(Outcome)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConnectionStatsAnalyze.
+func (in *ConnectionStatsAnalyze) DeepCopy() *ConnectionStatsAnalyze {
+	if in == nil {
+		return nil
+	}
+	out := new(ConnectionStatsAnalyze)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ContainerRuntime) DeepCopyInto(out *ContainerRuntime) {
 	*out = *in
@@ -1269,6 +1828,7 @@ func (in *CopyFromHost) DeepCopyInto(out *CopyFromHost) {
 		*out = new(ImagePullSecrets)
 		(*in).DeepCopyInto(*out)
 	}
+	in.PodScheduling.DeepCopyInto(&out.PodScheduling)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CopyFromHost.
@@ -1281,6 +1841,46 @@ func (in *CopyFromHost) DeepCopy() *CopyFromHost {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CrashLoopBackOffTrigger) DeepCopyInto(out *CrashLoopBackOffTrigger) {
+	*out = *in
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CrashLoopBackOffTrigger.
+func (in *CrashLoopBackOffTrigger) DeepCopy() *CrashLoopBackOffTrigger {
+	if in == nil {
+		return nil
+	}
+	out := new(CrashLoopBackOffTrigger)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CredentialSecret) DeepCopyInto(out *CredentialSecret) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CredentialSecret.
+func (in *CredentialSecret) DeepCopy() *CredentialSecret {
+	if in == nil {
+		return nil
+	}
+	out := new(CredentialSecret)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CustomMetrics) DeepCopyInto(out *CustomMetrics) {
 	*out = *in
@@ -1333,6 +1933,7 @@ func (in *CustomResourceDefinition) DeepCopy() *CustomResourceDefinition {
 func (in *DNS) DeepCopyInto(out *DNS) {
 	*out = *in
 	in.CollectorMeta.DeepCopyInto(&out.CollectorMeta)
+	in.PodScheduling.DeepCopyInto(&out.PodScheduling)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DNS.
@@ -1365,6 +1966,11 @@ func (in *Data) DeepCopy() *Data {
 func (in *Database) DeepCopyInto(out *Database) {
 	*out = *in
 	in.CollectorMeta.DeepCopyInto(&out.CollectorMeta)
+	if in.URISecret != nil {
+		in, out := &in.URISecret, &out.URISecret
+		*out = new(DatabaseURISecret)
+		**out = **in
+	}
 	if in.Parameters != nil {
 		in, out := &in.Parameters, &out.Parameters
 		*out = make([]string, len(*in))
@@ -1414,6 +2020,21 @@ func (in *DatabaseAnalyze) DeepCopy() *DatabaseAnalyze {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatabaseURISecret) DeepCopyInto(out *DatabaseURISecret) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseURISecret.
+func (in *DatabaseURISecret) DeepCopy() *DatabaseURISecret {
+	if in == nil {
+		return nil
+	}
+	out := new(DatabaseURISecret)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DeploymentStatus) DeepCopyInto(out *DeploymentStatus) {
 	*out = *in
@@ -1446,10 +2067,42 @@ func (in *DeploymentStatus) DeepCopy() *DeploymentStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DiskPressureAnalyze) DeepCopyInto(out *DiskPressureAnalyze) {
+	*out = *in
+	in.AnalyzeMeta.DeepCopyInto(&out.AnalyzeMeta)
+	if in.Outcomes != nil {
+		in, out := &in.Outcomes, &out.Outcomes
+		*out = make([]*Outcome, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(Outcome)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DiskPressureAnalyze.
+func (in *DiskPressureAnalyze) DeepCopy() *DiskPressureAnalyze {
+	if in == nil {
+		return nil
+	}
+	out := new(DiskPressureAnalyze)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DiskUsage) DeepCopyInto(out *DiskUsage) {
 	*out = *in
 	in.HostCollectorMeta.DeepCopyInto(&out.HostCollectorMeta)
+	if in.Forecast != nil {
+		in, out := &in.Forecast, &out.Forecast
+		*out = new(DiskUsageForecast)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DiskUsage.
@@ -1489,6 +2142,21 @@ func (in *DiskUsageAnalyze) DeepCopy() *DiskUsageAnalyze {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DiskUsageForecast) DeepCopyInto(out *DiskUsageForecast) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DiskUsageForecast.
+func (in *DiskUsageForecast) DeepCopy() *DiskUsageForecast {
+	if in == nil {
+		return nil
+	}
+	out := new(DiskUsageForecast)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Distribution) DeepCopyInto(out *Distribution) {
 	*out = *in
@@ -1516,6 +2184,21 @@ func (in *Distribution) DeepCopy() *Distribution {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Documentation) DeepCopyInto(out *Documentation) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Documentation.
+func (in *Documentation) DeepCopy() *Documentation {
+	if in == nil {
+		return nil
+	}
+	out := new(Documentation)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Etcd) DeepCopyInto(out *Etcd) {
 	*out = *in
@@ -1559,6 +2242,31 @@ func (in *EventAnalyze) DeepCopy() *EventAnalyze {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EventTrigger) DeepCopyInto(out *EventTrigger) {
+	*out = *in
+	if in.Reasons != nil {
+		in, out := &in.Reasons, &out.Reasons
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EventTrigger.
+func (in *EventTrigger) DeepCopy() *EventTrigger {
+	if in == nil {
+		return nil
+	}
+	out := new(EventTrigger)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Exec) DeepCopyInto(out *Exec) {
 	*out = *in
@@ -1658,6 +2366,33 @@ func (in *FilesystemPerformanceAnalyze) DeepCopy() *FilesystemPerformanceAnalyze
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GPUAnalyze) DeepCopyInto(out *GPUAnalyze) {
+	*out = *in
+	in.AnalyzeMeta.DeepCopyInto(&out.AnalyzeMeta)
+	if in.Outcomes != nil {
+		in, out := &in.Outcomes, &out.Outcomes
+		*out = make([]*Outcome, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(Outcome)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GPUAnalyze.
+func (in *GPUAnalyze) DeepCopy() *GPUAnalyze {
+	if in == nil {
+		return nil
+	}
+	out := new(GPUAnalyze)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Get) DeepCopyInto(out *Get) {
 	*out = *in
@@ -1673,6 +2408,11 @@ func (in *Get) DeepCopyInto(out *Get) {
 		*out = new(TLSParams)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Retry != nil {
+		in, out := &in.Retry, &out.Retry
+		*out = new(HTTPRetry)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Get.
@@ -1835,26 +2575,62 @@ func (in *HTTPLoadBalancerAnalyze) DeepCopy() *HTTPLoadBalancerAnalyze {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Helm) DeepCopyInto(out *Helm) {
+func (in *HTTPRetry) DeepCopyInto(out *HTTPRetry) {
 	*out = *in
-	in.CollectorMeta.DeepCopyInto(&out.CollectorMeta)
+	if in.StatusCodes != nil {
+		in, out := &in.StatusCodes, &out.StatusCodes
+		*out = make([]int, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Helm.
-func (in *Helm) DeepCopy() *Helm {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPRetry.
+func (in *HTTPRetry) DeepCopy() *HTTPRetry {
 	if in == nil {
 		return nil
 	}
-	out := new(Helm)
+	out := new(HTTPRetry)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HostAnalyze) DeepCopyInto(out *HostAnalyze) {
+func (in *Helm) DeepCopyInto(out *Helm) {
 	*out = *in
-	if in.CPU != nil {
-		in, out := &in.CPU, &out.CPU
+	in.CollectorMeta.DeepCopyInto(&out.CollectorMeta)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Helm.
+func (in *Helm) DeepCopy() *Helm {
+	if in == nil {
+		return nil
+	}
+	out := new(Helm)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HostActivityCapture) DeepCopyInto(out *HostActivityCapture) {
+	*out = *in
+	in.HostCollectorMeta.DeepCopyInto(&out.HostCollectorMeta)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostActivityCapture.
+func (in *HostActivityCapture) DeepCopy() *HostActivityCapture {
+	if in == nil {
+		return nil
+	}
+	out := new(HostActivityCapture)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HostAnalyze) DeepCopyInto(out *HostAnalyze) {
+	*out = *in
+	if in.CPU != nil {
+		in, out := &in.CPU, &out.CPU
 		*out = new(CPUAnalyze)
 		(*in).DeepCopyInto(*out)
 	}
@@ -1983,6 +2759,86 @@ func (in *HostAnalyze) DeepCopyInto(out *HostAnalyze) {
 		*out = new(HostSysctlAnalyze)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.KernelSnapshot != nil {
+		in, out := &in.KernelSnapshot, &out.KernelSnapshot
+		*out = new(KernelSnapshotAnalyze)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SystemdUnits != nil {
+		in, out := &in.SystemdUnits, &out.SystemdUnits
+		*out = new(SystemdUnitsAnalyze)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NetworkConfig != nil {
+		in, out := &in.NetworkConfig, &out.NetworkConfig
+		*out = new(NetworkConfigAnalyze)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.GPU != nil {
+		in, out := &in.GPU, &out.GPU
+		*out = new(GPUAnalyze)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TimeSync != nil {
+		in, out := &in.TimeSync, &out.TimeSync
+		*out = new(TimeSyncAnalyze)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.KubernetesDistribution != nil {
+		in, out := &in.KubernetesDistribution, &out.KubernetesDistribution
+		*out = new(KubernetesDistributionAnalyze)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Benchmark != nil {
+		in, out := &in.Benchmark, &out.Benchmark
+		*out = new(BenchmarkAnalyze)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Paths != nil {
+		in, out := &in.Paths, &out.Paths
+		*out = new(PathsAnalyze)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Security != nil {
+		in, out := &in.Security, &out.Security
+		*out = new(SecurityAnalyze)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Proxy != nil {
+		in, out := &in.Proxy, &out.Proxy
+		*out = new(HostProxyAnalyze)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ActivityCapture != nil {
+		in, out := &in.ActivityCapture, &out.ActivityCapture
+		*out = new(ActivityCaptureAnalyze)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.KernelLogs != nil {
+		in, out := &in.KernelLogs, &out.KernelLogs
+		*out = new(KernelLogsAnalyze)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ConnectionStats != nil {
+		in, out := &in.ConnectionStats, &out.ConnectionStats
+		*out = new(ConnectionStatsAnalyze)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.KubeletConfig != nil {
+		in, out := &in.KubeletConfig, &out.KubeletConfig
+		*out = new(KubeletConfigAnalyze)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Metrics != nil {
+		in, out := &in.Metrics, &out.Metrics
+		*out = new(MetricsAnalyze)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CRIRuntime != nil {
+		in, out := &in.CRIRuntime, &out.CRIRuntime
+		*out = new(CRIRuntimeAnalyze)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostAnalyze.
@@ -1995,6 +2851,22 @@ func (in *HostAnalyze) DeepCopy() *HostAnalyze {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HostBenchmark) DeepCopyInto(out *HostBenchmark) {
+	*out = *in
+	in.HostCollectorMeta.DeepCopyInto(&out.HostCollectorMeta)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostBenchmark.
+func (in *HostBenchmark) DeepCopy() *HostBenchmark {
+	if in == nil {
+		return nil
+	}
+	out := new(HostBenchmark)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HostBlockDevices) DeepCopyInto(out *HostBlockDevices) {
 	*out = *in
@@ -2027,6 +2899,22 @@ func (in *HostCGroups) DeepCopy() *HostCGroups {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HostCRIRuntime) DeepCopyInto(out *HostCRIRuntime) {
+	*out = *in
+	in.HostCollectorMeta.DeepCopyInto(&out.HostCollectorMeta)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostCRIRuntime.
+func (in *HostCRIRuntime) DeepCopy() *HostCRIRuntime {
+	if in == nil {
+		return nil
+	}
+	out := new(HostCRIRuntime)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HostCertificatesCollection) DeepCopyInto(out *HostCertificatesCollection) {
 	*out = *in
@@ -2223,6 +3111,86 @@ func (in *HostCollect) DeepCopyInto(out *HostCollect) {
 		*out = new(HostSysctl)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.HostKernelSnapshot != nil {
+		in, out := &in.HostKernelSnapshot, &out.HostKernelSnapshot
+		*out = new(HostKernelSnapshot)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HostSystemdUnits != nil {
+		in, out := &in.HostSystemdUnits, &out.HostSystemdUnits
+		*out = new(HostSystemdUnits)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HostNetworkConfig != nil {
+		in, out := &in.HostNetworkConfig, &out.HostNetworkConfig
+		*out = new(HostNetworkConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HostGPU != nil {
+		in, out := &in.HostGPU, &out.HostGPU
+		*out = new(HostGPU)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HostTimeSync != nil {
+		in, out := &in.HostTimeSync, &out.HostTimeSync
+		*out = new(HostTimeSync)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HostKubernetesDistribution != nil {
+		in, out := &in.HostKubernetesDistribution, &out.HostKubernetesDistribution
+		*out = new(HostKubernetesDistribution)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HostBenchmark != nil {
+		in, out := &in.HostBenchmark, &out.HostBenchmark
+		*out = new(HostBenchmark)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HostPaths != nil {
+		in, out := &in.HostPaths, &out.HostPaths
+		*out = new(HostPaths)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HostSecurity != nil {
+		in, out := &in.HostSecurity, &out.HostSecurity
+		*out = new(HostSecurity)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HostProxy != nil {
+		in, out := &in.HostProxy, &out.HostProxy
+		*out = new(HostProxy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HostActivityCapture != nil {
+		in, out := &in.HostActivityCapture, &out.HostActivityCapture
+		*out = new(HostActivityCapture)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HostKernelLogs != nil {
+		in, out := &in.HostKernelLogs, &out.HostKernelLogs
+		*out = new(HostKernelLogs)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HostConnectionStats != nil {
+		in, out := &in.HostConnectionStats, &out.HostConnectionStats
+		*out = new(HostConnectionStats)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HostKubeletConfig != nil {
+		in, out := &in.HostKubeletConfig, &out.HostKubeletConfig
+		*out = new(HostKubeletConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HostMetrics != nil {
+		in, out := &in.HostMetrics, &out.HostMetrics
+		*out = new(HostMetrics)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HostCRIRuntime != nil {
+		in, out := &in.HostCRIRuntime, &out.HostCRIRuntime
+		*out = new(HostCRIRuntime)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostCollect.
@@ -2366,6 +3334,22 @@ func (in *HostCollectorStatus) DeepCopy() *HostCollectorStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HostConnectionStats) DeepCopyInto(out *HostConnectionStats) {
+	*out = *in
+	in.HostCollectorMeta.DeepCopyInto(&out.HostCollectorMeta)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostConnectionStats.
+func (in *HostConnectionStats) DeepCopy() *HostConnectionStats {
+	if in == nil {
+		return nil
+	}
+	out := new(HostConnectionStats)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HostCopy) DeepCopyInto(out *HostCopy) {
 	*out = *in
@@ -2403,6 +3387,22 @@ func (in *HostDNS) DeepCopy() *HostDNS {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HostGPU) DeepCopyInto(out *HostGPU) {
+	*out = *in
+	in.HostCollectorMeta.DeepCopyInto(&out.HostCollectorMeta)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostGPU.
+func (in *HostGPU) DeepCopy() *HostGPU {
+	if in == nil {
+		return nil
+	}
+	out := new(HostGPU)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HostHTTP) DeepCopyInto(out *HostHTTP) {
 	*out = *in
@@ -2471,6 +3471,27 @@ func (in *HostKernelConfigs) DeepCopy() *HostKernelConfigs {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HostKernelLogs) DeepCopyInto(out *HostKernelLogs) {
+	*out = *in
+	in.HostCollectorMeta.DeepCopyInto(&out.HostCollectorMeta)
+	if in.Units != nil {
+		in, out := &in.Units, &out.Units
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostKernelLogs.
+func (in *HostKernelLogs) DeepCopy() *HostKernelLogs {
+	if in == nil {
+		return nil
+	}
+	out := new(HostKernelLogs)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HostKernelModules) DeepCopyInto(out *HostKernelModules) {
 	*out = *in
@@ -2487,6 +3508,91 @@ func (in *HostKernelModules) DeepCopy() *HostKernelModules {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HostKernelSnapshot) DeepCopyInto(out *HostKernelSnapshot) {
+	*out = *in
+	in.HostCollectorMeta.DeepCopyInto(&out.HostCollectorMeta)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostKernelSnapshot.
+func (in *HostKernelSnapshot) DeepCopy() *HostKernelSnapshot {
+	if in == nil {
+		return nil
+	}
+	out := new(HostKernelSnapshot)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HostKubeletConfig) DeepCopyInto(out *HostKubeletConfig) {
+	*out = *in
+	in.HostCollectorMeta.DeepCopyInto(&out.HostCollectorMeta)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostKubeletConfig.
+func (in *HostKubeletConfig) DeepCopy() *HostKubeletConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(HostKubeletConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HostKubernetesDistribution) DeepCopyInto(out *HostKubernetesDistribution) {
+	*out = *in
+	in.HostCollectorMeta.DeepCopyInto(&out.HostCollectorMeta)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostKubernetesDistribution.
+func (in *HostKubernetesDistribution) DeepCopy() *HostKubernetesDistribution {
+	if in == nil {
+		return nil
+	}
+	out := new(HostKubernetesDistribution)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HostMetrics) DeepCopyInto(out *HostMetrics) {
+	*out = *in
+	in.HostCollectorMeta.DeepCopyInto(&out.HostCollectorMeta)
+	if in.Interfaces != nil {
+		in, out := &in.Interfaces, &out.Interfaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostMetrics.
+func (in *HostMetrics) DeepCopy() *HostMetrics {
+	if in == nil {
+		return nil
+	}
+	out := new(HostMetrics)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HostNetworkConfig) DeepCopyInto(out *HostNetworkConfig) {
+	*out = *in
+	in.HostCollectorMeta.DeepCopyInto(&out.HostCollectorMeta)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostNetworkConfig.
+func (in *HostNetworkConfig) DeepCopy() *HostNetworkConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(HostNetworkConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HostNetworkNamespaceConnectivity) DeepCopyInto(out *HostNetworkNamespaceConnectivity) {
 	*out = *in
@@ -2546,6 +3652,27 @@ func (in *HostOSAnalyze) DeepCopy() *HostOSAnalyze {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HostPaths) DeepCopyInto(out *HostPaths) {
+	*out = *in
+	in.HostCollectorMeta.DeepCopyInto(&out.HostCollectorMeta)
+	if in.Paths != nil {
+		in, out := &in.Paths, &out.Paths
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostPaths.
+func (in *HostPaths) DeepCopy() *HostPaths {
+	if in == nil {
+		return nil
+	}
+	out := new(HostPaths)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HostPreflight) DeepCopyInto(out *HostPreflight) {
 	*out = *in
@@ -2641,6 +3768,17 @@ func (in *HostPreflightSpec) DeepCopyInto(out *HostPreflightSpec) {
 			}
 		}
 	}
+	if in.Notifications != nil {
+		in, out := &in.Notifications, &out.Notifications
+		*out = make([]*Notification, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(Notification)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostPreflightSpec.
@@ -2669,19 +3807,72 @@ func (in *HostPreflightStatus) DeepCopy() *HostPreflightStatus {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HostRun) DeepCopyInto(out *HostRun) {
+func (in *HostProxy) DeepCopyInto(out *HostProxy) {
 	*out = *in
 	in.HostCollectorMeta.DeepCopyInto(&out.HostCollectorMeta)
-	if in.Args != nil {
-		in, out := &in.Args, &out.Args
+	if in.Endpoints != nil {
+		in, out := &in.Endpoints, &out.Endpoints
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
-	if in.Input != nil {
-		in, out := &in.Input, &out.Input
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
+	if in.CIDRs != nil {
+		in, out := &in.CIDRs, &out.CIDRs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostProxy.
+func (in *HostProxy) DeepCopy() *HostProxy {
+	if in == nil {
+		return nil
+	}
+	out := new(HostProxy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HostProxyAnalyze) DeepCopyInto(out *HostProxyAnalyze) {
+	*out = *in
+	in.AnalyzeMeta.DeepCopyInto(&out.AnalyzeMeta)
+	if in.Outcomes != nil {
+		in, out := &in.Outcomes, &out.Outcomes
+		*out = make([]*Outcome, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(Outcome)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostProxyAnalyze.
+func (in *HostProxyAnalyze) DeepCopy() *HostProxyAnalyze {
+	if in == nil {
+		return nil
+	}
+	out := new(HostProxyAnalyze)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HostRun) DeepCopyInto(out *HostRun) {
+	*out = *in
+	in.HostCollectorMeta.DeepCopyInto(&out.HostCollectorMeta)
+	if in.Args != nil {
+		in, out := &in.Args, &out.Args
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Input != nil {
+		in, out := &in.Input, &out.Input
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
 		}
 	}
 	if in.Env != nil {
@@ -2706,6 +3897,22 @@ func (in *HostRun) DeepCopy() *HostRun {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HostSecurity) DeepCopyInto(out *HostSecurity) {
+	*out = *in
+	in.HostCollectorMeta.DeepCopyInto(&out.HostCollectorMeta)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostSecurity.
+func (in *HostSecurity) DeepCopy() *HostSecurity {
+	if in == nil {
+		return nil
+	}
+	out := new(HostSecurity)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HostServices) DeepCopyInto(out *HostServices) {
 	*out = *in
@@ -2913,6 +4120,27 @@ func (in *HostSystemPackages) DeepCopy() *HostSystemPackages {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HostSystemdUnits) DeepCopyInto(out *HostSystemdUnits) {
+	*out = *in
+	in.HostCollectorMeta.DeepCopyInto(&out.HostCollectorMeta)
+	if in.Units != nil {
+		in, out := &in.Units, &out.Units
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostSystemdUnits.
+func (in *HostSystemdUnits) DeepCopy() *HostSystemdUnits {
+	if in == nil {
+		return nil
+	}
+	out := new(HostSystemdUnits)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HostTime) DeepCopyInto(out *HostTime) {
 	*out = *in
@@ -2929,6 +4157,22 @@ func (in *HostTime) DeepCopy() *HostTime {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HostTimeSync) DeepCopyInto(out *HostTimeSync) {
+	*out = *in
+	in.HostCollectorMeta.DeepCopyInto(&out.HostCollectorMeta)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostTimeSync.
+func (in *HostTimeSync) DeepCopy() *HostTimeSync {
+	if in == nil {
+		return nil
+	}
+	out := new(HostTimeSync)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *IPV4Interfaces) DeepCopyInto(out *IPV4Interfaces) {
 	*out = *in
@@ -2973,56 +4217,76 @@ func (in *IPV4InterfacesAnalyze) DeepCopy() *IPV4InterfacesAnalyze {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ImagePullSecret) DeepCopyInto(out *ImagePullSecret) {
+func (in *ImageFacts) DeepCopyInto(out *ImageFacts) {
 	*out = *in
-	in.AnalyzeMeta.DeepCopyInto(&out.AnalyzeMeta)
-	if in.Outcomes != nil {
-		in, out := &in.Outcomes, &out.Outcomes
-		*out = make([]*Outcome, len(*in))
-		for i := range *in {
-			if (*in)[i] != nil {
-				in, out := &(*in)[i], &(*out)[i]
-				*out = new(Outcome)
-				(*in).DeepCopyInto(*out)
+	in.CollectorMeta.DeepCopyInto(&out.CollectorMeta)
+	if in.Images != nil {
+		in, out := &in.Images, &out.Images
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ImagePullSecrets != nil {
+		in, out := &in.ImagePullSecrets, &out.ImagePullSecrets
+		*out = new(ImagePullSecrets)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RegistryMirrors != nil {
+		in, out := &in.RegistryMirrors, &out.RegistryMirrors
+		*out = make(map[string][]string, len(*in))
+		for key, val := range *in {
+			var outVal []string
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				inVal := (*in)[key]
+				in, out := &inVal, &outVal
+				*out = make([]string, len(*in))
+				copy(*out, *in)
 			}
+			(*out)[key] = outVal
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImagePullSecret.
-func (in *ImagePullSecret) DeepCopy() *ImagePullSecret {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageFacts.
+func (in *ImageFacts) DeepCopy() *ImageFacts {
 	if in == nil {
 		return nil
 	}
-	out := new(ImagePullSecret)
+	out := new(ImageFacts)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ImagePullSecrets) DeepCopyInto(out *ImagePullSecrets) {
+func (in *ImageFactsAnalyze) DeepCopyInto(out *ImageFactsAnalyze) {
 	*out = *in
-	if in.Data != nil {
-		in, out := &in.Data, &out.Data
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
+	in.AnalyzeMeta.DeepCopyInto(&out.AnalyzeMeta)
+	if in.Outcomes != nil {
+		in, out := &in.Outcomes, &out.Outcomes
+		*out = make([]*Outcome, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(Outcome)
+				(*in).DeepCopyInto(*out)
+			}
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImagePullSecrets.
-func (in *ImagePullSecrets) DeepCopy() *ImagePullSecrets {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageFactsAnalyze.
+func (in *ImageFactsAnalyze) DeepCopy() *ImageFactsAnalyze {
 	if in == nil {
 		return nil
 	}
-	out := new(ImagePullSecrets)
+	out := new(ImageFactsAnalyze)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ImageSignatures) DeepCopyInto(out *ImageSignatures) {
+func (in *ImageLayers) DeepCopyInto(out *ImageLayers) {
 	*out = *in
 	in.CollectorMeta.DeepCopyInto(&out.CollectorMeta)
 	if in.Images != nil {
@@ -3035,20 +4299,36 @@ func (in *ImageSignatures) DeepCopyInto(out *ImageSignatures) {
 		*out = new(ImagePullSecrets)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.RegistryMirrors != nil {
+		in, out := &in.RegistryMirrors, &out.RegistryMirrors
+		*out = make(map[string][]string, len(*in))
+		for key, val := range *in {
+			var outVal []string
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				inVal := (*in)[key]
+				in, out := &inVal, &outVal
+				*out = make([]string, len(*in))
+				copy(*out, *in)
+			}
+			(*out)[key] = outVal
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageSignatures.
-func (in *ImageSignatures) DeepCopy() *ImageSignatures {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageLayers.
+func (in *ImageLayers) DeepCopy() *ImageLayers {
 	if in == nil {
 		return nil
 	}
-	out := new(ImageSignatures)
+	out := new(ImageLayers)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ImageSignaturesAnalyze) DeepCopyInto(out *ImageSignaturesAnalyze) {
+func (in *ImageLayersAnalyze) DeepCopyInto(out *ImageLayersAnalyze) {
 	*out = *in
 	in.AnalyzeMeta.DeepCopyInto(&out.AnalyzeMeta)
 	if in.Outcomes != nil {
@@ -3064,20 +4344,25 @@ func (in *ImageSignaturesAnalyze) DeepCopyInto(out *ImageSignaturesAnalyze) {
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageSignaturesAnalyze.
-func (in *ImageSignaturesAnalyze) DeepCopy() *ImageSignaturesAnalyze {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageLayersAnalyze.
+func (in *ImageLayersAnalyze) DeepCopy() *ImageLayersAnalyze {
 	if in == nil {
 		return nil
 	}
-	out := new(ImageSignaturesAnalyze)
+	out := new(ImageLayersAnalyze)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Ingress) DeepCopyInto(out *Ingress) {
+func (in *ImagePlatformsAnalyze) DeepCopyInto(out *ImagePlatformsAnalyze) {
 	*out = *in
 	in.AnalyzeMeta.DeepCopyInto(&out.AnalyzeMeta)
+	if in.Platforms != nil {
+		in, out := &in.Platforms, &out.Platforms
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.Outcomes != nil {
 		in, out := &in.Outcomes, &out.Outcomes
 		*out = make([]*Outcome, len(*in))
@@ -3091,18 +4376,18 @@ func (in *Ingress) DeepCopyInto(out *Ingress) {
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Ingress.
-func (in *Ingress) DeepCopy() *Ingress {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImagePlatformsAnalyze.
+func (in *ImagePlatformsAnalyze) DeepCopy() *ImagePlatformsAnalyze {
 	if in == nil {
 		return nil
 	}
-	out := new(Ingress)
+	out := new(ImagePlatformsAnalyze)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *JobStatus) DeepCopyInto(out *JobStatus) {
+func (in *ImagePullSecret) DeepCopyInto(out *ImagePullSecret) {
 	*out = *in
 	in.AnalyzeMeta.DeepCopyInto(&out.AnalyzeMeta)
 	if in.Outcomes != nil {
@@ -3116,84 +4401,84 @@ func (in *JobStatus) DeepCopyInto(out *JobStatus) {
 			}
 		}
 	}
-	if in.Namespaces != nil {
-		in, out := &in.Namespaces, &out.Namespaces
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JobStatus.
-func (in *JobStatus) DeepCopy() *JobStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImagePullSecret.
+func (in *ImagePullSecret) DeepCopy() *ImagePullSecret {
 	if in == nil {
 		return nil
 	}
-	out := new(JobStatus)
+	out := new(ImagePullSecret)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *JsonCompare) DeepCopyInto(out *JsonCompare) {
+func (in *ImagePullSecrets) DeepCopyInto(out *ImagePullSecrets) {
 	*out = *in
-	in.AnalyzeMeta.DeepCopyInto(&out.AnalyzeMeta)
-	if in.Outcomes != nil {
-		in, out := &in.Outcomes, &out.Outcomes
-		*out = make([]*Outcome, len(*in))
-		for i := range *in {
-			if (*in)[i] != nil {
-				in, out := &(*in)[i], &(*out)[i]
-				*out = new(Outcome)
-				(*in).DeepCopyInto(*out)
-			}
+	if in.Data != nil {
+		in, out := &in.Data, &out.Data
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JsonCompare.
-func (in *JsonCompare) DeepCopy() *JsonCompare {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImagePullSecrets.
+func (in *ImagePullSecrets) DeepCopy() *ImagePullSecrets {
 	if in == nil {
 		return nil
 	}
-	out := new(JsonCompare)
+	out := new(ImagePullSecrets)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KernelConfigsAnalyze) DeepCopyInto(out *KernelConfigsAnalyze) {
+func (in *ImageSignatures) DeepCopyInto(out *ImageSignatures) {
 	*out = *in
-	in.AnalyzeMeta.DeepCopyInto(&out.AnalyzeMeta)
-	if in.SelectedConfigs != nil {
-		in, out := &in.SelectedConfigs, &out.SelectedConfigs
+	in.CollectorMeta.DeepCopyInto(&out.CollectorMeta)
+	if in.Images != nil {
+		in, out := &in.Images, &out.Images
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
-	if in.Outcomes != nil {
-		in, out := &in.Outcomes, &out.Outcomes
-		*out = make([]*Outcome, len(*in))
-		for i := range *in {
-			if (*in)[i] != nil {
-				in, out := &(*in)[i], &(*out)[i]
-				*out = new(Outcome)
-				(*in).DeepCopyInto(*out)
+	if in.ImagePullSecrets != nil {
+		in, out := &in.ImagePullSecrets, &out.ImagePullSecrets
+		*out = new(ImagePullSecrets)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RegistryMirrors != nil {
+		in, out := &in.RegistryMirrors, &out.RegistryMirrors
+		*out = make(map[string][]string, len(*in))
+		for key, val := range *in {
+			var outVal []string
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				inVal := (*in)[key]
+				in, out := &inVal, &outVal
+				*out = make([]string, len(*in))
+				copy(*out, *in)
 			}
+			(*out)[key] = outVal
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KernelConfigsAnalyze.
-func (in *KernelConfigsAnalyze) DeepCopy() *KernelConfigsAnalyze {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageSignatures.
+func (in *ImageSignatures) DeepCopy() *ImageSignatures {
 	if in == nil {
 		return nil
 	}
-	out := new(KernelConfigsAnalyze)
+	out := new(ImageSignatures)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KernelModulesAnalyze) DeepCopyInto(out *KernelModulesAnalyze) {
+func (in *ImageSignaturesAnalyze) DeepCopyInto(out *ImageSignaturesAnalyze) {
 	*out = *in
 	in.AnalyzeMeta.DeepCopyInto(&out.AnalyzeMeta)
 	if in.Outcomes != nil {
@@ -3209,99 +4494,211 @@ func (in *KernelModulesAnalyze) DeepCopyInto(out *KernelModulesAnalyze) {
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KernelModulesAnalyze.
-func (in *KernelModulesAnalyze) DeepCopy() *KernelModulesAnalyze {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageSignaturesAnalyze.
+func (in *ImageSignaturesAnalyze) DeepCopy() *ImageSignaturesAnalyze {
 	if in == nil {
 		return nil
 	}
-	out := new(KernelModulesAnalyze)
+	out := new(ImageSignaturesAnalyze)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Kubernetes) DeepCopyInto(out *Kubernetes) {
+func (in *IncludeConfigMap) DeepCopyInto(out *IncludeConfigMap) {
 	*out = *in
-	in.HostCollectorMeta.DeepCopyInto(&out.HostCollectorMeta)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Kubernetes.
-func (in *Kubernetes) DeepCopy() *Kubernetes {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IncludeConfigMap.
+func (in *IncludeConfigMap) DeepCopy() *IncludeConfigMap {
 	if in == nil {
 		return nil
 	}
-	out := new(Kubernetes)
+	out := new(IncludeConfigMap)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *LogLimits) DeepCopyInto(out *LogLimits) {
+func (in *Ingress) DeepCopyInto(out *Ingress) {
 	*out = *in
-	in.SinceTime.DeepCopyInto(&out.SinceTime)
+	in.AnalyzeMeta.DeepCopyInto(&out.AnalyzeMeta)
+	if in.Outcomes != nil {
+		in, out := &in.Outcomes, &out.Outcomes
+		*out = make([]*Outcome, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(Outcome)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LogLimits.
-func (in *LogLimits) DeepCopy() *LogLimits {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Ingress.
+func (in *Ingress) DeepCopy() *Ingress {
 	if in == nil {
 		return nil
 	}
-	out := new(LogLimits)
+	out := new(Ingress)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Logs) DeepCopyInto(out *Logs) {
+func (in *JSONQueryAnalyze) DeepCopyInto(out *JSONQueryAnalyze) {
 	*out = *in
-	in.CollectorMeta.DeepCopyInto(&out.CollectorMeta)
-	if in.Selector != nil {
-		in, out := &in.Selector, &out.Selector
-		*out = make([]string, len(*in))
+	in.AnalyzeMeta.DeepCopyInto(&out.AnalyzeMeta)
+	if in.Outcomes != nil {
+		in, out := &in.Outcomes, &out.Outcomes
+		*out = make([]*Outcome, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(Outcome)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JSONQueryAnalyze.
+func (in *JSONQueryAnalyze) DeepCopy() *JSONQueryAnalyze {
+	if in == nil {
+		return nil
+	}
+	out := new(JSONQueryAnalyze)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JobStatus) DeepCopyInto(out *JobStatus) {
+	*out = *in
+	in.AnalyzeMeta.DeepCopyInto(&out.AnalyzeMeta)
+	if in.Outcomes != nil {
+		in, out := &in.Outcomes, &out.Outcomes
+		*out = make([]*Outcome, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(Outcome)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
-	if in.ContainerNames != nil {
-		in, out := &in.ContainerNames, &out.ContainerNames
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JobStatus.
+func (in *JobStatus) DeepCopy() *JobStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(JobStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JsonCompare) DeepCopyInto(out *JsonCompare) {
+	*out = *in
+	in.AnalyzeMeta.DeepCopyInto(&out.AnalyzeMeta)
+	if in.Outcomes != nil {
+		in, out := &in.Outcomes, &out.Outcomes
+		*out = make([]*Outcome, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(Outcome)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JsonCompare.
+func (in *JsonCompare) DeepCopy() *JsonCompare {
+	if in == nil {
+		return nil
+	}
+	out := new(JsonCompare)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Kafka) DeepCopyInto(out *Kafka) {
+	*out = *in
+	in.CollectorMeta.DeepCopyInto(&out.CollectorMeta)
+	if in.Brokers != nil {
+		in, out := &in.Brokers, &out.Brokers
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
-	if in.Limits != nil {
-		in, out := &in.Limits, &out.Limits
-		*out = new(LogLimits)
+	if in.ConsumerGroups != nil {
+		in, out := &in.ConsumerGroups, &out.ConsumerGroups
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(TLSParams)
 		(*in).DeepCopyInto(*out)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Logs.
-func (in *Logs) DeepCopy() *Logs {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Kafka.
+func (in *Kafka) DeepCopy() *Kafka {
 	if in == nil {
 		return nil
 	}
-	out := new(Logs)
+	out := new(Kafka)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Longhorn) DeepCopyInto(out *Longhorn) {
+func (in *KafkaAnalyze) DeepCopyInto(out *KafkaAnalyze) {
 	*out = *in
-	in.CollectorMeta.DeepCopyInto(&out.CollectorMeta)
+	in.AnalyzeMeta.DeepCopyInto(&out.AnalyzeMeta)
+	if in.Outcomes != nil {
+		in, out := &in.Outcomes, &out.Outcomes
+		*out = make([]*Outcome, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(Outcome)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Longhorn.
-func (in *Longhorn) DeepCopy() *Longhorn {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KafkaAnalyze.
+func (in *KafkaAnalyze) DeepCopy() *KafkaAnalyze {
 	if in == nil {
 		return nil
 	}
-	out := new(Longhorn)
+	out := new(KafkaAnalyze)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *LonghornAnalyze) DeepCopyInto(out *LonghornAnalyze) {
+func (in *KernelConfigsAnalyze) DeepCopyInto(out *KernelConfigsAnalyze) {
 	*out = *in
 	in.AnalyzeMeta.DeepCopyInto(&out.AnalyzeMeta)
+	if in.SelectedConfigs != nil {
+		in, out := &in.SelectedConfigs, &out.SelectedConfigs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.Outcomes != nil {
 		in, out := &in.Outcomes, &out.Outcomes
 		*out = make([]*Outcome, len(*in))
@@ -3315,34 +4712,50 @@ func (in *LonghornAnalyze) DeepCopyInto(out *LonghornAnalyze) {
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LonghornAnalyze.
-func (in *LonghornAnalyze) DeepCopy() *LonghornAnalyze {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KernelConfigsAnalyze.
+func (in *KernelConfigsAnalyze) DeepCopy() *KernelConfigsAnalyze {
 	if in == nil {
 		return nil
 	}
-	out := new(LonghornAnalyze)
+	out := new(KernelConfigsAnalyze)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Memory) DeepCopyInto(out *Memory) {
+func (in *KernelLogsAnalyze) DeepCopyInto(out *KernelLogsAnalyze) {
 	*out = *in
-	in.HostCollectorMeta.DeepCopyInto(&out.HostCollectorMeta)
+	in.AnalyzeMeta.DeepCopyInto(&out.AnalyzeMeta)
+	if in.Classes != nil {
+		in, out := &in.Classes, &out.Classes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Outcomes != nil {
+		in, out := &in.Outcomes, &out.Outcomes
+		*out = make([]*Outcome, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(Outcome)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Memory.
-func (in *Memory) DeepCopy() *Memory {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KernelLogsAnalyze.
+func (in *KernelLogsAnalyze) DeepCopy() *KernelLogsAnalyze {
 	if in == nil {
 		return nil
 	}
-	out := new(Memory)
+	out := new(KernelLogsAnalyze)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MemoryAnalyze) DeepCopyInto(out *MemoryAnalyze) {
+func (in *KernelModulesAnalyze) DeepCopyInto(out *KernelModulesAnalyze) {
 	*out = *in
 	in.AnalyzeMeta.DeepCopyInto(&out.AnalyzeMeta)
 	if in.Outcomes != nil {
@@ -3358,33 +4771,88 @@ func (in *MemoryAnalyze) DeepCopyInto(out *MemoryAnalyze) {
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MemoryAnalyze.
-func (in *MemoryAnalyze) DeepCopy() *MemoryAnalyze {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KernelModulesAnalyze.
+func (in *KernelModulesAnalyze) DeepCopy() *KernelModulesAnalyze {
 	if in == nil {
 		return nil
 	}
-	out := new(MemoryAnalyze)
+	out := new(KernelModulesAnalyze)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MetricRequest) DeepCopyInto(out *MetricRequest) {
+func (in *KernelSnapshotAnalyze) DeepCopyInto(out *KernelSnapshotAnalyze) {
 	*out = *in
+	in.AnalyzeMeta.DeepCopyInto(&out.AnalyzeMeta)
+	if in.Sysctl != nil {
+		in, out := &in.Sysctl, &out.Sysctl
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Modules != nil {
+		in, out := &in.Modules, &out.Modules
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Cmdline != nil {
+		in, out := &in.Cmdline, &out.Cmdline
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Outcomes != nil {
+		in, out := &in.Outcomes, &out.Outcomes
+		*out = make([]*Outcome, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(Outcome)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetricRequest.
-func (in *MetricRequest) DeepCopy() *MetricRequest {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KernelSnapshotAnalyze.
+func (in *KernelSnapshotAnalyze) DeepCopy() *KernelSnapshotAnalyze {
 	if in == nil {
 		return nil
 	}
-	out := new(MetricRequest)
+	out := new(KernelSnapshotAnalyze)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NetworkNamespaceConnectivityAnalyze) DeepCopyInto(out *NetworkNamespaceConnectivityAnalyze) {
+func (in *KubeletConfig) DeepCopyInto(out *KubeletConfig) {
+	*out = *in
+	in.CollectorMeta.DeepCopyInto(&out.CollectorMeta)
+	if in.NodeNames != nil {
+		in, out := &in.NodeNames, &out.NodeNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeletConfig.
+func (in *KubeletConfig) DeepCopy() *KubeletConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(KubeletConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubeletConfigAnalyze) DeepCopyInto(out *KubeletConfigAnalyze) {
 	*out = *in
 	in.AnalyzeMeta.DeepCopyInto(&out.AnalyzeMeta)
 	if in.Outcomes != nil {
@@ -3400,18 +4868,18 @@ func (in *NetworkNamespaceConnectivityAnalyze) DeepCopyInto(out *NetworkNamespac
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkNamespaceConnectivityAnalyze.
-func (in *NetworkNamespaceConnectivityAnalyze) DeepCopy() *NetworkNamespaceConnectivityAnalyze {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeletConfigAnalyze.
+func (in *KubeletConfigAnalyze) DeepCopy() *KubeletConfigAnalyze {
 	if in == nil {
 		return nil
 	}
-	out := new(NetworkNamespaceConnectivityAnalyze)
+	out := new(KubeletConfigAnalyze)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NodeMetrics) DeepCopyInto(out *NodeMetrics) {
+func (in *KubeletMetrics) DeepCopyInto(out *KubeletMetrics) {
 	*out = *in
 	in.CollectorMeta.DeepCopyInto(&out.CollectorMeta)
 	if in.NodeNames != nil {
@@ -3426,21 +4894,20 @@ func (in *NodeMetrics) DeepCopyInto(out *NodeMetrics) {
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeMetrics.
-func (in *NodeMetrics) DeepCopy() *NodeMetrics {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeletMetrics.
+func (in *KubeletMetrics) DeepCopy() *KubeletMetrics {
 	if in == nil {
 		return nil
 	}
-	out := new(NodeMetrics)
+	out := new(KubeletMetrics)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NodeMetricsAnalyze) DeepCopyInto(out *NodeMetricsAnalyze) {
+func (in *KubeletMetricsAnalyze) DeepCopyInto(out *KubeletMetricsAnalyze) {
 	*out = *in
 	in.AnalyzeMeta.DeepCopyInto(&out.AnalyzeMeta)
-	in.Filters.DeepCopyInto(&out.Filters)
 	if in.Outcomes != nil {
 		in, out := &in.Outcomes, &out.Outcomes
 		*out = make([]*Outcome, len(*in))
@@ -3454,185 +4921,1332 @@ func (in *NodeMetricsAnalyze) DeepCopyInto(out *NodeMetricsAnalyze) {
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeMetricsAnalyze.
-func (in *NodeMetricsAnalyze) DeepCopy() *NodeMetricsAnalyze {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeletMetricsAnalyze.
+func (in *KubeletMetricsAnalyze) DeepCopy() *KubeletMetricsAnalyze {
 	if in == nil {
 		return nil
 	}
-	out := new(NodeMetricsAnalyze)
+	out := new(KubeletMetricsAnalyze)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NodeMetricsAnalyzeFilters) DeepCopyInto(out *NodeMetricsAnalyzeFilters) {
+func (in *Kubernetes) DeepCopyInto(out *Kubernetes) {
 	*out = *in
-	if in.PVC != nil {
-		in, out := &in.PVC, &out.PVC
-		*out = new(PVCRef)
-		**out = **in
+	in.HostCollectorMeta.DeepCopyInto(&out.HostCollectorMeta)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Kubernetes.
+func (in *Kubernetes) DeepCopy() *Kubernetes {
+	if in == nil {
+		return nil
+	}
+	out := new(Kubernetes)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubernetesDistributionAnalyze) DeepCopyInto(out *KubernetesDistributionAnalyze) {
+	*out = *in
+	in.AnalyzeMeta.DeepCopyInto(&out.AnalyzeMeta)
+	if in.Outcomes != nil {
+		in, out := &in.Outcomes, &out.Outcomes
+		*out = make([]*Outcome, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(Outcome)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubernetesDistributionAnalyze.
+func (in *KubernetesDistributionAnalyze) DeepCopy() *KubernetesDistributionAnalyze {
+	if in == nil {
+		return nil
+	}
+	out := new(KubernetesDistributionAnalyze)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubernetesUpgrade) DeepCopyInto(out *KubernetesUpgrade) {
+	*out = *in
+	in.AnalyzeMeta.DeepCopyInto(&out.AnalyzeMeta)
+	if in.Outcomes != nil {
+		in, out := &in.Outcomes, &out.Outcomes
+		*out = make([]*Outcome, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(Outcome)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubernetesUpgrade.
+func (in *KubernetesUpgrade) DeepCopy() *KubernetesUpgrade {
+	if in == nil {
+		return nil
+	}
+	out := new(KubernetesUpgrade)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LDAP) DeepCopyInto(out *LDAP) {
+	*out = *in
+	in.CollectorMeta.DeepCopyInto(&out.CollectorMeta)
+	if in.BindPasswordSecret != nil {
+		in, out := &in.BindPasswordSecret, &out.BindPasswordSecret
+		*out = new(CredentialSecret)
+		**out = **in
+	}
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(TLSParams)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LDAP.
+func (in *LDAP) DeepCopy() *LDAP {
+	if in == nil {
+		return nil
+	}
+	out := new(LDAP)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LDAPAnalyze) DeepCopyInto(out *LDAPAnalyze) {
+	*out = *in
+	in.AnalyzeMeta.DeepCopyInto(&out.AnalyzeMeta)
+	if in.Outcomes != nil {
+		in, out := &in.Outcomes, &out.Outcomes
+		*out = make([]*Outcome, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(Outcome)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LDAPAnalyze.
+func (in *LDAPAnalyze) DeepCopy() *LDAPAnalyze {
+	if in == nil {
+		return nil
+	}
+	out := new(LDAPAnalyze)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LogFilter) DeepCopyInto(out *LogFilter) {
+	*out = *in
+	in.SinceTime.DeepCopyInto(&out.SinceTime)
+	in.UntilTime.DeepCopyInto(&out.UntilTime)
+	if in.Include != nil {
+		in, out := &in.Include, &out.Include
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Exclude != nil {
+		in, out := &in.Exclude, &out.Exclude
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Sample != nil {
+		in, out := &in.Sample, &out.Sample
+		*out = new(LogSample)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LogFilter.
+func (in *LogFilter) DeepCopy() *LogFilter {
+	if in == nil {
+		return nil
+	}
+	out := new(LogFilter)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LogLimits) DeepCopyInto(out *LogLimits) {
+	*out = *in
+	in.SinceTime.DeepCopyInto(&out.SinceTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LogLimits.
+func (in *LogLimits) DeepCopy() *LogLimits {
+	if in == nil {
+		return nil
+	}
+	out := new(LogLimits)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LogSample) DeepCopyInto(out *LogSample) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LogSample.
+func (in *LogSample) DeepCopy() *LogSample {
+	if in == nil {
+		return nil
+	}
+	out := new(LogSample)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Logs) DeepCopyInto(out *Logs) {
+	*out = *in
+	in.CollectorMeta.DeepCopyInto(&out.CollectorMeta)
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ContainerNames != nil {
+		in, out := &in.ContainerNames, &out.ContainerNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Limits != nil {
+		in, out := &in.Limits, &out.Limits
+		*out = new(LogLimits)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Filter != nil {
+		in, out := &in.Filter, &out.Filter
+		*out = new(LogFilter)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Logs.
+func (in *Logs) DeepCopy() *Logs {
+	if in == nil {
+		return nil
+	}
+	out := new(Logs)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Longhorn) DeepCopyInto(out *Longhorn) {
+	*out = *in
+	in.CollectorMeta.DeepCopyInto(&out.CollectorMeta)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Longhorn.
+func (in *Longhorn) DeepCopy() *Longhorn {
+	if in == nil {
+		return nil
+	}
+	out := new(Longhorn)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LonghornAnalyze) DeepCopyInto(out *LonghornAnalyze) {
+	*out = *in
+	in.AnalyzeMeta.DeepCopyInto(&out.AnalyzeMeta)
+	if in.Outcomes != nil {
+		in, out := &in.Outcomes, &out.Outcomes
+		*out = make([]*Outcome, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(Outcome)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LonghornAnalyze.
+func (in *LonghornAnalyze) DeepCopy() *LonghornAnalyze {
+	if in == nil {
+		return nil
+	}
+	out := new(LonghornAnalyze)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MachineConfigPoolStatus) DeepCopyInto(out *MachineConfigPoolStatus) {
+	*out = *in
+	in.AnalyzeMeta.DeepCopyInto(&out.AnalyzeMeta)
+	if in.Pools != nil {
+		in, out := &in.Pools, &out.Pools
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Outcomes != nil {
+		in, out := &in.Outcomes, &out.Outcomes
+		*out = make([]*Outcome, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(Outcome)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MachineConfigPoolStatus.
+func (in *MachineConfigPoolStatus) DeepCopy() *MachineConfigPoolStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(MachineConfigPoolStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManagedCluster) DeepCopyInto(out *ManagedCluster) {
+	*out = *in
+	in.CollectorMeta.DeepCopyInto(&out.CollectorMeta)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagedCluster.
+func (in *ManagedCluster) DeepCopy() *ManagedCluster {
+	if in == nil {
+		return nil
+	}
+	out := new(ManagedCluster)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManagedClusterAnalyze) DeepCopyInto(out *ManagedClusterAnalyze) {
+	*out = *in
+	in.AnalyzeMeta.DeepCopyInto(&out.AnalyzeMeta)
+	if in.Outcomes != nil {
+		in, out := &in.Outcomes, &out.Outcomes
+		*out = make([]*Outcome, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(Outcome)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagedClusterAnalyze.
+func (in *ManagedClusterAnalyze) DeepCopy() *ManagedClusterAnalyze {
+	if in == nil {
+		return nil
+	}
+	out := new(ManagedClusterAnalyze)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Memory) DeepCopyInto(out *Memory) {
+	*out = *in
+	in.HostCollectorMeta.DeepCopyInto(&out.HostCollectorMeta)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Memory.
+func (in *Memory) DeepCopy() *Memory {
+	if in == nil {
+		return nil
+	}
+	out := new(Memory)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MemoryAnalyze) DeepCopyInto(out *MemoryAnalyze) {
+	*out = *in
+	in.AnalyzeMeta.DeepCopyInto(&out.AnalyzeMeta)
+	if in.Outcomes != nil {
+		in, out := &in.Outcomes, &out.Outcomes
+		*out = make([]*Outcome, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(Outcome)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MemoryAnalyze.
+func (in *MemoryAnalyze) DeepCopy() *MemoryAnalyze {
+	if in == nil {
+		return nil
+	}
+	out := new(MemoryAnalyze)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MetricRequest) DeepCopyInto(out *MetricRequest) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetricRequest.
+func (in *MetricRequest) DeepCopy() *MetricRequest {
+	if in == nil {
+		return nil
+	}
+	out := new(MetricRequest)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MetricsAnalyze) DeepCopyInto(out *MetricsAnalyze) {
+	*out = *in
+	in.AnalyzeMeta.DeepCopyInto(&out.AnalyzeMeta)
+	if in.Outcomes != nil {
+		in, out := &in.Outcomes, &out.Outcomes
+		*out = make([]*Outcome, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(Outcome)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetricsAnalyze.
+func (in *MetricsAnalyze) DeepCopy() *MetricsAnalyze {
+	if in == nil {
+		return nil
+	}
+	out := new(MetricsAnalyze)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MutableImageTags) DeepCopyInto(out *MutableImageTags) {
+	*out = *in
+	in.AnalyzeMeta.DeepCopyInto(&out.AnalyzeMeta)
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Outcomes != nil {
+		in, out := &in.Outcomes, &out.Outcomes
+		*out = make([]*Outcome, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(Outcome)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MutableImageTags.
+func (in *MutableImageTags) DeepCopy() *MutableImageTags {
+	if in == nil {
+		return nil
+	}
+	out := new(MutableImageTags)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkConfigAnalyze) DeepCopyInto(out *NetworkConfigAnalyze) {
+	*out = *in
+	in.AnalyzeMeta.DeepCopyInto(&out.AnalyzeMeta)
+	if in.Outcomes != nil {
+		in, out := &in.Outcomes, &out.Outcomes
+		*out = make([]*Outcome, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(Outcome)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkConfigAnalyze.
+func (in *NetworkConfigAnalyze) DeepCopy() *NetworkConfigAnalyze {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkConfigAnalyze)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkNamespaceConnectivityAnalyze) DeepCopyInto(out *NetworkNamespaceConnectivityAnalyze) {
+	*out = *in
+	in.AnalyzeMeta.DeepCopyInto(&out.AnalyzeMeta)
+	if in.Outcomes != nil {
+		in, out := &in.Outcomes, &out.Outcomes
+		*out = make([]*Outcome, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(Outcome)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkNamespaceConnectivityAnalyze.
+func (in *NetworkNamespaceConnectivityAnalyze) DeepCopy() *NetworkNamespaceConnectivityAnalyze {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkNamespaceConnectivityAnalyze)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeLatency) DeepCopyInto(out *NodeLatency) {
+	*out = *in
+	in.CollectorMeta.DeepCopyInto(&out.CollectorMeta)
+	if in.ImagePullSecret != nil {
+		in, out := &in.ImagePullSecret, &out.ImagePullSecret
+		*out = new(ImagePullSecrets)
+		(*in).DeepCopyInto(*out)
+	}
+	in.PodScheduling.DeepCopyInto(&out.PodScheduling)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeLatency.
+func (in *NodeLatency) DeepCopy() *NodeLatency {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeLatency)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeLatencyAnalyze) DeepCopyInto(out *NodeLatencyAnalyze) {
+	*out = *in
+	in.AnalyzeMeta.DeepCopyInto(&out.AnalyzeMeta)
+	if in.Outcomes != nil {
+		in, out := &in.Outcomes, &out.Outcomes
+		*out = make([]*Outcome, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(Outcome)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeLatencyAnalyze.
+func (in *NodeLatencyAnalyze) DeepCopy() *NodeLatencyAnalyze {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeLatencyAnalyze)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeMetrics) DeepCopyInto(out *NodeMetrics) {
+	*out = *in
+	in.CollectorMeta.DeepCopyInto(&out.CollectorMeta)
+	if in.NodeNames != nil {
+		in, out := &in.NodeNames, &out.NodeNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeMetrics.
+func (in *NodeMetrics) DeepCopy() *NodeMetrics {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeMetrics)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeMetricsAnalyze) DeepCopyInto(out *NodeMetricsAnalyze) {
+	*out = *in
+	in.AnalyzeMeta.DeepCopyInto(&out.AnalyzeMeta)
+	in.Filters.DeepCopyInto(&out.Filters)
+	if in.Outcomes != nil {
+		in, out := &in.Outcomes, &out.Outcomes
+		*out = make([]*Outcome, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(Outcome)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeMetricsAnalyze.
+func (in *NodeMetricsAnalyze) DeepCopy() *NodeMetricsAnalyze {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeMetricsAnalyze)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeMetricsAnalyzeFilters) DeepCopyInto(out *NodeMetricsAnalyzeFilters) {
+	*out = *in
+	if in.PVC != nil {
+		in, out := &in.PVC, &out.PVC
+		*out = new(PVCRef)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeMetricsAnalyzeFilters.
+func (in *NodeMetricsAnalyzeFilters) DeepCopy() *NodeMetricsAnalyzeFilters {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeMetricsAnalyzeFilters)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeNotReadyTrigger) DeepCopyInto(out *NodeNotReadyTrigger) {
+	*out = *in
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeNotReadyTrigger.
+func (in *NodeNotReadyTrigger) DeepCopy() *NodeNotReadyTrigger {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeNotReadyTrigger)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeResourceFilters) DeepCopyInto(out *NodeResourceFilters) {
+	*out = *in
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = new(NodeResourceSelectors)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeResourceFilters.
+func (in *NodeResourceFilters) DeepCopy() *NodeResourceFilters {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeResourceFilters)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeResourceSelectors) DeepCopyInto(out *NodeResourceSelectors) {
+	*out = *in
+	if in.MatchLabel != nil {
+		in, out := &in.MatchLabel, &out.MatchLabel
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.MatchExpressions != nil {
+		in, out := &in.MatchExpressions, &out.MatchExpressions
+		*out = make([]v1.LabelSelectorRequirement, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeResourceSelectors.
+func (in *NodeResourceSelectors) DeepCopy() *NodeResourceSelectors {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeResourceSelectors)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeResources) DeepCopyInto(out *NodeResources) {
+	*out = *in
+	in.AnalyzeMeta.DeepCopyInto(&out.AnalyzeMeta)
+	if in.Outcomes != nil {
+		in, out := &in.Outcomes, &out.Outcomes
+		*out = make([]*Outcome, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(Outcome)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+	if in.Filters != nil {
+		in, out := &in.Filters, &out.Filters
+		*out = new(NodeResourceFilters)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeResources.
+func (in *NodeResources) DeepCopy() *NodeResources {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeResources)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Notification) DeepCopyInto(out *Notification) {
+	*out = *in
+	if in.Webhook != nil {
+		in, out := &in.Webhook, &out.Webhook
+		*out = new(WebhookNotification)
+		**out = **in
+	}
+	if in.Slack != nil {
+		in, out := &in.Slack, &out.Slack
+		*out = new(WebhookNotification)
+		**out = **in
+	}
+	if in.SMTP != nil {
+		in, out := &in.SMTP, &out.SMTP
+		*out = new(SMTPNotification)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Notification.
+func (in *Notification) DeepCopy() *Notification {
+	if in == nil {
+		return nil
+	}
+	out := new(Notification)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OIDC) DeepCopyInto(out *OIDC) {
+	*out = *in
+	in.CollectorMeta.DeepCopyInto(&out.CollectorMeta)
+	if in.ClientSecret != nil {
+		in, out := &in.ClientSecret, &out.ClientSecret
+		*out = new(CredentialSecret)
+		**out = **in
+	}
+	if in.Scopes != nil {
+		in, out := &in.Scopes, &out.Scopes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(TLSParams)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OIDC.
+func (in *OIDC) DeepCopy() *OIDC {
+	if in == nil {
+		return nil
+	}
+	out := new(OIDC)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OIDCAnalyze) DeepCopyInto(out *OIDCAnalyze) {
+	*out = *in
+	in.AnalyzeMeta.DeepCopyInto(&out.AnalyzeMeta)
+	if in.Outcomes != nil {
+		in, out := &in.Outcomes, &out.Outcomes
+		*out = make([]*Outcome, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(Outcome)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OIDCAnalyze.
+func (in *OIDCAnalyze) DeepCopy() *OIDCAnalyze {
+	if in == nil {
+		return nil
+	}
+	out := new(OIDCAnalyze)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpenShift) DeepCopyInto(out *OpenShift) {
+	*out = *in
+	in.CollectorMeta.DeepCopyInto(&out.CollectorMeta)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenShift.
+func (in *OpenShift) DeepCopy() *OpenShift {
+	if in == nil {
+		return nil
+	}
+	out := new(OpenShift)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Outcome) DeepCopyInto(out *Outcome) {
+	*out = *in
+	if in.Fail != nil {
+		in, out := &in.Fail, &out.Fail
+		*out = new(SingleOutcome)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Warn != nil {
+		in, out := &in.Warn, &out.Warn
+		*out = new(SingleOutcome)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Pass != nil {
+		in, out := &in.Pass, &out.Pass
+		*out = new(SingleOutcome)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Outcome.
+func (in *Outcome) DeepCopy() *Outcome {
+	if in == nil {
+		return nil
+	}
+	out := new(Outcome)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PVCRef) DeepCopyInto(out *PVCRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PVCRef.
+func (in *PVCRef) DeepCopy() *PVCRef {
+	if in == nil {
+		return nil
+	}
+	out := new(PVCRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PathsAnalyze) DeepCopyInto(out *PathsAnalyze) {
+	*out = *in
+	in.AnalyzeMeta.DeepCopyInto(&out.AnalyzeMeta)
+	if in.Outcomes != nil {
+		in, out := &in.Outcomes, &out.Outcomes
+		*out = make([]*Outcome, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(Outcome)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PathsAnalyze.
+func (in *PathsAnalyze) DeepCopy() *PathsAnalyze {
+	if in == nil {
+		return nil
+	}
+	out := new(PathsAnalyze)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PluginAnalyze) DeepCopyInto(out *PluginAnalyze) {
+	*out = *in
+	in.AnalyzeMeta.DeepCopyInto(&out.AnalyzeMeta)
+	if in.Args != nil {
+		in, out := &in.Args, &out.Args
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Config != nil {
+		in, out := &in.Config, &out.Config
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PluginAnalyze.
+func (in *PluginAnalyze) DeepCopy() *PluginAnalyze {
+	if in == nil {
+		return nil
+	}
+	out := new(PluginAnalyze)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PluginCollector) DeepCopyInto(out *PluginCollector) {
+	*out = *in
+	in.CollectorMeta.DeepCopyInto(&out.CollectorMeta)
+	if in.Args != nil {
+		in, out := &in.Args, &out.Args
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Config != nil {
+		in, out := &in.Config, &out.Config
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PluginCollector.
+func (in *PluginCollector) DeepCopy() *PluginCollector {
+	if in == nil {
+		return nil
+	}
+	out := new(PluginCollector)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodDisruptionBudget) DeepCopyInto(out *PodDisruptionBudget) {
+	*out = *in
+	in.AnalyzeMeta.DeepCopyInto(&out.AnalyzeMeta)
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Outcomes != nil {
+		in, out := &in.Outcomes, &out.Outcomes
+		*out = make([]*Outcome, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(Outcome)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodDisruptionBudget.
+func (in *PodDisruptionBudget) DeepCopy() *PodDisruptionBudget {
+	if in == nil {
+		return nil
+	}
+	out := new(PodDisruptionBudget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodLaunchOptions) DeepCopyInto(out *PodLaunchOptions) {
+	*out = *in
+	if in.ImagePullSecret != nil {
+		in, out := &in.ImagePullSecret, &out.ImagePullSecret
+		*out = new(ImagePullSecrets)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodLaunchOptions.
+func (in *PodLaunchOptions) DeepCopy() *PodLaunchOptions {
+	if in == nil {
+		return nil
+	}
+	out := new(PodLaunchOptions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodScheduling) DeepCopyInto(out *PodScheduling) {
+	*out = *in
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Affinity != nil {
+		in, out := &in.Affinity, &out.Affinity
+		*out = new(corev1.Affinity)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]corev1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodScheduling.
+func (in *PodScheduling) DeepCopy() *PodScheduling {
+	if in == nil {
+		return nil
+	}
+	out := new(PodScheduling)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodSecurityAnalyze) DeepCopyInto(out *PodSecurityAnalyze) {
+	*out = *in
+	in.AnalyzeMeta.DeepCopyInto(&out.AnalyzeMeta)
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Outcomes != nil {
+		in, out := &in.Outcomes, &out.Outcomes
+		*out = make([]*Outcome, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(Outcome)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodSecurityAnalyze.
+func (in *PodSecurityAnalyze) DeepCopy() *PodSecurityAnalyze {
+	if in == nil {
+		return nil
+	}
+	out := new(PodSecurityAnalyze)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PolicyViolations) DeepCopyInto(out *PolicyViolations) {
+	*out = *in
+	in.CollectorMeta.DeepCopyInto(&out.CollectorMeta)
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Engines != nil {
+		in, out := &in.Engines, &out.Engines
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PolicyViolations.
+func (in *PolicyViolations) DeepCopy() *PolicyViolations {
+	if in == nil {
+		return nil
+	}
+	out := new(PolicyViolations)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PolicyViolationsAnalyze) DeepCopyInto(out *PolicyViolationsAnalyze) {
+	*out = *in
+	in.AnalyzeMeta.DeepCopyInto(&out.AnalyzeMeta)
+	if in.Policies != nil {
+		in, out := &in.Policies, &out.Policies
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Outcomes != nil {
+		in, out := &in.Outcomes, &out.Outcomes
+		*out = make([]*Outcome, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(Outcome)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PolicyViolationsAnalyze.
+func (in *PolicyViolationsAnalyze) DeepCopy() *PolicyViolationsAnalyze {
+	if in == nil {
+		return nil
+	}
+	out := new(PolicyViolationsAnalyze)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Post) DeepCopyInto(out *Post) {
+	*out = *in
+	if in.Headers != nil {
+		in, out := &in.Headers, &out.Headers
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(TLSParams)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Retry != nil {
+		in, out := &in.Retry, &out.Retry
+		*out = new(HTTPRetry)
+		(*in).DeepCopyInto(*out)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeMetricsAnalyzeFilters.
-func (in *NodeMetricsAnalyzeFilters) DeepCopy() *NodeMetricsAnalyzeFilters {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Post.
+func (in *Post) DeepCopy() *Post {
 	if in == nil {
 		return nil
 	}
-	out := new(NodeMetricsAnalyzeFilters)
+	out := new(Post)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NodeResourceFilters) DeepCopyInto(out *NodeResourceFilters) {
+func (in *PostCollection) DeepCopyInto(out *PostCollection) {
 	*out = *in
-	if in.Selector != nil {
-		in, out := &in.Selector, &out.Selector
-		*out = new(NodeResourceSelectors)
+	if in.Summary != nil {
+		in, out := &in.Summary, &out.Summary
+		*out = new(PostCollectionSummary)
+		**out = **in
+	}
+	if in.Report != nil {
+		in, out := &in.Report, &out.Report
+		*out = new(PostCollectionReport)
+		**out = **in
+	}
+	if in.Statistics != nil {
+		in, out := &in.Statistics, &out.Statistics
+		*out = new(PostCollectionStatistics)
+		**out = **in
+	}
+	if in.StripFiles != nil {
+		in, out := &in.StripFiles, &out.StripFiles
+		*out = new(PostCollectionStripFiles)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Processor != nil {
+		in, out := &in.Processor, &out.Processor
+		*out = new(PostCollectionProcessor)
 		(*in).DeepCopyInto(*out)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeResourceFilters.
-func (in *NodeResourceFilters) DeepCopy() *NodeResourceFilters {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostCollection.
+func (in *PostCollection) DeepCopy() *PostCollection {
 	if in == nil {
 		return nil
 	}
-	out := new(NodeResourceFilters)
+	out := new(PostCollection)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NodeResourceSelectors) DeepCopyInto(out *NodeResourceSelectors) {
+func (in *PostCollectionProcessor) DeepCopyInto(out *PostCollectionProcessor) {
 	*out = *in
-	if in.MatchLabel != nil {
-		in, out := &in.MatchLabel, &out.MatchLabel
+	if in.Args != nil {
+		in, out := &in.Args, &out.Args
 		*out = make(map[string]string, len(*in))
 		for key, val := range *in {
 			(*out)[key] = val
 		}
 	}
-	if in.MatchExpressions != nil {
-		in, out := &in.MatchExpressions, &out.MatchExpressions
-		*out = make([]v1.LabelSelectorRequirement, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeResourceSelectors.
-func (in *NodeResourceSelectors) DeepCopy() *NodeResourceSelectors {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostCollectionProcessor.
+func (in *PostCollectionProcessor) DeepCopy() *PostCollectionProcessor {
 	if in == nil {
 		return nil
 	}
-	out := new(NodeResourceSelectors)
+	out := new(PostCollectionProcessor)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *NodeResources) DeepCopyInto(out *NodeResources) {
+func (in *PostCollectionReport) DeepCopyInto(out *PostCollectionReport) {
 	*out = *in
-	in.AnalyzeMeta.DeepCopyInto(&out.AnalyzeMeta)
-	if in.Outcomes != nil {
-		in, out := &in.Outcomes, &out.Outcomes
-		*out = make([]*Outcome, len(*in))
-		for i := range *in {
-			if (*in)[i] != nil {
-				in, out := &(*in)[i], &(*out)[i]
-				*out = new(Outcome)
-				(*in).DeepCopyInto(*out)
-			}
-		}
-	}
-	if in.Filters != nil {
-		in, out := &in.Filters, &out.Filters
-		*out = new(NodeResourceFilters)
-		(*in).DeepCopyInto(*out)
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeResources.
-func (in *NodeResources) DeepCopy() *NodeResources {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostCollectionReport.
+func (in *PostCollectionReport) DeepCopy() *PostCollectionReport {
 	if in == nil {
 		return nil
 	}
-	out := new(NodeResources)
+	out := new(PostCollectionReport)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Outcome) DeepCopyInto(out *Outcome) {
+func (in *PostCollectionStatistics) DeepCopyInto(out *PostCollectionStatistics) {
 	*out = *in
-	if in.Fail != nil {
-		in, out := &in.Fail, &out.Fail
-		*out = new(SingleOutcome)
-		**out = **in
-	}
-	if in.Warn != nil {
-		in, out := &in.Warn, &out.Warn
-		*out = new(SingleOutcome)
-		**out = **in
-	}
-	if in.Pass != nil {
-		in, out := &in.Pass, &out.Pass
-		*out = new(SingleOutcome)
-		**out = **in
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Outcome.
-func (in *Outcome) DeepCopy() *Outcome {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostCollectionStatistics.
+func (in *PostCollectionStatistics) DeepCopy() *PostCollectionStatistics {
 	if in == nil {
 		return nil
 	}
-	out := new(Outcome)
+	out := new(PostCollectionStatistics)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PVCRef) DeepCopyInto(out *PVCRef) {
+func (in *PostCollectionStripFiles) DeepCopyInto(out *PostCollectionStripFiles) {
 	*out = *in
+	if in.Files != nil {
+		in, out := &in.Files, &out.Files
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PVCRef.
-func (in *PVCRef) DeepCopy() *PVCRef {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostCollectionStripFiles.
+func (in *PostCollectionStripFiles) DeepCopy() *PostCollectionStripFiles {
 	if in == nil {
 		return nil
 	}
-	out := new(PVCRef)
+	out := new(PostCollectionStripFiles)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PodLaunchOptions) DeepCopyInto(out *PodLaunchOptions) {
+func (in *PostCollectionSummary) DeepCopyInto(out *PostCollectionSummary) {
 	*out = *in
-	if in.ImagePullSecret != nil {
-		in, out := &in.ImagePullSecret, &out.ImagePullSecret
-		*out = new(ImagePullSecrets)
-		(*in).DeepCopyInto(*out)
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodLaunchOptions.
-func (in *PodLaunchOptions) DeepCopy() *PodLaunchOptions {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostCollectionSummary.
+func (in *PostCollectionSummary) DeepCopy() *PostCollectionSummary {
 	if in == nil {
 		return nil
 	}
-	out := new(PodLaunchOptions)
+	out := new(PostCollectionSummary)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Post) DeepCopyInto(out *Post) {
+func (in *Pprof) DeepCopyInto(out *Pprof) {
 	*out = *in
+	in.CollectorMeta.DeepCopyInto(&out.CollectorMeta)
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Profiles != nil {
+		in, out := &in.Profiles, &out.Profiles
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.Headers != nil {
 		in, out := &in.Headers, &out.Headers
 		*out = make(map[string]string, len(*in))
@@ -3640,6 +6254,11 @@ func (in *Post) DeepCopyInto(out *Post) {
 			(*out)[key] = val
 		}
 	}
+	if in.BearerTokenSecret != nil {
+		in, out := &in.BearerTokenSecret, &out.BearerTokenSecret
+		*out = new(CredentialSecret)
+		**out = **in
+	}
 	if in.TLS != nil {
 		in, out := &in.TLS, &out.TLS
 		*out = new(TLSParams)
@@ -3647,12 +6266,12 @@ func (in *Post) DeepCopyInto(out *Post) {
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Post.
-func (in *Post) DeepCopy() *Post {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Pprof.
+func (in *Pprof) DeepCopy() *Pprof {
 	if in == nil {
 		return nil
 	}
-	out := new(Post)
+	out := new(Pprof)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -3752,6 +6371,28 @@ func (in *PreflightSpec) DeepCopyInto(out *PreflightSpec) {
 			}
 		}
 	}
+	if in.Notifications != nil {
+		in, out := &in.Notifications, &out.Notifications
+		*out = make([]*Notification, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(Notification)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+	if in.Include != nil {
+		in, out := &in.Include, &out.Include
+		*out = make([]*SpecInclude, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(SpecInclude)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PreflightSpec.
@@ -3779,6 +6420,59 @@ func (in *PreflightStatus) DeepCopy() *PreflightStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Proxy) DeepCopyInto(out *Proxy) {
+	*out = *in
+	in.CollectorMeta.DeepCopyInto(&out.CollectorMeta)
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.CIDRs != nil {
+		in, out := &in.CIDRs, &out.CIDRs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Proxy.
+func (in *Proxy) DeepCopy() *Proxy {
+	if in == nil {
+		return nil
+	}
+	out := new(Proxy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProxyAnalyze) DeepCopyInto(out *ProxyAnalyze) {
+	*out = *in
+	in.AnalyzeMeta.DeepCopyInto(&out.AnalyzeMeta)
+	if in.Outcomes != nil {
+		in, out := &in.Outcomes, &out.Outcomes
+		*out = make([]*Outcome, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(Outcome)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProxyAnalyze.
+func (in *ProxyAnalyze) DeepCopy() *ProxyAnalyze {
+	if in == nil {
+		return nil
+	}
+	out := new(ProxyAnalyze)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Put) DeepCopyInto(out *Put) {
 	*out = *in
@@ -3794,6 +6488,11 @@ func (in *Put) DeepCopyInto(out *Put) {
 		*out = new(TLSParams)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Retry != nil {
+		in, out := &in.Retry, &out.Retry
+		*out = new(HTTPRetry)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Put.
@@ -3806,6 +6505,59 @@ func (in *Put) DeepCopy() *Put {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RabbitMQ) DeepCopyInto(out *RabbitMQ) {
+	*out = *in
+	in.CollectorMeta.DeepCopyInto(&out.CollectorMeta)
+	if in.URISecret != nil {
+		in, out := &in.URISecret, &out.URISecret
+		*out = new(DatabaseURISecret)
+		**out = **in
+	}
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(TLSParams)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RabbitMQ.
+func (in *RabbitMQ) DeepCopy() *RabbitMQ {
+	if in == nil {
+		return nil
+	}
+	out := new(RabbitMQ)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RabbitMQAnalyze) DeepCopyInto(out *RabbitMQAnalyze) {
+	*out = *in
+	in.AnalyzeMeta.DeepCopyInto(&out.AnalyzeMeta)
+	if in.Outcomes != nil {
+		in, out := &in.Outcomes, &out.Outcomes
+		*out = make([]*Outcome, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(Outcome)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RabbitMQAnalyze.
+func (in *RabbitMQAnalyze) DeepCopy() *RabbitMQAnalyze {
+	if in == nil {
+		return nil
+	}
+	out := new(RabbitMQAnalyze)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Redact) DeepCopyInto(out *Redact) {
 	*out = *in
@@ -3952,6 +6704,22 @@ func (in *RegistryImages) DeepCopyInto(out *RegistryImages) {
 		*out = new(ImagePullSecrets)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.RegistryMirrors != nil {
+		in, out := &in.RegistryMirrors, &out.RegistryMirrors
+		*out = make(map[string][]string, len(*in))
+		for key, val := range *in {
+			var outVal []string
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				inVal := (*in)[key]
+				in, out := &inVal, &outVal
+				*out = make([]string, len(*in))
+				copy(*out, *in)
+			}
+			(*out)[key] = outVal
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RegistryImages.
@@ -3981,12 +6749,54 @@ func (in *RegistryImagesAnalyze) DeepCopyInto(out *RegistryImagesAnalyze) {
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RegistryImagesAnalyze.
-func (in *RegistryImagesAnalyze) DeepCopy() *RegistryImagesAnalyze {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RegistryImagesAnalyze.
+func (in *RegistryImagesAnalyze) DeepCopy() *RegistryImagesAnalyze {
+	if in == nil {
+		return nil
+	}
+	out := new(RegistryImagesAnalyze)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RegoAnalyze) DeepCopyInto(out *RegoAnalyze) {
+	*out = *in
+	in.AnalyzeMeta.DeepCopyInto(&out.AnalyzeMeta)
+	if in.Outcomes != nil {
+		in, out := &in.Outcomes, &out.Outcomes
+		*out = make([]*Outcome, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(Outcome)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RegoAnalyze.
+func (in *RegoAnalyze) DeepCopy() *RegoAnalyze {
+	if in == nil {
+		return nil
+	}
+	out := new(RegoAnalyze)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Remediation) DeepCopyInto(out *Remediation) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Remediation.
+func (in *Remediation) DeepCopy() *Remediation {
 	if in == nil {
 		return nil
 	}
-	out := new(RegistryImagesAnalyze)
+	out := new(Remediation)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -4631,6 +7441,38 @@ func (in *ReplicaSetStatus) DeepCopy() *ReplicaSetStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceQuotaAnalyze) DeepCopyInto(out *ResourceQuotaAnalyze) {
+	*out = *in
+	in.AnalyzeMeta.DeepCopyInto(&out.AnalyzeMeta)
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Outcomes != nil {
+		in, out := &in.Outcomes, &out.Outcomes
+		*out = make([]*Outcome, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(Outcome)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceQuotaAnalyze.
+func (in *ResourceQuotaAnalyze) DeepCopy() *ResourceQuotaAnalyze {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceQuotaAnalyze)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ResultRequest) DeepCopyInto(out *ResultRequest) {
 	*out = *in
@@ -4716,6 +7558,11 @@ func (in *RunPod) DeepCopyInto(out *RunPod) {
 			(*out)[key] = val
 		}
 	}
+	if in.Artifacts != nil {
+		in, out := &in.Artifacts, &out.Artifacts
+		*out = new(RunPodArtifacts)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RunPod.
@@ -4728,6 +7575,142 @@ func (in *RunPod) DeepCopy() *RunPod {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RunPodArtifacts) DeepCopyInto(out *RunPodArtifacts) {
+	*out = *in
+	if in.Paths != nil {
+		in, out := &in.Paths, &out.Paths
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RunPodArtifacts.
+func (in *RunPodArtifacts) DeepCopy() *RunPodArtifacts {
+	if in == nil {
+		return nil
+	}
+	out := new(RunPodArtifacts)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SMTPNotification) DeepCopyInto(out *SMTPNotification) {
+	*out = *in
+	if in.To != nil {
+		in, out := &in.To, &out.To
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SMTPNotification.
+func (in *SMTPNotification) DeepCopy() *SMTPNotification {
+	if in == nil {
+		return nil
+	}
+	out := new(SMTPNotification)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScheduledBundle) DeepCopyInto(out *ScheduledBundle) {
+	*out = *in
+	in.CollectedAt.DeepCopyInto(&out.CollectedAt)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduledBundle.
+func (in *ScheduledBundle) DeepCopy() *ScheduledBundle {
+	if in == nil {
+		return nil
+	}
+	out := new(ScheduledBundle)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScheduledBundleNotification) DeepCopyInto(out *ScheduledBundleNotification) {
+	*out = *in
+	if in.TokenSecretRef != nil {
+		in, out := &in.TokenSecretRef, &out.TokenSecretRef
+		*out = new(corev1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduledBundleNotification.
+func (in *ScheduledBundleNotification) DeepCopy() *ScheduledBundleNotification {
+	if in == nil {
+		return nil
+	}
+	out := new(ScheduledBundleNotification)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScheduledBundleObjectStorage) DeepCopyInto(out *ScheduledBundleObjectStorage) {
+	*out = *in
+	if in.TokenSecretRef != nil {
+		in, out := &in.TokenSecretRef, &out.TokenSecretRef
+		*out = new(corev1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduledBundleObjectStorage.
+func (in *ScheduledBundleObjectStorage) DeepCopy() *ScheduledBundleObjectStorage {
+	if in == nil {
+		return nil
+	}
+	out := new(ScheduledBundleObjectStorage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScheduledBundlePersistentVolumeClaim) DeepCopyInto(out *ScheduledBundlePersistentVolumeClaim) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduledBundlePersistentVolumeClaim.
+func (in *ScheduledBundlePersistentVolumeClaim) DeepCopy() *ScheduledBundlePersistentVolumeClaim {
+	if in == nil {
+		return nil
+	}
+	out := new(ScheduledBundlePersistentVolumeClaim)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScheduledBundleStorage) DeepCopyInto(out *ScheduledBundleStorage) {
+	*out = *in
+	if in.PersistentVolumeClaim != nil {
+		in, out := &in.PersistentVolumeClaim, &out.PersistentVolumeClaim
+		*out = new(ScheduledBundlePersistentVolumeClaim)
+		**out = **in
+	}
+	if in.ObjectStorage != nil {
+		in, out := &in.ObjectStorage, &out.ObjectStorage
+		*out = new(ScheduledBundleObjectStorage)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduledBundleStorage.
+func (in *ScheduledBundleStorage) DeepCopy() *ScheduledBundleStorage {
+	if in == nil {
+		return nil
+	}
+	out := new(ScheduledBundleStorage)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Secret) DeepCopyInto(out *Secret) {
 	*out = *in
@@ -4749,9 +7732,41 @@ func (in *Secret) DeepCopy() *Secret {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SecurityAnalyze) DeepCopyInto(out *SecurityAnalyze) {
+	*out = *in
+	in.AnalyzeMeta.DeepCopyInto(&out.AnalyzeMeta)
+	if in.Outcomes != nil {
+		in, out := &in.Outcomes, &out.Outcomes
+		*out = make([]*Outcome, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(Outcome)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecurityAnalyze.
+func (in *SecurityAnalyze) DeepCopy() *SecurityAnalyze {
+	if in == nil {
+		return nil
+	}
+	out := new(SecurityAnalyze)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SingleOutcome) DeepCopyInto(out *SingleOutcome) {
 	*out = *in
+	if in.Remediation != nil {
+		in, out := &in.Remediation, &out.Remediation
+		*out = new(Remediation)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SingleOutcome.
@@ -4780,6 +7795,58 @@ func (in *Sonobuoy) DeepCopy() *Sonobuoy {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SpecInclude) DeepCopyInto(out *SpecInclude) {
+	*out = *in
+	if in.ConfigMap != nil {
+		in, out := &in.ConfigMap, &out.ConfigMap
+		*out = new(IncludeConfigMap)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SpecInclude.
+func (in *SpecInclude) DeepCopy() *SpecInclude {
+	if in == nil {
+		return nil
+	}
+	out := new(SpecInclude)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StatefulSetVolumes) DeepCopyInto(out *StatefulSetVolumes) {
+	*out = *in
+	in.AnalyzeMeta.DeepCopyInto(&out.AnalyzeMeta)
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Outcomes != nil {
+		in, out := &in.Outcomes, &out.Outcomes
+		*out = make([]*Outcome, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(Outcome)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StatefulSetVolumes.
+func (in *StatefulSetVolumes) DeepCopy() *StatefulSetVolumes {
+	if in == nil {
+		return nil
+	}
+	out := new(StatefulSetVolumes)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *StatefulsetStatus) DeepCopyInto(out *StatefulsetStatus) {
 	*out = *in
@@ -4923,13 +7990,72 @@ func (in *SupportBundle) DeepCopy() *SupportBundle {
 	if in == nil {
 		return nil
 	}
-	out := new(SupportBundle)
+	out := new(SupportBundle)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SupportBundle) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SupportBundleList) DeepCopyInto(out *SupportBundleList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]SupportBundle, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SupportBundleList.
+func (in *SupportBundleList) DeepCopy() *SupportBundleList {
+	if in == nil {
+		return nil
+	}
+	out := new(SupportBundleList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SupportBundleList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SupportBundleSchedule) DeepCopyInto(out *SupportBundleSchedule) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SupportBundleSchedule.
+func (in *SupportBundleSchedule) DeepCopy() *SupportBundleSchedule {
+	if in == nil {
+		return nil
+	}
+	out := new(SupportBundleSchedule)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *SupportBundle) DeepCopyObject() runtime.Object {
+func (in *SupportBundleSchedule) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -4937,37 +8063,113 @@ func (in *SupportBundle) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *SupportBundleList) DeepCopyInto(out *SupportBundleList) {
+func (in *SupportBundleScheduleList) DeepCopyInto(out *SupportBundleScheduleList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]SupportBundle, len(*in))
+		*out = make([]SupportBundleSchedule, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SupportBundleList.
-func (in *SupportBundleList) DeepCopy() *SupportBundleList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SupportBundleScheduleList.
+func (in *SupportBundleScheduleList) DeepCopy() *SupportBundleScheduleList {
 	if in == nil {
 		return nil
 	}
-	out := new(SupportBundleList)
+	out := new(SupportBundleScheduleList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *SupportBundleList) DeepCopyObject() runtime.Object {
+func (in *SupportBundleScheduleList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SupportBundleScheduleSpec) DeepCopyInto(out *SupportBundleScheduleSpec) {
+	*out = *in
+	if in.RetainFor != nil {
+		in, out := &in.RetainFor, &out.RetainFor
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	in.SupportBundle.DeepCopyInto(&out.SupportBundle)
+	in.Storage.DeepCopyInto(&out.Storage)
+	if in.Triggers != nil {
+		in, out := &in.Triggers, &out.Triggers
+		*out = make([]CollectionTrigger, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.TriggerCooldown != nil {
+		in, out := &in.TriggerCooldown, &out.TriggerCooldown
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.Notification != nil {
+		in, out := &in.Notification, &out.Notification
+		*out = new(ScheduledBundleNotification)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SupportBundleScheduleSpec.
+func (in *SupportBundleScheduleSpec) DeepCopy() *SupportBundleScheduleSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SupportBundleScheduleSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SupportBundleScheduleStatus) DeepCopyInto(out *SupportBundleScheduleStatus) {
+	*out = *in
+	if in.LastScheduleTime != nil {
+		in, out := &in.LastScheduleTime, &out.LastScheduleTime
+		*out = new(v1.Time)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LastSuccessfulTime != nil {
+		in, out := &in.LastSuccessfulTime, &out.LastSuccessfulTime
+		*out = new(v1.Time)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LastTriggerTime != nil {
+		in, out := &in.LastTriggerTime, &out.LastTriggerTime
+		*out = new(v1.Time)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Bundles != nil {
+		in, out := &in.Bundles, &out.Bundles
+		*out = make([]ScheduledBundle, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SupportBundleScheduleStatus.
+func (in *SupportBundleScheduleStatus) DeepCopy() *SupportBundleScheduleStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SupportBundleScheduleStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SupportBundleSpec) DeepCopyInto(out *SupportBundleSpec) {
 	*out = *in
@@ -5026,6 +8228,66 @@ func (in *SupportBundleSpec) DeepCopyInto(out *SupportBundleSpec) {
 			}
 		}
 	}
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Notifications != nil {
+		in, out := &in.Notifications, &out.Notifications
+		*out = make([]*Notification, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(Notification)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+	if in.PostCollection != nil {
+		in, out := &in.PostCollection, &out.PostCollection
+		*out = make([]*PostCollection, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(PostCollection)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+	if in.Include != nil {
+		in, out := &in.Include, &out.Include
+		*out = make([]*SpecInclude, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(SpecInclude)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+	if in.AllowedOperations != nil {
+		in, out := &in.AllowedOperations, &out.AllowedOperations
+		*out = make([]*AllowedOperation, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(AllowedOperation)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+	if in.Documentation != nil {
+		in, out := &in.Documentation, &out.Documentation
+		*out = make([]*Documentation, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(Documentation)
+				**out = **in
+			}
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SupportBundleSpec.
@@ -5159,6 +8421,33 @@ func (in *SystemPackagesAnalyze) DeepCopy() *SystemPackagesAnalyze {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SystemdUnitsAnalyze) DeepCopyInto(out *SystemdUnitsAnalyze) {
+	*out = *in
+	in.AnalyzeMeta.DeepCopyInto(&out.AnalyzeMeta)
+	if in.Outcomes != nil {
+		in, out := &in.Outcomes, &out.Outcomes
+		*out = make([]*Outcome, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(Outcome)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SystemdUnitsAnalyze.
+func (in *SystemdUnitsAnalyze) DeepCopy() *SystemdUnitsAnalyze {
+	if in == nil {
+		return nil
+	}
+	out := new(SystemdUnitsAnalyze)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TCPConnect) DeepCopyInto(out *TCPConnect) {
 	*out = *in
@@ -5382,6 +8671,86 @@ func (in *TimeAnalyze) DeepCopy() *TimeAnalyze {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TimeSyncAnalyze) DeepCopyInto(out *TimeSyncAnalyze) {
+	*out = *in
+	in.AnalyzeMeta.DeepCopyInto(&out.AnalyzeMeta)
+	if in.Outcomes != nil {
+		in, out := &in.Outcomes, &out.Outcomes
+		*out = make([]*Outcome, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(Outcome)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TimeSyncAnalyze.
+func (in *TimeSyncAnalyze) DeepCopy() *TimeSyncAnalyze {
+	if in == nil {
+		return nil
+	}
+	out := new(TimeSyncAnalyze)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TrustChains) DeepCopyInto(out *TrustChains) {
+	*out = *in
+	in.CollectorMeta.DeepCopyInto(&out.CollectorMeta)
+	if in.Sources != nil {
+		in, out := &in.Sources, &out.Sources
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TrustChains.
+func (in *TrustChains) DeepCopy() *TrustChains {
+	if in == nil {
+		return nil
+	}
+	out := new(TrustChains)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TrustChainsAnalyze) DeepCopyInto(out *TrustChainsAnalyze) {
+	*out = *in
+	in.AnalyzeMeta.DeepCopyInto(&out.AnalyzeMeta)
+	if in.Outcomes != nil {
+		in, out := &in.Outcomes, &out.Outcomes
+		*out = make([]*Outcome, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(Outcome)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TrustChainsAnalyze.
+func (in *TrustChainsAnalyze) DeepCopy() *TrustChainsAnalyze {
+	if in == nil {
+		return nil
+	}
+	out := new(TrustChainsAnalyze)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *UDPPortStatus) DeepCopyInto(out *UDPPortStatus) {
 	*out = *in
@@ -5425,6 +8794,22 @@ func (in *UDPPortStatusAnalyze) DeepCopy() *UDPPortStatusAnalyze {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Velero) DeepCopyInto(out *Velero) {
+	*out = *in
+	in.CollectorMeta.DeepCopyInto(&out.CollectorMeta)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Velero.
+func (in *Velero) DeepCopy() *Velero {
+	if in == nil {
+		return nil
+	}
+	out := new(Velero)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VeleroAnalyze) DeepCopyInto(out *VeleroAnalyze) {
 	*out = *in
@@ -5441,6 +8826,76 @@ func (in *VeleroAnalyze) DeepCopy() *VeleroAnalyze {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VeleroReadinessAnalyze) DeepCopyInto(out *VeleroReadinessAnalyze) {
+	*out = *in
+	in.AnalyzeMeta.DeepCopyInto(&out.AnalyzeMeta)
+	if in.Outcomes != nil {
+		in, out := &in.Outcomes, &out.Outcomes
+		*out = make([]*Outcome, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(Outcome)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VeleroReadinessAnalyze.
+func (in *VeleroReadinessAnalyze) DeepCopy() *VeleroReadinessAnalyze {
+	if in == nil {
+		return nil
+	}
+	out := new(VeleroReadinessAnalyze)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WasmAnalyze) DeepCopyInto(out *WasmAnalyze) {
+	*out = *in
+	in.AnalyzeMeta.DeepCopyInto(&out.AnalyzeMeta)
+	if in.Config != nil {
+		in, out := &in.Config, &out.Config
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Limits != nil {
+		in, out := &in.Limits, &out.Limits
+		*out = new(WasmLimits)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WasmAnalyze.
+func (in *WasmAnalyze) DeepCopy() *WasmAnalyze {
+	if in == nil {
+		return nil
+	}
+	out := new(WasmAnalyze)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WasmLimits) DeepCopyInto(out *WasmLimits) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WasmLimits.
+func (in *WasmLimits) DeepCopy() *WasmLimits {
+	if in == nil {
+		return nil
+	}
+	out := new(WasmLimits)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *WeaveReportAnalyze) DeepCopyInto(out *WeaveReportAnalyze) {
 	*out = *in
@@ -5457,6 +8912,21 @@ func (in *WeaveReportAnalyze) DeepCopy() *WeaveReportAnalyze {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WebhookNotification) DeepCopyInto(out *WebhookNotification) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WebhookNotification.
+func (in *WebhookNotification) DeepCopy() *WebhookNotification {
+	if in == nil {
+		return nil
+	}
+	out := new(WebhookNotification)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *YamlCompare) DeepCopyInto(out *YamlCompare) {
 	*out = *in
@@ -0,0 +1,122 @@
+package bundleindex
+
+import (
+	"archive/tar"
+	"io"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/troubleshoot/pkg/collect"
+)
+
+// Match is a line of a file of the bundle that matched a search, with its text.
+type Match struct {
+	File string `json:"file"`
+	Line int    `json:"line"`
+	Text string `json:"text"`
+}
+
+// BuildFromArchive indexes the regular files of a support bundle archive, one at a time, so that
+// bundles larger than memory can be indexed. File names are relative to the root directory of the
+// bundle.
+func BuildFromArchive(archivePath string) (*Index, error) {
+	idx := New()
+	err := eachArchiveFile(archivePath, func(name string, r io.Reader) error {
+		return idx.Add(name, r)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	idx.root = archiveRoot(idx.files)
+	for i, name := range idx.files {
+		idx.files[i] = strings.TrimPrefix(name, idx.root)
+	}
+	return idx, nil
+}
+
+// ReadMatches reads the text of the hits from the archive the index was built from, in the order
+// of the hits.
+func (idx *Index) ReadMatches(archivePath string, hits []Hit) ([]Match, error) {
+	linesByFile := map[string][]int{}
+	for _, hit := range hits {
+		linesByFile[hit.File] = append(linesByFile[hit.File], hit.Line)
+	}
+
+	texts := map[string]map[int]string{}
+	err := eachArchiveFile(archivePath, func(name string, r io.Reader) error {
+		name = strings.TrimPrefix(name, idx.root)
+		lines, ok := linesByFile[name]
+		if !ok {
+			return nil
+		}
+		fileTexts, err := ReadLines(r, lines)
+		if err != nil {
+			return errors.Wrapf(err, "failed to read %s", name)
+		}
+		texts[name] = fileTexts
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	matches := make([]Match, 0, len(hits))
+	for _, hit := range hits {
+		matches = append(matches, Match{File: hit.File, Line: hit.Line, Text: texts[hit.File][hit.Line]})
+	}
+	return matches, nil
+}
+
+// eachArchiveFile calls fn with the name and content of the regular files of an archive. Paths
+// that escape the archive are skipped, like unarchive does.
+func eachArchiveFile(archivePath string, fn func(name string, r io.Reader) error) error {
+	f, err := os.Open(archivePath)
+	if err != nil {
+		return errors.Wrap(err, "failed to open archive")
+	}
+	defer f.Close()
+
+	ar, err := collect.NewBundleArchiveReader(f)
+	if err != nil {
+		return err
+	}
+	defer ar.Close()
+
+	for {
+		header, err := ar.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return errors.Wrap(err, "failed to read tar header")
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		name := path.Clean(header.Name)
+		if strings.HasPrefix(name, "../") || strings.HasPrefix(name, "/") {
+			continue
+		}
+		if err := fn(name, ar); err != nil {
+			return err
+		}
+	}
+}
+
+// archiveRoot returns the directory all of the files are in, including the trailing slash, or an
+// empty string if there is none.
+func archiveRoot(names []string) string {
+	root := ""
+	for _, name := range names {
+		dir, _, found := strings.Cut(name, "/")
+		if !found || (root != "" && root != dir+"/") {
+			return ""
+		}
+		root = dir + "/"
+	}
+	return root
+}
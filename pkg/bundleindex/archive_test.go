@@ -0,0 +1,65 @@
+package bundleindex
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeTestBundle(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	archivePath := filepath.Join(t.TempDir(), "bundle.tar.gz")
+	f, err := os.Create(archivePath)
+	require.NoError(t, err)
+	defer f.Close()
+
+	gzw := gzip.NewWriter(f)
+	defer gzw.Close()
+	tw := tar.NewWriter(gzw)
+	defer tw.Close()
+
+	for name, content := range files {
+		require.NoError(t, tw.WriteHeader(&tar.Header{
+			Name:     name,
+			Mode:     0644,
+			Size:     int64(len(content)),
+			Typeflag: tar.TypeReg,
+		}))
+		_, err := tw.Write([]byte(content))
+		require.NoError(t, err)
+	}
+
+	return archivePath
+}
+
+func TestBuildFromArchive(t *testing.T) {
+	archivePath := writeTestBundle(t, map[string]string{
+		"support-bundle-2026-10-16/version.yaml":                            "apiVersion: troubleshoot.sh/v1beta2\nkind: Version\n",
+		"support-bundle-2026-10-16/cluster-resources/pods/logs/app/api.log": "listening on :8080\nERROR: connection refused to postgres:5432\n",
+		"support-bundle-2026-10-16/postgres/postgres.json":                  `{"isConnected": false, "error": "connection refused"}`,
+		"../escape.txt": "connection refused",
+	})
+
+	idx, err := BuildFromArchive(archivePath)
+	require.NoError(t, err)
+	assert.Equal(t, 3, idx.Files())
+
+	hits := idx.Search("connection refused")
+	assert.Equal(t, []Hit{
+		{File: "cluster-resources/pods/logs/app/api.log", Line: 2},
+		{File: "postgres/postgres.json", Line: 1},
+	}, hits)
+
+	matches, err := idx.ReadMatches(archivePath, hits)
+	require.NoError(t, err)
+	assert.Equal(t, []Match{
+		{File: "cluster-resources/pods/logs/app/api.log", Line: 2, Text: "ERROR: connection refused to postgres:5432"},
+		{File: "postgres/postgres.json", Line: 1, Text: `{"isConnected": false, "error": "connection refused"}`},
+	}, matches)
+}
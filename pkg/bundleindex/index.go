@@ -0,0 +1,352 @@
+// Package bundleindex builds a full-text index of the text files of a support bundle, so that the
+// lines mentioning some words can be found without reading every file of the bundle again. The
+// index maps each term to the lines it appears on, delta and varint encoded, and can be saved
+// next to the bundle and loaded later.
+package bundleindex
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"encoding/gob"
+	"io"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// indexVersion is written with a saved index, indexes of other versions are rejected.
+	indexVersion = 1
+	// maxTermLength skips tokens that are too long to be searched for, such as base64 encoded
+	// blobs, which would otherwise make up most of the index.
+	maxTermLength = 64
+	// binarySniffLength is how much of a file is read to decide whether it is binary.
+	binarySniffLength = 8000
+	// lineBufferSize is how much of a line is read at once, longer lines are read in chunks.
+	lineBufferSize = 64 * 1024
+	// maxLineLength truncates the text of the lines returned by ReadLines.
+	maxLineLength = 4096
+)
+
+// Index is an inverted index of the lines of the text files of a bundle. Binary files are not
+// indexed.
+type Index struct {
+	files []string
+	terms map[string]*postings
+	// root is the directory of the archive the files are in, see BuildFromArchive
+	root string
+}
+
+// Hit is a line of a file of the bundle that has all of the terms of a query. Lines are numbered
+// from 1.
+type Hit struct {
+	File string `json:"file"`
+	Line int    `json:"line"`
+}
+
+// postings are the lines a term appears on, as pairs of varints: the difference with the previous
+// file and, in the same file, the difference with the previous line, or else the line.
+type postings struct {
+	Data     []byte
+	LastFile uint32
+	LastLine uint32
+}
+
+func (p *postings) add(file uint32, line uint32) {
+	if file == p.LastFile && line == p.LastLine {
+		return
+	}
+	if file != p.LastFile {
+		p.Data = binary.AppendUvarint(p.Data, uint64(file-p.LastFile))
+		p.Data = binary.AppendUvarint(p.Data, uint64(line))
+	} else {
+		p.Data = binary.AppendUvarint(p.Data, 0)
+		p.Data = binary.AppendUvarint(p.Data, uint64(line-p.LastLine))
+	}
+	p.LastFile, p.LastLine = file, line
+}
+
+// lines decodes the postings as file<<32|line, in ascending order.
+func (p *postings) lines() []uint64 {
+	lines := []uint64{}
+	file, line := uint64(0), uint64(0)
+	data := p.Data
+	for len(data) > 0 {
+		fileDelta, n := binary.Uvarint(data)
+		data = data[n:]
+		lineValue, n := binary.Uvarint(data)
+		data = data[n:]
+		if fileDelta != 0 {
+			file += fileDelta
+			line = lineValue
+		} else {
+			line += lineValue
+		}
+		lines = append(lines, file<<32|line)
+	}
+	return lines
+}
+
+func New() *Index {
+	return &Index{
+		files: []string{},
+		terms: map[string]*postings{},
+	}
+}
+
+// Files returns the number of files that were indexed.
+func (idx *Index) Files() int {
+	return len(idx.files)
+}
+
+// Terms returns the number of distinct terms in the index.
+func (idx *Index) Terms() int {
+	return len(idx.terms)
+}
+
+// Add indexes the lines of a file. Binary files, which have a NUL byte in their first bytes, are
+// skipped.
+func (idx *Index) Add(name string, r io.Reader) error {
+	reader := bufio.NewReaderSize(r, lineBufferSize)
+	head, err := reader.Peek(binarySniffLength)
+	if err != nil && err != io.EOF {
+		return errors.Wrapf(err, "failed to read %s", name)
+	}
+	if bytes.IndexByte(head, 0) >= 0 {
+		return nil
+	}
+
+	file := uint32(len(idx.files))
+	idx.files = append(idx.files, name)
+
+	// a token split between the chunks of a long line is indexed as two terms
+	err = eachLine(reader, func(line int, chunk []byte) {
+		eachTerm(chunk, func(term string) {
+			p, ok := idx.terms[term]
+			if !ok {
+				p = &postings{}
+				idx.terms[term] = p
+			}
+			p.add(file, uint32(line))
+		})
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to read %s", name)
+	}
+	return nil
+}
+
+// Search returns the lines that have all of the terms of the query, ignoring case, sorted by file
+// and line. Terms are words, numbers, and names made of them joined by dots and dashes such as
+// kube-proxy or 10.0.0.1, which are also found by their parts.
+func (idx *Index) Search(query string) []Hit {
+	queryTerms := []string{}
+	eachQueryTerm(query, func(term string) {
+		queryTerms = append(queryTerms, term)
+	})
+	if len(queryTerms) == 0 {
+		return []Hit{}
+	}
+
+	termPostings := []*postings{}
+	for _, term := range queryTerms {
+		p, ok := idx.terms[term]
+		if !ok {
+			return []Hit{}
+		}
+		termPostings = append(termPostings, p)
+	}
+	// intersecting from the rarest term keeps the intermediate results small
+	sort.Slice(termPostings, func(i, j int) bool {
+		return len(termPostings[i].Data) < len(termPostings[j].Data)
+	})
+
+	lines := termPostings[0].lines()
+	for _, p := range termPostings[1:] {
+		if len(lines) == 0 {
+			break
+		}
+		lines = intersect(lines, p.lines())
+	}
+
+	hits := make([]Hit, 0, len(lines))
+	for _, line := range lines {
+		hits = append(hits, Hit{File: idx.files[line>>32], Line: int(line & 0xffffffff)})
+	}
+	sort.SliceStable(hits, func(i, j int) bool {
+		if hits[i].File != hits[j].File {
+			return hits[i].File < hits[j].File
+		}
+		return hits[i].Line < hits[j].Line
+	})
+	return hits
+}
+
+func intersect(a []uint64, b []uint64) []uint64 {
+	result := []uint64{}
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		switch {
+		case a[i] < b[j]:
+			i++
+		case a[i] > b[j]:
+			j++
+		default:
+			result = append(result, a[i])
+			i++
+			j++
+		}
+	}
+	return result
+}
+
+// savedIndex is how an index is saved, gob encoded and gzip compressed.
+type savedIndex struct {
+	Version int
+	Root    string
+	Files   []string
+	Terms   map[string]*postings
+}
+
+// Save writes the index to w, to be read with Load.
+func (idx *Index) Save(w io.Writer) error {
+	gzw := gzip.NewWriter(w)
+	err := gob.NewEncoder(gzw).Encode(savedIndex{
+		Version: indexVersion,
+		Root:    idx.root,
+		Files:   idx.files,
+		Terms:   idx.terms,
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to encode index")
+	}
+	return errors.Wrap(gzw.Close(), "failed to compress index")
+}
+
+// Load reads an index written by Save.
+func Load(r io.Reader) (*Index, error) {
+	gzr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decompress index")
+	}
+	defer gzr.Close()
+
+	saved := savedIndex{}
+	if err := gob.NewDecoder(gzr).Decode(&saved); err != nil {
+		return nil, errors.Wrap(err, "failed to decode index")
+	}
+	if saved.Version != indexVersion {
+		return nil, errors.Errorf("unsupported index version %d", saved.Version)
+	}
+
+	idx := &Index{
+		files: saved.Files,
+		terms: saved.Terms,
+		root:  saved.Root,
+	}
+	if idx.files == nil {
+		idx.files = []string{}
+	}
+	if idx.terms == nil {
+		idx.terms = map[string]*postings{}
+	}
+	return idx, nil
+}
+
+// ReadLines returns the text of the given lines of r, by line number. Lines longer than 4KiB are
+// truncated.
+func ReadLines(r io.Reader, lines []int) (map[int]string, error) {
+	wanted := map[int]bool{}
+	for _, line := range lines {
+		wanted[line] = true
+	}
+
+	texts := map[int]string{}
+	builders := map[int]*strings.Builder{}
+	err := eachLine(bufio.NewReaderSize(r, lineBufferSize), func(line int, chunk []byte) {
+		if !wanted[line] {
+			return
+		}
+		b, ok := builders[line]
+		if !ok {
+			b = &strings.Builder{}
+			builders[line] = b
+		}
+		if remaining := maxLineLength - b.Len(); remaining > 0 {
+			if len(chunk) > remaining {
+				chunk = chunk[:remaining]
+			}
+			b.Write(chunk)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	for line, b := range builders {
+		texts[line] = b.String()
+	}
+	return texts, nil
+}
+
+// eachLine calls fn with the lines of r, without their line endings, numbered from 1. Lines
+// longer than the buffer of the reader are passed in several chunks with the same number.
+func eachLine(r *bufio.Reader, fn func(line int, chunk []byte)) error {
+	line := 1
+	for {
+		chunk, err := r.ReadSlice('\n')
+		if err == bufio.ErrBufferFull {
+			fn(line, chunk)
+			continue
+		}
+
+		complete := len(chunk) > 0 && chunk[len(chunk)-1] == '\n'
+		chunk = bytes.TrimSuffix(bytes.TrimSuffix(chunk, []byte("\n")), []byte("\r"))
+		if len(chunk) > 0 || complete {
+			fn(line, chunk)
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		line++
+	}
+}
+
+func isTermRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '.' || r == '-'
+}
+
+func isTermSeparator(r rune) bool {
+	return r == '.' || r == '-'
+}
+
+// eachTerm calls fn with the lowercase terms of text, and the parts of the terms joined by dots
+// and dashes. The same term may be passed more than once.
+func eachTerm(text []byte, fn func(term string)) {
+	eachQueryTerm(string(text), func(term string) {
+		fn(term)
+		if strings.IndexFunc(term, isTermSeparator) < 0 {
+			return
+		}
+		for _, part := range strings.FieldsFunc(term, isTermSeparator) {
+			fn(part)
+		}
+	})
+}
+
+// eachQueryTerm calls fn with the lowercase terms of text, trimmed of leading and trailing dots
+// and dashes.
+func eachQueryTerm(text string, fn func(term string)) {
+	for _, token := range strings.FieldsFunc(text, func(r rune) bool { return !isTermRune(r) }) {
+		term := strings.TrimFunc(token, isTermSeparator)
+		if term == "" || len(term) > maxTermLength {
+			continue
+		}
+		fn(strings.ToLower(term))
+	}
+}
@@ -0,0 +1,115 @@
+package bundleindex
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testIndex(t *testing.T) *Index {
+	t.Helper()
+
+	idx := New()
+	files := []struct {
+		name    string
+		content string
+	}{
+		{"cluster-resources/pods/logs/kube-system/kube-proxy-x7d2q/kube-proxy.log", "I1016 starting kube-proxy\r\nE1016 Failed to list *v1.Service: connection refused\nE1016 dial tcp 10.96.0.1:443: connection refused\n"},
+		{"cluster-resources/events/app.json", `{"reason": "BackOff", "message": "Back-off restarting failed container"}`},
+		{"postgres/postgres.json", "\n\nFATAL: too many connections for role \"app\"\n"},
+		{"host-collectors/run-host/binary.out", "ELF\x00\x01 connection refused"},
+	}
+	for _, f := range files {
+		require.NoError(t, idx.Add(f.name, strings.NewReader(f.content)))
+	}
+	return idx
+}
+
+func TestIndex_Search(t *testing.T) {
+	idx := testIndex(t)
+
+	assert.Equal(t, 3, idx.Files())
+
+	tests := []struct {
+		query string
+		want  []Hit
+	}{
+		{
+			query: "connection refused",
+			want: []Hit{
+				{File: "cluster-resources/pods/logs/kube-system/kube-proxy-x7d2q/kube-proxy.log", Line: 2},
+				{File: "cluster-resources/pods/logs/kube-system/kube-proxy-x7d2q/kube-proxy.log", Line: 3},
+			},
+		},
+		{
+			query: "REFUSED 10.96.0.1",
+			want:  []Hit{{File: "cluster-resources/pods/logs/kube-system/kube-proxy-x7d2q/kube-proxy.log", Line: 3}},
+		},
+		{
+			query: "proxy",
+			want:  []Hit{{File: "cluster-resources/pods/logs/kube-system/kube-proxy-x7d2q/kube-proxy.log", Line: 1}},
+		},
+		{
+			query: "failed",
+			want: []Hit{
+				{File: "cluster-resources/events/app.json", Line: 1},
+				{File: "cluster-resources/pods/logs/kube-system/kube-proxy-x7d2q/kube-proxy.log", Line: 2},
+			},
+		},
+		{
+			query: "too many connections",
+			want:  []Hit{{File: "postgres/postgres.json", Line: 3}},
+		},
+		{
+			query: "connection timeout",
+			want:  []Hit{},
+		},
+		{
+			query: "  :: ",
+			want:  []Hit{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			assert.Equal(t, tt.want, idx.Search(tt.query))
+		})
+	}
+}
+
+func TestIndex_SaveLoad(t *testing.T) {
+	idx := testIndex(t)
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, idx.Save(buf))
+
+	loaded, err := Load(buf)
+	require.NoError(t, err)
+	assert.Equal(t, idx.Files(), loaded.Files())
+	assert.Equal(t, idx.Terms(), loaded.Terms())
+	assert.Equal(t, idx.Search("connection refused"), loaded.Search("connection refused"))
+
+	// files added after loading continue the postings
+	require.NoError(t, loaded.Add("redis/info.txt", strings.NewReader("connection refused by replica\n")))
+	assert.Len(t, loaded.Search("connection refused"), 3)
+
+	_, err = Load(strings.NewReader("not an index"))
+	assert.Error(t, err)
+}
+
+func TestReadLines(t *testing.T) {
+	long := strings.Repeat("a", lineBufferSize+10)
+	content := "first\r\n\nthird\n" + long + "\nlast"
+
+	texts, err := ReadLines(strings.NewReader(content), []int{1, 2, 3, 4, 5, 6})
+	require.NoError(t, err)
+	assert.Equal(t, map[int]string{
+		1: "first",
+		2: "",
+		3: "third",
+		4: long[:maxLineLength],
+		5: "last",
+	}, texts)
+}
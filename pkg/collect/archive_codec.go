@@ -0,0 +1,217 @@
+package collect
+
+import (
+	"archive/tar"
+	"bufio"
+	"compress/gzip"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/pkg/errors"
+)
+
+const (
+	// StoreArchiveCodec stores files in the archive as they are
+	StoreArchiveCodec = "store"
+	// ZstdArchiveCodec compresses files with zstd, and is the codec of files no other codec is registered for
+	ZstdArchiveCodec = "zstd"
+
+	// archiveCodecPAXRecord is the PAX record of the codec of a file compressed in the archive, or
+	// of the file a symlink points to
+	archiveCodecPAXRecord = "TROUBLESHOOT.codec"
+	// archiveSizePAXRecord is the PAX record of the size of a file before it was compressed
+	archiveSizePAXRecord = "TROUBLESHOOT.size"
+)
+
+// ArchiveCodec compresses the files of a bundle archived with per-file compression. Compressed
+// files are named in the archive with the extension of their codec appended, so that they can also
+// be extracted with tar and decompressed with the matching tool.
+type ArchiveCodec interface {
+	Name() string
+	// Extension is appended to the names of the files compressed with the codec, e.g. ".zst". Files
+	// of codecs without an extension are stored as they are.
+	Extension() string
+	NewWriter(w io.Writer) (io.WriteCloser, error)
+	NewReader(r io.Reader) (io.ReadCloser, error)
+}
+
+var (
+	archiveCodecsMu sync.RWMutex
+	// archiveCodecs are the registered codecs, by name
+	archiveCodecs = map[string]ArchiveCodec{}
+	// archiveCodecSuffixes are the names of the codecs of files, by the suffix of their names
+	archiveCodecSuffixes = map[string]string{}
+)
+
+func init() {
+	RegisterArchiveCodec(zstdArchiveCodec{})
+	// files that are compressed already gain nothing from being compressed again
+	RegisterArchiveCodec(storeArchiveCodec{},
+		".gz", ".tgz", ".zst", ".zip", ".bz2", ".xz", ".lz4", ".pprof",
+		".png", ".jpg", ".jpeg", ".gif", ".webp",
+	)
+}
+
+// RegisterArchiveCodec registers a codec, replacing the codec of the same name if any, and makes
+// it the codec of files whose names end with one of the suffixes
+func RegisterArchiveCodec(codec ArchiveCodec, suffixes ...string) {
+	archiveCodecsMu.Lock()
+	defer archiveCodecsMu.Unlock()
+
+	archiveCodecs[codec.Name()] = codec
+	for _, suffix := range suffixes {
+		archiveCodecSuffixes[strings.ToLower(suffix)] = codec.Name()
+	}
+}
+
+// GetArchiveCodec returns the registered codec of a name
+func GetArchiveCodec(name string) (ArchiveCodec, bool) {
+	archiveCodecsMu.RLock()
+	defer archiveCodecsMu.RUnlock()
+
+	codec, ok := archiveCodecs[name]
+	return codec, ok
+}
+
+// ArchiveCodecForFile returns the codec of a file of the bundle: the codec registered for the
+// longest suffix its name ends with, or zstd
+func ArchiveCodecForFile(name string) ArchiveCodec {
+	archiveCodecsMu.RLock()
+	defer archiveCodecsMu.RUnlock()
+
+	name = strings.ToLower(name)
+	codecName, matched := ZstdArchiveCodec, ""
+	for suffix, codec := range archiveCodecSuffixes {
+		if strings.HasSuffix(name, suffix) && len(suffix) > len(matched) {
+			codecName, matched = codec, suffix
+		}
+	}
+	return archiveCodecs[codecName]
+}
+
+type storeArchiveCodec struct{}
+
+func (storeArchiveCodec) Name() string      { return StoreArchiveCodec }
+func (storeArchiveCodec) Extension() string { return "" }
+
+func (storeArchiveCodec) NewWriter(w io.Writer) (io.WriteCloser, error) {
+	return nopWriteCloser{w}, nil
+}
+
+func (storeArchiveCodec) NewReader(r io.Reader) (io.ReadCloser, error) {
+	return io.NopCloser(r), nil
+}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error { return nil }
+
+type zstdArchiveCodec struct{}
+
+func (zstdArchiveCodec) Name() string      { return ZstdArchiveCodec }
+func (zstdArchiveCodec) Extension() string { return ".zst" }
+
+func (zstdArchiveCodec) NewWriter(w io.Writer) (io.WriteCloser, error) {
+	return zstd.NewWriter(w)
+}
+
+func (zstdArchiveCodec) NewReader(r io.Reader) (io.ReadCloser, error) {
+	decoder, err := zstd.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	return decoder.IOReadCloser(), nil
+}
+
+// BundleArchiveReader reads the files of a bundle archive, either a tar.gz archive or a tar
+// archive with per-file compression, whose files it decompresses. Names are those of the files of
+// the bundle, without the extensions of their codecs.
+type BundleArchiveReader struct {
+	gzipReader *gzip.Reader
+	tarReader  *tar.Reader
+	file       io.ReadCloser
+}
+
+// NewBundleArchiveReader returns a reader of the bundle archive read from r
+func NewBundleArchiveReader(r io.Reader) (*BundleArchiveReader, error) {
+	buffered := bufio.NewReader(r)
+	magic, err := buffered.Peek(2)
+	if err != nil && err != io.EOF {
+		return nil, errors.Wrap(err, "failed to read archive")
+	}
+
+	reader := &BundleArchiveReader{}
+	if len(magic) == 2 && magic[0] == 0x1f && magic[1] == 0x8b {
+		reader.gzipReader, err = gzip.NewReader(buffered)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create gzip reader")
+		}
+		reader.tarReader = tar.NewReader(reader.gzipReader)
+	} else {
+		reader.tarReader = tar.NewReader(buffered)
+	}
+	return reader, nil
+}
+
+// Next advances to the next entry of the archive, like tar.Reader.Next. The size of a compressed
+// file is its size before it was compressed.
+func (r *BundleArchiveReader) Next() (*tar.Header, error) {
+	if r.file != nil {
+		r.file.Close()
+		r.file = nil
+	}
+
+	header, err := r.tarReader.Next()
+	if err != nil {
+		return nil, err
+	}
+
+	codecName := header.PAXRecords[archiveCodecPAXRecord]
+	if codecName == "" {
+		return header, nil
+	}
+	codec, ok := GetArchiveCodec(codecName)
+	if !ok {
+		return nil, errors.Errorf("unknown codec %q of %s", codecName, header.Name)
+	}
+
+	if header.Typeflag == tar.TypeSymlink {
+		header.Linkname = strings.TrimSuffix(header.Linkname, codec.Extension())
+		return header, nil
+	}
+
+	header.Name = strings.TrimSuffix(header.Name, codec.Extension())
+	if size, err := strconv.ParseInt(header.PAXRecords[archiveSizePAXRecord], 10, 64); err == nil {
+		header.Size = size
+	}
+	r.file, err = codec.NewReader(r.tarReader)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to decompress %s", header.Name)
+	}
+	return header, nil
+}
+
+// Read reads the current file of the archive
+func (r *BundleArchiveReader) Read(b []byte) (int, error) {
+	if r.file != nil {
+		return r.file.Read(b)
+	}
+	return r.tarReader.Read(b)
+}
+
+// Close releases the decompressors of the reader, but not the reader of the archive
+func (r *BundleArchiveReader) Close() error {
+	if r.file != nil {
+		r.file.Close()
+		r.file = nil
+	}
+	if r.gzipReader != nil {
+		return r.gzipReader.Close()
+	}
+	return nil
+}
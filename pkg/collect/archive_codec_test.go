@@ -0,0 +1,120 @@
+package collect
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArchiveCodecForFile(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{name: "cluster-resources/pods/logs/default/app/app.log", want: ZstdArchiveCodec},
+		{name: "cluster-resources/pods/default.json", want: ZstdArchiveCodec},
+		{name: "node-logs/kubelet.log.gz", want: StoreArchiveCodec},
+		{name: "pprof/app/default/app-0/heap.pb.GZ", want: StoreArchiveCodec},
+		{name: "screenshots/dashboard.png", want: StoreArchiveCodec},
+		{name: "execution-data/cpu.pprof", want: StoreArchiveCodec},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.want, ArchiveCodecForFile(test.name).Name())
+		})
+	}
+}
+
+// readArchive returns the contents of the regular files and the targets of the symlinks of an archive
+func readArchive(t *testing.T, archivePath string) (map[string]string, map[string]string) {
+	t.Helper()
+
+	f, err := os.Open(archivePath)
+	require.NoError(t, err)
+	defer f.Close()
+
+	reader, err := NewBundleArchiveReader(f)
+	require.NoError(t, err)
+	defer reader.Close()
+
+	files, links := map[string]string{}, map[string]string{}
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+
+		switch header.Typeflag {
+		case tar.TypeSymlink:
+			links[header.Name] = header.Linkname
+		case tar.TypeReg:
+			b, err := io.ReadAll(reader)
+			require.NoError(t, err)
+			assert.Equal(t, header.Size, int64(len(b)), header.Name)
+			files[header.Name] = string(b)
+		}
+	}
+	return files, links
+}
+
+func TestArchiveBundleWithCodecs(t *testing.T) {
+	bundlePath := filepath.Join(t.TempDir(), "support-bundle")
+	result := NewResult()
+
+	logs := strings.Repeat("I0101 00:00:00.000000 1 controller.go:42] reconciled\n", 1000)
+	require.NoError(t, result.SaveResult(bundlePath, "pods/logs/app.log", strings.NewReader(logs)))
+
+	gzipped := bytes.Buffer{}
+	gw := gzip.NewWriter(&gzipped)
+	_, err := gw.Write([]byte("rotated logs"))
+	require.NoError(t, err)
+	require.NoError(t, gw.Close())
+	require.NoError(t, result.SaveResult(bundlePath, "node-logs/kubelet.log.gz", bytes.NewReader(gzipped.Bytes())))
+
+	require.NoError(t, result.SymLinkResult(bundlePath, "current.log", "pods/logs/app.log"))
+
+	archivePath := filepath.Join(t.TempDir(), "support-bundle.tar")
+	require.NoError(t, result.ArchiveBundleWithCodecs(bundlePath, archivePath))
+
+	// the archive is a tar of the files, with the logs compressed with zstd
+	f, err := os.Open(archivePath)
+	require.NoError(t, err)
+	defer f.Close()
+	names := map[string]int64{}
+	tr := tar.NewReader(f)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		names[header.Name] = header.Size
+	}
+	require.Contains(t, names, "support-bundle/pods/logs/app.log.zst")
+	assert.Less(t, names["support-bundle/pods/logs/app.log.zst"], int64(len(logs)))
+	assert.Equal(t, int64(gzipped.Len()), names["support-bundle/node-logs/kubelet.log.gz"], "stored as it is")
+
+	files, links := readArchive(t, archivePath)
+	assert.Equal(t, map[string]string{
+		"support-bundle/pods/logs/app.log":        logs,
+		"support-bundle/node-logs/kubelet.log.gz": gzipped.String(),
+	}, files)
+	assert.Equal(t, map[string]string{"support-bundle/current.log": "pods/logs/app.log"}, links)
+
+	// tar.gz archives are read the same
+	archivePath = filepath.Join(t.TempDir(), "support-bundle.tar.gz")
+	require.NoError(t, result.ArchiveBundle(bundlePath, archivePath))
+	gzFiles, gzLinks := readArchive(t, archivePath)
+	assert.Equal(t, files, gzFiles)
+	assert.Equal(t, links, gzLinks)
+}
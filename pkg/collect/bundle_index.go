@@ -0,0 +1,192 @@
+package collect
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/constants"
+)
+
+// BundleIndexFile describes a file of the bundle. Collector and Name identify the collector
+// that produced the file, as in SkippedCollector, and are empty for files written by
+// troubleshoot itself (e.g. version.yaml). Error is the error the collector returned, if any,
+// in which case the file may be incomplete. Truncated files were cut to fit a maxSize budget
+// from their OriginalSize.
+type BundleIndexFile struct {
+	Path        string     `json:"path"`
+	Collector   string     `json:"collector,omitempty"`
+	Name        string     `json:"name,omitempty"`
+	Host        bool       `json:"host,omitempty"`
+	StartedAt   *time.Time `json:"startedAt,omitempty"`
+	CollectedAt *time.Time `json:"collectedAt,omitempty"`
+	Size        int64      `json:"size"`
+	SHA256      string     `json:"sha256"`
+	Error       string     `json:"error,omitempty"`
+
+	Truncated    bool  `json:"truncated,omitempty"`
+	OriginalSize int64 `json:"originalSize,omitempty"`
+}
+
+// BundleIndex lists the files of a bundle. Collectors are recorded as they run, and the sizes
+// and checksums are computed when the index is saved, after the results are redacted.
+type BundleIndex struct {
+	Files []BundleIndexFile `json:"files"`
+
+	sources   map[string]BundleIndexFile
+	truncated map[string]int64
+}
+
+// AddCollector records the files of an in-cluster collector
+func (i *BundleIndex) AddCollector(c Collector, result CollectorResult, startedAt time.Time, err error) {
+	kind, name, _ := getCollectorKind(c)
+	i.add(BundleIndexFile{Collector: kind, Name: name}, result, startedAt, err)
+}
+
+// AddHostCollector records the files of a host collector
+func (i *BundleIndex) AddHostCollector(spec *troubleshootv1beta2.HostCollect, result CollectorResult, startedAt time.Time, err error) {
+	kind, name := GetSpecKind(spec)
+	i.add(BundleIndexFile{Collector: kind, Name: name, Host: true}, result, startedAt, err)
+}
+
+// AddFiles records files produced outside of the collectors of the spec, such as the results of
+// remote host collectors, which run in a single pass, or of custom collectors.
+func (i *BundleIndex) AddFiles(collector string, host bool, result CollectorResult, startedAt time.Time, err error) {
+	i.add(BundleIndexFile{Collector: collector, Host: host}, result, startedAt, err)
+}
+
+// AddTruncated records files truncated to fit a maxSize budget. A file truncated more than once
+// keeps the size it was collected with.
+func (i *BundleIndex) AddTruncated(files []TruncatedFile) {
+	if i.truncated == nil {
+		i.truncated = map[string]int64{}
+	}
+	for _, file := range files {
+		if _, ok := i.truncated[file.Path]; !ok {
+			i.truncated[file.Path] = file.OriginalSize
+		}
+	}
+}
+
+func (i *BundleIndex) add(source BundleIndexFile, result CollectorResult, startedAt time.Time, err error) {
+	if i.sources == nil {
+		i.sources = map[string]BundleIndexFile{}
+	}
+
+	collectedAt := time.Now()
+	source.StartedAt = &startedAt
+	source.CollectedAt = &collectedAt
+	if err != nil {
+		source.Error = err.Error()
+	}
+
+	for path := range result {
+		i.sources[path] = source
+	}
+}
+
+// SaveResult computes the size and checksum of every file of output, and writes the index to the
+// bundle. Symlinks are listed with the size and checksum of the file they point to.
+func (i *BundleIndex) SaveResult(output CollectorResult, bundlePath string) error {
+	paths := make([]string, 0, len(output))
+	for path := range output {
+		if path != constants.BUNDLE_INDEX_FILENAME {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+
+	i.Files = make([]BundleIndexFile, 0, len(paths))
+	for _, path := range paths {
+		file := i.sources[path]
+		file.Path = path
+
+		size, checksum, isFile, err := checksumResult(output, bundlePath, path)
+		if err != nil {
+			return errors.Wrapf(err, "failed to checksum %s", path)
+		}
+		if !isFile {
+			continue
+		}
+		file.Size = size
+		file.SHA256 = checksum
+		if originalSize, ok := i.truncated[path]; ok {
+			file.Truncated = true
+			file.OriginalSize = originalSize
+		}
+
+		i.Files = append(i.Files, file)
+	}
+
+	b, err := json.MarshalIndent(i, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal bundle index")
+	}
+
+	return output.SaveResult(bundlePath, constants.BUNDLE_INDEX_FILENAME, bytes.NewBuffer(b))
+}
+
+// checksumResult returns the size and sha256 of a file of the result. isFile is false when the
+// path is a directory on disk, or a symlink to a file that does not exist.
+func checksumResult(output CollectorResult, bundlePath string, path string) (size int64, checksum string, isFile bool, err error) {
+	if bundlePath == "" || output[path] != nil {
+		sum := sha256.Sum256(output[path])
+		return int64(len(output[path])), hex.EncodeToString(sum[:]), true, nil
+	}
+
+	info, err := os.Stat(filepath.Join(bundlePath, path))
+	if os.IsNotExist(err) {
+		return 0, "", false, nil
+	} else if err != nil {
+		return 0, "", false, errors.Wrap(err, "failed to stat file")
+	}
+	if info.IsDir() {
+		return 0, "", false, nil
+	}
+
+	reader, err := output.GetReader(bundlePath, path)
+	if err != nil {
+		return 0, "", false, err
+	}
+	defer reader.Close()
+
+	hash := sha256.New()
+	size, err = io.Copy(hash, reader)
+	if err != nil {
+		return 0, "", false, errors.Wrap(err, "failed to read file")
+	}
+	return size, hex.EncodeToString(hash.Sum(nil)), true, nil
+}
+
+// ParseBundleIndex reads a bundle index written by BundleIndex.SaveResult
+func ParseBundleIndex(b []byte) (*BundleIndex, error) {
+	index := &BundleIndex{}
+	if err := json.Unmarshal(b, index); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal bundle index")
+	}
+	return index, nil
+}
+
+// FindFiles returns the paths of the files produced by collectors of kind collector. When name
+// is set, only files of the collector with that name are returned.
+func (i *BundleIndex) FindFiles(collector string, name string, host bool) []string {
+	paths := []string{}
+	for _, file := range i.Files {
+		if file.Collector != collector || file.Host != host {
+			continue
+		}
+		if name != "" && file.Name != name {
+			continue
+		}
+		paths = append(paths, file.Path)
+	}
+	return paths
+}
@@ -0,0 +1,104 @@
+package collect
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/constants"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func sha256Hex(data string) string {
+	sum := sha256.Sum256([]byte(data))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestBundleIndex_SaveResult(t *testing.T) {
+	bundlePath := t.TempDir()
+	output := NewResult()
+	startedAt := time.Now()
+
+	secrets := NewResult()
+	require.NoError(t, secrets.SaveResult(bundlePath, "secrets/app/db.json", strings.NewReader(`{"name":"db"}`)))
+	output.AddResult(secrets)
+
+	cpu := NewResult()
+	require.NoError(t, cpu.SaveResult(bundlePath, "host-collectors/system/cpu.json", strings.NewReader(`{}`)))
+	output.AddResult(cpu)
+
+	require.NoError(t, output.SaveResult(bundlePath, constants.VERSION_FILENAME, strings.NewReader("version")))
+	require.NoError(t, os.MkdirAll(filepath.Join(bundlePath, "empty"), 0777))
+	output["empty"] = nil
+
+	index := &BundleIndex{}
+	index.AddCollector(&CollectSecret{Collector: &troubleshootv1beta2.Secret{CollectorMeta: troubleshootv1beta2.CollectorMeta{CollectorName: "db"}}}, secrets, startedAt, nil)
+	index.AddHostCollector(&troubleshootv1beta2.HostCollect{CPU: &troubleshootv1beta2.CPU{}}, cpu, startedAt, errors.New("timeout"))
+	require.NoError(t, index.SaveResult(output, bundlePath))
+
+	b, err := os.ReadFile(filepath.Join(bundlePath, constants.BUNDLE_INDEX_FILENAME))
+	require.NoError(t, err)
+	saved, err := ParseBundleIndex(b)
+	require.NoError(t, err)
+
+	require.Len(t, saved.Files, 3)
+
+	cpuFile := saved.Files[0]
+	assert.Equal(t, "host-collectors/system/cpu.json", cpuFile.Path)
+	assert.Equal(t, "cpu", cpuFile.Collector)
+	assert.True(t, cpuFile.Host)
+	assert.Equal(t, "timeout", cpuFile.Error)
+	assert.Equal(t, int64(2), cpuFile.Size)
+	assert.Equal(t, sha256Hex("{}"), cpuFile.SHA256)
+	require.NotNil(t, cpuFile.StartedAt)
+	require.NotNil(t, cpuFile.CollectedAt)
+	assert.False(t, cpuFile.CollectedAt.Before(*cpuFile.StartedAt))
+
+	secretFile := saved.Files[1]
+	assert.Equal(t, "secrets/app/db.json", secretFile.Path)
+	assert.Equal(t, "secret", secretFile.Collector)
+	assert.Equal(t, "db", secretFile.Name)
+	assert.False(t, secretFile.Host)
+	assert.Empty(t, secretFile.Error)
+	assert.Equal(t, int64(len(`{"name":"db"}`)), secretFile.Size)
+	assert.Equal(t, sha256Hex(`{"name":"db"}`), secretFile.SHA256)
+
+	assert.Equal(t, BundleIndexFile{
+		Path:   constants.VERSION_FILENAME,
+		Size:   int64(len("version")),
+		SHA256: sha256Hex("version"),
+	}, saved.Files[2])
+
+	assert.Equal(t, []string{"secrets/app/db.json"}, saved.FindFiles("secret", "", false))
+	assert.Equal(t, []string{"secrets/app/db.json"}, saved.FindFiles("secret", "db", false))
+	assert.Empty(t, saved.FindFiles("secret", "tls", false))
+	assert.Equal(t, []string{"host-collectors/system/cpu.json"}, saved.FindFiles("cpu", "", true))
+	assert.Empty(t, saved.FindFiles("cpu", "", false))
+}
+
+func TestBundleIndex_SaveResultInMemory(t *testing.T) {
+	output := NewResult()
+	require.NoError(t, output.SaveResult("", "custom/report.txt", strings.NewReader("report")))
+
+	index := &BundleIndex{}
+	index.AddFiles("custom report", false, output, time.Now(), nil)
+	require.NoError(t, index.SaveResult(output, ""))
+
+	saved, err := ParseBundleIndex(output[constants.BUNDLE_INDEX_FILENAME])
+	require.NoError(t, err)
+	require.Len(t, saved.Files, 1)
+	assert.Equal(t, "custom report", saved.Files[0].Collector)
+	assert.Equal(t, int64(len("report")), saved.Files[0].Size)
+	assert.Equal(t, sha256Hex("report"), saved.Files[0].SHA256)
+
+	// saving again does not index the index itself
+	require.NoError(t, index.SaveResult(output, ""))
+	assert.Len(t, index.Files, 1)
+}
@@ -159,7 +159,7 @@ func (c *CollectCeph) IsExcluded() (bool, error) {
 }
 
 func (c *CollectCeph) Collect(progressChan chan<- interface{}) (CollectorResult, error) {
-	ctx := context.TODO()
+	ctx := collectorContext(c.Context)
 
 	if c.Collector.Namespace == "" {
 		c.Collector.Namespace = DefaultCephNamespace
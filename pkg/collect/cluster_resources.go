@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"path" // this code uses 'path' and not 'path/filepath' because we don't want backslashes on windows
 	"path/filepath"
+	"slices"
 	"sort"
 	"strings"
 
@@ -24,6 +25,7 @@ import (
 	apiextensionsv1beta1clientset "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/typed/apiextensions/v1beta1"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/version"
 	"k8s.io/client-go/dynamic"
@@ -52,6 +54,15 @@ func (c *CollectClusterResources) IsExcluded() (bool, error) {
 	return isExcluded(c.Collector.Exclude)
 }
 
+// shouldCollect returns whether the resource, named after its file or directory in
+// cluster-resources, is collected given the include and exclude lists of the collector.
+func (c *CollectClusterResources) shouldCollect(resource string) bool {
+	if len(c.Collector.IncludeResources) > 0 && !slices.Contains(c.Collector.IncludeResources, resource) {
+		return false
+	}
+	return !slices.Contains(c.Collector.ExcludeResources, resource)
+}
+
 func (c *CollectClusterResources) Merge(allCollectors []Collector) ([]Collector, error) {
 	var result []Collector
 	uniqueNamespaces := make(map[string]bool)
@@ -144,11 +155,13 @@ func (c *CollectClusterResources) Collect(progressChan chan<- interface{}) (Coll
 
 	// auth cani
 	klog.V(2).Infof("checking [%s] namespaces for permissions to collect resources", strings.Join(namespaceNames, ", "))
-	authCanI := authCanI(reviewStatuses, namespaceNames)
-	for k, v := range authCanI {
-		output.SaveResult(c.BundlePath, path.Join(constants.CLUSTER_RESOURCES_DIR, constants.CLUSTER_RESOURCES_AUTH_CANI, k), bytes.NewBuffer(v))
+	if c.shouldCollect(constants.CLUSTER_RESOURCES_AUTH_CANI) {
+		authCanI := authCanI(reviewStatuses, namespaceNames)
+		for k, v := range authCanI {
+			output.SaveResult(c.BundlePath, path.Join(constants.CLUSTER_RESOURCES_DIR, constants.CLUSTER_RESOURCES_AUTH_CANI, k), bytes.NewBuffer(v))
+		}
+		output.SaveResult(c.BundlePath, path.Join(constants.CLUSTER_RESOURCES_DIR, fmt.Sprintf("%s-errors.json", constants.CLUSTER_RESOURCES_AUTH_CANI)), marshalErrors(reviewStatusErrors))
 	}
-	output.SaveResult(c.BundlePath, path.Join(constants.CLUSTER_RESOURCES_DIR, fmt.Sprintf("%s-errors.json", constants.CLUSTER_RESOURCES_AUTH_CANI)), marshalErrors(reviewStatusErrors))
 
 	if nsListedFromCluster && !c.Collector.IgnoreRBAC {
 		filteredNamespaces := []string{}
@@ -166,240 +179,150 @@ func (c *CollectClusterResources) Collect(progressChan chan<- interface{}) (Coll
 		namespaceNames = filteredNamespaces
 	}
 
+	lister := resourceLister{
+		labelSelector: c.Collector.LabelSelector,
+		fieldSelector: c.Collector.FieldSelector,
+		pageSize:      c.Collector.PageSize,
+	}
+
 	// pods
-	pods, podErrors, unhealthyPods := pods(ctx, client, namespaceNames)
-	for k, v := range pods {
-		output.SaveResult(c.BundlePath, path.Join(constants.CLUSTER_RESOURCES_DIR, constants.CLUSTER_RESOURCES_PODS, k), bytes.NewBuffer(v))
-	}
-	output.SaveResult(c.BundlePath, path.Join(constants.CLUSTER_RESOURCES_DIR, fmt.Sprintf("%s-errors.json", constants.CLUSTER_RESOURCES_PODS)), marshalErrors(podErrors))
-
-	for _, pod := range unhealthyPods {
-		allContainers := append(pod.Spec.InitContainers, pod.Spec.Containers...)
-		for _, container := range allContainers {
-			limits := &troubleshootv1beta2.LogLimits{
-				MaxLines: 500,
-				// MaxBytes has been introduced to be able to limit the size of a pods logfile. This will in turn
-				// limit the total support bundle size as well as make sure the log(s) don't contain information
-				// that is too old/not relevant.
-				MaxBytes: 5000000,
+	if c.shouldCollect(constants.CLUSTER_RESOURCES_PODS) {
+		unhealthyPods := []corev1.Pod{}
+		podErrors := lister.saveNamespaced(ctx, output, c.BundlePath, client, podsResource, namespaceNames, func(obj runtime.Object) {
+			if pod, ok := obj.(*corev1.Pod); ok && k8sutil.IsPodUnhealthy(pod) {
+				unhealthyPods = append(unhealthyPods, *pod)
 			}
-			podLogs, err := savePodLogs(ctx, c.BundlePath, client, &pod, "", container.Name, limits, false, false, false)
-			if err != nil {
-				errPath := filepath.Join(constants.CLUSTER_RESOURCES_DIR, constants.CLUSTER_RESOURCES_PODS_LOGS, pod.Namespace, pod.Name, fmt.Sprintf("%s-logs-errors.log", container.Name))
-				output.SaveResult(c.BundlePath, errPath, bytes.NewBuffer([]byte(err.Error())))
+		})
+		output.SaveResult(c.BundlePath, path.Join(constants.CLUSTER_RESOURCES_DIR, fmt.Sprintf("%s-errors.json", constants.CLUSTER_RESOURCES_PODS)), marshalErrors(podErrors))
+
+		for _, pod := range unhealthyPods {
+			allContainers := append(pod.Spec.InitContainers, pod.Spec.Containers...)
+			for _, container := range allContainers {
+				limits := &troubleshootv1beta2.LogLimits{
+					MaxLines: 500,
+					// MaxBytes has been introduced to be able to limit the size of a pods logfile. This will in turn
+					// limit the total support bundle size as well as make sure the log(s) don't contain information
+					// that is too old/not relevant.
+					MaxBytes: 5000000,
+				}
+				podLogs, err := savePodLogs(ctx, c.BundlePath, client, &pod, "", container.Name, limits, false, false, false, nil)
+				if err != nil {
+					errPath := filepath.Join(constants.CLUSTER_RESOURCES_DIR, constants.CLUSTER_RESOURCES_PODS_LOGS, pod.Namespace, pod.Name, fmt.Sprintf("%s-logs-errors.log", container.Name))
+					output.SaveResult(c.BundlePath, errPath, bytes.NewBuffer([]byte(err.Error())))
+				}
+				// Add logs collector results to the rest of the output
+				output.AddResult(podLogs)
 			}
-			// Add logs collector results to the rest of the output
-			output.AddResult(podLogs)
 		}
 	}
 
-	// pod disruption budgets
-
-	PodDisruptionBudgets, pdbError := getPodDisruptionBudgets(ctx, client, namespaceNames)
-	for k, v := range PodDisruptionBudgets {
-		output.SaveResult(c.BundlePath, path.Join(constants.CLUSTER_RESOURCES_DIR, constants.CLUSTER_RESOURCES_POD_DISRUPTION_BUDGETS, k), bytes.NewBuffer(v))
-	}
-	output.SaveResult(c.BundlePath, path.Join(constants.CLUSTER_RESOURCES_DIR, fmt.Sprintf("%s-errors.json", constants.CLUSTER_RESOURCES_POD_DISRUPTION_BUDGETS)), marshalErrors(pdbError))
-
-	// services
-	services, servicesErrors := services(ctx, client, namespaceNames)
-	for k, v := range services {
-		output.SaveResult(c.BundlePath, path.Join(constants.CLUSTER_RESOURCES_DIR, constants.CLUSTER_RESOURCES_SERVICES, k), bytes.NewBuffer(v))
-	}
-	output.SaveResult(c.BundlePath, path.Join(constants.CLUSTER_RESOURCES_DIR, fmt.Sprintf("%s-errors.json", constants.CLUSTER_RESOURCES_SERVICES)), marshalErrors(servicesErrors))
-
-	// deployments
-	deployments, deploymentsErrors := deployments(ctx, client, namespaceNames)
-	for k, v := range deployments {
-		output.SaveResult(c.BundlePath, path.Join(constants.CLUSTER_RESOURCES_DIR, constants.CLUSTER_RESOURCES_DEPLOYMENTS, k), bytes.NewBuffer(v))
-	}
-	output.SaveResult(c.BundlePath, path.Join(constants.CLUSTER_RESOURCES_DIR, fmt.Sprintf("%s-errors.json", constants.CLUSTER_RESOURCES_DEPLOYMENTS)), marshalErrors(deploymentsErrors))
-
-	// statefulsets
-	statefulsets, statefulsetsErrors := statefulsets(ctx, client, namespaceNames)
-	for k, v := range statefulsets {
-		output.SaveResult(c.BundlePath, path.Join(constants.CLUSTER_RESOURCES_DIR, constants.CLUSTER_RESOURCES_STATEFULSETS, k), bytes.NewBuffer(v))
-	}
-	output.SaveResult(c.BundlePath, path.Join(constants.CLUSTER_RESOURCES_DIR, fmt.Sprintf("%s-errors.json", constants.CLUSTER_RESOURCES_STATEFULSETS)), marshalErrors(statefulsetsErrors))
-
-	// daemonsets
-	daemonsets, daemonsetsErrors := daemonsets(ctx, client, namespaceNames)
-	for k, v := range daemonsets {
-		output.SaveResult(c.BundlePath, path.Join(constants.CLUSTER_RESOURCES_DIR, constants.CLUSTER_RESOURCES_DAEMONSETS, k), bytes.NewBuffer(v))
-	}
-	output.SaveResult(c.BundlePath, path.Join(constants.CLUSTER_RESOURCES_DIR, fmt.Sprintf("%s-errors.json", constants.CLUSTER_RESOURCES_DAEMONSETS)), marshalErrors(daemonsetsErrors))
-
-	// replicasets
-	replicasets, replicasetsErrors := replicasets(ctx, client, namespaceNames)
-	for k, v := range replicasets {
-		output.SaveResult(c.BundlePath, path.Join(constants.CLUSTER_RESOURCES_DIR, fmt.Sprintf("%s-errors.json", constants.CLUSTER_RESOURCES_STATEFULSETS), k), bytes.NewBuffer(v))
-	}
-	output.SaveResult(c.BundlePath, path.Join(constants.CLUSTER_RESOURCES_DIR, fmt.Sprintf("%s-errors.json", constants.CLUSTER_RESOURCES_REPLICASETS)), marshalErrors(replicasetsErrors))
-
-	// jobs
-	jobs, jobsErrors := jobs(ctx, client, namespaceNames)
-	for k, v := range jobs {
-		output.SaveResult(c.BundlePath, path.Join(constants.CLUSTER_RESOURCES_DIR, constants.CLUSTER_RESOURCES_JOBS, k), bytes.NewBuffer(v))
-	}
-	output.SaveResult(c.BundlePath, path.Join(constants.CLUSTER_RESOURCES_DIR, fmt.Sprintf("%s-errors.json", constants.CLUSTER_RESOURCES_JOBS)), marshalErrors(jobsErrors))
-
-	// cronJobs
-	cronJobs, cronJobsErrors := cronJobs(ctx, client, namespaceNames)
-	for k, v := range cronJobs {
-		output.SaveResult(c.BundlePath, path.Join(constants.CLUSTER_RESOURCES_DIR, constants.CLUSTER_RESOURCES_CRONJOBS, k), bytes.NewBuffer(v))
-	}
-	output.SaveResult(c.BundlePath, path.Join(constants.CLUSTER_RESOURCES_DIR, fmt.Sprintf("%s-errors.json", constants.CLUSTER_RESOURCES_CRONJOBS)), marshalErrors(cronJobsErrors))
-
-	// ingress
-	ingress, ingressErrors := ingress(ctx, client, namespaceNames)
-	for k, v := range ingress {
-		output.SaveResult(c.BundlePath, path.Join(constants.CLUSTER_RESOURCES_DIR, constants.CLUSTER_RESOURCES_INGRESS, k), bytes.NewBuffer(v))
-	}
-	output.SaveResult(c.BundlePath, path.Join(constants.CLUSTER_RESOURCES_DIR, fmt.Sprintf("%s-errors.json", constants.CLUSTER_RESOURCES_INGRESS)), marshalErrors(ingressErrors))
-
-	// network policy
-	networkPolicy, networkPolicyErrors := networkPolicy(ctx, client, namespaceNames)
-	for k, v := range networkPolicy {
-		output.SaveResult(c.BundlePath, path.Join(constants.CLUSTER_RESOURCES_DIR, constants.CLUSTER_RESOURCES_NETWORK_POLICY, k), bytes.NewBuffer(v))
+	// services, deployments, events and the other namespaced resources
+	for _, resource := range namespacedResources {
+		if !c.shouldCollect(resource.name) {
+			continue
+		}
+		resourceErrors := lister.saveNamespaced(ctx, output, c.BundlePath, client, resource, namespaceNames, nil)
+		output.SaveResult(c.BundlePath, path.Join(constants.CLUSTER_RESOURCES_DIR, fmt.Sprintf("%s-errors.json", resource.name)), marshalErrors(resourceErrors))
 	}
-	output.SaveResult(c.BundlePath, path.Join(constants.CLUSTER_RESOURCES_DIR, fmt.Sprintf("%s-errors.json", constants.CLUSTER_RESOURCES_NETWORK_POLICY)), marshalErrors(networkPolicyErrors))
 
-	// resource quotas
-	resourceQuota, resourceQuotaErrors := resourceQuota(ctx, client, namespaceNames)
-	for k, v := range resourceQuota {
-		output.SaveResult(c.BundlePath, path.Join(constants.CLUSTER_RESOURCES_DIR, constants.CLUSTER_RESOURCES_RESOURCE_QUOTA, k), bytes.NewBuffer(v))
+	// pod disruption budgets, cronjobs and ingresses, at the version the cluster serves
+	for _, versioned := range versionedResources {
+		if !c.shouldCollect(versioned.resource.name) {
+			continue
+		}
+		var resourceErrors map[string]string
+		if resource, err := versioned.served(client); err != nil {
+			resourceErrors = map[string]string{"": err.Error()}
+		} else {
+			resourceErrors = lister.saveNamespaced(ctx, output, c.BundlePath, client, resource, namespaceNames, nil)
+		}
+		output.SaveResult(c.BundlePath, path.Join(constants.CLUSTER_RESOURCES_DIR, fmt.Sprintf("%s-errors.json", versioned.resource.name)), marshalErrors(resourceErrors))
 	}
-	output.SaveResult(c.BundlePath, path.Join(constants.CLUSTER_RESOURCES_DIR, fmt.Sprintf("%s-errors.json", constants.CLUSTER_RESOURCES_RESOURCE_QUOTA)), marshalErrors(resourceQuotaErrors))
 
 	// storage classes
-	storageClasses, storageErrors := storageClasses(ctx, client)
-	output.SaveResult(c.BundlePath, path.Join(constants.CLUSTER_RESOURCES_DIR, fmt.Sprintf("%s.json", constants.CLUSTER_RESOURCES_STORAGE_CLASS)), bytes.NewBuffer(storageClasses))
-	output.SaveResult(c.BundlePath, path.Join(constants.CLUSTER_RESOURCES_DIR, fmt.Sprintf("%s-errors.json", constants.CLUSTER_RESOURCES_STORAGE_CLASS)), marshalErrors(storageErrors))
+	if c.shouldCollect(constants.CLUSTER_RESOURCES_STORAGE_CLASS) {
+		storageClasses, storageErrors := storageClasses(ctx, client)
+		output.SaveResult(c.BundlePath, path.Join(constants.CLUSTER_RESOURCES_DIR, fmt.Sprintf("%s.json", constants.CLUSTER_RESOURCES_STORAGE_CLASS)), bytes.NewBuffer(storageClasses))
+		output.SaveResult(c.BundlePath, path.Join(constants.CLUSTER_RESOURCES_DIR, fmt.Sprintf("%s-errors.json", constants.CLUSTER_RESOURCES_STORAGE_CLASS)), marshalErrors(storageErrors))
+	}
 
 	// priority classes
-	priorityClasses, priorityErrors := priorityClasses(ctx, client)
-	output.SaveResult(c.BundlePath, path.Join(constants.CLUSTER_RESOURCES_DIR, fmt.Sprintf("%s.json", constants.CLUSTER_RESOURCES_PRIORITY_CLASS)), bytes.NewBuffer(priorityClasses))
-	output.SaveResult(c.BundlePath, path.Join(constants.CLUSTER_RESOURCES_DIR, fmt.Sprintf("%s-errors.json", constants.CLUSTER_RESOURCES_PRIORITY_CLASS)), marshalErrors(priorityErrors))
+	if c.shouldCollect(constants.CLUSTER_RESOURCES_PRIORITY_CLASS) {
+		priorityClasses, priorityErrors := priorityClasses(ctx, client)
+		output.SaveResult(c.BundlePath, path.Join(constants.CLUSTER_RESOURCES_DIR, fmt.Sprintf("%s.json", constants.CLUSTER_RESOURCES_PRIORITY_CLASS)), bytes.NewBuffer(priorityClasses))
+		output.SaveResult(c.BundlePath, path.Join(constants.CLUSTER_RESOURCES_DIR, fmt.Sprintf("%s-errors.json", constants.CLUSTER_RESOURCES_PRIORITY_CLASS)), marshalErrors(priorityErrors))
+	}
 
 	// crds
-	customResourceDefinitions, crdErrors := crds(ctx, client, c.ClientConfig)
-	output.SaveResult(c.BundlePath, path.Join(constants.CLUSTER_RESOURCES_DIR, fmt.Sprintf("%s.json", constants.CLUSTER_RESOURCES_CUSTOM_RESOURCE_DEFINITIONS)), bytes.NewBuffer(customResourceDefinitions))
-	output.SaveResult(c.BundlePath, path.Join(constants.CLUSTER_RESOURCES_DIR, fmt.Sprintf("%s-errors.json", constants.CLUSTER_RESOURCES_CUSTOM_RESOURCE_DEFINITIONS)), marshalErrors(crdErrors))
+	if c.shouldCollect(constants.CLUSTER_RESOURCES_CUSTOM_RESOURCE_DEFINITIONS) {
+		customResourceDefinitions, crdErrors := crds(ctx, client, c.ClientConfig)
+		output.SaveResult(c.BundlePath, path.Join(constants.CLUSTER_RESOURCES_DIR, fmt.Sprintf("%s.json", constants.CLUSTER_RESOURCES_CUSTOM_RESOURCE_DEFINITIONS)), bytes.NewBuffer(customResourceDefinitions))
+		output.SaveResult(c.BundlePath, path.Join(constants.CLUSTER_RESOURCES_DIR, fmt.Sprintf("%s-errors.json", constants.CLUSTER_RESOURCES_CUSTOM_RESOURCE_DEFINITIONS)), marshalErrors(crdErrors))
+	}
 
 	// crs
-	customResources, crErrors := crs(ctx, dynamicClient, client, c.ClientConfig, namespaceNames)
-	for k, v := range customResources {
-		output.SaveResult(c.BundlePath, path.Join(constants.CLUSTER_RESOURCES_DIR, constants.CLUSTER_RESOURCES_CUSTOM_RESOURCES, k), bytes.NewBuffer(v))
+	if c.shouldCollect(constants.CLUSTER_RESOURCES_CUSTOM_RESOURCES) {
+		customResources, crErrors := crs(ctx, dynamicClient, client, c.ClientConfig, namespaceNames, lister)
+		for k, v := range customResources {
+			output.SaveResult(c.BundlePath, path.Join(constants.CLUSTER_RESOURCES_DIR, constants.CLUSTER_RESOURCES_CUSTOM_RESOURCES, k), bytes.NewBuffer(v))
+		}
+		output.SaveResult(c.BundlePath, path.Join(constants.CLUSTER_RESOURCES_DIR, constants.CLUSTER_RESOURCES_CUSTOM_RESOURCES, fmt.Sprintf("%s-errors.json", constants.CLUSTER_RESOURCES_CUSTOM_RESOURCES)), marshalErrors(crErrors))
 	}
-	output.SaveResult(c.BundlePath, path.Join(constants.CLUSTER_RESOURCES_DIR, constants.CLUSTER_RESOURCES_CUSTOM_RESOURCES, fmt.Sprintf("%s-errors.json", constants.CLUSTER_RESOURCES_CUSTOM_RESOURCES)), marshalErrors(crErrors))
 
 	// imagepullsecrets
-	imagePullSecrets, pullSecretsErrors := imagePullSecrets(ctx, client, namespaceNames)
-	for k, v := range imagePullSecrets {
-		output.SaveResult(c.BundlePath, path.Join(constants.CLUSTER_RESOURCES_DIR, constants.CLUSTER_RESOURCES_IMAGE_PULL_SECRETS, k), bytes.NewBuffer(v))
+	if c.shouldCollect(constants.CLUSTER_RESOURCES_IMAGE_PULL_SECRETS) {
+		imagePullSecrets, pullSecretsErrors := imagePullSecrets(ctx, client, namespaceNames)
+		for k, v := range imagePullSecrets {
+			output.SaveResult(c.BundlePath, path.Join(constants.CLUSTER_RESOURCES_DIR, constants.CLUSTER_RESOURCES_IMAGE_PULL_SECRETS, k), bytes.NewBuffer(v))
+		}
+		output.SaveResult(c.BundlePath, path.Join(constants.CLUSTER_RESOURCES_DIR, fmt.Sprintf("%s-errors.json", constants.CLUSTER_RESOURCES_IMAGE_PULL_SECRETS)), marshalErrors(pullSecretsErrors))
 	}
-	output.SaveResult(c.BundlePath, path.Join(constants.CLUSTER_RESOURCES_DIR, fmt.Sprintf("%s-errors.json", constants.CLUSTER_RESOURCES_IMAGE_PULL_SECRETS)), marshalErrors(pullSecretsErrors))
 
 	// nodes
-	nodes, nodeErrors := nodes(ctx, client)
-	output.SaveResult(c.BundlePath, path.Join(constants.CLUSTER_RESOURCES_DIR, fmt.Sprintf("%s.json", constants.CLUSTER_RESOURCES_NODES)), bytes.NewBuffer(nodes))
-	output.SaveResult(c.BundlePath, path.Join(constants.CLUSTER_RESOURCES_DIR, fmt.Sprintf("%s-errors.json", constants.CLUSTER_RESOURCES_NODES)), marshalErrors(nodeErrors))
-
-	groups, resources, groupsResourcesErrors := apiResources(ctx, client)
-	output.SaveResult(c.BundlePath, path.Join(constants.CLUSTER_RESOURCES_DIR, fmt.Sprintf("%s.json", constants.CLUSTER_RESOURCES_GROUPS)), bytes.NewBuffer(groups))
-	output.SaveResult(c.BundlePath, path.Join(constants.CLUSTER_RESOURCES_DIR, fmt.Sprintf("%s.json", constants.CLUSTER_RESOURCES_RESOURCES)), bytes.NewBuffer(resources))
-	output.SaveResult(c.BundlePath, path.Join(constants.CLUSTER_RESOURCES_DIR, fmt.Sprintf("%s-%s-errors.json", constants.CLUSTER_RESOURCES_GROUPS, constants.CLUSTER_RESOURCES_RESOURCES)), marshalErrors(groupsResourcesErrors))
-
-	// limit ranges
-	limitRanges, limitRangesErrors := limitRanges(ctx, client, namespaceNames)
-	for k, v := range limitRanges {
-		output.SaveResult(c.BundlePath, path.Join(constants.CLUSTER_RESOURCES_DIR, constants.CLUSTER_RESOURCES_LIMITRANGES, k), bytes.NewBuffer(v))
+	if c.shouldCollect(constants.CLUSTER_RESOURCES_NODES) {
+		var nodeErrors []string
+		relativePath := path.Join(constants.CLUSTER_RESOURCES_DIR, fmt.Sprintf("%s.json", constants.CLUSTER_RESOURCES_NODES))
+		if err := lister.saveList(ctx, output, c.BundlePath, relativePath, lister.pageOptions(), listNodes(client), nil); err != nil {
+			nodeErrors = append(nodeErrors, err.Error())
+		}
+		output.SaveResult(c.BundlePath, path.Join(constants.CLUSTER_RESOURCES_DIR, fmt.Sprintf("%s-errors.json", constants.CLUSTER_RESOURCES_NODES)), marshalErrors(nodeErrors))
 	}
-	output.SaveResult(c.BundlePath, path.Join(constants.CLUSTER_RESOURCES_DIR, fmt.Sprintf("%s-errors.json", constants.CLUSTER_RESOURCES_LIMITRANGES)), marshalErrors(limitRangesErrors))
 
-	//Events
-	events, eventsErrors := events(ctx, client, namespaceNames)
-	for k, v := range events {
-		output.SaveResult(c.BundlePath, path.Join(constants.CLUSTER_RESOURCES_DIR, constants.CLUSTER_RESOURCES_EVENTS, k), bytes.NewBuffer(v))
+	if c.shouldCollect(constants.CLUSTER_RESOURCES_GROUPS) || c.shouldCollect(constants.CLUSTER_RESOURCES_RESOURCES) {
+		groups, resources, groupsResourcesErrors := apiResources(ctx, client)
+		output.SaveResult(c.BundlePath, path.Join(constants.CLUSTER_RESOURCES_DIR, fmt.Sprintf("%s.json", constants.CLUSTER_RESOURCES_GROUPS)), bytes.NewBuffer(groups))
+		output.SaveResult(c.BundlePath, path.Join(constants.CLUSTER_RESOURCES_DIR, fmt.Sprintf("%s.json", constants.CLUSTER_RESOURCES_RESOURCES)), bytes.NewBuffer(resources))
+		output.SaveResult(c.BundlePath, path.Join(constants.CLUSTER_RESOURCES_DIR, fmt.Sprintf("%s-%s-errors.json", constants.CLUSTER_RESOURCES_GROUPS, constants.CLUSTER_RESOURCES_RESOURCES)), marshalErrors(groupsResourcesErrors))
 	}
-	output.SaveResult(c.BundlePath, path.Join(constants.CLUSTER_RESOURCES_DIR, fmt.Sprintf("%s-errors.json", constants.CLUSTER_RESOURCES_EVENTS)), marshalErrors(eventsErrors))
 
 	//Persistent Volumes
-	pvs, pvsErrors := pvs(ctx, client)
-	output.SaveResult(c.BundlePath, path.Join(constants.CLUSTER_RESOURCES_DIR, fmt.Sprintf("%s.json", constants.CLUSTER_RESOURCES_PVS)), bytes.NewBuffer(pvs))
-	output.SaveResult(c.BundlePath, path.Join(constants.CLUSTER_RESOURCES_DIR, fmt.Sprintf("%s-errors.json", constants.CLUSTER_RESOURCES_PVS)), marshalErrors(pvsErrors))
-
-	//Persistent Volume Claims
-	pvcs, pvcsErrors := pvcs(ctx, client, namespaceNames)
-	for k, v := range pvcs {
-		output.SaveResult(c.BundlePath, path.Join(constants.CLUSTER_RESOURCES_DIR, constants.CLUSTER_RESOURCES_PVCS, k), bytes.NewBuffer(v))
-	}
-	output.SaveResult(c.BundlePath, path.Join(constants.CLUSTER_RESOURCES_DIR, fmt.Sprintf("%s-errors.json", constants.CLUSTER_RESOURCES_PVCS)), marshalErrors(pvcsErrors))
-
-	//Roles
-	roles, rolesErrors := roles(ctx, client, namespaceNames)
-	for k, v := range roles {
-		output.SaveResult(c.BundlePath, path.Join(constants.CLUSTER_RESOURCES_DIR, constants.CLUSTER_RESOURCES_ROLES, k), bytes.NewBuffer(v))
-	}
-	output.SaveResult(c.BundlePath, path.Join(constants.CLUSTER_RESOURCES_DIR, fmt.Sprintf("%s-errors.json", constants.CLUSTER_RESOURCES_ROLES)), marshalErrors(rolesErrors))
-
-	//Role Bindings
-	roleBindings, roleBindingsErrors := roleBindings(ctx, client, namespaceNames)
-	for k, v := range roleBindings {
-		output.SaveResult(c.BundlePath, path.Join(constants.CLUSTER_RESOURCES_DIR, constants.CLUSTER_RESOURCES_ROLE_BINDINGS, k), bytes.NewBuffer(v))
+	if c.shouldCollect(constants.CLUSTER_RESOURCES_PVS) {
+		pvs, pvsErrors := pvs(ctx, client)
+		output.SaveResult(c.BundlePath, path.Join(constants.CLUSTER_RESOURCES_DIR, fmt.Sprintf("%s.json", constants.CLUSTER_RESOURCES_PVS)), bytes.NewBuffer(pvs))
+		output.SaveResult(c.BundlePath, path.Join(constants.CLUSTER_RESOURCES_DIR, fmt.Sprintf("%s-errors.json", constants.CLUSTER_RESOURCES_PVS)), marshalErrors(pvsErrors))
 	}
-	output.SaveResult(c.BundlePath, path.Join(constants.CLUSTER_RESOURCES_DIR, fmt.Sprintf("%s-errors.json", constants.CLUSTER_RESOURCES_ROLE_BINDINGS)), marshalErrors(roleBindingsErrors))
 
 	//Cluster Roles
-	clusterRoles, clusterRolesErrors := clusterRoles(ctx, client)
-	output.SaveResult(c.BundlePath, path.Join(constants.CLUSTER_RESOURCES_DIR, fmt.Sprintf("%s.json", constants.CLUSTER_RESOURCES_CLUSTER_ROLES)), bytes.NewBuffer(clusterRoles))
-	output.SaveResult(c.BundlePath, path.Join(constants.CLUSTER_RESOURCES_DIR, fmt.Sprintf("%s-errors.json", constants.CLUSTER_RESOURCES_CLUSTER_ROLES)), marshalErrors(clusterRolesErrors))
-
-	//Cluster Role Bindings
-	clusterRoleBindings, clusterRoleBindingsErrors := clusterRoleBindings(ctx, client)
-	output.SaveResult(c.BundlePath, path.Join(constants.CLUSTER_RESOURCES_DIR, fmt.Sprintf("%s.json", constants.CLUSTER_RESOURCES_CLUSTER_ROLE_BINDINGS)), bytes.NewBuffer(clusterRoleBindings))
-	output.SaveResult(c.BundlePath, path.Join(constants.CLUSTER_RESOURCES_DIR, fmt.Sprintf("%s-errors.json", constants.CLUSTER_RESOURCES_CLUSTER_ROLE_BINDINGS)), marshalErrors(clusterRoleBindingsErrors))
-
-	// endpoints
-	endpoints, endpointsErrors := endpoints(ctx, client, namespaceNames)
-	for k, v := range endpoints {
-		output.SaveResult(c.BundlePath, path.Join(constants.CLUSTER_RESOURCES_DIR, constants.CLUSTER_RESOURCES_ENDPOINTS, k), bytes.NewBuffer(v))
+	if c.shouldCollect(constants.CLUSTER_RESOURCES_CLUSTER_ROLES) {
+		clusterRoles, clusterRolesErrors := clusterRoles(ctx, client)
+		output.SaveResult(c.BundlePath, path.Join(constants.CLUSTER_RESOURCES_DIR, fmt.Sprintf("%s.json", constants.CLUSTER_RESOURCES_CLUSTER_ROLES)), bytes.NewBuffer(clusterRoles))
+		output.SaveResult(c.BundlePath, path.Join(constants.CLUSTER_RESOURCES_DIR, fmt.Sprintf("%s-errors.json", constants.CLUSTER_RESOURCES_CLUSTER_ROLES)), marshalErrors(clusterRolesErrors))
 	}
-	output.SaveResult(c.BundlePath, path.Join(constants.CLUSTER_RESOURCES_DIR, fmt.Sprintf("%s-errors.json", constants.CLUSTER_RESOURCES_ENDPOINTS)), marshalErrors(endpointsErrors))
 
-	// endpointslices
-	endpointslices, endpointslicesErrors := endpointslices(ctx, client, namespaceNames)
-	for k, v := range endpointslices {
-		_ = output.SaveResult(c.BundlePath, path.Join(constants.CLUSTER_RESOURCES_DIR, constants.CLUSTER_RESOURCES_ENDPOINTSICES, k), bytes.NewBuffer(v))
-	}
-	_ = output.SaveResult(c.BundlePath, path.Join(constants.CLUSTER_RESOURCES_DIR, fmt.Sprintf("%s-errors.json", constants.CLUSTER_RESOURCES_ENDPOINTSICES)), marshalErrors(endpointslicesErrors))
-
-	// Service Accounts
-	servicesAccounts, servicesAccountsErrors := serviceAccounts(ctx, client, namespaceNames)
-	for k, v := range servicesAccounts {
-		output.SaveResult(c.BundlePath, path.Join(constants.CLUSTER_RESOURCES_DIR, constants.CLUSTER_RESOURCES_SERVICE_ACCOUNTS, k), bytes.NewBuffer(v))
-	}
-	output.SaveResult(c.BundlePath, path.Join(constants.CLUSTER_RESOURCES_DIR, fmt.Sprintf("%s-errors.json", constants.CLUSTER_RESOURCES_SERVICE_ACCOUNTS)), marshalErrors(servicesAccountsErrors))
-
-	// Leases
-	leases, leasesErrors := leases(ctx, client, namespaceNames)
-	for k, v := range leases {
-		output.SaveResult(c.BundlePath, path.Join(constants.CLUSTER_RESOURCES_DIR, constants.CLUSTER_RESOURCES_LEASES, k), bytes.NewBuffer(v))
+	//Cluster Role Bindings
+	if c.shouldCollect(constants.CLUSTER_RESOURCES_CLUSTER_ROLE_BINDINGS) {
+		clusterRoleBindings, clusterRoleBindingsErrors := clusterRoleBindings(ctx, client)
+		output.SaveResult(c.BundlePath, path.Join(constants.CLUSTER_RESOURCES_DIR, fmt.Sprintf("%s.json", constants.CLUSTER_RESOURCES_CLUSTER_ROLE_BINDINGS)), bytes.NewBuffer(clusterRoleBindings))
+		output.SaveResult(c.BundlePath, path.Join(constants.CLUSTER_RESOURCES_DIR, fmt.Sprintf("%s-errors.json", constants.CLUSTER_RESOURCES_CLUSTER_ROLE_BINDINGS)), marshalErrors(clusterRoleBindingsErrors))
 	}
-	output.SaveResult(c.BundlePath, path.Join(constants.CLUSTER_RESOURCES_DIR, fmt.Sprintf("%s-errors.json", constants.CLUSTER_RESOURCES_LEASES)), marshalErrors(leasesErrors))
 
 	// Volume Attachments
-	volumeAttachments, volumeAttachmentsErrors := volumeAttachments(ctx, client)
-	output.SaveResult(c.BundlePath, path.Join(constants.CLUSTER_RESOURCES_DIR, fmt.Sprintf("%s.json", constants.CLUSTER_RESOURCES_VOLUME_ATTACHMENTS)), bytes.NewBuffer(volumeAttachments))
-	output.SaveResult(c.BundlePath, path.Join(constants.CLUSTER_RESOURCES_DIR, fmt.Sprintf("%s-errors.json", constants.CLUSTER_RESOURCES_VOLUME_ATTACHMENTS)), marshalErrors(volumeAttachmentsErrors))
-
-	// ConfigMaps
-	configMaps, configMapsErrors := configMaps(ctx, client, namespaceNames)
-	for k, v := range configMaps {
-		output.SaveResult(c.BundlePath, path.Join(constants.CLUSTER_RESOURCES_DIR, constants.CLUSTER_RESOURCES_CONFIGMAPS, k), bytes.NewBuffer(v))
+	if c.shouldCollect(constants.CLUSTER_RESOURCES_VOLUME_ATTACHMENTS) {
+		volumeAttachments, volumeAttachmentsErrors := volumeAttachments(ctx, client)
+		output.SaveResult(c.BundlePath, path.Join(constants.CLUSTER_RESOURCES_DIR, fmt.Sprintf("%s.json", constants.CLUSTER_RESOURCES_VOLUME_ATTACHMENTS)), bytes.NewBuffer(volumeAttachments))
+		output.SaveResult(c.BundlePath, path.Join(constants.CLUSTER_RESOURCES_DIR, fmt.Sprintf("%s-errors.json", constants.CLUSTER_RESOURCES_VOLUME_ATTACHMENTS)), marshalErrors(volumeAttachmentsErrors))
 	}
 
-	output.SaveResult(c.BundlePath, path.Join(constants.CLUSTER_RESOURCES_DIR, fmt.Sprintf("%s-errors.json", constants.CLUSTER_RESOURCES_CONFIGMAPS)), marshalErrors(configMapsErrors))
 	return output, nil
 }
 
@@ -458,587 +381,16 @@ func getNamespace(ctx context.Context, client *kubernetes.Clientset, namespace s
 	}
 
 	gvk, err := apiutil.GVKForObject(ns, scheme.Scheme)
-	if err == nil {
-		ns.GetObjectKind().SetGroupVersionKind(gvk)
-	}
-
-	b, err := json.MarshalIndent(ns, "", "  ")
-	if err != nil {
-		return nil, []string{err.Error()}
-	}
-
-	return b, nil
-}
-
-func pods(ctx context.Context, client *kubernetes.Clientset, namespaces []string) (map[string][]byte, map[string]string, []corev1.Pod) {
-	podsByNamespace := make(map[string][]byte)
-	errorsByNamespace := make(map[string]string)
-	unhealthyPods := []corev1.Pod{}
-
-	for _, namespace := range namespaces {
-		pods, err := client.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
-		if err != nil {
-			errorsByNamespace[namespace] = err.Error()
-			continue
-		}
-
-		gvk, err := apiutil.GVKForObject(pods, scheme.Scheme)
-		if err == nil {
-			pods.GetObjectKind().SetGroupVersionKind(gvk)
-		}
-
-		for i, o := range pods.Items {
-			gvk, err := apiutil.GVKForObject(&o, scheme.Scheme)
-			if err == nil {
-				pods.Items[i].GetObjectKind().SetGroupVersionKind(gvk)
-			}
-		}
-
-		b, err := json.MarshalIndent(pods, "", "  ")
-		if err != nil {
-			errorsByNamespace[namespace] = err.Error()
-			continue
-		}
-
-		for _, pod := range pods.Items {
-			if k8sutil.IsPodUnhealthy(&pod) {
-				unhealthyPods = append(unhealthyPods, pod)
-			}
-		}
-
-		podsByNamespace[namespace+".json"] = b
-	}
-
-	return podsByNamespace, errorsByNamespace, unhealthyPods
-}
-
-func getPodDisruptionBudgets(ctx context.Context, client *kubernetes.Clientset, namespaces []string) (map[string][]byte, map[string]string) {
-	ok, err := discovery.HasResource(client, "policy/v1", "PodDisruptionBudgets")
-	if err != nil {
-		return nil, map[string]string{"": err.Error()}
-	}
-	if ok {
-		return pdbV1(ctx, client, namespaces)
-	}
-
-	return pdbV1beta(ctx, client, namespaces)
-}
-
-// TODO: The below function (`pdbV1`) needs to be DRY'd and moved into the main `getPodDisruptionBudgets` function.
-func pdbV1(ctx context.Context, client *kubernetes.Clientset, namespaces []string) (map[string][]byte, map[string]string) {
-	pdbByNamespace := make(map[string][]byte)
-	errorsByNamespace := make(map[string]string)
-
-	for _, namespace := range namespaces {
-		PodDisruptionBudgets, err := client.PolicyV1().PodDisruptionBudgets(namespace).List(ctx, metav1.ListOptions{})
-		if err != nil {
-			errorsByNamespace[namespace] = err.Error()
-			continue
-		}
-
-		gvk, err := apiutil.GVKForObject(PodDisruptionBudgets, scheme.Scheme)
-		if err == nil {
-			PodDisruptionBudgets.GetObjectKind().SetGroupVersionKind(gvk)
-		}
-
-		for i, o := range PodDisruptionBudgets.Items {
-			gvk, err := apiutil.GVKForObject(&o, scheme.Scheme)
-			if err == nil {
-				PodDisruptionBudgets.Items[i].GetObjectKind().SetGroupVersionKind(gvk)
-			}
-		}
-
-		b, err := json.MarshalIndent(PodDisruptionBudgets, "", "  ")
-		if err != nil {
-			errorsByNamespace[namespace] = err.Error()
-			continue
-		}
-
-		pdbByNamespace[namespace+".json"] = b
-	}
-
-	return pdbByNamespace, errorsByNamespace
-}
-
-// This block/function can remain as is
-func pdbV1beta(ctx context.Context, client *kubernetes.Clientset, namespaces []string) (map[string][]byte, map[string]string) {
-	pdbByNamespace := make(map[string][]byte)
-	errorsByNamespace := make(map[string]string)
-
-	for _, namespace := range namespaces {
-		PodDisruptionBudgets, err := client.PolicyV1beta1().PodDisruptionBudgets(namespace).List(ctx, metav1.ListOptions{})
-		if err != nil {
-			errorsByNamespace[namespace] = err.Error()
-			continue
-		}
-
-		gvk, err := apiutil.GVKForObject(PodDisruptionBudgets, scheme.Scheme)
-		if err == nil {
-			PodDisruptionBudgets.GetObjectKind().SetGroupVersionKind(gvk)
-		}
-
-		for i, o := range PodDisruptionBudgets.Items {
-			gvk, err := apiutil.GVKForObject(&o, scheme.Scheme)
-			if err == nil {
-				PodDisruptionBudgets.Items[i].GetObjectKind().SetGroupVersionKind(gvk)
-			}
-		}
-
-		b, err := json.MarshalIndent(PodDisruptionBudgets, "", "  ")
-		if err != nil {
-			errorsByNamespace[namespace] = err.Error()
-			continue
-		}
-
-		pdbByNamespace[namespace+".json"] = b
-	}
-
-	return pdbByNamespace, errorsByNamespace
-}
-
-func services(ctx context.Context, client *kubernetes.Clientset, namespaces []string) (map[string][]byte, map[string]string) {
-	servicesByNamespace := make(map[string][]byte)
-	errorsByNamespace := make(map[string]string)
-
-	for _, namespace := range namespaces {
-		services, err := client.CoreV1().Services(namespace).List(ctx, metav1.ListOptions{})
-		if err != nil {
-			errorsByNamespace[namespace] = err.Error()
-			continue
-		}
-
-		gvk, err := apiutil.GVKForObject(services, scheme.Scheme)
-		if err == nil {
-			services.GetObjectKind().SetGroupVersionKind(gvk)
-		}
-
-		for i, o := range services.Items {
-			gvk, err := apiutil.GVKForObject(&o, scheme.Scheme)
-			if err == nil {
-				services.Items[i].GetObjectKind().SetGroupVersionKind(gvk)
-			}
-		}
-
-		b, err := json.MarshalIndent(services, "", "  ")
-		if err != nil {
-			errorsByNamespace[namespace] = err.Error()
-			continue
-		}
-
-		servicesByNamespace[namespace+".json"] = b
-	}
-
-	return servicesByNamespace, errorsByNamespace
-}
-
-func deployments(ctx context.Context, client *kubernetes.Clientset, namespaces []string) (map[string][]byte, map[string]string) {
-	deploymentsByNamespace := make(map[string][]byte)
-	errorsByNamespace := make(map[string]string)
-
-	for _, namespace := range namespaces {
-		deployments, err := client.AppsV1().Deployments(namespace).List(ctx, metav1.ListOptions{})
-		if err != nil {
-			errorsByNamespace[namespace] = err.Error()
-			continue
-		}
-
-		gvk, err := apiutil.GVKForObject(deployments, scheme.Scheme)
-		if err == nil {
-			deployments.GetObjectKind().SetGroupVersionKind(gvk)
-		}
-
-		for i, o := range deployments.Items {
-			gvk, err := apiutil.GVKForObject(&o, scheme.Scheme)
-			if err == nil {
-				deployments.Items[i].GetObjectKind().SetGroupVersionKind(gvk)
-			}
-		}
-
-		b, err := json.MarshalIndent(deployments, "", "  ")
-		if err != nil {
-			errorsByNamespace[namespace] = err.Error()
-			continue
-		}
-
-		deploymentsByNamespace[namespace+".json"] = b
-	}
-
-	return deploymentsByNamespace, errorsByNamespace
-}
-
-func statefulsets(ctx context.Context, client *kubernetes.Clientset, namespaces []string) (map[string][]byte, map[string]string) {
-	statefulsetsByNamespace := make(map[string][]byte)
-	errorsByNamespace := make(map[string]string)
-
-	for _, namespace := range namespaces {
-		statefulsets, err := client.AppsV1().StatefulSets(namespace).List(ctx, metav1.ListOptions{})
-		if err != nil {
-			errorsByNamespace[namespace] = err.Error()
-			continue
-		}
-
-		gvk, err := apiutil.GVKForObject(statefulsets, scheme.Scheme)
-		if err == nil {
-			statefulsets.GetObjectKind().SetGroupVersionKind(gvk)
-		}
-
-		for i, o := range statefulsets.Items {
-			gvk, err := apiutil.GVKForObject(&o, scheme.Scheme)
-			if err == nil {
-				statefulsets.Items[i].GetObjectKind().SetGroupVersionKind(gvk)
-			}
-		}
-
-		b, err := json.MarshalIndent(statefulsets, "", "  ")
-		if err != nil {
-			errorsByNamespace[namespace] = err.Error()
-			continue
-		}
-
-		statefulsetsByNamespace[namespace+".json"] = b
-	}
-
-	return statefulsetsByNamespace, errorsByNamespace
-}
-
-func daemonsets(ctx context.Context, client *kubernetes.Clientset, namespaces []string) (map[string][]byte, map[string]string) {
-	daemonsetsByNamespace := make(map[string][]byte)
-	errorsByNamespace := make(map[string]string)
-
-	for _, namespace := range namespaces {
-		daemonsets, err := client.AppsV1().DaemonSets(namespace).List(ctx, metav1.ListOptions{})
-
-		if err != nil {
-			errorsByNamespace[namespace] = err.Error()
-			continue
-		}
-
-		gvk, err := apiutil.GVKForObject(daemonsets, scheme.Scheme)
-		if err == nil {
-			daemonsets.GetObjectKind().SetGroupVersionKind(gvk)
-		}
-
-		for i, o := range daemonsets.Items {
-			gvk, err := apiutil.GVKForObject(&o, scheme.Scheme)
-			if err == nil {
-				daemonsets.Items[i].GetObjectKind().SetGroupVersionKind(gvk)
-			}
-		}
-
-		b, err := json.MarshalIndent(daemonsets, "", "  ")
-		if err != nil {
-			errorsByNamespace[namespace] = err.Error()
-			continue
-		}
-
-		daemonsetsByNamespace[namespace+".json"] = b
-	}
-
-	return daemonsetsByNamespace, errorsByNamespace
-}
-
-func replicasets(ctx context.Context, client *kubernetes.Clientset, namespaces []string) (map[string][]byte, map[string]string) {
-	replicasetsByNamespace := make(map[string][]byte)
-	errorsByNamespace := make(map[string]string)
-
-	for _, namespace := range namespaces {
-		replicasets, err := client.AppsV1().ReplicaSets(namespace).List(ctx, metav1.ListOptions{})
-		if err != nil {
-			errorsByNamespace[namespace] = err.Error()
-			continue
-		}
-
-		gvk, err := apiutil.GVKForObject(replicasets, scheme.Scheme)
-		if err == nil {
-			replicasets.GetObjectKind().SetGroupVersionKind(gvk)
-		}
-
-		for i, o := range replicasets.Items {
-			gvk, err := apiutil.GVKForObject(&o, scheme.Scheme)
-			if err == nil {
-				replicasets.Items[i].GetObjectKind().SetGroupVersionKind(gvk)
-			}
-		}
-
-		b, err := json.MarshalIndent(replicasets, "", "  ")
-		if err != nil {
-			errorsByNamespace[namespace] = err.Error()
-			continue
-		}
-
-		replicasetsByNamespace[namespace+".json"] = b
-	}
-
-	return replicasetsByNamespace, errorsByNamespace
-}
-
-func jobs(ctx context.Context, client *kubernetes.Clientset, namespaces []string) (map[string][]byte, map[string]string) {
-	jobsByNamespace := make(map[string][]byte)
-	errorsByNamespace := make(map[string]string)
-
-	for _, namespace := range namespaces {
-		nsJobs, err := client.BatchV1().Jobs(namespace).List(ctx, metav1.ListOptions{})
-		if err != nil {
-			errorsByNamespace[namespace] = err.Error()
-			continue
-		}
-
-		gvk, err := apiutil.GVKForObject(nsJobs, scheme.Scheme)
-		if err == nil {
-			nsJobs.GetObjectKind().SetGroupVersionKind(gvk)
-		}
-
-		for i, o := range nsJobs.Items {
-			gvk, err := apiutil.GVKForObject(&o, scheme.Scheme)
-			if err == nil {
-				nsJobs.Items[i].GetObjectKind().SetGroupVersionKind(gvk)
-			}
-		}
-
-		b, err := json.MarshalIndent(nsJobs, "", "  ")
-		if err != nil {
-			errorsByNamespace[namespace] = err.Error()
-			continue
-		}
-
-		jobsByNamespace[namespace+".json"] = b
-	}
-
-	return jobsByNamespace, errorsByNamespace
-}
-
-func cronJobs(ctx context.Context, client *kubernetes.Clientset, namespaces []string) (map[string][]byte, map[string]string) {
-	ok, err := discovery.HasResource(client, "batch/v1", "CronJob")
-	if err != nil {
-		return nil, map[string]string{"": err.Error()}
-	}
-	if ok {
-		return cronJobsV1(ctx, client, namespaces)
-	}
-
-	return cronJobsV1beta(ctx, client, namespaces)
-}
-
-func cronJobsV1(ctx context.Context, client *kubernetes.Clientset, namespaces []string) (map[string][]byte, map[string]string) {
-	cronJobsByNamespace := make(map[string][]byte)
-	errorsByNamespace := make(map[string]string)
-
-	for _, namespace := range namespaces {
-		cronJobs, err := client.BatchV1().CronJobs(namespace).List(ctx, metav1.ListOptions{})
-		if err != nil {
-			errorsByNamespace[namespace] = err.Error()
-			continue
-		}
-
-		gvk, err := apiutil.GVKForObject(cronJobs, scheme.Scheme)
-		if err == nil {
-			cronJobs.GetObjectKind().SetGroupVersionKind(gvk)
-		}
-
-		for i, o := range cronJobs.Items {
-			gvk, err := apiutil.GVKForObject(&o, scheme.Scheme)
-			if err == nil {
-				cronJobs.Items[i].GetObjectKind().SetGroupVersionKind(gvk)
-			}
-		}
-
-		b, err := json.MarshalIndent(cronJobs, "", "  ")
-		if err != nil {
-			errorsByNamespace[namespace] = err.Error()
-			continue
-		}
-
-		cronJobsByNamespace[namespace+".json"] = b
-	}
-
-	return cronJobsByNamespace, errorsByNamespace
-}
-
-func cronJobsV1beta(ctx context.Context, client *kubernetes.Clientset, namespaces []string) (map[string][]byte, map[string]string) {
-	cronJobsByNamespace := make(map[string][]byte)
-	errorsByNamespace := make(map[string]string)
-
-	for _, namespace := range namespaces {
-		cronJobs, err := client.BatchV1beta1().CronJobs(namespace).List(ctx, metav1.ListOptions{})
-		if err != nil {
-			errorsByNamespace[namespace] = err.Error()
-			continue
-		}
-
-		gvk, err := apiutil.GVKForObject(cronJobs, scheme.Scheme)
-		if err == nil {
-			cronJobs.GetObjectKind().SetGroupVersionKind(gvk)
-		}
-
-		for i, o := range cronJobs.Items {
-			gvk, err := apiutil.GVKForObject(&o, scheme.Scheme)
-			if err == nil {
-				cronJobs.Items[i].GetObjectKind().SetGroupVersionKind(gvk)
-			}
-		}
-
-		b, err := json.MarshalIndent(cronJobs, "", "  ")
-		if err != nil {
-			errorsByNamespace[namespace] = err.Error()
-			continue
-		}
-
-		cronJobsByNamespace[namespace+".json"] = b
-	}
-
-	return cronJobsByNamespace, errorsByNamespace
-}
-
-func ingress(ctx context.Context, client *kubernetes.Clientset, namespaces []string) (map[string][]byte, map[string]string) {
-	ok, err := discovery.HasResource(client, "networking.k8s.io/v1", "Ingress")
-	if err != nil {
-		return nil, map[string]string{"": err.Error()}
-	}
-	if ok {
-		return ingressV1(ctx, client, namespaces)
-	}
-
-	return ingressV1beta(ctx, client, namespaces)
-}
-
-func ingressV1(ctx context.Context, client *kubernetes.Clientset, namespaces []string) (map[string][]byte, map[string]string) {
-	ingressByNamespace := make(map[string][]byte)
-	errorsByNamespace := make(map[string]string)
-
-	for _, namespace := range namespaces {
-		ingress, err := client.NetworkingV1().Ingresses(namespace).List(ctx, metav1.ListOptions{})
-		if err != nil {
-			errorsByNamespace[namespace] = err.Error()
-			continue
-		}
-
-		gvk, err := apiutil.GVKForObject(ingress, scheme.Scheme)
-		if err == nil {
-			ingress.GetObjectKind().SetGroupVersionKind(gvk)
-		}
-
-		for i, o := range ingress.Items {
-			gvk, err := apiutil.GVKForObject(&o, scheme.Scheme)
-			if err == nil {
-				ingress.Items[i].GetObjectKind().SetGroupVersionKind(gvk)
-			}
-		}
-
-		b, err := json.MarshalIndent(ingress, "", "  ")
-		if err != nil {
-			errorsByNamespace[namespace] = err.Error()
-			continue
-		}
-
-		ingressByNamespace[namespace+".json"] = b
-	}
-
-	return ingressByNamespace, errorsByNamespace
-}
-
-func ingressV1beta(ctx context.Context, client *kubernetes.Clientset, namespaces []string) (map[string][]byte, map[string]string) {
-	ingressByNamespace := make(map[string][]byte)
-	errorsByNamespace := make(map[string]string)
-
-	for _, namespace := range namespaces {
-		ingress, err := client.ExtensionsV1beta1().Ingresses(namespace).List(ctx, metav1.ListOptions{})
-		if err != nil {
-			errorsByNamespace[namespace] = err.Error()
-			continue
-		}
-
-		gvk, err := apiutil.GVKForObject(ingress, scheme.Scheme)
-		if err == nil {
-			ingress.GetObjectKind().SetGroupVersionKind(gvk)
-		}
-
-		for i, o := range ingress.Items {
-			gvk, err := apiutil.GVKForObject(&o, scheme.Scheme)
-			if err == nil {
-				ingress.Items[i].GetObjectKind().SetGroupVersionKind(gvk)
-			}
-		}
-
-		b, err := json.MarshalIndent(ingress, "", "  ")
-		if err != nil {
-			errorsByNamespace[namespace] = err.Error()
-			continue
-		}
-
-		ingressByNamespace[namespace+".json"] = b
-	}
-
-	return ingressByNamespace, errorsByNamespace
-}
-
-func networkPolicy(ctx context.Context, client *kubernetes.Clientset, namespaces []string) (map[string][]byte, map[string]string) {
-	networkPolicyByNamespace := make(map[string][]byte)
-	errorsByNamespace := make(map[string]string)
-
-	for _, namespace := range namespaces {
-		networkPolicy, err := client.NetworkingV1().NetworkPolicies(namespace).List(ctx, metav1.ListOptions{})
-		if err != nil {
-			errorsByNamespace[namespace] = err.Error()
-			continue
-		}
-
-		gvk, err := apiutil.GVKForObject(networkPolicy, scheme.Scheme)
-		if err == nil {
-			networkPolicy.GetObjectKind().SetGroupVersionKind(gvk)
-		}
-
-		for i, o := range networkPolicy.Items {
-			gvk, err := apiutil.GVKForObject(&o, scheme.Scheme)
-			if err == nil {
-				networkPolicy.Items[i].GetObjectKind().SetGroupVersionKind(gvk)
-			}
-		}
-
-		b, err := json.MarshalIndent(networkPolicy, "", "  ")
-		if err != nil {
-			errorsByNamespace[namespace] = err.Error()
-			continue
-		}
-
-		networkPolicyByNamespace[namespace+".json"] = b
-	}
-
-	return networkPolicyByNamespace, errorsByNamespace
-}
-
-func resourceQuota(ctx context.Context, client *kubernetes.Clientset, namespaces []string) (map[string][]byte, map[string]string) {
-	resourceQuotaByNamespace := make(map[string][]byte)
-	errorsByNamespace := make(map[string]string)
-
-	for _, namespace := range namespaces {
-		resourceQuota, err := client.CoreV1().ResourceQuotas(namespace).List(ctx, metav1.ListOptions{})
-		if err != nil {
-			errorsByNamespace[namespace] = err.Error()
-			continue
-		}
-
-		gvk, err := apiutil.GVKForObject(resourceQuota, scheme.Scheme)
-		if err == nil {
-			resourceQuota.GetObjectKind().SetGroupVersionKind(gvk)
-		}
-
-		for i, o := range resourceQuota.Items {
-			gvk, err := apiutil.GVKForObject(&o, scheme.Scheme)
-			if err == nil {
-				resourceQuota.Items[i].GetObjectKind().SetGroupVersionKind(gvk)
-			}
-		}
-
-		b, err := json.MarshalIndent(resourceQuota, "", "  ")
-		if err != nil {
-			errorsByNamespace[namespace] = err.Error()
-			continue
-		}
+	if err == nil {
+		ns.GetObjectKind().SetGroupVersionKind(gvk)
+	}
 
-		resourceQuotaByNamespace[namespace+".json"] = b
+	b, err := json.MarshalIndent(ns, "", "  ")
+	if err != nil {
+		return nil, []string{err.Error()}
 	}
 
-	return resourceQuotaByNamespace, errorsByNamespace
+	return b, nil
 }
 
 func storageClasses(ctx context.Context, client *kubernetes.Clientset) ([]byte, []string) {
@@ -1243,7 +595,7 @@ func crdsV1beta(ctx context.Context, config *rest.Config) ([]byte, []string) {
 	return b, nil
 }
 
-func crs(ctx context.Context, dyn dynamic.Interface, client *kubernetes.Clientset, config *rest.Config, namespaces []string) (map[string][]byte, map[string]string) {
+func crs(ctx context.Context, dyn dynamic.Interface, client *kubernetes.Clientset, config *rest.Config, namespaces []string, lister resourceLister) (map[string][]byte, map[string]string) {
 	errorList := make(map[string]string)
 	ok, err := discovery.HasResource(client, "apiextensions.k8s.io/v1", "CustomResourceDefinition")
 	if err != nil {
@@ -1255,7 +607,7 @@ func crs(ctx context.Context, dyn dynamic.Interface, client *kubernetes.Clientse
 			errorList["crdClient"] = err.Error()
 			return map[string][]byte{}, errorList
 		}
-		return crsV1(ctx, dyn, crdClient, namespaces)
+		return crsV1(ctx, dyn, crdClient, namespaces, lister)
 	}
 
 	crdClient, err := apiextensionsv1beta1clientset.NewForConfig(config)
@@ -1263,7 +615,7 @@ func crs(ctx context.Context, dyn dynamic.Interface, client *kubernetes.Clientse
 		errorList["crdClient"] = err.Error()
 		return map[string][]byte{}, errorList
 	}
-	return crsV1beta(ctx, dyn, crdClient, namespaces)
+	return crsV1beta(ctx, dyn, crdClient, namespaces, lister)
 }
 
 // Selects the newest version by kube-aware priority.
@@ -1283,6 +635,7 @@ func crsV1(
 	client dynamic.Interface,
 	crdClient apiextensionsv1clientset.ApiextensionsV1Interface,
 	namespaces []string,
+	lister resourceLister,
 ) (map[string][]byte, map[string]string) {
 	customResources := make(map[string][]byte)
 	errorList := make(map[string]string)
@@ -1323,19 +676,19 @@ func crsV1(
 		isNamespacedResource := crd.Spec.Scope == apiextensionsv1.NamespaceScoped
 
 		// Fetch all resources of given type
-		customResourceList, err := client.Resource(gvr).List(ctx, metav1.ListOptions{})
+		items, err := lister.listCustomResources(ctx, client.Resource(gvr))
 		if err != nil {
 			errorList[crd.Name] = err.Error()
 			continue
 		}
 
-		if len(customResourceList.Items) == 0 {
+		if len(items) == 0 {
 			continue
 		}
 
 		if !isNamespacedResource {
 			objects := []map[string]interface{}{}
-			for _, item := range customResourceList.Items {
+			for _, item := range items {
 				objects = append(objects, item.Object)
 			}
 			err := storeCustomResource(crd.Name, objects, customResources)
@@ -1348,7 +701,7 @@ func crsV1(
 			perNamespace := map[string][]map[string]interface{}{}
 			errors := []string{}
 
-			for _, item := range customResourceList.Items {
+			for _, item := range items {
 				ns, err := metaAccessor.Namespace(&item)
 				if err != nil {
 					errors = append(errors, err.Error())
@@ -1388,6 +741,7 @@ func crsV1beta(
 	client dynamic.Interface,
 	crdClient apiextensionsv1beta1clientset.ApiextensionsV1beta1Interface,
 	namespaces []string,
+	lister resourceLister,
 ) (map[string][]byte, map[string]string) {
 	customResources := make(map[string][]byte)
 	errorList := make(map[string]string)
@@ -1430,19 +784,19 @@ func crsV1beta(
 		isNamespacedResource := crd.Spec.Scope == apiextensionsv1beta1.NamespaceScoped
 
 		// Fetch all resources of given type
-		customResourceList, err := client.Resource(gvr).List(ctx, metav1.ListOptions{})
+		items, err := lister.listCustomResources(ctx, client.Resource(gvr))
 		if err != nil {
 			errorList[crd.Name] = err.Error()
 			continue
 		}
 
-		if len(customResourceList.Items) == 0 {
+		if len(items) == 0 {
 			continue
 		}
 
 		if !isNamespacedResource {
 			objects := []map[string]interface{}{}
-			for _, item := range customResourceList.Items {
+			for _, item := range items {
 				objects = append(objects, item.Object)
 			}
 
@@ -1457,7 +811,7 @@ func crsV1beta(
 			perNamespace := map[string][]map[string]interface{}{}
 			errors := []string{}
 
-			for _, item := range customResourceList.Items {
+			for _, item := range items {
 				ns, err := metaAccessor.Namespace(&item)
 				if err != nil {
 					errors = append(errors, err.Error())
@@ -1544,67 +898,6 @@ func imagePullSecrets(ctx context.Context, client *kubernetes.Clientset, namespa
 	return imagePullSecrets, errors
 }
 
-func limitRanges(ctx context.Context, client *kubernetes.Clientset, namespaces []string) (map[string][]byte, map[string]string) {
-	limitRangesByNamespace := make(map[string][]byte)
-	errorsByNamespace := make(map[string]string)
-
-	for _, namespace := range namespaces {
-		limitRanges, err := client.CoreV1().LimitRanges(namespace).List(ctx, metav1.ListOptions{})
-		if err != nil {
-			errorsByNamespace[namespace] = err.Error()
-			continue
-		}
-
-		gvk, err := apiutil.GVKForObject(limitRanges, scheme.Scheme)
-		if err == nil {
-			limitRanges.GetObjectKind().SetGroupVersionKind(gvk)
-		}
-
-		for i, o := range limitRanges.Items {
-			gvk, err := apiutil.GVKForObject(&o, scheme.Scheme)
-			if err == nil {
-				limitRanges.Items[i].GetObjectKind().SetGroupVersionKind(gvk)
-			}
-		}
-
-		b, err := json.MarshalIndent(limitRanges, "", "  ")
-		if err != nil {
-			errorsByNamespace[namespace] = err.Error()
-			continue
-		}
-
-		limitRangesByNamespace[namespace+".json"] = b
-	}
-
-	return limitRangesByNamespace, errorsByNamespace
-}
-
-func nodes(ctx context.Context, client *kubernetes.Clientset) ([]byte, []string) {
-	nodes, err := client.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
-	if err != nil {
-		return nil, []string{err.Error()}
-	}
-
-	gvk, err := apiutil.GVKForObject(nodes, scheme.Scheme)
-	if err == nil {
-		nodes.GetObjectKind().SetGroupVersionKind(gvk)
-	}
-
-	for i, o := range nodes.Items {
-		gvk, err := apiutil.GVKForObject(&o, scheme.Scheme)
-		if err == nil {
-			nodes.Items[i].GetObjectKind().SetGroupVersionKind(gvk)
-		}
-	}
-
-	b, err := json.MarshalIndent(nodes, "", "  ")
-	if err != nil {
-		return nil, []string{err.Error()}
-	}
-
-	return b, nil
-}
-
 // get the list of API resources, similar to 'kubectl api-resources'
 func apiResources(ctx context.Context, client *kubernetes.Clientset) ([]byte, []byte, []string) {
 	var errorArray []string
@@ -1673,41 +966,6 @@ func authCanI(accessStatuses map[string]*authorizationv1.SubjectRulesReviewStatu
 	return authListByNamespace
 }
 
-func events(ctx context.Context, client *kubernetes.Clientset, namespaces []string) (map[string][]byte, map[string]string) {
-	eventsByNamespace := make(map[string][]byte)
-	errorsByNamespace := make(map[string]string)
-
-	for _, namespace := range namespaces {
-		events, err := client.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{})
-		if err != nil {
-			errorsByNamespace[namespace] = err.Error()
-			continue
-		}
-
-		gvk, err := apiutil.GVKForObject(events, scheme.Scheme)
-		if err == nil {
-			events.GetObjectKind().SetGroupVersionKind(gvk)
-		}
-
-		for i, o := range events.Items {
-			gvk, err := apiutil.GVKForObject(&o, scheme.Scheme)
-			if err == nil {
-				events.Items[i].GetObjectKind().SetGroupVersionKind(gvk)
-			}
-		}
-
-		b, err := json.MarshalIndent(events, "", "  ")
-		if err != nil {
-			errorsByNamespace[namespace] = err.Error()
-			continue
-		}
-
-		eventsByNamespace[namespace+".json"] = b
-	}
-
-	return eventsByNamespace, errorsByNamespace
-}
-
 func canCollectNamespaceResources(status *authorizationv1.SubjectRulesReviewStatus) bool {
 	// This is all very approximate
 
@@ -1800,111 +1058,6 @@ func pvs(ctx context.Context, client *kubernetes.Clientset) ([]byte, []string) {
 	return b, nil
 }
 
-func pvcs(ctx context.Context, client *kubernetes.Clientset, namespaces []string) (map[string][]byte, map[string]string) {
-	pvcsByNamespace := make(map[string][]byte)
-	errorsByNamespace := make(map[string]string)
-
-	for _, namespace := range namespaces {
-		pvcs, err := client.CoreV1().PersistentVolumeClaims(namespace).List(ctx, metav1.ListOptions{})
-		if err != nil {
-			errorsByNamespace[namespace] = err.Error()
-			continue
-		}
-
-		gvk, err := apiutil.GVKForObject(pvcs, scheme.Scheme)
-		if err == nil {
-			pvcs.GetObjectKind().SetGroupVersionKind(gvk)
-		}
-
-		for i, o := range pvcs.Items {
-			gvk, err := apiutil.GVKForObject(&o, scheme.Scheme)
-			if err == nil {
-				pvcs.Items[i].GetObjectKind().SetGroupVersionKind(gvk)
-			}
-		}
-
-		b, err := json.MarshalIndent(pvcs, "", "  ")
-		if err != nil {
-			errorsByNamespace[namespace] = err.Error()
-			continue
-		}
-
-		pvcsByNamespace[namespace+".json"] = b
-	}
-
-	return pvcsByNamespace, errorsByNamespace
-}
-
-func roles(ctx context.Context, client *kubernetes.Clientset, namespaces []string) (map[string][]byte, map[string]string) {
-	rolesByNamespace := make(map[string][]byte)
-	errorsByNamespace := make(map[string]string)
-
-	for _, namespace := range namespaces {
-		roles, err := client.RbacV1().Roles(namespace).List(ctx, metav1.ListOptions{})
-		if err != nil {
-			errorsByNamespace[namespace] = err.Error()
-			continue
-		}
-
-		gvk, err := apiutil.GVKForObject(roles, scheme.Scheme)
-		if err == nil {
-			roles.GetObjectKind().SetGroupVersionKind(gvk)
-		}
-
-		for i, o := range roles.Items {
-			gvk, err := apiutil.GVKForObject(&o, scheme.Scheme)
-			if err == nil {
-				roles.Items[i].GetObjectKind().SetGroupVersionKind(gvk)
-			}
-		}
-
-		b, err := json.MarshalIndent(roles, "", "  ")
-		if err != nil {
-			errorsByNamespace[namespace] = err.Error()
-			continue
-		}
-
-		rolesByNamespace[namespace+".json"] = b
-	}
-
-	return rolesByNamespace, errorsByNamespace
-}
-
-func roleBindings(ctx context.Context, client *kubernetes.Clientset, namespaces []string) (map[string][]byte, map[string]string) {
-	roleBindingsByNamespace := make(map[string][]byte)
-	errorsByNamespace := make(map[string]string)
-
-	for _, namespace := range namespaces {
-		roleBindings, err := client.RbacV1().RoleBindings(namespace).List(ctx, metav1.ListOptions{})
-		if err != nil {
-			errorsByNamespace[namespace] = err.Error()
-			continue
-		}
-
-		gvk, err := apiutil.GVKForObject(roleBindings, scheme.Scheme)
-		if err == nil {
-			roleBindings.GetObjectKind().SetGroupVersionKind(gvk)
-		}
-
-		for i, o := range roleBindings.Items {
-			gvk, err := apiutil.GVKForObject(&o, scheme.Scheme)
-			if err == nil {
-				roleBindings.Items[i].GetObjectKind().SetGroupVersionKind(gvk)
-			}
-		}
-
-		b, err := json.MarshalIndent(roleBindings, "", "  ")
-		if err != nil {
-			errorsByNamespace[namespace] = err.Error()
-			continue
-		}
-
-		roleBindingsByNamespace[namespace+".json"] = b
-	}
-
-	return roleBindingsByNamespace, errorsByNamespace
-}
-
 func clusterRoles(ctx context.Context, client *kubernetes.Clientset) ([]byte, []string) {
 	clusterRoles, err := client.RbacV1().ClusterRoles().List(ctx, metav1.ListOptions{})
 	if err != nil {
@@ -1955,147 +1108,6 @@ func clusterRoleBindings(ctx context.Context, client *kubernetes.Clientset) ([]b
 	return b, nil
 }
 
-func endpoints(ctx context.Context, client *kubernetes.Clientset, namespaces []string) (map[string][]byte, map[string]string) {
-	endpointsByNamespace := make(map[string][]byte)
-	errorsByNamespace := make(map[string]string)
-
-	for _, namespace := range namespaces {
-		endpoints, err := client.CoreV1().Endpoints(namespace).List(ctx, metav1.ListOptions{})
-		if err != nil {
-			errorsByNamespace[namespace] = err.Error()
-			continue
-		}
-
-		gvk, err := apiutil.GVKForObject(endpoints, scheme.Scheme)
-		if err == nil {
-			endpoints.GetObjectKind().SetGroupVersionKind(gvk)
-		}
-
-		for i, o := range endpoints.Items {
-			gvk, err := apiutil.GVKForObject(&o, scheme.Scheme)
-			if err == nil {
-				endpoints.Items[i].GetObjectKind().SetGroupVersionKind(gvk)
-			}
-		}
-
-		b, err := json.MarshalIndent(endpoints, "", "  ")
-		if err != nil {
-			errorsByNamespace[namespace] = err.Error()
-			continue
-		}
-
-		endpointsByNamespace[namespace+".json"] = b
-	}
-
-	return endpointsByNamespace, errorsByNamespace
-}
-
-func endpointslices(ctx context.Context, client *kubernetes.Clientset, namespaces []string) (map[string][]byte, map[string]string) {
-	objsByNamespace := make(map[string][]byte)
-	errorsByNamespace := make(map[string]string)
-
-	for _, namespace := range namespaces {
-		objs, err := client.DiscoveryV1().EndpointSlices(namespace).List(ctx, metav1.ListOptions{})
-		if err != nil {
-			errorsByNamespace[namespace] = err.Error()
-			continue
-		}
-
-		// TODO: Can we DRY this? We repeat this pattern a lot
-		gvk, err := apiutil.GVKForObject(objs, scheme.Scheme)
-		if err == nil {
-			objs.GetObjectKind().SetGroupVersionKind(gvk)
-		}
-
-		for i, o := range objs.Items {
-			gvk, err := apiutil.GVKForObject(&o, scheme.Scheme)
-			if err == nil {
-				objs.Items[i].GetObjectKind().SetGroupVersionKind(gvk)
-			}
-		}
-
-		b, err := json.MarshalIndent(objs, "", "  ")
-		if err != nil {
-			errorsByNamespace[namespace] = err.Error()
-			continue
-		}
-
-		objsByNamespace[namespace+".json"] = b
-	}
-
-	return objsByNamespace, errorsByNamespace
-}
-
-func serviceAccounts(ctx context.Context, client kubernetes.Interface, namespaces []string) (map[string][]byte, map[string]string) {
-	serviceAccountsByNamespace := make(map[string][]byte)
-	errorsByNamespace := make(map[string]string)
-
-	for _, namespace := range namespaces {
-		serviceAccounts, err := client.CoreV1().ServiceAccounts(namespace).List(ctx, metav1.ListOptions{})
-		if err != nil {
-			errorsByNamespace[namespace] = err.Error()
-			continue
-		}
-
-		gvk, err := apiutil.GVKForObject(serviceAccounts, scheme.Scheme)
-		if err == nil {
-			serviceAccounts.GetObjectKind().SetGroupVersionKind(gvk)
-		}
-
-		for i, o := range serviceAccounts.Items {
-			gvk, err := apiutil.GVKForObject(&o, scheme.Scheme)
-			if err == nil {
-				serviceAccounts.Items[i].GetObjectKind().SetGroupVersionKind(gvk)
-			}
-		}
-
-		b, err := json.MarshalIndent(serviceAccounts, "", "  ")
-		if err != nil {
-			errorsByNamespace[namespace] = err.Error()
-			continue
-		}
-
-		serviceAccountsByNamespace[namespace+".json"] = b
-	}
-
-	return serviceAccountsByNamespace, errorsByNamespace
-}
-
-func leases(ctx context.Context, client kubernetes.Interface, namespaces []string) (map[string][]byte, map[string]string) {
-	leasesByNamespace := make(map[string][]byte)
-	errorsByNamespace := make(map[string]string)
-
-	for _, namespace := range namespaces {
-		leases, err := client.CoordinationV1().Leases(namespace).List(ctx, metav1.ListOptions{})
-		if err != nil {
-			errorsByNamespace[namespace] = err.Error()
-			continue
-		}
-
-		gvk, err := apiutil.GVKForObject(leases, scheme.Scheme)
-		if err == nil {
-			leases.GetObjectKind().SetGroupVersionKind(gvk)
-		}
-
-		for i, o := range leases.Items {
-			gvk, err := apiutil.GVKForObject(&o, scheme.Scheme)
-			if err == nil {
-				leases.Items[i].GetObjectKind().SetGroupVersionKind(gvk)
-			}
-		}
-
-		b, err := json.MarshalIndent(leases, "", "  ")
-		if err != nil {
-			errorsByNamespace[namespace] = err.Error()
-			continue
-		}
-
-		leasesByNamespace[namespace+".json"] = b
-	}
-
-	return leasesByNamespace, errorsByNamespace
-}
-
 func volumeAttachments(ctx context.Context, client kubernetes.Interface) ([]byte, []string) {
 	volumeAttachments, err := client.StorageV1().VolumeAttachments().List(ctx, metav1.ListOptions{})
 	if err != nil {
@@ -2122,41 +1134,6 @@ func volumeAttachments(ctx context.Context, client kubernetes.Interface) ([]byte
 	return b, nil
 }
 
-func configMaps(ctx context.Context, client kubernetes.Interface, namespaces []string) (map[string][]byte, map[string]string) {
-	configmapByNamespace := make(map[string][]byte)
-	errorsByNamespace := make(map[string]string)
-
-	for _, namespace := range namespaces {
-		configmaps, err := client.CoreV1().ConfigMaps(namespace).List(ctx, metav1.ListOptions{})
-		if err != nil {
-			errorsByNamespace[namespace] = err.Error()
-			continue
-		}
-
-		gvk, err := apiutil.GVKForObject(configmaps, scheme.Scheme)
-		if err == nil {
-			configmaps.GetObjectKind().SetGroupVersionKind(gvk)
-		}
-
-		for i, o := range configmaps.Items {
-			gvk, err := apiutil.GVKForObject(&o, scheme.Scheme)
-			if err == nil {
-				configmaps.Items[i].GetObjectKind().SetGroupVersionKind(gvk)
-			}
-		}
-
-		b, err := json.MarshalIndent(configmaps, "", "  ")
-		if err != nil {
-			errorsByNamespace[namespace] = err.Error()
-			continue
-		}
-
-		configmapByNamespace[namespace+".json"] = b
-	}
-
-	return configmapByNamespace, errorsByNamespace
-}
-
 // storeCustomResource stores a custom resource as JSON and YAML
 // We use both formats for backwards compatibility. This way we
 // avoid breaking existing tools and analysers that already rely on
@@ -0,0 +1,418 @@
+package collect
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"path"
+	"slices"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/troubleshoot/pkg/constants"
+	"github.com/replicatedhq/troubleshoot/pkg/k8sutil/discovery"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+const defaultClusterResourcesPageSize = 500
+
+type listFunc func(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error)
+
+// namespacedResource is a kind of namespaced object whose objects are saved to
+// cluster-resources/<name>/<namespace>.json.
+type namespacedResource struct {
+	name string
+	list func(client kubernetes.Interface, namespace string) listFunc
+}
+
+var (
+	podsResource = namespacedResource{constants.CLUSTER_RESOURCES_PODS, func(client kubernetes.Interface, namespace string) listFunc {
+		return func(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error) {
+			return client.CoreV1().Pods(namespace).List(ctx, opts)
+		}
+	}}
+	servicesResource = namespacedResource{constants.CLUSTER_RESOURCES_SERVICES, func(client kubernetes.Interface, namespace string) listFunc {
+		return func(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error) {
+			return client.CoreV1().Services(namespace).List(ctx, opts)
+		}
+	}}
+	deploymentsResource = namespacedResource{constants.CLUSTER_RESOURCES_DEPLOYMENTS, func(client kubernetes.Interface, namespace string) listFunc {
+		return func(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error) {
+			return client.AppsV1().Deployments(namespace).List(ctx, opts)
+		}
+	}}
+	statefulsetsResource = namespacedResource{constants.CLUSTER_RESOURCES_STATEFULSETS, func(client kubernetes.Interface, namespace string) listFunc {
+		return func(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error) {
+			return client.AppsV1().StatefulSets(namespace).List(ctx, opts)
+		}
+	}}
+	daemonsetsResource = namespacedResource{constants.CLUSTER_RESOURCES_DAEMONSETS, func(client kubernetes.Interface, namespace string) listFunc {
+		return func(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error) {
+			return client.AppsV1().DaemonSets(namespace).List(ctx, opts)
+		}
+	}}
+	replicasetsResource = namespacedResource{constants.CLUSTER_RESOURCES_REPLICASETS, func(client kubernetes.Interface, namespace string) listFunc {
+		return func(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error) {
+			return client.AppsV1().ReplicaSets(namespace).List(ctx, opts)
+		}
+	}}
+	jobsResource = namespacedResource{constants.CLUSTER_RESOURCES_JOBS, func(client kubernetes.Interface, namespace string) listFunc {
+		return func(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error) {
+			return client.BatchV1().Jobs(namespace).List(ctx, opts)
+		}
+	}}
+	networkPolicyResource = namespacedResource{constants.CLUSTER_RESOURCES_NETWORK_POLICY, func(client kubernetes.Interface, namespace string) listFunc {
+		return func(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error) {
+			return client.NetworkingV1().NetworkPolicies(namespace).List(ctx, opts)
+		}
+	}}
+	resourceQuotaResource = namespacedResource{constants.CLUSTER_RESOURCES_RESOURCE_QUOTA, func(client kubernetes.Interface, namespace string) listFunc {
+		return func(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error) {
+			return client.CoreV1().ResourceQuotas(namespace).List(ctx, opts)
+		}
+	}}
+	limitRangesResource = namespacedResource{constants.CLUSTER_RESOURCES_LIMITRANGES, func(client kubernetes.Interface, namespace string) listFunc {
+		return func(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error) {
+			return client.CoreV1().LimitRanges(namespace).List(ctx, opts)
+		}
+	}}
+	eventsResource = namespacedResource{constants.CLUSTER_RESOURCES_EVENTS, func(client kubernetes.Interface, namespace string) listFunc {
+		return func(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error) {
+			return client.CoreV1().Events(namespace).List(ctx, opts)
+		}
+	}}
+	pvcsResource = namespacedResource{constants.CLUSTER_RESOURCES_PVCS, func(client kubernetes.Interface, namespace string) listFunc {
+		return func(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error) {
+			return client.CoreV1().PersistentVolumeClaims(namespace).List(ctx, opts)
+		}
+	}}
+	rolesResource = namespacedResource{constants.CLUSTER_RESOURCES_ROLES, func(client kubernetes.Interface, namespace string) listFunc {
+		return func(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error) {
+			return client.RbacV1().Roles(namespace).List(ctx, opts)
+		}
+	}}
+	roleBindingsResource = namespacedResource{constants.CLUSTER_RESOURCES_ROLE_BINDINGS, func(client kubernetes.Interface, namespace string) listFunc {
+		return func(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error) {
+			return client.RbacV1().RoleBindings(namespace).List(ctx, opts)
+		}
+	}}
+	endpointsResource = namespacedResource{constants.CLUSTER_RESOURCES_ENDPOINTS, func(client kubernetes.Interface, namespace string) listFunc {
+		return func(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error) {
+			return client.CoreV1().Endpoints(namespace).List(ctx, opts)
+		}
+	}}
+	endpointSlicesResource = namespacedResource{constants.CLUSTER_RESOURCES_ENDPOINTSICES, func(client kubernetes.Interface, namespace string) listFunc {
+		return func(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error) {
+			return client.DiscoveryV1().EndpointSlices(namespace).List(ctx, opts)
+		}
+	}}
+	serviceAccountsResource = namespacedResource{constants.CLUSTER_RESOURCES_SERVICE_ACCOUNTS, func(client kubernetes.Interface, namespace string) listFunc {
+		return func(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error) {
+			return client.CoreV1().ServiceAccounts(namespace).List(ctx, opts)
+		}
+	}}
+	leasesResource = namespacedResource{constants.CLUSTER_RESOURCES_LEASES, func(client kubernetes.Interface, namespace string) listFunc {
+		return func(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error) {
+			return client.CoordinationV1().Leases(namespace).List(ctx, opts)
+		}
+	}}
+	configMapsResource = namespacedResource{constants.CLUSTER_RESOURCES_CONFIGMAPS, func(client kubernetes.Interface, namespace string) listFunc {
+		return func(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error) {
+			return client.CoreV1().ConfigMaps(namespace).List(ctx, opts)
+		}
+	}}
+	pdbsResource = namespacedResource{constants.CLUSTER_RESOURCES_POD_DISRUPTION_BUDGETS, func(client kubernetes.Interface, namespace string) listFunc {
+		return func(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error) {
+			return client.PolicyV1().PodDisruptionBudgets(namespace).List(ctx, opts)
+		}
+	}}
+	pdbsV1beta1Resource = namespacedResource{constants.CLUSTER_RESOURCES_POD_DISRUPTION_BUDGETS, func(client kubernetes.Interface, namespace string) listFunc {
+		return func(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error) {
+			return client.PolicyV1beta1().PodDisruptionBudgets(namespace).List(ctx, opts)
+		}
+	}}
+	cronJobsResource = namespacedResource{constants.CLUSTER_RESOURCES_CRONJOBS, func(client kubernetes.Interface, namespace string) listFunc {
+		return func(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error) {
+			return client.BatchV1().CronJobs(namespace).List(ctx, opts)
+		}
+	}}
+	cronJobsV1beta1Resource = namespacedResource{constants.CLUSTER_RESOURCES_CRONJOBS, func(client kubernetes.Interface, namespace string) listFunc {
+		return func(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error) {
+			return client.BatchV1beta1().CronJobs(namespace).List(ctx, opts)
+		}
+	}}
+	ingressResource = namespacedResource{constants.CLUSTER_RESOURCES_INGRESS, func(client kubernetes.Interface, namespace string) listFunc {
+		return func(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error) {
+			return client.NetworkingV1().Ingresses(namespace).List(ctx, opts)
+		}
+	}}
+	ingressV1beta1Resource = namespacedResource{constants.CLUSTER_RESOURCES_INGRESS, func(client kubernetes.Interface, namespace string) listFunc {
+		return func(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error) {
+			return client.ExtensionsV1beta1().Ingresses(namespace).List(ctx, opts)
+		}
+	}}
+)
+
+// namespacedResources are collected from each namespace after pods, which are collected
+// first to get the logs of unhealthy pods.
+var namespacedResources = []namespacedResource{
+	servicesResource,
+	deploymentsResource,
+	statefulsetsResource,
+	daemonsetsResource,
+	replicasetsResource,
+	jobsResource,
+	networkPolicyResource,
+	resourceQuotaResource,
+	limitRangesResource,
+	eventsResource,
+	pvcsResource,
+	rolesResource,
+	roleBindingsResource,
+	endpointsResource,
+	endpointSlicesResource,
+	serviceAccountsResource,
+	leasesResource,
+	configMapsResource,
+}
+
+// versionedResource is a namespaced resource listed at apiVersion when the cluster serves its
+// kind there, and at the older version of fallback otherwise.
+type versionedResource struct {
+	apiVersion string
+	kind       string
+	resource   namespacedResource
+	fallback   namespacedResource
+}
+
+// versionedResources are collected from each namespace after namespacedResources
+var versionedResources = []versionedResource{
+	{"policy/v1", "PodDisruptionBudget", pdbsResource, pdbsV1beta1Resource},
+	{"batch/v1", "CronJob", cronJobsResource, cronJobsV1beta1Resource},
+	{"networking.k8s.io/v1", "Ingress", ingressResource, ingressV1beta1Resource},
+}
+
+func (r versionedResource) served(client kubernetes.Interface) (namespacedResource, error) {
+	ok, err := discovery.HasResource(client.Discovery(), r.apiVersion, r.kind)
+	if err != nil {
+		return namespacedResource{}, err
+	}
+	if ok {
+		return r.resource, nil
+	}
+	return r.fallback, nil
+}
+
+func listNodes(client kubernetes.Interface) listFunc {
+	return func(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error) {
+		return client.CoreV1().Nodes().List(ctx, opts)
+	}
+}
+
+// resourceFieldSelectors are the fields that objects of each resource can be selected by, besides
+// metadata.name and metadata.namespace, which all resources support. The API server rejects the
+// lists of a resource selecting other fields.
+var resourceFieldSelectors = map[string][]string{
+	constants.CLUSTER_RESOURCES_PODS: {
+		"spec.nodeName", "spec.restartPolicy", "spec.schedulerName", "spec.serviceAccountName",
+		"spec.hostNetwork", "status.phase", "status.podIP", "status.podIPs", "status.nominatedNodeName",
+	},
+	constants.CLUSTER_RESOURCES_EVENTS: {
+		"involvedObject.kind", "involvedObject.namespace", "involvedObject.name", "involvedObject.uid",
+		"involvedObject.apiVersion", "involvedObject.resourceVersion", "involvedObject.fieldPath",
+		"reason", "reportingComponent", "source", "type",
+	},
+	constants.CLUSTER_RESOURCES_REPLICASETS: {"status.replicas"},
+	constants.CLUSTER_RESOURCES_JOBS:        {"status.successful"},
+}
+
+// scopeFieldSelector returns the requirements of a field selector that the resource supports, so
+// that a selector such as status.phase=Running filters pods without failing the other lists. An
+// invalid selector is returned as it is, for the API server to report.
+func scopeFieldSelector(fieldSelector string, resource string) string {
+	selector, err := fields.ParseSelector(fieldSelector)
+	if err != nil {
+		return fieldSelector
+	}
+
+	scoped := []fields.Selector{}
+	for _, requirement := range selector.Requirements() {
+		if requirement.Field != "metadata.name" && requirement.Field != "metadata.namespace" &&
+			!slices.Contains(resourceFieldSelectors[resource], requirement.Field) {
+			continue
+		}
+		if requirement.Operator == selection.NotEquals {
+			scoped = append(scoped, fields.OneTermNotEqualSelector(requirement.Field, requirement.Value))
+		} else {
+			scoped = append(scoped, fields.OneTermEqualSelector(requirement.Field, requirement.Value))
+		}
+	}
+	return fields.AndSelectors(scoped...).String()
+}
+
+// resourceLister lists namespaced resources a page at a time and streams them to the bundle,
+// so that only one page of objects is held in memory.
+type resourceLister struct {
+	labelSelector string
+	fieldSelector string
+	pageSize      int64
+}
+
+// pageOptions are the options of the lists that the selectors do not apply to, such as the lists
+// of cluster scoped resources
+func (l resourceLister) pageOptions() metav1.ListOptions {
+	pageSize := l.pageSize
+	if pageSize <= 0 {
+		pageSize = defaultClusterResourcesPageSize
+	}
+	return metav1.ListOptions{Limit: pageSize}
+}
+
+// listOptions are the options of the lists of a namespaced resource, with the label selector and
+// the requirements of the field selector that the resource supports
+func (l resourceLister) listOptions(resource string) metav1.ListOptions {
+	opts := l.pageOptions()
+	opts.LabelSelector = l.labelSelector
+	opts.FieldSelector = scopeFieldSelector(l.fieldSelector, resource)
+	return opts
+}
+
+// saveNamespaced saves the objects of the resource in each of the namespaces, calling each, if
+// set, with every object. It returns the errors by namespace.
+func (l resourceLister) saveNamespaced(ctx context.Context, output CollectorResult, bundlePath string, client kubernetes.Interface, resource namespacedResource, namespaces []string, each func(runtime.Object)) map[string]string {
+	errorsByNamespace := make(map[string]string)
+	for _, namespace := range namespaces {
+		relativePath := path.Join(constants.CLUSTER_RESOURCES_DIR, resource.name, namespace+".json")
+		if err := l.saveList(ctx, output, bundlePath, relativePath, l.listOptions(resource.name), resource.list(client, namespace), each); err != nil {
+			errorsByNamespace[namespace] = err.Error()
+		}
+	}
+	return errorsByNamespace
+}
+
+// saveList saves the objects returned by list with opts as a JSON list. Nothing is saved when the
+// first page cannot be listed. When a later page fails, the objects listed so far are saved and
+// the error is returned.
+func (l resourceLister) saveList(ctx context.Context, output CollectorResult, bundlePath string, relativePath string, opts metav1.ListOptions, list listFunc, each func(runtime.Object)) error {
+	page, err := list(ctx, opts)
+	if err != nil {
+		return err
+	}
+
+	pr, pw := io.Pipe()
+	listErr := make(chan error, 1)
+	go func() {
+		err := writeListPages(ctx, pw, page, opts, list, each)
+		pw.Close()
+		listErr <- err
+	}()
+
+	saveErr := output.SaveResult(bundlePath, relativePath, pr)
+	// unblocks the writer when the result could not be saved
+	pr.Close()
+	if err := <-listErr; saveErr == nil {
+		return err
+	}
+	return saveErr
+}
+
+// listCustomResources lists the objects of a custom resource a page at a time. The selectors do
+// not apply to custom resources.
+func (l resourceLister) listCustomResources(ctx context.Context, resource dynamic.ResourceInterface) ([]unstructured.Unstructured, error) {
+	opts := l.pageOptions()
+	items := []unstructured.Unstructured{}
+	for {
+		page, err := resource.List(ctx, opts)
+		if err != nil {
+			if len(items) > 0 {
+				return nil, errors.Wrapf(err, "failed to list objects after the first %d", len(items))
+			}
+			return nil, err
+		}
+		items = append(items, page.Items...)
+		if page.GetContinue() == "" {
+			return items, nil
+		}
+		opts.Continue = page.GetContinue()
+	}
+}
+
+// writeListPages writes the objects of the first page, and of the pages that follow it, to w
+// as a JSON list of the same kind as the page.
+func writeListPages(ctx context.Context, w io.Writer, page runtime.Object, opts metav1.ListOptions, list listFunc, each func(runtime.Object)) error {
+	gvk, err := apiutil.GVKForObject(page, scheme.Scheme)
+	if err != nil {
+		return errors.Wrap(err, "failed to get kind of list")
+	}
+	kind, _ := json.Marshal(gvk.Kind)
+	apiVersion, _ := json.Marshal(gvk.GroupVersion().String())
+
+	bw := bufio.NewWriter(w)
+	bw.WriteString("{\n  \"kind\": ")
+	bw.Write(kind)
+	bw.WriteString(",\n  \"apiVersion\": ")
+	bw.Write(apiVersion)
+	bw.WriteString(",\n  \"metadata\": {},\n  \"items\": [")
+
+	count := 0
+	var listErr error
+	for {
+		items, err := meta.ExtractList(page)
+		if err != nil {
+			listErr = errors.Wrap(err, "failed to extract list items")
+			break
+		}
+		for _, item := range items {
+			if itemGVK, err := apiutil.GVKForObject(item, scheme.Scheme); err == nil {
+				item.GetObjectKind().SetGroupVersionKind(itemGVK)
+			}
+			if each != nil {
+				each(item)
+			}
+
+			b, err := json.MarshalIndent(item, "    ", "  ")
+			if err != nil {
+				listErr = errors.Wrap(err, "failed to marshal object")
+				continue
+			}
+			if count > 0 {
+				bw.WriteString(",")
+			}
+			bw.WriteString("\n    ")
+			bw.Write(b)
+			count++
+		}
+		if err := bw.Flush(); err != nil {
+			return errors.Wrap(err, "failed to write list")
+		}
+
+		listMeta, err := meta.ListAccessor(page)
+		if err != nil || listMeta.GetContinue() == "" {
+			break
+		}
+		opts.Continue = listMeta.GetContinue()
+		page, err = list(ctx, opts)
+		if err != nil {
+			listErr = errors.Wrapf(err, "failed to list objects after the first %d", count)
+			break
+		}
+	}
+
+	if count > 0 {
+		bw.WriteString("\n  ")
+	}
+	bw.WriteString("]\n}\n")
+	if err := bw.Flush(); err != nil {
+		return errors.Wrap(err, "failed to write list")
+	}
+	return listErr
+}
@@ -0,0 +1,283 @@
+package collect
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"testing"
+
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic"
+	testclient "k8s.io/client-go/kubernetes/fake"
+)
+
+// pagedConfigMaps returns a listFunc serving the config maps a page at a time, failing with
+// failErr when asked for page failAt.
+func pagedConfigMaps(names []string, failAt int, failErr error, requests *[]metav1.ListOptions) listFunc {
+	return func(ctx context.Context, opts metav1.ListOptions) (runtime.Object, error) {
+		*requests = append(*requests, opts)
+
+		start := 0
+		if opts.Continue != "" {
+			start, _ = strconv.Atoi(opts.Continue)
+		}
+		if failAt >= 0 && len(*requests)-1 == failAt {
+			return nil, failErr
+		}
+
+		end := min(start+int(opts.Limit), len(names))
+		list := &corev1.ConfigMapList{}
+		for _, name := range names[start:end] {
+			list.Items = append(list.Items, corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"}})
+		}
+		if end < len(names) {
+			list.Continue = strconv.Itoa(end)
+		}
+		return list, nil
+	}
+}
+
+func TestResourceLister_saveList(t *testing.T) {
+	names := []string{"a", "b", "c", "d", "e"}
+
+	tests := []struct {
+		name         string
+		pageSize     int64
+		failAt       int
+		wantNames    []string
+		wantRequests int
+		wantErr      string
+		wantSaved    bool
+	}{
+		{
+			name:         "single page",
+			pageSize:     10,
+			failAt:       -1,
+			wantNames:    names,
+			wantRequests: 1,
+			wantSaved:    true,
+		},
+		{
+			name:         "multiple pages",
+			pageSize:     2,
+			failAt:       -1,
+			wantNames:    names,
+			wantRequests: 3,
+			wantSaved:    true,
+		},
+		{
+			name:         "first page fails",
+			pageSize:     2,
+			failAt:       0,
+			wantRequests: 1,
+			wantErr:      "forbidden",
+		},
+		{
+			name:         "later page fails",
+			pageSize:     2,
+			failAt:       2,
+			wantNames:    []string{"a", "b", "c", "d"},
+			wantRequests: 3,
+			wantErr:      "failed to list objects after the first 4: forbidden",
+			wantSaved:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests := []metav1.ListOptions{}
+			list := pagedConfigMaps(names, tt.failAt, errors.New("forbidden"), &requests)
+
+			lister := resourceLister{labelSelector: "app=test", pageSize: tt.pageSize}
+			output := NewResult()
+			err := lister.saveList(context.Background(), output, "", "cluster-resources/configmaps/default.json", lister.listOptions("configmaps"), list, nil)
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+			} else {
+				require.NoError(t, err)
+			}
+
+			require.Len(t, requests, tt.wantRequests)
+			for _, opts := range requests {
+				assert.Equal(t, "app=test", opts.LabelSelector)
+				assert.Equal(t, tt.pageSize, opts.Limit)
+			}
+
+			data, ok := output["cluster-resources/configmaps/default.json"]
+			require.Equal(t, tt.wantSaved, ok)
+			if !tt.wantSaved {
+				return
+			}
+
+			var configMapList corev1.ConfigMapList
+			require.NoError(t, json.Unmarshal(data, &configMapList))
+			assert.Equal(t, "ConfigMapList", configMapList.Kind)
+			assert.Equal(t, "v1", configMapList.APIVersion)
+			got := []string{}
+			for _, cm := range configMapList.Items {
+				assert.Equal(t, "ConfigMap", cm.Kind)
+				got = append(got, cm.Name)
+			}
+			assert.Equal(t, tt.wantNames, got)
+		})
+	}
+}
+
+func TestResourceLister_saveNamespacedLabelSelector(t *testing.T) {
+	client := testclient.NewSimpleClientset(
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "default", Labels: map[string]string{"app": "api"}}},
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "default", Labels: map[string]string{"app": "db"}}},
+	)
+
+	seen := []string{}
+	output := NewResult()
+	errs := resourceLister{labelSelector: "app=api"}.saveNamespaced(context.Background(), output, "", client, podsResource, []string{"default"}, func(obj runtime.Object) {
+		seen = append(seen, obj.(*corev1.Pod).Name)
+	})
+	assert.Empty(t, errs)
+	assert.Equal(t, []string{"api"}, seen)
+
+	var podList corev1.PodList
+	require.NoError(t, json.Unmarshal(output["cluster-resources/pods/default.json"], &podList))
+	require.Len(t, podList.Items, 1)
+	assert.Equal(t, "api", podList.Items[0].Name)
+}
+
+func TestScopeFieldSelector(t *testing.T) {
+	tests := []struct {
+		name          string
+		fieldSelector string
+		resource      string
+		want          string
+	}{
+		{
+			name:          "supported by the resource",
+			fieldSelector: "status.phase!=Running,spec.nodeName=node-1",
+			resource:      "pods",
+			want:          "spec.nodeName=node-1,status.phase!=Running",
+		},
+		{
+			name:          "not supported by the resource",
+			fieldSelector: "status.phase!=Running",
+			resource:      "deployments",
+			want:          "",
+		},
+		{
+			name:          "supported by every resource",
+			fieldSelector: "metadata.name=api,involvedObject.kind=Pod",
+			resource:      "deployments",
+			want:          "metadata.name=api",
+		},
+		{
+			name:          "events",
+			fieldSelector: "metadata.name=api,involvedObject.kind=Pod",
+			resource:      "events",
+			want:          "involvedObject.kind=Pod,metadata.name=api",
+		},
+		{
+			name:          "invalid",
+			fieldSelector: "status.phase",
+			resource:      "pods",
+			want:          "status.phase",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, scopeFieldSelector(tt.fieldSelector, tt.resource))
+		})
+	}
+}
+
+// pagedCustomResources serves the objects of a custom resource a page at a time
+type pagedCustomResources struct {
+	dynamic.ResourceInterface
+	names    []string
+	requests []metav1.ListOptions
+}
+
+func (r *pagedCustomResources) List(ctx context.Context, opts metav1.ListOptions) (*unstructured.UnstructuredList, error) {
+	r.requests = append(r.requests, opts)
+
+	start := 0
+	if opts.Continue != "" {
+		start, _ = strconv.Atoi(opts.Continue)
+	}
+	end := min(start+int(opts.Limit), len(r.names))
+	list := &unstructured.UnstructuredList{}
+	for _, name := range r.names[start:end] {
+		item := unstructured.Unstructured{}
+		item.SetName(name)
+		list.Items = append(list.Items, item)
+	}
+	if end < len(r.names) {
+		list.SetContinue(strconv.Itoa(end))
+	}
+	return list, nil
+}
+
+func TestResourceLister_listCustomResources(t *testing.T) {
+	resource := &pagedCustomResources{names: []string{"a", "b", "c", "d", "e"}}
+
+	items, err := resourceLister{labelSelector: "app=test", pageSize: 2}.listCustomResources(context.Background(), resource)
+	require.NoError(t, err)
+
+	got := []string{}
+	for _, item := range items {
+		got = append(got, item.GetName())
+	}
+	assert.Equal(t, resource.names, got)
+	require.Len(t, resource.requests, 3)
+	for _, opts := range resource.requests {
+		assert.Equal(t, int64(2), opts.Limit)
+		assert.Empty(t, opts.LabelSelector)
+	}
+}
+
+func TestCollectClusterResources_shouldCollect(t *testing.T) {
+	tests := []struct {
+		name    string
+		include []string
+		exclude []string
+		want    map[string]bool
+	}{
+		{
+			name: "everything by default",
+			want: map[string]bool{"pods": true, "events": true},
+		},
+		{
+			name:    "include",
+			include: []string{"pods"},
+			want:    map[string]bool{"pods": true, "events": false},
+		},
+		{
+			name:    "exclude",
+			exclude: []string{"events"},
+			want:    map[string]bool{"pods": true, "events": false},
+		},
+		{
+			name:    "exclude wins over include",
+			include: []string{"pods", "events"},
+			exclude: []string{"events"},
+			want:    map[string]bool{"pods": true, "events": false},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &CollectClusterResources{
+				Collector: &troubleshootv1beta2.ClusterResources{
+					IncludeResources: tt.include,
+					ExcludeResources: tt.exclude,
+				},
+			}
+			for resource, want := range tt.want {
+				assert.Equal(t, want, c.shouldCollect(resource), resource)
+			}
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"os"
+	"path"
 	"reflect"
 	"testing"
 
@@ -55,13 +56,16 @@ func Test_ConfigMaps(t *testing.T) {
 			err := createConfigMaps(client, tt.configMapNames, tt.namespaces)
 			assert.NoError(t, err)
 
-			configMaps, _ := configMaps(ctx, client, tt.namespaces)
+			configMaps := NewResult()
+			errs := resourceLister{}.saveNamespaced(ctx, configMaps, "", client, configMapsResource, tt.namespaces, nil)
+			assert.Empty(t, errs)
 			assert.Equal(t, len(tt.namespaces), len(configMaps))
 
 			for _, ns := range tt.namespaces {
-				assert.NotEmpty(t, configMaps[ns+".json"])
+				file := path.Join("cluster-resources/configmaps", ns+".json")
+				assert.NotEmpty(t, configMaps[file])
 				var configmapList corev1.ConfigMapList
-				err := json.Unmarshal(configMaps[ns+".json"], &configmapList)
+				err := json.Unmarshal(configMaps[file], &configmapList)
 				assert.NoError(t, err)
 				// Ensure the ConfigMap names match those in the list
 				assert.Equal(t, len(configmapList.Items), len(tt.configMapNames))
@@ -168,13 +172,16 @@ func Test_Leases(t *testing.T) {
 			err := createTestLeases(client, tt.leaseNames, tt.namespaces)
 			assert.NoError(t, err)
 
-			leases, _ := leases(ctx, client, tt.namespaces)
+			leases := NewResult()
+			errs := resourceLister{}.saveNamespaced(ctx, leases, "", client, leasesResource, tt.namespaces, nil)
+			assert.Empty(t, errs)
 			assert.Equal(t, len(tt.namespaces), len(leases))
 
 			for _, ns := range tt.namespaces {
-				assert.NotEmpty(t, leases[ns+".json"])
+				file := path.Join("cluster-resources/leases", ns+".json")
+				assert.NotEmpty(t, leases[file])
 				var leaseList v1.LeaseList
-				err := json.Unmarshal(leases[ns+".json"], &leaseList)
+				err := json.Unmarshal(leases[file], &leaseList)
 				assert.NoError(t, err)
 				// Ensure the Lease names match those in the list
 				assert.Equal(t, len(leaseList.Items), len(tt.leaseNames))
@@ -231,13 +238,16 @@ func Test_ServiceAccounts(t *testing.T) {
 			err := createTestServiceAccounts(client, tt.serviceAccountNames, tt.namespaces)
 			assert.NoError(t, err)
 
-			servicesAccounts, _ := serviceAccounts(ctx, client, tt.namespaces)
+			servicesAccounts := NewResult()
+			errs := resourceLister{}.saveNamespaced(ctx, servicesAccounts, "", client, serviceAccountsResource, tt.namespaces, nil)
+			assert.Empty(t, errs)
 			assert.Equal(t, len(tt.namespaces), len(servicesAccounts))
 
 			for _, ns := range tt.namespaces {
-				assert.NotEmpty(t, servicesAccounts[ns+".json"])
+				file := path.Join("cluster-resources/serviceaccounts", ns+".json")
+				assert.NotEmpty(t, servicesAccounts[file])
 				var serviceAccountList corev1.ServiceAccountList
-				err := json.Unmarshal(servicesAccounts[ns+".json"], &serviceAccountList)
+				err := json.Unmarshal(servicesAccounts[file], &serviceAccountList)
 				assert.NoError(t, err)
 				// Ensure the ServiceAccount names match those in the list
 				assert.Equal(t, len(serviceAccountList.Items), len(tt.serviceAccountNames))
@@ -501,7 +511,7 @@ func TestCollectClusterResources_CustomResource(t *testing.T) {
 	dynamicClient := testdynamicclient.NewSimpleDynamicClient(scheme.Scheme, &sbObject)
 
 	// Fetch the CR from cluster
-	res, errs := crsV1(ctx, dynamicClient, apixClient.ApiextensionsV1(), []string{"default"})
+	res, errs := crsV1(ctx, dynamicClient, apixClient.ApiextensionsV1(), []string{"default"}, resourceLister{})
 	assert.Empty(t, errs)
 	require.Equal(t, 2, len(res))
 	assert.Equal(t, fromJSON(t, res["supportbundles.troubleshoot.sh/default.json"]), sbObject)
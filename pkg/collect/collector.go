@@ -10,6 +10,7 @@ import (
 
 	"github.com/pkg/errors"
 	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/k8sutil"
 	"github.com/replicatedhq/troubleshoot/pkg/multitype"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
@@ -29,6 +30,13 @@ type MergeableCollector interface {
 	Merge(allCollectors []Collector) ([]Collector, error)
 }
 
+// PortForwardingCollector is a collector that forwards local ports to pods. The collectors of a
+// run share the port-forwards of a pool, collectors that are not given one create their own.
+type PortForwardingCollector interface {
+	Collector
+	SetPortForwardPool(pool *k8sutil.PortForwardPool)
+}
+
 //type Collectors []*Collector
 
 func isExcluded(excludeVal *multitype.BoolOrString) (bool, error) {
@@ -53,9 +61,12 @@ func isExcluded(excludeVal *multitype.BoolOrString) (bool, error) {
 }
 
 func GetCollector(collector *troubleshootv1beta2.Collect, bundlePath string, namespace string, clientConfig *rest.Config, client kubernetes.Interface, sinceTime *time.Time) (interface{}, bool) {
+	return GetCollectorWithContext(context.TODO(), collector, bundlePath, namespace, clientConfig, client, sinceTime)
+}
 
-	ctx := context.TODO()
-
+// GetCollectorWithContext is GetCollector with the context the collector runs with. Collectors stop
+// when the context is done, and still delete the pods and other resources they created.
+func GetCollectorWithContext(ctx context.Context, collector *troubleshootv1beta2.Collect, bundlePath string, namespace string, clientConfig *rest.Config, client kubernetes.Interface, sinceTime *time.Time) (interface{}, bool) {
 	var RBACErrors []error
 
 	switch {
@@ -95,7 +106,7 @@ func GetCollector(collector *troubleshootv1beta2.Collect, bundlePath string, nam
 			RBACErrors:       RBACErrors,
 		}, true
 	case collector.HTTP != nil:
-		return &CollectHTTP{collector.HTTP, bundlePath, namespace, clientConfig, client, RBACErrors}, true
+		return &CollectHTTP{collector.HTTP, bundlePath, namespace, clientConfig, client, ctx, RBACErrors}, true
 	case collector.Postgres != nil:
 		return &CollectPostgres{collector.Postgres, bundlePath, namespace, clientConfig, client, ctx, RBACErrors}, true
 	case collector.Mssql != nil:
@@ -104,6 +115,14 @@ func GetCollector(collector *troubleshootv1beta2.Collect, bundlePath string, nam
 		return &CollectMysql{collector.Mysql, bundlePath, namespace, clientConfig, client, ctx, RBACErrors}, true
 	case collector.Redis != nil:
 		return &CollectRedis{collector.Redis, bundlePath, namespace, clientConfig, client, ctx, RBACErrors}, true
+	case collector.Kafka != nil:
+		return &CollectKafka{collector.Kafka, bundlePath, namespace, clientConfig, client, ctx, RBACErrors}, true
+	case collector.RabbitMQ != nil:
+		return &CollectRabbitMQ{collector.RabbitMQ, bundlePath, namespace, clientConfig, client, ctx, RBACErrors}, true
+	case collector.LDAP != nil:
+		return &CollectLDAP{collector.LDAP, bundlePath, namespace, clientConfig, client, ctx, RBACErrors}, true
+	case collector.OIDC != nil:
+		return &CollectOIDC{collector.OIDC, bundlePath, namespace, clientConfig, client, ctx, RBACErrors}, true
 	case collector.Collectd != nil:
 		return &CollectCollectd{collector.Collectd, bundlePath, namespace, clientConfig, client, ctx, RBACErrors}, true
 	case collector.Ceph != nil:
@@ -122,6 +141,10 @@ func GetCollector(collector *troubleshootv1beta2.Collect, bundlePath string, nam
 		return &CollectHelm{collector.Helm, bundlePath, namespace, clientConfig, client, ctx, RBACErrors}, true
 	case collector.Goldpinger != nil:
 		return &CollectGoldpinger{collector.Goldpinger, bundlePath, namespace, clientConfig, client, ctx, RBACErrors}, true
+	case collector.NodeLatency != nil:
+		return &CollectNodeLatency{collector.NodeLatency, bundlePath, namespace, clientConfig, client, ctx, RBACErrors}, true
+	case collector.OpenShift != nil:
+		return &CollectOpenShift{collector.OpenShift, bundlePath, clientConfig, client, ctx, RBACErrors}, true
 	case collector.Sonobuoy != nil:
 		return &CollectSonobuoyResults{collector.Sonobuoy, bundlePath, namespace, clientConfig, client, ctx, RBACErrors}, true
 	case collector.NodeMetrics != nil:
@@ -130,14 +153,67 @@ func GetCollector(collector *troubleshootv1beta2.Collect, bundlePath string, nam
 		return &CollectDNS{collector.DNS, bundlePath, namespace, clientConfig, client, ctx, RBACErrors}, true
 	case collector.Etcd != nil:
 		return &CollectEtcd{collector.Etcd, bundlePath, clientConfig, client, ctx, RBACErrors}, true
+	case collector.Plugin != nil:
+		return &CollectPlugin{collector.Plugin, bundlePath, namespace, clientConfig, client, ctx, RBACErrors}, true
+	case collector.Proxy != nil:
+		return &CollectProxy{collector.Proxy, bundlePath, namespace, clientConfig, client, ctx, RBACErrors}, true
+	case collector.Pprof != nil:
+		return &CollectPprof{collector.Pprof, bundlePath, namespace, clientConfig, client, ctx, nil, RBACErrors}, true
+	case collector.Velero != nil:
+		return &CollectVelero{collector.Velero, bundlePath, namespace, clientConfig, client, ctx, RBACErrors}, true
+	case collector.KubeletConfig != nil:
+		return &CollectKubeletConfig{collector.KubeletConfig, bundlePath, clientConfig, client, ctx, RBACErrors}, true
+	case collector.TrustChains != nil:
+		return &CollectTrustChains{collector.TrustChains, bundlePath, namespace, clientConfig, client, ctx, RBACErrors}, true
+	case collector.ImageLayers != nil:
+		return &CollectImageLayers{collector.ImageLayers, bundlePath, namespace, clientConfig, client, ctx, RBACErrors}, true
+	case collector.ImageFacts != nil:
+		return &CollectImageFacts{collector.ImageFacts, bundlePath, namespace, clientConfig, client, ctx, RBACErrors}, true
+	case collector.KubeletMetrics != nil:
+		return &CollectKubeletMetrics{collector.KubeletMetrics, bundlePath, clientConfig, client, ctx, RBACErrors}, true
+	case collector.PolicyViolations != nil:
+		return &CollectPolicyViolations{collector.PolicyViolations, bundlePath, namespace, clientConfig, client, ctx, RBACErrors}, true
+	case collector.ManagedCluster != nil:
+		return &CollectManagedCluster{collector.ManagedCluster, bundlePath, namespace, clientConfig, client, ctx, RBACErrors}, true
 	default:
 		return nil, false
 	}
 }
 
+// collectorContext returns ctx, or the background context for collectors created without one
+func collectorContext(ctx context.Context) context.Context {
+	if ctx == nil {
+		return context.Background()
+	}
+	return ctx
+}
+
+// cleanupContext returns the context to delete the pods and other resources a collector created
+// with. It is not canceled with ctx, so that the resources are deleted after Ctrl-C or a timeout.
+func cleanupContext(ctx context.Context) context.Context {
+	return context.WithoutCancel(collectorContext(ctx))
+}
+
 func getCollectorName(c interface{}) string {
-	var collector, name, selector string
+	collector, name, selector := getCollectorKind(c)
 
+	if name != "" {
+		return fmt.Sprintf("%s/%s", collector, name)
+	}
+	if selector != "" {
+		return fmt.Sprintf("%s/%s", collector, selector)
+	}
+	return collector
+}
+
+// CollectorKind returns the kind of an in-cluster collector, e.g. "logs", without its name
+func CollectorKind(c Collector) string {
+	collector, _, _ := getCollectorKind(c)
+	return collector
+}
+
+// getCollectorKind returns the kind of collector along with its name and selector, if any
+func getCollectorKind(c interface{}) (collector, name, selector string) {
 	switch v := c.(type) {
 	case *CollectClusterInfo:
 		collector = "cluster-info"
@@ -196,6 +272,18 @@ func getCollectorName(c interface{}) string {
 	case *CollectRedis:
 		collector = "redis"
 		name = v.Collector.CollectorName
+	case *CollectKafka:
+		collector = "kafka"
+		name = v.Collector.CollectorName
+	case *CollectRabbitMQ:
+		collector = "rabbitmq"
+		name = v.Collector.CollectorName
+	case *CollectLDAP:
+		collector = "ldap"
+		name = v.Collector.CollectorName
+	case *CollectOIDC:
+		collector = "oidc"
+		name = v.Collector.CollectorName
 	case *CollectCollectd:
 		collector = "collectd"
 		name = v.Collector.CollectorName
@@ -211,6 +299,12 @@ func getCollectorName(c interface{}) string {
 	case *CollectImageSignatures:
 		collector = "image-signatures"
 		name = v.Collector.CollectorName
+	case *CollectImageLayers:
+		collector = "image-layers"
+		name = v.Collector.CollectorName
+	case *CollectImageFacts:
+		collector = "image-facts"
+		name = v.Collector.CollectorName
 	case *CollectSysctl:
 		collector = "sysctl"
 		name = v.Collector.Name
@@ -220,6 +314,12 @@ func getCollectorName(c interface{}) string {
 		collector = "helm"
 	case *CollectGoldpinger:
 		collector = "goldpinger"
+	case *CollectNodeLatency:
+		collector = "node-latency"
+		name = v.Collector.CollectorName
+	case *CollectOpenShift:
+		collector = "openshift"
+		name = v.Collector.CollectorName
 	case *CollectSonobuoyResults:
 		collector = "sonobuoy"
 	case *CollectNodeMetrics:
@@ -228,17 +328,43 @@ func getCollectorName(c interface{}) string {
 		collector = "dns"
 	case *CollectEtcd:
 		collector = "etcd"
+	case *CollectPlugin:
+		collector = "plugin"
+		name = v.Collector.CollectorName
+		if name == "" {
+			name = v.Collector.Name
+		}
+	case *CollectProxy:
+		collector = "proxy"
+		name = v.Collector.CollectorName
+		selector = strings.Join(v.Collector.Selector, ",")
+	case *CollectPprof:
+		collector = "pprof"
+		name = v.Collector.CollectorName
+		selector = strings.Join(v.Collector.Selector, ",")
+	case *CollectVelero:
+		collector = "velero"
+		name = v.Collector.CollectorName
+	case *CollectKubeletConfig:
+		collector = "kubelet-config"
+		name = v.Collector.CollectorName
+	case *CollectTrustChains:
+		collector = "trust-chains"
+		name = v.Collector.CollectorName
+	case *CollectKubeletMetrics:
+		collector = "kubelet-metrics"
+		name = v.Collector.CollectorName
+	case *CollectPolicyViolations:
+		collector = "policy-violations"
+		name = v.Collector.CollectorName
+	case *CollectManagedCluster:
+		collector = "managed-cluster"
+		name = v.Collector.CollectorName
 	default:
 		collector = "<none>"
 	}
 
-	if name != "" {
-		return fmt.Sprintf("%s/%s", collector, name)
-	}
-	if selector != "" {
-		return fmt.Sprintf("%s/%s", collector, selector)
-	}
-	return collector
+	return collector, name, selector
 }
 
 // Ensure that the specified collector is in the list of collectors
@@ -0,0 +1,65 @@
+package collect
+
+import (
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/constants"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// CollectorImages returns the images of the pods a collector runs in the cluster, with the
+// defaults of the collectors whose image is optional. Images of existing pods, such as those the
+// exec collector runs commands in, are not included.
+func CollectorImages(collector *troubleshootv1beta2.Collect) []string {
+	images := []string{}
+	add := func(image string, defaultImage string) {
+		if image == "" {
+			image = defaultImage
+		}
+		if image != "" {
+			images = append(images, image)
+		}
+	}
+
+	switch c := troubleshootv1beta2.GetCollector(collector).(type) {
+	case ImageRunner:
+		add(c.GetImage(), "")
+	case *troubleshootv1beta2.RunPod:
+		images = append(images, podSpecImages(c.PodSpec)...)
+	case *troubleshootv1beta2.RunDaemonSet:
+		images = append(images, podSpecImages(c.PodSpec)...)
+	case *troubleshootv1beta2.Goldpinger:
+		add(c.Image, goldpingerImage)
+		podImage := ""
+		if c.PodLaunchOptions != nil {
+			podImage = c.PodLaunchOptions.Image
+		}
+		add(podImage, constants.GP_DEFAULT_IMAGE)
+	case *troubleshootv1beta2.NodeLatency:
+		add(c.Image, constants.NODE_LATENCY_DEFAULT_IMAGE)
+	case *troubleshootv1beta2.DNS:
+		add(c.Image, dnsUtilsImage)
+	case *troubleshootv1beta2.Etcd:
+		add(c.Image, "")
+	}
+
+	return images
+}
+
+// RemoteCollectorImage returns the image of the pods that run remote collectors on the nodes,
+// the image of the --collector-image flag when it is set
+func RemoteCollectorImage(image string) string {
+	if image == "" {
+		return remoteCollectorDefaultImage
+	}
+	return image
+}
+
+func podSpecImages(podSpec corev1.PodSpec) []string {
+	images := []string{}
+	for _, container := range append(append([]corev1.Container{}, podSpec.InitContainers...), podSpec.Containers...) {
+		if container.Image != "" {
+			images = append(images, container.Image)
+		}
+	}
+	return images
+}
@@ -46,7 +46,7 @@ func (c *CollectCopy) Collect(progressChan chan<- interface{}) (CollectorResult,
 
 	output := NewResult()
 
-	ctx := context.Background()
+	ctx := collectorContext(c.Context)
 
 	pods, podsErrors := listPodsInSelectors(ctx, client, c.Collector.Namespace, c.Collector.Selector)
 	if len(podsErrors) > 0 {
@@ -137,6 +137,10 @@ func copyFilesFromPod(ctx context.Context, dstPath string, clientConfig *restcli
 
 				switch header.Typeflag {
 				case tar.TypeDir:
+					if dstPath == "" {
+						// memory only bundles have no directories
+						continue
+					}
 					name := filepath.Join(dstPath, header.Name)
 					if err := os.MkdirAll(name, os.FileMode(header.Mode)); err != nil {
 						pipeWriter.CloseWithError(errors.Wrap(err, "failed to mkdir"))
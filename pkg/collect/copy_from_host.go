@@ -192,6 +192,8 @@ func (c *CollectCopyFromHost) copyFromHostCreateDaemonSet(ctx context.Context, c
 		},
 	}
 
+	applyPodScheduling(&ds.Spec.Template.Spec, collector.PodScheduling)
+
 	cleanupFuncs := []func(){}
 	cleanup = func() {
 		for _, fn := range cleanupFuncs {
@@ -220,7 +222,7 @@ func (c *CollectCopyFromHost) copyFromHostCreateDaemonSet(ctx context.Context, c
 		return "", cleanup, errors.Wrap(err, "create daemonset")
 	}
 	cleanupFuncs = append(cleanupFuncs, func() {
-		deleteDaemonSet(client, ctx, createdDS, namespace, labels)
+		deleteDaemonSet(client, cleanupContext(ctx), createdDS, namespace, labels)
 	})
 
 	// This timeout is different from collector timeout.
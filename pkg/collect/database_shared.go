@@ -1,8 +1,68 @@
 package collect
 
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
 type DatabaseConnection struct {
-	IsConnected bool              `json:"isConnected"`
-	Error       string            `json:"error,omitempty"`
-	Version     string            `json:"version,omitempty"`
-	Variables   map[string]string `json:"variables,omitempty"`
+	IsConnected bool                 `json:"isConnected"`
+	Error       string               `json:"error,omitempty"`
+	Version     string               `json:"version,omitempty"`
+	Variables   map[string]string    `json:"variables,omitempty"`
+	Diagnostics *DatabaseDiagnostics `json:"diagnostics,omitempty"`
+}
+
+// DatabaseDiagnostics are collected from a database server when the collector enables
+// diagnostics. Diagnostics that cannot be read, e.g. for lack of privileges, are listed in Errors
+// and left empty.
+type DatabaseDiagnostics struct {
+	// IsReplica is true when the server replicates from a primary
+	IsReplica bool `json:"isReplica"`
+	// ReplicationLagSeconds is how far a replica is behind its primary. It is not set on primaries
+	// nor on replicas that do not know their lag, e.g. when replication is stopped.
+	ReplicationLagSeconds *float64 `json:"replicationLagSeconds,omitempty"`
+	Connections           int      `json:"connections"`
+	MaxConnections        int      `json:"maxConnections,omitempty"`
+	// SlowQueries is what the server counts as slow queries: the queries that have been running
+	// for more than 5 seconds in PostgreSQL, the Slow_queries status of MySQL, and the length of
+	// the slow log of Redis
+	SlowQueries int64    `json:"slowQueries"`
+	Errors      []string `json:"errors,omitempty"`
+}
+
+func (d *DatabaseDiagnostics) addError(diagnostic string, err error) {
+	d.Errors = append(d.Errors, fmt.Sprintf("failed to get %s: %v", diagnostic, err))
+}
+
+// databaseURI returns the URI of the database of the collector, reading it from a secret when
+// the collector sets one
+func databaseURI(ctx context.Context, client kubernetes.Interface, collector *troubleshootv1beta2.Database) (string, error) {
+	if collector.URISecret == nil {
+		return collector.URI, nil
+	}
+	return readURISecret(ctx, client, collector.URISecret)
+}
+
+// readURISecret returns the URI held in the key of the secret
+func readURISecret(ctx context.Context, client kubernetes.Interface, uriSecret *troubleshootv1beta2.DatabaseURISecret) (string, error) {
+	return readSecretKey(ctx, client, uriSecret.Namespace, uriSecret.Name, uriSecret.Key)
+}
+
+// readSecretKey returns the value of the key of a secret
+func readSecretKey(ctx context.Context, client kubernetes.Interface, namespace, name, key string) (string, error) {
+	secret, err := client.CoreV1().Secrets(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get secret %s/%s", namespace, name)
+	}
+	value, ok := secret.Data[key]
+	if !ok {
+		return "", errors.Errorf("secret %s/%s does not have key %q", namespace, name, key)
+	}
+	return string(value), nil
 }
@@ -91,7 +91,7 @@ func (c *CollectDNS) Collect(progressChan chan<- interface{}) (CollectorResult,
 		image = dnsUtilsImage
 	}
 
-	podLog, err := troubleshootDNSFromPod(c.Client, ctx, testDomain, image)
+	podLog, err := troubleshootDNSFromPod(c.Client, ctx, testDomain, image, c.Collector.PodScheduling)
 	if err == nil {
 		sb.WriteString(fmt.Sprintf("=== Test DNS resolution in pod %s: \n", image))
 		sb.WriteString(podLog)
@@ -158,7 +158,7 @@ func getKubernetesClusterIP(client kubernetes.Interface, ctx context.Context) (s
 	return service.Spec.ClusterIP, nil
 }
 
-func troubleshootDNSFromPod(client kubernetes.Interface, ctx context.Context, nonResolvableDomain string, image string) (string, error) {
+func troubleshootDNSFromPod(client kubernetes.Interface, ctx context.Context, nonResolvableDomain string, image string, scheduling troubleshootv1beta2.PodScheduling) (string, error) {
 	namespace := "default"
 	command := []string{"/bin/sh", "-c", fmt.Sprintf(`
 		echo "=== /etc/resolv.conf ==="
@@ -191,6 +191,7 @@ func troubleshootDNSFromPod(client kubernetes.Interface, ctx context.Context, no
 			RestartPolicy: corev1.RestartPolicyNever,
 		},
 	}
+	applyPodScheduling(&pod.Spec, scheduling)
 
 	created, err := client.CoreV1().Pods(namespace).Create(ctx, pod, metav1.CreateOptions{})
 	if err != nil {
@@ -202,7 +203,7 @@ func troubleshootDNSFromPod(client kubernetes.Interface, ctx context.Context, no
 		if created == nil {
 			return
 		}
-		err := client.CoreV1().Pods(namespace).Delete(ctx, created.Name, metav1.DeleteOptions{})
+		err := client.CoreV1().Pods(namespace).Delete(cleanupContext(ctx), created.Name, metav1.DeleteOptions{})
 		if err != nil {
 			klog.Errorf("Failed to delete troubleshoot DNS pod %s: %v", created.Name, err)
 		}
@@ -35,8 +35,10 @@ func (c *CollectExec) IsExcluded() (bool, error) {
 }
 
 func (c *CollectExec) Collect(progressChan chan<- interface{}) (CollectorResult, error) {
+	ctx := collectorContext(c.Context)
+
 	if c.Collector.Timeout == "" {
-		return execWithoutTimeout(c.ClientConfig, c.BundlePath, c.Collector)
+		return execWithoutTimeout(ctx, c.ClientConfig, c.BundlePath, c.Collector)
 	}
 
 	timeout, err := time.ParseDuration(c.Collector.Timeout)
@@ -47,9 +49,12 @@ func (c *CollectExec) Collect(progressChan chan<- interface{}) (CollectorResult,
 	errCh := make(chan error, 1)
 	resultCh := make(chan CollectorResult, 1)
 
-	// TODO: Use a context with timeout instead of a goroutine
+	// the command is stopped when the collector returns
+	timeoutCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
 	go func() {
-		b, err := execWithoutTimeout(c.ClientConfig, c.BundlePath, c.Collector)
+		b, err := execWithoutTimeout(timeoutCtx, c.ClientConfig, c.BundlePath, c.Collector)
 		if err != nil {
 			errCh <- err
 		} else {
@@ -58,7 +63,10 @@ func (c *CollectExec) Collect(progressChan chan<- interface{}) (CollectorResult,
 	}()
 
 	select {
-	case <-time.After(timeout):
+	case <-timeoutCtx.Done():
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
 		return nil, errors.New("timeout")
 	case result := <-resultCh:
 		return result, nil
@@ -67,7 +75,7 @@ func (c *CollectExec) Collect(progressChan chan<- interface{}) (CollectorResult,
 	}
 }
 
-func execWithoutTimeout(clientConfig *rest.Config, bundlePath string, execCollector *troubleshootv1beta2.Exec) (CollectorResult, error) {
+func execWithoutTimeout(ctx context.Context, clientConfig *rest.Config, bundlePath string, execCollector *troubleshootv1beta2.Exec) (CollectorResult, error) {
 	client, err := kubernetes.NewForConfig(clientConfig)
 	if err != nil {
 		return nil, err
@@ -75,8 +83,6 @@ func execWithoutTimeout(clientConfig *rest.Config, bundlePath string, execCollec
 
 	output := NewResult()
 
-	ctx := context.Background()
-
 	pods, podsErrors := listPodsInSelectors(ctx, client, execCollector.Namespace, execCollector.Selector)
 	if len(podsErrors) > 0 {
 		output.SaveResult(bundlePath, getExecErrorsFileName(execCollector), marshalErrors(podsErrors))
@@ -94,6 +100,11 @@ func execWithoutTimeout(clientConfig *rest.Config, bundlePath string, execCollec
 		if len(stderr) > 0 {
 			output.SaveResult(bundlePath, filepath.Join(path, execCollector.CollectorName+"-stderr.txt"), bytes.NewBuffer(stderr))
 		}
+		if execCollector.Parse != "" && len(stdout) > 0 {
+			if err := saveParsedOutput(output, bundlePath, filepath.Join(path, execCollector.CollectorName+"-parsed.json"), execCollector.Parse, stdout); err != nil {
+				execErrors = append(execErrors, err.Error())
+			}
+		}
 
 		if len(execErrors) > 0 {
 			output.SaveResult(bundlePath, filepath.Join(path, execCollector.CollectorName+"-errors.json"), marshalErrors(execErrors))
@@ -107,37 +107,38 @@ type createdResources struct {
 }
 
 func (c *CollectGoldpinger) cleanupResources(resources createdResources) error {
+	ctx := cleanupContext(c.Context)
 	var errs []error
 	if resources.Service != nil {
-		if err := c.Client.CoreV1().Services(resources.Service.Namespace).Delete(c.Context, resources.Service.Name, metav1.DeleteOptions{}); err != nil {
+		if err := c.Client.CoreV1().Services(resources.Service.Namespace).Delete(ctx, resources.Service.Name, metav1.DeleteOptions{}); err != nil {
 			errs = append(errs, errors.Wrapf(err, "failed to delete Service %s", resources.Service.Name))
 		}
 		klog.V(2).Infof("%s Service deleted", resources.Service.Name)
 	}
 
 	if resources.DaemonSet != nil {
-		if err := c.Client.AppsV1().DaemonSets(resources.DaemonSet.Namespace).Delete(c.Context, resources.DaemonSet.Name, metav1.DeleteOptions{}); err != nil {
+		if err := c.Client.AppsV1().DaemonSets(resources.DaemonSet.Namespace).Delete(ctx, resources.DaemonSet.Name, metav1.DeleteOptions{}); err != nil {
 			errs = append(errs, errors.Wrapf(err, "failed to delete DaemonSet %s", resources.DaemonSet.Name))
 		}
 		klog.V(2).Infof("%s DaemonSet deleted", resources.DaemonSet.Name)
 	}
 
 	if resources.ServiceAccnt != nil {
-		if err := c.Client.CoreV1().ServiceAccounts(resources.ServiceAccnt.Namespace).Delete(c.Context, resources.ServiceAccnt.Name, metav1.DeleteOptions{}); err != nil {
+		if err := c.Client.CoreV1().ServiceAccounts(resources.ServiceAccnt.Namespace).Delete(ctx, resources.ServiceAccnt.Name, metav1.DeleteOptions{}); err != nil {
 			errs = append(errs, errors.Wrapf(err, "failed to delete ServiceAccount %s", resources.ServiceAccnt.Name))
 		}
 		klog.V(2).Infof("%s ServiceAccount deleted", resources.ServiceAccnt.Name)
 	}
 
 	if resources.RoleBinding != nil {
-		if err := c.Client.RbacV1().RoleBindings(resources.RoleBinding.Namespace).Delete(c.Context, resources.RoleBinding.Name, metav1.DeleteOptions{}); err != nil {
+		if err := c.Client.RbacV1().RoleBindings(resources.RoleBinding.Namespace).Delete(ctx, resources.RoleBinding.Name, metav1.DeleteOptions{}); err != nil {
 			errs = append(errs, errors.Wrapf(err, "failed to delete RoleBinding %s", resources.RoleBinding.Name))
 		}
 		klog.V(2).Infof("%s RoleBinding deleted", resources.RoleBinding.Name)
 	}
 
 	if resources.Role != nil {
-		if err := c.Client.RbacV1().Roles(resources.Role.Namespace).Delete(c.Context, resources.Role.Name, metav1.DeleteOptions{}); err != nil {
+		if err := c.Client.RbacV1().Roles(resources.Role.Namespace).Delete(ctx, resources.Role.Name, metav1.DeleteOptions{}); err != nil {
 			errs = append(errs, errors.Wrapf(err, "failed to delete Role %s", resources.Role.Name))
 		}
 		klog.V(2).Infof("%s Role deleted", resources.Role.Name)
@@ -0,0 +1,465 @@
+package collect
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"k8s.io/klog/v2"
+)
+
+// Ensure `CollectHostActivityCapture` implements `HostCollector` interface at compile time.
+var _ HostCollector = (*CollectHostActivityCapture)(nil)
+
+const HostActivityCapturePath = `host-collectors/system/activity-capture.json`
+const HostActivityCaptureFileName = `activity-capture.json`
+
+const (
+	defaultActivityCaptureInterval  = time.Second
+	defaultActivityCaptureDuration  = 10 * time.Second
+	maxActivityCaptureDuration      = 60 * time.Second
+	defaultActivityCaptureProcesses = 10
+	// diskSectorSize is the size of the sectors counted in /proc/diskstats, whatever the sector
+	// size of the disk
+	diskSectorSize = 512
+)
+
+// ActivityCaptureInfo is the output of the activity capture collector
+type ActivityCaptureInfo struct {
+	IntervalSeconds float64 `json:"intervalSeconds"`
+	DurationSeconds float64 `json:"durationSeconds"`
+	// Samples are sorted by time. Each one reports the activity of the interval before it.
+	Samples []ActivitySample `json:"samples"`
+	// Errors are the failures to collect the optional parts of the samples, e.g. the processes
+	// when ps is not installed
+	Errors []string `json:"errors,omitempty"`
+}
+
+type ActivitySample struct {
+	Time time.Time   `json:"time"`
+	CPU  ActivityCPU `json:"cpu"`
+	// Load1 is the load average over the last minute
+	Load1                float64           `json:"load1"`
+	MemoryAvailableBytes uint64            `json:"memoryAvailableBytes"`
+	Disks                []ActivityDisk    `json:"disks,omitempty"`
+	Processes            []ActivityProcess `json:"processes,omitempty"`
+}
+
+// ActivityCPU is the percentage of the CPU time of all the CPUs spent in each state during the
+// interval, as vmstat reports it
+type ActivityCPU struct {
+	User   float64 `json:"user"`
+	System float64 `json:"system"`
+	Idle   float64 `json:"idle"`
+	IOWait float64 `json:"iowait"`
+	Steal  float64 `json:"steal"`
+}
+
+// ActivityDisk is the throughput of a disk during the interval, as iostat reports it
+type ActivityDisk struct {
+	Name                string  `json:"name"`
+	ReadBytesPerSecond  float64 `json:"readBytesPerSecond"`
+	WriteBytesPerSecond float64 `json:"writeBytesPerSecond"`
+	// Utilization is the percentage of the interval the disk was busy with requests
+	Utilization float64 `json:"utilization"`
+}
+
+// ActivityProcess is a process as ps reports it. Its CPU percentage is the average over the
+// lifetime of the process, not over the interval.
+type ActivityProcess struct {
+	PID           int     `json:"pid"`
+	State         string  `json:"state"`
+	CPUPercent    float64 `json:"cpuPercent"`
+	MemoryPercent float64 `json:"memoryPercent"`
+	Command       string  `json:"command"`
+}
+
+type CollectHostActivityCapture struct {
+	hostCollector *troubleshootv1beta2.HostActivityCapture
+	BundlePath    string
+	Context       context.Context
+	fs            fs.FS
+}
+
+func (c *CollectHostActivityCapture) Title() string {
+	return hostCollectorTitleOrDefault(c.hostCollector.HostCollectorMeta, "Activity Capture")
+}
+
+func (c *CollectHostActivityCapture) IsExcluded() (bool, error) {
+	return isExcluded(c.hostCollector.Exclude)
+}
+
+func (c *CollectHostActivityCapture) Collect(progressChan chan<- interface{}) (map[string][]byte, error) {
+	interval, duration, err := parseActivityCaptureWindow(c.hostCollector.Interval, c.hostCollector.Duration)
+	if err != nil {
+		return nil, err
+	}
+
+	processes := c.hostCollector.Processes
+	if processes < 0 {
+		return nil, errors.Errorf("processes %d must not be negative", processes)
+	}
+	if processes == 0 {
+		processes = defaultActivityCaptureProcesses
+	}
+
+	capture := activityCapture{fs: c.fs, processes: processes}
+	info, err := capture.run(collectorContext(c.Context), interval, duration)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := json.Marshal(info)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal activity capture info")
+	}
+
+	output := NewResult()
+	output.SaveResult(c.BundlePath, HostActivityCapturePath, bytes.NewBuffer(b))
+
+	return output, nil
+}
+
+func parseActivityCaptureWindow(interval string, duration string) (time.Duration, time.Duration, error) {
+	return parseSamplingWindow(interval, duration, defaultActivityCaptureInterval, defaultActivityCaptureDuration, maxActivityCaptureDuration)
+}
+
+// parseSamplingWindow parses the interval and the duration of a collector sampling the host,
+// defaulting the empty ones, and checks the duration is not longer than maxDuration
+func parseSamplingWindow(interval string, duration string, defaultInterval time.Duration, defaultDuration time.Duration, maxDuration time.Duration) (time.Duration, time.Duration, error) {
+	i, d := defaultInterval, defaultDuration
+
+	var err error
+	if interval != "" {
+		i, err = time.ParseDuration(interval)
+		if err != nil {
+			return 0, 0, errors.Wrapf(err, "failed to parse interval %q", interval)
+		}
+		if i <= 0 {
+			return 0, 0, errors.Errorf("interval %q must be positive", interval)
+		}
+	}
+	if duration != "" {
+		d, err = time.ParseDuration(duration)
+		if err != nil {
+			return 0, 0, errors.Wrapf(err, "failed to parse duration %q", duration)
+		}
+		if d <= 0 {
+			return 0, 0, errors.Errorf("duration %q must be positive", duration)
+		}
+	}
+
+	if d > maxDuration {
+		return 0, 0, errors.Errorf("duration %s must not be longer than %s", d, maxDuration)
+	}
+	if i > d {
+		return 0, 0, errors.Errorf("interval %s must not be longer than the duration %s", i, d)
+	}
+	return i, d, nil
+}
+
+// activityCapture samples the activity of the host from /proc, and the processes from ps
+type activityCapture struct {
+	fs        fs.FS
+	processes int
+	failures  []string
+}
+
+// cpuTimes are the CPU times of all the CPUs in the cpu line of /proc/stat, in clock ticks
+type cpuTimes struct {
+	user, nice, system, idle, iowait, irq, softirq, steal uint64
+}
+
+type diskCounters struct {
+	sectorsRead, sectorsWritten, ioMilliseconds uint64
+}
+
+// activitySnapshot are the counters of /proc the activity of an interval is computed from
+type activitySnapshot struct {
+	time  time.Time
+	cpu   cpuTimes
+	disks map[string]diskCounters
+}
+
+// run samples the activity every interval for the duration. The samples collected so far are
+// returned when ctx is canceled.
+func (a *activityCapture) run(ctx context.Context, interval time.Duration, duration time.Duration) (*ActivityCaptureInfo, error) {
+	info := &ActivityCaptureInfo{
+		IntervalSeconds: interval.Seconds(),
+		DurationSeconds: duration.Seconds(),
+		Samples:         []ActivitySample{},
+	}
+
+	previous, err := a.snapshot()
+	if err != nil {
+		return nil, err
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for i := 0; i < int(duration/interval); i++ {
+		select {
+		case <-ctx.Done():
+			klog.V(2).Infof("Activity capture stopped after %d samples: %v", len(info.Samples), ctx.Err())
+			info.Errors = a.failures
+			return info, nil
+		case <-ticker.C:
+		}
+
+		current, err := a.snapshot()
+		if err != nil {
+			return nil, err
+		}
+		info.Samples = append(info.Samples, a.sample(previous, current))
+		previous = current
+	}
+
+	info.Errors = a.failures
+	return info, nil
+}
+
+func (a *activityCapture) snapshot() (*activitySnapshot, error) {
+	stat, err := fs.ReadFile(a.fs, "proc/stat")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read /proc/stat")
+	}
+	cpu, err := parseProcStatCPU(stat)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &activitySnapshot{time: time.Now(), cpu: cpu}
+	diskstats, err := fs.ReadFile(a.fs, "proc/diskstats")
+	if err != nil {
+		a.addError(errors.Wrap(err, "failed to read /proc/diskstats"))
+	} else {
+		s.disks = parseDiskStats(diskstats, func(name string) bool { return isDisk(a.fs, name) })
+	}
+	return s, nil
+}
+
+func (a *activityCapture) sample(previous *activitySnapshot, current *activitySnapshot) ActivitySample {
+	sample := ActivitySample{
+		Time: current.time,
+		CPU:  cpuActivity(previous.cpu, current.cpu),
+	}
+
+	if loadavg, err := fs.ReadFile(a.fs, "proc/loadavg"); err != nil {
+		a.addError(errors.Wrap(err, "failed to read /proc/loadavg"))
+	} else if fields := strings.Fields(string(loadavg)); len(fields) > 0 {
+		sample.Load1, _ = strconv.ParseFloat(fields[0], 64)
+	}
+
+	if meminfo, err := fs.ReadFile(a.fs, "proc/meminfo"); err != nil {
+		a.addError(errors.Wrap(err, "failed to read /proc/meminfo"))
+	} else {
+		sample.MemoryAvailableBytes = parseMemAvailable(meminfo)
+	}
+
+	sample.Disks = diskActivity(previous.disks, current.disks, current.time.Sub(previous.time))
+
+	out, err := execCommand("ps", "-eo", "pid=,stat=,pcpu=,pmem=,comm=", "--sort=-pcpu").Output()
+	if err != nil {
+		a.addError(errors.Wrap(err, "failed to run ps"))
+	} else {
+		sample.Processes = parsePsProcesses(out, a.processes)
+	}
+
+	return sample
+}
+
+// isDisk returns true when a device of /proc/diskstats is a disk rather than a partition, or a
+// loop or ram device
+func isDisk(fsys fs.FS, name string) bool {
+	if strings.HasPrefix(name, "loop") || strings.HasPrefix(name, "ram") {
+		return false
+	}
+	if _, err := fs.Stat(fsys, path.Join("sys/block", name)); err != nil {
+		return false
+	}
+	return true
+}
+
+// addError records an error once, as the same part of every sample fails the same way
+func (a *activityCapture) addError(err error) {
+	for _, e := range a.failures {
+		if e == err.Error() {
+			return
+		}
+	}
+	klog.V(2).Infof("Activity capture: %v", err)
+	a.failures = append(a.failures, err.Error())
+}
+
+func parseProcStatCPU(stat []byte) (cpuTimes, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(stat))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 9 || fields[0] != "cpu" {
+			continue
+		}
+
+		values := make([]uint64, 8)
+		for i := range values {
+			v, err := strconv.ParseUint(fields[i+1], 10, 64)
+			if err != nil {
+				return cpuTimes{}, errors.Wrapf(err, "failed to parse cpu time %q", fields[i+1])
+			}
+			values[i] = v
+		}
+		return cpuTimes{
+			user:    values[0],
+			nice:    values[1],
+			system:  values[2],
+			idle:    values[3],
+			iowait:  values[4],
+			irq:     values[5],
+			softirq: values[6],
+			steal:   values[7],
+		}, nil
+	}
+	return cpuTimes{}, errors.New("cpu line not found in /proc/stat")
+}
+
+// cpuActivity returns the percentage of the CPU time spent in each state between two readings of
+// /proc/stat. The guest time is part of the user time.
+func cpuActivity(previous cpuTimes, current cpuTimes) ActivityCPU {
+	delta := func(p, c uint64) float64 {
+		if c < p {
+			return 0
+		}
+		return float64(c - p)
+	}
+
+	user := delta(previous.user, current.user) + delta(previous.nice, current.nice)
+	system := delta(previous.system, current.system) + delta(previous.irq, current.irq) + delta(previous.softirq, current.softirq)
+	idle := delta(previous.idle, current.idle)
+	iowait := delta(previous.iowait, current.iowait)
+	steal := delta(previous.steal, current.steal)
+
+	total := user + system + idle + iowait + steal
+	if total == 0 {
+		return ActivityCPU{}
+	}
+	return ActivityCPU{
+		User:   100 * user / total,
+		System: 100 * system / total,
+		Idle:   100 * idle / total,
+		IOWait: 100 * iowait / total,
+		Steal:  100 * steal / total,
+	}
+}
+
+// parseDiskStats returns the counters of the devices of /proc/diskstats that isDisk accepts
+func parseDiskStats(diskstats []byte, isDisk func(string) bool) map[string]diskCounters {
+	disks := map[string]diskCounters{}
+
+	scanner := bufio.NewScanner(bytes.NewReader(diskstats))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 13 || !isDisk(fields[2]) {
+			continue
+		}
+
+		sectorsRead, err1 := strconv.ParseUint(fields[5], 10, 64)
+		sectorsWritten, err2 := strconv.ParseUint(fields[9], 10, 64)
+		ioMilliseconds, err3 := strconv.ParseUint(fields[12], 10, 64)
+		if err1 != nil || err2 != nil || err3 != nil {
+			continue
+		}
+		disks[fields[2]] = diskCounters{
+			sectorsRead:    sectorsRead,
+			sectorsWritten: sectorsWritten,
+			ioMilliseconds: ioMilliseconds,
+		}
+	}
+
+	return disks
+}
+
+// diskActivity returns the throughput of the disks between two readings of /proc/diskstats,
+// sorted by name
+func diskActivity(previous map[string]diskCounters, current map[string]diskCounters, elapsed time.Duration) []ActivityDisk {
+	if elapsed <= 0 {
+		return nil
+	}
+
+	disks := []ActivityDisk{}
+	for name, c := range current {
+		p, ok := previous[name]
+		if !ok || c.sectorsRead < p.sectorsRead || c.sectorsWritten < p.sectorsWritten || c.ioMilliseconds < p.ioMilliseconds {
+			continue
+		}
+
+		utilization := 100 * float64(c.ioMilliseconds-p.ioMilliseconds) / float64(elapsed.Milliseconds())
+		if utilization > 100 {
+			utilization = 100
+		}
+		disks = append(disks, ActivityDisk{
+			Name:                name,
+			ReadBytesPerSecond:  float64((c.sectorsRead-p.sectorsRead)*diskSectorSize) / elapsed.Seconds(),
+			WriteBytesPerSecond: float64((c.sectorsWritten-p.sectorsWritten)*diskSectorSize) / elapsed.Seconds(),
+			Utilization:         utilization,
+		})
+	}
+
+	sort.Slice(disks, func(i, j int) bool { return disks[i].Name < disks[j].Name })
+	return disks
+}
+
+// parseMemAvailable returns the MemAvailable of /proc/meminfo in bytes
+func parseMemAvailable(meminfo []byte) uint64 {
+	scanner := bufio.NewScanner(bytes.NewReader(meminfo))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 || fields[0] != "MemAvailable:" {
+			continue
+		}
+		kb, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			return 0
+		}
+		return kb * 1024
+	}
+	return 0
+}
+
+// parsePsProcesses returns the first limit processes of the output of
+// ps -eo pid=,stat=,pcpu=,pmem=,comm=
+func parsePsProcesses(out []byte, limit int) []ActivityProcess {
+	processes := []ActivityProcess{}
+
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() && len(processes) < limit {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 5 {
+			continue
+		}
+
+		pid, err := strconv.Atoi(fields[0])
+		if err != nil {
+			continue
+		}
+		cpuPercent, _ := strconv.ParseFloat(fields[2], 64)
+		memoryPercent, _ := strconv.ParseFloat(fields[3], 64)
+		processes = append(processes, ActivityProcess{
+			PID:           pid,
+			State:         fields[1],
+			CPUPercent:    cpuPercent,
+			MemoryPercent: memoryPercent,
+			Command:       strings.Join(fields[4:], " "),
+		})
+	}
+
+	return processes
+}
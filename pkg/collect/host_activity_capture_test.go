@@ -0,0 +1,126 @@
+package collect
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_parseActivityCaptureWindow(t *testing.T) {
+	interval, duration, err := parseActivityCaptureWindow("", "")
+	require.NoError(t, err)
+	assert.Equal(t, time.Second, interval)
+	assert.Equal(t, 10*time.Second, duration)
+
+	interval, duration, err = parseActivityCaptureWindow("5s", "30s")
+	require.NoError(t, err)
+	assert.Equal(t, 5*time.Second, interval)
+	assert.Equal(t, 30*time.Second, duration)
+
+	for _, window := range [][2]string{{"", "2m"}, {"0s", ""}, {"", "-1s"}, {"20s", ""}, {"1 second", ""}} {
+		_, _, err := parseActivityCaptureWindow(window[0], window[1])
+		assert.Error(t, err, "interval %q, duration %q", window[0], window[1])
+	}
+}
+
+func Test_cpuActivity(t *testing.T) {
+	previous, err := parseProcStatCPU([]byte("cpu  100 0 50 800 20 0 0 30 0 0\ncpu0 100 0 50 800 20 0 0 30 0 0\n"))
+	require.NoError(t, err)
+	current, err := parseProcStatCPU([]byte("cpu  150 10 70 900 60 5 5 50 0 0\ncpu0 150 10 70 900 60 5 5 50 0 0\n"))
+	require.NoError(t, err)
+
+	// 60 user, 30 system, 100 idle, 40 iowait and 20 steal ticks
+	assert.Equal(t, ActivityCPU{User: 24, System: 12, Idle: 40, IOWait: 16, Steal: 8}, cpuActivity(previous, current))
+	assert.Equal(t, ActivityCPU{}, cpuActivity(current, current))
+
+	_, err = parseProcStatCPU([]byte("intr 1234\n"))
+	assert.Error(t, err)
+}
+
+func Test_diskActivity(t *testing.T) {
+	isDisk := func(name string) bool { return name == "sda" || name == "nvme0n1" }
+	previous := parseDiskStats([]byte(`   8       0 sda 100 0 2000 50 100 0 4000 80 0 300 130 0 0 0 0
+   8       1 sda1 100 0 2000 50 100 0 4000 80 0 300 130 0 0 0 0
+ 259       0 nvme0n1 10 0 20 1 0 0 0 0 0 5 1 0 0 0 0
+`), isDisk)
+	assert.Len(t, previous, 2)
+
+	current := parseDiskStats([]byte(`   8       0 sda 200 0 4048 60 300 0 12192 90 0 800 150 0 0 0 0
+ 259       0 nvme0n1 10 0 20 1 0 0 0 0 0 5 1 0 0 0 0
+`), isDisk)
+
+	assert.Equal(t, []ActivityDisk{
+		{Name: "nvme0n1"},
+		{Name: "sda", ReadBytesPerSecond: 1048576, WriteBytesPerSecond: 4194304, Utilization: 50},
+	}, diskActivity(previous, current, time.Second))
+}
+
+func Test_parsePsProcesses(t *testing.T) {
+	out := []byte(`   1234 Rl   85.5  2.1 etcd
+    567 D     3.0  0.4 kworker/u8:2 flush
+      1 Ss    0.1  0.2 systemd
+`)
+
+	assert.Equal(t, []ActivityProcess{
+		{PID: 1234, State: "Rl", CPUPercent: 85.5, MemoryPercent: 2.1, Command: "etcd"},
+		{PID: 567, State: "D", CPUPercent: 3, MemoryPercent: 0.4, Command: "kworker/u8:2 flush"},
+	}, parsePsProcesses(out, 2))
+}
+
+func Test_parseMemAvailable(t *testing.T) {
+	assert.Equal(t, uint64(2048*1024), parseMemAvailable([]byte("MemTotal:       8000 kB\nMemAvailable:   2048 kB\n")))
+	assert.Equal(t, uint64(0), parseMemAvailable([]byte("MemTotal:       8000 kB\n")))
+}
+
+// procStatFS returns a different /proc/stat at each read, with 10 ticks of steal out of 100
+type procStatFS struct {
+	fstest.MapFS
+	reads uint64
+}
+
+func (f *procStatFS) ReadFile(name string) ([]byte, error) {
+	if name != "proc/stat" {
+		return f.MapFS.ReadFile(name)
+	}
+	f.reads++
+	n := f.reads
+	return []byte(fmt.Sprintf("cpu  %d 0 %d %d 0 0 0 %d 0 0\n", 50*n, 20*n, 20*n, 10*n)), nil
+}
+
+func Test_activityCapture_run(t *testing.T) {
+	original := execCommand
+	t.Cleanup(func() { execCommand = original })
+	execCommand = func(name string, args ...string) *exec.Cmd {
+		return exec.Command("printf", "    42 R   12.0  1.0 dd\\n")
+	}
+
+	fsys := &procStatFS{MapFS: fstest.MapFS{
+		"proc/loadavg": {Data: []byte("1.50 1.00 0.50 2/300 4000\n")},
+		"proc/meminfo": {Data: []byte("MemAvailable:   1024 kB\n")},
+	}}
+
+	capture := activityCapture{fs: fsys, processes: 10}
+	info, err := capture.run(context.Background(), 10*time.Millisecond, 30*time.Millisecond)
+	require.NoError(t, err)
+
+	require.Len(t, info.Samples, 3)
+	for _, sample := range info.Samples {
+		assert.Equal(t, ActivityCPU{User: 50, System: 20, Idle: 20, Steal: 10}, sample.CPU)
+		assert.Equal(t, 1.5, sample.Load1)
+		assert.Equal(t, uint64(1024*1024), sample.MemoryAvailableBytes)
+		assert.Equal(t, []ActivityProcess{{PID: 42, State: "R", CPUPercent: 12, MemoryPercent: 1, Command: "dd"}}, sample.Processes)
+	}
+	assert.Equal(t, []string{"failed to read /proc/diskstats: open proc/diskstats: file does not exist"}, info.Errors)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	info, err = capture.run(ctx, 10*time.Millisecond, 30*time.Millisecond)
+	require.NoError(t, err)
+	assert.Empty(t, info.Samples)
+}
@@ -0,0 +1,171 @@
+package collect
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+)
+
+// Ensure `CollectHostBenchmark` implements `HostCollector` interface at compile time.
+var _ HostCollector = (*CollectHostBenchmark)(nil)
+
+const HostBenchmarkPath = `host-collectors/system/benchmark.json`
+const HostBenchmarkFileName = `benchmark.json`
+
+const (
+	defaultBenchmarkDuration = 2 * time.Second
+	maxBenchmarkDuration     = 10 * time.Second
+	// benchmarkMaxPrime bounds the primes verified by each event of the CPU benchmark. It is the
+	// default of sysbench cpu, so that the events per second are in the same range.
+	benchmarkMaxPrime = 10000
+	// benchmarkMemoryBlockSize is the size of the buffer copied by the memory benchmark, larger
+	// than the CPU caches so that the copies go to memory
+	benchmarkMemoryBlockSize = 64 * 1024 * 1024
+)
+
+// BenchmarkInfo is the output of the benchmark collector
+type BenchmarkInfo struct {
+	// DurationSeconds is the duration of each of the benchmarks
+	DurationSeconds float64 `json:"durationSeconds"`
+	Threads         int     `json:"threads"`
+	// SingleThreadEventsPerSecond and MultiThreadEventsPerSecond are the events of the CPU
+	// benchmark per second, each event verifying the primes up to 10000
+	SingleThreadEventsPerSecond float64 `json:"singleThreadEventsPerSecond"`
+	MultiThreadEventsPerSecond  float64 `json:"multiThreadEventsPerSecond"`
+	// MemoryBytesPerSecond is the number of bytes copied in memory per second
+	MemoryBytesPerSecond float64 `json:"memoryBytesPerSecond"`
+}
+
+type CollectHostBenchmark struct {
+	hostCollector *troubleshootv1beta2.HostBenchmark
+	BundlePath    string
+}
+
+func (c *CollectHostBenchmark) Title() string {
+	return hostCollectorTitleOrDefault(c.hostCollector.HostCollectorMeta, "Benchmark")
+}
+
+func (c *CollectHostBenchmark) IsExcluded() (bool, error) {
+	return isExcluded(c.hostCollector.Exclude)
+}
+
+func (c *CollectHostBenchmark) Collect(progressChan chan<- interface{}) (map[string][]byte, error) {
+	duration, err := parseBenchmarkDuration(c.hostCollector.Duration)
+	if err != nil {
+		return nil, err
+	}
+
+	threads := c.hostCollector.Threads
+	if threads < 0 {
+		return nil, errors.Errorf("threads %d must not be negative", threads)
+	}
+	if threads == 0 {
+		threads = runtime.NumCPU()
+	}
+
+	info := &BenchmarkInfo{
+		DurationSeconds:             duration.Seconds(),
+		Threads:                     threads,
+		SingleThreadEventsPerSecond: benchmarkCPU(1, duration),
+		MultiThreadEventsPerSecond:  benchmarkCPU(threads, duration),
+		MemoryBytesPerSecond:        benchmarkMemory(duration),
+	}
+
+	b, err := json.Marshal(info)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal benchmark info")
+	}
+
+	output := NewResult()
+	output.SaveResult(c.BundlePath, HostBenchmarkPath, bytes.NewBuffer(b))
+
+	return output, nil
+}
+
+func parseBenchmarkDuration(duration string) (time.Duration, error) {
+	if duration == "" {
+		return defaultBenchmarkDuration, nil
+	}
+
+	d, err := time.ParseDuration(duration)
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to parse duration %q", duration)
+	}
+	if d <= 0 {
+		return 0, errors.Errorf("duration %q must be positive", duration)
+	}
+	if d > maxBenchmarkDuration {
+		return 0, errors.Errorf("duration %q must not be longer than %s", duration, maxBenchmarkDuration)
+	}
+	return d, nil
+}
+
+// benchmarkCPU runs events of the CPU benchmark on a number of threads for a duration, and
+// returns the number of events per second
+func benchmarkCPU(threads int, duration time.Duration) float64 {
+	start := time.Now()
+	deadline := start.Add(duration)
+
+	var wg sync.WaitGroup
+	events := make([]int, threads)
+	for i := 0; i < threads; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for time.Now().Before(deadline) {
+				cpuBenchmarkEvent()
+				events[i]++
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	total := 0
+	for _, e := range events {
+		total += e
+	}
+	return float64(total) / time.Since(start).Seconds()
+}
+
+// cpuBenchmarkEvent verifies the numbers up to benchmarkMaxPrime by trial division, the way
+// sysbench cpu does, and returns the number of primes found
+func cpuBenchmarkEvent() int {
+	primes := 0
+	for c := 3; c < benchmarkMaxPrime; c++ {
+		t := int(math.Sqrt(float64(c)))
+		l := 2
+		for ; l <= t; l++ {
+			if c%l == 0 {
+				break
+			}
+		}
+		if l > t {
+			primes++
+		}
+	}
+	return primes
+}
+
+// benchmarkMemory copies a buffer in memory for a duration, and returns the number of bytes
+// copied per second
+func benchmarkMemory(duration time.Duration) float64 {
+	src := make([]byte, benchmarkMemoryBlockSize)
+	dst := make([]byte, benchmarkMemoryBlockSize)
+	for i := range src {
+		src[i] = byte(i)
+	}
+
+	start := time.Now()
+	deadline := start.Add(duration)
+	copied := 0
+	for time.Now().Before(deadline) {
+		copied += copy(dst, src)
+	}
+	return float64(copied) / time.Since(start).Seconds()
+}
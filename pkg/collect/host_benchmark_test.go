@@ -0,0 +1,71 @@
+package collect
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_cpuBenchmarkEvent(t *testing.T) {
+	// the primes below 10000, but 2
+	assert.Equal(t, 1228, cpuBenchmarkEvent())
+}
+
+func Test_parseBenchmarkDuration(t *testing.T) {
+	tests := []struct {
+		duration string
+		want     time.Duration
+		wantErr  string
+	}{
+		{duration: "", want: 2 * time.Second},
+		{duration: "500ms", want: 500 * time.Millisecond},
+		{duration: "10s", want: 10 * time.Second},
+		{duration: "11s", wantErr: `duration "11s" must not be longer than 10s`},
+		{duration: "0s", wantErr: `duration "0s" must be positive`},
+		{duration: "two seconds", wantErr: `failed to parse duration "two seconds"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.duration, func(t *testing.T) {
+			got, err := parseBenchmarkDuration(tt.duration)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCollectHostBenchmark(t *testing.T) {
+	c := &CollectHostBenchmark{
+		hostCollector: &troubleshootv1beta2.HostBenchmark{Duration: "50ms", Threads: 2},
+		BundlePath:    "",
+	}
+
+	result, err := c.Collect(nil)
+	require.NoError(t, err)
+	require.Contains(t, result, HostBenchmarkPath)
+
+	info := BenchmarkInfo{}
+	require.NoError(t, json.Unmarshal(result[HostBenchmarkPath], &info))
+	assert.Equal(t, 0.05, info.DurationSeconds)
+	assert.Equal(t, 2, info.Threads)
+	assert.Greater(t, info.SingleThreadEventsPerSecond, float64(0))
+	assert.Greater(t, info.MultiThreadEventsPerSecond, float64(0))
+	assert.Greater(t, info.MemoryBytesPerSecond, float64(0))
+}
+
+func TestCollectHostBenchmark_NegativeThreads(t *testing.T) {
+	c := &CollectHostBenchmark{
+		hostCollector: &troubleshootv1beta2.HostBenchmark{Threads: -1},
+	}
+
+	_, err := c.Collect(nil)
+	assert.EqualError(t, err, "threads -1 must not be negative")
+}
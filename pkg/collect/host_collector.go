@@ -13,8 +13,6 @@ import (
 	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
 	"github.com/replicatedhq/troubleshoot/pkg/constants"
 	"golang.org/x/sync/errgroup"
-	corev1 "k8s.io/api/core/v1"
-	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apiserver/pkg/storage/names"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
@@ -41,6 +39,12 @@ type RemoteCollectParams struct {
 }
 
 func GetHostCollector(collector *troubleshootv1beta2.HostCollect, bundlePath string) (HostCollector, bool) {
+	return GetHostCollectorWithContext(context.TODO(), collector, bundlePath)
+}
+
+// GetHostCollectorWithContext is GetHostCollector with the context the collector runs with. The
+// collectors that run commands or send requests stop when the context is done.
+func GetHostCollectorWithContext(ctx context.Context, collector *troubleshootv1beta2.HostCollect, bundlePath string) (HostCollector, bool) {
 	switch {
 	case collector.CPU != nil:
 		return &CollectHostCPU{collector.CPU, bundlePath}, true
@@ -57,7 +61,7 @@ func GetHostCollector(collector *troubleshootv1beta2.HostCollect, bundlePath str
 	case collector.UDPPortStatus != nil:
 		return &CollectHostUDPPortStatus{collector.UDPPortStatus, bundlePath}, true
 	case collector.HTTP != nil:
-		return &CollectHostHTTP{collector.HTTP, bundlePath}, true
+		return &CollectHostHTTP{collector.HTTP, bundlePath, ctx}, true
 	case collector.Time != nil:
 		return &CollectHostTime{collector.Time, bundlePath}, true
 	case collector.BlockDevices != nil:
@@ -80,7 +84,7 @@ func GetHostCollector(collector *troubleshootv1beta2.HostCollect, bundlePath str
 	case collector.SubnetAvailable != nil:
 		return &CollectHostSubnetAvailable{collector.SubnetAvailable, bundlePath}, true
 	case collector.FilesystemPerformance != nil:
-		return &CollectHostFilesystemPerformance{collector.FilesystemPerformance, bundlePath}, true
+		return &CollectHostFilesystemPerformance{collector.FilesystemPerformance, bundlePath, ctx}, true
 	case collector.Certificate != nil:
 		return &CollectHostCertificate{collector.Certificate, bundlePath}, true
 	case collector.CertificatesCollection != nil:
@@ -90,13 +94,13 @@ func GetHostCollector(collector *troubleshootv1beta2.HostCollect, bundlePath str
 	case collector.HostOS != nil:
 		return &CollectHostOS{collector.HostOS, bundlePath}, true
 	case collector.HostRun != nil:
-		return &CollectHostRun{collector.HostRun, bundlePath}, true
+		return &CollectHostRun{collector.HostRun, bundlePath, ctx}, true
 	case collector.HostCopy != nil:
 		return &CollectHostCopy{collector.HostCopy, bundlePath}, true
 	case collector.HostKernelConfigs != nil:
 		return &CollectHostKernelConfigs{collector.HostKernelConfigs, bundlePath}, true
 	case collector.HostJournald != nil:
-		return &CollectHostJournald{collector.HostJournald, bundlePath}, true
+		return &CollectHostJournald{collector.HostJournald, bundlePath, ctx}, true
 	case collector.HostCGroups != nil:
 		return &CollectHostCGroups{collector.HostCGroups, bundlePath}, true
 	case collector.HostDNS != nil:
@@ -105,6 +109,87 @@ func GetHostCollector(collector *troubleshootv1beta2.HostCollect, bundlePath str
 		return &CollectHostNetworkNamespaceConnectivity{collector.NetworkNamespaceConnectivity, bundlePath}, true
 	case collector.HostSysctl != nil:
 		return &CollectHostSysctl{collector.HostSysctl, bundlePath}, true
+	case collector.HostKernelSnapshot != nil:
+		return &CollectHostKernelSnapshot{
+			hostCollector: collector.HostKernelSnapshot,
+			BundlePath:    bundlePath,
+			fs:            os.DirFS("/"),
+			loaded: kernelModulesLoaded{
+				fs: os.DirFS("/"),
+			},
+		}, true
+	case collector.HostSystemdUnits != nil:
+		return &CollectHostSystemdUnits{collector.HostSystemdUnits, bundlePath}, true
+	case collector.HostNetworkConfig != nil:
+		return &CollectHostNetworkConfig{
+			hostCollector: collector.HostNetworkConfig,
+			BundlePath:    bundlePath,
+			fs:            os.DirFS("/"),
+		}, true
+	case collector.HostGPU != nil:
+		return &CollectHostGPU{
+			hostCollector: collector.HostGPU,
+			BundlePath:    bundlePath,
+			fs:            os.DirFS("/"),
+		}, true
+	case collector.HostTimeSync != nil:
+		return &CollectHostTimeSync{collector.HostTimeSync, bundlePath}, true
+	case collector.HostKubernetesDistribution != nil:
+		return &CollectHostKubernetesDistribution{collector.HostKubernetesDistribution, bundlePath}, true
+	case collector.HostBenchmark != nil:
+		return &CollectHostBenchmark{collector.HostBenchmark, bundlePath}, true
+	case collector.HostPaths != nil:
+		return &CollectHostPaths{
+			hostCollector: collector.HostPaths,
+			BundlePath:    bundlePath,
+			fs:            os.DirFS("/"),
+		}, true
+	case collector.HostSecurity != nil:
+		return &CollectHostSecurity{
+			hostCollector: collector.HostSecurity,
+			BundlePath:    bundlePath,
+			fs:            os.DirFS("/"),
+		}, true
+	case collector.HostProxy != nil:
+		return &CollectHostProxy{
+			hostCollector: collector.HostProxy,
+			BundlePath:    bundlePath,
+			fs:            os.DirFS("/"),
+		}, true
+	case collector.HostActivityCapture != nil:
+		return &CollectHostActivityCapture{
+			hostCollector: collector.HostActivityCapture,
+			BundlePath:    bundlePath,
+			Context:       ctx,
+			fs:            os.DirFS("/"),
+		}, true
+	case collector.HostKernelLogs != nil:
+		return &CollectHostKernelLogs{collector.HostKernelLogs, bundlePath, ctx}, true
+	case collector.HostConnectionStats != nil:
+		return &CollectHostConnectionStats{
+			hostCollector: collector.HostConnectionStats,
+			BundlePath:    bundlePath,
+			fs:            os.DirFS("/"),
+		}, true
+	case collector.HostKubeletConfig != nil:
+		return &CollectHostKubeletConfig{
+			hostCollector: collector.HostKubeletConfig,
+			BundlePath:    bundlePath,
+			fs:            os.DirFS("/"),
+		}, true
+	case collector.HostMetrics != nil:
+		return &CollectHostMetrics{
+			hostCollector: collector.HostMetrics,
+			BundlePath:    bundlePath,
+			Context:       ctx,
+			fs:            os.DirFS("/"),
+		}, true
+	case collector.HostCRIRuntime != nil:
+		return &CollectHostCRIRuntime{
+			hostCollector: collector.HostCRIRuntime,
+			BundlePath:    bundlePath,
+			fs:            os.DirFS("/"),
+		}, true
 	default:
 		return nil, false
 	}
@@ -118,35 +203,7 @@ func hostCollectorTitleOrDefault(meta troubleshootv1beta2.HostCollectorMeta, def
 }
 
 func RemoteHostCollect(ctx context.Context, params RemoteCollectParams) (map[string][]byte, error) {
-	scheme := runtime.NewScheme()
-	if err := corev1.AddToScheme(scheme); err != nil {
-		return nil, errors.Wrap(err, "failed to add runtime scheme")
-	}
-
-	client, err := kubernetes.NewForConfig(params.ClientConfig)
-	if err != nil {
-		return nil, err
-	}
-
-	runner := &podRunner{
-		client:       client,
-		scheme:       scheme,
-		image:        params.Image,
-		pullPolicy:   params.PullPolicy,
-		waitInterval: remoteCollectorDefaultInterval,
-	}
-
-	// Get all the nodes where we should run.
-	nodes, err := listNodesNamesInSelector(ctx, client, params.LabelSelector)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to get the list of nodes matching a nodeSelector")
-	}
-
-	if params.NamePrefix == "" {
-		params.NamePrefix = remoteCollectorNamePrefix
-	}
-
-	result, err := runRemote(ctx, runner, nodes, params.HostCollector, names.SimpleNameGenerator, params.NamePrefix, params.Namespace)
+	nodes, result, err := runRemoteHostCollectors(ctx, params, []*troubleshootv1beta2.HostCollect{params.HostCollector})
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to run collector remotely")
 	}
@@ -182,7 +239,111 @@ func RemoteHostCollect(ctx context.Context, params RemoteCollectParams) (map[str
 	return output, nil
 }
 
-func runRemote(ctx context.Context, runner runner, nodes []string, collector *troubleshootv1beta2.HostCollect, nameGenerator names.NameGenerator, namePrefix string, namespace string) (map[string][]byte, error) {
+// RemoteHostCollectNodes runs the host collectors on every node matching the label selector in a
+// single DaemonSet, and returns the collected files of each node keyed by node name.
+func RemoteHostCollectNodes(ctx context.Context, params RemoteCollectParams, collectors []*troubleshootv1beta2.HostCollect) (map[string]map[string][]byte, error) {
+	_, result, err := runRemoteHostCollectors(ctx, params, collectors)
+	if err != nil {
+		return nil, err
+	}
+
+	nodeFiles := make(map[string]map[string][]byte, len(result))
+	for node, data := range result {
+		var nodeResult map[string]string
+		if err := json.Unmarshal(data, &nodeResult); err != nil {
+			return nil, errors.Wrapf(err, "failed to unmarshal results of node %s", node)
+		}
+
+		nodeFiles[node] = make(map[string][]byte, len(nodeResult))
+		for file, collectorResult := range nodeResult {
+			nodeFiles[node][file] = []byte(collectorResult)
+		}
+	}
+
+	return nodeFiles, nil
+}
+
+// runRemoteHostCollectors runs the host collectors on every node matching the label selector in a
+// single DaemonSet, and returns the selected nodes and the raw results of each node keyed by node name.
+func runRemoteHostCollectors(ctx context.Context, params RemoteCollectParams, collectors []*troubleshootv1beta2.HostCollect) ([]string, map[string][]byte, error) {
+	var result map[string][]byte
+	var runErr error
+	nodes, err := runRemoteHostCollectorBatches(ctx, params, []remoteHostCollectorBatch{{collectors: collectors}}, func(_ int, batchResult map[string][]byte, err error) {
+		result, runErr = batchResult, err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	if runErr != nil {
+		return nil, nil, runErr
+	}
+	return nodes, result, nil
+}
+
+// remoteHostCollectorBatch is host collectors run together on each node, in one exec, with the
+// timeout of the batch. A timeout of 0 does not limit the batch.
+type remoteHostCollectorBatch struct {
+	collectors []*troubleshootv1beta2.HostCollect
+	timeout    time.Duration
+}
+
+// runRemoteHostCollectorBatches runs the batches of host collectors one after the other in a
+// single DaemonSet on every node matching the label selector, so that a batch that times out does
+// not take the time of the others. onResult is called with the raw results of each batch keyed by
+// node name, or with the error of the batch. The selected nodes are returned.
+func runRemoteHostCollectorBatches(ctx context.Context, params RemoteCollectParams, batches []remoteHostCollectorBatch, onResult func(batch int, result map[string][]byte, err error)) ([]string, error) {
+	if params.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, params.Timeout)
+		defer cancel()
+	}
+
+	client, err := kubernetes.NewForConfig(params.ClientConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	// Get all the nodes where we should run.
+	nodes, err := listNodesNamesInSelector(ctx, client, params.LabelSelector)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get the list of nodes matching a nodeSelector")
+	}
+	if len(nodes) == 0 {
+		for i := range batches {
+			onResult(i, map[string][]byte{}, nil)
+		}
+		return nodes, nil
+	}
+
+	if params.NamePrefix == "" {
+		params.NamePrefix = remoteCollectorNamePrefix
+	}
+	if params.Namespace == "" {
+		params.Namespace = remoteCollectorDefaultNamespace
+	}
+
+	runner := newDaemonSetRunner(client, params.ClientConfig, params.Image, params.PullPolicy)
+	defer runner.stop()
+
+	if err := runner.start(ctx, params.Namespace, names.SimpleNameGenerator.GenerateName(params.NamePrefix+"-"), nodes); err != nil {
+		return nil, errors.Wrap(err, "failed to start remote collectors")
+	}
+
+	for i, batch := range batches {
+		batchCtx, cancel := ctx, context.CancelFunc(func() {})
+		if batch.timeout > 0 {
+			batchCtx, cancel = context.WithTimeout(ctx, batch.timeout)
+		}
+		result, err := runRemote(batchCtx, runner, nodes, batch.collectors, names.SimpleNameGenerator, params.NamePrefix, params.Namespace)
+		cancel()
+		onResult(i, result, err)
+	}
+
+	return nodes, nil
+}
+
+// runRemote runs the collectors on all nodes in parallel.
+func runRemote(ctx context.Context, runner runner, nodes []string, collectors []*troubleshootv1beta2.HostCollect, nameGenerator names.NameGenerator, namePrefix string, namespace string) (map[string][]byte, error) {
 	g, ctx := errgroup.WithContext(ctx)
 	results := make(chan map[string][]byte, len(nodes))
 
@@ -192,7 +353,7 @@ func runRemote(ctx context.Context, runner runner, nodes []string, collector *tr
 			// May need to evaluate error and log warning.  Otherwise any error
 			// here will cancel the context of other goroutines and no results
 			// will be returned.
-			return runner.run(ctx, collector, namespace, nameGenerator.GenerateName(namePrefix+"-"), node, results)
+			return runner.run(ctx, collectors, namespace, nameGenerator.GenerateName(namePrefix+"-"), node, results)
 		})
 	}
 
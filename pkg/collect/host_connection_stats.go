@@ -0,0 +1,369 @@
+package collect
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"k8s.io/klog/v2"
+)
+
+// Ensure `CollectHostConnectionStats` implements `HostCollector` interface at compile time.
+var _ HostCollector = (*CollectHostConnectionStats)(nil)
+
+const HostConnectionStatsPath = `host-collectors/system/connection-stats.json`
+const HostConnectionStatsFileName = `connection-stats.json`
+
+// tcpStates are the names of the states of /proc/net/tcp, by their number
+var tcpStates = map[int64]string{
+	0x01: "ESTABLISHED",
+	0x02: "SYN_SENT",
+	0x03: "SYN_RECV",
+	0x04: "FIN_WAIT1",
+	0x05: "FIN_WAIT2",
+	0x06: "TIME_WAIT",
+	0x07: "CLOSE",
+	0x08: "CLOSE_WAIT",
+	0x09: "LAST_ACK",
+	0x0A: "LISTEN",
+	0x0B: "CLOSING",
+	0x0C: "NEW_SYN_RECV",
+}
+
+// ConnectionStatsInfo is the output of the connection stats collector. The sockets are those of
+// the network namespace of the host, the connections of pods with their own network namespace
+// are only counted in the conntrack table.
+type ConnectionStatsInfo struct {
+	Conntrack      ConntrackStats     `json:"conntrack"`
+	Sockets        SocketStats        `json:"sockets"`
+	EphemeralPorts EphemeralPortStats `json:"ephemeralPorts"`
+	TCPStates      map[string]int     `json:"tcpStates"`
+	TCPExt         map[string]uint64  `json:"tcpExt,omitempty"`
+	Errors         []string           `json:"errors,omitempty"`
+}
+
+// ConntrackStats is the usage of the conntrack table. Available is false when the nf_conntrack
+// module is not loaded.
+type ConntrackStats struct {
+	Available bool  `json:"available"`
+	Count     int64 `json:"count"`
+	Max       int64 `json:"max"`
+	// Drop, EarlyDrop and InsertFailed are the totals of the CPUs since boot
+	Drop         uint64 `json:"drop"`
+	EarlyDrop    uint64 `json:"earlyDrop"`
+	InsertFailed uint64 `json:"insertFailed"`
+}
+
+// SocketStats are the counts of /proc/net/sockstat and /proc/net/sockstat6, as ss -s reports them
+type SocketStats struct {
+	Used        int `json:"used"`
+	TCPInUse    int `json:"tcpInUse"`
+	TCPOrphan   int `json:"tcpOrphan"`
+	TCPTimeWait int `json:"tcpTimeWait"`
+	TCPAlloc    int `json:"tcpAlloc"`
+	TCP6InUse   int `json:"tcp6InUse"`
+	UDPInUse    int `json:"udpInUse"`
+	UDP6InUse   int `json:"udp6InUse"`
+}
+
+// EphemeralPortStats is the usage of the local port range by TCP connections. A connection needs
+// a local port that is not used by another connection to the same destination, so the range is
+// exhausted per destination rather than for the host.
+type EphemeralPortStats struct {
+	RangeStart int `json:"rangeStart"`
+	RangeEnd   int `json:"rangeEnd"`
+	// InUse is the number of distinct local ports of the range used by connections
+	InUse int `json:"inUse"`
+	// MaxPerDestination is the most connections from a local port of the range to a single
+	// destination, BusiestDestination
+	MaxPerDestination  int    `json:"maxPerDestination"`
+	BusiestDestination string `json:"busiestDestination,omitempty"`
+}
+
+// Size returns the number of ports in the range, 0 when it was not collected
+func (s EphemeralPortStats) Size() int {
+	if s.RangeStart <= 0 || s.RangeEnd < s.RangeStart {
+		return 0
+	}
+	return s.RangeEnd - s.RangeStart + 1
+}
+
+type CollectHostConnectionStats struct {
+	hostCollector *troubleshootv1beta2.HostConnectionStats
+	BundlePath    string
+	fs            fs.FS
+}
+
+func (c *CollectHostConnectionStats) Title() string {
+	return hostCollectorTitleOrDefault(c.hostCollector.HostCollectorMeta, "Connection Stats")
+}
+
+func (c *CollectHostConnectionStats) IsExcluded() (bool, error) {
+	return isExcluded(c.hostCollector.Exclude)
+}
+
+func (c *CollectHostConnectionStats) Collect(progressChan chan<- interface{}) (map[string][]byte, error) {
+	info := collectConnectionStats(c.fs)
+
+	b, err := json.Marshal(info)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal connection stats")
+	}
+
+	output := NewResult()
+	output.SaveResult(c.BundlePath, HostConnectionStatsPath, bytes.NewBuffer(b))
+
+	return output, nil
+}
+
+// collectConnectionStats reads the statistics from /proc. Each statistic is best effort, the
+// files that could not be read are reported in the errors.
+func collectConnectionStats(fsys fs.FS) ConnectionStatsInfo {
+	info := ConnectionStatsInfo{TCPStates: map[string]int{}}
+	addError := func(err error) {
+		klog.V(2).Infof("connection stats: %v", err)
+		info.Errors = append(info.Errors, err.Error())
+	}
+
+	if count, err := readProcInt(fsys, "proc/sys/net/netfilter/nf_conntrack_count"); err == nil {
+		info.Conntrack.Available = true
+		info.Conntrack.Count = count
+		if info.Conntrack.Max, err = readProcInt(fsys, "proc/sys/net/netfilter/nf_conntrack_max"); err != nil {
+			addError(err)
+		}
+		if data, err := fs.ReadFile(fsys, "proc/net/stat/nf_conntrack"); err != nil {
+			addError(errors.Wrap(err, "failed to read /proc/net/stat/nf_conntrack"))
+		} else {
+			stats := parseConntrackStats(data)
+			info.Conntrack.Drop, info.Conntrack.EarlyDrop, info.Conntrack.InsertFailed = stats["drop"], stats["early_drop"], stats["insert_failed"]
+		}
+	} else {
+		klog.V(2).Infof("conntrack is not available: %v", err)
+	}
+
+	for _, name := range []string{"sockstat", "sockstat6"} {
+		data, err := fs.ReadFile(fsys, "proc/net/"+name)
+		if err != nil {
+			addError(errors.Wrapf(err, "failed to read /proc/net/%s", name))
+			continue
+		}
+		parseSockstat(data, &info.Sockets)
+	}
+
+	if data, err := fs.ReadFile(fsys, "proc/sys/net/ipv4/ip_local_port_range"); err != nil {
+		addError(errors.Wrap(err, "failed to read /proc/sys/net/ipv4/ip_local_port_range"))
+	} else if fields := strings.Fields(string(data)); len(fields) == 2 {
+		info.EphemeralPorts.RangeStart, _ = strconv.Atoi(fields[0])
+		info.EphemeralPorts.RangeEnd, _ = strconv.Atoi(fields[1])
+	}
+
+	connections := []tcpConnection{}
+	for _, name := range []string{"tcp", "tcp6"} {
+		data, err := fs.ReadFile(fsys, "proc/net/"+name)
+		if err != nil {
+			addError(errors.Wrapf(err, "failed to read /proc/net/%s", name))
+			continue
+		}
+		connections = append(connections, parseProcNetTCP(data)...)
+	}
+	for _, connection := range connections {
+		info.TCPStates[connection.state]++
+	}
+	ephemeralPortUsage(connections, &info.EphemeralPorts)
+
+	if data, err := fs.ReadFile(fsys, "proc/net/netstat"); err != nil {
+		addError(errors.Wrap(err, "failed to read /proc/net/netstat"))
+	} else {
+		info.TCPExt = parseNetstat(data)["TcpExt"]
+	}
+
+	return info
+}
+
+func readProcInt(fsys fs.FS, name string) (int64, error) {
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to read /%s", name)
+	}
+	value, err := strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64)
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to parse /%s", name)
+	}
+	return value, nil
+}
+
+// parseConntrackStats sums the hexadecimal counters of the CPUs in /proc/net/stat/nf_conntrack,
+// by the name of their column in the header
+func parseConntrackStats(data []byte) map[string]uint64 {
+	stats := map[string]uint64{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	if !scanner.Scan() {
+		return stats
+	}
+	header := strings.Fields(scanner.Text())
+	for scanner.Scan() {
+		for i, field := range strings.Fields(scanner.Text()) {
+			if i >= len(header) {
+				break
+			}
+			if value, err := strconv.ParseUint(field, 16, 64); err == nil {
+				stats[header[i]] += value
+			}
+		}
+	}
+	return stats
+}
+
+// parseSockstat adds the counts of /proc/net/sockstat or /proc/net/sockstat6 to the stats
+func parseSockstat(data []byte, stats *SocketStats) {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 3 {
+			continue
+		}
+		values := map[string]int{}
+		for i := 1; i+1 < len(fields); i += 2 {
+			values[fields[i]], _ = strconv.Atoi(fields[i+1])
+		}
+		switch fields[0] {
+		case "sockets:":
+			stats.Used += values["used"]
+		case "TCP:":
+			stats.TCPInUse += values["inuse"]
+			stats.TCPOrphan += values["orphan"]
+			stats.TCPTimeWait += values["tw"]
+			stats.TCPAlloc += values["alloc"]
+		case "TCP6:":
+			stats.TCP6InUse += values["inuse"]
+		case "UDP:":
+			stats.UDPInUse += values["inuse"]
+		case "UDP6:":
+			stats.UDP6InUse += values["inuse"]
+		}
+	}
+}
+
+// tcpConnection is a socket of /proc/net/tcp or /proc/net/tcp6
+type tcpConnection struct {
+	localPort   int
+	destination string
+	state       string
+}
+
+// parseProcNetTCP parses the sockets of /proc/net/tcp or /proc/net/tcp6, e.g.
+// "0: 0100007F:0CEA 0200007F:1F90 01 ...", skipping the lines it cannot parse
+func parseProcNetTCP(data []byte) []tcpConnection {
+	connections := []tcpConnection{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 4 || !strings.HasSuffix(fields[0], ":") {
+			continue
+		}
+
+		local := strings.Split(fields[1], ":")
+		if len(local) != 2 {
+			continue
+		}
+		localPort, err := strconv.ParseInt(local[1], 16, 32)
+		if err != nil {
+			continue
+		}
+		destination, err := parseProcNetAddress(fields[2])
+		if err != nil {
+			continue
+		}
+		state, err := strconv.ParseInt(fields[3], 16, 32)
+		if err != nil {
+			continue
+		}
+
+		name, ok := tcpStates[state]
+		if !ok {
+			name = fmt.Sprintf("UNKNOWN(%d)", state)
+		}
+		connections = append(connections, tcpConnection{localPort: int(localPort), destination: destination, state: name})
+	}
+	return connections
+}
+
+// parseProcNetAddress parses an address of /proc/net/tcp, whose IP is written as 32 bit words in
+// host byte order, e.g. "0100007F:1F90" is 127.0.0.1:8080
+func parseProcNetAddress(address string) (string, error) {
+	parts := strings.Split(address, ":")
+	if len(parts) != 2 {
+		return "", errors.Errorf("invalid address %q", address)
+	}
+	b, err := hex.DecodeString(parts[0])
+	if err != nil || (len(b) != net.IPv4len && len(b) != net.IPv6len) {
+		return "", errors.Errorf("invalid address %q", address)
+	}
+	port, err := strconv.ParseInt(parts[1], 16, 32)
+	if err != nil {
+		return "", errors.Errorf("invalid address %q", address)
+	}
+
+	ip := make(net.IP, len(b))
+	for i := 0; i < len(b); i += 4 {
+		binary.BigEndian.PutUint32(ip[i:], binary.LittleEndian.Uint32(b[i:]))
+	}
+	return net.JoinHostPort(ip.String(), strconv.Itoa(int(port))), nil
+}
+
+// ephemeralPortUsage counts the local ports of the range used by the connections, and the most
+// used by a single destination
+func ephemeralPortUsage(connections []tcpConnection, stats *EphemeralPortStats) {
+	if stats.Size() == 0 {
+		return
+	}
+
+	ports := map[int]struct{}{}
+	byDestination := map[string]int{}
+	for _, connection := range connections {
+		if connection.state == "LISTEN" || connection.localPort < stats.RangeStart || connection.localPort > stats.RangeEnd {
+			continue
+		}
+		ports[connection.localPort] = struct{}{}
+		byDestination[connection.destination]++
+	}
+
+	stats.InUse = len(ports)
+	for destination, count := range byDestination {
+		if count > stats.MaxPerDestination || (count == stats.MaxPerDestination && destination < stats.BusiestDestination) {
+			stats.MaxPerDestination, stats.BusiestDestination = count, destination
+		}
+	}
+}
+
+// parseNetstat parses the pairs of header and value lines of /proc/net/netstat, e.g.
+// "TcpExt: SyncookiesSent ListenOverflows" and "TcpExt: 0 12", by the name of the line
+func parseNetstat(data []byte) map[string]map[string]uint64 {
+	stats := map[string]map[string]uint64{}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	for i := 0; i+1 < len(lines); i += 2 {
+		header, values := strings.Fields(lines[i]), strings.Fields(lines[i+1])
+		if len(header) == 0 || len(header) != len(values) || header[0] != values[0] {
+			continue
+		}
+		name := strings.TrimSuffix(header[0], ":")
+		stats[name] = map[string]uint64{}
+		for j := 1; j < len(header); j++ {
+			value, err := strconv.ParseUint(values[j], 10, 64)
+			if err != nil {
+				continue
+			}
+			stats[name][header[j]] = value
+		}
+	}
+	return stats
+}
@@ -0,0 +1,83 @@
+package collect
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_parseProcNetAddress(t *testing.T) {
+	address, err := parseProcNetAddress("0100007F:1F90")
+	require.NoError(t, err)
+	assert.Equal(t, "127.0.0.1:8080", address)
+
+	address, err = parseProcNetAddress("0000000000000000FFFF00000A00600A:0035")
+	require.NoError(t, err)
+	assert.Equal(t, "10.96.0.10:53", address)
+
+	address, err = parseProcNetAddress("B80D01200000000067452301EFCDAB89:01BB")
+	require.NoError(t, err)
+	assert.Equal(t, "[2001:db8::123:4567:89ab:cdef]:443", address)
+
+	_, err = parseProcNetAddress("0100007F")
+	assert.Error(t, err)
+}
+
+func Test_collectConnectionStats(t *testing.T) {
+	fsys := fstest.MapFS{
+		"proc/sys/net/netfilter/nf_conntrack_count": {Data: []byte("1200\n")},
+		"proc/sys/net/netfilter/nf_conntrack_max":   {Data: []byte("131072\n")},
+		"proc/net/stat/nf_conntrack": {Data: []byte(`entries  clashres found new invalid ignore delete delete_list insert insert_failed drop early_drop icmp_error  expect_new expect_create expect_delete search_restart
+000004b0  00000000  00000000 00000000 00000010 00000000 00000000 00000000 00000000 00000002 0000000a 00000001 00000000  00000000 00000000 00000000 00000000
+000004b0  00000000  00000000 00000000 00000010 00000000 00000000 00000000 00000000 00000001 00000005 00000000 00000000  00000000 00000000 00000000 00000000
+`)},
+		"proc/net/sockstat": {Data: []byte(`sockets: used 320
+TCP: inuse 12 orphan 1 tw 3 alloc 20 mem 4
+UDP: inuse 5 mem 2
+UDPLITE: inuse 0
+RAW: inuse 0
+FRAG: inuse 0 memory 0
+`)},
+		"proc/net/sockstat6": {Data: []byte(`TCP6: inuse 4
+UDP6: inuse 2
+`)},
+		"proc/sys/net/ipv4/ip_local_port_range": {Data: []byte("32768\t60999\n")},
+		"proc/net/tcp": {Data: []byte(`  sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode
+   0: 00000000:1F90 00000000:0000 0A 00000000:00000000 00:00000000 00000000     0        0 1000 1 0000000000000000 100 0 0 10 0
+   1: 0A00000A:8000 0A00600A:0035 01 00000000:00000000 00:00000000 00000000     0        0 1001 1 0000000000000000 20 4 30 10 -1
+   2: 0A00000A:8001 0A00600A:0035 06 00000000:00000000 03:00000a00 00000000     0        0 0 3 0000000000000000
+   3: 0A00000A:8002 0B00600A:01BB 01 00000000:00000000 00:00000000 00000000     0        0 1003 1 0000000000000000 20 4 30 10 -1
+   4: 0100007F:1F90 0100007F:0400 01 00000000:00000000 00:00000000 00000000     0        0 1004 1 0000000000000000 20 4 30 10 -1
+`)},
+		"proc/net/netstat": {Data: []byte(`TcpExt: SyncookiesSent ListenOverflows ListenDrops
+TcpExt: 0 7 9
+IpExt: InNoRoutes
+IpExt: 0
+`)},
+	}
+
+	info := collectConnectionStats(fsys)
+
+	assert.Equal(t, ConntrackStats{Available: true, Count: 1200, Max: 131072, Drop: 15, EarlyDrop: 1, InsertFailed: 3}, info.Conntrack)
+	assert.Equal(t, SocketStats{Used: 320, TCPInUse: 12, TCPOrphan: 1, TCPTimeWait: 3, TCPAlloc: 20, TCP6InUse: 4, UDPInUse: 5, UDP6InUse: 2}, info.Sockets)
+	assert.Equal(t, map[string]int{"LISTEN": 1, "ESTABLISHED": 3, "TIME_WAIT": 1}, info.TCPStates)
+	assert.Equal(t, EphemeralPortStats{
+		RangeStart:         32768,
+		RangeEnd:           60999,
+		InUse:              3,
+		MaxPerDestination:  2,
+		BusiestDestination: "10.96.0.10:53",
+	}, info.EphemeralPorts)
+	assert.Equal(t, uint64(7), info.TCPExt["ListenOverflows"])
+	assert.Equal(t, []string{"failed to read /proc/net/tcp6: open proc/net/tcp6: file does not exist"}, info.Errors)
+}
+
+func Test_collectConnectionStats_noConntrack(t *testing.T) {
+	info := collectConnectionStats(fstest.MapFS{})
+
+	assert.False(t, info.Conntrack.Available)
+	assert.Equal(t, 0, info.EphemeralPorts.Size())
+	assert.Len(t, info.Errors, 6)
+}
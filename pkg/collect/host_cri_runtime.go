@@ -0,0 +1,233 @@
+package collect
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io/fs"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/shirou/gopsutil/v4/disk"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/yaml"
+)
+
+// Ensure `CollectHostCRIRuntime` implements `HostCollector` interface at compile time.
+var _ HostCollector = (*CollectHostCRIRuntime)(nil)
+
+const HostCRIRuntimePath = `host-collectors/system/cri-runtime.json`
+const HostCRIRuntimeFileName = `cri-runtime.json`
+
+const (
+	CRIConditionRuntimeReady = "RuntimeReady"
+	CRIConditionNetworkReady = "NetworkReady"
+)
+
+// CRIRuntimeInfo is the output of the CRI runtime collector. Each crictl command is run on a best
+// effort basis, failures are recorded in Errors keyed by command. The config of the runtime that
+// `crictl info` also prints is not saved as it may have registry credentials.
+type CRIRuntimeInfo struct {
+	RuntimeEndpoint   string                `json:"runtimeEndpoint"`
+	RuntimeName       string                `json:"runtimeName,omitempty"`
+	RuntimeVersion    string                `json:"runtimeVersion,omitempty"`
+	RuntimeAPIVersion string                `json:"runtimeApiVersion,omitempty"`
+	Conditions        []CRIRuntimeCondition `json:"conditions,omitempty"`
+	ImageFilesystems  []CRIImageFilesystem  `json:"imageFilesystems,omitempty"`
+	Errors            map[string]string     `json:"errors,omitempty"`
+}
+
+// CRIRuntimeCondition is a condition of the runtime, RuntimeReady or NetworkReady
+type CRIRuntimeCondition struct {
+	Type    string `json:"type"`
+	Status  bool   `json:"status"`
+	Reason  string `json:"reason,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+type CRIImageFilesystem struct {
+	Mountpoint string `json:"mountpoint"`
+	UsedBytes  uint64 `json:"usedBytes"`
+	InodesUsed uint64 `json:"inodesUsed"`
+	// TotalBytes and FreeBytes are of the filesystem of the mountpoint, 0 when its usage could not
+	// be read
+	TotalBytes uint64 `json:"totalBytes,omitempty"`
+	FreeBytes  uint64 `json:"freeBytes,omitempty"`
+}
+
+type CollectHostCRIRuntime struct {
+	hostCollector *troubleshootv1beta2.HostCRIRuntime
+	BundlePath    string
+	fs            fs.FS
+}
+
+func (c *CollectHostCRIRuntime) Title() string {
+	return hostCollectorTitleOrDefault(c.hostCollector.HostCollectorMeta, "CRI Runtime")
+}
+
+func (c *CollectHostCRIRuntime) IsExcluded() (bool, error) {
+	return isExcluded(c.hostCollector.Exclude)
+}
+
+func (c *CollectHostCRIRuntime) Collect(progressChan chan<- interface{}) (map[string][]byte, error) {
+	info := CRIRuntimeInfo{
+		RuntimeEndpoint: c.hostCollector.RuntimeEndpoint,
+		Errors:          map[string]string{},
+	}
+	if info.RuntimeEndpoint == "" {
+		info.RuntimeEndpoint = kubeletRuntimeEndpoint(c.fs)
+	}
+	addFailure := func(command string, err error) {
+		klog.V(2).Infof("failed to collect crictl %s: %v", command, err)
+		info.Errors[command] = err.Error()
+	}
+	crictl := func(command string) ([]byte, error) {
+		out, err := execCommand("crictl", "--runtime-endpoint", info.RuntimeEndpoint, command).Output()
+		if err != nil {
+			return nil, errors.New(commandError(err))
+		}
+		return out, nil
+	}
+
+	if out, err := crictl("version"); err != nil {
+		addFailure("version", err)
+	} else {
+		version := parseCrictlVersion(out)
+		info.RuntimeName = version["RuntimeName"]
+		info.RuntimeVersion = version["RuntimeVersion"]
+		info.RuntimeAPIVersion = version["RuntimeApiVersion"]
+	}
+
+	if out, err := crictl("info"); err != nil {
+		addFailure("info", err)
+	} else if info.Conditions, err = parseCrictlInfoConditions(out); err != nil {
+		addFailure("info", err)
+	}
+
+	if out, err := crictl("imagefsinfo"); err != nil {
+		addFailure("imagefsinfo", err)
+	} else if info.ImageFilesystems, err = parseCrictlImageFilesystems(out); err != nil {
+		addFailure("imagefsinfo", err)
+	}
+	for i, imageFs := range info.ImageFilesystems {
+		usage, err := disk.Usage(imageFs.Mountpoint)
+		if err != nil {
+			addFailure("imagefsinfo", errors.Wrapf(err, "failed to get the disk usage of %s", imageFs.Mountpoint))
+			continue
+		}
+		info.ImageFilesystems[i].TotalBytes = usage.Total
+		info.ImageFilesystems[i].FreeBytes = usage.Free
+	}
+
+	if len(info.Errors) == 0 {
+		info.Errors = nil
+	}
+
+	b, err := json.Marshal(info)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal cri runtime info")
+	}
+
+	output := NewResult()
+	output.SaveResult(c.BundlePath, HostCRIRuntimePath, bytes.NewBuffer(b))
+
+	return output, nil
+}
+
+// kubeletRuntimeEndpoint returns the container runtime endpoint of the running kubelet, from its
+// flags then its config file, or the default endpoint of the kubelet
+func kubeletRuntimeEndpoint(fsys fs.FS) string {
+	flags := []string{}
+	if command, err := findKubeletCommand(fsys); err != nil {
+		klog.V(2).Infof("failed to find the kubelet command: %v", err)
+	} else if len(command) > 0 {
+		flags = command[1:]
+	}
+
+	kubeletConfig := struct {
+		ContainerRuntimeEndpoint string `json:"containerRuntimeEndpoint"`
+	}{}
+	kubeletConfigPath := firstNonEmpty(kubeletFlag(flags, "config"), defaultKubeletConfigPath)
+	if b, err := readOptionalFile(fsys, kubeletConfigPath); err != nil {
+		klog.V(2).Infof("failed to read kubelet config: %v", err)
+	} else if b != nil {
+		if err := yaml.Unmarshal(b, &kubeletConfig); err != nil {
+			klog.V(2).Infof("failed to parse kubelet config: %v", err)
+		}
+	}
+
+	return firstNonEmpty(kubeletFlag(flags, "container-runtime-endpoint"), kubeletConfig.ContainerRuntimeEndpoint, defaultContainerRuntimeEndpoint)
+}
+
+// parseCrictlVersion parses the "Key:  value" lines of `crictl version`
+func parseCrictlVersion(out []byte) map[string]string {
+	version := map[string]string{}
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), ":")
+		if !ok {
+			continue
+		}
+		version[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+	return version
+}
+
+// parseCrictlInfoConditions returns the conditions of the status of the runtime reported by
+// `crictl info`
+func parseCrictlInfoConditions(out []byte) ([]CRIRuntimeCondition, error) {
+	info := struct {
+		Status struct {
+			Conditions []CRIRuntimeCondition `json:"conditions"`
+		} `json:"status"`
+	}{}
+	if err := json.Unmarshal(out, &info); err != nil {
+		return nil, errors.Wrap(err, "failed to parse crictl info output")
+	}
+	return info.Status.Conditions, nil
+}
+
+// parseCrictlImageFilesystems returns the image filesystems reported by `crictl imagefsinfo`,
+// whose values are strings
+func parseCrictlImageFilesystems(out []byte) ([]CRIImageFilesystem, error) {
+	type value struct {
+		Value string `json:"value"`
+	}
+	info := struct {
+		Status struct {
+			ImageFilesystems []struct {
+				FsID struct {
+					Mountpoint string `json:"mountpoint"`
+				} `json:"fsId"`
+				UsedBytes  value `json:"usedBytes"`
+				InodesUsed value `json:"inodesUsed"`
+			} `json:"imageFilesystems"`
+		} `json:"status"`
+	}{}
+	if err := json.Unmarshal(out, &info); err != nil {
+		return nil, errors.Wrap(err, "failed to parse crictl imagefsinfo output")
+	}
+
+	filesystems := []CRIImageFilesystem{}
+	for _, imageFs := range info.Status.ImageFilesystems {
+		filesystem := CRIImageFilesystem{Mountpoint: imageFs.FsID.Mountpoint}
+		var err error
+		if filesystem.UsedBytes, err = parseOptionalUint(imageFs.UsedBytes.Value); err != nil {
+			return nil, errors.Wrapf(err, "failed to parse the used bytes of %s", filesystem.Mountpoint)
+		}
+		if filesystem.InodesUsed, err = parseOptionalUint(imageFs.InodesUsed.Value); err != nil {
+			return nil, errors.Wrapf(err, "failed to parse the used inodes of %s", filesystem.Mountpoint)
+		}
+		filesystems = append(filesystems, filesystem)
+	}
+	return filesystems, nil
+}
+
+func parseOptionalUint(s string) (uint64, error) {
+	if s == "" {
+		return 0, nil
+	}
+	return strconv.ParseUint(s, 10, 64)
+}
@@ -0,0 +1,114 @@
+package collect
+
+import (
+	"encoding/json"
+	"os/exec"
+	"testing"
+	"testing/fstest"
+
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testCrictlVersion = `Version:  0.1.0
+RuntimeName:  containerd
+RuntimeVersion:  v1.7.22
+RuntimeApiVersion:  v1
+`
+	testCrictlInfo        = `{"status":{"conditions":[{"type":"RuntimeReady","status":true,"reason":"","message":""},{"type":"NetworkReady","status":false,"reason":"NetworkPluginNotReady","message":"Network plugin returns error: cni plugin not initialized"}]},"config":{"registry":{"configs":{"registry.example.com":{"auth":{"password":"secret"}}}}}}`
+	testCrictlImageFSInfo = `{"status":{"imageFilesystems":[{"timestamp":"1700000000000000000","fsId":{"mountpoint":"/"},"usedBytes":{"value":"5368709120"},"inodesUsed":{"value":"12345"}}],"containerFilesystems":[]}}`
+)
+
+func Test_kubeletRuntimeEndpoint(t *testing.T) {
+	tests := []struct {
+		name string
+		fs   fstest.MapFS
+		want string
+	}{
+		{
+			name: "flag",
+			fs: fstest.MapFS{
+				"proc/812/cmdline": {Data: []byte("/usr/bin/kubelet\x00--container-runtime-endpoint=unix:///var/run/crio/crio.sock\x00")},
+			},
+			want: "unix:///var/run/crio/crio.sock",
+		},
+		{
+			name: "config file of the flag",
+			fs: fstest.MapFS{
+				"proc/812/cmdline":            {Data: []byte("/usr/bin/kubelet\x00--config\x00/etc/kubernetes/kubelet.yaml\x00")},
+				"etc/kubernetes/kubelet.yaml": {Data: []byte("containerRuntimeEndpoint: unix:///run/k3s/containerd/containerd.sock\n")},
+			},
+			want: "unix:///run/k3s/containerd/containerd.sock",
+		},
+		{
+			name: "kubelet not running",
+			fs:   fstest.MapFS{"proc/1/cmdline": {Data: []byte("/sbin/init\x00")}},
+			want: defaultContainerRuntimeEndpoint,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, kubeletRuntimeEndpoint(tt.fs))
+		})
+	}
+}
+
+func Test_parseCrictlVersion(t *testing.T) {
+	version := parseCrictlVersion([]byte(testCrictlVersion))
+	assert.Equal(t, "containerd", version["RuntimeName"])
+	assert.Equal(t, "v1.7.22", version["RuntimeVersion"])
+	assert.Equal(t, "v1", version["RuntimeApiVersion"])
+}
+
+func Test_parseCrictlImageFilesystems(t *testing.T) {
+	filesystems, err := parseCrictlImageFilesystems([]byte(testCrictlImageFSInfo))
+	require.NoError(t, err)
+	assert.Equal(t, []CRIImageFilesystem{{Mountpoint: "/", UsedBytes: 5368709120, InodesUsed: 12345}}, filesystems)
+
+	_, err = parseCrictlImageFilesystems([]byte(`{"status":{"imageFilesystems":[{"usedBytes":{"value":"a lot"}}]}}`))
+	assert.Error(t, err)
+}
+
+func TestCollectHostCRIRuntime(t *testing.T) {
+	req := require.New(t)
+
+	original := execCommand
+	t.Cleanup(func() { execCommand = original })
+	commands := [][]string{}
+	execCommand = func(name string, args ...string) *exec.Cmd {
+		commands = append(commands, append([]string{name}, args...))
+		switch args[len(args)-1] {
+		case "version":
+			return exec.Command("printf", "%s", testCrictlVersion)
+		case "info":
+			return exec.Command("printf", "%s", testCrictlInfo)
+		}
+		return exec.Command("sh", "-c", "echo 'connect: connection refused' >&2; exit 1")
+	}
+
+	c := &CollectHostCRIRuntime{
+		hostCollector: &troubleshootv1beta2.HostCRIRuntime{RuntimeEndpoint: "unix:///var/run/crio/crio.sock"},
+		BundlePath:    "",
+		fs:            fstest.MapFS{},
+	}
+
+	result, err := c.Collect(nil)
+	req.NoError(err)
+	req.Contains(commands, []string{"crictl", "--runtime-endpoint", "unix:///var/run/crio/crio.sock", "info"})
+
+	info := CRIRuntimeInfo{}
+	req.NoError(json.Unmarshal(result[HostCRIRuntimePath], &info))
+	assert.Equal(t, "containerd", info.RuntimeName)
+	assert.Equal(t, "v1.7.22", info.RuntimeVersion)
+	assert.Equal(t, []CRIRuntimeCondition{
+		{Type: CRIConditionRuntimeReady, Status: true},
+		{Type: CRIConditionNetworkReady, Status: false, Reason: "NetworkPluginNotReady", Message: "Network plugin returns error: cni plugin not initialized"},
+	}, info.Conditions)
+	assert.Empty(t, info.ImageFilesystems)
+	assert.Equal(t, map[string]string{"imagefsinfo": "connect: connection refused"}, info.Errors)
+	// the config of the runtime is not saved
+	assert.NotContains(t, string(result[HostCRIRuntimePath]), "secret")
+}
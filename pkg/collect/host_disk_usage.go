@@ -6,6 +6,10 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
@@ -13,10 +17,31 @@ import (
 )
 
 type DiskUsageInfo struct {
-	TotalBytes uint64 `json:"total_bytes"`
-	UsedBytes  uint64 `json:"used_bytes"`
+	TotalBytes uint64                 `json:"total_bytes"`
+	UsedBytes  uint64                 `json:"used_bytes"`
+	Forecast   *DiskUsageForecastInfo `json:"forecast,omitempty"`
 }
 
+// DiskUsageForecastInfo holds the usage samples used to estimate growth of the path, along
+// with the space used by the journal and container images which can be reclaimed before the
+// disk fills up. The journal and image stats are best effort, failures are recorded in Errors.
+type DiskUsageForecastInfo struct {
+	Samples      []DiskUsageSample `json:"samples"`
+	JournalBytes *uint64           `json:"journal_bytes,omitempty"`
+	ImageFSBytes *uint64           `json:"image_fs_bytes,omitempty"`
+	Errors       map[string]string `json:"errors,omitempty"`
+}
+
+type DiskUsageSample struct {
+	Time      time.Time `json:"time"`
+	UsedBytes uint64    `json:"used_bytes"`
+}
+
+const (
+	defaultDiskUsageForecastSamples  = 5
+	defaultDiskUsageForecastInterval = 5 * time.Second
+)
+
 type CollectHostDiskUsage struct {
 	hostCollector *troubleshootv1beta2.DiskUsage
 	BundlePath    string
@@ -50,6 +75,14 @@ func (c *CollectHostDiskUsage) Collect(progressChan chan<- interface{}) (map[str
 		TotalBytes: du.Total,
 		UsedBytes:  du.Used,
 	}
+	if c.hostCollector.Forecast != nil {
+		first := DiskUsageSample{Time: time.Now(), UsedBytes: du.Used}
+		forecast, err := collectDiskUsageForecast(pathExists, c.hostCollector.Forecast, first)
+		if err != nil {
+			return result, errors.Wrapf(err, "collect disk usage forecast for %s", pathExists)
+		}
+		diskSpaceInfo.Forecast = forecast
+	}
 	b, err := json.Marshal(diskSpaceInfo)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to marshal disk space info")
@@ -69,6 +102,109 @@ func (c *CollectHostDiskUsage) Collect(progressChan chan<- interface{}) (map[str
 	return result, nil
 }
 
+func collectDiskUsageForecast(path string, spec *troubleshootv1beta2.DiskUsageForecast, first DiskUsageSample) (*DiskUsageForecastInfo, error) {
+	samples := spec.Samples
+	if samples == 0 {
+		samples = defaultDiskUsageForecastSamples
+	}
+	if samples < 2 {
+		return nil, errors.Errorf("at least 2 samples are required, got %d", samples)
+	}
+	interval := defaultDiskUsageForecastInterval
+	if spec.Interval != "" {
+		var err error
+		interval, err = time.ParseDuration(spec.Interval)
+		if err != nil {
+			return nil, errors.Wrapf(err, "parse interval %q", spec.Interval)
+		}
+	}
+
+	forecast := &DiskUsageForecastInfo{
+		Samples: []DiskUsageSample{first},
+		Errors:  map[string]string{},
+	}
+	for i := 1; i < samples; i++ {
+		time.Sleep(interval)
+		du, err := disk.Usage(path)
+		if err != nil {
+			return nil, errors.Wrapf(err, "sample disk usage for %s", path)
+		}
+		forecast.Samples = append(forecast.Samples, DiskUsageSample{Time: time.Now(), UsedBytes: du.Used})
+	}
+
+	if out, err := execCommand("journalctl", "--disk-usage").Output(); err != nil {
+		forecast.Errors["journalctl"] = err.Error()
+	} else if size, err := parseJournalDiskUsage(out); err != nil {
+		forecast.Errors["journalctl"] = err.Error()
+	} else {
+		forecast.JournalBytes = &size
+	}
+
+	if out, err := execCommand("crictl", "imagefsinfo").Output(); err != nil {
+		forecast.Errors["crictl"] = err.Error()
+	} else if size, err := parseCrictlImageFSInfo(out); err != nil {
+		forecast.Errors["crictl"] = err.Error()
+	} else {
+		forecast.ImageFSBytes = &size
+	}
+
+	return forecast, nil
+}
+
+var journalDiskUsageRegex = regexp.MustCompile(`take up ([0-9.]+)([BKMGTPE]?)`)
+
+// parseJournalDiskUsage parses `journalctl --disk-usage` output, e.g:
+//
+//	Archived and active journals take up 1.2G in the file system.
+func parseJournalDiskUsage(out []byte) (uint64, error) {
+	matches := journalDiskUsageRegex.FindSubmatch(out)
+	if matches == nil {
+		return 0, errors.Errorf("unexpected journalctl output %q", strings.TrimSpace(string(out)))
+	}
+
+	size, err := strconv.ParseFloat(string(matches[1]), 64)
+	if err != nil {
+		return 0, errors.Wrapf(err, "parse journal size %q", matches[1])
+	}
+	// journalctl formats sizes with base 1024 suffixes
+	multiplier := float64(1)
+	for _, suffix := range "KMGTPE" {
+		multiplier *= 1024
+		if string(matches[2]) == string(suffix) {
+			return uint64(size * multiplier), nil
+		}
+	}
+
+	return uint64(size), nil
+}
+
+// parseCrictlImageFSInfo returns the bytes used by all image filesystems reported by `crictl imagefsinfo`
+func parseCrictlImageFSInfo(out []byte) (uint64, error) {
+	info := struct {
+		Status struct {
+			ImageFilesystems []struct {
+				UsedBytes struct {
+					Value string `json:"value"`
+				} `json:"usedBytes"`
+			} `json:"imageFilesystems"`
+		} `json:"status"`
+	}{}
+	if err := json.Unmarshal(out, &info); err != nil {
+		return 0, errors.Wrap(err, "unmarshal crictl imagefsinfo output")
+	}
+
+	var total uint64
+	for _, fs := range info.Status.ImageFilesystems {
+		used, err := strconv.ParseUint(fs.UsedBytes.Value, 10, 64)
+		if err != nil {
+			return 0, errors.Wrapf(err, "parse image filesystem usage %q", fs.UsedBytes.Value)
+		}
+		total += used
+	}
+
+	return total, nil
+}
+
 func traverseFiletreeDirExists(filename string) (string, error) {
 	filename = filepath.Clean(filename)
 	for i := 0; i < 50; i++ {
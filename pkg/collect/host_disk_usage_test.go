@@ -3,6 +3,7 @@ package collect
 import (
 	"encoding/json"
 	"fmt"
+	"os/exec"
 	"strings"
 	"testing"
 
@@ -67,3 +68,61 @@ func TestCollectHostDiskUsage_Collect(t *testing.T) {
 		})
 	}
 }
+
+func Test_parseJournalDiskUsage(t *testing.T) {
+	req := require.New(t)
+
+	size, err := parseJournalDiskUsage([]byte("Archived and active journals take up 1.5G in the file system.\n"))
+	req.NoError(err)
+	req.Equal(uint64(1610612736), size)
+
+	size, err = parseJournalDiskUsage([]byte("Journals take up 8.0M on disk.\n"))
+	req.NoError(err)
+	req.Equal(uint64(8388608), size)
+
+	_, err = parseJournalDiskUsage([]byte("No journal files were found.\n"))
+	req.Error(err)
+}
+
+func Test_parseCrictlImageFSInfo(t *testing.T) {
+	req := require.New(t)
+
+	out := `{"status":{"imageFilesystems":[{"timestamp":"1700000000000000000","fsId":{"mountpoint":"/var/lib/containerd/io.containerd.snapshotter.v1.overlayfs"},"usedBytes":{"value":"5368709120"},"inodesUsed":{"value":"12345"}}],"containerFilesystems":[]}}`
+	size, err := parseCrictlImageFSInfo([]byte(out))
+	req.NoError(err)
+	req.Equal(uint64(5368709120), size)
+}
+
+func TestCollectHostDiskUsage_Forecast(t *testing.T) {
+	req := require.New(t)
+
+	original := execCommand
+	t.Cleanup(func() { execCommand = original })
+	execCommand = func(name string, args ...string) *exec.Cmd {
+		if name == "journalctl" {
+			return exec.Command("echo", "Archived and active journals take up 16.0M in the file system.")
+		}
+		return exec.Command("sh", "-c", "exit 127")
+	}
+
+	c := &CollectHostDiskUsage{
+		hostCollector: &troubleshootv1beta2.DiskUsage{
+			Path: "/",
+			Forecast: &troubleshootv1beta2.DiskUsageForecast{
+				Samples:  3,
+				Interval: "1ms",
+			},
+		},
+	}
+
+	got, err := c.Collect(nil)
+	req.NoError(err)
+
+	info := DiskUsageInfo{}
+	req.NoError(json.Unmarshal(got["host-collectors/diskUsage/diskUsage.json"], &info))
+	req.NotNil(info.Forecast)
+	req.Len(info.Forecast.Samples, 3)
+	req.Equal(uint64(16*1024*1024), *info.Forecast.JournalBytes)
+	req.Nil(info.Forecast.ImageFSBytes)
+	req.Contains(info.Forecast.Errors, "crictl")
+}
@@ -41,6 +41,7 @@ func (d Durations) Swap(i, j int) {
 type CollectHostFilesystemPerformance struct {
 	hostCollector *troubleshootv1beta2.FilesystemPerformance
 	BundlePath    string
+	Context       context.Context
 }
 
 func (c *CollectHostFilesystemPerformance) Title() string {
@@ -52,7 +53,7 @@ func (c *CollectHostFilesystemPerformance) IsExcluded() (bool, error) {
 }
 
 func (c *CollectHostFilesystemPerformance) Collect(progressChan chan<- interface{}) (map[string][]byte, error) {
-	return collectHostFilesystemPerformance(c.hostCollector, c.BundlePath)
+	return collectHostFilesystemPerformance(collectorContext(c.Context), c.hostCollector, c.BundlePath)
 }
 
 type FSPerfResults struct {
@@ -23,7 +23,7 @@ import (
 // and filter out the fsync results for analysis.  TODO: update the analyzer so any/all results
 // from fio can be analyzed.
 
-func collectHostFilesystemPerformance(hostCollector *troubleshootv1beta2.FilesystemPerformance, bundlePath string) (map[string][]byte, error) {
+func collectHostFilesystemPerformance(ctx context.Context, hostCollector *troubleshootv1beta2.FilesystemPerformance, bundlePath string) (map[string][]byte, error) {
 	timeout := time.Minute
 
 	if hostCollector.Timeout != "" {
@@ -33,12 +33,12 @@ func collectHostFilesystemPerformance(hostCollector *troubleshootv1beta2.Filesys
 		}
 		timeout = d
 	}
-	timeoutCtx, timeoutCancel := context.WithTimeout(context.Background(), timeout)
+	timeoutCtx, timeoutCancel := context.WithTimeout(ctx, timeout)
 	defer timeoutCancel()
 
 	// Start a new context for the fio collector and handle the timeout separately so we can
 	// distinguish between the timeout and a command failure in the analyzer.
-	collectCtx, collectCancel := context.WithCancel(context.Background())
+	collectCtx, collectCancel := context.WithCancel(ctx)
 	defer collectCancel()
 
 	collectorName := hostCollector.CollectorName
@@ -0,0 +1,269 @@
+package collect
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io/fs"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"k8s.io/klog/v2"
+)
+
+// Ensure `CollectHostGPU` implements `HostCollector` interface at compile time.
+var _ HostCollector = (*CollectHostGPU)(nil)
+
+const HostGPUPath = `host-collectors/system/gpu.json`
+const HostGPUFileName = `gpu.json`
+
+const (
+	GPUVendorNVIDIA = "nvidia"
+	GPUVendorAMD    = "amd"
+)
+
+// PCI vendor ids of the supported GPU vendors
+var gpuVendorIDs = map[string]string{
+	"10de": GPUVendorNVIDIA,
+	"1002": GPUVendorAMD,
+}
+
+// GPUInventory is the output of the GPU collector. Each source is collected on a best
+// effort basis, failures are recorded in Errors keyed by the command that failed.
+type GPUInventory struct {
+	Devices []GPUDevice       `json:"devices"`
+	NVIDIA  *NVIDIAGPUInfo    `json:"nvidia,omitempty"`
+	AMD     *AMDGPUInfo       `json:"amd,omitempty"`
+	Errors  map[string]string `json:"errors,omitempty"`
+}
+
+// GPUDevice is a display controller found on the PCI bus
+type GPUDevice struct {
+	Slot   string `json:"slot"`
+	Vendor string `json:"vendor"`
+	Name   string `json:"name"`
+}
+
+type NVIDIAGPUInfo struct {
+	DriverVersion string      `json:"driverVersion"`
+	CUDAVersion   string      `json:"cudaVersion,omitempty"`
+	GPUs          []NVIDIAGPU `json:"gpus"`
+}
+
+type NVIDIAGPU struct {
+	Index          int    `json:"index"`
+	Name           string `json:"name"`
+	UUID           string `json:"uuid"`
+	MemoryTotalMiB int    `json:"memoryTotalMiB"`
+	MIGMode        string `json:"migMode,omitempty"`
+	MIGDevices     int    `json:"migDevices,omitempty"`
+}
+
+type AMDGPUInfo struct {
+	DriverVersion string `json:"driverVersion,omitempty"`
+}
+
+type CollectHostGPU struct {
+	hostCollector *troubleshootv1beta2.HostGPU
+	BundlePath    string
+	fs            fs.FS
+}
+
+func (c *CollectHostGPU) Title() string {
+	return hostCollectorTitleOrDefault(c.hostCollector.HostCollectorMeta, "GPU")
+}
+
+func (c *CollectHostGPU) IsExcluded() (bool, error) {
+	return isExcluded(c.hostCollector.Exclude)
+}
+
+func (c *CollectHostGPU) Collect(progressChan chan<- interface{}) (map[string][]byte, error) {
+	inventory := GPUInventory{
+		Devices: []GPUDevice{},
+		Errors:  map[string]string{},
+	}
+
+	addFailure := func(source string, err error) {
+		klog.V(2).Infof("failed to collect %s: %v", source, err)
+		inventory.Errors[source] = err.Error()
+	}
+
+	if out, err := execCommand("lspci", "-mm", "-nn").Output(); err != nil {
+		addFailure("lspci", err)
+	} else {
+		inventory.Devices = parseLspciGPUs(out)
+	}
+
+	// nvidia-smi is only expected to be present on hosts with NVIDIA GPUs
+	if nvidia, err := collectNVIDIAGPUs(); err != nil {
+		if hasGPUVendor(inventory.Devices, GPUVendorNVIDIA) {
+			addFailure("nvidia-smi", err)
+		}
+	} else {
+		inventory.NVIDIA = nvidia
+	}
+
+	if hasGPUVendor(inventory.Devices, GPUVendorAMD) {
+		inventory.AMD = &AMDGPUInfo{}
+		// only set by the out-of-tree (ROCm) driver
+		if version, err := fs.ReadFile(c.fs, "sys/module/amdgpu/version"); err == nil {
+			inventory.AMD.DriverVersion = strings.TrimSpace(string(version))
+		}
+	}
+
+	b, err := json.Marshal(inventory)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal gpu inventory")
+	}
+
+	output := NewResult()
+	output.SaveResult(c.BundlePath, HostGPUPath, bytes.NewBuffer(b))
+
+	return output, nil
+}
+
+func hasGPUVendor(devices []GPUDevice, vendor string) bool {
+	for _, device := range devices {
+		if device.Vendor == vendor {
+			return true
+		}
+	}
+	return false
+}
+
+var lspciFieldRegex = regexp.MustCompile(`"([^"]*)"`)
+var lspciIDRegex = regexp.MustCompile(`\s*\[([0-9a-f]{4})\]$`)
+
+// parseLspciGPUs returns the NVIDIA and AMD display controllers from `lspci -mm -nn` output, e.g:
+//
+//	01:00.0 "VGA compatible controller [0300]" "NVIDIA Corporation [10de]" "GA102 [GeForce RTX 3090] [2204]" -ra1 "" ""
+func parseLspciGPUs(out []byte) []GPUDevice {
+	devices := []GPUDevice{}
+
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		line := scanner.Text()
+		fields := lspciFieldRegex.FindAllStringSubmatch(line, 3)
+		if len(fields) < 3 {
+			continue
+		}
+
+		// display controllers are all in PCI class 03
+		class := lspciIDRegex.FindStringSubmatch(fields[0][1])
+		if class == nil || !strings.HasPrefix(class[1], "03") {
+			continue
+		}
+		vendorID := lspciIDRegex.FindStringSubmatch(fields[1][1])
+		if vendorID == nil {
+			continue
+		}
+		vendor, ok := gpuVendorIDs[vendorID[1]]
+		if !ok {
+			continue
+		}
+
+		devices = append(devices, GPUDevice{
+			Slot:   strings.Fields(line)[0],
+			Vendor: vendor,
+			Name:   lspciIDRegex.ReplaceAllString(fields[2][1], ""),
+		})
+	}
+
+	return devices
+}
+
+var cudaVersionRegex = regexp.MustCompile(`CUDA Version:\s*([0-9.]+)`)
+
+func collectNVIDIAGPUs() (*NVIDIAGPUInfo, error) {
+	out, err := execCommand(
+		"nvidia-smi", "--query-gpu=index,name,uuid,driver_version,memory.total,mig.mode.current",
+		"--format=csv,noheader,nounits",
+	).Output()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query gpus")
+	}
+	info, err := parseNVIDIASMIQuery(out)
+	if err != nil {
+		return nil, err
+	}
+
+	// the cuda version is only reported in the summary
+	if out, err := execCommand("nvidia-smi").Output(); err == nil {
+		if matches := cudaVersionRegex.FindSubmatch(out); matches != nil {
+			info.CUDAVersion = string(matches[1])
+		}
+	}
+
+	if out, err := execCommand("nvidia-smi", "-L").Output(); err == nil {
+		migDevices := countMIGDevices(out)
+		for i := range info.GPUs {
+			info.GPUs[i].MIGDevices = migDevices[info.GPUs[i].Index]
+		}
+	}
+
+	return info, nil
+}
+
+func parseNVIDIASMIQuery(out []byte) (*NVIDIAGPUInfo, error) {
+	info := &NVIDIAGPUInfo{GPUs: []NVIDIAGPU{}}
+
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+		fields := strings.Split(scanner.Text(), ",")
+		if len(fields) != 6 {
+			return nil, errors.Errorf("unexpected nvidia-smi output %q", scanner.Text())
+		}
+		for i := range fields {
+			fields[i] = strings.TrimSpace(fields[i])
+		}
+
+		index, err := strconv.Atoi(fields[0])
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse gpu index %q", fields[0])
+		}
+		// memory is reported as [N/A] on some platforms
+		memory, _ := strconv.Atoi(fields[4])
+
+		gpu := NVIDIAGPU{
+			Index:          index,
+			Name:           fields[1],
+			UUID:           fields[2],
+			MemoryTotalMiB: memory,
+		}
+		if fields[5] != "[N/A]" {
+			gpu.MIGMode = fields[5]
+		}
+		info.DriverVersion = fields[3]
+		info.GPUs = append(info.GPUs, gpu)
+	}
+
+	return info, nil
+}
+
+var nvidiaGPUListRegex = regexp.MustCompile(`^GPU (\d+):`)
+
+// countMIGDevices counts the MIG devices listed under each GPU in `nvidia-smi -L` output
+func countMIGDevices(out []byte) map[int]int {
+	counts := map[int]int{}
+	current := -1
+
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if matches := nvidiaGPUListRegex.FindStringSubmatch(line); matches != nil {
+			current, _ = strconv.Atoi(matches[1])
+			continue
+		}
+		if current >= 0 && strings.HasPrefix(line, "MIG ") {
+			counts[current]++
+		}
+	}
+
+	return counts
+}
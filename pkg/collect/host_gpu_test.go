@@ -0,0 +1,132 @@
+package collect
+
+import (
+	"encoding/json"
+	"os/exec"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/stretchr/testify/require"
+)
+
+const testLspciOutput = `00:02.0 "VGA compatible controller [0300]" "Intel Corporation [8086]" "UHD Graphics 630 [3e92]" -r02 "Dell [1028]" "Device [0869]"
+01:00.0 "3D controller [0302]" "NVIDIA Corporation [10de]" "GA100 [A100 SXM4 40GB] [20b0]" -ra1 "NVIDIA Corporation [10de]" "Device [134f]"
+02:00.0 "Ethernet controller [0200]" "NVIDIA Corporation [10de]" "MT2892 Family [ConnectX-6 Dx] [101d]" "" ""
+03:00.0 "Display controller [0380]" "Advanced Micro Devices, Inc. [AMD/ATI] [1002]" "Aldebaran/MI200 [Instinct MI210] [740f]" -r02 "" ""
+`
+
+func Test_parseLspciGPUs(t *testing.T) {
+	req := require.New(t)
+
+	req.Equal([]GPUDevice{
+		{Slot: "01:00.0", Vendor: GPUVendorNVIDIA, Name: "GA100 [A100 SXM4 40GB]"},
+		{Slot: "03:00.0", Vendor: GPUVendorAMD, Name: "Aldebaran/MI200 [Instinct MI210]"},
+	}, parseLspciGPUs([]byte(testLspciOutput)))
+}
+
+func Test_parseNVIDIASMIQuery(t *testing.T) {
+	req := require.New(t)
+
+	out := "0, NVIDIA A100-SXM4-40GB, GPU-2b5a3b5c, 535.104.05, 40960, Enabled\n1, NVIDIA A100-SXM4-40GB, GPU-7f1d2c3e, 535.104.05, 40960, Disabled\n"
+	info, err := parseNVIDIASMIQuery([]byte(out))
+	req.NoError(err)
+	req.Equal(&NVIDIAGPUInfo{
+		DriverVersion: "535.104.05",
+		GPUs: []NVIDIAGPU{
+			{Index: 0, Name: "NVIDIA A100-SXM4-40GB", UUID: "GPU-2b5a3b5c", MemoryTotalMiB: 40960, MIGMode: "Enabled"},
+			{Index: 1, Name: "NVIDIA A100-SXM4-40GB", UUID: "GPU-7f1d2c3e", MemoryTotalMiB: 40960, MIGMode: "Disabled"},
+		},
+	}, info)
+
+	_, err = parseNVIDIASMIQuery([]byte("0, NVIDIA T4\n"))
+	req.Error(err)
+}
+
+func Test_countMIGDevices(t *testing.T) {
+	req := require.New(t)
+
+	out := strings.Join([]string{
+		"GPU 0: NVIDIA A100-SXM4-40GB (UUID: GPU-2b5a3b5c)",
+		"  MIG 3g.20gb     Device  0: (UUID: MIG-1a)",
+		"  MIG 3g.20gb     Device  1: (UUID: MIG-1b)",
+		"GPU 1: NVIDIA A100-SXM4-40GB (UUID: GPU-7f1d2c3e)",
+	}, "\n")
+
+	req.Equal(map[int]int{0: 2}, countMIGDevices([]byte(out)))
+}
+
+func TestCollectHostGPU(t *testing.T) {
+	req := require.New(t)
+
+	original := execCommand
+	t.Cleanup(func() { execCommand = original })
+	execCommand = func(name string, args ...string) *exec.Cmd {
+		switch {
+		case name == "lspci":
+			return exec.Command("printf", "%s", testLspciOutput)
+		case name == "nvidia-smi" && len(args) == 0:
+			return exec.Command("echo", "| NVIDIA-SMI 535.104.05   Driver Version: 535.104.05   CUDA Version: 12.2 |")
+		case name == "nvidia-smi" && args[0] == "-L":
+			return exec.Command("echo", "GPU 0: NVIDIA A100-SXM4-40GB (UUID: GPU-2b5a3b5c)")
+		case name == "nvidia-smi":
+			return exec.Command("echo", "0, NVIDIA A100-SXM4-40GB, GPU-2b5a3b5c, 535.104.05, 40960, [N/A]")
+		}
+		return exec.Command("sh", "-c", "exit 127")
+	}
+
+	c := &CollectHostGPU{
+		hostCollector: &troubleshootv1beta2.HostGPU{},
+		BundlePath:    "",
+		fs: fstest.MapFS{
+			"sys/module/amdgpu/version": &fstest.MapFile{Data: []byte("6.3.6\n")},
+		},
+	}
+
+	result, err := c.Collect(nil)
+	req.NoError(err)
+
+	inventory := GPUInventory{}
+	req.NoError(json.Unmarshal(result[HostGPUPath], &inventory))
+	req.Len(inventory.Devices, 2)
+	req.Equal(&NVIDIAGPUInfo{
+		DriverVersion: "535.104.05",
+		CUDAVersion:   "12.2",
+		GPUs: []NVIDIAGPU{
+			{Index: 0, Name: "NVIDIA A100-SXM4-40GB", UUID: "GPU-2b5a3b5c", MemoryTotalMiB: 40960},
+		},
+	}, inventory.NVIDIA)
+	req.Equal(&AMDGPUInfo{DriverVersion: "6.3.6"}, inventory.AMD)
+	req.Empty(inventory.Errors)
+}
+
+func TestCollectHostGPU_NoGPUs(t *testing.T) {
+	req := require.New(t)
+
+	original := execCommand
+	t.Cleanup(func() { execCommand = original })
+	execCommand = func(name string, args ...string) *exec.Cmd {
+		if name == "lspci" {
+			return exec.Command("echo", `00:02.0 "VGA compatible controller [0300]" "Intel Corporation [8086]" "UHD Graphics 630 [3e92]" "" ""`)
+		}
+		return exec.Command("sh", "-c", "exit 127")
+	}
+
+	c := &CollectHostGPU{
+		hostCollector: &troubleshootv1beta2.HostGPU{},
+		BundlePath:    "",
+		fs:            fstest.MapFS{},
+	}
+
+	result, err := c.Collect(nil)
+	req.NoError(err)
+
+	inventory := GPUInventory{}
+	req.NoError(json.Unmarshal(result[HostGPUPath], &inventory))
+	req.Empty(inventory.Devices)
+	req.Nil(inventory.NVIDIA)
+	req.Nil(inventory.AMD)
+	// a missing nvidia-smi is expected without NVIDIA devices
+	req.Empty(inventory.Errors)
+}
@@ -2,16 +2,16 @@ package collect
 
 import (
 	"bytes"
-	"net/http"
+	"context"
 	"path/filepath"
 
-	"github.com/pkg/errors"
 	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
 )
 
 type CollectHostHTTP struct {
 	hostCollector *troubleshootv1beta2.HostHTTP
 	BundlePath    string
+	Context       context.Context
 }
 
 func (c *CollectHostHTTP) Title() string {
@@ -23,29 +23,12 @@ func (c *CollectHostHTTP) IsExcluded() (bool, error) {
 }
 
 func (c *CollectHostHTTP) Collect(progressChan chan<- interface{}) (map[string][]byte, error) {
-	httpCollector := c.hostCollector
-
-	var response *http.Response
-	var err error
-
-	switch {
-	case httpCollector.Get != nil:
-		response, err = doRequest(
-			"GET", httpCollector.Get.URL, httpCollector.Get.Headers,
-			"", httpCollector.Get.InsecureSkipVerify, httpCollector.Get.Timeout, httpCollector.Get.TLS, httpCollector.Get.Proxy)
-	case httpCollector.Post != nil:
-		response, err = doRequest(
-			"POST", httpCollector.Post.URL, httpCollector.Post.Headers,
-			httpCollector.Post.Body, httpCollector.Post.InsecureSkipVerify, httpCollector.Post.Timeout, httpCollector.Post.TLS, httpCollector.Post.Proxy)
-	case httpCollector.Put != nil:
-		response, err = doRequest(
-			"PUT", httpCollector.Put.URL, httpCollector.Put.Headers,
-			httpCollector.Put.Body, httpCollector.Put.InsecureSkipVerify, httpCollector.Put.Timeout, httpCollector.Put.TLS, httpCollector.Put.Proxy)
-	default:
-		return nil, errors.New("no supported http request type")
+	request, err := newHTTPRequest(c.hostCollector.Get, c.hostCollector.Post, c.hostCollector.Put)
+	if err != nil {
+		return nil, err
 	}
 
-	responseOutput, err := responseToOutput(response, err)
+	responseOutput, err := request.collect(collectorContext(c.Context))
 	if err != nil {
 		return nil, err
 	}
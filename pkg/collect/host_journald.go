@@ -18,6 +18,7 @@ import (
 type CollectHostJournald struct {
 	hostCollector *troubleshootv1beta2.HostJournald
 	BundlePath    string
+	Context       context.Context
 }
 
 const HostJournaldPath = `host-collectors/journald/`
@@ -45,7 +46,7 @@ func (c *CollectHostJournald) Collect(progressChan chan<- interface{}) (map[stri
 	}
 
 	// set timeout context
-	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	ctx, cancel := context.WithTimeout(collectorContext(c.Context), timeout)
 	defer cancel()
 
 	// prepare command options
@@ -0,0 +1,159 @@
+package collect
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"k8s.io/klog/v2"
+)
+
+// Ensure `CollectHostKernelLogs` implements `HostCollector` interface at compile time.
+var _ HostCollector = (*CollectHostKernelLogs)(nil)
+
+const HostKernelLogsPath = `host-collectors/system/kernel-logs.json`
+const HostKernelLogsFileName = `kernel-logs.json`
+
+const (
+	defaultKernelLogsSince = "-24h"
+	defaultKernelLogsLines = 5000
+	// KernelLogsDmesgSource is the name of the source of the kernel ring buffer
+	KernelLogsDmesgSource = "dmesg"
+)
+
+// KernelLogsInfo is the output of the kernel logs collector.
+type KernelLogsInfo struct {
+	Since   string            `json:"since,omitempty"`
+	Until   string            `json:"until,omitempty"`
+	Sources []KernelLogSource `json:"sources"`
+}
+
+// KernelLogSource is the log of the kernel, named dmesg, or of a systemd unit.
+type KernelLogSource struct {
+	Name    string `json:"name"`
+	Command string `json:"command"`
+	// Filtered is false when the window could not be applied, as dmesg was read directly
+	Filtered bool     `json:"filtered"`
+	Lines    []string `json:"lines"`
+	Error    string   `json:"error,omitempty"`
+}
+
+type CollectHostKernelLogs struct {
+	hostCollector *troubleshootv1beta2.HostKernelLogs
+	BundlePath    string
+	Context       context.Context
+}
+
+func (c *CollectHostKernelLogs) Title() string {
+	return hostCollectorTitleOrDefault(c.hostCollector.HostCollectorMeta, "Kernel Logs")
+}
+
+func (c *CollectHostKernelLogs) IsExcluded() (bool, error) {
+	return isExcluded(c.hostCollector.Exclude)
+}
+
+func (c *CollectHostKernelLogs) Collect(progressChan chan<- interface{}) (map[string][]byte, error) {
+	ctx := collectorContext(c.Context)
+
+	since := c.hostCollector.Since
+	if since == "" {
+		since = defaultKernelLogsSince
+	}
+	lines := c.hostCollector.Lines
+	if lines <= 0 {
+		lines = defaultKernelLogsLines
+	}
+
+	info := KernelLogsInfo{
+		Since:   since,
+		Until:   c.hostCollector.Until,
+		Sources: []KernelLogSource{getKernelLog(since, c.hostCollector.Until, lines)},
+	}
+	for _, unit := range c.hostCollector.Units {
+		if ctx.Err() != nil {
+			break
+		}
+		info.Sources = append(info.Sources, getJournalLog(SystemdUnitName(unit), []string{"-u", SystemdUnitName(unit)}, since, c.hostCollector.Until, lines))
+	}
+
+	b, err := json.Marshal(info)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal kernel logs")
+	}
+
+	output := NewResult()
+	output.SaveResult(c.BundlePath, HostKernelLogsPath, bytes.NewBuffer(b))
+
+	return output, nil
+}
+
+// getKernelLog reads the kernel messages from the journal, or from dmesg without the window
+// when the journal is not available
+func getKernelLog(since string, until string, lines int) KernelLogSource {
+	source := getJournalLog(KernelLogsDmesgSource, []string{"-k"}, since, until, lines)
+	if source.Error == "" {
+		return source
+	}
+	klog.V(2).Infof("failed to read kernel messages from the journal, reading dmesg: %s", source.Error)
+
+	cmd := execCommand("dmesg", "--time-format=iso")
+	source = KernelLogSource{Name: KernelLogsDmesgSource, Command: cmd.String(), Lines: []string{}}
+	out, err := cmd.Output()
+	if err != nil {
+		source.Error = commandError(err)
+		return source
+	}
+	source.Lines = lastLogLines(out, lines)
+	return source
+}
+
+// getJournalLog reads the journal entries matching the args in the window
+func getJournalLog(name string, args []string, since string, until string, lines int) KernelLogSource {
+	args = append(args, "-n", strconv.Itoa(lines), "--no-pager", "--output=short-iso", "--since", since)
+	if until != "" {
+		args = append(args, "--until", until)
+	}
+
+	cmd := execCommand("journalctl", args...)
+	source := KernelLogSource{Name: name, Command: cmd.String(), Filtered: true, Lines: []string{}}
+	out, err := cmd.Output()
+	if err != nil {
+		source.Error = commandError(err)
+		return source
+	}
+	source.Lines = lastLogLines(out, lines)
+	return source
+}
+
+// lastLogLines returns the last non-empty lines of a log, without the "-- No entries --"
+// and "-- Boot ... --" markers of journalctl
+func lastLogLines(out []byte, max int) []string {
+	lines := []string{}
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" || (strings.HasPrefix(line, "-- ") && strings.HasSuffix(line, " --")) {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if len(lines) > max {
+		lines = lines[len(lines)-max:]
+	}
+	return lines
+}
+
+// commandError returns the error of a command, with its stderr when it exited with an error
+func commandError(err error) string {
+	if exitErr, ok := err.(*exec.ExitError); ok && len(exitErr.Stderr) > 0 {
+		return strings.TrimSpace(string(exitErr.Stderr))
+	}
+	return err.Error()
+}
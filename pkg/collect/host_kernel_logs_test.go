@@ -0,0 +1,70 @@
+package collect
+
+import (
+	"encoding/json"
+	"os/exec"
+	"strings"
+	"testing"
+
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_lastLogLines(t *testing.T) {
+	out := []byte(`-- Boot 1a2b3c --
+2024-01-01T00:00:00+0000 node kernel: eth0: Link is Down
+
+2024-01-01T00:00:01+0000 node kernel: eth0: Link is Up
+2024-01-01T00:00:02+0000 node kernel: Out of memory: Killed process 42 (java)
+`)
+
+	assert.Equal(t, []string{
+		"2024-01-01T00:00:01+0000 node kernel: eth0: Link is Up",
+		"2024-01-01T00:00:02+0000 node kernel: Out of memory: Killed process 42 (java)",
+	}, lastLogLines(out, 2))
+	assert.Equal(t, []string{}, lastLogLines([]byte("-- No entries --\n"), 10))
+}
+
+func TestCollectHostKernelLogs(t *testing.T) {
+	commands := []string{}
+	original := execCommand
+	t.Cleanup(func() { execCommand = original })
+	execCommand = func(name string, args ...string) *exec.Cmd {
+		commands = append(commands, name+" "+strings.Join(args, " "))
+		switch {
+		case name == "journalctl" && args[0] == "-k":
+			return exec.Command("sh", "-c", "echo 'No journal files were found.' >&2; exit 1")
+		case name == "dmesg":
+			return exec.Command("printf", "line 1\\nline 2\\nline 3\\n")
+		}
+		return exec.Command("printf", "kubelet line\\n")
+	}
+
+	c := &CollectHostKernelLogs{
+		hostCollector: &troubleshootv1beta2.HostKernelLogs{Units: []string{"kubelet"}, Until: "-1h", Lines: 2},
+		BundlePath:    "",
+	}
+	result, err := c.Collect(nil)
+	require.NoError(t, err)
+
+	info := KernelLogsInfo{}
+	require.NoError(t, json.Unmarshal(result[HostKernelLogsPath], &info))
+
+	assert.Equal(t, []string{
+		"journalctl -k -n 2 --no-pager --output=short-iso --since -24h --until -1h",
+		"dmesg --time-format=iso",
+		"journalctl -u kubelet.service -n 2 --no-pager --output=short-iso --since -24h --until -1h",
+	}, commands)
+	assert.Equal(t, "-24h", info.Since)
+	require.Len(t, info.Sources, 2)
+	assert.Equal(t, KernelLogSource{
+		Name:     "dmesg",
+		Command:  info.Sources[0].Command,
+		Filtered: false,
+		Lines:    []string{"line 2", "line 3"},
+	}, info.Sources[0])
+	assert.Equal(t, "kubelet.service", info.Sources[1].Name)
+	assert.True(t, info.Sources[1].Filtered)
+	assert.Equal(t, []string{"kubelet line"}, info.Sources[1].Lines)
+}
@@ -0,0 +1,88 @@
+package collect
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/fs"
+	"os/exec"
+	"strings"
+
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"k8s.io/klog/v2"
+)
+
+// Ensure `CollectHostKernelSnapshot` implements `HostCollector` interface at compile time.
+var _ HostCollector = (*CollectHostKernelSnapshot)(nil)
+
+const HostKernelSnapshotPath = `host-collectors/system/kernel-snapshot.json`
+const HostKernelSnapshotFileName = `kernel-snapshot.json`
+
+// KernelSnapshot is the output of the kernel snapshot collector.
+type KernelSnapshot struct {
+	KernelRelease string                      `json:"kernelRelease"`
+	Sysctl        map[string]string           `json:"sysctl"`
+	Modules       map[string]KernelModuleInfo `json:"modules"`
+	Cmdline       string                      `json:"cmdline"`
+}
+
+// CollectHostKernelSnapshot captures the kernel parameters, loaded kernel modules
+// and kernel command line of the host.
+type CollectHostKernelSnapshot struct {
+	hostCollector *troubleshootv1beta2.HostKernelSnapshot
+	BundlePath    string
+	fs            fs.FS
+	loaded        kernelModuleCollector
+}
+
+func (c *CollectHostKernelSnapshot) Title() string {
+	return hostCollectorTitleOrDefault(c.hostCollector.HostCollectorMeta, "Kernel Snapshot")
+}
+
+func (c *CollectHostKernelSnapshot) IsExcluded() (bool, error) {
+	return isExcluded(c.hostCollector.Exclude)
+}
+
+func (c *CollectHostKernelSnapshot) Collect(progressChan chan<- interface{}) (map[string][]byte, error) {
+	out, err := execCommand("uname", "-r").Output()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to determine kernel release")
+	}
+	snapshot := KernelSnapshot{
+		KernelRelease: strings.TrimSpace(string(out)),
+	}
+
+	out, err = execCommand("sysctl", "-a").Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			return nil, errors.Wrapf(err, "failed to run sysctl exit-code=%d stderr=%s", exitErr.ExitCode(), exitErr.Stderr)
+		}
+		return nil, errors.Wrap(err, "failed to run sysctl")
+	}
+	snapshot.Sysctl = parseSysctlParameters(out)
+
+	// modules and cmdline are best effort, a partial snapshot is still useful
+	snapshot.Modules, err = c.loaded.collect(snapshot.KernelRelease)
+	if err != nil {
+		klog.V(2).Infof("failed to read loaded kernel modules: %v", err)
+	}
+	if snapshot.Modules == nil {
+		snapshot.Modules = map[string]KernelModuleInfo{}
+	}
+
+	cmdline, err := fs.ReadFile(c.fs, "proc/cmdline")
+	if err != nil {
+		klog.V(2).Infof("failed to read kernel command line: %v", err)
+	}
+	snapshot.Cmdline = strings.TrimSpace(string(cmdline))
+
+	b, err := json.Marshal(snapshot)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal kernel snapshot")
+	}
+
+	output := NewResult()
+	output.SaveResult(c.BundlePath, HostKernelSnapshotPath, bytes.NewBuffer(b))
+
+	return output, nil
+}
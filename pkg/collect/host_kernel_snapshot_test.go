@@ -0,0 +1,94 @@
+package collect
+
+import (
+	"encoding/json"
+	"os/exec"
+	"testing"
+	"testing/fstest"
+
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/stretchr/testify/require"
+)
+
+func setKernelSnapshotExecStub(t *testing.T, sysctl *exec.Cmd) {
+	original := execCommand
+	t.Cleanup(func() { execCommand = original })
+
+	execCommand = func(name string, args ...string) *exec.Cmd {
+		if name == "uname" {
+			return exec.Command("echo", "6.1.0-18-amd64")
+		}
+		return sysctl
+	}
+}
+
+func TestCollectHostKernelSnapshot(t *testing.T) {
+	req := require.New(t)
+	setKernelSnapshotExecStub(t, exec.Command("echo", "vm.max_map_count = 262144\nnet.ipv4.ip_forward = 1"))
+
+	c := &CollectHostKernelSnapshot{
+		hostCollector: &troubleshootv1beta2.HostKernelSnapshot{},
+		BundlePath:    "",
+		fs: fstest.MapFS{
+			"proc/cmdline": &fstest.MapFile{Data: []byte("BOOT_IMAGE=/vmlinuz ro quiet\n")},
+		},
+		loaded: mockKernelModulesCollector{
+			result: map[string]KernelModuleInfo{
+				"overlay": {Size: 151552, Instances: 1, Status: KernelModuleLoaded},
+			},
+		},
+	}
+
+	result, err := c.Collect(nil)
+	req.NoError(err)
+
+	snapshot := KernelSnapshot{}
+	req.NoError(json.Unmarshal(result[HostKernelSnapshotPath], &snapshot))
+	req.Equal(KernelSnapshot{
+		KernelRelease: "6.1.0-18-amd64",
+		Sysctl: map[string]string{
+			"vm.max_map_count":    "262144",
+			"net.ipv4.ip_forward": "1",
+		},
+		Modules: map[string]KernelModuleInfo{
+			"overlay": {Size: 151552, Instances: 1, Status: KernelModuleLoaded},
+		},
+		Cmdline: "BOOT_IMAGE=/vmlinuz ro quiet",
+	}, snapshot)
+}
+
+func TestCollectHostKernelSnapshot_PartialSnapshot(t *testing.T) {
+	req := require.New(t)
+	setKernelSnapshotExecStub(t, exec.Command("echo", "vm.max_map_count = 262144"))
+
+	c := &CollectHostKernelSnapshot{
+		hostCollector: &troubleshootv1beta2.HostKernelSnapshot{},
+		BundlePath:    "",
+		fs:            fstest.MapFS{},
+		loaded:        mockKernelModulesCollector{err: testKernelModuleErr},
+	}
+
+	result, err := c.Collect(nil)
+	req.NoError(err)
+
+	snapshot := KernelSnapshot{}
+	req.NoError(json.Unmarshal(result[HostKernelSnapshotPath], &snapshot))
+	req.Equal(map[string]string{"vm.max_map_count": "262144"}, snapshot.Sysctl)
+	req.Empty(snapshot.Modules)
+	req.Empty(snapshot.Cmdline)
+}
+
+func TestCollectHostKernelSnapshot_SysctlError(t *testing.T) {
+	req := require.New(t)
+	setKernelSnapshotExecStub(t, exec.Command("sh", "-c", "exit 1"))
+
+	c := &CollectHostKernelSnapshot{
+		hostCollector: &troubleshootv1beta2.HostKernelSnapshot{},
+		BundlePath:    "",
+		fs:            fstest.MapFS{},
+		loaded:        mockKernelModulesCollector{},
+	}
+
+	_, err := c.Collect(nil)
+	req.ErrorContains(err, "failed to run sysctl exit-code=1")
+}
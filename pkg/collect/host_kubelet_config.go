@@ -0,0 +1,380 @@
+package collect
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	toml "github.com/pelletier/go-toml/v2"
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/yaml"
+)
+
+// Ensure `CollectHostKubeletConfig` implements `HostCollector` interface at compile time.
+var _ HostCollector = (*CollectHostKubeletConfig)(nil)
+
+const HostKubeletConfigPath = `host-collectors/system/kubelet-config.json`
+const HostKubeletConfigFileName = `kubelet-config.json`
+
+const (
+	CgroupDriverSystemd  = "systemd"
+	CgroupDriverCgroupfs = "cgroupfs"
+)
+
+const (
+	// defaultContainerRuntimeEndpoint is the default of the kubelet on linux
+	defaultContainerRuntimeEndpoint = "unix:///run/containerd/containerd.sock"
+	cgroupMountPoint                = "/sys/fs/cgroup"
+)
+
+// KubeletConfigInfo is the output of the kubelet config collector. The settings of the kubelet
+// are those of its flags, which override its config file, with the defaults of the kubelet for
+// the ones set by neither. Each source is collected on a best effort basis, failures other than
+// missing files are recorded in Errors keyed by source.
+type KubeletConfigInfo struct {
+	// KubeletCommand is the command line of the running kubelet, empty when it is not running
+	KubeletCommand    []string `json:"kubeletCommand,omitempty"`
+	KubeletVersion    string   `json:"kubeletVersion,omitempty"`
+	KubeletConfigPath string   `json:"kubeletConfigPath"`
+	// KubeletConfig is the content of the config file, nil when it was not found
+	KubeletConfig map[string]interface{} `json:"kubeletConfig,omitempty"`
+	// CgroupVersion is the version of the hierarchy mounted at /sys/fs/cgroup, v1 or v2. Hosts
+	// with the hybrid layout are v1 as the kubelet does not use the unified hierarchy of the
+	// layout.
+	CgroupVersion string `json:"cgroupVersion,omitempty"`
+	CgroupDriver  string `json:"cgroupDriver"`
+	CgroupRoot    string `json:"cgroupRoot"`
+	// FailCgroupV1 is failCgroupV1 of the kubelet config, whether the kubelet refuses to start
+	// on cgroup v1. nil when it is not set, for the default of the version of the kubelet.
+	FailCgroupV1             *bool  `json:"failCgroupV1,omitempty"`
+	ContainerRuntimeEndpoint string `json:"containerRuntimeEndpoint"`
+	// ContainerRuntime is the runtime of the endpoint, nil when it is neither containerd nor CRI-O
+	ContainerRuntime *ContainerRuntimeCgroups `json:"containerRuntime,omitempty"`
+	// Slices are the systemd slices of the hierarchy to a depth of two, e.g.
+	// "kubepods.slice/kubepods-burstable.slice"
+	Slices []string `json:"slices,omitempty"`
+	// PodsCgroup is the cgroup the kubelet created for the pods, kubepods.slice with the systemd
+	// driver and kubepods with the cgroupfs one. Empty when it was not found.
+	PodsCgroup string            `json:"podsCgroup,omitempty"`
+	Errors     map[string]string `json:"errors,omitempty"`
+}
+
+type ContainerRuntimeCgroups struct {
+	// Name is containerd or crio
+	Name         string   `json:"name"`
+	ConfigPaths  []string `json:"configPaths"`
+	CgroupDriver string   `json:"cgroupDriver"`
+}
+
+type CollectHostKubeletConfig struct {
+	hostCollector *troubleshootv1beta2.HostKubeletConfig
+	BundlePath    string
+	fs            fs.FS
+}
+
+func (c *CollectHostKubeletConfig) Title() string {
+	return hostCollectorTitleOrDefault(c.hostCollector.HostCollectorMeta, "Kubelet Config")
+}
+
+func (c *CollectHostKubeletConfig) IsExcluded() (bool, error) {
+	return isExcluded(c.hostCollector.Exclude)
+}
+
+func (c *CollectHostKubeletConfig) Collect(progressChan chan<- interface{}) (map[string][]byte, error) {
+	info := collectKubeletConfig(c.fs, c.hostCollector)
+
+	kubelet := "kubelet"
+	if len(info.KubeletCommand) > 0 {
+		kubelet = info.KubeletCommand[0]
+	}
+	if out, err := execCommand(kubelet, "--version").Output(); err != nil {
+		klog.V(2).Infof("failed to get the kubelet version: %v", err)
+		info.Errors["kubeletVersion"] = commandError(err)
+	} else {
+		info.KubeletVersion = strings.TrimPrefix(strings.TrimSpace(string(out)), "Kubernetes ")
+	}
+
+	if len(info.Errors) == 0 {
+		info.Errors = nil
+	}
+
+	b, err := json.Marshal(info)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal kubelet config")
+	}
+
+	output := NewResult()
+	output.SaveResult(c.BundlePath, HostKubeletConfigPath, bytes.NewBuffer(b))
+
+	return output, nil
+}
+
+// collectKubeletConfig reads the settings of the kubelet from its command line and its config
+// file, the cgroup driver of its container runtime from the config of the runtime, and the
+// hierarchy from /sys/fs/cgroup
+func collectKubeletConfig(fsys fs.FS, spec *troubleshootv1beta2.HostKubeletConfig) KubeletConfigInfo {
+	info := KubeletConfigInfo{Errors: map[string]string{}}
+	addFailure := func(source string, err error) {
+		klog.V(2).Infof("failed to collect %s: %v", source, err)
+		info.Errors[source] = err.Error()
+	}
+
+	command, err := findKubeletCommand(fsys)
+	if err != nil {
+		addFailure("kubeletCommand", err)
+	}
+	info.KubeletCommand = command
+	flags := []string{}
+	if len(command) > 0 {
+		flags = command[1:]
+	}
+
+	info.KubeletConfigPath = spec.KubeletConfigPath
+	if info.KubeletConfigPath == "" {
+		info.KubeletConfigPath = kubeletFlag(flags, "config")
+	}
+	if info.KubeletConfigPath == "" {
+		info.KubeletConfigPath = defaultKubeletConfigPath
+	}
+
+	kubeletConfig := struct {
+		CgroupDriver             string `json:"cgroupDriver"`
+		CgroupRoot               string `json:"cgroupRoot"`
+		ContainerRuntimeEndpoint string `json:"containerRuntimeEndpoint"`
+		FailCgroupV1             *bool  `json:"failCgroupV1"`
+	}{}
+	if b, err := readOptionalFile(fsys, info.KubeletConfigPath); err != nil {
+		addFailure("kubeletConfig", errors.Wrap(err, "failed to read kubelet config"))
+	} else if b != nil {
+		if err := yaml.Unmarshal(b, &info.KubeletConfig); err != nil {
+			addFailure("kubeletConfig", errors.Wrap(err, "failed to parse kubelet config"))
+		} else if err := yaml.Unmarshal(b, &kubeletConfig); err != nil {
+			addFailure("kubeletConfig", errors.Wrap(err, "failed to parse kubelet config"))
+		}
+	}
+	info.FailCgroupV1 = kubeletConfig.FailCgroupV1
+	if value := kubeletFlag(flags, "fail-cgroupv1"); value != "" {
+		if failCgroupV1, err := strconv.ParseBool(value); err == nil {
+			info.FailCgroupV1 = &failCgroupV1
+		}
+	}
+	info.CgroupDriver = firstNonEmpty(kubeletFlag(flags, "cgroup-driver"), kubeletConfig.CgroupDriver, CgroupDriverCgroupfs)
+	info.CgroupRoot = firstNonEmpty(kubeletFlag(flags, "cgroup-root"), kubeletConfig.CgroupRoot, "/")
+	info.ContainerRuntimeEndpoint = firstNonEmpty(kubeletFlag(flags, "container-runtime-endpoint"), kubeletConfig.ContainerRuntimeEndpoint, defaultContainerRuntimeEndpoint)
+
+	switch {
+	case strings.Contains(info.ContainerRuntimeEndpoint, "containerd"):
+		containerdConfigPath := spec.ContainerdConfigPath
+		if containerdConfigPath == "" {
+			containerdConfigPath = defaultContainerdConfigPath
+		}
+		if info.ContainerRuntime, err = collectContainerdCgroups(fsys, containerdConfigPath); err != nil {
+			addFailure("containerd", err)
+		}
+	case strings.Contains(info.ContainerRuntimeEndpoint, "crio"):
+		if info.ContainerRuntime, err = collectCRIOCgroups(fsys); err != nil {
+			addFailure("crio", err)
+		}
+	}
+
+	info.CgroupVersion = cgroupVersion(fsys)
+	hierarchy, podsHierarchy := cgroupMountPoint, cgroupMountPoint
+	if info.CgroupVersion == "v1" {
+		hierarchy, podsHierarchy = path.Join(cgroupMountPoint, "systemd"), path.Join(cgroupMountPoint, "memory")
+	}
+	if info.CgroupVersion != "" {
+		if info.Slices, err = systemdSlices(fsys, hierarchy); err != nil {
+			addFailure("cgroups", err)
+		}
+		info.PodsCgroup = podsCgroup(fsys, podsHierarchy, info.CgroupRoot)
+	}
+
+	return info
+}
+
+// findKubeletCommand returns the command line of the running kubelet from /proc, or nil when
+// it is not running
+func findKubeletCommand(fsys fs.FS) ([]string, error) {
+	entries, err := fs.ReadDir(fsys, "proc")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list processes")
+	}
+	for _, entry := range entries {
+		if _, err := strconv.Atoi(entry.Name()); err != nil {
+			continue
+		}
+		// processes may exit while they are listed
+		b, err := fs.ReadFile(fsys, path.Join("proc", entry.Name(), "cmdline"))
+		if err != nil {
+			continue
+		}
+		command := strings.Split(strings.TrimRight(string(b), "\x00"), "\x00")
+		if path.Base(command[0]) == "kubelet" {
+			return command, nil
+		}
+	}
+	return nil, nil
+}
+
+// kubeletFlag returns the value of a flag in the --name=value or --name value forms, or an empty
+// string when it is not set
+func kubeletFlag(flags []string, name string) string {
+	for i, flag := range flags {
+		flag = strings.TrimLeft(flag, "-")
+		if flag == name && i+1 < len(flags) {
+			return flags[i+1]
+		}
+		if value, ok := strings.CutPrefix(flag, name+"="); ok {
+			return value
+		}
+	}
+	return ""
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// collectContainerdCgroups returns the cgroup driver of the default runtime of the CRI plugin of
+// containerd, cgroupfs unless the runtime sets SystemdCgroup
+func collectContainerdCgroups(fsys fs.FS, configPath string) (*ContainerRuntimeCgroups, error) {
+	runtime := &ContainerRuntimeCgroups{Name: "containerd", ConfigPaths: []string{}, CgroupDriver: CgroupDriverCgroupfs}
+
+	b, err := readOptionalFile(fsys, configPath)
+	if err != nil {
+		return runtime, errors.Wrap(err, "failed to read containerd config")
+	}
+	if b == nil {
+		return runtime, nil
+	}
+	runtime.ConfigPaths = append(runtime.ConfigPaths, configPath)
+
+	config := struct {
+		Plugins map[string]struct {
+			// SystemdCgroup is the setting of the deprecated io.containerd.runtime.v1.linux runtime
+			SystemdCgroup bool `toml:"systemd_cgroup"`
+			Containerd    struct {
+				DefaultRuntimeName string `toml:"default_runtime_name"`
+				Runtimes           map[string]struct {
+					Options struct {
+						SystemdCgroup bool `toml:"SystemdCgroup"`
+					} `toml:"options"`
+				} `toml:"runtimes"`
+			} `toml:"containerd"`
+		} `toml:"plugins"`
+	}{}
+	if err := toml.Unmarshal(b, &config); err != nil {
+		return runtime, errors.Wrap(err, "failed to parse containerd config")
+	}
+
+	for _, name := range containerdCRIPlugins {
+		plugin, ok := config.Plugins[name]
+		if !ok {
+			continue
+		}
+		defaultRuntime := firstNonEmpty(plugin.Containerd.DefaultRuntimeName, "runc")
+		if plugin.SystemdCgroup || plugin.Containerd.Runtimes[defaultRuntime].Options.SystemdCgroup {
+			runtime.CgroupDriver = CgroupDriverSystemd
+		}
+	}
+	return runtime, nil
+}
+
+// collectCRIOCgroups returns the cgroup_manager of CRI-O, systemd unless its config files set
+// another one
+func collectCRIOCgroups(fsys fs.FS) (*ContainerRuntimeCgroups, error) {
+	runtime := &ContainerRuntimeCgroups{Name: "crio", ConfigPaths: []string{}, CgroupDriver: CgroupDriverSystemd}
+
+	paths, err := crioConfigPaths(fsys)
+	if err != nil {
+		return runtime, err
+	}
+	for _, p := range paths {
+		b, err := readOptionalFile(fsys, p)
+		if err != nil {
+			return runtime, errors.Wrapf(err, "failed to read crio config %s", p)
+		}
+		if b == nil {
+			continue
+		}
+		runtime.ConfigPaths = append(runtime.ConfigPaths, p)
+
+		config := struct {
+			CRIO struct {
+				Runtime struct {
+					CgroupManager string `toml:"cgroup_manager"`
+				} `toml:"runtime"`
+			} `toml:"crio"`
+		}{}
+		if err := toml.Unmarshal(b, &config); err != nil {
+			return runtime, errors.Wrapf(err, "failed to parse crio config %s", p)
+		}
+		if config.CRIO.Runtime.CgroupManager != "" {
+			runtime.CgroupDriver = config.CRIO.Runtime.CgroupManager
+		}
+	}
+	return runtime, nil
+}
+
+// cgroupVersion returns v2 when the unified hierarchy is mounted at /sys/fs/cgroup, v1 when
+// the mount point exists without it, and an empty string when it does not exist
+func cgroupVersion(fsys fs.FS) string {
+	if _, err := fs.Stat(fsys, hostFSPath(path.Join(cgroupMountPoint, "cgroup.controllers"))); err == nil {
+		return "v2"
+	}
+	if _, err := fs.Stat(fsys, hostFSPath(cgroupMountPoint)); err == nil {
+		return "v1"
+	}
+	return ""
+}
+
+// systemdSlices returns the slices of a hierarchy to a depth of two, relative to its root
+func systemdSlices(fsys fs.FS, hierarchy string) ([]string, error) {
+	root := hostFSPath(hierarchy)
+	slices := []string{}
+	err := fs.WalkDir(fsys, root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() || p == root {
+			return nil
+		}
+		if !strings.HasSuffix(d.Name(), ".slice") {
+			return fs.SkipDir
+		}
+		rel := strings.TrimPrefix(p, root+"/")
+		slices = append(slices, rel)
+		if strings.Count(rel, "/") >= 1 {
+			return fs.SkipDir
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, errors.Wrapf(err, "failed to list the slices of %s", hierarchy)
+	}
+	sort.Strings(slices)
+	return slices, nil
+}
+
+// podsCgroup returns the cgroup of the pods under the cgroup root of the kubelet, or an empty
+// string when the kubelet has not created it
+func podsCgroup(fsys fs.FS, hierarchy string, cgroupRoot string) string {
+	for _, name := range []string{"kubepods.slice", "kubepods"} {
+		cgroup := path.Join("/", cgroupRoot, name)
+		if info, err := fs.Stat(fsys, hostFSPath(path.Join(hierarchy, cgroup))); err == nil && info.IsDir() {
+			return cgroup
+		}
+	}
+	return ""
+}
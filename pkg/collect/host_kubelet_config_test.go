@@ -0,0 +1,153 @@
+package collect
+
+import (
+	"io/fs"
+	"testing"
+	"testing/fstest"
+
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/utils/ptr"
+)
+
+func Test_kubeletFlag(t *testing.T) {
+	flags := []string{"--config=/etc/kubernetes/kubelet.yaml", "--cgroup-driver", "systemd", "-v=2"}
+
+	assert.Equal(t, "/etc/kubernetes/kubelet.yaml", kubeletFlag(flags, "config"))
+	assert.Equal(t, "systemd", kubeletFlag(flags, "cgroup-driver"))
+	assert.Equal(t, "2", kubeletFlag(flags, "v"))
+	assert.Equal(t, "", kubeletFlag(flags, "cgroup-root"))
+}
+
+func Test_collectKubeletConfig(t *testing.T) {
+	dir := &fstest.MapFile{Mode: fs.ModeDir | 0755}
+	tests := []struct {
+		name string
+		fs   fstest.MapFS
+		spec troubleshootv1beta2.HostKubeletConfig
+		want KubeletConfigInfo
+	}{
+		{
+			name: "kubeadm node with containerd on cgroup v2",
+			fs: fstest.MapFS{
+				"proc/1/cmdline":   {Data: []byte("/sbin/init\x00")},
+				"proc/812/cmdline": {Data: []byte("/usr/bin/kubelet\x00--config=/var/lib/kubelet/config.yaml\x00--container-runtime-endpoint=unix:///var/run/containerd/containerd.sock\x00")},
+				"var/lib/kubelet/config.yaml": {Data: []byte(`apiVersion: kubelet.config.k8s.io/v1beta1
+kind: KubeletConfiguration
+cgroupDriver: systemd
+`)},
+				"etc/containerd/config.toml": {Data: []byte(`version = 2
+[plugins."io.containerd.grpc.v1.cri".containerd.runtimes.runc]
+  runtime_type = "io.containerd.runc.v2"
+[plugins."io.containerd.grpc.v1.cri".containerd.runtimes.runc.options]
+  SystemdCgroup = true
+`)},
+				"sys/fs/cgroup/cgroup.controllers":              {Data: []byte("cpuset cpu io memory pids\n")},
+				"sys/fs/cgroup/init.scope":                      dir,
+				"sys/fs/cgroup/system.slice/containerd.service": dir,
+				"sys/fs/cgroup/kubepods.slice/kubepods-burstable.slice/kubepods-burstable-pod1.slice": dir,
+				"sys/fs/cgroup/kubepods.slice/kubepods-besteffort.slice":                              dir,
+			},
+			want: KubeletConfigInfo{
+				KubeletCommand:    []string{"/usr/bin/kubelet", "--config=/var/lib/kubelet/config.yaml", "--container-runtime-endpoint=unix:///var/run/containerd/containerd.sock"},
+				KubeletConfigPath: "/var/lib/kubelet/config.yaml",
+				KubeletConfig: map[string]interface{}{
+					"apiVersion":   "kubelet.config.k8s.io/v1beta1",
+					"kind":         "KubeletConfiguration",
+					"cgroupDriver": "systemd",
+				},
+				CgroupVersion:            "v2",
+				CgroupDriver:             "systemd",
+				CgroupRoot:               "/",
+				ContainerRuntimeEndpoint: "unix:///var/run/containerd/containerd.sock",
+				ContainerRuntime: &ContainerRuntimeCgroups{
+					Name:         "containerd",
+					ConfigPaths:  []string{"/etc/containerd/config.toml"},
+					CgroupDriver: "systemd",
+				},
+				Slices:     []string{"kubepods.slice", "kubepods.slice/kubepods-besteffort.slice", "kubepods.slice/kubepods-burstable.slice", "system.slice"},
+				PodsCgroup: "/kubepods.slice",
+				Errors:     map[string]string{},
+			},
+		},
+		{
+			name: "cgroupfs kubelet with crio on cgroup v1",
+			fs: fstest.MapFS{
+				"proc/900/cmdline": {Data: []byte("kubelet\x00--config\x00/etc/kubernetes/kubelet.yaml\x00--cgroup-driver=cgroupfs\x00")},
+				"etc/kubernetes/kubelet.yaml": {Data: []byte(`containerRuntimeEndpoint: unix:///var/run/crio/crio.sock
+cgroupDriver: systemd
+failCgroupV1: false
+`)},
+				"etc/crio/crio.conf.d/10-crio.conf":  {Data: []byte("[crio.runtime]\ncgroup_manager = \"systemd\"\n")},
+				"sys/fs/cgroup/systemd/system.slice": dir,
+				"sys/fs/cgroup/systemd/user.slice":   dir,
+				"sys/fs/cgroup/memory/kubepods":      dir,
+			},
+			want: KubeletConfigInfo{
+				KubeletCommand:    []string{"kubelet", "--config", "/etc/kubernetes/kubelet.yaml", "--cgroup-driver=cgroupfs"},
+				KubeletConfigPath: "/etc/kubernetes/kubelet.yaml",
+				KubeletConfig: map[string]interface{}{
+					"containerRuntimeEndpoint": "unix:///var/run/crio/crio.sock",
+					"cgroupDriver":             "systemd",
+					"failCgroupV1":             false,
+				},
+				CgroupVersion:            "v1",
+				CgroupDriver:             "cgroupfs",
+				CgroupRoot:               "/",
+				FailCgroupV1:             ptr.To(false),
+				ContainerRuntimeEndpoint: "unix:///var/run/crio/crio.sock",
+				ContainerRuntime: &ContainerRuntimeCgroups{
+					Name:         "crio",
+					ConfigPaths:  []string{"/etc/crio/crio.conf.d/10-crio.conf"},
+					CgroupDriver: "systemd",
+				},
+				Slices:     []string{"system.slice", "user.slice"},
+				PodsCgroup: "/kubepods",
+				Errors:     map[string]string{},
+			},
+		},
+		{
+			name: "kubelet not running and no config",
+			fs: fstest.MapFS{
+				"proc/1/cmdline": {Data: []byte("/sbin/init\x00")},
+			},
+			spec: troubleshootv1beta2.HostKubeletConfig{ContainerdConfigPath: "/etc/k0s/containerd.toml"},
+			want: KubeletConfigInfo{
+				KubeletConfigPath:        "/var/lib/kubelet/config.yaml",
+				CgroupDriver:             "cgroupfs",
+				CgroupRoot:               "/",
+				ContainerRuntimeEndpoint: "unix:///run/containerd/containerd.sock",
+				ContainerRuntime: &ContainerRuntimeCgroups{
+					Name:         "containerd",
+					ConfigPaths:  []string{},
+					CgroupDriver: "cgroupfs",
+				},
+				Errors: map[string]string{},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := collectKubeletConfig(tt.fs, &tt.spec)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_collectContainerdCgroups(t *testing.T) {
+	fsys := fstest.MapFS{
+		"etc/containerd/config.toml": {Data: []byte(`version = 3
+[plugins."io.containerd.cri.v1.runtime".containerd]
+  default_runtime_name = "crun"
+[plugins."io.containerd.cri.v1.runtime".containerd.runtimes.runc.options]
+  SystemdCgroup = false
+[plugins."io.containerd.cri.v1.runtime".containerd.runtimes.crun.options]
+  SystemdCgroup = true
+`)},
+	}
+
+	runtime, err := collectContainerdCgroups(fsys, "/etc/containerd/config.toml")
+	assert.NoError(t, err)
+	assert.Equal(t, "systemd", runtime.CgroupDriver)
+}
@@ -0,0 +1,315 @@
+package collect
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/redact"
+	"sigs.k8s.io/yaml"
+)
+
+// Ensure `CollectHostKubernetesDistribution` implements `HostCollector` interface at compile time.
+var _ HostCollector = (*CollectHostKubernetesDistribution)(nil)
+
+const HostKubernetesDistributionPath = `host-collectors/system/kubernetes-distribution.json`
+const HostKubernetesDistributionFileName = `kubernetes-distribution.json`
+
+const (
+	KubernetesDistributionK3s             = "k3s"
+	KubernetesDistributionRKE2            = "rke2"
+	KubernetesDistributionEmbeddedCluster = "embedded-cluster"
+)
+
+// KubernetesDistributionInfo is the output of the kubernetes distribution collector. Config is
+// the config file of the distribution, merged with the drop-in files of k3s and RKE2, with the
+// values of secret settings redacted. The server token is never collected, only whether it
+// exists.
+type KubernetesDistributionInfo struct {
+	Distribution             string                 `json:"distribution,omitempty"`
+	ConfigPath               string                 `json:"configPath,omitempty"`
+	Config                   map[string]interface{} `json:"config,omitempty"`
+	DataDir                  string                 `json:"dataDir,omitempty"`
+	ContainerdConfigPath     string                 `json:"containerdConfigPath,omitempty"`
+	ContainerdConfig         string                 `json:"containerdConfig,omitempty"`
+	ContainerdConfigTemplate bool                   `json:"containerdConfigTemplate"`
+	ServerToken              bool                   `json:"serverToken"`
+	EtcdSnapshotDir          string                 `json:"etcdSnapshotDir,omitempty"`
+	EtcdSnapshots            []EtcdSnapshotFile     `json:"etcdSnapshots"`
+	Errors                   map[string]string      `json:"errors,omitempty"`
+}
+
+// EtcdSnapshotFile is a local etcd snapshot of k3s or RKE2
+type EtcdSnapshotFile struct {
+	Name    string    `json:"name"`
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"modTime"`
+}
+
+// kubernetesDistributionLayout is where a distribution keeps its files. Paths in the data dir
+// are relative, and empty when the distribution has no such file.
+type kubernetesDistributionLayout struct {
+	configPath       string
+	dataDir          string
+	containerdConfig string
+	serverToken      string
+	etcdSnapshotDir  string
+}
+
+var kubernetesDistributionLayouts = map[string]kubernetesDistributionLayout{
+	KubernetesDistributionK3s: {
+		configPath:       "/etc/rancher/k3s/config.yaml",
+		dataDir:          "/var/lib/rancher/k3s",
+		containerdConfig: "agent/etc/containerd/config.toml",
+		serverToken:      "server/token",
+		etcdSnapshotDir:  "server/db/snapshots",
+	},
+	KubernetesDistributionRKE2: {
+		configPath:       "/etc/rancher/rke2/config.yaml",
+		dataDir:          "/var/lib/rancher/rke2",
+		containerdConfig: "agent/etc/containerd/config.toml",
+		serverToken:      "server/token",
+		etcdSnapshotDir:  "server/db/snapshots",
+	},
+	KubernetesDistributionEmbeddedCluster: {
+		configPath:       "/etc/k0s/k0s.yaml",
+		dataDir:          "/var/lib/embedded-cluster/k0s",
+		containerdConfig: "/etc/k0s/containerd.toml",
+	},
+}
+
+// kubernetesDistributionDetectOrder is the order in which distributions are looked for
+var kubernetesDistributionDetectOrder = []string{
+	KubernetesDistributionK3s,
+	KubernetesDistributionRKE2,
+	KubernetesDistributionEmbeddedCluster,
+}
+
+type CollectHostKubernetesDistribution struct {
+	hostCollector *troubleshootv1beta2.HostKubernetesDistribution
+	BundlePath    string
+}
+
+func (c *CollectHostKubernetesDistribution) Title() string {
+	return hostCollectorTitleOrDefault(c.hostCollector.HostCollectorMeta, "Kubernetes Distribution")
+}
+
+func (c *CollectHostKubernetesDistribution) IsExcluded() (bool, error) {
+	return isExcluded(c.hostCollector.Exclude)
+}
+
+func (c *CollectHostKubernetesDistribution) Collect(progressChan chan<- interface{}) (map[string][]byte, error) {
+	info, err := collectKubernetesDistribution("/", c.hostCollector.Distribution)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := json.Marshal(info)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal kubernetes distribution info")
+	}
+
+	output := NewResult()
+	output.SaveResult(c.BundlePath, HostKubernetesDistributionPath, bytes.NewBuffer(b))
+
+	return output, nil
+}
+
+// collectKubernetesDistribution reads the files of the distribution under root. When distribution
+// is empty, the first distribution whose config file or data dir exists is used, and an empty
+// Distribution is returned when none does.
+func collectKubernetesDistribution(root string, distribution string) (*KubernetesDistributionInfo, error) {
+	info := &KubernetesDistributionInfo{EtcdSnapshots: []EtcdSnapshotFile{}}
+
+	if distribution == "" {
+		distribution = detectKubernetesDistribution(root)
+		if distribution == "" {
+			return info, nil
+		}
+	}
+	layout, ok := kubernetesDistributionLayouts[distribution]
+	if !ok {
+		return nil, errors.Errorf("unsupported distribution %q, must be one of %s", distribution, strings.Join(kubernetesDistributionDetectOrder, ", "))
+	}
+	info.Distribution = distribution
+
+	errs := map[string]string{}
+	info.ConfigPath = layout.configPath
+	config, err := readDistributionConfig(root, layout.configPath, distribution != KubernetesDistributionEmbeddedCluster)
+	if err != nil {
+		errs["config"] = err.Error()
+	}
+	if config != nil {
+		info.Config = redactDistributionConfig(config).(map[string]interface{})
+	}
+
+	// k3s and RKE2 can move their data and snapshots, the defaults only apply when not configured
+	info.DataDir = layout.dataDir
+	if dataDir, ok := config["data-dir"].(string); ok && dataDir != "" {
+		info.DataDir = dataDir
+	}
+
+	if layout.containerdConfig != "" {
+		info.ContainerdConfigPath = distributionPath(info.DataDir, layout.containerdConfig)
+		b, err := os.ReadFile(filepath.Join(root, info.ContainerdConfigPath))
+		if err == nil {
+			info.ContainerdConfig = redactContainerdConfig(string(b))
+		} else if !os.IsNotExist(err) {
+			errs["containerdConfig"] = err.Error()
+		}
+		if _, err := os.Stat(filepath.Join(root, info.ContainerdConfigPath+".tmpl")); err == nil {
+			info.ContainerdConfigTemplate = true
+		}
+	}
+
+	if layout.serverToken != "" {
+		if _, err := os.Stat(filepath.Join(root, distributionPath(info.DataDir, layout.serverToken))); err == nil {
+			info.ServerToken = true
+		}
+	}
+
+	if layout.etcdSnapshotDir != "" {
+		info.EtcdSnapshotDir = distributionPath(info.DataDir, layout.etcdSnapshotDir)
+		if snapshotDir, ok := config["etcd-snapshot-dir"].(string); ok && snapshotDir != "" {
+			info.EtcdSnapshotDir = snapshotDir
+		}
+		info.EtcdSnapshots, err = listEtcdSnapshots(filepath.Join(root, info.EtcdSnapshotDir))
+		if err != nil {
+			errs["etcdSnapshots"] = err.Error()
+		}
+	}
+
+	if len(errs) > 0 {
+		info.Errors = errs
+	}
+	return info, nil
+}
+
+func detectKubernetesDistribution(root string) string {
+	for _, distribution := range kubernetesDistributionDetectOrder {
+		layout := kubernetesDistributionLayouts[distribution]
+		for _, path := range []string{layout.configPath, layout.dataDir} {
+			if _, err := os.Stat(filepath.Join(root, path)); err == nil {
+				return distribution
+			}
+		}
+	}
+	return ""
+}
+
+func distributionPath(dataDir string, path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(dataDir, path)
+}
+
+// readDistributionConfig reads a config file, and with dropIns the files of its config.yaml.d
+// directory in lexical order, the keys of later files replacing those of earlier ones. A missing
+// config file is not an error, the distributions can run without one.
+func readDistributionConfig(root string, configPath string, dropIns bool) (map[string]interface{}, error) {
+	paths := []string{filepath.Join(root, configPath)}
+	if dropIns {
+		matches, err := filepath.Glob(filepath.Join(root, configPath+".d", "*.yaml"))
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to list config drop-in files")
+		}
+		sort.Strings(matches)
+		paths = append(paths, matches...)
+	}
+
+	var config map[string]interface{}
+	for _, path := range paths {
+		b, err := os.ReadFile(path)
+		if os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return config, errors.Wrapf(err, "failed to read %s", path)
+		}
+
+		values := map[string]interface{}{}
+		if err := yaml.Unmarshal(b, &values); err != nil {
+			return config, errors.Wrapf(err, "failed to parse %s", path)
+		}
+		if config == nil {
+			config = map[string]interface{}{}
+		}
+		for key, value := range values {
+			config[key] = value
+		}
+	}
+	return config, nil
+}
+
+var distributionSecretKeyRegex = regexp.MustCompile(`(?i)token|secret|password|access-key`)
+
+// redactDistributionConfig hides the values of the settings whose names suggest a secret, such as
+// token, agent-token or etcd-s3-secret-key
+func redactDistributionConfig(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		redacted := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			if distributionSecretKeyRegex.MatchString(key) {
+				redacted[key] = redact.MASK_TEXT
+			} else {
+				redacted[key] = redactDistributionConfig(item)
+			}
+		}
+		return redacted
+	case []interface{}:
+		redacted := make([]interface{}, len(v))
+		for i, item := range v {
+			redacted[i] = redactDistributionConfig(item)
+		}
+		return redacted
+	}
+	return value
+}
+
+var containerdSecretRegex = regexp.MustCompile(`(?im)^(\s*(?:password|auth|token|identitytoken|identity_token)\s*=\s*).+$`)
+
+// redactContainerdConfig hides the registry credentials of a containerd config
+func redactContainerdConfig(config string) string {
+	return containerdSecretRegex.ReplaceAllString(config, `${1}"`+redact.MASK_TEXT+`"`)
+}
+
+// listEtcdSnapshots lists the files of the snapshot directory, the most recent first. A missing
+// directory has no snapshots.
+func listEtcdSnapshots(dir string) ([]EtcdSnapshotFile, error) {
+	snapshots := []EtcdSnapshotFile{}
+
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return snapshots, nil
+	} else if err != nil {
+		return snapshots, errors.Wrapf(err, "failed to list %s", dir)
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		fileInfo, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		snapshots = append(snapshots, EtcdSnapshotFile{
+			Name:    entry.Name(),
+			Size:    fileInfo.Size(),
+			ModTime: fileInfo.ModTime(),
+		})
+	}
+
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].ModTime.After(snapshots[j].ModTime)
+	})
+	return snapshots, nil
+}
@@ -0,0 +1,90 @@
+package collect
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/replicatedhq/troubleshoot/pkg/redact"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeDistributionFile(t *testing.T, root string, path string, content string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(root, path)), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, path), []byte(content), 0644))
+}
+
+func Test_collectKubernetesDistribution_k3s(t *testing.T) {
+	root := t.TempDir()
+	writeDistributionFile(t, root, "etc/rancher/k3s/config.yaml", `token: supersecret
+write-kubeconfig-mode: "0644"
+cluster-init: true
+etcd-s3-secret-key: s3secret
+tls-san:
+  - k3s.example.com
+`)
+	writeDistributionFile(t, root, "etc/rancher/k3s/config.yaml.d/10-snapshots.yaml", `etcd-snapshot-retention: 10
+write-kubeconfig-mode: "0600"
+`)
+	writeDistributionFile(t, root, "var/lib/rancher/k3s/agent/etc/containerd/config.toml", `[plugins."io.containerd.grpc.v1.cri".registry.configs."registry.example.com".auth]
+  username = "robot"
+  password = "hunter2"
+`)
+	writeDistributionFile(t, root, "var/lib/rancher/k3s/server/token", "K10secret::server:secret\n")
+	writeDistributionFile(t, root, "var/lib/rancher/k3s/server/db/snapshots/etcd-snapshot-old", "old")
+	writeDistributionFile(t, root, "var/lib/rancher/k3s/server/db/snapshots/etcd-snapshot-new", "newer")
+	old := time.Now().Add(-48 * time.Hour)
+	require.NoError(t, os.Chtimes(filepath.Join(root, "var/lib/rancher/k3s/server/db/snapshots/etcd-snapshot-old"), old, old))
+
+	info, err := collectKubernetesDistribution(root, "")
+	require.NoError(t, err)
+
+	assert.Equal(t, KubernetesDistributionK3s, info.Distribution)
+	assert.Equal(t, "/var/lib/rancher/k3s", info.DataDir)
+	assert.Equal(t, redact.MASK_TEXT, info.Config["token"])
+	assert.Equal(t, redact.MASK_TEXT, info.Config["etcd-s3-secret-key"])
+	assert.Equal(t, "0600", info.Config["write-kubeconfig-mode"])
+	assert.Equal(t, true, info.Config["cluster-init"])
+	assert.Equal(t, []interface{}{"k3s.example.com"}, info.Config["tls-san"])
+	assert.Contains(t, info.ContainerdConfig, `username = "robot"`)
+	assert.Contains(t, info.ContainerdConfig, `password = "`+redact.MASK_TEXT+`"`)
+	assert.NotContains(t, info.ContainerdConfig, "hunter2")
+	assert.False(t, info.ContainerdConfigTemplate)
+	assert.True(t, info.ServerToken)
+	require.Len(t, info.EtcdSnapshots, 2)
+	assert.Equal(t, "etcd-snapshot-new", info.EtcdSnapshots[0].Name)
+	assert.Equal(t, int64(5), info.EtcdSnapshots[0].Size)
+	assert.Empty(t, info.Errors)
+}
+
+func Test_collectKubernetesDistribution_customDataDir(t *testing.T) {
+	root := t.TempDir()
+	writeDistributionFile(t, root, "etc/rancher/rke2/config.yaml", "data-dir: /opt/rke2\netcd-snapshot-dir: /backups/etcd\n")
+	writeDistributionFile(t, root, "opt/rke2/agent/etc/containerd/config.toml.tmpl", "{{ template \"base\" . }}\n")
+	writeDistributionFile(t, root, "backups/etcd/on-demand-1", "snapshot")
+
+	info, err := collectKubernetesDistribution(root, KubernetesDistributionRKE2)
+	require.NoError(t, err)
+
+	assert.Equal(t, KubernetesDistributionRKE2, info.Distribution)
+	assert.Equal(t, "/opt/rke2", info.DataDir)
+	assert.Equal(t, "/opt/rke2/agent/etc/containerd/config.toml", info.ContainerdConfigPath)
+	assert.True(t, info.ContainerdConfigTemplate)
+	assert.False(t, info.ServerToken)
+	assert.Equal(t, "/backups/etcd", info.EtcdSnapshotDir)
+	require.Len(t, info.EtcdSnapshots, 1)
+	assert.Equal(t, "on-demand-1", info.EtcdSnapshots[0].Name)
+}
+
+func Test_collectKubernetesDistribution_none(t *testing.T) {
+	info, err := collectKubernetesDistribution(t.TempDir(), "")
+	require.NoError(t, err)
+	assert.Equal(t, "", info.Distribution)
+	assert.Equal(t, []EtcdSnapshotFile{}, info.EtcdSnapshots)
+
+	_, err = collectKubernetesDistribution(t.TempDir(), "kind")
+	assert.Error(t, err)
+}
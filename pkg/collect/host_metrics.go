@@ -0,0 +1,383 @@
+package collect
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/fs"
+	"math"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"k8s.io/klog/v2"
+)
+
+// Ensure `CollectHostMetrics` implements `HostCollector` interface at compile time.
+var _ HostCollector = (*CollectHostMetrics)(nil)
+
+const HostMetricsPath = `host-collectors/system/metrics.json`
+const HostMetricsFileName = `metrics.json`
+
+const (
+	defaultMetricsInterval = 5 * time.Second
+	defaultMetricsDuration = time.Minute
+	minMetricsInterval     = time.Second
+	maxMetricsDuration     = 10 * time.Minute
+)
+
+// MetricsInfo is the output of the metrics collector. The samples are stored as series rather
+// than as objects to keep the file compact: the value of a sample is at the same index in every
+// series as its time in Timestamps.
+type MetricsInfo struct {
+	IntervalSeconds float64 `json:"intervalSeconds"`
+	DurationSeconds float64 `json:"durationSeconds"`
+	// Timestamps are the unix times of the samples in seconds. Each sample reports the interval
+	// before it.
+	Timestamps []int64       `json:"timestamps"`
+	CPU        CPUMetrics    `json:"cpu"`
+	Memory     MemoryMetrics `json:"memory"`
+	// Disks are the series of the disks, by name
+	Disks map[string]DiskMetrics `json:"disks,omitempty"`
+	// Network are the series of the network interfaces, by name
+	Network map[string]NetworkMetrics `json:"network,omitempty"`
+	// Errors are the failures to collect the optional series, e.g. the disks when /proc/diskstats
+	// cannot be read
+	Errors []string `json:"errors,omitempty"`
+}
+
+// CPUMetrics are percentages of the CPU time of all the CPUs
+type CPUMetrics struct {
+	// Usage is the time not spent idle, waiting for IO or stolen by the hypervisor
+	Usage  []float64 `json:"usage"`
+	IOWait []float64 `json:"iowait"`
+	Steal  []float64 `json:"steal"`
+}
+
+type MemoryMetrics struct {
+	// Usage is the percentage of the memory that is not available
+	Usage          []float64 `json:"usage"`
+	AvailableBytes []uint64  `json:"availableBytes"`
+	// SwapUsage is the percentage of the swap in use, 0 without swap
+	SwapUsage []float64 `json:"swapUsage"`
+}
+
+type DiskMetrics struct {
+	ReadBytesPerSecond  []float64 `json:"readBytesPerSecond"`
+	WriteBytesPerSecond []float64 `json:"writeBytesPerSecond"`
+	// Utilization is the percentage of the interval the disk was busy with requests
+	Utilization []float64 `json:"utilization"`
+}
+
+type NetworkMetrics struct {
+	ReceiveBytesPerSecond  []float64 `json:"receiveBytesPerSecond"`
+	TransmitBytesPerSecond []float64 `json:"transmitBytesPerSecond"`
+	// ErrorsPerSecond are the receive and transmit errors
+	ErrorsPerSecond []float64 `json:"errorsPerSecond"`
+	// DropsPerSecond are the packets dropped on receive and transmit
+	DropsPerSecond []float64 `json:"dropsPerSecond"`
+}
+
+type CollectHostMetrics struct {
+	hostCollector *troubleshootv1beta2.HostMetrics
+	BundlePath    string
+	Context       context.Context
+	fs            fs.FS
+}
+
+func (c *CollectHostMetrics) Title() string {
+	return hostCollectorTitleOrDefault(c.hostCollector.HostCollectorMeta, "Metrics")
+}
+
+func (c *CollectHostMetrics) IsExcluded() (bool, error) {
+	return isExcluded(c.hostCollector.Exclude)
+}
+
+func (c *CollectHostMetrics) Collect(progressChan chan<- interface{}) (map[string][]byte, error) {
+	interval, duration, err := parseSamplingWindow(c.hostCollector.Interval, c.hostCollector.Duration, defaultMetricsInterval, defaultMetricsDuration, maxMetricsDuration)
+	if err != nil {
+		return nil, err
+	}
+	if interval < minMetricsInterval {
+		return nil, errors.Errorf("interval %s must not be shorter than %s", interval, minMetricsInterval)
+	}
+
+	sampler := metricsSampler{fs: c.fs, interfaces: c.hostCollector.Interfaces}
+	info, err := sampler.run(collectorContext(c.Context), interval, duration)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := json.Marshal(info)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal metrics info")
+	}
+
+	output := NewResult()
+	output.SaveResult(c.BundlePath, HostMetricsPath, bytes.NewBuffer(b))
+
+	return output, nil
+}
+
+// metricsSampler samples the counters of /proc into the series of a MetricsInfo
+type metricsSampler struct {
+	fs         fs.FS
+	interfaces []string
+	failures   []string
+}
+
+type networkCounters struct {
+	receiveBytes, transmitBytes, errors, drops uint64
+}
+
+// metricsSnapshot are the counters of /proc the metrics of an interval are computed from
+type metricsSnapshot struct {
+	time    time.Time
+	cpu     cpuTimes
+	disks   map[string]diskCounters
+	network map[string]networkCounters
+}
+
+// run samples the counters every interval for the duration. The disks and the interfaces are the
+// ones of the first snapshot, for all the series to have a value for every sample. The samples
+// collected so far are returned when ctx is canceled.
+func (m *metricsSampler) run(ctx context.Context, interval time.Duration, duration time.Duration) (*MetricsInfo, error) {
+	previous, err := m.snapshot()
+	if err != nil {
+		return nil, err
+	}
+
+	info := &MetricsInfo{
+		IntervalSeconds: interval.Seconds(),
+		DurationSeconds: duration.Seconds(),
+		Timestamps:      []int64{},
+		CPU:             CPUMetrics{Usage: []float64{}, IOWait: []float64{}, Steal: []float64{}},
+		Memory:          MemoryMetrics{Usage: []float64{}, AvailableBytes: []uint64{}, SwapUsage: []float64{}},
+	}
+	if previous.disks != nil {
+		info.Disks = map[string]DiskMetrics{}
+		for name := range previous.disks {
+			info.Disks[name] = DiskMetrics{ReadBytesPerSecond: []float64{}, WriteBytesPerSecond: []float64{}, Utilization: []float64{}}
+		}
+	}
+	if previous.network != nil {
+		info.Network = map[string]NetworkMetrics{}
+		for name := range previous.network {
+			info.Network[name] = NetworkMetrics{ReceiveBytesPerSecond: []float64{}, TransmitBytesPerSecond: []float64{}, ErrorsPerSecond: []float64{}, DropsPerSecond: []float64{}}
+		}
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for i := 0; i < int(duration/interval); i++ {
+		select {
+		case <-ctx.Done():
+			klog.V(2).Infof("Metrics sampling stopped after %d samples: %v", len(info.Timestamps), ctx.Err())
+			info.Errors = m.failures
+			return info, nil
+		case <-ticker.C:
+		}
+
+		current, err := m.snapshot()
+		if err != nil {
+			return nil, err
+		}
+		m.addSample(info, previous, current)
+		previous = current
+	}
+
+	info.Errors = m.failures
+	return info, nil
+}
+
+func (m *metricsSampler) snapshot() (*metricsSnapshot, error) {
+	stat, err := fs.ReadFile(m.fs, "proc/stat")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read /proc/stat")
+	}
+	cpu, err := parseProcStatCPU(stat)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &metricsSnapshot{time: time.Now(), cpu: cpu}
+
+	if diskstats, err := fs.ReadFile(m.fs, "proc/diskstats"); err != nil {
+		m.addError(errors.Wrap(err, "failed to read /proc/diskstats"))
+	} else {
+		s.disks = parseDiskStats(diskstats, func(name string) bool { return isDisk(m.fs, name) })
+	}
+
+	if netdev, err := fs.ReadFile(m.fs, "proc/net/dev"); err != nil {
+		m.addError(errors.Wrap(err, "failed to read /proc/net/dev"))
+	} else {
+		s.network = parseNetDev(netdev, m.isInterface)
+	}
+
+	return s, nil
+}
+
+// addSample appends the metrics of the interval between two snapshots to the series
+func (m *metricsSampler) addSample(info *MetricsInfo, previous *metricsSnapshot, current *metricsSnapshot) {
+	info.Timestamps = append(info.Timestamps, current.time.Unix())
+
+	cpu := cpuActivity(previous.cpu, current.cpu)
+	usage := 0.0
+	if cpu != (ActivityCPU{}) {
+		usage = 100 - cpu.Idle - cpu.IOWait - cpu.Steal
+	}
+	info.CPU.Usage = append(info.CPU.Usage, roundMetric(usage))
+	info.CPU.IOWait = append(info.CPU.IOWait, roundMetric(cpu.IOWait))
+	info.CPU.Steal = append(info.CPU.Steal, roundMetric(cpu.Steal))
+
+	memory := map[string]uint64{}
+	if meminfo, err := fs.ReadFile(m.fs, "proc/meminfo"); err != nil {
+		m.addError(errors.Wrap(err, "failed to read /proc/meminfo"))
+	} else {
+		memory = parseMemInfo(meminfo)
+	}
+	info.Memory.Usage = append(info.Memory.Usage, roundMetric(percentUsed(memory["MemTotal"], memory["MemAvailable"])))
+	info.Memory.AvailableBytes = append(info.Memory.AvailableBytes, memory["MemAvailable"])
+	info.Memory.SwapUsage = append(info.Memory.SwapUsage, roundMetric(percentUsed(memory["SwapTotal"], memory["SwapFree"])))
+
+	elapsed := current.time.Sub(previous.time)
+	disks := map[string]ActivityDisk{}
+	for _, disk := range diskActivity(previous.disks, current.disks, elapsed) {
+		disks[disk.Name] = disk
+	}
+	for name, series := range info.Disks {
+		disk := disks[name]
+		series.ReadBytesPerSecond = append(series.ReadBytesPerSecond, roundMetric(disk.ReadBytesPerSecond))
+		series.WriteBytesPerSecond = append(series.WriteBytesPerSecond, roundMetric(disk.WriteBytesPerSecond))
+		series.Utilization = append(series.Utilization, roundMetric(disk.Utilization))
+		info.Disks[name] = series
+	}
+
+	for name, series := range info.Network {
+		p, c := previous.network[name], current.network[name]
+		series.ReceiveBytesPerSecond = append(series.ReceiveBytesPerSecond, counterRate(p.receiveBytes, c.receiveBytes, elapsed))
+		series.TransmitBytesPerSecond = append(series.TransmitBytesPerSecond, counterRate(p.transmitBytes, c.transmitBytes, elapsed))
+		series.ErrorsPerSecond = append(series.ErrorsPerSecond, counterRate(p.errors, c.errors, elapsed))
+		series.DropsPerSecond = append(series.DropsPerSecond, counterRate(p.drops, c.drops, elapsed))
+		info.Network[name] = series
+	}
+}
+
+// isInterface returns true for the interfaces of the collector, or by default for the
+// interfaces backed by a device
+func (m *metricsSampler) isInterface(name string) bool {
+	if len(m.interfaces) > 0 {
+		for _, i := range m.interfaces {
+			if i == name {
+				return true
+			}
+		}
+		return false
+	}
+	if _, err := fs.Stat(m.fs, path.Join("sys/class/net", name, "device")); err != nil {
+		return false
+	}
+	return true
+}
+
+// addError records an error once, as the same series fails the same way for every sample
+func (m *metricsSampler) addError(err error) {
+	for _, e := range m.failures {
+		if e == err.Error() {
+			return
+		}
+	}
+	klog.V(2).Infof("Metrics sampling: %v", err)
+	m.failures = append(m.failures, err.Error())
+}
+
+// parseNetDev returns the counters of the interfaces of /proc/net/dev that isInterface accepts
+func parseNetDev(netdev []byte, isInterface func(string) bool) map[string]networkCounters {
+	interfaces := map[string]networkCounters{}
+
+	scanner := bufio.NewScanner(bytes.NewReader(netdev))
+	for scanner.Scan() {
+		name, counters, ok := strings.Cut(scanner.Text(), ":")
+		if !ok {
+			continue
+		}
+		name = strings.TrimSpace(name)
+		fields := strings.Fields(counters)
+		if len(fields) < 12 || !isInterface(name) {
+			continue
+		}
+
+		values := make([]uint64, 12)
+		valid := true
+		for i := range values {
+			v, err := strconv.ParseUint(fields[i], 10, 64)
+			if err != nil {
+				valid = false
+				break
+			}
+			values[i] = v
+		}
+		if !valid {
+			continue
+		}
+		// the receive counters are followed by the transmit ones: bytes, packets, errs, drop...
+		interfaces[name] = networkCounters{
+			receiveBytes:  values[0],
+			transmitBytes: values[8],
+			errors:        values[2] + values[10],
+			drops:         values[3] + values[11],
+		}
+	}
+
+	return interfaces
+}
+
+// parseMemInfo returns the values of /proc/meminfo in bytes, by name without the colon
+func parseMemInfo(meminfo []byte) map[string]uint64 {
+	values := map[string]uint64{}
+
+	scanner := bufio.NewScanner(bytes.NewReader(meminfo))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+		v, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			continue
+		}
+		if len(fields) > 2 && fields[2] == "kB" {
+			v *= 1024
+		}
+		values[strings.TrimSuffix(fields[0], ":")] = v
+	}
+
+	return values
+}
+
+// percentUsed returns the percentage of total that is not free
+func percentUsed(total uint64, free uint64) float64 {
+	if total == 0 || free > total {
+		return 0
+	}
+	return 100 * float64(total-free) / float64(total)
+}
+
+// counterRate returns the increase of a counter per second, 0 when it was reset
+func counterRate(previous uint64, current uint64, elapsed time.Duration) float64 {
+	if current < previous || elapsed <= 0 {
+		return 0
+	}
+	return roundMetric(float64(current-previous) / elapsed.Seconds())
+}
+
+// roundMetric rounds a value to 2 decimals, which is enough to threshold against and keeps the
+// series short
+func roundMetric(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,98 @@
+package collect
+
+import (
+	"context"
+	"io/fs"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testNetDev = `Inter-|   Receive                                                |  Transmit
+ face |bytes    packets errs drop fifo frame compressed multicast|bytes    packets errs drop fifo colls carrier compressed
+    lo: 5000      50    0    0    0     0          0         0     5000      50    0    0    0     0       0          0
+  eth0: 1000000  900    2    3    0     0          0         0   400000     500    1    4    0     0       0          0
+veth12ab: 800    10    0    0    0     0          0         0      600      10    0    0    0     0       0          0
+`
+
+func Test_parseNetDev(t *testing.T) {
+	isInterface := func(name string) bool { return name == "eth0" }
+
+	assert.Equal(t, map[string]networkCounters{
+		"eth0": {receiveBytes: 1000000, transmitBytes: 400000, errors: 3, drops: 7},
+	}, parseNetDev([]byte(testNetDev), isInterface))
+}
+
+func Test_parseMemInfo(t *testing.T) {
+	memory := parseMemInfo([]byte("MemTotal:       8000 kB\nMemAvailable:   2000 kB\nSwapTotal:         0 kB\nHugePages_Total:   4\n"))
+
+	assert.Equal(t, map[string]uint64{
+		"MemTotal":        8000 * 1024,
+		"MemAvailable":    2000 * 1024,
+		"SwapTotal":       0,
+		"HugePages_Total": 4,
+	}, memory)
+	assert.Equal(t, 75.0, percentUsed(memory["MemTotal"], memory["MemAvailable"]))
+	assert.Equal(t, 0.0, percentUsed(memory["SwapTotal"], memory["SwapFree"]))
+}
+
+func Test_counterRate(t *testing.T) {
+	assert.Equal(t, 333.33, counterRate(1000, 2000, 3*time.Second))
+	assert.Equal(t, 0.0, counterRate(2000, 1000, time.Second))
+	assert.Equal(t, 0.0, counterRate(1000, 2000, 0))
+}
+
+func Test_metricsSampler_run(t *testing.T) {
+	dir := &fstest.MapFile{Mode: fs.ModeDir | 0755}
+	fsys := &procStatFS{MapFS: fstest.MapFS{
+		"proc/meminfo":              {Data: []byte("MemTotal:       4096 kB\nMemAvailable:   1024 kB\nSwapTotal:      1000 kB\nSwapFree:        900 kB\n")},
+		"proc/diskstats":            {Data: []byte("   8       0 sda 100 0 2000 50 100 0 4000 80 0 300 130 0 0 0 0\n   7       0 loop0 1 0 2 0 0 0 0 0 0 0 0 0 0 0 0\n")},
+		"proc/net/dev":              {Data: []byte(testNetDev)},
+		"sys/block/sda":             dir,
+		"sys/block/loop0":           dir,
+		"sys/class/net/eth0/device": dir,
+	}}
+
+	sampler := metricsSampler{fs: fsys}
+	info, err := sampler.run(context.Background(), 10*time.Millisecond, 30*time.Millisecond)
+	require.NoError(t, err)
+
+	assert.Len(t, info.Timestamps, 3)
+	// 50 user, 20 system, 20 idle and 10 steal ticks
+	assert.Equal(t, CPUMetrics{Usage: []float64{70, 70, 70}, IOWait: []float64{0, 0, 0}, Steal: []float64{10, 10, 10}}, info.CPU)
+	assert.Equal(t, MemoryMetrics{
+		Usage:          []float64{75, 75, 75},
+		AvailableBytes: []uint64{1024 * 1024, 1024 * 1024, 1024 * 1024},
+		SwapUsage:      []float64{10, 10, 10},
+	}, info.Memory)
+	assert.Equal(t, map[string]DiskMetrics{
+		"sda": {ReadBytesPerSecond: []float64{0, 0, 0}, WriteBytesPerSecond: []float64{0, 0, 0}, Utilization: []float64{0, 0, 0}},
+	}, info.Disks)
+	assert.Equal(t, map[string]NetworkMetrics{
+		"eth0": {ReceiveBytesPerSecond: []float64{0, 0, 0}, TransmitBytesPerSecond: []float64{0, 0, 0}, ErrorsPerSecond: []float64{0, 0, 0}, DropsPerSecond: []float64{0, 0, 0}},
+	}, info.Network)
+	assert.Empty(t, info.Errors)
+
+	sampler = metricsSampler{fs: &procStatFS{MapFS: fstest.MapFS{"proc/net/dev": {Data: []byte(testNetDev)}}}, interfaces: []string{"lo"}}
+	info, err = sampler.run(context.Background(), 10*time.Millisecond, 10*time.Millisecond)
+	require.NoError(t, err)
+	assert.Len(t, info.Timestamps, 1)
+	assert.Contains(t, info.Network, "lo")
+	assert.Nil(t, info.Disks)
+	assert.Equal(t, []string{
+		"failed to read /proc/diskstats: open proc/diskstats: file does not exist",
+		"failed to read /proc/meminfo: open proc/meminfo: file does not exist",
+	}, info.Errors)
+}
+
+func TestCollectHostMetrics_window(t *testing.T) {
+	for _, window := range [][2]string{{"500ms", ""}, {"", "11m"}, {"2m", "1m"}} {
+		c := CollectHostMetrics{hostCollector: &troubleshootv1beta2.HostMetrics{Interval: window[0], Duration: window[1]}}
+		_, err := c.Collect(nil)
+		assert.Error(t, err, "interval %q, duration %q", window[0], window[1])
+	}
+}
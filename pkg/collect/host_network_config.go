@@ -0,0 +1,283 @@
+package collect
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"strings"
+
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"k8s.io/klog/v2"
+)
+
+// Ensure `CollectHostNetworkConfig` implements `HostCollector` interface at compile time.
+var _ HostCollector = (*CollectHostNetworkConfig)(nil)
+
+const HostNetworkConfigPath = `host-collectors/system/network-config.json`
+const HostNetworkConfigFileName = `network-config.json`
+
+// NetworkConfigInfo is the output of the network config collector. Each source is collected on
+// a best effort basis, failures are recorded in Errors keyed by the command that failed. Commands
+// that need privileges the collector does not have are not run, and recorded in Errors as well.
+type NetworkConfigInfo struct {
+	Interfaces []NetworkInterfaceInfo `json:"interfaces"`
+	Routes     []NetworkRoute         `json:"routes"`
+	Iptables   []FirewallChain        `json:"iptables,omitempty"`
+	Nftables   []FirewallChain        `json:"nftables,omitempty"`
+	Errors     map[string]string      `json:"errors,omitempty"`
+}
+
+type NetworkInterfaceInfo struct {
+	Name      string   `json:"name"`
+	MTU       int      `json:"mtu"`
+	State     string   `json:"state"`
+	Kind      string   `json:"kind,omitempty"`
+	Master    string   `json:"master,omitempty"`
+	Addresses []string `json:"addresses,omitempty"`
+}
+
+type NetworkRoute struct {
+	Family      string `json:"family"`
+	Destination string `json:"destination"`
+	Gateway     string `json:"gateway,omitempty"`
+	Device      string `json:"device,omitempty"`
+	Protocol    string `json:"protocol,omitempty"`
+}
+
+// FirewallChain summarizes a single iptables or nftables chain
+type FirewallChain struct {
+	Family string `json:"family,omitempty"`
+	Table  string `json:"table"`
+	Chain  string `json:"chain"`
+	Hook   string `json:"hook,omitempty"`
+	Policy string `json:"policy,omitempty"`
+	Rules  int    `json:"rules"`
+}
+
+type CollectHostNetworkConfig struct {
+	hostCollector *troubleshootv1beta2.HostNetworkConfig
+	BundlePath    string
+	fs            fs.FS
+}
+
+func (c *CollectHostNetworkConfig) Title() string {
+	return hostCollectorTitleOrDefault(c.hostCollector.HostCollectorMeta, "Network Config")
+}
+
+func (c *CollectHostNetworkConfig) IsExcluded() (bool, error) {
+	return isExcluded(c.hostCollector.Exclude)
+}
+
+func (c *CollectHostNetworkConfig) Collect(progressChan chan<- interface{}) (map[string][]byte, error) {
+	info := NetworkConfigInfo{
+		Interfaces: []NetworkInterfaceInfo{},
+		Routes:     []NetworkRoute{},
+		Errors:     map[string]string{},
+	}
+
+	addFailure := func(source string, err error) {
+		klog.V(2).Infof("failed to collect %s: %v", source, err)
+		info.Errors[source] = err.Error()
+	}
+
+	if out, err := execCommand("ip", "-details", "-json", "addr", "show").Output(); err != nil {
+		addFailure("ip addr", err)
+	} else if info.Interfaces, err = parseIPAddr(out); err != nil {
+		addFailure("ip addr", err)
+	}
+
+	for _, family := range []string{"inet", "inet6"} {
+		source := fmt.Sprintf("ip -family %s route", family)
+		out, err := execCommand("ip", "-family", family, "-json", "route", "show").Output()
+		if err != nil {
+			addFailure(source, err)
+			continue
+		}
+		routes, err := parseIPRoute(out, family)
+		if err != nil {
+			addFailure(source, err)
+			continue
+		}
+		info.Routes = append(info.Routes, routes...)
+	}
+
+	if !DetectHostPrivileges(c.fs)[HostPrivilegeFirewall] {
+		addFailure("iptables-save", insufficientPrivilegesError(HostPrivilegeFirewall))
+		addFailure("nft", insufficientPrivilegesError(HostPrivilegeFirewall))
+	} else {
+		if out, err := execCommand("iptables-save").Output(); err != nil {
+			addFailure("iptables-save", err)
+		} else {
+			info.Iptables = parseIptablesSave(out)
+		}
+
+		if out, err := execCommand("nft", "-json", "list", "ruleset").Output(); err != nil {
+			addFailure("nft", err)
+		} else if info.Nftables, err = parseNftRuleset(out); err != nil {
+			addFailure("nft", err)
+		}
+	}
+
+	b, err := json.Marshal(info)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal network config")
+	}
+
+	output := NewResult()
+	output.SaveResult(c.BundlePath, HostNetworkConfigPath, bytes.NewBuffer(b))
+
+	return output, nil
+}
+
+func parseIPAddr(out []byte) ([]NetworkInterfaceInfo, error) {
+	var links []struct {
+		Name      string `json:"ifname"`
+		MTU       int    `json:"mtu"`
+		OperState string `json:"operstate"`
+		Master    string `json:"master"`
+		LinkInfo  struct {
+			Kind string `json:"info_kind"`
+		} `json:"linkinfo"`
+		AddrInfo []struct {
+			Local     string `json:"local"`
+			PrefixLen int    `json:"prefixlen"`
+		} `json:"addr_info"`
+	}
+	if err := json.Unmarshal(out, &links); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal ip addr output")
+	}
+
+	interfaces := []NetworkInterfaceInfo{}
+	for _, link := range links {
+		iface := NetworkInterfaceInfo{
+			Name:   link.Name,
+			MTU:    link.MTU,
+			State:  strings.ToLower(link.OperState),
+			Kind:   link.LinkInfo.Kind,
+			Master: link.Master,
+		}
+		for _, addr := range link.AddrInfo {
+			iface.Addresses = append(iface.Addresses, fmt.Sprintf("%s/%d", addr.Local, addr.PrefixLen))
+		}
+		interfaces = append(interfaces, iface)
+	}
+
+	return interfaces, nil
+}
+
+func parseIPRoute(out []byte, family string) ([]NetworkRoute, error) {
+	var entries []struct {
+		Dst      string `json:"dst"`
+		Gateway  string `json:"gateway"`
+		Dev      string `json:"dev"`
+		Protocol string `json:"protocol"`
+	}
+	if err := json.Unmarshal(out, &entries); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal ip route output")
+	}
+
+	routes := []NetworkRoute{}
+	for _, entry := range entries {
+		routes = append(routes, NetworkRoute{
+			Family:      family,
+			Destination: entry.Dst,
+			Gateway:     entry.Gateway,
+			Device:      entry.Dev,
+			Protocol:    entry.Protocol,
+		})
+	}
+
+	return routes, nil
+}
+
+// parseIptablesSave counts the rules in each chain of iptables-save output, e.g:
+//
+//	*filter
+//	:INPUT ACCEPT [0:0]
+//	-A INPUT -j KUBE-FIREWALL
+//	COMMIT
+func parseIptablesSave(out []byte) []FirewallChain {
+	chains := []FirewallChain{}
+	index := map[string]int{}
+	table := ""
+
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case strings.HasPrefix(line, "*"):
+			table = strings.TrimPrefix(line, "*")
+		case strings.HasPrefix(line, ":"):
+			fields := strings.Fields(strings.TrimPrefix(line, ":"))
+			if len(fields) == 0 {
+				continue
+			}
+			chain := FirewallChain{Table: table, Chain: fields[0]}
+			if len(fields) > 1 && fields[1] != "-" {
+				chain.Policy = fields[1]
+			}
+			index[table+"/"+chain.Chain] = len(chains)
+			chains = append(chains, chain)
+		case strings.HasPrefix(line, "-A "):
+			fields := strings.Fields(line)
+			if len(fields) < 2 {
+				continue
+			}
+			if i, ok := index[table+"/"+fields[1]]; ok {
+				chains[i].Rules++
+			}
+		}
+	}
+
+	return chains
+}
+
+// parseNftRuleset counts the rules in each chain of `nft -json list ruleset` output
+func parseNftRuleset(out []byte) ([]FirewallChain, error) {
+	var ruleset struct {
+		Nftables []struct {
+			Chain *struct {
+				Family string `json:"family"`
+				Table  string `json:"table"`
+				Name   string `json:"name"`
+				Hook   string `json:"hook"`
+				Policy string `json:"policy"`
+			} `json:"chain"`
+			Rule *struct {
+				Family string `json:"family"`
+				Table  string `json:"table"`
+				Chain  string `json:"chain"`
+			} `json:"rule"`
+		} `json:"nftables"`
+	}
+	if err := json.Unmarshal(out, &ruleset); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal nft ruleset")
+	}
+
+	chains := []FirewallChain{}
+	index := map[string]int{}
+	for _, entry := range ruleset.Nftables {
+		if entry.Chain != nil {
+			key := strings.Join([]string{entry.Chain.Family, entry.Chain.Table, entry.Chain.Name}, "/")
+			index[key] = len(chains)
+			chains = append(chains, FirewallChain{
+				Family: entry.Chain.Family,
+				Table:  entry.Chain.Table,
+				Chain:  entry.Chain.Name,
+				Hook:   entry.Chain.Hook,
+				Policy: entry.Chain.Policy,
+			})
+		}
+		if entry.Rule != nil {
+			key := strings.Join([]string{entry.Rule.Family, entry.Rule.Table, entry.Rule.Chain}, "/")
+			if i, ok := index[key]; ok {
+				chains[i].Rules++
+			}
+		}
+	}
+
+	return chains, nil
+}
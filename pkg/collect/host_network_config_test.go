@@ -0,0 +1,158 @@
+package collect
+
+import (
+	"encoding/json"
+	"os/exec"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_parseIPAddr(t *testing.T) {
+	req := require.New(t)
+
+	out := `[
+		{"ifindex":1,"ifname":"lo","mtu":65536,"operstate":"UNKNOWN","addr_info":[{"family":"inet","local":"127.0.0.1","prefixlen":8}]},
+		{"ifindex":2,"ifname":"eth0","mtu":1500,"operstate":"UP","addr_info":[{"family":"inet","local":"10.0.0.10","prefixlen":24},{"family":"inet6","local":"fe80::1","prefixlen":64}]},
+		{"ifindex":3,"ifname":"flannel.1","mtu":1450,"operstate":"UNKNOWN","linkinfo":{"info_kind":"vxlan","info_data":{"id":1}},"addr_info":[]},
+		{"ifindex":4,"ifname":"veth1","mtu":1450,"operstate":"UP","master":"cni0","linkinfo":{"info_kind":"veth"}}
+	]`
+
+	interfaces, err := parseIPAddr([]byte(out))
+	req.NoError(err)
+	req.Equal([]NetworkInterfaceInfo{
+		{Name: "lo", MTU: 65536, State: "unknown", Addresses: []string{"127.0.0.1/8"}},
+		{Name: "eth0", MTU: 1500, State: "up", Addresses: []string{"10.0.0.10/24", "fe80::1/64"}},
+		{Name: "flannel.1", MTU: 1450, State: "unknown", Kind: "vxlan"},
+		{Name: "veth1", MTU: 1450, State: "up", Kind: "veth", Master: "cni0"},
+	}, interfaces)
+}
+
+func Test_parseIPRoute(t *testing.T) {
+	req := require.New(t)
+
+	out := `[
+		{"dst":"default","gateway":"10.0.0.1","dev":"eth0","protocol":"dhcp","flags":[]},
+		{"dst":"10.0.0.0/24","dev":"eth0","protocol":"kernel","scope":"link","prefsrc":"10.0.0.10","flags":[]}
+	]`
+
+	routes, err := parseIPRoute([]byte(out), "inet")
+	req.NoError(err)
+	req.Equal([]NetworkRoute{
+		{Family: "inet", Destination: "default", Gateway: "10.0.0.1", Device: "eth0", Protocol: "dhcp"},
+		{Family: "inet", Destination: "10.0.0.0/24", Device: "eth0", Protocol: "kernel"},
+	}, routes)
+}
+
+func Test_parseIptablesSave(t *testing.T) {
+	req := require.New(t)
+
+	out := strings.Join([]string{
+		"# Generated by iptables-save v1.8.7",
+		"*nat",
+		":PREROUTING ACCEPT [0:0]",
+		":KUBE-SERVICES - [0:0]",
+		"-A PREROUTING -m comment --comment \"kubernetes service portals\" -j KUBE-SERVICES",
+		"-A KUBE-SERVICES -d 10.96.0.1/32 -j KUBE-SVC-NPX46M4PTMTKRN6Y",
+		"-A KUBE-SERVICES -d 10.96.0.10/32 -j KUBE-SVC-TCOU7JCQXEZGVUNU",
+		"COMMIT",
+		"*filter",
+		":INPUT DROP [0:0]",
+		":FORWARD ACCEPT [0:0]",
+		"-A INPUT -i lo -j ACCEPT",
+		"COMMIT",
+	}, "\n")
+
+	req.Equal([]FirewallChain{
+		{Table: "nat", Chain: "PREROUTING", Policy: "ACCEPT", Rules: 1},
+		{Table: "nat", Chain: "KUBE-SERVICES", Rules: 2},
+		{Table: "filter", Chain: "INPUT", Policy: "DROP", Rules: 1},
+		{Table: "filter", Chain: "FORWARD", Policy: "ACCEPT", Rules: 0},
+	}, parseIptablesSave([]byte(out)))
+}
+
+func Test_parseNftRuleset(t *testing.T) {
+	req := require.New(t)
+
+	out := `{"nftables": [
+		{"metainfo": {"version": "1.0.2", "json_schema_version": 1}},
+		{"table": {"family": "inet", "name": "filter", "handle": 1}},
+		{"chain": {"family": "inet", "table": "filter", "name": "input", "handle": 1, "type": "filter", "hook": "input", "prio": 0, "policy": "drop"}},
+		{"chain": {"family": "inet", "table": "filter", "name": "forward", "handle": 2, "type": "filter", "hook": "forward", "prio": 0, "policy": "accept"}},
+		{"rule": {"family": "inet", "table": "filter", "chain": "input", "handle": 3, "expr": []}},
+		{"rule": {"family": "inet", "table": "filter", "chain": "input", "handle": 4, "expr": []}}
+	]}`
+
+	chains, err := parseNftRuleset([]byte(out))
+	req.NoError(err)
+	req.Equal([]FirewallChain{
+		{Family: "inet", Table: "filter", Chain: "input", Hook: "input", Policy: "drop", Rules: 2},
+		{Family: "inet", Table: "filter", Chain: "forward", Hook: "forward", Policy: "accept", Rules: 0},
+	}, chains)
+}
+
+func TestCollectHostNetworkConfig_MissingTools(t *testing.T) {
+	req := require.New(t)
+
+	original := execCommand
+	t.Cleanup(func() { execCommand = original })
+	execCommand = func(name string, args ...string) *exec.Cmd {
+		if name == "ip" && args[0] == "-details" {
+			return exec.Command("echo", `[{"ifname":"eth0","mtu":1500,"operstate":"UP"}]`)
+		}
+		if name == "ip" {
+			return exec.Command("echo", `[{"dst":"default","gateway":"10.0.0.1","dev":"eth0"}]`)
+		}
+		return exec.Command("sh", "-c", "exit 127")
+	}
+
+	c := &CollectHostNetworkConfig{
+		hostCollector: &troubleshootv1beta2.HostNetworkConfig{},
+		BundlePath:    "",
+		fs:            fstest.MapFS{},
+	}
+
+	result, err := c.Collect(nil)
+	req.NoError(err)
+
+	info := NetworkConfigInfo{}
+	req.NoError(json.Unmarshal(result[HostNetworkConfigPath], &info))
+	req.Equal([]NetworkInterfaceInfo{{Name: "eth0", MTU: 1500, State: "up"}}, info.Interfaces)
+	req.Len(info.Routes, 2)
+	req.Empty(info.Iptables)
+	req.Empty(info.Nftables)
+	req.Contains(info.Errors, "iptables-save")
+	req.Contains(info.Errors, "nft")
+}
+
+func TestCollectHostNetworkConfig_InsufficientPrivileges(t *testing.T) {
+	req := require.New(t)
+
+	original := execCommand
+	t.Cleanup(func() { execCommand = original })
+	execCommand = func(name string, args ...string) *exec.Cmd {
+		if name != "ip" {
+			t.Fatalf("unexpected command %s", name)
+		}
+		return exec.Command("echo", "[]")
+	}
+
+	c := &CollectHostNetworkConfig{
+		hostCollector: &troubleshootv1beta2.HostNetworkConfig{},
+		BundlePath:    "",
+		fs: fstest.MapFS{
+			"proc/self/status": {Data: []byte("Name:\tpreflight\nCapEff:\t0000000000000000\n")},
+		},
+	}
+
+	result, err := c.Collect(nil)
+	req.NoError(err)
+
+	info := NetworkConfigInfo{}
+	req.NoError(json.Unmarshal(result[HostNetworkConfigPath], &info))
+	req.Equal("insufficient privileges: firewall access is required", info.Errors["iptables-save"])
+	req.Equal("insufficient privileges: firewall access is required", info.Errors["nft"])
+}
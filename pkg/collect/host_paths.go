@@ -0,0 +1,223 @@
+package collect
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"k8s.io/klog/v2"
+)
+
+// Ensure `CollectHostPaths` implements `HostCollector` interface at compile time.
+var _ HostCollector = (*CollectHostPaths)(nil)
+
+const HostPathsPath = `host-collectors/system/paths.json`
+const HostPathsFileName = `paths.json`
+
+// PathInfo is the state of a single path. Owner and Group are the names of the user and group
+// when they can be looked up, and their ids otherwise. The mount is the one the path is on, or
+// the one its closest existing parent is on when it does not exist.
+type PathInfo struct {
+	Path           string   `json:"path"`
+	Exists         bool     `json:"exists"`
+	Type           string   `json:"type,omitempty"`
+	Owner          string   `json:"owner,omitempty"`
+	UID            *int     `json:"uid,omitempty"`
+	Group          string   `json:"group,omitempty"`
+	GID            *int     `json:"gid,omitempty"`
+	Mode           string   `json:"mode,omitempty"`
+	SELinuxContext string   `json:"selinuxContext,omitempty"`
+	MountPoint     string   `json:"mountPoint,omitempty"`
+	FSType         string   `json:"fsType,omitempty"`
+	MountOptions   []string `json:"mountOptions,omitempty"`
+	Error          string   `json:"error,omitempty"`
+}
+
+// mountInfo is a mount read from /proc/self/mountinfo
+type mountInfo struct {
+	mountPoint string
+	fsType     string
+	options    []string
+}
+
+type CollectHostPaths struct {
+	hostCollector *troubleshootv1beta2.HostPaths
+	BundlePath    string
+	fs            fs.FS
+}
+
+func (c *CollectHostPaths) Title() string {
+	return hostCollectorTitleOrDefault(c.hostCollector.HostCollectorMeta, "Paths")
+}
+
+func (c *CollectHostPaths) IsExcluded() (bool, error) {
+	return isExcluded(c.hostCollector.Exclude)
+}
+
+func (c *CollectHostPaths) Collect(progressChan chan<- interface{}) (map[string][]byte, error) {
+	mounts, err := readMountInfo(c.fs)
+	if err != nil {
+		// the paths are still useful without their mounts, e.g. on hosts other than linux
+		klog.V(2).Infof("failed to read mounts: %v", err)
+	}
+
+	paths := []PathInfo{}
+	for _, path := range c.hostCollector.Paths {
+		paths = append(paths, getPathInfo(path, mounts))
+	}
+
+	b, err := json.Marshal(paths)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal paths")
+	}
+
+	output := NewResult()
+	output.SaveResult(c.BundlePath, HostPathsPath, bytes.NewBuffer(b))
+
+	return output, nil
+}
+
+func getPathInfo(path string, mounts []mountInfo) PathInfo {
+	info := PathInfo{Path: path}
+
+	fi, err := os.Stat(path)
+	switch {
+	case err == nil:
+		info.Exists = true
+		info.Type = pathType(fi.Mode())
+		info.Mode = fmt.Sprintf("%04o", unixPermissions(fi.Mode()))
+		if uid, gid, ok := pathOwnership(fi); ok {
+			info.UID, info.GID = &uid, &gid
+			info.Owner = strconv.Itoa(uid)
+			if u, err := user.LookupId(info.Owner); err == nil {
+				info.Owner = u.Username
+			}
+			info.Group = strconv.Itoa(gid)
+			if g, err := user.LookupGroupId(info.Group); err == nil {
+				info.Group = g.Name
+			}
+		}
+		info.SELinuxContext = pathSELinuxContext(path)
+	case os.IsNotExist(err):
+	default:
+		info.Error = err.Error()
+	}
+
+	if mount := findMount(mounts, resolveExistingPath(path)); mount != nil {
+		info.MountPoint = mount.mountPoint
+		info.FSType = mount.fsType
+		info.MountOptions = mount.options
+	}
+
+	return info
+}
+
+func pathType(mode os.FileMode) string {
+	switch {
+	case mode.IsDir():
+		return "directory"
+	case mode.IsRegular():
+		return "file"
+	case mode&os.ModeSymlink != 0:
+		return "symlink"
+	case mode&os.ModeSocket != 0:
+		return "socket"
+	case mode&os.ModeNamedPipe != 0:
+		return "pipe"
+	case mode&os.ModeDevice != 0:
+		return "device"
+	}
+	return "other"
+}
+
+// unixPermissions returns the permission bits of a mode along with the setuid, setgid and
+// sticky bits, e.g. 01777 for /tmp
+func unixPermissions(mode os.FileMode) uint32 {
+	permissions := uint32(mode.Perm())
+	if mode&os.ModeSetuid != 0 {
+		permissions |= 04000
+	}
+	if mode&os.ModeSetgid != 0 {
+		permissions |= 02000
+	}
+	if mode&os.ModeSticky != 0 {
+		permissions |= 01000
+	}
+	return permissions
+}
+
+// resolveExistingPath resolves the symbolic links of a path, or of its closest existing parent
+// when it does not exist
+func resolveExistingPath(path string) string {
+	path = filepath.Clean(path)
+	for {
+		if resolved, err := filepath.EvalSymlinks(path); err == nil {
+			return resolved
+		}
+		parent := filepath.Dir(path)
+		if parent == path {
+			return path
+		}
+		path = parent
+	}
+}
+
+// findMount returns the mount a path is on, the last mounted when several are on the same mount
+// point, or nil if there is none
+func findMount(mounts []mountInfo, path string) *mountInfo {
+	var found *mountInfo
+	for i := range mounts {
+		mount := &mounts[i]
+		if path != mount.mountPoint && mount.mountPoint != "/" && !strings.HasPrefix(path, mount.mountPoint+"/") {
+			continue
+		}
+		if found == nil || len(mount.mountPoint) >= len(found.mountPoint) {
+			found = mount
+		}
+	}
+	return found
+}
+
+var mountInfoUnescaper = strings.NewReplacer(`\040`, " ", `\011`, "\t", `\012`, "\n", `\134`, `\`)
+
+// readMountInfo reads the mounts from /proc/self/mountinfo, e.g:
+//
+//	36 35 98:0 /mnt1 /mnt2 rw,noatime master:1 - ext3 /dev/root rw,errors=continue
+func readMountInfo(fsys fs.FS) ([]mountInfo, error) {
+	b, err := fs.ReadFile(fsys, "proc/self/mountinfo")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read mountinfo")
+	}
+
+	mounts := []mountInfo{}
+	scanner := bufio.NewScanner(bytes.NewReader(b))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		separator := -1
+		for i := 6; i < len(fields); i++ {
+			if fields[i] == "-" {
+				separator = i
+				break
+			}
+		}
+		if separator == -1 || separator+1 >= len(fields) {
+			continue
+		}
+
+		mounts = append(mounts, mountInfo{
+			mountPoint: mountInfoUnescaper.Replace(fields[4]),
+			fsType:     fields[separator+1],
+			options:    strings.Split(fields[5], ","),
+		})
+	}
+	return mounts, scanner.Err()
+}
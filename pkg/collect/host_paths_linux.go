@@ -0,0 +1,34 @@
+//go:build linux
+
+package collect
+
+import (
+	"os"
+	"strings"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+func pathOwnership(fi os.FileInfo) (int, int, bool) {
+	st, ok := fi.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 0, 0, false
+	}
+	return int(st.Uid), int(st.Gid), true
+}
+
+// pathSELinuxContext returns the SELinux label of a path, empty when it has none or SELinux is
+// not enabled
+func pathSELinuxContext(path string) string {
+	size, err := unix.Getxattr(path, "security.selinux", nil)
+	if err != nil || size <= 0 {
+		return ""
+	}
+	buf := make([]byte, size)
+	n, err := unix.Getxattr(path, "security.selinux", buf)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimRight(string(buf[:n]), "\x00")
+}
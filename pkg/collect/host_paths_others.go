@@ -0,0 +1,15 @@
+//go:build !linux
+
+package collect
+
+import (
+	"os"
+)
+
+func pathOwnership(_ os.FileInfo) (int, int, bool) {
+	return 0, 0, false
+}
+
+func pathSELinuxContext(_ string) string {
+	return ""
+}
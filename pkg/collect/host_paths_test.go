@@ -0,0 +1,87 @@
+package collect
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testMountInfo = `22 1 8:1 / / rw,relatime shared:1 - ext4 /dev/sda1 rw,errors=remount-ro
+25 22 0:22 / /tmp rw,nosuid,nodev shared:5 - tmpfs tmpfs rw
+31 22 8:2 / /var/lib rw,nosuid,nodev,noexec,relatime shared:12 - xfs /dev/sda2 rw,attr2
+32 31 8:3 / /var/lib/my\040data rw,relatime shared:13 - xfs /dev/sda3 rw
+`
+
+func Test_readMountInfo(t *testing.T) {
+	mounts, err := readMountInfo(fstest.MapFS{"proc/self/mountinfo": {Data: []byte(testMountInfo)}})
+	require.NoError(t, err)
+	require.Len(t, mounts, 4)
+	assert.Equal(t, mountInfo{
+		mountPoint: "/var/lib",
+		fsType:     "xfs",
+		options:    []string{"rw", "nosuid", "nodev", "noexec", "relatime"},
+	}, mounts[2])
+	assert.Equal(t, "/var/lib/my data", mounts[3].mountPoint)
+
+	_, err = readMountInfo(fstest.MapFS{})
+	assert.Error(t, err)
+}
+
+func Test_findMount(t *testing.T) {
+	mounts, err := readMountInfo(fstest.MapFS{"proc/self/mountinfo": {Data: []byte(testMountInfo)}})
+	require.NoError(t, err)
+
+	assert.Equal(t, "/var/lib", findMount(mounts, "/var/lib/kubelet").mountPoint)
+	assert.Equal(t, "/var/lib", findMount(mounts, "/var/lib").mountPoint)
+	assert.Equal(t, "/var/lib/my data", findMount(mounts, "/var/lib/my data/db").mountPoint)
+	assert.Equal(t, "/", findMount(mounts, "/var/library").mountPoint)
+	assert.Equal(t, "/", findMount(mounts, "/etc").mountPoint)
+	assert.Nil(t, findMount(nil, "/etc"))
+}
+
+func Test_unixPermissions(t *testing.T) {
+	assert.Equal(t, uint32(0755), unixPermissions(0755|os.ModeDir))
+	assert.Equal(t, uint32(01777), unixPermissions(0777|os.ModeDir|os.ModeSticky))
+	assert.Equal(t, uint32(04755), unixPermissions(0755|os.ModeSetuid))
+}
+
+func TestCollectHostPaths(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "admin.conf")
+	require.NoError(t, os.WriteFile(file, []byte("apiVersion: v1"), 0600))
+	require.NoError(t, os.Chmod(file, 0640))
+
+	// the temporary directory is on the root mount
+	mountInfo := "22 1 8:1 / / rw,relatime shared:1 - ext4 /dev/sda1 rw\n"
+	c := &CollectHostPaths{
+		hostCollector: &troubleshootv1beta2.HostPaths{Paths: []string{file, filepath.Join(dir, "kubelet", "pods")}},
+		BundlePath:    "",
+		fs:            fstest.MapFS{"proc/self/mountinfo": {Data: []byte(mountInfo)}},
+	}
+
+	result, err := c.Collect(nil)
+	require.NoError(t, err)
+	require.Contains(t, result, HostPathsPath)
+
+	paths := []PathInfo{}
+	require.NoError(t, json.Unmarshal(result[HostPathsPath], &paths))
+	require.Len(t, paths, 2)
+
+	assert.Equal(t, file, paths[0].Path)
+	assert.True(t, paths[0].Exists)
+	assert.Equal(t, "file", paths[0].Type)
+	assert.Equal(t, "0640", paths[0].Mode)
+	assert.Equal(t, "/", paths[0].MountPoint)
+	assert.Equal(t, "ext4", paths[0].FSType)
+	assert.Equal(t, []string{"rw", "relatime"}, paths[0].MountOptions)
+
+	assert.False(t, paths[1].Exists)
+	assert.Empty(t, paths[1].Mode)
+	assert.Equal(t, "/", paths[1].MountPoint)
+}
@@ -0,0 +1,116 @@
+package collect
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/fs"
+	"strconv"
+	"strings"
+
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+)
+
+const (
+	// HostPrivilegeRawDisk is the privilege to read block devices, e.g. to probe their filesystems
+	HostPrivilegeRawDisk = "raw disk access"
+	// HostPrivilegeKernelLog is the privilege to read the kernel log
+	HostPrivilegeKernelLog = "kernel log access"
+	// HostPrivilegeFirewall is the privilege to read the iptables and nftables rules
+	HostPrivilegeFirewall = "firewall access"
+)
+
+// capabilities from include/uapi/linux/capability.h
+const (
+	capDACOverride   = 1
+	capDACReadSearch = 2
+	capNetAdmin      = 12
+	capSysAdmin      = 21
+	capSyslog        = 34
+)
+
+// HostPrivileges records which of the privileges host collectors need are available
+type HostPrivileges map[string]bool
+
+// DetectHostPrivileges detects the privileges of the current process from its effective
+// capabilities. When they cannot be read, e.g. on hosts other than Linux, every privilege is
+// assumed to be available and collectors fail as they did before.
+func DetectHostPrivileges(fsys fs.FS) HostPrivileges {
+	privileges := HostPrivileges{
+		HostPrivilegeRawDisk:   true,
+		HostPrivilegeKernelLog: true,
+		HostPrivilegeFirewall:  true,
+	}
+
+	status, err := fs.ReadFile(fsys, "proc/self/status")
+	if err != nil {
+		return privileges
+	}
+	capabilities, ok := parseEffectiveCapabilities(status)
+	if !ok {
+		return privileges
+	}
+
+	has := func(capability uint) bool {
+		return capabilities&(1<<capability) != 0
+	}
+
+	privileges[HostPrivilegeRawDisk] = has(capDACOverride) || has(capDACReadSearch)
+	privileges[HostPrivilegeFirewall] = has(capNetAdmin)
+	privileges[HostPrivilegeKernelLog] = has(capSyslog) || has(capSysAdmin)
+	if restrict, err := fs.ReadFile(fsys, "proc/sys/kernel/dmesg_restrict"); err == nil && strings.TrimSpace(string(restrict)) == "0" {
+		privileges[HostPrivilegeKernelLog] = true
+	}
+
+	return privileges
+}
+
+// Missing returns the privileges that are required but not available
+func (p HostPrivileges) Missing(required []string) []string {
+	missing := []string{}
+	for _, privilege := range required {
+		if available, ok := p[privilege]; ok && !available {
+			missing = append(missing, privilege)
+		}
+	}
+	return missing
+}
+
+// RequiredHostPrivileges returns the privileges a host collector needs to collect everything.
+// Collectors run without them on a best effort basis: the parts that need a missing privilege
+// are left out, and the collector is recorded as skipped for the missing privileges.
+func RequiredHostPrivileges(spec *troubleshootv1beta2.HostCollect) []string {
+	switch {
+	case spec.BlockDevices != nil:
+		// lsblk probes devices that udev has not, which needs read access to the devices
+		return []string{HostPrivilegeRawDisk}
+	case spec.HostJournald != nil && spec.HostJournald.Dmesg:
+		return []string{HostPrivilegeKernelLog}
+	case spec.HostNetworkConfig != nil:
+		return []string{HostPrivilegeFirewall}
+	}
+	return nil
+}
+
+// insufficientPrivilegesError is recorded in the output of a collector in place of what it could not
+// collect without the privilege
+func insufficientPrivilegesError(privilege string) error {
+	return fmt.Errorf("%s: %s is required", SkipReasonInsufficientPrivileges, privilege)
+}
+
+// parseEffectiveCapabilities returns the CapEff bitmask of /proc/<pid>/status
+func parseEffectiveCapabilities(status []byte) (uint64, bool) {
+	scanner := bufio.NewScanner(bytes.NewReader(status))
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), ":")
+		if !ok || key != "CapEff" {
+			continue
+		}
+		capabilities, err := strconv.ParseUint(strings.TrimSpace(value), 16, 64)
+		if err != nil {
+			return 0, false
+		}
+		return capabilities, true
+	}
+	return 0, false
+}
@@ -0,0 +1,78 @@
+package collect
+
+import (
+	"testing"
+	"testing/fstest"
+
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetectHostPrivileges(t *testing.T) {
+	status := func(capEff string) *fstest.MapFile {
+		return &fstest.MapFile{Data: []byte("Name:\tpreflight\nCapInh:\t0000000000000000\nCapEff:\t" + capEff + "\n")}
+	}
+
+	tests := []struct {
+		name string
+		fs   fstest.MapFS
+		want HostPrivileges
+	}{
+		{
+			name: "root",
+			fs:   fstest.MapFS{"proc/self/status": status("000001ffffffffff")},
+			want: HostPrivileges{HostPrivilegeRawDisk: true, HostPrivilegeKernelLog: true, HostPrivilegeFirewall: true},
+		},
+		{
+			name: "unprivileged",
+			fs:   fstest.MapFS{"proc/self/status": status("0000000000000000")},
+			want: HostPrivileges{HostPrivilegeRawDisk: false, HostPrivilegeKernelLog: false, HostPrivilegeFirewall: false},
+		},
+		{
+			name: "unprivileged with unrestricted dmesg",
+			fs: fstest.MapFS{
+				"proc/self/status":               status("0000000000000000"),
+				"proc/sys/kernel/dmesg_restrict": {Data: []byte("0\n")},
+			},
+			want: HostPrivileges{HostPrivilegeRawDisk: false, HostPrivilegeKernelLog: true, HostPrivilegeFirewall: false},
+		},
+		{
+			name: "net admin",
+			fs:   fstest.MapFS{"proc/self/status": status("0000000000001000")},
+			want: HostPrivileges{HostPrivilegeRawDisk: false, HostPrivilegeKernelLog: false, HostPrivilegeFirewall: true},
+		},
+		{
+			name: "no proc",
+			fs:   fstest.MapFS{},
+			want: HostPrivileges{HostPrivilegeRawDisk: true, HostPrivilegeKernelLog: true, HostPrivilegeFirewall: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, DetectHostPrivileges(tt.fs))
+		})
+	}
+}
+
+func TestHostPrivileges_Missing(t *testing.T) {
+	privileges := HostPrivileges{HostPrivilegeRawDisk: true, HostPrivilegeFirewall: false}
+
+	assert.Equal(t, []string{HostPrivilegeFirewall}, privileges.Missing([]string{HostPrivilegeRawDisk, HostPrivilegeFirewall}))
+	assert.Equal(t, []string{}, privileges.Missing(nil))
+}
+
+func TestRequiredHostPrivileges(t *testing.T) {
+	assert.Equal(t, []string{HostPrivilegeKernelLog}, RequiredHostPrivileges(&troubleshootv1beta2.HostCollect{
+		HostJournald: &troubleshootv1beta2.HostJournald{Dmesg: true},
+	}))
+	assert.Nil(t, RequiredHostPrivileges(&troubleshootv1beta2.HostCollect{
+		HostJournald: &troubleshootv1beta2.HostJournald{Units: []string{"kubelet"}},
+	}))
+	assert.Equal(t, []string{HostPrivilegeFirewall}, RequiredHostPrivileges(&troubleshootv1beta2.HostCollect{
+		HostNetworkConfig: &troubleshootv1beta2.HostNetworkConfig{},
+	}))
+	assert.Nil(t, RequiredHostPrivileges(&troubleshootv1beta2.HostCollect{
+		CPU: &troubleshootv1beta2.CPU{},
+	}))
+}
@@ -0,0 +1,283 @@
+package collect
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"golang.org/x/net/http/httpproxy"
+	"k8s.io/klog/v2"
+)
+
+// Ensure `CollectHostProxy` implements `HostCollector` interface at compile time.
+var _ HostCollector = (*CollectHostProxy)(nil)
+
+const HostProxyPath = `host-collectors/system/proxy.json`
+const HostProxyFileName = `proxy.json`
+
+const (
+	defaultProxyEndpointTimeout = 10 * time.Second
+	etcEnvironmentPath          = "/etc/environment"
+)
+
+// The sources of the proxy settings of the host
+const (
+	ProxySourceEnvironment    = "environment"
+	ProxySourceEtcEnvironment = "/etc/environment"
+	ProxySourceContainerdUnit = "containerd"
+	ProxySourceKubeletUnit    = "kubelet"
+)
+
+// proxySources are the sources of the proxy settings in the order they are preferred to test
+// the endpoints
+var proxySources = []string{ProxySourceEnvironment, ProxySourceEtcEnvironment, ProxySourceContainerdUnit, ProxySourceKubeletUnit}
+
+// HostProxyInfo is the output of the host proxy collector
+type HostProxyInfo struct {
+	// Sources are the proxy settings by source: environment for the environment of the
+	// collector, /etc/environment, and the Environment of the containerd and kubelet units
+	Sources map[string]ProxySettings `json:"sources"`
+	// EndpointsSource is the source of the proxy settings that the endpoints are tested with,
+	// the first one that sets a proxy. Empty when the endpoints are tested without a proxy.
+	EndpointsSource string                `json:"endpointsSource,omitempty"`
+	Endpoints       []ProxyEndpointResult `json:"endpoints,omitempty"`
+	Errors          map[string]string     `json:"errors,omitempty"`
+}
+
+type ProxyEndpointResult struct {
+	URL string `json:"url"`
+	// Proxy is the proxy of the connection, empty when NO_PROXY or the lack of a proxy made it direct
+	Proxy string `json:"proxy,omitempty"`
+	// Reachable is whether the endpoint responded, whatever the status code
+	Reachable  bool   `json:"reachable"`
+	StatusCode int    `json:"statusCode,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+type CollectHostProxy struct {
+	hostCollector *troubleshootv1beta2.HostProxy
+	BundlePath    string
+	fs            fs.FS
+}
+
+func (c *CollectHostProxy) Title() string {
+	return hostCollectorTitleOrDefault(c.hostCollector.HostCollectorMeta, "Proxy")
+}
+
+func (c *CollectHostProxy) IsExcluded() (bool, error) {
+	return isExcluded(c.hostCollector.Exclude)
+}
+
+func (c *CollectHostProxy) Collect(progressChan chan<- interface{}) (map[string][]byte, error) {
+	if err := validateCIDRs(c.hostCollector.CIDRs); err != nil {
+		return nil, err
+	}
+
+	timeout := defaultProxyEndpointTimeout
+	if c.hostCollector.Timeout != "" {
+		var err error
+		timeout, err = time.ParseDuration(c.hostCollector.Timeout)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse timeout %q", c.hostCollector.Timeout)
+		}
+	}
+
+	info := HostProxyInfo{
+		Sources: map[string]ProxySettings{},
+		Errors:  map[string]string{},
+	}
+
+	addFailure := func(source string, err error) {
+		klog.V(2).Infof("failed to collect proxy settings of %s: %v", source, err)
+		info.Errors[source] = err.Error()
+	}
+
+	envs := map[string]map[string]string{
+		ProxySourceEnvironment: parseEnvironment(os.Environ()),
+	}
+
+	etcEnvironment, err := readOptionalFile(c.fs, etcEnvironmentPath)
+	if err != nil {
+		addFailure(ProxySourceEtcEnvironment, err)
+	} else {
+		envs[ProxySourceEtcEnvironment] = parseEnvironmentFile(etcEnvironment)
+	}
+
+	for _, unit := range []string{ProxySourceContainerdUnit, ProxySourceKubeletUnit} {
+		out, err := execCommand("systemctl", "show", unit, "--property=Environment", "--value").Output()
+		if err != nil {
+			addFailure(unit, errors.Wrapf(err, "failed to show environment of unit %s", unit))
+			continue
+		}
+		envs[unit] = parseEnvironment(splitSystemdEnvironment(string(out)))
+	}
+
+	var endpointsEnv map[string]string
+	for _, source := range proxySources {
+		env, ok := envs[source]
+		if !ok {
+			continue
+		}
+		settings := proxySettingsFromEnv(env, c.hostCollector.CIDRs)
+		info.Sources[source] = settings
+		if endpointsEnv == nil && settings.IsConfigured() {
+			info.EndpointsSource = source
+			endpointsEnv = env
+		}
+	}
+
+	config := &httpproxy.Config{
+		HTTPProxy:  lookupProxyEnv(endpointsEnv, "HTTP_PROXY"),
+		HTTPSProxy: lookupProxyEnv(endpointsEnv, "HTTPS_PROXY"),
+		NoProxy:    lookupProxyEnv(endpointsEnv, "NO_PROXY"),
+	}
+	for _, endpoint := range c.hostCollector.Endpoints {
+		info.Endpoints = append(info.Endpoints, checkProxyEndpoint(endpoint, config, timeout))
+	}
+
+	if len(info.Errors) == 0 {
+		info.Errors = nil
+	}
+
+	b, err := json.Marshal(info)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal proxy info")
+	}
+
+	output := NewResult()
+	output.SaveResult(c.BundlePath, HostProxyPath, bytes.NewBuffer(b))
+
+	return output, nil
+}
+
+// lookupProxyEnv returns a proxy variable, the upper case one taking precedence
+func lookupProxyEnv(env map[string]string, name string) string {
+	if value := env[name]; value != "" {
+		return value
+	}
+	return env[strings.ToLower(name)]
+}
+
+// parseEnvironment returns the proxy variables of a list of KEY=value
+func parseEnvironment(environ []string) map[string]string {
+	env := map[string]string{}
+	for _, kv := range environ {
+		key, value, ok := strings.Cut(kv, "=")
+		if ok && isProxyEnvVar(key) {
+			env[key] = value
+		}
+	}
+	return env
+}
+
+// parseEnvironmentFile returns the proxy variables of a file like /etc/environment, with a
+// KEY=value per line that may be quoted or exported
+func parseEnvironmentFile(b []byte) map[string]string {
+	environ := []string{}
+	scanner := bufio.NewScanner(bytes.NewReader(b))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimSpace(strings.TrimPrefix(line, "export "))
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+			value = value[1 : len(value)-1]
+		}
+		environ = append(environ, strings.TrimSpace(key)+"="+value)
+	}
+	return parseEnvironment(environ)
+}
+
+// splitSystemdEnvironment splits the Environment property of a unit, which systemctl prints as
+// space separated assignments quoting those with spaces, e.g. `A=1 "B=2 3"`
+func splitSystemdEnvironment(s string) []string {
+	environ := []string{}
+	var current strings.Builder
+	inQuotes, escaped, started := false, false, false
+	for _, r := range strings.TrimSpace(s) {
+		switch {
+		case escaped:
+			current.WriteRune(r)
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == '"':
+			inQuotes = !inQuotes
+			started = true
+		case (r == ' ' || r == '\n') && !inQuotes:
+			if started {
+				environ = append(environ, current.String())
+				current.Reset()
+				started = false
+			}
+		default:
+			current.WriteRune(r)
+			started = true
+		}
+	}
+	if started {
+		environ = append(environ, current.String())
+	}
+	return environ
+}
+
+// checkProxyEndpoint sends a GET to an endpoint through the proxy that a proxy config selects
+// for it. Redirects are not followed, any response of the endpoint makes it reachable.
+func checkProxyEndpoint(endpoint string, config *httpproxy.Config, timeout time.Duration) ProxyEndpointResult {
+	result := ProxyEndpointResult{URL: endpoint}
+
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		result.Error = errors.Wrap(err, "failed to parse endpoint").Error()
+		return result
+	}
+	proxyURL, err := config.ProxyFunc()(u)
+	if err != nil {
+		result.Error = errors.Wrap(err, "failed to select proxy").Error()
+		return result
+	}
+	if proxyURL != nil {
+		result.Proxy = redactProxyURL(proxyURL.String())
+	}
+
+	client := &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			Proxy: func(*http.Request) (*url.URL, error) {
+				return proxyURL, nil
+			},
+		},
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	resp, err := client.Get(endpoint)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	defer resp.Body.Close()
+
+	result.StatusCode = resp.StatusCode
+	if proxyURL != nil && resp.StatusCode == http.StatusProxyAuthRequired {
+		result.Error = "proxy authentication required"
+		return result
+	}
+	result.Reachable = true
+	return result
+}
@@ -0,0 +1,128 @@
+package collect
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/http/httpproxy"
+)
+
+func Test_splitSystemdEnvironment(t *testing.T) {
+	tests := []struct {
+		name string
+		env  string
+		want []string
+	}{
+		{
+			name: "empty",
+			env:  "\n",
+			want: []string{},
+		},
+		{
+			name: "assignments",
+			env:  "HTTP_PROXY=http://proxy:3128 NO_PROXY=localhost,10.96.0.0/12\n",
+			want: []string{"HTTP_PROXY=http://proxy:3128", "NO_PROXY=localhost,10.96.0.0/12"},
+		},
+		{
+			name: "quoted",
+			env:  `"GREETING=hello world" HTTPS_PROXY=http://proxy:3128 "ESCAPED=a\"b"`,
+			want: []string{"GREETING=hello world", "HTTPS_PROXY=http://proxy:3128", `ESCAPED=a"b`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, splitSystemdEnvironment(tt.env))
+		})
+	}
+}
+
+func Test_parseEnvironmentFile(t *testing.T) {
+	b := []byte(`# proxy settings
+PATH="/usr/local/sbin:/usr/local/bin:/usr/sbin:/usr/bin"
+http_proxy="http://proxy:3128"
+export HTTPS_PROXY='http://proxy:3129'
+NO_PROXY = localhost,127.0.0.1
+`)
+
+	assert.Equal(t, map[string]string{
+		"http_proxy":  "http://proxy:3128",
+		"HTTPS_PROXY": "http://proxy:3129",
+		"NO_PROXY":    "localhost,127.0.0.1",
+	}, parseEnvironmentFile(b))
+}
+
+func Test_checkProxyEndpoint(t *testing.T) {
+	proxied := []string{}
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = append(proxied, r.URL.String())
+		if r.Header.Get("Proxy-Authorization") == "" && r.URL.Host == "auth.example.com" {
+			w.WriteHeader(http.StatusProxyAuthRequired)
+			return
+		}
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer proxy.Close()
+
+	direct := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/elsewhere", http.StatusFound)
+	}))
+	defer direct.Close()
+
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closed.Close()
+
+	config := &httpproxy.Config{
+		HTTPProxy: proxy.URL,
+		NoProxy:   "internal.example.com",
+	}
+
+	tests := []struct {
+		name     string
+		endpoint string
+		config   *httpproxy.Config
+		want     ProxyEndpointResult
+		wantErr  bool
+	}{
+		{
+			name:     "through proxy",
+			endpoint: "http://registry.example.com/v2/",
+			config:   config,
+			want:     ProxyEndpointResult{URL: "http://registry.example.com/v2/", Proxy: proxy.URL, Reachable: true, StatusCode: http.StatusUnauthorized},
+		},
+		{
+			name:     "proxy authentication",
+			endpoint: "http://auth.example.com/",
+			config:   config,
+			want:     ProxyEndpointResult{URL: "http://auth.example.com/", Proxy: proxy.URL, StatusCode: http.StatusProxyAuthRequired, Error: "proxy authentication required"},
+		},
+		{
+			name:     "direct without following redirects",
+			endpoint: direct.URL,
+			config:   &httpproxy.Config{},
+			want:     ProxyEndpointResult{URL: direct.URL, Reachable: true, StatusCode: http.StatusFound},
+		},
+		{
+			name:     "proxy unreachable",
+			endpoint: "http://registry.example.com/v2/",
+			config:   &httpproxy.Config{HTTPProxy: closed.URL},
+			want:     ProxyEndpointResult{URL: "http://registry.example.com/v2/", Proxy: closed.URL},
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := checkProxyEndpoint(tt.endpoint, tt.config, 5*time.Second)
+			if tt.wantErr {
+				require.NotEmpty(t, got.Error)
+				got.Error = ""
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	assert.Equal(t, []string{"http://registry.example.com/v2/", "http://auth.example.com/"}, proxied)
+}
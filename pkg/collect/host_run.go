@@ -23,11 +23,14 @@ type HostRunInfo struct {
 	OutputDir string   `json:"outputDir"`
 	Input     string   `json:"input"`
 	Env       []string `json:"env"`
+	// ParseError is the failure to parse the output in the format of the parse hint
+	ParseError string `json:"parseError,omitempty"`
 }
 
 type CollectHostRun struct {
 	hostCollector *troubleshootv1beta2.HostRun
 	BundlePath    string
+	Context       context.Context
 }
 
 func (c *CollectHostRun) Title() string {
@@ -57,7 +60,7 @@ func (c *CollectHostRun) Collect(progressChan chan<- interface{}) (map[string][]
 		errInvalidDuration error
 	)
 
-	ctx := context.Background()
+	ctx := collectorContext(c.Context)
 	cmdPath := c.attemptToConvertCmdToAbsPath()
 
 	if runHostCollector.Timeout != "" {
@@ -68,7 +71,7 @@ func (c *CollectHostRun) Collect(progressChan chan<- interface{}) (map[string][]
 	}
 
 	if timeout <= time.Duration(0) {
-		cmd = exec.Command(cmdPath, runHostCollector.Args...)
+		cmd = exec.CommandContext(ctx, cmdPath, runHostCollector.Args...)
 	} else {
 		timeoutCtx, cancel := context.WithTimeout(ctx, timeout)
 		defer cancel()
@@ -154,6 +157,14 @@ func (c *CollectHostRun) Collect(progressChan chan<- interface{}) (map[string][]
 	resultInfo := filepath.Join("host-collectors/run-host", collectorName+"-info.json")
 	result := filepath.Join("host-collectors/run-host", collectorName+".txt")
 
+	if runHostCollector.Parse != "" {
+		parsedResult := filepath.Join("host-collectors/run-host", collectorName+"-parsed.json")
+		if err := saveParsedOutput(output, c.BundlePath, parsedResult, runHostCollector.Parse, stdout.Bytes()); err != nil {
+			klog.V(2).Infof("failed to parse the output of %s: %v", collectorName, err)
+			runInfo.ParseError = err.Error()
+		}
+	}
+
 	b, err := json.Marshal(runInfo)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to marshal run host result")
@@ -0,0 +1,406 @@
+package collect
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+
+	toml "github.com/pelletier/go-toml/v2"
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/yaml"
+)
+
+// Ensure `CollectHostSecurity` implements `HostCollector` interface at compile time.
+var _ HostCollector = (*CollectHostSecurity)(nil)
+
+const HostSecurityPath = `host-collectors/system/security.json`
+const HostSecurityFileName = `security.json`
+
+const (
+	SELinuxModeEnforcing  = "enforcing"
+	SELinuxModePermissive = "permissive"
+	SELinuxModeDisabled   = "disabled"
+)
+
+const (
+	defaultContainerdConfigPath = "/etc/containerd/config.toml"
+	defaultKubeletConfigPath    = "/var/lib/kubelet/config.yaml"
+	crioConfigPath              = "/etc/crio/crio.conf"
+	crioConfigDropInDir         = "/etc/crio/crio.conf.d"
+)
+
+// containerdCRIPlugins are the plugins of containerd that hold the security settings of the CRI,
+// in config version 2 and in version 3 of containerd 2.0
+var containerdCRIPlugins = []string{"io.containerd.grpc.v1.cri", "io.containerd.cri.v1.runtime"}
+
+// SecurityInfo is the output of the host security collector. Each source is collected on a
+// best effort basis, failures other than missing files are recorded in Errors keyed by source.
+type SecurityInfo struct {
+	SELinux    SELinuxInfo               `json:"selinux"`
+	AppArmor   AppArmorInfo              `json:"apparmor"`
+	Seccomp    SeccompInfo               `json:"seccomp"`
+	Containerd *ContainerRuntimeSecurity `json:"containerd,omitempty"`
+	CRIO       *ContainerRuntimeSecurity `json:"crio,omitempty"`
+	Errors     map[string]string         `json:"errors,omitempty"`
+}
+
+type SELinuxInfo struct {
+	// Mode is the current mode, one of enforcing, permissive or disabled
+	Mode string `json:"mode"`
+	// ConfiguredMode and Policy are SELINUX and SELINUXTYPE of /etc/selinux/config, the mode
+	// and policy after a reboot
+	ConfiguredMode string          `json:"configuredMode,omitempty"`
+	Policy         string          `json:"policy,omitempty"`
+	Booleans       map[string]bool `json:"booleans,omitempty"`
+}
+
+type AppArmorInfo struct {
+	Enabled bool `json:"enabled"`
+	// Profiles are the modes of the loaded profiles by name, e.g. enforce or complain
+	Profiles map[string]string `json:"profiles,omitempty"`
+}
+
+type SeccompInfo struct {
+	// Available is whether the kernel supports seccomp
+	Available bool `json:"available"`
+	// KubeletDefault is the seccompDefault of the kubelet config, whether containers run with
+	// the RuntimeDefault profile unless they set another one. nil when the kubelet config was
+	// not found.
+	KubeletDefault *bool `json:"kubeletDefault,omitempty"`
+}
+
+// ContainerRuntimeSecurity is the security configuration of containerd or CRI-O
+type ContainerRuntimeSecurity struct {
+	ConfigPaths []string `json:"configPaths"`
+	// SELinux is whether the runtime labels containers
+	SELinux bool `json:"selinux"`
+	// AppArmor is whether the runtime applies an AppArmor profile to containers
+	AppArmor        bool   `json:"apparmor"`
+	AppArmorProfile string `json:"apparmorProfile,omitempty"`
+	// SeccompProfile is the profile of the runtime for containers with the RuntimeDefault or
+	// unset profile, empty for the default of the runtime
+	SeccompProfile string `json:"seccompProfile,omitempty"`
+}
+
+type CollectHostSecurity struct {
+	hostCollector *troubleshootv1beta2.HostSecurity
+	BundlePath    string
+	fs            fs.FS
+}
+
+func (c *CollectHostSecurity) Title() string {
+	return hostCollectorTitleOrDefault(c.hostCollector.HostCollectorMeta, "Security")
+}
+
+func (c *CollectHostSecurity) IsExcluded() (bool, error) {
+	return isExcluded(c.hostCollector.Exclude)
+}
+
+func (c *CollectHostSecurity) Collect(progressChan chan<- interface{}) (map[string][]byte, error) {
+	info := SecurityInfo{Errors: map[string]string{}}
+
+	addFailure := func(source string, err error) {
+		klog.V(2).Infof("failed to collect %s: %v", source, err)
+		info.Errors[source] = err.Error()
+	}
+
+	selinux, err := collectSELinux(c.fs)
+	if err != nil {
+		addFailure("selinux", err)
+	}
+	info.SELinux = selinux
+
+	apparmor, err := collectAppArmor(c.fs)
+	if err != nil {
+		addFailure("apparmor", err)
+	}
+	info.AppArmor = apparmor
+
+	kubeletConfigPath := c.hostCollector.KubeletConfigPath
+	if kubeletConfigPath == "" {
+		kubeletConfigPath = defaultKubeletConfigPath
+	}
+	seccomp, err := collectSeccomp(c.fs, kubeletConfigPath)
+	if err != nil {
+		addFailure("seccomp", err)
+	}
+	info.Seccomp = seccomp
+
+	containerdConfigPath := c.hostCollector.ContainerdConfigPath
+	if containerdConfigPath == "" {
+		containerdConfigPath = defaultContainerdConfigPath
+	}
+	if info.Containerd, err = collectContainerdSecurity(c.fs, containerdConfigPath); err != nil {
+		addFailure("containerd", err)
+	}
+	if info.CRIO, err = collectCRIOSecurity(c.fs); err != nil {
+		addFailure("crio", err)
+	}
+
+	if len(info.Errors) == 0 {
+		info.Errors = nil
+	}
+
+	b, err := json.Marshal(info)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal security info")
+	}
+
+	output := NewResult()
+	output.SaveResult(c.BundlePath, HostSecurityPath, bytes.NewBuffer(b))
+
+	return output, nil
+}
+
+// hostFSPath returns an absolute path of the host as a path of a fs.FS rooted at /
+func hostFSPath(p string) string {
+	return strings.TrimPrefix(path.Clean(p), "/")
+}
+
+// readOptionalFile reads a file that may not exist, returning nil without an error when it
+// does not
+func readOptionalFile(fsys fs.FS, p string) ([]byte, error) {
+	b, err := fs.ReadFile(fsys, hostFSPath(p))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	return b, err
+}
+
+func collectSELinux(fsys fs.FS) (SELinuxInfo, error) {
+	info := SELinuxInfo{Mode: SELinuxModeDisabled}
+
+	config, err := readOptionalFile(fsys, "/etc/selinux/config")
+	if err != nil {
+		return info, errors.Wrap(err, "failed to read selinux config")
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(config))
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(strings.TrimSpace(scanner.Text()), "=")
+		if !ok || strings.HasPrefix(key, "#") {
+			continue
+		}
+		switch strings.TrimSpace(key) {
+		case "SELINUX":
+			info.ConfiguredMode = strings.TrimSpace(value)
+		case "SELINUXTYPE":
+			info.Policy = strings.TrimSpace(value)
+		}
+	}
+
+	// selinuxfs is only mounted when SELinux is enabled
+	enforce, err := readOptionalFile(fsys, "/sys/fs/selinux/enforce")
+	if err != nil {
+		return info, errors.Wrap(err, "failed to read selinux enforce")
+	}
+	if enforce == nil {
+		return info, nil
+	}
+	info.Mode = SELinuxModePermissive
+	if strings.TrimSpace(string(enforce)) == "1" {
+		info.Mode = SELinuxModeEnforcing
+	}
+
+	booleans, err := fs.ReadDir(fsys, hostFSPath("/sys/fs/selinux/booleans"))
+	if err != nil {
+		return info, errors.Wrap(err, "failed to list selinux booleans")
+	}
+	info.Booleans = map[string]bool{}
+	for _, boolean := range booleans {
+		// the current and the pending values, e.g. "1 1"
+		b, err := fs.ReadFile(fsys, hostFSPath(path.Join("/sys/fs/selinux/booleans", boolean.Name())))
+		if err != nil {
+			return info, errors.Wrapf(err, "failed to read selinux boolean %s", boolean.Name())
+		}
+		fields := strings.Fields(string(b))
+		info.Booleans[boolean.Name()] = len(fields) > 0 && fields[0] == "1"
+	}
+
+	return info, nil
+}
+
+func collectAppArmor(fsys fs.FS) (AppArmorInfo, error) {
+	info := AppArmorInfo{}
+
+	enabled, err := readOptionalFile(fsys, "/sys/module/apparmor/parameters/enabled")
+	if err != nil {
+		return info, errors.Wrap(err, "failed to read apparmor parameters")
+	}
+	info.Enabled = strings.TrimSpace(string(enabled)) == "Y"
+	if !info.Enabled {
+		return info, nil
+	}
+
+	// only readable by root
+	profiles, err := fs.ReadFile(fsys, hostFSPath("/sys/kernel/security/apparmor/profiles"))
+	if err != nil {
+		return info, errors.Wrap(err, "failed to read apparmor profiles")
+	}
+	info.Profiles = parseAppArmorProfiles(profiles)
+
+	return info, nil
+}
+
+// parseAppArmorProfiles parses the loaded AppArmor profiles, e.g:
+//
+//	cri-containerd.apparmor.d (enforce)
+//	/usr/sbin/chronyd (enforce)
+func parseAppArmorProfiles(b []byte) map[string]string {
+	profiles := map[string]string{}
+	scanner := bufio.NewScanner(bytes.NewReader(b))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		i := strings.LastIndex(line, " (")
+		if i == -1 || !strings.HasSuffix(line, ")") {
+			continue
+		}
+		profiles[line[:i]] = line[i+2 : len(line)-1]
+	}
+	return profiles
+}
+
+func collectSeccomp(fsys fs.FS, kubeletConfigPath string) (SeccompInfo, error) {
+	info := SeccompInfo{}
+
+	status, err := readOptionalFile(fsys, "/proc/self/status")
+	if err != nil {
+		return info, errors.Wrap(err, "failed to read process status")
+	}
+	for _, line := range strings.Split(string(status), "\n") {
+		if strings.HasPrefix(line, "Seccomp:") {
+			info.Available = true
+			break
+		}
+	}
+
+	b, err := readOptionalFile(fsys, kubeletConfigPath)
+	if err != nil {
+		return info, errors.Wrap(err, "failed to read kubelet config")
+	}
+	if b == nil {
+		return info, nil
+	}
+	kubeletConfig := struct {
+		SeccompDefault *bool `json:"seccompDefault"`
+	}{}
+	if err := yaml.Unmarshal(b, &kubeletConfig); err != nil {
+		return info, errors.Wrap(err, "failed to parse kubelet config")
+	}
+	info.KubeletDefault = kubeletConfig.SeccompDefault
+	if info.KubeletDefault == nil {
+		disabled := false
+		info.KubeletDefault = &disabled
+	}
+
+	return info, nil
+}
+
+// collectContainerdSecurity returns the security settings of the CRI plugin of containerd, or
+// nil when it has no config file
+func collectContainerdSecurity(fsys fs.FS, configPath string) (*ContainerRuntimeSecurity, error) {
+	b, err := readOptionalFile(fsys, configPath)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read containerd config")
+	}
+	if b == nil {
+		return nil, nil
+	}
+
+	config := struct {
+		Plugins map[string]struct {
+			EnableSELinux       bool   `toml:"enable_selinux"`
+			DisableAppArmor     bool   `toml:"disable_apparmor"`
+			UnsetSeccompProfile string `toml:"unset_seccomp_profile"`
+		} `toml:"plugins"`
+	}{}
+	if err := toml.Unmarshal(b, &config); err != nil {
+		return nil, errors.Wrap(err, "failed to parse containerd config")
+	}
+
+	security := &ContainerRuntimeSecurity{ConfigPaths: []string{configPath}, AppArmor: true}
+	for _, name := range containerdCRIPlugins {
+		plugin, ok := config.Plugins[name]
+		if !ok {
+			continue
+		}
+		security.SELinux = plugin.EnableSELinux
+		security.AppArmor = !plugin.DisableAppArmor
+		security.SeccompProfile = plugin.UnsetSeccompProfile
+	}
+	return security, nil
+}
+
+// crioConfigPaths returns the config file of CRI-O followed by its drop-in files, which
+// override it in lexical order. The files may not exist.
+func crioConfigPaths(fsys fs.FS) ([]string, error) {
+	paths := []string{crioConfigPath}
+	dropIns, err := fs.ReadDir(fsys, hostFSPath(crioConfigDropInDir))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, errors.Wrap(err, "failed to list crio drop-in configs")
+	}
+	dropInPaths := []string{}
+	for _, dropIn := range dropIns {
+		if !dropIn.IsDir() && strings.HasSuffix(dropIn.Name(), ".conf") {
+			dropInPaths = append(dropInPaths, path.Join(crioConfigDropInDir, dropIn.Name()))
+		}
+	}
+	sort.Strings(dropInPaths)
+	return append(paths, dropInPaths...), nil
+}
+
+// collectCRIOSecurity returns the security settings of CRI-O from its config file and its
+// drop-in files, or nil when it has none of them
+func collectCRIOSecurity(fsys fs.FS) (*ContainerRuntimeSecurity, error) {
+	paths, err := crioConfigPaths(fsys)
+	if err != nil {
+		return nil, err
+	}
+
+	var security *ContainerRuntimeSecurity
+	for _, p := range paths {
+		b, err := readOptionalFile(fsys, p)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read crio config %s", p)
+		}
+		if b == nil {
+			continue
+		}
+		if security == nil {
+			// the defaults of CRI-O
+			security = &ContainerRuntimeSecurity{ConfigPaths: []string{}, AppArmor: true, AppArmorProfile: "crio-default"}
+		}
+		security.ConfigPaths = append(security.ConfigPaths, p)
+
+		config := struct {
+			CRIO struct {
+				Runtime struct {
+					SELinux         *bool   `toml:"selinux"`
+					SeccompProfile  *string `toml:"seccomp_profile"`
+					AppArmorProfile *string `toml:"apparmor_profile"`
+				} `toml:"runtime"`
+			} `toml:"crio"`
+		}{}
+		if err := toml.Unmarshal(b, &config); err != nil {
+			return nil, errors.Wrapf(err, "failed to parse crio config %s", p)
+		}
+
+		runtime := config.CRIO.Runtime
+		if runtime.SELinux != nil {
+			security.SELinux = *runtime.SELinux
+		}
+		if runtime.SeccompProfile != nil {
+			security.SeccompProfile = *runtime.SeccompProfile
+		}
+		if runtime.AppArmorProfile != nil {
+			security.AppArmorProfile = *runtime.AppArmorProfile
+			security.AppArmor = security.AppArmorProfile != "unconfined"
+		}
+	}
+	return security, nil
+}
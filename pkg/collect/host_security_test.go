@@ -0,0 +1,176 @@
+package collect
+
+import (
+	"encoding/json"
+	"testing"
+	"testing/fstest"
+
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_collectSELinux(t *testing.T) {
+	fsys := fstest.MapFS{
+		"etc/selinux/config":                              {Data: []byte("# This file controls the state of SELinux on the system.\nSELINUX=enforcing\nSELINUXTYPE=targeted\n")},
+		"sys/fs/selinux/enforce":                          {Data: []byte("0")},
+		"sys/fs/selinux/booleans/container_manage_cgroup": {Data: []byte("1 1")},
+		"sys/fs/selinux/booleans/virt_use_nfs":            {Data: []byte("0 0")},
+		// a boolean with a pending change has its current value
+		"sys/fs/selinux/booleans/container_use_devices": {Data: []byte("0 1")},
+	}
+
+	info, err := collectSELinux(fsys)
+	require.NoError(t, err)
+	assert.Equal(t, SELinuxInfo{
+		Mode:           SELinuxModePermissive,
+		ConfiguredMode: "enforcing",
+		Policy:         "targeted",
+		Booleans: map[string]bool{
+			"container_manage_cgroup": true,
+			"virt_use_nfs":            false,
+			"container_use_devices":   false,
+		},
+	}, info)
+
+	info, err = collectSELinux(fstest.MapFS{})
+	require.NoError(t, err)
+	assert.Equal(t, SELinuxInfo{Mode: SELinuxModeDisabled}, info)
+}
+
+func Test_collectAppArmor(t *testing.T) {
+	info, err := collectAppArmor(fstest.MapFS{
+		"sys/module/apparmor/parameters/enabled": {Data: []byte("Y\n")},
+		"sys/kernel/security/apparmor/profiles":  {Data: []byte("cri-containerd.apparmor.d (enforce)\n/usr/sbin/chronyd (enforce)\nnvidia_modprobe//kmod (complain)\n")},
+	})
+	require.NoError(t, err)
+	assert.True(t, info.Enabled)
+	assert.Equal(t, map[string]string{
+		"cri-containerd.apparmor.d": "enforce",
+		"/usr/sbin/chronyd":         "enforce",
+		"nvidia_modprobe//kmod":     "complain",
+	}, info.Profiles)
+
+	// the profiles are only readable by root
+	info, err = collectAppArmor(fstest.MapFS{
+		"sys/module/apparmor/parameters/enabled": {Data: []byte("Y\n")},
+	})
+	assert.Error(t, err)
+	assert.True(t, info.Enabled)
+
+	info, err = collectAppArmor(fstest.MapFS{})
+	require.NoError(t, err)
+	assert.False(t, info.Enabled)
+}
+
+func Test_collectSeccomp(t *testing.T) {
+	status := []byte("Name:\tcat\nNoNewPrivs:\t0\nSeccomp:\t0\nSeccomp_filters:\t0\n")
+
+	info, err := collectSeccomp(fstest.MapFS{
+		"proc/self/status":            {Data: status},
+		"var/lib/kubelet/config.yaml": {Data: []byte("apiVersion: kubelet.config.k8s.io/v1beta1\nkind: KubeletConfiguration\nseccompDefault: true\n")},
+	}, defaultKubeletConfigPath)
+	require.NoError(t, err)
+	assert.True(t, info.Available)
+	require.NotNil(t, info.KubeletDefault)
+	assert.True(t, *info.KubeletDefault)
+
+	info, err = collectSeccomp(fstest.MapFS{
+		"proc/self/status":         {Data: status},
+		"etc/kubelet/kubelet.yaml": {Data: []byte("kind: KubeletConfiguration\n")},
+	}, "/etc/kubelet/kubelet.yaml")
+	require.NoError(t, err)
+	require.NotNil(t, info.KubeletDefault)
+	assert.False(t, *info.KubeletDefault)
+
+	info, err = collectSeccomp(fstest.MapFS{}, defaultKubeletConfigPath)
+	require.NoError(t, err)
+	assert.Equal(t, SeccompInfo{}, info)
+}
+
+func Test_collectContainerdSecurity(t *testing.T) {
+	config := `version = 2
+
+[plugins."io.containerd.grpc.v1.cri"]
+  enable_selinux = true
+  disable_apparmor = true
+  sandbox_image = "registry.k8s.io/pause:3.10"
+
+[plugins."io.containerd.grpc.v1.cri".registry.configs."registry.example.com".auth]
+  password = "hunter2"
+`
+	security, err := collectContainerdSecurity(fstest.MapFS{"etc/containerd/config.toml": {Data: []byte(config)}}, defaultContainerdConfigPath)
+	require.NoError(t, err)
+	assert.Equal(t, &ContainerRuntimeSecurity{
+		ConfigPaths: []string{defaultContainerdConfigPath},
+		SELinux:     true,
+		AppArmor:    false,
+	}, security)
+
+	security, err = collectContainerdSecurity(fstest.MapFS{"etc/containerd/config.toml": {Data: []byte("version = 3\n")}}, defaultContainerdConfigPath)
+	require.NoError(t, err)
+	assert.False(t, security.SELinux)
+	assert.True(t, security.AppArmor)
+
+	security, err = collectContainerdSecurity(fstest.MapFS{}, defaultContainerdConfigPath)
+	require.NoError(t, err)
+	assert.Nil(t, security)
+
+	_, err = collectContainerdSecurity(fstest.MapFS{"etc/containerd/config.toml": {Data: []byte("[plugins")}}, defaultContainerdConfigPath)
+	assert.Error(t, err)
+}
+
+func Test_collectCRIOSecurity(t *testing.T) {
+	security, err := collectCRIOSecurity(fstest.MapFS{
+		"etc/crio/crio.conf":                      {Data: []byte("[crio.runtime]\nselinux = true\nseccomp_profile = \"/etc/crio/seccomp.json\"\n")},
+		"etc/crio/crio.conf.d/10-apparmor.conf":   {Data: []byte("[crio.runtime]\napparmor_profile = \"unconfined\"\n")},
+		"etc/crio/crio.conf.d/20-selinux.conf":    {Data: []byte("[crio.runtime]\nselinux = false\n")},
+		"etc/crio/crio.conf.d/README":             {Data: []byte("not a config")},
+		"etc/crio/crio.conf.d/30-registries.conf": {Data: []byte("[crio.image]\npause_image = \"registry.k8s.io/pause:3.10\"\n")},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, &ContainerRuntimeSecurity{
+		ConfigPaths: []string{
+			"/etc/crio/crio.conf",
+			"/etc/crio/crio.conf.d/10-apparmor.conf",
+			"/etc/crio/crio.conf.d/20-selinux.conf",
+			"/etc/crio/crio.conf.d/30-registries.conf",
+		},
+		SELinux:         false,
+		AppArmor:        false,
+		AppArmorProfile: "unconfined",
+		SeccompProfile:  "/etc/crio/seccomp.json",
+	}, security)
+
+	security, err = collectCRIOSecurity(fstest.MapFS{})
+	require.NoError(t, err)
+	assert.Nil(t, security)
+}
+
+func TestCollectHostSecurity(t *testing.T) {
+	c := &CollectHostSecurity{
+		hostCollector: &troubleshootv1beta2.HostSecurity{},
+		BundlePath:    "",
+		fs: fstest.MapFS{
+			"sys/fs/selinux/enforce":                          {Data: []byte("1")},
+			"sys/fs/selinux/booleans/container_manage_cgroup": {Data: []byte("1 1")},
+			"sys/module/apparmor/parameters/enabled":          {Data: []byte("Y\n")},
+			"proc/self/status":                                {Data: []byte("Seccomp:\t0\n")},
+		},
+	}
+
+	result, err := c.Collect(nil)
+	require.NoError(t, err)
+	require.Contains(t, result, HostSecurityPath)
+
+	info := SecurityInfo{}
+	require.NoError(t, json.Unmarshal(result[HostSecurityPath], &info))
+	assert.Equal(t, SELinuxModeEnforcing, info.SELinux.Mode)
+	assert.Equal(t, map[string]bool{"container_manage_cgroup": true}, info.SELinux.Booleans)
+	assert.True(t, info.AppArmor.Enabled)
+	assert.True(t, info.Seccomp.Available)
+	assert.Nil(t, info.Seccomp.KubeletDefault)
+	assert.Nil(t, info.Containerd)
+	assert.Nil(t, info.CRIO)
+	assert.Contains(t, info.Errors, "apparmor")
+}
@@ -0,0 +1,144 @@
+package collect
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"k8s.io/klog/v2"
+)
+
+// Ensure `CollectHostSystemdUnits` implements `HostCollector` interface at compile time.
+var _ HostCollector = (*CollectHostSystemdUnits)(nil)
+
+const HostSystemdUnitsPath = `host-collectors/system/systemd-units.json`
+const HostSystemdUnitsFileName = `systemd-units.json`
+
+const defaultSystemdUnitJournalLines = 20
+
+// SystemdUnitInfo is the state of a single systemd unit.
+type SystemdUnitInfo struct {
+	Unit        string   `json:"unit"`
+	LoadState   string   `json:"loadState"`
+	ActiveState string   `json:"activeState"`
+	SubState    string   `json:"subState"`
+	Restarts    int      `json:"restarts"`
+	Journal     []string `json:"journal,omitempty"`
+}
+
+type CollectHostSystemdUnits struct {
+	hostCollector *troubleshootv1beta2.HostSystemdUnits
+	BundlePath    string
+}
+
+func (c *CollectHostSystemdUnits) Title() string {
+	return hostCollectorTitleOrDefault(c.hostCollector.HostCollectorMeta, "Systemd Units")
+}
+
+func (c *CollectHostSystemdUnits) IsExcluded() (bool, error) {
+	return isExcluded(c.hostCollector.Exclude)
+}
+
+func (c *CollectHostSystemdUnits) Collect(progressChan chan<- interface{}) (map[string][]byte, error) {
+	journalLines := c.hostCollector.JournalLines
+	if journalLines == 0 {
+		journalLines = defaultSystemdUnitJournalLines
+	}
+
+	units := []SystemdUnitInfo{}
+	for _, name := range c.hostCollector.Units {
+		unit, err := getSystemdUnitInfo(SystemdUnitName(name))
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get state of unit %s", name)
+		}
+
+		if journalLines > 0 {
+			journal, err := getSystemdUnitJournal(unit.Unit, journalLines)
+			if err != nil {
+				// the unit state is still useful without its logs
+				klog.V(2).Infof("failed to read journal for unit %s: %v", unit.Unit, err)
+			}
+			unit.Journal = journal
+		}
+
+		units = append(units, unit)
+	}
+
+	b, err := json.Marshal(units)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal systemd units")
+	}
+
+	output := NewResult()
+	output.SaveResult(c.BundlePath, HostSystemdUnitsPath, bytes.NewBuffer(b))
+
+	return output, nil
+}
+
+// SystemdUnitName returns the full name of a unit, assuming units without a suffix are services
+func SystemdUnitName(name string) string {
+	if strings.Contains(name, ".") {
+		return name
+	}
+	return name + ".service"
+}
+
+func getSystemdUnitInfo(name string) (SystemdUnitInfo, error) {
+	out, err := execCommand(
+		"systemctl", "show", name, "--no-pager",
+		"--property=Id,LoadState,ActiveState,SubState,NRestarts",
+	).Output()
+	if err != nil {
+		return SystemdUnitInfo{}, errors.Wrap(err, "failed to run systemctl show")
+	}
+
+	unit := SystemdUnitInfo{Unit: name}
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(strings.TrimSpace(scanner.Text()), "=")
+		if !ok {
+			continue
+		}
+
+		switch key {
+		case "Id":
+			if value != "" {
+				unit.Unit = value
+			}
+		case "LoadState":
+			unit.LoadState = value
+		case "ActiveState":
+			unit.ActiveState = value
+		case "SubState":
+			unit.SubState = value
+		case "NRestarts":
+			// older versions of systemd do not track restarts
+			unit.Restarts, _ = strconv.Atoi(value)
+		}
+	}
+
+	return unit, nil
+}
+
+func getSystemdUnitJournal(name string, lines int) ([]string, error) {
+	out, err := execCommand(
+		"journalctl", "-u", name, "-n", strconv.Itoa(lines), "--no-pager", "--output=short-iso",
+	).Output()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to run journalctl")
+	}
+
+	var journal []string
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		if line := scanner.Text(); line != "" {
+			journal = append(journal, line)
+		}
+	}
+
+	return journal, nil
+}
@@ -0,0 +1,95 @@
+package collect
+
+import (
+	"encoding/json"
+	"os/exec"
+	"testing"
+
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/stretchr/testify/require"
+)
+
+func setSystemdExecStub(t *testing.T, outputs map[string]string) {
+	original := execCommand
+	t.Cleanup(func() { execCommand = original })
+
+	execCommand = func(name string, args ...string) *exec.Cmd {
+		out, ok := outputs[name+" "+args[0]+" "+args[1]]
+		if !ok {
+			return exec.Command("sh", "-c", "exit 1")
+		}
+		return exec.Command("printf", "%s", out)
+	}
+}
+
+func TestSystemdUnitName(t *testing.T) {
+	req := require.New(t)
+	req.Equal("containerd.service", SystemdUnitName("containerd"))
+	req.Equal("kubelet.service", SystemdUnitName("kubelet.service"))
+	req.Equal("fstrim.timer", SystemdUnitName("fstrim.timer"))
+}
+
+func TestCollectHostSystemdUnits(t *testing.T) {
+	req := require.New(t)
+	setSystemdExecStub(t, map[string]string{
+		"systemctl show containerd.service": "Id=containerd.service\nLoadState=loaded\nActiveState=active\nSubState=running\nNRestarts=2\n",
+		"journalctl -u containerd.service":  "2024-01-01T00:00:00+0000 host containerd[1]: starting\n2024-01-01T00:00:01+0000 host containerd[1]: started\n",
+		"systemctl show firewalld.service":  "Id=firewalld.service\nLoadState=not-found\nActiveState=inactive\nSubState=dead\nNRestarts=\n",
+	})
+
+	c := &CollectHostSystemdUnits{
+		hostCollector: &troubleshootv1beta2.HostSystemdUnits{
+			Units: []string{"containerd", "firewalld"},
+		},
+		BundlePath: "",
+	}
+
+	result, err := c.Collect(nil)
+	req.NoError(err)
+
+	var units []SystemdUnitInfo
+	req.NoError(json.Unmarshal(result[HostSystemdUnitsPath], &units))
+	req.Equal([]SystemdUnitInfo{
+		{
+			Unit:        "containerd.service",
+			LoadState:   "loaded",
+			ActiveState: "active",
+			SubState:    "running",
+			Restarts:    2,
+			Journal: []string{
+				"2024-01-01T00:00:00+0000 host containerd[1]: starting",
+				"2024-01-01T00:00:01+0000 host containerd[1]: started",
+			},
+		},
+		{
+			Unit:        "firewalld.service",
+			LoadState:   "not-found",
+			ActiveState: "inactive",
+			SubState:    "dead",
+		},
+	}, units)
+}
+
+func TestCollectHostSystemdUnits_SkipJournal(t *testing.T) {
+	req := require.New(t)
+	setSystemdExecStub(t, map[string]string{
+		"systemctl show kubelet.service": "Id=kubelet.service\nLoadState=loaded\nActiveState=failed\nSubState=failed\nNRestarts=7\n",
+	})
+
+	c := &CollectHostSystemdUnits{
+		hostCollector: &troubleshootv1beta2.HostSystemdUnits{
+			Units:        []string{"kubelet.service"},
+			JournalLines: -1,
+		},
+		BundlePath: "",
+	}
+
+	result, err := c.Collect(nil)
+	req.NoError(err)
+
+	var units []SystemdUnitInfo
+	req.NoError(json.Unmarshal(result[HostSystemdUnitsPath], &units))
+	req.Equal([]SystemdUnitInfo{
+		{Unit: "kubelet.service", LoadState: "loaded", ActiveState: "failed", SubState: "failed", Restarts: 7},
+	}, units)
+}
@@ -0,0 +1,259 @@
+package collect
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"k8s.io/klog/v2"
+)
+
+// Ensure `CollectHostTimeSync` implements `HostCollector` interface at compile time.
+var _ HostCollector = (*CollectHostTimeSync)(nil)
+
+const HostTimeSyncPath = `host-collectors/system/time-sync.json`
+const HostTimeSyncFileName = `time-sync.json`
+
+const (
+	TimeSyncSourceChrony    = "chrony"
+	TimeSyncSourceNTPD      = "ntpd"
+	TimeSyncSourceTimesyncd = "timesyncd"
+)
+
+// TimeSyncInfo is the output of the time sync collector. The first time sync daemon that
+// responds is used, Errors is only populated when none of them could be queried.
+type TimeSyncInfo struct {
+	Source       string `json:"source,omitempty"`
+	Synchronized bool   `json:"synchronized"`
+	// OffsetSeconds is the offset of the system clock from the reference, nil if unknown
+	OffsetSeconds *float64          `json:"offsetSeconds,omitempty"`
+	Servers       []string          `json:"servers"`
+	Errors        map[string]string `json:"errors,omitempty"`
+}
+
+type CollectHostTimeSync struct {
+	hostCollector *troubleshootv1beta2.HostTimeSync
+	BundlePath    string
+}
+
+func (c *CollectHostTimeSync) Title() string {
+	return hostCollectorTitleOrDefault(c.hostCollector.HostCollectorMeta, "Time Sync")
+}
+
+func (c *CollectHostTimeSync) IsExcluded() (bool, error) {
+	return isExcluded(c.hostCollector.Exclude)
+}
+
+func (c *CollectHostTimeSync) Collect(progressChan chan<- interface{}) (map[string][]byte, error) {
+	sources := []struct {
+		command string
+		collect func() (*TimeSyncInfo, error)
+	}{
+		{"chronyc", collectChronyTimeSync},
+		{"ntpq", collectNTPDTimeSync},
+		{"timedatectl", collectTimesyncdTimeSync},
+	}
+
+	info := &TimeSyncInfo{Servers: []string{}}
+	failures := map[string]string{}
+	for _, source := range sources {
+		collected, err := source.collect()
+		if err != nil {
+			klog.V(2).Infof("failed to collect time sync status from %s: %v", source.command, err)
+			failures[source.command] = err.Error()
+			continue
+		}
+		info = collected
+		failures = nil
+		break
+	}
+	info.Errors = failures
+
+	b, err := json.Marshal(info)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal time sync info")
+	}
+
+	output := NewResult()
+	output.SaveResult(c.BundlePath, HostTimeSyncPath, bytes.NewBuffer(b))
+
+	return output, nil
+}
+
+func collectChronyTimeSync() (*TimeSyncInfo, error) {
+	out, err := execCommand("chronyc", "-c", "tracking").Output()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to run chronyc tracking")
+	}
+	info, err := parseChronyTracking(out)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err = execCommand("chronyc", "-c", "sources").Output()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to run chronyc sources")
+	}
+	info.Servers, err = parseChronySources(out)
+	if err != nil {
+		return nil, err
+	}
+
+	return info, nil
+}
+
+// parseChronyTracking parses `chronyc -c tracking` output, e.g:
+//
+//	A29FC87B,162.159.200.123,3,1700000000.123456789,-0.000012345,0.000003,0.000021,-12.345,0.001,0.045,0.012,0.001,64.2,Normal
+func parseChronyTracking(out []byte) (*TimeSyncInfo, error) {
+	fields, err := csv.NewReader(bytes.NewReader(out)).Read()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse chronyc tracking output")
+	}
+	if len(fields) < 14 {
+		return nil, errors.Errorf("unexpected chronyc tracking output %q", strings.TrimSpace(string(out)))
+	}
+
+	offset, err := strconv.ParseFloat(fields[4], 64)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse system time offset %q", fields[4])
+	}
+
+	return &TimeSyncInfo{
+		Source:        TimeSyncSourceChrony,
+		Synchronized:  fields[len(fields)-1] != "Not synchronised" && fields[2] != "0",
+		OffsetSeconds: &offset,
+	}, nil
+}
+
+// parseChronySources returns the servers and peers from `chronyc -c sources` output, e.g:
+//
+//	^,*,162.159.200.123,3,7,377,35,-0.000012,-0.000013,0.004567
+func parseChronySources(out []byte) ([]string, error) {
+	records, err := csv.NewReader(bytes.NewReader(out)).ReadAll()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse chronyc sources output")
+	}
+
+	servers := []string{}
+	for _, record := range records {
+		// reference clocks (#) are not network servers
+		if len(record) < 3 || (record[0] != "^" && record[0] != "=") {
+			continue
+		}
+		servers = append(servers, record[2])
+	}
+
+	return servers, nil
+}
+
+func collectNTPDTimeSync() (*TimeSyncInfo, error) {
+	out, err := execCommand("ntpq", "-pn").Output()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to run ntpq")
+	}
+	return parseNtpqPeers(out)
+}
+
+// parseNtpqPeers parses `ntpq -pn` output. The system peer is marked with a "*" and its
+// offset, reported in milliseconds, is used as the offset of the system clock, e.g:
+//
+//	     remote           refid      st t when poll reach   delay   offset  jitter
+//	==============================================================================
+//	*162.159.200.1   10.12.3.5        3 u   35   64  377    1.234   -0.567   0.089
+func parseNtpqPeers(out []byte) (*TimeSyncInfo, error) {
+	info := &TimeSyncInfo{
+		Source:  TimeSyncSourceNTPD,
+		Servers: []string{},
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		line := scanner.Text()
+		fields := strings.Fields(line)
+		if len(fields) < 10 || fields[0] == "remote" || strings.HasPrefix(line, "=") {
+			continue
+		}
+
+		tally := line[0]
+		info.Servers = append(info.Servers, strings.TrimLeft(fields[0], "*#o+x.-"))
+		if tally != '*' && tally != 'o' {
+			continue
+		}
+
+		offsetMillis, err := strconv.ParseFloat(fields[8], 64)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse offset %q", fields[8])
+		}
+		offset := offsetMillis / 1000
+		info.OffsetSeconds = &offset
+		info.Synchronized = true
+	}
+
+	return info, nil
+}
+
+func collectTimesyncdTimeSync() (*TimeSyncInfo, error) {
+	out, err := execCommand("timedatectl", "timesync-status", "--no-pager").Output()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to run timedatectl timesync-status")
+	}
+	info, err := parseTimesyncStatus(out)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err = execCommand("timedatectl", "show", "--property=NTPSynchronized", "--value").Output()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to run timedatectl show")
+	}
+	info.Synchronized = strings.TrimSpace(string(out)) == "yes"
+
+	return info, nil
+}
+
+// parseTimesyncStatus parses `timedatectl timesync-status` output, e.g:
+//
+//	       Server: 185.125.190.56 (ntp.ubuntu.com)
+//	Poll interval: 34min 8s (min: 32s; max 34min 8s)
+//	       Offset: -1.031ms
+func parseTimesyncStatus(out []byte) (*TimeSyncInfo, error) {
+	info := &TimeSyncInfo{
+		Source:  TimeSyncSourceTimesyncd,
+		Servers: []string{},
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+
+		switch strings.TrimSpace(key) {
+		case "Server":
+			// prefer the configured name over the resolved address
+			server, name, ok := strings.Cut(value, " (")
+			if ok {
+				server = strings.TrimSuffix(name, ")")
+			}
+			info.Servers = append(info.Servers, server)
+		case "Offset":
+			offset, err := time.ParseDuration(value)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to parse offset %q", value)
+			}
+			seconds := offset.Seconds()
+			info.OffsetSeconds = &seconds
+		}
+	}
+
+	return info, nil
+}
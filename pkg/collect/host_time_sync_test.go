@@ -0,0 +1,138 @@
+package collect
+
+import (
+	"encoding/json"
+	"os/exec"
+	"strings"
+	"testing"
+
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_parseChronyTracking(t *testing.T) {
+	req := require.New(t)
+
+	info, err := parseChronyTracking([]byte("A29FC87B,162.159.200.123,3,1700000000.123456789,-0.000012345,0.000003,0.000021,-12.345,0.001,0.045,0.012,0.001,64.2,Normal\n"))
+	req.NoError(err)
+	req.Equal(TimeSyncSourceChrony, info.Source)
+	req.True(info.Synchronized)
+	req.InDelta(-0.000012345, *info.OffsetSeconds, 1e-12)
+
+	info, err = parseChronyTracking([]byte("00000000,,0,0.000000000,0.000000000,0.000000000,0.000000000,0.000,0.000,0.000,1.000000000,1.000000000,0.0,Not synchronised\n"))
+	req.NoError(err)
+	req.False(info.Synchronized)
+
+	_, err = parseChronyTracking([]byte("506 Cannot talk to daemon\n"))
+	req.Error(err)
+}
+
+func Test_parseChronySources(t *testing.T) {
+	req := require.New(t)
+
+	out := strings.Join([]string{
+		"^,*,162.159.200.123,3,7,377,35,-0.000012,-0.000013,0.004567",
+		"^,+,time.cloudflare.com,3,7,377,36,0.000102,0.000101,0.004789",
+		"#,-,GPS,0,4,377,12,0.000001,0.000001,0.000100",
+	}, "\n")
+
+	servers, err := parseChronySources([]byte(out))
+	req.NoError(err)
+	req.Equal([]string{"162.159.200.123", "time.cloudflare.com"}, servers)
+}
+
+func Test_parseNtpqPeers(t *testing.T) {
+	req := require.New(t)
+
+	out := strings.Join([]string{
+		"     remote           refid      st t when poll reach   delay   offset  jitter",
+		"==============================================================================",
+		"*162.159.200.1   10.12.3.5        3 u   35   64  377    1.234   -2.567   0.089",
+		"+10.0.0.5        .GPS.            1 u   12   64  377    0.456    0.102   0.021",
+		" 10.0.0.6        .INIT.          16 u    -   64    0    0.000    0.000   0.000",
+	}, "\n")
+
+	info, err := parseNtpqPeers([]byte(out))
+	req.NoError(err)
+	req.Equal(TimeSyncSourceNTPD, info.Source)
+	req.True(info.Synchronized)
+	req.InDelta(-0.002567, *info.OffsetSeconds, 1e-9)
+	req.Equal([]string{"162.159.200.1", "10.0.0.5", "10.0.0.6"}, info.Servers)
+}
+
+func Test_parseTimesyncStatus(t *testing.T) {
+	req := require.New(t)
+
+	out := strings.Join([]string{
+		"       Server: 185.125.190.56 (ntp.ubuntu.com)",
+		"Poll interval: 34min 8s (min: 32s; max 34min 8s)",
+		"         Leap: normal",
+		"       Offset: +1.031ms",
+		"        Delay: 10.713ms",
+	}, "\n")
+
+	info, err := parseTimesyncStatus([]byte(out))
+	req.NoError(err)
+	req.Equal(TimeSyncSourceTimesyncd, info.Source)
+	req.Equal([]string{"ntp.ubuntu.com"}, info.Servers)
+	req.InDelta(0.001031, *info.OffsetSeconds, 1e-9)
+}
+
+func TestCollectHostTimeSync_NoDaemon(t *testing.T) {
+	req := require.New(t)
+
+	original := execCommand
+	t.Cleanup(func() { execCommand = original })
+	execCommand = func(name string, args ...string) *exec.Cmd {
+		return exec.Command("sh", "-c", "exit 127")
+	}
+
+	c := &CollectHostTimeSync{
+		hostCollector: &troubleshootv1beta2.HostTimeSync{},
+		BundlePath:    "",
+	}
+
+	result, err := c.Collect(nil)
+	req.NoError(err)
+
+	info := TimeSyncInfo{}
+	req.NoError(json.Unmarshal(result[HostTimeSyncPath], &info))
+	req.Empty(info.Source)
+	req.False(info.Synchronized)
+	req.Nil(info.OffsetSeconds)
+	req.Empty(info.Servers)
+	req.Len(info.Errors, 3)
+}
+
+func TestCollectHostTimeSync_Timesyncd(t *testing.T) {
+	req := require.New(t)
+
+	original := execCommand
+	t.Cleanup(func() { execCommand = original })
+	execCommand = func(name string, args ...string) *exec.Cmd {
+		switch {
+		case name == "timedatectl" && args[0] == "timesync-status":
+			return exec.Command("printf", "%s\n%s\n", "Server: 10.0.0.1 (10.0.0.1)", "Offset: -250ms")
+		case name == "timedatectl":
+			return exec.Command("echo", "yes")
+		}
+		return exec.Command("sh", "-c", "exit 127")
+	}
+
+	c := &CollectHostTimeSync{
+		hostCollector: &troubleshootv1beta2.HostTimeSync{},
+		BundlePath:    "",
+	}
+
+	result, err := c.Collect(nil)
+	req.NoError(err)
+
+	info := TimeSyncInfo{}
+	req.NoError(json.Unmarshal(result[HostTimeSyncPath], &info))
+	req.Equal(TimeSyncSourceTimesyncd, info.Source)
+	req.True(info.Synchronized)
+	req.InDelta(-0.25, *info.OffsetSeconds, 1e-9)
+	req.Equal([]string{"10.0.0.1"}, info.Servers)
+	// failures of the daemons that are not running are not reported
+	req.Empty(info.Errors)
+}
@@ -2,6 +2,7 @@ package collect
 
 import (
 	"bytes"
+	"context"
 	"crypto/tls"
 	"crypto/x509"
 	"encoding/json"
@@ -11,11 +12,13 @@ import (
 	neturl "net/url"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/pkg/errors"
 	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/klog/v2"
@@ -26,10 +29,68 @@ type HTTPResponse struct {
 	Body    string            `json:"body"`
 	Headers map[string]string `json:"headers"`
 	RawJSON json.RawMessage   `json:"raw_json,omitempty"`
+	// BodyTruncated is true when the body was cut to the max body size of the request
+	BodyTruncated bool `json:"bodyTruncated,omitempty"`
+	// LatencyMilliseconds is the time from sending the request to receiving the response headers
+	LatencyMilliseconds int64 `json:"latencyMilliseconds,omitempty"`
+	// Attempts is the number of times the request was sent when it could be retried
+	Attempts int      `json:"attempts,omitempty"`
+	TLS      *HTTPTLS `json:"tls,omitempty"`
 }
 
 type HTTPError struct {
-	Message string `json:"message"`
+	Message  string `json:"message"`
+	Attempts int    `json:"attempts,omitempty"`
+}
+
+// HTTPTLS is the outcome of the TLS handshake of a request
+type HTTPTLS struct {
+	Version            string            `json:"version"`
+	CipherSuite        string            `json:"cipherSuite"`
+	ServerName         string            `json:"serverName,omitempty"`
+	NegotiatedProtocol string            `json:"negotiatedProtocol,omitempty"`
+	PeerCertificates   []HTTPCertificate `json:"peerCertificates,omitempty"`
+}
+
+// HTTPCertificate is a certificate of the chain the server presented, starting with its own
+type HTTPCertificate struct {
+	Subject   string    `json:"subject"`
+	Issuer    string    `json:"issuer"`
+	DNSNames  []string  `json:"dnsNames,omitempty"`
+	NotBefore time.Time `json:"notBefore"`
+	NotAfter  time.Time `json:"notAfter"`
+}
+
+// defaultRetryStatusCodes are the status codes of responses that are retried when the retry of a
+// request does not list any
+var defaultRetryStatusCodes = []int{
+	http.StatusTooManyRequests,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+const defaultRetryBackoff = time.Second
+
+// httpRequest is the request of an http collector, with the options of how it is sent and how its
+// response is captured
+type httpRequest struct {
+	method             string
+	url                string
+	headers            map[string]string
+	body               string
+	insecureSkipVerify bool
+	timeout            string
+	tls                *troubleshootv1beta2.TLSParams
+	proxy              string
+	retry              *troubleshootv1beta2.HTTPRetry
+	maxBodySize        string
+}
+
+// httpRequestDetails are how a response was received, recorded with the response
+type httpRequestDetails struct {
+	attempts int
+	latency  time.Duration
 }
 
 type CollectHTTP struct {
@@ -38,6 +99,7 @@ type CollectHTTP struct {
 	Namespace    string
 	ClientConfig *rest.Config
 	Client       kubernetes.Interface
+	Context      context.Context
 	RBACErrors
 }
 
@@ -50,24 +112,28 @@ func (c *CollectHTTP) IsExcluded() (bool, error) {
 }
 
 func (c *CollectHTTP) Collect(progressChan chan<- interface{}) (CollectorResult, error) {
-	var response *http.Response
-	var err error
+	ctx := collectorContext(c.Context)
 
-	switch {
-	case c.Collector.Get != nil:
-		response, err = doRequest(
-			"GET", c.Collector.Get.URL, c.Collector.Get.Headers, "", c.Collector.Get.InsecureSkipVerify, c.Collector.Get.Timeout, c.Collector.Get.TLS, c.Collector.Get.Proxy)
-	case c.Collector.Post != nil:
-		response, err = doRequest(
-			"POST", c.Collector.Post.URL, c.Collector.Post.Headers, c.Collector.Post.Body, c.Collector.Post.InsecureSkipVerify, c.Collector.Post.Timeout, c.Collector.Post.TLS, c.Collector.Post.Proxy)
-	case c.Collector.Put != nil:
-		response, err = doRequest(
-			"PUT", c.Collector.Put.URL, c.Collector.Put.Headers, c.Collector.Put.Body, c.Collector.Put.InsecureSkipVerify, c.Collector.Put.Timeout, c.Collector.Put.TLS, c.Collector.Put.Proxy)
-	default:
-		return nil, errors.New("no supported http request type")
-	}
-
-	o, err := responseToOutput(response, err)
+	request, err := newHTTPRequest(c.Collector.Get, c.Collector.Post, c.Collector.Put)
+	if err != nil {
+		return nil, err
+	}
+
+	// in the cluster, certificates can be read from a secret
+	if request.tls != nil && request.tls.Secret != nil {
+		caCert, clientCert, clientKey, err := getTLSParamTriplet(ctx, c.Client, request.tls)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read tls secret")
+		}
+		request.tls = &troubleshootv1beta2.TLSParams{
+			SkipVerify: request.tls.SkipVerify,
+			CACert:     caCert,
+			ClientCert: clientCert,
+			ClientKey:  clientKey,
+		}
+	}
+
+	o, err := request.collect(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -83,6 +149,91 @@ func (c *CollectHTTP) Collect(progressChan chan<- interface{}) (CollectorResult,
 	return output, nil
 }
 
+// newHTTPRequest returns the request of the method that is set
+func newHTTPRequest(get *troubleshootv1beta2.Get, post *troubleshootv1beta2.Post, put *troubleshootv1beta2.Put) (*httpRequest, error) {
+	switch {
+	case get != nil:
+		return &httpRequest{
+			method: http.MethodGet, url: get.URL, headers: get.Headers, insecureSkipVerify: get.InsecureSkipVerify,
+			timeout: get.Timeout, tls: get.TLS, proxy: get.Proxy, retry: get.Retry, maxBodySize: get.MaxBodySize,
+		}, nil
+	case post != nil:
+		return &httpRequest{
+			method: http.MethodPost, url: post.URL, headers: post.Headers, body: post.Body, insecureSkipVerify: post.InsecureSkipVerify,
+			timeout: post.Timeout, tls: post.TLS, proxy: post.Proxy, retry: post.Retry, maxBodySize: post.MaxBodySize,
+		}, nil
+	case put != nil:
+		return &httpRequest{
+			method: http.MethodPut, url: put.URL, headers: put.Headers, body: put.Body, insecureSkipVerify: put.InsecureSkipVerify,
+			timeout: put.Timeout, tls: put.TLS, proxy: put.Proxy, retry: put.Retry, maxBodySize: put.MaxBodySize,
+		}, nil
+	}
+	return nil, errors.New("no supported http request type")
+}
+
+// collect sends the request and returns the output of the collector
+func (r *httpRequest) collect(ctx context.Context) ([]byte, error) {
+	maxBodySize := int64(0)
+	if r.maxBodySize != "" {
+		quantity, err := resource.ParseQuantity(r.maxBodySize)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse max body size %q", r.maxBodySize)
+		}
+		maxBodySize = quantity.Value()
+	}
+
+	response, details, err := r.send(ctx)
+	return httpResponseToOutput(response, err, details, maxBodySize)
+}
+
+// send sends the request until it gets a response that is not retried or runs out of attempts, and
+// returns the last response. Retrying stops when the context is done.
+func (r *httpRequest) send(ctx context.Context) (*http.Response, httpRequestDetails, error) {
+	attempts := 1
+	backoff := defaultRetryBackoff
+	statusCodes := defaultRetryStatusCodes
+	if r.retry != nil {
+		attempts = max(r.retry.Attempts, 1)
+		if r.retry.Backoff != "" {
+			var err error
+			backoff, err = time.ParseDuration(r.retry.Backoff)
+			if err != nil {
+				return nil, httpRequestDetails{}, errors.Wrapf(err, "failed to parse backoff %q", r.retry.Backoff)
+			}
+		}
+		if len(r.retry.StatusCodes) > 0 {
+			statusCodes = r.retry.StatusCodes
+		}
+	}
+
+	details := httpRequestDetails{}
+	for {
+		details.attempts++
+		start := time.Now()
+		response, err := doRequest(ctx, r.method, r.url, r.headers, r.body, r.insecureSkipVerify, r.timeout, r.tls, r.proxy)
+		details.latency = time.Since(start)
+
+		retry := err != nil || slices.Contains(statusCodes, response.StatusCode)
+		if !retry || details.attempts >= attempts {
+			if r.retry == nil {
+				details.attempts = 0
+			}
+			return response, details, err
+		}
+
+		if response != nil {
+			response.Body.Close()
+		}
+		klog.V(2).Infof("Retrying %s %s in %s\n", r.method, r.url, backoff)
+		select {
+		case <-ctx.Done():
+			return nil, details, errors.Wrapf(ctx.Err(), "%s %s was not retried", r.method, r.url)
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
 func handleFileOrDir(path string) (bool, error) {
 	f, err := os.Stat(path)
 	if err != nil {
@@ -103,33 +254,19 @@ func isPEMCertificate(s string) bool {
 	return strings.Contains(s, "BEGIN CERTIFICATE") || strings.Contains(s, "BEGIN RSA PRIVATE KEY")
 }
 
-func doRequest(method, url string, headers map[string]string, body string, insecureSkipVerify bool, timeout string, tlsParams *troubleshootv1beta2.TLSParams, proxy string) (*http.Response, error) {
+func doRequest(ctx context.Context, method, url string, headers map[string]string, body string, insecureSkipVerify bool, timeout string, tlsParams *troubleshootv1beta2.TLSParams, proxy string) (*http.Response, error) {
 
 	t, err := parseTimeout(timeout)
 	if err != nil {
 		return nil, err
 	}
 
-	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
-	httpTransport := &http.Transport{}
-
-	if tlsParams != nil && tlsParams.CACert != "" {
-		if isPEMCertificate(tlsParams.CACert) {
-			klog.V(2).Infof("Using PEM certificate from spec\n")
-			certPool := x509.NewCertPool()
-			if !certPool.AppendCertsFromPEM([]byte(tlsParams.CACert)) {
-				return nil, errors.New("failed to append certificate to cert pool")
-			}
-			tlsConfig.RootCAs = certPool
-		} else if _, err := handleFileOrDir(tlsParams.CACert); err != nil {
-			return nil, errors.Wrap(err, "failed to handle cacert file path")
-		}
-	}
-
-	if insecureSkipVerify {
-		tlsConfig.InsecureSkipVerify = true
+	tlsConfig, err := httpTLSConfig(tlsParams, insecureSkipVerify)
+	if err != nil {
+		return nil, err
 	}
 
+	httpTransport := &http.Transport{}
 	httpTransport.TLSClientConfig = tlsConfig
 
 	if proxy != "" || os.Getenv("HTTPS_PROXY") != "" {
@@ -151,7 +288,7 @@ func doRequest(method, url string, headers map[string]string, body string, insec
 		},
 	}
 
-	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	req, err := http.NewRequestWithContext(ctx, method, url, strings.NewReader(body))
 	if err != nil {
 		return nil, err
 	}
@@ -163,6 +300,75 @@ func doRequest(method, url string, headers map[string]string, body string, insec
 	return httpClient.Do(req)
 }
 
+// httpTLSConfig returns the TLS configuration of a request. The CA certificates and the client
+// certificate and key of the params are PEM encoded, or the paths of PEM files. The CA certificates
+// can be a bundle of several certificates, or a directory of certificates.
+func httpTLSConfig(tlsParams *troubleshootv1beta2.TLSParams, insecureSkipVerify bool) (*tls.Config, error) {
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if insecureSkipVerify {
+		tlsConfig.InsecureSkipVerify = true
+	}
+	if tlsParams == nil {
+		return tlsConfig, nil
+	}
+	if tlsParams.Secret != nil {
+		return nil, errors.New("tls secrets are only supported by in-cluster collectors")
+	}
+	if tlsParams.SkipVerify {
+		tlsConfig.InsecureSkipVerify = true
+	}
+
+	if tlsParams.CACert != "" {
+		caCert := []byte(tlsParams.CACert)
+		if isPEMCertificate(tlsParams.CACert) {
+			klog.V(2).Infof("Using PEM certificate from spec\n")
+		} else if info, err := os.Stat(tlsParams.CACert); err == nil && info.IsDir() {
+			if _, err := handleFileOrDir(tlsParams.CACert); err != nil {
+				return nil, errors.Wrap(err, "failed to handle cacert directory")
+			}
+			caCert = nil
+		} else if caCert, err = os.ReadFile(tlsParams.CACert); err != nil {
+			return nil, errors.Wrap(err, "failed to read cacert file")
+		}
+
+		if caCert != nil {
+			certPool := x509.NewCertPool()
+			if !certPool.AppendCertsFromPEM(caCert) {
+				return nil, errors.New("failed to append certificate to cert pool")
+			}
+			tlsConfig.RootCAs = certPool
+		}
+	}
+
+	if tlsParams.ClientCert != "" || tlsParams.ClientKey != "" {
+		clientCert, err := readPEMOrFile(tlsParams.ClientCert)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read client certificate")
+		}
+		clientKey, err := readPEMOrFile(tlsParams.ClientKey)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read client key")
+		}
+		certPair, err := tls.X509KeyPair(clientCert, clientKey)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to load client certificate")
+		}
+		tlsConfig.Certificates = []tls.Certificate{certPair}
+	}
+
+	return tlsConfig, nil
+}
+
+// readPEMOrFile returns the value when it is PEM encoded, or the contents of the file at the path
+// it holds
+func readPEMOrFile(value string) ([]byte, error) {
+	if strings.Contains(value, "-----BEGIN ") {
+		return []byte(value), nil
+	}
+	return os.ReadFile(value)
+}
+
 type LoggingTransport struct {
 	Transport http.RoundTripper
 }
@@ -194,16 +400,33 @@ func (t *LoggingTransport) RoundTrip(req *http.Request) (*http.Response, error)
 }
 
 func responseToOutput(response *http.Response, err error) ([]byte, error) {
+	return httpResponseToOutput(response, err, httpRequestDetails{}, 0)
+}
+
+// httpResponseToOutput returns the output of the collector for the response, or the error of the
+// request. The body is truncated to maxBodySize when it is not 0.
+func httpResponseToOutput(response *http.Response, err error, details httpRequestDetails, maxBodySize int64) ([]byte, error) {
 	output := make(map[string]interface{})
 	if err != nil {
 		output["error"] = HTTPError{
-			Message: err.Error(),
+			Message:  err.Error(),
+			Attempts: details.attempts,
 		}
 	} else {
-		body, err := io.ReadAll(response.Body)
+		defer response.Body.Close()
+
+		var bodyReader io.Reader = response.Body
+		if maxBodySize > 0 {
+			bodyReader = io.LimitReader(response.Body, maxBodySize+1)
+		}
+		body, err := io.ReadAll(bodyReader)
 		if err != nil {
 			return nil, err
 		}
+		truncated := maxBodySize > 0 && int64(len(body)) > maxBodySize
+		if truncated {
+			body = body[:maxBodySize]
+		}
 
 		headers := make(map[string]string)
 		for k, v := range response.Header {
@@ -211,20 +434,24 @@ func responseToOutput(response *http.Response, err error) ([]byte, error) {
 		}
 
 		var rawJSON json.RawMessage
-		if len(body) > 0 {
+		if len(body) > 0 && !truncated {
 			if err := json.Unmarshal(body, &rawJSON); err != nil {
 				klog.Infof("failed to unmarshal response body as JSON: %+v", err)
 				rawJSON = json.RawMessage{}
 			}
 		} else {
 			rawJSON = json.RawMessage{}
-			klog.V(2).Infof("empty response body\n")
+			klog.V(2).Infof("empty or truncated response body\n")
 		}
 		output["response"] = HTTPResponse{
-			Status:  response.StatusCode,
-			Body:    string(body),
-			Headers: headers,
-			RawJSON: rawJSON,
+			Status:              response.StatusCode,
+			Body:                string(body),
+			Headers:             headers,
+			RawJSON:             rawJSON,
+			BodyTruncated:       truncated,
+			LatencyMilliseconds: details.latency.Milliseconds(),
+			Attempts:            details.attempts,
+			TLS:                 httpTLS(response.TLS),
 		}
 	}
 
@@ -236,6 +463,30 @@ func responseToOutput(response *http.Response, err error) ([]byte, error) {
 	return b, nil
 }
 
+// httpTLS returns the outcome of the TLS handshake of a connection, nil for plain text
+func httpTLS(state *tls.ConnectionState) *HTTPTLS {
+	if state == nil {
+		return nil
+	}
+
+	info := &HTTPTLS{
+		Version:            tls.VersionName(state.Version),
+		CipherSuite:        tls.CipherSuiteName(state.CipherSuite),
+		ServerName:         state.ServerName,
+		NegotiatedProtocol: state.NegotiatedProtocol,
+	}
+	for _, cert := range state.PeerCertificates {
+		info.PeerCertificates = append(info.PeerCertificates, HTTPCertificate{
+			Subject:   cert.Subject.String(),
+			Issuer:    cert.Issuer.String(),
+			DNSNames:  cert.DNSNames,
+			NotBefore: cert.NotBefore,
+			NotAfter:  cert.NotAfter,
+		})
+	}
+	return info
+}
+
 // parseTimeout parses a string into a time.Duration.
 // If the string is empty, it returns 0.
 func parseTimeout(s string) (time.Duration, error) {
@@ -2,7 +2,11 @@ package collect
 
 import (
 	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"encoding/json"
+	"encoding/pem"
 	"fmt"
 	"io"
 	"net/http"
@@ -11,8 +15,10 @@ import (
 	"testing"
 	"time"
 
+	"github.com/replicatedhq/troubleshoot/internal/testutils"
 	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 type Headers struct {
@@ -473,3 +479,212 @@ func Test_responseToOutput(t *testing.T) {
 		})
 	}
 }
+
+func Test_httpRequest_retries(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`{"status": "ok"}`))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name         string
+		retry        *troubleshootv1beta2.HTTPRetry
+		wantStatus   int
+		wantAttempts int
+		wantRequests int
+	}{
+		{
+			name:         "no retry",
+			wantStatus:   http.StatusServiceUnavailable,
+			wantRequests: 1,
+		},
+		{
+			name:         "retried until success",
+			retry:        &troubleshootv1beta2.HTTPRetry{Attempts: 5, Backoff: "1ms"},
+			wantStatus:   http.StatusOK,
+			wantAttempts: 3,
+			wantRequests: 3,
+		},
+		{
+			name:         "out of attempts",
+			retry:        &troubleshootv1beta2.HTTPRetry{Attempts: 2, Backoff: "1ms"},
+			wantStatus:   http.StatusServiceUnavailable,
+			wantAttempts: 2,
+			wantRequests: 2,
+		},
+		{
+			name:         "status not retried",
+			retry:        &troubleshootv1beta2.HTTPRetry{Attempts: 5, Backoff: "1ms", StatusCodes: []int{http.StatusTooManyRequests}},
+			wantStatus:   http.StatusServiceUnavailable,
+			wantAttempts: 1,
+			wantRequests: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests = 0
+			request, err := newHTTPRequest(&troubleshootv1beta2.Get{URL: server.URL, Retry: tt.retry}, nil, nil)
+			require.NoError(t, err)
+
+			b, err := request.collect(context.Background())
+			require.NoError(t, err)
+
+			var output struct {
+				Response HTTPResponse `json:"response"`
+			}
+			require.NoError(t, json.Unmarshal(b, &output))
+			assert.Equal(t, tt.wantStatus, output.Response.Status)
+			assert.Equal(t, tt.wantAttempts, output.Response.Attempts)
+			assert.Equal(t, tt.wantRequests, requests)
+		})
+	}
+}
+
+func Test_httpRequest_canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		cancel()
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	request, err := newHTTPRequest(&troubleshootv1beta2.Get{
+		URL:   server.URL,
+		Retry: &troubleshootv1beta2.HTTPRetry{Attempts: 5, Backoff: "1h"},
+	}, nil, nil)
+	require.NoError(t, err)
+
+	b, err := request.collect(ctx)
+	require.NoError(t, err)
+
+	var output struct {
+		Error *HTTPError `json:"error"`
+	}
+	require.NoError(t, json.Unmarshal(b, &output))
+	require.NotNil(t, output.Error)
+	assert.Contains(t, output.Error.Message, "context canceled")
+	assert.Equal(t, 1, requests)
+}
+
+func Test_httpResponseToOutput_maxBodySize(t *testing.T) {
+	tests := []struct {
+		name          string
+		body          string
+		maxBodySize   int64
+		wantBody      string
+		wantTruncated bool
+	}{
+		{
+			name:        "body within limit",
+			body:        `{"ok": true}`,
+			maxBodySize: 12,
+			wantBody:    `{"ok": true}`,
+		},
+		{
+			name:          "body over limit",
+			body:          `{"ok": true}`,
+			maxBodySize:   5,
+			wantBody:      `{"ok"`,
+			wantTruncated: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response := &http.Response{
+				Body:       io.NopCloser(bytes.NewBufferString(tt.body)),
+				StatusCode: http.StatusOK,
+			}
+			details := httpRequestDetails{latency: 1500 * time.Millisecond}
+
+			b, err := httpResponseToOutput(response, nil, details, tt.maxBodySize)
+			require.NoError(t, err)
+
+			var output struct {
+				Response HTTPResponse `json:"response"`
+			}
+			require.NoError(t, json.Unmarshal(b, &output))
+			assert.Equal(t, tt.wantBody, output.Response.Body)
+			assert.Equal(t, tt.wantTruncated, output.Response.BodyTruncated)
+			assert.Equal(t, int64(1500), output.Response.LatencyMilliseconds)
+			assert.Equal(t, tt.wantTruncated, len(output.Response.RawJSON) == 0)
+		})
+	}
+}
+
+func Test_httpRequest_clientCertificate(t *testing.T) {
+	clientCAs := x509.NewCertPool()
+	require.True(t, clientCAs.AppendCertsFromPEM([]byte(testutils.GetTestFixture(t, "db/ca.pem"))))
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.TLS.PeerCertificates[0].Subject.CommonName))
+	}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs}
+	server.StartTLS()
+	defer server.Close()
+
+	serverCert := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}))
+
+	tests := []struct {
+		name      string
+		tlsParams *troubleshootv1beta2.TLSParams
+		wantErr   bool
+	}{
+		{
+			name: "client certificate",
+			tlsParams: &troubleshootv1beta2.TLSParams{
+				CACert:     serverCert,
+				ClientCert: testutils.GetTestFixture(t, "db/client.pem"),
+				ClientKey:  testutils.GetTestFixture(t, "db/client-key.pem"),
+			},
+		},
+		{
+			name: "client certificate files",
+			tlsParams: &troubleshootv1beta2.TLSParams{
+				CACert:     serverCert,
+				ClientCert: testutils.TestFixtureFilePath(t, "db/client.pem"),
+				ClientKey:  testutils.TestFixtureFilePath(t, "db/client-key.pem"),
+			},
+		},
+		{
+			name:      "no client certificate",
+			tlsParams: &troubleshootv1beta2.TLSParams{CACert: serverCert},
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request, err := newHTTPRequest(&troubleshootv1beta2.Get{URL: server.URL, TLS: tt.tlsParams}, nil, nil)
+			require.NoError(t, err)
+
+			b, err := request.collect(context.Background())
+			require.NoError(t, err)
+
+			var output struct {
+				Response *HTTPResponse `json:"response"`
+				Error    *HTTPError    `json:"error"`
+			}
+			require.NoError(t, json.Unmarshal(b, &output))
+			if tt.wantErr {
+				require.NotNil(t, output.Error)
+				return
+			}
+
+			require.NotNil(t, output.Response)
+			assert.Equal(t, "client", output.Response.Body)
+			require.NotNil(t, output.Response.TLS)
+			assert.Equal(t, "TLS 1.3", output.Response.TLS.Version)
+			require.Len(t, output.Response.TLS.PeerCertificates, 1)
+			assert.Equal(t, "O=Acme Co", output.Response.TLS.PeerCertificates[0].Subject)
+		})
+	}
+}
@@ -0,0 +1,178 @@
+package collect
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/registry"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/klog/v2"
+)
+
+// ImageFactsInfo is the output of the imageFacts collector
+type ImageFactsInfo struct {
+	// Platform is the platform of the configs read from multi-architecture images
+	Platform string           `json:"platform"`
+	Images   []ImageFactsData `json:"images"`
+}
+
+// ImageFactsData is the metadata of an image from its manifests and the config of the platform
+type ImageFactsData struct {
+	Image string `json:"image"`
+	// Mirror is the image at the registry mirror the metadata was read from, if any
+	Mirror string `json:"mirror,omitempty"`
+	// Digest is the digest of the manifest of the platform
+	Digest string `json:"digest,omitempty"`
+	// Platforms are the platforms the image is available for
+	Platforms    []string          `json:"platforms,omitempty"`
+	Created      *time.Time        `json:"created,omitempty"`
+	Labels       map[string]string `json:"labels,omitempty"`
+	ExposedPorts []string          `json:"exposedPorts,omitempty"`
+	// User is the user of the config, empty for images that run as root by default
+	User string `json:"user,omitempty"`
+	// RunsAsRoot is true when the user of the image is root, by name or ID, or is not set
+	RunsAsRoot bool   `json:"runsAsRoot"`
+	Error      string `json:"error,omitempty"`
+}
+
+type CollectImageFacts struct {
+	Collector    *troubleshootv1beta2.ImageFacts
+	BundlePath   string
+	Namespace    string
+	ClientConfig *rest.Config
+	Client       kubernetes.Interface
+	Context      context.Context
+	RBACErrors
+}
+
+func (c *CollectImageFacts) Title() string {
+	return getCollectorName(c)
+}
+
+func (c *CollectImageFacts) IsExcluded() (bool, error) {
+	return isExcluded(c.Collector.Exclude)
+}
+
+func (c *CollectImageFacts) Collect(progressChan chan<- interface{}) (CollectorResult, error) {
+	platform := c.Collector.Platform
+	if platform == "" {
+		platform = defaultImageLayersPlatform
+	}
+	if _, _, _, err := registry.ParsePlatform(platform); err != nil {
+		return nil, err
+	}
+
+	info := ImageFactsInfo{
+		Platform: platform,
+		Images:   []ImageFactsData{},
+	}
+
+	collected := map[string]bool{}
+	for _, image := range c.Collector.Images {
+		if collected[image] {
+			continue
+		}
+		collected[image] = true
+
+		imageData, err := c.collectImageFacts(image, platform)
+		if err != nil {
+			klog.Errorf("failed to collect facts of image %s: %v", image, err)
+			imageData = &ImageFactsData{Image: image, Error: err.Error()}
+		}
+		info.Images = append(info.Images, *imageData)
+	}
+
+	b, err := json.MarshalIndent(info, "", "  ")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal image facts")
+	}
+
+	output := NewResult()
+	output.SaveResult(c.BundlePath, ImageFactsOutputPath(c.Collector.CollectorName), bytes.NewBuffer(b))
+
+	return output, nil
+}
+
+// ImageFactsOutputPath is the path of the output of an imageFacts collector in the bundle
+func ImageFactsOutputPath(collectorName string) string {
+	if collectorName == "" {
+		collectorName = "images"
+	}
+	return fmt.Sprintf("registry/%s-facts.json", collectorName)
+}
+
+// collectImageFacts reads the metadata of the image from the mirrors of its registry, then from
+// its registry, and returns the first it could read
+func (c *CollectImageFacts) collectImageFacts(image string, platform string) (*ImageFactsData, error) {
+	candidates, err := registry.MirrorCandidates(image, c.Collector.RegistryMirrors)
+	if err != nil {
+		return nil, err
+	}
+
+	var lastErr error
+	for _, candidate := range candidates {
+		imageData, err := c.collectImageFactsFrom(candidate, platform)
+		if err != nil {
+			klog.Errorf("failed to get facts of image %s: %v", candidate, err)
+			lastErr = err
+			continue
+		}
+
+		imageData.Image = image
+		if candidate != image {
+			imageData.Mirror = candidate
+		}
+		return imageData, nil
+	}
+	return nil, lastErr
+}
+
+func (c *CollectImageFacts) collectImageFactsFrom(image string, platform string) (*ImageFactsData, error) {
+	imageRef, err := registry.ParseImageReference(image)
+	if err != nil {
+		return nil, err
+	}
+
+	authConfig, err := registry.ResolveAuthConfig(c.Context, c.ClientConfig, c.Namespace, c.Collector, imageRef)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get auth config")
+	}
+
+	client := registry.NewClient(registry.DefaultOptions(authConfig))
+	imageManifest, err := client.GetManifest(c.Context, imageRef)
+	if err != nil {
+		return nil, err
+	}
+	imageConfig, err := client.GetConfig(c.Context, imageRef, platform)
+	if err != nil {
+		return nil, err
+	}
+	return newImageFactsData(image, imageManifest, imageConfig), nil
+}
+
+func newImageFactsData(image string, imageManifest *registry.ImageManifest, imageConfig *registry.ImageConfig) *ImageFactsData {
+	return &ImageFactsData{
+		Image:        image,
+		Digest:       imageConfig.Digest,
+		Platforms:    imageManifest.Platforms,
+		Created:      imageConfig.Created,
+		Labels:       imageConfig.Labels,
+		ExposedPorts: imageConfig.ExposedPorts,
+		User:         imageConfig.User,
+		RunsAsRoot:   IsRootUser(imageConfig.User),
+	}
+}
+
+// IsRootUser returns whether the user of an image config, a name or ID optionally followed by a
+// group, e.g. 1000:1000, is root. Images without a user run as root.
+func IsRootUser(user string) bool {
+	user, _, _ = strings.Cut(strings.TrimSpace(user), ":")
+	return user == "" || user == "root" || user == "0"
+}
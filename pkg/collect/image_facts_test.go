@@ -0,0 +1,39 @@
+package collect
+
+import (
+	"testing"
+	"time"
+
+	"github.com/replicatedhq/troubleshoot/pkg/registry"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_newImageFactsData(t *testing.T) {
+	created := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	imageData := newImageFactsData("nginx:1.27", &registry.ImageManifest{
+		ManifestList: true,
+		Platforms:    []string{"linux/amd64", "linux/arm64"},
+	}, &registry.ImageConfig{
+		Digest:       "sha256:m1",
+		Created:      &created,
+		ExposedPorts: []string{"80/tcp"},
+	})
+
+	assert.Equal(t, &ImageFactsData{
+		Image:        "nginx:1.27",
+		Digest:       "sha256:m1",
+		Platforms:    []string{"linux/amd64", "linux/arm64"},
+		Created:      &created,
+		ExposedPorts: []string{"80/tcp"},
+		RunsAsRoot:   true,
+	}, imageData)
+}
+
+func TestIsRootUser(t *testing.T) {
+	for _, user := range []string{"", "root", "0", "0:0", "root:wheel", " 0 "} {
+		assert.True(t, IsRootUser(user), user)
+	}
+	for _, user := range []string{"1000", "1000:0", "nobody", "nonroot:nonroot", "65532"} {
+		assert.False(t, IsRootUser(user), user)
+	}
+}
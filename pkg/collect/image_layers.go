@@ -0,0 +1,179 @@
+package collect
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/registry"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/klog/v2"
+)
+
+const defaultImageLayersPlatform = "linux/amd64"
+
+// ImageLayersInfo is the output of the imageLayers collector
+type ImageLayersInfo struct {
+	// Platform is the platform of the manifests of multi-architecture images
+	Platform string            `json:"platform"`
+	Images   []ImageLayersData `json:"images"`
+}
+
+// ImageLayersData are the blobs of the manifest of an image. Sizes are compressed sizes, as
+// stored in the registry and pulled.
+type ImageLayersData struct {
+	Image string `json:"image"`
+	// Mirror is the image at the registry mirror the manifest was read from, if any
+	Mirror string `json:"mirror,omitempty"`
+	// Digest is the digest of the manifest of the platform
+	Digest string `json:"digest,omitempty"`
+	// Size is the size of the config and the layers of the image, without the layers of unknown
+	// size
+	Size       int64        `json:"size"`
+	ConfigSize int64        `json:"configSize,omitempty"`
+	Layers     []ImageLayer `json:"layers,omitempty"`
+	Error      string       `json:"error,omitempty"`
+}
+
+type ImageLayer struct {
+	Digest string `json:"digest"`
+	// DiffID is the digest of the uncompressed layer, the same for the same content compressed
+	// differently
+	DiffID string `json:"diffID,omitempty"`
+	// Size is -1 when the manifest does not record it
+	Size int64 `json:"size"`
+}
+
+type CollectImageLayers struct {
+	Collector    *troubleshootv1beta2.ImageLayers
+	BundlePath   string
+	Namespace    string
+	ClientConfig *rest.Config
+	Client       kubernetes.Interface
+	Context      context.Context
+	RBACErrors
+}
+
+func (c *CollectImageLayers) Title() string {
+	return getCollectorName(c)
+}
+
+func (c *CollectImageLayers) IsExcluded() (bool, error) {
+	return isExcluded(c.Collector.Exclude)
+}
+
+func (c *CollectImageLayers) Collect(progressChan chan<- interface{}) (CollectorResult, error) {
+	platform := c.Collector.Platform
+	if platform == "" {
+		platform = defaultImageLayersPlatform
+	}
+	if _, _, _, err := registry.ParsePlatform(platform); err != nil {
+		return nil, err
+	}
+
+	info := ImageLayersInfo{
+		Platform: platform,
+		Images:   []ImageLayersData{},
+	}
+
+	collected := map[string]bool{}
+	for _, image := range c.Collector.Images {
+		if collected[image] {
+			continue
+		}
+		collected[image] = true
+
+		imageData, err := c.collectImageLayers(image, platform)
+		if err != nil {
+			klog.Errorf("failed to collect layers of image %s: %v", image, err)
+			imageData = &ImageLayersData{Image: image, Error: err.Error()}
+		}
+		info.Images = append(info.Images, *imageData)
+	}
+
+	b, err := json.MarshalIndent(info, "", "  ")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal image layers")
+	}
+
+	output := NewResult()
+	output.SaveResult(c.BundlePath, ImageLayersOutputPath(c.Collector.CollectorName), bytes.NewBuffer(b))
+
+	return output, nil
+}
+
+// ImageLayersOutputPath is the path of the output of an imageLayers collector in the bundle
+func ImageLayersOutputPath(collectorName string) string {
+	if collectorName == "" {
+		collectorName = "images"
+	}
+	return fmt.Sprintf("registry/%s-layers.json", collectorName)
+}
+
+// collectImageLayers reads the layers of the image from the mirrors of its registry, then from
+// its registry, and returns the first it could read
+func (c *CollectImageLayers) collectImageLayers(image string, platform string) (*ImageLayersData, error) {
+	candidates, err := registry.MirrorCandidates(image, c.Collector.RegistryMirrors)
+	if err != nil {
+		return nil, err
+	}
+
+	var lastErr error
+	for _, candidate := range candidates {
+		imageLayers, err := c.collectImageLayersFrom(candidate, platform)
+		if err != nil {
+			klog.Errorf("failed to get layers of image %s: %v", candidate, err)
+			lastErr = err
+			continue
+		}
+
+		imageData := newImageLayersData(image, imageLayers)
+		if candidate != image {
+			imageData.Mirror = candidate
+		}
+		return imageData, nil
+	}
+	return nil, lastErr
+}
+
+func (c *CollectImageLayers) collectImageLayersFrom(image string, platform string) (*registry.ImageLayers, error) {
+	imageRef, err := registry.ParseImageReference(image)
+	if err != nil {
+		return nil, err
+	}
+
+	authConfig, err := registry.ResolveAuthConfig(c.Context, c.ClientConfig, c.Namespace, c.Collector, imageRef)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get auth config")
+	}
+
+	client := registry.NewClient(registry.DefaultOptions(authConfig))
+	return client.GetLayers(c.Context, imageRef, platform)
+}
+
+func newImageLayersData(image string, imageLayers *registry.ImageLayers) *ImageLayersData {
+	imageData := &ImageLayersData{
+		Image:      image,
+		Digest:     imageLayers.Digest,
+		ConfigSize: imageLayers.ConfigSize,
+		Layers:     []ImageLayer{},
+	}
+	if imageLayers.ConfigSize > 0 {
+		imageData.Size = imageLayers.ConfigSize
+	}
+	for _, layer := range imageLayers.Layers {
+		imageData.Layers = append(imageData.Layers, ImageLayer{
+			Digest: layer.Digest,
+			DiffID: layer.DiffID,
+			Size:   layer.Size,
+		})
+		if layer.Size > 0 {
+			imageData.Size += layer.Size
+		}
+	}
+	return imageData
+}
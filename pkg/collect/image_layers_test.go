@@ -0,0 +1,45 @@
+package collect
+
+import (
+	"testing"
+
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/registry"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_newImageLayersData(t *testing.T) {
+	imageData := newImageLayersData("nginx:1.27", &registry.ImageLayers{
+		Digest:     "sha256:m1",
+		ConfigSize: 1500,
+		Layers: []registry.Layer{
+			{Digest: "sha256:a1", DiffID: "sha256:d1", Size: 3000000},
+			{Digest: "sha256:a2", DiffID: "sha256:d2", Size: -1},
+		},
+	})
+
+	assert.Equal(t, &ImageLayersData{
+		Image:      "nginx:1.27",
+		Digest:     "sha256:m1",
+		Size:       3001500,
+		ConfigSize: 1500,
+		Layers: []ImageLayer{
+			{Digest: "sha256:a1", DiffID: "sha256:d1", Size: 3000000},
+			{Digest: "sha256:a2", DiffID: "sha256:d2", Size: -1},
+		},
+	}, imageData)
+}
+
+func TestCollectImageLayers_invalidPlatform(t *testing.T) {
+	c := &CollectImageLayers{Collector: &troubleshootv1beta2.ImageLayers{
+		Images:   []string{"nginx:1.27"},
+		Platform: "arm64",
+	}}
+	_, err := c.Collect(nil)
+	assert.EqualError(t, err, `invalid platform "arm64", must be os/architecture or os/architecture/variant`)
+}
+
+func TestImageLayersOutputPath(t *testing.T) {
+	assert.Equal(t, "registry/images-layers.json", ImageLayersOutputPath(""))
+	assert.Equal(t, "registry/app-layers.json", ImageLayersOutputPath("app"))
+}
@@ -8,19 +8,21 @@ import (
 	"strings"
 
 	"github.com/containers/image/v5/docker/reference"
-	"github.com/containers/image/v5/transports/alltransports"
 	"github.com/containers/image/v5/types"
 	"github.com/google/go-containerregistry/pkg/name"
 	"github.com/pkg/errors"
 	"github.com/sigstore/cosign/v2/pkg/cosign"
 	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/registry"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/klog/v2"
 )
 
 type ImageSignatureData struct {
-	Image      string      `json:"image"`
+	Image string `json:"image"`
+	// Mirror is the image at the registry mirror the signatures were found at, if any
+	Mirror     string      `json:"mirror,omitempty"`
 	Signatures []Signature `json:"signatures,omitempty"`
 	Error      string      `json:"error,omitempty"`
 }
@@ -28,7 +30,16 @@ type ImageSignatureData struct {
 type Signature struct {
 	Verified  bool   `json:"verified"`
 	Signature string `json:"signature,omitempty"`
-	Error     string `json:"error,omitempty"`
+	// Format is how the signature is stored: cosign, notation or sigstore-bundle
+	Format string `json:"format,omitempty"`
+	// ArtifactType and Annotations describe a signature found with the referrers API
+	ArtifactType string            `json:"artifactType,omitempty"`
+	Annotations  map[string]string `json:"annotations,omitempty"`
+	// Payload is the decoded Signature, when it is a simple signing payload
+	Payload *SimpleSigningPayload `json:"payload,omitempty"`
+	// Certificate is the decoded signing certificate of a keyless signature
+	Certificate *SignatureCertificate `json:"certificate,omitempty"`
+	Error       string                `json:"error,omitempty"`
 }
 
 type ImageSignaturesInfo struct {
@@ -59,121 +70,22 @@ func (c *CollectImageSignatures) Collect(progressChan chan<- interface{}) (Colle
 	}
 
 	for _, image := range c.Collector.Images {
-		imageData := ImageSignatureData{
-			Image: image,
-		}
-
-		// Handle empty image names
-		if strings.TrimSpace(image) == "" {
-			klog.Errorf("empty image name provided")
-			imageData.Error = "empty image name provided"
-			signatureInfo.Images = append(signatureInfo.Images, imageData)
-			continue
-		}
-
-		// Set up authentication for the image with improved error handling
-		imageRef, err := alltransports.ParseImageName(fmt.Sprintf("docker://%s", image))
+		// Look the signatures up at the mirrors of the registry of the image first, the
+		// signatures of an image are stored next to it
+		candidates, err := registry.MirrorCandidates(image, c.Collector.RegistryMirrors)
 		if err != nil {
-			klog.Errorf("failed to parse image name %s: %v", image, err)
-			// Categorize parsing errors
-			if strings.Contains(err.Error(), "invalid reference format") {
-				imageData.Error = fmt.Sprintf("invalid image name format: %s", image)
-			} else {
-				imageData.Error = fmt.Sprintf("failed to parse image name: %v", err)
-			}
-			signatureInfo.Images = append(signatureInfo.Images, imageData)
-			continue
+			candidates = []string{image}
 		}
 
-		// Handle authentication configuration with better error categorization
-		authConfig, err := getImageAuthConfigGeneric(c.Namespace, c.ClientConfig, c.Collector, imageRef)
-		if err != nil {
-			klog.Errorf("failed to get auth config for %s: %v", image, err)
-			// Categorize auth errors for better debugging
-			var authError string
-			if strings.Contains(err.Error(), "connection refused") {
-				authError = "registry authentication failed: unable to connect to Kubernetes API"
-			} else if strings.Contains(err.Error(), "secret") && strings.Contains(err.Error(), "not found") {
-				authError = "registry authentication failed: specified secret not found"
-			} else if strings.Contains(err.Error(), "not supported") {
-				authError = "registry authentication failed: invalid secret format"
-			} else {
-				authError = fmt.Sprintf("registry authentication failed: %v", err)
+		var imageData ImageSignatureData
+		for i, candidate := range candidates {
+			imageData = c.collectImageSignatures(candidate)
+			imageData.Image = image
+			if candidate != image {
+				imageData.Mirror = candidate
 			}
-			imageData.Error = authError
-			signatureInfo.Images = append(signatureInfo.Images, imageData)
-			continue
-		}
-
-		// Create system context with authentication
-		sysCtx := createSystemContextWithErrorHandling(authConfig, image)
-		if sysCtx == nil {
-			imageData.Error = "failed to create system context for registry access"
-			signatureInfo.Images = append(signatureInfo.Images, imageData)
-			continue
-		}
-
-		// Log authentication status for debugging
-		if authConfig != nil {
-			klog.V(4).Infof("Using authentication for image %s", image)
-		} else {
-			klog.V(4).Infof("No authentication configured for image %s", image)
-		}
-
-		// Handle registry connectivity with timeout and retries
-		err = validateRegistryAccess(c.Context, imageRef, sysCtx)
-		if err != nil {
-			klog.Errorf("registry access validation failed for %s: %v", image, err)
-			var registryError string
-			if strings.Contains(err.Error(), "timeout") {
-				registryError = "registry access failed: connection timeout"
-			} else if strings.Contains(err.Error(), "connection refused") {
-				registryError = "registry access failed: connection refused (registry may be down or unreachable)"
-			} else if strings.Contains(err.Error(), "no such host") {
-				registryError = "registry access failed: registry hostname not found (check network or air-gapped environment)"
-			} else if strings.Contains(err.Error(), "certificate") || strings.Contains(err.Error(), "tls") {
-				registryError = "registry access failed: TLS/certificate error (check registry certificate configuration)"
-			} else {
-				registryError = fmt.Sprintf("registry access failed: %v", err)
-			}
-
-			// For air-gapped environments, we still try to collect what we can
-			if isAirGappedRegistry(image) {
-				klog.V(2).Infof("Detected air-gapped registry for %s, collecting basic info", image)
-				imageData.Signatures = []Signature{
-					{
-						Verified:  false,
-						Signature: "",
-						Error:     "signature verification skipped: air-gapped environment detected",
-					},
-				}
-			} else {
-				imageData.Error = registryError
-			}
-			signatureInfo.Images = append(signatureInfo.Images, imageData)
-			continue
-		}
-
-		// Fetch signatures using Cosign
-		sigInfos, err := fetchImageSignatures(c.Context, imageRef, sysCtx)
-		if err != nil {
-			klog.Errorf("failed to fetch signatures for %s: %v", image, err)
-			imageData.Error = fmt.Sprintf("failed to fetch signatures: %v", err)
-			signatureInfo.Images = append(signatureInfo.Images, imageData)
-			continue
-		}
-
-		// Format signature data for JSON output
-		imageData.Signatures = formatSignatureData(sigInfos)
-
-		// If no signatures found, add a message indicating that
-		if len(imageData.Signatures) == 0 {
-			imageData.Signatures = []Signature{
-				{
-					Verified:  false,
-					Signature: "",
-					Error:     "no signatures found for this image",
-				},
+			if i == len(candidates)-1 || hasSignaturePayloads(imageData) {
+				break
 			}
 		}
 
@@ -197,48 +109,139 @@ func (c *CollectImageSignatures) Collect(progressChan chan<- interface{}) (Colle
 	return output, nil
 }
 
-// SignatureInfo represents signature information retrieved from Cosign
-type SignatureInfo struct {
-	Signature string `json:"signature"`
-	Verified  bool   `json:"verified"`
-	Error     string `json:"error,omitempty"`
-}
+// collectImageSignatures fetches the signatures of an image
+func (c *CollectImageSignatures) collectImageSignatures(image string) ImageSignatureData {
+	imageData := ImageSignatureData{
+		Image: image,
+	}
 
-// createSystemContextForSignatures creates a system context with authentication for signature operations
-func createSystemContextForSignatures(authConfig *registryAuthConfig) *types.SystemContext {
-	sysCtx := &types.SystemContext{
-		DockerDisableV1Ping:         true,
-		DockerInsecureSkipTLSVerify: types.OptionalBoolTrue,
+	// Handle empty image names
+	if strings.TrimSpace(image) == "" {
+		klog.Errorf("empty image name provided")
+		imageData.Error = "empty image name provided"
+		return imageData
+	}
+
+	// Set up authentication for the image with improved error handling
+	imageRef, err := registry.ParseImageReference(image)
+	if err != nil {
+		klog.Errorf("failed to parse image name %s: %v", image, err)
+		// Categorize parsing errors
+		if strings.Contains(err.Error(), "invalid reference format") {
+			imageData.Error = fmt.Sprintf("invalid image name format: %s", image)
+		} else {
+			imageData.Error = fmt.Sprintf("failed to parse image name: %v", err)
+		}
+		return imageData
 	}
 
+	// Handle authentication configuration with better error categorization
+	authConfig, err := registry.ResolveAuthConfig(c.Context, c.ClientConfig, c.Namespace, c.Collector, imageRef)
+	if err != nil {
+		klog.Errorf("failed to get auth config for %s: %v", image, err)
+		// Categorize auth errors for better debugging
+		var authError string
+		if strings.Contains(err.Error(), "connection refused") {
+			authError = "registry authentication failed: unable to connect to Kubernetes API"
+		} else if strings.Contains(err.Error(), "secret") && strings.Contains(err.Error(), "not found") {
+			authError = "registry authentication failed: specified secret not found"
+		} else if strings.Contains(err.Error(), "not supported") {
+			authError = "registry authentication failed: invalid secret format"
+		} else {
+			authError = fmt.Sprintf("registry authentication failed: %v", err)
+		}
+		imageData.Error = authError
+		return imageData
+	}
+
+	// Create system context with authentication
+	sysCtx := registry.NewClient(registry.DefaultOptions(authConfig)).SystemContext()
+
+	// Log authentication status for debugging
 	if authConfig != nil {
-		sysCtx.DockerAuthConfig = &types.DockerAuthConfig{
-			Username: authConfig.username,
-			Password: authConfig.password,
+		klog.V(4).Infof("Using authentication for image %s", image)
+	} else {
+		klog.V(4).Infof("No authentication configured for image %s", image)
+	}
+
+	// Handle registry connectivity with timeout and retries
+	err = validateRegistryAccess(c.Context, imageRef, sysCtx)
+	if err != nil {
+		klog.Errorf("registry access validation failed for %s: %v", image, err)
+		var registryError string
+		if strings.Contains(err.Error(), "timeout") {
+			registryError = "registry access failed: connection timeout"
+		} else if strings.Contains(err.Error(), "connection refused") {
+			registryError = "registry access failed: connection refused (registry may be down or unreachable)"
+		} else if strings.Contains(err.Error(), "no such host") {
+			registryError = "registry access failed: registry hostname not found (check network or air-gapped environment)"
+		} else if strings.Contains(err.Error(), "certificate") || strings.Contains(err.Error(), "tls") {
+			registryError = "registry access failed: TLS/certificate error (check registry certificate configuration)"
+		} else {
+			registryError = fmt.Sprintf("registry access failed: %v", err)
 		}
+
+		// For air-gapped environments, we still try to collect what we can
+		if isAirGappedRegistry(image) {
+			klog.V(2).Infof("Detected air-gapped registry for %s, collecting basic info", image)
+			imageData.Signatures = []Signature{
+				{
+					Verified:  false,
+					Signature: "",
+					Error:     "signature verification skipped: air-gapped environment detected",
+				},
+			}
+		} else {
+			imageData.Error = registryError
+		}
+		return imageData
 	}
 
-	return sysCtx
-}
+	// Fetch signatures using Cosign
+	sigInfos, err := fetchImageSignatures(c.Context, imageRef, sysCtx)
+	if err != nil {
+		klog.Errorf("failed to fetch signatures for %s: %v", image, err)
+		imageData.Error = fmt.Sprintf("failed to fetch signatures: %v", err)
+		return imageData
+	}
 
-// createSystemContextWithErrorHandling creates a system context with enhanced error handling
-func createSystemContextWithErrorHandling(authConfig *registryAuthConfig, image string) *types.SystemContext {
-	sysCtx := &types.SystemContext{
-		DockerDisableV1Ping:         true,
-		DockerInsecureSkipTLSVerify: types.OptionalBoolTrue,
+	// Format signature data for JSON output
+	imageData.Signatures = formatSignatureData(sigInfos)
+
+	// If no signatures found, add a message indicating that
+	if len(imageData.Signatures) == 0 {
+		imageData.Signatures = []Signature{
+			{
+				Verified:  false,
+				Signature: "",
+				Error:     "no signatures found for this image",
+			},
+		}
 	}
 
-	if authConfig != nil {
-		sysCtx.DockerAuthConfig = &types.DockerAuthConfig{
-			Username: authConfig.username,
-			Password: authConfig.password,
+	return imageData
+}
+
+// hasSignaturePayloads returns true when signatures of the image were found
+func hasSignaturePayloads(imageData ImageSignatureData) bool {
+	for _, signature := range imageData.Signatures {
+		if signature.Signature != "" {
+			return true
 		}
-		klog.V(4).Infof("Created system context with authentication for %s", image)
-	} else {
-		klog.V(4).Infof("Created system context without authentication for %s", image)
 	}
+	return false
+}
 
-	return sysCtx
+// SignatureInfo represents signature information retrieved from Cosign
+type SignatureInfo struct {
+	Signature    string                `json:"signature"`
+	Format       string                `json:"format,omitempty"`
+	ArtifactType string                `json:"artifactType,omitempty"`
+	Annotations  map[string]string     `json:"annotations,omitempty"`
+	Payload      *SimpleSigningPayload `json:"payload,omitempty"`
+	Certificate  *SignatureCertificate `json:"certificate,omitempty"`
+	Verified     bool                  `json:"verified"`
+	Error        string                `json:"error,omitempty"`
 }
 
 // validateRegistryAccess validates that we can access the registry with given credentials
@@ -316,7 +319,8 @@ func isAirGappedRegistry(image string) bool {
 	return false
 }
 
-// fetchImageSignatures retrieves signatures for an image using Cosign
+// fetchImageSignatures retrieves the signatures of an image, those cosign finds by their tag and
+// those attached with the referrers API, such as Notation signatures
 func fetchImageSignatures(ctx context.Context, imageRef types.ImageReference, sysCtx *types.SystemContext) ([]SignatureInfo, error) {
 	var signatures []SignatureInfo
 
@@ -335,26 +339,39 @@ func fetchImageSignatures(ctx context.Context, imageRef types.ImageReference, sy
 	// Note: We use FetchSignaturesForReference which is the public API
 	signedPayloads, err := cosign.FetchSignaturesForReference(ctx, ref)
 	if err != nil {
-		klog.V(2).Infof("No signatures found or error fetching for %s: %v", imageName, err)
-		// Return empty slice instead of error - many images don't have signatures
-		return signatures, nil
+		// Many images don't have signatures, not finding any is not an error
+		klog.V(2).Infof("No cosign signatures found or error fetching for %s: %v", imageName, err)
 	}
 
 	// Process each signed payload
 	for i, payload := range signedPayloads {
 		sigInfo := SignatureInfo{
 			Signature: string(payload.Payload),
+			Format:    SignatureFormatCosign,
 			Verified:  false, // We're only collecting, not verifying yet
 		}
 
 		if len(payload.Payload) == 0 {
 			sigInfo.Error = "empty signature payload"
+		} else if decoded, err := decodeSimpleSigningPayload(payload.Payload); err != nil {
+			klog.V(4).Infof("Failed to decode signature %d for image %s: %v", i+1, imageName, err)
+			sigInfo.Error = fmt.Sprintf("failed to decode signature payload: %v", err)
+		} else {
+			sigInfo.Payload = decoded
 		}
+		sigInfo.Certificate = decodeSignatureCertificate(payload.Cert)
 
 		signatures = append(signatures, sigInfo)
 		klog.V(4).Infof("Found signature %d for image %s (length: %d bytes)", i+1, imageName, len(payload.Payload))
 	}
 
+	// Signatures attached with the referrers API are not found by their tag
+	referrers, err := fetchReferrerSignatures(ctx, ref, sysCtx)
+	if err != nil {
+		klog.V(2).Infof("No referrer signatures found or error fetching for %s: %v", imageName, err)
+	}
+	signatures = append(signatures, referrers...)
+
 	klog.V(2).Infof("Found %d signatures for image %s", len(signatures), imageName)
 	return signatures, nil
 }
@@ -364,9 +381,14 @@ func formatSignatureData(sigInfos []SignatureInfo) []Signature {
 	signatures := make([]Signature, len(sigInfos))
 	for i, info := range sigInfos {
 		signatures[i] = Signature{
-			Signature: info.Signature,
-			Verified:  info.Verified,
-			Error:     info.Error,
+			Signature:    info.Signature,
+			Format:       info.Format,
+			ArtifactType: info.ArtifactType,
+			Annotations:  info.Annotations,
+			Payload:      info.Payload,
+			Certificate:  info.Certificate,
+			Verified:     info.Verified,
+			Error:        info.Error,
 		}
 	}
 	return signatures
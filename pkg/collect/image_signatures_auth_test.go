@@ -1,11 +1,13 @@
 package collect
 
 import (
+	"context"
 	"encoding/base64"
 	"testing"
 
 	"github.com/containers/image/v5/transports/alltransports"
 	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/registry"
 	"k8s.io/client-go/kubernetes/fake"
 	"k8s.io/client-go/rest"
 )
@@ -98,7 +100,7 @@ func TestGetImageAuthConfig_ImageSignatures(t *testing.T) {
 				t.Fatalf("Failed to parse image name: %v", err)
 			}
 
-			authConfig, err := getImageAuthConfigGeneric("default", &rest.Config{}, tt.imageSignatures, imageRef)
+			authConfig, err := registry.ResolveAuthConfig(context.Background(), &rest.Config{}, "default", tt.imageSignatures, imageRef)
 
 			if tt.expectError {
 				if err == nil {
@@ -117,7 +119,7 @@ func TestGetImageAuthConfig_ImageSignatures(t *testing.T) {
 					t.Error("Expected auth config but got nil")
 					return
 				}
-				if authConfig.username == "" || authConfig.password == "" {
+				if authConfig.Username == "" || authConfig.Password == "" {
 					t.Error("Expected username and password in auth config")
 				}
 			} else {
@@ -129,59 +131,6 @@ func TestGetImageAuthConfig_ImageSignatures(t *testing.T) {
 	}
 }
 
-func TestCreateSystemContextForSignatures(t *testing.T) {
-	tests := []struct {
-		name       string
-		authConfig *registryAuthConfig
-		expectAuth bool
-	}{
-		{
-			name:       "no auth config",
-			authConfig: nil,
-			expectAuth: false,
-		},
-		{
-			name: "with auth config",
-			authConfig: &registryAuthConfig{
-				username: "testuser",
-				password: "testpass",
-			},
-			expectAuth: true,
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			sysCtx := createSystemContextForSignatures(tt.authConfig)
-
-			if tt.expectAuth {
-				if sysCtx.DockerAuthConfig == nil {
-					t.Error("Expected Docker auth config but got nil")
-					return
-				}
-				if sysCtx.DockerAuthConfig.Username != tt.authConfig.username {
-					t.Errorf("Expected username %s but got %s", tt.authConfig.username, sysCtx.DockerAuthConfig.Username)
-				}
-				if sysCtx.DockerAuthConfig.Password != tt.authConfig.password {
-					t.Errorf("Expected password %s but got %s", tt.authConfig.password, sysCtx.DockerAuthConfig.Password)
-				}
-			} else {
-				if sysCtx.DockerAuthConfig != nil {
-					t.Error("Expected no Docker auth config but got one")
-				}
-			}
-
-			// Check default system context settings
-			if !sysCtx.DockerDisableV1Ping {
-				t.Error("Expected DockerDisableV1Ping to be true")
-			}
-			if sysCtx.DockerInsecureSkipTLSVerify != 1 { // types.OptionalBoolTrue = 1
-				t.Error("Expected DockerInsecureSkipTLSVerify to be true")
-			}
-		})
-	}
-}
-
 func TestCollectImageSignatures_WithAuthentication(t *testing.T) {
 	collector := &CollectImageSignatures{
 		Collector: &troubleshootv1beta2.ImageSignatures{
@@ -0,0 +1,108 @@
+package collect
+
+import (
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/json"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	// fulcioIssuerOID is the deprecated extension of Fulcio certificates with the OIDC issuer as a raw string
+	fulcioIssuerOID = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 57264, 1, 1}
+	// fulcioIssuerV2OID is the extension of Fulcio certificates with the OIDC issuer as a DER encoded UTF8String
+	fulcioIssuerV2OID = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 57264, 1, 8}
+)
+
+// SimpleSigningPayload is the decoded simple signing payload of a signature: the image the signature
+// was created for and the annotations added when signing, e.g. with cosign sign -a key=value
+type SimpleSigningPayload struct {
+	Type                 string                 `json:"type,omitempty"`
+	DockerReference      string                 `json:"dockerReference,omitempty"`
+	DockerManifestDigest string                 `json:"dockerManifestDigest,omitempty"`
+	Annotations          map[string]interface{} `json:"annotations,omitempty"`
+}
+
+// SignatureCertificate is the decoded signing certificate of a keyless signature. Identities are the
+// email addresses and URIs of the subject alternative names, and Issuer is the OIDC issuer that
+// authenticated them.
+type SignatureCertificate struct {
+	Identities        []string  `json:"identities,omitempty"`
+	Issuer            string    `json:"issuer,omitempty"`
+	Subject           string    `json:"subject,omitempty"`
+	CertificateIssuer string    `json:"certificateIssuer,omitempty"`
+	SerialNumber      string    `json:"serialNumber,omitempty"`
+	NotBefore         time.Time `json:"notBefore"`
+	NotAfter          time.Time `json:"notAfter"`
+}
+
+// simpleSigning is the format of simple signing payloads,
+// https://github.com/containers/image/blob/main/docs/containers-signature.5.md
+type simpleSigning struct {
+	Critical struct {
+		Identity struct {
+			DockerReference string `json:"docker-reference"`
+		} `json:"identity"`
+		Image struct {
+			DockerManifestDigest string `json:"docker-manifest-digest"`
+		} `json:"image"`
+		Type string `json:"type"`
+	} `json:"critical"`
+	Optional map[string]interface{} `json:"optional"`
+}
+
+// decodeSimpleSigningPayload decodes the simple signing payload of a signature
+func decodeSimpleSigningPayload(payload []byte) (*SimpleSigningPayload, error) {
+	s := simpleSigning{}
+	if err := json.Unmarshal(payload, &s); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal simple signing payload")
+	}
+	if s.Critical.Image.DockerManifestDigest == "" {
+		return nil, errors.New("simple signing payload has no image digest")
+	}
+
+	return &SimpleSigningPayload{
+		Type:                 s.Critical.Type,
+		DockerReference:      s.Critical.Identity.DockerReference,
+		DockerManifestDigest: s.Critical.Image.DockerManifestDigest,
+		Annotations:          s.Optional,
+	}, nil
+}
+
+// decodeSignatureCertificate decodes the signing certificate of a keyless signature, nil when the
+// signature was created with a key
+func decodeSignatureCertificate(cert *x509.Certificate) *SignatureCertificate {
+	if cert == nil {
+		return nil
+	}
+
+	decoded := &SignatureCertificate{
+		Identities:        append([]string{}, cert.EmailAddresses...),
+		Subject:           cert.Subject.String(),
+		CertificateIssuer: cert.Issuer.String(),
+		NotBefore:         cert.NotBefore.UTC(),
+		NotAfter:          cert.NotAfter.UTC(),
+	}
+	if cert.SerialNumber != nil {
+		decoded.SerialNumber = cert.SerialNumber.String()
+	}
+	for _, uri := range cert.URIs {
+		decoded.Identities = append(decoded.Identities, uri.String())
+	}
+
+	for _, extension := range cert.Extensions {
+		switch {
+		case extension.Id.Equal(fulcioIssuerV2OID):
+			var issuer string
+			if _, err := asn1.Unmarshal(extension.Value, &issuer); err == nil {
+				decoded.Issuer = issuer
+			}
+		case extension.Id.Equal(fulcioIssuerOID) && decoded.Issuer == "":
+			decoded.Issuer = string(extension.Value)
+		}
+	}
+
+	return decoded
+}
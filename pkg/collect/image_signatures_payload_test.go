@@ -0,0 +1,115 @@
+package collect
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"math/big"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_decodeSimpleSigningPayload(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload string
+		want    *SimpleSigningPayload
+		wantErr bool
+	}{
+		{
+			name: "with annotations",
+			payload: `{
+				"critical": {
+					"identity": {"docker-reference": "ghcr.io/example/app"},
+					"image": {"docker-manifest-digest": "sha256:3f57d9401f8d42f986df300f0c69192fc41da28ccc8d797829467780db3dd741"},
+					"type": "cosign container image signature"
+				},
+				"optional": {"commit": "4faf740", "release": "1.2.0"}
+			}`,
+			want: &SimpleSigningPayload{
+				Type:                 "cosign container image signature",
+				DockerReference:      "ghcr.io/example/app",
+				DockerManifestDigest: "sha256:3f57d9401f8d42f986df300f0c69192fc41da28ccc8d797829467780db3dd741",
+				Annotations:          map[string]interface{}{"commit": "4faf740", "release": "1.2.0"},
+			},
+		},
+		{
+			name:    "without annotations",
+			payload: `{"critical": {"identity": {"docker-reference": "nginx"}, "image": {"docker-manifest-digest": "sha256:abc"}, "type": "cosign container image signature"}, "optional": null}`,
+			want: &SimpleSigningPayload{
+				Type:                 "cosign container image signature",
+				DockerReference:      "nginx",
+				DockerManifestDigest: "sha256:abc",
+			},
+		},
+		{
+			name:    "not json",
+			payload: "signature-data",
+			wantErr: true,
+		},
+		{
+			name:    "no digest",
+			payload: `{"critical": {"identity": {"docker-reference": "nginx"}}}`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decodeSimpleSigningPayload([]byte(tt.payload))
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_decodeSignatureCertificate(t *testing.T) {
+	assert.Nil(t, decodeSignatureCertificate(nil))
+
+	issuer, err := asn1.Marshal("https://token.actions.githubusercontent.com")
+	require.NoError(t, err)
+	workflow, err := url.Parse("https://github.com/example/app/.github/workflows/release.yaml@refs/heads/main")
+	require.NoError(t, err)
+
+	notBefore := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	template := &x509.Certificate{
+		SerialNumber:   big.NewInt(42),
+		NotBefore:      notBefore,
+		NotAfter:       notBefore.Add(10 * time.Minute),
+		EmailAddresses: []string{"release@example.com"},
+		URIs:           []*url.URL{workflow},
+		ExtraExtensions: []pkix.Extension{
+			{Id: fulcioIssuerOID, Value: []byte("https://accounts.example.com")},
+			{Id: fulcioIssuerV2OID, Value: issuer},
+		},
+	}
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	parent := &x509.Certificate{Subject: pkix.Name{CommonName: "sigstore-intermediate", Organization: []string{"sigstore.dev"}}}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, key.Public(), key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	assert.Equal(t, &SignatureCertificate{
+		Identities: []string{
+			"release@example.com",
+			"https://github.com/example/app/.github/workflows/release.yaml@refs/heads/main",
+		},
+		Issuer:            "https://token.actions.githubusercontent.com",
+		CertificateIssuer: "CN=sigstore-intermediate,O=sigstore.dev",
+		SerialNumber:      "42",
+		NotBefore:         notBefore,
+		NotAfter:          notBefore.Add(10 * time.Minute),
+	}, decodeSignatureCertificate(cert))
+}
@@ -0,0 +1,133 @@
+package collect
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http"
+	"strings"
+
+	"github.com/containers/image/v5/types"
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/pkg/errors"
+)
+
+const (
+	// SignatureFormatCosign is a cosign signature, found by its tag or as a referrer of the image
+	SignatureFormatCosign = "cosign"
+	// SignatureFormatNotation is a Notation (Notary v2) signature
+	SignatureFormatNotation = "notation"
+	// SignatureFormatSigstoreBundle is a sigstore bundle, which cosign attaches as a referrer
+	SignatureFormatSigstoreBundle = "sigstore-bundle"
+)
+
+const (
+	notationSignatureArtifactType      = "application/vnd.cncf.notary.signature"
+	cosignSignatureArtifactType        = "application/vnd.dev.cosign.artifact.sig.v1+json"
+	sigstoreBundleArtifactTypePrefix   = "application/vnd.dev.sigstore.bundle"
+	notationSigningSchemeAnnotation    = "io.cncf.notary.signingScheme"
+	notationThumbprintsAnnotation      = "io.cncf.notary.x509chain.thumbprint#S256"
+	referrerSignatureCreatedAnnotation = "org.opencontainers.image.created"
+)
+
+// fetchReferrerSignatures lists the signatures attached to an image with the OCI 1.1 referrers API,
+// such as Notation signatures and cosign signatures stored as referrers. Registries that do not
+// support the API are asked for the referrers tag of the image instead.
+func fetchReferrerSignatures(ctx context.Context, ref name.Reference, sysCtx *types.SystemContext) ([]SignatureInfo, error) {
+	options := referrersOptions(ctx, sysCtx)
+
+	descriptor, err := remote.Head(ref, options...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to resolve image digest")
+	}
+
+	index, err := remote.Referrers(ref.Context().Digest(descriptor.Digest.String()), options...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list referrers")
+	}
+	indexManifest, err := index.IndexManifest()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read referrers")
+	}
+
+	return referrerSignatures(indexManifest.Manifests), nil
+}
+
+// referrerSignatures returns the referrers that are signatures. The signature of a referrer is the
+// digest of its manifest, the envelope of the signature is not downloaded.
+func referrerSignatures(referrers []v1.Descriptor) []SignatureInfo {
+	signatures := []SignatureInfo{}
+	for _, referrer := range referrers {
+		format := referrerSignatureFormat(referrer.ArtifactType)
+		if format == "" {
+			continue
+		}
+
+		signatures = append(signatures, SignatureInfo{
+			Signature:    referrer.Digest.String(),
+			Format:       format,
+			ArtifactType: referrer.ArtifactType,
+			Annotations:  referrerSignatureAnnotations(referrer.Annotations),
+		})
+	}
+	return signatures
+}
+
+func referrerSignatureFormat(artifactType string) string {
+	switch {
+	case artifactType == notationSignatureArtifactType:
+		return SignatureFormatNotation
+	case artifactType == cosignSignatureArtifactType:
+		return SignatureFormatCosign
+	case strings.HasPrefix(artifactType, sigstoreBundleArtifactTypePrefix):
+		return SignatureFormatSigstoreBundle
+	}
+	return ""
+}
+
+// referrerSignatureAnnotations keeps the annotations of a signature that identify how and by whom
+// it was signed
+func referrerSignatureAnnotations(annotations map[string]string) map[string]string {
+	kept := map[string]string{}
+	for _, key := range []string{notationSigningSchemeAnnotation, notationThumbprintsAnnotation, referrerSignatureCreatedAnnotation} {
+		if value, ok := annotations[key]; ok {
+			kept[key] = value
+		}
+	}
+	if len(kept) == 0 {
+		return nil
+	}
+	return kept
+}
+
+// referrersOptions returns the options to access the registry of an image with the credentials
+// and TLS settings of the system context of the collector
+func referrersOptions(ctx context.Context, sysCtx *types.SystemContext) []remote.Option {
+	options := []remote.Option{}
+	// collectors created without a context leave it nil, remote uses the background context then
+	if ctx != nil {
+		options = append(options, remote.WithContext(ctx))
+	}
+	if sysCtx == nil {
+		return options
+	}
+
+	if sysCtx.DockerAuthConfig != nil {
+		options = append(options, remote.WithAuth(&authn.Basic{
+			Username: sysCtx.DockerAuthConfig.Username,
+			Password: sysCtx.DockerAuthConfig.Password,
+		}))
+	} else {
+		options = append(options, remote.WithAuthFromKeychain(authn.DefaultKeychain))
+	}
+
+	if sysCtx.DockerInsecureSkipTLSVerify == types.OptionalBoolTrue {
+		transport := remote.DefaultTransport.(*http.Transport).Clone()
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
+		options = append(options, remote.WithTransport(transport))
+	}
+
+	return options
+}
@@ -0,0 +1,60 @@
+package collect
+
+import (
+	"testing"
+
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_referrerSignatures(t *testing.T) {
+	notation := v1.Hash{Algorithm: "sha256", Hex: "3f57d9401f8d42f986df300f0c69192fc41da28ccc8d797829467780db3dd741"}
+	bundle := v1.Hash{Algorithm: "sha256", Hex: "0000000000000000000000000000000000000000000000000000000000000000"}
+	sbom := v1.Hash{Algorithm: "sha256", Hex: "1111111111111111111111111111111111111111111111111111111111111111"}
+
+	referrers := []v1.Descriptor{
+		{
+			Digest:       notation,
+			ArtifactType: "application/vnd.cncf.notary.signature",
+			Annotations: map[string]string{
+				"io.cncf.notary.x509chain.thumbprint#S256": `["b6a5ee8a"]`,
+				"org.opencontainers.image.created":         "2026-10-16T12:00:00Z",
+				"io.cncf.notary.unrelated":                 "ignored",
+			},
+		},
+		{
+			Digest:       sbom,
+			ArtifactType: "application/spdx+json",
+		},
+		{
+			Digest:       bundle,
+			ArtifactType: "application/vnd.dev.sigstore.bundle.v0.3+json",
+		},
+	}
+
+	assert.Equal(t, []SignatureInfo{
+		{
+			Signature:    notation.String(),
+			Format:       SignatureFormatNotation,
+			ArtifactType: "application/vnd.cncf.notary.signature",
+			Annotations: map[string]string{
+				"io.cncf.notary.x509chain.thumbprint#S256": `["b6a5ee8a"]`,
+				"org.opencontainers.image.created":         "2026-10-16T12:00:00Z",
+			},
+		},
+		{
+			Signature:    bundle.String(),
+			Format:       SignatureFormatSigstoreBundle,
+			ArtifactType: "application/vnd.dev.sigstore.bundle.v0.3+json",
+		},
+	}, referrerSignatures(referrers))
+
+	assert.Equal(t, []SignatureInfo{}, referrerSignatures(nil))
+}
+
+func Test_referrerSignatureFormat(t *testing.T) {
+	assert.Equal(t, SignatureFormatNotation, referrerSignatureFormat("application/vnd.cncf.notary.signature"))
+	assert.Equal(t, SignatureFormatCosign, referrerSignatureFormat("application/vnd.dev.cosign.artifact.sig.v1+json"))
+	assert.Equal(t, SignatureFormatSigstoreBundle, referrerSignatureFormat("application/vnd.dev.sigstore.bundle+json;version=0.2"))
+	assert.Equal(t, "", referrerSignatureFormat("application/vnd.in-toto+json"))
+}
@@ -6,13 +6,14 @@ import (
 
 	"github.com/containers/image/v5/transports/alltransports"
 	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/registry"
 )
 
 func TestFetchImageSignatures(t *testing.T) {
 	tests := []struct {
 		name        string
 		imageName   string
-		authConfig  *registryAuthConfig
+		authConfig  *registry.AuthConfig
 		expectSigs  bool
 		expectError bool
 	}{
@@ -33,9 +34,9 @@ func TestFetchImageSignatures(t *testing.T) {
 		{
 			name:        "public image with auth config",
 			imageName:   "nginx:latest",
-			authConfig: &registryAuthConfig{
-				username: "testuser",
-				password: "testpass",
+			authConfig: &registry.AuthConfig{
+				Username: "testuser",
+				Password: "testpass",
 			},
 			expectSigs:  false,
 			expectError: false,
@@ -52,7 +53,7 @@ func TestFetchImageSignatures(t *testing.T) {
 				return
 			}
 
-			sysCtx := createSystemContextForSignatures(tt.authConfig)
+			sysCtx := registry.NewClient(registry.DefaultOptions(tt.authConfig)).SystemContext()
 			
 			signatures, err := fetchImageSignatures(context.Background(), imageRef, sysCtx)
 
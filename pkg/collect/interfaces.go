@@ -2,6 +2,7 @@ package collect
 
 import (
 	"github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/registry"
 	corev1 "k8s.io/api/core/v1"
 )
 
@@ -33,11 +34,4 @@ type PodSpecRunner interface {
 var _ PodSpecRunner = &v1beta2.RunPod{}
 
 // AuthConfigProvider is an interface for collectors that need registry authentication
-type AuthConfigProvider interface {
-	GetImagePullSecrets() *v1beta2.ImagePullSecrets
-	GetNamespace() string
-}
-
-// Ensure both registry and image signatures collectors implement this interface
-var _ AuthConfigProvider = &v1beta2.RegistryImages{}
-var _ AuthConfigProvider = &v1beta2.ImageSignatures{}
+type AuthConfigProvider = registry.AuthConfigProvider
@@ -0,0 +1,67 @@
+package collect
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"path"
+
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/klog/v2"
+)
+
+const (
+	configzUrlTemplate = "/api/v1/nodes/%s/proxy/configz"
+	KubeletConfigDir   = "kubelet-config"
+)
+
+// CollectKubeletConfig saves the running config of the kubelet of each node, as its /configz
+// endpoint reports it, to kubelet-config/<node>.json
+type CollectKubeletConfig struct {
+	Collector    *troubleshootv1beta2.KubeletConfig
+	BundlePath   string
+	ClientConfig *rest.Config
+	Client       kubernetes.Interface
+	Context      context.Context
+	RBACErrors
+}
+
+func (c *CollectKubeletConfig) Title() string {
+	return getCollectorName(c)
+}
+
+func (c *CollectKubeletConfig) IsExcluded() (bool, error) {
+	return isExcluded(c.Collector.Exclude)
+}
+
+func (c *CollectKubeletConfig) Collect(progressChan chan<- interface{}) (CollectorResult, error) {
+	output := NewResult()
+	nodeNames := selectNodeNames(c.Context, c.Client, c.Collector.NodeNames, c.Collector.Selector)
+	if len(nodeNames) == 0 {
+		klog.V(2).Info("no nodes found to collect the kubelet config of")
+		return output, nil
+	}
+
+	collectErrors := []string{}
+	for _, nodeName := range nodeNames {
+		// Equivalent to `kubectl get --raw "/api/v1/nodes/<nodeName>/proxy/configz"`
+		endpoint := fmt.Sprintf(configzUrlTemplate, nodeName)
+		response, err := c.Client.CoreV1().RESTClient().Get().AbsPath(endpoint).DoRaw(c.Context)
+		if err != nil {
+			klog.V(2).Infof("failed to query %s: %v", endpoint, err)
+			collectErrors = append(collectErrors, fmt.Sprintf("failed to get the kubelet config of node %s: %v", nodeName, err))
+			continue
+		}
+		if err := output.SaveResult(c.BundlePath, path.Join(KubeletConfigDir, fmt.Sprintf("%s.json", nodeName)), bytes.NewBuffer(response)); err != nil {
+			klog.Errorf("failed to save the kubelet config of %s: %v", nodeName, err)
+		}
+	}
+
+	if len(collectErrors) > 0 {
+		output.SaveResult(c.BundlePath, path.Join(KubeletConfigDir, "errors.json"), marshalErrors(collectErrors))
+	}
+
+	return output, nil
+}
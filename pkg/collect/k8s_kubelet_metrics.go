@@ -0,0 +1,67 @@
+package collect
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"path"
+
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/klog/v2"
+)
+
+const (
+	kubeletMetricsUrlTemplate = "/api/v1/nodes/%s/proxy/metrics"
+	KubeletMetricsDir         = "kubelet-metrics"
+)
+
+// CollectKubeletMetrics saves the Prometheus metrics of the kubelet of each node, as its /metrics
+// endpoint reports them, to kubelet-metrics/<node>.txt
+type CollectKubeletMetrics struct {
+	Collector    *troubleshootv1beta2.KubeletMetrics
+	BundlePath   string
+	ClientConfig *rest.Config
+	Client       kubernetes.Interface
+	Context      context.Context
+	RBACErrors
+}
+
+func (c *CollectKubeletMetrics) Title() string {
+	return getCollectorName(c)
+}
+
+func (c *CollectKubeletMetrics) IsExcluded() (bool, error) {
+	return isExcluded(c.Collector.Exclude)
+}
+
+func (c *CollectKubeletMetrics) Collect(progressChan chan<- interface{}) (CollectorResult, error) {
+	output := NewResult()
+	nodeNames := selectNodeNames(c.Context, c.Client, c.Collector.NodeNames, c.Collector.Selector)
+	if len(nodeNames) == 0 {
+		klog.V(2).Info("no nodes found to collect the kubelet metrics of")
+		return output, nil
+	}
+
+	collectErrors := []string{}
+	for _, nodeName := range nodeNames {
+		// Equivalent to `kubectl get --raw "/api/v1/nodes/<nodeName>/proxy/metrics"`
+		endpoint := fmt.Sprintf(kubeletMetricsUrlTemplate, nodeName)
+		response, err := c.Client.CoreV1().RESTClient().Get().AbsPath(endpoint).DoRaw(c.Context)
+		if err != nil {
+			klog.V(2).Infof("failed to query %s: %v", endpoint, err)
+			collectErrors = append(collectErrors, fmt.Sprintf("failed to get the kubelet metrics of node %s: %v", nodeName, err))
+			continue
+		}
+		if err := output.SaveResult(c.BundlePath, path.Join(KubeletMetricsDir, fmt.Sprintf("%s.txt", nodeName)), bytes.NewBuffer(response)); err != nil {
+			klog.Errorf("failed to save the kubelet metrics of %s: %v", nodeName, err)
+		}
+	}
+
+	if len(collectErrors) > 0 {
+		output.SaveResult(c.BundlePath, path.Join(KubeletMetricsDir, "errors.json"), marshalErrors(collectErrors))
+	}
+
+	return output, nil
+}
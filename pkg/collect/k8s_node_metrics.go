@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/pkg/errors"
@@ -68,35 +69,46 @@ func (c *CollectNodeMetrics) Collect(progressChan chan<- interface{}) (Collector
 
 func (c *CollectNodeMetrics) constructNodesMap() map[string]string {
 	nodesMap := map[string]string{}
+	for _, nodeName := range selectNodeNames(c.Context, c.Client, c.Collector.NodeNames, c.Collector.Selector) {
+		nodesMap[nodeName] = fmt.Sprintf(summaryUrlTemplate, nodeName)
+	}
+	return nodesMap
+}
 
-	if c.Collector.NodeNames == nil && c.Collector.Selector == nil {
+// selectNodeNames returns the names of the nodes of the list and of those matching the label
+// selector, or all the nodes when neither is set
+func selectNodeNames(ctx context.Context, client kubernetes.Interface, names []string, selector []string) []string {
+	if names == nil && selector == nil {
 		// If no node names or selectors are provided, collect all nodes
-		nodes, err := c.Client.CoreV1().Nodes().List(c.Context, metav1.ListOptions{})
+		nodes, err := client.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
 		if err != nil {
 			klog.Errorf("failed to list nodes: %v", err)
+			return nil
 		}
+		nodeNames := make([]string, 0, len(nodes.Items))
 		for _, node := range nodes.Items {
-			nodesMap[node.Name] = fmt.Sprintf(summaryUrlTemplate, node.Name)
+			nodeNames = append(nodeNames, node.Name)
 		}
-		return nodesMap
+		return nodeNames
 	}
 
-	for _, nodeName := range c.Collector.NodeNames {
-		nodesMap[nodeName] = fmt.Sprintf(summaryUrlTemplate, nodeName)
-	}
+	nodeNames := append([]string{}, names...)
 
 	// Find nodes by label selector
-	if c.Collector.Selector != nil {
-		nodes, err := c.Client.CoreV1().Nodes().List(c.Context, metav1.ListOptions{
-			LabelSelector: strings.Join(c.Collector.Selector, ","),
+	if selector != nil {
+		nodes, err := client.CoreV1().Nodes().List(ctx, metav1.ListOptions{
+			LabelSelector: strings.Join(selector, ","),
 		})
 		if err != nil {
 			klog.Errorf("failed to list nodes by label selector: %v", err)
+			return nodeNames
 		}
 		for _, node := range nodes.Items {
-			nodesMap[node.Name] = fmt.Sprintf(summaryUrlTemplate, node.Name)
+			if !slices.Contains(nodeNames, node.Name) {
+				nodeNames = append(nodeNames, node.Name)
+			}
 		}
 	}
 
-	return nodesMap
+	return nodeNames
 }
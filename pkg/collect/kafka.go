@@ -0,0 +1,285 @@
+package collect
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/segmentio/kafka-go"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/klog/v2"
+)
+
+const defaultKafkaTimeout = 10 * time.Second
+
+type CollectKafka struct {
+	Collector    *troubleshootv1beta2.Kafka
+	BundlePath   string
+	Namespace    string
+	ClientConfig *rest.Config
+	Client       kubernetes.Interface
+	Context      context.Context
+	RBACErrors
+}
+
+// KafkaCluster is the state of a Kafka cluster as reported by the brokers the collector connects to
+type KafkaCluster struct {
+	IsConnected  bool          `json:"isConnected"`
+	Error        string        `json:"error,omitempty"`
+	ClusterID    string        `json:"clusterId,omitempty"`
+	ControllerID int           `json:"controllerId"`
+	Brokers      []KafkaBroker `json:"brokers,omitempty"`
+	// Partitions is the number of partitions of all topics
+	Partitions                int                  `json:"partitions"`
+	UnderReplicatedPartitions []KafkaPartition     `json:"underReplicatedPartitions,omitempty"`
+	OfflinePartitions         []KafkaPartition     `json:"offlinePartitions,omitempty"`
+	ConsumerGroups            []KafkaConsumerGroup `json:"consumerGroups,omitempty"`
+}
+
+type KafkaBroker struct {
+	ID   int    `json:"id"`
+	Host string `json:"host"`
+	Port int    `json:"port"`
+	Rack string `json:"rack,omitempty"`
+}
+
+// KafkaPartition is a partition with fewer in-sync replicas than replicas, or without a leader
+type KafkaPartition struct {
+	Topic     string `json:"topic"`
+	Partition int    `json:"partition"`
+	// Leader is the ID of the broker that leads the partition, -1 when it is not a live broker
+	Leader         int `json:"leader"`
+	Replicas       int `json:"replicas"`
+	InSyncReplicas int `json:"inSyncReplicas"`
+}
+
+// KafkaConsumerGroup is the lag of a consumer group, the number of messages in the partitions it
+// has committed offsets for that it has not consumed yet
+type KafkaConsumerGroup struct {
+	GroupID string `json:"groupId"`
+	Lag     int64  `json:"lag"`
+	// Topics is the lag of the group by topic
+	Topics map[string]int64 `json:"topics,omitempty"`
+	Error  string           `json:"error,omitempty"`
+}
+
+func (c *CollectKafka) Title() string {
+	return getCollectorName(c)
+}
+
+func (c *CollectKafka) IsExcluded() (bool, error) {
+	return isExcluded(c.Collector.Exclude)
+}
+
+func (c *CollectKafka) createTransport() (*kafka.Transport, time.Duration, error) {
+	if len(c.Collector.Brokers) == 0 {
+		return nil, 0, errors.New("no brokers to connect to")
+	}
+
+	timeout := defaultKafkaTimeout
+	if c.Collector.Timeout != "" {
+		var err error
+		timeout, err = time.ParseDuration(c.Collector.Timeout)
+		if err != nil {
+			return nil, 0, errors.Wrapf(err, "failed to parse timeout %q", c.Collector.Timeout)
+		}
+	}
+
+	transport := &kafka.Transport{DialTimeout: timeout}
+	if c.Collector.TLS != nil {
+		klog.V(2).Infof("Connecting to kafka with TLS")
+		tlsCfg, err := createTLSConfig(c.Context, c.Client, c.Collector.TLS)
+		if err != nil {
+			return nil, 0, err
+		}
+		transport.TLS = tlsCfg
+	}
+
+	return transport, timeout, nil
+}
+
+func (c *CollectKafka) Collect(progressChan chan<- interface{}) (CollectorResult, error) {
+	cluster := KafkaCluster{}
+
+	transport, timeout, err := c.createTransport()
+	if err != nil {
+		cluster.Error = err.Error()
+	} else {
+		defer transport.CloseIdleConnections()
+
+		client := &kafka.Client{
+			Addr:      kafka.TCP(c.Collector.Brokers...),
+			Timeout:   timeout,
+			Transport: transport,
+		}
+		collectKafkaCluster(c.Context, client, c.Collector.ConsumerGroups, &cluster)
+	}
+
+	b, err := json.Marshal(cluster)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal kafka cluster")
+	}
+
+	collectorName := c.Collector.CollectorName
+	if collectorName == "" {
+		collectorName = "kafka"
+	}
+
+	output := NewResult()
+	output.SaveResult(c.BundlePath, fmt.Sprintf("kafka/%s.json", collectorName), bytes.NewBuffer(b))
+
+	return output, nil
+}
+
+// collectKafkaCluster reads the brokers, the health of the partitions and the lag of the consumer
+// groups, all groups when none are given, into the cluster
+func collectKafkaCluster(ctx context.Context, client *kafka.Client, groupIDs []string, cluster *KafkaCluster) {
+	metadata, err := client.Metadata(ctx, &kafka.MetadataRequest{})
+	if err != nil {
+		cluster.Error = err.Error()
+		return
+	}
+
+	cluster.IsConnected = true
+	cluster.ClusterID = metadata.ClusterID
+	cluster.ControllerID = metadata.Controller.ID
+	for _, broker := range metadata.Brokers {
+		cluster.Brokers = append(cluster.Brokers, KafkaBroker{
+			ID:   broker.ID,
+			Host: broker.Host,
+			Port: broker.Port,
+			Rack: broker.Rack,
+		})
+	}
+	sort.Slice(cluster.Brokers, func(i, j int) bool {
+		return cluster.Brokers[i].ID < cluster.Brokers[j].ID
+	})
+	cluster.Partitions, cluster.UnderReplicatedPartitions, cluster.OfflinePartitions = kafkaPartitionHealth(metadata.Topics)
+
+	if len(groupIDs) == 0 {
+		groups, err := client.ListGroups(ctx, &kafka.ListGroupsRequest{})
+		if err == nil {
+			err = groups.Error
+		}
+		if err != nil {
+			cluster.Error = errors.Wrap(err, "failed to list consumer groups").Error()
+			return
+		}
+		for _, group := range groups.Groups {
+			groupIDs = append(groupIDs, group.GroupID)
+		}
+	}
+	sort.Strings(groupIDs)
+
+	for _, groupID := range groupIDs {
+		cluster.ConsumerGroups = append(cluster.ConsumerGroups, kafkaConsumerGroupLag(ctx, client, groupID))
+	}
+}
+
+func kafkaConsumerGroupLag(ctx context.Context, client *kafka.Client, groupID string) KafkaConsumerGroup {
+	group := KafkaConsumerGroup{GroupID: groupID}
+
+	committed, err := client.OffsetFetch(ctx, &kafka.OffsetFetchRequest{GroupID: groupID})
+	if err == nil {
+		err = committed.Error
+	}
+	if err != nil {
+		group.Error = errors.Wrap(err, "failed to fetch committed offsets").Error()
+		return group
+	}
+
+	requests := map[string][]kafka.OffsetRequest{}
+	for topic, partitions := range committed.Topics {
+		for _, partition := range partitions {
+			requests[topic] = append(requests[topic], kafka.LastOffsetOf(partition.Partition))
+		}
+	}
+	if len(requests) == 0 {
+		return group
+	}
+
+	offsets, err := client.ListOffsets(ctx, &kafka.ListOffsetsRequest{Topics: requests})
+	if err != nil {
+		group.Error = errors.Wrap(err, "failed to list offsets").Error()
+		return group
+	}
+
+	group.Lag, group.Topics = kafkaLag(committed.Topics, offsets.Topics)
+	return group
+}
+
+// kafkaPartitionHealth returns the number of partitions of the topics, and those that are
+// under-replicated and offline. The leader of a partition is only known when it is a live broker,
+// otherwise it is reported without a host.
+func kafkaPartitionHealth(topics []kafka.Topic) (int, []KafkaPartition, []KafkaPartition) {
+	count := 0
+	underReplicated := []KafkaPartition{}
+	offline := []KafkaPartition{}
+
+	for _, topic := range topics {
+		for _, partition := range topic.Partitions {
+			count++
+
+			p := KafkaPartition{
+				Topic:          topic.Name,
+				Partition:      partition.ID,
+				Leader:         partition.Leader.ID,
+				Replicas:       len(partition.Replicas),
+				InSyncReplicas: len(partition.Isr),
+			}
+			if partition.Leader.Host == "" {
+				p.Leader = -1
+				offline = append(offline, p)
+			}
+			if p.InSyncReplicas < p.Replicas {
+				underReplicated = append(underReplicated, p)
+			}
+		}
+	}
+
+	for _, partitions := range [][]KafkaPartition{underReplicated, offline} {
+		sort.Slice(partitions, func(i, j int) bool {
+			if partitions[i].Topic != partitions[j].Topic {
+				return partitions[i].Topic < partitions[j].Topic
+			}
+			return partitions[i].Partition < partitions[j].Partition
+		})
+	}
+
+	return count, underReplicated, offline
+}
+
+// kafkaLag returns how far the committed offsets are behind the last offsets of the partitions,
+// in total and by topic. Partitions whose offsets could not be read are not counted.
+func kafkaLag(committed map[string][]kafka.OffsetFetchPartition, last map[string][]kafka.PartitionOffsets) (int64, map[string]int64) {
+	total := int64(0)
+	byTopic := map[string]int64{}
+
+	for topic, partitions := range committed {
+		lastOffsets := map[int]int64{}
+		for _, offsets := range last[topic] {
+			if offsets.Error == nil {
+				lastOffsets[offsets.Partition] = offsets.LastOffset
+			}
+		}
+
+		for _, partition := range partitions {
+			lastOffset, ok := lastOffsets[partition.Partition]
+			// a negative offset means the group has not committed an offset for the partition
+			if !ok || partition.Error != nil || partition.CommittedOffset < 0 {
+				continue
+			}
+			lag := max(lastOffset-partition.CommittedOffset, 0)
+			byTopic[topic] += lag
+			total += lag
+		}
+	}
+
+	return total, byTopic
+}
@@ -0,0 +1,71 @@
+package collect
+
+import (
+	"testing"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_kafkaPartitionHealth(t *testing.T) {
+	broker1 := kafka.Broker{ID: 1, Host: "kafka-0", Port: 9092}
+	broker2 := kafka.Broker{ID: 2, Host: "kafka-1", Port: 9092}
+	// brokers that are down are not in the metadata, their partitions refer to them without a host
+	broker3 := kafka.Broker{}
+
+	topics := []kafka.Topic{
+		{
+			Name: "orders",
+			Partitions: []kafka.Partition{
+				{Topic: "orders", ID: 1, Leader: broker2, Replicas: []kafka.Broker{broker1, broker2, broker3}, Isr: []kafka.Broker{broker2}},
+				{Topic: "orders", ID: 0, Leader: broker1, Replicas: []kafka.Broker{broker1, broker2}, Isr: []kafka.Broker{broker1, broker2}},
+			},
+		},
+		{
+			Name: "audit",
+			Partitions: []kafka.Partition{
+				{Topic: "audit", ID: 0, Leader: broker3, Replicas: []kafka.Broker{broker3}, Isr: []kafka.Broker{}},
+			},
+		},
+	}
+
+	count, underReplicated, offline := kafkaPartitionHealth(topics)
+
+	assert.Equal(t, 3, count)
+	assert.Equal(t, []KafkaPartition{
+		{Topic: "audit", Partition: 0, Leader: -1, Replicas: 1, InSyncReplicas: 0},
+		{Topic: "orders", Partition: 1, Leader: 2, Replicas: 3, InSyncReplicas: 1},
+	}, underReplicated)
+	assert.Equal(t, []KafkaPartition{
+		{Topic: "audit", Partition: 0, Leader: -1, Replicas: 1, InSyncReplicas: 0},
+	}, offline)
+}
+
+func Test_kafkaLag(t *testing.T) {
+	committed := map[string][]kafka.OffsetFetchPartition{
+		"orders": {
+			{Partition: 0, CommittedOffset: 90},
+			{Partition: 1, CommittedOffset: 200},
+			// no offset committed
+			{Partition: 2, CommittedOffset: -1},
+		},
+		"audit": {
+			{Partition: 0, CommittedOffset: 5},
+		},
+	}
+	last := map[string][]kafka.PartitionOffsets{
+		"orders": {
+			{Partition: 0, LastOffset: 100},
+			{Partition: 1, LastOffset: 250},
+			{Partition: 2, LastOffset: 10},
+		},
+		"audit": {
+			{Partition: 0, LastOffset: 5},
+		},
+	}
+
+	total, byTopic := kafkaLag(committed, last)
+
+	assert.Equal(t, int64(60), total)
+	assert.Equal(t, map[string]int64{"orders": 60, "audit": 0}, byTopic)
+}
@@ -0,0 +1,187 @@
+package collect
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/url"
+	"time"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+const (
+	defaultLDAPTimeout = 10 * time.Second
+	defaultLDAPFilter  = "(objectClass=*)"
+	// entries are only counted, so searches stop at ldapSearchSizeLimit entries and request the
+	// ldapNoAttributes attribute, which stands for none
+	ldapSearchSizeLimit = 100
+	ldapNoAttributes    = "1.1"
+)
+
+type CollectLDAP struct {
+	Collector    *troubleshootv1beta2.LDAP
+	BundlePath   string
+	Namespace    string
+	ClientConfig *rest.Config
+	Client       kubernetes.Interface
+	Context      context.Context
+	RBACErrors
+}
+
+// LDAPConnection is the outcome of connecting to, binding to and searching an LDAP server. The
+// bind is not attempted when the certificate of the server is not trusted.
+type LDAPConnection struct {
+	IsConnected bool   `json:"isConnected"`
+	Error       string `json:"error,omitempty"`
+	// LatencyMilliseconds is the time it took to connect and bind
+	LatencyMilliseconds int64 `json:"latencyMilliseconds,omitempty"`
+	// TLS is the handshake with the server, nil when the connection is not encrypted
+	TLS *HTTPTLS `json:"tls,omitempty"`
+	// CertificateError is why the certificate of the server is not trusted, empty when it is
+	CertificateError string `json:"certificateError,omitempty"`
+	IsBound          bool   `json:"isBound"`
+	IsSearched       bool   `json:"isSearched"`
+	// Entries is the number of entries the search found, up to 100
+	Entries int `json:"entries"`
+}
+
+func (c *CollectLDAP) Title() string {
+	return getCollectorName(c)
+}
+
+func (c *CollectLDAP) IsExcluded() (bool, error) {
+	return isExcluded(c.Collector.Exclude)
+}
+
+func (c *CollectLDAP) Collect(progressChan chan<- interface{}) (CollectorResult, error) {
+	connection := LDAPConnection{}
+
+	if err := c.collectConnection(&connection); err != nil {
+		connection.Error = err.Error()
+	}
+
+	b, err := json.Marshal(connection)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal ldap connection")
+	}
+
+	collectorName := c.Collector.CollectorName
+	if collectorName == "" {
+		collectorName = "ldap"
+	}
+
+	output := NewResult()
+	output.SaveResult(c.BundlePath, fmt.Sprintf("ldap/%s.json", collectorName), bytes.NewBuffer(b))
+
+	return output, nil
+}
+
+// collectConnection reads the password and the TLS configuration of the collector before checking
+// the server
+func (c *CollectLDAP) collectConnection(connection *LDAPConnection) error {
+	timeout := defaultLDAPTimeout
+	if c.Collector.Timeout != "" {
+		var err error
+		timeout, err = time.ParseDuration(c.Collector.Timeout)
+		if err != nil {
+			return errors.Wrapf(err, "failed to parse timeout %q", c.Collector.Timeout)
+		}
+	}
+
+	password := ""
+	if c.Collector.BindPasswordSecret != nil {
+		secret := c.Collector.BindPasswordSecret
+		var err error
+		password, err = readSecretKey(c.Context, c.Client, secret.Namespace, secret.Name, secret.Key)
+		if err != nil {
+			return errors.Wrap(err, "failed to read bind password")
+		}
+	}
+
+	var tlsConfig *tls.Config
+	if c.Collector.TLS != nil {
+		var err error
+		tlsConfig, err = createTLSConfig(c.Context, c.Client, c.Collector.TLS)
+		if err != nil {
+			return errors.Wrap(err, "failed to create tls config")
+		}
+	}
+
+	collectLDAPConnection(c.Collector, password, tlsConfig, timeout, connection)
+	return nil
+}
+
+// collectLDAPConnection connects to the server of the collector, upgrading the connection with
+// StartTLS when the collector sets it, binds with the password and searches the server
+func collectLDAPConnection(collector *troubleshootv1beta2.LDAP, password string, tlsConfig *tls.Config, timeout time.Duration, connection *LDAPConnection) {
+	u, err := url.Parse(collector.URL)
+	if err != nil {
+		connection.Error = errors.Wrap(err, "failed to parse url").Error()
+		return
+	}
+	verifier, tlsConfig := newTLSVerifier(tlsConfig, u.Hostname())
+
+	start := time.Now()
+	conn, err := ldap.DialURL(collector.URL, ldap.DialWithDialer(&net.Dialer{Timeout: timeout}), ldap.DialWithTLSConfig(tlsConfig))
+	if err != nil {
+		connection.Error = errors.Wrap(err, "failed to connect").Error()
+		return
+	}
+	defer conn.Close()
+	conn.SetTimeout(timeout)
+	connection.IsConnected = true
+
+	if collector.StartTLS {
+		if err := conn.StartTLS(tlsConfig); err != nil {
+			connection.Error = errors.Wrap(err, "failed to start tls").Error()
+			return
+		}
+	}
+	if _, ok := conn.TLSConnectionState(); ok {
+		connection.TLS = httpTLS(verifier.state)
+		if connection.CertificateError = verifier.certificateError(); connection.CertificateError != "" {
+			return
+		}
+	}
+
+	if collector.BindDN == "" {
+		err = conn.UnauthenticatedBind("")
+	} else {
+		err = conn.Bind(collector.BindDN, password)
+	}
+	connection.LatencyMilliseconds = time.Since(start).Milliseconds()
+	if err != nil {
+		connection.Error = errors.Wrap(err, "failed to bind").Error()
+		return
+	}
+	connection.IsBound = true
+
+	// without a base DN, the search reads the root DSE, which any server has
+	scope := ldap.ScopeWholeSubtree
+	if collector.BaseDN == "" {
+		scope = ldap.ScopeBaseObject
+	}
+	filter := collector.Filter
+	if filter == "" {
+		filter = defaultLDAPFilter
+	}
+	request := ldap.NewSearchRequest(
+		collector.BaseDN, scope, ldap.NeverDerefAliases, ldapSearchSizeLimit, int(timeout.Seconds()), false,
+		filter, []string{ldapNoAttributes}, nil,
+	)
+	result, err := conn.Search(request)
+	if err != nil && !ldap.IsErrorWithCode(err, ldap.LDAPResultSizeLimitExceeded) {
+		connection.Error = errors.Wrap(err, "failed to search").Error()
+		return
+	}
+	connection.IsSearched = true
+	connection.Entries = len(result.Entries)
+}
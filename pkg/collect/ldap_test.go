@@ -0,0 +1,80 @@
+package collect
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_collectLDAPConnection(t *testing.T) {
+	// a TLS server that does not speak LDAP is enough to check the handshake
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	ldapsURL := strings.Replace(server.URL, "https://", "ldaps://", 1)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(server.Certificate())
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	closedURL := "ldap://" + listener.Addr().String()
+	listener.Close()
+
+	tests := []struct {
+		name      string
+		url       string
+		tlsConfig *tls.Config
+		assert    func(t *testing.T, connection LDAPConnection)
+	}{
+		{
+			name: "not listening",
+			url:  closedURL,
+			assert: func(t *testing.T, connection LDAPConnection) {
+				assert.False(t, connection.IsConnected)
+				assert.Contains(t, connection.Error, "failed to connect")
+			},
+		},
+		{
+			name: "certificate not trusted",
+			url:  ldapsURL,
+			assert: func(t *testing.T, connection LDAPConnection) {
+				assert.True(t, connection.IsConnected)
+				assert.Contains(t, connection.CertificateError, "certificate signed by unknown authority")
+				require.NotNil(t, connection.TLS)
+				require.Len(t, connection.TLS.PeerCertificates, 1)
+				assert.Equal(t, "O=Acme Co", connection.TLS.PeerCertificates[0].Subject)
+				assert.False(t, connection.IsBound)
+				assert.Empty(t, connection.Error)
+			},
+		},
+		{
+			name:      "certificate trusted",
+			url:       ldapsURL,
+			tlsConfig: &tls.Config{RootCAs: roots},
+			assert: func(t *testing.T, connection LDAPConnection) {
+				assert.True(t, connection.IsConnected)
+				assert.Empty(t, connection.CertificateError)
+				require.NotNil(t, connection.TLS)
+				assert.False(t, connection.IsBound)
+				assert.Contains(t, connection.Error, "failed to bind")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			connection := LDAPConnection{}
+			collectLDAPConnection(&troubleshootv1beta2.LDAP{URL: tt.url}, "", tt.tlsConfig, time.Second, &connection)
+			tt.assert(t, connection)
+		})
+	}
+}
@@ -64,6 +64,11 @@ func (c *CollectLogs) CollectWithClient(progressChan chan<- interface{}, client
 		c.Collector.Limits.SinceTime = metav1.NewTime(*c.SinceTime)
 	}
 
+	filter, err := newLogLineFilter(c.Collector.Filter, c.Collector.Timestamps)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid log filter")
+	}
+
 	pods, podsErrors := listPodsInSelectors(ctx, client, c.Collector.Namespace, c.Collector.Selector)
 	if len(podsErrors) > 0 {
 		output.SaveResult(c.BundlePath, getLogsErrorsFileName(c.Collector), marshalErrors(podsErrors))
@@ -81,7 +86,7 @@ func (c *CollectLogs) CollectWithClient(progressChan chan<- interface{}, client
 			}
 
 			for _, containerName := range containerNames {
-				podLogs, err := savePodLogs(ctx, c.BundlePath, client, &pod, c.Collector.Name, containerName, c.Collector.Limits, false, true, c.Collector.Timestamps)
+				podLogs, err := savePodLogs(ctx, c.BundlePath, client, &pod, c.Collector.Name, containerName, c.Collector.Limits, false, true, c.Collector.Timestamps, filter)
 				if err != nil {
 					if errors.Is(err, context.DeadlineExceeded) {
 						klog.Errorf("Pod logs timed out for pod %s and container %s: %v", pod.Name, containerName, err)
@@ -100,7 +105,7 @@ func (c *CollectLogs) CollectWithClient(progressChan chan<- interface{}, client
 			}
 		} else {
 			for _, containerName := range c.Collector.ContainerNames {
-				containerLogs, err := savePodLogs(ctx, c.BundlePath, client, &pod, c.Collector.Name, containerName, c.Collector.Limits, false, true, c.Collector.Timestamps)
+				containerLogs, err := savePodLogs(ctx, c.BundlePath, client, &pod, c.Collector.Name, containerName, c.Collector.Limits, false, true, c.Collector.Timestamps, filter)
 				if err != nil {
 					if errors.Is(err, context.DeadlineExceeded) {
 						klog.Errorf("Pod logs timed out for pod %s and container %s: %v", pod.Name, containerName, err)
@@ -145,6 +150,7 @@ func savePodLogs(
 	follow bool,
 	createSymLinks bool,
 	timestamps bool,
+	filter *logLineFilter,
 ) (CollectorResult, error) {
 	podLogOpts := corev1.PodLogOptions{
 		Follow:     follow,
@@ -174,6 +180,9 @@ func savePodLogs(
 	}
 
 	setLogLimits(&podLogOpts, limits, convertMaxAgeToTime)
+	if filter != nil && filter.needsTimestamps() {
+		podLogOpts.Timestamps = true
+	}
 
 	req := client.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, &podLogOpts)
 	podLogs, err := req.Stream(ctx)
@@ -192,7 +201,7 @@ func savePodLogs(
 	}
 	defer result.CloseWriter(bundlePath, filePathPrefix+".log", logWriter)
 
-	_, err = io.Copy(logWriter, podLogs)
+	err = copyLogs(logWriter, podLogs, filter)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to copy log")
 	}
@@ -216,7 +225,7 @@ func savePodLogs(
 	}
 	defer result.CloseWriter(bundlePath, filePathPrefix+"-previous.log", logWriter)
 
-	_, err = io.Copy(prevLogWriter, podLogs)
+	err = copyLogs(prevLogWriter, podLogs, filter)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to copy previous log")
 	}
@@ -224,6 +233,14 @@ func savePodLogs(
 	return result, nil
 }
 
+func copyLogs(w io.Writer, r io.Reader, filter *logLineFilter) error {
+	if filter == nil {
+		_, err := io.Copy(w, r)
+		return err
+	}
+	return filter.copy(w, r)
+}
+
 func convertMaxAgeToTime(maxAge string) *metav1.Time {
 	parsedDuration, err := time.ParseDuration(maxAge)
 	if err != nil {
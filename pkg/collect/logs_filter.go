@@ -0,0 +1,154 @@
+package collect
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"regexp"
+	"time"
+
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+)
+
+// logLineFilter applies a LogFilter to the lines of a log as it is copied
+type logLineFilter struct {
+	since   time.Time
+	until   time.Time
+	include []*regexp.Regexp
+	exclude []*regexp.Regexp
+
+	sampleAfterBytes int64
+	keepEvery        int
+
+	// timestamps is whether the lines start with a timestamp, and stripTimestamps removes the
+	// timestamps requested to filter by time from the saved lines
+	timestamps      bool
+	stripTimestamps bool
+}
+
+// newLogLineFilter returns nil when there is no filter. timestamps is whether the logs are saved
+// with their timestamps.
+func newLogLineFilter(filter *troubleshootv1beta2.LogFilter, timestamps bool) (*logLineFilter, error) {
+	if filter == nil {
+		return nil, nil
+	}
+
+	f := &logLineFilter{
+		since: filter.SinceTime.Time,
+		until: filter.UntilTime.Time,
+	}
+	if !f.since.IsZero() && !f.until.IsZero() && f.until.Before(f.since) {
+		return nil, errors.New("untilTime must be after sinceTime")
+	}
+	f.timestamps = timestamps || f.needsTimestamps()
+	f.stripTimestamps = f.needsTimestamps() && !timestamps
+
+	for _, pattern := range filter.Include {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to compile include pattern %q", pattern)
+		}
+		f.include = append(f.include, re)
+	}
+	for _, pattern := range filter.Exclude {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to compile exclude pattern %q", pattern)
+		}
+		f.exclude = append(f.exclude, re)
+	}
+
+	if filter.Sample != nil {
+		if filter.Sample.KeepEvery < 1 {
+			return nil, errors.Errorf("keepEvery must be at least 1, got %d", filter.Sample.KeepEvery)
+		}
+		f.sampleAfterBytes = filter.Sample.AfterBytes
+		f.keepEvery = filter.Sample.KeepEvery
+	}
+
+	return f, nil
+}
+
+// needsTimestamps is whether the logs must be requested with timestamps to be filtered
+func (f *logLineFilter) needsTimestamps() bool {
+	return !f.since.IsZero() || !f.until.IsZero()
+}
+
+// copy writes the lines of r that pass the filter to w. Once sampling starts, a line notes it
+// in the log.
+func (f *logLineFilter) copy(w io.Writer, r io.Reader) error {
+	reader := bufio.NewReader(r)
+	var written int64
+	sampled := 0
+	inRange := true
+
+	for {
+		line, readErr := reader.ReadBytes('\n')
+		if len(line) > 0 {
+			line, keep := f.filterLine(line, &inRange)
+			if keep && f.keepEvery > 1 && written >= f.sampleAfterBytes {
+				if sampled == 0 {
+					marker := fmt.Sprintf("[troubleshoot: keeping 1 line in %d after %d bytes]\n", f.keepEvery, written)
+					if _, err := io.WriteString(w, marker); err != nil {
+						return errors.Wrap(err, "failed to write log")
+					}
+				}
+				keep = sampled%f.keepEvery == 0
+				sampled++
+			}
+			if keep {
+				n, err := w.Write(line)
+				if err != nil {
+					return errors.Wrap(err, "failed to write log")
+				}
+				written += int64(n)
+			}
+		}
+
+		if readErr == io.EOF {
+			return nil
+		} else if readErr != nil {
+			return errors.Wrap(readErr, "failed to read log")
+		}
+	}
+}
+
+// filterLine returns the line to save, without the timestamp when it is stripped, and whether
+// it passes the time range and the patterns. Patterns are matched against the message of the
+// line, after the timestamp. Lines without a timestamp are in the time range of the last line
+// with one, which inRange tracks.
+func (f *logLineFilter) filterLine(line []byte, inRange *bool) ([]byte, bool) {
+	message := line
+	if f.timestamps {
+		if i := bytes.IndexByte(line, ' '); i > 0 {
+			if ts, err := time.Parse(time.RFC3339Nano, string(line[:i])); err == nil {
+				*inRange = (f.since.IsZero() || !ts.Before(f.since)) && (f.until.IsZero() || !ts.After(f.until))
+				message = line[i+1:]
+				if f.stripTimestamps {
+					line = message
+				}
+			}
+		}
+	}
+	if !*inRange {
+		return nil, false
+	}
+	message = bytes.TrimRight(message, "\r\n")
+
+	for _, re := range f.exclude {
+		if re.Match(message) {
+			return nil, false
+		}
+	}
+	if len(f.include) == 0 {
+		return line, true
+	}
+	for _, re := range f.include {
+		if re.Match(message) {
+			return line, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,135 @@
+package collect
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func mustParseTime(t *testing.T, value string) metav1.Time {
+	t.Helper()
+	parsed, err := time.Parse(time.RFC3339, value)
+	require.NoError(t, err)
+	return metav1.NewTime(parsed)
+}
+
+func Test_logLineFilter_copy(t *testing.T) {
+	timestamped := `2024-01-01T10:00:00.000000000Z level=debug msg="polling"
+2024-01-01T11:00:00.000000000Z level=info msg="started"
+  continuation of the previous message
+2024-01-01T12:00:00.000000000Z level=error msg="failed"
+2024-01-01T13:00:00.000000000Z level=info msg="stopped"
+`
+
+	tests := []struct {
+		name       string
+		filter     troubleshootv1beta2.LogFilter
+		timestamps bool
+		input      string
+		want       string
+	}{
+		{
+			name: "time range without timestamps",
+			filter: troubleshootv1beta2.LogFilter{
+				SinceTime: mustParseTime(t, "2024-01-01T10:30:00Z"),
+				UntilTime: mustParseTime(t, "2024-01-01T12:00:00Z"),
+			},
+			input: timestamped,
+			want: `level=info msg="started"
+  continuation of the previous message
+level=error msg="failed"
+`,
+		},
+		{
+			name: "time range with timestamps",
+			filter: troubleshootv1beta2.LogFilter{
+				SinceTime: mustParseTime(t, "2024-01-01T12:30:00Z"),
+			},
+			timestamps: true,
+			input:      timestamped,
+			want: `2024-01-01T13:00:00.000000000Z level=info msg="stopped"
+`,
+		},
+		{
+			name: "include and exclude",
+			filter: troubleshootv1beta2.LogFilter{
+				Include: []string{`^level=(info|error)`},
+				Exclude: []string{`stopped`},
+			},
+			timestamps: true,
+			input:      timestamped,
+			want: `2024-01-01T11:00:00.000000000Z level=info msg="started"
+2024-01-01T12:00:00.000000000Z level=error msg="failed"
+`,
+		},
+		{
+			name:   "exclude without timestamps",
+			filter: troubleshootv1beta2.LogFilter{Exclude: []string{`debug`}},
+			input:  "debug one\ninfo two\ndebug three\ninfo four",
+			want:   "info two\ninfo four",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := newLogLineFilter(&tt.filter, tt.timestamps)
+			require.NoError(t, err)
+
+			var out bytes.Buffer
+			require.NoError(t, filter.copy(&out, strings.NewReader(tt.input)))
+			assert.Equal(t, tt.want, out.String())
+		})
+	}
+}
+
+func Test_logLineFilter_sample(t *testing.T) {
+	lines := []string{}
+	for i := 0; i < 10; i++ {
+		lines = append(lines, fmt.Sprintf("line %d", i))
+	}
+
+	filter, err := newLogLineFilter(&troubleshootv1beta2.LogFilter{
+		Exclude: []string{"line 1$"},
+		Sample:  &troubleshootv1beta2.LogSample{AfterBytes: 14, KeepEvery: 3},
+	}, false)
+	require.NoError(t, err)
+
+	var out bytes.Buffer
+	require.NoError(t, filter.copy(&out, strings.NewReader(strings.Join(lines, "\n")+"\n")))
+	assert.Equal(t, `line 0
+line 2
+[troubleshoot: keeping 1 line in 3 after 14 bytes]
+line 3
+line 6
+line 9
+`, out.String())
+}
+
+func Test_newLogLineFilter(t *testing.T) {
+	filter, err := newLogLineFilter(nil, false)
+	require.NoError(t, err)
+	assert.Nil(t, filter)
+
+	_, err = newLogLineFilter(&troubleshootv1beta2.LogFilter{Include: []string{"("}}, false)
+	assert.Error(t, err)
+
+	_, err = newLogLineFilter(&troubleshootv1beta2.LogFilter{Sample: &troubleshootv1beta2.LogSample{AfterBytes: 100}}, false)
+	assert.Error(t, err)
+
+	_, err = newLogLineFilter(&troubleshootv1beta2.LogFilter{
+		SinceTime: mustParseTime(t, "2024-01-02T00:00:00Z"),
+		UntilTime: mustParseTime(t, "2024-01-01T00:00:00Z"),
+	}, false)
+	assert.Error(t, err)
+
+	filter, err = newLogLineFilter(&troubleshootv1beta2.LogFilter{SinceTime: mustParseTime(t, "2024-01-01T00:00:00Z")}, false)
+	require.NoError(t, err)
+	assert.True(t, filter.needsTimestamps())
+	assert.True(t, filter.stripTimestamps)
+}
@@ -49,7 +49,7 @@ func (c *CollectLonghorn) IsExcluded() (bool, error) {
 }
 
 func (c *CollectLonghorn) Collect(progressChan chan<- interface{}) (CollectorResult, error) {
-	ctx := context.TODO()
+	ctx := collectorContext(c.Context)
 
 	ns := DefaultLonghornNamespace
 	if c.Collector.Namespace != "" {
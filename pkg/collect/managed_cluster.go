@@ -0,0 +1,433 @@
+package collect
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	corev1 "k8s.io/api/core/v1"
+	kuberneteserrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/klog/v2"
+)
+
+const (
+	ManagedClusterProviderEKS = "eks"
+	ManagedClusterProviderGKE = "gke"
+	ManagedClusterProviderAKS = "aks"
+
+	// AKSNetworkPluginAzure and AKSNetworkPluginKubenet are the network plugins of AKS. Azure CNI
+	// Overlay is the azure plugin in overlay mode.
+	AKSNetworkPluginAzure   = "azure"
+	AKSNetworkPluginKubenet = "kubenet"
+	AKSNetworkPluginOverlay = "overlay"
+)
+
+// ManagedClusterInfo is the output of the managedCluster collector. Only the configuration of the
+// detected provider is set.
+type ManagedClusterInfo struct {
+	// Provider is eks, gke or aks, or empty when the cluster is not managed by one of them
+	Provider string   `json:"provider"`
+	EKS      *EKSInfo `json:"eks,omitempty"`
+	GKE      *GKEInfo `json:"gke,omitempty"`
+	AKS      *AKSInfo `json:"aks,omitempty"`
+	Errors   []string `json:"errors,omitempty"`
+}
+
+// ManagedDaemonSet is a DaemonSet the provider runs in the kube-system namespace
+type ManagedDaemonSet struct {
+	Name  string `json:"name"`
+	Image string `json:"image,omitempty"`
+	// Env are the environment variables of the first container that are set by value
+	Env         map[string]string `json:"env,omitempty"`
+	Desired     int32             `json:"desired"`
+	Ready       int32             `json:"ready"`
+	Unavailable int32             `json:"unavailable"`
+}
+
+type EKSInfo struct {
+	// CNI is the aws-node DaemonSet of the Amazon VPC CNI, nil when it is not installed
+	CNI *ManagedDaemonSet `json:"cni,omitempty"`
+	// OIDCIssuer is the issuer of the service account tokens, the IAM OIDC provider of the cluster
+	OIDCIssuer string `json:"oidcIssuer,omitempty"`
+	// PodIdentityWebhook is whether the webhook that injects the credentials of the IAM roles of
+	// service accounts in pods is registered
+	PodIdentityWebhook bool `json:"podIdentityWebhook"`
+	// PodIdentityAgent is the eks-pod-identity-agent DaemonSet of EKS Pod Identity, nil when it is
+	// not installed
+	PodIdentityAgent *ManagedDaemonSet `json:"podIdentityAgent,omitempty"`
+	// RoleServiceAccounts are the service accounts annotated with an IAM role, as namespace/name
+	RoleServiceAccounts []string `json:"roleServiceAccounts"`
+}
+
+type GKEInfo struct {
+	// Autopilot is whether the nodes are managed by GKE Autopilot
+	Autopilot bool     `json:"autopilot"`
+	NodePools []string `json:"nodePools"`
+	// WorkloadIdentityNodePools are the node pools that run the GKE metadata server
+	WorkloadIdentityNodePools []string `json:"workloadIdentityNodePools"`
+	// WorkloadIdentityServiceAccounts are the service accounts annotated with a Google service
+	// account, as namespace/name
+	WorkloadIdentityServiceAccounts []string `json:"workloadIdentityServiceAccounts"`
+	// AutopilotRejections are the workloads whose pods were rejected by the constraints of
+	// Autopilot, from their FailedCreate events
+	AutopilotRejections []ManagedClusterRejection `json:"autopilotRejections"`
+}
+
+// ManagedClusterRejection is a workload whose pods the provider rejected
+type ManagedClusterRejection struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Message   string `json:"message"`
+}
+
+type AKSInfo struct {
+	// NetworkPlugin is azure or kubenet
+	NetworkPlugin string `json:"networkPlugin"`
+	// NetworkPluginMode is overlay for Azure CNI Overlay
+	NetworkPluginMode string   `json:"networkPluginMode,omitempty"`
+	NetworkPolicy     string   `json:"networkPolicy,omitempty"`
+	NetworkDataplane  string   `json:"networkDataplane,omitempty"`
+	NodePools         []string `json:"nodePools"`
+	Nodes             int      `json:"nodes"`
+	// MaxPods is the lowest number of pods that are allocatable on a node
+	MaxPods int64 `json:"maxPods"`
+	// CNS is the azure-cns DaemonSet of the Azure Container Networking Service, nil when it is not
+	// installed
+	CNS *ManagedDaemonSet `json:"cns,omitempty"`
+}
+
+type CollectManagedCluster struct {
+	Collector    *troubleshootv1beta2.ManagedCluster
+	BundlePath   string
+	Namespace    string
+	ClientConfig *rest.Config
+	Client       kubernetes.Interface
+	Context      context.Context
+	RBACErrors
+}
+
+func (c *CollectManagedCluster) Title() string {
+	return getCollectorName(c)
+}
+
+func (c *CollectManagedCluster) IsExcluded() (bool, error) {
+	return isExcluded(c.Collector.Exclude)
+}
+
+func (c *CollectManagedCluster) Collect(progressChan chan<- interface{}) (CollectorResult, error) {
+	switch c.Collector.Provider {
+	case "", ManagedClusterProviderEKS, ManagedClusterProviderGKE, ManagedClusterProviderAKS:
+	default:
+		return nil, errors.Errorf("unsupported provider %q, must be one of %s, %s or %s", c.Collector.Provider, ManagedClusterProviderEKS, ManagedClusterProviderGKE, ManagedClusterProviderAKS)
+	}
+
+	info := collectManagedCluster(collectorContext(c.Context), c.Client, c.Client.Discovery().RESTClient(), c.Collector.Provider)
+
+	b, err := json.MarshalIndent(info, "", "  ")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal managed cluster")
+	}
+
+	output := NewResult()
+	output.SaveResult(c.BundlePath, ManagedClusterOutputPath(c.Collector.CollectorName), bytes.NewBuffer(b))
+
+	return output, nil
+}
+
+// ManagedClusterOutputPath returns the path of the file collected by the managedCluster collector
+// of a name
+func ManagedClusterOutputPath(collectorName string) string {
+	if collectorName == "" {
+		collectorName = "managed-cluster"
+	}
+	return filepath.Join("managed-cluster", fmt.Sprintf("%s.json", collectorName))
+}
+
+// collectManagedCluster detects the provider, unless it is set, and collects its configuration.
+// The issuer of service account tokens is requested with restClient when it is not nil.
+func collectManagedCluster(ctx context.Context, client kubernetes.Interface, restClient rest.Interface, provider string) *ManagedClusterInfo {
+	info := &ManagedClusterInfo{}
+	addError := func(err error) {
+		klog.V(2).Infof("managed cluster collector: %v", err)
+		info.Errors = append(info.Errors, err.Error())
+	}
+
+	nodes, err := client.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		addError(errors.Wrap(err, "failed to list nodes"))
+		nodes = &corev1.NodeList{}
+	}
+
+	if provider == "" {
+		gitVersion := ""
+		if version, err := client.Discovery().ServerVersion(); err != nil {
+			addError(errors.Wrap(err, "failed to get server version"))
+		} else {
+			gitVersion = version.GitVersion
+		}
+		provider = detectManagedClusterProvider(gitVersion, nodes.Items)
+	}
+	info.Provider = provider
+
+	switch provider {
+	case ManagedClusterProviderEKS:
+		info.EKS = collectEKS(ctx, client, restClient, addError)
+	case ManagedClusterProviderGKE:
+		info.GKE = collectGKE(ctx, client, nodes.Items, addError)
+	case ManagedClusterProviderAKS:
+		info.AKS = collectAKS(ctx, client, nodes.Items, addError)
+	}
+
+	return info
+}
+
+// detectManagedClusterProvider returns the provider from the version of the API server, e.g.
+// v1.30.4-eks-a737599 or v1.30.5-gke.1014001, or else the labels of the nodes
+func detectManagedClusterProvider(gitVersion string, nodes []corev1.Node) string {
+	switch {
+	case strings.Contains(gitVersion, "-eks-"):
+		return ManagedClusterProviderEKS
+	case strings.Contains(gitVersion, "-gke."):
+		return ManagedClusterProviderGKE
+	}
+
+	for _, node := range nodes {
+		if _, ok := node.Labels["kubernetes.azure.com/cluster"]; ok {
+			return ManagedClusterProviderAKS
+		}
+		if _, ok := node.Labels["eks.amazonaws.com/nodegroup"]; ok {
+			return ManagedClusterProviderEKS
+		}
+		if _, ok := node.Labels["cloud.google.com/gke-nodepool"]; ok {
+			return ManagedClusterProviderGKE
+		}
+	}
+	return ""
+}
+
+func collectEKS(ctx context.Context, client kubernetes.Interface, restClient rest.Interface, addError func(error)) *EKSInfo {
+	info := &EKSInfo{RoleServiceAccounts: []string{}}
+
+	var err error
+	if info.CNI, err = getManagedDaemonSet(ctx, client, "aws-node"); err != nil {
+		addError(err)
+	}
+	if info.PodIdentityAgent, err = getManagedDaemonSet(ctx, client, "eks-pod-identity-agent"); err != nil {
+		addError(err)
+	}
+
+	if restClient != nil {
+		if info.OIDCIssuer, err = getServiceAccountIssuer(ctx, restClient); err != nil {
+			addError(err)
+		}
+	}
+
+	_, err = client.AdmissionregistrationV1().MutatingWebhookConfigurations().Get(ctx, "pod-identity-webhook", metav1.GetOptions{})
+	if err == nil {
+		info.PodIdentityWebhook = true
+	} else if !kuberneteserrors.IsNotFound(err) {
+		addError(errors.Wrap(err, "failed to get mutating webhook configuration pod-identity-webhook"))
+	}
+
+	if info.RoleServiceAccounts, err = listAnnotatedServiceAccounts(ctx, client, "eks.amazonaws.com/role-arn"); err != nil {
+		addError(err)
+	}
+
+	return info
+}
+
+func collectGKE(ctx context.Context, client kubernetes.Interface, nodes []corev1.Node, addError func(error)) *GKEInfo {
+	info := &GKEInfo{
+		NodePools:                 []string{},
+		WorkloadIdentityNodePools: []string{},
+		AutopilotRejections:       []ManagedClusterRejection{},
+	}
+
+	for _, node := range nodes {
+		// the nodes of Autopilot are named gk3-<cluster>-<pool>-<id>
+		if strings.HasPrefix(node.Name, "gk3-") {
+			info.Autopilot = true
+		}
+		pool := node.Labels["cloud.google.com/gke-nodepool"]
+		if pool == "" {
+			continue
+		}
+		if !slices.Contains(info.NodePools, pool) {
+			info.NodePools = append(info.NodePools, pool)
+		}
+		if node.Labels["iam.gke.io/gke-metadata-server-enabled"] == "true" && !slices.Contains(info.WorkloadIdentityNodePools, pool) {
+			info.WorkloadIdentityNodePools = append(info.WorkloadIdentityNodePools, pool)
+		}
+	}
+	sort.Strings(info.NodePools)
+	sort.Strings(info.WorkloadIdentityNodePools)
+
+	var err error
+	if info.WorkloadIdentityServiceAccounts, err = listAnnotatedServiceAccounts(ctx, client, "iam.gke.io/gcp-service-account"); err != nil {
+		addError(err)
+	}
+
+	if info.Autopilot {
+		if info.AutopilotRejections, err = listAutopilotRejections(ctx, client); err != nil {
+			addError(err)
+		}
+	}
+
+	return info
+}
+
+func collectAKS(ctx context.Context, client kubernetes.Interface, nodes []corev1.Node, addError func(error)) *AKSInfo {
+	info := &AKSInfo{NodePools: []string{}, Nodes: len(nodes)}
+
+	podCIDRs := false
+	for _, node := range nodes {
+		labels := node.Labels
+		if pool := labels["kubernetes.azure.com/agentpool"]; pool != "" && !slices.Contains(info.NodePools, pool) {
+			info.NodePools = append(info.NodePools, pool)
+		}
+		if labels["kubernetes.azure.com/azure-cni-overlay"] == "true" || labels["kubernetes.azure.com/podnetwork-type"] == AKSNetworkPluginOverlay {
+			info.NetworkPluginMode = AKSNetworkPluginOverlay
+		}
+		if policy := labels["kubernetes.azure.com/network-policy"]; policy != "" {
+			info.NetworkPolicy = policy
+		}
+		if dataplane := labels["kubernetes.azure.com/ebpf-dataplane"]; dataplane != "" {
+			info.NetworkDataplane = dataplane
+		}
+		if node.Spec.PodCIDR != "" {
+			podCIDRs = true
+		}
+		if pods, ok := node.Status.Allocatable[corev1.ResourcePods]; ok && (info.MaxPods == 0 || pods.Value() < info.MaxPods) {
+			info.MaxPods = pods.Value()
+		}
+	}
+	sort.Strings(info.NodePools)
+
+	// the nodes of kubenet and Azure CNI Overlay are assigned the CIDRs of their pods, the pods of
+	// Azure CNI are assigned addresses of the subnet of the nodes
+	info.NetworkPlugin = AKSNetworkPluginAzure
+	if podCIDRs && info.NetworkPluginMode != AKSNetworkPluginOverlay {
+		info.NetworkPlugin = AKSNetworkPluginKubenet
+	}
+
+	var err error
+	if info.CNS, err = getManagedDaemonSet(ctx, client, "azure-cns"); err != nil {
+		addError(err)
+	}
+
+	return info
+}
+
+// getManagedDaemonSet returns the DaemonSet of a name in kube-system, or nil when it does not exist
+func getManagedDaemonSet(ctx context.Context, client kubernetes.Interface, name string) (*ManagedDaemonSet, error) {
+	daemonSet, err := client.AppsV1().DaemonSets("kube-system").Get(ctx, name, metav1.GetOptions{})
+	if kuberneteserrors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Wrapf(err, "failed to get daemonset %s", name)
+	}
+
+	managed := &ManagedDaemonSet{
+		Name:        name,
+		Desired:     daemonSet.Status.DesiredNumberScheduled,
+		Ready:       daemonSet.Status.NumberReady,
+		Unavailable: daemonSet.Status.NumberUnavailable,
+	}
+	if containers := daemonSet.Spec.Template.Spec.Containers; len(containers) > 0 {
+		managed.Image = containers[0].Image
+		for _, env := range containers[0].Env {
+			if env.ValueFrom != nil {
+				continue
+			}
+			if managed.Env == nil {
+				managed.Env = map[string]string{}
+			}
+			managed.Env[env.Name] = env.Value
+		}
+	}
+	return managed, nil
+}
+
+// getServiceAccountIssuer returns the issuer of the OpenID configuration of the service account
+// issuer discovery of the API server
+func getServiceAccountIssuer(ctx context.Context, restClient rest.Interface) (string, error) {
+	b, err := restClient.Get().AbsPath("/.well-known/openid-configuration").DoRaw(ctx)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get the openid configuration of the service account issuer")
+	}
+
+	configuration := struct {
+		Issuer string `json:"issuer"`
+	}{}
+	if err := json.Unmarshal(b, &configuration); err != nil {
+		return "", errors.Wrap(err, "failed to parse the openid configuration of the service account issuer")
+	}
+	return configuration.Issuer, nil
+}
+
+// listAnnotatedServiceAccounts returns the service accounts with an annotation, as namespace/name
+func listAnnotatedServiceAccounts(ctx context.Context, client kubernetes.Interface, annotation string) ([]string, error) {
+	serviceAccounts, err := client.CoreV1().ServiceAccounts("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return []string{}, errors.Wrap(err, "failed to list service accounts")
+	}
+
+	annotated := []string{}
+	for _, serviceAccount := range serviceAccounts.Items {
+		if serviceAccount.Annotations[annotation] != "" {
+			annotated = append(annotated, fmt.Sprintf("%s/%s", serviceAccount.Namespace, serviceAccount.Name))
+		}
+	}
+	sort.Strings(annotated)
+	return annotated, nil
+}
+
+// listAutopilotRejections returns the workloads whose pods were rejected by GKE Warden, the
+// admission webhook that enforces the constraints of Autopilot, once per workload
+func listAutopilotRejections(ctx context.Context, client kubernetes.Interface) ([]ManagedClusterRejection, error) {
+	events, err := client.CoreV1().Events("").List(ctx, metav1.ListOptions{FieldSelector: "reason=FailedCreate"})
+	if err != nil {
+		return []ManagedClusterRejection{}, errors.Wrap(err, "failed to list events")
+	}
+
+	rejections := []ManagedClusterRejection{}
+	seen := map[string]bool{}
+	for _, event := range events.Items {
+		if event.Reason != "FailedCreate" || !strings.Contains(event.Message, "GKE Warden") {
+			continue
+		}
+
+		object := event.InvolvedObject
+		key := fmt.Sprintf("%s/%s/%s", object.Kind, object.Namespace, object.Name)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		rejections = append(rejections, ManagedClusterRejection{
+			Kind:      object.Kind,
+			Namespace: object.Namespace,
+			Name:      object.Name,
+			Message:   event.Message,
+		})
+	}
+
+	sort.Slice(rejections, func(i, j int) bool {
+		if rejections[i].Namespace != rejections[j].Namespace {
+			return rejections[i].Namespace < rejections[j].Namespace
+		}
+		return rejections[i].Name < rejections[j].Name
+	})
+	return rejections, nil
+}
@@ -0,0 +1,138 @@
+package collect
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/version"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func Test_detectManagedClusterProvider(t *testing.T) {
+	aksNode := corev1.Node{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"kubernetes.azure.com/cluster": "MC_rg_cluster_eastus"}}}
+
+	assert.Equal(t, "eks", detectManagedClusterProvider("v1.30.4-eks-a737599", nil))
+	assert.Equal(t, "gke", detectManagedClusterProvider("v1.30.5-gke.1014001", nil))
+	assert.Equal(t, "aks", detectManagedClusterProvider("v1.30.3", []corev1.Node{aksNode}))
+	assert.Equal(t, "", detectManagedClusterProvider("v1.30.3+k3s1", []corev1.Node{{}}))
+}
+
+func Test_collectManagedCluster_eks(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		&appsv1.DaemonSet{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "kube-system", Name: "aws-node"},
+			Spec: appsv1.DaemonSetSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{Containers: []corev1.Container{{
+				Name:  "aws-node",
+				Image: "602401143452.dkr.ecr.us-west-2.amazonaws.com/amazon-k8s-cni:v1.18.3-eksbuild.1",
+				Env: []corev1.EnvVar{
+					{Name: "ENABLE_PREFIX_DELEGATION", Value: "true"},
+					{Name: "MY_NODE_NAME", ValueFrom: &corev1.EnvVarSource{FieldRef: &corev1.ObjectFieldSelector{FieldPath: "spec.nodeName"}}},
+				},
+			}}}}},
+			Status: appsv1.DaemonSetStatus{DesiredNumberScheduled: 3, NumberReady: 2, NumberUnavailable: 1},
+		},
+		&admissionregistrationv1.MutatingWebhookConfiguration{ObjectMeta: metav1.ObjectMeta{Name: "pod-identity-webhook"}},
+		&corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Namespace: "app", Name: "uploader", Annotations: map[string]string{"eks.amazonaws.com/role-arn": "arn:aws:iam::111122223333:role/uploader"}}},
+		&corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Namespace: "app", Name: "default"}},
+	)
+	client.Discovery().(*fakediscovery.FakeDiscovery).FakedServerVersion = &version.Info{GitVersion: "v1.30.4-eks-a737599"}
+
+	info := collectManagedCluster(context.Background(), client, nil, "")
+
+	assert.Empty(t, info.Errors)
+	assert.Equal(t, "eks", info.Provider)
+	assert.Equal(t, &EKSInfo{
+		CNI: &ManagedDaemonSet{
+			Name:        "aws-node",
+			Image:       "602401143452.dkr.ecr.us-west-2.amazonaws.com/amazon-k8s-cni:v1.18.3-eksbuild.1",
+			Env:         map[string]string{"ENABLE_PREFIX_DELEGATION": "true"},
+			Desired:     3,
+			Ready:       2,
+			Unavailable: 1,
+		},
+		PodIdentityWebhook:  true,
+		RoleServiceAccounts: []string{"app/uploader"},
+	}, info.EKS)
+}
+
+func Test_collectManagedCluster_gke(t *testing.T) {
+	node := func(name string, pool string, metadataServer bool) *corev1.Node {
+		labels := map[string]string{"cloud.google.com/gke-nodepool": pool}
+		if metadataServer {
+			labels["iam.gke.io/gke-metadata-server-enabled"] = "true"
+		}
+		return &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}}
+	}
+
+	client := fake.NewSimpleClientset(
+		node("gk3-prod-pool-2-0d1e2f3a-x7d2", "pool-2", true),
+		node("gk3-prod-nap-1a2b3c4d-j9k8", "nap-1a2b3c4d", true),
+		&corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Namespace: "app", Name: "uploader", Annotations: map[string]string{"iam.gke.io/gcp-service-account": "uploader@project.iam.gserviceaccount.com"}}},
+		&corev1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Namespace: "app", Name: "node-agent.17f3"},
+			InvolvedObject: corev1.ObjectReference{Kind: "DaemonSet", Namespace: "app", Name: "node-agent"},
+			Reason:         "FailedCreate",
+			Message:        `Error creating: admission webhook "warden-validating.common-webhooks.networking.gke.io" denied the request: GKE Warden rejected the request because it violates one or more constraints.`,
+		},
+		&corev1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Namespace: "app", Name: "api-7d9f8c.17f4"},
+			InvolvedObject: corev1.ObjectReference{Kind: "ReplicaSet", Namespace: "app", Name: "api-7d9f8c"},
+			Reason:         "FailedCreate",
+			Message:        `Error creating: pods "api-7d9f8c-x7d2q" is forbidden: exceeded quota: compute`,
+		},
+	)
+
+	info := collectManagedCluster(context.Background(), client, nil, "gke")
+
+	assert.Empty(t, info.Errors)
+	assert.Equal(t, "gke", info.Provider)
+	assert.True(t, info.GKE.Autopilot)
+	assert.Equal(t, []string{"nap-1a2b3c4d", "pool-2"}, info.GKE.NodePools)
+	assert.Equal(t, []string{"nap-1a2b3c4d", "pool-2"}, info.GKE.WorkloadIdentityNodePools)
+	assert.Equal(t, []string{"app/uploader"}, info.GKE.WorkloadIdentityServiceAccounts)
+	assert.Equal(t, []ManagedClusterRejection{{
+		Kind:      "DaemonSet",
+		Namespace: "app",
+		Name:      "node-agent",
+		Message:   `Error creating: admission webhook "warden-validating.common-webhooks.networking.gke.io" denied the request: GKE Warden rejected the request because it violates one or more constraints.`,
+	}}, info.GKE.AutopilotRejections)
+}
+
+func Test_collectManagedCluster_aks(t *testing.T) {
+	node := func(name string, pool string, maxPods int64) *corev1.Node {
+		return &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{
+				"kubernetes.azure.com/cluster":        "MC_rg_cluster_eastus",
+				"kubernetes.azure.com/agentpool":      pool,
+				"kubernetes.azure.com/network-policy": "calico",
+			}},
+			Spec:   corev1.NodeSpec{PodCIDR: "10.244.0.0/24"},
+			Status: corev1.NodeStatus{Allocatable: corev1.ResourceList{corev1.ResourcePods: *resource.NewQuantity(maxPods, resource.DecimalSI)}},
+		}
+	}
+
+	client := fake.NewSimpleClientset(
+		node("aks-system-12345678-vmss000000", "system", 110),
+		node("aks-user-12345678-vmss000000", "user", 30),
+	)
+	client.Discovery().(*fakediscovery.FakeDiscovery).FakedServerVersion = &version.Info{GitVersion: "v1.30.3"}
+
+	info := collectManagedCluster(context.Background(), client, nil, "")
+
+	assert.Empty(t, info.Errors)
+	assert.Equal(t, "aks", info.Provider)
+	assert.Equal(t, &AKSInfo{
+		NetworkPlugin: "kubenet",
+		NetworkPolicy: "calico",
+		NodePools:     []string{"system", "user"},
+		Nodes:         2,
+		MaxPods:       30,
+	}, info.AKS)
+}
@@ -0,0 +1,202 @@
+package collect
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/constants"
+)
+
+// IsMetadataOnly returns whether the spec collects with the metadataOnly profile
+func IsMetadataOnly(spec *troubleshootv1beta2.SupportBundleSpec) (bool, error) {
+	switch spec.Profile {
+	case "", troubleshootv1beta2.CollectionProfileFull:
+		return false, nil
+	case troubleshootv1beta2.CollectionProfileMetadataOnly:
+		return true, nil
+	default:
+		return false, errors.Errorf("unknown profile %q, must be %s or %s", spec.Profile, troubleshootv1beta2.CollectionProfileFull, troubleshootv1beta2.CollectionProfileMetadataOnly)
+	}
+}
+
+// StripPayloads replaces the payloads in the result of a collector with their metadata, for the
+// metadataOnly profile. Logs, the output of commands and copied files are replaced as a whole,
+// the values of configmaps and secrets are replaced in place so that their keys are kept. The
+// errors of the collector, and the specs and statuses of resources, are left as they are.
+func StripPayloads(c Collector, result CollectorResult, bundlePath string) error {
+	for filePath := range result {
+		strip := payloadStripper(c, filePath)
+		if strip == nil {
+			continue
+		}
+		if _, ok, err := resultFileSize(result, bundlePath, filePath); err != nil {
+			return errors.Wrapf(err, "failed to get size of %s", filePath)
+		} else if !ok {
+			// symlinks point to files of the result, which are stripped themselves
+			continue
+		}
+
+		reader, err := result.GetReader(bundlePath, filePath)
+		if err != nil {
+			return errors.Wrapf(err, "failed to read %s", filePath)
+		}
+		data, err := io.ReadAll(reader)
+		reader.Close()
+		if err != nil {
+			return errors.Wrapf(err, "failed to read %s", filePath)
+		}
+
+		stripped, err := strip(data)
+		if err != nil {
+			// a file that can't be parsed is replaced as a whole, rather than shipped with its payload
+			stripped, _ = stripFile(data)
+		}
+		if err := result.ReplaceResult(bundlePath, filePath, bytes.NewReader(stripped)); err != nil {
+			return errors.Wrapf(err, "failed to write stripped %s", filePath)
+		}
+	}
+	return nil
+}
+
+// payloadStripper returns the function that strips the payload of a file of the collector, or
+// nil when the file has no payload
+func payloadStripper(c Collector, filePath string) func([]byte) ([]byte, error) {
+	if isErrorsFile(filePath) {
+		return nil
+	}
+
+	switch c.(type) {
+	case *CollectLogs, *CollectExec, *CollectCopy, *CollectCopyFromHost, *CollectRunDaemonSet:
+		return stripFile
+	case *CollectRun, *CollectRunPod:
+		// the pod and its events are kept
+		if strings.HasSuffix(filePath, ".log") {
+			return stripFile
+		}
+	case *CollectConfigMap:
+		return stripConfigMapOutput
+	case *CollectSecret:
+		return stripSecretOutput
+	case *CollectClusterResources:
+		if strings.HasPrefix(filePath, path.Join(constants.CLUSTER_RESOURCES_DIR, constants.CLUSTER_RESOURCES_PODS_LOGS)+"/") {
+			return stripFile
+		}
+		if filepath.ToSlash(filepath.Dir(filePath)) == path.Join(constants.CLUSTER_RESOURCES_DIR, constants.CLUSTER_RESOURCES_CONFIGMAPS) {
+			return stripConfigMapList
+		}
+	}
+	return nil
+}
+
+// isErrorsFile returns whether the file holds the errors of a collector, e.g. logs-errors.json
+func isErrorsFile(filePath string) bool {
+	filePath = filepath.ToSlash(filePath)
+	if strings.HasPrefix(filePath, "configmaps-errors/") || strings.HasPrefix(filePath, "secrets-errors/") {
+		return true
+	}
+	base := path.Base(filePath)
+	return strings.HasSuffix(base, "errors.json") || strings.HasSuffix(base, "-errors.log") || base == "error.txt"
+}
+
+// payloadMetadata describes a payload that was removed
+func payloadMetadata(data []byte) string {
+	lines := bytes.Count(data, []byte("\n"))
+	if len(data) > 0 && data[len(data)-1] != '\n' {
+		lines++
+	}
+	sum := sha256.Sum256(data)
+	return fmt.Sprintf("[troubleshoot: removed by the metadataOnly profile, %d bytes, %d lines, sha256 %s]", len(data), lines, hex.EncodeToString(sum[:]))
+}
+
+func stripFile(data []byte) ([]byte, error) {
+	return []byte(payloadMetadata(data) + "\n"), nil
+}
+
+func stripConfigMapOutput(data []byte) ([]byte, error) {
+	output := ConfigMapOutput{}
+	if err := json.Unmarshal(data, &output); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal configmap")
+	}
+	if output.Value != "" {
+		output.Value = payloadMetadata([]byte(output.Value))
+	}
+	for key, value := range output.Data {
+		output.Data[key] = payloadMetadata([]byte(value))
+	}
+	return json.MarshalIndent(output, "", "  ")
+}
+
+func stripSecretOutput(data []byte) ([]byte, error) {
+	output := SecretOutput{}
+	if err := json.Unmarshal(data, &output); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal secret")
+	}
+	if output.Value != "" {
+		output.Value = payloadMetadata([]byte(output.Value))
+	}
+	return json.MarshalIndent(output, "", "  ")
+}
+
+// stripConfigMapList replaces the data and binary data of the configmaps of a list collected by
+// the cluster resources collector. The description of binary data is itself base64 encoded, so
+// that the list still parses as configmaps.
+func stripConfigMapList(data []byte) ([]byte, error) {
+	list := map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal configmap list")
+	}
+	raw, ok := list["items"]
+	if !ok {
+		return data, nil
+	}
+	items := []map[string]json.RawMessage{}
+	if err := json.Unmarshal(raw, &items); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal configmaps")
+	}
+
+	for _, item := range items {
+		for _, field := range []string{"data", "binaryData"} {
+			raw, ok := item[field]
+			if !ok {
+				continue
+			}
+			values := map[string]string{}
+			if err := json.Unmarshal(raw, &values); err != nil {
+				return nil, errors.Wrapf(err, "failed to unmarshal configmap %s", field)
+			}
+			for key, value := range values {
+				if field == "data" {
+					values[key] = payloadMetadata([]byte(value))
+					continue
+				}
+				payload, err := base64.StdEncoding.DecodeString(value)
+				if err != nil {
+					payload = []byte(value)
+				}
+				values[key] = base64.StdEncoding.EncodeToString([]byte(payloadMetadata(payload)))
+			}
+			b, err := json.Marshal(values)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to marshal configmap %s", field)
+			}
+			item[field] = b
+		}
+	}
+
+	b, err := json.Marshal(items)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal configmaps")
+	}
+	list["items"] = b
+	return json.MarshalIndent(list, "", "  ")
+}
@@ -0,0 +1,144 @@
+package collect
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsMetadataOnly(t *testing.T) {
+	metadataOnly, err := IsMetadataOnly(&troubleshootv1beta2.SupportBundleSpec{})
+	require.NoError(t, err)
+	assert.False(t, metadataOnly)
+
+	metadataOnly, err = IsMetadataOnly(&troubleshootv1beta2.SupportBundleSpec{Profile: "full"})
+	require.NoError(t, err)
+	assert.False(t, metadataOnly)
+
+	metadataOnly, err = IsMetadataOnly(&troubleshootv1beta2.SupportBundleSpec{Profile: "metadataOnly"})
+	require.NoError(t, err)
+	assert.True(t, metadataOnly)
+
+	_, err = IsMetadataOnly(&troubleshootv1beta2.SupportBundleSpec{Profile: "minimal"})
+	assert.EqualError(t, err, `unknown profile "minimal", must be full or metadataOnly`)
+}
+
+func Test_payloadMetadata(t *testing.T) {
+	assert.Equal(t,
+		"[troubleshoot: removed by the metadataOnly profile, 11 bytes, 2 lines, sha256 766340ea4a5a16ae8b6538ca557df8b2a0925f1a8d7ac534ac9f3049bf99e598]",
+		payloadMetadata([]byte("line 1\nline")),
+	)
+	assert.Contains(t, payloadMetadata([]byte("line 1\nline 2\n")), "14 bytes, 2 lines")
+	assert.Contains(t, payloadMetadata(nil), "0 bytes, 0 lines, sha256 e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855")
+}
+
+func TestStripPayloads(t *testing.T) {
+	bundlePath := t.TempDir()
+	output := NewResult()
+
+	logs := "a log line\nanother log line\n"
+	require.NoError(t, output.SaveResult(bundlePath, "app/api/api-1.log", strings.NewReader(logs)))
+	require.NoError(t, output.SymLinkResult(bundlePath, "app/api/api-1-link.log", "app/api/api-1.log"))
+	require.NoError(t, output.SaveResult(bundlePath, "app/api/errors.json", strings.NewReader(`["pod api-2 not found"]`)))
+
+	c := &CollectLogs{Collector: &troubleshootv1beta2.Logs{Name: "app/api"}}
+	require.NoError(t, StripPayloads(c, output, bundlePath))
+
+	b, err := os.ReadFile(filepath.Join(bundlePath, "app/api/api-1.log"))
+	require.NoError(t, err)
+	assert.Equal(t, payloadMetadata([]byte(logs))+"\n", string(b))
+	assert.NotContains(t, string(b), "log line")
+
+	b, err = os.ReadFile(filepath.Join(bundlePath, "app/api/errors.json"))
+	require.NoError(t, err)
+	assert.Equal(t, `["pod api-2 not found"]`, string(b))
+}
+
+func TestStripPayloads_ConfigMapAndSecret(t *testing.T) {
+	output := NewResult()
+	configMap, err := json.Marshal(ConfigMapOutput{
+		Namespace:       "default",
+		Name:            "api-config",
+		ConfigMapExists: true,
+		Data:            map[string]string{"config.yaml": "password: hunter2\n"},
+	})
+	require.NoError(t, err)
+	output["configmaps/default/api-config.json"] = configMap
+	output["configmaps-errors/default/other.json"] = []byte(`["configmaps \"other\" not found"]`)
+
+	c := &CollectConfigMap{Collector: &troubleshootv1beta2.ConfigMap{Name: "api-config"}}
+	require.NoError(t, StripPayloads(c, output, ""))
+
+	stripped := ConfigMapOutput{}
+	require.NoError(t, json.Unmarshal(output["configmaps/default/api-config.json"], &stripped))
+	assert.Equal(t, "api-config", stripped.Name)
+	assert.True(t, stripped.ConfigMapExists)
+	assert.Equal(t, payloadMetadata([]byte("password: hunter2\n")), stripped.Data["config.yaml"])
+	assert.Equal(t, `["configmaps \"other\" not found"]`, string(output["configmaps-errors/default/other.json"]))
+
+	secret, err := json.Marshal(SecretOutput{Namespace: "default", Name: "db", Key: "password", SecretExists: true, KeyExists: true, Value: "hunter2"})
+	require.NoError(t, err)
+	output = NewResult()
+	output["secrets/default/db/password.json"] = secret
+
+	require.NoError(t, StripPayloads(&CollectSecret{Collector: &troubleshootv1beta2.Secret{Name: "db"}}, output, ""))
+	assert.NotContains(t, string(output["secrets/default/db/password.json"]), "hunter2")
+	assert.Contains(t, string(output["secrets/default/db/password.json"]), `"keyExists": true`)
+}
+
+func TestStripPayloads_ClusterResources(t *testing.T) {
+	output := NewResult()
+	output["cluster-resources/configmaps/default.json"] = []byte(`{
+  "kind": "ConfigMapList",
+  "apiVersion": "v1",
+  "metadata": {},
+  "items": [
+    {
+      "metadata": {"name": "api-config", "namespace": "default"},
+      "data": {"config.yaml": "password: hunter2\n"},
+      "binaryData": {"cert.der": "` + base64.StdEncoding.EncodeToString([]byte{0x30, 0x82}) + `"}
+    }
+  ]
+}`)
+	output["cluster-resources/pods/default.json"] = []byte(`{"kind": "PodList", "items": []}`)
+	output["cluster-resources/pods/logs/default/api-1/api.log"] = []byte("starting api\n")
+	output["cluster-resources/pods/logs/default/api-2/api-logs-errors.log"] = []byte("container is waiting to start\n")
+
+	c := &CollectClusterResources{Collector: &troubleshootv1beta2.ClusterResources{}}
+	require.NoError(t, StripPayloads(c, output, ""))
+
+	list := struct {
+		Kind  string `json:"kind"`
+		Items []struct {
+			Metadata   map[string]string `json:"metadata"`
+			Data       map[string]string `json:"data"`
+			BinaryData map[string][]byte `json:"binaryData"`
+		} `json:"items"`
+	}{}
+	require.NoError(t, json.Unmarshal(output["cluster-resources/configmaps/default.json"], &list))
+	assert.Equal(t, "ConfigMapList", list.Kind)
+	require.Len(t, list.Items, 1)
+	assert.Equal(t, "api-config", list.Items[0].Metadata["name"])
+	assert.Equal(t, payloadMetadata([]byte("password: hunter2\n")), list.Items[0].Data["config.yaml"])
+	assert.Equal(t, payloadMetadata([]byte{0x30, 0x82}), string(list.Items[0].BinaryData["cert.der"]))
+
+	assert.Equal(t, `{"kind": "PodList", "items": []}`, string(output["cluster-resources/pods/default.json"]))
+	assert.Equal(t, payloadMetadata([]byte("starting api\n"))+"\n", string(output["cluster-resources/pods/logs/default/api-1/api.log"]))
+	assert.Equal(t, "container is waiting to start\n", string(output["cluster-resources/pods/logs/default/api-2/api-logs-errors.log"]))
+}
+
+func TestStripPayloads_Unparseable(t *testing.T) {
+	output := NewResult()
+	output["configmaps/default/api-config.json"] = []byte("password: hunter2")
+
+	c := &CollectConfigMap{Collector: &troubleshootv1beta2.ConfigMap{Name: "api-config"}}
+	require.NoError(t, StripPayloads(c, output, ""))
+	assert.Equal(t, payloadMetadata([]byte("password: hunter2"))+"\n", string(output["configmaps/default/api-config.json"]))
+}
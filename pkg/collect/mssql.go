@@ -38,7 +38,33 @@ func (c *CollectMssql) IsExcluded() (bool, error) {
 func (c *CollectMssql) Collect(progressChan chan<- interface{}) (CollectorResult, error) {
 	databaseConnection := DatabaseConnection{}
 
-	connUrl, err := url.Parse(c.Collector.URI)
+	uri, err := databaseURI(c.Context, c.Client, c.Collector)
+	if err != nil {
+		klog.V(2).Infof("Failed to get MSSQL Database uri: %v", err)
+		databaseConnection.Error = err.Error()
+	} else {
+		c.checkConnection(uri, &databaseConnection)
+	}
+
+	b, err := json.Marshal(databaseConnection)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal database connection")
+	}
+
+	collectorName := c.Collector.CollectorName
+	if collectorName == "" {
+		collectorName = "mssql"
+	}
+
+	output := NewResult()
+	output.SaveResult(c.BundlePath, fmt.Sprintf("mssql/%s.json", collectorName), bytes.NewBuffer(b))
+
+	return output, nil
+}
+
+// checkConnection connects to the database and records its version in databaseConnection
+func (c *CollectMssql) checkConnection(uri string, databaseConnection *DatabaseConnection) {
+	connUrl, err := url.Parse(uri)
 	// Parsing the uri should not lead to the collector failing
 	// sql.Open will fail if the uri is invalid.
 	if err == nil {
@@ -47,7 +73,7 @@ func (c *CollectMssql) Collect(progressChan chan<- interface{}) (CollectorResult
 		klog.V(2).Info("Connect to MSSQL Database")
 	}
 
-	db, err := sql.Open("mssql", c.Collector.URI)
+	db, err := sql.Open("mssql", uri)
 	if err != nil {
 		klog.V(2).Infof("Failed to connect to %q MSSQL Database: %v", connUrl.Host, err)
 		databaseConnection.Error = err.Error()
@@ -72,21 +98,6 @@ func (c *CollectMssql) Collect(progressChan chan<- interface{}) (CollectorResult
 			klog.V(2).Infof("Successfully queried version string from database: %s", version)
 		}
 	}
-
-	b, err := json.Marshal(databaseConnection)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to marshal database connection")
-	}
-
-	collectorName := c.Collector.CollectorName
-	if collectorName == "" {
-		collectorName = "mssql"
-	}
-
-	output := NewResult()
-	output.SaveResult(c.BundlePath, fmt.Sprintf("mssql/%s.json", collectorName), bytes.NewBuffer(b))
-
-	return output, nil
 }
 
 func parseMsSqlVersion(mssqlVersion string) (string, error) {
@@ -6,6 +6,7 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"strconv"
 
 	_ "github.com/go-sql-driver/mysql"
 	"github.com/pkg/errors"
@@ -35,9 +36,11 @@ func (c *CollectMysql) IsExcluded() (bool, error) {
 func (c *CollectMysql) Collect(progressChan chan<- interface{}) (CollectorResult, error) {
 	databaseConnection := DatabaseConnection{}
 
-	db, err := sql.Open("mysql", c.Collector.URI)
+	uri, err := databaseURI(c.Context, c.Client, c.Collector)
 	if err != nil {
 		databaseConnection.Error = err.Error()
+	} else if db, err := sql.Open("mysql", uri); err != nil {
+		databaseConnection.Error = err.Error()
 	} else {
 		defer db.Close()
 		query := `select version()`
@@ -52,6 +55,9 @@ func (c *CollectMysql) Collect(progressChan chan<- interface{}) (CollectorResult
 		}
 
 		requestedParameters := c.Collector.Parameters
+		if len(requestedParameters) == 0 && c.Collector.Diagnostics {
+			requestedParameters = defaultMysqlParameters
+		}
 		if len(requestedParameters) > 0 {
 			rows, err := db.Query("SHOW VARIABLES")
 
@@ -82,6 +88,9 @@ func (c *CollectMysql) Collect(progressChan chan<- interface{}) (CollectorResult
 			}
 		}
 
+		if databaseConnection.IsConnected && c.Collector.Diagnostics {
+			databaseConnection.Diagnostics = mysqlDiagnostics(c.Context, db)
+		}
 	}
 
 	b, err := json.Marshal(databaseConnection)
@@ -99,3 +108,100 @@ func (c *CollectMysql) Collect(progressChan chan<- interface{}) (CollectorResult
 
 	return output, nil
 }
+
+// defaultMysqlParameters are the configuration variables collected with diagnostics
+var defaultMysqlParameters = []string{
+	"max_connections",
+	"innodb_buffer_pool_size",
+	"long_query_time",
+	"slow_query_log",
+	"read_only",
+	"binlog_format",
+	"sql_mode",
+}
+
+func mysqlDiagnostics(ctx context.Context, db *sql.DB) *DatabaseDiagnostics {
+	diagnostics := &DatabaseDiagnostics{}
+
+	// SHOW SLAVE STATUS was renamed in MySQL 8.0.22, and MariaDB only has the old name
+	replicaStatus, err := queryMysqlRow(ctx, db, "SHOW REPLICA STATUS")
+	if err != nil {
+		replicaStatus, err = queryMysqlRow(ctx, db, "SHOW SLAVE STATUS")
+	}
+	if err != nil {
+		diagnostics.addError("replication status", err)
+	} else if replicaStatus != nil {
+		diagnostics.IsReplica = true
+		lag, ok := replicaStatus["Seconds_Behind_Source"]
+		if !ok {
+			lag = replicaStatus["Seconds_Behind_Master"]
+		}
+		// the lag is null when replication is stopped
+		if lag.Valid {
+			seconds, err := strconv.ParseFloat(lag.String, 64)
+			if err != nil {
+				diagnostics.addError("replication lag", err)
+			} else {
+				diagnostics.ReplicationLagSeconds = &seconds
+			}
+		}
+	}
+
+	rows, err := db.QueryContext(ctx, "SHOW GLOBAL STATUS WHERE Variable_name IN ('Threads_connected', 'Slow_queries')")
+	if err != nil {
+		diagnostics.addError("status", err)
+	} else {
+		defer rows.Close()
+		for rows.Next() {
+			var name, value string
+			if err := rows.Scan(&name, &value); err != nil {
+				diagnostics.addError("status", err)
+				break
+			}
+			switch name {
+			case "Threads_connected":
+				diagnostics.Connections, _ = strconv.Atoi(value)
+			case "Slow_queries":
+				diagnostics.SlowQueries, _ = strconv.ParseInt(value, 10, 64)
+			}
+		}
+	}
+
+	if err := db.QueryRowContext(ctx, "SELECT @@max_connections").Scan(&diagnostics.MaxConnections); err != nil {
+		diagnostics.addError("max connections", err)
+	}
+
+	return diagnostics
+}
+
+// queryMysqlRow returns the columns of the first row of a SHOW statement by name, or nil when it
+// returns no rows
+func queryMysqlRow(ctx context.Context, db *sql.DB, query string) (map[string]sql.NullString, error) {
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		return nil, rows.Err()
+	}
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	values := make([]sql.NullString, len(columns))
+	dest := make([]interface{}, len(columns))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+	if err := rows.Scan(dest...); err != nil {
+		return nil, err
+	}
+
+	row := map[string]sql.NullString{}
+	for i, column := range columns {
+		row[column] = values[i]
+	}
+	return row, nil
+}
@@ -0,0 +1,396 @@
+package collect
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/constants"
+	"github.com/replicatedhq/troubleshoot/pkg/k8sutil"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	kuberneteserrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/klog/v2"
+)
+
+const nodeLatencyDefaultTimeout = 2 * time.Minute
+
+// NodeLatencyResult is the latency matrix saved by the node latency collector, with a pair for
+// every ordered pair of distinct nodes
+type NodeLatencyResult struct {
+	Nodes []string          `json:"nodes"`
+	Pairs []NodeLatencyPair `json:"pairs"`
+}
+
+// NodeLatencyPair is the result of pinging the pod on node To from a pod on node From. Round
+// trip times are in milliseconds, and are not set when no ping was answered. Error is set when
+// the pair could not be measured, in which case the packet loss is 100%.
+type NodeLatencyPair struct {
+	From       string   `json:"from"`
+	To         string   `json:"to"`
+	Sent       int      `json:"sent"`
+	Received   int      `json:"received"`
+	PacketLoss float64  `json:"packetLoss"`
+	MinRTT     *float64 `json:"minRttMs,omitempty"`
+	AvgRTT     *float64 `json:"avgRttMs,omitempty"`
+	MaxRTT     *float64 `json:"maxRttMs,omitempty"`
+	Error      string   `json:"error,omitempty"`
+}
+
+// NodeLatencyPath returns the path of the latency matrix saved by a node latency collector
+func NodeLatencyPath(collectorName string) string {
+	if collectorName == "" {
+		collectorName = "node-latency"
+	}
+	return filepath.Join("node-latency", collectorName+".json")
+}
+
+// CollectNodeLatency runs a target pod on every node with a DaemonSet, then a probe pod on every
+// ready node that pings all the targets over the pod network
+type CollectNodeLatency struct {
+	Collector    *troubleshootv1beta2.NodeLatency
+	BundlePath   string
+	Namespace    string
+	ClientConfig *rest.Config
+	Client       kubernetes.Interface
+	Context      context.Context
+	RBACErrors
+}
+
+func (c *CollectNodeLatency) Title() string {
+	return getCollectorName(c)
+}
+
+func (c *CollectNodeLatency) IsExcluded() (bool, error) {
+	return isExcluded(c.Collector.Exclude)
+}
+
+func (c *CollectNodeLatency) Collect(progressChan chan<- interface{}) (CollectorResult, error) {
+	timeout := nodeLatencyDefaultTimeout
+	if c.Collector.Timeout != "" {
+		parsed, err := time.ParseDuration(c.Collector.Timeout)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse timeout %q", c.Collector.Timeout)
+		}
+		if parsed > 0 {
+			timeout = parsed
+		}
+	}
+	ctx, cancel := context.WithTimeout(c.Context, timeout)
+	defer cancel()
+
+	namespace := c.Collector.Namespace
+	if namespace == "" {
+		namespace = c.Namespace
+	}
+	if namespace == "" {
+		kubeconfig := k8sutil.GetKubeconfig()
+		namespace, _, _ = kubeconfig.Namespace()
+	}
+
+	image := c.Collector.Image
+	if image == "" {
+		image = constants.NODE_LATENCY_DEFAULT_IMAGE
+	}
+	count := c.Collector.Count
+	if count <= 0 {
+		count = constants.NODE_LATENCY_DEFAULT_COUNT
+	}
+
+	var secretName string
+	if c.Collector.ImagePullSecret != nil {
+		secretName = c.Collector.ImagePullSecret.Name
+		if c.Collector.ImagePullSecret.Data != nil {
+			var err error
+			secretName, err = createSecret(ctx, c.Client, namespace, c.Collector.ImagePullSecret)
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to create image pull secret")
+			}
+			defer func() {
+				err := c.Client.CoreV1().Secrets(namespace).Delete(context.Background(), secretName, metav1.DeleteOptions{})
+				if err != nil && !kuberneteserrors.IsNotFound(err) {
+					klog.Errorf("Failed to delete secret %s: %v", secretName, err)
+				}
+			}()
+		}
+	}
+
+	nodes, err := c.Client.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list nodes")
+	}
+	targetDS := nodeLatencyTargetDaemonSet(namespace, image, c.Collector.ImagePullPolicy, secretName, timeout)
+	applyPodScheduling(&targetDS.Spec.Template.Spec, c.Collector.PodScheduling)
+	nodeNames, err := schedulableNodes(nodes.Items, &targetDS.Spec.Template.Spec)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to match nodes")
+	}
+	sort.Strings(nodeNames)
+
+	ds, err := c.Client.AppsV1().DaemonSets(namespace).Create(ctx, targetDS, metav1.CreateOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create node latency target DaemonSet")
+	}
+	defer func() {
+		propagation := metav1.DeletePropagationForeground
+		err := c.Client.AppsV1().DaemonSets(ds.Namespace).Delete(context.Background(), ds.Name, metav1.DeleteOptions{PropagationPolicy: &propagation})
+		if err != nil && !kuberneteserrors.IsNotFound(err) {
+			klog.Errorf("Failed to delete DaemonSet %s: %v", ds.Name, err)
+		}
+	}()
+
+	// Targets that are not ready by half the timeout are reported as unreachable, leaving the
+	// other half to the pings
+	targets, err := waitForNodeLatencyTargets(ctx, c.Client, ds, timeout/2)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to wait for node latency targets")
+	}
+	klog.V(2).Infof("%d of %d node latency targets are ready", len(targets), len(nodeNames))
+
+	probeLogs := map[string][]byte{}
+	if len(targets) > 0 {
+		probeLogs, err = RunPodsReadyNodes(ctx, c.Client.CoreV1(), RunPodOptions{
+			Image:               image,
+			ImagePullPolicy:     c.Collector.ImagePullPolicy,
+			ImagePullSecretName: secretName,
+			Namespace:           namespace,
+			Command:             []string{"sh", "-c", nodeLatencyProbeScript(targets, count)},
+			Scheduling:          &c.Collector.PodScheduling,
+		})
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to run node latency probes")
+		}
+	}
+
+	b, err := json.MarshalIndent(buildNodeLatencyResult(nodeNames, targets, probeLogs), "", "  ")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal node latency result")
+	}
+
+	output := NewResult()
+	err = output.SaveResult(c.BundlePath, NodeLatencyPath(c.Collector.CollectorName), bytes.NewBuffer(b))
+	return output, err
+}
+
+// nodeLatencyTargetDaemonSet returns a DaemonSet of pods that only wait to be pinged, for at most
+// the timeout of the collector
+func nodeLatencyTargetDaemonSet(namespace string, image string, pullPolicy string, secretName string, timeout time.Duration) *appsv1.DaemonSet {
+	labels := map[string]string{
+		"troubleshoot-role": "node-latency-target",
+	}
+
+	ds := &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: "node-latency-target-",
+			Namespace:    namespace,
+			Labels:       labels,
+		},
+		Spec: appsv1.DaemonSetSpec{
+			Selector: &metav1.LabelSelector{
+				MatchLabels: labels,
+			},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: labels,
+				},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{
+							Name:            "target",
+							Image:           image,
+							ImagePullPolicy: corev1.PullPolicy(pullPolicy),
+							Command:         []string{"sleep", strconv.Itoa(int(timeout.Seconds()))},
+						},
+					},
+					Tolerations: []corev1.Toleration{
+						{
+							Key:      "node-role.kubernetes.io/master",
+							Operator: "Exists",
+							Effect:   "NoSchedule",
+						},
+						{
+							Key:      "node-role.kubernetes.io/control-plane",
+							Operator: "Exists",
+							Effect:   "NoSchedule",
+						},
+					},
+				},
+			},
+		},
+	}
+	if secretName != "" {
+		ds.Spec.Template.Spec.ImagePullSecrets = []corev1.LocalObjectReference{{Name: secretName}}
+	}
+
+	return ds
+}
+
+// waitForNodeLatencyTargets waits for all the pods of the DaemonSet to be ready, or for
+// readyTimeout to elapse, and returns the IPs of the pods that are running by node name
+func waitForNodeLatencyTargets(ctx context.Context, client kubernetes.Interface, ds *appsv1.DaemonSet, readyTimeout time.Duration) (map[string]string, error) {
+	waitCtx, cancel := context.WithTimeout(ctx, readyTimeout)
+	defer cancel()
+
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+wait:
+	for {
+		select {
+		case <-waitCtx.Done():
+			break wait
+		case <-ticker.C:
+			current, err := client.AppsV1().DaemonSets(ds.Namespace).Get(ctx, ds.Name, metav1.GetOptions{})
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to get DaemonSet")
+			}
+			if current.Status.DesiredNumberScheduled > 0 && current.Status.NumberReady == current.Status.DesiredNumberScheduled {
+				break wait
+			}
+		}
+	}
+
+	pods, err := client.CoreV1().Pods(ds.Namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: getLabelSelector(ds),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list pods")
+	}
+
+	targets := map[string]string{}
+	for _, pod := range pods.Items {
+		if pod.Status.Phase == corev1.PodRunning && pod.Status.PodIP != "" && pod.Spec.NodeName != "" {
+			targets[pod.Spec.NodeName] = pod.Status.PodIP
+		}
+	}
+	return targets, nil
+}
+
+// nodeLatencyProbeScript pings all the targets at once, then prints the output of each ping
+// after a "=== <node>" header
+func nodeLatencyProbeScript(targets map[string]string, count int) string {
+	nodes := make([]string, 0, len(targets))
+	for node := range targets {
+		nodes = append(nodes, node)
+	}
+	sort.Strings(nodes)
+
+	script := strings.Builder{}
+	for i, node := range nodes {
+		fmt.Fprintf(&script, "ping -c %d -W 2 %s > /tmp/target-%d 2>&1 &\n", count, targets[node], i)
+	}
+	script.WriteString("wait\n")
+	for i, node := range nodes {
+		fmt.Fprintf(&script, "echo '=== %s'\ncat /tmp/target-%d\n", node, i)
+	}
+	return script.String()
+}
+
+// buildNodeLatencyResult builds the matrix of every pair of nodes from the output of the probe
+// pods, by node name
+func buildNodeLatencyResult(nodes []string, targets map[string]string, probeLogs map[string][]byte) NodeLatencyResult {
+	result := NodeLatencyResult{
+		Nodes: nodes,
+		Pairs: []NodeLatencyPair{},
+	}
+
+	for _, from := range nodes {
+		var outputs map[string]string
+		if logs, ok := probeLogs[from]; ok {
+			outputs = splitNodeLatencyProbeLogs(logs)
+		}
+
+		for _, to := range nodes {
+			if from == to {
+				continue
+			}
+
+			pair := NodeLatencyPair{From: from, To: to, PacketLoss: 100}
+			output, ok := outputs[to]
+			switch {
+			case targets[to] == "":
+				pair.Error = "no target pod was ready on the node"
+			case outputs == nil:
+				pair.Error = "the probe pod did not run on the node"
+			case !ok:
+				pair.Error = "no ping output for the node"
+			default:
+				pair = parsePingOutput(from, to, output)
+			}
+			result.Pairs = append(result.Pairs, pair)
+		}
+	}
+
+	return result
+}
+
+func splitNodeLatencyProbeLogs(logs []byte) map[string]string {
+	outputs := map[string]string{}
+	var node string
+	var output strings.Builder
+
+	scanner := bufio.NewScanner(bytes.NewReader(logs))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "=== ") {
+			if node != "" {
+				outputs[node] = output.String()
+			}
+			node = strings.TrimPrefix(line, "=== ")
+			output.Reset()
+			continue
+		}
+		output.WriteString(line + "\n")
+	}
+	if node != "" {
+		outputs[node] = output.String()
+	}
+	return outputs
+}
+
+var (
+	pingPacketsRegex = regexp.MustCompile(`(\d+) packets transmitted, (\d+) (?:packets )?received`)
+	pingRTTRegex     = regexp.MustCompile(`min/avg/max\S* = ([\d.]+)/([\d.]+)/([\d.]+)`)
+)
+
+// parsePingOutput reads the statistics printed by the ping of busybox or iputils
+func parsePingOutput(from string, to string, output string) NodeLatencyPair {
+	pair := NodeLatencyPair{From: from, To: to, PacketLoss: 100}
+
+	packets := pingPacketsRegex.FindStringSubmatch(output)
+	if packets == nil {
+		pair.Error = strings.TrimSpace(output)
+		if pair.Error == "" {
+			pair.Error = "no ping statistics"
+		}
+		return pair
+	}
+	pair.Sent, _ = strconv.Atoi(packets[1])
+	pair.Received, _ = strconv.Atoi(packets[2])
+	if pair.Sent > 0 {
+		pair.PacketLoss = float64(pair.Sent-pair.Received) * 100 / float64(pair.Sent)
+	}
+
+	if rtt := pingRTTRegex.FindStringSubmatch(output); rtt != nil {
+		values := make([]float64, 3)
+		for i := range values {
+			values[i], _ = strconv.ParseFloat(rtt[i+1], 64)
+		}
+		pair.MinRTT, pair.AvgRTT, pair.MaxRTT = &values[0], &values[1], &values[2]
+	}
+
+	return pair
+}
@@ -0,0 +1,96 @@
+package collect
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_parsePingOutput(t *testing.T) {
+	rtt := func(v float64) *float64 { return &v }
+
+	tests := []struct {
+		name   string
+		output string
+		want   NodeLatencyPair
+	}{
+		{
+			name: "busybox",
+			output: `PING 10.42.1.5 (10.42.1.5): 56 data bytes
+64 bytes from 10.42.1.5: seq=0 ttl=62 time=0.612 ms
+
+--- 10.42.1.5 ping statistics ---
+4 packets transmitted, 3 packets received, 25% packet loss
+round-trip min/avg/max = 0.512/0.634/0.801 ms
+`,
+			want: NodeLatencyPair{From: "a", To: "b", Sent: 4, Received: 3, PacketLoss: 25, MinRTT: rtt(0.512), AvgRTT: rtt(0.634), MaxRTT: rtt(0.801)},
+		},
+		{
+			name: "iputils",
+			output: `--- 10.42.1.5 ping statistics ---
+10 packets transmitted, 10 received, 0% packet loss, time 9013ms
+rtt min/avg/max/mdev = 0.301/0.402/0.612/0.080 ms
+`,
+			want: NodeLatencyPair{From: "a", To: "b", Sent: 10, Received: 10, PacketLoss: 0, MinRTT: rtt(0.301), AvgRTT: rtt(0.402), MaxRTT: rtt(0.612)},
+		},
+		{
+			name: "no replies",
+			output: `--- 10.42.1.5 ping statistics ---
+2 packets transmitted, 0 packets received, 100% packet loss
+`,
+			want: NodeLatencyPair{From: "a", To: "b", Sent: 2, PacketLoss: 100},
+		},
+		{
+			name:   "error",
+			output: "ping: permission denied (are you root?)\n",
+			want:   NodeLatencyPair{From: "a", To: "b", PacketLoss: 100, Error: "ping: permission denied (are you root?)"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, parsePingOutput("a", "b", tt.output))
+		})
+	}
+}
+
+func Test_nodeLatencyProbeScript(t *testing.T) {
+	script := nodeLatencyProbeScript(map[string]string{"node-b": "10.0.0.2", "node-a": "10.0.0.1"}, 5)
+	assert.Equal(t, `ping -c 5 -W 2 10.0.0.1 > /tmp/target-0 2>&1 &
+ping -c 5 -W 2 10.0.0.2 > /tmp/target-1 2>&1 &
+wait
+echo '=== node-a'
+cat /tmp/target-0
+echo '=== node-b'
+cat /tmp/target-1
+`, script)
+}
+
+func Test_buildNodeLatencyResult(t *testing.T) {
+	probeLogs := map[string][]byte{
+		"node-a": []byte(`=== node-a
+1 packets transmitted, 1 packets received, 0% packet loss
+round-trip min/avg/max = 0.1/0.1/0.1 ms
+=== node-b
+2 packets transmitted, 2 packets received, 0% packet loss
+round-trip min/avg/max = 1.5/2.0/2.5 ms
+`),
+	}
+	targets := map[string]string{"node-a": "10.0.0.1", "node-b": "10.0.0.2"}
+
+	result := buildNodeLatencyResult([]string{"node-a", "node-b", "node-c"}, targets, probeLogs)
+
+	assert.Equal(t, []string{"node-a", "node-b", "node-c"}, result.Nodes)
+	require.Len(t, result.Pairs, 6)
+
+	pairs := map[string]NodeLatencyPair{}
+	for _, pair := range result.Pairs {
+		pairs[pair.From+"->"+pair.To] = pair
+	}
+	assert.Equal(t, 2, pairs["node-a->node-b"].Received)
+	assert.Equal(t, 2.0, *pairs["node-a->node-b"].AvgRTT)
+	assert.Equal(t, "no target pod was ready on the node", pairs["node-a->node-c"].Error)
+	assert.Equal(t, "the probe pod did not run on the node", pairs["node-b->node-a"].Error)
+	assert.Equal(t, 100.0, pairs["node-b->node-a"].PacketLoss)
+	assert.Equal(t, "no target pod was ready on the node", pairs["node-b->node-c"].Error)
+}
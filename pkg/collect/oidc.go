@@ -0,0 +1,280 @@
+package collect
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+const (
+	defaultOIDCTimeout = 10 * time.Second
+	oidcDiscoveryPath  = "/.well-known/openid-configuration"
+)
+
+type CollectOIDC struct {
+	Collector    *troubleshootv1beta2.OIDC
+	BundlePath   string
+	Namespace    string
+	ClientConfig *rest.Config
+	Client       kubernetes.Interface
+	Context      context.Context
+	RBACErrors
+}
+
+// OIDCProvider is the outcome of reading the discovery document and the signing keys of an OpenID
+// Connect provider, and of requesting a token from it. Nothing is requested from a provider whose
+// certificate is not trusted past its discovery document.
+type OIDCProvider struct {
+	IsConnected bool   `json:"isConnected"`
+	Error       string `json:"error,omitempty"`
+	// LatencyMilliseconds is the time it took to read the discovery document
+	LatencyMilliseconds int64 `json:"latencyMilliseconds,omitempty"`
+	// TLS is the handshake with the issuer, nil when its URL is not https
+	TLS *HTTPTLS `json:"tls,omitempty"`
+	// CertificateError is why the certificate of the issuer is not trusted, empty when it is
+	CertificateError string `json:"certificateError,omitempty"`
+	// IsDiscovered is true when the discovery document was read and is of the issuer
+	IsDiscovered          bool   `json:"isDiscovered"`
+	Issuer                string `json:"issuer,omitempty"`
+	AuthorizationEndpoint string `json:"authorizationEndpoint,omitempty"`
+	TokenEndpoint         string `json:"tokenEndpoint,omitempty"`
+	JWKSURI               string `json:"jwksURI,omitempty"`
+	// SigningKeys is the number of keys of the JWKS of the provider
+	SigningKeys int `json:"signingKeys"`
+	// TokenRequested is true when the collector sets a client to request a token for
+	TokenRequested           bool   `json:"tokenRequested"`
+	TokenIssued              bool   `json:"tokenIssued"`
+	TokenError               string `json:"tokenError,omitempty"`
+	TokenLatencyMilliseconds int64  `json:"tokenLatencyMilliseconds,omitempty"`
+}
+
+// oidcDiscovery, oidcJWKS and oidcTokenResponse are the fields of the responses of the provider
+// the collector reads
+type oidcDiscovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+type oidcJWKS struct {
+	Keys []json.RawMessage `json:"keys"`
+}
+
+type oidcTokenResponse struct {
+	AccessToken      string `json:"access_token"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+func (c *CollectOIDC) Title() string {
+	return getCollectorName(c)
+}
+
+func (c *CollectOIDC) IsExcluded() (bool, error) {
+	return isExcluded(c.Collector.Exclude)
+}
+
+func (c *CollectOIDC) Collect(progressChan chan<- interface{}) (CollectorResult, error) {
+	provider := OIDCProvider{}
+
+	if err := c.collectProvider(&provider); err != nil {
+		provider.Error = err.Error()
+	}
+
+	b, err := json.Marshal(provider)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal oidc provider")
+	}
+
+	collectorName := c.Collector.CollectorName
+	if collectorName == "" {
+		collectorName = "oidc"
+	}
+
+	output := NewResult()
+	output.SaveResult(c.BundlePath, fmt.Sprintf("oidc/%s.json", collectorName), bytes.NewBuffer(b))
+
+	return output, nil
+}
+
+// collectProvider reads the client secret and the TLS configuration of the collector before
+// checking the provider
+func (c *CollectOIDC) collectProvider(provider *OIDCProvider) error {
+	timeout := defaultOIDCTimeout
+	if c.Collector.Timeout != "" {
+		var err error
+		timeout, err = time.ParseDuration(c.Collector.Timeout)
+		if err != nil {
+			return errors.Wrapf(err, "failed to parse timeout %q", c.Collector.Timeout)
+		}
+	}
+
+	clientSecret := ""
+	if c.Collector.ClientSecret != nil {
+		secret := c.Collector.ClientSecret
+		var err error
+		clientSecret, err = readSecretKey(c.Context, c.Client, secret.Namespace, secret.Name, secret.Key)
+		if err != nil {
+			return errors.Wrap(err, "failed to read client secret")
+		}
+	}
+
+	var tlsConfig *tls.Config
+	if c.Collector.TLS != nil {
+		var err error
+		tlsConfig, err = createTLSConfig(c.Context, c.Client, c.Collector.TLS)
+		if err != nil {
+			return errors.Wrap(err, "failed to create tls config")
+		}
+	}
+
+	collectOIDCProvider(c.Context, c.Collector, clientSecret, tlsConfig, timeout, provider)
+	return nil
+}
+
+// collectOIDCProvider reads the discovery document of the issuer of the collector and the signing
+// keys it lists, and requests a token with the client credentials grant when the collector sets a
+// client. Only the discovery document is read without verifying the certificate of the issuer, to
+// record why it is not trusted.
+func collectOIDCProvider(ctx context.Context, collector *troubleshootv1beta2.OIDC, clientSecret string, tlsConfig *tls.Config, timeout time.Duration, provider *OIDCProvider) {
+	issuerURL := strings.TrimSuffix(collector.IssuerURL, "/")
+	u, err := url.Parse(issuerURL)
+	if err != nil {
+		provider.Error = errors.Wrap(err, "failed to parse issuer url").Error()
+		return
+	}
+	verifier, discoveryTLSConfig := newTLSVerifier(tlsConfig, u.Hostname())
+	discoveryClient := &http.Client{Timeout: timeout, Transport: &http.Transport{TLSClientConfig: discoveryTLSConfig}}
+	client := &http.Client{Timeout: timeout, Transport: &http.Transport{TLSClientConfig: tlsConfig}}
+
+	discovery := oidcDiscovery{}
+	start := time.Now()
+	err = getOIDC(ctx, discoveryClient, issuerURL+oidcDiscoveryPath, &discovery)
+	provider.LatencyMilliseconds = time.Since(start).Milliseconds()
+	if verifier.state != nil {
+		provider.IsConnected = true
+		provider.TLS = httpTLS(verifier.state)
+		if provider.CertificateError = verifier.certificateError(); provider.CertificateError != "" {
+			return
+		}
+	}
+	if err != nil {
+		provider.Error = errors.Wrap(err, "failed to read discovery document").Error()
+		return
+	}
+	provider.IsConnected = true
+	provider.Issuer = discovery.Issuer
+	provider.AuthorizationEndpoint = discovery.AuthorizationEndpoint
+	provider.TokenEndpoint = discovery.TokenEndpoint
+	provider.JWKSURI = discovery.JWKSURI
+
+	// clients reject providers whose discovery document names another issuer
+	if discovery.Issuer != issuerURL {
+		provider.Error = fmt.Sprintf("discovery document is of issuer %q, not %q", discovery.Issuer, issuerURL)
+		return
+	}
+	provider.IsDiscovered = true
+
+	jwks := oidcJWKS{}
+	if err := getOIDC(ctx, client, discovery.JWKSURI, &jwks); err != nil {
+		provider.Error = errors.Wrap(err, "failed to read signing keys").Error()
+		return
+	}
+	provider.SigningKeys = len(jwks.Keys)
+
+	if collector.ClientID == "" {
+		return
+	}
+	provider.TokenRequested = true
+	start = time.Now()
+	err = requestOIDCToken(ctx, client, discovery.TokenEndpoint, collector.ClientID, clientSecret, collector.Scopes)
+	provider.TokenLatencyMilliseconds = time.Since(start).Milliseconds()
+	if err != nil {
+		provider.TokenError = err.Error()
+		return
+	}
+	provider.TokenIssued = true
+}
+
+// getOIDC decodes the response of the provider at the URL into v
+func getOIDC(ctx context.Context, client *http.Client, url string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to create request")
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return errors.Wrap(err, "failed to read response")
+	}
+	if resp.StatusCode != http.StatusOK {
+		return errors.Errorf("unexpected status %s", resp.Status)
+	}
+
+	if err := json.Unmarshal(body, v); err != nil {
+		return errors.Wrap(err, "failed to unmarshal response")
+	}
+	return nil
+}
+
+// requestOIDCToken requests a token for the client from the token endpoint with the client
+// credentials grant, authenticating the client with basic auth
+func requestOIDCToken(ctx context.Context, client *http.Client, tokenEndpoint string, clientID string, clientSecret string, scopes []string) error {
+	form := url.Values{"grant_type": {"client_credentials"}}
+	if len(scopes) > 0 {
+		form.Set("scope", strings.Join(scopes, " "))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, tokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return errors.Wrap(err, "failed to create request")
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(url.QueryEscape(clientID), url.QueryEscape(clientSecret))
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return errors.Wrap(err, "failed to read response")
+	}
+
+	token := oidcTokenResponse{}
+	if err := json.Unmarshal(body, &token); err != nil && resp.StatusCode == http.StatusOK {
+		return errors.Wrap(err, "failed to unmarshal response")
+	}
+	switch {
+	case token.Error != "" && token.ErrorDescription != "":
+		return errors.Errorf("%s: %s", token.Error, token.ErrorDescription)
+	case token.Error != "":
+		return errors.New(token.Error)
+	case resp.StatusCode != http.StatusOK:
+		return errors.Errorf("unexpected status %s", resp.Status)
+	case token.AccessToken == "":
+		return errors.New("response has no access token")
+	}
+	return nil
+}
@@ -0,0 +1,156 @@
+package collect
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_collectOIDCProvider(t *testing.T) {
+	var server *httptest.Server
+	issuer := ""
+	server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/realms/apps/.well-known/openid-configuration":
+			_ = json.NewEncoder(w).Encode(map[string]string{
+				"issuer":                 issuer,
+				"authorization_endpoint": server.URL + "/realms/apps/auth",
+				"token_endpoint":         server.URL + "/realms/apps/token",
+				"jwks_uri":               server.URL + "/realms/apps/certs",
+			})
+		case "/realms/apps/certs":
+			_, _ = w.Write([]byte(`{"keys": [{"kid": "a", "kty": "RSA"}, {"kid": "b", "kty": "RSA"}]}`))
+		case "/realms/apps/token":
+			clientID, clientSecret, _ := r.BasicAuth()
+			if r.FormValue("grant_type") != "client_credentials" || clientID != "app" || clientSecret != "s3cr3t" {
+				w.WriteHeader(http.StatusUnauthorized)
+				_, _ = w.Write([]byte(`{"error": "invalid_client", "error_description": "Invalid client credentials"}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"access_token": "token", "token_type": "Bearer"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	roots := x509.NewCertPool()
+	roots.AddCert(server.Certificate())
+	trusted := &tls.Config{RootCAs: roots}
+
+	tests := []struct {
+		name         string
+		issuer       string
+		collector    troubleshootv1beta2.OIDC
+		clientSecret string
+		tlsConfig    *tls.Config
+		want         OIDCProvider
+	}{
+		{
+			name:      "certificate not trusted",
+			issuer:    server.URL + "/realms/apps",
+			collector: troubleshootv1beta2.OIDC{IssuerURL: server.URL + "/realms/apps", ClientID: "app"},
+			want: OIDCProvider{
+				IsConnected:      true,
+				CertificateError: "x509: certificate signed by unknown authority",
+			},
+		},
+		{
+			name:      "discovery only",
+			issuer:    server.URL + "/realms/apps",
+			collector: troubleshootv1beta2.OIDC{IssuerURL: server.URL + "/realms/apps/"},
+			tlsConfig: trusted,
+			want: OIDCProvider{
+				IsConnected:           true,
+				IsDiscovered:          true,
+				Issuer:                server.URL + "/realms/apps",
+				AuthorizationEndpoint: server.URL + "/realms/apps/auth",
+				TokenEndpoint:         server.URL + "/realms/apps/token",
+				JWKSURI:               server.URL + "/realms/apps/certs",
+				SigningKeys:           2,
+			},
+		},
+		{
+			name:         "token issued",
+			issuer:       server.URL + "/realms/apps",
+			collector:    troubleshootv1beta2.OIDC{IssuerURL: server.URL + "/realms/apps", ClientID: "app"},
+			clientSecret: "s3cr3t",
+			tlsConfig:    trusted,
+			want: OIDCProvider{
+				IsConnected:           true,
+				IsDiscovered:          true,
+				Issuer:                server.URL + "/realms/apps",
+				AuthorizationEndpoint: server.URL + "/realms/apps/auth",
+				TokenEndpoint:         server.URL + "/realms/apps/token",
+				JWKSURI:               server.URL + "/realms/apps/certs",
+				SigningKeys:           2,
+				TokenRequested:        true,
+				TokenIssued:           true,
+			},
+		},
+		{
+			name:         "token refused",
+			issuer:       server.URL + "/realms/apps",
+			collector:    troubleshootv1beta2.OIDC{IssuerURL: server.URL + "/realms/apps", ClientID: "app"},
+			clientSecret: "wrong",
+			tlsConfig:    trusted,
+			want: OIDCProvider{
+				IsConnected:           true,
+				IsDiscovered:          true,
+				Issuer:                server.URL + "/realms/apps",
+				AuthorizationEndpoint: server.URL + "/realms/apps/auth",
+				TokenEndpoint:         server.URL + "/realms/apps/token",
+				JWKSURI:               server.URL + "/realms/apps/certs",
+				SigningKeys:           2,
+				TokenRequested:        true,
+				TokenError:            "invalid_client: Invalid client credentials",
+			},
+		},
+		{
+			name:      "issuer mismatch",
+			issuer:    "https://login.example.com/realms/apps",
+			collector: troubleshootv1beta2.OIDC{IssuerURL: server.URL + "/realms/apps"},
+			tlsConfig: trusted,
+			want: OIDCProvider{
+				IsConnected:           true,
+				Error:                 `discovery document is of issuer "https://login.example.com/realms/apps", not "` + server.URL + `/realms/apps"`,
+				Issuer:                "https://login.example.com/realms/apps",
+				AuthorizationEndpoint: server.URL + "/realms/apps/auth",
+				TokenEndpoint:         server.URL + "/realms/apps/token",
+				JWKSURI:               server.URL + "/realms/apps/certs",
+			},
+		},
+		{
+			name:      "no discovery document",
+			collector: troubleshootv1beta2.OIDC{IssuerURL: server.URL + "/realms/other"},
+			tlsConfig: trusted,
+			want: OIDCProvider{
+				IsConnected: true,
+				Error:       "failed to read discovery document: unexpected status 404 Not Found",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issuer = tt.issuer
+			provider := OIDCProvider{}
+			collectOIDCProvider(context.Background(), &tt.collector, tt.clientSecret, tt.tlsConfig, 2*time.Second, &provider)
+
+			assert.NotNil(t, provider.TLS)
+			assert.Equal(t, "TLS 1.3", provider.TLS.Version)
+			provider.TLS = nil
+			provider.LatencyMilliseconds = 0
+			provider.TokenLatencyMilliseconds = 0
+			assert.Equal(t, tt.want, provider)
+		})
+	}
+}
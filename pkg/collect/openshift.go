@@ -0,0 +1,113 @@
+package collect
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/constants"
+	kuberneteserrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/klog/v2"
+)
+
+// openShiftResources are the cluster-scoped OpenShift resources collected, by the name of the
+// file they are saved to
+var openShiftResources = []struct {
+	name string
+	gvr  schema.GroupVersionResource
+}{
+	{constants.OPENSHIFT_CLUSTER_VERSIONS, schema.GroupVersionResource{Group: "config.openshift.io", Version: "v1", Resource: "clusterversions"}},
+	{constants.OPENSHIFT_CLUSTER_OPERATORS, schema.GroupVersionResource{Group: "config.openshift.io", Version: "v1", Resource: "clusteroperators"}},
+	{constants.OPENSHIFT_MACHINE_CONFIG_POOLS, schema.GroupVersionResource{Group: "machineconfiguration.openshift.io", Version: "v1", Resource: "machineconfigpools"}},
+	{constants.OPENSHIFT_MACHINE_CONFIGS, schema.GroupVersionResource{Group: "machineconfiguration.openshift.io", Version: "v1", Resource: "machineconfigs"}},
+	{constants.OPENSHIFT_SECURITY_CONTEXT_CONSTRAINTS, schema.GroupVersionResource{Group: "security.openshift.io", Version: "v1", Resource: "securitycontextconstraints"}},
+}
+
+// OpenShiftPath returns the path of the file an OpenShift collector saves a resource to
+func OpenShiftPath(collectorName string, resource string) string {
+	return filepath.Join(constants.OPENSHIFT_DIR, collectorName, resource+".json")
+}
+
+// CollectOpenShift saves the cluster-scoped resources of OpenShift that support cases need
+// without a must-gather. Resources that are not served, as on any other distribution, are
+// recorded in the errors file of the resource.
+type CollectOpenShift struct {
+	Collector    *troubleshootv1beta2.OpenShift
+	BundlePath   string
+	ClientConfig *rest.Config
+	Client       kubernetes.Interface
+	Context      context.Context
+	RBACErrors
+}
+
+func (c *CollectOpenShift) Title() string {
+	return getCollectorName(c)
+}
+
+func (c *CollectOpenShift) IsExcluded() (bool, error) {
+	return isExcluded(c.Collector.Exclude)
+}
+
+func (c *CollectOpenShift) Collect(progressChan chan<- interface{}) (CollectorResult, error) {
+	dynamicClient, err := dynamic.NewForConfig(c.ClientConfig)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create dynamic client")
+	}
+
+	return collectOpenShift(c.Context, dynamicClient, c.BundlePath, c.Collector.CollectorName), nil
+}
+
+func collectOpenShift(ctx context.Context, client dynamic.Interface, bundlePath string, collectorName string) CollectorResult {
+	output := NewResult()
+
+	for _, resource := range openShiftResources {
+		errorsPath := filepath.Join(constants.OPENSHIFT_DIR, collectorName, fmt.Sprintf("%s-errors.json", resource.name))
+
+		objects, err := listOpenShiftResource(ctx, client, resource.gvr)
+		if err != nil {
+			klog.V(2).Infof("failed to collect %s: %v", resource.name, err)
+			output.SaveResult(bundlePath, errorsPath, marshalErrors([]string{err.Error()}))
+			continue
+		}
+
+		b, err := json.MarshalIndent(objects, "", "  ")
+		if err != nil {
+			output.SaveResult(bundlePath, errorsPath, marshalErrors([]string{err.Error()}))
+			continue
+		}
+		output.SaveResult(bundlePath, OpenShiftPath(collectorName, resource.name), bytes.NewBuffer(b))
+	}
+
+	return output
+}
+
+// listOpenShiftResource lists the objects of the resource. The rendered files of MachineConfigs
+// are removed, they hold the contents of files written to the nodes, including pull secrets and
+// certificates.
+func listOpenShiftResource(ctx context.Context, client dynamic.Interface, gvr schema.GroupVersionResource) ([]map[string]interface{}, error) {
+	list, err := client.Resource(gvr).List(ctx, metav1.ListOptions{})
+	if kuberneteserrors.IsNotFound(err) {
+		return nil, errors.Errorf("resource %s.%s not found, this is not an OpenShift cluster", gvr.Resource, gvr.Group)
+	} else if err != nil {
+		return nil, errors.Wrapf(err, "failed to list %s.%s", gvr.Resource, gvr.Group)
+	}
+
+	objects := []map[string]interface{}{}
+	for _, item := range list.Items {
+		if gvr.Resource == "machineconfigs" {
+			unstructured.RemoveNestedField(item.Object, "spec", "config")
+		}
+		objects = append(objects, item.Object)
+	}
+	return objects, nil
+}
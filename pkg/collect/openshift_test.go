@@ -0,0 +1,66 @@
+package collect
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	kuberneteserrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	testdynamicclient "k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func Test_collectOpenShift(t *testing.T) {
+	listKinds := map[schema.GroupVersionResource]string{}
+	for _, resource := range openShiftResources {
+		listKinds[resource.gvr] = "List"
+	}
+
+	operator := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "config.openshift.io/v1",
+		"kind":       "ClusterOperator",
+		"metadata":   map[string]interface{}{"name": "ingress"},
+		"status": map[string]interface{}{
+			"conditions": []interface{}{
+				map[string]interface{}{"type": "Degraded", "status": "True"},
+			},
+		},
+	}}
+	machineConfig := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "machineconfiguration.openshift.io/v1",
+		"kind":       "MachineConfig",
+		"metadata":   map[string]interface{}{"name": "00-worker"},
+		"spec": map[string]interface{}{
+			"config":          map[string]interface{}{"storage": map[string]interface{}{"files": []interface{}{"pull-secret"}}},
+			"kernelArguments": []interface{}{"nosmt"},
+		},
+	}}
+
+	client := testdynamicclient.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), listKinds, operator, machineConfig)
+	client.PrependReactor("list", "securitycontextconstraints", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, kuberneteserrors.NewNotFound(schema.GroupResource{Group: "security.openshift.io", Resource: "securitycontextconstraints"}, "")
+	})
+
+	result := collectOpenShift(context.Background(), client, "", "")
+
+	operators := []map[string]interface{}{}
+	require.NoError(t, json.Unmarshal(result["openshift/clusteroperators.json"], &operators))
+	require.Len(t, operators, 1)
+	assert.Equal(t, "ingress", operators[0]["metadata"].(map[string]interface{})["name"])
+
+	machineConfigs := []map[string]interface{}{}
+	require.NoError(t, json.Unmarshal(result["openshift/machineconfigs.json"], &machineConfigs))
+	require.Len(t, machineConfigs, 1)
+	spec := machineConfigs[0]["spec"].(map[string]interface{})
+	assert.NotContains(t, spec, "config")
+	assert.Equal(t, []interface{}{"nosmt"}, spec["kernelArguments"])
+
+	assert.JSONEq(t, "[]", string(result["openshift/machineconfigpools.json"]))
+	assert.NotContains(t, result, "openshift/securitycontextconstraints.json")
+	assert.Contains(t, string(result["openshift/securitycontextconstraints-errors.json"]), "not an OpenShift cluster")
+}
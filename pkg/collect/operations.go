@@ -0,0 +1,335 @@
+package collect
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/gobwas/glob"
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/plugin"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Operation is a command a collector runs, or a file or directory it copies, in pods, on nodes or
+// on hosts
+type Operation struct {
+	// Type is the type of the collector, one of the troubleshootv1beta2.OperationType constants
+	Type string `json:"type"`
+	// Collector is the name of the collector, e.g. exec/pg-schema
+	Collector string `json:"collector"`
+	// Command is the command with its arguments, for the collectors that run commands
+	Command string `json:"command,omitempty"`
+	// Image is the image the command runs in, for the collectors that create pods
+	Image string `json:"image,omitempty"`
+	// Path is the copied file or directory, for the collectors that copy files
+	Path      string `json:"path,omitempty"`
+	Namespace string `json:"namespace,omitempty"`
+	// Hosts describes where the operation runs, e.g. pods matching app=api, all nodes, or the local
+	// host
+	Hosts string `json:"hosts"`
+}
+
+func (o Operation) String() string {
+	what := o.Command
+	if o.Path != "" {
+		what = o.Path
+	}
+	if what == "" {
+		what = "the default command"
+	}
+	if o.Image != "" {
+		what = fmt.Sprintf("%s (image %s)", what, o.Image)
+	}
+	return fmt.Sprintf("%s: %s on %s", o.Collector, what, o.Hosts)
+}
+
+// SpecOperations returns the commands the collectors and host collectors of a spec run, and the
+// files they copy. Collectors without a namespace run in defaultNamespace.
+func SpecOperations(spec *troubleshootv1beta2.SupportBundleSpec, defaultNamespace string) []Operation {
+	operations := []Operation{}
+	namespaceOrDefault := func(namespace string) string {
+		if namespace == "" {
+			return defaultNamespace
+		}
+		return namespace
+	}
+	containerOperations := func(operationType string, collector string, namespace string, hosts string, podSpec corev1.PodSpec) {
+		for _, container := range podSpec.Containers {
+			operations = append(operations, Operation{
+				Type:      operationType,
+				Collector: collector,
+				Command:   joinCommand(container.Command, container.Args),
+				Image:     container.Image,
+				Namespace: namespace,
+				Hosts:     hosts,
+			})
+		}
+	}
+
+	for _, c := range spec.Collectors {
+		if c == nil {
+			continue
+		}
+		switch {
+		case c.Exec != nil:
+			operations = append(operations, Operation{
+				Type:      troubleshootv1beta2.OperationTypeExec,
+				Collector: c.GetName(),
+				Command:   joinCommand(c.Exec.Command, c.Exec.Args),
+				Namespace: namespaceOrDefault(c.Exec.Namespace),
+				Hosts:     podsMatching(c.Exec.Selector, c.Exec.ContainerName),
+			})
+		case c.Run != nil:
+			operations = append(operations, Operation{
+				Type:      troubleshootv1beta2.OperationTypeRun,
+				Collector: c.GetName(),
+				Command:   joinCommand(c.Run.Command, c.Run.Args),
+				Image:     c.Run.Image,
+				Namespace: namespaceOrDefault(c.Run.Namespace),
+				Hosts:     "a new pod",
+			})
+		case c.RunPod != nil:
+			containerOperations(troubleshootv1beta2.OperationTypeRunPod, c.GetName(), namespaceOrDefault(c.RunPod.Namespace), "a new pod", c.RunPod.PodSpec)
+		case c.RunDaemonSet != nil:
+			containerOperations(troubleshootv1beta2.OperationTypeRunDaemonSet, c.GetName(), namespaceOrDefault(c.RunDaemonSet.Namespace), nodesMatching(c.RunDaemonSet.PodSpec.NodeSelector), c.RunDaemonSet.PodSpec)
+		case c.Copy != nil:
+			operations = append(operations, Operation{
+				Type:      troubleshootv1beta2.OperationTypeCopy,
+				Collector: c.GetName(),
+				Path:      c.Copy.ContainerPath,
+				Namespace: namespaceOrDefault(c.Copy.Namespace),
+				Hosts:     podsMatching(c.Copy.Selector, c.Copy.ContainerName),
+			})
+		case c.CopyFromHost != nil:
+			operations = append(operations, Operation{
+				Type:      troubleshootv1beta2.OperationTypeCopyFromHost,
+				Collector: c.GetName(),
+				Path:      c.CopyFromHost.HostPath,
+				Image:     c.CopyFromHost.Image,
+				Namespace: namespaceOrDefault(c.CopyFromHost.Namespace),
+				Hosts:     nodesMatching(c.CopyFromHost.NodeSelector),
+			})
+		case c.Plugin != nil:
+			// plugins are executables on the host of the CLI, run with the collect command
+			operations = append(operations, Operation{
+				Type:      troubleshootv1beta2.OperationTypeExec,
+				Collector: c.GetName(),
+				Command:   joinCommand([]string{plugin.ExecutablePrefix + c.Plugin.Name, plugin.CommandCollect}, c.Plugin.Args),
+				Hosts:     "the local host",
+			})
+		}
+	}
+
+	// host collectors run on the host of the CLI, or on every node when they run in pods
+	hosts := "the local host"
+	if spec.RunHostCollectorsInPod {
+		hosts = "all nodes"
+	}
+	for _, c := range spec.HostCollectors {
+		if c == nil {
+			continue
+		}
+		switch {
+		case c.HostRun != nil:
+			operations = append(operations, Operation{
+				Type:      troubleshootv1beta2.OperationTypeHostRun,
+				Collector: hostCollectorName("host-run", c.HostRun.CollectorName),
+				Command:   joinCommand([]string{c.HostRun.Command}, c.HostRun.Args),
+				Hosts:     hosts,
+			})
+		case c.HostCopy != nil:
+			operations = append(operations, Operation{
+				Type:      troubleshootv1beta2.OperationTypeHostCopy,
+				Collector: hostCollectorName("host-copy", c.HostCopy.CollectorName),
+				Path:      c.HostCopy.Path,
+				Hosts:     hosts,
+			})
+		}
+	}
+
+	return operations
+}
+
+func hostCollectorName(collector string, name string) string {
+	if name == "" {
+		return collector
+	}
+	return fmt.Sprintf("%s/%s", collector, name)
+}
+
+func joinCommand(command []string, args []string) string {
+	return strings.TrimSpace(strings.Join(append(append([]string{}, command...), args...), " "))
+}
+
+func podsMatching(selector []string, containerName string) string {
+	pods := fmt.Sprintf("pods matching %s", strings.Join(selector, ","))
+	if containerName != "" {
+		pods = fmt.Sprintf("container %s of %s", containerName, pods)
+	}
+	return pods
+}
+
+func nodesMatching(nodeSelector map[string]string) string {
+	if len(nodeSelector) == 0 {
+		return "all nodes"
+	}
+	selector := []string{}
+	for key, value := range nodeSelector {
+		selector = append(selector, fmt.Sprintf("%s=%s", key, value))
+	}
+	sort.Strings(selector)
+	return fmt.Sprintf("nodes matching %s", strings.Join(selector, ","))
+}
+
+// CheckAllowedOperations returns an error listing the operations the spec does not declare in its
+// allowed operations. All operations are allowed when the spec declares none.
+func CheckAllowedOperations(spec *troubleshootv1beta2.SupportBundleSpec, operations []Operation) error {
+	if len(spec.AllowedOperations) == 0 {
+		return nil
+	}
+
+	allowed := make([]*allowedOperationMatcher, 0, len(spec.AllowedOperations))
+	for i, allowedOperation := range spec.AllowedOperations {
+		matcher, err := newAllowedOperationMatcher(allowedOperation)
+		if err != nil {
+			return errors.Wrapf(err, "allowedOperations %d", i)
+		}
+		allowed = append(allowed, matcher)
+	}
+
+	disallowed := []string{}
+	for _, operation := range operations {
+		isAllowed := false
+		for _, matcher := range allowed {
+			if matcher.matches(operation) {
+				isAllowed = true
+				break
+			}
+		}
+		if !isAllowed {
+			disallowed = append(disallowed, operation.String())
+		}
+	}
+	if len(disallowed) > 0 {
+		return errors.Errorf("the spec does not allow the operations %s", strings.Join(disallowed, "; "))
+	}
+	return nil
+}
+
+type allowedOperationMatcher struct {
+	operationType string
+	commands      []glob.Glob
+	paths         []glob.Glob
+	namespaces    []glob.Glob
+}
+
+func newAllowedOperationMatcher(allowed *troubleshootv1beta2.AllowedOperation) (*allowedOperationMatcher, error) {
+	switch allowed.Type {
+	case troubleshootv1beta2.OperationTypeExec, troubleshootv1beta2.OperationTypeRun, troubleshootv1beta2.OperationTypeRunPod,
+		troubleshootv1beta2.OperationTypeRunDaemonSet, troubleshootv1beta2.OperationTypeCopy, troubleshootv1beta2.OperationTypeCopyFromHost,
+		troubleshootv1beta2.OperationTypeHostRun, troubleshootv1beta2.OperationTypeHostCopy:
+	default:
+		return nil, errors.Errorf("unknown type %q", allowed.Type)
+	}
+
+	compile := func(patterns []string) ([]glob.Glob, error) {
+		globs := []glob.Glob{}
+		for _, pattern := range patterns {
+			g, err := glob.Compile(pattern)
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid pattern %q", pattern)
+			}
+			globs = append(globs, g)
+		}
+		return globs, nil
+	}
+
+	matcher := &allowedOperationMatcher{operationType: allowed.Type}
+	var err error
+	if matcher.commands, err = compile(allowed.Commands); err != nil {
+		return nil, err
+	}
+	if matcher.paths, err = compile(allowed.Paths); err != nil {
+		return nil, err
+	}
+	if matcher.namespaces, err = compile(allowed.Namespaces); err != nil {
+		return nil, err
+	}
+	return matcher, nil
+}
+
+func (m *allowedOperationMatcher) matches(operation Operation) bool {
+	matchesAny := func(globs []glob.Glob, value string) bool {
+		if len(globs) == 0 {
+			return true
+		}
+		for _, g := range globs {
+			if g.Match(value) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if operation.Type != m.operationType {
+		return false
+	}
+	if operation.Command != "" && !matchesAny(m.commands, operation.Command) {
+		return false
+	}
+	if operation.Path != "" && !matchesAny(m.paths, operation.Path) {
+		return false
+	}
+	if operation.Namespace != "" && !matchesAny(m.namespaces, operation.Namespace) {
+		return false
+	}
+	return true
+}
+
+// FormatOperations summarizes the operations for the consent of the user: the commands, the
+// copied files, the namespaces and the hosts they run on
+func FormatOperations(operations []Operation) string {
+	commands, paths, namespaces, hosts := []string{}, []string{}, []string{}, []string{}
+	addUnique := func(list []string, value string) []string {
+		if slices.Contains(list, value) {
+			return list
+		}
+		return append(list, value)
+	}
+
+	for _, operation := range operations {
+		if operation.Path != "" {
+			paths = append(paths, operation.String())
+		} else {
+			commands = append(commands, operation.String())
+		}
+		if operation.Namespace != "" {
+			namespaces = addUnique(namespaces, operation.Namespace)
+		}
+		hosts = addUnique(hosts, operation.Hosts)
+	}
+	sort.Strings(namespaces)
+
+	b := strings.Builder{}
+	b.WriteString("The support bundle runs commands and copies files in the cluster and on hosts.\n")
+	if len(commands) > 0 {
+		b.WriteString("Commands:\n")
+		for _, command := range commands {
+			b.WriteString(fmt.Sprintf("  %s\n", command))
+		}
+	}
+	if len(paths) > 0 {
+		b.WriteString("Copied files:\n")
+		for _, path := range paths {
+			b.WriteString(fmt.Sprintf("  %s\n", path))
+		}
+	}
+	if len(namespaces) > 0 {
+		b.WriteString(fmt.Sprintf("Namespaces: %s\n", strings.Join(namespaces, ", ")))
+	}
+	b.WriteString(fmt.Sprintf("Hosts: %s\n", strings.Join(hosts, ", ")))
+	return b.String()
+}
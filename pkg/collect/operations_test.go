@@ -0,0 +1,135 @@
+package collect
+
+import (
+	"testing"
+
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func operationsSpec() *troubleshootv1beta2.SupportBundleSpec {
+	return &troubleshootv1beta2.SupportBundleSpec{
+		Collectors: []*troubleshootv1beta2.Collect{
+			{ClusterResources: &troubleshootv1beta2.ClusterResources{}},
+			{Exec: &troubleshootv1beta2.Exec{
+				CollectorMeta: troubleshootv1beta2.CollectorMeta{CollectorName: "pg-schema"},
+				Selector:      []string{"app=postgres"},
+				Command:       []string{"pg_dump"},
+				Args:          []string{"--schema-only", "app"},
+			}},
+			{RunPod: &troubleshootv1beta2.RunPod{
+				CollectorMeta: troubleshootv1beta2.CollectorMeta{CollectorName: "dns"},
+				Namespace:     "kube-system",
+				PodSpec: corev1.PodSpec{Containers: []corev1.Container{
+					{Image: "busybox:1", Command: []string{"nslookup", "kubernetes.default"}},
+				}},
+			}},
+			{CopyFromHost: &troubleshootv1beta2.CopyFromHost{
+				CollectorMeta: troubleshootv1beta2.CollectorMeta{CollectorName: "kubelet-logs"},
+				Image:         "alpine:3",
+				HostPath:      "/var/log/kubelet",
+				PodScheduling: troubleshootv1beta2.PodScheduling{NodeSelector: map[string]string{"node-role.kubernetes.io/worker": ""}},
+			}},
+			{Plugin: &troubleshootv1beta2.PluginCollector{
+				Name: "vault",
+				Args: []string{"--address", "https://vault:8200"},
+			}},
+		},
+		HostCollectors: []*troubleshootv1beta2.HostCollect{
+			{HostRun: &troubleshootv1beta2.HostRun{
+				HostCollectorMeta: troubleshootv1beta2.HostCollectorMeta{CollectorName: "ip-route"},
+				Command:           "ip",
+				Args:              []string{"route"},
+			}},
+		},
+	}
+}
+
+func TestSpecOperations(t *testing.T) {
+	operations := SpecOperations(operationsSpec(), "app")
+
+	assert.Equal(t, []Operation{
+		{Type: "exec", Collector: "exec/pg-schema", Command: "pg_dump --schema-only app", Namespace: "app", Hosts: "pods matching app=postgres"},
+		{Type: "runPod", Collector: "run-pod/dns", Command: "nslookup kubernetes.default", Image: "busybox:1", Namespace: "kube-system", Hosts: "a new pod"},
+		{Type: "copyFromHost", Collector: "copy-from-host/kubelet-logs", Path: "/var/log/kubelet", Image: "alpine:3", Namespace: "app", Hosts: "nodes matching node-role.kubernetes.io/worker="},
+		{Type: "exec", Collector: "plugin/vault", Command: "troubleshoot-plugin-vault collect --address https://vault:8200", Hosts: "the local host"},
+		{Type: "hostRun", Collector: "host-run/ip-route", Command: "ip route", Hosts: "the local host"},
+	}, operations)
+
+	assert.Equal(t, `The support bundle runs commands and copies files in the cluster and on hosts.
+Commands:
+  exec/pg-schema: pg_dump --schema-only app on pods matching app=postgres
+  run-pod/dns: nslookup kubernetes.default (image busybox:1) on a new pod
+  plugin/vault: troubleshoot-plugin-vault collect --address https://vault:8200 on the local host
+  host-run/ip-route: ip route on the local host
+Copied files:
+  copy-from-host/kubelet-logs: /var/log/kubelet (image alpine:3) on nodes matching node-role.kubernetes.io/worker=
+Namespaces: app, kube-system
+Hosts: pods matching app=postgres, a new pod, nodes matching node-role.kubernetes.io/worker=, the local host
+`, FormatOperations(operations))
+}
+
+func TestCheckAllowedOperations(t *testing.T) {
+	allowAll := []*troubleshootv1beta2.AllowedOperation{
+		{Type: "exec", Commands: []string{"pg_dump --schema-only *", "troubleshoot-plugin-vault collect *"}, Namespaces: []string{"app"}},
+		{Type: "runPod", Commands: []string{"nslookup *"}, Namespaces: []string{"kube-*"}},
+		{Type: "copyFromHost", Paths: []string{"/var/log/*"}},
+		{Type: "hostRun", Commands: []string{"ip route", "ip addr"}},
+	}
+
+	tests := []struct {
+		name    string
+		allowed []*troubleshootv1beta2.AllowedOperation
+		wantErr string
+	}{
+		{
+			name: "nothing declared",
+		},
+		{
+			name:    "all declared",
+			allowed: allowAll,
+		},
+		{
+			name:    "undeclared operations",
+			allowed: allowAll[:2],
+			wantErr: "the spec does not allow the operations copy-from-host/kubelet-logs: /var/log/kubelet (image alpine:3) on nodes matching node-role.kubernetes.io/worker=; host-run/ip-route: ip route on the local host",
+		},
+		{
+			name: "command not declared",
+			allowed: append([]*troubleshootv1beta2.AllowedOperation{
+				{Type: "exec", Commands: []string{"pg_dump --data-only *"}},
+			}, allowAll[1:]...),
+			wantErr: "the spec does not allow the operations exec/pg-schema: pg_dump --schema-only app on pods matching app=postgres; " +
+				"plugin/vault: troubleshoot-plugin-vault collect --address https://vault:8200 on the local host",
+		},
+		{
+			name: "plugin not declared",
+			allowed: append([]*troubleshootv1beta2.AllowedOperation{
+				{Type: "exec", Commands: []string{"pg_dump --schema-only *"}},
+			}, allowAll[1:]...),
+			wantErr: "the spec does not allow the operations plugin/vault: troubleshoot-plugin-vault collect --address https://vault:8200 on the local host",
+		},
+		{
+			name:    "unknown type",
+			allowed: []*troubleshootv1beta2.AllowedOperation{{Type: "kubectl"}},
+			wantErr: `allowedOperations 0: unknown type "kubectl"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := operationsSpec()
+			spec.AllowedOperations = tt.allowed
+
+			err := CheckAllowedOperations(spec, SpecOperations(spec, "app"))
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Equal(t, tt.wantErr, err.Error())
+		})
+	}
+}
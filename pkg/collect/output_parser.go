@@ -0,0 +1,169 @@
+package collect
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/troubleshoot/internal/util"
+	"sigs.k8s.io/yaml"
+)
+
+// The formats of the parse hint of the exec and run collectors
+const (
+	ParseFormatJSON     = "json"
+	ParseFormatYAML     = "yaml"
+	ParseFormatKeyValue = "keyvalue"
+	ParseFormatTable    = "table"
+)
+
+// ParseCommandOutput normalizes the output of a command to JSON for analyzers to query it:
+//
+//   - json: a document, or a list of the documents of JSON lines output
+//   - yaml: a document, or a list of the documents of multi-document output
+//   - keyvalue: an object of the key=value or key: value lines, e.g. of /etc/os-release. Blank
+//     lines, comments and lines without a separator are skipped.
+//   - table: a list of objects keyed by the columns of the header line, e.g. of kubectl get or
+//     ps. Columns are separated by whitespace and the last one takes the rest of the line.
+func ParseCommandOutput(format string, data []byte) ([]byte, error) {
+	var parsed interface{}
+	var err error
+	switch format {
+	case ParseFormatJSON:
+		parsed, err = parseJSONOutput(data)
+	case ParseFormatYAML:
+		parsed, err = parseYAMLOutput(data)
+	case ParseFormatKeyValue:
+		parsed = parseKeyValueOutput(data)
+	case ParseFormatTable:
+		parsed = parseTableOutput(data)
+	default:
+		return nil, errors.Errorf("unsupported parse format %q, must be one of json, yaml, keyvalue or table", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return json.MarshalIndent(parsed, "", "  ")
+}
+
+// saveParsedOutput saves the output of a command in the format of the parse hint of its
+// collector as JSON. Failures to parse are returned for the collector to record them with its
+// errors, the raw output is always saved.
+func saveParsedOutput(output CollectorResult, bundlePath string, relativePath string, format string, data []byte) error {
+	parsed, err := ParseCommandOutput(format, data)
+	if err != nil {
+		return errors.Wrapf(err, "failed to parse output as %s", format)
+	}
+	return output.SaveResult(bundlePath, relativePath, bytes.NewBuffer(parsed))
+}
+
+func parseJSONOutput(data []byte) (interface{}, error) {
+	documents := []interface{}{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	for {
+		var document interface{}
+		if err := decoder.Decode(&document); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, errors.Wrap(err, "failed to decode json")
+		}
+		documents = append(documents, document)
+	}
+	return singleOrList(documents)
+}
+
+func parseYAMLOutput(data []byte) (interface{}, error) {
+	documents := []interface{}{}
+	for _, doc := range util.SplitYAML(string(data)) {
+		doc = strings.TrimPrefix(strings.TrimSpace(doc), "---")
+		if strings.TrimSpace(doc) == "" {
+			continue
+		}
+		b, err := yaml.YAMLToJSON([]byte(doc))
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to decode yaml")
+		}
+		decoder := json.NewDecoder(bytes.NewReader(b))
+		decoder.UseNumber()
+		var document interface{}
+		if err := decoder.Decode(&document); err != nil {
+			return nil, errors.Wrap(err, "failed to decode yaml")
+		}
+		documents = append(documents, document)
+	}
+	return singleOrList(documents)
+}
+
+func singleOrList(documents []interface{}) (interface{}, error) {
+	switch len(documents) {
+	case 0:
+		return nil, errors.New("the output has no document")
+	case 1:
+		return documents[0], nil
+	}
+	return documents, nil
+}
+
+func parseKeyValueOutput(data []byte) map[string]string {
+	values := map[string]string{}
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		i := strings.IndexAny(line, "=:")
+		if i <= 0 {
+			continue
+		}
+		value := strings.TrimSpace(line[i+1:])
+		if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+			value = value[1 : len(value)-1]
+		}
+		values[strings.TrimSpace(line[:i])] = value
+	}
+	return values
+}
+
+func parseTableOutput(data []byte) []map[string]string {
+	rows := []map[string]string{}
+	var columns []string
+	for _, line := range strings.Split(string(data), "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		if columns == nil {
+			columns = strings.Fields(line)
+			continue
+		}
+		fields := splitFields(line, len(columns))
+		row := map[string]string{}
+		for i, field := range fields {
+			row[columns[i]] = field
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// splitFields splits a line around runs of whitespace into at most n fields, the last of which
+// is the rest of the line
+func splitFields(line string, n int) []string {
+	fields := []string{}
+	line = strings.TrimSpace(line)
+	for line != "" && len(fields) < n-1 {
+		i := strings.IndexAny(line, " \t")
+		if i < 0 {
+			break
+		}
+		fields = append(fields, line[:i])
+		line = strings.TrimLeft(line[i:], " \t")
+	}
+	if line != "" {
+		fields = append(fields, line)
+	}
+	return fields
+}
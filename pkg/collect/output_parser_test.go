@@ -0,0 +1,107 @@
+package collect
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCommandOutput(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  string
+		output  string
+		want    string
+		wantErr string
+	}{
+		{
+			name:   "json document",
+			format: "json",
+			output: `{"replicas": 3, "ready": true, "size": 12345678901234567890}`,
+			want:   `{"ready": true, "replicas": 3, "size": 12345678901234567890}`,
+		},
+		{
+			name:   "json lines",
+			format: "json",
+			output: "{\"level\":\"info\"}\n{\"level\":\"error\"}\n",
+			want:   `[{"level": "info"}, {"level": "error"}]`,
+		},
+		{
+			name:    "invalid json",
+			format:  "json",
+			output:  "Error: connection refused",
+			wantErr: "failed to decode json",
+		},
+		{
+			name:    "empty json",
+			format:  "json",
+			output:  "\n",
+			wantErr: "the output has no document",
+		},
+		{
+			name:   "yaml document",
+			format: "yaml",
+			output: "---\nname: etcd\nmembers:\n  - etcd-0\n  - etcd-1\n",
+			want:   `{"name": "etcd", "members": ["etcd-0", "etcd-1"]}`,
+		},
+		{
+			name:   "yaml documents",
+			format: "yaml",
+			output: "name: a\n---\nname: b\n---\n",
+			want:   `[{"name": "a"}, {"name": "b"}]`,
+		},
+		{
+			name:   "key values",
+			format: "keyvalue",
+			output: "# os-release\nNAME=\"Ubuntu\"\nVERSION_ID='22.04'\n\nMode: leader\nignored line\nurl=https://example.com/a=b\n",
+			want:   `{"NAME": "Ubuntu", "VERSION_ID": "22.04", "Mode": "leader", "url": "https://example.com/a=b"}`,
+		},
+		{
+			name:   "table",
+			format: "table",
+			output: "NAME      READY   STATUS             AGE   MESSAGE\nweb-0     1/1     Running            2d\nworker-0  0/1     CrashLoopBackOff   5m    back-off 5m0s restarting\n",
+			want: `[
+				{"NAME": "web-0", "READY": "1/1", "STATUS": "Running", "AGE": "2d"},
+				{"NAME": "worker-0", "READY": "0/1", "STATUS": "CrashLoopBackOff", "AGE": "5m", "MESSAGE": "back-off 5m0s restarting"}
+			]`,
+		},
+		{
+			name:   "table without rows",
+			format: "table",
+			output: "NAME   READY\n",
+			want:   `[]`,
+		},
+		{
+			name:    "unsupported format",
+			format:  "csv",
+			output:  "a,b",
+			wantErr: `unsupported parse format "csv"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseCommandOutput(tt.format, []byte(tt.output))
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.want, string(got))
+		})
+	}
+}
+
+func TestSaveParsedOutput(t *testing.T) {
+	output := NewResult()
+
+	err := saveParsedOutput(output, "", "exec/ns/pod/version-parsed.json", "keyvalue", []byte("version=1.2.3\n"))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"version": "1.2.3"}`, string(output["exec/ns/pod/version-parsed.json"]))
+
+	err = saveParsedOutput(output, "", "exec/ns/pod/status-parsed.json", "json", []byte("not json"))
+	assert.ErrorContains(t, err, "failed to parse output as json")
+	assert.NotContains(t, output, "exec/ns/pod/status-parsed.json")
+}
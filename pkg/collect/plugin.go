@@ -0,0 +1,77 @@
+package collect
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/plugin"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+type CollectPlugin struct {
+	Collector    *troubleshootv1beta2.PluginCollector
+	BundlePath   string
+	Namespace    string
+	ClientConfig *rest.Config
+	Client       kubernetes.Interface
+	Context      context.Context
+	RBACErrors
+}
+
+// PluginOutputDir is the directory of the bundle where the files of a plugin collector are saved.
+func PluginOutputDir(collectorName string) string {
+	return filepath.Join("plugins", collectorName)
+}
+
+func (c *CollectPlugin) Title() string {
+	return getCollectorName(c)
+}
+
+func (c *CollectPlugin) IsExcluded() (bool, error) {
+	return isExcluded(c.Collector.Exclude)
+}
+
+func (c *CollectPlugin) Collect(progressChan chan<- interface{}) (CollectorResult, error) {
+	var timeout time.Duration
+	if c.Collector.Timeout != "" {
+		var err error
+		timeout, err = time.ParseDuration(c.Collector.Timeout)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to parse timeout")
+		}
+	}
+
+	request := plugin.Request{
+		Command:   plugin.CommandCollect,
+		Config:    c.Collector.Config,
+		Namespace: c.Namespace,
+	}
+	response := plugin.CollectResponse{}
+	if err := plugin.Run(c.Context, c.Collector.Name, c.Collector.Args, timeout, request, &response); err != nil {
+		return nil, err
+	}
+
+	collectorName := c.Collector.CollectorName
+	if collectorName == "" {
+		collectorName = c.Collector.Name
+	}
+	outputDir := PluginOutputDir(collectorName)
+
+	output := NewResult()
+	for name, data := range response.Files {
+		if !filepath.IsLocal(name) {
+			progressChan <- errors.Errorf("plugin %s returned file %q outside of its directory", c.Collector.Name, name)
+			continue
+		}
+		if err := output.SaveResult(c.BundlePath, filepath.Join(outputDir, name), bytes.NewBuffer(data)); err != nil {
+			return output, errors.Wrapf(err, "failed to save %s", name)
+		}
+	}
+
+	return output, nil
+}
@@ -0,0 +1,47 @@
+package collect
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/plugin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollectPlugin_Collect(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("plugin scripts need a POSIX shell")
+	}
+
+	pluginDir := t.TempDir()
+	// files are base64 encoded: "ok" and "outside"
+	script := "#!/bin/sh\ncat > /dev/null\necho '{\"files\":{\"status/health.txt\":\"b2s=\",\"../escape.txt\":\"b3V0c2lkZQ==\"}}'\n"
+	require.NoError(t, os.WriteFile(filepath.Join(pluginDir, plugin.ExecutablePrefix+"acme"), []byte(script), 0755))
+	t.Setenv(plugin.PathEnvVar, pluginDir)
+
+	bundlePath := t.TempDir()
+	c := &CollectPlugin{
+		Collector: &troubleshootv1beta2.PluginCollector{
+			CollectorMeta: troubleshootv1beta2.CollectorMeta{CollectorName: "acme-app"},
+			Name:          "acme",
+		},
+		BundlePath: bundlePath,
+		Context:    context.Background(),
+	}
+	assert.Equal(t, "plugin/acme-app", c.Title())
+
+	progressChan := make(chan interface{}, 1)
+	result, err := c.Collect(progressChan)
+	require.NoError(t, err)
+	assert.Equal(t, CollectorResult{"plugins/acme-app/status/health.txt": nil}, result)
+	assert.EqualError(t, (<-progressChan).(error), `plugin acme returned file "../escape.txt" outside of its directory`)
+
+	data, err := os.ReadFile(filepath.Join(bundlePath, "plugins/acme-app/status/health.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "ok", string(data))
+}
@@ -0,0 +1,361 @@
+package collect
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	corev1 "k8s.io/api/core/v1"
+	kuberneteserrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/klog/v2"
+)
+
+const (
+	PolicyEngineKyverno    = "kyverno"
+	PolicyEngineGatekeeper = "gatekeeper"
+
+	// PolicyActionDeny is the action of the violations that are enforced: the failed and errored
+	// results of Kyverno, and the violations of Gatekeeper constraints enforced with deny
+	PolicyActionDeny = "deny"
+	// PolicyActionWarn is the action of the violations that are only reported: the warned results
+	// of Kyverno, and the violations of Gatekeeper constraints enforced with warn or dryrun
+	PolicyActionWarn = "warn"
+)
+
+var (
+	policyReportsGVR        = schema.GroupVersionResource{Group: "wgpolicyk8s.io", Version: "v1alpha2", Resource: "policyreports"}
+	clusterPolicyReportsGVR = schema.GroupVersionResource{Group: "wgpolicyk8s.io", Version: "v1alpha2", Resource: "clusterpolicyreports"}
+	gatekeeperConstraintsGV = schema.GroupVersion{Group: "constraints.gatekeeper.sh", Version: "v1beta1"}
+)
+
+// PolicyViolationsInfo is the output of the policyViolations collector
+type PolicyViolationsInfo struct {
+	// Engines are the policy engines whose reports were found
+	Engines    []string          `json:"engines"`
+	Violations []PolicyViolation `json:"violations"`
+	Errors     []string          `json:"errors,omitempty"`
+}
+
+// PolicyViolation is a resource that violates a policy. Gatekeeper records at most the number of
+// violations of its audit limit, 20 by default, in the status of each constraint.
+type PolicyViolation struct {
+	Engine string `json:"engine"`
+	// Policy is the name of the Kyverno policy, or of the Gatekeeper constraint
+	Policy string `json:"policy"`
+	// Rule is the rule of the Kyverno policy, or the kind of the Gatekeeper constraint
+	Rule string `json:"rule,omitempty"`
+	// Action is either deny or warn
+	Action string `json:"action"`
+	// Result is the result of Kyverno, or the enforcement action of Gatekeeper
+	Result    string `json:"result"`
+	Severity  string `json:"severity,omitempty"`
+	Kind      string `json:"kind,omitempty"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name,omitempty"`
+	Message   string `json:"message,omitempty"`
+}
+
+// policyReport is a PolicyReport or ClusterPolicyReport of the policy working group. Reports are
+// either of a single resource, their scope, or list the resources of each result.
+type policyReport struct {
+	metav1.ObjectMeta `json:"metadata"`
+	Scope             *corev1.ObjectReference `json:"scope,omitempty"`
+	Results           []policyReportResult    `json:"results,omitempty"`
+}
+
+type policyReportResult struct {
+	Source    string                   `json:"source,omitempty"`
+	Policy    string                   `json:"policy"`
+	Rule      string                   `json:"rule,omitempty"`
+	Result    string                   `json:"result,omitempty"`
+	Severity  string                   `json:"severity,omitempty"`
+	Message   string                   `json:"message,omitempty"`
+	Resources []corev1.ObjectReference `json:"resources,omitempty"`
+}
+
+// gatekeeperConstraint is a constraint of any kind, with the violations of its last audit
+type gatekeeperConstraint struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+	Spec              struct {
+		EnforcementAction string `json:"enforcementAction,omitempty"`
+	} `json:"spec"`
+	Status struct {
+		Violations []gatekeeperConstraintViolation `json:"violations,omitempty"`
+	} `json:"status"`
+}
+
+type gatekeeperConstraintViolation struct {
+	EnforcementAction string `json:"enforcementAction,omitempty"`
+	Kind              string `json:"kind,omitempty"`
+	Namespace         string `json:"namespace,omitempty"`
+	Name              string `json:"name,omitempty"`
+	Message           string `json:"message,omitempty"`
+}
+
+type CollectPolicyViolations struct {
+	Collector    *troubleshootv1beta2.PolicyViolations
+	BundlePath   string
+	Namespace    string
+	ClientConfig *rest.Config
+	Client       kubernetes.Interface
+	Context      context.Context
+	RBACErrors
+}
+
+func (c *CollectPolicyViolations) Title() string {
+	return getCollectorName(c)
+}
+
+func (c *CollectPolicyViolations) IsExcluded() (bool, error) {
+	return isExcluded(c.Collector.Exclude)
+}
+
+func (c *CollectPolicyViolations) Collect(progressChan chan<- interface{}) (CollectorResult, error) {
+	for _, engine := range c.Collector.Engines {
+		if engine != PolicyEngineKyverno && engine != PolicyEngineGatekeeper {
+			return nil, errors.Errorf("unsupported policy engine %q, must be one of %s or %s", engine, PolicyEngineKyverno, PolicyEngineGatekeeper)
+		}
+	}
+
+	dynamicClient, err := dynamic.NewForConfig(c.ClientConfig)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create dynamic client")
+	}
+
+	info := collectPolicyViolations(collectorContext(c.Context), c.Client.Discovery(), dynamicClient, c.Collector.Engines, c.Collector.Namespaces)
+
+	b, err := json.MarshalIndent(info, "", "  ")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal policy violations")
+	}
+
+	output := NewResult()
+	output.SaveResult(c.BundlePath, PolicyViolationsOutputPath(c.Collector.CollectorName), bytes.NewBuffer(b))
+
+	return output, nil
+}
+
+// PolicyViolationsOutputPath returns the path of the file collected by the policyViolations
+// collector of a name
+func PolicyViolationsOutputPath(collectorName string) string {
+	if collectorName == "" {
+		collectorName = "policy-violations"
+	}
+	return filepath.Join("policy-violations", fmt.Sprintf("%s.json", collectorName))
+}
+
+// collectPolicyViolations collects the violations of the engines, all of them when none is set,
+// of the resources in the namespaces, or in all namespaces when none is set. Engines that are not
+// installed are skipped, and recorded in the errors only when they were set.
+func collectPolicyViolations(ctx context.Context, discoveryClient discovery.DiscoveryInterface, dynamicClient dynamic.Interface, engines []string, namespaces []string) *PolicyViolationsInfo {
+	info := &PolicyViolationsInfo{
+		Engines:    []string{},
+		Violations: []PolicyViolation{},
+	}
+	addError := func(err error) {
+		klog.V(2).Infof("policy violations collector: %v", err)
+		info.Errors = append(info.Errors, err.Error())
+	}
+
+	selected := func(engine string) bool {
+		return len(engines) == 0 || slices.Contains(engines, engine)
+	}
+	inNamespaces := func(namespace string) bool {
+		return namespace == "" || len(namespaces) == 0 || slices.Contains(namespaces, namespace)
+	}
+
+	if selected(PolicyEngineKyverno) {
+		violations, installed, err := collectKyvernoViolations(ctx, dynamicClient)
+		if installed {
+			info.Engines = append(info.Engines, PolicyEngineKyverno)
+		}
+		if err != nil && (installed || len(engines) > 0) {
+			addError(err)
+		}
+		for _, violation := range violations {
+			if inNamespaces(violation.Namespace) {
+				info.Violations = append(info.Violations, violation)
+			}
+		}
+	}
+
+	if selected(PolicyEngineGatekeeper) {
+		violations, installed, err := collectGatekeeperViolations(ctx, discoveryClient, dynamicClient)
+		if installed {
+			info.Engines = append(info.Engines, PolicyEngineGatekeeper)
+		}
+		if err != nil && (installed || len(engines) > 0) {
+			addError(err)
+		}
+		for _, violation := range violations {
+			if inNamespaces(violation.Namespace) {
+				info.Violations = append(info.Violations, violation)
+			}
+		}
+	}
+
+	sort.SliceStable(info.Violations, func(i, j int) bool {
+		a, b := info.Violations[i], info.Violations[j]
+		if a.Engine != b.Engine {
+			return a.Engine < b.Engine
+		}
+		if a.Policy != b.Policy {
+			return a.Policy < b.Policy
+		}
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Name < b.Name
+	})
+
+	return info
+}
+
+// collectKyvernoViolations returns the failed, errored and warned results of the PolicyReports and
+// ClusterPolicyReports, and whether the reports are served
+func collectKyvernoViolations(ctx context.Context, dynamicClient dynamic.Interface) ([]PolicyViolation, bool, error) {
+	violations := []PolicyViolation{}
+	for _, gvr := range []schema.GroupVersionResource{policyReportsGVR, clusterPolicyReportsGVR} {
+		list, err := dynamicClient.Resource(gvr).List(ctx, metav1.ListOptions{})
+		if kuberneteserrors.IsNotFound(err) {
+			return nil, false, errors.Errorf("resource %s.%s not found, Kyverno is not installed", gvr.Resource, gvr.Group)
+		} else if err != nil {
+			return violations, true, errors.Wrapf(err, "failed to list %s.%s", gvr.Resource, gvr.Group)
+		}
+
+		for _, item := range list.Items {
+			report := policyReport{}
+			if err := runtime.DefaultUnstructuredConverter.FromUnstructured(item.Object, &report); err != nil {
+				return violations, true, errors.Wrapf(err, "failed to convert %s.%s %s", gvr.Resource, gvr.Group, item.GetName())
+			}
+			violations = append(violations, policyReportViolations(report)...)
+		}
+	}
+	return violations, true, nil
+}
+
+// policyReportViolations returns a violation for each resource of the results of the report that
+// failed, errored or warned
+func policyReportViolations(report policyReport) []PolicyViolation {
+	violations := []PolicyViolation{}
+	for _, result := range report.Results {
+		action := ""
+		switch result.Result {
+		case "fail", "error":
+			action = PolicyActionDeny
+		case "warn":
+			action = PolicyActionWarn
+		default:
+			continue
+		}
+
+		engine := strings.ToLower(result.Source)
+		if engine == "" {
+			engine = PolicyEngineKyverno
+		}
+
+		resources := result.Resources
+		if len(resources) == 0 && report.Scope != nil {
+			resources = []corev1.ObjectReference{*report.Scope}
+		}
+		if len(resources) == 0 {
+			resources = []corev1.ObjectReference{{}}
+		}
+
+		for _, resource := range resources {
+			violations = append(violations, PolicyViolation{
+				Engine:    engine,
+				Policy:    result.Policy,
+				Rule:      result.Rule,
+				Action:    action,
+				Result:    result.Result,
+				Severity:  result.Severity,
+				Kind:      resource.Kind,
+				Namespace: resource.Namespace,
+				Name:      resource.Name,
+				Message:   result.Message,
+			})
+		}
+	}
+	return violations
+}
+
+// collectGatekeeperViolations returns the violations of the constraints of every kind, and whether
+// the constraints are served
+func collectGatekeeperViolations(ctx context.Context, discoveryClient discovery.DiscoveryInterface, dynamicClient dynamic.Interface) ([]PolicyViolation, bool, error) {
+	resources, err := discoveryClient.ServerResourcesForGroupVersion(gatekeeperConstraintsGV.String())
+	if kuberneteserrors.IsNotFound(err) {
+		return nil, false, errors.Errorf("group %s not found, Gatekeeper is not installed", gatekeeperConstraintsGV.Group)
+	} else if err != nil {
+		return nil, false, errors.Wrapf(err, "failed to discover resources of %s", gatekeeperConstraintsGV)
+	}
+
+	violations := []PolicyViolation{}
+	for _, resource := range resources.APIResources {
+		if strings.Contains(resource.Name, "/") {
+			continue
+		}
+
+		gvr := gatekeeperConstraintsGV.WithResource(resource.Name)
+		list, err := dynamicClient.Resource(gvr).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return violations, true, errors.Wrapf(err, "failed to list %s.%s", gvr.Resource, gvr.Group)
+		}
+
+		for _, item := range list.Items {
+			constraint := gatekeeperConstraint{}
+			if err := runtime.DefaultUnstructuredConverter.FromUnstructured(item.Object, &constraint); err != nil {
+				return violations, true, errors.Wrapf(err, "failed to convert %s.%s %s", gvr.Resource, gvr.Group, item.GetName())
+			}
+			violations = append(violations, gatekeeperConstraintViolations(constraint)...)
+		}
+	}
+	return violations, true, nil
+}
+
+// gatekeeperConstraintViolations returns the violations of the last audit of the constraint
+func gatekeeperConstraintViolations(constraint gatekeeperConstraint) []PolicyViolation {
+	violations := []PolicyViolation{}
+	for _, violation := range constraint.Status.Violations {
+		enforcementAction := violation.EnforcementAction
+		if enforcementAction == "" {
+			enforcementAction = constraint.Spec.EnforcementAction
+		}
+		if enforcementAction == "" {
+			enforcementAction = PolicyActionDeny
+		}
+
+		action := PolicyActionWarn
+		if enforcementAction == PolicyActionDeny {
+			action = PolicyActionDeny
+		}
+
+		violations = append(violations, PolicyViolation{
+			Engine:    PolicyEngineGatekeeper,
+			Policy:    constraint.Name,
+			Rule:      constraint.Kind,
+			Action:    action,
+			Result:    enforcementAction,
+			Kind:      violation.Kind,
+			Namespace: violation.Namespace,
+			Name:      violation.Name,
+			Message:   violation.Message,
+		})
+	}
+	return violations
+}
@@ -0,0 +1,117 @@
+package collect
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	kuberneteserrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	testdynamicclient "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func policyReportObject(kind string, name string, namespace string, scope map[string]interface{}, results ...interface{}) *unstructured.Unstructured {
+	object := map[string]interface{}{
+		"apiVersion": "wgpolicyk8s.io/v1alpha2",
+		"kind":       kind,
+		"metadata": map[string]interface{}{
+			"name":      name,
+			"namespace": namespace,
+		},
+		"results": results,
+	}
+	if scope != nil {
+		object["scope"] = scope
+	}
+	return &unstructured.Unstructured{Object: object}
+}
+
+func Test_collectPolicyViolations(t *testing.T) {
+	constraintsGVR := gatekeeperConstraintsGV.WithResource("k8spspprivilegedcontainers")
+	listKinds := map[schema.GroupVersionResource]string{
+		policyReportsGVR:        "PolicyReportList",
+		clusterPolicyReportsGVR: "ClusterPolicyReportList",
+		constraintsGVR:          "K8sPSPPrivilegedContainerList",
+	}
+
+	dynamicClient := testdynamicclient.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), listKinds,
+		// a report of a single resource, as Kyverno 1.10 and later write them
+		policyReportObject("PolicyReport", "a1b2", "app", map[string]interface{}{"apiVersion": "apps/v1", "kind": "Deployment", "namespace": "app", "name": "api"},
+			map[string]interface{}{"policy": "require-limits", "rule": "memory", "result": "fail", "severity": "medium", "message": "memory limit is required"},
+			map[string]interface{}{"policy": "disallow-latest", "rule": "tag", "result": "pass"},
+			map[string]interface{}{"policy": "require-probes", "rule": "readiness", "result": "warn", "message": "readiness probe is recommended"},
+		),
+		policyReportObject("PolicyReport", "c3d4", "kube-system", map[string]interface{}{"kind": "Pod", "namespace": "kube-system", "name": "coredns"},
+			map[string]interface{}{"policy": "require-limits", "rule": "memory", "result": "fail"},
+		),
+		// a report listing the resources of each result
+		policyReportObject("ClusterPolicyReport", "cpol-require-owner", "", nil,
+			map[string]interface{}{"policy": "require-owner", "result": "error", "source": "Kyverno", "resources": []interface{}{
+				map[string]interface{}{"kind": "Namespace", "name": "app"},
+				map[string]interface{}{"kind": "Namespace", "name": "legacy"},
+			}},
+		),
+		&unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "constraints.gatekeeper.sh/v1beta1",
+			"kind":       "K8sPSPPrivilegedContainer",
+			"metadata":   map[string]interface{}{"name": "psp-privileged-container"},
+			"spec":       map[string]interface{}{"enforcementAction": "dryrun"},
+			"status": map[string]interface{}{
+				"totalViolations": int64(2),
+				"violations": []interface{}{
+					map[string]interface{}{"kind": "Pod", "namespace": "app", "name": "debug", "message": "Privileged container is not allowed: shell"},
+					map[string]interface{}{"kind": "Pod", "namespace": "kube-system", "name": "kube-proxy-x7d2q", "message": "Privileged container is not allowed: kube-proxy"},
+				},
+			},
+		}},
+	)
+
+	client := fake.NewSimpleClientset()
+	client.Discovery().(*fakediscovery.FakeDiscovery).Resources = []*metav1.APIResourceList{
+		{
+			GroupVersion: gatekeeperConstraintsGV.String(),
+			APIResources: []metav1.APIResource{
+				{Name: "k8spspprivilegedcontainers", Kind: "K8sPSPPrivilegedContainer"},
+				{Name: "k8spspprivilegedcontainers/status", Kind: "K8sPSPPrivilegedContainer"},
+			},
+		},
+	}
+
+	info := collectPolicyViolations(context.Background(), client.Discovery(), dynamicClient, nil, []string{"app"})
+
+	assert.Equal(t, []string{PolicyEngineKyverno, PolicyEngineGatekeeper}, info.Engines)
+	assert.Empty(t, info.Errors)
+	assert.Equal(t, []PolicyViolation{
+		{Engine: "gatekeeper", Policy: "psp-privileged-container", Rule: "K8sPSPPrivilegedContainer", Action: "warn", Result: "dryrun", Kind: "Pod", Namespace: "app", Name: "debug", Message: "Privileged container is not allowed: shell"},
+		{Engine: "kyverno", Policy: "require-limits", Rule: "memory", Action: "deny", Result: "fail", Severity: "medium", Kind: "Deployment", Namespace: "app", Name: "api", Message: "memory limit is required"},
+		{Engine: "kyverno", Policy: "require-owner", Action: "deny", Result: "error", Kind: "Namespace", Name: "app"},
+		{Engine: "kyverno", Policy: "require-owner", Action: "deny", Result: "error", Kind: "Namespace", Name: "legacy"},
+		{Engine: "kyverno", Policy: "require-probes", Rule: "readiness", Action: "warn", Result: "warn", Kind: "Deployment", Namespace: "app", Name: "api", Message: "readiness probe is recommended"},
+	}, info.Violations)
+}
+
+func Test_collectPolicyViolations_notInstalled(t *testing.T) {
+	dynamicClient := testdynamicclient.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		policyReportsGVR: "PolicyReportList",
+	})
+	dynamicClient.PrependReactor("list", "policyreports", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, kuberneteserrors.NewNotFound(schema.GroupResource{Group: "wgpolicyk8s.io", Resource: "policyreports"}, "")
+	})
+	client := fake.NewSimpleClientset()
+
+	info := collectPolicyViolations(context.Background(), client.Discovery(), dynamicClient, nil, nil)
+	assert.Empty(t, info.Engines)
+	assert.Empty(t, info.Violations)
+	assert.Empty(t, info.Errors, "engines that are not installed are skipped unless they are set")
+
+	info = collectPolicyViolations(context.Background(), client.Discovery(), dynamicClient, []string{PolicyEngineGatekeeper}, nil)
+	require.Len(t, info.Errors, 1)
+	assert.Contains(t, info.Errors[0], "Gatekeeper is not installed")
+}
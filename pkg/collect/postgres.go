@@ -36,7 +36,11 @@ func (c *CollectPostgres) IsExcluded() (bool, error) {
 }
 
 func (c *CollectPostgres) createConnectConfig() (*pgx.ConnConfig, error) {
-	if c.Collector.URI == "" {
+	uri, err := databaseURI(c.Context, c.Client, c.Collector)
+	if err != nil {
+		return nil, err
+	}
+	if uri == "" {
 		return nil, errors.New("postgres uri cannot be empty")
 	}
 
@@ -103,7 +107,7 @@ func (c *CollectPostgres) createConnectConfig() (*pgx.ConnConfig, error) {
 		}
 	}
 
-	cfg, err := pgx.ParseConfig(c.Collector.URI)
+	cfg, err := pgx.ParseConfig(uri)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to parse postgres config")
 	}
@@ -152,6 +156,23 @@ func (c *CollectPostgres) Collect(progressChan chan<- interface{}) (CollectorRes
 			} else {
 				databaseConnection.Version = postgresVersion
 			}
+
+			parameters := c.Collector.Parameters
+			if len(parameters) == 0 && c.Collector.Diagnostics {
+				parameters = defaultPostgresParameters
+			}
+			if len(parameters) > 0 {
+				variables, err := postgresParameters(c.Context, conn, parameters)
+				if err != nil {
+					databaseConnection.Error = err.Error()
+				} else {
+					databaseConnection.Variables = variables
+				}
+			}
+
+			if c.Collector.Diagnostics {
+				databaseConnection.Diagnostics = postgresDiagnostics(c.Context, conn)
+			}
 		}
 	}
 
@@ -171,6 +192,66 @@ func (c *CollectPostgres) Collect(progressChan chan<- interface{}) (CollectorRes
 	return output, nil
 }
 
+// defaultPostgresParameters are the configuration parameters collected with diagnostics
+var defaultPostgresParameters = []string{
+	"max_connections",
+	"shared_buffers",
+	"work_mem",
+	"effective_cache_size",
+	"wal_level",
+	"max_wal_senders",
+	"synchronous_commit",
+}
+
+// postgresSlowQuerySeconds is how long a query runs before it is counted as slow
+const postgresSlowQuerySeconds = 5
+
+func postgresParameters(ctx context.Context, conn *pgx.Conn, parameters []string) (map[string]string, error) {
+	rows, err := conn.Query(ctx, `select name, setting from pg_settings where name = any($1)`, parameters)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query settings")
+	}
+	defer rows.Close()
+
+	variables := map[string]string{}
+	for rows.Next() {
+		var name, setting string
+		if err := rows.Scan(&name, &setting); err != nil {
+			return nil, errors.Wrap(err, "failed to scan setting")
+		}
+		variables[name] = setting
+	}
+	return variables, rows.Err()
+}
+
+func postgresDiagnostics(ctx context.Context, conn *pgx.Conn) *DatabaseDiagnostics {
+	diagnostics := &DatabaseDiagnostics{}
+
+	if err := conn.QueryRow(ctx, `select pg_is_in_recovery()`).Scan(&diagnostics.IsReplica); err != nil {
+		diagnostics.addError("replication status", err)
+	} else if diagnostics.IsReplica {
+		// the lag is null until the replica has replayed a transaction
+		query := `select extract(epoch from now() - pg_last_xact_replay_timestamp())::float8`
+		if err := conn.QueryRow(ctx, query).Scan(&diagnostics.ReplicationLagSeconds); err != nil {
+			diagnostics.addError("replication lag", err)
+		}
+	}
+
+	if err := conn.QueryRow(ctx, `select count(*) from pg_stat_activity where backend_type = 'client backend'`).Scan(&diagnostics.Connections); err != nil {
+		diagnostics.addError("connections", err)
+	}
+	if err := conn.QueryRow(ctx, `select current_setting('max_connections')::int`).Scan(&diagnostics.MaxConnections); err != nil {
+		diagnostics.addError("max connections", err)
+	}
+
+	query := `select count(*) from pg_stat_activity where state = 'active' and now() - query_start > make_interval(secs => $1)`
+	if err := conn.QueryRow(ctx, query, postgresSlowQuerySeconds).Scan(&diagnostics.SlowQueries); err != nil {
+		diagnostics.addError("slow queries", err)
+	}
+
+	return diagnostics
+}
+
 func parsePostgresVersion(postgresVersion string) (string, error) {
 	re := regexp.MustCompile("PostgreSQL ([0-9.]*)")
 	matches := re.FindStringSubmatch(postgresVersion)
@@ -0,0 +1,276 @@
+package collect
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/k8sutil"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/klog/v2"
+)
+
+const (
+	defaultPprofPort           = 6060
+	defaultPprofPath           = "/debug/pprof"
+	defaultPprofProfileSeconds = 10
+	maxPprofProfileSeconds     = 60
+	defaultPprofTimeout        = 30 * time.Second
+	// maxPprofErrorBody bounds the response body kept in the error of a failed request
+	maxPprofErrorBody = 512
+)
+
+// pprofProfiles are the profiles served by net/http/pprof that are fetched as protobuf
+var pprofProfiles = []string{"goroutine", "heap", "allocs", "profile", "block", "mutex", "threadcreate"}
+
+var defaultPprofProfiles = []string{"goroutine", "heap", "profile"}
+
+type CollectPprof struct {
+	Collector    *troubleshootv1beta2.Pprof
+	BundlePath   string
+	Namespace    string
+	ClientConfig *rest.Config
+	Client       kubernetes.Interface
+	Context      context.Context
+	// PortForwards is the pool of the run, a pool of the collector is used when it is nil
+	PortForwards *k8sutil.PortForwardPool
+	RBACErrors
+}
+
+func (c *CollectPprof) Title() string {
+	return getCollectorName(c)
+}
+
+func (c *CollectPprof) SetPortForwardPool(pool *k8sutil.PortForwardPool) {
+	c.PortForwards = pool
+}
+
+func (c *CollectPprof) IsExcluded() (bool, error) {
+	return isExcluded(c.Collector.Exclude)
+}
+
+// Collect saves the profiles of each running pod as pprof/<collector>/<namespace>/<pod>/<profile>.pb.gz,
+// and the profiles that could not be fetched in errors.json next to them.
+func (c *CollectPprof) Collect(progressChan chan<- interface{}) (CollectorResult, error) {
+	profiles, profileSeconds, timeout, err := pprofOptions(c.Collector)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := collectorContext(c.Context)
+	namespace := c.Collector.Namespace
+	if namespace == "" {
+		namespace = c.Namespace
+	}
+
+	output := NewResult()
+	outputDir := PprofOutputDir(c.Collector.CollectorName)
+
+	client, headers, err := c.httpClient(ctx)
+	if err != nil {
+		output.SaveResult(c.BundlePath, filepath.Join(outputDir, "errors.json"), marshalErrors([]string{err.Error()}))
+		return output, nil
+	}
+
+	pods, err := c.Client.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: strings.Join(c.Collector.Selector, ","),
+	})
+	if err != nil {
+		output.SaveResult(c.BundlePath, filepath.Join(outputDir, "errors.json"), marshalErrors([]string{err.Error()}))
+		return output, nil
+	}
+
+	pool := c.PortForwards
+	if pool == nil {
+		pool, err = k8sutil.NewPortForwardPool(c.ClientConfig)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to create port-forward pool")
+		}
+		defer pool.Close()
+	}
+
+	port := c.Collector.Port
+	if port == 0 {
+		port = defaultPprofPort
+	}
+	scheme := "http"
+	if c.Collector.TLS != nil {
+		scheme = "https"
+	}
+	path := c.Collector.Path
+	if path == "" {
+		path = defaultPprofPath
+	}
+
+	for _, pod := range pods.Items {
+		if ctx.Err() != nil {
+			break
+		}
+
+		podDir := filepath.Join(outputDir, pod.Namespace, pod.Name)
+		if pod.Status.Phase != corev1.PodRunning {
+			err := fmt.Sprintf("pod is %s, not running", pod.Status.Phase)
+			output.SaveResult(c.BundlePath, filepath.Join(podDir, "errors.json"), marshalErrors([]string{err}))
+			continue
+		}
+
+		klog.V(2).Infof("Fetching pprof profiles of pod %s/%s", pod.Namespace, pod.Name)
+		target := k8sutil.PortForwardTarget{Namespace: pod.Namespace, Pod: pod.Name, Port: port}
+		localPort, release, err := pool.Forward(ctx, target)
+		if err != nil {
+			output.SaveResult(c.BundlePath, filepath.Join(podDir, "errors.json"), marshalErrors([]string{err.Error()}))
+			continue
+		}
+
+		baseURL := fmt.Sprintf("%s://localhost:%d%s", scheme, localPort, strings.TrimSuffix(path, "/"))
+		fetched, fetchErrors := fetchPprofProfiles(ctx, client, baseURL, profiles, profileSeconds, timeout, headers)
+		release()
+
+		for _, profile := range profiles {
+			if data, ok := fetched[profile]; ok {
+				output.SaveResult(c.BundlePath, filepath.Join(podDir, profile+".pb.gz"), bytes.NewBuffer(data))
+			}
+		}
+		if len(fetchErrors) > 0 {
+			output.SaveResult(c.BundlePath, filepath.Join(podDir, "errors.json"), marshalErrors(fetchErrors))
+		}
+	}
+
+	return output, nil
+}
+
+// httpClient returns the client of the requests to the pprof endpoints and their headers, with the
+// token of the bearer token secret
+func (c *CollectPprof) httpClient(ctx context.Context) (*http.Client, map[string]string, error) {
+	headers := map[string]string{}
+	for name, value := range c.Collector.Headers {
+		headers[name] = value
+	}
+
+	if secret := c.Collector.BearerTokenSecret; secret != nil {
+		token, err := readSecretKey(ctx, c.Client, secret.Namespace, secret.Name, secret.Key)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "failed to read bearer token")
+		}
+		headers["Authorization"] = "Bearer " + strings.TrimSpace(token)
+	}
+
+	transport := &http.Transport{}
+	if c.Collector.TLS != nil {
+		tlsConfig, err := createTLSConfig(ctx, c.Client, c.Collector.TLS)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "failed to create tls config")
+		}
+		transport.TLSClientConfig = tlsConfig
+	}
+
+	return &http.Client{Transport: transport}, headers, nil
+}
+
+// PprofOutputDir returns the directory of the profiles collected by the pprof collector of a name
+func PprofOutputDir(collectorName string) string {
+	if collectorName == "" {
+		collectorName = "pprof"
+	}
+	return filepath.Join("pprof", collectorName)
+}
+
+// pprofOptions returns the profiles of a collector, the duration of its CPU profile in seconds
+// and the timeout of its requests, with their defaults
+func pprofOptions(collector *troubleshootv1beta2.Pprof) ([]string, int, time.Duration, error) {
+	profiles := collector.Profiles
+	if len(profiles) == 0 {
+		profiles = defaultPprofProfiles
+	}
+	for _, profile := range profiles {
+		if !slices.Contains(pprofProfiles, profile) {
+			return nil, 0, 0, errors.Errorf("unsupported profile %q, must be one of %s", profile, strings.Join(pprofProfiles, ", "))
+		}
+	}
+
+	seconds := collector.ProfileSeconds
+	if seconds == 0 {
+		seconds = defaultPprofProfileSeconds
+	}
+	if seconds < 0 || seconds > maxPprofProfileSeconds {
+		return nil, 0, 0, errors.Errorf("profileSeconds %d must be between 1 and %d", seconds, maxPprofProfileSeconds)
+	}
+
+	timeout := defaultPprofTimeout
+	if collector.Timeout != "" {
+		var err error
+		timeout, err = time.ParseDuration(collector.Timeout)
+		if err != nil {
+			return nil, 0, 0, errors.Wrapf(err, "failed to parse timeout %q", collector.Timeout)
+		}
+	}
+
+	return profiles, seconds, timeout, nil
+}
+
+// fetchPprofProfiles fetches the profiles under the base URL of the pprof endpoints, and returns
+// them by profile with the errors of the profiles that could not be fetched. The CPU profile is
+// sampled for profileSeconds, which is added to its timeout.
+func fetchPprofProfiles(ctx context.Context, client *http.Client, baseURL string, profiles []string, profileSeconds int, timeout time.Duration, headers map[string]string) (map[string][]byte, []string) {
+	fetched := map[string][]byte{}
+	fetchErrors := []string{}
+
+	for _, profile := range profiles {
+		url := fmt.Sprintf("%s/%s", baseURL, profile)
+		profileTimeout := timeout
+		if profile == "profile" {
+			url = fmt.Sprintf("%s?seconds=%d", url, profileSeconds)
+			profileTimeout += time.Duration(profileSeconds) * time.Second
+		}
+
+		data, err := fetchPprofProfile(ctx, client, url, profileTimeout, headers)
+		if err != nil {
+			fetchErrors = append(fetchErrors, fmt.Sprintf("%s: %v", profile, err))
+			continue
+		}
+		fetched[profile] = data
+	}
+
+	return fetched, fetchErrors
+}
+
+func fetchPprofProfile(ctx context.Context, client *http.Client, url string, timeout time.Duration, headers map[string]string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create request")
+	}
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxPprofErrorBody))
+		return nil, errors.Errorf("unexpected status %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read profile")
+	}
+	return data, nil
+}
@@ -0,0 +1,72 @@
+package collect
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_pprofOptions(t *testing.T) {
+	profiles, seconds, timeout, err := pprofOptions(&troubleshootv1beta2.Pprof{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"goroutine", "heap", "profile"}, profiles)
+	assert.Equal(t, 10, seconds)
+	assert.Equal(t, 30*time.Second, timeout)
+
+	profiles, seconds, timeout, err = pprofOptions(&troubleshootv1beta2.Pprof{
+		Profiles:       []string{"mutex", "block"},
+		ProfileSeconds: 60,
+		Timeout:        "1m",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"mutex", "block"}, profiles)
+	assert.Equal(t, 60, seconds)
+	assert.Equal(t, time.Minute, timeout)
+
+	_, _, _, err = pprofOptions(&troubleshootv1beta2.Pprof{Profiles: []string{"trace"}})
+	assert.ErrorContains(t, err, `unsupported profile "trace"`)
+	_, _, _, err = pprofOptions(&troubleshootv1beta2.Pprof{ProfileSeconds: 61})
+	assert.ErrorContains(t, err, "profileSeconds 61 must be between 1 and 60")
+	_, _, _, err = pprofOptions(&troubleshootv1beta2.Pprof{Timeout: "soon"})
+	assert.ErrorContains(t, err, `failed to parse timeout "soon"`)
+}
+
+func Test_fetchPprofProfiles(t *testing.T) {
+	requests := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL.String())
+		if r.Header.Get("Authorization") != "Bearer s3cr3t" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/debug/pprof/goroutine":
+			w.Write([]byte("goroutine profile"))
+		case "/debug/pprof/profile":
+			w.Write([]byte("cpu profile"))
+		default:
+			http.Error(w, "Unknown profile", http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	headers := map[string]string{"Authorization": "Bearer s3cr3t"}
+	fetched, fetchErrors := fetchPprofProfiles(context.Background(), server.Client(), server.URL+"/debug/pprof", []string{"goroutine", "profile", "mutex"}, 2, time.Second, headers)
+
+	assert.Equal(t, map[string][]byte{
+		"goroutine": []byte("goroutine profile"),
+		"profile":   []byte("cpu profile"),
+	}, fetched)
+	assert.Equal(t, []string{"mutex: unexpected status 404 Not Found: Unknown profile"}, fetchErrors)
+	assert.Equal(t, []string{"/debug/pprof/goroutine", "/debug/pprof/profile?seconds=2", "/debug/pprof/mutex"}, requests)
+
+	fetched, fetchErrors = fetchPprofProfiles(context.Background(), server.Client(), server.URL+"/debug/pprof", []string{"heap"}, 2, time.Second, nil)
+	assert.Empty(t, fetched)
+	assert.Equal(t, []string{"heap: unexpected status 401 Unauthorized: "}, fetchErrors)
+}
@@ -3,12 +3,10 @@ package collect
 import (
 	"context"
 	"encoding/base64"
-	"fmt"
 	"testing"
 
-	"github.com/containers/image/v5/transports/alltransports"
-	"github.com/containers/image/v5/types"
 	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/registry"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes/fake"
@@ -163,7 +161,7 @@ func TestPrivateRegistryAuthConfigParity(t *testing.T) {
 	}
 
 	// Test both auth config extraction functions with the same data
-	imageRef, err := parseImageReference("private-registry.io/user/app:latest")
+	imageRef, err := registry.ParseImageReference("private-registry.io/user/app:latest")
 	if err != nil {
 		t.Fatalf("Failed to parse image reference: %v", err)
 	}
@@ -173,7 +171,7 @@ func TestPrivateRegistryAuthConfigParity(t *testing.T) {
 		ImagePullSecrets: imagePullSecrets,
 	}
 
-	registryAuthConfig, err := getImageAuthConfig("default", &rest.Config{}, registryCollector, imageRef)
+	registryAuthConfig, err := registry.ResolveAuthConfig(context.Background(), &rest.Config{}, "default", registryCollector, imageRef)
 	if err != nil {
 		t.Errorf("Registry collector auth config failed: %v", err)
 	}
@@ -183,7 +181,7 @@ func TestPrivateRegistryAuthConfigParity(t *testing.T) {
 		ImagePullSecrets: imagePullSecrets,
 	}
 
-	signaturesAuthConfig, err := getImageAuthConfigGeneric("default", &rest.Config{}, signaturesCollector, imageRef)
+	signaturesAuthConfig, err := registry.ResolveAuthConfig(context.Background(), &rest.Config{}, "default", signaturesCollector, imageRef)
 	if err != nil {
 		t.Errorf("Signatures collector auth config failed: %v", err)
 	}
@@ -199,15 +197,10 @@ func TestPrivateRegistryAuthConfigParity(t *testing.T) {
 		return
 	}
 
-	if registryAuthConfig.username != signaturesAuthConfig.username ||
-		registryAuthConfig.password != signaturesAuthConfig.password {
+	if registryAuthConfig.Username != signaturesAuthConfig.Username ||
+		registryAuthConfig.Password != signaturesAuthConfig.Password {
 		t.Errorf("Auth configs differ: registry={%s:%s}, signatures={%s:%s}",
-			registryAuthConfig.username, registryAuthConfig.password,
-			signaturesAuthConfig.username, signaturesAuthConfig.password)
+			registryAuthConfig.Username, registryAuthConfig.Password,
+			signaturesAuthConfig.Username, signaturesAuthConfig.Password)
 	}
 }
-
-// Helper function to parse image reference consistently
-func parseImageReference(image string) (types.ImageReference, error) {
-	return alltransports.ParseImageName(fmt.Sprintf("docker://%s", image))
-}
@@ -0,0 +1,232 @@
+package collect
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+const defaultRabbitMQTimeout = 10 * time.Second
+
+type CollectRabbitMQ struct {
+	Collector    *troubleshootv1beta2.RabbitMQ
+	BundlePath   string
+	Namespace    string
+	ClientConfig *rest.Config
+	Client       kubernetes.Interface
+	Context      context.Context
+	RBACErrors
+}
+
+// RabbitMQCluster is the state of a RabbitMQ cluster as reported by its management API
+type RabbitMQCluster struct {
+	IsConnected bool            `json:"isConnected"`
+	Error       string          `json:"error,omitempty"`
+	ClusterName string          `json:"clusterName,omitempty"`
+	Version     string          `json:"version,omitempty"`
+	Nodes       []RabbitMQNode  `json:"nodes,omitempty"`
+	Queues      []RabbitMQQueue `json:"queues,omitempty"`
+}
+
+type RabbitMQNode struct {
+	Name          string `json:"name"`
+	Running       bool   `json:"running"`
+	MemoryAlarm   bool   `json:"memoryAlarm"`
+	DiskFreeAlarm bool   `json:"diskFreeAlarm"`
+	// Partitions are the nodes this node cannot reach since a network partition
+	Partitions []string `json:"partitions,omitempty"`
+}
+
+type RabbitMQQueue struct {
+	Name  string `json:"name"`
+	VHost string `json:"vhost"`
+	// Messages is the number of messages ready for delivery and unacknowledged
+	Messages               int64  `json:"messages"`
+	MessagesUnacknowledged int64  `json:"messagesUnacknowledged"`
+	Consumers              int    `json:"consumers"`
+	State                  string `json:"state,omitempty"`
+}
+
+// rabbitMQOverview, rabbitMQNodeResponse and rabbitMQQueueResponse are the fields of the
+// responses of the management API the collector reads
+type rabbitMQOverview struct {
+	ClusterName     string `json:"cluster_name"`
+	RabbitMQVersion string `json:"rabbitmq_version"`
+}
+
+type rabbitMQNodeResponse struct {
+	Name          string   `json:"name"`
+	Running       bool     `json:"running"`
+	MemAlarm      bool     `json:"mem_alarm"`
+	DiskFreeAlarm bool     `json:"disk_free_alarm"`
+	Partitions    []string `json:"partitions"`
+}
+
+type rabbitMQQueueResponse struct {
+	Name                   string `json:"name"`
+	VHost                  string `json:"vhost"`
+	Messages               int64  `json:"messages"`
+	MessagesUnacknowledged int64  `json:"messages_unacknowledged"`
+	Consumers              int    `json:"consumers"`
+	State                  string `json:"state"`
+}
+
+func (c *CollectRabbitMQ) Title() string {
+	return getCollectorName(c)
+}
+
+func (c *CollectRabbitMQ) IsExcluded() (bool, error) {
+	return isExcluded(c.Collector.Exclude)
+}
+
+func (c *CollectRabbitMQ) createClient() (*http.Client, error) {
+	timeout := defaultRabbitMQTimeout
+	if c.Collector.Timeout != "" {
+		var err error
+		timeout, err = time.ParseDuration(c.Collector.Timeout)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse timeout %q", c.Collector.Timeout)
+		}
+	}
+
+	transport := &http.Transport{}
+	if c.Collector.TLS != nil {
+		tlsCfg, err := createTLSConfig(c.Context, c.Client, c.Collector.TLS)
+		if err != nil {
+			return nil, err
+		}
+		transport.TLSClientConfig = tlsCfg
+	}
+
+	return &http.Client{Timeout: timeout, Transport: transport}, nil
+}
+
+func (c *CollectRabbitMQ) Collect(progressChan chan<- interface{}) (CollectorResult, error) {
+	cluster := RabbitMQCluster{}
+
+	uri := c.Collector.URI
+	var err error
+	if c.Collector.URISecret != nil {
+		uri, err = readURISecret(c.Context, c.Client, c.Collector.URISecret)
+	}
+	if err != nil {
+		cluster.Error = err.Error()
+	} else if client, err := c.createClient(); err != nil {
+		cluster.Error = err.Error()
+	} else {
+		collectRabbitMQCluster(c.Context, client, uri, &cluster)
+	}
+
+	b, err := json.Marshal(cluster)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal rabbitmq cluster")
+	}
+
+	collectorName := c.Collector.CollectorName
+	if collectorName == "" {
+		collectorName = "rabbitmq"
+	}
+
+	output := NewResult()
+	output.SaveResult(c.BundlePath, fmt.Sprintf("rabbitmq/%s.json", collectorName), bytes.NewBuffer(b))
+
+	return output, nil
+}
+
+// collectRabbitMQCluster reads the overview, the nodes and the queues from the management API at
+// the URI into the cluster. The credentials of the URI are sent with basic auth.
+func collectRabbitMQCluster(ctx context.Context, client *http.Client, uri string, cluster *RabbitMQCluster) {
+	overview := rabbitMQOverview{}
+	if err := getRabbitMQ(ctx, client, uri, "overview", &overview); err != nil {
+		cluster.Error = err.Error()
+		return
+	}
+	cluster.IsConnected = true
+	cluster.ClusterName = overview.ClusterName
+	cluster.Version = overview.RabbitMQVersion
+
+	nodes := []rabbitMQNodeResponse{}
+	if err := getRabbitMQ(ctx, client, uri, "nodes", &nodes); err != nil {
+		cluster.Error = err.Error()
+		return
+	}
+	for _, node := range nodes {
+		cluster.Nodes = append(cluster.Nodes, RabbitMQNode{
+			Name:          node.Name,
+			Running:       node.Running,
+			MemoryAlarm:   node.MemAlarm,
+			DiskFreeAlarm: node.DiskFreeAlarm,
+			Partitions:    node.Partitions,
+		})
+	}
+	sort.Slice(cluster.Nodes, func(i, j int) bool {
+		return cluster.Nodes[i].Name < cluster.Nodes[j].Name
+	})
+
+	queues := []rabbitMQQueueResponse{}
+	if err := getRabbitMQ(ctx, client, uri, "queues", &queues); err != nil {
+		cluster.Error = err.Error()
+		return
+	}
+	for _, queue := range queues {
+		cluster.Queues = append(cluster.Queues, RabbitMQQueue(queue))
+	}
+	sort.Slice(cluster.Queues, func(i, j int) bool {
+		if cluster.Queues[i].VHost != cluster.Queues[j].VHost {
+			return cluster.Queues[i].VHost < cluster.Queues[j].VHost
+		}
+		return cluster.Queues[i].Name < cluster.Queues[j].Name
+	})
+}
+
+// getRabbitMQ decodes the response of an endpoint of the management API, e.g. nodes for /api/nodes,
+// into v
+func getRabbitMQ(ctx context.Context, client *http.Client, uri string, endpoint string, v interface{}) error {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return errors.Wrap(err, "failed to parse uri")
+	}
+	user := u.User
+	u.User = nil
+	u.Path = path.Join("/", u.Path, "api", endpoint)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to create request")
+	}
+	if user != nil {
+		password, _ := user.Password()
+		req.SetBasicAuth(user.Username(), password)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get %s", endpoint)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return errors.Wrapf(err, "failed to read %s", endpoint)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return errors.Errorf("failed to get %s: %s", endpoint, resp.Status)
+	}
+
+	if err := json.Unmarshal(body, v); err != nil {
+		return errors.Wrapf(err, "failed to unmarshal %s", endpoint)
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package collect
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_collectRabbitMQCluster(t *testing.T) {
+	responses := map[string]string{
+		"/rabbitmq/api/overview": `{"cluster_name": "rabbit@rabbitmq-0", "rabbitmq_version": "3.13.7"}`,
+		"/rabbitmq/api/nodes": `[
+		  {"name": "rabbit@rabbitmq-1", "running": false, "mem_alarm": false, "disk_free_alarm": false, "partitions": []},
+		  {"name": "rabbit@rabbitmq-0", "running": true, "mem_alarm": true, "disk_free_alarm": false, "partitions": ["rabbit@rabbitmq-1"]}
+		]`,
+		"/rabbitmq/api/queues": `[
+		  {"name": "orders", "vhost": "/", "messages": 12000, "messages_unacknowledged": 20, "consumers": 0, "state": "running"},
+		  {"name": "emails", "vhost": "/", "consumers": 2}
+		]`,
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, password, ok := r.BasicAuth()
+		if !ok || user != "monitoring" || password != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		response, ok := responses[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(response))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name string
+		uri  string
+		want RabbitMQCluster
+	}{
+		{
+			name: "cluster",
+			uri:  strings.Replace(server.URL, "http://", "http://monitoring:secret@", 1) + "/rabbitmq",
+			want: RabbitMQCluster{
+				IsConnected: true,
+				ClusterName: "rabbit@rabbitmq-0",
+				Version:     "3.13.7",
+				Nodes: []RabbitMQNode{
+					{Name: "rabbit@rabbitmq-0", Running: true, MemoryAlarm: true, Partitions: []string{"rabbit@rabbitmq-1"}},
+					{Name: "rabbit@rabbitmq-1", Partitions: []string{}},
+				},
+				Queues: []RabbitMQQueue{
+					{Name: "emails", VHost: "/", Consumers: 2},
+					{Name: "orders", VHost: "/", Messages: 12000, MessagesUnacknowledged: 20, State: "running"},
+				},
+			},
+		},
+		{
+			name: "unauthorized",
+			uri:  server.URL + "/rabbitmq",
+			want: RabbitMQCluster{Error: "failed to get overview: 401 Unauthorized"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cluster := RabbitMQCluster{}
+			collectRabbitMQCluster(context.Background(), server.Client(), tt.uri, &cluster)
+			assert.Equal(t, tt.want, cluster)
+		})
+	}
+}
@@ -41,7 +41,9 @@ func (e *RBACErrors) CheckRBAC(ctx context.Context, c Collector, collector *trou
 type RBACError struct {
 	DisplayName string
 	Namespace   string
+	Group       string
 	Resource    string
+	Subresource string
 	Verb        string
 }
 
@@ -80,7 +82,9 @@ func checkRBAC(ctx context.Context, clientConfig *rest.Config, namespace string,
 			forbidden = append(forbidden, RBACError{
 				DisplayName: title,
 				Namespace:   spec.ResourceAttributes.Namespace,
+				Group:       spec.ResourceAttributes.Group,
 				Resource:    spec.ResourceAttributes.Resource,
+				Subresource: spec.ResourceAttributes.Subresource,
 				Verb:        spec.ResourceAttributes.Verb,
 			})
 		}
@@ -4,6 +4,7 @@ import (
 	"archive/tar"
 	"bytes"
 	"compress/gzip"
+	"context"
 	"io"
 	"os"
 	"path/filepath"
@@ -12,7 +13,11 @@ import (
 
 	"github.com/pkg/errors"
 	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/constants"
 	"github.com/replicatedhq/troubleshoot/pkg/redact"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
 	"k8s.io/klog/v2"
 )
 
@@ -23,6 +28,19 @@ import (
 const MAX_CONCURRENT_REDACTORS = 10
 
 func RedactResult(bundlePath string, input CollectorResult, additionalRedactors []*troubleshootv1beta2.Redact) error {
+	return RedactResultWithContext(context.Background(), bundlePath, input, additionalRedactors)
+}
+
+// RedactResultWithContext is RedactResult with a context that is the parent of the spans of
+// each redacted file.
+func RedactResultWithContext(ctx context.Context, bundlePath string, input CollectorResult, additionalRedactors []*troubleshootv1beta2.Redact) error {
+	return RedactResultWithTokenizer(ctx, bundlePath, input, additionalRedactors, nil)
+}
+
+// RedactResultWithTokenizer is RedactResultWithContext with the values the redactors remove
+// replaced by their tokens, unless tokens is nil. Results redacted with the same tokenizer share
+// their tokens.
+func RedactResultWithTokenizer(ctx context.Context, bundlePath string, input CollectorResult, additionalRedactors []*troubleshootv1beta2.Redact, tokens *redact.Tokenizer) error {
 	wg := &sync.WaitGroup{}
 
 	// Error channel to capture errors from goroutines
@@ -39,6 +57,14 @@ func RedactResult(bundlePath string, input CollectorResult, additionalRedactors
 			defer wg.Done()
 			defer func() { <-limitCh }() // free up after the function execution has run
 
+			ctx, span := otel.Tracer(constants.LIB_TRACER_NAME).Start(ctx, file)
+			span.SetAttributes(attribute.String("type", "RedactFile"))
+			defer span.End()
+			fail := func(err error) {
+				span.SetStatus(codes.Error, err.Error())
+				errorCh <- err
+			}
+
 			var reader io.Reader
 			var readerCloseFn func() error // Function to close reader if needed
 			if data == nil {
@@ -50,7 +76,7 @@ func RedactResult(bundlePath string, input CollectorResult, additionalRedactors
 						// File not found, moving on.
 						return
 					}
-					errorCh <- errors.Wrap(err, "failed to stat file")
+					fail(errors.Wrap(err, "failed to stat file"))
 					return
 				}
 
@@ -62,14 +88,14 @@ func RedactResult(bundlePath string, input CollectorResult, additionalRedactors
 					symlink := file
 					target, err := os.Readlink(filepath.Join(bundlePath, symlink))
 					if err != nil {
-						errorCh <- errors.Wrap(err, "failed to read symlink")
+						fail(errors.Wrap(err, "failed to read symlink"))
 						return
 					}
 					// Get the relative path to the target file to conform with
 					// the path formats of the CollectorResult
 					file, err = filepath.Rel(bundlePath, target)
 					if err != nil {
-						errorCh <- errors.Wrap(err, "failed to get relative path")
+						fail(errors.Wrap(err, "failed to get relative path"))
 						return
 					}
 					klog.V(4).Infof("Redacting %s (symlink => %s)\n", file, symlink)
@@ -81,7 +107,7 @@ func RedactResult(bundlePath string, input CollectorResult, additionalRedactors
 					if os.IsNotExist(errors.Cause(err)) {
 						return
 					}
-					errorCh <- errors.Wrap(err, "failed to get reader")
+					fail(errors.Wrap(err, "failed to get reader"))
 					return
 				}
 
@@ -98,34 +124,34 @@ func RedactResult(bundlePath string, input CollectorResult, additionalRedactors
 			if filepath.Ext(file) == ".tar" || filepath.Ext(file) == ".tgz" || strings.HasSuffix(file, ".tar.gz") {
 				tmpDir, err := os.MkdirTemp("", "troubleshoot-subresult-")
 				if err != nil {
-					errorCh <- errors.Wrap(err, "failed to create temp dir")
+					fail(errors.Wrap(err, "failed to create temp dir"))
 					return
 				}
 				defer os.RemoveAll(tmpDir)
 
 				subResult, tarHeaders, err := decompressFile(tmpDir, reader, file)
 				if err != nil {
-					errorCh <- errors.Wrap(err, "failed to decompress file")
+					fail(errors.Wrap(err, "failed to decompress file"))
 					return
 				}
 
 				// Ensure the reader is closed after processing
 				if err := readerCloseFn(); err != nil {
 					klog.Warningf("Failed to close reader for %s: %v", file, err)
-					errorCh <- errors.Wrap(err, "failed to close reader")
+					fail(errors.Wrap(err, "failed to close reader"))
 					return
 				}
 
-				err = RedactResult(tmpDir, subResult, additionalRedactors)
+				err = RedactResultWithTokenizer(ctx, tmpDir, subResult, additionalRedactors, tokens)
 				if err != nil {
-					errorCh <- errors.Wrap(err, "failed to redact file")
+					fail(errors.Wrap(err, "failed to redact file"))
 					return
 				}
 
 				dstFilename := filepath.Join(bundlePath, file)
 				err = compressFiles(tmpDir, subResult, tarHeaders, dstFilename)
 				if err != nil {
-					errorCh <- errors.Wrap(err, "failed to re-compress file")
+					fail(errors.Wrap(err, "failed to re-compress file"))
 					return
 				}
 
@@ -135,15 +161,15 @@ func RedactResult(bundlePath string, input CollectorResult, additionalRedactors
 				return
 			}
 
-			redacted, err := redact.Redact(reader, file, additionalRedactors)
+			redacted, err := redact.RedactWithTokenizer(reader, file, additionalRedactors, tokens)
 			if err != nil {
-				errorCh <- errors.Wrap(err, "failed to redact io stream")
+				fail(errors.Wrap(err, "failed to redact io stream"))
 				return
 			}
 
 			err = input.ReplaceResult(bundlePath, file, redacted)
 			if err != nil {
-				errorCh <- errors.Wrap(err, "failed to create redacted result")
+				fail(errors.Wrap(err, "failed to create redacted result"))
 				return
 			}
 		}(k, v)
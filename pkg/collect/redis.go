@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/go-redis/redis/v7"
@@ -34,7 +35,12 @@ func (c *CollectRedis) IsExcluded() (bool, error) {
 }
 
 func (c *CollectRedis) createClient() (*redis.Client, error) {
-	opt, err := redis.ParseURL(c.Collector.URI)
+	uri, err := databaseURI(c.Context, c.Client, c.Collector)
+	if err != nil {
+		return nil, err
+	}
+
+	opt, err := redis.ParseURL(uri)
 	if err != nil {
 		return nil, err
 	}
@@ -60,17 +66,19 @@ func (c *CollectRedis) createMTLSClient(opt *redis.Options) (*redis.Client, erro
 }
 
 func extractServerVersion(info string) string {
-	lines := strings.Split(info, "\n")
-	for _, line := range lines {
-		lineParts := strings.Split(strings.TrimSpace(line), ":")
-		if len(lineParts) == 2 {
-			if lineParts[0] == "redis_version" {
-				return strings.TrimSpace(lineParts[1])
-			}
+	return parseRedisInfo(info)["redis_version"]
+}
+
+// parseRedisInfo returns the fields of the output of the INFO command by name
+func parseRedisInfo(info string) map[string]string {
+	fields := map[string]string{}
+	for _, line := range strings.Split(info, "\n") {
+		name, value, found := strings.Cut(strings.TrimSpace(line), ":")
+		if found && !strings.HasPrefix(name, "#") {
+			fields[name] = strings.TrimSpace(value)
 		}
 	}
-
-	return ""
+	return fields
 }
 
 func (c *CollectRedis) Collect(progressChan chan<- interface{}) (CollectorResult, error) {
@@ -92,6 +100,23 @@ func (c *CollectRedis) Collect(progressChan chan<- interface{}) (CollectorResult
 
 		if databaseConnection.Error == "" {
 			databaseConnection.Version = extractServerVersion(stringResult.Val())
+
+			parameters := c.Collector.Parameters
+			if len(parameters) == 0 && c.Collector.Diagnostics {
+				parameters = defaultRedisParameters
+			}
+			if len(parameters) > 0 {
+				variables, err := redisParameters(client, parameters)
+				if err != nil {
+					databaseConnection.Error = err.Error()
+				} else {
+					databaseConnection.Variables = variables
+				}
+			}
+
+			if c.Collector.Diagnostics {
+				databaseConnection.Diagnostics = redisDiagnostics(client)
+			}
 		}
 	}
 
@@ -110,3 +135,80 @@ func (c *CollectRedis) Collect(progressChan chan<- interface{}) (CollectorResult
 
 	return output, nil
 }
+
+// defaultRedisParameters are the configuration parameters collected with diagnostics
+var defaultRedisParameters = []string{
+	"maxclients",
+	"maxmemory",
+	"maxmemory-policy",
+	"appendonly",
+	"save",
+	"slowlog-log-slower-than",
+}
+
+func redisParameters(client *redis.Client, parameters []string) (map[string]string, error) {
+	variables := map[string]string{}
+	for _, parameter := range parameters {
+		// CONFIG GET returns the names and values of the parameters that match a pattern
+		values, err := client.ConfigGet(parameter).Result()
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get config %s", parameter)
+		}
+		for i := 0; i+1 < len(values); i += 2 {
+			variables[fmt.Sprint(values[i])] = fmt.Sprint(values[i+1])
+		}
+	}
+	return variables, nil
+}
+
+func redisDiagnostics(client *redis.Client) *DatabaseDiagnostics {
+	diagnostics := &DatabaseDiagnostics{}
+
+	replication, err := client.Info("replication").Result()
+	if err != nil {
+		diagnostics.addError("replication status", err)
+	} else {
+		setRedisReplication(diagnostics, parseRedisInfo(replication))
+	}
+
+	clients, err := client.Info("clients").Result()
+	if err != nil {
+		diagnostics.addError("connections", err)
+	} else {
+		diagnostics.Connections, _ = strconv.Atoi(parseRedisInfo(clients)["connected_clients"])
+	}
+
+	maxClients, err := client.ConfigGet("maxclients").Result()
+	if err != nil {
+		diagnostics.addError("max connections", err)
+	} else if len(maxClients) == 2 {
+		diagnostics.MaxConnections, _ = strconv.Atoi(fmt.Sprint(maxClients[1]))
+	}
+
+	slowQueries, err := client.Do("slowlog", "len").Int64()
+	if err != nil {
+		diagnostics.addError("slow queries", err)
+	} else {
+		diagnostics.SlowQueries = slowQueries
+	}
+
+	return diagnostics
+}
+
+// setRedisReplication sets the replication status from the replication section of INFO. Redis
+// does not measure the lag of a replica, it is the time since the replica last heard from its
+// primary, and is not known while the link to the primary is down.
+func setRedisReplication(diagnostics *DatabaseDiagnostics, replication map[string]string) {
+	if replication["role"] != "slave" {
+		return
+	}
+	diagnostics.IsReplica = true
+
+	if replication["master_link_status"] != "up" {
+		return
+	}
+	lag, err := strconv.ParseFloat(replication["master_last_io_seconds_ago"], 64)
+	if err == nil && lag >= 0 {
+		diagnostics.ReplicationLagSeconds = &lag
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	testclient "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/ptr"
 )
 
 func Test_extractServerVersion(t *testing.T) {
@@ -41,6 +42,52 @@ func Test_extractServerVersion(t *testing.T) {
 	}
 }
 
+func Test_setRedisReplication(t *testing.T) {
+	tests := []struct {
+		name        string
+		replication string
+		want        DatabaseDiagnostics
+	}{
+		{
+			name: "primary",
+			replication: `
+			# Replication
+			role:master
+			connected_slaves:1
+			`,
+			want: DatabaseDiagnostics{},
+		},
+		{
+			name: "replica",
+			replication: `
+			# Replication
+			role:slave
+			master_host:redis-primary
+			master_link_status:up
+			master_last_io_seconds_ago:2
+			`,
+			want: DatabaseDiagnostics{IsReplica: true, ReplicationLagSeconds: ptr.To(2.0)},
+		},
+		{
+			name: "replica disconnected from its primary",
+			replication: `
+			# Replication
+			role:slave
+			master_link_status:down
+			master_last_io_seconds_ago:-1
+			`,
+			want: DatabaseDiagnostics{IsReplica: true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diagnostics := DatabaseDiagnostics{}
+			setRedisReplication(&diagnostics, parseRedisInfo(tt.replication))
+			assert.Equal(t, tt.want, diagnostics)
+		})
+	}
+}
+
 func TestCollectRedis_createPlainTextClient(t *testing.T) {
 	tests := []struct {
 		name     string
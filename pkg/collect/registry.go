@@ -3,29 +3,28 @@ package collect
 import (
 	"bytes"
 	"context"
-	"encoding/base64"
 	"encoding/json"
 	"fmt"
-	"strings"
 	"time"
 
-	imagedocker "github.com/containers/image/v5/docker"
-	dockerref "github.com/containers/image/v5/docker/reference"
-	"github.com/containers/image/v5/transports/alltransports"
-	"github.com/containers/image/v5/types"
-	"github.com/distribution/distribution/v3/registry/api/errcode"
-	registryv2 "github.com/distribution/distribution/v3/registry/api/v2"
 	"github.com/pkg/errors"
-	"github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
 	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"github.com/replicatedhq/troubleshoot/pkg/registry"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/klog/v2"
 )
 
 type RegistryImage struct {
-	Exists bool   `json:"exists"`
+	Exists       bool     `json:"exists"`
+	ManifestList bool     `json:"manifestList,omitempty"`
+	Platforms    []string `json:"platforms,omitempty"`
+	// Digest is the digest the tag of the image resolved to at ResolvedAt, when the collector
+	// resolves digests
+	Digest     string     `json:"digest,omitempty"`
+	ResolvedAt *time.Time `json:"resolvedAt,omitempty"`
+	// Mirror is the image at the registry mirror the image was found at, if any
+	Mirror string `json:"mirror,omitempty"`
 	Error  string `json:"error,omitempty"`
 }
 
@@ -33,11 +32,6 @@ type RegistryInfo struct {
 	Images map[string]RegistryImage `json:"images"`
 }
 
-type registryAuthConfig struct {
-	username string
-	password string
-}
-
 type CollectRegistry struct {
 	Collector    *troubleshootv1beta2.RegistryImages
 	BundlePath   string
@@ -62,15 +56,13 @@ func (c *CollectRegistry) Collect(progressChan chan<- interface{}) (CollectorRes
 	}
 
 	for _, image := range c.Collector.Images {
-		exists, err := imageExists(c.Namespace, c.ClientConfig, c.Collector, image)
+		registryImage, err := collectRegistryImage(c.Context, c.Namespace, c.ClientConfig, c.Collector, image)
 		if err != nil {
 			registryInfo.Images[image] = RegistryImage{
 				Error: err.Error(),
 			}
 		} else {
-			registryInfo.Images[image] = RegistryImage{
-				Exists: exists,
-			}
+			registryInfo.Images[image] = *registryImage
 		}
 	}
 
@@ -90,216 +82,84 @@ func (c *CollectRegistry) Collect(progressChan chan<- interface{}) (CollectorRes
 	return output, nil
 }
 
-func imageExists(namespace string, clientConfig *rest.Config, registryCollector *troubleshootv1beta2.RegistryImages, image string) (bool, error) {
-	imageRef, err := alltransports.ParseImageName(fmt.Sprintf("docker://%s", image))
+// collectRegistryImage looks the image up at the mirrors of its registry, then at its registry, and
+// returns the first image that exists. The image does not exist when it was not found anywhere and
+// at least one registry could be asked.
+func collectRegistryImage(ctx context.Context, namespace string, clientConfig *rest.Config, registryCollector *troubleshootv1beta2.RegistryImages, image string) (*RegistryImage, error) {
+	candidates, err := registry.MirrorCandidates(image, registryCollector.RegistryMirrors)
 	if err != nil {
-		return false, errors.Wrapf(err, "failed to parse image name %s", image)
-	}
-
-	authConfig, err := getImageAuthConfig(namespace, clientConfig, registryCollector, imageRef)
-	if err != nil {
-		klog.Errorf("failed to get auth config: %v", err)
-		return false, errors.Wrap(err, "failed to get auth config")
+		return nil, err
 	}
 
+	var notFound *RegistryImage
 	var lastErr error
-	for i := 0; i < 3; i++ {
-		sysCtx := types.SystemContext{
-			DockerDisableV1Ping:         true,
-			DockerInsecureSkipTLSVerify: types.OptionalBoolTrue,
-		}
-		if authConfig != nil {
-			sysCtx.DockerAuthConfig = &types.DockerAuthConfig{
-				Username: authConfig.username,
-				Password: authConfig.password,
-			}
-		}
-
-		remoteImage, err := imageRef.NewImage(context.Background(), &sysCtx)
-		if err == nil {
-			klog.Infof("image %s exists", image)
-			remoteImage.Close()
-			return true, nil
-		}
-
-		klog.Errorf("failed to get image %s: %v", image, err)
-
-		if strings.Contains(err.Error(), "no image found in manifest list for architecture") {
-			// manifest was downloaded, but no matching architecture found in manifest
-			// should this count as image does not exist?
-			// this binary's architecture is not necessarily what will run in the cluster
-			return true, nil
-		}
-
-		if isNotFound(err) {
-			return false, nil
-		}
-
-		if strings.Contains(err.Error(), "EOF") {
+	for _, candidate := range candidates {
+		registryImage, err := collectRegistryImageFrom(ctx, namespace, clientConfig, registryCollector, candidate)
+		if err != nil {
+			klog.Errorf("failed to check image %s: %v", candidate, err)
 			lastErr = err
-			time.Sleep(1 * time.Second)
 			continue
 		}
-
-		return false, errors.Wrap(err, "failed to get image manifest")
-	}
-
-	return false, errors.Wrap(lastErr, "failed to retry")
-}
-
-func getImageAuthConfig(namespace string, clientConfig *rest.Config, registryCollector *troubleshootv1beta2.RegistryImages, imageRef types.ImageReference) (*registryAuthConfig, error) {
-	return getImageAuthConfigGeneric(namespace, clientConfig, registryCollector, imageRef)
-}
-
-// getImageAuthConfigGeneric works with any collector that implements AuthConfigProvider
-func getImageAuthConfigGeneric(namespace string, clientConfig *rest.Config, authProvider AuthConfigProvider, imageRef types.ImageReference) (*registryAuthConfig, error) {
-	imagePullSecrets := authProvider.GetImagePullSecrets()
-	if imagePullSecrets == nil {
-		return nil, nil
-	}
-
-	if imagePullSecrets.Data != nil {
-		config, err := getImageAuthConfigFromData(imageRef, imagePullSecrets)
-		if err != nil {
-			return nil, errors.Wrap(err, "failed to get auth from data")
-		}
-		return config, nil
-	}
-
-	if imagePullSecrets.Name != "" {
-		collectorNamespace := authProvider.GetNamespace()
-		if collectorNamespace == "" {
-			collectorNamespace = namespace
-		}
-		if collectorNamespace == "" {
-			collectorNamespace = "default"
+		if !registryImage.Exists {
+			notFound = registryImage
+			continue
 		}
-		config, err := getImageAuthConfigFromSecret(clientConfig, imageRef, imagePullSecrets, collectorNamespace)
-		if err != nil {
-			return nil, errors.Wrap(err, "failed to get auth from secret")
+		if candidate != image {
+			registryImage.Mirror = candidate
 		}
-		return config, nil
-	}
-
-	return nil, errors.New("image pull secret spec is not valid")
-}
-
-func getImageAuthConfigFromData(imageRef types.ImageReference, pullSecrets *v1beta2.ImagePullSecrets) (*registryAuthConfig, error) {
-	if pullSecrets.SecretType != "kubernetes.io/dockerconfigjson" {
-		return nil, errors.Errorf("secret type is not supported: %s", pullSecrets.SecretType)
-	}
-
-	configJsonBase64 := pullSecrets.Data[".dockerconfigjson"]
-	registry := dockerref.Domain(imageRef.DockerReference())
-
-	configJson, err := base64.StdEncoding.DecodeString(configJsonBase64)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to decode docker config string")
+		return registryImage, nil
 	}
 
-	dockerCfgJSON := struct {
-		Auths map[string]struct {
-			Auth     string `json:"auth"`
-			Username string `json:"username"`
-			Password string `json:"password"`
-		} `json:"auths"`
-	}{}
-
-	err = json.Unmarshal([]byte(configJson), &dockerCfgJSON)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to unmarshal config json")
+	if notFound != nil {
+		return notFound, nil
 	}
-
-	auth, ok := dockerCfgJSON.Auths[registry]
-	if !ok {
-		// Suport a mix of public and private images
-		return nil, nil
-	}
-
-	// gcr.io auth uses username and password, e.g. username: _json_key, password: <sa_key>
-	if auth.Username != "" && auth.Password != "" {
-		return &registryAuthConfig{
-			username: auth.Username,
-			password: auth.Password,
-		}, nil
-	}
-
-	// docker.io auth uses auth, e.g. auth: <base64_encoded_username_password>
-	// username and password can't contain colon
-	// at least according to https://github.com/docker/cli/blob/v27.0.3/cli/config/configfile/file.go#L247
-	// fallback to not decode for compatibility
-	authStr := auth.Auth
-	decodedAuth, err := base64.StdEncoding.DecodeString(authStr)
-	if err == nil {
-		authStr = string(decodedAuth)
-	}
-
-	parts := strings.Split(authStr, ":")
-	if len(parts) != 2 {
-		return nil, errors.Errorf("expected 2 parts in the auth string, but found %d", len(parts))
-	}
-
-	authConfig := registryAuthConfig{
-		username: parts[0],
-		password: strings.Trim(parts[1], "\x00"),
-	}
-
-	return &authConfig, nil
+	return nil, lastErr
 }
 
-func getImageAuthConfigFromSecret(clientConfig *rest.Config, imageRef types.ImageReference, pullSecrets *v1beta2.ImagePullSecrets, namespace string) (*registryAuthConfig, error) {
-	ctx := context.Background()
-
-	client, err := kubernetes.NewForConfig(clientConfig)
+// collectRegistryImageFrom checks that the image exists and records the platforms it is available
+// for, and its digest when the collector resolves digests. Failing to read the platforms or the
+// digest of an image that exists is not an error, it is only logged.
+func collectRegistryImageFrom(ctx context.Context, namespace string, clientConfig *rest.Config, registryCollector *troubleshootv1beta2.RegistryImages, image string) (*RegistryImage, error) {
+	imageRef, err := registry.ParseImageReference(image)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to create client from config")
+		return nil, err
 	}
 
-	secret, err := client.CoreV1().Secrets(namespace).Get(ctx, pullSecrets.Name, metav1.GetOptions{})
+	authConfig, err := registry.ResolveAuthConfig(ctx, clientConfig, namespace, registryCollector, imageRef)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to get secret")
+		klog.Errorf("failed to get auth config: %v", err)
+		return nil, errors.Wrap(err, "failed to get auth config")
 	}
 
-	foundSecrets := &v1beta2.ImagePullSecrets{
-		Name:       secret.Name,
-		SecretType: string(secret.Type),
-		Data: map[string]string{
-			".dockerconfigjson": base64.StdEncoding.EncodeToString(secret.Data[".dockerconfigjson"]),
-		},
-	}
+	client := registry.NewClient(registry.DefaultOptions(authConfig))
 
-	config, err := getImageAuthConfigFromData(imageRef, foundSecrets)
+	exists, err := client.ImageExists(ctx, imageRef)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to get auth from secret data")
+		return nil, err
 	}
-
-	return config, nil
-}
-
-func isNotFound(err error) bool {
-	switch err := err.(type) {
-	case errcode.Errors:
-		for _, e := range err {
-			if isNotFound(e) {
-				return true
-			}
-		}
-		return false
-	case errcode.Error:
-		return err.Message == registryv2.ErrorCodeManifestUnknown.Message()
+	if !exists {
+		return &RegistryImage{}, nil
 	}
 
-	// this type will cause panic when compared to error type
-	if _, ok := err.(imagedocker.ErrUnauthorizedForCredentials); ok {
-		return false
+	registryImage := &RegistryImage{Exists: true}
+	if registryCollector.ResolveDigests {
+		resolved, err := client.ResolveDigest(ctx, imageRef)
+		if err != nil {
+			klog.Errorf("failed to resolve digest of image %s: %v", image, err)
+		} else {
+			resolvedAt := time.Now().UTC()
+			registryImage.Digest = resolved.String()
+			registryImage.ResolvedAt = &resolvedAt
+		}
 	}
 
-	cause := errors.Cause(err)
-	if cause, ok := cause.(error); ok {
-		if cause == err {
-			return false
-		}
+	imageManifest, err := client.GetManifest(ctx, imageRef)
+	if err != nil {
+		klog.Errorf("failed to get platforms of image %s: %v", image, err)
+		return registryImage, nil
 	}
+	registryImage.ManifestList = imageManifest.ManifestList
+	registryImage.Platforms = imageManifest.Platforms
 
-	return isNotFound(cause)
+	return registryImage, nil
 }
@@ -2,15 +2,15 @@ package collect
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/pkg/errors"
 	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
-	"golang.org/x/sync/errgroup"
 	authorizationv1 "k8s.io/api/authorization/v1"
-	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apiserver/pkg/storage/names"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
@@ -33,12 +33,13 @@ type RemoteCollector struct {
 	BundlePath    string
 	Timeout       time.Duration
 	NamePrefix    string
+	Context       context.Context
 }
 
 type RemoteCollectors []*RemoteCollector
 
 type runner interface {
-	run(ctx context.Context, collector *troubleshootv1beta2.HostCollect, namespace string, name string, nodeName string, results chan<- map[string][]byte) error
+	run(ctx context.Context, collectors []*troubleshootv1beta2.HostCollect, namespace string, name string, nodeName string, results chan<- map[string][]byte) error
 }
 
 // checks if a given collector has a spec with 'exclude' that evaluates to true.
@@ -72,38 +73,10 @@ func (c *RemoteCollector) RunCollectorSync(globalRedactors []*troubleshootv1beta
 		return nil, errors.Wrap(err, "failed to convert to host collector")
 	}
 
-	client, err := kubernetes.NewForConfig(c.ClientConfig)
-	if err != nil {
-		return nil, err
-	}
-
-	scheme := runtime.NewScheme()
-	if err := corev1.AddToScheme(scheme); err != nil {
-		return nil, errors.Wrap(err, "failed to add runtime scheme")
-	}
-
-	runner := &podRunner{
-		client:       client,
-		scheme:       scheme,
-		image:        c.Image,
-		pullPolicy:   c.PullPolicy,
-		waitInterval: remoteCollectorDefaultInterval,
-	}
-
-	ctx, cancel := context.WithTimeout(context.Background(), c.Timeout)
+	ctx, cancel := context.WithTimeout(collectorContext(c.Context), c.Timeout)
 	defer cancel()
 
-	// Get all the nodes where we should run.
-	nodes, err := listNodesNamesInSelector(ctx, client, c.LabelSelector)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to get the list of nodes matching a nodeSelector")
-	}
-
-	if c.NamePrefix == "" {
-		c.NamePrefix = remoteCollectorNamePrefix
-	}
-
-	result, err := c.RunRemote(ctx, runner, nodes, hostCollector, names.SimpleNameGenerator, c.NamePrefix)
+	_, result, err := runRemoteHostCollectors(ctx, c.remoteCollectParams(), []*troubleshootv1beta2.HostCollect{hostCollector})
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to run collector remotely")
 	}
@@ -120,34 +93,19 @@ func (c *RemoteCollector) RunCollectorSync(globalRedactors []*troubleshootv1beta
 }
 
 func (c *RemoteCollector) RunRemote(ctx context.Context, runner runner, nodes []string, collector *troubleshootv1beta2.HostCollect, nameGenerator names.NameGenerator, namePrefix string) (map[string][]byte, error) {
-	g, ctx := errgroup.WithContext(ctx)
-	results := make(chan map[string][]byte, len(nodes))
-
-	for _, node := range nodes {
-		node := node
-		g.Go(func() error {
-			// May need to evaluate error and log warning.  Otherwise any error
-			// here will cancel the context of other goroutines and no results
-			// will be returned.
-			return runner.run(ctx, collector, c.Namespace, nameGenerator.GenerateName(namePrefix+"-"), node, results)
-		})
-	}
-
-	// Wait for all collectors to complete or return the first error.
-	if err := g.Wait(); err != nil {
-		return nil, errors.Wrap(err, "failed remote collection")
-	}
-	close(results)
+	return runRemote(ctx, runner, nodes, []*troubleshootv1beta2.HostCollect{collector}, nameGenerator, namePrefix, c.Namespace)
+}
 
-	output := make(map[string][]byte)
-	for result := range results {
-		r := result
-		for k, v := range r {
-			output[k] = v
-		}
+func (c *RemoteCollector) remoteCollectParams() RemoteCollectParams {
+	return RemoteCollectParams{
+		ClientConfig:  c.ClientConfig,
+		Image:         c.Image,
+		PullPolicy:    c.PullPolicy,
+		LabelSelector: c.LabelSelector,
+		NamePrefix:    c.NamePrefix,
+		Namespace:     c.Namespace,
+		Title:         c.GetDisplayName(),
 	}
-
-	return output, nil
 }
 
 func (c *RemoteCollector) GetDisplayName() string {
@@ -357,7 +315,9 @@ func (c *RemoteCollector) CheckRBAC(ctx context.Context) error {
 			forbidden = append(forbidden, RBACError{
 				DisplayName: c.GetDisplayName(),
 				Namespace:   spec.ResourceAttributes.Namespace,
+				Group:       spec.ResourceAttributes.Group,
 				Resource:    spec.ResourceAttributes.Resource,
+				Subresource: spec.ResourceAttributes.Subresource,
 				Verb:        spec.ResourceAttributes.Verb,
 			})
 		}
@@ -367,6 +327,144 @@ func (c *RemoteCollector) CheckRBAC(ctx context.Context) error {
 	return nil
 }
 
+// RunCollectorsSync runs the collectors that are not excluded on each node, in a single DaemonSet
+// per group of collectors with the same client configuration, image, node selector and namespace.
+// The collectors of a group run one after the other in its DaemonSet, each with its own timeout,
+// and their results are redacted when they ask to be. The raw results are keyed by node name.
+func (cs RemoteCollectors) RunCollectorsSync(globalRedactors []*troubleshootv1beta2.Redact) (result CollectorResult, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = errors.Errorf("recovered from panic: %v", r)
+		}
+	}()
+
+	groups, err := cs.groupByRunParams()
+	if err != nil {
+		return nil, err
+	}
+	if len(groups) == 0 {
+		return nil, nil
+	}
+
+	result = NewResult()
+	messages := []string{}
+	for _, group := range groups {
+		first := group.collectors[0]
+		_, startErr := runRemoteHostCollectorBatches(collectorContext(first.Context), first.remoteCollectParams(), group.batches, func(i int, batchResult map[string][]byte, runErr error) {
+			c := group.collectors[i]
+			if runErr != nil {
+				messages = append(messages, fmt.Sprintf("%s: %v", c.GetDisplayName(), runErr))
+				return
+			}
+			if c.Redact {
+				if err := RedactResult("", batchResult, globalRedactors); err != nil {
+					messages = append(messages, fmt.Sprintf("%s: failed to redact results: %v", c.GetDisplayName(), err))
+				}
+			}
+			if err := mergeNodeResults(result, batchResult); err != nil {
+				messages = append(messages, fmt.Sprintf("%s: %v", c.GetDisplayName(), err))
+			}
+		})
+		if startErr != nil {
+			for _, c := range group.collectors {
+				messages = append(messages, fmt.Sprintf("%s: %v", c.GetDisplayName(), startErr))
+			}
+		}
+	}
+
+	if len(messages) > 0 {
+		// Returning result on error to be consistent with local collector.
+		return result, errors.Errorf("failed to run collectors remotely: %s", strings.Join(messages, "; "))
+	}
+	return result, nil
+}
+
+// remoteRunParams are the fields of a RemoteCollector that decide where and how it runs. The
+// collectors with the same parameters run in the same DaemonSet.
+type remoteRunParams struct {
+	clientConfig  *rest.Config
+	image         string
+	pullPolicy    string
+	labelSelector string
+	namespace     string
+	namePrefix    string
+	ctx           context.Context
+}
+
+// remoteCollectorGroup is collectors with the same run parameters, with the batch each of them runs in
+type remoteCollectorGroup struct {
+	collectors []*RemoteCollector
+	batches    []remoteHostCollectorBatch
+}
+
+// groupByRunParams groups the collectors that are not excluded by their run parameters, in the
+// order they first appear
+func (cs RemoteCollectors) groupByRunParams() ([]*remoteCollectorGroup, error) {
+	groups := []*remoteCollectorGroup{}
+	byParams := map[remoteRunParams]*remoteCollectorGroup{}
+	for _, c := range cs {
+		if c.IsExcluded() {
+			continue
+		}
+		hostCollector, err := c.toHostCollector()
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to convert %s to host collector", c.GetDisplayName())
+		}
+
+		params := remoteRunParams{
+			clientConfig:  c.ClientConfig,
+			image:         c.Image,
+			pullPolicy:    c.PullPolicy,
+			labelSelector: c.LabelSelector,
+			namespace:     c.Namespace,
+			namePrefix:    c.NamePrefix,
+			ctx:           c.Context,
+		}
+		group, ok := byParams[params]
+		if !ok {
+			group = &remoteCollectorGroup{}
+			byParams[params] = group
+			groups = append(groups, group)
+		}
+		group.collectors = append(group.collectors, c)
+		group.batches = append(group.batches, remoteHostCollectorBatch{
+			collectors: []*troubleshootv1beta2.HostCollect{hostCollector},
+			timeout:    c.Timeout,
+		})
+	}
+	return groups, nil
+}
+
+// mergeNodeResults adds the raw results of each node of from to the results of the node in into.
+// The raw results of a node are a JSON object of the files collected on it.
+func mergeNodeResults(into CollectorResult, from map[string][]byte) error {
+	for node, data := range from {
+		current, ok := into[node]
+		if !ok {
+			into[node] = data
+			continue
+		}
+
+		files := map[string]string{}
+		if err := json.Unmarshal(current, &files); err != nil {
+			return errors.Wrapf(err, "failed to read results of node %s", node)
+		}
+		added := map[string]string{}
+		if err := json.Unmarshal(data, &added); err != nil {
+			return errors.Wrapf(err, "failed to read results of node %s", node)
+		}
+		for file, content := range added {
+			files[file] = content
+		}
+		merged, err := json.Marshal(files)
+		if err != nil {
+			return errors.Wrapf(err, "failed to combine results of node %s", node)
+		}
+		into[node] = merged
+	}
+	return nil
+}
+
 func (cs RemoteCollectors) CheckRBAC(ctx context.Context) error {
 	for _, c := range cs {
 		if err := c.CheckRBAC(ctx); err != nil {
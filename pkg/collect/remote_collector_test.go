@@ -7,14 +7,18 @@ import (
 	"time"
 
 	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/multitype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"k8s.io/apiserver/pkg/storage/names"
+	"k8s.io/client-go/rest"
 )
 
 type testRunner struct {
 	delay time.Duration
 }
 
-func (r *testRunner) run(ctx context.Context, collector *troubleshootv1beta2.HostCollect, namespace string, name string, nodeName string, results chan<- map[string][]byte) error {
+func (r *testRunner) run(ctx context.Context, collectors []*troubleshootv1beta2.HostCollect, namespace string, name string, nodeName string, results chan<- map[string][]byte) error {
 	output := map[string][]byte{
 		nodeName: []byte("logdata"),
 	}
@@ -99,3 +103,62 @@ func TestRemoteCollector_RunRemote(t *testing.T) {
 		})
 	}
 }
+
+func TestRemoteCollectors_groupByRunParams(t *testing.T) {
+	config := &rest.Config{Host: "https://cluster"}
+	cpu := &RemoteCollector{
+		Collect:      &troubleshootv1beta2.RemoteCollect{CPU: &troubleshootv1beta2.RemoteCPU{}},
+		ClientConfig: config,
+		Image:        "troubleshoot:1",
+		Timeout:      time.Minute,
+	}
+	memory := &RemoteCollector{
+		Collect:      &troubleshootv1beta2.RemoteCollect{Memory: &troubleshootv1beta2.RemoteMemory{}},
+		ClientConfig: config,
+		Image:        "troubleshoot:1",
+		Timeout:      time.Second,
+		Redact:       true,
+	}
+	gpuNodes := &RemoteCollector{
+		Collect:       &troubleshootv1beta2.RemoteCollect{Time: &troubleshootv1beta2.RemoteTime{}},
+		ClientConfig:  config,
+		Image:         "troubleshoot:1",
+		LabelSelector: "gpu=true",
+	}
+	excluded := &RemoteCollector{
+		Collect: &troubleshootv1beta2.RemoteCollect{KernelModules: &troubleshootv1beta2.RemoteKernelModules{
+			RemoteCollectorMeta: troubleshootv1beta2.RemoteCollectorMeta{Exclude: multitype.FromBool(true)},
+		}},
+		ClientConfig: config,
+		Image:        "troubleshoot:1",
+	}
+
+	groups, err := RemoteCollectors{cpu, gpuNodes, excluded, memory}.groupByRunParams()
+	require.NoError(t, err)
+	require.Len(t, groups, 2)
+
+	assert.Equal(t, []*RemoteCollector{cpu, memory}, groups[0].collectors)
+	require.Len(t, groups[0].batches, 2)
+	assert.Equal(t, time.Minute, groups[0].batches[0].timeout)
+	assert.NotNil(t, groups[0].batches[0].collectors[0].CPU)
+	assert.Equal(t, time.Second, groups[0].batches[1].timeout)
+	assert.NotNil(t, groups[0].batches[1].collectors[0].Memory)
+
+	assert.Equal(t, []*RemoteCollector{gpuNodes}, groups[1].collectors)
+}
+
+func TestMergeNodeResults(t *testing.T) {
+	result := NewResult()
+	require.NoError(t, mergeNodeResults(result, map[string][]byte{
+		"node-1": []byte(`{"host-collectors/system/cpu.json":"{}"}`),
+	}))
+	require.NoError(t, mergeNodeResults(result, map[string][]byte{
+		"node-1": []byte(`{"host-collectors/system/memory.json":"{}"}`),
+		"node-2": []byte(`{"host-collectors/system/memory.json":"{}"}`),
+	}))
+
+	assert.JSONEq(t, `{"host-collectors/system/cpu.json":"{}","host-collectors/system/memory.json":"{}"}`, string(result["node-1"]))
+	assert.JSONEq(t, `{"host-collectors/system/memory.json":"{}"}`, string(result["node-2"]))
+
+	assert.Error(t, mergeNodeResults(result, map[string][]byte{"node-2": []byte("not json")}))
+}
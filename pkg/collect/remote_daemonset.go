@@ -0,0 +1,320 @@
+package collect
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/remotecommand"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
+)
+
+const (
+	remoteCollectorContainerName    = "remote-collector"
+	remoteCollectorLabelKey         = "troubleshoot.sh/remote-collector"
+	remoteCollectorDefaultImage     = "replicated/troubleshoot:latest"
+	remoteCollectorScheduleTimeout  = 2 * time.Minute
+	remoteCollectorDefaultNamespace = "default"
+)
+
+type remoteExecFunc func(ctx context.Context, client kubernetes.Interface, clientConfig *rest.Config, pod corev1.Pod, stdin []byte) ([]byte, error)
+
+// daemonSetRunner runs host collectors in the pods of a short-lived privileged DaemonSet. The
+// DaemonSet is created once for all selected nodes, and every collector is run on a node with a
+// single exec through the API server, instead of scheduling a new pod per node and collector.
+type daemonSetRunner struct {
+	client          kubernetes.Interface
+	clientConfig    *rest.Config
+	image           string
+	pullPolicy      string
+	waitInterval    time.Duration
+	scheduleTimeout time.Duration
+	exec            remoteExecFunc
+
+	daemonSet *appsv1.DaemonSet
+	pods      map[string]corev1.Pod
+}
+
+func newDaemonSetRunner(client kubernetes.Interface, clientConfig *rest.Config, image string, pullPolicy string) *daemonSetRunner {
+	return &daemonSetRunner{
+		client:          client,
+		clientConfig:    clientConfig,
+		image:           image,
+		pullPolicy:      pullPolicy,
+		waitInterval:    remoteCollectorDefaultInterval,
+		scheduleTimeout: remoteCollectorScheduleTimeout,
+		exec:            execRemoteHostCollector,
+	}
+}
+
+// start creates the DaemonSet and waits for its pod to be running on each of the nodes.
+func (r *daemonSetRunner) start(ctx context.Context, namespace string, name string, nodes []string) error {
+	ds := remoteCollectorDaemonSet(namespace, name, nodes, r.image, r.pullPolicy)
+
+	created, err := r.client.AppsV1().DaemonSets(namespace).Create(ctx, ds, metav1.CreateOptions{})
+	if err != nil {
+		return errors.Wrap(err, "failed to create remote collector daemonset")
+	}
+	r.daemonSet = created
+	klog.V(2).Infof("Created remote collector daemonset %s/%s", namespace, created.Name)
+
+	waitCtx, cancel := context.WithTimeout(ctx, r.scheduleTimeout)
+	defer cancel()
+
+	pods, err := waitForRemoteCollectorPods(waitCtx, r.client, created, nodes, r.waitInterval)
+	if err != nil {
+		return err
+	}
+	r.pods = pods
+
+	return nil
+}
+
+// stop deletes the DaemonSet, its pods are garbage collected by the API server.
+func (r *daemonSetRunner) stop() {
+	if r.daemonSet == nil {
+		return
+	}
+
+	err := r.client.AppsV1().DaemonSets(r.daemonSet.Namespace).Delete(context.Background(), r.daemonSet.Name, metav1.DeleteOptions{
+		PropagationPolicy: ptr.To(metav1.DeletePropagationBackground),
+	})
+	if err != nil {
+		klog.Errorf("Failed to delete remote collector daemonset %s: %v", r.daemonSet.Name, err)
+	}
+}
+
+func (r *daemonSetRunner) run(ctx context.Context, collectors []*troubleshootv1beta2.HostCollect, namespace string, name string, nodeName string, results chan<- map[string][]byte) error {
+	pod, ok := r.pods[nodeName]
+	if !ok {
+		return errors.Errorf("no remote collector pod is running on node %s", nodeName)
+	}
+
+	spec, err := json.Marshal(remoteHostCollectorSpec(collectors))
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal host collector spec")
+	}
+
+	output, err := r.exec(ctx, r.client, r.clientConfig, pod, spec)
+	if err != nil {
+		return errors.Wrapf(err, "failed to run host collectors on node %s", nodeName)
+	}
+
+	results <- map[string][]byte{
+		nodeName: output,
+	}
+
+	return nil
+}
+
+func remoteHostCollectorSpec(collectors []*troubleshootv1beta2.HostCollect) *troubleshootv1beta2.HostCollector {
+	return &troubleshootv1beta2.HostCollector{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "troubleshoot.sh/v1beta2",
+			Kind:       "HostCollector",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "collector",
+		},
+		Spec: troubleshootv1beta2.HostCollectorSpec{
+			Collectors: collectors,
+		},
+	}
+}
+
+// remoteCollectorDaemonSet returns a privileged DaemonSet which runs an idle pod on each of the
+// nodes, tolerating any taint so collectors can also run on control plane nodes.
+func remoteCollectorDaemonSet(namespace string, name string, nodes []string, image string, pullPolicy string) *appsv1.DaemonSet {
+	if image == "" {
+		image = remoteCollectorDefaultImage
+	}
+	imagePullPolicy := corev1.PullAlways
+	if pullPolicy != "" {
+		imagePullPolicy = corev1.PullPolicy(pullPolicy)
+	}
+
+	labels := map[string]string{
+		remoteCollectorLabelKey: name,
+		"troubleshoot-role":     runnerJobType,
+	}
+
+	return &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels:    labels,
+		},
+		Spec: appsv1.DaemonSetSpec{
+			Selector: &metav1.LabelSelector{
+				MatchLabels: labels,
+			},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: labels,
+				},
+				Spec: corev1.PodSpec{
+					HostNetwork: true,
+					HostPID:     true,
+					HostIPC:     true,
+					Affinity: &corev1.Affinity{
+						NodeAffinity: &corev1.NodeAffinity{
+							RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+								NodeSelectorTerms: []corev1.NodeSelectorTerm{
+									{
+										MatchFields: []corev1.NodeSelectorRequirement{
+											{
+												Key:      "metadata.name",
+												Operator: corev1.NodeSelectorOpIn,
+												Values:   nodes,
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+					Tolerations: []corev1.Toleration{
+						{
+							Operator: corev1.TolerationOpExists,
+						},
+					},
+					Containers: []corev1.Container{
+						{
+							Image:           image,
+							ImagePullPolicy: imagePullPolicy,
+							Name:            remoteCollectorContainerName,
+							Command:         []string{"tail", "-f", "/dev/null"},
+							SecurityContext: &corev1.SecurityContext{
+								Privileged: ptr.To(true),
+							},
+							VolumeMounts: []corev1.VolumeMount{
+								{
+									Name:      "host-root",
+									MountPath: "/host",
+								},
+							},
+						},
+					},
+					Volumes: []corev1.Volume{
+						{
+							Name: "host-root",
+							VolumeSource: corev1.VolumeSource{
+								HostPath: &corev1.HostPathVolumeSource{
+									Path: "/",
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// waitForRemoteCollectorPods waits for a running pod of the DaemonSet on each of the nodes and
+// returns the pods keyed by node name.
+func waitForRemoteCollectorPods(ctx context.Context, client kubernetes.Interface, ds *appsv1.DaemonSet, nodes []string, interval time.Duration) (map[string]corev1.Pod, error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	running := map[string]corev1.Pod{}
+	for {
+		pods, err := client.CoreV1().Pods(ds.Namespace).List(ctx, metav1.ListOptions{
+			LabelSelector: remoteCollectorLabelKey + "=" + ds.Name,
+		})
+		if err != nil && ctx.Err() == nil {
+			return nil, errors.Wrap(err, "failed to list remote collector pods")
+		}
+
+		if pods != nil {
+			for _, pod := range pods.Items {
+				if isRemoteCollectorPodRunning(pod) {
+					running[pod.Spec.NodeName] = pod
+				}
+				for _, status := range pod.Status.ContainerStatuses {
+					if status.State.Waiting != nil && status.State.Waiting.Reason == "ImagePullBackOff" {
+						return nil, errors.Errorf("remote collector pod on node %s failed to pull image %s", pod.Spec.NodeName, status.Image)
+					}
+				}
+			}
+		}
+
+		missing := []string{}
+		for _, node := range nodes {
+			if _, ok := running[node]; !ok {
+				missing = append(missing, node)
+			}
+		}
+		if len(missing) == 0 {
+			return running, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			sort.Strings(missing)
+			return nil, errors.Wrapf(ctx.Err(), "remote collector pods are not running on nodes %s", strings.Join(missing, ", "))
+		case <-ticker.C:
+		}
+	}
+}
+
+func isRemoteCollectorPodRunning(pod corev1.Pod) bool {
+	if pod.Status.Phase != corev1.PodRunning || pod.DeletionTimestamp != nil {
+		return false
+	}
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.Name == remoteCollectorContainerName {
+			return status.State.Running != nil
+		}
+	}
+	return false
+}
+
+// execRemoteHostCollector runs the collect binary in the pod against the host's root filesystem,
+// streaming the host collector spec over stdin and the raw results back over stdout.
+func execRemoteHostCollector(ctx context.Context, client kubernetes.Interface, clientConfig *rest.Config, pod corev1.Pod, stdin []byte) ([]byte, error) {
+	scheme := runtime.NewScheme()
+	if err := corev1.AddToScheme(scheme); err != nil {
+		return nil, errors.Wrap(err, "failed to add runtime scheme")
+	}
+
+	req := client.CoreV1().RESTClient().Post().Resource("pods").Name(pod.Name).Namespace(pod.Namespace).SubResource("exec")
+	req.VersionedParams(&corev1.PodExecOptions{
+		Command:   []string{"/troubleshoot/collect", "-", "--chroot", "/host", "--collect-without-permissions", "--format", "raw"},
+		Container: remoteCollectorContainerName,
+		Stdin:     true,
+		Stdout:    true,
+		Stderr:    true,
+		TTY:       false,
+	}, runtime.NewParameterCodec(scheme))
+
+	executor, err := remotecommand.NewSPDYExecutor(clientConfig, "POST", req.URL())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create executor")
+	}
+
+	stdout := new(bytes.Buffer)
+	stderr := new(bytes.Buffer)
+	err = executor.StreamWithContext(ctx, remotecommand.StreamOptions{
+		Stdin:  bytes.NewBuffer(stdin),
+		Stdout: stdout,
+		Stderr: stderr,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to exec collector in pod %s: %s", pod.Name, strings.TrimSpace(stderr.String()))
+	}
+
+	return stdout.Bytes(), nil
+}
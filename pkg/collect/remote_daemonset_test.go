@@ -0,0 +1,136 @@
+package collect
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apiserver/pkg/storage/names"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+)
+
+func remoteCollectorTestPod(name string, node string, phase corev1.PodPhase) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      name + "-" + node,
+			Labels: map[string]string{
+				remoteCollectorLabelKey: name,
+			},
+		},
+		Spec: corev1.PodSpec{
+			NodeName: node,
+		},
+		Status: corev1.PodStatus{
+			Phase: phase,
+			ContainerStatuses: []corev1.ContainerStatus{
+				{
+					Name:  remoteCollectorContainerName,
+					State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}},
+				},
+			},
+		},
+	}
+}
+
+func Test_remoteCollectorDaemonSet(t *testing.T) {
+	ds := remoteCollectorDaemonSet("default", "preflight-remote-abcde", []string{"node-1", "node-2"}, "", "")
+
+	assert.Equal(t, "preflight-remote-abcde", ds.Spec.Selector.MatchLabels[remoteCollectorLabelKey])
+	assert.Equal(t, ds.Spec.Selector.MatchLabels, ds.Spec.Template.Labels)
+
+	podSpec := ds.Spec.Template.Spec
+	assert.True(t, podSpec.HostPID)
+	assert.True(t, podSpec.HostNetwork)
+	assert.Equal(t, []corev1.Toleration{{Operator: corev1.TolerationOpExists}}, podSpec.Tolerations)
+
+	terms := podSpec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms
+	require.Len(t, terms, 1)
+	assert.Equal(t, []corev1.NodeSelectorRequirement{
+		{Key: "metadata.name", Operator: corev1.NodeSelectorOpIn, Values: []string{"node-1", "node-2"}},
+	}, terms[0].MatchFields)
+
+	container := podSpec.Containers[0]
+	assert.Equal(t, remoteCollectorDefaultImage, container.Image)
+	assert.Equal(t, corev1.PullAlways, container.ImagePullPolicy)
+	assert.True(t, *container.SecurityContext.Privileged)
+}
+
+func Test_waitForRemoteCollectorPods(t *testing.T) {
+	ctx := context.Background()
+	ds := remoteCollectorDaemonSet("default", "collector", []string{"node-1", "node-2"}, "", "")
+
+	client := fake.NewSimpleClientset(
+		remoteCollectorTestPod("collector", "node-1", corev1.PodRunning),
+		remoteCollectorTestPod("collector", "node-2", corev1.PodRunning),
+		remoteCollectorTestPod("other", "node-3", corev1.PodRunning),
+	)
+
+	pods, err := waitForRemoteCollectorPods(ctx, client, ds, []string{"node-1", "node-2"}, time.Millisecond)
+	require.NoError(t, err)
+	assert.Len(t, pods, 2)
+	assert.Equal(t, "collector-node-2", pods["node-2"].Name)
+}
+
+func Test_waitForRemoteCollectorPods_NotRunning(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	ds := remoteCollectorDaemonSet("default", "collector", []string{"node-1", "node-2"}, "", "")
+
+	client := fake.NewSimpleClientset(
+		remoteCollectorTestPod("collector", "node-1", corev1.PodRunning),
+		remoteCollectorTestPod("collector", "node-2", corev1.PodPending),
+	)
+
+	_, err := waitForRemoteCollectorPods(ctx, client, ds, []string{"node-1", "node-2"}, time.Millisecond)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not running on nodes node-2")
+}
+
+func TestDaemonSetRunner(t *testing.T) {
+	ctx := context.Background()
+	nodes := []string{"node-1", "node-2"}
+
+	client := fake.NewSimpleClientset(
+		remoteCollectorTestPod("collector", "node-1", corev1.PodRunning),
+		remoteCollectorTestPod("collector", "node-2", corev1.PodRunning),
+	)
+
+	runner := newDaemonSetRunner(client, nil, "", "")
+	runner.waitInterval = time.Millisecond
+	runner.exec = func(ctx context.Context, client kubernetes.Interface, clientConfig *rest.Config, pod corev1.Pod, stdin []byte) ([]byte, error) {
+		spec := troubleshootv1beta2.HostCollector{}
+		if err := json.Unmarshal(stdin, &spec); err != nil {
+			return nil, err
+		}
+		// each node runs all of the collectors in a single exec
+		assert.Len(t, spec.Spec.Collectors, 2)
+		return []byte(`{"host-collectors/system/cpu.json":"` + pod.Spec.NodeName + `"}`), nil
+	}
+
+	require.NoError(t, runner.start(ctx, "default", "collector", nodes))
+
+	collectors := []*troubleshootv1beta2.HostCollect{
+		{CPU: &troubleshootv1beta2.CPU{}},
+		{Memory: &troubleshootv1beta2.Memory{}},
+	}
+	result, err := runRemote(ctx, runner, nodes, collectors, names.SimpleNameGenerator, "collector", "default")
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]byte{
+		"node-1": []byte(`{"host-collectors/system/cpu.json":"node-1"}`),
+		"node-2": []byte(`{"host-collectors/system/cpu.json":"node-2"}`),
+	}, result)
+
+	runner.stop()
+	daemonSets, err := client.AppsV1().DaemonSets("default").List(ctx, metav1.ListOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, daemonSets.Items)
+}
@@ -9,6 +9,7 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"strconv"
 	"strings"
 
 	"github.com/pkg/errors"
@@ -294,6 +295,27 @@ func (r CollectorResult) ArchiveBundle(bundlePath string, outputFilename string)
 	tarWriter := tar.NewWriter(gzipWriter)
 	defer tarWriter.Close()
 
+	return r.writeArchive(tarWriter, bundlePath, false)
+}
+
+// ArchiveBundleWithCodecs creates a tar archive of the files in the bundle directory, each
+// compressed with the codec of its file type (see ArchiveCodecForFile) rather than the archive
+// as a whole, so that files compressed already are not compressed again. The archive can be read
+// with NewBundleArchiveReader.
+func (r CollectorResult) ArchiveBundleWithCodecs(bundlePath string, outputFilename string) error {
+	fileWriter, err := os.Create(outputFilename)
+	if err != nil {
+		return errors.Wrap(err, "failed to create output file")
+	}
+	defer fileWriter.Close()
+
+	tarWriter := tar.NewWriter(fileWriter)
+	defer tarWriter.Close()
+
+	return r.writeArchive(tarWriter, bundlePath, true)
+}
+
+func (r CollectorResult) writeArchive(tarWriter *tar.Writer, bundlePath string, withCodecs bool) error {
 	for relativeName := range r {
 		filename := filepath.Join(bundlePath, relativeName)
 		info, err := os.Lstat(filename)
@@ -340,43 +362,108 @@ func (r CollectorResult) ArchiveBundle(bundlePath string, outputFilename string)
 			}
 
 			hdr.Linkname = relLinkPath
-		}
 
-		err = tarWriter.WriteHeader(hdr)
-		if err != nil {
-			return errors.Wrap(err, "failed to write tar header")
-		}
-
-		func() error {
-			if fileMode.Type() == os.ModeSymlink {
-				// Don't copy the symlink, just write the header which
-				// will create a symlink in the tarball
-				klog.V(4).Infof("Added %q symlink to bundle archive", hdr.Linkname)
-				return nil
+			// the link points to the file as it is named in the archive
+			if codec := ArchiveCodecForFile(linkTarget); withCodecs && codec.Extension() != "" {
+				hdr.Linkname += codec.Extension()
+				hdr.Format = tar.FormatPAX
+				hdr.PAXRecords = map[string]string{archiveCodecPAXRecord: codec.Name()}
 			}
 
-			fileReader, err := os.Open(filename)
+			err = tarWriter.WriteHeader(hdr)
 			if err != nil {
-				return errors.Wrap(err, "failed to open source file")
+				return errors.Wrap(err, "failed to write tar header")
 			}
-			defer fileReader.Close()
+			// Don't copy the symlink, just write the header which
+			// will create a symlink in the tarball
+			klog.V(4).Infof("Added %q symlink to bundle archive", hdr.Linkname)
+			continue
+		}
 
-			_, err = io.Copy(tarWriter, fileReader)
-			if err != nil {
-				return errors.Wrap(err, "failed to copy file into archive")
-			}
-			klog.V(4).Infof("Added %q file to bundle archive", hdr.Name)
+		var codec ArchiveCodec = storeArchiveCodec{}
+		if withCodecs {
+			codec = ArchiveCodecForFile(relativeName)
+		}
+		if err := writeArchiveFile(tarWriter, hdr, filename, codec); err != nil {
+			return err
+		}
+	}
 
-			return nil
-		}()
+	return nil
+}
+
+// writeArchiveFile adds a file to the archive, compressed with the codec. Compressed files are
+// written to a temporary file first, as their size is needed for their header.
+func writeArchiveFile(tarWriter *tar.Writer, hdr *tar.Header, filename string, codec ArchiveCodec) error {
+	fileReader, err := os.Open(filename)
+	if err != nil {
+		return errors.Wrap(err, "failed to open source file")
+	}
+	defer fileReader.Close()
+
+	var content io.Reader = fileReader
+	if codec.Extension() != "" {
+		compressed, err := os.CreateTemp("", "bundle-file-*")
 		if err != nil {
-			return err
+			return errors.Wrap(err, "failed to create temp file")
 		}
+		defer os.Remove(compressed.Name())
+		defer compressed.Close()
+
+		size, err := compressArchiveFile(compressed, fileReader, codec)
+		if err != nil {
+			return errors.Wrapf(err, "failed to compress %s with %s", hdr.Name, codec.Name())
+		}
+
+		hdr.Format = tar.FormatPAX
+		hdr.PAXRecords = map[string]string{
+			archiveCodecPAXRecord: codec.Name(),
+			archiveSizePAXRecord:  strconv.FormatInt(hdr.Size, 10),
+		}
+		hdr.Name += codec.Extension()
+		hdr.Size = size
+		content = compressed
+	}
+
+	err = tarWriter.WriteHeader(hdr)
+	if err != nil {
+		return errors.Wrap(err, "failed to write tar header")
 	}
 
+	_, err = io.Copy(tarWriter, content)
+	if err != nil {
+		return errors.Wrap(err, "failed to copy file into archive")
+	}
+	klog.V(4).Infof("Added %q file to bundle archive", hdr.Name)
+
 	return nil
 }
 
+// compressArchiveFile compresses the content to the file with the codec, and returns the size of
+// the compressed content, with the file rewound to its start
+func compressArchiveFile(f *os.File, content io.Reader, codec ArchiveCodec) (int64, error) {
+	w, err := codec.NewWriter(f)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := io.Copy(w, content); err != nil {
+		w.Close()
+		return 0, err
+	}
+	if err := w.Close(); err != nil {
+		return 0, err
+	}
+
+	size, err := f.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return 0, err
+	}
+	return size, nil
+}
+
 // CollectorResultFromBundle creates a CollectorResult from a bundle directory
 // The bundle directory is not necessarily a support bundle, it can be any directory
 // of collected files as part of other operations or files that are already on disk.
@@ -0,0 +1,239 @@
+package collect
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// DefaultResultSpillThreshold is the size above which a ResultStore keeps a file on disk
+const DefaultResultSpillThreshold = 10 * 1024 * 1024
+
+// ResultStore gathers the results of the collectors of a bundle. Unlike CollectorResult, it is
+// safe for concurrent writers. The files of a bundle written to disk are recorded without being
+// read back, and, when the bundle is kept in memory, the files larger than the spill threshold are
+// kept in temporary files rather than in memory, so that large logs or command output do not grow
+// the memory of the process while the other collectors run. Close removes the temporary files.
+type ResultStore struct {
+	mut            sync.RWMutex
+	bundlePath     string
+	spillThreshold int64
+	spillDir       string
+	entries        map[string]*resultEntry
+}
+
+// resultEntry is a file of a ResultStore: in the bundle on disk, in memory, or spilled to path
+// when it is larger than the spill threshold
+type resultEntry struct {
+	inBundle bool
+	data     []byte
+	path     string
+}
+
+// NewResultStore returns an empty store of the results of a bundle saved to bundlePath, or kept
+// in memory when bundlePath is empty. Files kept in memory that are larger than spillThreshold
+// bytes are spilled to disk, DefaultResultSpillThreshold is used when spillThreshold is not
+// greater than 0.
+func NewResultStore(bundlePath string, spillThreshold int64) *ResultStore {
+	if spillThreshold <= 0 {
+		spillThreshold = DefaultResultSpillThreshold
+	}
+	return &ResultStore{
+		bundlePath:     bundlePath,
+		spillThreshold: spillThreshold,
+		entries:        map[string]*resultEntry{},
+	}
+}
+
+// SaveResult saves the contents of reader as relativePath, replacing the file if it was saved
+// before.
+func (s *ResultStore) SaveResult(relativePath string, reader io.Reader) error {
+	if reader == nil {
+		return nil
+	}
+
+	if s.bundlePath != "" {
+		if err := NewResult().SaveResult(s.bundlePath, relativePath, reader); err != nil {
+			return err
+		}
+		return s.set(relativePath, &resultEntry{inBundle: true})
+	}
+
+	// Read one byte more than the threshold to know whether the file has to be spilled
+	var buf bytes.Buffer
+	n, err := io.Copy(&buf, io.LimitReader(reader, s.spillThreshold+1))
+	if err != nil {
+		return errors.Wrapf(err, "failed to read %s", relativePath)
+	}
+
+	// an empty file still has data, a file without data is read from the bundle
+	data := buf.Bytes()
+	if data == nil {
+		data = []byte{}
+	}
+	entry := &resultEntry{data: data}
+	if n > s.spillThreshold {
+		entry, err = s.spill(relativePath, io.MultiReader(&buf, reader))
+		if err != nil {
+			return err
+		}
+	}
+	return s.set(relativePath, entry)
+}
+
+// AddResult saves the files of the result of a collector. The files the collector saved in the
+// bundle on disk are recorded as they are.
+func (s *ResultStore) AddResult(result CollectorResult) error {
+	for relativePath, data := range result {
+		if data == nil && s.bundlePath != "" {
+			if err := s.set(relativePath, &resultEntry{inBundle: true}); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := s.SaveResult(relativePath, bytes.NewReader(data)); err != nil {
+			return errors.Wrapf(err, "failed to save %s", relativePath)
+		}
+	}
+	return nil
+}
+
+func (s *ResultStore) set(relativePath string, entry *resultEntry) error {
+	s.mut.Lock()
+	previous := s.entries[relativePath]
+	s.entries[relativePath] = entry
+	s.mut.Unlock()
+
+	return removeSpilled(previous)
+}
+
+func (s *ResultStore) spill(relativePath string, reader io.Reader) (*resultEntry, error) {
+	dir, err := s.getSpillDir()
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.CreateTemp(dir, "result-")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create spill file")
+	}
+	defer f.Close()
+
+	if _, err := io.Copy(f, reader); err != nil {
+		os.Remove(f.Name())
+		return nil, errors.Wrapf(err, "failed to spill %s", relativePath)
+	}
+	return &resultEntry{path: f.Name()}, nil
+}
+
+func (s *ResultStore) getSpillDir() (string, error) {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	if s.spillDir != "" {
+		return s.spillDir, nil
+	}
+	dir, err := os.MkdirTemp("", "troubleshoot-results-")
+	if err != nil {
+		return "", errors.Wrap(err, "failed to create spill directory")
+	}
+	s.spillDir = dir
+	return dir, nil
+}
+
+func removeSpilled(entry *resultEntry) error {
+	if entry == nil || entry.path == "" {
+		return nil
+	}
+	if err := os.Remove(entry.path); err != nil && !os.IsNotExist(err) {
+		return errors.Wrap(err, "failed to remove spill file")
+	}
+	return nil
+}
+
+// GetReader returns a reader of a file of the store
+func (s *ResultStore) GetReader(relativePath string) (io.ReadCloser, error) {
+	s.mut.RLock()
+	entry, ok := s.entries[relativePath]
+	s.mut.RUnlock()
+	if !ok {
+		return nil, errors.Errorf("%s not found in results", relativePath)
+	}
+
+	switch {
+	case entry.inBundle:
+		f, err := os.Open(filepath.Join(s.bundlePath, relativePath))
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to open %s", relativePath)
+		}
+		return f, nil
+	case entry.path != "":
+		f, err := os.Open(entry.path)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to open spilled %s", relativePath)
+		}
+		return f, nil
+	default:
+		return io.NopCloser(bytes.NewReader(entry.data)), nil
+	}
+}
+
+// Files returns the names of the files of the store, sorted
+func (s *ResultStore) Files() []string {
+	s.mut.RLock()
+	defer s.mut.RUnlock()
+	names := make([]string, 0, len(s.entries))
+	for name := range s.entries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Result returns the files of the store as a CollectorResult. The files in the bundle on disk
+// have no data, as the files CollectorResult.SaveResult saves, and the spilled files are read
+// back into memory.
+func (s *ResultStore) Result() (CollectorResult, error) {
+	s.mut.RLock()
+	defer s.mut.RUnlock()
+
+	result := NewResult()
+	for relativePath, entry := range s.entries {
+		switch {
+		case entry.inBundle:
+			result[relativePath] = nil
+		case entry.path != "":
+			data, err := os.ReadFile(entry.path)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to read spilled %s", relativePath)
+			}
+			result[relativePath] = data
+		default:
+			result[relativePath] = entry.data
+		}
+	}
+	return result, nil
+}
+
+// Close removes the files of the store, and the files spilled to disk. The files in the bundle
+// on disk are kept.
+func (s *ResultStore) Close() error {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	s.entries = map[string]*resultEntry{}
+	if s.spillDir == "" {
+		return nil
+	}
+	err := os.RemoveAll(s.spillDir)
+	s.spillDir = ""
+	if err != nil {
+		return errors.Wrap(err, "failed to remove spill directory")
+	}
+	return nil
+}
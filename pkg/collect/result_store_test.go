@@ -0,0 +1,111 @@
+package collect
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func readStoreFile(t *testing.T, store *ResultStore, relativePath string) string {
+	reader, err := store.GetReader(relativePath)
+	require.NoError(t, err)
+	defer reader.Close()
+	data, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestResultStore_Spill(t *testing.T) {
+	store := NewResultStore("", 8)
+	defer store.Close()
+
+	require.NoError(t, store.SaveResult("small.txt", bytes.NewBufferString("12345678")))
+	require.NoError(t, store.SaveResult("large.txt", bytes.NewBufferString("123456789")))
+	require.NoError(t, store.SaveResult("empty.txt", bytes.NewBufferString("")))
+
+	assert.Empty(t, store.entries["small.txt"].path)
+	spilled := store.entries["large.txt"].path
+	assert.NotEmpty(t, spilled)
+	assert.FileExists(t, spilled)
+	assert.Equal(t, "123456789", readStoreFile(t, store, "large.txt"))
+
+	// replacing a spilled file removes it from disk
+	require.NoError(t, store.SaveResult("large.txt", bytes.NewBufferString("small")))
+	assert.NoFileExists(t, spilled)
+	assert.Equal(t, "small", readStoreFile(t, store, "large.txt"))
+
+	require.NoError(t, store.SaveResult("other.txt", bytes.NewBufferString(strings.Repeat("x", 100))))
+	result, err := store.Result()
+	require.NoError(t, err)
+	assert.Equal(t, CollectorResult{
+		"empty.txt": []byte{},
+		"large.txt": []byte("small"),
+		"other.txt": []byte(strings.Repeat("x", 100)),
+		"small.txt": []byte("12345678"),
+	}, result)
+
+	_, err = store.GetReader("missing.txt")
+	assert.Error(t, err)
+
+	spillDir := store.spillDir
+	assert.DirExists(t, spillDir)
+	require.NoError(t, store.Close())
+	assert.NoFileExists(t, spillDir)
+	assert.Empty(t, store.Files())
+}
+
+func TestResultStore_ConcurrentWriters(t *testing.T) {
+	store := NewResultStore("", 16)
+	defer store.Close()
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			result := CollectorResult{fmt.Sprintf("node-%02d.log", i): []byte(strings.Repeat(fmt.Sprint(i%10), i))}
+			assert.NoError(t, store.AddResult(result))
+		}(i)
+	}
+	wg.Wait()
+
+	require.Len(t, store.Files(), 50)
+	for i := 0; i < 50; i++ {
+		assert.Equal(t, strings.Repeat(fmt.Sprint(i%10), i), readStoreFile(t, store, fmt.Sprintf("node-%02d.log", i)))
+	}
+}
+
+func TestResultStore_Bundle(t *testing.T) {
+	bundlePath := t.TempDir()
+	store := NewResultStore(bundlePath, 4)
+	defer store.Close()
+
+	// the files a collector saved in the bundle are recorded as they are
+	collected := NewResult()
+	require.NoError(t, collected.SaveResult(bundlePath, "host/disk.json", bytes.NewBufferString(`{"free": 10}`)))
+	require.NoError(t, store.AddResult(collected))
+
+	// the files kept in memory by a collector are saved in the bundle
+	require.NoError(t, store.AddResult(CollectorResult{"host/cpu.json": []byte(`{"cores": 4}`)}))
+
+	result, err := store.Result()
+	require.NoError(t, err)
+	assert.Equal(t, CollectorResult{"host/cpu.json": nil, "host/disk.json": nil}, result)
+	assert.Empty(t, store.spillDir)
+
+	data, err := os.ReadFile(filepath.Join(bundlePath, "host/cpu.json"))
+	require.NoError(t, err)
+	assert.Equal(t, `{"cores": 4}`, string(data))
+	assert.Equal(t, `{"free": 10}`, readStoreFile(t, store, "host/disk.json"))
+
+	require.NoError(t, store.Close())
+	assert.FileExists(t, filepath.Join(bundlePath, "host/disk.json"))
+}
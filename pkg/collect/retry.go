@@ -0,0 +1,136 @@
+package collect
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"reflect"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/troubleshoot/pkg/constants"
+)
+
+// DefaultRetryBackoff is how long a collector waits before its first retry when it sets no retryBackoff
+const DefaultRetryBackoff = time.Second
+
+// CollectorRetries is how many times a collector runs again after failing, and how long it waits
+// before the first retry. The wait doubles before each next retry.
+type CollectorRetries struct {
+	Retries int
+	Backoff time.Duration
+}
+
+// ParseCollectorRetries parses the retries and retryBackoff settings of a collector
+func ParseCollectorRetries(retries int, backoff string) (CollectorRetries, error) {
+	if retries < 0 {
+		return CollectorRetries{}, errors.Errorf("retries %d must not be negative", retries)
+	}
+
+	parsed := CollectorRetries{Retries: retries, Backoff: DefaultRetryBackoff}
+	if backoff != "" {
+		d, err := time.ParseDuration(backoff)
+		if err != nil {
+			return CollectorRetries{}, errors.Wrapf(err, "failed to parse retryBackoff %q", backoff)
+		}
+		if d < 0 {
+			return CollectorRetries{}, errors.Errorf("retryBackoff %q must not be negative", backoff)
+		}
+		parsed.Backoff = d
+	}
+	return parsed, nil
+}
+
+// GetCollectorRetries returns the retries of an in-cluster collector. Collectors without retries
+// run once.
+func GetCollectorRetries(c Collector) (CollectorRetries, error) {
+	spec := reflect.ValueOf(c).Elem().FieldByName("Collector")
+	if !spec.IsValid() || spec.Kind() != reflect.Ptr || spec.IsNil() || spec.Elem().Kind() != reflect.Struct {
+		return CollectorRetries{}, nil
+	}
+	retries := spec.Elem().FieldByName("Retries")
+	backoff := spec.Elem().FieldByName("RetryBackoff")
+	if !retries.IsValid() || retries.Kind() != reflect.Int || !backoff.IsValid() || backoff.Kind() != reflect.String {
+		return CollectorRetries{}, nil
+	}
+	return ParseCollectorRetries(int(retries.Int()), backoff.String())
+}
+
+// CollectAttempt is a run of a collector. Error is the error it returned, if any.
+type CollectAttempt struct {
+	StartedAt   time.Time `json:"startedAt"`
+	CollectedAt time.Time `json:"collectedAt"`
+	Error       string    `json:"error,omitempty"`
+}
+
+// CollectWithRetries runs the collector, and runs it again while it fails, up to its number of
+// retries, reporting each retry on the progress channel. The result and error of the last attempt
+// are returned, along with all the attempts. Retries stop when ctx is done.
+func CollectWithRetries(ctx context.Context, c Collector, progressChan chan<- interface{}) (CollectorResult, []CollectAttempt, error) {
+	retries, err := GetCollectorRetries(c)
+	if err != nil {
+		progressChan <- errors.Errorf("failed to read retries of collector: %s: %v", c.Title(), err)
+	}
+
+	attempts := []CollectAttempt{}
+	backoff := retries.Backoff
+	for {
+		attempt := CollectAttempt{StartedAt: time.Now()}
+		result, err := c.Collect(progressChan)
+		attempt.CollectedAt = time.Now()
+		if err != nil {
+			attempt.Error = err.Error()
+		}
+		attempts = append(attempts, attempt)
+
+		if err == nil || len(attempts) > retries.Retries || ctx.Err() != nil {
+			return result, attempts, err
+		}
+
+		progressChan <- errors.Errorf("collector %s failed, retrying in %s (%d of %d): %v", c.Title(), backoff, len(attempts), retries.Retries, err)
+		select {
+		case <-ctx.Done():
+			return result, attempts, err
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// RetriedCollector is the history of the attempts of a collector that ran more than once.
+// Collector and Name identify the collector as in SkippedCollector.
+type RetriedCollector struct {
+	Collector string           `json:"collector"`
+	Name      string           `json:"name,omitempty"`
+	Attempts  []CollectAttempt `json:"attempts"`
+}
+
+type RetriedCollectors []RetriedCollector
+
+// AddCollector records the attempts of an in-cluster collector. Collectors that ran once are not
+// recorded.
+func (r *RetriedCollectors) AddCollector(c Collector, attempts []CollectAttempt) {
+	if len(attempts) < 2 {
+		return
+	}
+	kind, name, _ := getCollectorKind(c)
+	*r = append(*r, RetriedCollector{
+		Collector: kind,
+		Name:      name,
+		Attempts:  attempts,
+	})
+}
+
+// SaveResult writes the attempts of the retried collectors to the bundle. Nothing is written if no collectors were retried.
+func (r RetriedCollectors) SaveResult(output CollectorResult, bundlePath string) error {
+	if len(r) == 0 {
+		return nil
+	}
+
+	b, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal retried collectors")
+	}
+
+	return output.SaveResult(bundlePath, constants.COLLECTOR_ATTEMPTS_FILENAME, bytes.NewBuffer(b))
+}
@@ -0,0 +1,126 @@
+package collect
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/rest"
+)
+
+// flakyCollector fails the first failures times it runs
+type flakyCollector struct {
+	Collector *troubleshootv1beta2.Data
+	failures  int
+	calls     int
+}
+
+func (c *flakyCollector) Title() string             { return "flaky" }
+func (c *flakyCollector) IsExcluded() (bool, error) { return false, nil }
+func (c *flakyCollector) GetRBACErrors() []error    { return nil }
+func (c *flakyCollector) HasRBACErrors() bool       { return false }
+func (c *flakyCollector) CheckRBAC(ctx context.Context, _ Collector, _ *troubleshootv1beta2.Collect, _ *rest.Config, _ string) error {
+	return nil
+}
+
+func (c *flakyCollector) Collect(progressChan chan<- interface{}) (CollectorResult, error) {
+	c.calls++
+	if c.calls <= c.failures {
+		return nil, errors.Errorf("attempt %d failed", c.calls)
+	}
+	return CollectorResult{"data": []byte("ok")}, nil
+}
+
+func newFlakyCollector(failures int, retries int, backoff string) *flakyCollector {
+	return &flakyCollector{
+		Collector: &troubleshootv1beta2.Data{
+			CollectorMeta: troubleshootv1beta2.CollectorMeta{Retries: retries, RetryBackoff: backoff},
+		},
+		failures: failures,
+	}
+}
+
+func TestParseCollectorRetries(t *testing.T) {
+	retries, err := ParseCollectorRetries(3, "")
+	require.NoError(t, err)
+	assert.Equal(t, CollectorRetries{Retries: 3, Backoff: DefaultRetryBackoff}, retries)
+
+	retries, err = ParseCollectorRetries(2, "500ms")
+	require.NoError(t, err)
+	assert.Equal(t, CollectorRetries{Retries: 2, Backoff: 500 * time.Millisecond}, retries)
+
+	_, err = ParseCollectorRetries(-1, "")
+	assert.Error(t, err)
+	_, err = ParseCollectorRetries(1, "soon")
+	assert.Error(t, err)
+}
+
+func TestCollectWithRetries(t *testing.T) {
+	tests := []struct {
+		name         string
+		collector    *flakyCollector
+		wantAttempts int
+		wantErr      bool
+	}{
+		{
+			name:         "no retries",
+			collector:    newFlakyCollector(1, 0, ""),
+			wantAttempts: 1,
+			wantErr:      true,
+		},
+		{
+			name:         "succeeds after retries",
+			collector:    newFlakyCollector(2, 3, "1ms"),
+			wantAttempts: 3,
+		},
+		{
+			name:         "fails after all retries",
+			collector:    newFlakyCollector(5, 2, "1ms"),
+			wantAttempts: 3,
+			wantErr:      true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			progressChan := make(chan interface{}, 10)
+			result, attempts, err := CollectWithRetries(context.Background(), test.collector, progressChan)
+			assert.Len(t, attempts, test.wantAttempts)
+			assert.Equal(t, test.wantAttempts, test.collector.calls)
+			assert.Len(t, progressChan, test.wantAttempts-1, "each retry is reported")
+			if test.wantErr {
+				assert.Error(t, err)
+				assert.NotEmpty(t, attempts[len(attempts)-1].Error)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, CollectorResult{"data": []byte("ok")}, result)
+			assert.Empty(t, attempts[len(attempts)-1].Error)
+			assert.Equal(t, "attempt 1 failed", attempts[0].Error)
+		})
+	}
+}
+
+func TestCollectWithRetries_canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	collector := newFlakyCollector(5, 3, "1h")
+	_, attempts, err := CollectWithRetries(ctx, collector, make(chan interface{}, 10))
+	assert.Error(t, err)
+	assert.Len(t, attempts, 1, "no retries once the collection is canceled")
+}
+
+func TestRetriedCollectors(t *testing.T) {
+	retried := RetriedCollectors{}
+	retried.AddCollector(newFlakyCollector(0, 0, ""), []CollectAttempt{{}})
+	assert.Empty(t, retried, "collectors that ran once are not recorded")
+
+	retried.AddCollector(newFlakyCollector(0, 0, ""), []CollectAttempt{{Error: "throttled"}, {}})
+	require.Len(t, retried, 1)
+	assert.Len(t, retried[0].Attempts, 2)
+}
@@ -55,6 +55,7 @@ func (c *CollectRun) Collect(progressChan chan<- interface{}) (CollectorResult,
 		Namespace:       namespace,
 		Timeout:         c.Collector.Timeout,
 		ImagePullSecret: c.Collector.ImagePullSecret,
+		Parse:           c.Collector.Parse,
 		PodSpec: corev1.PodSpec{
 			RestartPolicy:      corev1.RestartPolicyNever,
 			ServiceAccountName: serviceAccountName,
@@ -45,7 +45,7 @@ func (c *CollectRunDaemonSet) IsExcluded() (bool, error) {
 }
 
 func (c *CollectRunDaemonSet) Collect(progressChan chan<- interface{}) (CollectorResult, error) {
-	ctx := context.Background()
+	ctx := collectorContext(c.Context)
 
 	client, err := kubernetes.NewForConfig(c.ClientConfig)
 	if err != nil {
@@ -76,6 +76,7 @@ func (c *CollectRunDaemonSet) Collect(progressChan chan<- interface{}) (Collecto
 	}
 
 	defer func() {
+		ctx := cleanupContext(ctx)
 		// delete DaemonSet
 		err := client.AppsV1().DaemonSets(ds.ObjectMeta.Namespace).Delete(ctx, ds.ObjectMeta.Name, metav1.DeleteOptions{})
 		if err != nil {
@@ -14,7 +14,6 @@ import (
 	"github.com/pkg/errors"
 	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
 	"github.com/replicatedhq/troubleshoot/pkg/constants"
-	"github.com/replicatedhq/troubleshoot/pkg/k8sutil"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/client-go/kubernetes"
 	v1 "k8s.io/client-go/kubernetes/typed/core/v1"
@@ -44,16 +43,80 @@ func (c *CollectRunPod) IsExcluded() (bool, error) {
 	return isExcluded(c.Collector.Exclude)
 }
 
-func (c *CollectRunPod) Collect(progressChan chan<- interface{}) (result CollectorResult, err error) {
-	ctx := context.Background()
-	result = NewResult()
+func (c *CollectRunPod) Collect(progressChan chan<- interface{}) (CollectorResult, error) {
+	ctx := collectorContext(c.Context)
 
 	client, err := kubernetes.NewForConfig(c.ClientConfig)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to create client from config")
 	}
 
-	pod, err := runPodWithSpec(ctx, client, c.Collector)
+	if c.Collector.EveryNode {
+		return c.collectEveryNode(ctx, client)
+	}
+	return c.runPod(ctx, client, c.Collector, c.Collector.Name)
+}
+
+// collectEveryNode runs a copy of the pod on each ready node it can be scheduled on, in parallel.
+// The results of each node are saved to <name>/<node>, with errors.json when its pod failed.
+func (c *CollectRunPod) collectEveryNode(ctx context.Context, client *kubernetes.Clientset) (CollectorResult, error) {
+	nodes, err := client.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list nodes")
+	}
+	nodeNames, err := schedulableNodes(nodes.Items, &c.Collector.PodSpec)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to match nodes")
+	}
+	if len(nodeNames) == 0 {
+		return nil, errors.New("no ready node matches the node selector, affinity and tolerations of the pod")
+	}
+
+	result := NewResult()
+	wg := sync.WaitGroup{}
+	mtx := sync.Mutex{}
+	for _, nodeName := range nodeNames {
+		wg.Add(1)
+		go func(nodeName string) {
+			defer wg.Done()
+
+			collector := runPodOnNode(c.Collector, nodeName)
+			nodeResult, err := c.runPod(ctx, client, collector, nodeName)
+
+			mtx.Lock()
+			defer mtx.Unlock()
+			result.AddResult(nodeResult)
+			if err != nil {
+				klog.Errorf("Failed to run pod on node %s: %v", nodeName, err)
+				result.SaveResult(c.BundlePath, filepath.Join(collector.Name, "errors.json"), marshalErrors([]string{err.Error()}))
+			}
+		}(nodeName)
+	}
+	wg.Wait()
+
+	return result, nil
+}
+
+// runPodOnNode returns a copy of the collector that runs its pod on the node, with its results
+// saved to <name>/<node>
+func runPodOnNode(collector *troubleshootv1beta2.RunPod, nodeName string) *troubleshootv1beta2.RunPod {
+	onNode := collector.DeepCopy()
+	onNode.Name = filepath.Join(collector.Name, nodeName)
+	onNode.CollectorName = fmt.Sprintf("%s-%s", runPodName(collector), nodeName)
+	onNode.EveryNode = false
+	if onNode.PodSpec.NodeSelector == nil {
+		onNode.PodSpec.NodeSelector = map[string]string{}
+	}
+	onNode.PodSpec.NodeSelector["kubernetes.io/hostname"] = nodeName
+	return onNode
+}
+
+// runPod runs the pod of the collector and saves its logs, and its status and events to
+// <name>/<detailsName>.json and <name>/<detailsName>-events.json
+func (c *CollectRunPod) runPod(ctx context.Context, client *kubernetes.Clientset, collector *troubleshootv1beta2.RunPod, detailsName string) (result CollectorResult, err error) {
+	result = NewResult()
+
+	pod, err := runPodWithSpec(ctx, client, collector)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to run pod")
 	}
@@ -61,7 +124,7 @@ func (c *CollectRunPod) Collect(progressChan chan<- interface{}) (result Collect
 		deletePod(ctx, client, pod)
 	}()
 
-	if c.Collector.ImagePullSecret != nil && c.Collector.ImagePullSecret.Data != nil {
+	if collector.ImagePullSecret != nil && collector.ImagePullSecret.Data != nil {
 		defer c.deleteImagePullSecret(context.Background(), client, pod)
 	}
 
@@ -69,17 +132,17 @@ func (c *CollectRunPod) Collect(progressChan chan<- interface{}) (result Collect
 		if err != nil {
 			return
 		}
-		result, err = savePodDetails(ctx, client, result, c.BundlePath, c.ClientConfig, pod, c.Collector)
+		result, err = savePodDetails(ctx, client, result, c.BundlePath, c.ClientConfig, pod, collector, detailsName)
 		if err != nil {
 			klog.Errorf("failed to save pod details: %v", err)
 		}
 	}()
 
-	if c.Collector.Timeout == "" {
-		return runWithoutTimeout(ctx, c.BundlePath, c.ClientConfig, pod, c.Collector)
+	if collector.Timeout == "" {
+		return runWithoutTimeout(ctx, c.BundlePath, c.ClientConfig, pod, collector)
 	}
 
-	timeout, err := time.ParseDuration(c.Collector.Timeout)
+	timeout, err := time.ParseDuration(collector.Timeout)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to parse timeout")
 	}
@@ -92,7 +155,7 @@ func (c *CollectRunPod) Collect(progressChan chan<- interface{}) (result Collect
 
 	// TODO: Use context with timeout instead of go routine
 	go func() {
-		b, err := runWithoutTimeout(timeoutCtx, c.BundlePath, c.ClientConfig, pod, c.Collector)
+		b, err := runWithoutTimeout(timeoutCtx, c.BundlePath, c.ClientConfig, pod, collector)
 		if err != nil {
 			errCh <- err
 		} else {
@@ -101,7 +164,10 @@ func (c *CollectRunPod) Collect(progressChan chan<- interface{}) (result Collect
 	}()
 
 	select {
-	case <-time.After(timeout):
+	case <-timeoutCtx.Done():
+		if ctx.Err() != nil {
+			return result, ctx.Err()
+		}
 		return result, errors.New("timeout")
 	case output := <-resultCh:
 		result.AddResult(output)
@@ -202,7 +268,7 @@ func runWithoutTimeout(ctx context.Context, bundlePath string, clientConfig *res
 		MaxLines: 10000,
 		MaxBytes: 5000000,
 	}
-	podLogs, err := savePodLogs(ctx, bundlePath, client, pod, collectorName, "", &limits, true, true, false)
+	podLogs, err := savePodLogs(ctx, bundlePath, client, pod, collectorName, "", &limits, true, true, false, nil)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to get pod logs")
 	}
@@ -211,9 +277,43 @@ func runWithoutTimeout(ctx context.Context, bundlePath string, clientConfig *res
 		output[k] = v
 	}
 
+	if runPodCollector.Parse != "" {
+		saveParsedPodLogs(output, bundlePath, pod, collectorName, runPodCollector.Parse)
+	}
+
+	if runPodCollector.Artifacts != nil {
+		artifacts, err := collectRunPodArtifacts(ctx, bundlePath, clientConfig, client, pod, runPodCollector)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to collect artifacts")
+		}
+		output.AddResult(artifacts)
+	}
+
 	return output, nil
 }
 
+// saveParsedPodLogs saves the logs of the pod parsed as JSON next to them, recording a failure to
+// parse them in an errors file
+func saveParsedPodLogs(output CollectorResult, bundlePath string, pod *corev1.Pod, collectorName string, format string) {
+	prefix := fmt.Sprintf("%s/%s", collectorName, pod.Name)
+	err := func() error {
+		reader, err := output.GetReader(bundlePath, prefix+".log")
+		if err != nil {
+			return errors.Wrap(err, "failed to read pod logs")
+		}
+		defer reader.Close()
+		logs, err := io.ReadAll(reader)
+		if err != nil {
+			return errors.Wrap(err, "failed to read pod logs")
+		}
+		return saveParsedOutput(output, bundlePath, prefix+"-parsed.json", format, logs)
+	}()
+	if err != nil {
+		klog.V(2).Infof("failed to parse the logs of pod %s: %v", pod.Name, err)
+		output.SaveResult(bundlePath, prefix+"-parsed-errors.json", marshalErrors([]string{err.Error()}))
+	}
+}
+
 func createSecret(ctx context.Context, client kubernetes.Interface, namespace string, imagePullSecret *troubleshootv1beta2.ImagePullSecrets) (string, error) {
 	if imagePullSecret.Data == nil {
 		return "", nil
@@ -278,6 +378,8 @@ type RunPodOptions struct {
 	Command             []string
 	ImagePullSecretName string
 	HostNetwork         bool
+	// Scheduling restricts the nodes the pods run on
+	Scheduling *troubleshootv1beta2.PodScheduling
 }
 
 func RunPodsReadyNodes(ctx context.Context, client v1.CoreV1Interface, opts RunPodOptions) (map[string][]byte, error) {
@@ -290,11 +392,43 @@ func RunPodsReadyNodes(ctx context.Context, client v1.CoreV1Interface, opts RunP
 		return nil, errors.Wrapf(err, "list nodes")
 	}
 
-	for _, node := range nodes.Items {
-		if !k8sutil.NodeIsReady(node) {
-			continue
-		}
+	podSpec := corev1.PodSpec{
+		RestartPolicy: corev1.RestartPolicyNever,
+		HostNetwork:   opts.HostNetwork,
+		Containers: []corev1.Container{
+			{
+				Name:            "run",
+				Image:           opts.Image,
+				ImagePullPolicy: corev1.PullPolicy(opts.ImagePullPolicy),
+				Command:         opts.Command,
+			},
+		},
+		Tolerations: []corev1.Toleration{
+			{
+				Key:      "node-role.kubernetes.io/master",
+				Operator: "Exists",
+				Effect:   "NoSchedule",
+			},
+			{
+				Key:      "node-role.kubernetes.io/control-plane",
+				Operator: "Exists",
+				Effect:   "NoSchedule",
+			},
+		},
+	}
+	if opts.ImagePullSecretName != "" {
+		podSpec.ImagePullSecrets = append(podSpec.ImagePullSecrets, corev1.LocalObjectReference{Name: opts.ImagePullSecretName})
+	}
+	if opts.Scheduling != nil {
+		applyPodScheduling(&podSpec, *opts.Scheduling)
+	}
+
+	nodeNames, err := schedulableNodes(nodes.Items, &podSpec)
+	if err != nil {
+		return nil, errors.Wrap(err, "match nodes")
+	}
 
+	for _, nodeName := range nodeNames {
 		wg.Add(1)
 
 		go func(node string) {
@@ -305,37 +439,12 @@ func RunPodsReadyNodes(ctx context.Context, client v1.CoreV1Interface, opts RunP
 					GenerateName: "run-pod-",
 					Namespace:    opts.Namespace,
 				},
-				Spec: corev1.PodSpec{
-					NodeSelector: map[string]string{
-						"kubernetes.io/hostname": node,
-					},
-					RestartPolicy: corev1.RestartPolicyNever,
-					HostNetwork:   opts.HostNetwork,
-					Containers: []corev1.Container{
-						{
-							Name:            "run",
-							Image:           opts.Image,
-							ImagePullPolicy: corev1.PullPolicy(opts.ImagePullPolicy),
-							Command:         opts.Command,
-						},
-					},
-					Tolerations: []corev1.Toleration{
-						{
-							Key:      "node-role.kubernetes.io/master",
-							Operator: "Exists",
-							Effect:   "NoSchedule",
-						},
-						{
-							Key:      "node-role.kubernetes.io/control-plane",
-							Operator: "Exists",
-							Effect:   "NoSchedule",
-						},
-					},
-				},
+				Spec: *podSpec.DeepCopy(),
 			}
-			if opts.ImagePullSecretName != "" {
-				pod.Spec.ImagePullSecrets = append(pod.Spec.ImagePullSecrets, corev1.LocalObjectReference{Name: opts.ImagePullSecretName})
+			if pod.Spec.NodeSelector == nil {
+				pod.Spec.NodeSelector = map[string]string{}
 			}
+			pod.Spec.NodeSelector["kubernetes.io/hostname"] = node
 			logs, err := RunPodLogs(ctx, client, pod)
 			if err != nil {
 				klog.Errorf("Failed to run pod on node %s: %v", node, err)
@@ -345,7 +454,7 @@ func RunPodsReadyNodes(ctx context.Context, client v1.CoreV1Interface, opts RunP
 			mtx.Lock()
 			defer mtx.Unlock()
 			nodeLogs[node] = logs
-		}(node.Name)
+		}(nodeName)
 	}
 
 	wg.Wait()
@@ -402,7 +511,7 @@ func RunPodLogs(ctx context.Context, client v1.CoreV1Interface, podSpec *corev1.
 	return io.ReadAll(logs)
 }
 
-func savePodDetails(ctx context.Context, client *kubernetes.Clientset, output CollectorResult, bundlePath string, clientConfig *rest.Config, pod *corev1.Pod, runPodCollector *troubleshootv1beta2.RunPod) (CollectorResult, error) {
+func savePodDetails(ctx context.Context, client *kubernetes.Clientset, output CollectorResult, bundlePath string, clientConfig *rest.Config, pod *corev1.Pod, runPodCollector *troubleshootv1beta2.RunPod, detailsName string) (CollectorResult, error) {
 	podStatus, err := client.CoreV1().Pods(pod.Namespace).Get(ctx, pod.Name, metav1.GetOptions{})
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to get pod")
@@ -423,18 +532,20 @@ func savePodDetails(ctx context.Context, client *kubernetes.Clientset, output Co
 		return nil, errors.Wrap(err, "failed to marshal pod events")
 	}
 
-	err = output.SaveResult(bundlePath, filepath.Join(runPodCollector.Name, fmt.Sprintf("%s.json", runPodCollector.Name)), bytes.NewBuffer(podBytes))
+	err = output.SaveResult(bundlePath, filepath.Join(runPodCollector.Name, fmt.Sprintf("%s.json", detailsName)), bytes.NewBuffer(podBytes))
 	if err != nil {
-		klog.Errorf("failed to save pod status results to %s.json: %v", runPodCollector.Name, err)
+		klog.Errorf("failed to save pod status results to %s.json: %v", detailsName, err)
 	}
 
-	err = output.SaveResult(bundlePath, filepath.Join(runPodCollector.Name, fmt.Sprintf("%s-events.json", runPodCollector.Name)), bytes.NewBuffer(podEventBytes))
+	err = output.SaveResult(bundlePath, filepath.Join(runPodCollector.Name, fmt.Sprintf("%s-events.json", detailsName)), bytes.NewBuffer(podEventBytes))
 	if err != nil {
-		klog.Errorf("failed to save pod event results to %s-events.json: %v", runPodCollector.Name, err)
+		klog.Errorf("failed to save pod event results to %s-events.json: %v", detailsName, err)
 	}
 	return output, nil
 }
 
+// deletePod deletes a pod and waits for it to be gone. The pod is deleted forcefully when it is
+// not gone in time, or right away when ctx is done.
 func deletePod(ctx context.Context, client *kubernetes.Clientset, pod *corev1.Pod) {
 	if err := client.CoreV1().Pods(pod.Namespace).Delete(context.Background(), pod.Name, metav1.DeleteOptions{}); err != nil {
 		klog.Errorf("Failed to delete pod %s: %v", pod.Name, err)
@@ -484,16 +595,9 @@ func createPodStruct(runPodCollector *troubleshootv1beta2.RunPod) corev1.Pod {
 		namespace = runPodCollector.Namespace
 	}
 
-	podName := "run-pod"
-	if runPodCollector.CollectorName != "" {
-		podName = runPodCollector.CollectorName
-	} else if runPodCollector.Name != "" {
-		podName = runPodCollector.Name
-	}
-
 	pod := corev1.Pod{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:        podName,
+			Name:        runPodName(runPodCollector),
 			Namespace:   namespace,
 			Labels:      podLabels,
 			Annotations: runPodCollector.Annotations,
@@ -505,5 +609,19 @@ func createPodStruct(runPodCollector *troubleshootv1beta2.RunPod) corev1.Pod {
 		Spec: runPodCollector.PodSpec,
 	}
 
+	if runPodCollector.Artifacts != nil {
+		pod.Spec = *runPodCollector.PodSpec.DeepCopy()
+		addRunPodArtifacts(&pod.Spec, runPodCollector.Artifacts)
+	}
+
 	return pod
 }
+
+func runPodName(runPodCollector *troubleshootv1beta2.RunPod) string {
+	if runPodCollector.CollectorName != "" {
+		return runPodCollector.CollectorName
+	} else if runPodCollector.Name != "" {
+		return runPodCollector.Name
+	}
+	return "run-pod"
+}
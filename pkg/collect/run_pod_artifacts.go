@@ -0,0 +1,131 @@
+package collect
+
+import (
+	"context"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/klog/v2"
+)
+
+const (
+	runPodArtifactsName             = "troubleshoot-artifacts"
+	defaultRunPodArtifactsMountPath = "/troubleshoot/artifacts"
+	defaultRunPodArtifactsImage     = "busybox:1.36"
+)
+
+// addRunPodArtifacts mounts the artifacts volume in the containers of the pod, and adds the
+// helper container the artifacts are copied from once they exit
+func addRunPodArtifacts(spec *corev1.PodSpec, artifacts *troubleshootv1beta2.RunPodArtifacts) {
+	mountPath := runPodArtifactsMountPath(artifacts)
+	image := artifacts.Image
+	if image == "" {
+		image = defaultRunPodArtifactsImage
+	}
+
+	spec.Volumes = append(spec.Volumes, corev1.Volume{
+		Name:         runPodArtifactsName,
+		VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+	})
+	mount := corev1.VolumeMount{Name: runPodArtifactsName, MountPath: mountPath}
+	for i := range spec.Containers {
+		spec.Containers[i].VolumeMounts = append(spec.Containers[i].VolumeMounts, mount)
+	}
+	// the helper is the last container for the logs of the first one to be collected
+	spec.Containers = append(spec.Containers, corev1.Container{
+		Name:         runPodArtifactsName,
+		Image:        image,
+		Command:      []string{"sh", "-c", "trap 'exit 0' TERM; while true; do sleep 1; done"},
+		VolumeMounts: []corev1.VolumeMount{mount},
+	})
+	if spec.RestartPolicy == "" {
+		spec.RestartPolicy = corev1.RestartPolicyNever
+	}
+}
+
+func runPodArtifactsMountPath(artifacts *troubleshootv1beta2.RunPodArtifacts) string {
+	if artifacts.MountPath == "" {
+		return defaultRunPodArtifactsMountPath
+	}
+	return artifacts.MountPath
+}
+
+// collectRunPodArtifacts waits for the containers of the pod to exit, and copies the artifacts
+// from the helper container to <name>/artifacts. Failures to copy a path are saved to
+// <name>/artifacts-errors.json.
+func collectRunPodArtifacts(ctx context.Context, bundlePath string, clientConfig *rest.Config, client kubernetes.Interface, pod *corev1.Pod, runPodCollector *troubleshootv1beta2.RunPod) (CollectorResult, error) {
+	output := NewResult()
+
+	if err := waitForContainersToExit(ctx, client, pod); err != nil {
+		return nil, errors.Wrap(err, "failed to wait for the containers to exit")
+	}
+
+	mountPath := runPodArtifactsMountPath(runPodCollector.Artifacts)
+	paths := runPodCollector.Artifacts.Paths
+	if len(paths) == 0 {
+		paths = []string{"."}
+	}
+
+	artifactsPath := filepath.Join(runPodCollector.Name, "artifacts")
+	copyErrors := map[string]string{}
+	for _, p := range paths {
+		relativePath := path.Clean(p)
+		if path.IsAbs(relativePath) || relativePath == ".." || strings.HasPrefix(relativePath, "../") {
+			copyErrors[filepath.Join(p, "error")] = "artifact paths must be relative to the mount path of the artifacts"
+			continue
+		}
+
+		// the path is not joined for the whole volume, ".", to be copied without its directory
+		containerPath := mountPath + "/" + relativePath
+		subPath := filepath.Join(artifactsPath, path.Dir(relativePath))
+		dstPath := ""
+		if bundlePath != "" {
+			dstPath = filepath.Join(bundlePath, subPath)
+		}
+		files, stderr, err := copyFilesFromPod(ctx, dstPath, clientConfig, client, pod.Name, runPodArtifactsName, pod.Namespace, containerPath, true)
+		if err != nil {
+			klog.V(2).Infof("failed to copy artifact %s from pod %s: %v", p, pod.Name, err)
+			copyErrors[filepath.Join(p, "error")] = err.Error()
+			if len(stderr) > 0 {
+				copyErrors[filepath.Join(p, "stderr")] = string(stderr)
+			}
+			continue
+		}
+
+		for k, v := range files {
+			output[filepath.Join(subPath, k)] = v
+		}
+	}
+
+	if len(copyErrors) > 0 {
+		output.SaveResult(bundlePath, artifactsPath+"-errors.json", marshalErrors(copyErrors))
+	}
+
+	return output, nil
+}
+
+// waitForContainersToExit waits for the containers of the pod other than the artifacts helper
+// to exit
+func waitForContainersToExit(ctx context.Context, client kubernetes.Interface, pod *corev1.Pod) error {
+	return wait.PollUntilContextCancel(ctx, time.Second, true, func(ctx context.Context) (bool, error) {
+		status, err := client.CoreV1().Pods(pod.Namespace).Get(ctx, pod.Name, metav1.GetOptions{})
+		if err != nil {
+			return false, errors.Wrap(err, "failed to get pod")
+		}
+		for _, container := range status.Status.ContainerStatuses {
+			if container.Name != runPodArtifactsName && container.State.Terminated == nil {
+				return false, nil
+			}
+		}
+		return true, nil
+	})
+}
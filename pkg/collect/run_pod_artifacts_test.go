@@ -0,0 +1,86 @@
+package collect
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestCreatePodStruct_artifacts(t *testing.T) {
+	runPodCollector := &troubleshootv1beta2.RunPod{
+		Name: "diagnostics",
+		PodSpec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{Name: "report", Image: "report-image"},
+				{Name: "profile", Image: "profile-image"},
+			},
+		},
+		Artifacts: &troubleshootv1beta2.RunPodArtifacts{Paths: []string{"report.json"}},
+	}
+
+	pod := createPodStruct(runPodCollector)
+
+	mount := corev1.VolumeMount{Name: "troubleshoot-artifacts", MountPath: "/troubleshoot/artifacts"}
+	assert.Equal(t, corev1.RestartPolicyNever, pod.Spec.RestartPolicy)
+	assert.Equal(t, []corev1.Volume{
+		{Name: "troubleshoot-artifacts", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}}},
+	}, pod.Spec.Volumes)
+	require.Len(t, pod.Spec.Containers, 3)
+	assert.Equal(t, []corev1.VolumeMount{mount}, pod.Spec.Containers[0].VolumeMounts)
+	assert.Equal(t, []corev1.VolumeMount{mount}, pod.Spec.Containers[1].VolumeMounts)
+	assert.Equal(t, "troubleshoot-artifacts", pod.Spec.Containers[2].Name)
+	assert.Equal(t, "busybox:1.36", pod.Spec.Containers[2].Image)
+	assert.Equal(t, []corev1.VolumeMount{mount}, pod.Spec.Containers[2].VolumeMounts)
+
+	// the spec of the collector is left as is for it to be run again
+	assert.Len(t, runPodCollector.PodSpec.Containers, 2)
+	assert.Empty(t, runPodCollector.PodSpec.Containers[0].VolumeMounts)
+	assert.Empty(t, runPodCollector.PodSpec.Volumes)
+}
+
+func TestCreatePodStruct_artifactsOptions(t *testing.T) {
+	pod := createPodStruct(&troubleshootv1beta2.RunPod{
+		PodSpec: corev1.PodSpec{
+			RestartPolicy: corev1.RestartPolicyOnFailure,
+			Containers:    []corev1.Container{{Name: "report", Image: "report-image"}},
+		},
+		Artifacts: &troubleshootv1beta2.RunPodArtifacts{MountPath: "/out", Image: "registry.example.com/busybox:1.36"},
+	})
+
+	assert.Equal(t, corev1.RestartPolicyOnFailure, pod.Spec.RestartPolicy)
+	require.Len(t, pod.Spec.Containers, 2)
+	assert.Equal(t, "/out", pod.Spec.Containers[0].VolumeMounts[0].MountPath)
+	assert.Equal(t, "registry.example.com/busybox:1.36", pod.Spec.Containers[1].Image)
+}
+
+func Test_waitForContainersToExit(t *testing.T) {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "diagnostics", Namespace: "default"},
+		Status: corev1.PodStatus{
+			ContainerStatuses: []corev1.ContainerStatus{
+				{Name: "report", State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 0}}},
+				{Name: "troubleshoot-artifacts", State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}},
+			},
+		},
+	}
+	client := fake.NewSimpleClientset(pod)
+
+	err := waitForContainersToExit(context.Background(), client, pod)
+	assert.NoError(t, err)
+
+	running := pod.DeepCopy()
+	running.Status.ContainerStatuses[0].State = corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}
+	client = fake.NewSimpleClientset(running)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	err = waitForContainersToExit(ctx, client, running)
+	assert.Error(t, err)
+}
@@ -8,7 +8,6 @@ import (
 	"strconv"
 	"time"
 
-	"github.com/pkg/errors"
 	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
 	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
@@ -17,52 +16,15 @@ import (
 	runtime "k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/util/retry"
-	"k8s.io/klog/v2"
 	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
 const (
-	runnerContainerName      = "collector"
-	runnerJobType            = "remote-collector"
-	runnerServiceAccountName = ""
+	runnerContainerName = "collector"
+	runnerJobType       = "remote-collector"
 )
 
-type podRunner struct {
-	client       *kubernetes.Clientset
-	scheme       *runtime.Scheme
-	image        string
-	pullPolicy   string
-	waitInterval time.Duration
-}
-
-func (r *podRunner) run(ctx context.Context, collector *troubleshootv1beta2.HostCollect, namespace string, name string, nodeName string, results chan<- map[string][]byte) error {
-	cm, pod, err := CreateCollector(ctx, r.client, r.scheme, nil, name, namespace, nodeName, runnerServiceAccountName, runnerJobType, collector, r.image, r.pullPolicy)
-	if err != nil {
-		return errors.Wrap(err, "failed to create collector")
-	}
-
-	defer func() {
-		if err := r.client.CoreV1().Pods(namespace).Delete(context.Background(), pod.Name, metav1.DeleteOptions{}); err != nil {
-			klog.Errorf("Failed to delete pod %s: %v\n", pod.Name, err)
-		}
-		if err := r.client.CoreV1().ConfigMaps(namespace).Delete(context.Background(), cm.Name, metav1.DeleteOptions{}); err != nil {
-			klog.Errorf("Failed to delete configmap %s: %v\n", pod.Name, err)
-		}
-	}()
-
-	logs, err := GetContainerLogs(ctx, r.client, namespace, pod.Name, runnerContainerName, true, r.waitInterval)
-	if err != nil {
-		return errors.Wrap(err, "failed to retrieve collector logs")
-	}
-
-	results <- map[string][]byte{
-		nodeName: logs,
-	}
-
-	return nil
-}
-
 func CreateCollector(ctx context.Context, client *kubernetes.Clientset, scheme *runtime.Scheme, ownerRef metav1.Object, name string, namespace string, nodeName string, serviceAccountName string, jobType string, collect *troubleshootv1beta2.HostCollect, image string, pullPolicy string) (*corev1.ConfigMap, *corev1.Pod, error) {
 	configMap, err := createCollectorConfigMap(client, scheme, ownerRef, name, namespace, collect)
 	if err != nil {
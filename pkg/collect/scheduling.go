@@ -0,0 +1,143 @@
+package collect
+
+import (
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/k8sutil"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+)
+
+var nodeSelectorOperators = map[corev1.NodeSelectorOperator]selection.Operator{
+	corev1.NodeSelectorOpIn:           selection.In,
+	corev1.NodeSelectorOpNotIn:        selection.NotIn,
+	corev1.NodeSelectorOpExists:       selection.Exists,
+	corev1.NodeSelectorOpDoesNotExist: selection.DoesNotExist,
+	corev1.NodeSelectorOpGt:           selection.GreaterThan,
+	corev1.NodeSelectorOpLt:           selection.LessThan,
+}
+
+// applyPodScheduling sets the node selector, affinity and tolerations of the collector on the
+// spec of the pods it runs. The tolerations are added to those the collector sets by default.
+func applyPodScheduling(podSpec *corev1.PodSpec, scheduling troubleshootv1beta2.PodScheduling) {
+	if len(scheduling.NodeSelector) > 0 {
+		if podSpec.NodeSelector == nil {
+			podSpec.NodeSelector = map[string]string{}
+		}
+		for key, value := range scheduling.NodeSelector {
+			podSpec.NodeSelector[key] = value
+		}
+	}
+	if scheduling.Affinity != nil {
+		podSpec.Affinity = scheduling.Affinity.DeepCopy()
+	}
+	podSpec.Tolerations = append(podSpec.Tolerations, scheduling.Tolerations...)
+}
+
+// schedulableNodes returns the names of the ready nodes that pods with the spec can be scheduled
+// on: those matching its node selector and required node affinity, whose NoSchedule and NoExecute
+// taints it tolerates
+func schedulableNodes(nodes []corev1.Node, podSpec *corev1.PodSpec) ([]string, error) {
+	nodeNames := []string{}
+	for _, node := range nodes {
+		if !k8sutil.NodeIsReady(node) {
+			continue
+		}
+		match, err := nodeMatchesPodSpec(node, podSpec)
+		if err != nil {
+			return nil, err
+		}
+		if match {
+			nodeNames = append(nodeNames, node.Name)
+		}
+	}
+	return nodeNames, nil
+}
+
+func nodeMatchesPodSpec(node corev1.Node, podSpec *corev1.PodSpec) (bool, error) {
+	if !labels.SelectorFromSet(podSpec.NodeSelector).Matches(labels.Set(node.Labels)) {
+		return false, nil
+	}
+
+	if podSpec.Affinity != nil && podSpec.Affinity.NodeAffinity != nil && podSpec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution != nil {
+		// the terms are ORed
+		match := false
+		for _, term := range podSpec.Affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms {
+			termMatch, err := nodeMatchesSelectorTerm(node, term)
+			if err != nil {
+				return false, err
+			}
+			if termMatch {
+				match = true
+				break
+			}
+		}
+		if !match {
+			return false, nil
+		}
+	}
+
+	for _, taint := range node.Spec.Taints {
+		if taint.Effect == corev1.TaintEffectPreferNoSchedule {
+			continue
+		}
+		if !toleratesTaint(podSpec.Tolerations, taint) {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// nodeMatchesSelectorTerm returns whether the node matches all the requirements of the term. An
+// empty term matches no node, and metadata.name is the only field that can be matched.
+func nodeMatchesSelectorTerm(node corev1.Node, term corev1.NodeSelectorTerm) (bool, error) {
+	if len(term.MatchExpressions) == 0 && len(term.MatchFields) == 0 {
+		return false, nil
+	}
+
+	labelSelector, err := nodeSelectorRequirements(term.MatchExpressions)
+	if err != nil {
+		return false, err
+	}
+	if !labelSelector.Matches(labels.Set(node.Labels)) {
+		return false, nil
+	}
+
+	for _, requirement := range term.MatchFields {
+		if requirement.Key != "metadata.name" {
+			return false, errors.Errorf("unsupported node field %q", requirement.Key)
+		}
+	}
+	fieldSelector, err := nodeSelectorRequirements(term.MatchFields)
+	if err != nil {
+		return false, err
+	}
+	return fieldSelector.Matches(labels.Set{"metadata.name": node.Name}), nil
+}
+
+func nodeSelectorRequirements(requirements []corev1.NodeSelectorRequirement) (labels.Selector, error) {
+	selector := labels.NewSelector()
+	for _, requirement := range requirements {
+		operator, ok := nodeSelectorOperators[requirement.Operator]
+		if !ok {
+			return nil, errors.Errorf("unknown node selector operator %q", requirement.Operator)
+		}
+		r, err := labels.NewRequirement(requirement.Key, operator, requirement.Values)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid node selector requirement on %q", requirement.Key)
+		}
+		selector = selector.Add(*r)
+	}
+	return selector, nil
+}
+
+func toleratesTaint(tolerations []corev1.Toleration, taint corev1.Taint) bool {
+	for _, toleration := range tolerations {
+		if toleration.ToleratesTaint(&taint) {
+			return true
+		}
+	}
+	return false
+}
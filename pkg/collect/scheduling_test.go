@@ -0,0 +1,143 @@
+package collect
+
+import (
+	"testing"
+
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/k8sutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func testNode(name string, nodeLabels map[string]string, taints ...corev1.Taint) corev1.Node {
+	return corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: nodeLabels},
+		Spec:       corev1.NodeSpec{Taints: taints},
+	}
+}
+
+func TestSchedulableNodes(t *testing.T) {
+	gpuTaint := corev1.Taint{Key: "nvidia.com/gpu", Value: "present", Effect: corev1.TaintEffectNoSchedule}
+	nodes := []corev1.Node{
+		testNode("worker-1", map[string]string{"pool": "general"}),
+		testNode("worker-2", map[string]string{"pool": "general"}, corev1.Taint{Key: "spot", Effect: corev1.TaintEffectPreferNoSchedule}),
+		testNode("gpu-1", map[string]string{"pool": "gpu", "gpu-count": "4"}, gpuTaint),
+		testNode("gpu-2", map[string]string{"pool": "gpu", "gpu-count": "8"}, gpuTaint),
+		testNode("not-ready", map[string]string{"pool": "general"}, corev1.Taint{Key: k8sutil.NotReadyTaint, Effect: corev1.TaintEffectNoSchedule}),
+	}
+
+	tests := []struct {
+		name       string
+		scheduling troubleshootv1beta2.PodScheduling
+		want       []string
+		wantErr    bool
+	}{
+		{
+			name: "untainted nodes by default",
+			want: []string{"worker-1", "worker-2"},
+		},
+		{
+			name: "tolerated taints",
+			scheduling: troubleshootv1beta2.PodScheduling{
+				Tolerations: []corev1.Toleration{{Key: "nvidia.com/gpu", Operator: corev1.TolerationOpExists}},
+			},
+			want: []string{"worker-1", "worker-2", "gpu-1", "gpu-2"},
+		},
+		{
+			name: "node selector",
+			scheduling: troubleshootv1beta2.PodScheduling{
+				NodeSelector: map[string]string{"pool": "gpu"},
+				Tolerations:  []corev1.Toleration{{Key: "nvidia.com/gpu", Operator: corev1.TolerationOpEqual, Value: "present", Effect: corev1.TaintEffectNoSchedule}},
+			},
+			want: []string{"gpu-1", "gpu-2"},
+		},
+		{
+			name: "node selector with untolerated taint",
+			scheduling: troubleshootv1beta2.PodScheduling{
+				NodeSelector: map[string]string{"pool": "gpu"},
+			},
+			want: []string{},
+		},
+		{
+			name: "required node affinity",
+			scheduling: troubleshootv1beta2.PodScheduling{
+				Affinity: &corev1.Affinity{
+					NodeAffinity: &corev1.NodeAffinity{
+						RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+							NodeSelectorTerms: []corev1.NodeSelectorTerm{
+								{MatchExpressions: []corev1.NodeSelectorRequirement{{Key: "gpu-count", Operator: corev1.NodeSelectorOpGt, Values: []string{"4"}}}},
+								{MatchFields: []corev1.NodeSelectorRequirement{{Key: "metadata.name", Operator: corev1.NodeSelectorOpIn, Values: []string{"worker-1"}}}},
+							},
+						},
+					},
+				},
+				Tolerations: []corev1.Toleration{{Operator: corev1.TolerationOpExists}},
+			},
+			want: []string{"worker-1", "gpu-2"},
+		},
+		{
+			name: "unsupported node field",
+			scheduling: troubleshootv1beta2.PodScheduling{
+				Affinity: &corev1.Affinity{
+					NodeAffinity: &corev1.NodeAffinity{
+						RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+							NodeSelectorTerms: []corev1.NodeSelectorTerm{
+								{MatchFields: []corev1.NodeSelectorRequirement{{Key: "spec.podCIDR", Operator: corev1.NodeSelectorOpExists}}},
+							},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			podSpec := corev1.PodSpec{}
+			applyPodScheduling(&podSpec, test.scheduling)
+
+			got, err := schedulableNodes(nodes, &podSpec)
+			if test.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
+
+func TestApplyPodScheduling(t *testing.T) {
+	podSpec := corev1.PodSpec{
+		NodeSelector: map[string]string{"kubernetes.io/os": "linux"},
+		Tolerations:  []corev1.Toleration{{Key: "node-role.kubernetes.io/control-plane", Operator: corev1.TolerationOpExists}},
+	}
+	applyPodScheduling(&podSpec, troubleshootv1beta2.PodScheduling{
+		NodeSelector: map[string]string{"kubernetes.io/hostname": "worker-1"},
+		Tolerations:  []corev1.Toleration{{Key: "dedicated", Operator: corev1.TolerationOpExists}},
+	})
+
+	assert.Equal(t, map[string]string{"kubernetes.io/os": "linux", "kubernetes.io/hostname": "worker-1"}, podSpec.NodeSelector)
+	assert.Len(t, podSpec.Tolerations, 2)
+	assert.Nil(t, podSpec.Affinity)
+}
+
+func TestRunPodOnNode(t *testing.T) {
+	collector := &troubleshootv1beta2.RunPod{
+		Name:      "gpu-check",
+		EveryNode: true,
+		PodSpec: corev1.PodSpec{
+			NodeSelector: map[string]string{"pool": "gpu"},
+		},
+	}
+
+	onNode := runPodOnNode(collector, "gpu-1")
+	assert.Equal(t, "gpu-check/gpu-1", onNode.Name)
+	assert.Equal(t, "gpu-check-gpu-1", createPodStruct(onNode).Name)
+	assert.False(t, onNode.EveryNode)
+	assert.Equal(t, map[string]string{"pool": "gpu", "kubernetes.io/hostname": "gpu-1"}, onNode.PodSpec.NodeSelector)
+	assert.Equal(t, map[string]string{"pool": "gpu"}, collector.PodSpec.NodeSelector, "the collector is not modified")
+}
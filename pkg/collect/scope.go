@@ -0,0 +1,141 @@
+package collect
+
+import (
+	"reflect"
+	"slices"
+
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/constants"
+)
+
+// ClusterScopedResources are the cluster resources that cannot be read with namespaced
+// permissions, and are not collected in namespaced scope.
+var ClusterScopedResources = []string{
+	constants.CLUSTER_RESOURCES_NODES,
+	constants.CLUSTER_RESOURCES_STORAGE_CLASS,
+	constants.CLUSTER_RESOURCES_PRIORITY_CLASS,
+	constants.CLUSTER_RESOURCES_CUSTOM_RESOURCE_DEFINITIONS,
+	constants.CLUSTER_RESOURCES_CUSTOM_RESOURCES,
+	constants.CLUSTER_RESOURCES_PVS,
+	constants.CLUSTER_RESOURCES_CLUSTER_ROLES,
+	constants.CLUSTER_RESOURCES_CLUSTER_ROLE_BINDINGS,
+	constants.CLUSTER_RESOURCES_VOLUME_ATTACHMENTS,
+}
+
+// NamespacedScope restricts collection to a list of namespaces.
+type NamespacedScope struct {
+	Namespaces []string
+	// Namespace is used by collectors that do not set a namespace
+	Namespace string
+}
+
+// NewNamespacedScope returns the scope of a spec, or nil when the spec is cluster scoped. The
+// namespaces of the spec default to defaultNamespace, which is also used by collectors without a
+// namespace when it is in scope.
+func NewNamespacedScope(spec *troubleshootv1beta2.SupportBundleSpec, defaultNamespace string) (*NamespacedScope, error) {
+	switch spec.Scope {
+	case "", troubleshootv1beta2.CollectionScopeCluster:
+		return nil, nil
+	case troubleshootv1beta2.CollectionScopeNamespaced:
+	default:
+		return nil, errors.Errorf("unknown scope %q, must be %s or %s", spec.Scope, troubleshootv1beta2.CollectionScopeCluster, troubleshootv1beta2.CollectionScopeNamespaced)
+	}
+
+	scope := &NamespacedScope{}
+	for _, namespace := range spec.Namespaces {
+		if namespace != "" && !slices.Contains(scope.Namespaces, namespace) {
+			scope.Namespaces = append(scope.Namespaces, namespace)
+		}
+	}
+	if len(scope.Namespaces) == 0 {
+		if defaultNamespace == "" {
+			return nil, errors.New("namespaced scope requires a list of namespaces or a namespace to run in")
+		}
+		scope.Namespaces = []string{defaultNamespace}
+	}
+
+	scope.Namespace = scope.Namespaces[0]
+	if slices.Contains(scope.Namespaces, defaultNamespace) {
+		scope.Namespace = defaultNamespace
+	}
+	return scope, nil
+}
+
+// IsClusterScoped returns whether the collector needs cluster-wide access, to schedule pods on
+// every node or to read nodes, kube-system and cluster-scoped resources.
+func IsClusterScoped(c Collector) bool {
+	switch c.(type) {
+	case *CollectNodeMetrics, *CollectRunDaemonSet, *CollectCopyFromHost, *CollectCollectd, *CollectSysctl, *CollectEtcd, *CollectDNS, *CollectNodeLatency, *CollectOpenShift, *CollectKubeletConfig, *CollectTrustChains, *CollectKubeletMetrics:
+		return true
+	}
+	return false
+}
+
+// Restrict limits the collector to the namespaces of the scope. It returns false, and records
+// the collector as skipped, when the collector cannot run in scope. Collectors without a
+// namespace are set to the namespace of the scope, and cluster resources are only collected
+// from the namespaces of the scope.
+func (s *NamespacedScope) Restrict(c Collector, skipped *SkippedCollectors) bool {
+	if clusterResources, ok := c.(*CollectClusterResources); ok {
+		return s.restrictClusterResources(clusterResources, skipped)
+	}
+
+	if IsClusterScoped(c) {
+		skipped.AddCollector(c, SkipReasonClusterScoped)
+		return false
+	}
+
+	spec := reflect.ValueOf(c).Elem().FieldByName("Collector")
+	if !spec.IsValid() || spec.Kind() != reflect.Ptr || spec.IsNil() {
+		return true
+	}
+	namespace := spec.Elem().FieldByName("Namespace")
+	if !namespace.IsValid() || namespace.Kind() != reflect.String {
+		return true
+	}
+
+	if namespace.String() == "" {
+		// copy the spec so that the caller's is left untouched
+		scoped := reflect.New(spec.Elem().Type())
+		scoped.Elem().Set(spec.Elem())
+		scoped.Elem().FieldByName("Namespace").SetString(s.Namespace)
+		spec.Set(scoped)
+		return true
+	}
+
+	if !slices.Contains(s.Namespaces, namespace.String()) {
+		skipped.AddCollector(c, SkipReasonNamespaceOutOfScope)
+		return false
+	}
+	return true
+}
+
+func (s *NamespacedScope) restrictClusterResources(c *CollectClusterResources, skipped *SkippedCollectors) bool {
+	spec := c.Collector.DeepCopy()
+
+	if len(spec.Namespaces) == 0 {
+		spec.Namespaces = s.Namespaces
+	} else {
+		namespaces := []string{}
+		for _, namespace := range spec.Namespaces {
+			if slices.Contains(s.Namespaces, namespace) {
+				namespaces = append(namespaces, namespace)
+			}
+		}
+		if len(namespaces) == 0 {
+			skipped.AddCollector(c, SkipReasonNamespaceOutOfScope)
+			return false
+		}
+		spec.Namespaces = namespaces
+	}
+
+	for _, resource := range ClusterScopedResources {
+		if !slices.Contains(spec.ExcludeResources, resource) {
+			spec.ExcludeResources = append(spec.ExcludeResources, resource)
+		}
+	}
+
+	c.Collector = spec
+	return true
+}
@@ -0,0 +1,142 @@
+package collect
+
+import (
+	"testing"
+
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewNamespacedScope(t *testing.T) {
+	tests := []struct {
+		name             string
+		spec             troubleshootv1beta2.SupportBundleSpec
+		defaultNamespace string
+		want             *NamespacedScope
+		wantErr          bool
+	}{
+		{
+			name:             "cluster scope by default",
+			defaultNamespace: "default",
+		},
+		{
+			name:             "cluster scope",
+			spec:             troubleshootv1beta2.SupportBundleSpec{Scope: "cluster"},
+			defaultNamespace: "default",
+		},
+		{
+			name:             "namespaces default to the namespace of the run",
+			spec:             troubleshootv1beta2.SupportBundleSpec{Scope: "namespaced"},
+			defaultNamespace: "app",
+			want:             &NamespacedScope{Namespaces: []string{"app"}, Namespace: "app"},
+		},
+		{
+			name:             "namespace of the run out of scope",
+			spec:             troubleshootv1beta2.SupportBundleSpec{Scope: "namespaced", Namespaces: []string{"app", "db", "app"}},
+			defaultNamespace: "default",
+			want:             &NamespacedScope{Namespaces: []string{"app", "db"}, Namespace: "app"},
+		},
+		{
+			name:             "namespace of the run in scope",
+			spec:             troubleshootv1beta2.SupportBundleSpec{Scope: "namespaced", Namespaces: []string{"app", "db"}},
+			defaultNamespace: "db",
+			want:             &NamespacedScope{Namespaces: []string{"app", "db"}, Namespace: "db"},
+		},
+		{
+			name:    "no namespaces",
+			spec:    troubleshootv1beta2.SupportBundleSpec{Scope: "namespaced"},
+			wantErr: true,
+		},
+		{
+			name:    "unknown scope",
+			spec:    troubleshootv1beta2.SupportBundleSpec{Scope: "node"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewNamespacedScope(&tt.spec, tt.defaultNamespace)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestNamespacedScope_Restrict(t *testing.T) {
+	scope := &NamespacedScope{Namespaces: []string{"app", "db"}, Namespace: "app"}
+
+	t.Run("collector without a namespace", func(t *testing.T) {
+		spec := &troubleshootv1beta2.Logs{Selector: []string{"app=api"}}
+		collector := &CollectLogs{Collector: spec}
+
+		skipped := SkippedCollectors{}
+		assert.True(t, scope.Restrict(collector, &skipped))
+		assert.Empty(t, skipped)
+		assert.Equal(t, "app", collector.Collector.Namespace)
+		assert.Equal(t, []string{"app=api"}, collector.Collector.Selector)
+		assert.Empty(t, spec.Namespace, "the spec is copied")
+	})
+
+	t.Run("collector in scope", func(t *testing.T) {
+		collector := &CollectSecret{Collector: &troubleshootv1beta2.Secret{Namespace: "db"}}
+
+		skipped := SkippedCollectors{}
+		assert.True(t, scope.Restrict(collector, &skipped))
+		assert.Empty(t, skipped)
+		assert.Equal(t, "db", collector.Collector.Namespace)
+	})
+
+	t.Run("collector out of scope", func(t *testing.T) {
+		collector := &CollectLogs{Collector: &troubleshootv1beta2.Logs{
+			CollectorMeta: troubleshootv1beta2.CollectorMeta{CollectorName: "proxy"},
+			Namespace:     "kube-system",
+		}}
+
+		skipped := SkippedCollectors{}
+		assert.False(t, scope.Restrict(collector, &skipped))
+		assert.Equal(t, SkippedCollectors{{Collector: "logs", Name: "proxy", Reason: SkipReasonNamespaceOutOfScope}}, skipped)
+	})
+
+	t.Run("cluster-scoped collector", func(t *testing.T) {
+		collector := &CollectNodeMetrics{Collector: &troubleshootv1beta2.NodeMetrics{}}
+
+		skipped := SkippedCollectors{}
+		assert.False(t, scope.Restrict(collector, &skipped))
+		assert.Equal(t, SkippedCollectors{{Collector: "node-metrics", Reason: SkipReasonClusterScoped}}, skipped)
+	})
+
+	t.Run("cluster resources", func(t *testing.T) {
+		spec := &troubleshootv1beta2.ClusterResources{ExcludeResources: []string{"events"}}
+		collector := &CollectClusterResources{Collector: spec}
+
+		skipped := SkippedCollectors{}
+		assert.True(t, scope.Restrict(collector, &skipped))
+		assert.Empty(t, skipped)
+		assert.Equal(t, []string{"app", "db"}, collector.Collector.Namespaces)
+		assert.Equal(t, append([]string{"events"}, ClusterScopedResources...), collector.Collector.ExcludeResources)
+		assert.False(t, collector.shouldCollect("nodes"))
+		assert.True(t, collector.shouldCollect("pods"))
+		assert.Equal(t, []string{"events"}, spec.ExcludeResources, "the spec is copied")
+	})
+
+	t.Run("cluster resources of some namespaces", func(t *testing.T) {
+		collector := &CollectClusterResources{Collector: &troubleshootv1beta2.ClusterResources{Namespaces: []string{"db", "kube-system"}}}
+
+		skipped := SkippedCollectors{}
+		assert.True(t, scope.Restrict(collector, &skipped))
+		assert.Equal(t, []string{"db"}, collector.Collector.Namespaces)
+	})
+
+	t.Run("cluster resources out of scope", func(t *testing.T) {
+		collector := &CollectClusterResources{Collector: &troubleshootv1beta2.ClusterResources{Namespaces: []string{"kube-system"}}}
+
+		skipped := SkippedCollectors{}
+		assert.False(t, scope.Restrict(collector, &skipped))
+		assert.Equal(t, SkippedCollectors{{Collector: "cluster-resources", Reason: SkipReasonNamespaceOutOfScope}}, skipped)
+	})
+}
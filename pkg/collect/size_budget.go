@@ -0,0 +1,346 @@
+package collect
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// TruncatedFile is a file of the bundle that was truncated to fit a maxSize budget
+type TruncatedFile struct {
+	Path         string
+	OriginalSize int64
+	Size         int64
+}
+
+// ParseMaxSize parses a maxSize setting, a quantity such as 500Mi. An empty setting means there
+// is no limit, and 0 is returned.
+func ParseMaxSize(maxSize string) (int64, error) {
+	if maxSize == "" {
+		return 0, nil
+	}
+
+	quantity, err := resource.ParseQuantity(maxSize)
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to parse maxSize %q", maxSize)
+	}
+	size, ok := quantity.AsInt64()
+	if !ok || size <= 0 {
+		return 0, errors.Errorf("maxSize %q must be a number of bytes greater than 0", maxSize)
+	}
+	return size, nil
+}
+
+// GetCollectorMaxSize returns the maxSize of an in-cluster collector, or 0 when it has none
+func GetCollectorMaxSize(c Collector) (int64, error) {
+	spec := reflect.ValueOf(c).Elem().FieldByName("Collector")
+	if !spec.IsValid() || spec.Kind() != reflect.Ptr || spec.IsNil() {
+		return 0, nil
+	}
+	return specMaxSize(spec.Elem())
+}
+
+// GetHostCollectorMaxSize returns the maxSize of a host collector, or 0 when it has none
+func GetHostCollectorMaxSize(spec *troubleshootv1beta2.HostCollect) (int64, error) {
+	reflected := reflect.ValueOf(spec).Elem()
+	for i := 0; i < reflected.NumField(); i++ {
+		field := reflected.Field(i)
+		if field.Kind() == reflect.Ptr && !field.IsNil() {
+			return specMaxSize(field.Elem())
+		}
+	}
+	return 0, nil
+}
+
+func specMaxSize(spec reflect.Value) (int64, error) {
+	if spec.Kind() != reflect.Struct {
+		return 0, nil
+	}
+	maxSize := spec.FieldByName("MaxSize")
+	if !maxSize.IsValid() || maxSize.Kind() != reflect.String {
+		return 0, nil
+	}
+	return ParseMaxSize(maxSize.String())
+}
+
+// TruncateResult truncates the files of a result so that together they take at most maxSize
+// bytes. The budget is shared evenly, files smaller than their share leaving the rest to the
+// larger ones. Logs keep their most recent lines, JSON lists keep an evenly spaced sample of
+// their items, other JSON objects keep their smallest fields so that they are still valid JSON,
+// and other files keep their beginning. Text files are marked where they were cut.
+// Symlinks are not counted, they point to files of the result.
+func TruncateResult(output CollectorResult, bundlePath string, maxSize int64) ([]TruncatedFile, error) {
+	if maxSize <= 0 {
+		return nil, nil
+	}
+
+	sizes := map[string]int64{}
+	var total int64
+	for path := range output {
+		size, ok, err := resultFileSize(output, bundlePath, path)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get size of %s", path)
+		}
+		if ok {
+			sizes[path] = size
+			total += size
+		}
+	}
+	if total <= maxSize {
+		return nil, nil
+	}
+
+	budgets := shareBudget(sizes, maxSize)
+
+	paths := make([]string, 0, len(budgets))
+	for path := range budgets {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	truncated := []TruncatedFile{}
+	for _, path := range paths {
+		if budgets[path] >= sizes[path] {
+			continue
+		}
+
+		reader, err := output.GetReader(bundlePath, path)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read %s", path)
+		}
+		data, err := io.ReadAll(reader)
+		reader.Close()
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read %s", path)
+		}
+
+		data = truncateFile(path, data, budgets[path])
+		if err := output.ReplaceResult(bundlePath, path, bytes.NewReader(data)); err != nil {
+			return nil, errors.Wrapf(err, "failed to write truncated %s", path)
+		}
+		truncated = append(truncated, TruncatedFile{Path: path, OriginalSize: sizes[path], Size: int64(len(data))})
+	}
+
+	return truncated, nil
+}
+
+// ResultSize returns the total size of the files of a result, whether they are held in memory or
+// saved to the bundle on disk
+func ResultSize(output CollectorResult, bundlePath string) (int64, error) {
+	var total int64
+	for path := range output {
+		size, ok, err := resultFileSize(output, bundlePath, path)
+		if err != nil {
+			return 0, errors.Wrapf(err, "failed to get size of %s", path)
+		}
+		if ok {
+			total += size
+		}
+	}
+	return total, nil
+}
+
+// resultFileSize returns the size of a file of the result. ok is false for directories and
+// symlinks on disk.
+func resultFileSize(output CollectorResult, bundlePath string, path string) (size int64, ok bool, err error) {
+	if bundlePath == "" || output[path] != nil {
+		return int64(len(output[path])), true, nil
+	}
+
+	info, err := os.Lstat(filepath.Join(bundlePath, path))
+	if os.IsNotExist(err) {
+		return 0, false, nil
+	} else if err != nil {
+		return 0, false, err
+	}
+	if !info.Mode().IsRegular() {
+		return 0, false, nil
+	}
+	return info.Size(), true, nil
+}
+
+// shareBudget splits maxSize between files, starting from the smallest so that the share left
+// unused by a small file goes to the larger ones.
+func shareBudget(sizes map[string]int64, maxSize int64) map[string]int64 {
+	paths := make([]string, 0, len(sizes))
+	for path := range sizes {
+		paths = append(paths, path)
+	}
+	sort.Slice(paths, func(i, j int) bool {
+		if sizes[paths[i]] != sizes[paths[j]] {
+			return sizes[paths[i]] < sizes[paths[j]]
+		}
+		return paths[i] < paths[j]
+	})
+
+	budgets := map[string]int64{}
+	remaining := maxSize
+	for i, path := range paths {
+		budget := min(sizes[path], remaining/int64(len(paths)-i))
+		budgets[path] = budget
+		remaining -= budget
+	}
+	return budgets
+}
+
+func truncateFile(path string, data []byte, budget int64) []byte {
+	switch {
+	case strings.HasSuffix(path, ".log"):
+		return truncateTail(data, budget)
+	case strings.HasSuffix(path, ".json"):
+		if truncated, ok := truncateJSON(data, budget); ok {
+			return truncated
+		}
+	}
+	return truncateHead(data, budget)
+}
+
+func truncationMarker(removed int) []byte {
+	return []byte(fmt.Sprintf("[troubleshoot: truncated %d bytes to fit maxSize]\n", removed))
+}
+
+// truncateTail keeps the last lines of data that fit the budget, after a truncation marker
+func truncateTail(data []byte, budget int64) []byte {
+	keep := max(budget-int64(len(truncationMarker(len(data)))), 0)
+	tail := data[int64(len(data))-keep:]
+	if i := bytes.IndexByte(tail, '\n'); i >= 0 {
+		tail = tail[i+1:]
+	}
+	return append(truncationMarker(len(data)-len(tail)), tail...)
+}
+
+// truncateHead keeps the first lines of data that fit the budget, followed by a truncation marker
+func truncateHead(data []byte, budget int64) []byte {
+	keep := max(budget-int64(len(truncationMarker(len(data))))-1, 0)
+	head := data[:keep]
+	if i := bytes.LastIndexByte(head, '\n'); i >= 0 {
+		head = head[:i+1]
+	}
+
+	truncated := append([]byte{}, head...)
+	if len(head) > 0 && head[len(head)-1] != '\n' {
+		truncated = append(truncated, '\n')
+	}
+	return append(truncated, truncationMarker(len(data)-len(head))...)
+}
+
+// truncatedJSONFieldsKey lists the fields that were dropped from a truncated JSON object
+const truncatedJSONFieldsKey = "troubleshoot.sh/truncatedFields"
+
+// truncateJSON truncates a JSON list or object so that it is still valid JSON. Lists keep a sample
+// of their items, and objects their smallest fields. An array with no sample that fits the budget
+// is emptied. ok is false when data is neither.
+func truncateJSON(data []byte, budget int64) ([]byte, bool) {
+	if sampled, ok := sampleJSONList(data, budget); ok {
+		return sampled, true
+	}
+
+	var items []json.RawMessage
+	if err := json.Unmarshal(data, &items); err == nil {
+		return []byte("[]"), true
+	}
+	return dropJSONFields(data, budget)
+}
+
+// dropJSONFields drops the largest fields of a JSON object until it fits the budget, or until
+// none are left, and lists them in a troubleshoot.sh/truncatedFields field. ok is false when data
+// is not an object.
+func dropJSONFields(data []byte, budget int64) ([]byte, bool) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil || fields == nil {
+		return nil, false
+	}
+
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if len(fields[names[i]]) != len(fields[names[j]]) {
+			return len(fields[names[i]]) > len(fields[names[j]])
+		}
+		return names[i] < names[j]
+	})
+
+	dropped := []string{}
+	for _, name := range names {
+		dropped = append(dropped, name)
+		delete(fields, name)
+
+		droppedJSON, err := json.Marshal(dropped)
+		if err != nil {
+			return nil, false
+		}
+		fields[truncatedJSONFieldsKey] = droppedJSON
+		b, err := json.MarshalIndent(fields, "", "  ")
+		if err != nil {
+			return nil, false
+		}
+		if int64(len(b)) <= budget || len(fields) == 1 {
+			return b, true
+		}
+		delete(fields, truncatedJSONFieldsKey)
+	}
+
+	return nil, false
+}
+
+// sampleJSONList keeps an evenly spaced sample of the items of a JSON array, or of a list object
+// with an items array such as the lists of the cluster resources collector. ok is false when
+// data is not a list, or when no sample fits the budget.
+func sampleJSONList(data []byte, budget int64) ([]byte, bool) {
+	var items []json.RawMessage
+	var list map[string]json.RawMessage
+	if err := json.Unmarshal(data, &items); err != nil {
+		if err := json.Unmarshal(data, &list); err != nil || list["items"] == nil {
+			return nil, false
+		}
+		if err := json.Unmarshal(list["items"], &items); err != nil {
+			return nil, false
+		}
+	}
+	if len(items) == 0 {
+		return nil, false
+	}
+
+	encode := func(sample []json.RawMessage) ([]byte, error) {
+		if list == nil {
+			return json.MarshalIndent(sample, "", "  ")
+		}
+		b, err := json.Marshal(sample)
+		if err != nil {
+			return nil, err
+		}
+		list["items"] = b
+		return json.MarshalIndent(list, "", "  ")
+	}
+
+	n := min(int64(len(items))*budget/int64(len(data)), int64(len(items))-1)
+	for n > 0 {
+		sample := make([]json.RawMessage, 0, n)
+		for i := int64(0); i < n; i++ {
+			sample = append(sample, items[i*int64(len(items))/n])
+		}
+
+		b, err := encode(sample)
+		if err != nil {
+			return nil, false
+		}
+		if int64(len(b)) <= budget {
+			return b, true
+		}
+		n = min(n*9/10, n-1)
+	}
+
+	return nil, false
+}
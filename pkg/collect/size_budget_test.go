@@ -0,0 +1,197 @@
+package collect
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMaxSize(t *testing.T) {
+	tests := []struct {
+		maxSize string
+		want    int64
+		wantErr bool
+	}{
+		{maxSize: "", want: 0},
+		{maxSize: "100", want: 100},
+		{maxSize: "1Ki", want: 1024},
+		{maxSize: "5M", want: 5000000},
+		{maxSize: "0", wantErr: true},
+		{maxSize: "-1Mi", wantErr: true},
+		{maxSize: "lots", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.maxSize, func(t *testing.T) {
+			got, err := ParseMaxSize(tt.maxSize)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestGetMaxSize(t *testing.T) {
+	collector := &CollectLogs{Collector: &troubleshootv1beta2.Logs{CollectorMeta: troubleshootv1beta2.CollectorMeta{MaxSize: "1Ki"}}}
+	maxSize, err := GetCollectorMaxSize(collector)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1024), maxSize)
+
+	maxSize, err = GetCollectorMaxSize(&CollectSecret{Collector: &troubleshootv1beta2.Secret{}})
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), maxSize)
+
+	maxSize, err = GetHostCollectorMaxSize(&troubleshootv1beta2.HostCollect{
+		HostJournald: &troubleshootv1beta2.HostJournald{HostCollectorMeta: troubleshootv1beta2.HostCollectorMeta{MaxSize: "2Ki"}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2048), maxSize)
+}
+
+func TestShareBudget(t *testing.T) {
+	sizes := map[string]int64{"small": 10, "medium": 100, "large": 1000}
+
+	assert.Equal(t, map[string]int64{"small": 10, "medium": 100, "large": 190}, shareBudget(sizes, 300))
+	assert.Equal(t, map[string]int64{"small": 10, "medium": 45, "large": 45}, shareBudget(sizes, 100))
+}
+
+func TestTruncateTail(t *testing.T) {
+	lines := []string{}
+	for i := 0; i < 100; i++ {
+		lines = append(lines, fmt.Sprintf("line %d", i))
+	}
+	data := []byte(strings.Join(lines, "\n") + "\n")
+
+	got := truncateTail(data, 100)
+	assert.LessOrEqual(t, len(got), 100)
+	assert.True(t, strings.HasPrefix(string(got), "[troubleshoot: truncated "))
+	assert.True(t, strings.HasSuffix(string(got), "\nline 98\nline 99\n"))
+	// the first kept line is whole
+	assert.Contains(t, string(got), "maxSize]\nline ")
+}
+
+func TestTruncateHead(t *testing.T) {
+	data := []byte(strings.Repeat("0123456789\n", 20))
+
+	got := truncateHead(data, 80)
+	assert.LessOrEqual(t, len(got), 80)
+	assert.True(t, strings.HasPrefix(string(got), "0123456789\n"))
+	assert.True(t, strings.HasSuffix(string(got), "0123456789\n[troubleshoot: truncated 198 bytes to fit maxSize]\n"))
+}
+
+func TestSampleJSONList(t *testing.T) {
+	items := []map[string]int{}
+	for i := 0; i < 100; i++ {
+		items = append(items, map[string]int{"index": i})
+	}
+
+	t.Run("list object", func(t *testing.T) {
+		data, err := json.MarshalIndent(map[string]interface{}{"kind": "PodList", "items": items}, "", "  ")
+		require.NoError(t, err)
+
+		got, ok := sampleJSONList(data, int64(len(data)/4))
+		require.True(t, ok)
+		assert.LessOrEqual(t, len(got), len(data)/4)
+
+		sampled := struct {
+			Kind  string           `json:"kind"`
+			Items []map[string]int `json:"items"`
+		}{}
+		require.NoError(t, json.Unmarshal(got, &sampled))
+		assert.Equal(t, "PodList", sampled.Kind)
+		require.NotEmpty(t, sampled.Items)
+		assert.Less(t, len(sampled.Items), 100)
+		assert.Equal(t, 0, sampled.Items[0]["index"])
+		// items are spread over the whole list
+		assert.Greater(t, sampled.Items[len(sampled.Items)-1]["index"], 50)
+	})
+
+	t.Run("array", func(t *testing.T) {
+		data, err := json.Marshal(items)
+		require.NoError(t, err)
+
+		got, ok := sampleJSONList(data, int64(len(data)/2))
+		require.True(t, ok)
+		sampled := []map[string]int{}
+		require.NoError(t, json.Unmarshal(got, &sampled))
+		assert.NotEmpty(t, sampled)
+	})
+
+	t.Run("not a list", func(t *testing.T) {
+		_, ok := sampleJSONList([]byte(`{"kind": "Pod", "metadata": {"name": "api"}}`), 10)
+		assert.False(t, ok)
+	})
+}
+
+func TestTruncateJSON(t *testing.T) {
+	t.Run("object", func(t *testing.T) {
+		data := []byte(`{"kind": "ConfigMap", "metadata": {"name": "app"}, "data": {"large": "` + strings.Repeat("x", 1000) + `"}}`)
+
+		got := truncateFile("configmaps/app.json", data, 200)
+		assert.LessOrEqual(t, len(got), 200)
+		truncated := map[string]interface{}{}
+		require.NoError(t, json.Unmarshal(got, &truncated))
+		assert.Equal(t, map[string]interface{}{
+			"kind":                            "ConfigMap",
+			"metadata":                        map[string]interface{}{"name": "app"},
+			"troubleshoot.sh/truncatedFields": []interface{}{"data"},
+		}, truncated)
+	})
+
+	t.Run("list without a sample that fits", func(t *testing.T) {
+		data := []byte(`[{"large": "` + strings.Repeat("x", 1000) + `"}, {"large": "` + strings.Repeat("y", 1000) + `"}]`)
+
+		got := truncateFile("items.json", data, 100)
+		assert.Equal(t, "[]", string(got))
+	})
+
+	t.Run("not json", func(t *testing.T) {
+		data := []byte(strings.Repeat("0123456789\n", 20))
+
+		got := truncateFile("broken.json", data, 80)
+		assert.Equal(t, truncateHead(data, 80), got)
+	})
+}
+
+func TestTruncateResult(t *testing.T) {
+	bundlePath := t.TempDir()
+	output := NewResult()
+
+	logs := strings.Repeat("a log line\n", 1000)
+	require.NoError(t, output.SaveResult(bundlePath, "logs/api.log", strings.NewReader(logs)))
+	require.NoError(t, output.SaveResult(bundlePath, "config.yaml", strings.NewReader("key: value\n")))
+	require.NoError(t, output.SymLinkResult(bundlePath, "logs/api-link.log", "logs/api.log"))
+
+	size, err := ResultSize(output, bundlePath)
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(logs)+len("key: value\n")), size, "symlinks are not counted")
+
+	truncated, err := TruncateResult(output, bundlePath, 1000)
+	require.NoError(t, err)
+	require.Len(t, truncated, 1)
+	assert.Equal(t, "logs/api.log", truncated[0].Path)
+	assert.Equal(t, int64(len(logs)), truncated[0].OriginalSize)
+	assert.LessOrEqual(t, truncated[0].Size, int64(1000-len("key: value\n")))
+
+	b, err := os.ReadFile(filepath.Join(bundlePath, "logs/api.log"))
+	require.NoError(t, err)
+	assert.Equal(t, truncated[0].Size, int64(len(b)))
+	assert.True(t, strings.HasSuffix(string(b), "a log line\n"))
+
+	b, err = os.ReadFile(filepath.Join(bundlePath, "config.yaml"))
+	require.NoError(t, err)
+	assert.Equal(t, "key: value\n", string(b))
+
+	truncated, err = TruncateResult(output, bundlePath, 1000)
+	require.NoError(t, err)
+	assert.Empty(t, truncated, "the result fits")
+}
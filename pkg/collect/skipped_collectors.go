@@ -0,0 +1,157 @@
+package collect
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/constants"
+)
+
+const (
+	// SkipReasonExcluded is used when a collector was excluded by its spec
+	SkipReasonExcluded = "excluded"
+	// SkipReasonInsufficientRBAC is used when the caller lacks the permissions the collector needs
+	SkipReasonInsufficientRBAC = "insufficient RBAC permissions"
+	// SkipReasonClusterScoped is used when a collector, or some of its resources, need
+	// cluster-wide access in namespaced scope
+	SkipReasonClusterScoped = "cluster-scoped in namespaced scope"
+	// SkipReasonNamespaceOutOfScope is used when a collector reads from namespaces outside of the namespaced scope
+	SkipReasonNamespaceOutOfScope = "namespace outside of namespaced scope"
+	// SkipReasonInsufficientPrivileges is used when a host collector ran without some of the
+	// privileges it needs, and skipped what needs them
+	SkipReasonInsufficientPrivileges = "insufficient privileges"
+	// SkipReasonSkipped is used when a collector was skipped while it ran, such as from the
+	// interactive mode of preflight when it took too long
+	SkipReasonSkipped = "skipped while running"
+)
+
+// SkippedCollector describes a collector that did not run, and why.
+// Collector is the kind of collector (e.g. "logs" or "secret" for in-cluster
+// collectors, and the spec key such as "cpu" or "sysctl" for host collectors).
+// Resources is set when the collector ran, but skipped some of the resources it collects.
+// Object is the namespace/name of the object a secret or configmap collector reads by name.
+type SkippedCollector struct {
+	Collector string   `json:"collector"`
+	Name      string   `json:"name,omitempty"`
+	Host      bool     `json:"host,omitempty"`
+	Object    string   `json:"object,omitempty"`
+	Resources []string `json:"resources,omitempty"`
+	Reason    string   `json:"reason"`
+}
+
+type SkippedCollectors []SkippedCollector
+
+// AddCollector records an in-cluster collector as skipped
+func (s *SkippedCollectors) AddCollector(c Collector, reason string) {
+	kind, name, _ := getCollectorKind(c)
+	*s = append(*s, SkippedCollector{
+		Collector: kind,
+		Name:      name,
+		Object:    getCollectorObject(c),
+		Reason:    reason,
+	})
+}
+
+// getCollectorObject returns the namespace/name of the object a collector reads, for the
+// collectors of a single object by name. Analyzers of that object are matched to it, since they
+// do not reference collectors by name.
+func getCollectorObject(c Collector) string {
+	switch v := c.(type) {
+	case *CollectSecret:
+		if v.Collector.Name != "" {
+			return fmt.Sprintf("%s/%s", v.Collector.Namespace, v.Collector.Name)
+		}
+	case *CollectConfigMap:
+		if v.Collector.Name != "" {
+			return fmt.Sprintf("%s/%s", v.Collector.Namespace, v.Collector.Name)
+		}
+	}
+	return ""
+}
+
+// AddResources records resources of a collector that ran as skipped
+func (s *SkippedCollectors) AddResources(collector string, resources []string, reason string) {
+	*s = append(*s, SkippedCollector{
+		Collector: collector,
+		Resources: resources,
+		Reason:    reason,
+	})
+}
+
+// AddHostCollector records a host collector as skipped
+func (s *SkippedCollectors) AddHostCollector(spec *troubleshootv1beta2.HostCollect, reason string) {
+	kind, name := GetSpecKind(spec)
+	*s = append(*s, SkippedCollector{
+		Collector: kind,
+		Name:      name,
+		Host:      true,
+		Reason:    reason,
+	})
+}
+
+// AddHostPrivileges records a host collector that ran without the given privileges. The privileges
+// are recorded as the resources of the collector that were skipped.
+func (s *SkippedCollectors) AddHostPrivileges(spec *troubleshootv1beta2.HostCollect, privileges []string) {
+	kind, name := GetSpecKind(spec)
+	*s = append(*s, SkippedCollector{
+		Collector: kind,
+		Name:      name,
+		Host:      true,
+		Resources: privileges,
+		Reason:    SkipReasonInsufficientPrivileges,
+	})
+}
+
+// SaveResult writes the list of skipped collectors to the bundle. Nothing is written if no collectors were skipped.
+func (s SkippedCollectors) SaveResult(output CollectorResult, bundlePath string) error {
+	if len(s) == 0 {
+		return nil
+	}
+
+	b, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal skipped collectors")
+	}
+
+	return output.SaveResult(bundlePath, constants.SKIPPED_COLLECTORS_FILENAME, bytes.NewBuffer(b))
+}
+
+// GetSpecKind finds the first non-nil field of a collector or analyzer spec (e.g. a HostCollect)
+// and returns its json key (e.g. "cpu") along with the value of its CollectorName field, if any.
+// Host collectors and host analyzers share keys, which allows them to be matched to each other.
+func GetSpecKind(spec interface{}) (string, string) {
+	reflected := reflect.ValueOf(spec)
+	if reflected.Kind() != reflect.Ptr || reflected.IsNil() {
+		return "", ""
+	}
+	reflected = reflected.Elem()
+	if reflected.Kind() != reflect.Struct {
+		return "", ""
+	}
+
+	for i := 0; i < reflected.NumField(); i++ {
+		field := reflected.Field(i)
+		if field.Kind() != reflect.Ptr || field.IsNil() {
+			continue
+		}
+
+		kind := strings.Split(reflected.Type().Field(i).Tag.Get("json"), ",")[0]
+
+		name := ""
+		if elem := field.Elem(); elem.Kind() == reflect.Struct {
+			collectorName := elem.FieldByName("CollectorName")
+			if collectorName.IsValid() && collectorName.Kind() == reflect.String {
+				name = collectorName.String()
+			}
+		}
+
+		return kind, name
+	}
+
+	return "", ""
+}
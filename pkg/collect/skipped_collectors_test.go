@@ -0,0 +1,86 @@
+package collect
+
+import (
+	"encoding/json"
+	"testing"
+
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/constants"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSkippedCollectors_SaveResult(t *testing.T) {
+	skipped := SkippedCollectors{}
+	skipped.AddCollector(&CollectPostgres{
+		Collector: &troubleshootv1beta2.Database{
+			CollectorMeta: troubleshootv1beta2.CollectorMeta{CollectorName: "pg"},
+		},
+	}, SkipReasonInsufficientRBAC)
+	skipped.AddCollector(&CollectSecret{
+		Collector: &troubleshootv1beta2.Secret{Name: "db-creds", Namespace: "app"},
+	}, SkipReasonNamespaceOutOfScope)
+	skipped.AddHostCollector(&troubleshootv1beta2.HostCollect{
+		CPU: &troubleshootv1beta2.CPU{},
+	}, SkipReasonExcluded)
+	skipped.AddHostPrivileges(&troubleshootv1beta2.HostCollect{
+		HostNetworkConfig: &troubleshootv1beta2.HostNetworkConfig{},
+	}, []string{HostPrivilegeFirewall})
+
+	output := NewResult()
+	require.NoError(t, skipped.SaveResult(output, ""))
+
+	got := SkippedCollectors{}
+	require.NoError(t, json.Unmarshal(output[constants.SKIPPED_COLLECTORS_FILENAME], &got))
+	assert.Equal(t, SkippedCollectors{
+		{Collector: "postgres", Name: "pg", Reason: SkipReasonInsufficientRBAC},
+		{Collector: "secret", Object: "app/db-creds", Reason: SkipReasonNamespaceOutOfScope},
+		{Collector: "cpu", Host: true, Reason: SkipReasonExcluded},
+		{Collector: "networkConfig", Host: true, Resources: []string{HostPrivilegeFirewall}, Reason: SkipReasonInsufficientPrivileges},
+	}, got)
+}
+
+func TestSkippedCollectors_SaveResultEmpty(t *testing.T) {
+	output := NewResult()
+	require.NoError(t, SkippedCollectors{}.SaveResult(output, ""))
+	assert.Empty(t, output)
+}
+
+func TestGetSpecKind(t *testing.T) {
+	tests := []struct {
+		name     string
+		spec     interface{}
+		wantKind string
+		wantName string
+	}{
+		{
+			name:     "nil spec",
+			spec:     (*troubleshootv1beta2.HostCollect)(nil),
+			wantKind: "",
+		},
+		{
+			name: "host collector with name",
+			spec: &troubleshootv1beta2.HostCollect{
+				HostSysctl: &troubleshootv1beta2.HostSysctl{
+					HostCollectorMeta: troubleshootv1beta2.HostCollectorMeta{CollectorName: "kernel"},
+				},
+			},
+			wantKind: "sysctl",
+			wantName: "kernel",
+		},
+		{
+			name: "analyzer without collector name",
+			spec: &troubleshootv1beta2.Analyze{
+				ClusterVersion: &troubleshootv1beta2.ClusterVersion{},
+			},
+			wantKind: "clusterVersion",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kind, name := GetSpecKind(tt.spec)
+			assert.Equal(t, tt.wantKind, kind)
+			assert.Equal(t, tt.wantName, name)
+		})
+	}
+}
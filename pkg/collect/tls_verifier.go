@@ -0,0 +1,76 @@
+package collect
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+
+	"github.com/pkg/errors"
+)
+
+// tlsVerifier verifies the certificate a server presents in place of the TLS handshake, so that
+// the chain of a server that is not trusted can still be recorded. Handshakes with the config of
+// the verifier complete whether the server is trusted or not: the verifier has to be checked
+// before any credentials are sent over the connection.
+type tlsVerifier struct {
+	serverName string
+	roots      *x509.CertPool
+	skipVerify bool
+
+	// state is the last handshake, and err why its certificate is not trusted
+	state *tls.ConnectionState
+	err   error
+}
+
+// newTLSVerifier returns a verifier of the certificates of the server with the roots of the config,
+// or the roots of the system when the config is nil, with a copy of the config that uses it
+func newTLSVerifier(tlsConfig *tls.Config, serverName string) (*tlsVerifier, *tls.Config) {
+	if tlsConfig == nil {
+		tlsConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	} else {
+		tlsConfig = tlsConfig.Clone()
+	}
+
+	verifier := &tlsVerifier{
+		serverName: serverName,
+		roots:      tlsConfig.RootCAs,
+		skipVerify: tlsConfig.InsecureSkipVerify,
+	}
+	if tlsConfig.ServerName == "" {
+		tlsConfig.ServerName = serverName
+	}
+	tlsConfig.InsecureSkipVerify = true
+	tlsConfig.VerifyConnection = verifier.verify
+
+	return verifier, tlsConfig
+}
+
+func (v *tlsVerifier) verify(state tls.ConnectionState) error {
+	v.state = &state
+	v.err = nil
+	if v.skipVerify {
+		return nil
+	}
+	if len(state.PeerCertificates) == 0 {
+		v.err = errors.New("server presented no certificate")
+		return nil
+	}
+
+	intermediates := x509.NewCertPool()
+	for _, cert := range state.PeerCertificates[1:] {
+		intermediates.AddCert(cert)
+	}
+	_, v.err = state.PeerCertificates[0].Verify(x509.VerifyOptions{
+		DNSName:       v.serverName,
+		Roots:         v.roots,
+		Intermediates: intermediates,
+	})
+	return nil
+}
+
+// certificateError is why the certificate of the last handshake is not trusted, empty when it is
+func (v *tlsVerifier) certificateError() string {
+	if v.err == nil {
+		return ""
+	}
+	return v.err.Error()
+}
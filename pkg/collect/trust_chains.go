@@ -0,0 +1,377 @@
+package collect
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/klog/v2"
+)
+
+// The sources of the certificates of a trustChains collector
+const (
+	TrustChainSourceAPIServer = "apiServer"
+	TrustChainSourceKubelets  = "kubelets"
+	TrustChainSourceWebhooks  = "webhooks"
+	TrustChainSourceIngresses = "ingresses"
+)
+
+// The kinds of the chains of a trustChains collector
+const (
+	TrustChainKindAPIServer = "apiServer"
+	TrustChainKindKubelet   = "kubelet"
+	TrustChainKindWebhook   = "webhook"
+	TrustChainKindIngress   = "ingress"
+)
+
+const (
+	defaultTrustChainsTimeout = 5 * time.Second
+	defaultKubeletPort        = 10250
+)
+
+type CollectTrustChains struct {
+	Collector    *troubleshootv1beta2.TrustChains
+	BundlePath   string
+	Namespace    string
+	ClientConfig *rest.Config
+	Client       kubernetes.Interface
+	Context      context.Context
+	RBACErrors
+}
+
+// TrustChainsInfo is the output of the trustChains collector
+type TrustChainsInfo struct {
+	// ClusterCA are the PEM certificates of the cluster CA, of the kube-root-ca.crt configmap or
+	// of the config of the client
+	ClusterCA string       `json:"clusterCA,omitempty"`
+	Chains    []TrustChain `json:"chains"`
+	// Errors are the failures to list the nodes, webhooks or ingresses
+	Errors []string `json:"errors,omitempty"`
+}
+
+// TrustChain are the certificates of an endpoint or a secret, and the certificate authorities
+// its clients trust
+type TrustChain struct {
+	// Kind is one of apiServer, kubelet, webhook or ingress
+	Kind string `json:"kind"`
+	// Name is the name of the node of a kubelet, <configuration>/<webhook> for a webhook, or
+	// <namespace>/<ingress>/<secret> for an ingress
+	Name string `json:"name"`
+	// Endpoint is the address the certificates were presented on, empty for a secret
+	Endpoint string `json:"endpoint,omitempty"`
+	// Certificates are the PEM certificates presented or stored, the leaf first
+	Certificates string `json:"certificates,omitempty"`
+	// CABundle are the PEM certificates of the authorities the clients trust, e.g. the caBundle
+	// of a webhook or the ca.crt of a TLS secret
+	CABundle string `json:"caBundle,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+func (c *CollectTrustChains) Title() string {
+	return getCollectorName(c)
+}
+
+func (c *CollectTrustChains) IsExcluded() (bool, error) {
+	return isExcluded(c.Collector.Exclude)
+}
+
+func (c *CollectTrustChains) Collect(progressChan chan<- interface{}) (CollectorResult, error) {
+	sources := c.Collector.Sources
+	if len(sources) == 0 {
+		sources = []string{TrustChainSourceAPIServer, TrustChainSourceKubelets, TrustChainSourceWebhooks, TrustChainSourceIngresses}
+	}
+
+	timeout := defaultTrustChainsTimeout
+	if c.Collector.Timeout != "" {
+		var err error
+		timeout, err = time.ParseDuration(c.Collector.Timeout)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse timeout %q", c.Collector.Timeout)
+		}
+	}
+
+	ctx := collectorContext(c.Context)
+	info := TrustChainsInfo{Chains: []TrustChain{}}
+	info.ClusterCA = c.clusterCA(ctx, &info)
+
+	for _, source := range sources {
+		switch source {
+		case TrustChainSourceAPIServer:
+			info.Chains = append(info.Chains, c.apiServerChain(ctx, timeout))
+		case TrustChainSourceKubelets:
+			info.Chains = append(info.Chains, c.kubeletChains(ctx, timeout, &info)...)
+		case TrustChainSourceWebhooks:
+			info.Chains = append(info.Chains, c.webhookChains(ctx, timeout, &info)...)
+		case TrustChainSourceIngresses:
+			info.Chains = append(info.Chains, c.ingressChains(ctx, &info)...)
+		default:
+			return nil, errors.Errorf("unsupported source %q, must be one of apiServer, kubelets, webhooks or ingresses", source)
+		}
+	}
+
+	b, err := json.MarshalIndent(info, "", "  ")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal trust chains")
+	}
+
+	output := NewResult()
+	output.SaveResult(c.BundlePath, TrustChainsOutputPath(c.Collector.CollectorName), bytes.NewBuffer(b))
+
+	return output, nil
+}
+
+// TrustChainsOutputPath is the path of the output of a trustChains collector in the bundle
+func TrustChainsOutputPath(collectorName string) string {
+	if collectorName == "" {
+		collectorName = "trust-chains"
+	}
+	return fmt.Sprintf("trust-chains/%s.json", collectorName)
+}
+
+// clusterCA returns the certificates of the kube-root-ca.crt configmap every namespace has, or
+// the CA of the config of the client when the configmap cannot be read
+func (c *CollectTrustChains) clusterCA(ctx context.Context, info *TrustChainsInfo) string {
+	configMap, err := c.Client.CoreV1().ConfigMaps("kube-system").Get(ctx, "kube-root-ca.crt", metav1.GetOptions{})
+	if err == nil && configMap.Data["ca.crt"] != "" {
+		return configMap.Data["ca.crt"]
+	}
+	if err != nil {
+		klog.V(2).Infof("failed to get the kube-root-ca.crt configmap: %v", err)
+	}
+
+	if c.ClientConfig != nil && len(c.ClientConfig.CAData) > 0 {
+		return string(c.ClientConfig.CAData)
+	}
+	if err != nil {
+		info.Errors = append(info.Errors, errors.Wrap(err, "failed to get the cluster CA").Error())
+	}
+	return ""
+}
+
+func (c *CollectTrustChains) apiServerChain(ctx context.Context, timeout time.Duration) TrustChain {
+	chain := TrustChain{Kind: TrustChainKindAPIServer, Name: "kubernetes"}
+	if c.ClientConfig == nil {
+		chain.Error = "the collector has no client config"
+		return chain
+	}
+
+	host := c.ClientConfig.Host
+	if !strings.Contains(host, "://") {
+		host = "https://" + host
+	}
+	u, err := url.Parse(host)
+	if err != nil {
+		chain.Error = errors.Wrapf(err, "failed to parse %q", c.ClientConfig.Host).Error()
+		return chain
+	}
+	if u.Scheme != "https" {
+		chain.Error = fmt.Sprintf("the API server %s is not served over TLS", c.ClientConfig.Host)
+		return chain
+	}
+	port := u.Port()
+	if port == "" {
+		port = "443"
+	}
+
+	chain.Endpoint = net.JoinHostPort(u.Hostname(), port)
+	chain.Certificates, err = presentedCertificates(ctx, chain.Endpoint, c.ClientConfig.ServerName, timeout)
+	if err != nil {
+		chain.Error = err.Error()
+	}
+	return chain
+}
+
+// kubeletChains returns the certificates the kubelets present on the address of their node the
+// API server connects to
+func (c *CollectTrustChains) kubeletChains(ctx context.Context, timeout time.Duration, info *TrustChainsInfo) []TrustChain {
+	nodes, err := c.Client.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		info.Errors = append(info.Errors, errors.Wrap(err, "failed to list nodes").Error())
+		return nil
+	}
+
+	chains := []TrustChain{}
+	for _, node := range nodes.Items {
+		chain := TrustChain{Kind: TrustChainKindKubelet, Name: node.Name}
+
+		address := nodeAddress(node)
+		if address == "" {
+			chain.Error = "the node has no address"
+			chains = append(chains, chain)
+			continue
+		}
+		port := int(node.Status.DaemonEndpoints.KubeletEndpoint.Port)
+		if port == 0 {
+			port = defaultKubeletPort
+		}
+
+		chain.Endpoint = net.JoinHostPort(address, strconv.Itoa(port))
+		chain.Certificates, err = presentedCertificates(ctx, chain.Endpoint, "", timeout)
+		if err != nil {
+			chain.Error = err.Error()
+		}
+		chains = append(chains, chain)
+	}
+	return chains
+}
+
+// nodeAddress returns the internal IP of a node, or its external IP or hostname without one
+func nodeAddress(node corev1.Node) string {
+	for _, addressType := range []corev1.NodeAddressType{corev1.NodeInternalIP, corev1.NodeExternalIP, corev1.NodeHostName} {
+		for _, address := range node.Status.Addresses {
+			if address.Type == addressType && address.Address != "" {
+				return address.Address
+			}
+		}
+	}
+	return ""
+}
+
+// webhookChains returns the caBundle of the admission webhooks, and the certificates they present.
+// The services of webhooks are only reachable when collecting from inside the cluster.
+func (c *CollectTrustChains) webhookChains(ctx context.Context, timeout time.Duration, info *TrustChainsInfo) []TrustChain {
+	chains := []TrustChain{}
+
+	mutating, err := c.Client.AdmissionregistrationV1().MutatingWebhookConfigurations().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		info.Errors = append(info.Errors, errors.Wrap(err, "failed to list mutating webhook configurations").Error())
+	} else {
+		for _, configuration := range mutating.Items {
+			for _, webhook := range configuration.Webhooks {
+				chains = append(chains, webhookChain(ctx, configuration.Name, webhook.Name, webhook.ClientConfig, timeout))
+			}
+		}
+	}
+
+	validating, err := c.Client.AdmissionregistrationV1().ValidatingWebhookConfigurations().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		info.Errors = append(info.Errors, errors.Wrap(err, "failed to list validating webhook configurations").Error())
+	} else {
+		for _, configuration := range validating.Items {
+			for _, webhook := range configuration.Webhooks {
+				chains = append(chains, webhookChain(ctx, configuration.Name, webhook.Name, webhook.ClientConfig, timeout))
+			}
+		}
+	}
+
+	return chains
+}
+
+func webhookChain(ctx context.Context, configuration string, webhook string, clientConfig admissionregistrationv1.WebhookClientConfig, timeout time.Duration) TrustChain {
+	chain := TrustChain{
+		Kind:     TrustChainKindWebhook,
+		Name:     configuration + "/" + webhook,
+		CABundle: string(clientConfig.CABundle),
+	}
+
+	serverName := ""
+	switch {
+	case clientConfig.URL != nil:
+		u, err := url.Parse(*clientConfig.URL)
+		if err != nil {
+			chain.Error = errors.Wrapf(err, "failed to parse %q", *clientConfig.URL).Error()
+			return chain
+		}
+		port := u.Port()
+		if port == "" {
+			port = "443"
+		}
+		chain.Endpoint = net.JoinHostPort(u.Hostname(), port)
+	case clientConfig.Service != nil:
+		port := int32(443)
+		if clientConfig.Service.Port != nil {
+			port = *clientConfig.Service.Port
+		}
+		// the API server verifies the certificate for the DNS name of the service
+		serverName = fmt.Sprintf("%s.%s.svc", clientConfig.Service.Name, clientConfig.Service.Namespace)
+		chain.Endpoint = net.JoinHostPort(serverName, strconv.Itoa(int(port)))
+	default:
+		chain.Error = "the webhook has neither a URL nor a service"
+		return chain
+	}
+
+	var err error
+	chain.Certificates, err = presentedCertificates(ctx, chain.Endpoint, serverName, timeout)
+	if err != nil {
+		chain.Error = err.Error()
+	}
+	return chain
+}
+
+// ingressChains returns the certificates of the TLS secrets of the ingresses
+func (c *CollectTrustChains) ingressChains(ctx context.Context, info *TrustChainsInfo) []TrustChain {
+	namespaces := c.Collector.Namespaces
+	if len(namespaces) == 0 {
+		namespaces = []string{metav1.NamespaceAll}
+	}
+
+	chains := []TrustChain{}
+	for _, namespace := range namespaces {
+		ingresses, err := c.Client.NetworkingV1().Ingresses(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			info.Errors = append(info.Errors, errors.Wrapf(err, "failed to list ingresses in namespace %q", namespace).Error())
+			continue
+		}
+
+		for _, ingress := range ingresses.Items {
+			for _, ingressTLS := range ingress.Spec.TLS {
+				if ingressTLS.SecretName == "" {
+					continue
+				}
+				chain := TrustChain{
+					Kind: TrustChainKindIngress,
+					Name: fmt.Sprintf("%s/%s/%s", ingress.Namespace, ingress.Name, ingressTLS.SecretName),
+				}
+				secret, err := c.Client.CoreV1().Secrets(ingress.Namespace).Get(ctx, ingressTLS.SecretName, metav1.GetOptions{})
+				if err != nil {
+					chain.Error = errors.Wrap(err, "failed to get secret").Error()
+				} else {
+					chain.Certificates = string(secret.Data[corev1.TLSCertKey])
+					chain.CABundle = string(secret.Data["ca.crt"])
+				}
+				chains = append(chains, chain)
+			}
+		}
+	}
+	return chains
+}
+
+// presentedCertificates returns the PEM certificates a server presents in the TLS handshake. They
+// are not verified, for the chains that are not trusted to be recorded.
+func presentedCertificates(ctx context.Context, address string, serverName string, timeout time.Duration) (string, error) {
+	dialer := &tls.Dialer{
+		NetDialer: &net.Dialer{Timeout: timeout},
+		Config:    &tls.Config{ServerName: serverName, InsecureSkipVerify: true},
+	}
+
+	dialCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	conn, err := dialer.DialContext(dialCtx, "tcp", address)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to connect to %s", address)
+	}
+	defer conn.Close()
+
+	var certificates bytes.Buffer
+	for _, cert := range conn.(*tls.Conn).ConnectionState().PeerCertificates {
+		if err := pem.Encode(&certificates, &pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}); err != nil {
+			return "", errors.Wrap(err, "failed to encode certificate")
+		}
+	}
+	return certificates.String(), nil
+}
@@ -0,0 +1,55 @@
+package collect
+
+import (
+	"context"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func Test_webhookChain(t *testing.T) {
+	server := httptest.NewTLSServer(http.NotFoundHandler())
+	defer server.Close()
+
+	chain := webhookChain(context.Background(), "validating", "pods.example.com", admissionregistrationv1.WebhookClientConfig{
+		URL:      &server.URL,
+		CABundle: []byte("bundle"),
+	}, time.Second)
+	require.Empty(t, chain.Error)
+	assert.Equal(t, TrustChainKindWebhook, chain.Kind)
+	assert.Equal(t, "validating/pods.example.com", chain.Name)
+	assert.Equal(t, server.Listener.Addr().String(), chain.Endpoint)
+	assert.Equal(t, "bundle", chain.CABundle)
+
+	block, _ := pem.Decode([]byte(chain.Certificates))
+	require.NotNil(t, block)
+	assert.Equal(t, server.Certificate().Raw, block.Bytes)
+
+	chain = webhookChain(context.Background(), "validating", "pods.example.com", admissionregistrationv1.WebhookClientConfig{}, time.Second)
+	assert.Equal(t, "the webhook has neither a URL nor a service", chain.Error)
+}
+
+func Test_nodeAddress(t *testing.T) {
+	node := corev1.Node{Status: corev1.NodeStatus{Addresses: []corev1.NodeAddress{
+		{Type: corev1.NodeHostName, Address: "node-1"},
+		{Type: corev1.NodeExternalIP, Address: "203.0.113.10"},
+		{Type: corev1.NodeInternalIP, Address: "10.0.0.10"},
+	}}}
+	assert.Equal(t, "10.0.0.10", nodeAddress(node))
+
+	node.Status.Addresses = node.Status.Addresses[:1]
+	assert.Equal(t, "node-1", nodeAddress(node))
+	assert.Equal(t, "", nodeAddress(corev1.Node{}))
+}
+
+func TestTrustChainsOutputPath(t *testing.T) {
+	assert.Equal(t, "trust-chains/trust-chains.json", TrustChainsOutputPath(""))
+	assert.Equal(t, "trust-chains/cluster.json", TrustChainsOutputPath("cluster"))
+}
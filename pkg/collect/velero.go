@@ -0,0 +1,315 @@
+package collect
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	velerov1 "github.com/vmware-tanzu/velero/pkg/apis/velero/v1"
+	kuberneteserrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/klog/v2"
+)
+
+const (
+	defaultVeleroNamespace = "velero"
+	defaultVeleroRecent    = 20
+)
+
+// VeleroInfo is the output of the velero collector
+type VeleroInfo struct {
+	Namespace string `json:"namespace"`
+	// Version is the tag of the image of the velero container of the velero deployment
+	Version string `json:"version,omitempty"`
+	// Plugins are the init containers of the velero deployment, which install the plugins
+	Plugins                []VeleroPlugin                `json:"plugins"`
+	BackupStorageLocations []VeleroBackupStorageLocation `json:"backupStorageLocations"`
+	Schedules              []VeleroSchedule              `json:"schedules"`
+	// Backups and Restores are the most recent ones, latest first
+	Backups  []VeleroBackup  `json:"backups"`
+	Restores []VeleroRestore `json:"restores"`
+	Errors   []string        `json:"errors,omitempty"`
+}
+
+type VeleroPlugin struct {
+	Name    string `json:"name"`
+	Image   string `json:"image"`
+	Version string `json:"version,omitempty"`
+}
+
+type VeleroBackupStorageLocation struct {
+	Name               string       `json:"name"`
+	Provider           string       `json:"provider"`
+	Bucket             string       `json:"bucket,omitempty"`
+	Default            bool         `json:"default,omitempty"`
+	AccessMode         string       `json:"accessMode,omitempty"`
+	Phase              string       `json:"phase,omitempty"`
+	Message            string       `json:"message,omitempty"`
+	LastValidationTime *metav1.Time `json:"lastValidationTime,omitempty"`
+}
+
+type VeleroSchedule struct {
+	Name     string `json:"name"`
+	Schedule string `json:"schedule"`
+	Paused   bool   `json:"paused,omitempty"`
+	Phase    string `json:"phase,omitempty"`
+	// LastBackup is the latest backup of the schedule, whether it is among the recent backups or not
+	LastBackup *VeleroBackup `json:"lastBackup,omitempty"`
+}
+
+type VeleroBackup struct {
+	Name                string       `json:"name"`
+	Schedule            string       `json:"schedule,omitempty"`
+	StorageLocation     string       `json:"storageLocation,omitempty"`
+	Phase               string       `json:"phase,omitempty"`
+	Errors              int          `json:"errors,omitempty"`
+	Warnings            int          `json:"warnings,omitempty"`
+	FailureReason       string       `json:"failureReason,omitempty"`
+	ValidationErrors    []string     `json:"validationErrors,omitempty"`
+	StartTimestamp      *metav1.Time `json:"startTimestamp,omitempty"`
+	CompletionTimestamp *metav1.Time `json:"completionTimestamp,omitempty"`
+	created             metav1.Time
+}
+
+type VeleroRestore struct {
+	Name                string       `json:"name"`
+	Backup              string       `json:"backup,omitempty"`
+	Schedule            string       `json:"schedule,omitempty"`
+	Phase               string       `json:"phase,omitempty"`
+	Errors              int          `json:"errors,omitempty"`
+	Warnings            int          `json:"warnings,omitempty"`
+	FailureReason       string       `json:"failureReason,omitempty"`
+	ValidationErrors    []string     `json:"validationErrors,omitempty"`
+	StartTimestamp      *metav1.Time `json:"startTimestamp,omitempty"`
+	CompletionTimestamp *metav1.Time `json:"completionTimestamp,omitempty"`
+	created             metav1.Time
+}
+
+var (
+	veleroBackupStorageLocationsGVR = velerov1.SchemeGroupVersion.WithResource("backupstoragelocations")
+	veleroSchedulesGVR              = velerov1.SchemeGroupVersion.WithResource("schedules")
+	veleroBackupsGVR                = velerov1.SchemeGroupVersion.WithResource("backups")
+	veleroRestoresGVR               = velerov1.SchemeGroupVersion.WithResource("restores")
+)
+
+type CollectVelero struct {
+	Collector    *troubleshootv1beta2.Velero
+	BundlePath   string
+	Namespace    string
+	ClientConfig *rest.Config
+	Client       kubernetes.Interface
+	Context      context.Context
+	RBACErrors
+}
+
+func (c *CollectVelero) Title() string {
+	return getCollectorName(c)
+}
+
+func (c *CollectVelero) IsExcluded() (bool, error) {
+	return isExcluded(c.Collector.Exclude)
+}
+
+func (c *CollectVelero) Collect(progressChan chan<- interface{}) (CollectorResult, error) {
+	dynamicClient, err := dynamic.NewForConfig(c.ClientConfig)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create dynamic client")
+	}
+
+	namespace := c.Collector.Namespace
+	if namespace == "" {
+		namespace = defaultVeleroNamespace
+	}
+	recent := c.Collector.Recent
+	if recent <= 0 {
+		recent = defaultVeleroRecent
+	}
+
+	info := collectVelero(collectorContext(c.Context), c.Client, dynamicClient, namespace, recent)
+
+	b, err := json.MarshalIndent(info, "", "  ")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal velero info")
+	}
+
+	output := NewResult()
+	output.SaveResult(c.BundlePath, VeleroOutputPath(c.Collector.CollectorName), bytes.NewBuffer(b))
+
+	return output, nil
+}
+
+// VeleroOutputPath returns the path of the file collected by the velero collector of a name
+func VeleroOutputPath(collectorName string) string {
+	if collectorName == "" {
+		collectorName = "velero"
+	}
+	return filepath.Join("velero", fmt.Sprintf("%s.json", collectorName))
+}
+
+// collectVelero collects the Velero installation of a namespace. The resources that cannot be
+// listed, e.g. when Velero is not installed, are recorded in the errors of the info.
+func collectVelero(ctx context.Context, client kubernetes.Interface, dynamicClient dynamic.Interface, namespace string, recent int) *VeleroInfo {
+	info := &VeleroInfo{
+		Namespace:              namespace,
+		Plugins:                []VeleroPlugin{},
+		BackupStorageLocations: []VeleroBackupStorageLocation{},
+		Schedules:              []VeleroSchedule{},
+		Backups:                []VeleroBackup{},
+		Restores:               []VeleroRestore{},
+	}
+	addError := func(err error) {
+		klog.V(2).Infof("velero collector: %v", err)
+		info.Errors = append(info.Errors, err.Error())
+	}
+
+	deployment, err := client.AppsV1().Deployments(namespace).Get(ctx, "velero", metav1.GetOptions{})
+	if err != nil {
+		addError(errors.Wrap(err, "failed to get velero deployment"))
+	} else {
+		for _, container := range deployment.Spec.Template.Spec.Containers {
+			if container.Name == "velero" {
+				info.Version = imageTag(container.Image)
+			}
+		}
+		for _, container := range deployment.Spec.Template.Spec.InitContainers {
+			info.Plugins = append(info.Plugins, VeleroPlugin{
+				Name:    container.Name,
+				Image:   container.Image,
+				Version: imageTag(container.Image),
+			})
+		}
+	}
+
+	locations, err := listVeleroResource[velerov1.BackupStorageLocation](ctx, dynamicClient, veleroBackupStorageLocationsGVR, namespace)
+	if err != nil {
+		addError(err)
+	}
+	for _, location := range locations {
+		l := VeleroBackupStorageLocation{
+			Name:               location.Name,
+			Provider:           location.Spec.Provider,
+			Default:            location.Spec.Default,
+			AccessMode:         string(location.Spec.AccessMode),
+			Phase:              string(location.Status.Phase),
+			Message:            location.Status.Message,
+			LastValidationTime: location.Status.LastValidationTime,
+		}
+		if location.Spec.ObjectStorage != nil {
+			l.Bucket = location.Spec.ObjectStorage.Bucket
+		}
+		info.BackupStorageLocations = append(info.BackupStorageLocations, l)
+	}
+
+	backups, err := listVeleroResource[velerov1.Backup](ctx, dynamicClient, veleroBackupsGVR, namespace)
+	if err != nil {
+		addError(err)
+	}
+	allBackups := []VeleroBackup{}
+	for _, backup := range backups {
+		allBackups = append(allBackups, VeleroBackup{
+			Name:                backup.Name,
+			Schedule:            backup.Labels[velerov1.ScheduleNameLabel],
+			StorageLocation:     backup.Spec.StorageLocation,
+			Phase:               string(backup.Status.Phase),
+			Errors:              backup.Status.Errors,
+			Warnings:            backup.Status.Warnings,
+			FailureReason:       backup.Status.FailureReason,
+			ValidationErrors:    backup.Status.ValidationErrors,
+			StartTimestamp:      backup.Status.StartTimestamp,
+			CompletionTimestamp: backup.Status.CompletionTimestamp,
+			created:             backup.CreationTimestamp,
+		})
+	}
+	sort.SliceStable(allBackups, func(i, j int) bool { return allBackups[j].created.Before(&allBackups[i].created) })
+
+	schedules, err := listVeleroResource[velerov1.Schedule](ctx, dynamicClient, veleroSchedulesGVR, namespace)
+	if err != nil {
+		addError(err)
+	}
+	for _, schedule := range schedules {
+		s := VeleroSchedule{
+			Name:     schedule.Name,
+			Schedule: schedule.Spec.Schedule,
+			Paused:   schedule.Spec.Paused,
+			Phase:    string(schedule.Status.Phase),
+		}
+		for i := range allBackups {
+			if allBackups[i].Schedule == schedule.Name {
+				s.LastBackup = &allBackups[i]
+				break
+			}
+		}
+		info.Schedules = append(info.Schedules, s)
+	}
+
+	if len(allBackups) > recent {
+		allBackups = allBackups[:recent]
+	}
+	info.Backups = allBackups
+
+	restores, err := listVeleroResource[velerov1.Restore](ctx, dynamicClient, veleroRestoresGVR, namespace)
+	if err != nil {
+		addError(err)
+	}
+	for _, restore := range restores {
+		info.Restores = append(info.Restores, VeleroRestore{
+			Name:                restore.Name,
+			Backup:              restore.Spec.BackupName,
+			Schedule:            restore.Spec.ScheduleName,
+			Phase:               string(restore.Status.Phase),
+			Errors:              restore.Status.Errors,
+			Warnings:            restore.Status.Warnings,
+			FailureReason:       restore.Status.FailureReason,
+			ValidationErrors:    restore.Status.ValidationErrors,
+			StartTimestamp:      restore.Status.StartTimestamp,
+			CompletionTimestamp: restore.Status.CompletionTimestamp,
+			created:             restore.CreationTimestamp,
+		})
+	}
+	sort.SliceStable(info.Restores, func(i, j int) bool { return info.Restores[j].created.Before(&info.Restores[i].created) })
+	if len(info.Restores) > recent {
+		info.Restores = info.Restores[:recent]
+	}
+
+	return info
+}
+
+// listVeleroResource lists the objects of a Velero resource in a namespace as their type
+func listVeleroResource[T any](ctx context.Context, client dynamic.Interface, gvr schema.GroupVersionResource, namespace string) ([]T, error) {
+	list, err := client.Resource(gvr).Namespace(namespace).List(ctx, metav1.ListOptions{})
+	if kuberneteserrors.IsNotFound(err) {
+		return nil, errors.Errorf("resource %s.%s not found, Velero is not installed", gvr.Resource, gvr.Group)
+	} else if err != nil {
+		return nil, errors.Wrapf(err, "failed to list %s.%s", gvr.Resource, gvr.Group)
+	}
+
+	objects := make([]T, len(list.Items))
+	for i, item := range list.Items {
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(item.Object, &objects[i]); err != nil {
+			return nil, errors.Wrapf(err, "failed to convert %s.%s %s", gvr.Resource, gvr.Group, item.GetName())
+		}
+	}
+	return objects, nil
+}
+
+// imageTag returns the tag of an image, or an empty string when it is referenced by digest only
+// or has no tag
+func imageTag(image string) string {
+	image = strings.SplitN(image, "@", 2)[0]
+	i := strings.LastIndex(image, ":")
+	if i < 0 || strings.Contains(image[i:], "/") {
+		return ""
+	}
+	return image[i+1:]
+}
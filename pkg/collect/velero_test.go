@@ -0,0 +1,135 @@
+package collect
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	kuberneteserrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	testdynamicclient "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func veleroObject(kind string, name string, created string, labels map[string]interface{}, spec map[string]interface{}, status map[string]interface{}) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "velero.io/v1",
+		"kind":       kind,
+		"metadata": map[string]interface{}{
+			"name":              name,
+			"namespace":         "velero",
+			"creationTimestamp": created,
+			"labels":            labels,
+		},
+		"spec":   spec,
+		"status": status,
+	}}
+}
+
+func Test_collectVelero(t *testing.T) {
+	client := fake.NewSimpleClientset(&appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "velero", Namespace: "velero"},
+		Spec: appsv1.DeploymentSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					InitContainers: []corev1.Container{
+						{Name: "velero-plugin-for-aws", Image: "velero/velero-plugin-for-aws:v1.12.0"},
+					},
+					Containers: []corev1.Container{
+						{Name: "velero", Image: "docker.io/velero/velero:v1.16.0"},
+					},
+				},
+			},
+		},
+	})
+
+	listKinds := map[schema.GroupVersionResource]string{
+		veleroBackupStorageLocationsGVR: "BackupStorageLocationList",
+		veleroSchedulesGVR:              "ScheduleList",
+		veleroBackupsGVR:                "BackupList",
+		veleroRestoresGVR:               "RestoreList",
+	}
+	scheduleLabel := map[string]interface{}{"velero.io/schedule-name": "daily"}
+	dynamicClient := testdynamicclient.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), listKinds,
+		veleroObject("BackupStorageLocation", "default", "2024-01-01T00:00:00Z", nil,
+			map[string]interface{}{"provider": "aws", "default": true, "objectStorage": map[string]interface{}{"bucket": "backups"}},
+			map[string]interface{}{"phase": "Unavailable", "message": "bucket not found"}),
+		veleroObject("Schedule", "daily", "2024-01-01T00:00:00Z", nil,
+			map[string]interface{}{"schedule": "0 1 * * *"},
+			map[string]interface{}{"phase": "Enabled"}),
+		veleroObject("Backup", "daily-1", "2024-01-02T01:00:00Z", scheduleLabel,
+			map[string]interface{}{"storageLocation": "default"},
+			map[string]interface{}{"phase": "Completed"}),
+		veleroObject("Backup", "daily-2", "2024-01-03T01:00:00Z", scheduleLabel,
+			map[string]interface{}{"storageLocation": "default"},
+			map[string]interface{}{"phase": "Failed", "failureReason": "bucket not found"}),
+		veleroObject("Backup", "manual", "2024-01-02T12:00:00Z", nil,
+			map[string]interface{}{"storageLocation": "default"},
+			map[string]interface{}{"phase": "PartiallyFailed", "errors": int64(2)}),
+	)
+	dynamicClient.PrependReactor("list", "restores", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, kuberneteserrors.NewForbidden(schema.GroupResource{Group: "velero.io", Resource: "restores"}, "", nil)
+	})
+
+	info := collectVelero(context.Background(), client, dynamicClient, "velero", 2)
+
+	assert.Equal(t, "velero", info.Namespace)
+	assert.Equal(t, "v1.16.0", info.Version)
+	assert.Equal(t, []VeleroPlugin{
+		{Name: "velero-plugin-for-aws", Image: "velero/velero-plugin-for-aws:v1.12.0", Version: "v1.12.0"},
+	}, info.Plugins)
+
+	require.Len(t, info.BackupStorageLocations, 1)
+	location := info.BackupStorageLocations[0]
+	assert.Equal(t, "aws", location.Provider)
+	assert.Equal(t, "backups", location.Bucket)
+	assert.True(t, location.Default)
+	assert.Equal(t, "Unavailable", location.Phase)
+
+	require.Len(t, info.Schedules, 1)
+	require.NotNil(t, info.Schedules[0].LastBackup)
+	assert.Equal(t, "daily-2", info.Schedules[0].LastBackup.Name)
+	assert.Equal(t, "bucket not found", info.Schedules[0].LastBackup.FailureReason)
+
+	require.Len(t, info.Backups, 2)
+	assert.Equal(t, "daily-2", info.Backups[0].Name)
+	assert.Equal(t, "manual", info.Backups[1].Name)
+	assert.Equal(t, 2, info.Backups[1].Errors)
+
+	assert.Empty(t, info.Restores)
+	require.Len(t, info.Errors, 1)
+	assert.Contains(t, info.Errors[0], "failed to list restores.velero.io")
+}
+
+func Test_collectVelero_notInstalled(t *testing.T) {
+	listKinds := map[schema.GroupVersionResource]string{
+		veleroBackupStorageLocationsGVR: "BackupStorageLocationList",
+		veleroSchedulesGVR:              "ScheduleList",
+		veleroBackupsGVR:                "BackupList",
+		veleroRestoresGVR:               "RestoreList",
+	}
+	dynamicClient := testdynamicclient.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), listKinds)
+	dynamicClient.PrependReactor("list", "*", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, kuberneteserrors.NewNotFound(action.GetResource().GroupResource(), "")
+	})
+
+	info := collectVelero(context.Background(), fake.NewSimpleClientset(), dynamicClient, "velero", 20)
+
+	assert.Empty(t, info.BackupStorageLocations)
+	assert.Len(t, info.Errors, 5)
+	assert.Contains(t, info.Errors, "resource backupstoragelocations.velero.io not found, Velero is not installed")
+}
+
+func Test_imageTag(t *testing.T) {
+	assert.Equal(t, "v1.16.0", imageTag("velero/velero:v1.16.0"))
+	assert.Equal(t, "v1.16.0", imageTag("registry:5000/velero/velero:v1.16.0@sha256:abc"))
+	assert.Equal(t, "", imageTag("registry:5000/velero/velero"))
+	assert.Equal(t, "", imageTag("velero/velero@sha256:abc"))
+}
@@ -7,6 +7,11 @@ const (
 	DEFAULT_CLIENT_QPS = 100
 	// DEFAULT_CLIENT_QPS is maximum burst for throttle.
 	DEFAULT_CLIENT_BURST = 100
+	// IN_CLUSTER_CLIENT_QPS is the maximum QPS from troubleshoot running in a pod, kept low to share
+	// the API server with the workloads of the cluster.
+	IN_CLUSTER_CLIENT_QPS = 20
+	// IN_CLUSTER_CLIENT_BURST is the maximum burst from troubleshoot running in a pod.
+	IN_CLUSTER_CLIENT_BURST = 40
 	// DEFAULT_CLIENT_USER_AGENT is an field that specifies the caller of troubleshoot request.
 	DEFAULT_CLIENT_USER_AGENT = "ReplicatedTroubleshoot"
 	// VERSION_FILENAME is the name of the file that contains the support bundle version.
@@ -21,6 +26,28 @@ const (
 	TROUBLESHOOT_ROOT_SPAN_NAME = "ReplicatedTroubleshootRootSpan"
 	EXCLUDED                    = "excluded"
 	ANALYSIS_FILENAME           = "analysis.json"
+	// SKIPPED_COLLECTORS_FILENAME is the name of the file listing collectors that did not run, and why.
+	SKIPPED_COLLECTORS_FILENAME = "skipped-collectors.json"
+	// COLLECTOR_ATTEMPTS_FILENAME is the name of the file with the attempts of the collectors that were retried.
+	COLLECTOR_ATTEMPTS_FILENAME = "collector-attempts.json"
+	// BUNDLE_INDEX_FILENAME is the name of the file listing the files of the bundle and the collectors that produced them.
+	BUNDLE_INDEX_FILENAME = "bundle-index.json"
+	// TRACES_FILENAME is the name of the file with the spans of the collection and analysis, when they are embedded in the bundle.
+	TRACES_FILENAME = "execution-data/traces.json"
+	// CPU_PROFILE_FILENAME is the name of the file with the CPU profile of troubleshoot, when profiles are embedded in the bundle.
+	CPU_PROFILE_FILENAME = "execution-data/cpu.pprof"
+	// HEAP_PROFILE_FILENAME is the name of the file with the heap profile of troubleshoot, when profiles are embedded in the bundle.
+	HEAP_PROFILE_FILENAME = "execution-data/heap.pprof"
+	// EXECUTION_TRACE_FILENAME is the name of the file with the Go execution trace of troubleshoot, when profiles are embedded in the bundle.
+	EXECUTION_TRACE_FILENAME = "execution-data/trace.out"
+	// FACTS_FILENAME is the name of the file with the facts of the cluster read before collection, which exclude templates are evaluated against.
+	FACTS_FILENAME = "facts.json"
+	// MANIFEST_FILENAME is the name of the file with the checksums of the files of the bundle and where it comes from, written last.
+	MANIFEST_FILENAME = "manifest.json"
+	// MANIFEST_SIGNATURE_FILENAME is the name of the file with the base64 encoded ed25519 signature of the manifest, when the bundle is signed.
+	MANIFEST_SIGNATURE_FILENAME = "manifest.json.sig"
+	// DOCS_DIR is the directory of the bundle with the documentation shipped with the spec, one markdown file per document.
+	DOCS_DIR = "docs"
 
 	// Cluster Resources Collector Directories
 	CLUSTER_RESOURCES_DIR                         = "cluster-resources"
@@ -106,6 +133,18 @@ const (
 	GP_DEFAULT_IMAGE     = "alpine:3"
 	GP_DEFAULT_NAMESPACE = "default"
 
+	// Node latency constants
+	NODE_LATENCY_DEFAULT_IMAGE = "alpine:3"
+	NODE_LATENCY_DEFAULT_COUNT = 10
+
+	// OpenShift Collector Directories
+	OPENSHIFT_DIR                          = "openshift"
+	OPENSHIFT_CLUSTER_VERSIONS             = "clusterversions"
+	OPENSHIFT_CLUSTER_OPERATORS            = "clusteroperators"
+	OPENSHIFT_MACHINE_CONFIG_POOLS         = "machineconfigpools"
+	OPENSHIFT_MACHINE_CONFIGS              = "machineconfigs"
+	OPENSHIFT_SECURITY_CONTEXT_CONSTRAINTS = "securitycontextconstraints"
+
 	// Analyzer Outcome types
 	OUTCOME_PASS = "pass"
 	OUTCOME_WARN = "warn"
@@ -113,4 +152,16 @@ const (
 
 	// List of remote nodes to collect data from in a support bundle
 	NODE_LIST_FILE = "host-collectors/system/node_list.json"
+
+	// Support bundle layout versions, recorded in the version file of a bundle.
+	// BUNDLE_LAYOUT_V1 stores per-node host collector output under the collector's
+	// directory e.g. host-collectors/system/<node>/cpu.json
+	BUNDLE_LAYOUT_V1 = "v1"
+	// BUNDLE_LAYOUT_V2 stores per-node host collector output under a directory per
+	// node e.g. host-collectors/<node>/system/cpu.json
+	BUNDLE_LAYOUT_V2 = "v2"
+	// BUNDLE_LAYOUT_VERSION is the layout version of bundles written by this version of troubleshoot
+	BUNDLE_LAYOUT_VERSION = BUNDLE_LAYOUT_V2
+	// HOST_COLLECTORS_DIR is the top level directory of host collector output in a bundle
+	HOST_COLLECTORS_DIR = "host-collectors"
 )
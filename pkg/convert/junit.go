@@ -0,0 +1,111 @@
+package convert
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	analyze "github.com/replicatedhq/troubleshoot/pkg/analyze"
+)
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// ToJUnit renders analyzer results as a JUnit XML report with one test case per result. Failed
+// results are test failures; JUnit has no notion of a warning, so warnings are passing test cases
+// with the message written to system-out.
+func ToJUnit(name string, results []*analyze.AnalyzeResult) ([]byte, error) {
+	suite := junitTestSuite{
+		Name:      name,
+		TestCases: []junitTestCase{},
+	}
+
+	for _, result := range results {
+		if result == nil {
+			continue
+		}
+
+		testCase := junitTestCase{
+			Name:      result.Title,
+			ClassName: name,
+		}
+		details := resultDetails(result)
+
+		switch {
+		case result.IsFail:
+			suite.Failures++
+			testCase.Failure = &junitFailure{
+				Message: result.Message,
+				Type:    string(failureSeverity(result)),
+				Text:    details,
+			}
+		case result.IsWarn:
+			testCase.SystemOut = fmt.Sprintf("WARN: %s", details)
+		default:
+			testCase.SystemOut = details
+		}
+
+		suite.Tests++
+		suite.TestCases = append(suite.TestCases, testCase)
+	}
+
+	report := junitTestSuites{
+		Name:     name,
+		Tests:    suite.Tests,
+		Failures: suite.Failures,
+		Suites:   []junitTestSuite{suite},
+	}
+
+	b, err := xml.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal results as junit")
+	}
+
+	return append([]byte(xml.Header), append(b, '\n')...), nil
+}
+
+// resultDetails is the message of a result followed by its link and remediation, one per line.
+func resultDetails(result *analyze.AnalyzeResult) string {
+	lines := []string{result.Message}
+	if result.URI != "" {
+		lines = append(lines, fmt.Sprintf("For more information: %s", result.URI))
+	}
+	lines = append(lines, analyze.RemediationLines(result.Remediation)...)
+	return strings.Join(lines, "\n")
+}
+
+func failureSeverity(result *analyze.AnalyzeResult) analyze.Severity {
+	if result.Severity != "" {
+		return result.Severity
+	}
+	if result.Strict {
+		return analyze.SeverityCritical
+	}
+	return analyze.SeverityError
+}
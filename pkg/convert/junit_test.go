@@ -0,0 +1,39 @@
+package convert
+
+import (
+	"encoding/xml"
+	"testing"
+
+	analyze "github.com/replicatedhq/troubleshoot/pkg/analyze"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToJUnit(t *testing.T) {
+	b, err := ToJUnit("my-preflight", []*analyze.AnalyzeResult{
+		{IsPass: true, Title: "Kubernetes version", Message: "Kubernetes 1.29 is supported"},
+		{IsWarn: true, Title: "Amount of Memory", Message: "At least 32Gi is recommended"},
+		{IsFail: true, Strict: true, Title: "Number of CPUs", Message: "At least 4 CPUs are required", URI: "https://troubleshoot.sh"},
+		nil,
+	})
+	require.NoError(t, err)
+	assert.Contains(t, string(b), xml.Header)
+
+	report := junitTestSuites{}
+	require.NoError(t, xml.Unmarshal(b, &report))
+	assert.Equal(t, 3, report.Tests)
+	assert.Equal(t, 1, report.Failures)
+	require.Len(t, report.Suites, 1)
+
+	cases := report.Suites[0].TestCases
+	require.Len(t, cases, 3)
+	assert.Equal(t, "Kubernetes version", cases[0].Name)
+	assert.Equal(t, "my-preflight", cases[0].ClassName)
+	assert.Nil(t, cases[0].Failure)
+	assert.Equal(t, "WARN: At least 32Gi is recommended", cases[1].SystemOut)
+	assert.Nil(t, cases[1].Failure)
+	require.NotNil(t, cases[2].Failure)
+	assert.Equal(t, "At least 4 CPUs are required", cases[2].Failure.Message)
+	assert.Equal(t, "critical", cases[2].Failure.Type)
+	assert.Equal(t, "At least 4 CPUs are required\nFor more information: https://troubleshoot.sh", cases[2].Failure.Text)
+}
@@ -0,0 +1,174 @@
+package convert
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+	analyze "github.com/replicatedhq/troubleshoot/pkg/analyze"
+	"github.com/replicatedhq/troubleshoot/pkg/version"
+)
+
+const (
+	sarifVersion   = "2.1.0"
+	sarifSchemaURI = "https://json.schemastore.org/sarif-2.1.0.json"
+)
+
+var resultNameRegex = regexp.MustCompile("[^a-zA-Z0-9]+")
+
+type sarifLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version,omitempty"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	Name             string       `json:"name,omitempty"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+	HelpURI          string       `json:"helpUri,omitempty"`
+}
+
+type sarifResult struct {
+	RuleID     string                 `json:"ruleId"`
+	RuleIndex  int                    `json:"ruleIndex"`
+	Level      string                 `json:"level"`
+	Message    sarifMessage           `json:"message"`
+	Locations  []sarifLocation        `json:"locations,omitempty"`
+	Properties map[string]interface{} `json:"properties,omitempty"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations"`
+}
+
+type sarifLogicalLocation struct {
+	Name               string `json:"name"`
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+	Kind               string `json:"kind"`
+}
+
+// ToSARIF renders the warnings and failures of analyzer results as a SARIF 2.1.0 log, with a
+// rule for each analyzer that reported one. Passing results are not findings and are left out.
+func ToSARIF(toolName string, results []*analyze.AnalyzeResult) ([]byte, error) {
+	run := sarifRun{
+		Tool: sarifTool{
+			Driver: sarifDriver{
+				Name:           toolName,
+				Version:        version.Version(),
+				InformationURI: "https://troubleshoot.sh",
+				Rules:          []sarifRule{},
+			},
+		},
+		Results: []sarifResult{},
+	}
+
+	ruleIndexes := map[string]int{}
+	for _, result := range results {
+		if result == nil || (!result.IsFail && !result.IsWarn) {
+			continue
+		}
+
+		ruleID := resultName(result.Title)
+		ruleIndex, ok := ruleIndexes[ruleID]
+		if !ok {
+			ruleIndex = len(run.Tool.Driver.Rules)
+			ruleIndexes[ruleID] = ruleIndex
+			run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRule{
+				ID:               ruleID,
+				Name:             result.Title,
+				ShortDescription: sarifMessage{Text: result.Title},
+				HelpURI:          result.URI,
+			})
+		}
+
+		level := "warning"
+		if result.IsFail {
+			level = "error"
+		}
+
+		sarifRes := sarifResult{
+			RuleID:     ruleID,
+			RuleIndex:  ruleIndex,
+			Level:      level,
+			Message:    sarifMessage{Text: resultDetails(result)},
+			Properties: sarifProperties(result),
+		}
+		if obj := result.InvolvedObject; obj != nil {
+			name := obj.Name
+			if obj.Namespace != "" {
+				name = obj.Namespace + "/" + obj.Name
+			}
+			sarifRes.Locations = []sarifLocation{{
+				LogicalLocations: []sarifLogicalLocation{{
+					Name:               obj.Name,
+					FullyQualifiedName: strings.ToLower(obj.Kind) + "/" + name,
+					Kind:               "resource",
+				}},
+			}}
+		}
+
+		run.Results = append(run.Results, sarifRes)
+	}
+
+	b, err := json.MarshalIndent(sarifLog{
+		Version: sarifVersion,
+		Schema:  sarifSchemaURI,
+		Runs:    []sarifRun{run},
+	}, "", "  ")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal results as sarif")
+	}
+
+	return append(b, '\n'), nil
+}
+
+func sarifProperties(result *analyze.AnalyzeResult) map[string]interface{} {
+	properties := map[string]interface{}{}
+	if result.Severity != "" {
+		properties["severity"] = result.Severity
+	}
+	if len(result.Categories) > 0 {
+		properties["tags"] = result.Categories
+	}
+	if result.Strict {
+		properties["strict"] = true
+	}
+	if result.Measurement != nil {
+		properties["measurement"] = result.Measurement
+	}
+	if result.Remediation != nil {
+		properties["remediation"] = result.Remediation
+	}
+	if len(properties) == 0 {
+		return nil
+	}
+	return properties
+}
+
+// resultName turns a result title into a dotted lowercase identifier, e.g. "Amount of Memory"
+// becomes "amount.of.memory".
+func resultName(title string) string {
+	return resultNameRegex.ReplaceAllString(strings.ToLower(title), ".")
+}
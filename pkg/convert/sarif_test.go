@@ -0,0 +1,54 @@
+package convert
+
+import (
+	"encoding/json"
+	"testing"
+
+	analyze "github.com/replicatedhq/troubleshoot/pkg/analyze"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestToSARIF(t *testing.T) {
+	b, err := ToSARIF("preflight", []*analyze.AnalyzeResult{
+		{IsPass: true, Title: "Kubernetes version", Message: "Kubernetes 1.29 is supported"},
+		{IsWarn: true, Title: "Amount of Memory - Node node1", Message: "At least 32Gi is recommended", Categories: []string{"resources"}},
+		{IsWarn: true, Title: "Amount of Memory - Node node1", Message: "Swap is enabled"},
+		{
+			IsFail:  true,
+			Title:   "Deployment Status",
+			Message: "The api deployment has no ready replicas",
+			URI:     "https://troubleshoot.sh",
+			InvolvedObject: &corev1.ObjectReference{
+				Kind:      "Deployment",
+				Namespace: "default",
+				Name:      "api",
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	log := sarifLog{}
+	require.NoError(t, json.Unmarshal(b, &log))
+	assert.Equal(t, "2.1.0", log.Version)
+	require.Len(t, log.Runs, 1)
+
+	run := log.Runs[0]
+	assert.Equal(t, "preflight", run.Tool.Driver.Name)
+	require.Len(t, run.Tool.Driver.Rules, 2)
+	assert.Equal(t, "amount.of.memory.node.node1", run.Tool.Driver.Rules[0].ID)
+	assert.Equal(t, "deployment.status", run.Tool.Driver.Rules[1].ID)
+	assert.Equal(t, "https://troubleshoot.sh", run.Tool.Driver.Rules[1].HelpURI)
+
+	// passing results are not reported
+	require.Len(t, run.Results, 3)
+	assert.Equal(t, "warning", run.Results[0].Level)
+	assert.Equal(t, []interface{}{"resources"}, run.Results[0].Properties["tags"])
+	assert.Equal(t, 0, run.Results[1].RuleIndex)
+	assert.Equal(t, "error", run.Results[2].Level)
+	assert.Equal(t, 1, run.Results[2].RuleIndex)
+	assert.Equal(t, "The api deployment has no ready replicas\nFor more information: https://troubleshoot.sh", run.Results[2].Message.Text)
+	require.Len(t, run.Results[2].Locations, 1)
+	assert.Equal(t, "deployment/default/api", run.Results[2].Locations[0].LogicalLocations[0].FullyQualifiedName)
+}
@@ -2,11 +2,10 @@ package convert
 
 import (
 	"fmt"
-	"regexp"
-	"strings"
 
 	multierror "github.com/hashicorp/go-multierror"
 	analyze "github.com/replicatedhq/troubleshoot/pkg/analyze"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
 	corev1 "k8s.io/api/core/v1"
 )
 
@@ -41,6 +40,9 @@ type Result struct {
 	Variables      map[string]interface{}  `json:"variables,omitempty" yaml:"variables,omitempty" hcl:"variables,omitempty"`
 	Error          string                  `json:"error,omitempty" yaml:"error,omitempty" hcl:"error,omitempty"`
 	InvolvedObject *corev1.ObjectReference `json:"involvedObject,omitempty" yaml:"involvedObject,omitempty" hcl:"involvedObject,omitempty"`
+
+	Remediation   *troubleshootv1beta2.Remediation `json:"remediation,omitempty" yaml:"remediation,omitempty" hcl:"remediation,omitempty"`
+	Documentation string                           `json:"documentation,omitempty" yaml:"documentation,omitempty" hcl:"documentation,omitempty"`
 }
 
 func (m *Insight) Render(data interface{}) (*Insight, error) {
@@ -65,15 +67,13 @@ func errWrap(err error, text string) error {
 }
 
 func FromAnalyzerResult(input []*analyze.AnalyzeResult) []*Result {
-	reg := regexp.MustCompile("[^a-zA-Z0-9]+")
-
 	result := make([]*Result, 0)
 	for _, i := range input {
 		// Continue on nil result to prevent panic
 		if i == nil {
 			continue
 		}
-		name := reg.ReplaceAllString(strings.ToLower(i.Title), ".")
+		name := resultName(i.Title)
 		r := &Result{
 			Meta: Meta{
 				Name: name,
@@ -97,6 +97,8 @@ func FromAnalyzerResult(input []*analyze.AnalyzeResult) []*Result {
 			AnalyzerSpec:   "",
 			Variables:      map[string]interface{}{},
 			InvolvedObject: i.InvolvedObject,
+			Remediation:    i.Remediation,
+			Documentation:  i.Documentation,
 		}
 		if i.IsFail {
 			r.Severity = SeverityError
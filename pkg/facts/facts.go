@@ -0,0 +1,283 @@
+// Package facts reads facts about a cluster before anything is collected from it, such as its
+// Kubernetes version, distribution, APIs and nodes, and evaluates the exclude templates of
+// collectors and analyzers against them, so that one spec adapts to many environments. The facts
+// are read in a phase of their own, once per run, before the collectors run, and only when a
+// template references them.
+package facts
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/troubleshoot/internal/util"
+	analyze "github.com/replicatedhq/troubleshoot/pkg/analyze"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/multitype"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes"
+)
+
+// Facts are the facts of a cluster exclude templates are evaluated against, as .Facts. They are
+// saved in bundles as facts.json, for analyzers to read.
+type Facts struct {
+	// KubernetesVersion is the version of the API server without the v prefix, e.g. 1.30.2, to
+	// compare with semverCompare
+	KubernetesVersion string `json:"kubernetesVersion"`
+	// Distribution is the distribution the nodes and APIs of the cluster are from, e.g. eks, k3s
+	// or openShift, as the distribution analyzer names it, and empty when unknown
+	Distribution string `json:"distribution"`
+	NodeCount    int    `json:"nodeCount"`
+	// NodeOS are the operating systems of the nodes, e.g. linux and windows
+	NodeOS []string `json:"nodeOS"`
+	// NodeOSImages are the OS images of the nodes, e.g. Ubuntu 22.04.4 LTS
+	NodeOSImages []string `json:"nodeOSImages"`
+	// APIs are the kinds the API server serves outside of the core group, as group/Kind
+	APIs []string `json:"apis"`
+
+	// resourceNames are the plural.group names of the resources of the APIs
+	resourceNames map[string]bool
+}
+
+// HasCRD returns true when the cluster serves the kind, by group/Kind, e.g.
+// argoproj.io/Application, or by the name of its custom resource definition, e.g.
+// applications.argoproj.io. Kinds of the core group are not matched.
+func (f *Facts) HasCRD(name string) bool {
+	if f.resourceNames[name] {
+		return true
+	}
+	for _, api := range f.APIs {
+		if api == name {
+			return true
+		}
+	}
+	return false
+}
+
+// HasNodeOS returns true when a node runs the operating system, e.g. windows
+func (f *Facts) HasNodeOS(os string) bool {
+	for _, nodeOS := range f.NodeOS {
+		if strings.EqualFold(nodeOS, os) {
+			return true
+		}
+	}
+	return false
+}
+
+// Gather reads the facts of the cluster. Facts that cannot be read, for lack of permissions,
+// are left empty and their errors returned with the facts that could.
+func Gather(ctx context.Context, client kubernetes.Interface) (*Facts, []error) {
+	facts := &Facts{resourceNames: map[string]bool{}}
+	errs := []error{}
+
+	version, err := client.Discovery().ServerVersion()
+	if err != nil {
+		errs = append(errs, errors.Wrap(err, "failed to get kubernetes version"))
+	} else {
+		facts.KubernetesVersion = strings.TrimPrefix(version.GitVersion, "v")
+	}
+
+	// groups that cannot be discovered are reported in the error, along with those that could
+	_, resources, err := client.Discovery().ServerGroupsAndResources()
+	if err != nil {
+		errs = append(errs, errors.Wrap(err, "failed to discover apis"))
+	}
+	apis := map[string]bool{}
+	for _, list := range resources {
+		gv, err := schema.ParseGroupVersion(list.GroupVersion)
+		if err != nil || gv.Group == "" {
+			continue
+		}
+		for _, resource := range list.APIResources {
+			if strings.Contains(resource.Name, "/") {
+				// subresources
+				continue
+			}
+			apis[fmt.Sprintf("%s/%s", gv.Group, resource.Kind)] = true
+			facts.resourceNames[fmt.Sprintf("%s.%s", resource.Name, gv.Group)] = true
+		}
+	}
+	facts.APIs = sortedNonEmpty(apis)
+
+	nodes, err := client.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		errs = append(errs, errors.Wrap(err, "failed to list nodes"))
+	} else {
+		facts.NodeCount = len(nodes.Items)
+		nodeOS, nodeOSImages := map[string]bool{}, map[string]bool{}
+		for _, node := range nodes.Items {
+			nodeOS[node.Status.NodeInfo.OperatingSystem] = true
+			nodeOSImages[node.Status.NodeInfo.OSImage] = true
+		}
+		facts.NodeOS, facts.NodeOSImages = sortedNonEmpty(nodeOS), sortedNonEmpty(nodeOSImages)
+
+		nodeProviders, distribution := analyze.ParseNodesForProviders(nodes.Items)
+		facts.Distribution = analyze.CheckApiResourcesForProviders(&nodeProviders, resources, distribution)
+	}
+
+	return facts, errs
+}
+
+func sortedNonEmpty(set map[string]bool) []string {
+	values := []string{}
+	for value := range set {
+		if value != "" {
+			values = append(values, value)
+		}
+	}
+	sort.Strings(values)
+	return values
+}
+
+// templateData is what exclude templates are rendered with
+type templateData struct {
+	Facts *Facts
+}
+
+// IsTemplate returns true when the exclude of a collector is a template to evaluate against the
+// facts of the cluster
+func IsTemplate(exclude *multitype.BoolOrString) bool {
+	return exclude != nil && exclude.Type == multitype.String && strings.Contains(exclude.StrVal, "{{")
+}
+
+// Referenced returns true when an exclude template of the collectors or analyzers references the
+// facts, which are only read then, since listing the nodes and APIs of large clusters is slow and
+// may be forbidden
+func Referenced(collectors []*troubleshootv1beta2.Collect, analyzers []*troubleshootv1beta2.Analyze) bool {
+	for _, collector := range collectors {
+		if template, ok := excludeTemplate(collector); ok && strings.Contains(template, ".Facts") {
+			return true
+		}
+	}
+	for _, analyzer := range analyzers {
+		if template, ok := excludeTemplate(analyzer); ok && strings.Contains(template, ".Facts") {
+			return true
+		}
+	}
+	return false
+}
+
+// EvaluateExclude renders an exclude template with the facts and parses the result as a bool
+func EvaluateExclude(exclude string, facts *Facts) (bool, error) {
+	rendered, err := util.RenderTemplate(exclude, templateData{Facts: facts})
+	if err != nil {
+		return false, errors.Wrap(err, "failed to render exclude template")
+	}
+	excluded, err := strconv.ParseBool(strings.TrimSpace(rendered))
+	if err != nil {
+		return false, errors.Wrapf(err, "exclude template rendered %q, which is not a bool", rendered)
+	}
+	return excluded, nil
+}
+
+// ResolveCollectorExcludes returns the collectors with their exclude templates evaluated against
+// the facts. The collectors that have one are copied, with their exclude replaced by its value,
+// and the others are returned as they are, so that the specs can be evaluated again. Collectors
+// whose template cannot be evaluated keep it, and are collected, and the errors are returned with
+// the collectors.
+func ResolveCollectorExcludes(collectors []*troubleshootv1beta2.Collect, facts *Facts) ([]*troubleshootv1beta2.Collect, error) {
+	resolved := make([]*troubleshootv1beta2.Collect, 0, len(collectors))
+	messages := []string{}
+	for _, collector := range collectors {
+		template, ok := excludeTemplate(collector)
+		if !ok {
+			resolved = append(resolved, collector)
+			continue
+		}
+		excluded, err := EvaluateExclude(template, facts)
+		if err != nil {
+			messages = append(messages, fmt.Sprintf("%s collector: %v", specTitle(collector), err))
+			resolved = append(resolved, collector)
+			continue
+		}
+		collector = collector.DeepCopy()
+		setExclude(collector, excluded)
+		resolved = append(resolved, collector)
+	}
+	return resolved, excludeErrors(messages)
+}
+
+// ResolveAnalyzerExcludes is ResolveCollectorExcludes for analyzers. Analyzers whose template
+// cannot be evaluated fail when they are run.
+func ResolveAnalyzerExcludes(analyzers []*troubleshootv1beta2.Analyze, facts *Facts) ([]*troubleshootv1beta2.Analyze, error) {
+	resolved := make([]*troubleshootv1beta2.Analyze, 0, len(analyzers))
+	messages := []string{}
+	for _, analyzer := range analyzers {
+		template, ok := excludeTemplate(analyzer)
+		if !ok {
+			resolved = append(resolved, analyzer)
+			continue
+		}
+		excluded, err := EvaluateExclude(template, facts)
+		if err != nil {
+			messages = append(messages, fmt.Sprintf("%s analyzer: %v", specTitle(analyzer), err))
+			resolved = append(resolved, analyzer)
+			continue
+		}
+		analyzer = analyzer.DeepCopy()
+		setExclude(analyzer, excluded)
+		resolved = append(resolved, analyzer)
+	}
+	return resolved, excludeErrors(messages)
+}
+
+func excludeErrors(messages []string) error {
+	if len(messages) == 0 {
+		return nil
+	}
+	return errors.Errorf("failed to evaluate exclude templates: %s", strings.Join(messages, "; "))
+}
+
+// specExclude returns the exclude field of the collector or analyzer set in a Collect or an
+// Analyze, which have exactly one of their fields set
+func specExclude(spec interface{}) reflect.Value {
+	reflected := reflect.ValueOf(spec).Elem()
+	for i := 0; i < reflected.NumField(); i++ {
+		field := reflected.Field(i)
+		if field.Kind() != reflect.Ptr || field.IsNil() || field.Elem().Kind() != reflect.Struct {
+			continue
+		}
+		exclude := field.Elem().FieldByName("Exclude")
+		if exclude.IsValid() && exclude.Type() == reflect.TypeOf(&multitype.BoolOrString{}) {
+			return exclude
+		}
+		return reflect.Value{}
+	}
+	return reflect.Value{}
+}
+
+// excludeTemplate returns the exclude template of a Collect or an Analyze, and false when its
+// exclude is not a template
+func excludeTemplate(spec interface{}) (string, bool) {
+	exclude := specExclude(spec)
+	if !exclude.IsValid() || exclude.IsNil() {
+		return "", false
+	}
+	value := exclude.Interface().(*multitype.BoolOrString)
+	if !IsTemplate(value) {
+		return "", false
+	}
+	return value.StrVal, true
+}
+
+func setExclude(spec interface{}, excluded bool) {
+	specExclude(spec).Set(reflect.ValueOf(multitype.FromBool(excluded)))
+}
+
+// specTitle returns the type of the collector or analyzer set in a Collect or an Analyze as it is
+// named in specs
+func specTitle(spec interface{}) string {
+	reflected := reflect.ValueOf(spec).Elem()
+	for i := 0; i < reflected.NumField(); i++ {
+		field := reflected.Field(i)
+		if field.Kind() == reflect.Ptr && !field.IsNil() {
+			return strings.Split(reflected.Type().Field(i).Tag.Get("json"), ",")[0]
+		}
+	}
+	return "unknown"
+}
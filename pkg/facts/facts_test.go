@@ -0,0 +1,162 @@
+package facts
+
+import (
+	"context"
+	"testing"
+
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/multitype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/version"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func testClient() *fake.Clientset {
+	client := fake.NewSimpleClientset(
+		&corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: "node-1", Labels: map[string]string{"node.kubernetes.io/instance-type": "k3s"}},
+			Status:     corev1.NodeStatus{NodeInfo: corev1.NodeSystemInfo{OperatingSystem: "linux", OSImage: "Ubuntu 22.04.4 LTS"}},
+		},
+		&corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: "node-2"},
+			Status:     corev1.NodeStatus{NodeInfo: corev1.NodeSystemInfo{OperatingSystem: "windows", OSImage: "Windows Server 2022 Datacenter"}},
+		},
+	)
+	discovery := client.Discovery().(*fakediscovery.FakeDiscovery)
+	discovery.FakedServerVersion = &version.Info{GitVersion: "v1.30.2+k3s1"}
+	discovery.Resources = []*metav1.APIResourceList{
+		{
+			GroupVersion: "v1",
+			APIResources: []metav1.APIResource{{Name: "pods", Kind: "Pod"}},
+		},
+		{
+			GroupVersion: "argoproj.io/v1alpha1",
+			APIResources: []metav1.APIResource{
+				{Name: "applications", Kind: "Application"},
+				{Name: "applications/status", Kind: "Application"},
+			},
+		},
+	}
+	return client
+}
+
+func TestGather(t *testing.T) {
+	facts, errs := Gather(context.Background(), testClient())
+	require.Empty(t, errs)
+
+	assert.Equal(t, "1.30.2+k3s1", facts.KubernetesVersion)
+	assert.Equal(t, "k3s", facts.Distribution)
+	assert.Equal(t, 2, facts.NodeCount)
+	assert.Equal(t, []string{"argoproj.io/Application"}, facts.APIs)
+	assert.Equal(t, []string{"linux", "windows"}, facts.NodeOS)
+	assert.Equal(t, []string{"Ubuntu 22.04.4 LTS", "Windows Server 2022 Datacenter"}, facts.NodeOSImages)
+	assert.True(t, facts.HasCRD("argoproj.io/Application"))
+	assert.True(t, facts.HasCRD("applications.argoproj.io"))
+	assert.False(t, facts.HasCRD("/Pod"))
+	assert.False(t, facts.HasCRD("velero.io/Backup"))
+	assert.True(t, facts.HasNodeOS("Windows"))
+}
+
+func TestEvaluateExclude(t *testing.T) {
+	facts := &Facts{
+		KubernetesVersion: "1.27.4",
+		Distribution:      "eks",
+		NodeOS:            []string{"linux"},
+		APIs:              []string{"argoproj.io/Application"},
+	}
+
+	tests := []struct {
+		exclude string
+		want    bool
+		wantErr bool
+	}{
+		{exclude: `{{ not (.Facts.HasCRD "argoproj.io/Application") }}`, want: false},
+		{exclude: `{{ semverCompare "<1.28.0" .Facts.KubernetesVersion }}`, want: true},
+		{exclude: `{{ ne .Facts.Distribution "eks" }}`, want: false},
+		{exclude: ` {{ .Facts.HasNodeOS "windows" }}
+`, want: false},
+		{exclude: `{{ .Facts.Distribution }}`, wantErr: true},
+		{exclude: `{{ .Facts.Unknown }}`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.exclude, func(t *testing.T) {
+			got, err := EvaluateExclude(tt.exclude, facts)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestReferenced(t *testing.T) {
+	templated := &troubleshootv1beta2.Collect{Logs: &troubleshootv1beta2.Logs{
+		CollectorMeta: troubleshootv1beta2.CollectorMeta{Exclude: multitype.FromString(`{{ not (.Facts.HasNodeOS "windows") }}`)},
+	}}
+	constant := &troubleshootv1beta2.Collect{Logs: &troubleshootv1beta2.Logs{
+		CollectorMeta: troubleshootv1beta2.CollectorMeta{Exclude: multitype.FromString("{{ false }}")},
+	}}
+	analyzer := &troubleshootv1beta2.Analyze{ClusterVersion: &troubleshootv1beta2.ClusterVersion{
+		AnalyzeMeta: troubleshootv1beta2.AnalyzeMeta{Exclude: multitype.FromString(`{{ eq .Facts.Distribution "eks" }}`)},
+	}}
+
+	assert.False(t, Referenced(nil, nil))
+	assert.False(t, Referenced([]*troubleshootv1beta2.Collect{{ClusterInfo: &troubleshootv1beta2.ClusterInfo{}}, constant}, nil))
+	assert.True(t, Referenced([]*troubleshootv1beta2.Collect{constant, templated}, nil))
+	assert.True(t, Referenced(nil, []*troubleshootv1beta2.Analyze{analyzer}))
+}
+
+func TestResolveCollectorExcludes(t *testing.T) {
+	collectors := []*troubleshootv1beta2.Collect{
+		{ClusterInfo: &troubleshootv1beta2.ClusterInfo{}},
+		{Logs: &troubleshootv1beta2.Logs{
+			CollectorMeta: troubleshootv1beta2.CollectorMeta{Exclude: multitype.FromString(`{{ not (.Facts.HasCRD "argoproj.io/Application") }}`)},
+			Name:          "argocd",
+		}},
+		{Logs: &troubleshootv1beta2.Logs{
+			CollectorMeta: troubleshootv1beta2.CollectorMeta{Exclude: multitype.FromString(`{{ .Facts.HasCRD "argoproj.io/Application" }}`)},
+			Name:          "no-argocd",
+		}},
+		{Logs: &troubleshootv1beta2.Logs{
+			CollectorMeta: troubleshootv1beta2.CollectorMeta{Exclude: multitype.FromString(`{{ .Facts.Distribution }}`)},
+			Name:          "broken",
+		}},
+		{Secret: &troubleshootv1beta2.Secret{CollectorMeta: troubleshootv1beta2.CollectorMeta{Exclude: multitype.FromString("true")}}},
+	}
+
+	facts, errs := Gather(context.Background(), testClient())
+	require.Empty(t, errs)
+
+	resolved, err := ResolveCollectorExcludes(collectors, facts)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "logs collector: exclude template rendered \"k3s\"")
+	require.Len(t, resolved, 5)
+
+	assert.Same(t, collectors[0], resolved[0])
+	assert.Equal(t, multitype.FromBool(false), resolved[1].Logs.Exclude)
+	assert.Equal(t, multitype.FromBool(true), resolved[2].Logs.Exclude)
+	assert.Same(t, collectors[3], resolved[3])
+	assert.Same(t, collectors[4], resolved[4])
+	// the specs are not changed, so that they can be evaluated again
+	assert.Equal(t, multitype.FromString(`{{ not (.Facts.HasCRD "argoproj.io/Application") }}`), collectors[1].Logs.Exclude)
+}
+
+func TestResolveAnalyzerExcludes(t *testing.T) {
+	analyzers := []*troubleshootv1beta2.Analyze{
+		{ClusterVersion: &troubleshootv1beta2.ClusterVersion{}},
+		{CustomResourceDefinition: &troubleshootv1beta2.CustomResourceDefinition{
+			AnalyzeMeta: troubleshootv1beta2.AnalyzeMeta{Exclude: multitype.FromString(`{{ lt .Facts.NodeCount 3 }}`)},
+		}},
+	}
+
+	resolved, err := ResolveAnalyzerExcludes(analyzers, &Facts{NodeCount: 1})
+	require.NoError(t, err)
+	assert.Same(t, analyzers[0], resolved[0])
+	assert.Equal(t, multitype.FromBool(true), resolved[1].CustomResourceDefinition.Exclude)
+}
@@ -0,0 +1,56 @@
+package k8sutil
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/troubleshoot/pkg/constants"
+	"k8s.io/client-go/rest"
+)
+
+// serviceAccountDir is where the credentials of the service account are mounted in a pod
+var serviceAccountDir = "/var/run/secrets/kubernetes.io/serviceaccount"
+
+// IsInCluster returns whether troubleshoot runs in a pod with the credentials of its service
+// account mounted.
+func IsInCluster() bool {
+	if os.Getenv("KUBERNETES_SERVICE_HOST") == "" || os.Getenv("KUBERNETES_SERVICE_PORT") == "" {
+		return false
+	}
+	_, err := os.Stat(filepath.Join(serviceAccountDir, "token"))
+	return err == nil
+}
+
+// GetInClusterRESTConfig returns the config of the service account of the pod, throttled to
+// IN_CLUSTER_CLIENT_QPS. Requests the API server rejects with 429 are retried by the client
+// after the delay the server asks for.
+func GetInClusterRESTConfig() (*rest.Config, error) {
+	if !IsInCluster() {
+		return nil, errors.New("service account credentials not found, troubleshoot is not running in a pod")
+	}
+
+	config, err := rest.InClusterConfig()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load in-cluster config")
+	}
+	config.QPS = constants.IN_CLUSTER_CLIENT_QPS
+	config.Burst = constants.IN_CLUSTER_CLIENT_BURST
+
+	return config, nil
+}
+
+// GetInClusterNamespace returns the namespace of the pod, from the POD_NAMESPACE environment
+// variable when it is set with the downward API, or from the namespace of the service account.
+func GetInClusterNamespace() (string, error) {
+	if namespace := os.Getenv("POD_NAMESPACE"); namespace != "" {
+		return namespace, nil
+	}
+
+	b, err := os.ReadFile(filepath.Join(serviceAccountDir, "namespace"))
+	if err != nil {
+		return "", errors.Wrap(err, "failed to read the namespace of the service account")
+	}
+	return strings.TrimSpace(string(b)), nil
+}
@@ -0,0 +1,63 @@
+package k8sutil
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setServiceAccountDir(t *testing.T, files map[string]string) {
+	t.Helper()
+
+	dir := t.TempDir()
+	for name, contents := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(contents), 0644))
+	}
+
+	previous := serviceAccountDir
+	serviceAccountDir = dir
+	t.Cleanup(func() { serviceAccountDir = previous })
+}
+
+func TestIsInCluster(t *testing.T) {
+	t.Setenv("KUBERNETES_SERVICE_HOST", "10.96.0.1")
+	t.Setenv("KUBERNETES_SERVICE_PORT", "443")
+
+	setServiceAccountDir(t, map[string]string{"token": "token"})
+	assert.True(t, IsInCluster())
+
+	setServiceAccountDir(t, nil)
+	assert.False(t, IsInCluster(), "no service account token")
+
+	setServiceAccountDir(t, map[string]string{"token": "token"})
+	t.Setenv("KUBERNETES_SERVICE_HOST", "")
+	assert.False(t, IsInCluster(), "no kubernetes service")
+}
+
+func TestGetInClusterRESTConfig_NotInCluster(t *testing.T) {
+	t.Setenv("KUBERNETES_SERVICE_HOST", "")
+	_, err := GetInClusterRESTConfig()
+	assert.Error(t, err)
+}
+
+func TestGetInClusterNamespace(t *testing.T) {
+	setServiceAccountDir(t, map[string]string{"namespace": "troubleshoot\n"})
+
+	t.Setenv("POD_NAMESPACE", "")
+	namespace, err := GetInClusterNamespace()
+	require.NoError(t, err)
+	assert.Equal(t, "troubleshoot", namespace)
+
+	t.Setenv("POD_NAMESPACE", "app")
+	namespace, err = GetInClusterNamespace()
+	require.NoError(t, err)
+	assert.Equal(t, "app", namespace)
+
+	setServiceAccountDir(t, nil)
+	t.Setenv("POD_NAMESPACE", "")
+	_, err = GetInClusterNamespace()
+	assert.Error(t, err)
+}
@@ -3,52 +3,39 @@ package k8sutil
 import (
 	"bytes"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
 	"strings"
 	"time"
 
+	"github.com/pkg/errors"
 	restclient "k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/portforward"
 	"k8s.io/client-go/transport/spdy"
 )
 
+// PortForward forwards localPort to remotePort of a pod until the returned channel is closed. It
+// returns once the forwarded port responds to HTTP requests. Collectors that forward ports should
+// share the tunnels of a PortForwardPool instead.
 func PortForward(config *restclient.Config, localPort int, remotePort int, namespace string, podName string) (chan struct{}, error) {
-	roundTripper, upgrader, err := spdy.RoundTripperFor(config)
-	if err != nil {
-		return nil, err
-	}
-
-	path := fmt.Sprintf("/api/v1/namespaces/%s/pods/%s/portforward", namespace, podName)
-	hostIP := strings.TrimLeft(config.Host, "htps:/")
-	serverURL := url.URL{Scheme: "http", Path: path, Host: hostIP}
-	dialer := spdy.NewDialer(upgrader, &http.Client{Transport: roundTripper}, http.MethodPost, &serverURL)
-
-	stopChan, readyChan := make(chan struct{}, 1), make(chan struct{}, 1)
-	out, errOut := new(bytes.Buffer), new(bytes.Buffer)
-
-	forwarder, err := portforward.New(dialer, []string{fmt.Sprintf("%d:%d", localPort, remotePort)}, stopChan, readyChan, out, errOut)
+	forward, err := spdyForward(config)
 	if err != nil {
 		return nil, err
 	}
 
+	target := PortForwardTarget{Namespace: namespace, Pod: podName, Port: remotePort}
+	stopChan, readyChan := make(chan struct{}, 1), make(chan struct{})
+	errChan := make(chan error, 1)
 	go func() {
-		for range readyChan { // Kubernetes will close this channel when it has something to tell us.
-		}
-		if errOut.String() != "" {
-			panic(errOut.String())
-		} else if out.String() != "" {
-			// fmt.Println(out.String())
-		}
+		errChan <- forward(target, localPort, stopChan, readyChan)
 	}()
 
-	go func() error {
-		if err = forwarder.ForwardPorts(); err != nil { // Locks until stopChan is closed.
-			panic(err)
-		}
-
-		return nil
-	}()
+	select {
+	case <-readyChan:
+	case err := <-errChan:
+		return nil, errors.Wrapf(err, "failed to forward port to %s", target)
+	}
 
 	// Block until the new service is responding, limited to (math) seconds
 	quickClient := &http.Client{
@@ -58,10 +45,14 @@ func PortForward(config *restclient.Config, localPort int, remotePort int, names
 	start := time.Now()
 	for {
 		response, err := quickClient.Get(fmt.Sprintf("http://localhost:%d", localPort))
-		if err == nil && response.StatusCode == http.StatusOK {
-			break
+		if err == nil {
+			response.Body.Close()
+			if response.StatusCode == http.StatusOK {
+				break
+			}
 		}
 		if time.Now().Sub(start) > time.Duration(time.Second*5) {
+			close(stopChan)
 			return nil, err
 		}
 
@@ -70,3 +61,34 @@ func PortForward(config *restclient.Config, localPort int, remotePort int, names
 
 	return stopChan, nil
 }
+
+// spdyForward returns the forwardFunc forwarding ports of pods through the API server of config
+func spdyForward(config *restclient.Config) (forwardFunc, error) {
+	roundTripper, upgrader, err := spdy.RoundTripperFor(config)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create port-forward round tripper")
+	}
+
+	return func(target PortForwardTarget, localPort int, stopChan <-chan struct{}, readyChan chan struct{}) error {
+		path := fmt.Sprintf("/api/v1/namespaces/%s/pods/%s/portforward", target.Namespace, target.Pod)
+		hostIP := strings.TrimLeft(config.Host, "htps:/")
+		serverURL := url.URL{Scheme: "http", Path: path, Host: hostIP}
+		dialer := spdy.NewDialer(upgrader, &http.Client{Transport: roundTripper}, http.MethodPost, &serverURL)
+
+		errOut := new(bytes.Buffer)
+		ports := []string{fmt.Sprintf("%d:%d", localPort, target.Port)}
+		forwarder, err := portforward.NewOnAddresses(dialer, []string{"localhost"}, ports, stopChan, readyChan, io.Discard, errOut)
+		if err != nil {
+			return errors.Wrap(err, "failed to create port forwarder")
+		}
+
+		// ForwardPorts returns when stopChan is closed, or when the connection to the pod is lost
+		if err := forwarder.ForwardPorts(); err != nil {
+			return err
+		}
+		if errOut.Len() > 0 {
+			return errors.New(strings.TrimSpace(errOut.String()))
+		}
+		return nil
+	}, nil
+}
@@ -0,0 +1,345 @@
+package k8sutil
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	restclient "k8s.io/client-go/rest"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// DefaultPortForwardAttempts is how many times a port-forward is attempted before giving up
+	DefaultPortForwardAttempts = 3
+	// DefaultPortForwardRetryBackoff is how long to wait before the second attempt of a
+	// port-forward, and grows with each attempt
+	DefaultPortForwardRetryBackoff = time.Second
+	// DefaultPortForwardReadyTimeout is how long to wait for a port-forward to be ready, which
+	// can take a while with slow API servers
+	DefaultPortForwardReadyTimeout = 30 * time.Second
+	// DefaultPortForwardReconnectInterval is how long to wait before reconnecting a dropped
+	// port-forward once reconnecting failed
+	DefaultPortForwardReconnectInterval = 5 * time.Second
+)
+
+// PortForwardTarget is a port of a pod to forward a local port to
+type PortForwardTarget struct {
+	Namespace string
+	Pod       string
+	Port      int
+}
+
+func (t PortForwardTarget) String() string {
+	return fmt.Sprintf("%s/%s:%d", t.Namespace, t.Pod, t.Port)
+}
+
+// forwardFunc forwards localPort to the target until stopChan is closed or the connection to the
+// pod is lost, and closes readyChan once the port is forwarded
+type forwardFunc func(target PortForwardTarget, localPort int, stopChan <-chan struct{}, readyChan chan struct{}) error
+
+// PortForwardPool shares port-forwards between the collectors forwarding to the same port of a
+// pod. A port-forward is checked before it is handed out, reconnected on the same local port when
+// it drops, and closed when the last collector using it releases it. Port-forwards connect
+// independently of each other, so that a slow pod does not hold up the collectors of the others.
+type PortForwardPool struct {
+	forward           forwardFunc
+	attempts          int
+	retryBackoff      time.Duration
+	readyTimeout      time.Duration
+	reconnectInterval time.Duration
+
+	// mut guards the port-forwards of the pool and their references, not their tunnels
+	mut      sync.Mutex
+	forwards map[PortForwardTarget]*pooledPortForward
+	closed   bool
+}
+
+type pooledPortForward struct {
+	target    PortForwardTarget
+	localPort int
+	// refs is guarded by the mutex of the pool
+	refs int
+	// ctx is canceled once the port-forward is no longer used
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	// sem guards the tunnel, it is held while connecting, and acquired with a context unlike a mutex
+	sem chan struct{}
+	// stopChan stops the current tunnel, which closes done
+	stopChan  chan struct{}
+	done      chan struct{}
+	monitored bool
+}
+
+// NewPortForwardPool returns an empty pool forwarding ports through the API server of config
+func NewPortForwardPool(config *restclient.Config) (*PortForwardPool, error) {
+	forward, err := spdyForward(config)
+	if err != nil {
+		return nil, err
+	}
+	return newPortForwardPool(forward), nil
+}
+
+func newPortForwardPool(forward forwardFunc) *PortForwardPool {
+	return &PortForwardPool{
+		forward:           forward,
+		attempts:          DefaultPortForwardAttempts,
+		retryBackoff:      DefaultPortForwardRetryBackoff,
+		readyTimeout:      DefaultPortForwardReadyTimeout,
+		reconnectInterval: DefaultPortForwardReconnectInterval,
+		forwards:          map[PortForwardTarget]*pooledPortForward{},
+	}
+}
+
+// Forward returns the local port forwarded to the target, and the function to call once the port
+// is no longer used. It returns when ctx is done, even while another collector connects the
+// port-forward.
+func (p *PortForwardPool) Forward(ctx context.Context, target PortForwardTarget) (int, func(), error) {
+	p.mut.Lock()
+	if p.closed {
+		p.mut.Unlock()
+		return 0, nil, errors.New("port-forward pool is closed")
+	}
+	f, ok := p.forwards[target]
+	if !ok {
+		localPort, err := freeLocalPort()
+		if err != nil {
+			p.mut.Unlock()
+			return 0, nil, err
+		}
+		f = newPooledPortForward(target, localPort)
+		p.forwards[target] = f
+	}
+	f.refs++
+	p.mut.Unlock()
+
+	var once sync.Once
+	release := func() {
+		once.Do(func() { p.release(f) })
+	}
+	if err := p.ensureConnected(ctx, f); err != nil {
+		release()
+		return 0, nil, err
+	}
+	return f.localPort, release, nil
+}
+
+func newPooledPortForward(target PortForwardTarget, localPort int) *pooledPortForward {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &pooledPortForward{
+		target:    target,
+		localPort: localPort,
+		ctx:       ctx,
+		cancel:    cancel,
+		sem:       make(chan struct{}, 1),
+	}
+}
+
+// ensureConnected connects the tunnel of a port-forward unless it is running and healthy, and
+// starts monitoring it once it first connects
+func (p *PortForwardPool) ensureConnected(ctx context.Context, f *pooledPortForward) error {
+	if err := f.lock(ctx); err != nil {
+		return errors.Wrapf(err, "failed to forward port to %s", f.target)
+	}
+	defer f.unlock()
+
+	if f.ctx.Err() != nil {
+		return errors.New("port-forward pool is closed")
+	}
+	if f.done != nil {
+		if f.healthy() {
+			return nil
+		}
+		klog.V(2).Infof("Port-forward to %s is not healthy, reconnecting", f.target)
+		f.stop()
+	}
+	if err := p.connect(ctx, f); err != nil {
+		return err
+	}
+	if !f.monitored {
+		f.monitored = true
+		go p.monitor(f)
+	}
+	return nil
+}
+
+// connect starts the tunnel of a port-forward, retrying with a backoff when it fails or is not
+// ready in time, until ctx is done or the port-forward is closed. It is called with the
+// port-forward locked.
+func (p *PortForwardPool) connect(ctx context.Context, f *pooledPortForward) error {
+	var lastErr error
+	for attempt := 1; attempt <= p.attempts; attempt++ {
+		if attempt > 1 {
+			klog.V(2).Infof("Retrying port-forward to %s: %v", f.target, lastErr)
+			select {
+			case <-ctx.Done():
+				return errors.Wrapf(ctx.Err(), "failed to forward port to %s", f.target)
+			case <-f.ctx.Done():
+				return errors.Errorf("port-forward to %s was closed", f.target)
+			case <-time.After(time.Duration(attempt-1) * p.retryBackoff):
+			}
+		}
+
+		stopChan, readyChan, done := make(chan struct{}), make(chan struct{}), make(chan struct{})
+		errChan := make(chan error, 1)
+		go func() {
+			defer close(done)
+			errChan <- p.forward(f.target, f.localPort, stopChan, readyChan)
+		}()
+
+		select {
+		case <-readyChan:
+			f.stopChan, f.done = stopChan, done
+			return nil
+		case err := <-errChan:
+			lastErr = err
+			if lastErr == nil {
+				lastErr = errors.New("port-forward stopped")
+			}
+		case <-time.After(p.readyTimeout):
+			close(stopChan)
+			lastErr = errors.Errorf("port-forward not ready after %s", p.readyTimeout)
+		case <-ctx.Done():
+			close(stopChan)
+			return errors.Wrapf(ctx.Err(), "failed to forward port to %s", f.target)
+		case <-f.ctx.Done():
+			close(stopChan)
+			return errors.Errorf("port-forward to %s was closed", f.target)
+		}
+	}
+	return errors.Wrapf(lastErr, "failed to forward port to %s", f.target)
+}
+
+// monitor reconnects a port-forward when its tunnel drops, until it is released
+func (p *PortForwardPool) monitor(f *pooledPortForward) {
+	for {
+		if err := f.lock(f.ctx); err != nil {
+			return
+		}
+		done := f.done
+		f.unlock()
+
+		select {
+		case <-f.ctx.Done():
+			return
+		case <-done:
+		}
+
+		if err := f.lock(f.ctx); err != nil {
+			return
+		}
+		if f.done != done {
+			// reconnected by Forward
+			f.unlock()
+			continue
+		}
+		klog.V(2).Infof("Port-forward to %s dropped, reconnecting", f.target)
+		err := p.connect(f.ctx, f)
+		f.unlock()
+		if err != nil {
+			if f.ctx.Err() != nil {
+				return
+			}
+			klog.Errorf("Failed to reconnect port-forward: %v", err)
+			select {
+			case <-f.ctx.Done():
+				return
+			case <-time.After(p.reconnectInterval):
+			}
+		}
+	}
+}
+
+func (p *PortForwardPool) release(f *pooledPortForward) {
+	p.mut.Lock()
+	f.refs--
+	if f.refs > 0 || f.ctx.Err() != nil {
+		// still used, or closed with the pool
+		p.mut.Unlock()
+		return
+	}
+	delete(p.forwards, f.target)
+	p.mut.Unlock()
+
+	f.close()
+}
+
+// Close stops all the port-forwards of the pool, whether they were released or not
+func (p *PortForwardPool) Close() {
+	p.mut.Lock()
+	p.closed = true
+	forwards := make([]*pooledPortForward, 0, len(p.forwards))
+	for target, f := range p.forwards {
+		forwards = append(forwards, f)
+		delete(p.forwards, target)
+	}
+	p.mut.Unlock()
+
+	for _, f := range forwards {
+		f.close()
+	}
+}
+
+func (f *pooledPortForward) lock(ctx context.Context) error {
+	select {
+	case f.sem <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (f *pooledPortForward) unlock() {
+	<-f.sem
+}
+
+// close cancels the port-forward, which stops a reconnection in progress, and stops its tunnel
+func (f *pooledPortForward) close() {
+	f.cancel()
+	// a connection in progress holds the lock until it sees the cancellation
+	_ = f.lock(context.Background())
+	defer f.unlock()
+	f.stop()
+}
+
+// healthy returns true when the tunnel of the port-forward is running and accepts connections
+func (f *pooledPortForward) healthy() bool {
+	select {
+	case <-f.done:
+		return false
+	default:
+	}
+	conn, err := net.DialTimeout("tcp", net.JoinHostPort("localhost", strconv.Itoa(f.localPort)), time.Second)
+	if err != nil {
+		return false
+	}
+	conn.Close()
+	return true
+}
+
+// stop stops the current tunnel of the port-forward, if it is running
+func (f *pooledPortForward) stop() {
+	if f.stopChan == nil {
+		return
+	}
+	select {
+	case <-f.stopChan:
+	default:
+		close(f.stopChan)
+	}
+}
+
+// freeLocalPort returns a local port that is not in use
+func freeLocalPort() (int, error) {
+	listener, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to find a free local port")
+	}
+	defer listener.Close()
+	return listener.Addr().(*net.TCPAddr).Port, nil
+}
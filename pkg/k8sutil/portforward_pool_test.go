@@ -0,0 +1,201 @@
+package k8sutil
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeForwarder listens on the local port instead of forwarding it to a pod
+type fakeForwarder struct {
+	mut      sync.Mutex
+	calls    int
+	failures int
+	drop     chan struct{}
+}
+
+func (f *fakeForwarder) forward(target PortForwardTarget, localPort int, stopChan <-chan struct{}, readyChan chan struct{}) error {
+	f.mut.Lock()
+	f.calls++
+	fail := f.calls <= f.failures
+	drop := f.drop
+	f.mut.Unlock()
+	if fail {
+		return errors.New("unable to upgrade connection")
+	}
+
+	listener, err := net.Listen("tcp", net.JoinHostPort("localhost", strconv.Itoa(localPort)))
+	if err != nil {
+		return err
+	}
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			conn.Close()
+		}
+	}()
+	close(readyChan)
+
+	select {
+	case <-stopChan:
+		return nil
+	case <-drop:
+		return errors.New("lost connection to pod")
+	}
+}
+
+func (f *fakeForwarder) getCalls() int {
+	f.mut.Lock()
+	defer f.mut.Unlock()
+	return f.calls
+}
+
+func testPortForwardPool(forwarder *fakeForwarder) *PortForwardPool {
+	pool := newPortForwardPool(forwarder.forward)
+	pool.retryBackoff = time.Millisecond
+	pool.readyTimeout = time.Second
+	pool.reconnectInterval = 10 * time.Millisecond
+	return pool
+}
+
+func canDial(port int) bool {
+	conn, err := net.DialTimeout("tcp", net.JoinHostPort("localhost", strconv.Itoa(port)), time.Second)
+	if err != nil {
+		return false
+	}
+	conn.Close()
+	return true
+}
+
+func TestPortForwardPool_Shared(t *testing.T) {
+	forwarder := &fakeForwarder{drop: make(chan struct{})}
+	pool := testPortForwardPool(forwarder)
+	defer pool.Close()
+
+	prometheus := PortForwardTarget{Namespace: "monitoring", Pod: "prometheus-0", Port: 9090}
+	port, release, err := pool.Forward(context.Background(), prometheus)
+	require.NoError(t, err)
+	samePort, releaseSame, err := pool.Forward(context.Background(), prometheus)
+	require.NoError(t, err)
+	assert.Equal(t, port, samePort)
+	assert.Equal(t, 1, forwarder.getCalls())
+
+	otherPort, releaseOther, err := pool.Forward(context.Background(), PortForwardTarget{Namespace: "db", Pod: "postgres-0", Port: 5432})
+	require.NoError(t, err)
+	assert.NotEqual(t, port, otherPort)
+	assert.Equal(t, 2, forwarder.getCalls())
+	releaseOther()
+
+	// the port-forward stays open until its last user releases it
+	release()
+	release()
+	assert.True(t, canDial(port))
+	releaseSame()
+	assert.Eventually(t, func() bool { return !canDial(port) }, time.Second, 10*time.Millisecond)
+}
+
+func TestPortForwardPool_Reconnect(t *testing.T) {
+	forwarder := &fakeForwarder{drop: make(chan struct{})}
+	pool := testPortForwardPool(forwarder)
+	defer pool.Close()
+
+	port, release, err := pool.Forward(context.Background(), PortForwardTarget{Namespace: "default", Pod: "registry-0", Port: 5000})
+	require.NoError(t, err)
+	defer release()
+
+	// the tunnel drops, and is reconnected on the same local port
+	forwarder.mut.Lock()
+	close(forwarder.drop)
+	forwarder.drop = make(chan struct{})
+	forwarder.mut.Unlock()
+
+	assert.Eventually(t, func() bool { return forwarder.getCalls() == 2 && canDial(port) }, time.Second, 10*time.Millisecond)
+}
+
+func TestPortForwardPool_Retry(t *testing.T) {
+	target := PortForwardTarget{Namespace: "default", Pod: "mysql-0", Port: 3306}
+
+	forwarder := &fakeForwarder{failures: 2, drop: make(chan struct{})}
+	pool := testPortForwardPool(forwarder)
+	port, release, err := pool.Forward(context.Background(), target)
+	require.NoError(t, err)
+	assert.Equal(t, 3, forwarder.getCalls())
+	assert.True(t, canDial(port))
+	release()
+	pool.Close()
+
+	forwarder = &fakeForwarder{failures: 3, drop: make(chan struct{})}
+	pool = testPortForwardPool(forwarder)
+	defer pool.Close()
+	_, _, err = pool.Forward(context.Background(), target)
+	assert.ErrorContains(t, err, "unable to upgrade connection")
+	assert.Equal(t, 3, forwarder.getCalls())
+}
+
+func TestPortForwardPool_Canceled(t *testing.T) {
+	pool := newPortForwardPool(func(target PortForwardTarget, localPort int, stopChan <-chan struct{}, readyChan chan struct{}) error {
+		<-stopChan
+		return nil
+	})
+	defer pool.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, _, err := pool.Forward(ctx, PortForwardTarget{Namespace: "default", Pod: "slow-0", Port: 80})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	pool.Close()
+	_, _, err = pool.Forward(context.Background(), PortForwardTarget{Namespace: "default", Pod: "slow-0", Port: 80})
+	assert.Error(t, err)
+}
+
+func TestPortForwardPool_Independent(t *testing.T) {
+	slow := PortForwardTarget{Namespace: "default", Pod: "slow-0", Port: 80}
+	forwarder := &fakeForwarder{drop: make(chan struct{})}
+	pool := newPortForwardPool(func(target PortForwardTarget, localPort int, stopChan <-chan struct{}, readyChan chan struct{}) error {
+		if target == slow {
+			<-stopChan
+			return nil
+		}
+		return forwarder.forward(target, localPort, stopChan, readyChan)
+	})
+	defer pool.Close()
+
+	connecting := make(chan error)
+	go func() {
+		_, _, err := pool.Forward(context.Background(), slow)
+		connecting <- err
+	}()
+
+	// another pod is forwarded while the slow one connects
+	port, release, err := pool.Forward(context.Background(), PortForwardTarget{Namespace: "default", Pod: "fast-0", Port: 80})
+	require.NoError(t, err)
+	assert.True(t, canDial(port))
+	release()
+
+	// waiting for the slow pod to be connected by another collector stops with the context
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, _, err = pool.Forward(ctx, slow)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	// closing the pool stops the connection in progress
+	pool.Close()
+	select {
+	case err := <-connecting:
+		assert.Error(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("connection in progress was not stopped by Close")
+	}
+}
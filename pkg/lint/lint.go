@@ -0,0 +1,466 @@
+// Package lint checks troubleshoot specs for mistakes that decoding them does not catch: fields
+// the types do not have, analyzers reading from collectors the spec does not define, outcomes
+// that can never be reached, deprecated fields, and collectors of sensitive data with no
+// redactor.
+package lint
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/replicatedhq/troubleshoot/internal/util"
+	"github.com/replicatedhq/troubleshoot/pkg/client/troubleshootclientset/scheme"
+	"github.com/replicatedhq/troubleshoot/pkg/constants"
+	"github.com/replicatedhq/troubleshoot/pkg/docrewrite"
+	"sigs.k8s.io/yaml"
+)
+
+type Severity string
+
+const (
+	// SeverityError findings make the spec behave differently than it reads
+	SeverityError Severity = "error"
+	// SeverityWarning findings are likely mistakes
+	SeverityWarning Severity = "warning"
+)
+
+const (
+	RuleInvalidSpec        = "invalid-spec"
+	RuleUnknownField       = "unknown-field"
+	RuleMissingCollector   = "missing-collector"
+	RuleUnreachableOutcome = "unreachable-outcome"
+	RuleDeprecated         = "deprecated"
+	RuleMissingRedactor    = "missing-redactor"
+)
+
+// Source is a YAML document, or documents separated by "---", to lint
+type Source struct {
+	// Name identifies the source in the findings, e.g. a file name
+	Name    string
+	Content string
+}
+
+// Finding is a problem found in a document of a source
+type Finding struct {
+	Source string `json:"source,omitempty"`
+	// Document is the index of the document in the source, from 0
+	Document int    `json:"document"`
+	Kind     string `json:"kind,omitempty"`
+	Name     string `json:"name,omitempty"`
+	// Path is the path of the field in the document, e.g. spec.collectors[2].logs.limits, empty
+	// for findings about the whole document
+	Path     string   `json:"path,omitempty"`
+	Rule     string   `json:"rule"`
+	Severity Severity `json:"severity"`
+	Message  string   `json:"message"`
+}
+
+func (f Finding) String() string {
+	location := fmt.Sprintf("%s[%d]", f.Source, f.Document)
+	if f.Path != "" {
+		location = fmt.Sprintf("%s %s", location, f.Path)
+	}
+	return fmt.Sprintf("%s: %s: %s (%s)", location, f.Severity, f.Message, f.Rule)
+}
+
+// HasErrors returns true when any of the findings is an error
+func HasErrors(findings []Finding) bool {
+	for _, finding := range findings {
+		if finding.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// sensitiveCollectors are the collectors, of the cluster or of hosts, whose output can have
+// credentials the default redactors do not know of
+var sensitiveCollectors = map[string]bool{
+	"copy":         true,
+	"copyFromHost": true,
+	"exec":         true,
+	"http":         true,
+	"journald":     true,
+	"run":          true,
+	"runDaemonSet": true,
+	"runPod":       true,
+}
+
+// deprecatedCollectors are the collectors that are deprecated, with what to use instead
+var deprecatedCollectors = map[string]string{
+	"collectd": "use the metrics host collector, which samples the counters of the host itself",
+}
+
+// collectorLists and analyzerLists are the fields of the specs of all kinds with collectors and
+// analyzers
+var (
+	collectorLists = []string{"collectors", "hostCollectors", "remoteCollectors"}
+	analyzerLists  = []string{"analyzers", "hostAnalyzers"}
+)
+
+var decoder = scheme.Codecs.UniversalDeserializer()
+
+// document is a troubleshoot spec of a source
+type document struct {
+	source string
+	index  int
+	kind   string
+	name   string
+	// spec is the spec field of the document, converted to v1beta2, and nil when the document is
+	// not a troubleshoot kind or could not be decoded
+	spec     map[string]interface{}
+	findings []Finding
+}
+
+func (d *document) addFinding(path string, rule string, severity Severity, message string) {
+	d.findings = append(d.findings, d.finding(path, rule, severity, message))
+}
+
+func (d *document) finding(path string, rule string, severity Severity, message string) Finding {
+	return Finding{
+		Source:   d.source,
+		Document: d.index,
+		Kind:     d.kind,
+		Name:     d.name,
+		Path:     path,
+		Rule:     rule,
+		Severity: severity,
+		Message:  message,
+	}
+}
+
+// Lint checks the troubleshoot documents of the sources and returns the findings, in the order of
+// the sources and of their documents. Documents that are not troubleshoot kinds, e.g. Secrets
+// wrapping specs, are skipped.
+func Lint(sources ...Source) []Finding {
+	documents := []*document{}
+	for _, source := range sources {
+		for i, rawDoc := range util.SplitYAML(source.Content) {
+			documents = append(documents, lintDocument(source.Name, i, rawDoc))
+		}
+	}
+
+	hasRedactor := false
+	for _, doc := range documents {
+		if doc.spec != nil && doc.kind == "Redactor" {
+			hasRedactor = true
+		}
+	}
+
+	findings := []Finding{}
+	for _, doc := range documents {
+		findings = append(findings, doc.findings...)
+		if doc.spec == nil {
+			continue
+		}
+		findings = append(findings, lintCollectorReferences(doc)...)
+		findings = append(findings, lintOutcomes(doc)...)
+		findings = append(findings, lintDeprecatedCollectors(doc)...)
+		if !hasRedactor {
+			findings = append(findings, lintSensitiveCollectors(doc)...)
+		}
+	}
+	return findings
+}
+
+// lintDocument decodes a document and checks its fields against its type. The spec of the
+// returned document is nil when it is not a troubleshoot kind or could not be decoded.
+func lintDocument(source string, index int, rawDoc string) *document {
+	doc := &document{source: source, index: index}
+	if strings.TrimSpace(rawDoc) == "" {
+		return doc
+	}
+
+	head := struct {
+		APIVersion string `json:"apiVersion"`
+		Kind       string `json:"kind"`
+		Metadata   struct {
+			Name string `json:"name"`
+		} `json:"metadata"`
+	}{}
+	if err := yaml.Unmarshal([]byte(rawDoc), &head); err != nil {
+		doc.addFinding("", RuleInvalidSpec, SeverityError, fmt.Sprintf("failed to parse yaml: %v", err))
+		return doc
+	}
+	doc.kind, doc.name = head.Kind, head.Metadata.Name
+	if head.APIVersion != constants.Troubleshootv1beta2Kind && head.APIVersion != constants.Troubleshootv1beta1Kind {
+		return doc
+	}
+
+	if head.APIVersion == constants.Troubleshootv1beta1Kind {
+		doc.addFinding("apiVersion", RuleDeprecated, SeverityWarning,
+			fmt.Sprintf("%s is deprecated, use %s", constants.Troubleshootv1beta1Kind, constants.Troubleshootv1beta2Kind))
+	}
+
+	converted, err := docrewrite.ConvertToV1Beta2([]byte(rawDoc))
+	if err != nil {
+		doc.addFinding("", RuleInvalidSpec, SeverityError, fmt.Sprintf("failed to convert to %s: %v", constants.Troubleshootv1beta2Kind, err))
+		return doc
+	}
+	obj, _, err := decoder.Decode(converted, nil, nil)
+	if err != nil {
+		doc.addFinding("", RuleInvalidSpec, SeverityError, fmt.Sprintf("failed to decode: %v", err))
+		return doc
+	}
+
+	raw := map[string]interface{}{}
+	if err := yaml.Unmarshal(converted, &raw); err != nil {
+		doc.addFinding("", RuleInvalidSpec, SeverityError, fmt.Sprintf("failed to parse yaml: %v", err))
+		return doc
+	}
+	for _, path := range unknownFields(reflect.TypeOf(obj), raw, "") {
+		doc.addFinding(path, RuleUnknownField, SeverityError, fmt.Sprintf("unknown field %q, it is ignored", lastPathElement(path)))
+	}
+
+	doc.spec, _ = raw["spec"].(map[string]interface{})
+	if doc.spec == nil {
+		doc.spec = map[string]interface{}{}
+	}
+	return doc
+}
+
+var jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+// unknownFields returns the paths of the keys of value that are not fields of the type, as the
+// json decoder would ignore them. Values of types that decode themselves are not checked.
+func unknownFields(t reflect.Type, value interface{}, path string) []string {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Implements(jsonUnmarshalerType) || reflect.PointerTo(t).Implements(jsonUnmarshalerType) {
+		return nil
+	}
+
+	unknown := []string{}
+	switch t.Kind() {
+	case reflect.Struct:
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		fields := jsonFields(t)
+		for _, key := range sortedKeys(object) {
+			fieldType, ok := fields[key]
+			if !ok {
+				unknown = append(unknown, joinPath(path, key))
+				continue
+			}
+			unknown = append(unknown, unknownFields(fieldType, object[key], joinPath(path, key))...)
+		}
+	case reflect.Map:
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		for _, key := range sortedKeys(object) {
+			unknown = append(unknown, unknownFields(t.Elem(), object[key], joinPath(path, key))...)
+		}
+	case reflect.Slice, reflect.Array:
+		items, ok := value.([]interface{})
+		if !ok {
+			return nil
+		}
+		for i, item := range items {
+			unknown = append(unknown, unknownFields(t.Elem(), item, fmt.Sprintf("%s[%d]", path, i))...)
+		}
+	}
+	return unknown
+}
+
+// jsonFields returns the types of the fields of a struct by json name, with the fields of the
+// structs it inlines
+func jsonFields(t reflect.Type) map[string]reflect.Type {
+	fields := map[string]reflect.Type{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() && !field.Anonymous {
+			continue
+		}
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name := strings.Split(tag, ",")[0]
+
+		fieldType := field.Type
+		for fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+		if field.Anonymous && name == "" && fieldType.Kind() == reflect.Struct {
+			for inlinedName, inlinedType := range jsonFields(fieldType) {
+				fields[inlinedName] = inlinedType
+			}
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fields[name] = field.Type
+	}
+	return fields
+}
+
+// lintCollectorReferences checks that the collectors analyzers read from are in the spec. Specs of
+// the Analyzer kind are analyzed with collectors of other specs and are not checked.
+func lintCollectorReferences(doc *document) []Finding {
+	if doc.kind != "SupportBundle" && doc.kind != "Preflight" && doc.kind != "HostPreflight" {
+		return nil
+	}
+
+	names := map[string]bool{}
+	for _, list := range collectorLists {
+		forEachEntry(doc.spec, list, func(path string, key string, entry map[string]interface{}) {
+			for _, field := range []string{"collectorName", "name"} {
+				if name, ok := entry[field].(string); ok && name != "" {
+					names[name] = true
+				}
+			}
+		})
+	}
+
+	findings := []Finding{}
+	for _, list := range analyzerLists {
+		forEachEntry(doc.spec, list, func(path string, key string, entry map[string]interface{}) {
+			name, ok := entry["collectorName"].(string)
+			if !ok || name == "" || names[name] {
+				return
+			}
+			findings = append(findings, doc.finding(joinPath(path, "collectorName"), RuleMissingCollector, SeverityWarning,
+				fmt.Sprintf("the %s analyzer reads from collector %q, which the spec does not have", key, name)))
+		})
+	}
+	return findings
+}
+
+// lintOutcomes checks that every outcome of the analyzers can be reached. The first outcome whose
+// when clause matches is the result, so outcomes after one without a when clause, or after one
+// with the same when clause, are never used.
+func lintOutcomes(doc *document) []Finding {
+	findings := []Finding{}
+	for _, list := range analyzerLists {
+		forEachEntry(doc.spec, list, func(path string, key string, entry map[string]interface{}) {
+			outcomes, _ := entry["outcomes"].([]interface{})
+			seen := map[string]int{}
+			catchAll := -1
+			for i, outcome := range outcomes {
+				outcomePath := fmt.Sprintf("%s[%d]", joinPath(path, "outcomes"), i)
+				when, ok := outcomeWhen(outcome)
+				if !ok {
+					continue
+				}
+				if catchAll >= 0 {
+					findings = append(findings, doc.finding(outcomePath, RuleUnreachableOutcome, SeverityWarning,
+						fmt.Sprintf("the outcome is never used, outcome %d has no when clause and always matches first", catchAll)))
+					continue
+				}
+				if when == "" {
+					catchAll = i
+					continue
+				}
+				if first, ok := seen[when]; ok {
+					findings = append(findings, doc.finding(outcomePath, RuleUnreachableOutcome, SeverityWarning,
+						fmt.Sprintf("the outcome is never used, outcome %d has the same when clause %q and matches first", first, when)))
+					continue
+				}
+				seen[when] = i
+			}
+		})
+	}
+	return findings
+}
+
+// outcomeWhen returns the normalized when clause of an outcome, and false when the outcome is
+// neither a fail, a warn nor a pass
+func outcomeWhen(outcome interface{}) (string, bool) {
+	object, ok := outcome.(map[string]interface{})
+	if !ok {
+		return "", false
+	}
+	for _, level := range []string{"fail", "warn", "pass"} {
+		single, ok := object[level].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		when, ok := single["when"]
+		if !ok || when == nil {
+			return "", true
+		}
+		return strings.Join(strings.Fields(fmt.Sprint(when)), " "), true
+	}
+	return "", false
+}
+
+func lintDeprecatedCollectors(doc *document) []Finding {
+	findings := []Finding{}
+	for _, list := range collectorLists {
+		forEachEntry(doc.spec, list, func(path string, key string, entry map[string]interface{}) {
+			if instead, ok := deprecatedCollectors[key]; ok {
+				findings = append(findings, doc.finding(path, RuleDeprecated, SeverityWarning,
+					fmt.Sprintf("the %s collector is deprecated, %s", key, instead)))
+			}
+		})
+	}
+	return findings
+}
+
+// lintSensitiveCollectors reports the collectors of data that may have credentials, when none of
+// the sources has a redactor
+func lintSensitiveCollectors(doc *document) []Finding {
+	findings := []Finding{}
+	for _, list := range collectorLists {
+		forEachEntry(doc.spec, list, func(path string, key string, entry map[string]interface{}) {
+			sensitive := sensitiveCollectors[key]
+			if key == "secret" {
+				sensitive, _ = entry["includeValue"].(bool)
+			}
+			if sensitive {
+				findings = append(findings, doc.finding(path, RuleMissingRedactor, SeverityWarning,
+					fmt.Sprintf("the %s collector may collect credentials the default redactors do not know of, and there is no redactor", key)))
+			}
+		})
+	}
+	return findings
+}
+
+// forEachEntry calls fn with the path, the type and the fields of each entry of a list of
+// collectors or analyzers, e.g. spec.collectors[0].logs, logs and the fields of the logs
+// collector
+func forEachEntry(spec map[string]interface{}, list string, fn func(path string, key string, entry map[string]interface{})) {
+	items, _ := spec[list].([]interface{})
+	for i, item := range items {
+		object, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for _, key := range sortedKeys(object) {
+			entry, ok := object[key].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			fn(fmt.Sprintf("spec.%s[%d].%s", list, i, key), key, entry)
+		}
+	}
+}
+
+func sortedKeys(object map[string]interface{}) []string {
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func joinPath(path string, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func lastPathElement(path string) string {
+	return path[strings.LastIndex(path, ".")+1:]
+}
@@ -0,0 +1,144 @@
+package lint
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLint(t *testing.T) {
+	spec := `apiVersion: troubleshoot.sh/v1beta2
+kind: SupportBundle
+metadata:
+  name: app
+spec:
+  collectors:
+    - logs:
+        name: app/logs
+        selector:
+          - app=api
+        limits:
+          maxLine: 1000
+    - exec:
+        collectorName: version
+        name: version
+        command: ["app", "--version"]
+    - collectd:
+        namespace: default
+        image: collectd
+        hostPath: /var/lib/collectd
+  analyzers:
+    - textAnalyze:
+        collectorName: versoin
+        fileName: version/app.log
+        regex: v1
+        outcomes:
+          - pass:
+              message: found
+          - fail:
+              when: "false"
+              message: not found
+    - deploymentStatus:
+        name: api
+        namespace: default
+        outcomes:
+          - fail:
+              when: "< 1"
+              message: down
+          - warn:
+              when: "<  1"
+              message: down
+          - pass:
+              message: up
+`
+	findings := Lint(Source{Name: "spec.yaml", Content: spec})
+
+	assert.Equal(t, []Finding{
+		{Source: "spec.yaml", Kind: "SupportBundle", Name: "app", Path: "spec.collectors[0].logs.limits.maxLine", Rule: RuleUnknownField, Severity: SeverityError, Message: `unknown field "maxLine", it is ignored`},
+		{Source: "spec.yaml", Kind: "SupportBundle", Name: "app", Path: "spec.analyzers[0].textAnalyze.collectorName", Rule: RuleMissingCollector, Severity: SeverityWarning, Message: `the textAnalyze analyzer reads from collector "versoin", which the spec does not have`},
+		{Source: "spec.yaml", Kind: "SupportBundle", Name: "app", Path: "spec.analyzers[0].textAnalyze.outcomes[1]", Rule: RuleUnreachableOutcome, Severity: SeverityWarning, Message: "the outcome is never used, outcome 0 has no when clause and always matches first"},
+		{Source: "spec.yaml", Kind: "SupportBundle", Name: "app", Path: "spec.analyzers[1].deploymentStatus.outcomes[1]", Rule: RuleUnreachableOutcome, Severity: SeverityWarning, Message: `the outcome is never used, outcome 0 has the same when clause "< 1" and matches first`},
+		{Source: "spec.yaml", Kind: "SupportBundle", Name: "app", Path: "spec.collectors[2].collectd", Rule: RuleDeprecated, Severity: SeverityWarning, Message: "the collectd collector is deprecated, use the metrics host collector, which samples the counters of the host itself"},
+		{Source: "spec.yaml", Kind: "SupportBundle", Name: "app", Path: "spec.collectors[1].exec", Rule: RuleMissingRedactor, Severity: SeverityWarning, Message: "the exec collector may collect credentials the default redactors do not know of, and there is no redactor"},
+	}, findings)
+	assert.True(t, HasErrors(findings))
+}
+
+func TestLint_redactorsAndOtherKinds(t *testing.T) {
+	spec := `apiVersion: troubleshoot.sh/v1beta2
+kind: SupportBundle
+metadata:
+  name: app
+spec:
+  collectors:
+    - secret:
+        name: app
+        namespace: default
+        includeValue: true
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: not-a-spec
+data:
+  unknown: field
+`
+	findings := Lint(Source{Name: "spec.yaml", Content: spec})
+	require.Len(t, findings, 1)
+	assert.Equal(t, RuleMissingRedactor, findings[0].Rule)
+	assert.Equal(t, "spec.collectors[0].secret", findings[0].Path)
+	assert.False(t, HasErrors(findings))
+
+	redactor := `apiVersion: troubleshoot.sh/v1beta2
+kind: Redactor
+metadata:
+  name: app
+spec:
+  redactors:
+    - name: token
+      removals:
+        values:
+          - abc
+`
+	findings = Lint(Source{Name: "spec.yaml", Content: spec}, Source{Name: "redactor.yaml", Content: redactor})
+	assert.Empty(t, findings)
+}
+
+func TestLint_invalidAndDeprecatedDocuments(t *testing.T) {
+	spec := `apiVersion: troubleshoot.replicated.com/v1beta1
+kind: Preflight
+metadata:
+  name: old
+spec:
+  analyzers:
+    - clusterVersion:
+        outcomes:
+          - pass:
+              message: ok
+---
+apiVersion: troubleshoot.sh/v1beta2
+kind: Preflight
+spec:
+  collectors: not a list
+`
+	findings := Lint(Source{Name: "spec.yaml", Content: spec})
+	require.Len(t, findings, 2)
+	assert.Equal(t, Finding{
+		Source:   "spec.yaml",
+		Kind:     "Preflight",
+		Name:     "old",
+		Path:     "apiVersion",
+		Rule:     RuleDeprecated,
+		Severity: SeverityWarning,
+		Message:  "troubleshoot.replicated.com/v1beta1 is deprecated, use troubleshoot.sh/v1beta2",
+	}, findings[0])
+	assert.Equal(t, 1, findings[1].Document)
+	assert.Equal(t, RuleInvalidSpec, findings[1].Rule)
+	assert.Equal(t, SeverityError, findings[1].Severity)
+}
+
+func TestFinding_String(t *testing.T) {
+	finding := Finding{Source: "spec.yaml", Document: 1, Path: "spec.collectors[0].logs.limits.maxLine", Rule: RuleUnknownField, Severity: SeverityError, Message: `unknown field "maxLine", it is ignored`}
+	assert.Equal(t, `spec.yaml[1] spec.collectors[0].logs.limits.maxLine: error: unknown field "maxLine", it is ignored (unknown-field)`, finding.String())
+}
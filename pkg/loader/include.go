@@ -0,0 +1,203 @@
+package loader
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/constants"
+	"github.com/replicatedhq/troubleshoot/pkg/types"
+)
+
+// IncludeFetcher returns the raw yaml documents an include of a spec references
+type IncludeFetcher func(ctx context.Context, include *troubleshootv1beta2.SpecInclude) (string, error)
+
+// includeRef returns a string identifying the spec an include references, used to detect cycles
+func includeRef(include *troubleshootv1beta2.SpecInclude) (string, error) {
+	if include == nil {
+		return "", errors.New("include is empty")
+	}
+	if include.URI != "" && include.ConfigMap != nil {
+		return "", errors.New("include must have only one of uri or configMap")
+	}
+	if include.URI != "" {
+		return include.URI, nil
+	}
+	if cm := include.ConfigMap; cm != nil {
+		if cm.Name == "" || cm.Namespace == "" {
+			return "", errors.New("include configMap must have a name and a namespace")
+		}
+		ref := fmt.Sprintf("configmap/%s/%s", cm.Namespace, cm.Name)
+		if cm.Key != "" {
+			ref = fmt.Sprintf("%s/%s", ref, cm.Key)
+		}
+		return ref, nil
+	}
+	return "", errors.New("include must have one of uri or configMap")
+}
+
+// resolveIncludes replaces the support bundle and preflight specs that include other specs with
+// their merge with the included specs
+func (l *specLoader) resolveIncludes(ctx context.Context, kinds *TroubleshootKinds) error {
+	for i := range kinds.SupportBundlesV1Beta2 {
+		spec := &kinds.SupportBundlesV1Beta2[i]
+		merged, err := l.resolveSupportBundleIncludes(ctx, spec.Spec, []string{spec.Name})
+		if err != nil {
+			return types.NewExitCodeError(constants.EXIT_CODE_SPEC_ISSUES, errors.Wrapf(err, "failed to include specs in support bundle %q", spec.Name))
+		}
+		spec.Spec = merged
+	}
+	for i := range kinds.PreflightsV1Beta2 {
+		spec := &kinds.PreflightsV1Beta2[i]
+		merged, err := l.resolvePreflightIncludes(ctx, spec.Spec, []string{spec.Name})
+		if err != nil {
+			return types.NewExitCodeError(constants.EXIT_CODE_SPEC_ISSUES, errors.Wrapf(err, "failed to include specs in preflight %q", spec.Name))
+		}
+		spec.Spec = merged
+	}
+	return nil
+}
+
+// fetchInclude loads the specs an include references. chain is the references of the specs
+// that led to the include, starting with the name of the spec being loaded.
+func (l *specLoader) fetchInclude(ctx context.Context, include *troubleshootv1beta2.SpecInclude, chain []string) (*TroubleshootKinds, []string, error) {
+	ref, err := includeRef(include)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, seen := range chain[1:] {
+		if seen == ref {
+			return nil, nil, errors.Errorf("include cycle: %s -> %s", strings.Join(chain, " -> "), ref)
+		}
+	}
+
+	raw, err := l.fetchIncludeFn(ctx, include)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "failed to fetch %s", ref)
+	}
+	kinds, err := l.loadFromStrings(raw)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "failed to load %s", ref)
+	}
+	return kinds, append(append([]string{}, chain...), ref), nil
+}
+
+func (l *specLoader) resolveSupportBundleIncludes(ctx context.Context, spec troubleshootv1beta2.SupportBundleSpec, chain []string) (troubleshootv1beta2.SupportBundleSpec, error) {
+	if len(spec.Include) == 0 {
+		return spec, nil
+	}
+
+	base := troubleshootv1beta2.SupportBundleSpec{}
+	for _, include := range spec.Include {
+		kinds, includeChain, err := l.fetchInclude(ctx, include, chain)
+		if err != nil {
+			return spec, err
+		}
+		if len(kinds.SupportBundlesV1Beta2) == 0 {
+			return spec, errors.Errorf("%s has no support bundle spec", includeChain[len(includeChain)-1])
+		}
+		for _, included := range kinds.SupportBundlesV1Beta2 {
+			resolved, err := l.resolveSupportBundleIncludes(ctx, included.Spec, includeChain)
+			if err != nil {
+				return spec, err
+			}
+			base = mergeSupportBundleSpecs(base, resolved)
+		}
+	}
+
+	spec.Include = nil
+	return mergeSupportBundleSpecs(base, spec), nil
+}
+
+func (l *specLoader) resolvePreflightIncludes(ctx context.Context, spec troubleshootv1beta2.PreflightSpec, chain []string) (troubleshootv1beta2.PreflightSpec, error) {
+	if len(spec.Include) == 0 {
+		return spec, nil
+	}
+
+	base := troubleshootv1beta2.PreflightSpec{}
+	for _, include := range spec.Include {
+		kinds, includeChain, err := l.fetchInclude(ctx, include, chain)
+		if err != nil {
+			return spec, err
+		}
+		if len(kinds.PreflightsV1Beta2) == 0 {
+			return spec, errors.Errorf("%s has no preflight spec", includeChain[len(includeChain)-1])
+		}
+		for _, included := range kinds.PreflightsV1Beta2 {
+			resolved, err := l.resolvePreflightIncludes(ctx, included.Spec, includeChain)
+			if err != nil {
+				return spec, err
+			}
+			base = mergePreflightSpecs(base, resolved)
+		}
+	}
+
+	spec.Include = nil
+	return mergePreflightSpecs(base, spec), nil
+}
+
+// mergeSupportBundleSpecs appends the lists of the overlay to those of the base. Settings of the
+// overlay replace those of the base when they are set, and the uri is that of the overlay.
+func mergeSupportBundleSpecs(base, overlay troubleshootv1beta2.SupportBundleSpec) troubleshootv1beta2.SupportBundleSpec {
+	merged := base
+	merged.AfterCollection = append(append([]*troubleshootv1beta2.AfterCollection{}, base.AfterCollection...), overlay.AfterCollection...)
+	merged.Collectors = append(append([]*troubleshootv1beta2.Collect{}, base.Collectors...), overlay.Collectors...)
+	merged.HostCollectors = append(append([]*troubleshootv1beta2.HostCollect{}, base.HostCollectors...), overlay.HostCollectors...)
+	merged.Analyzers = append(append([]*troubleshootv1beta2.Analyze{}, base.Analyzers...), overlay.Analyzers...)
+	merged.HostAnalyzers = append(append([]*troubleshootv1beta2.HostAnalyze{}, base.HostAnalyzers...), overlay.HostAnalyzers...)
+	merged.Notifications = append(append([]*troubleshootv1beta2.Notification{}, base.Notifications...), overlay.Notifications...)
+	merged.PostCollection = append(append([]*troubleshootv1beta2.PostCollection{}, base.PostCollection...), overlay.PostCollection...)
+
+	// the uri of an included spec would replace the spec that includes it
+	merged.Uri = overlay.Uri
+	if overlay.RunHostCollectorsInPod {
+		merged.RunHostCollectorsInPod = true
+	}
+	if overlay.Scope != "" {
+		merged.Scope = overlay.Scope
+	}
+	if len(overlay.Namespaces) > 0 {
+		merged.Namespaces = overlay.Namespaces
+	}
+	if overlay.MaxSize != "" {
+		merged.MaxSize = overlay.MaxSize
+	}
+	if overlay.Profile != "" {
+		merged.Profile = overlay.Profile
+	}
+	merged.Include = nil
+	return merged
+}
+
+// mergePreflightSpecs appends the lists of the overlay to those of the base. Settings of the
+// overlay replace those of the base when they are set, and the uri is that of the overlay.
+func mergePreflightSpecs(base, overlay troubleshootv1beta2.PreflightSpec) troubleshootv1beta2.PreflightSpec {
+	merged := base
+	merged.Collectors = append(append([]*troubleshootv1beta2.Collect{}, base.Collectors...), overlay.Collectors...)
+	merged.RemoteCollectors = append(append([]*troubleshootv1beta2.RemoteCollect{}, base.RemoteCollectors...), overlay.RemoteCollectors...)
+	merged.Analyzers = append(append([]*troubleshootv1beta2.Analyze{}, base.Analyzers...), overlay.Analyzers...)
+	merged.Notifications = append(append([]*troubleshootv1beta2.Notification{}, base.Notifications...), overlay.Notifications...)
+
+	if overlay.UploadResultsTo != "" {
+		merged.UploadResultsTo = overlay.UploadResultsTo
+	}
+	merged.Uri = overlay.Uri
+	merged.Include = nil
+	return merged
+}
+
+func hasIncludes(kinds *TroubleshootKinds) bool {
+	for _, spec := range kinds.SupportBundlesV1Beta2 {
+		if len(spec.Spec.Include) > 0 {
+			return true
+		}
+	}
+	for _, spec := range kinds.PreflightsV1Beta2 {
+		if len(spec.Spec.Include) > 0 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,181 @@
+package loader
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func fakeIncludeFetcher(specs map[string]string) IncludeFetcher {
+	return func(ctx context.Context, include *troubleshootv1beta2.SpecInclude) (string, error) {
+		ref, err := includeRef(include)
+		if err != nil {
+			return "", err
+		}
+		spec, ok := specs[ref]
+		if !ok {
+			return "", errors.Errorf("%s not found", ref)
+		}
+		return spec, nil
+	}
+}
+
+func TestLoadSpecs_includes(t *testing.T) {
+	specs := map[string]string{
+		"https://example.com/base.yaml": `apiVersion: troubleshoot.sh/v1beta2
+kind: SupportBundle
+metadata:
+  name: base
+spec:
+  uri: https://example.com/base.yaml
+  profile: metadataOnly
+  collectors:
+    - clusterInfo: {}
+  include:
+    - configMap:
+        namespace: default
+        name: common
+        key: support-bundle-spec
+---
+apiVersion: troubleshoot.sh/v1beta2
+kind: Redactor
+metadata:
+  name: ignored
+`,
+		"configmap/default/common/support-bundle-spec": `apiVersion: troubleshoot.sh/v1beta2
+kind: SupportBundle
+metadata:
+  name: common
+spec:
+  collectors:
+    - clusterResources: {}
+  analyzers:
+    - clusterVersion: {}
+`,
+	}
+
+	kinds, err := LoadSpecs(context.Background(), LoadOptions{
+		RawSpec: `apiVersion: troubleshoot.sh/v1beta2
+kind: SupportBundle
+metadata:
+  name: product
+spec:
+  include:
+    - uri: https://example.com/base.yaml
+  collectors:
+    - logs:
+        name: product
+`,
+		FetchInclude: fakeIncludeFetcher(specs),
+		Strict:       true,
+	})
+	require.NoError(t, err)
+	require.Len(t, kinds.SupportBundlesV1Beta2, 1)
+	assert.Empty(t, kinds.RedactorsV1Beta2)
+
+	spec := kinds.SupportBundlesV1Beta2[0].Spec
+	require.Len(t, spec.Collectors, 3)
+	assert.NotNil(t, spec.Collectors[0].ClusterResources)
+	assert.NotNil(t, spec.Collectors[1].ClusterInfo)
+	assert.Equal(t, "product", spec.Collectors[2].Logs.Name)
+	require.Len(t, spec.Analyzers, 1)
+	assert.Equal(t, troubleshootv1beta2.CollectionProfileMetadataOnly, spec.Profile)
+	assert.Empty(t, spec.Uri)
+	assert.Empty(t, spec.Include)
+}
+
+func TestLoadSpecs_includeErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		specs   map[string]string
+		wantErr string
+	}{
+		{
+			name: "cycle",
+			specs: map[string]string{
+				"https://example.com/a.yaml": `apiVersion: troubleshoot.sh/v1beta2
+kind: Preflight
+metadata:
+  name: a
+spec:
+  include:
+    - uri: https://example.com/b.yaml
+`,
+				"https://example.com/b.yaml": `apiVersion: troubleshoot.sh/v1beta2
+kind: Preflight
+metadata:
+  name: b
+spec:
+  include:
+    - uri: https://example.com/a.yaml
+`,
+			},
+			wantErr: "include cycle: product -> https://example.com/a.yaml -> https://example.com/b.yaml -> https://example.com/a.yaml",
+		},
+		{
+			name: "other kind",
+			specs: map[string]string{
+				"https://example.com/a.yaml": `apiVersion: troubleshoot.sh/v1beta2
+kind: SupportBundle
+metadata:
+  name: a
+`,
+			},
+			wantErr: "https://example.com/a.yaml has no preflight spec",
+		},
+		{
+			name:    "not found",
+			specs:   map[string]string{},
+			wantErr: "failed to fetch https://example.com/a.yaml: https://example.com/a.yaml not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadSpecs(context.Background(), LoadOptions{
+				RawSpec: `apiVersion: troubleshoot.sh/v1beta2
+kind: Preflight
+metadata:
+  name: product
+spec:
+  include:
+    - uri: https://example.com/a.yaml
+`,
+				FetchInclude: fakeIncludeFetcher(tt.specs),
+			})
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestLoadSpecs_includesNotFetched(t *testing.T) {
+	kinds, err := LoadSpecs(context.Background(), LoadOptions{
+		RawSpec: `apiVersion: troubleshoot.sh/v1beta2
+kind: Preflight
+metadata:
+  name: product
+spec:
+  include:
+    - uri: https://example.com/a.yaml
+`,
+	})
+	require.NoError(t, err)
+	require.Len(t, kinds.PreflightsV1Beta2, 1)
+	assert.Len(t, kinds.PreflightsV1Beta2[0].Spec.Include, 1)
+}
+
+func Test_includeRef(t *testing.T) {
+	ref, err := includeRef(&troubleshootv1beta2.SpecInclude{ConfigMap: &troubleshootv1beta2.IncludeConfigMap{Namespace: "default", Name: "base"}})
+	require.NoError(t, err)
+	assert.Equal(t, "configmap/default/base", ref)
+
+	_, err = includeRef(&troubleshootv1beta2.SpecInclude{URI: "https://example.com/a.yaml", ConfigMap: &troubleshootv1beta2.IncludeConfigMap{Namespace: "default", Name: "base"}})
+	assert.Error(t, err)
+	_, err = includeRef(&troubleshootv1beta2.SpecInclude{})
+	assert.Error(t, err)
+}
@@ -40,6 +40,10 @@ type LoadOptions struct {
 	// If true, the loader will return an error if any of the specs are not valid
 	// else the invalid specs will be ignored
 	Strict bool
+
+	// FetchInclude fetches the specs that support bundle and preflight specs include. When nil,
+	// includes are not resolved.
+	FetchInclude IncludeFetcher
 }
 
 // TODO: Additional requirements needed in this package
@@ -62,13 +66,31 @@ type LoadOptions struct {
 //
 // If the `Strict` flag is set to true, this function will return an error if any of
 // the documents are not valid, else the invalid documents will be ignored.
+//
+// Support bundle and preflight specs that include other specs are merged with them, using
+// `FetchInclude` to fetch the included specs. Include cycles are an error.
 func LoadSpecs(ctx context.Context, opt LoadOptions) (*TroubleshootKinds, error) {
 	opt.RawSpecs = append(opt.RawSpecs, opt.RawSpec)
 	l := specLoader{
-		strict: opt.Strict,
+		strict:         opt.Strict,
+		fetchIncludeFn: opt.FetchInclude,
+	}
+
+	kinds, err := l.loadFromStrings(opt.RawSpecs...)
+	if err != nil {
+		return nil, err
 	}
 
-	return l.loadFromStrings(opt.RawSpecs...)
+	if l.fetchIncludeFn == nil {
+		if hasIncludes(kinds) {
+			klog.Warningf("Specs include other specs, but includes are not loaded")
+		}
+		return kinds, nil
+	}
+	if err := l.resolveIncludes(ctx, kinds); err != nil {
+		return nil, err
+	}
+	return kinds, nil
 }
 
 type TroubleshootKinds struct {
@@ -140,7 +162,8 @@ func NewTroubleshootKinds() *TroubleshootKinds {
 }
 
 type specLoader struct {
-	strict bool
+	strict         bool
+	fetchIncludeFn IncludeFetcher
 }
 
 // loadFromStrings accepts a list of strings (exploded) which should be yaml documents
@@ -0,0 +1,164 @@
+package metrics
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"k8s.io/klog/v2"
+)
+
+const (
+	namespace = "troubleshoot"
+
+	// ResultSuccess is the result of a collection that saved a bundle without errors
+	ResultSuccess = "success"
+	// ResultPartial is the result of a collection that saved a bundle, but some of its collectors failed
+	ResultPartial = "partial"
+	// ResultFailure is the result of a collection that did not save a bundle
+	ResultFailure = "failure"
+)
+
+// Registry holds the metrics of collections. They are recorded whether or not they are served,
+// so that processes that run collections repeatedly can expose them with Handler.
+var Registry = prometheus.NewRegistry()
+
+var (
+	collectorRuns = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "collector_runs_total",
+		Help:      "Number of times each kind of collector ran.",
+	}, []string{"collector"})
+	collectorFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "collector_failures_total",
+		Help:      "Number of times each kind of collector returned an error.",
+	}, []string{"collector"})
+	collectorDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "collector_duration_seconds",
+		Help:      "Time each kind of collector took to run.",
+		Buckets:   prometheus.ExponentialBuckets(0.1, 2, 12),
+	}, []string{"collector"})
+	collectedBytes = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "collected_bytes_total",
+		Help:      "Size of the files each kind of collector saved, before redaction.",
+	}, []string{"collector"})
+	redactions = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "redactions_total",
+		Help:      "Number of values each redactor removed.",
+	}, []string{"redactor"})
+	collections = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "collections_total",
+		Help:      "Number of support bundle collections by result, one of success, partial or failure.",
+	}, []string{"result"})
+	collectionDuration = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "collection_duration_seconds",
+		Help:      "Time support bundle collections took, from the first collector to the archive.",
+		Buckets:   prometheus.ExponentialBuckets(1, 2, 12),
+	})
+	lastCollection = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "last_collection_timestamp_seconds",
+		Help:      "Unix time the last support bundle collection with each result completed.",
+	}, []string{"result"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectorRuns,
+		collectorFailures,
+		collectorDuration,
+		collectedBytes,
+		redactions,
+		collections,
+		collectionDuration,
+		lastCollection,
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+}
+
+// ObserveCollector records a run of a collector of kind collector, e.g. "logs", that saved
+// files of size bytes
+func ObserveCollector(collector string, duration time.Duration, bytes int64, err error) {
+	collectorRuns.WithLabelValues(collector).Inc()
+	collectorDuration.WithLabelValues(collector).Observe(duration.Seconds())
+	collectedBytes.WithLabelValues(collector).Add(float64(bytes))
+	if err != nil {
+		collectorFailures.WithLabelValues(collector).Inc()
+	}
+}
+
+// ObserveRedaction records a value removed by a redactor
+func ObserveRedaction(redactor string) {
+	redactions.WithLabelValues(redactor).Inc()
+}
+
+// ObserveCollection records a support bundle collection. collected is whether a bundle was saved,
+// in which case err holds the errors of the collectors that failed.
+func ObserveCollection(duration time.Duration, collected bool, err error) {
+	result := ResultSuccess
+	if !collected {
+		result = ResultFailure
+	} else if err != nil {
+		result = ResultPartial
+	}
+
+	collections.WithLabelValues(result).Inc()
+	collectionDuration.Observe(duration.Seconds())
+	lastCollection.WithLabelValues(result).SetToCurrentTime()
+}
+
+// Handler serves the metrics of the Registry in the Prometheus exposition format
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{})
+}
+
+// Start serves the metrics on /metrics of address until the context is cancelled. An empty
+// address or "0" disables the endpoint. The address is bound before Start returns, so that a
+// port in use is reported to the caller.
+func Start(ctx context.Context, address string) error {
+	if address == "" || address == "0" {
+		return nil
+	}
+
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return errors.Wrapf(err, "failed to listen on metrics address %s", address)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("GET /metrics", Handler())
+	server := &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			klog.Errorf("Failed to shut down metrics server: %v", err)
+		}
+	}()
+
+	go func() {
+		klog.Infof("Serving metrics on %s", listener.Addr())
+		if err := server.Serve(listener); err != nil && err != http.ErrServerClosed {
+			klog.Errorf("Failed to serve metrics: %v", err)
+		}
+	}()
+
+	return nil
+}
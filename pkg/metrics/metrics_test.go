@@ -0,0 +1,69 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestObserveCollector(t *testing.T) {
+	ObserveCollector("logs", 2*time.Second, 1024, nil)
+	ObserveCollector("logs", time.Second, 512, errors.New("pod not found"))
+
+	assert.Equal(t, 2.0, testutil.ToFloat64(collectorRuns.WithLabelValues("logs")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(collectorFailures.WithLabelValues("logs")))
+	assert.Equal(t, 1536.0, testutil.ToFloat64(collectedBytes.WithLabelValues("logs")))
+	assert.Equal(t, 1, testutil.CollectAndCount(collectorDuration, "troubleshoot_collector_duration_seconds"))
+}
+
+func TestObserveCollection(t *testing.T) {
+	ObserveCollection(time.Minute, true, nil)
+	ObserveCollection(time.Minute, true, errors.New("failed to run collector"))
+	ObserveCollection(time.Minute, false, errors.New("failed to generate support bundle"))
+	ObserveCollection(time.Minute, true, nil)
+
+	assert.Equal(t, 2.0, testutil.ToFloat64(collections.WithLabelValues(ResultSuccess)))
+	assert.Equal(t, 1.0, testutil.ToFloat64(collections.WithLabelValues(ResultPartial)))
+	assert.Equal(t, 1.0, testutil.ToFloat64(collections.WithLabelValues(ResultFailure)))
+	assert.InDelta(t, float64(time.Now().Unix()), testutil.ToFloat64(lastCollection.WithLabelValues(ResultSuccess)), 5)
+}
+
+func TestStart(t *testing.T) {
+	ObserveRedaction("Redact passwords in connection strings")
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	address := listener.Addr().String()
+	require.NoError(t, listener.Close())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	require.NoError(t, Start(ctx, address))
+
+	// the address is bound, a second endpoint can't use it
+	assert.ErrorContains(t, Start(ctx, address), "failed to listen on metrics address")
+
+	resp, err := http.Get(fmt.Sprintf("http://%s/metrics", address))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, string(body), `troubleshoot_redactions_total{redactor="Redact passwords in connection strings"} 1`)
+	assert.Contains(t, string(body), "go_goroutines")
+}
+
+func TestStart_Disabled(t *testing.T) {
+	assert.NoError(t, Start(context.Background(), ""))
+	assert.NoError(t, Start(context.Background(), "0"))
+}
@@ -0,0 +1,60 @@
+package notify
+
+import (
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	flag "github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+const (
+	flagNotifyWebhook = "notify-webhook"
+	flagNotifySlack   = "notify-slack"
+	flagNotifyEmail   = "notify-email"
+	flagSMTPServer    = "smtp-server"
+	flagSMTPFrom      = "smtp-from"
+	flagSMTPUsername  = "smtp-username"
+)
+
+// AddFlags adds the flags that send notifications when a run completes, in addition to those of
+// the spec
+func AddFlags(flags *flag.FlagSet) {
+	flags.StringSlice(flagNotifyWebhook, []string{}, "post a JSON summary of the run to these URLs when it completes")
+	flags.StringSlice(flagNotifySlack, []string{}, "post a summary of the run to these Slack compatible incoming webhook URLs when it completes")
+	flags.StringSlice(flagNotifyEmail, []string{}, "email a summary of the run to these addresses when it completes, through the --smtp-server")
+	flags.String(flagSMTPServer, "", "host and port of the SMTP server to send email notifications through, e.g. smtp.example.com:587")
+	flags.String(flagSMTPFrom, "", "sender address of email notifications")
+	flags.String(flagSMTPUsername, "", "user to authenticate to the SMTP server as. The password is read from the "+DefaultSMTPPasswordEnv+" environment variable")
+}
+
+// NotificationsFromFlags returns the notifications the flags added by AddFlags set
+func NotificationsFromFlags(v *viper.Viper) ([]*troubleshootv1beta2.Notification, error) {
+	notifications := []*troubleshootv1beta2.Notification{}
+
+	for _, uri := range v.GetStringSlice(flagNotifyWebhook) {
+		notifications = append(notifications, &troubleshootv1beta2.Notification{
+			Webhook: &troubleshootv1beta2.WebhookNotification{URI: uri},
+		})
+	}
+	for _, uri := range v.GetStringSlice(flagNotifySlack) {
+		notifications = append(notifications, &troubleshootv1beta2.Notification{
+			Slack: &troubleshootv1beta2.WebhookNotification{URI: uri},
+		})
+	}
+
+	if to := v.GetStringSlice(flagNotifyEmail); len(to) > 0 {
+		if v.GetString(flagSMTPServer) == "" || v.GetString(flagSMTPFrom) == "" {
+			return nil, errors.Errorf("--%s requires --%s and --%s", flagNotifyEmail, flagSMTPServer, flagSMTPFrom)
+		}
+		notifications = append(notifications, &troubleshootv1beta2.Notification{
+			SMTP: &troubleshootv1beta2.SMTPNotification{
+				Server:   v.GetString(flagSMTPServer),
+				From:     v.GetString(flagSMTPFrom),
+				To:       to,
+				Username: v.GetString(flagSMTPUsername),
+			},
+		})
+	}
+
+	return notifications, nil
+}
@@ -0,0 +1,123 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	analyzer "github.com/replicatedhq/troubleshoot/pkg/analyze"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/httputil"
+)
+
+const (
+	KindPreflight     = "preflight"
+	KindSupportBundle = "support-bundle"
+)
+
+// Summary is what notifications report about a completed run
+type Summary struct {
+	Kind string `json:"kind"`
+	Name string `json:"name,omitempty"`
+	Pass int    `json:"pass"`
+	Warn int    `json:"warn"`
+	Fail int    `json:"fail"`
+	// Failed and Warned are the titles of the checks that failed and warned
+	Failed []string `json:"failed,omitempty"`
+	Warned []string `json:"warned,omitempty"`
+	// BundlePath is where the archive of the run was saved, and Uploaded is true when the bundle
+	// or the results were also uploaded
+	BundlePath  string    `json:"bundlePath,omitempty"`
+	Uploaded    bool      `json:"uploaded"`
+	CompletedAt time.Time `json:"completedAt"`
+}
+
+// NewSummary counts the outcomes of the results of a run
+func NewSummary(kind string, name string, results []*analyzer.AnalyzeResult) Summary {
+	summary := Summary{
+		Kind:        kind,
+		Name:        name,
+		CompletedAt: time.Now().UTC(),
+	}
+	for _, result := range results {
+		if result == nil {
+			continue
+		}
+		switch {
+		case result.IsFail:
+			summary.Fail++
+			summary.Failed = append(summary.Failed, result.Title)
+		case result.IsWarn:
+			summary.Warn++
+			summary.Warned = append(summary.Warned, result.Title)
+		case result.IsPass:
+			summary.Pass++
+		}
+	}
+	return summary
+}
+
+// Title is a one line summary of the run, e.g. "Preflight checks for my-app completed: 3 passed,
+// 1 warned, 0 failed"
+func (s Summary) Title() string {
+	subject := "Support bundle"
+	if s.Kind == KindPreflight {
+		subject = "Preflight checks"
+	}
+	if s.Name != "" {
+		subject = fmt.Sprintf("%s for %s", subject, s.Name)
+	}
+	return fmt.Sprintf("%s completed: %d passed, %d warned, %d failed", subject, s.Pass, s.Warn, s.Fail)
+}
+
+// Text is the title followed by the checks that failed and warned and where the bundle is
+func (s Summary) Text() string {
+	lines := []string{s.Title()}
+	if len(s.Failed) > 0 {
+		lines = append(lines, fmt.Sprintf("Failed: %s", strings.Join(s.Failed, ", ")))
+	}
+	if len(s.Warned) > 0 {
+		lines = append(lines, fmt.Sprintf("Warned: %s", strings.Join(s.Warned, ", ")))
+	}
+	if s.BundlePath != "" {
+		bundle := fmt.Sprintf("Bundle: %s", s.BundlePath)
+		if s.Uploaded {
+			bundle += " (uploaded)"
+		}
+		lines = append(lines, bundle)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Send sends the summary to each of the notifications. A notification that cannot be sent does
+// not stop the others, the errors of all of them are returned together.
+func Send(ctx context.Context, notifications []*troubleshootv1beta2.Notification, summary Summary) error {
+	errs := []string{}
+	for _, notification := range notifications {
+		if notification == nil {
+			continue
+		}
+
+		var err error
+		switch {
+		case notification.Webhook != nil:
+			err = sendWebhook(ctx, httputil.GetHttpClient(), notification.Webhook, summary)
+		case notification.Slack != nil:
+			err = sendSlack(ctx, httputil.GetHttpClient(), notification.Slack, summary)
+		case notification.SMTP != nil:
+			err = sendSMTP(notification.SMTP, summary)
+		default:
+			err = errors.New("notification has no webhook, slack or smtp destination")
+		}
+		if err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+
+	if len(errs) > 0 {
+		return errors.Errorf("failed to send notifications: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
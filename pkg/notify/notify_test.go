@@ -0,0 +1,254 @@
+package notify
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	analyzer "github.com/replicatedhq/troubleshoot/pkg/analyze"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	flag "github.com/spf13/pflag"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testSummary() Summary {
+	summary := NewSummary(KindPreflight, "my-app", []*analyzer.AnalyzeResult{
+		{IsPass: true, Title: "Kubernetes version"},
+		{IsPass: true, Title: "Node count"},
+		{IsWarn: true, Title: "Memory"},
+		{IsFail: true, Title: "Storage class"},
+	})
+	summary.BundlePath = "preflightbundle-2024-01-01T12_00_00.tar.gz"
+	return summary
+}
+
+func TestNewSummary(t *testing.T) {
+	summary := testSummary()
+
+	assert.Equal(t, 2, summary.Pass)
+	assert.Equal(t, 1, summary.Warn)
+	assert.Equal(t, 1, summary.Fail)
+	assert.Equal(t, []string{"Storage class"}, summary.Failed)
+	assert.Equal(t, []string{"Memory"}, summary.Warned)
+	assert.Equal(t, "Preflight checks for my-app completed: 2 passed, 1 warned, 1 failed", summary.Title())
+	assert.Equal(t, `Preflight checks for my-app completed: 2 passed, 1 warned, 1 failed
+Failed: Storage class
+Warned: Memory
+Bundle: preflightbundle-2024-01-01T12_00_00.tar.gz`, summary.Text())
+
+	summary = NewSummary(KindSupportBundle, "", nil)
+	summary.BundlePath = "support-bundle.tar.gz"
+	summary.Uploaded = true
+	assert.Equal(t, `Support bundle completed: 0 passed, 0 warned, 0 failed
+Bundle: support-bundle.tar.gz (uploaded)`, summary.Text())
+}
+
+func Test_sendWebhook(t *testing.T) {
+	t.Setenv("NOTIFY_TOKEN", "secret")
+
+	var received Summary
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+	}))
+	defer server.Close()
+
+	webhook := &troubleshootv1beta2.WebhookNotification{URI: server.URL, TokenEnv: "NOTIFY_TOKEN"}
+	require.NoError(t, sendWebhook(context.Background(), server.Client(), webhook, testSummary()))
+	assert.Equal(t, KindPreflight, received.Kind)
+	assert.Equal(t, "my-app", received.Name)
+	assert.Equal(t, 1, received.Fail)
+	assert.Equal(t, []string{"Storage class"}, received.Failed)
+}
+
+func Test_sendSlack(t *testing.T) {
+	var received slackMessage
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Empty(t, r.Header.Get("Authorization"))
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+	}))
+	defer server.Close()
+
+	webhook := &troubleshootv1beta2.WebhookNotification{URI: server.URL}
+	require.NoError(t, sendSlack(context.Background(), server.Client(), webhook, testSummary()))
+	assert.Equal(t, testSummary().Text(), received.Text)
+}
+
+func Test_sendSlack_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	webhook := &troubleshootv1beta2.WebhookNotification{URI: server.URL + "/services/T000/B000/XXXX"}
+	err := sendSlack(context.Background(), server.Client(), webhook, testSummary())
+	assert.ErrorContains(t, err, "unexpected status code 403")
+	assert.NotContains(t, err.Error(), "XXXX")
+
+	server.Close()
+	err = sendSlack(context.Background(), server.Client(), webhook, testSummary())
+	assert.Error(t, err)
+	assert.NotContains(t, err.Error(), "XXXX")
+}
+
+func Test_sendSMTP(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+
+	received := make(chan string, 1)
+	go serveSMTP(t, listener, received)
+
+	notification := &troubleshootv1beta2.SMTPNotification{
+		Server: listener.Addr().String(),
+		From:   "preflight@example.com",
+		To:     []string{"ops@example.com", "dev@example.com"},
+	}
+	require.NoError(t, sendSMTP(notification, testSummary()))
+
+	message := <-received
+	assert.Contains(t, message, "From: preflight@example.com\r\n")
+	assert.Contains(t, message, "To: ops@example.com, dev@example.com\r\n")
+	assert.Contains(t, message, "Subject: Preflight checks for my-app completed: 2 passed, 1 warned, 1 failed\r\n")
+	assert.Contains(t, message, "\r\nFailed: Storage class\r\n")
+}
+
+func Test_sendSMTP_Invalid(t *testing.T) {
+	err := sendSMTP(&troubleshootv1beta2.SMTPNotification{Server: "localhost:25"}, testSummary())
+	assert.ErrorContains(t, err, "requires a server, a sender and at least one recipient")
+}
+
+// serveSMTP accepts one connection and sends the message it delivers to received, answering every
+// command with success
+func serveSMTP(t *testing.T, listener net.Listener, received chan<- string) {
+	conn, err := listener.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	reader := bufio.NewReader(conn)
+	reply := func(line string) {
+		_, err := conn.Write([]byte(line + "\r\n"))
+		assert.NoError(t, err)
+	}
+	reply("220 localhost ESMTP")
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		command := strings.ToUpper(strings.Fields(line)[0])
+		switch command {
+		case "EHLO", "HELO":
+			reply("250 localhost")
+		case "DATA":
+			reply("354 go ahead")
+			message := strings.Builder{}
+			for {
+				line, err := reader.ReadString('\n')
+				if err != nil || line == ".\r\n" {
+					break
+				}
+				message.WriteString(line)
+			}
+			received <- message.String()
+			reply("250 ok")
+		case "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("250 ok")
+		}
+	}
+}
+
+func TestSend(t *testing.T) {
+	posts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		posts++
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	notifications := []*troubleshootv1beta2.Notification{
+		{Webhook: &troubleshootv1beta2.WebhookNotification{URI: server.URL + "/fail"}},
+		{Slack: &troubleshootv1beta2.WebhookNotification{URI: server.URL + "/slack"}},
+		{},
+	}
+	err := Send(context.Background(), notifications, testSummary())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "/fail: unexpected status code 500")
+	assert.Contains(t, err.Error(), "notification has no webhook, slack or smtp destination")
+	// the failed webhook does not stop the slack notification
+	assert.Equal(t, 2, posts)
+}
+
+func TestNotificationsFromFlags(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		want    []*troubleshootv1beta2.Notification
+		wantErr string
+	}{
+		{
+			name: "none",
+			args: []string{},
+			want: []*troubleshootv1beta2.Notification{},
+		},
+		{
+			name: "webhooks and email",
+			args: []string{
+				"--notify-webhook", "https://example.com/hook",
+				"--notify-slack", "https://hooks.slack.com/services/T000/B000/XXXX",
+				"--notify-email", "ops@example.com,dev@example.com",
+				"--smtp-server", "smtp.example.com:587",
+				"--smtp-from", "preflight@example.com",
+				"--smtp-username", "preflight",
+			},
+			want: []*troubleshootv1beta2.Notification{
+				{Webhook: &troubleshootv1beta2.WebhookNotification{URI: "https://example.com/hook"}},
+				{Slack: &troubleshootv1beta2.WebhookNotification{URI: "https://hooks.slack.com/services/T000/B000/XXXX"}},
+				{SMTP: &troubleshootv1beta2.SMTPNotification{
+					Server:   "smtp.example.com:587",
+					From:     "preflight@example.com",
+					To:       []string{"ops@example.com", "dev@example.com"},
+					Username: "preflight",
+				}},
+			},
+		},
+		{
+			name:    "email without server",
+			args:    []string{"--notify-email", "ops@example.com"},
+			wantErr: "--notify-email requires --smtp-server and --smtp-from",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flags := flag.NewFlagSet("test", flag.ContinueOnError)
+			AddFlags(flags)
+			require.NoError(t, flags.Parse(tt.args))
+			v := viper.New()
+			require.NoError(t, v.BindPFlags(flags))
+
+			got, err := NotificationsFromFlags(v)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package notify
+
+import (
+	"bytes"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+)
+
+// DefaultSMTPPasswordEnv is the environment variable the password of an SMTP user is read from
+// when the notification does not name one
+const DefaultSMTPPasswordEnv = "SMTP_PASSWORD"
+
+// sendSMTP emails the text of the summary, authenticating when the notification sets a user. The
+// connection is upgraded with STARTTLS when the server offers it.
+func sendSMTP(notification *troubleshootv1beta2.SMTPNotification, summary Summary) error {
+	if notification.Server == "" || notification.From == "" || len(notification.To) == 0 {
+		return errors.New("smtp notification requires a server, a sender and at least one recipient")
+	}
+
+	var auth smtp.Auth
+	if notification.Username != "" {
+		host, _, err := net.SplitHostPort(notification.Server)
+		if err != nil {
+			return errors.Wrapf(err, "failed to parse smtp server %q", notification.Server)
+		}
+		passwordEnv := notification.PasswordEnv
+		if passwordEnv == "" {
+			passwordEnv = DefaultSMTPPasswordEnv
+		}
+		auth = smtp.PlainAuth("", notification.Username, os.Getenv(passwordEnv), host)
+	}
+
+	if err := smtp.SendMail(notification.Server, auth, notification.From, notification.To, emailMessage(notification, summary)); err != nil {
+		return errors.Wrapf(err, "failed to send notification through smtp server %s", notification.Server)
+	}
+	return nil
+}
+
+// emailMessage is the summary as a plain text email
+func emailMessage(notification *troubleshootv1beta2.SMTPNotification, summary Summary) []byte {
+	// the subject is built from the name of the spec, which must not add headers
+	subject := strings.NewReplacer("\r", " ", "\n", " ").Replace(summary.Title())
+
+	message := bytes.Buffer{}
+	fmt.Fprintf(&message, "From: %s\r\n", notification.From)
+	fmt.Fprintf(&message, "To: %s\r\n", strings.Join(notification.To, ", "))
+	fmt.Fprintf(&message, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&message, "Date: %s\r\n", summary.CompletedAt.Format(time.RFC1123Z))
+	message.WriteString("MIME-Version: 1.0\r\n")
+	message.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	message.WriteString("\r\n")
+	message.WriteString(strings.ReplaceAll(summary.Text(), "\n", "\r\n"))
+	message.WriteString("\r\n")
+	return message.Bytes()
+}
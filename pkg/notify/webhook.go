@@ -0,0 +1,69 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"os"
+
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+)
+
+// slackMessage is the body of a message posted to a Slack compatible incoming webhook
+type slackMessage struct {
+	Text string `json:"text"`
+}
+
+// sendWebhook posts the summary as JSON
+func sendWebhook(ctx context.Context, httpClient *http.Client, webhook *troubleshootv1beta2.WebhookNotification, summary Summary) error {
+	if err := post(ctx, httpClient, webhook, summary); err != nil {
+		return errors.Wrapf(err, "failed to post notification to webhook %s", webhook.URI)
+	}
+	return nil
+}
+
+// sendSlack posts the text of the summary
+func sendSlack(ctx context.Context, httpClient *http.Client, webhook *troubleshootv1beta2.WebhookNotification, summary Summary) error {
+	// the url of a slack webhook is its credential, so it is left out of the errors
+	if err := post(ctx, httpClient, webhook, slackMessage{Text: summary.Text()}); err != nil {
+		return errors.Wrap(err, "failed to post notification to slack")
+	}
+	return nil
+}
+
+func post(ctx context.Context, httpClient *http.Client, webhook *troubleshootv1beta2.WebhookNotification, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal notification")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URI, bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrap(err, "failed to create request")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if webhook.TokenEnv != "" {
+		if token := os.Getenv(webhook.TokenEnv); token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		// errors of the client repeat the url, which is the credential of a slack webhook
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			return urlErr.Err
+		}
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return errors.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -22,6 +22,8 @@ import (
 
 const (
 	HelmCredentialsFileBasename = ".config/helm/registry/config.json"
+	// WasmModuleMediaType is the media type of the layer of a WASM module artifact.
+	WasmModuleMediaType = "application/vnd.wasm.content.layer.v1+wasm"
 )
 
 var (
@@ -74,7 +76,26 @@ func PullSpecsFromOCI(ctx context.Context, uri string) ([]string, error) {
 	return rawSpecs, nil
 }
 
+// PullWasmModuleFromOCI pulls the WASM module of the artifact referenced by uri, such as
+// oci://registry.example.com/vendor/analyzer:1.0.0. The tag defaults to latest.
+func PullWasmModuleFromOCI(ctx context.Context, uri string) ([]byte, error) {
+	parsedRef, err := parseReference(uri)
+	if err != nil {
+		return nil, err
+	}
+	return pullLayer(ctx, parsedRef, WasmModuleMediaType)
+}
+
 func pullFromOCI(ctx context.Context, uri string, mediaType string, imageName string) ([]byte, error) {
+	parsedRef, err := parseURI(uri, imageName)
+	if err != nil {
+		return nil, err
+	}
+	return pullLayer(ctx, parsedRef, mediaType)
+}
+
+// pullLayer pulls the artifact and returns the contents of its layer with the media type.
+func pullLayer(ctx context.Context, parsedRef string, mediaType string) ([]byte, error) {
 	// helm credentials
 	helmCredentialsFile := filepath.Join(util.HomeDir(), HelmCredentialsFileBasename)
 	dockerauthClient, err := dockerauth.NewClientWithDockerFallback(helmCredentialsFile)
@@ -100,12 +121,7 @@ func pullFromOCI(ctx context.Context, uri string, mediaType string, imageName st
 	var descriptors, layers []ocispec.Descriptor
 	registryStore := content.Registry{Resolver: resolver}
 
-	parsedRef, err := parseURI(uri, imageName)
-	if err != nil {
-		return nil, err
-	}
-
-	klog.V(1).Infof("Pulling spec from %q OCI uri", parsedRef)
+	klog.V(1).Infof("Pulling %s from %q OCI uri", mediaType, parsedRef)
 
 	manifest, err := oras.Copy(ctx, registryStore, parsedRef, memoryStore, "",
 		oras.WithPullEmptyNameAllowed(),
@@ -181,3 +197,21 @@ func parseURI(in, imageName string) (string, error) {
 
 	return parsedRef.String(), nil
 }
+
+// parseReference parses an oci:// URI that references an artifact by its full name.
+func parseReference(in string) (string, error) {
+	ref, ok := strings.CutPrefix(in, "oci://")
+	if !ok {
+		return "", fmt.Errorf("%q is not an oci:// reference", in)
+	}
+
+	parsedRef, err := registry.ParseReference(ref)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to parse OCI uri reference")
+	}
+	if parsedRef.Reference == "" {
+		parsedRef.Reference = "latest"
+	}
+
+	return parsedRef.String(), nil
+}
@@ -64,3 +64,35 @@ func Test_parseURI(t *testing.T) {
 		})
 	}
 }
+
+func Test_parseReference(t *testing.T) {
+	tests := []struct {
+		name    string
+		uri     string
+		wantRef string
+		wantErr bool
+	}{
+		{
+			name:    "with tag",
+			uri:     "oci://registry.example.com/vendor/analyzer:1.0.0",
+			wantRef: "registry.example.com/vendor/analyzer:1.0.0",
+		},
+		{
+			name:    "without tag",
+			uri:     "oci://localhost:5000/vendor/analyzer",
+			wantRef: "localhost:5000/vendor/analyzer:latest",
+		},
+		{
+			name:    "missing scheme",
+			uri:     "registry.example.com/vendor/analyzer",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseReference(tt.uri)
+			require.Equalf(t, tt.wantErr, err != nil, "parseReference() error = %v, wantErr %v", err, tt.wantErr)
+			assert.Equal(t, tt.wantRef, got)
+		})
+	}
+}
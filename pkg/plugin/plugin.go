@@ -0,0 +1,184 @@
+// Package plugin runs collectors and analyzers shipped as separate executables.
+//
+// A plugin named "example" is an executable called troubleshoot-plugin-example, found in the
+// directories of the TROUBLESHOOT_PLUGIN_PATH environment variable, then in PATH. It is run
+// with "collect" or "analyze" as its first argument followed by the args of the spec, reads a
+// Request as JSON on stdin, and writes a CollectResponse or AnalyzeResponse as JSON on
+// stdout. It inherits the environment, so that it can use the same kubeconfig. A non-zero exit
+// code fails the collector or analyzer, and stderr is reported in the error.
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// ProtocolVersion is sent in every request, plugins should reject versions they do not know.
+	ProtocolVersion = "v1"
+	// ExecutablePrefix is the prefix of the executable name of plugins.
+	ExecutablePrefix = "troubleshoot-plugin-"
+	// PathEnvVar lists directories searched for plugins before PATH.
+	PathEnvVar = "TROUBLESHOOT_PLUGIN_PATH"
+	// DefaultTimeout is how long a plugin can run when the spec does not set a timeout.
+	DefaultTimeout = 5 * time.Minute
+
+	CommandCollect = "collect"
+	CommandAnalyze = "analyze"
+
+	// maxOutputSize is the largest response a plugin can write to stdout, 16MiB.
+	maxOutputSize = 16 << 20
+	// maxStderrSize is how much of stderr is kept for error messages.
+	maxStderrSize = 4 << 10
+)
+
+var namePattern = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]*[a-z0-9])?$`)
+
+// Request is written to the stdin of plugins.
+type Request struct {
+	ProtocolVersion string            `json:"protocolVersion"`
+	Command         string            `json:"command"`
+	Config          map[string]string `json:"config,omitempty"`
+	// Namespace is the namespace the collection is limited to, collectors only.
+	Namespace string `json:"namespace,omitempty"`
+	// Files are the bundle files matching the spec, keyed by their path relative to the root of
+	// the bundle, analyzers only.
+	Files map[string][]byte `json:"files,omitempty"`
+}
+
+// CollectResponse is written by collector plugins to stdout. Files are keyed by their path
+// relative to the directory of the collector in the bundle.
+type CollectResponse struct {
+	Files map[string][]byte `json:"files"`
+}
+
+// AnalyzeResponse is written by analyzer plugins to stdout.
+type AnalyzeResponse struct {
+	Results []Result `json:"results"`
+}
+
+type Result struct {
+	Title   string `json:"title"`
+	IsPass  bool   `json:"isPass,omitempty"`
+	IsWarn  bool   `json:"isWarn,omitempty"`
+	IsFail  bool   `json:"isFail,omitempty"`
+	Message string `json:"message,omitempty"`
+	URI     string `json:"uri,omitempty"`
+}
+
+// Find returns the path of the executable of the plugin.
+func Find(name string) (string, error) {
+	if !namePattern.MatchString(name) {
+		return "", errors.Errorf("invalid plugin name %q", name)
+	}
+	executable := ExecutablePrefix + name
+
+	for _, dir := range filepath.SplitList(os.Getenv(PathEnvVar)) {
+		if dir == "" {
+			continue
+		}
+		path := filepath.Join(dir, executable)
+		if isExecutable(path) {
+			return path, nil
+		}
+	}
+
+	path, err := exec.LookPath(executable)
+	if err != nil {
+		return "", errors.Errorf("plugin %q not found, install %s in %s or PATH", name, executable, PathEnvVar)
+	}
+	return path, nil
+}
+
+func isExecutable(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil {
+		return false
+	}
+	return !info.IsDir() && info.Mode()&0111 != 0
+}
+
+// Run runs the plugin with the command and args, sends it the request, and decodes its output
+// into response.
+func Run(ctx context.Context, name string, args []string, timeout time.Duration, request Request, response interface{}) error {
+	path, err := Find(name)
+	if err != nil {
+		return err
+	}
+
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	request.ProtocolVersion = ProtocolVersion
+	input, err := json.Marshal(request)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal plugin request")
+	}
+
+	stdout := &limitedBuffer{max: maxOutputSize}
+	stderr := &limitedBuffer{max: maxStderrSize}
+	cmd := exec.CommandContext(ctx, path, append([]string{request.Command}, args...)...)
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return errors.Errorf("plugin %q timed out after %s", name, timeout)
+		}
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return errors.Wrapf(err, "plugin %q failed: %s", name, msg)
+		}
+		return errors.Wrapf(err, "plugin %q failed", name)
+	}
+
+	if stdout.truncated {
+		return errors.Errorf("output of plugin %q is larger than %d bytes", name, maxOutputSize)
+	}
+	if err := json.Unmarshal(stdout.Bytes(), response); err != nil {
+		return errors.Wrapf(err, "failed to decode output of plugin %q", name)
+	}
+	return nil
+}
+
+// limitedBuffer keeps the first max bytes written to it, discards the rest, and records whether
+// more were written. The buffer is not embedded, so that io.Copy cannot bypass Write with the
+// ReadFrom of bytes.Buffer.
+type limitedBuffer struct {
+	buf       bytes.Buffer
+	max       int
+	truncated bool
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	remaining := b.max - b.buf.Len()
+	if len(p) > remaining {
+		b.truncated = true
+		if remaining > 0 {
+			b.buf.Write(p[:remaining])
+		}
+		return len(p), nil
+	}
+	b.buf.Write(p)
+	return len(p), nil
+}
+
+func (b *limitedBuffer) Bytes() []byte {
+	return b.buf.Bytes()
+}
+
+func (b *limitedBuffer) String() string {
+	return b.buf.String()
+}
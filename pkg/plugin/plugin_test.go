@@ -0,0 +1,125 @@
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writePlugin writes a plugin script that saves its arguments and request next to it and runs
+// the body, and adds its directory to the plugin path.
+func writePlugin(t *testing.T, name string, body string) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("plugin scripts need a POSIX shell")
+	}
+
+	dir := t.TempDir()
+	script := "#!/bin/sh\necho \"$@\" > \"$(dirname \"$0\")/args\"\ncat > \"$(dirname \"$0\")/request.json\"\n" + body + "\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ExecutablePrefix+name), []byte(script), 0755))
+	t.Setenv(PathEnvVar, dir)
+	return dir
+}
+
+func TestFind(t *testing.T) {
+	dir := writePlugin(t, "example", "exit 0")
+
+	path, err := Find("example")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "troubleshoot-plugin-example"), path)
+
+	_, err = Find("missing")
+	assert.EqualError(t, err, `plugin "missing" not found, install troubleshoot-plugin-missing in TROUBLESHOOT_PLUGIN_PATH or PATH`)
+
+	for _, name := range []string{"", "../example", "Example", "example-"} {
+		_, err = Find(name)
+		assert.EqualError(t, err, `invalid plugin name "`+name+`"`)
+	}
+}
+
+func TestRun(t *testing.T) {
+	dir := writePlugin(t, "example", `echo '{"files":{"status.txt":"b2s="}}'`)
+
+	response := CollectResponse{}
+	err := Run(context.Background(), "example", []string{"--verbose"}, 0, Request{
+		Command:   CommandCollect,
+		Config:    map[string]string{"endpoint": "http://example"},
+		Namespace: "default",
+	}, &response)
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]byte{"status.txt": []byte("ok")}, response.Files)
+
+	args, err := os.ReadFile(filepath.Join(dir, "args"))
+	require.NoError(t, err)
+	assert.Equal(t, "collect --verbose\n", string(args))
+
+	request := Request{}
+	data, err := os.ReadFile(filepath.Join(dir, "request.json"))
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(data, &request))
+	assert.Equal(t, Request{
+		ProtocolVersion: ProtocolVersion,
+		Command:         CommandCollect,
+		Config:          map[string]string{"endpoint": "http://example"},
+		Namespace:       "default",
+	}, request)
+}
+
+func TestRun_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		timeout time.Duration
+		wantErr string
+	}{
+		{
+			name:    "exit code",
+			body:    "echo 'cannot reach endpoint' >&2\nexit 3",
+			wantErr: `plugin "example" failed: cannot reach endpoint: exit status 3`,
+		},
+		{
+			name:    "invalid output",
+			body:    "echo 'not json'",
+			wantErr: `failed to decode output of plugin "example": invalid character 'o' in literal null (expecting 'u')`,
+		},
+		{
+			name:    "timeout",
+			body:    "exec sleep 5",
+			timeout: 100 * time.Millisecond,
+			wantErr: `plugin "example" timed out after 100ms`,
+		},
+		{
+			name:    "output too large",
+			body:    "head -c 17000000 /dev/zero",
+			wantErr: `output of plugin "example" is larger than 16777216 bytes`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writePlugin(t, "example", tt.body)
+
+			err := Run(context.Background(), "example", nil, tt.timeout, Request{Command: CommandAnalyze}, &AnalyzeResponse{})
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
+
+func Test_limitedBuffer(t *testing.T) {
+	b := &limitedBuffer{max: 4}
+	n, err := b.Write([]byte("abc"))
+	require.NoError(t, err)
+	assert.Equal(t, 3, n)
+	assert.False(t, b.truncated)
+	n, err = b.Write([]byte("def"))
+	require.NoError(t, err)
+	assert.Equal(t, 3, n)
+	assert.Equal(t, "abcd", b.String())
+	assert.True(t, b.truncated)
+}
@@ -0,0 +1,391 @@
+package preflight
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+
+	"github.com/containers/image/v5/docker/reference"
+	"github.com/containers/image/v5/types"
+	"github.com/opencontainers/go-digest"
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/collect"
+	"github.com/replicatedhq/troubleshoot/pkg/loader"
+	"github.com/replicatedhq/troubleshoot/pkg/registry"
+)
+
+// airgapInternalSuffixes are the domains that resolve inside a cluster or a private network
+var airgapInternalSuffixes = []string{".svc", ".cluster.local", ".local", ".localdomain", ".internal", ".home.arpa"}
+
+// AirgapOptions configure the air gap readiness check
+type AirgapOptions struct {
+	// Registry is the private registry the images are mirrored to, with an optional namespace,
+	// e.g. registry.internal:5000/mirror. The repository of an image is kept under it, so that
+	// docker.io/library/alpine:3 is expected at registry.internal:5000/mirror/library/alpine:3.
+	Registry string
+	// RegistryAuth is the credentials of the private registry, if any
+	RegistryAuth *registry.AuthConfig
+	// AllowedHosts are hosts inside the air gap, besides private addresses and cluster domains.
+	// An entry starting with a dot allows the subdomains of a domain, e.g. .corp.example.com.
+	AllowedHosts []string
+	// CollectorImage is the image of the remote collectors, the --collector-image of preflight
+	CollectorImage string
+}
+
+// AirgapReport is the air gap readiness of preflight specs. The specs are ready when every image
+// resolves in the private registry, and no collector or endpoint reaches outside the air gap.
+type AirgapReport struct {
+	Registry  string           `json:"registry"`
+	Ready     bool             `json:"ready"`
+	Images    []AirgapImage    `json:"images"`
+	Egress    []AirgapEgress   `json:"egress"`
+	Endpoints []AirgapEndpoint `json:"endpoints"`
+}
+
+// AirgapImage is an image that a collector runs or an analyzer pulls, and its mirror in the
+// private registry. Digest is the digest the mirror resolves to, empty when Error says why it
+// did not.
+type AirgapImage struct {
+	Source string `json:"source"`
+	Image  string `json:"image"`
+	Mirror string `json:"mirror"`
+	Digest string `json:"digest,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// AirgapEgress is a host that a collector connects to. External is set when the host is not
+// inside the air gap.
+type AirgapEgress struct {
+	Collector string `json:"collector"`
+	Host      string `json:"host"`
+	External  bool   `json:"external"`
+}
+
+// AirgapEndpoint is a setting of a spec that fetches updates of the spec, or sends results or
+// notifications, e.g. uri or uploadResultsTo. External is set when it reaches outside the air gap.
+type AirgapEndpoint struct {
+	Spec     string `json:"spec"`
+	Setting  string `json:"setting"`
+	URL      string `json:"url"`
+	External bool   `json:"external"`
+}
+
+// digestResolver resolves the digest of an image in a registry
+type digestResolver interface {
+	ResolveDigest(ctx context.Context, imageRef types.ImageReference) (digest.Digest, error)
+}
+
+// CheckAirgap checks that the preflight specs can run in an air gap, without running any of
+// their collectors. The images the collectors and analyzers need are resolved by digest in the
+// private registry, which is the only registry that is contacted.
+func CheckAirgap(ctx context.Context, kinds *loader.TroubleshootKinds, opts AirgapOptions) (*AirgapReport, error) {
+	client := registry.NewClient(registry.DefaultOptions(opts.RegistryAuth))
+	return checkAirgap(ctx, kinds, opts, client)
+}
+
+func checkAirgap(ctx context.Context, kinds *loader.TroubleshootKinds, opts AirgapOptions, resolver digestResolver) (*AirgapReport, error) {
+	opts.Registry = strings.TrimSuffix(opts.Registry, "/")
+	if opts.Registry == "" {
+		return nil, errors.New("a private registry is required")
+	}
+	registryHost, _, _ := strings.Cut(opts.Registry, "/")
+
+	report := &AirgapReport{
+		Registry:  opts.Registry,
+		Images:    []AirgapImage{},
+		Egress:    []AirgapEgress{},
+		Endpoints: []AirgapEndpoint{},
+	}
+
+	isInternal := func(host string) bool {
+		return host == registryHost || isAirgapHost(host, opts.AllowedHosts)
+	}
+	addImage := func(source string, image string) {
+		report.Images = append(report.Images, AirgapImage{Source: source, Image: image})
+	}
+	addEgress := func(collector string, targets ...string) {
+		for _, target := range targets {
+			host := egressHost(target)
+			report.Egress = append(report.Egress, AirgapEgress{Collector: collector, Host: host, External: !isInternal(host)})
+		}
+	}
+	addEndpoint := func(spec string, setting string, endpoint string) {
+		if endpoint == "" {
+			return
+		}
+		report.Endpoints = append(report.Endpoints, AirgapEndpoint{Spec: spec, Setting: setting, URL: endpoint, External: !isInternal(egressHost(endpoint))})
+	}
+
+	for _, spec := range kinds.PreflightsV1Beta2 {
+		for _, collector := range spec.Spec.Collectors {
+			name := collector.GetName()
+			for _, image := range collect.CollectorImages(collector) {
+				addImage(name, image)
+			}
+			addEgress(name, collectorEgressTargets(collector)...)
+		}
+		for _, analyzer := range spec.Spec.Analyzers {
+			if analyzer.Wasm != nil && analyzer.Wasm.Image != "" {
+				addImage("analyzer/wasm", analyzer.Wasm.Image)
+			}
+		}
+		addEndpoint(spec.Name, "uri", spec.Spec.Uri)
+		addEndpoint(spec.Name, "uploadResultsTo", spec.Spec.UploadResultsTo)
+		addNotificationEndpoints(spec.Name, spec.Spec.Notifications, addEndpoint)
+	}
+
+	for _, spec := range kinds.HostPreflightsV1Beta2 {
+		for _, collector := range spec.Spec.Collectors {
+			if name, targets := hostCollectorEgressTargets(collector); len(targets) > 0 {
+				addEgress(name, targets...)
+			}
+		}
+		if len(spec.Spec.RemoteCollectors) > 0 {
+			addImage("remoteCollectors", collect.RemoteCollectorImage(opts.CollectorImage))
+		}
+		for _, collector := range spec.Spec.RemoteCollectors {
+			addEgress(collector.GetName(), remoteCollectorEgressTargets(collector)...)
+		}
+		addEndpoint(spec.Name, "uri", spec.Spec.Uri)
+		addNotificationEndpoints(spec.Name, spec.Spec.Notifications, addEndpoint)
+	}
+
+	// each image is resolved once, however many collectors run it
+	resolved := map[string]AirgapImage{}
+	for i, image := range report.Images {
+		result, ok := resolved[image.Image]
+		if !ok {
+			result = resolveAirgapImage(ctx, resolver, image.Image, opts.Registry, registryHost)
+			resolved[image.Image] = result
+		}
+		result.Source = image.Source
+		report.Images[i] = result
+	}
+
+	report.Ready = true
+	for _, image := range report.Images {
+		report.Ready = report.Ready && image.Digest != ""
+	}
+	for _, egress := range report.Egress {
+		report.Ready = report.Ready && !egress.External
+	}
+	for _, endpoint := range report.Endpoints {
+		report.Ready = report.Ready && !endpoint.External
+	}
+
+	return report, nil
+}
+
+func addNotificationEndpoints(spec string, notifications []*troubleshootv1beta2.Notification, addEndpoint func(string, string, string)) {
+	for _, notification := range notifications {
+		switch {
+		case notification.Webhook != nil:
+			addEndpoint(spec, "notifications.webhook", notification.Webhook.URI)
+		case notification.Slack != nil:
+			addEndpoint(spec, "notifications.slack", notification.Slack.URI)
+		case notification.SMTP != nil:
+			addEndpoint(spec, "notifications.smtp", notification.SMTP.Server)
+		}
+	}
+}
+
+// resolveAirgapImage resolves the mirror of an image in the private registry. An image that is
+// pinned by digest must resolve to the same digest.
+func resolveAirgapImage(ctx context.Context, resolver digestResolver, image string, privateRegistry string, registryHost string) AirgapImage {
+	result := AirgapImage{Image: image}
+
+	named, err := reference.ParseNormalizedNamed(image)
+	if err != nil {
+		result.Error = errors.Wrapf(err, "failed to parse image %s", image).Error()
+		return result
+	}
+	result.Mirror = mirrorImage(named, privateRegistry, registryHost)
+
+	imageRef, err := registry.ParseImageReference(result.Mirror)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	resolvedDigest, err := resolver.ResolveDigest(ctx, imageRef)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	if digested, ok := named.(reference.Digested); ok && digested.Digest() != resolvedDigest {
+		result.Error = fmt.Sprintf("mirror resolves to %s instead of %s", resolvedDigest, digested.Digest())
+		return result
+	}
+
+	result.Digest = resolvedDigest.String()
+	return result
+}
+
+// mirrorImage returns the name of an image in the private registry, with the same repository,
+// tag and digest. Images that are already in the private registry are not renamed.
+func mirrorImage(named reference.Named, privateRegistry string, registryHost string) string {
+	if reference.Domain(named) == registryHost {
+		return reference.TagNameOnly(named).String()
+	}
+
+	mirror := privateRegistry + "/" + reference.Path(named)
+	if digested, ok := named.(reference.Digested); ok {
+		if tagged, ok := named.(reference.Tagged); ok {
+			mirror += ":" + tagged.Tag()
+		}
+		return mirror + "@" + digested.Digest().String()
+	}
+	if tagged, ok := reference.TagNameOnly(named).(reference.Tagged); ok {
+		mirror += ":" + tagged.Tag()
+	}
+	return mirror
+}
+
+// collectorEgressTargets returns the URLs, addresses and images of registries that an in-cluster
+// collector connects to. URIs that are read from secrets are not known and not returned.
+func collectorEgressTargets(collector *troubleshootv1beta2.Collect) []string {
+	switch c := troubleshootv1beta2.GetCollector(collector).(type) {
+	case *troubleshootv1beta2.HTTP:
+		return httpEgressTargets(c.Get, c.Post, c.Put)
+	case *troubleshootv1beta2.Database:
+		return nonEmpty(c.URI)
+	case *troubleshootv1beta2.Kafka:
+		return c.Brokers
+	case *troubleshootv1beta2.RabbitMQ:
+		return nonEmpty(c.URI)
+	case *troubleshootv1beta2.LDAP:
+		return nonEmpty(c.URL)
+	case *troubleshootv1beta2.OIDC:
+		return nonEmpty(c.IssuerURL)
+	case *troubleshootv1beta2.RegistryImages:
+		return imageRegistries(c.Images)
+	case *troubleshootv1beta2.ImageSignatures:
+		return imageRegistries(c.Images)
+	case *troubleshootv1beta2.ImageLayers:
+		return imageRegistries(c.Images)
+	case *troubleshootv1beta2.ImageFacts:
+		return imageRegistries(c.Images)
+	}
+	return nil
+}
+
+// hostCollectorEgressTargets returns the name of a host collector and the URLs, addresses and
+// names it connects to or resolves
+func hostCollectorEgressTargets(collector *troubleshootv1beta2.HostCollect) (string, []string) {
+	switch {
+	case collector.HTTP != nil:
+		c := collector.HTTP
+		return airgapHostCollectorName("http", c.HostCollectorMeta), httpEgressTargets(c.Get, c.Post, c.Put)
+	case collector.TCPConnect != nil:
+		c := collector.TCPConnect
+		return airgapHostCollectorName("tcpConnect", c.HostCollectorMeta), nonEmpty(c.Address)
+	case collector.HostDNS != nil:
+		c := collector.HostDNS
+		return airgapHostCollectorName("dns", c.HostCollectorMeta), c.Hostnames
+	case collector.HostProxy != nil:
+		c := collector.HostProxy
+		return airgapHostCollectorName("proxy", c.HostCollectorMeta), c.Endpoints
+	}
+	return "", nil
+}
+
+func remoteCollectorEgressTargets(collector *troubleshootv1beta2.RemoteCollect) []string {
+	switch {
+	case collector.HTTP != nil:
+		return httpEgressTargets(collector.HTTP.Get, collector.HTTP.Post, collector.HTTP.Put)
+	case collector.TCPConnect != nil:
+		return nonEmpty(collector.TCPConnect.Address)
+	}
+	return nil
+}
+
+func airgapHostCollectorName(collector string, meta troubleshootv1beta2.HostCollectorMeta) string {
+	if meta.CollectorName != "" {
+		return fmt.Sprintf("%s/%s", collector, meta.CollectorName)
+	}
+	return collector
+}
+
+func httpEgressTargets(get *troubleshootv1beta2.Get, post *troubleshootv1beta2.Post, put *troubleshootv1beta2.Put) []string {
+	switch {
+	case get != nil:
+		return nonEmpty(get.URL)
+	case post != nil:
+		return nonEmpty(post.URL)
+	case put != nil:
+		return nonEmpty(put.URL)
+	}
+	return nil
+}
+
+// imageRegistries returns the registries of images, those that fail to parse as they are
+func imageRegistries(images []string) []string {
+	registries := []string{}
+	for _, image := range images {
+		named, err := reference.ParseNormalizedNamed(image)
+		if err != nil {
+			registries = append(registries, image)
+			continue
+		}
+		registries = append(registries, reference.Domain(named))
+	}
+	return registries
+}
+
+func nonEmpty(values ...string) []string {
+	nonEmpty := []string{}
+	for _, value := range values {
+		if value != "" {
+			nonEmpty = append(nonEmpty, value)
+		}
+	}
+	return nonEmpty
+}
+
+// egressHost returns the host of a URL, a host:port address or a MySQL DSN such as
+// user:password@tcp(mysql:3306)/db
+func egressHost(target string) string {
+	if u, err := url.Parse(target); err == nil && u.Host != "" {
+		return u.Hostname()
+	}
+	if _, address, ok := strings.Cut(target, "@tcp("); ok {
+		target, _, _ = strings.Cut(address, ")")
+	}
+	if host, _, err := net.SplitHostPort(target); err == nil {
+		return host
+	}
+	return target
+}
+
+// isAirgapHost returns true when a host is inside the air gap: a loopback, private or link-local
+// address, a name without a domain, a cluster or private domain, or one of the allowed hosts.
+func isAirgapHost(host string, allowedHosts []string) bool {
+	host = strings.TrimSuffix(strings.ToLower(strings.Trim(host, "[]")), ".")
+	if host == "" || host == "localhost" {
+		return true
+	}
+
+	if ip := net.ParseIP(host); ip != nil {
+		return ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsUnspecified()
+	}
+
+	if !strings.Contains(host, ".") {
+		return true
+	}
+	for _, suffix := range airgapInternalSuffixes {
+		if strings.HasSuffix(host, suffix) {
+			return true
+		}
+	}
+
+	for _, allowed := range allowedHosts {
+		allowed = strings.ToLower(allowed)
+		if host == allowed || host == strings.TrimPrefix(allowed, ".") ||
+			(strings.HasPrefix(allowed, ".") && strings.HasSuffix(host, allowed)) {
+			return true
+		}
+	}
+	return false
+}
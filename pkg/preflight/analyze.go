@@ -9,12 +9,18 @@ import (
 
 	analyze "github.com/replicatedhq/troubleshoot/pkg/analyze"
 	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/facts"
 	"k8s.io/klog/v2"
 )
 
-// Analyze runs the analyze phase of preflight checks
+// Analyze runs the analyze phase of preflight checks, with the exclude templates of the analyzers
+// evaluated against the facts of the collection
 func (c ClusterCollectResult) Analyze() []*analyze.AnalyzeResult {
-	return doAnalyze(c.Context, c.AllCollectedData, c.Spec.Spec.Analyzers, nil, "")
+	analyzers, err := facts.ResolveAnalyzerExcludes(c.Spec.Spec.Analyzers, c.facts)
+	if err != nil {
+		klog.Errorf("%v", err)
+	}
+	return doAnalyze(c.Context, c.AllCollectedData, analyzers, nil, "")
 }
 
 // Analyze runs the analyze phase of host preflight checks
@@ -2,9 +2,10 @@ package preflight
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
+	"os"
 	"reflect"
+	"strings"
 	"time"
 
 	"github.com/pkg/errors"
@@ -12,6 +13,8 @@ import (
 	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
 	"github.com/replicatedhq/troubleshoot/pkg/collect"
 	"github.com/replicatedhq/troubleshoot/pkg/constants"
+	"github.com/replicatedhq/troubleshoot/pkg/facts"
+	"github.com/replicatedhq/troubleshoot/pkg/k8sutil"
 	"github.com/replicatedhq/troubleshoot/pkg/version"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
@@ -33,6 +36,8 @@ type CollectOpts struct {
 
 	// Optional path to the bundle directory to store the collected data
 	BundlePath string
+	// Skipper, when set, lets the running collector be skipped
+	Skipper *CollectorSkipper
 }
 
 type CollectProgress struct {
@@ -63,6 +68,9 @@ type ClusterCollectResult struct {
 	isRBACAllowed    bool
 	Spec             *troubleshootv1beta2.Preflight
 	Context          context.Context
+	// facts are the facts of the cluster the exclude templates of the analyzers are evaluated
+	// against, nil when no template references them
+	facts *facts.Facts
 }
 
 func (cr ClusterCollectResult) IsRBACAllowed() bool {
@@ -104,13 +112,24 @@ func CollectHostWithContext(
 		collectSpecs = append(collectSpecs, p.Spec.Collectors...)
 	}
 
-	allCollectedData := make(map[string][]byte)
+	store := collect.NewResultStore(opts.BundlePath, 0)
+	defer store.Close()
+
+	// collectors are created with a context the skipper can cancel while they run
+	var collectorCtx context.Context = ctx
+	var skippable *skippableContext
+	if opts.Skipper != nil {
+		skippable = opts.Skipper.context(ctx)
+		collectorCtx = skippable
+	}
 
 	var collectors []collect.HostCollector
+	var specs []*troubleshootv1beta2.HostCollect
 	for _, desiredCollector := range collectSpecs {
-		collector, ok := collect.GetHostCollector(desiredCollector, opts.BundlePath)
+		collector, ok := collect.GetHostCollectorWithContext(collectorCtx, desiredCollector, opts.BundlePath)
 		if ok {
 			collectors = append(collectors, collector)
+			specs = append(specs, desiredCollector)
 		}
 	}
 
@@ -120,32 +139,64 @@ func CollectHostWithContext(
 		Context:    ctx,
 	}
 
-	for _, collector := range collectors {
+	skipped := collect.SkippedCollectors{}
+	privileges := collect.DetectHostPrivileges(os.DirFS("/"))
+	for i, collector := range collectors {
+		if ctx.Err() != nil {
+			break
+		}
 		_, span := otel.Tracer(constants.LIB_TRACER_NAME).Start(ctx, collector.Title())
 		span.SetAttributes(attribute.String("type", reflect.TypeOf(collector).String()))
 
 		isExcluded, _ := collector.IsExcluded()
 		if isExcluded {
 			opts.ProgressChan <- fmt.Sprintf("[%s] Excluding collector", collector.Title())
+			skipped.AddHostCollector(specs[i], collect.SkipReasonExcluded)
 			span.SetAttributes(attribute.Bool(constants.EXCLUDED, true))
 			span.End()
 			continue
 		}
 
+		if missing := privileges.Missing(collect.RequiredHostPrivileges(specs[i])); len(missing) > 0 {
+			opts.ProgressChan <- fmt.Sprintf("[%s] Collecting without %s", collector.Title(), strings.Join(missing, ", "))
+			skipped.AddHostPrivileges(specs[i], missing)
+		}
+
 		opts.ProgressChan <- fmt.Sprintf("[%s] Running collector...", collector.Title())
+		skippable.start()
 		result, err := collector.Collect(opts.ProgressChan)
+		if skippable.finish() {
+			opts.ProgressChan <- fmt.Sprintf("[%s] Skipped collector", collector.Title())
+			skipped.AddHostCollector(specs[i], collect.SkipReasonSkipped)
+			span.SetStatus(codes.Error, "skipped collector")
+			span.End()
+			continue
+		}
 		if err != nil {
 			opts.ProgressChan <- errors.Errorf("failed to run collector: %s: %v", collector.Title(), err)
 		}
-		for k, v := range result {
-			allCollectedData[k] = v
+		span.SetAttributes(attribute.Int("files", len(result)))
+		if err := store.AddResult(result); err != nil {
+			opts.ProgressChan <- errors.Wrapf(err, "failed to save results of collector: %s", collector.Title())
 		}
 		span.End()
 	}
 
+	allCollectedData, err := store.Result()
+	if err != nil {
+		return collectResult, errors.Wrap(err, "failed to read collector results")
+	}
+	if err := skipped.SaveResult(allCollectedData, opts.BundlePath); err != nil {
+		opts.ProgressChan <- errors.Wrap(err, "failed to save skipped collectors")
+	}
+
 	// The values of map entries will contain the collected data in bytes if the data was not stored to disk
 	collectResult.AllCollectedData = allCollectedData
 
+	if err := ctx.Err(); err != nil {
+		return collectResult, errors.Wrap(err, "collection canceled")
+	}
+
 	return collectResult, nil
 }
 
@@ -180,12 +231,46 @@ func CollectWithContext(ctx context.Context, opts CollectOpts, p *troubleshootv1
 		return nil, errors.Wrap(err, "failed to instantiate Kubernetes client")
 	}
 
+	// The facts phase reads the facts of the cluster the exclude templates of collectors and
+	// analyzers are evaluated against, before anything is collected, when a template references them
+	var clusterFacts *facts.Facts
+	if p != nil && facts.Referenced(collectSpecs, p.Spec.Analyzers) {
+		var errs []error
+		clusterFacts, errs = facts.Gather(ctx, k8sClient)
+		for _, err := range errs {
+			klog.Warningf("failed to read a cluster fact for exclude templates: %v", err)
+		}
+	}
+	collectSpecs, err = facts.ResolveCollectorExcludes(collectSpecs, clusterFacts)
+	if err != nil {
+		opts.ProgressChan <- err
+	}
+
+	// the collectors of the run share the port-forwards to the same pods
+	portForwards, err := k8sutil.NewPortForwardPool(opts.KubernetesRestConfig)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create port-forward pool")
+	}
+	defer portForwards.Close()
+
 	allCollectorsMap := make(map[reflect.Type][]collect.Collector)
-	allCollectedData := make(map[string][]byte)
+	store := collect.NewResultStore(opts.BundlePath, 0)
+	defer store.Close()
+
+	// collectors are created with a context the skipper can cancel while they run
+	var collectorCtx context.Context = ctx
+	var skippable *skippableContext
+	if opts.Skipper != nil {
+		skippable = opts.Skipper.context(ctx)
+		collectorCtx = skippable
+	}
 
 	for _, desiredCollector := range collectSpecs {
-		if collectorInterface, ok := collect.GetCollector(desiredCollector, opts.BundlePath, opts.Namespace, opts.KubernetesRestConfig, k8sClient, nil); ok {
+		if collectorInterface, ok := collect.GetCollectorWithContext(collectorCtx, desiredCollector, opts.BundlePath, opts.Namespace, opts.KubernetesRestConfig, k8sClient, nil); ok {
 			if collector, ok := collectorInterface.(collect.Collector); ok {
+				if c, ok := collector.(collect.PortForwardingCollector); ok {
+					c.SetPortForwardPool(portForwards)
+				}
 				err := collector.CheckRBAC(ctx, collector, desiredCollector, opts.KubernetesRestConfig, opts.Namespace)
 				if err != nil {
 					return nil, errors.Wrap(err, "failed to check RBAC for collectors")
@@ -227,6 +312,7 @@ func CollectWithContext(ctx context.Context, opts CollectOpts, p *troubleshootv1
 		Collectors: allCollectors,
 		Spec:       p,
 		Context:    ctx,
+		facts:      clusterFacts,
 	}
 
 	if foundForbidden && !opts.IgnorePermissionErrors {
@@ -237,13 +323,19 @@ func CollectWithContext(ctx context.Context, opts CollectOpts, p *troubleshootv1
 	// move Copy Collectors if any to the end of the execution list
 	allCollectors = collect.EnsureCopyLast(allCollectors)
 
+	skipped := collect.SkippedCollectors{}
+	retried := collect.RetriedCollectors{}
 	for i, collector := range allCollectors {
+		if ctx.Err() != nil {
+			break
+		}
 		_, span := otel.Tracer(constants.LIB_TRACER_NAME).Start(ctx, collector.Title())
 		span.SetAttributes(attribute.String("type", reflect.TypeOf(collector).String()))
 
 		isExcluded, _ := collector.IsExcluded()
 		if isExcluded {
 			klog.Infof("excluding %q collector", collector.Title())
+			skipped.AddCollector(collector, collect.SkipReasonExcluded)
 			span.SetAttributes(attribute.Bool(constants.EXCLUDED, true))
 			span.End()
 			continue
@@ -253,6 +345,7 @@ func CollectWithContext(ctx context.Context, opts CollectOpts, p *troubleshootv1
 		if collector.HasRBACErrors() {
 			if _, ok := collector.(*collect.CollectClusterResources); !ok {
 				opts.ProgressChan <- fmt.Sprintf("skipping collector %s with insufficient RBAC permissions", collector.Title())
+				skipped.AddCollector(collector, collect.SkipReasonInsufficientRBAC)
 				opts.ProgressChan <- CollectProgress{
 					CurrentName:    collector.Title(),
 					CurrentStatus:  "skipped",
@@ -277,7 +370,25 @@ func CollectWithContext(ctx context.Context, opts CollectOpts, p *troubleshootv1
 			Collectors:     collectorList,
 		}
 
-		result, err := collector.Collect(opts.ProgressChan)
+		skippable.start()
+		result, attempts, err := collect.CollectWithRetries(collectorCtx, collector, opts.ProgressChan)
+		retried.AddCollector(collector, attempts)
+		if skippable.finish() {
+			collectorList[collector.Title()] = CollectorStatus{
+				Status: "skipped",
+			}
+			skipped.AddCollector(collector, collect.SkipReasonSkipped)
+			opts.ProgressChan <- CollectProgress{
+				CurrentName:    collector.Title(),
+				CurrentStatus:  "skipped",
+				CompletedCount: i + 1,
+				TotalCount:     len(allCollectors),
+				Collectors:     collectorList,
+			}
+			span.SetStatus(codes.Error, "skipped collector")
+			span.End()
+			continue
+		}
 		if err != nil {
 			collectorList[collector.Title()] = CollectorStatus{
 				Status: "failed",
@@ -306,15 +417,31 @@ func CollectWithContext(ctx context.Context, opts CollectOpts, p *troubleshootv1
 			Collectors:     collectorList,
 		}
 
-		for k, v := range result {
-			allCollectedData[k] = v
+		span.SetAttributes(attribute.Int("files", len(result)))
+		if err := store.AddResult(result); err != nil {
+			opts.ProgressChan <- errors.Wrapf(err, "failed to save results of collector: %s", collector.Title())
 		}
 		span.End()
 	}
 
+	allCollectedData, err := store.Result()
+	if err != nil {
+		return collectResult, errors.Wrap(err, "failed to read collector results")
+	}
+	if err := skipped.SaveResult(allCollectedData, opts.BundlePath); err != nil {
+		opts.ProgressChan <- errors.Wrap(err, "failed to save skipped collectors")
+	}
+	if err := retried.SaveResult(allCollectedData, opts.BundlePath); err != nil {
+		opts.ProgressChan <- errors.Wrap(err, "failed to save collector attempts")
+	}
+
 	// The values of map entries will contain the collected data in bytes if the data was not stored to disk
 	collectResult.AllCollectedData = allCollectedData
 
+	if err := ctx.Err(); err != nil {
+		return collectResult, errors.Wrap(err, "collection canceled")
+	}
+
 	return collectResult, nil
 }
 
@@ -342,6 +469,7 @@ func CollectRemoteWithContext(ctx context.Context, opts CollectOpts, p *troubles
 			LabelSelector: opts.LabelSelector,
 			Namespace:     opts.Namespace,
 			Timeout:       opts.Timeout,
+			Context:       ctx,
 		}
 		collectors = append(collectors, &collector)
 	}
@@ -360,82 +488,51 @@ func CollectRemoteWithContext(ctx context.Context, opts CollectOpts, p *troubles
 		}
 	}
 
-	// Run preflights collectors synchronously
-	for i, collector := range collectors {
-		_, span := otel.Tracer(constants.LIB_TRACER_NAME).Start(ctx, collector.GetDisplayName())
-		span.SetAttributes(attribute.String("type", reflect.TypeOf(collector).String()))
+	// Run all preflight collectors in a single pass on each node
+	_, span := otel.Tracer(constants.LIB_TRACER_NAME).Start(ctx, "RemoteCollectors")
+	span.SetAttributes(attribute.String("type", reflect.TypeOf(collectors).String()))
 
+	for _, collector := range collectors {
 		collectorList[collector.GetDisplayName()] = CollectorStatus{
 			Status: "running",
 		}
-
+	}
+	for _, collector := range collectors {
 		opts.ProgressChan <- CollectProgress{
 			CurrentName:    collector.GetDisplayName(),
 			CurrentStatus:  "running",
-			CompletedCount: i,
+			CompletedCount: 0,
 			TotalCount:     len(collectors),
 			Collectors:     collectorList,
 		}
+	}
 
-		result, err := collector.RunCollectorSync(nil)
-		if err != nil {
-			collectorList[collector.GetDisplayName()] = CollectorStatus{
-				Status: "failed",
-			}
-
-			opts.ProgressChan <- errors.Errorf("failed to run collector %s: %v\n", collector.GetDisplayName(), err)
-			opts.ProgressChan <- CollectProgress{
-				CurrentName:    collector.GetDisplayName(),
-				CurrentStatus:  "failed",
-				CompletedCount: i + 1,
-				TotalCount:     len(collectors),
-				Collectors:     collectorList,
-			}
-			span.SetStatus(codes.Error, err.Error())
-			span.End()
-			continue
-		}
+	status := "completed"
+	result, err := collectors.RunCollectorsSync(nil)
+	if err != nil {
+		status = "failed"
+		opts.ProgressChan <- errors.Errorf("failed to run remote collectors: %v\n", err)
+		span.SetStatus(codes.Error, err.Error())
+	}
 
+	for _, collector := range collectors {
 		collectorList[collector.GetDisplayName()] = CollectorStatus{
-			Status: "completed",
+			Status: status,
 		}
-
+	}
+	for i, collector := range collectors {
 		opts.ProgressChan <- CollectProgress{
 			CurrentName:    collector.GetDisplayName(),
-			CurrentStatus:  "completed",
+			CurrentStatus:  status,
 			CompletedCount: i + 1,
 			TotalCount:     len(collectors),
 			Collectors:     collectorList,
 		}
+	}
+	span.End()
 
-		for k, v := range result {
-			if curBytes, ok := allCollectedData[k]; ok {
-				var curResults map[string]string
-				if err := json.Unmarshal(curBytes, &curResults); err != nil {
-					opts.ProgressChan <- errors.Errorf("failed to read existing results for collector %s: %v\n", collector.GetDisplayName(), err)
-					continue
-				}
-				var newResults map[string]string
-				if err := json.Unmarshal(v, &newResults); err != nil {
-					opts.ProgressChan <- errors.Errorf("failed to read new results for collector %s: %v\n", collector.GetDisplayName(), err)
-					continue
-				}
-				for file, data := range newResults {
-					curResults[file] = data
-				}
-				combinedResults, err := json.Marshal(curResults)
-				if err != nil {
-					opts.ProgressChan <- errors.Errorf("failed to combine results for collector %s: %v\n", collector.GetDisplayName(), err)
-					continue
-				}
-				allCollectedData[k] = combinedResults
-			} else {
-				allCollectedData[k] = v
-			}
-
-		}
-
-		span.End()
+	for k, v := range result {
+		allCollectedData[k] = v
 	}
 
 	collectResult.AllCollectedData = allCollectedData
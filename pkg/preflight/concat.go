@@ -16,6 +16,7 @@ func ConcatPreflightSpec(target *troubleshootv1beta2.Preflight, source *troubles
 		newSpec.Spec.Collectors = append(newSpec.Spec.Collectors, source.Spec.Collectors...)
 		newSpec.Spec.RemoteCollectors = append(newSpec.Spec.RemoteCollectors, source.Spec.RemoteCollectors...)
 		newSpec.Spec.Analyzers = append(newSpec.Spec.Analyzers, source.Spec.Analyzers...)
+		newSpec.Spec.Notifications = append(newSpec.Spec.Notifications, source.Spec.Notifications...)
 	}
 	return newSpec
 }
@@ -32,6 +33,7 @@ func ConcatHostPreflightSpec(target *troubleshootv1beta2.HostPreflight, source *
 		newSpec.Spec.Collectors = append(newSpec.Spec.Collectors, source.Spec.Collectors...)
 		newSpec.Spec.RemoteCollectors = append(newSpec.Spec.RemoteCollectors, source.Spec.RemoteCollectors...)
 		newSpec.Spec.Analyzers = append(newSpec.Spec.Analyzers, source.Spec.Analyzers...)
+		newSpec.Spec.Notifications = append(newSpec.Spec.Notifications, source.Spec.Notifications...)
 	}
 	return newSpec
 }
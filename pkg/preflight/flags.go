@@ -66,7 +66,7 @@ func (f *PreflightFlags) addFlags(flags *flag.FlagSet) {
 		flags.BoolVar(f.Interactive, flagInteractive, *f.Interactive, "interactive preflights")
 	}
 	if f.Format != nil {
-		flags.StringVar(f.Format, flagFormat, *f.Format, "output format, one of human, json, yaml. only used when interactive is set to false")
+		flags.StringVar(f.Format, flagFormat, *f.Format, "output format, one of human, json, yaml, junit, sarif. only used when interactive is set to false")
 	}
 
 	if f.CollectorImage != nil {
@@ -19,12 +19,18 @@ var (
 	isShowingSaved = false
 )
 
-func showInteractiveResults(preflightName string, outputPath string, analyzeResults []*analyzerunner.AnalyzeResult) error {
+// showInteractiveResults shows the results until the user quits, and returns true when the user
+// asks for the checks that failed to be run again, once they are remediated
+func showInteractiveResults(preflightName string, outputPath string, analyzeResults []*analyzerunner.AnalyzeResult) (bool, error) {
 	if err := ui.Init(); err != nil {
-		return errors.Wrap(err, "failed to create terminal ui")
+		return false, errors.Wrap(err, "failed to create terminal ui")
 	}
 	defer ui.Close()
 
+	// the results may be shown again after some of the checks run again
+	selectedResult = 0
+	table.SelectedRow = 0
+
 	drawUI(preflightName, analyzeResults)
 
 	uiEvents := ui.PollEvents()
@@ -33,14 +39,18 @@ func showInteractiveResults(preflightName string, outputPath string, analyzeResu
 		case e := <-uiEvents:
 			switch e.ID {
 			case "<C-c>":
-				return nil
+				return false, nil
 			case "q":
 				if isShowingSaved == true {
 					isShowingSaved = false
 					ui.Clear()
 					drawUI(preflightName, analyzeResults)
 				} else {
-					return nil
+					return false, nil
+				}
+			case "r":
+				if hasFailedResults(analyzeResults) {
+					return true, nil
 				}
 			case "s":
 				filename, err := outputToFile(preflightName, outputPath, analyzeResults)
@@ -86,7 +96,16 @@ func showInteractiveResults(preflightName string, outputPath string, analyzeResu
 func drawUI(preflightName string, analyzeResults []*analyzerunner.AnalyzeResult) {
 	drawGrid(analyzeResults)
 	drawHeader(preflightName)
-	drawFooter()
+	drawFooter(hasFailedResults(analyzeResults))
+}
+
+func hasFailedResults(analyzeResults []*analyzerunner.AnalyzeResult) bool {
+	for _, analyzeResult := range analyzeResults {
+		if analyzeResult.IsFail {
+			return true
+		}
+	}
+	return false
 }
 
 func drawGrid(analyzeResults []*analyzerunner.AnalyzeResult) {
@@ -111,11 +130,14 @@ func drawHeader(preflightName string) {
 	ui.Render(title)
 }
 
-func drawFooter() {
+func drawFooter(canRerun bool) {
 	termWidth, termHeight := ui.TerminalDimensions()
 
 	instructions := widgets.NewParagraph()
 	instructions.Text = "[q] quit    [s] save    [↑][↓] scroll"
+	if canRerun {
+		instructions.Text = "[q] quit    [s] save    [r] re-run failed checks once remediated    [↑][↓] scroll"
+	}
 	instructions.Border = false
 
 	left := 0
@@ -209,6 +231,13 @@ func drawDetails(analysisResult *analyzerunner.AnalyzeResult) {
 		urlText := wordwrap.WrapString(fmt.Sprintf("For more information: %s", analysisResult.URI), uint(termWidth/2-constants.MESSAGE_TEXT_PADDING))
 		message.Text = message.Text + "\n\n" + urlText
 	}
+	if remediation := analyzerunner.RemediationLines(analysisResult.Remediation); len(remediation) > 0 && !analysisResult.IsPass {
+		remediationText := ""
+		for _, line := range remediation {
+			remediationText = remediationText + "\n" + wordwrap.WrapString(line, uint(termWidth/2-constants.MESSAGE_TEXT_PADDING))
+		}
+		message.Text = message.Text + "\n" + remediationText
+	}
 	height = util.EstimateNumberOfLines(message.Text) + constants.MESSAGE_TEXT_LINES_MARGIN_TO_BOTTOM
 	message.Border = false
 	message.SetRect(termWidth/2, currentTop, termWidth, currentTop+height)
@@ -54,6 +54,12 @@ func outputToFile(preflightName string, outputPath string, analyzeResults []*ana
 			result = result + fmt.Sprintf("Strict: %t\n", analyzeResult.Strict)
 		}
 
+		if !analyzeResult.IsPass {
+			for _, line := range analyzerunner.RemediationLines(analyzeResult.Remediation) {
+				result = result + fmt.Sprintf("%s\n", line)
+			}
+		}
+
 		result = result + "\n------------\n"
 
 		results = results + result
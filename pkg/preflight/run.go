@@ -8,18 +8,23 @@ import (
 	"os"
 	"os/signal"
 	"path/filepath"
+	"strings"
 	"time"
 
 	cursor "github.com/ahmetalpbalkan/go-cursor"
 	"github.com/fatih/color"
 	"github.com/pkg/errors"
+	"github.com/replicatedhq/troubleshoot/internal/traces"
 	"github.com/replicatedhq/troubleshoot/internal/util"
 	analyzer "github.com/replicatedhq/troubleshoot/pkg/analyze"
 	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
 	"github.com/replicatedhq/troubleshoot/pkg/collect"
 	"github.com/replicatedhq/troubleshoot/pkg/constants"
 	"github.com/replicatedhq/troubleshoot/pkg/convert"
+	"github.com/replicatedhq/troubleshoot/pkg/facts"
 	"github.com/replicatedhq/troubleshoot/pkg/k8sutil"
+	"github.com/replicatedhq/troubleshoot/pkg/loader"
+	"github.com/replicatedhq/troubleshoot/pkg/notify"
 	"github.com/replicatedhq/troubleshoot/pkg/types"
 	"github.com/replicatedhq/troubleshoot/pkg/version"
 	"github.com/spf13/viper"
@@ -33,6 +38,10 @@ import (
 
 type empty struct{}
 
+// interruptTwiceWithin is how soon after skipping a check with Ctrl-C in interactive mode a
+// second Ctrl-C exits
+const interruptTwiceWithin = 2 * time.Second
+
 func RunPreflights(interactive bool, output string, format string, args []string) error {
 	ctx, root := otel.Tracer(
 		constants.LIB_TRACER_NAME).Start(context.Background(), constants.TROUBLESHOOT_ROOT_SPAN_NAME)
@@ -43,12 +52,23 @@ func RunPreflights(interactive bool, output string, format string, args []string
 		defer fmt.Print(cursor.Show())
 	}
 
+	skipper := NewCollectorSkipper()
 	go func() {
 		signalChan := make(chan os.Signal, 1)
 		signal.Notify(signalChan, os.Interrupt)
-		<-signalChan
-		// exiting due to a signal shouldn't be considered successful
-		os.Exit(1)
+		var skippedAt time.Time
+		for range signalChan {
+			// in interactive mode, Ctrl-C skips the running check, and exits when pressed twice
+			if interactive && time.Since(skippedAt) > interruptTwiceWithin && skipper.Skip() {
+				skippedAt = time.Now()
+				continue
+			}
+			if interactive {
+				fmt.Print(cursor.Show())
+			}
+			// exiting due to a signal shouldn't be considered successful
+			os.Exit(1)
+		}
 	}()
 
 	specs, err := readSpecs(args)
@@ -82,23 +102,117 @@ func RunPreflights(interactive bool, output string, format string, args []string
 		return nil
 	}
 
+	notifications, err := notify.NotificationsFromFlags(viper.GetViper())
+	if err != nil {
+		return types.NewExitCodeError(constants.EXIT_CODE_CATCH_ALL, err)
+	}
+	for _, spec := range specs.PreflightsV1Beta2 {
+		notifications = append(notifications, spec.Spec.Notifications...)
+	}
+	for _, spec := range specs.HostPreflightsV1Beta2 {
+		notifications = append(notifications, spec.Spec.Notifications...)
+	}
+
+	archivePath := fmt.Sprintf("preflightbundle-%s.tar.gz", time.Now().Format("2006-01-02T15_04_05"))
+
+	// In interactive mode, the checks that failed can be run again once they are remediated, with
+	// the data collected again. The results of the other checks are kept.
+	var run *preflightRun
+	var previousChecks []*preflightCheck
+	for {
+		run, err = runPreflightChecks(ctx, specs, interactive, skipper, archivePath, previousChecks)
+		if err != nil {
+			return err
+		}
+		if len(run.results) == 0 {
+			return types.NewExitCodeError(constants.EXIT_CODE_CATCH_ALL, errors.New("completed with no analysis results"))
+		}
+		if !interactive {
+			break
+		}
+
+		rerun, err := showInteractiveResults(run.specName, output, run.results)
+		if err != nil {
+			return err
+		}
+		if !rerun {
+			break
+		}
+		previousChecks = run.checks
+	}
+	defer fmt.Fprintf(os.Stderr, "\nSaving preflight bundle to %s\n", archivePath)
+
+	if len(notifications) > 0 {
+		summary := notify.NewSummary(notify.KindPreflight, run.specName, run.results)
+		summary.BundlePath = archivePath
+		summary.Uploaded = run.uploaded
+		if err := notify.Send(ctx, notifications, summary); err != nil {
+			fmt.Fprintf(os.Stderr, "error - %v\n", err)
+		}
+	}
+
+	if !interactive {
+		if err := showTextResults(format, run.specName, output, run.results); err != nil {
+			return err
+		}
+	}
+
+	exitCode := checkOutcomesToExitCode(run.results)
+
+	if exitCode == 0 {
+		return nil
+	}
+
+	return types.NewExitCodeError(exitCode, errors.New("preflights failed with warnings or errors"))
+}
+
+// preflightCheck is an analyzer of the specs and its results, so that the checks that failed can
+// be run again. Exactly one of spec and hostSpec is set.
+type preflightCheck struct {
+	spec     *troubleshootv1beta2.Analyze
+	hostSpec *troubleshootv1beta2.HostAnalyze
+	results  []*analyzer.AnalyzeResult
+}
+
+func (c *preflightCheck) failed() bool {
+	for _, result := range c.results {
+		if result.IsFail {
+			return true
+		}
+	}
+	return false
+}
+
+// preflightRun is a collection and analysis of the specs
+type preflightRun struct {
+	specName string
+	checks   []*preflightCheck
+	results  []*analyzer.AnalyzeResult
+	uploaded bool
+}
+
+// runPreflightChecks collects the data of the specs, analyzes it and archives the preflight
+// bundle. When previousChecks are set, only the checks that failed in them are analyzed, and the
+// others keep their results.
+func runPreflightChecks(
+	ctx context.Context, specs *loader.TroubleshootKinds, interactive bool, skipper *CollectorSkipper,
+	archivePath string, previousChecks []*preflightCheck,
+) (*preflightRun, error) {
 	var collectResults []CollectResult
 	var uploadCollectResults []CollectResult
-	preflightSpecName := ""
+	run := &preflightRun{}
 
 	// Create a temporary directory to save the preflight bundle
 	tmpDir, err := os.MkdirTemp("", "preflightbundle-")
 	if err != nil {
-		return errors.Wrap(err, "create temp dir for preflightbundle")
+		return nil, errors.Wrap(err, "create temp dir for preflightbundle")
 	}
 	defer os.RemoveAll(tmpDir)
-	bundleFileName := fmt.Sprintf("preflightbundle-%s", time.Now().Format("2006-01-02T15_04_05"))
-	bundlePath := filepath.Join(tmpDir, bundleFileName)
+	bundlePath := filepath.Join(tmpDir, strings.TrimSuffix(archivePath, ".tar.gz"))
 	if err := os.MkdirAll(bundlePath, 0777); err != nil {
-		return errors.Wrap(err, "failed to create preflight bundle dir")
+		return nil, errors.Wrap(err, "failed to create preflight bundle dir")
 	}
 
-	archivePath := fmt.Sprintf("%s.tar.gz", bundleFileName)
 	klog.V(2).Infof("Preflight data collected in temporary directory: %s", tmpDir)
 
 	progressCh := make(chan interface{})
@@ -117,17 +231,15 @@ func RunPreflights(interactive bool, output string, format string, args []string
 
 	uploadResultsMap := make(map[string]empty)
 	collectorResults := collect.NewResult()
-	analyzers := []*troubleshootv1beta2.Analyze{}
-	hostAnalyzers := []*troubleshootv1beta2.HostAnalyze{}
 
 	for _, spec := range specs.PreflightsV1Beta2 {
-		r, err := collectInCluster(ctx, &spec, progressCh, bundlePath)
+		r, err := collectInCluster(ctx, &spec, progressCh, bundlePath, skipper)
 		if err != nil {
-			return types.NewExitCodeError(constants.EXIT_CODE_CATCH_ALL, errors.Wrap(err, "failed to collect in cluster"))
+			return nil, types.NewExitCodeError(constants.EXIT_CODE_CATCH_ALL, errors.Wrap(err, "failed to collect in cluster"))
 		}
 		collectorResult, ok := (*r).(ClusterCollectResult)
 		if !ok {
-			return errors.Errorf("unexpected result type: %T", collectResults)
+			return nil, errors.Errorf("unexpected result type: %T", collectResults)
 		}
 		collectorResults.AddResult(collect.CollectorResult(collectorResult.AllCollectedData))
 
@@ -138,60 +250,78 @@ func RunPreflights(interactive bool, output string, format string, args []string
 			collectResults = append(collectResults, *r)
 		}
 		// TODO: This spec name will be overwritten by the next spec. Is this intentional?
-		preflightSpecName = spec.Name
-		analyzers = append(analyzers, spec.Spec.Analyzers...)
+		run.specName = spec.Name
+		// the exclude templates of the analyzers are evaluated against the facts of the collection
+		specAnalyzers, err := facts.ResolveAnalyzerExcludes(spec.Spec.Analyzers, collectorResult.facts)
+		if err != nil {
+			progressCh <- err
+		}
+		for _, specAnalyzer := range specAnalyzers {
+			run.checks = append(run.checks, &preflightCheck{spec: specAnalyzer})
+		}
 	}
 
 	for _, spec := range specs.HostPreflightsV1Beta2 {
 		if len(spec.Spec.Collectors) > 0 {
-			r, err := collectHost(ctx, &spec, progressCh, bundlePath)
+			r, err := collectHost(ctx, &spec, progressCh, bundlePath, skipper)
 			if err != nil {
-				return types.NewExitCodeError(constants.EXIT_CODE_CATCH_ALL, errors.Wrap(err, "failed to collect from host"))
+				return nil, types.NewExitCodeError(constants.EXIT_CODE_CATCH_ALL, errors.Wrap(err, "failed to collect from host"))
 			}
 			collectResults = append(collectResults, *r)
 			collectorResult, ok := (*r).(HostCollectResult)
 			if !ok {
-				return errors.Errorf("unexpected result type: %T", collectResults)
+				return nil, errors.Errorf("unexpected result type: %T", collectResults)
 			}
 			collectorResults.AddResult(collect.CollectorResult(collectorResult.AllCollectedData))
 		}
 		if len(spec.Spec.RemoteCollectors) > 0 {
 			r, err := collectRemote(ctx, &spec, progressCh)
 			if err != nil {
-				return types.NewExitCodeError(constants.EXIT_CODE_CATCH_ALL, errors.Wrap(err, "failed to collect remotely"))
+				return nil, types.NewExitCodeError(constants.EXIT_CODE_CATCH_ALL, errors.Wrap(err, "failed to collect remotely"))
 			}
 			collectResults = append(collectResults, *r)
 			collectorResult, ok := (*r).(RemoteCollectResult)
 			if !ok {
-				return errors.Errorf("unexpected result type: %T", collectResults)
+				return nil, errors.Errorf("unexpected result type: %T", collectResults)
 			}
 			collectorResults.AddResult(collect.CollectorResult(collectorResult.AllCollectedData))
 		}
-		preflightSpecName = spec.Name
-		hostAnalyzers = append(hostAnalyzers, spec.Spec.Analyzers...)
+		run.specName = spec.Name
+		for _, hostAnalyzer := range spec.Spec.Analyzers {
+			run.checks = append(run.checks, &preflightCheck{hostSpec: hostAnalyzer})
+		}
 	}
 
 	if len(collectResults) == 0 && len(uploadCollectResults) == 0 {
-		return types.NewExitCodeError(constants.EXIT_CODE_CATCH_ALL, errors.New("no data was collected"))
+		return nil, types.NewExitCodeError(constants.EXIT_CODE_CATCH_ALL, errors.New("no data was collected"))
 	}
 
 	err = saveTSVersionToBundle(collectorResults, bundlePath)
 	if err != nil {
-		return errors.Wrap(err, "failed to save version file")
+		return nil, errors.Wrap(err, "failed to save version file")
 	}
 
-	analyzeResults, err := analyzer.AnalyzeLocal(ctx, bundlePath, analyzers, hostAnalyzers)
-	if err != nil {
-		return errors.Wrap(err, "failed to analyze support bundle")
+	if err := analyzeChecks(ctx, bundlePath, run.checks, previousChecks); err != nil {
+		return nil, errors.Wrap(err, "failed to analyze support bundle")
+	}
+	for _, check := range run.checks {
+		run.results = append(run.results, check.results...)
 	}
-	err = saveAnalysisResultsToBundle(collectorResults, analyzeResults, bundlePath)
+	err = saveAnalysisResultsToBundle(collectorResults, run.results, bundlePath)
 	if err != nil {
-		return errors.Wrap(err, "failed to save analysis results to bundle")
+		return nil, errors.Wrap(err, "failed to save analysis results to bundle")
+	}
+
+	if viper.GetBool("embed-traces") {
+		if err := saveTracesToBundle(collectorResults, bundlePath); err != nil {
+			// Don't fail the preflights if the traces can't be saved
+			progressCh <- errors.Wrap(err, "failed to save traces to bundle")
+		}
 	}
 
 	uploadAnalyzeResultsMap := make(map[string][]*analyzer.AnalyzeResult)
 	for location := range uploadResultsMap {
-		uploadAnalyzeResultsMap[location] = append(uploadAnalyzeResultsMap[location], analyzeResults...)
+		uploadAnalyzeResultsMap[location] = append(uploadAnalyzeResultsMap[location], run.results...)
 	}
 
 	for k, v := range uploadAnalyzeResultsMap {
@@ -200,37 +330,43 @@ func RunPreflights(interactive bool, output string, format string, args []string
 			progressCh <- err
 		}
 	}
+	run.uploaded = len(uploadResultsMap) > 0
 
 	// Archive preflight bundle
 	if err := collectorResults.ArchiveBundle(bundlePath, archivePath); err != nil {
-		return errors.Wrapf(err, "failed to create %s archive", archivePath)
+		return nil, errors.Wrapf(err, "failed to create %s archive", archivePath)
 	}
-	defer fmt.Fprintf(os.Stderr, "\nSaving preflight bundle to %s\n", archivePath)
 
 	stopProgressCollection()
 	progressCollection.Wait()
 
-	if len(analyzeResults) == 0 {
-		return types.NewExitCodeError(constants.EXIT_CODE_CATCH_ALL, errors.New("completed with no analysis results"))
-	}
-
-	if interactive {
-		err = showInteractiveResults(preflightSpecName, output, analyzeResults)
-	} else {
-		err = showTextResults(format, preflightSpecName, output, analyzeResults)
-	}
-
-	if err != nil {
-		return err
-	}
+	return run, nil
+}
 
-	exitCode := checkOutcomesToExitCode(analyzeResults)
+// analyzeChecks runs the analyzers of the checks one at a time, so that their results can be
+// told apart. When previousChecks are set, the checks that did not fail in them keep their
+// results, the checks are the same when the specs are.
+func analyzeChecks(ctx context.Context, bundlePath string, checks []*preflightCheck, previousChecks []*preflightCheck) error {
+	for i, check := range checks {
+		if i < len(previousChecks) && !previousChecks[i].failed() {
+			check.results = previousChecks[i].results
+			continue
+		}
 
-	if exitCode == 0 {
-		return nil
+		var analyzers []*troubleshootv1beta2.Analyze
+		var hostAnalyzers []*troubleshootv1beta2.HostAnalyze
+		if check.spec != nil {
+			analyzers = append(analyzers, check.spec)
+		} else {
+			hostAnalyzers = append(hostAnalyzers, check.hostSpec)
+		}
+		results, err := analyzer.AnalyzeLocal(ctx, bundlePath, analyzers, hostAnalyzers)
+		if err != nil {
+			return err
+		}
+		check.results = results
 	}
-
-	return types.NewExitCodeError(exitCode, errors.New("preflights failed with warnings or errors"))
+	return nil
 }
 
 func saveAnalysisResultsToBundle(
@@ -264,6 +400,16 @@ func saveTSVersionToBundle(results collect.CollectorResult, bundlePath string) e
 	return nil
 }
 
+// saveTracesToBundle saves the spans that have ended so far, those of the collectors and analyzers
+func saveTracesToBundle(results collect.CollectorResult, bundlePath string) error {
+	spans, err := traces.GetExporterInstance().GetSpans()
+	if err != nil {
+		return err
+	}
+
+	return results.SaveResult(bundlePath, constants.TRACES_FILENAME, bytes.NewReader(spans))
+}
+
 // Determine if any preflight checks passed vs failed vs warned
 // If all checks passed: 0
 // If 1 or more checks failed: 3
@@ -285,13 +431,17 @@ func checkOutcomesToExitCode(analyzeResults []*analyzer.AnalyzeResult) int {
 	return exitCode
 }
 
+// collectInteractiveProgress shows the checks as they run, and how to skip the one running
 func collectInteractiveProgress(ctx context.Context, progressCh <-chan interface{}) func() error {
 	return func() error {
 		spinner := spin.New()
 		lastMsg := ""
+		running := ""
 
 		errorTxt := color.New(color.FgHiRed)
 		infoTxt := color.New(color.FgCyan)
+		passTxt := color.New(color.FgGreen)
+		skipTxt := color.New(color.FgYellow)
 
 		for {
 			select {
@@ -305,10 +455,25 @@ func collectInteractiveProgress(ctx context.Context, progressCh <-chan interface
 					}
 					lastMsg = msg
 					infoTxt.Printf("%s\r * %s\n", cursor.ClearEntireLine(), msg)
-
+				case CollectProgress:
+					running = ""
+					switch msg.CurrentStatus {
+					case "running":
+						running = fmt.Sprintf("[%d/%d] %s", msg.CompletedCount+1, msg.TotalCount, msg.CurrentName)
+					case "completed":
+						passTxt.Printf("%s\r ✔ %s\n", cursor.ClearEntireLine(), msg.CurrentName)
+					case "failed":
+						errorTxt.Printf("%s\r ✘ %s\n", cursor.ClearEntireLine(), msg.CurrentName)
+					case "skipped":
+						skipTxt.Printf("%s\r - %s skipped\n", cursor.ClearEntireLine(), msg.CurrentName)
+					}
 				}
 			case <-time.After(time.Millisecond * 100):
-				fmt.Printf("\r  %s %s ", color.CyanString("Running Preflight Checks"), spinner.Next())
+				if running == "" {
+					fmt.Printf("%s\r  %s %s ", cursor.ClearEntireLine(), color.CyanString("Running Preflight Checks"), spinner.Next())
+				} else {
+					fmt.Printf("%s\r  %s %s %s %s", cursor.ClearEntireLine(), color.CyanString("Running Preflight Checks"), spinner.Next(), running, color.HiBlackString("(Ctrl-C to skip)"))
+				}
 			case <-ctx.Done():
 				fmt.Printf("\r%s\r", cursor.ClearEntireLine())
 				return nil
@@ -339,7 +504,7 @@ func collectNonInteractiveProgess(ctx context.Context, progressCh <-chan interfa
 }
 
 func collectInCluster(
-	ctx context.Context, preflightSpec *troubleshootv1beta2.Preflight, progressCh chan interface{}, bundlePath string,
+	ctx context.Context, preflightSpec *troubleshootv1beta2.Preflight, progressCh chan interface{}, bundlePath string, skipper *CollectorSkipper,
 ) (*CollectResult, error) {
 	v := viper.GetViper()
 
@@ -354,6 +519,7 @@ func collectInCluster(
 		ProgressChan:           progressCh,
 		KubernetesRestConfig:   restConfig,
 		BundlePath:             bundlePath,
+		Skipper:                skipper,
 	}
 
 	if v.GetString("since") != "" || v.GetString("since-time") != "" {
@@ -423,11 +589,12 @@ func collectRemote(_ context.Context, preflightSpec *troubleshootv1beta2.HostPre
 }
 
 func collectHost(
-	_ context.Context, hostPreflightSpec *troubleshootv1beta2.HostPreflight, progressCh chan interface{}, bundlePath string,
+	_ context.Context, hostPreflightSpec *troubleshootv1beta2.HostPreflight, progressCh chan interface{}, bundlePath string, skipper *CollectorSkipper,
 ) (*CollectResult, error) {
 	collectOpts := CollectOpts{
 		ProgressChan: progressCh,
 		BundlePath:   bundlePath,
+		Skipper:      skipper,
 	}
 
 	collectResults, err := CollectHost(collectOpts, hostPreflightSpec)
@@ -0,0 +1,123 @@
+package preflight
+
+import (
+	"context"
+	"sync"
+)
+
+// CollectorSkipper lets the collector that is running be skipped, such as a check that takes too
+// long in interactive mode. Collectors are created with a context of the skipper that is canceled
+// for the running collector only, so that it stops and cleans up what it created as it does when
+// the whole collection is canceled, and the next collectors run.
+type CollectorSkipper struct {
+	mu  sync.Mutex
+	ctx *skippableContext
+}
+
+func NewCollectorSkipper() *CollectorSkipper {
+	return &CollectorSkipper{}
+}
+
+// Skip skips the running collector, and returns false when no collector is running
+func (s *CollectorSkipper) Skip() bool {
+	s.mu.Lock()
+	ctx := s.ctx
+	s.mu.Unlock()
+
+	if ctx == nil {
+		return false
+	}
+	return ctx.skip()
+}
+
+// context returns the context to create the collectors of a collection with
+func (s *CollectorSkipper) context(parent context.Context) *skippableContext {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.ctx = newSkippableContext(parent)
+	return s.ctx
+}
+
+// skippableContext is done when its parent is, or when the collector running is skipped, until
+// the next collector runs
+type skippableContext struct {
+	context.Context
+
+	mu      sync.Mutex
+	done    chan struct{}
+	err     error
+	running bool
+	skipped bool
+}
+
+func newSkippableContext(parent context.Context) *skippableContext {
+	c := &skippableContext{Context: parent, done: make(chan struct{})}
+	context.AfterFunc(parent, func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		c.cancel(parent.Err())
+	})
+	return c
+}
+
+func (c *skippableContext) Done() <-chan struct{} {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.done
+}
+
+func (c *skippableContext) Err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.err
+}
+
+// start is called before a collector runs, to give it a context that is not done when the
+// previous collector was skipped. start and finish do nothing on a nil context, for collections
+// without a skipper.
+func (c *skippableContext) start() {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.err != nil && c.Context.Err() == nil {
+		c.done, c.err = make(chan struct{}), nil
+	}
+	c.running, c.skipped = true, false
+}
+
+// finish is called after a collector ran, and returns true when it was skipped
+func (c *skippableContext) finish() bool {
+	if c == nil {
+		return false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.running = false
+	return c.skipped
+}
+
+func (c *skippableContext) skip() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.running || c.err != nil {
+		return false
+	}
+	c.skipped = true
+	c.cancel(context.Canceled)
+	return true
+}
+
+// cancel closes the done channel, with the lock held
+func (c *skippableContext) cancel(err error) {
+	if c.err != nil {
+		return
+	}
+	c.err = err
+	close(c.done)
+}
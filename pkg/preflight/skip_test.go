@@ -0,0 +1,58 @@
+package preflight
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func isDone(ctx context.Context) bool {
+	select {
+	case <-ctx.Done():
+		return true
+	default:
+		return false
+	}
+}
+
+func TestCollectorSkipper(t *testing.T) {
+	parent, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	skipper := NewCollectorSkipper()
+	assert.False(t, skipper.Skip(), "no collection")
+
+	ctx := skipper.context(parent)
+	assert.False(t, skipper.Skip(), "no collector running")
+
+	ctx.start()
+	child, cancelChild := context.WithCancel(ctx)
+	defer cancelChild()
+	assert.False(t, isDone(ctx))
+	assert.True(t, skipper.Skip())
+	assert.False(t, skipper.Skip(), "already skipped")
+	assert.True(t, isDone(ctx))
+	assert.Equal(t, context.Canceled, ctx.Err())
+	<-child.Done()
+	assert.True(t, ctx.finish())
+	assert.NoError(t, parent.Err())
+
+	// the next collector runs
+	ctx.start()
+	assert.False(t, isDone(ctx))
+	assert.NoError(t, ctx.Err())
+	assert.False(t, ctx.finish())
+
+	cancel()
+	<-ctx.Done()
+	ctx.start()
+	assert.True(t, isDone(ctx), "the collection is canceled")
+	assert.False(t, skipper.Skip())
+}
+
+func TestSkippableContext_nil(t *testing.T) {
+	var ctx *skippableContext
+	ctx.start()
+	assert.False(t, ctx.finish())
+}
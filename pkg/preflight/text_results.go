@@ -7,7 +7,10 @@ import (
 
 	"github.com/pkg/errors"
 	analyzerunner "github.com/replicatedhq/troubleshoot/pkg/analyze"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/convert"
 	"gopkg.in/yaml.v2"
+	corev1 "k8s.io/api/core/v1"
 )
 
 // Text results can go to stdout or to an output file
@@ -21,6 +24,10 @@ func showTextResults(format string, preflightName string, outputPath string, ana
 		results, err = showTextResultsJSON(preflightName, analyzeResults)
 	} else if format == "yaml" {
 		results, err = showTextResultsYAML(preflightName, analyzeResults)
+	} else if format == "junit" {
+		results, err = showTextResultsJUnit(preflightName, analyzeResults)
+	} else if format == "sarif" {
+		results, err = showTextResultsSARIF(preflightName, analyzeResults)
 	} else {
 		return errors.Errorf("unknown output format: %q", format)
 	}
@@ -64,14 +71,26 @@ func showTextResultsHuman(preflightName string, analyzeResults []*analyzerunner.
 	return results, nil
 }
 
+// TextOutputSchemaVersion is the version of the JSON and YAML output. Fields are only added
+// within a version, a removed or changed field increments it.
+const TextOutputSchemaVersion = "v1"
+
 type TextResultOutput struct {
-	Title   string `json:"title" yaml:"title"`
-	Message string `json:"message" yaml:"message"`
-	URI     string `json:"uri,omitempty" yaml:"uri,omitempty"`
-	Strict  bool   `json:"strict,omitempty" yaml:"strict,omitempty"`
+	Title          string                           `json:"title" yaml:"title"`
+	Message        string                           `json:"message" yaml:"message"`
+	URI            string                           `json:"uri,omitempty" yaml:"uri,omitempty"`
+	Strict         bool                             `json:"strict,omitempty" yaml:"strict,omitempty"`
+	Remediation    *troubleshootv1beta2.Remediation `json:"remediation,omitempty" yaml:"remediation,omitempty"`
+	Severity       analyzerunner.Severity           `json:"severity,omitempty" yaml:"severity,omitempty"`
+	Categories     []string                         `json:"categories,omitempty" yaml:"categories,omitempty"`
+	Condition      string                           `json:"condition,omitempty" yaml:"condition,omitempty"`
+	Measurement    *analyzerunner.Measurement       `json:"measurement,omitempty" yaml:"measurement,omitempty"`
+	InvolvedObject *corev1.ObjectReference          `json:"involvedObject,omitempty" yaml:"involvedObject,omitempty"`
 }
 
 type TextOutput struct {
+	SchemaVersion string `json:"schemaVersion" yaml:"schemaVersion"`
+
 	Pass []TextResultOutput `json:"pass,omitempty" yaml:"pass,omitempty"`
 	Warn []TextResultOutput `json:"warn,omitempty" yaml:"warn,omitempty"`
 	Fail []TextResultOutput `json:"fail,omitempty" yaml:"fail,omitempty"`
@@ -80,6 +99,8 @@ type TextOutput struct {
 // Used by both JSON and YAML outputs
 func ShowTextResultsStructured(preflightName string, analyzeResults []*analyzerunner.AnalyzeResult) *TextOutput {
 	output := TextOutput{
+		SchemaVersion: TextOutputSchemaVersion,
+
 		Pass: []TextResultOutput{},
 		Warn: []TextResultOutput{},
 		Fail: []TextResultOutput{},
@@ -87,9 +108,15 @@ func ShowTextResultsStructured(preflightName string, analyzeResults []*analyzeru
 
 	for _, analyzeResult := range analyzeResults {
 		resultOutput := TextResultOutput{
-			Title:   analyzeResult.Title,
-			Message: analyzeResult.Message,
-			URI:     analyzeResult.URI,
+			Title:          analyzeResult.Title,
+			Message:        analyzeResult.Message,
+			URI:            analyzeResult.URI,
+			Remediation:    analyzeResult.Remediation,
+			Severity:       analyzeResult.Severity,
+			Categories:     analyzeResult.Categories,
+			Condition:      analyzeResult.Condition,
+			Measurement:    analyzeResult.Measurement,
+			InvolvedObject: analyzeResult.InvolvedObject,
 		}
 
 		if analyzeResult.Strict {
@@ -130,6 +157,24 @@ func showTextResultsYAML(preflightName string, analyzeResults []*analyzerunner.A
 	return fmt.Sprintf("%s\n", b), nil
 }
 
+func showTextResultsJUnit(preflightName string, analyzeResults []*analyzerunner.AnalyzeResult) (string, error) {
+	b, err := convert.ToJUnit(preflightName, analyzeResults)
+	if err != nil {
+		return "", err
+	}
+
+	return string(b), nil
+}
+
+func showTextResultsSARIF(preflightName string, analyzeResults []*analyzerunner.AnalyzeResult) (string, error) {
+	b, err := convert.ToSARIF(preflightName, analyzeResults)
+	if err != nil {
+		return "", err
+	}
+
+	return string(b), nil
+}
+
 func outputResult(results string, analyzeResult *analyzerunner.AnalyzeResult) (string, bool) {
 	if analyzeResult.IsPass {
 		results = fmt.Sprintf("%s   --- PASS %s\n", results, analyzeResult.Title)
@@ -146,6 +191,12 @@ func outputResult(results string, analyzeResult *analyzerunner.AnalyzeResult) (s
 		results = fmt.Sprintf("%s      --- Strict: %t\n", results, analyzeResult.Strict)
 	}
 
+	if !analyzeResult.IsPass {
+		for _, line := range analyzerunner.RemediationLines(analyzeResult.Remediation) {
+			results = fmt.Sprintf("%s      --- %s\n", results, line)
+		}
+	}
+
 	if analyzeResult.IsFail {
 		return results, true
 	}
@@ -0,0 +1,103 @@
+package preflight
+
+import (
+	"encoding/json"
+	"testing"
+
+	analyzerunner "github.com/replicatedhq/troubleshoot/pkg/analyze"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShowTextResultsStructured_Remediation(t *testing.T) {
+	remediation := &troubleshootv1beta2.Remediation{
+		Command:    "sudo swapoff -a",
+		Severity:   troubleshootv1beta2.RemediationSeverityHigh,
+		Automation: troubleshootv1beta2.RemediationAutomationApproval,
+	}
+
+	output := ShowTextResultsStructured("test", []*analyzerunner.AnalyzeResult{
+		{IsFail: true, Title: "Swap", Message: "Swap is enabled", Remediation: remediation},
+		{IsPass: true, Title: "CPU", Message: "Enough CPU"},
+	})
+
+	require.Len(t, output.Fail, 1)
+	assert.Equal(t, remediation, output.Fail[0].Remediation)
+	require.Len(t, output.Pass, 1)
+	assert.Nil(t, output.Pass[0].Remediation)
+}
+
+func TestOutputResult_Remediation(t *testing.T) {
+	results, failed := outputResult("", &analyzerunner.AnalyzeResult{
+		IsFail:  true,
+		Title:   "Swap",
+		Message: "Swap is enabled",
+		Remediation: &troubleshootv1beta2.Remediation{
+			Command: "sudo swapoff -a",
+			DocLink: "https://troubleshoot.sh/docs/host-collect-analyze/memory/",
+		},
+	})
+
+	assert.True(t, failed)
+	assert.Equal(t, "   --- FAIL: Swap\n"+
+		"      --- Swap is enabled\n"+
+		"      --- To fix, run: sudo swapoff -a\n"+
+		"      --- Documentation: https://troubleshoot.sh/docs/host-collect-analyze/memory/\n", results)
+}
+
+func TestShowTextResultsJSON_Schema(t *testing.T) {
+	threshold := float64(8)
+	results, err := showTextResultsJSON("test", []*analyzerunner.AnalyzeResult{
+		{
+			IsFail:      true,
+			Strict:      true,
+			Title:       "Number of CPUs",
+			Message:     "At least 8 CPUs are required",
+			Severity:    analyzerunner.SeverityCritical,
+			Categories:  []string{"resources"},
+			Condition:   "count < 8",
+			Measurement: &analyzerunner.Measurement{Observed: 4, Threshold: &threshold, Unit: "cpus"},
+		},
+	})
+	require.NoError(t, err)
+
+	output := map[string]interface{}{}
+	require.NoError(t, json.Unmarshal([]byte(results), &output))
+	assert.Equal(t, TextOutputSchemaVersion, output["schemaVersion"])
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{
+			"title":      "Number of CPUs",
+			"message":    "At least 8 CPUs are required",
+			"strict":     true,
+			"severity":   "critical",
+			"categories": []interface{}{"resources"},
+			"condition":  "count < 8",
+			"measurement": map[string]interface{}{
+				"observed":  float64(4),
+				"threshold": float64(8),
+				"unit":      "cpus",
+			},
+		},
+	}, output["fail"])
+}
+
+func TestShowTextResultsSARIF_ToolName(t *testing.T) {
+	results, err := showTextResultsSARIF("my-app-preflight", []*analyzerunner.AnalyzeResult{
+		{IsFail: true, Title: "Swap", Message: "Swap is enabled"},
+	})
+	require.NoError(t, err)
+
+	var log struct {
+		Runs []struct {
+			Tool struct {
+				Driver struct {
+					Name string `json:"name"`
+				} `json:"driver"`
+			} `json:"tool"`
+		} `json:"runs"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(results), &log))
+	require.Len(t, log.Runs, 1)
+	assert.Equal(t, "my-app-preflight", log.Runs[0].Tool.Driver.Name)
+}
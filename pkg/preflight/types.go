@@ -1,5 +1,7 @@
 package preflight
 
+import troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+
 type UploadPreflightResult struct {
 	Strict bool `json:"strict,omitempty"`
 	IsFail bool `json:"isFail,omitempty"`
@@ -9,6 +11,8 @@ type UploadPreflightResult struct {
 	Title   string `json:"title"`
 	Message string `json:"message"`
 	URI     string `json:"uri,omitempty"`
+
+	Remediation *troubleshootv1beta2.Remediation `json:"remediation,omitempty"`
 }
 
 type UploadPreflightError struct {
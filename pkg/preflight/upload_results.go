@@ -16,13 +16,14 @@ func uploadResults(uri string, analyzeResults []*analyzerunner.AnalyzeResult) er
 	}
 	for _, analyzeResult := range analyzeResults {
 		uploadPreflightResult := &UploadPreflightResult{
-			Strict:  analyzeResult.Strict,
-			IsFail:  analyzeResult.IsFail,
-			IsWarn:  analyzeResult.IsWarn,
-			IsPass:  analyzeResult.IsPass,
-			Title:   analyzeResult.Title,
-			Message: analyzeResult.Message,
-			URI:     analyzeResult.URI,
+			Strict:      analyzeResult.Strict,
+			IsFail:      analyzeResult.IsFail,
+			IsWarn:      analyzeResult.IsWarn,
+			IsPass:      analyzeResult.IsPass,
+			Title:       analyzeResult.Title,
+			Message:     analyzeResult.Message,
+			URI:         analyzeResult.URI,
+			Remediation: analyzeResult.Remediation,
 		}
 
 		uploadPreflightResults.Results = append(uploadPreflightResults.Results, uploadPreflightResult)
@@ -15,17 +15,22 @@ type literalRedactor struct {
 	filePath   string
 	redactName string
 	isDefault  bool
+	tokens     *Tokenizer
 }
 
 func literalString(match []byte, path, name string) Redactor {
-	return literalRedactor{
+	return &literalRedactor{
 		match:      match,
 		filePath:   path,
 		redactName: name,
 	}
 }
 
-func (r literalRedactor) Redact(input io.Reader, path string) io.Reader {
+func (r *literalRedactor) setTokenizer(tokens *Tokenizer) {
+	r.tokens = tokens
+}
+
+func (r *literalRedactor) Redact(input io.Reader, path string) io.Reader {
 	out, writer := io.Pipe()
 
 	go func() {
@@ -53,7 +58,7 @@ func (r literalRedactor) Redact(input io.Reader, path string) io.Reader {
 			lineNum++
 			line := scanner.Bytes()
 
-			clean := bytes.ReplaceAll(line, r.match, maskTextBytes)
+			clean := bytes.ReplaceAll(line, r.match, r.tokens.mask(r.match))
 
 			// Append newline since scanner strips it
 			err = writeBytes(writer, clean, NEW_LINE)
@@ -15,6 +15,7 @@ type MultiLineRedactor struct {
 	filePath   string
 	redactName string
 	isDefault  bool
+	tokens     *Tokenizer
 }
 
 func NewMultiLineRedactor(re1 LineRedactor, re2 string, maskText, path, name string, isDefault bool) (*MultiLineRedactor, error) {
@@ -39,6 +40,10 @@ func NewMultiLineRedactor(re1 LineRedactor, re2 string, maskText, path, name str
 	return &MultiLineRedactor{scan: scanCompiled, re1: compiled1, re2: compiled2, maskText: maskText, filePath: path, redactName: name, isDefault: isDefault}, nil
 }
 
+func (r *MultiLineRedactor) setTokenizer(tokens *Tokenizer) {
+	r.tokens = tokens
+}
+
 func (r *MultiLineRedactor) Redact(input io.Reader, path string) io.Reader {
 	out, writer := io.Pipe()
 	go func() {
@@ -94,7 +99,7 @@ func (r *MultiLineRedactor) Redact(input io.Reader, path string) io.Reader {
 				continue
 			}
 			flushLastLine = false
-			clean := r.re2.ReplaceAll(line2, substStr)
+			clean := r.tokens.replaceMasked(r.re2, line2, substStr)
 
 			// Append newlines since scanner strips them
 			err = writeBytes(writer, line1, NEW_LINE, clean, NEW_LINE)
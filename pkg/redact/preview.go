@@ -0,0 +1,118 @@
+package redact
+
+import (
+	"bytes"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"github.com/pmezard/go-difflib/difflib"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+)
+
+// RedactionPreview is what redacting a file would change. Nothing is written when previewing.
+type RedactionPreview struct {
+	// File is the path the redactors matched, relative to the directory previewed
+	File string `json:"file"`
+	// Diff is a unified diff of the file before and after redaction, empty when nothing would be
+	// redacted
+	Diff string `json:"diff,omitempty"`
+	// Redacted is the content of the file once redacted
+	Redacted []byte `json:"-"`
+}
+
+// IsRedacted returns true when redacting the file changes it
+func (p *RedactionPreview) IsRedacted() bool {
+	return p.Diff != ""
+}
+
+// PreviewRedaction redacts the content of a file with the default redactors and the additional
+// redactors, as if it was collected at path, and returns the difference. It lets redactors be
+// tried on sample files without running a collection.
+func PreviewRedaction(content []byte, path string, additionalRedactors []*troubleshootv1beta2.Redact) (*RedactionPreview, error) {
+	preview := &RedactionPreview{File: path, Redacted: content}
+	if len(content) == 0 {
+		return preview, nil
+	}
+
+	reader, err := Redact(bytes.NewReader(content), path, additionalRedactors)
+	if err != nil {
+		return nil, err
+	}
+	redacted, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to redact %s", path)
+	}
+	// Redactors end every line with a newline, and may add empty lines at the end of the file,
+	// which are not changes worth showing
+	trailing := content[len(bytes.TrimRight(content, "\n")):]
+	redacted = append(bytes.TrimRight(redacted, "\n"), trailing...)
+	preview.Redacted = redacted
+
+	if bytes.Equal(content, redacted) {
+		return preview, nil
+	}
+	preview.Diff, err = difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(string(content)),
+		B:        difflib.SplitLines(string(redacted)),
+		FromFile: path,
+		ToFile:   path + " (redacted)",
+		Context:  1,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to diff %s", path)
+	}
+	return preview, nil
+}
+
+// PreviewDir previews the redaction of every regular file under root, which may also be a single
+// file. Files are named by their slash separated path relative to root, which is what the file
+// selectors of the redactors match, and are returned in lexical order.
+func PreviewDir(root string, additionalRedactors []*troubleshootv1beta2.Redact) ([]*RedactionPreview, error) {
+	info, err := os.Stat(root)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to stat %s", root)
+	}
+	if !info.IsDir() {
+		content, err := os.ReadFile(root)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read %s", root)
+		}
+		preview, err := PreviewRedaction(content, filepath.Base(root), additionalRedactors)
+		if err != nil {
+			return nil, err
+		}
+		return []*RedactionPreview{preview}, nil
+	}
+
+	previews := []*RedactionPreview{}
+	err = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return errors.Wrapf(err, "failed to read %s", rel)
+		}
+		preview, err := PreviewRedaction(content, filepath.ToSlash(rel), additionalRedactors)
+		if err != nil {
+			return err
+		}
+		previews = append(previews, preview)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return previews, nil
+}
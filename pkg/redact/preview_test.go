@@ -0,0 +1,62 @@
+package redact
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_PreviewDir(t *testing.T) {
+	defer func() {
+		GetRedactionList()
+		ResetRedactionList()
+	}()
+
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "config"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "config", "app.conf"), []byte("user=admin\nmode=debug\napi_key=abc123"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "notes.txt"), []byte("api_key=abc123\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "empty.txt"), nil, 0644))
+
+	redactors := []*troubleshootv1beta2.Redact{
+		{
+			Name:         "api keys",
+			FileSelector: troubleshootv1beta2.FileSelector{File: "config/*"},
+			Removals: troubleshootv1beta2.Removals{
+				Regex: []troubleshootv1beta2.Regex{{Redactor: `(api_key=)(?P<mask>.*)`}},
+			},
+		},
+	}
+
+	previews, err := PreviewDir(root, redactors)
+	require.NoError(t, err)
+	require.Len(t, previews, 3)
+
+	assert.Equal(t, "config/app.conf", previews[0].File)
+	assert.True(t, previews[0].IsRedacted())
+	assert.Equal(t, "user=admin\nmode=debug\napi_key=***HIDDEN***", string(previews[0].Redacted))
+	assert.Equal(t, `--- config/app.conf
++++ config/app.conf (redacted)
+@@ -2,2 +2,2 @@
+ mode=debug
+-api_key=abc123
++api_key=***HIDDEN***
+`, previews[0].Diff)
+
+	assert.Equal(t, "empty.txt", previews[1].File)
+	assert.False(t, previews[1].IsRedacted())
+
+	// the redactor only applies to the files in config
+	assert.Equal(t, "notes.txt", previews[2].File)
+	assert.False(t, previews[2].IsRedacted())
+	assert.Equal(t, "api_key=abc123\n", string(previews[2].Redacted))
+
+	previews, err = PreviewDir(filepath.Join(root, "notes.txt"), nil)
+	require.NoError(t, err)
+	require.Len(t, previews, 1)
+	assert.Equal(t, "notes.txt", previews[0].File)
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/pkg/errors"
 	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
 	"github.com/replicatedhq/troubleshoot/pkg/constants"
+	"github.com/replicatedhq/troubleshoot/pkg/metrics"
 )
 
 const (
@@ -77,6 +78,12 @@ type LineRedactor struct {
 }
 
 func Redact(input io.Reader, path string, additionalRedactors []*troubleshootv1beta2.Redact) (io.Reader, error) {
+	return RedactWithTokenizer(input, path, additionalRedactors, nil)
+}
+
+// RedactWithTokenizer is Redact with the values the redactors remove replaced by their tokens,
+// unless tokens is nil.
+func RedactWithTokenizer(input io.Reader, path string, additionalRedactors []*troubleshootv1beta2.Redact, tokens *Tokenizer) (io.Reader, error) {
 	redactors, err := getRedactors(path)
 	if err != nil {
 		return nil, err
@@ -90,6 +97,9 @@ func Redact(input io.Reader, path string, additionalRedactors []*troubleshootv1b
 
 	nextReader := input
 	for _, r := range redactors {
+		if tr, ok := r.(tokenizingRedactor); ok {
+			tr.setTokenizer(tokens)
+		}
 		nextReader = r.Redact(nextReader, path)
 	}
 
@@ -510,6 +520,7 @@ func readLine(r *bufio.Reader) ([]byte, error) {
 }
 
 func addRedaction(redaction Redaction) {
+	metrics.ObserveRedaction(redaction.RedactorName)
 	pendingRedactions.Add(1)
 	go func(redaction Redaction) {
 		redactionListMut.Lock()
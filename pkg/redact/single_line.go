@@ -18,6 +18,7 @@ type SingleLineRedactor struct {
 	filePath   string
 	redactName string
 	isDefault  bool
+	tokens     *Tokenizer
 }
 
 var NEW_LINE = []byte{'\n'}
@@ -39,6 +40,10 @@ func NewSingleLineRedactor(re LineRedactor, maskText, path, name string, isDefau
 	return &SingleLineRedactor{scan: scanCompiled, re: compiled, maskText: maskText, filePath: path, redactName: name, isDefault: isDefault}, nil
 }
 
+func (r *SingleLineRedactor) setTokenizer(tokens *Tokenizer) {
+	r.tokens = tokens
+}
+
 func (r *SingleLineRedactor) Redact(input io.Reader, path string) io.Reader {
 	out, writer := io.Pipe()
 
@@ -92,7 +97,7 @@ func (r *SingleLineRedactor) Redact(input io.Reader, path string) io.Reader {
 				continue
 			}
 
-			clean := r.re.ReplaceAll(line, substStr)
+			clean := r.tokens.replaceMasked(r.re, line, substStr)
 			// Append newline since scanner strips it
 			err = writeBytes(writer, clean, NEW_LINE)
 			if err != nil {
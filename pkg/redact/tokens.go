@@ -0,0 +1,201 @@
+package redact
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sync"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	// TOKEN_FORMAT is the format of the tokens that replace redacted values, see Tokenizer
+	TOKEN_FORMAT = "***TOKEN_%d***"
+
+	tokenMappingVersion = 1
+	// scrypt parameters recommended for interactive logins
+	tokenMappingScryptN = 1 << 15
+	tokenMappingScryptR = 8
+	tokenMappingScryptP = 1
+	tokenMappingKeySize = 32
+	tokenMappingSalt    = 16
+)
+
+// Tokenizer assigns stable tokens to redacted values, such as ***TOKEN_42***, which replace them
+// instead of ***HIDDEN***. The same value is replaced by the same token in every file redacted
+// with the same tokenizer, see RedactWithTokenizer.
+type Tokenizer struct {
+	mut    sync.Mutex
+	tokens map[string]string
+	values map[string]string
+}
+
+func NewTokenizer() *Tokenizer {
+	return &Tokenizer{
+		tokens: map[string]string{},
+		values: map[string]string{},
+	}
+}
+
+// Mapping returns the redacted value of each token assigned so far
+func (t *Tokenizer) Mapping() map[string]string {
+	t.mut.Lock()
+	defer t.mut.Unlock()
+	mapping := make(map[string]string, len(t.values))
+	for token, value := range t.values {
+		mapping[token] = value
+	}
+	return mapping
+}
+
+func (t *Tokenizer) token(value []byte) []byte {
+	t.mut.Lock()
+	defer t.mut.Unlock()
+
+	if token, ok := t.tokens[string(value)]; ok {
+		return []byte(token)
+	}
+	token := fmt.Sprintf(TOKEN_FORMAT, len(t.tokens)+1)
+	t.tokens[string(value)] = token
+	t.values[token] = string(value)
+	return []byte(token)
+}
+
+// mask returns what replaces a redacted value, its token unless the tokenizer is nil
+func (t *Tokenizer) mask(value []byte) []byte {
+	if t == nil {
+		return maskTextBytes
+	}
+	return t.token(value)
+}
+
+// replaceMasked replaces the matches of re in line with substStr, the replacement pattern of re.
+// Unless the tokenizer is nil, each "mask" group is replaced by the token of the value it matched
+// instead.
+func (t *Tokenizer) replaceMasked(re *regexp.Regexp, line []byte, substStr []byte) []byte {
+	if t == nil {
+		return re.ReplaceAll(line, substStr)
+	}
+
+	result := []byte{}
+	last := 0
+	for _, match := range re.FindAllSubmatchIndex(line, -1) {
+		result = append(result, line[last:match[0]]...)
+		template := ""
+		for i, name := range re.SubexpNames() {
+			switch {
+			case i == 0: // index 0 is the entire string
+			case name == "":
+				template = fmt.Sprintf("%s${%d}", template, i)
+			case name == "mask":
+				if match[2*i] >= 0 {
+					template += string(t.token(line[match[2*i]:match[2*i+1]]))
+				}
+			case name == "drop":
+				// no-op, string is just dropped from result
+			default:
+				template = fmt.Sprintf("%s${%s}", template, name)
+			}
+		}
+		result = re.Expand(result, []byte(template), line, match)
+		last = match[1]
+	}
+	return append(result, line[last:]...)
+}
+
+// tokenizingRedactor is a redactor that can replace the values it removes with tokens
+type tokenizingRedactor interface {
+	setTokenizer(tokens *Tokenizer)
+}
+
+// encryptedTokenMapping is the file the token mapping is encrypted in. The key is derived from a
+// passphrase with scrypt, and the mapping is sealed with AES-256-GCM.
+type encryptedTokenMapping struct {
+	Version    int    `json:"version"`
+	Salt       []byte `json:"salt"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// EncryptTokenMapping encrypts the value of each token with a key derived from the passphrase, so
+// that the mapping can be kept apart from the bundle by whoever collected it.
+func EncryptTokenMapping(mapping map[string]string, passphrase string) ([]byte, error) {
+	if passphrase == "" {
+		return nil, errors.New("a passphrase is required to encrypt the token mapping")
+	}
+
+	plaintext, err := json.Marshal(mapping)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal token mapping")
+	}
+
+	salt := make([]byte, tokenMappingSalt)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, errors.Wrap(err, "failed to generate salt")
+	}
+	aead, err := tokenMappingCipher(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, errors.Wrap(err, "failed to generate nonce")
+	}
+
+	return json.MarshalIndent(encryptedTokenMapping{
+		Version:    tokenMappingVersion,
+		Salt:       salt,
+		Nonce:      nonce,
+		Ciphertext: aead.Seal(nil, nonce, plaintext, nil),
+	}, "", "  ")
+}
+
+// DecryptTokenMapping decrypts a token mapping encrypted by EncryptTokenMapping
+func DecryptTokenMapping(data []byte, passphrase string) (map[string]string, error) {
+	encrypted := encryptedTokenMapping{}
+	if err := json.Unmarshal(data, &encrypted); err != nil {
+		return nil, errors.Wrap(err, "failed to parse token mapping")
+	}
+	if encrypted.Version != tokenMappingVersion {
+		return nil, errors.Errorf("unsupported token mapping version %d", encrypted.Version)
+	}
+
+	aead, err := tokenMappingCipher(passphrase, encrypted.Salt)
+	if err != nil {
+		return nil, err
+	}
+	if len(encrypted.Nonce) != aead.NonceSize() {
+		return nil, errors.New("invalid token mapping nonce")
+	}
+	plaintext, err := aead.Open(nil, encrypted.Nonce, encrypted.Ciphertext, nil)
+	if err != nil {
+		return nil, errors.New("failed to decrypt token mapping, the passphrase may be wrong")
+	}
+
+	mapping := map[string]string{}
+	if err := json.Unmarshal(plaintext, &mapping); err != nil {
+		return nil, errors.Wrap(err, "failed to parse decrypted token mapping")
+	}
+	return mapping, nil
+}
+
+func tokenMappingCipher(passphrase string, salt []byte) (cipher.AEAD, error) {
+	key, err := scrypt.Key([]byte(passphrase), salt, tokenMappingScryptN, tokenMappingScryptR, tokenMappingScryptP, tokenMappingKeySize)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to derive key")
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create cipher")
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create cipher")
+	}
+	return aead, nil
+}
@@ -3,6 +3,7 @@ package redact
 import (
 	"bufio"
 	"bytes"
+	"fmt"
 	"io"
 	"path/filepath"
 	"strconv"
@@ -18,6 +19,7 @@ type YamlRedactor struct {
 	filePath   string
 	redactName string
 	isDefault  bool
+	tokens     *Tokenizer
 }
 
 func NewYamlRedactor(yamlPath, filePath, name string) *YamlRedactor {
@@ -25,6 +27,10 @@ func NewYamlRedactor(yamlPath, filePath, name string) *YamlRedactor {
 	return &YamlRedactor{maskPath: pathComponents, filePath: filePath, redactName: name}
 }
 
+func (r *YamlRedactor) setTokenizer(tokens *Tokenizer) {
+	r.tokens = tokens
+}
+
 func (r *YamlRedactor) Redact(input io.Reader, path string) io.Reader {
 	if r.filePath != "" {
 		match, err := filepath.Match(r.filePath, path)
@@ -90,7 +96,7 @@ func (r *YamlRedactor) Redact(input io.Reader, path string) io.Reader {
 func (r *YamlRedactor) redactYaml(in interface{}, path []string) interface{} {
 	if len(path) == 0 {
 		r.foundMatch = true
-		return MASK_TEXT
+		return maskYamlValue(in, r.tokens)
 	}
 	switch typed := in.(type) {
 	case []interface{}:
@@ -133,3 +139,13 @@ func (r *YamlRedactor) redactYaml(in interface{}, path []string) interface{} {
 		return typed
 	}
 }
+
+// maskYamlValue returns what replaces a redacted yaml value. Scalars are replaced by their token
+// unless tokens is nil, maps and lists are always replaced by ***HIDDEN***.
+func maskYamlValue(in interface{}, tokens *Tokenizer) interface{} {
+	switch in.(type) {
+	case []interface{}, map[interface{}]interface{}, nil:
+		return MASK_TEXT
+	}
+	return string(tokens.mask([]byte(fmt.Sprint(in))))
+}
@@ -0,0 +1,159 @@
+package registry
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+
+	dockerref "github.com/containers/image/v5/docker/reference"
+	"github.com/containers/image/v5/types"
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+// AuthConfig holds the credentials used to access a registry
+type AuthConfig struct {
+	Username string
+	Password string
+}
+
+// AuthConfigProvider is implemented by specs that reference image pull secrets
+type AuthConfigProvider interface {
+	GetImagePullSecrets() *v1beta2.ImagePullSecrets
+	GetNamespace() string
+}
+
+var _ AuthConfigProvider = &v1beta2.RegistryImages{}
+var _ AuthConfigProvider = &v1beta2.ImageSignatures{}
+var _ AuthConfigProvider = &v1beta2.ImageLayers{}
+var _ AuthConfigProvider = &v1beta2.ImageFacts{}
+
+// ResolveAuthConfig returns the credentials of the pull secrets of the provider for the registry of
+// the image. Secrets referenced by name are read from the namespace of the provider, or namespace
+// when the provider has none. It returns nil when the provider has no pull secrets or they have no
+// credentials for the registry.
+func ResolveAuthConfig(ctx context.Context, clientConfig *rest.Config, namespace string, provider AuthConfigProvider, imageRef types.ImageReference) (*AuthConfig, error) {
+	imagePullSecrets := provider.GetImagePullSecrets()
+	if imagePullSecrets == nil {
+		return nil, nil
+	}
+
+	if imagePullSecrets.Data != nil {
+		config, err := AuthConfigFromData(imageRef, imagePullSecrets)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get auth from data")
+		}
+		return config, nil
+	}
+
+	if imagePullSecrets.Name != "" {
+		secretNamespace := provider.GetNamespace()
+		if secretNamespace == "" {
+			secretNamespace = namespace
+		}
+		if secretNamespace == "" {
+			secretNamespace = "default"
+		}
+		config, err := authConfigFromSecret(ctx, clientConfig, imageRef, imagePullSecrets, secretNamespace)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get auth from secret")
+		}
+		return config, nil
+	}
+
+	return nil, errors.New("image pull secret spec is not valid")
+}
+
+// AuthConfigFromData returns the credentials of a kubernetes.io/dockerconfigjson pull secret for
+// the registry of the image, or nil when it has none for the registry
+func AuthConfigFromData(imageRef types.ImageReference, pullSecrets *v1beta2.ImagePullSecrets) (*AuthConfig, error) {
+	if pullSecrets.SecretType != "kubernetes.io/dockerconfigjson" {
+		return nil, errors.Errorf("secret type is not supported: %s", pullSecrets.SecretType)
+	}
+
+	configJsonBase64 := pullSecrets.Data[".dockerconfigjson"]
+	registry := dockerref.Domain(imageRef.DockerReference())
+
+	configJson, err := base64.StdEncoding.DecodeString(configJsonBase64)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode docker config string")
+	}
+
+	dockerCfgJSON := struct {
+		Auths map[string]struct {
+			Auth     string `json:"auth"`
+			Username string `json:"username"`
+			Password string `json:"password"`
+		} `json:"auths"`
+	}{}
+
+	err = json.Unmarshal([]byte(configJson), &dockerCfgJSON)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal config json")
+	}
+
+	auth, ok := dockerCfgJSON.Auths[registry]
+	if !ok {
+		// Suport a mix of public and private images
+		return nil, nil
+	}
+
+	// gcr.io auth uses username and password, e.g. username: _json_key, password: <sa_key>
+	if auth.Username != "" && auth.Password != "" {
+		return &AuthConfig{
+			Username: auth.Username,
+			Password: auth.Password,
+		}, nil
+	}
+
+	// docker.io auth uses auth, e.g. auth: <base64_encoded_username_password>
+	// username and password can't contain colon
+	// at least according to https://github.com/docker/cli/blob/v27.0.3/cli/config/configfile/file.go#L247
+	// fallback to not decode for compatibility
+	authStr := auth.Auth
+	decodedAuth, err := base64.StdEncoding.DecodeString(authStr)
+	if err == nil {
+		authStr = string(decodedAuth)
+	}
+
+	parts := strings.Split(authStr, ":")
+	if len(parts) != 2 {
+		return nil, errors.Errorf("expected 2 parts in the auth string, but found %d", len(parts))
+	}
+
+	return &AuthConfig{
+		Username: parts[0],
+		Password: strings.Trim(parts[1], "\x00"),
+	}, nil
+}
+
+func authConfigFromSecret(ctx context.Context, clientConfig *rest.Config, imageRef types.ImageReference, pullSecrets *v1beta2.ImagePullSecrets, namespace string) (*AuthConfig, error) {
+	client, err := kubernetes.NewForConfig(clientConfig)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create client from config")
+	}
+
+	secret, err := client.CoreV1().Secrets(namespace).Get(ctx, pullSecrets.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get secret")
+	}
+
+	foundSecrets := &v1beta2.ImagePullSecrets{
+		Name:       secret.Name,
+		SecretType: string(secret.Type),
+		Data: map[string]string{
+			".dockerconfigjson": base64.StdEncoding.EncodeToString(secret.Data[".dockerconfigjson"]),
+		},
+	}
+
+	config, err := AuthConfigFromData(imageRef, foundSecrets)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get auth from secret data")
+	}
+
+	return config, nil
+}
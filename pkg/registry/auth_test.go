@@ -0,0 +1,148 @@
+package registry
+
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+
+	"github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/rest"
+)
+
+func TestAuthConfigFromData(t *testing.T) {
+	tests := []struct {
+		name             string
+		imageName        string
+		dockerConfigJSON string
+		expectedUsername string
+		expectedPassword string
+		expectedError    bool
+	}{
+		{
+			name:             "docker.io auth",
+			imageName:        "docker.io/myimage",
+			dockerConfigJSON: `{"auths":{"docker.io":{"auth":"username:password"}}}`,
+			expectedUsername: "username",
+			expectedPassword: "password",
+			expectedError:    false,
+		},
+		{
+			name:             "docker.io auth multi colon",
+			imageName:        "docker.io/myimage",
+			dockerConfigJSON: `{"auths":{"docker.io":{"auth":"user:name:pass:word"}}}`,
+			expectedError:    true,
+		},
+		{
+			name:             "gcr.io auth",
+			imageName:        "gcr.io/myimage",
+			dockerConfigJSON: `{"auths":{"gcr.io":{"username":"_json_key","password":"sa-key"}}}`,
+			expectedUsername: "_json_key",
+			expectedPassword: "sa-key",
+			expectedError:    false,
+		},
+		{
+			name:             "proxy.replicated.com auth base64 encoded",
+			imageName:        "proxy.replicated.com/app-slug/myimage",
+			dockerConfigJSON: `{"auths":{"proxy.replicated.com":{"auth":"bGljZW5zZV9pZF8xOmxpY2Vuc2VfaWRfMQ=="}}}`,
+			expectedUsername: "license_id_1",
+			expectedPassword: "license_id_1",
+			expectedError:    false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			imageRef, err := ParseImageReference(test.imageName)
+			assert.NoError(t, err)
+
+			pullSecrets := &v1beta2.ImagePullSecrets{
+				SecretType: "kubernetes.io/dockerconfigjson",
+				Data: map[string]string{
+					".dockerconfigjson": base64.StdEncoding.EncodeToString([]byte(test.dockerConfigJSON)),
+				},
+			}
+
+			authConfig, err := AuthConfigFromData(imageRef, pullSecrets)
+			if test.expectedError {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.NotNil(t, authConfig)
+			assert.Equal(t, test.expectedUsername, authConfig.Username)
+			assert.Equal(t, test.expectedPassword, authConfig.Password)
+		})
+	}
+}
+
+func TestResolveAuthConfig(t *testing.T) {
+	dockerConfigJSON := `{
+		"auths": {
+			"private-registry.io": {
+				"username": "testuser",
+				"password": "testpass"
+			}
+		}
+	}`
+	pullSecrets := &v1beta2.ImagePullSecrets{
+		Data: map[string]string{
+			".dockerconfigjson": base64.StdEncoding.EncodeToString([]byte(dockerConfigJSON)),
+		},
+		SecretType: "kubernetes.io/dockerconfigjson",
+	}
+
+	tests := []struct {
+		name     string
+		provider AuthConfigProvider
+		image    string
+		want     *AuthConfig
+		wantErr  bool
+	}{
+		{
+			name:     "registry images",
+			provider: &v1beta2.RegistryImages{ImagePullSecrets: pullSecrets},
+			image:    "private-registry.io/user/app:latest",
+			want:     &AuthConfig{Username: "testuser", Password: "testpass"},
+		},
+		{
+			name:     "image signatures",
+			provider: &v1beta2.ImageSignatures{ImagePullSecrets: pullSecrets},
+			image:    "private-registry.io/user/app@sha256:0000000000000000000000000000000000000000000000000000000000000000",
+			want:     &AuthConfig{Username: "testuser", Password: "testpass"},
+		},
+		{
+			name:     "other registry",
+			provider: &v1beta2.RegistryImages{ImagePullSecrets: pullSecrets},
+			image:    "nginx:latest",
+		},
+		{
+			name:     "no pull secrets",
+			provider: &v1beta2.RegistryImages{},
+			image:    "private-registry.io/user/app:latest",
+		},
+		{
+			name:     "empty pull secrets",
+			provider: &v1beta2.RegistryImages{ImagePullSecrets: &v1beta2.ImagePullSecrets{}},
+			image:    "private-registry.io/user/app:latest",
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			imageRef, err := ParseImageReference(tt.image)
+			require.NoError(t, err)
+
+			authConfig, err := ResolveAuthConfig(context.Background(), &rest.Config{}, "default", tt.provider, imageRef)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, authConfig)
+		})
+	}
+}
@@ -0,0 +1,460 @@
+// Package registry checks images in container registries. It is used by the registryImages,
+// imageSignatures, imageLayers and imageFacts collectors, and can be used by custom collectors
+// that need registry access with the same authentication.
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	imagedocker "github.com/containers/image/v5/docker"
+	"github.com/containers/image/v5/manifest"
+	"github.com/containers/image/v5/transports/alltransports"
+	"github.com/containers/image/v5/types"
+	"github.com/distribution/distribution/v3/registry/api/errcode"
+	registryv2 "github.com/distribution/distribution/v3/registry/api/v2"
+	"github.com/opencontainers/go-digest"
+	imgspecv1 "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+)
+
+// Options configure how a Client accesses registries
+type Options struct {
+	// Auth is the credentials sent to the registry, if any
+	Auth *AuthConfig
+	// InsecureSkipTLSVerify disables the verification of the certificates of registries
+	InsecureSkipTLSVerify bool
+	// CertDir is a directory of certificates and keys to access registries with, laid out like
+	// /etc/docker/certs.d/<registry>
+	CertDir string
+	// Retries is the number of times a request that failed with a transient error is retried
+	Retries int
+	// RetryDelay is the time to wait before retrying a request
+	RetryDelay time.Duration
+}
+
+// DefaultOptions returns the options the collectors access registries with. Certificates are not
+// verified since registries in clusters commonly use self-signed certificates.
+func DefaultOptions(auth *AuthConfig) Options {
+	return Options{
+		Auth:                  auth,
+		InsecureSkipTLSVerify: true,
+		Retries:               2,
+		RetryDelay:            time.Second,
+	}
+}
+
+// ImageManifest describes the manifest of an image
+type ImageManifest struct {
+	MediaType string
+	// ManifestList is true when the manifest is a manifest list or an OCI index, with a manifest
+	// per platform
+	ManifestList bool
+	// Platforms are the platforms the image is available for, e.g. linux/amd64 or linux/arm/v7
+	Platforms []string
+}
+
+type Client struct {
+	options Options
+}
+
+func NewClient(options Options) *Client {
+	return &Client{options: options}
+}
+
+// ParseImageReference parses an image name as it is written in a pod spec, e.g. "nginx:1.27" or
+// "registry.example.com/app@sha256:..."
+func ParseImageReference(image string) (types.ImageReference, error) {
+	imageRef, err := alltransports.ParseImageName(fmt.Sprintf("docker://%s", image))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse image name %s", image)
+	}
+	return imageRef, nil
+}
+
+// SystemContext returns the context of the requests of the client to the registry
+func (c *Client) SystemContext() *types.SystemContext {
+	sysCtx := &types.SystemContext{
+		DockerDisableV1Ping: true,
+		DockerCertPath:      c.options.CertDir,
+	}
+	if c.options.InsecureSkipTLSVerify {
+		sysCtx.DockerInsecureSkipTLSVerify = types.OptionalBoolTrue
+	}
+	if c.options.Auth != nil {
+		sysCtx.DockerAuthConfig = &types.DockerAuthConfig{
+			Username: c.options.Auth.Username,
+			Password: c.options.Auth.Password,
+		}
+	}
+	return sysCtx
+}
+
+// ImageExists fetches the manifest of the image. It returns false when the registry does not have
+// the image, and an error when the registry could not be asked.
+func (c *Client) ImageExists(ctx context.Context, imageRef types.ImageReference) (bool, error) {
+	image := imageRef.DockerReference().String()
+
+	err := c.withRetries(ctx, func() error {
+		remoteImage, err := imageRef.NewImage(ctx, c.SystemContext())
+		if err != nil {
+			return err
+		}
+		remoteImage.Close()
+		return nil
+	})
+	if err == nil {
+		klog.Infof("image %s exists", image)
+		return true, nil
+	}
+
+	klog.Errorf("failed to get image %s: %v", image, err)
+
+	if strings.Contains(err.Error(), "no image found in manifest list for architecture") {
+		// manifest was downloaded, but no matching architecture found in manifest
+		// should this count as image does not exist?
+		// this binary's architecture is not necessarily what will run in the cluster
+		return true, nil
+	}
+
+	if isNotFound(err) {
+		return false, nil
+	}
+
+	return false, errors.Wrap(err, "failed to get image manifest")
+}
+
+// ResolveDigest returns the digest of the manifest of the image with a HEAD request, without
+// downloading the manifest. The digest of a multi-architecture image is that of its manifest list.
+func (c *Client) ResolveDigest(ctx context.Context, imageRef types.ImageReference) (digest.Digest, error) {
+	var resolved digest.Digest
+	err := c.withRetries(ctx, func() error {
+		var err error
+		resolved, err = imagedocker.GetDigest(ctx, c.SystemContext(), imageRef)
+		return err
+	})
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to resolve digest of %s", imageRef.DockerReference())
+	}
+	return resolved, nil
+}
+
+// GetManifest fetches the manifest of the image and the platforms it is available for. The
+// platform of an image without a manifest list is that of its config.
+func (c *Client) GetManifest(ctx context.Context, imageRef types.ImageReference) (*ImageManifest, error) {
+	var imageManifest *ImageManifest
+	err := c.withRetries(ctx, func() error {
+		var err error
+		imageManifest, err = c.getManifest(ctx, imageRef)
+		return err
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get manifest of %s", imageRef.DockerReference())
+	}
+	return imageManifest, nil
+}
+
+func (c *Client) getManifest(ctx context.Context, imageRef types.ImageReference) (*ImageManifest, error) {
+	sysCtx := c.SystemContext()
+
+	src, err := imageRef.NewImageSource(ctx, sysCtx)
+	if err != nil {
+		return nil, err
+	}
+	defer src.Close()
+
+	rawManifest, mediaType, err := src.GetManifest(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	imageManifest := &ImageManifest{
+		MediaType: mediaType,
+	}
+
+	if manifest.MIMETypeIsMultiImage(mediaType) {
+		imageManifest.ManifestList = true
+
+		// docker manifest lists and OCI indexes share the fields that describe platforms
+		index := imgspecv1.Index{}
+		if err := json.Unmarshal(rawManifest, &index); err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal manifest list")
+		}
+		for _, descriptor := range index.Manifests {
+			// build attestations are stored in the index with an unknown platform
+			if descriptor.Platform == nil || descriptor.Platform.OS == "unknown" {
+				continue
+			}
+			platform := FormatPlatform(descriptor.Platform.OS, descriptor.Platform.Architecture, descriptor.Platform.Variant)
+			if !slices.Contains(imageManifest.Platforms, platform) {
+				imageManifest.Platforms = append(imageManifest.Platforms, platform)
+			}
+		}
+		return imageManifest, nil
+	}
+
+	remoteImage, err := imageRef.NewImage(ctx, sysCtx)
+	if err != nil {
+		return nil, err
+	}
+	defer remoteImage.Close()
+
+	info, err := remoteImage.Inspect(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read image config")
+	}
+	imageManifest.Platforms = []string{FormatPlatform(info.Os, info.Architecture, info.Variant)}
+
+	return imageManifest, nil
+}
+
+// ImageConfig is the metadata of an image for a platform, from the config of its manifest
+type ImageConfig struct {
+	// Digest is the digest of the manifest of the platform, not of the manifest list
+	Digest string
+	// Created is when the image was built, if its config records it
+	Created *time.Time
+	Labels  map[string]string
+	// ExposedPorts are the ports the image exposes, e.g. 8080/tcp, sorted
+	ExposedPorts []string
+	// User is the user the processes of the image run as, e.g. 1000 or nobody:nogroup. It is
+	// empty for images that run as root by default.
+	User string
+}
+
+// GetConfig fetches the manifest of the image for the platform, e.g. linux/amd64, and its config.
+// Layers are not downloaded.
+func (c *Client) GetConfig(ctx context.Context, imageRef types.ImageReference, platform string) (*ImageConfig, error) {
+	var imageConfig *ImageConfig
+	err := c.withRetries(ctx, func() error {
+		var err error
+		imageConfig, err = c.getConfig(ctx, imageRef, platform)
+		return err
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get config of %s", imageRef.DockerReference())
+	}
+	return imageConfig, nil
+}
+
+func (c *Client) getConfig(ctx context.Context, imageRef types.ImageReference, platform string) (*ImageConfig, error) {
+	sysCtx, err := c.platformSystemContext(platform)
+	if err != nil {
+		return nil, err
+	}
+
+	remoteImage, err := imageRef.NewImage(ctx, sysCtx)
+	if err != nil {
+		return nil, err
+	}
+	defer remoteImage.Close()
+
+	rawManifest, _, err := remoteImage.Manifest(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read manifest")
+	}
+	manifestDigest, err := manifest.Digest(rawManifest)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to compute manifest digest")
+	}
+
+	config, err := remoteImage.OCIConfig(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read image config")
+	}
+
+	return newImageConfig(manifestDigest, config), nil
+}
+
+func newImageConfig(manifestDigest digest.Digest, config *imgspecv1.Image) *ImageConfig {
+	imageConfig := &ImageConfig{
+		Digest:       manifestDigest.String(),
+		Created:      config.Created,
+		Labels:       config.Config.Labels,
+		ExposedPorts: []string{},
+		User:         config.Config.User,
+	}
+	for port := range config.Config.ExposedPorts {
+		imageConfig.ExposedPorts = append(imageConfig.ExposedPorts, port)
+	}
+	slices.Sort(imageConfig.ExposedPorts)
+	return imageConfig
+}
+
+// ImageLayers describes the blobs of the manifest of an image for a platform, as stored in a
+// registry. Sizes are compressed sizes, -1 when the manifest does not record them.
+type ImageLayers struct {
+	// Digest is the digest of the manifest of the platform, not of the manifest list
+	Digest     string
+	ConfigSize int64
+	Layers     []Layer
+}
+
+// Layer is a layer of an image
+type Layer struct {
+	// Digest is the digest of the compressed layer, as stored in the registry
+	Digest string
+	// DiffID is the digest of the uncompressed layer, from the config of the image. The same
+	// content compressed differently has different digests but the same diff ID.
+	DiffID string
+	Size   int64
+}
+
+// GetLayers fetches the manifest of the image for the platform, e.g. linux/amd64, and its config
+// for the diff IDs of the layers. Layers are not downloaded.
+func (c *Client) GetLayers(ctx context.Context, imageRef types.ImageReference, platform string) (*ImageLayers, error) {
+	var imageLayers *ImageLayers
+	err := c.withRetries(ctx, func() error {
+		var err error
+		imageLayers, err = c.getLayers(ctx, imageRef, platform)
+		return err
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get layers of %s", imageRef.DockerReference())
+	}
+	return imageLayers, nil
+}
+
+func (c *Client) getLayers(ctx context.Context, imageRef types.ImageReference, platform string) (*ImageLayers, error) {
+	sysCtx, err := c.platformSystemContext(platform)
+	if err != nil {
+		return nil, err
+	}
+
+	// the image of a manifest list is that of the platform of the system context
+	remoteImage, err := imageRef.NewImage(ctx, sysCtx)
+	if err != nil {
+		return nil, err
+	}
+	defer remoteImage.Close()
+
+	rawManifest, _, err := remoteImage.Manifest(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read manifest")
+	}
+	manifestDigest, err := manifest.Digest(rawManifest)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to compute manifest digest")
+	}
+
+	// schema 1 images have no config and their layers no diff ID
+	var diffIDs []digest.Digest
+	if remoteImage.ConfigInfo().Digest != "" {
+		config, err := remoteImage.OCIConfig(ctx)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read image config")
+		}
+		diffIDs = config.RootFS.DiffIDs
+	}
+
+	return newImageLayers(manifestDigest, remoteImage.ConfigInfo(), remoteImage.LayerInfos(), diffIDs), nil
+}
+
+// newImageLayers pairs the layers of a manifest with the diff IDs of its config, which are in the
+// same order
+func newImageLayers(manifestDigest digest.Digest, config types.BlobInfo, layers []types.BlobInfo, diffIDs []digest.Digest) *ImageLayers {
+	imageLayers := &ImageLayers{
+		Digest:     manifestDigest.String(),
+		ConfigSize: config.Size,
+		Layers:     []Layer{},
+	}
+	for i, layer := range layers {
+		l := Layer{
+			Digest: layer.Digest.String(),
+			Size:   layer.Size,
+		}
+		if len(diffIDs) == len(layers) {
+			l.DiffID = diffIDs[i].String()
+		}
+		imageLayers.Layers = append(imageLayers.Layers, l)
+	}
+	return imageLayers
+}
+
+// platformSystemContext returns the context of the requests for the image of a platform of
+// manifest lists, or of the platform of the system when platform is empty
+func (c *Client) platformSystemContext(platform string) (*types.SystemContext, error) {
+	sysCtx := c.SystemContext()
+	if platform != "" {
+		var err error
+		sysCtx.OSChoice, sysCtx.ArchitectureChoice, sysCtx.VariantChoice, err = ParsePlatform(platform)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return sysCtx, nil
+}
+
+// ParsePlatform parses a platform formatted by FormatPlatform
+func ParsePlatform(platform string) (string, string, string, error) {
+	parts := strings.Split(platform, "/")
+	if len(parts) < 2 || len(parts) > 3 || parts[0] == "" || parts[1] == "" {
+		return "", "", "", errors.Errorf("invalid platform %q, must be os/architecture or os/architecture/variant", platform)
+	}
+	if len(parts) == 2 {
+		return parts[0], parts[1], "", nil
+	}
+	return parts[0], parts[1], parts[2], nil
+}
+
+// FormatPlatform formats a platform as os/architecture, followed by /variant if any
+func FormatPlatform(operatingSystem string, architecture string, variant string) string {
+	if variant == "" {
+		return fmt.Sprintf("%s/%s", operatingSystem, architecture)
+	}
+	return fmt.Sprintf("%s/%s/%s", operatingSystem, architecture, variant)
+}
+
+// withRetries calls fn until it succeeds, fails with an error that is not transient, or the
+// retries are exhausted
+func (c *Client) withRetries(ctx context.Context, fn func() error) error {
+	var err error
+	for attempt := 0; attempt <= c.options.Retries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(c.options.RetryDelay):
+			}
+		}
+
+		err = fn()
+		if err == nil || !strings.Contains(err.Error(), "EOF") {
+			return err
+		}
+	}
+	return errors.Wrap(err, "failed to retry")
+}
+
+func isNotFound(err error) bool {
+	switch err := err.(type) {
+	case errcode.Errors:
+		for _, e := range err {
+			if isNotFound(e) {
+				return true
+			}
+		}
+		return false
+	case errcode.Error:
+		return err.Message == registryv2.ErrorCodeManifestUnknown.Message()
+	}
+
+	// this type will cause panic when compared to error type
+	if _, ok := err.(imagedocker.ErrUnauthorizedForCredentials); ok {
+		return false
+	}
+
+	cause := errors.Cause(err)
+	if cause, ok := cause.(error); ok {
+		if cause == err {
+			return false
+		}
+	}
+
+	return isNotFound(cause)
+}
@@ -0,0 +1,181 @@
+package registry
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/containers/image/v5/types"
+	"github.com/opencontainers/go-digest"
+	imgspecv1 "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_SystemContext(t *testing.T) {
+	tests := []struct {
+		name    string
+		options Options
+		want    *types.SystemContext
+	}{
+		{
+			name:    "default options without auth",
+			options: DefaultOptions(nil),
+			want: &types.SystemContext{
+				DockerDisableV1Ping:         true,
+				DockerInsecureSkipTLSVerify: types.OptionalBoolTrue,
+			},
+		},
+		{
+			name:    "default options with auth",
+			options: DefaultOptions(&AuthConfig{Username: "testuser", Password: "testpass"}),
+			want: &types.SystemContext{
+				DockerDisableV1Ping:         true,
+				DockerInsecureSkipTLSVerify: types.OptionalBoolTrue,
+				DockerAuthConfig:            &types.DockerAuthConfig{Username: "testuser", Password: "testpass"},
+			},
+		},
+		{
+			name:    "verified certificates",
+			options: Options{CertDir: "/etc/docker/certs.d"},
+			want: &types.SystemContext{
+				DockerDisableV1Ping: true,
+				DockerCertPath:      "/etc/docker/certs.d",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, NewClient(tt.options).SystemContext())
+		})
+	}
+}
+
+func TestParseImageReference(t *testing.T) {
+	imageRef, err := ParseImageReference("nginx:1.27")
+	require.NoError(t, err)
+	assert.Equal(t, "docker.io/library/nginx:1.27", imageRef.DockerReference().String())
+
+	_, err = ParseImageReference("registry.io/user/image:tag:invalid")
+	assert.Error(t, err)
+}
+
+func TestClient_withRetries(t *testing.T) {
+	tests := []struct {
+		name      string
+		errs      []error
+		wantCalls int
+		wantErr   bool
+	}{
+		{
+			name:      "success",
+			errs:      []error{nil},
+			wantCalls: 1,
+		},
+		{
+			name:      "transient error",
+			errs:      []error{io.EOF, nil},
+			wantCalls: 2,
+		},
+		{
+			name:      "permanent error",
+			errs:      []error{errors.New("unauthorized")},
+			wantCalls: 1,
+			wantErr:   true,
+		},
+		{
+			name:      "retries exhausted",
+			errs:      []error{io.EOF, io.EOF, io.EOF, nil},
+			wantCalls: 3,
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := NewClient(Options{Retries: 2})
+
+			calls := 0
+			err := client.withRetries(context.Background(), func() error {
+				err := tt.errs[calls]
+				calls++
+				return err
+			})
+			assert.Equal(t, tt.wantCalls, calls)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestFormatPlatform(t *testing.T) {
+	assert.Equal(t, "linux/amd64", FormatPlatform("linux", "amd64", ""))
+	assert.Equal(t, "linux/arm/v7", FormatPlatform("linux", "arm", "v7"))
+}
+
+func TestParsePlatform(t *testing.T) {
+	operatingSystem, architecture, variant, err := ParsePlatform("linux/arm64")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"linux", "arm64", ""}, []string{operatingSystem, architecture, variant})
+
+	operatingSystem, architecture, variant, err = ParsePlatform(FormatPlatform("linux", "arm", "v7"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"linux", "arm", "v7"}, []string{operatingSystem, architecture, variant})
+
+	for _, platform := range []string{"linux", "linux/", "linux/arm/v7/extra"} {
+		_, _, _, err = ParsePlatform(platform)
+		assert.Error(t, err, platform)
+	}
+}
+
+func Test_newImageLayers(t *testing.T) {
+	config := types.BlobInfo{Digest: "sha256:c0", Size: 1500}
+	layers := []types.BlobInfo{
+		{Digest: "sha256:a1", Size: 3000000},
+		{Digest: "sha256:a2", Size: 1000},
+	}
+
+	imageLayers := newImageLayers("sha256:m1", config, layers, []digest.Digest{"sha256:d1", "sha256:d2"})
+	assert.Equal(t, &ImageLayers{
+		Digest:     "sha256:m1",
+		ConfigSize: 1500,
+		Layers: []Layer{
+			{Digest: "sha256:a1", DiffID: "sha256:d1", Size: 3000000},
+			{Digest: "sha256:a2", DiffID: "sha256:d2", Size: 1000},
+		},
+	}, imageLayers)
+
+	// the diff IDs of a config that does not match the manifest are ignored
+	imageLayers = newImageLayers("sha256:m1", types.BlobInfo{Size: -1}, layers, []digest.Digest{"sha256:d1"})
+	assert.Equal(t, []Layer{
+		{Digest: "sha256:a1", Size: 3000000},
+		{Digest: "sha256:a2", Size: 1000},
+	}, imageLayers.Layers)
+	assert.Equal(t, int64(-1), imageLayers.ConfigSize)
+}
+
+func Test_newImageConfig(t *testing.T) {
+	created := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	config := &imgspecv1.Image{
+		Created: &created,
+		Config: imgspecv1.ImageConfig{
+			User:         "1000:1000",
+			ExposedPorts: map[string]struct{}{"9090/tcp": {}, "8080/tcp": {}},
+			Labels:       map[string]string{"org.opencontainers.image.version": "1.4.0"},
+		},
+	}
+
+	assert.Equal(t, &ImageConfig{
+		Digest:       "sha256:m1",
+		Created:      &created,
+		Labels:       map[string]string{"org.opencontainers.image.version": "1.4.0"},
+		ExposedPorts: []string{"8080/tcp", "9090/tcp"},
+		User:         "1000:1000",
+	}, newImageConfig("sha256:m1", config))
+}
@@ -0,0 +1,84 @@
+package registry
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/containers/image/v5/docker/reference"
+	"github.com/pkg/errors"
+)
+
+// dockerHubHosts are the names of Docker Hub that images and mirror configurations use
+var dockerHubHosts = []string{"docker.io", "index.docker.io", "registry-1.docker.io"}
+
+// MirrorCandidates returns the images to look an image up at, in order, like containerd does with
+// the mirrors of a registry: the image at each mirror of its registry, then the image itself.
+//
+// Mirrors are keyed by registry, e.g. docker.io or gcr.io. A mirror is a host with an optional
+// namespace, e.g. https://harbor.internal/dockerhub for a pull-through cache project, under which
+// the repository of the image is kept: docker.io/library/nginx:1.27 is looked up at
+// harbor.internal/dockerhub/library/nginx:1.27.
+func MirrorCandidates(image string, mirrors map[string][]string) ([]string, error) {
+	if len(mirrors) == 0 {
+		return []string{image}, nil
+	}
+
+	named, err := reference.ParseNormalizedNamed(image)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse image name %s", image)
+	}
+
+	suffix := ""
+	if tagged, ok := named.(reference.Tagged); ok {
+		suffix += ":" + tagged.Tag()
+	}
+	if digested, ok := named.(reference.Digested); ok {
+		suffix += "@" + digested.Digest().String()
+	}
+
+	registries := []string{}
+	for registry := range mirrors {
+		registries = append(registries, registry)
+	}
+	sort.Strings(registries)
+
+	candidates := []string{}
+	domain := normalizeRegistryHost(reference.Domain(named))
+	for _, registry := range registries {
+		if normalizeRegistryHost(registry) != domain {
+			continue
+		}
+		for _, endpoint := range mirrors[registry] {
+			endpoint = mirrorEndpoint(endpoint)
+			if endpoint == "" {
+				continue
+			}
+			candidates = append(candidates, endpoint+"/"+reference.Path(named)+suffix)
+		}
+	}
+
+	return append(candidates, image), nil
+}
+
+// normalizeRegistryHost returns the host of a registry, with docker.io for the names of Docker Hub
+func normalizeRegistryHost(registry string) string {
+	host := strings.ToLower(mirrorEndpoint(registry))
+	for _, dockerHubHost := range dockerHubHosts {
+		if host == dockerHubHost {
+			return "docker.io"
+		}
+	}
+	return host
+}
+
+// mirrorEndpoint strips the scheme of a mirror, and the /v2 API prefix of containerd host configs
+func mirrorEndpoint(endpoint string) string {
+	if _, rest, ok := strings.Cut(endpoint, "://"); ok {
+		endpoint = rest
+	}
+	endpoint = strings.TrimSuffix(strings.TrimSpace(endpoint), "/")
+	if host, path, ok := strings.Cut(endpoint, "/v2"); ok && (path == "" || strings.HasPrefix(path, "/")) {
+		endpoint = host + path
+	}
+	return strings.TrimSuffix(endpoint, "/")
+}
@@ -0,0 +1,74 @@
+package registry
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMirrorCandidates(t *testing.T) {
+	mirrors := map[string][]string{
+		"docker.io":       {"https://harbor.internal/dockerhub/", "mirror.internal:5000"},
+		"index.docker.io": {"https://harbor.internal/v2/hub"},
+		"gcr.io":          {"harbor.internal/gcr"},
+	}
+
+	tests := []struct {
+		name    string
+		image   string
+		mirrors map[string][]string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:  "no mirrors",
+			image: "nginx:1.27",
+			want:  []string{"nginx:1.27"},
+		},
+		{
+			name:    "docker hub image",
+			image:   "nginx:1.27",
+			mirrors: mirrors,
+			want: []string{
+				"harbor.internal/dockerhub/library/nginx:1.27",
+				"mirror.internal:5000/library/nginx:1.27",
+				"harbor.internal/hub/library/nginx:1.27",
+				"nginx:1.27",
+			},
+		},
+		{
+			name:    "digest",
+			image:   "gcr.io/distroless/static@sha256:3f57d9401f8d42f986df300f0c69192fc41da28ccc8d797829467780db3dd741",
+			mirrors: mirrors,
+			want: []string{
+				"harbor.internal/gcr/distroless/static@sha256:3f57d9401f8d42f986df300f0c69192fc41da28ccc8d797829467780db3dd741",
+				"gcr.io/distroless/static@sha256:3f57d9401f8d42f986df300f0c69192fc41da28ccc8d797829467780db3dd741",
+			},
+		},
+		{
+			name:    "registry without mirrors",
+			image:   "quay.io/prometheus/node-exporter",
+			mirrors: mirrors,
+			want:    []string{"quay.io/prometheus/node-exporter"},
+		},
+		{
+			name:    "invalid image",
+			image:   "registry.io/user/image:tag:invalid",
+			mirrors: mirrors,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := MirrorCandidates(tt.image, tt.mirrors)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
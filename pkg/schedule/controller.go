@@ -0,0 +1,171 @@
+package schedule
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+)
+
+// Reconciler keeps a CronJob for each SupportBundleSchedule. The CronJob is owned by the
+// schedule, so it is garbage collected when the schedule is deleted. Retained bundles are
+// left in storage.
+type Reconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+}
+
+func (r *Reconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	schedule := &troubleshootv1beta2.SupportBundleSchedule{}
+	if err := r.Get(ctx, req.NamespacedName, schedule); err != nil {
+		if apierrors.IsNotFound(err) {
+			deleteScheduleMetrics(req.Namespace, req.Name)
+		}
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	recordScheduleMetrics(schedule)
+
+	cronJob := &batchv1.CronJob{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      schedule.Name,
+			Namespace: schedule.Namespace,
+		},
+	}
+
+	// schedules without a cron schedule only collect when triggered
+	if schedule.Spec.Schedule == "" {
+		if err := r.Get(ctx, client.ObjectKeyFromObject(cronJob), cronJob); err != nil {
+			return ctrl.Result{}, client.IgnoreNotFound(err)
+		}
+		if !metav1.IsControlledBy(cronJob, schedule) {
+			return ctrl.Result{}, nil
+		}
+		if err := r.Delete(ctx, cronJob); client.IgnoreNotFound(err) != nil {
+			return ctrl.Result{}, errors.Wrapf(err, "failed to delete cronjob for support bundle schedule %s/%s", schedule.Namespace, schedule.Name)
+		}
+		return ctrl.Result{}, nil
+	}
+
+	op, err := controllerutil.CreateOrUpdate(ctx, r.Client, cronJob, func() error {
+		setCronJobSpec(cronJob, schedule)
+		return controllerutil.SetControllerReference(schedule, cronJob, r.Scheme)
+	})
+	if err != nil {
+		return ctrl.Result{}, errors.Wrapf(err, "failed to reconcile cronjob for support bundle schedule %s/%s", schedule.Namespace, schedule.Name)
+	}
+	if op != controllerutil.OperationResultNone {
+		klog.V(2).Infof("CronJob %s/%s %s", cronJob.Namespace, cronJob.Name, op)
+	}
+
+	return ctrl.Result{}, nil
+}
+
+func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&troubleshootv1beta2.SupportBundleSchedule{}).
+		Owns(&batchv1.CronJob{}).
+		Complete(r)
+}
+
+// NewScheme returns a scheme with the troubleshoot and built-in Kubernetes types.
+func NewScheme() (*runtime.Scheme, error) {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		return nil, errors.Wrap(err, "failed to add kubernetes types to scheme")
+	}
+	if err := troubleshootv1beta2.AddToScheme(scheme); err != nil {
+		return nil, errors.Wrap(err, "failed to add troubleshoot types to scheme")
+	}
+	return scheme, nil
+}
+
+func setupTriggerControllers(mgr ctrl.Manager) error {
+	objects := map[string]func() client.Object{
+		"pod":   func() client.Object { return &corev1.Pod{} },
+		"node":  func() client.Object { return &corev1.Node{} },
+		"event": func() client.Object { return &corev1.Event{} },
+	}
+
+	for name, newObject := range objects {
+		r := &triggerReconciler{
+			Client:    mgr.GetClient(),
+			Scheme:    mgr.GetScheme(),
+			newObject: newObject,
+			now:       time.Now,
+		}
+		err := ctrl.NewControllerManagedBy(mgr).
+			Named("support-bundle-schedule-" + name + "-trigger").
+			For(newObject()).
+			Complete(r)
+		if err != nil {
+			return errors.Wrapf(err, "failed to set up %s trigger controller", name)
+		}
+	}
+
+	return nil
+}
+
+type ControllerOptions struct {
+	// Namespace limits the schedules that are reconciled to a namespace, all namespaces are
+	// reconciled when it is empty.
+	Namespace          string
+	MetricsBindAddress string
+	// DisableTriggers stops watching pods, nodes and events for the triggers of schedules.
+	DisableTriggers bool
+}
+
+// RunController runs the schedule controller until the context is cancelled.
+func RunController(ctx context.Context, restConfig *rest.Config, opts ControllerOptions) error {
+	scheme, err := NewScheme()
+	if err != nil {
+		return err
+	}
+
+	options := ctrl.Options{
+		Scheme: scheme,
+		Metrics: metricsserver.Options{
+			BindAddress: opts.MetricsBindAddress,
+		},
+	}
+	if opts.Namespace != "" {
+		options.Cache = cache.Options{
+			DefaultNamespaces: map[string]cache.Config{
+				opts.Namespace: {},
+			},
+		}
+	}
+
+	mgr, err := ctrl.NewManager(restConfig, options)
+	if err != nil {
+		return errors.Wrap(err, "failed to create manager")
+	}
+
+	reconciler := &Reconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+	}
+	if err := reconciler.SetupWithManager(mgr); err != nil {
+		return errors.Wrap(err, "failed to set up controller")
+	}
+	if !opts.DisableTriggers {
+		if err := setupTriggerControllers(mgr); err != nil {
+			return err
+		}
+	}
+
+	return mgr.Start(ctx)
+}
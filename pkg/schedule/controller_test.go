@@ -0,0 +1,157 @@
+package schedule
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	batchv1 "k8s.io/api/batch/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestReconciler(t *testing.T) {
+	ctx := context.Background()
+
+	scheme, err := NewScheme()
+	require.NoError(t, err)
+
+	schedule := &troubleshootv1beta2.SupportBundleSchedule{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "nightly",
+			Namespace: "default",
+		},
+		Spec: troubleshootv1beta2.SupportBundleScheduleSpec{
+			Schedule: "0 2 * * *",
+			Storage: troubleshootv1beta2.ScheduledBundleStorage{
+				PersistentVolumeClaim: &troubleshootv1beta2.ScheduledBundlePersistentVolumeClaim{ClaimName: "bundles"},
+			},
+		},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(schedule).Build()
+	r := &Reconciler{Client: c, Scheme: scheme}
+
+	key := types.NamespacedName{Namespace: "default", Name: "nightly"}
+	_, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+	require.NoError(t, err)
+
+	cronJob := &batchv1.CronJob{}
+	require.NoError(t, c.Get(ctx, key, cronJob))
+	assert.Equal(t, "0 2 * * *", cronJob.Spec.Schedule)
+	require.Len(t, cronJob.OwnerReferences, 1)
+	assert.Equal(t, "SupportBundleSchedule", cronJob.OwnerReferences[0].Kind)
+	assert.True(t, *cronJob.OwnerReferences[0].Controller)
+
+	// changes to the schedule are applied to the existing CronJob
+	require.NoError(t, c.Get(ctx, key, schedule))
+	schedule.Spec.Schedule = "@hourly"
+	schedule.Spec.Suspend = true
+	require.NoError(t, c.Update(ctx, schedule))
+
+	_, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+	require.NoError(t, err)
+
+	require.NoError(t, c.Get(ctx, key, cronJob))
+	assert.Equal(t, "@hourly", cronJob.Spec.Schedule)
+	assert.True(t, *cronJob.Spec.Suspend)
+
+	// a deleted schedule is not an error, its CronJob is garbage collected
+	_, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "default", Name: "missing"}})
+	require.NoError(t, err)
+}
+
+func TestReconciler_TriggerOnly(t *testing.T) {
+	ctx := context.Background()
+
+	scheme, err := NewScheme()
+	require.NoError(t, err)
+
+	schedule := &troubleshootv1beta2.SupportBundleSchedule{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "crashes",
+			Namespace: "default",
+			UID:       "schedule-uid",
+		},
+		Spec: troubleshootv1beta2.SupportBundleScheduleSpec{
+			Triggers: []troubleshootv1beta2.CollectionTrigger{
+				{NodeNotReady: &troubleshootv1beta2.NodeNotReadyTrigger{}},
+			},
+		},
+	}
+	// left over from when the schedule had a cron schedule
+	cronJob := &batchv1.CronJob{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "crashes",
+			Namespace: "default",
+			OwnerReferences: []metav1.OwnerReference{
+				{
+					APIVersion: "troubleshoot.sh/v1beta2",
+					Kind:       "SupportBundleSchedule",
+					Name:       "crashes",
+					UID:        "schedule-uid",
+					Controller: ptr.To(true),
+				},
+			},
+		},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(schedule, cronJob).Build()
+	r := &Reconciler{Client: c, Scheme: scheme}
+
+	key := types.NamespacedName{Namespace: "default", Name: "crashes"}
+	_, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+	require.NoError(t, err)
+
+	err = c.Get(ctx, key, &batchv1.CronJob{})
+	assert.True(t, apierrors.IsNotFound(err))
+
+	// reconciling again is a no-op
+	_, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+	require.NoError(t, err)
+}
+
+func TestReconciler_Metrics(t *testing.T) {
+	ctx := context.Background()
+
+	scheme, err := NewScheme()
+	require.NoError(t, err)
+
+	lastSchedule := metav1.NewTime(time.Date(2024, 1, 2, 2, 0, 0, 0, time.UTC))
+	lastSuccess := metav1.NewTime(time.Date(2024, 1, 1, 2, 0, 0, 0, time.UTC))
+	schedule := &troubleshootv1beta2.SupportBundleSchedule{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "metrics",
+			Namespace: "default",
+		},
+		Status: troubleshootv1beta2.SupportBundleScheduleStatus{
+			LastScheduleTime:   &lastSchedule,
+			LastSuccessfulTime: &lastSuccess,
+			LastError:          "failed to create bundle store",
+			Bundles:            []troubleshootv1beta2.ScheduledBundle{{Name: "metrics-2024-01-01T02_00_00.tar.gz"}},
+		},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(schedule).Build()
+	r := &Reconciler{Client: c, Scheme: scheme}
+
+	key := types.NamespacedName{Namespace: "default", Name: "metrics"}
+	_, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+	require.NoError(t, err)
+
+	assert.Equal(t, float64(lastSchedule.Unix()), testutil.ToFloat64(scheduleLastScheduleTime.WithLabelValues("default", "metrics")))
+	assert.Equal(t, float64(lastSuccess.Unix()), testutil.ToFloat64(scheduleLastSuccessfulTime.WithLabelValues("default", "metrics")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(scheduleLastCollectionFailed.WithLabelValues("default", "metrics")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(scheduleRetainedBundles.WithLabelValues("default", "metrics")))
+
+	// the metrics of a deleted schedule are removed
+	require.NoError(t, c.Delete(ctx, schedule))
+	_, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+	require.NoError(t, err)
+	assert.Equal(t, 0, testutil.CollectAndCount(scheduleLastCollectionFailed))
+}
@@ -0,0 +1,127 @@
+package schedule
+
+import (
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+const (
+	DefaultImage  = "replicated/troubleshoot:latest"
+	DefaultRetain = 5
+
+	ScheduleLabelKey = "troubleshoot.sh/support-bundle-schedule"
+
+	bundlesVolumeName = "bundles"
+	bundlesMountPath  = "/bundles"
+	tokenEnvVar       = "TROUBLESHOOT_SCHEDULE_TOKEN"
+
+	notificationTokenEnvVar = "TROUBLESHOOT_NOTIFICATION_TOKEN"
+)
+
+func scheduleLabels(schedule *troubleshootv1beta2.SupportBundleSchedule) map[string]string {
+	return map[string]string{
+		ScheduleLabelKey:    schedule.Name,
+		"troubleshoot-role": "scheduled-collector",
+	}
+}
+
+// setCronJobSpec sets the fields of the CronJob that runs the collections of a schedule. Kubernetes
+// takes care of the cron schedule, each job runs "support-bundle schedule run" which collects a
+// bundle, stores it and records it in the status of the schedule.
+func setCronJobSpec(cronJob *batchv1.CronJob, schedule *troubleshootv1beta2.SupportBundleSchedule) {
+	labels := scheduleLabels(schedule)
+
+	if cronJob.Labels == nil {
+		cronJob.Labels = map[string]string{}
+	}
+	for k, v := range labels {
+		cronJob.Labels[k] = v
+	}
+
+	cronJob.Spec.Schedule = schedule.Spec.Schedule
+	cronJob.Spec.Suspend = ptr.To(schedule.Spec.Suspend)
+	// a collection can take longer than the interval of a tight schedule, don't pile them up
+	cronJob.Spec.ConcurrencyPolicy = batchv1.ForbidConcurrent
+	cronJob.Spec.SuccessfulJobsHistoryLimit = ptr.To(int32(1))
+	cronJob.Spec.FailedJobsHistoryLimit = ptr.To(int32(1))
+
+	cronJob.Spec.JobTemplate = batchv1.JobTemplateSpec{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: labels,
+		},
+		Spec: jobSpecForSchedule(schedule),
+	}
+}
+
+// jobSpecForSchedule returns the spec of a job that runs a single collection for the schedule,
+// the args are appended to the "support-bundle schedule run" command.
+func jobSpecForSchedule(schedule *troubleshootv1beta2.SupportBundleSchedule, args ...string) batchv1.JobSpec {
+	image := schedule.Spec.Image
+	if image == "" {
+		image = DefaultImage
+	}
+
+	command := []string{
+		"support-bundle", "schedule", "run", schedule.Name,
+		"--namespace", schedule.Namespace,
+	}
+
+	container := corev1.Container{
+		Name:            "collector",
+		Image:           image,
+		ImagePullPolicy: corev1.PullIfNotPresent,
+		Command:         append(command, args...),
+	}
+	volumes := []corev1.Volume{}
+
+	storage := schedule.Spec.Storage
+	if pvc := storage.PersistentVolumeClaim; pvc != nil {
+		container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+			Name:      bundlesVolumeName,
+			MountPath: bundlesMountPath,
+			SubPath:   pvc.SubPath,
+		})
+		volumes = append(volumes, corev1.Volume{
+			Name: bundlesVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+					ClaimName: pvc.ClaimName,
+				},
+			},
+		})
+	}
+	if objectStorage := storage.ObjectStorage; objectStorage != nil && objectStorage.TokenSecretRef != nil {
+		container.Env = append(container.Env, corev1.EnvVar{
+			Name: tokenEnvVar,
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: objectStorage.TokenSecretRef,
+			},
+		})
+	}
+	if notification := schedule.Spec.Notification; notification != nil && notification.TokenSecretRef != nil {
+		container.Env = append(container.Env, corev1.EnvVar{
+			Name: notificationTokenEnvVar,
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: notification.TokenSecretRef,
+			},
+		})
+	}
+
+	return batchv1.JobSpec{
+		BackoffLimit: ptr.To(int32(0)),
+		Template: corev1.PodTemplateSpec{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: scheduleLabels(schedule),
+			},
+			Spec: corev1.PodSpec{
+				RestartPolicy:      corev1.RestartPolicyNever,
+				ServiceAccountName: schedule.Spec.ServiceAccountName,
+				Containers:         []corev1.Container{container},
+				Volumes:            volumes,
+			},
+		},
+	}
+}
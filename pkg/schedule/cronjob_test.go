@@ -0,0 +1,131 @@
+package schedule
+
+import (
+	"testing"
+
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_setCronJobSpec(t *testing.T) {
+	schedule := &troubleshootv1beta2.SupportBundleSchedule{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "nightly",
+			Namespace: "troubleshoot",
+		},
+		Spec: troubleshootv1beta2.SupportBundleScheduleSpec{
+			Schedule: "0 2 * * *",
+			Storage: troubleshootv1beta2.ScheduledBundleStorage{
+				PersistentVolumeClaim: &troubleshootv1beta2.ScheduledBundlePersistentVolumeClaim{
+					ClaimName: "bundles",
+					SubPath:   "nightly",
+				},
+			},
+			ServiceAccountName: "troubleshoot",
+		},
+	}
+
+	cronJob := &batchv1.CronJob{}
+	setCronJobSpec(cronJob, schedule)
+
+	assert.Equal(t, "0 2 * * *", cronJob.Spec.Schedule)
+	assert.False(t, *cronJob.Spec.Suspend)
+	assert.Equal(t, batchv1.ForbidConcurrent, cronJob.Spec.ConcurrencyPolicy)
+	assert.Equal(t, "nightly", cronJob.Labels[ScheduleLabelKey])
+
+	podSpec := cronJob.Spec.JobTemplate.Spec.Template.Spec
+	assert.Equal(t, corev1.RestartPolicyNever, podSpec.RestartPolicy)
+	assert.Equal(t, "troubleshoot", podSpec.ServiceAccountName)
+	require.Len(t, podSpec.Volumes, 1)
+	assert.Equal(t, "bundles", podSpec.Volumes[0].PersistentVolumeClaim.ClaimName)
+
+	require.Len(t, podSpec.Containers, 1)
+	container := podSpec.Containers[0]
+	assert.Equal(t, DefaultImage, container.Image)
+	assert.Equal(t, []string{"support-bundle", "schedule", "run", "nightly", "--namespace", "troubleshoot"}, container.Command)
+	assert.Equal(t, []corev1.VolumeMount{
+		{Name: bundlesVolumeName, MountPath: bundlesMountPath, SubPath: "nightly"},
+	}, container.VolumeMounts)
+	assert.Empty(t, container.Env)
+}
+
+func Test_setCronJobSpec_ObjectStorage(t *testing.T) {
+	tokenRef := &corev1.SecretKeySelector{
+		LocalObjectReference: corev1.LocalObjectReference{Name: "bundle-storage"},
+		Key:                  "token",
+	}
+	schedule := &troubleshootv1beta2.SupportBundleSchedule{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "hourly",
+			Namespace: "default",
+		},
+		Spec: troubleshootv1beta2.SupportBundleScheduleSpec{
+			Schedule: "@hourly",
+			Suspend:  true,
+			Image:    "registry.example.com/troubleshoot:v1",
+			Storage: troubleshootv1beta2.ScheduledBundleStorage{
+				ObjectStorage: &troubleshootv1beta2.ScheduledBundleObjectStorage{
+					URI:            "https://storage.example.com/bundles",
+					TokenSecretRef: tokenRef,
+				},
+			},
+		},
+	}
+
+	cronJob := &batchv1.CronJob{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: map[string]string{"app": "existing"},
+		},
+	}
+	setCronJobSpec(cronJob, schedule)
+
+	assert.True(t, *cronJob.Spec.Suspend)
+	assert.Equal(t, "existing", cronJob.Labels["app"])
+
+	podSpec := cronJob.Spec.JobTemplate.Spec.Template.Spec
+	assert.Empty(t, podSpec.Volumes)
+	container := podSpec.Containers[0]
+	assert.Equal(t, "registry.example.com/troubleshoot:v1", container.Image)
+	assert.Empty(t, container.VolumeMounts)
+	require.Len(t, container.Env, 1)
+	assert.Equal(t, tokenEnvVar, container.Env[0].Name)
+	assert.Equal(t, tokenRef, container.Env[0].ValueFrom.SecretKeyRef)
+}
+
+func Test_jobSpecForSchedule(t *testing.T) {
+	tokenRef := &corev1.SecretKeySelector{
+		LocalObjectReference: corev1.LocalObjectReference{Name: "webhook"},
+		Key:                  "token",
+	}
+	schedule := &troubleshootv1beta2.SupportBundleSchedule{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "crashes",
+			Namespace: "default",
+		},
+		Spec: troubleshootv1beta2.SupportBundleScheduleSpec{
+			Storage: troubleshootv1beta2.ScheduledBundleStorage{
+				PersistentVolumeClaim: &troubleshootv1beta2.ScheduledBundlePersistentVolumeClaim{ClaimName: "bundles"},
+			},
+			Notification: &troubleshootv1beta2.ScheduledBundleNotification{
+				URI:            "https://hooks.example.com",
+				TokenSecretRef: tokenRef,
+			},
+		},
+	}
+
+	spec := jobSpecForSchedule(schedule, "--trigger", "node worker-1 is NotReady")
+
+	assert.Equal(t, "crashes", spec.Template.Labels[ScheduleLabelKey])
+	container := spec.Template.Spec.Containers[0]
+	assert.Equal(t, []string{
+		"support-bundle", "schedule", "run", "crashes", "--namespace", "default",
+		"--trigger", "node worker-1 is NotReady",
+	}, container.Command)
+	require.Len(t, container.Env, 1)
+	assert.Equal(t, notificationTokenEnvVar, container.Env[0].Name)
+	assert.Equal(t, tokenRef, container.Env[0].ValueFrom.SecretKeyRef)
+}
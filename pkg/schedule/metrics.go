@@ -0,0 +1,76 @@
+package schedule
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// The jobs that collect the bundles of a schedule exit when they are done, so the results of
+// scheduled collections are exported by the controller from the status of the schedules, on the
+// metrics endpoint of the manager.
+var (
+	scheduleLastScheduleTime = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "troubleshoot",
+		Subsystem: "schedule",
+		Name:      "last_collection_timestamp_seconds",
+		Help:      "Unix time of the last collection of the support bundle schedule.",
+	}, []string{"namespace", "schedule"})
+	scheduleLastSuccessfulTime = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "troubleshoot",
+		Subsystem: "schedule",
+		Name:      "last_successful_collection_timestamp_seconds",
+		Help:      "Unix time of the last collection of the support bundle schedule that saved a bundle.",
+	}, []string{"namespace", "schedule"})
+	scheduleLastCollectionFailed = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "troubleshoot",
+		Subsystem: "schedule",
+		Name:      "last_collection_failed",
+		Help:      "1 when the last collection of the support bundle schedule had an error, 0 otherwise.",
+	}, []string{"namespace", "schedule"})
+	scheduleRetainedBundles = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "troubleshoot",
+		Subsystem: "schedule",
+		Name:      "retained_bundles",
+		Help:      "Number of bundles retained for the support bundle schedule.",
+	}, []string{"namespace", "schedule"})
+)
+
+func init() {
+	ctrlmetrics.Registry.MustRegister(
+		scheduleLastScheduleTime,
+		scheduleLastSuccessfulTime,
+		scheduleLastCollectionFailed,
+		scheduleRetainedBundles,
+	)
+}
+
+// recordScheduleMetrics sets the metrics of a schedule from its status. Times that are not set yet
+// are left out, so that they are not mistaken for collections at the epoch.
+func recordScheduleMetrics(schedule *troubleshootv1beta2.SupportBundleSchedule) {
+	labels := prometheus.Labels{"namespace": schedule.Namespace, "schedule": schedule.Name}
+	status := schedule.Status
+
+	if status.LastScheduleTime != nil {
+		scheduleLastScheduleTime.With(labels).Set(float64(status.LastScheduleTime.Unix()))
+
+		failed := 0.0
+		if status.LastError != "" {
+			failed = 1
+		}
+		scheduleLastCollectionFailed.With(labels).Set(failed)
+	}
+	if status.LastSuccessfulTime != nil {
+		scheduleLastSuccessfulTime.With(labels).Set(float64(status.LastSuccessfulTime.Unix()))
+	}
+	scheduleRetainedBundles.With(labels).Set(float64(len(status.Bundles)))
+}
+
+// deleteScheduleMetrics removes the metrics of a schedule that was deleted
+func deleteScheduleMetrics(namespace string, name string) {
+	labels := prometheus.Labels{"namespace": namespace, "schedule": name}
+	scheduleLastScheduleTime.Delete(labels)
+	scheduleLastSuccessfulTime.Delete(labels)
+	scheduleLastCollectionFailed.Delete(labels)
+	scheduleRetainedBundles.Delete(labels)
+}
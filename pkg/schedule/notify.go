@@ -0,0 +1,52 @@
+package schedule
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+)
+
+// NotificationPayload is the JSON body posted to the notification webhook of a schedule when a
+// collection completes.
+type NotificationPayload struct {
+	Schedule  string                               `json:"schedule"`
+	Namespace string                               `json:"namespace"`
+	Trigger   string                               `json:"trigger,omitempty"`
+	Bundle    *troubleshootv1beta2.ScheduledBundle `json:"bundle,omitempty"`
+	Error     string                               `json:"error,omitempty"`
+}
+
+func notify(ctx context.Context, httpClient *http.Client, notification *troubleshootv1beta2.ScheduledBundleNotification, payload NotificationPayload) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal notification")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, notification.URI, bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrap(err, "failed to create request")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if token := os.Getenv(notificationTokenEnvVar); token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "failed to post notification")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return errors.Errorf("unexpected status code %d posting notification", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package schedule
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_notify(t *testing.T) {
+	t.Setenv(notificationTokenEnvVar, "secret")
+
+	var received NotificationPayload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+	}))
+	defer server.Close()
+
+	payload := NotificationPayload{
+		Schedule:  "crashes",
+		Namespace: "app",
+		Trigger:   "node worker-1 is NotReady",
+		Bundle: &troubleshootv1beta2.ScheduledBundle{
+			Name:     "crashes-2024-01-01T12_00_00.tar.gz",
+			Location: "/bundles/crashes-2024-01-01T12_00_00.tar.gz",
+		},
+	}
+	notification := &troubleshootv1beta2.ScheduledBundleNotification{URI: server.URL}
+
+	require.NoError(t, notify(context.Background(), server.Client(), notification, payload))
+	assert.Equal(t, payload.Trigger, received.Trigger)
+	assert.Equal(t, payload.Bundle.Location, received.Bundle.Location)
+}
+
+func Test_notify_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	notification := &troubleshootv1beta2.ScheduledBundleNotification{URI: server.URL}
+	err := notify(context.Background(), server.Client(), notification, NotificationPayload{})
+	assert.ErrorContains(t, err, "unexpected status code 500")
+}
@@ -0,0 +1,212 @@
+package schedule
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/supportbundle"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Run collects a support bundle for the schedule, stores it, removes the bundles that are past
+// the retention limit and records the result in the status of the schedule. It is run by the
+// jobs of the schedule's CronJob, and by those started by triggers, with a description of the
+// trigger.
+func Run(ctx context.Context, c client.Client, restConfig *rest.Config, namespace string, name string, trigger string) error {
+	schedule := &troubleshootv1beta2.SupportBundleSchedule{}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, schedule); err != nil {
+		return errors.Wrapf(err, "failed to get support bundle schedule %s/%s", namespace, name)
+	}
+
+	policy, err := retentionPolicy(schedule.Spec)
+	if err != nil {
+		return err
+	}
+
+	now := metav1.Now()
+	bundle, collectErr := collect(ctx, schedule, restConfig, now)
+	if bundle != nil {
+		bundle.Trigger = trigger
+	}
+
+	var removed []troubleshootv1beta2.ScheduledBundle
+	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		latest := &troubleshootv1beta2.SupportBundleSchedule{}
+		if err := c.Get(ctx, client.ObjectKeyFromObject(schedule), latest); err != nil {
+			return err
+		}
+		removed = recordCollection(&latest.Status, bundle, collectErr, policy, now)
+		return c.Status().Update(ctx, latest)
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to update support bundle schedule status")
+	}
+
+	// bundles are only removed once they are no longer referenced by the status
+	if len(removed) > 0 {
+		store, err := newBundleStore(schedule.Spec.Storage, bundlesMountPath, nil)
+		if err != nil {
+			return errors.Wrap(err, "failed to create bundle store")
+		}
+		for _, b := range removed {
+			if err := store.remove(ctx, b.Location); err != nil {
+				klog.Errorf("Failed to remove bundle %s: %v", b.Name, err)
+			}
+		}
+	}
+
+	if notification := schedule.Spec.Notification; notification != nil {
+		payload := NotificationPayload{
+			Schedule:  schedule.Name,
+			Namespace: schedule.Namespace,
+			Trigger:   trigger,
+			Bundle:    bundle,
+		}
+		if collectErr != nil {
+			payload.Error = collectErr.Error()
+		}
+		if err := notify(ctx, nil, notification, payload); err != nil {
+			klog.Errorf("Failed to send notification for support bundle schedule %s/%s: %v", schedule.Namespace, schedule.Name, err)
+		}
+	}
+
+	return collectErr
+}
+
+// collect runs the collection and saves the archive to the schedule's storage. A bundle is
+// returned along with an error when some of the collectors failed.
+func collect(ctx context.Context, schedule *troubleshootv1beta2.SupportBundleSchedule, restConfig *rest.Config, now metav1.Time) (*troubleshootv1beta2.ScheduledBundle, error) {
+	store, err := newBundleStore(schedule.Spec.Storage, bundlesMountPath, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create bundle store")
+	}
+
+	tmpDir, err := os.MkdirTemp("", "scheduled-bundle")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create temp dir")
+	}
+	defer os.RemoveAll(tmpDir)
+
+	progressChan := make(chan interface{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for msg := range progressChan {
+			klog.V(2).Infof("Collection progress: %v", msg)
+		}
+	}()
+
+	name := fmt.Sprintf("%s-%s.tar.gz", schedule.Name, now.UTC().Format("2006-01-02T15_04_05"))
+	response, collectErr := supportbundle.CollectSupportBundleFromSpec(&schedule.Spec.SupportBundle, nil, supportbundle.SupportBundleCreateOpts{
+		CollectorProgressCallback: func(c chan interface{}, msg string) { c <- msg },
+		CollectWithoutPermissions: true,
+		KubernetesRestConfig:      restConfig,
+		ProgressChan:              progressChan,
+		OutputPath:                filepath.Join(tmpDir, name),
+		Redact:                    true,
+	})
+	close(progressChan)
+	<-done
+
+	if response == nil {
+		return nil, errors.Wrap(collectErr, "failed to collect support bundle")
+	}
+
+	info, err := os.Stat(response.ArchivePath)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to stat support bundle")
+	}
+
+	location, err := store.save(ctx, name, response.ArchivePath)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to store support bundle")
+	}
+
+	bundle := &troubleshootv1beta2.ScheduledBundle{
+		Name:        name,
+		Location:    location,
+		CollectedAt: now,
+		Size:        info.Size(),
+	}
+	return bundle, collectErr
+}
+
+// recordCollection records the result of a collection in the status, and returns the bundles
+// that were dropped because the retention policy does not keep them.
+func recordCollection(status *troubleshootv1beta2.SupportBundleScheduleStatus, bundle *troubleshootv1beta2.ScheduledBundle, collectErr error, policy supportbundle.RetentionPolicy, now metav1.Time) []troubleshootv1beta2.ScheduledBundle {
+	status.LastScheduleTime = &now
+	status.LastError = ""
+	if collectErr != nil {
+		status.LastError = collectErr.Error()
+	}
+
+	if bundle == nil {
+		return nil
+	}
+
+	status.LastSuccessfulTime = &now
+	bundles := append([]troubleshootv1beta2.ScheduledBundle{*bundle}, status.Bundles...)
+
+	archives := []supportbundle.BundleArchive{}
+	for _, b := range bundles {
+		archives = append(archives, supportbundle.BundleArchive{
+			Path:    b.Location,
+			Size:    b.Size,
+			ModTime: b.CollectedAt.Time,
+		})
+	}
+	_, pruned := policy.Select(archives, now.Time)
+	if len(pruned) == 0 {
+		status.Bundles = bundles
+		return nil
+	}
+
+	prunedLocations := map[string]bool{}
+	for _, archive := range pruned {
+		prunedLocations[archive.Path] = true
+	}
+
+	status.Bundles = []troubleshootv1beta2.ScheduledBundle{}
+	removed := []troubleshootv1beta2.ScheduledBundle{}
+	for _, b := range bundles {
+		if prunedLocations[b.Location] {
+			removed = append(removed, b)
+		} else {
+			status.Bundles = append(status.Bundles, b)
+		}
+	}
+	return removed
+}
+
+// retentionPolicy returns the policy that prunes the bundles of a schedule
+func retentionPolicy(spec troubleshootv1beta2.SupportBundleScheduleSpec) (supportbundle.RetentionPolicy, error) {
+	policy := supportbundle.RetentionPolicy{
+		MaxCount: retainLimit(spec.Retain),
+	}
+	if spec.RetainFor != nil {
+		policy.MaxAge = spec.RetainFor.Duration
+	}
+
+	maxTotalSize, err := supportbundle.ParseRetentionSize(spec.RetainSize)
+	if err != nil {
+		return supportbundle.RetentionPolicy{}, errors.Wrap(err, "invalid retainSize")
+	}
+	policy.MaxTotalSize = maxTotalSize
+
+	return policy, nil
+}
+
+func retainLimit(retain int) int {
+	if retain <= 0 {
+		return DefaultRetain
+	}
+	return retain
+}
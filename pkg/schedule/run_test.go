@@ -0,0 +1,109 @@
+package schedule
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/supportbundle"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_recordCollection(t *testing.T) {
+	earlier := metav1.NewTime(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	now := metav1.NewTime(time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC))
+
+	bundle := func(name string) troubleshootv1beta2.ScheduledBundle {
+		return troubleshootv1beta2.ScheduledBundle{Name: name, Location: "/bundles/" + name}
+	}
+
+	t.Run("prunes bundles past the retention limit", func(t *testing.T) {
+		status := troubleshootv1beta2.SupportBundleScheduleStatus{
+			LastError: "previous failure",
+			Bundles:   []troubleshootv1beta2.ScheduledBundle{bundle("c"), bundle("b"), bundle("a")},
+		}
+		latest := bundle("d")
+
+		removed := recordCollection(&status, &latest, nil, supportbundle.RetentionPolicy{MaxCount: 2}, now)
+
+		assert.Equal(t, []troubleshootv1beta2.ScheduledBundle{bundle("b"), bundle("a")}, removed)
+		assert.Equal(t, []troubleshootv1beta2.ScheduledBundle{bundle("d"), bundle("c")}, status.Bundles)
+		assert.Equal(t, &now, status.LastScheduleTime)
+		assert.Equal(t, &now, status.LastSuccessfulTime)
+		assert.Empty(t, status.LastError)
+	})
+
+	t.Run("prunes bundles past the retention age and size", func(t *testing.T) {
+		old := bundle("a")
+		old.CollectedAt = metav1.NewTime(now.Add(-30 * 24 * time.Hour))
+		large := bundle("b")
+		large.CollectedAt = earlier
+		large.Size = 900
+		status := troubleshootv1beta2.SupportBundleScheduleStatus{
+			Bundles: []troubleshootv1beta2.ScheduledBundle{large, old},
+		}
+		latest := bundle("c")
+		latest.CollectedAt = now
+		latest.Size = 200
+
+		removed := recordCollection(&status, &latest, nil, supportbundle.RetentionPolicy{
+			MaxCount:     DefaultRetain,
+			MaxAge:       7 * 24 * time.Hour,
+			MaxTotalSize: 1000,
+		}, now)
+
+		assert.Equal(t, []troubleshootv1beta2.ScheduledBundle{large, old}, removed)
+		assert.Equal(t, []troubleshootv1beta2.ScheduledBundle{latest}, status.Bundles)
+	})
+
+	t.Run("keeps bundles within the retention limit", func(t *testing.T) {
+		status := troubleshootv1beta2.SupportBundleScheduleStatus{}
+		latest := bundle("a")
+
+		removed := recordCollection(&status, &latest, errors.New("failed to run collectors"), supportbundle.RetentionPolicy{MaxCount: DefaultRetain}, now)
+
+		assert.Empty(t, removed)
+		assert.Equal(t, []troubleshootv1beta2.ScheduledBundle{bundle("a")}, status.Bundles)
+		assert.Equal(t, &now, status.LastSuccessfulTime)
+		assert.Equal(t, "failed to run collectors", status.LastError)
+	})
+
+	t.Run("failed collection", func(t *testing.T) {
+		status := troubleshootv1beta2.SupportBundleScheduleStatus{
+			LastSuccessfulTime: &earlier,
+			Bundles:            []troubleshootv1beta2.ScheduledBundle{bundle("a")},
+		}
+
+		removed := recordCollection(&status, nil, errors.New("failed to store support bundle"), supportbundle.RetentionPolicy{MaxCount: DefaultRetain}, now)
+
+		assert.Empty(t, removed)
+		assert.Equal(t, []troubleshootv1beta2.ScheduledBundle{bundle("a")}, status.Bundles)
+		assert.Equal(t, &now, status.LastScheduleTime)
+		assert.Equal(t, &earlier, status.LastSuccessfulTime)
+		assert.Equal(t, "failed to store support bundle", status.LastError)
+	})
+}
+
+func Test_retainLimit(t *testing.T) {
+	assert.Equal(t, DefaultRetain, retainLimit(0))
+	assert.Equal(t, 3, retainLimit(3))
+}
+
+func Test_retentionPolicy(t *testing.T) {
+	policy, err := retentionPolicy(troubleshootv1beta2.SupportBundleScheduleSpec{
+		RetainFor:  &metav1.Duration{Duration: 168 * time.Hour},
+		RetainSize: "10Gi",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, supportbundle.RetentionPolicy{
+		MaxAge:       168 * time.Hour,
+		MaxCount:     DefaultRetain,
+		MaxTotalSize: 10 << 30,
+	}, policy)
+
+	_, err = retentionPolicy(troubleshootv1beta2.SupportBundleScheduleSpec{RetainSize: "lots"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,161 @@
+package schedule
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+)
+
+// bundleStore keeps the archives of scheduled collections.
+type bundleStore interface {
+	// save stores the archive under name and returns its location.
+	save(ctx context.Context, name string, archivePath string) (string, error)
+	// remove deletes a bundle that was saved to location.
+	remove(ctx context.Context, location string) error
+}
+
+func newBundleStore(storage troubleshootv1beta2.ScheduledBundleStorage, bundlesDir string, httpClient *http.Client) (bundleStore, error) {
+	switch {
+	case storage.PersistentVolumeClaim != nil && storage.ObjectStorage != nil:
+		return nil, errors.New("only one of persistentVolumeClaim and objectStorage can be set")
+	case storage.PersistentVolumeClaim != nil:
+		return &volumeStore{dir: bundlesDir}, nil
+	case storage.ObjectStorage != nil:
+		if storage.ObjectStorage.URI == "" {
+			return nil, errors.New("objectStorage uri is required")
+		}
+		return &objectStore{
+			uri:    strings.TrimSuffix(storage.ObjectStorage.URI, "/"),
+			token:  os.Getenv(tokenEnvVar),
+			client: httpClient,
+		}, nil
+	}
+
+	return nil, errors.New("storage is required")
+}
+
+// volumeStore writes bundles to a directory the persistent volume claim is mounted on.
+type volumeStore struct {
+	dir string
+}
+
+func (s *volumeStore) save(ctx context.Context, name string, archivePath string) (string, error) {
+	location := filepath.Join(s.dir, name)
+	if err := copyFile(archivePath, location); err != nil {
+		return "", errors.Wrapf(err, "failed to copy bundle to %s", location)
+	}
+	return location, nil
+}
+
+func (s *volumeStore) remove(ctx context.Context, location string) error {
+	if filepath.Dir(location) != filepath.Clean(s.dir) {
+		return errors.Errorf("bundle %s is not in %s", location, s.dir)
+	}
+	if err := os.Remove(location); err != nil && !os.IsNotExist(err) {
+		return errors.Wrapf(err, "failed to remove bundle %s", location)
+	}
+	return nil
+}
+
+func copyFile(src string, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		os.Remove(dst)
+		return err
+	}
+	return out.Close()
+}
+
+// objectStore uploads bundles with a PUT request to <uri>/<name>, which works with presigned
+// bucket URLs and most object storage gateways.
+type objectStore struct {
+	uri    string
+	token  string
+	client *http.Client
+}
+
+func (s *objectStore) save(ctx context.Context, name string, archivePath string) (string, error) {
+	f, err := os.Open(archivePath)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to open bundle")
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return "", errors.Wrap(err, "failed to stat bundle")
+	}
+
+	location := fmt.Sprintf("%s/%s", s.uri, name)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, location, f)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to create request")
+	}
+	req.ContentLength = info.Size()
+	req.Header.Set("Content-Type", "application/gzip")
+
+	if err := s.do(req); err != nil {
+		return "", errors.Wrapf(err, "failed to upload bundle to %s", location)
+	}
+	return location, nil
+}
+
+func (s *objectStore) remove(ctx context.Context, location string) error {
+	if !strings.HasPrefix(location, s.uri+"/") {
+		return errors.Errorf("bundle %s is not in %s", location, s.uri)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, location, nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to create request")
+	}
+
+	if err := s.do(req); err != nil {
+		return errors.Wrapf(err, "failed to delete bundle %s", location)
+	}
+	return nil
+}
+
+func (s *objectStore) do(req *http.Request) error {
+	if s.token != "" {
+		req.Header.Set("Authorization", "Bearer "+s.token)
+	}
+
+	client := s.client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	// a bundle that is already gone does not need to be deleted again
+	if req.Method == http.MethodDelete && resp.StatusCode == http.StatusNotFound {
+		return nil
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return errors.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package schedule
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeTestArchive(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "bundle.tar.gz")
+	require.NoError(t, os.WriteFile(path, []byte("bundle"), 0644))
+	return path
+}
+
+func Test_newBundleStore(t *testing.T) {
+	_, err := newBundleStore(troubleshootv1beta2.ScheduledBundleStorage{}, bundlesMountPath, nil)
+	assert.EqualError(t, err, "storage is required")
+
+	_, err = newBundleStore(troubleshootv1beta2.ScheduledBundleStorage{
+		PersistentVolumeClaim: &troubleshootv1beta2.ScheduledBundlePersistentVolumeClaim{ClaimName: "bundles"},
+		ObjectStorage:         &troubleshootv1beta2.ScheduledBundleObjectStorage{URI: "https://example.com"},
+	}, bundlesMountPath, nil)
+	assert.Error(t, err)
+
+	_, err = newBundleStore(troubleshootv1beta2.ScheduledBundleStorage{
+		ObjectStorage: &troubleshootv1beta2.ScheduledBundleObjectStorage{},
+	}, bundlesMountPath, nil)
+	assert.EqualError(t, err, "objectStorage uri is required")
+}
+
+func Test_volumeStore(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	store := &volumeStore{dir: dir}
+
+	location, err := store.save(ctx, "nightly-1.tar.gz", writeTestArchive(t))
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "nightly-1.tar.gz"), location)
+
+	contents, err := os.ReadFile(location)
+	require.NoError(t, err)
+	assert.Equal(t, "bundle", string(contents))
+
+	require.NoError(t, store.remove(ctx, location))
+	assert.NoFileExists(t, location)
+	// removing a bundle that is already gone is not an error
+	require.NoError(t, store.remove(ctx, location))
+
+	assert.Error(t, store.remove(ctx, "/etc/passwd"))
+}
+
+func Test_objectStore(t *testing.T) {
+	ctx := context.Background()
+
+	objects := map[string]string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.Method {
+		case http.MethodPut:
+			body, _ := io.ReadAll(r.Body)
+			objects[r.URL.Path] = string(body)
+		case http.MethodDelete:
+			if _, ok := objects[r.URL.Path]; !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			delete(objects, r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	store := &objectStore{uri: server.URL + "/bundles", token: "secret", client: server.Client()}
+
+	location, err := store.save(ctx, "nightly-1.tar.gz", writeTestArchive(t))
+	require.NoError(t, err)
+	assert.Equal(t, server.URL+"/bundles/nightly-1.tar.gz", location)
+	assert.Equal(t, map[string]string{"/bundles/nightly-1.tar.gz": "bundle"}, objects)
+
+	require.NoError(t, store.remove(ctx, location))
+	assert.Empty(t, objects)
+	require.NoError(t, store.remove(ctx, location))
+
+	assert.Error(t, store.remove(ctx, "https://other.example.com/bundles/nightly-1.tar.gz"))
+
+	store.token = "wrong"
+	_, err = store.save(ctx, "nightly-2.tar.gz", writeTestArchive(t))
+	assert.ErrorContains(t, err, "unexpected status code 401")
+}
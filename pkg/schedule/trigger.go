@@ -0,0 +1,252 @@
+package schedule
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const (
+	DefaultTriggerCooldown = 30 * time.Minute
+
+	// events last seen before this window are not new, e.g. those listed when the controller starts
+	eventTriggerWindow = 5 * time.Minute
+	// finished triggered jobs are removed after a day, the bundles they collected are kept
+	triggeredJobTTL = 24 * 60 * 60
+)
+
+// triggerReconciler starts a collection for each schedule with a trigger that matches a pod,
+// node or event. Triggered collections of a schedule are at least the schedule's cooldown apart.
+type triggerReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+
+	newObject func() client.Object
+	now       func() time.Time
+}
+
+func (r *triggerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	obj := r.newObject()
+	if err := r.Get(ctx, req.NamespacedName, obj); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	schedules := &troubleshootv1beta2.SupportBundleScheduleList{}
+	if err := r.List(ctx, schedules); err != nil {
+		return ctrl.Result{}, errors.Wrap(err, "failed to list support bundle schedules")
+	}
+
+	now := r.now()
+	for i := range schedules.Items {
+		schedule := &schedules.Items[i]
+		if schedule.Spec.Suspend {
+			continue
+		}
+
+		reason, err := matchTriggers(schedule, obj, now)
+		if err != nil {
+			klog.Errorf("Failed to evaluate triggers of support bundle schedule %s/%s: %v", schedule.Namespace, schedule.Name, err)
+			continue
+		}
+		if reason == "" {
+			continue
+		}
+
+		if err := r.startCollection(ctx, schedule, reason, now); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// startCollection records the trigger in the status of the schedule before creating the job, so
+// that concurrent triggers of the same schedule conflict and only the first starts a collection.
+func (r *triggerReconciler) startCollection(ctx context.Context, schedule *troubleshootv1beta2.SupportBundleSchedule, reason string, now time.Time) error {
+	started := false
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		started = false
+		latest := &troubleshootv1beta2.SupportBundleSchedule{}
+		if err := r.Get(ctx, client.ObjectKeyFromObject(schedule), latest); err != nil {
+			return err
+		}
+		if inTriggerCooldown(latest, now) {
+			return nil
+		}
+		latest.Status.LastTriggerTime = &metav1.Time{Time: now}
+		if err := r.Status().Update(ctx, latest); err != nil {
+			return err
+		}
+		schedule = latest
+		started = true
+		return nil
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to update support bundle schedule %s/%s", schedule.Namespace, schedule.Name)
+	}
+	if !started {
+		return nil
+	}
+
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: schedule.Name + "-triggered-",
+			Namespace:    schedule.Namespace,
+			Labels:       scheduleLabels(schedule),
+		},
+		Spec: jobSpecForSchedule(schedule, "--trigger", reason),
+	}
+	job.Spec.TTLSecondsAfterFinished = ptr.To(int32(triggeredJobTTL))
+	if err := controllerutil.SetControllerReference(schedule, job, r.Scheme); err != nil {
+		return errors.Wrap(err, "failed to set owner of triggered job")
+	}
+	if err := r.Create(ctx, job); err != nil {
+		return errors.Wrapf(err, "failed to create triggered job for support bundle schedule %s/%s", schedule.Namespace, schedule.Name)
+	}
+
+	klog.Infof("Started collection for support bundle schedule %s/%s: %s", schedule.Namespace, schedule.Name, reason)
+	return nil
+}
+
+func inTriggerCooldown(schedule *troubleshootv1beta2.SupportBundleSchedule, now time.Time) bool {
+	last := schedule.Status.LastTriggerTime
+	return last != nil && now.Sub(last.Time) < triggerCooldown(schedule)
+}
+
+func triggerCooldown(schedule *troubleshootv1beta2.SupportBundleSchedule) time.Duration {
+	if schedule.Spec.TriggerCooldown == nil {
+		return DefaultTriggerCooldown
+	}
+	return schedule.Spec.TriggerCooldown.Duration
+}
+
+// matchTriggers returns a description of the first trigger of the schedule that matches the
+// object, or an empty string when none do.
+func matchTriggers(schedule *troubleshootv1beta2.SupportBundleSchedule, obj client.Object, now time.Time) (string, error) {
+	for _, trigger := range schedule.Spec.Triggers {
+		var reason string
+		var err error
+
+		switch o := obj.(type) {
+		case *corev1.Pod:
+			if trigger.CrashLoopBackOff != nil {
+				reason, err = matchCrashLoopBackOff(trigger.CrashLoopBackOff, schedule.Namespace, o)
+			}
+		case *corev1.Node:
+			if trigger.NodeNotReady != nil {
+				reason, err = matchNodeNotReady(trigger.NodeNotReady, o)
+			}
+		case *corev1.Event:
+			if trigger.Event != nil {
+				reason = matchEvent(trigger.Event, schedule.Namespace, o, now.Add(-eventTriggerWindow))
+			}
+		}
+
+		if err != nil {
+			return "", err
+		}
+		if reason != "" {
+			return reason, nil
+		}
+	}
+
+	return "", nil
+}
+
+func matchCrashLoopBackOff(trigger *troubleshootv1beta2.CrashLoopBackOffTrigger, scheduleNamespace string, pod *corev1.Pod) (string, error) {
+	if !matchNamespace(trigger.Namespaces, scheduleNamespace, pod.Namespace) {
+		return "", nil
+	}
+
+	selector, err := labels.Parse(strings.Join(trigger.Selector, ","))
+	if err != nil {
+		return "", errors.Wrap(err, "failed to parse crashLoopBackOff selector")
+	}
+	if !selector.Matches(labels.Set(pod.Labels)) {
+		return "", nil
+	}
+
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.State.Waiting != nil && status.State.Waiting.Reason == "CrashLoopBackOff" {
+			return fmt.Sprintf("container %s of pod %s/%s is in CrashLoopBackOff", status.Name, pod.Namespace, pod.Name), nil
+		}
+	}
+	return "", nil
+}
+
+func matchNodeNotReady(trigger *troubleshootv1beta2.NodeNotReadyTrigger, node *corev1.Node) (string, error) {
+	selector, err := labels.Parse(strings.Join(trigger.Selector, ","))
+	if err != nil {
+		return "", errors.Wrap(err, "failed to parse nodeNotReady selector")
+	}
+	if !selector.Matches(labels.Set(node.Labels)) {
+		return "", nil
+	}
+
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == corev1.NodeReady && condition.Status != corev1.ConditionTrue {
+			return fmt.Sprintf("node %s is NotReady", node.Name), nil
+		}
+	}
+	return "", nil
+}
+
+func matchEvent(trigger *troubleshootv1beta2.EventTrigger, scheduleNamespace string, event *corev1.Event, since time.Time) string {
+	if !matchNamespace(trigger.Namespaces, scheduleNamespace, event.Namespace) {
+		return ""
+	}
+	if trigger.InvolvedObjectKind != "" && trigger.InvolvedObjectKind != event.InvolvedObject.Kind {
+		return ""
+	}
+	if eventTime(event).Before(since) {
+		return ""
+	}
+
+	for _, reason := range trigger.Reasons {
+		if reason == event.Reason {
+			object := event.InvolvedObject
+			return fmt.Sprintf("event %s on %s %s/%s", event.Reason, object.Kind, object.Namespace, object.Name)
+		}
+	}
+	return ""
+}
+
+func matchNamespace(namespaces []string, scheduleNamespace string, namespace string) bool {
+	if len(namespaces) == 0 {
+		return namespace == scheduleNamespace
+	}
+	for _, n := range namespaces {
+		if n == namespace {
+			return true
+		}
+	}
+	return false
+}
+
+// eventTime returns when the event was last seen, events from the events.k8s.io API only set
+// the event time.
+func eventTime(event *corev1.Event) time.Time {
+	t := event.LastTimestamp.Time
+	if event.Series != nil && event.Series.LastObservedTime.Time.After(t) {
+		t = event.Series.LastObservedTime.Time
+	}
+	if event.EventTime.Time.After(t) {
+		t = event.EventTime.Time
+	}
+	return t
+}
@@ -0,0 +1,177 @@
+package schedule
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func crashingPod(namespace string, name string, labels map[string]string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+			Name:      name,
+			Labels:    labels,
+		},
+		Status: corev1.PodStatus{
+			ContainerStatuses: []corev1.ContainerStatus{
+				{
+					Name: "app",
+					State: corev1.ContainerState{
+						Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"},
+					},
+				},
+			},
+		},
+	}
+}
+
+func Test_matchTriggers(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	schedule := &troubleshootv1beta2.SupportBundleSchedule{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "app"},
+		Spec: troubleshootv1beta2.SupportBundleScheduleSpec{
+			Triggers: []troubleshootv1beta2.CollectionTrigger{
+				{CrashLoopBackOff: &troubleshootv1beta2.CrashLoopBackOffTrigger{Selector: []string{"app=web"}}},
+				{NodeNotReady: &troubleshootv1beta2.NodeNotReadyTrigger{Selector: []string{"node-role.kubernetes.io/worker"}}},
+				{Event: &troubleshootv1beta2.EventTrigger{Reasons: []string{"OOMKilling"}, InvolvedObjectKind: "Pod", Namespaces: []string{"app", "kube-system"}}},
+			},
+		},
+	}
+
+	running := crashingPod("app", "web-1", map[string]string{"app": "web"})
+	running.Status.ContainerStatuses[0].State = corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}
+
+	notReady := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "worker-1",
+			Labels: map[string]string{"node-role.kubernetes.io/worker": ""},
+		},
+		Status: corev1.NodeStatus{
+			Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionUnknown}},
+		},
+	}
+	controlPlane := notReady.DeepCopy()
+	controlPlane.Labels = map[string]string{"node-role.kubernetes.io/control-plane": ""}
+
+	event := func(namespace string, reason string, lastSeen time.Time) *corev1.Event {
+		return &corev1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Namespace: namespace, Name: "event"},
+			Reason:         reason,
+			InvolvedObject: corev1.ObjectReference{Kind: "Pod", Namespace: namespace, Name: "web-1"},
+			LastTimestamp:  metav1.NewTime(lastSeen),
+		}
+	}
+
+	tests := []struct {
+		name string
+		obj  client.Object
+		want string
+	}{
+		{name: "crashing pod", obj: crashingPod("app", "web-1", map[string]string{"app": "web"}), want: "container app of pod app/web-1 is in CrashLoopBackOff"},
+		{name: "running pod", obj: running},
+		{name: "crashing pod not selected", obj: crashingPod("app", "db-1", map[string]string{"app": "db"})},
+		{name: "crashing pod in another namespace", obj: crashingPod("default", "web-1", map[string]string{"app": "web"})},
+		{name: "node not ready", obj: notReady, want: "node worker-1 is NotReady"},
+		{name: "node not selected", obj: controlPlane},
+		{name: "event", obj: event("kube-system", "OOMKilling", now.Add(-time.Minute)), want: "event OOMKilling on Pod kube-system/web-1"},
+		{name: "event with another reason", obj: event("app", "Pulled", now)},
+		{name: "event in another namespace", obj: event("default", "OOMKilling", now)},
+		{name: "old event", obj: event("app", "OOMKilling", now.Add(-time.Hour))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := matchTriggers(schedule, tt.obj, now)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_matchTriggers_InvalidSelector(t *testing.T) {
+	schedule := &troubleshootv1beta2.SupportBundleSchedule{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "app"},
+		Spec: troubleshootv1beta2.SupportBundleScheduleSpec{
+			Triggers: []troubleshootv1beta2.CollectionTrigger{
+				{CrashLoopBackOff: &troubleshootv1beta2.CrashLoopBackOffTrigger{Selector: []string{"app in web"}}},
+			},
+		},
+	}
+
+	_, err := matchTriggers(schedule, crashingPod("app", "web-1", nil), time.Now())
+	assert.Error(t, err)
+}
+
+func TestTriggerReconciler(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	scheme, err := NewScheme()
+	require.NoError(t, err)
+
+	schedule := &troubleshootv1beta2.SupportBundleSchedule{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "crashes",
+			Namespace: "app",
+		},
+		Spec: troubleshootv1beta2.SupportBundleScheduleSpec{
+			Triggers: []troubleshootv1beta2.CollectionTrigger{
+				{CrashLoopBackOff: &troubleshootv1beta2.CrashLoopBackOffTrigger{Selector: []string{"app=web"}}},
+			},
+			TriggerCooldown: &metav1.Duration{Duration: 10 * time.Minute},
+			Storage: troubleshootv1beta2.ScheduledBundleStorage{
+				PersistentVolumeClaim: &troubleshootv1beta2.ScheduledBundlePersistentVolumeClaim{ClaimName: "bundles"},
+			},
+		},
+	}
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(schedule, crashingPod("app", "web-1", map[string]string{"app": "web"}), crashingPod("app", "web-2", map[string]string{"app": "web"})).
+		WithStatusSubresource(schedule).
+		Build()
+
+	r := &triggerReconciler{
+		Client:    c,
+		Scheme:    scheme,
+		newObject: func() client.Object { return &corev1.Pod{} },
+		now:       func() time.Time { return now },
+	}
+
+	_, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "app", Name: "web-1"}})
+	require.NoError(t, err)
+
+	jobs := &batchv1.JobList{}
+	require.NoError(t, c.List(ctx, jobs))
+	require.Len(t, jobs.Items, 1)
+	assert.Contains(t, jobs.Items[0].Spec.Template.Spec.Containers[0].Command, "container app of pod app/web-1 is in CrashLoopBackOff")
+	assert.Equal(t, "crashes", jobs.Items[0].OwnerReferences[0].Name)
+
+	require.NoError(t, c.Get(ctx, client.ObjectKeyFromObject(schedule), schedule))
+	assert.True(t, now.Equal(schedule.Status.LastTriggerTime.Time))
+
+	// another crashing pod within the cooldown does not start a collection
+	_, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "app", Name: "web-2"}})
+	require.NoError(t, err)
+	require.NoError(t, c.List(ctx, jobs))
+	assert.Len(t, jobs.Items, 1)
+
+	// it does once the cooldown is over
+	now = now.Add(11 * time.Minute)
+	_, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "app", Name: "web-2"}})
+	require.NoError(t, err)
+	require.NoError(t, c.List(ctx, jobs))
+	assert.Len(t, jobs.Items, 2)
+}
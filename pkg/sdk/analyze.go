@@ -0,0 +1,128 @@
+package sdk
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	analyzer "github.com/replicatedhq/troubleshoot/pkg/analyze"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"k8s.io/klog/v2"
+)
+
+// BundleReader reads the files of a support bundle. Paths are relative to the root of the
+// bundle.
+type BundleReader interface {
+	// ReadFile returns the contents of a file, the error wraps fs.ErrNotExist when the file
+	// is not in the bundle.
+	ReadFile(name string) ([]byte, error)
+	// Glob returns the contents of the files matching the pattern, keyed by their path.
+	Glob(pattern string) (map[string][]byte, error)
+}
+
+// Analyzer is an analyzer implemented by the embedder.
+type Analyzer interface {
+	Title() string
+	Analyze(ctx context.Context, bundle BundleReader) ([]*AnalyzeResult, error)
+}
+
+type AnalyzeOptions struct {
+	Analyzers     []*troubleshootv1beta2.Analyze
+	HostAnalyzers []*troubleshootv1beta2.HostAnalyze
+	// CustomAnalyzers run after the analyzers of the spec.
+	CustomAnalyzers []Analyzer
+}
+
+// Analyze runs the analyzers against the support bundle at bundlePath, which is either an
+// archive or an extracted bundle directory. Analyzers that fail are logged and skipped.
+func Analyze(ctx context.Context, bundlePath string, opts AnalyzeOptions) ([]*AnalyzeResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	info, err := os.Stat(bundlePath)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to stat bundle")
+	}
+
+	bundleDir := bundlePath
+	if !info.IsDir() {
+		tmpDir, rootDir, err := extractBundle(bundlePath)
+		if err != nil {
+			return nil, err
+		}
+		defer os.RemoveAll(tmpDir)
+		bundleDir = rootDir
+	}
+
+	rootDir, err := analyzer.FindBundleRootDir(bundleDir)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to find root dir")
+	}
+
+	results, err := analyzer.AnalyzeLocal(ctx, rootDir, analyzer.DedupAnalyzers(opts.Analyzers), opts.HostAnalyzers)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to analyze support bundle")
+	}
+
+	reader := bundleFiles{rootDir: rootDir}
+	for _, a := range opts.CustomAnalyzers {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		customResults, err := a.Analyze(ctx, reader)
+		if err != nil {
+			klog.Errorf("Analyzer %s failed to run: %v", a.Title(), err)
+			continue
+		}
+		for _, r := range customResults {
+			if r != nil {
+				results = append(results, r)
+			}
+		}
+	}
+
+	return results, nil
+}
+
+type bundleFiles struct {
+	rootDir string
+}
+
+func (b bundleFiles) ReadFile(name string) ([]byte, error) {
+	if !filepath.IsLocal(name) {
+		return nil, errors.Errorf("%q is outside of the bundle", name)
+	}
+	return os.ReadFile(filepath.Join(b.rootDir, name))
+}
+
+func (b bundleFiles) Glob(pattern string) (map[string][]byte, error) {
+	paths, err := filepath.Glob(filepath.Join(b.rootDir, pattern))
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid glob %q", pattern)
+	}
+
+	files := map[string][]byte{}
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to stat %q", path)
+		}
+		if info.IsDir() {
+			continue
+		}
+
+		name, err := filepath.Rel(b.rootDir, path)
+		if err != nil || !filepath.IsLocal(name) {
+			continue
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read %q", name)
+		}
+		files[filepath.ToSlash(name)] = data
+	}
+	return files, nil
+}
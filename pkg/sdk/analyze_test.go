@@ -0,0 +1,122 @@
+package sdk
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeTestBundle writes a support bundle archive with the files, and a version file, in a
+// support-bundle directory.
+func writeTestBundle(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "support-bundle.tar.gz")
+	f, err := os.Create(path)
+	require.NoError(t, err)
+	defer f.Close()
+
+	gzw := gzip.NewWriter(f)
+	tw := tar.NewWriter(gzw)
+	files["version.yaml"] = "apiVersion: troubleshoot.sh/v1beta2\nkind: Version\n"
+	for name, contents := range files {
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: "support-bundle/" + name, Mode: 0644, Size: int64(len(contents)), Typeflag: tar.TypeReg}))
+		_, err := tw.Write([]byte(contents))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	require.NoError(t, gzw.Close())
+	return path
+}
+
+type greetingAnalyzer struct{}
+
+func (greetingAnalyzer) Title() string {
+	return "Greeting files"
+}
+
+func (greetingAnalyzer) Analyze(ctx context.Context, bundle BundleReader) ([]*AnalyzeResult, error) {
+	if _, err := bundle.ReadFile("missing.txt"); !errors.Is(err, fs.ErrNotExist) {
+		return nil, errors.Errorf("expected not exist error, got %v", err)
+	}
+
+	files, err := bundle.Glob("greetings/*.txt")
+	if err != nil {
+		return nil, err
+	}
+	if len(files) != 2 {
+		return []*AnalyzeResult{{Title: "Greeting files", IsFail: true, Message: "Missing greeting files"}}, nil
+	}
+	return []*AnalyzeResult{{Title: "Greeting files", IsPass: true, Message: string(files["greetings/hello.txt"])}}, nil
+}
+
+type failingAnalyzer struct{}
+
+func (failingAnalyzer) Title() string {
+	return "Failing"
+}
+
+func (failingAnalyzer) Analyze(ctx context.Context, bundle BundleReader) ([]*AnalyzeResult, error) {
+	return nil, errors.New("failed")
+}
+
+func TestAnalyze(t *testing.T) {
+	archive := writeTestBundle(t, map[string]string{
+		"greetings/hello.txt": "hello world",
+		"greetings/hi.txt":    "hi",
+	})
+
+	opts := AnalyzeOptions{
+		Analyzers: []*troubleshootv1beta2.Analyze{
+			{
+				TextAnalyze: &troubleshootv1beta2.TextAnalyze{
+					AnalyzeMeta:   troubleshootv1beta2.AnalyzeMeta{CheckName: "Hello"},
+					CollectorName: "greetings",
+					FileName:      "hello.txt",
+					RegexPattern:  "hello",
+					Outcomes: []*troubleshootv1beta2.Outcome{
+						{Pass: &troubleshootv1beta2.SingleOutcome{When: "true", Message: "Found hello"}},
+						{Fail: &troubleshootv1beta2.SingleOutcome{When: "false", Message: "No hello"}},
+					},
+				},
+			},
+		},
+		CustomAnalyzers: []Analyzer{failingAnalyzer{}, greetingAnalyzer{}},
+	}
+
+	results, err := Analyze(context.Background(), archive, opts)
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.Equal(t, "Hello", results[0].Title)
+	assert.True(t, results[0].IsPass)
+	assert.Equal(t, "Greeting files", results[1].Title)
+	assert.True(t, results[1].IsPass)
+	assert.Equal(t, "hello world", results[1].Message)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = Analyze(ctx, archive, opts)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func Test_bundleFiles_ReadFile(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "hello.txt"), []byte("hello"), 0644))
+	b := bundleFiles{rootDir: dir}
+
+	data, err := b.ReadFile("hello.txt")
+	require.NoError(t, err)
+	assert.Equal(t, "hello", string(data))
+
+	_, err = b.ReadFile("../hello.txt")
+	assert.Error(t, err)
+}
@@ -0,0 +1,110 @@
+package sdk
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/supportbundle"
+	"k8s.io/client-go/rest"
+)
+
+// Collector is a collector implemented by the embedder. Collect returns the contents of the
+// files to add to the bundle, keyed by their path relative to the root of the bundle.
+type Collector interface {
+	Title() string
+	Collect(ctx context.Context) (map[string][]byte, error)
+}
+
+type CollectOptions struct {
+	// RestConfig is the config of the cluster to collect from, it is required.
+	RestConfig *rest.Config
+	// Namespace limits the collectors to a namespace, all namespaces are collected when empty.
+	Namespace string
+	// SinceTime limits the logs collected to the ones written after it.
+	SinceTime *time.Time
+	// OutputPath is where the bundle archive is written, a file in the OS temp folder is used
+	// when empty.
+	OutputPath string
+	// Redactors are applied in addition to the default redactors.
+	Redactors []*troubleshootv1beta2.Redact
+	// DisableRedaction keeps the collected files as they are.
+	DisableRedaction bool
+	// CollectWithoutPermissions runs the collectors that the RBAC permissions allow, instead
+	// of failing when some are missing.
+	CollectWithoutPermissions bool
+	// RunHostCollectorsInPod runs host collectors on the nodes of the cluster rather than on
+	// the local host.
+	RunHostCollectorsInPod bool
+	// Collectors run after the collectors of the spec.
+	Collectors []Collector
+	// OnProgress is called with the progress of the collection.
+	OnProgress func(ProgressEvent)
+}
+
+type CollectResult struct {
+	ArchivePath    string
+	AnalyzeResults []*AnalyzeResult
+}
+
+// Collect collects a support bundle from the spec, runs its analyzers and writes the bundle
+// archive. When some collectors fail, the bundle is still written and both the result and
+// an error describing the failures are returned.
+func Collect(ctx context.Context, spec *troubleshootv1beta2.SupportBundleSpec, opts CollectOptions) (*CollectResult, error) {
+	if spec == nil {
+		return nil, errors.New("spec is required")
+	}
+	if opts.RestConfig == nil {
+		return nil, errors.New("rest config is required")
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	progressChan := make(chan interface{})
+	progressDone := make(chan struct{})
+	go func() {
+		defer close(progressDone)
+		for msg := range progressChan {
+			if opts.OnProgress != nil {
+				opts.OnProgress(progressEvent(msg))
+			}
+		}
+	}()
+
+	customCollectors := make([]supportbundle.CustomCollector, 0, len(opts.Collectors))
+	for _, c := range opts.Collectors {
+		customCollectors = append(customCollectors, c)
+	}
+
+	redactors := &troubleshootv1beta2.Redactor{
+		Spec: troubleshootv1beta2.RedactorSpec{
+			Redactors: opts.Redactors,
+		},
+	}
+
+	response, err := supportbundle.CollectSupportBundleFromSpecWithContext(ctx, spec, redactors, supportbundle.SupportBundleCreateOpts{
+		CollectorProgressCallback: func(c chan interface{}, msg string) { c <- msg },
+		CollectWithoutPermissions: opts.CollectWithoutPermissions,
+		// the collection changes the client settings of the config
+		KubernetesRestConfig:   rest.CopyConfig(opts.RestConfig),
+		Namespace:              opts.Namespace,
+		ProgressChan:           progressChan,
+		SinceTime:              opts.SinceTime,
+		OutputPath:             opts.OutputPath,
+		Redact:                 !opts.DisableRedaction,
+		RunHostCollectorsInPod: opts.RunHostCollectorsInPod || spec.RunHostCollectorsInPod,
+		CustomCollectors:       customCollectors,
+	})
+	close(progressChan)
+	<-progressDone
+
+	if response == nil {
+		return nil, err
+	}
+	return &CollectResult{
+		ArchivePath:    response.ArchivePath,
+		AnalyzeResults: response.AnalyzerResults,
+	}, err
+}
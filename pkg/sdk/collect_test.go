@@ -0,0 +1,104 @@
+package sdk
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/rest"
+)
+
+type staticCollector struct {
+	title string
+	files map[string][]byte
+	err   error
+}
+
+func (c staticCollector) Title() string {
+	return c.title
+}
+
+func (c staticCollector) Collect(ctx context.Context) (map[string][]byte, error) {
+	return c.files, c.err
+}
+
+func TestCollect_CustomCollectors(t *testing.T) {
+	output := filepath.Join(t.TempDir(), "bundle.tar.gz")
+	spec := &troubleshootv1beta2.SupportBundleSpec{}
+
+	events := []ProgressEvent{}
+	result, err := Collect(context.Background(), spec, CollectOptions{
+		RestConfig: &rest.Config{},
+		OutputPath: output,
+		Redactors: []*troubleshootv1beta2.Redact{
+			{
+				Name: "passwords",
+				Removals: troubleshootv1beta2.Removals{
+					Regex: []troubleshootv1beta2.Regex{{Redactor: `(password=)(?P<mask>.*)`}},
+				},
+			},
+		},
+		Collectors: []Collector{
+			staticCollector{
+				title: "App config",
+				files: map[string][]byte{
+					"app/config.txt": []byte("user=admin\npassword=hunter2\n"),
+					"../escape.txt":  []byte("outside"),
+				},
+			},
+			staticCollector{title: "Broken", err: errors.New("boom")},
+		},
+		OnProgress: func(event ProgressEvent) {
+			events = append(events, event)
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, output, result.ArchivePath)
+
+	messages := []string{}
+	errs := []string{}
+	for _, event := range events {
+		if event.Err != nil {
+			errs = append(errs, event.Err.Error())
+			continue
+		}
+		messages = append(messages, event.Message)
+	}
+	assert.Equal(t, []string{"App config", "Broken"}, messages)
+	assert.Equal(t, []string{
+		`collector App config returned file "../escape.txt" outside of the bundle`,
+		"failed to run collector: Broken: boom",
+	}, errs)
+
+	results, err := Analyze(context.Background(), result.ArchivePath, AnalyzeOptions{
+		CustomAnalyzers: []Analyzer{contentsAnalyzer{name: "app/config.txt"}},
+	})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "user=admin\npassword=***HIDDEN***\n", results[0].Message)
+}
+
+func TestCollect_RequiresRestConfig(t *testing.T) {
+	_, err := Collect(context.Background(), &troubleshootv1beta2.SupportBundleSpec{}, CollectOptions{})
+	assert.EqualError(t, err, "rest config is required")
+}
+
+type contentsAnalyzer struct {
+	name string
+}
+
+func (a contentsAnalyzer) Title() string {
+	return "Contents"
+}
+
+func (a contentsAnalyzer) Analyze(ctx context.Context, bundle BundleReader) ([]*AnalyzeResult, error) {
+	data, err := bundle.ReadFile(a.name)
+	if err != nil {
+		return nil, err
+	}
+	return []*AnalyzeResult{{Title: a.Title(), IsPass: true, Message: string(data)}}, nil
+}
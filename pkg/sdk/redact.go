@@ -0,0 +1,97 @@
+package sdk
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	analyzer "github.com/replicatedhq/troubleshoot/pkg/analyze"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/collect"
+	"github.com/replicatedhq/troubleshoot/pkg/redact"
+)
+
+type RedactOptions struct {
+	// Redactors are applied in addition to the default redactors.
+	Redactors []*troubleshootv1beta2.Redact
+	// OutputPath is where the redacted archive is written, a file in the OS temp folder is
+	// used when empty.
+	OutputPath string
+}
+
+// Redact redacts the support bundle archive at bundlePath and returns the path of the
+// redacted archive. The original archive is left unchanged.
+func Redact(ctx context.Context, bundlePath string, opts RedactOptions) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	tmpDir, bundleDir, err := extractBundle(bundlePath)
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	result, err := collect.CollectorResultFromBundle(bundleDir)
+	if err != nil {
+		return "", err
+	}
+
+	if err := collect.RedactResult(bundleDir, result, opts.Redactors); err != nil {
+		return "", errors.Wrap(err, "failed to redact support bundle")
+	}
+
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	output := opts.OutputPath
+	if output == "" {
+		output = filepath.Join(os.TempDir(), fmt.Sprintf("redacted-support-bundle-%s.tar.gz", time.Now().Format("2006-01-02T15_04_05")))
+	}
+	if err := result.ArchiveBundle(bundleDir, output); err != nil {
+		return "", errors.Wrap(err, "failed to create support bundle archive")
+	}
+
+	return output, nil
+}
+
+// RedactionPreview is what redacting a file would change.
+type RedactionPreview = redact.RedactionPreview
+
+// PreviewRedact applies the default redactors and opts.Redactors to the files at inputPath,
+// without changing them, and returns a preview of each file. inputPath is a directory of sample
+// files, a single file, or a support bundle archive. File names are relative to the directory,
+// or to the root of the bundle, which is what the file selectors of the redactors match.
+// opts.OutputPath is not used.
+func PreviewRedact(ctx context.Context, inputPath string, opts RedactOptions) ([]*RedactionPreview, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	root := inputPath
+	if strings.HasSuffix(inputPath, ".tar.gz") || strings.HasSuffix(inputPath, ".tgz") {
+		tmpDir, bundleDir, err := extractBundle(inputPath)
+		if err != nil {
+			return nil, err
+		}
+		defer os.RemoveAll(tmpDir)
+		root = bundleDir
+	}
+
+	return redact.PreviewDir(root, opts.Redactors)
+}
+
+// extractBundle extracts the support bundle archive to a temporary directory, and returns
+// the directory and the root of the bundle in it.
+func extractBundle(bundlePath string) (string, string, error) {
+	path, err := filepath.Abs(bundlePath)
+	if err != nil {
+		return "", "", errors.Wrap(err, "failed to get absolute path of bundle")
+	}
+	return analyzer.DownloadAndExtractSupportBundle(path)
+}
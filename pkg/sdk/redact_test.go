@@ -0,0 +1,88 @@
+package sdk
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	analyzer "github.com/replicatedhq/troubleshoot/pkg/analyze"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedact(t *testing.T) {
+	archive := writeTestBundle(t, map[string]string{
+		"config/app.conf": "user=admin\npassword=hunter2\n",
+	})
+	output := filepath.Join(t.TempDir(), "redacted.tar.gz")
+
+	path, err := Redact(context.Background(), archive, RedactOptions{
+		Redactors: []*troubleshootv1beta2.Redact{
+			{
+				Name: "passwords",
+				Removals: troubleshootv1beta2.Removals{
+					Regex: []troubleshootv1beta2.Regex{{Redactor: `(password=)(?P<mask>.*)`}},
+				},
+			},
+		},
+		OutputPath: output,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, output, path)
+
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+
+	dir := t.TempDir()
+	require.NoError(t, analyzer.ExtractTroubleshootBundle(f, dir))
+	rootDir, err := analyzer.FindBundleRootDir(dir)
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(filepath.Join(rootDir, "config/app.conf"))
+	require.NoError(t, err)
+	assert.Equal(t, "user=admin\npassword=***HIDDEN***\n", string(data))
+}
+
+func TestPreviewRedact(t *testing.T) {
+	archive := writeTestBundle(t, map[string]string{
+		"config/app.conf": "user=admin\npassword=hunter2\n",
+	})
+
+	previews, err := PreviewRedact(context.Background(), archive, RedactOptions{
+		Redactors: []*troubleshootv1beta2.Redact{
+			{
+				Name: "users",
+				Removals: troubleshootv1beta2.Removals{
+					Regex: []troubleshootv1beta2.Regex{{Redactor: `(user=)(?P<mask>.*)`}},
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	var preview *RedactionPreview
+	for _, p := range previews {
+		if p.File == "config/app.conf" {
+			preview = p
+		}
+	}
+	require.NotNil(t, preview)
+	assert.Contains(t, string(preview.Redacted), "user=***HIDDEN***\n")
+	assert.Contains(t, preview.Diff, "-user=admin\n")
+	assert.Contains(t, preview.Diff, "+user=***HIDDEN***\n")
+
+	// the archive is left unchanged
+	f, err := os.Open(archive)
+	require.NoError(t, err)
+	defer f.Close()
+	dir := t.TempDir()
+	require.NoError(t, analyzer.ExtractTroubleshootBundle(f, dir))
+	rootDir, err := analyzer.FindBundleRootDir(dir)
+	require.NoError(t, err)
+	data, err := os.ReadFile(filepath.Join(rootDir, "config/app.conf"))
+	require.NoError(t, err)
+	assert.Equal(t, "user=admin\npassword=hunter2\n", string(data))
+}
@@ -0,0 +1,37 @@
+// Package sdk is the API for programs that embed troubleshoot to collect, redact and analyze
+// support bundles. Its functions, option structs and interfaces are kept stable across
+// releases, so that embedders do not depend on the packages it wraps, which change as
+// troubleshoot is refactored.
+package sdk
+
+import (
+	"fmt"
+
+	analyzer "github.com/replicatedhq/troubleshoot/pkg/analyze"
+	"github.com/replicatedhq/troubleshoot/pkg/collect"
+)
+
+// AnalyzeResult is the outcome of an analyzer.
+type AnalyzeResult = analyzer.AnalyzeResult
+
+// ProgressEvent reports the progress of a collection. Err is set when a collector failed,
+// the collection carries on with the other collectors.
+type ProgressEvent struct {
+	Message   string
+	Collector string
+	Completed int
+	Total     int
+	Err       error
+}
+
+func progressEvent(msg interface{}) ProgressEvent {
+	switch m := msg.(type) {
+	case error:
+		return ProgressEvent{Err: m}
+	case collect.CollectProgress:
+		return ProgressEvent{Message: m.CurrentStatus, Collector: m.CurrentName, Completed: m.CompletedCount, Total: m.TotalCount}
+	case string:
+		return ProgressEvent{Message: m}
+	}
+	return ProgressEvent{Message: fmt.Sprintf("%v", msg)}
+}
@@ -0,0 +1,54 @@
+package sdk
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/troubleshoot/internal/util"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/loader"
+	"github.com/replicatedhq/troubleshoot/pkg/supportbundle"
+)
+
+// Specs are the specs loaded from YAML documents.
+type Specs struct {
+	// SupportBundle merges the SupportBundle, Collector, HostCollector and Analyzer documents.
+	SupportBundle *troubleshootv1beta2.SupportBundleSpec
+	// Redactors are the redactors of the Redactor documents.
+	Redactors []*troubleshootv1beta2.Redact
+}
+
+// LoadSpecs loads the troubleshoot specs in the YAML documents, each of which can contain
+// several specs separated by "---". Specs of kinds that cannot be collected, such as
+// preflights, are ignored.
+func LoadSpecs(ctx context.Context, docs ...string) (*Specs, error) {
+	kinds, err := loader.LoadSpecs(ctx, loader.LoadOptions{RawSpecs: docs, Strict: true})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load specs")
+	}
+
+	bundle := &troubleshootv1beta2.SupportBundle{}
+	for _, sb := range kinds.SupportBundlesV1Beta2 {
+		sb := sb
+		bundle = supportbundle.ConcatSpec(bundle, &sb)
+	}
+	for _, c := range kinds.CollectorsV1Beta2 {
+		bundle.Spec.Collectors = util.Append(bundle.Spec.Collectors, c.Spec.Collectors)
+	}
+	for _, hc := range kinds.HostCollectorsV1Beta2 {
+		bundle.Spec.HostCollectors = util.Append(bundle.Spec.HostCollectors, hc.Spec.Collectors)
+	}
+	for _, a := range kinds.AnalyzersV1Beta2 {
+		bundle.Spec.Analyzers = util.Append(bundle.Spec.Analyzers, a.Spec.Analyzers)
+		bundle.Spec.HostAnalyzers = util.Append(bundle.Spec.HostAnalyzers, a.Spec.HostAnalyzers)
+	}
+
+	specs := &Specs{
+		SupportBundle: &bundle.Spec,
+	}
+	for _, r := range kinds.RedactorsV1Beta2 {
+		specs.Redactors = util.Append(specs.Redactors, r.Spec.Redactors)
+	}
+
+	return specs, nil
+}
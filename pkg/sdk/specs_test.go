@@ -0,0 +1,70 @@
+package sdk
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadSpecs(t *testing.T) {
+	bundle := `apiVersion: troubleshoot.sh/v1beta2
+kind: SupportBundle
+metadata:
+  name: bundle
+spec:
+  collectors:
+    - clusterInfo: {}
+  analyzers:
+    - clusterVersion:
+        outcomes:
+          - pass:
+              message: ok
+`
+	others := `apiVersion: troubleshoot.sh/v1beta2
+kind: Collector
+metadata:
+  name: collector
+spec:
+  collectors:
+    - logs:
+        selector:
+          - app=api
+---
+apiVersion: troubleshoot.sh/v1beta2
+kind: Analyzer
+metadata:
+  name: analyzer
+spec:
+  analyzers:
+    - nodeResources:
+        outcomes:
+          - pass:
+              message: ok
+---
+apiVersion: troubleshoot.sh/v1beta2
+kind: Redactor
+metadata:
+  name: redactor
+spec:
+  redactors:
+    - name: passwords
+      removals:
+        values:
+          - hunter2
+`
+
+	specs, err := LoadSpecs(context.Background(), bundle, others)
+	require.NoError(t, err)
+	require.Len(t, specs.SupportBundle.Collectors, 2)
+	assert.NotNil(t, specs.SupportBundle.Collectors[0].ClusterInfo)
+	assert.NotNil(t, specs.SupportBundle.Collectors[1].Logs)
+	require.Len(t, specs.SupportBundle.Analyzers, 2)
+	assert.NotNil(t, specs.SupportBundle.Analyzers[1].NodeResources)
+	require.Len(t, specs.Redactors, 1)
+	assert.Equal(t, "passwords", specs.Redactors[0].Name)
+
+	_, err = LoadSpecs(context.Background(), "kind: [")
+	assert.Error(t, err)
+}
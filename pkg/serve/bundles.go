@@ -0,0 +1,295 @@
+package serve
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"os"
+	"slices"
+	"strconv"
+
+	"github.com/pkg/errors"
+	analyzer "github.com/replicatedhq/troubleshoot/pkg/analyze"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/bundleindex"
+	"github.com/replicatedhq/troubleshoot/pkg/loader"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// defaultSearchLimit and maxSearchLimit bound the number of lines a search returns.
+	defaultSearchLimit = 100
+	maxSearchLimit     = 10000
+)
+
+// Bundle is a support bundle archive kept by the server.
+type Bundle struct {
+	ID   string `json:"id"`
+	Size int64  `json:"size"`
+}
+
+// SearchResults are the lines of a bundle that have all of the words of a search. Total counts all
+// of them, Matches has at most the limit of the search.
+type SearchResults struct {
+	Total   int                 `json:"total"`
+	Matches []bundleindex.Match `json:"matches"`
+}
+
+func (s *Server) uploadBundle(w http.ResponseWriter, r *http.Request) {
+	id, err := newID()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, errors.Wrap(err, "failed to generate id"))
+		return
+	}
+
+	path := s.bundlePath(id)
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, errors.Wrap(err, "failed to create bundle"))
+		return
+	}
+
+	size, err := io.Copy(f, http.MaxBytesReader(w, r.Body, s.opts.MaxUploadSize))
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(path)
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			writeError(w, http.StatusRequestEntityTooLarge, errors.Errorf("bundle is larger than %d bytes", s.opts.MaxUploadSize))
+			return
+		}
+		writeError(w, http.StatusBadRequest, errors.Wrap(err, "failed to read bundle"))
+		return
+	}
+
+	s.addUpload(id)
+
+	writeJSON(w, http.StatusCreated, Bundle{ID: id, Size: size})
+}
+
+// addUpload records an uploaded bundle, and deletes the oldest uploaded bundles beyond the
+// maximum
+func (s *Server) addUpload(id string) {
+	s.mu.Lock()
+	s.uploads = append(s.uploads, id)
+	evicted := []string{}
+	if excess := len(s.uploads) - s.opts.MaxBundles; excess > 0 {
+		evicted = append(evicted, s.uploads[:excess]...)
+		s.uploads = s.uploads[excess:]
+	}
+	s.mu.Unlock()
+
+	for _, id := range evicted {
+		if err := os.Remove(s.bundlePath(id)); err != nil && !os.IsNotExist(err) {
+			klog.Errorf("Failed to delete evicted bundle %s: %v", id, err)
+		}
+		s.removeBundleIndex(id)
+	}
+}
+
+// bundleFromRequest returns the path of the bundle in the request path, or writes an error
+// response when there is no such bundle.
+func (s *Server) bundleFromRequest(w http.ResponseWriter, r *http.Request) (string, bool) {
+	id := r.PathValue("id")
+	if !idPattern.MatchString(id) {
+		writeError(w, http.StatusNotFound, errors.New("bundle not found"))
+		return "", false
+	}
+
+	path := s.bundlePath(id)
+	if _, err := os.Stat(path); err != nil {
+		writeError(w, http.StatusNotFound, errors.New("bundle not found"))
+		return "", false
+	}
+	return path, true
+}
+
+func (s *Server) downloadBundle(w http.ResponseWriter, r *http.Request) {
+	path, ok := s.bundleFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/gzip")
+	w.Header().Set("Content-Disposition", `attachment; filename="support-bundle-`+r.PathValue("id")+`.tar.gz"`)
+	http.ServeFile(w, r, path)
+}
+
+func (s *Server) deleteBundle(w http.ResponseWriter, r *http.Request) {
+	path, ok := s.bundleFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	if err := os.Remove(path); err != nil {
+		writeError(w, http.StatusInternalServerError, errors.Wrap(err, "failed to delete bundle"))
+		return
+	}
+	s.mu.Lock()
+	s.uploads = slices.DeleteFunc(s.uploads, func(id string) bool { return id == r.PathValue("id") })
+	s.mu.Unlock()
+	s.removeBundleIndex(r.PathValue("id"))
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// removeBundleIndex forgets the index of a deleted bundle and deletes it
+func (s *Server) removeBundleIndex(id string) {
+	s.indexMu.Lock()
+	delete(s.indexes, id)
+	s.indexMu.Unlock()
+	if err := os.Remove(s.bundleIndexPath(id)); err != nil && !os.IsNotExist(err) {
+		klog.Errorf("Failed to delete index of bundle %s: %v", id, err)
+	}
+}
+
+// analyzeBundle runs the analyzers in the request body against a bundle, or the default
+// analyzers when the body is empty.
+func (s *Server) analyzeBundle(w http.ResponseWriter, r *http.Request) {
+	path, ok := s.bundleFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	spec, err := io.ReadAll(io.LimitReader(r.Body, maxSpecSize))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, errors.Wrap(err, "failed to read spec"))
+		return
+	}
+
+	if !s.opts.ApproveOperations {
+		runsCode, err := analyzersRunCode(r.Context(), string(spec))
+		if err != nil {
+			writeError(w, http.StatusUnprocessableEntity, err)
+			return
+		}
+		if runsCode {
+			writeError(w, http.StatusForbidden, errors.New("the analyzers run plugins or WASM modules, which the server does not approve"))
+			return
+		}
+	}
+
+	results, err := analyzer.DownloadAndAnalyze(path, string(spec))
+	if err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, results)
+}
+
+// searchBundle returns the lines of a bundle with all of the words of the q parameter, up to the
+// limit parameter. The bundle is indexed on its first search.
+func (s *Server) searchBundle(w http.ResponseWriter, r *http.Request) {
+	path, ok := s.bundleFromRequest(w, r)
+	if !ok {
+		return
+	}
+
+	query := r.URL.Query().Get("q")
+	if query == "" {
+		writeError(w, http.StatusBadRequest, errors.New("q is required"))
+		return
+	}
+	limit := defaultSearchLimit
+	if value := r.URL.Query().Get("limit"); value != "" {
+		var err error
+		limit, err = strconv.Atoi(value)
+		if err != nil || limit <= 0 || limit > maxSearchLimit {
+			writeError(w, http.StatusBadRequest, errors.Errorf("limit must be between 1 and %d", maxSearchLimit))
+			return
+		}
+	}
+
+	index, err := s.bundleIndex(r.PathValue("id"), path)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	hits := index.Search(query)
+	total := len(hits)
+	if len(hits) > limit {
+		hits = hits[:limit]
+	}
+	matches, err := index.ReadMatches(path, hits)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, errors.Wrap(err, "failed to read matches"))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, SearchResults{Total: total, Matches: matches})
+}
+
+// bundleIndex returns the index of a bundle, loading it from the data dir or building it the
+// first time the bundle is searched.
+func (s *Server) bundleIndex(id string, bundlePath string) (*bundleindex.Index, error) {
+	s.indexMu.Lock()
+	defer s.indexMu.Unlock()
+
+	if index, ok := s.indexes[id]; ok {
+		return index, nil
+	}
+
+	indexPath := s.bundleIndexPath(id)
+	if f, err := os.Open(indexPath); err == nil {
+		index, err := bundleindex.Load(f)
+		f.Close()
+		if err == nil {
+			s.indexes[id] = index
+			return index, nil
+		}
+		klog.Errorf("Failed to load index of bundle %s, indexing it again: %v", id, err)
+	}
+
+	index, err := bundleindex.BuildFromArchive(bundlePath)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to index bundle")
+	}
+	s.indexes[id] = index
+
+	// the index is kept in memory when it cannot be saved
+	f, err := os.OpenFile(indexPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		klog.Errorf("Failed to save index of bundle %s: %v", id, err)
+		return index, nil
+	}
+	err = index.Save(f)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(indexPath)
+		klog.Errorf("Failed to save index of bundle %s: %v", id, err)
+	}
+	return index, nil
+}
+
+// analyzersRunCode reports whether the analyzers of the specs run plugins, which are executables
+// on the host of the server, or WASM modules, which may be pulled from registries.
+func analyzersRunCode(ctx context.Context, raw string) (bool, error) {
+	if raw == "" {
+		return false, nil
+	}
+
+	kinds, err := loader.LoadSpecs(ctx, loader.LoadOptions{RawSpec: raw})
+	if err != nil {
+		return false, errors.Wrap(err, "failed to load specs")
+	}
+
+	analyzers := []*troubleshootv1beta2.Analyze{}
+	for _, a := range kinds.AnalyzersV1Beta2 {
+		analyzers = append(analyzers, a.Spec.Analyzers...)
+	}
+	for _, sb := range kinds.SupportBundlesV1Beta2 {
+		analyzers = append(analyzers, sb.Spec.Analyzers...)
+	}
+	for _, a := range analyzers {
+		if a != nil && (a.Plugin != nil || a.Wasm != nil) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
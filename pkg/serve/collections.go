@@ -0,0 +1,378 @@
+package serve
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/troubleshoot/internal/util"
+	analyzer "github.com/replicatedhq/troubleshoot/pkg/analyze"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/collect"
+	"github.com/replicatedhq/troubleshoot/pkg/loader"
+	"github.com/replicatedhq/troubleshoot/pkg/supportbundle"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+)
+
+type CollectionStatus string
+
+const (
+	CollectionPending   CollectionStatus = "pending"
+	CollectionRunning   CollectionStatus = "running"
+	CollectionCompleted CollectionStatus = "completed"
+	CollectionFailed    CollectionStatus = "failed"
+)
+
+// Collection is the state of a collection returned by the API. A completed collection can
+// still have an error when some of its collectors failed, its bundle is available as BundleID.
+type Collection struct {
+	ID          string                    `json:"id"`
+	Status      CollectionStatus          `json:"status"`
+	Error       string                    `json:"error,omitempty"`
+	BundleID    string                    `json:"bundleId,omitempty"`
+	Analysis    []*analyzer.AnalyzeResult `json:"analysis,omitempty"`
+	CreatedAt   time.Time                 `json:"createdAt"`
+	CompletedAt *time.Time                `json:"completedAt,omitempty"`
+}
+
+// ProgressEvent is an event of the progress stream of a collection.
+type ProgressEvent struct {
+	Message   string `json:"message,omitempty"`
+	Error     string `json:"error,omitempty"`
+	Collector string `json:"collector,omitempty"`
+	Completed int    `json:"completed,omitempty"`
+	Total     int    `json:"total,omitempty"`
+}
+
+type collection struct {
+	mu     sync.Mutex
+	state  Collection
+	events []ProgressEvent
+	// changed is closed and replaced whenever an event is added or the collection finishes
+	changed chan struct{}
+	done    bool
+}
+
+func newCollection(id string) *collection {
+	return &collection{
+		state: Collection{
+			ID:        id,
+			Status:    CollectionPending,
+			CreatedAt: time.Now(),
+		},
+		changed: make(chan struct{}),
+	}
+}
+
+func (c *collection) snapshot() Collection {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.state
+}
+
+func (c *collection) update(fn func(state *Collection)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	fn(&c.state)
+}
+
+func (c *collection) addEvent(event ProgressEvent) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.events = append(c.events, event)
+	close(c.changed)
+	c.changed = make(chan struct{})
+}
+
+func (c *collection) finish() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.done = true
+	close(c.changed)
+	c.changed = make(chan struct{})
+}
+
+func (c *collection) isDone() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.done
+}
+
+// eventsSince returns the events after the first n, whether the collection is done, and a
+// channel that is closed on the next change.
+func (c *collection) eventsSince(n int) ([]ProgressEvent, bool, <-chan struct{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.events[n:], c.done, c.changed
+}
+
+func progressEvent(msg interface{}) ProgressEvent {
+	switch m := msg.(type) {
+	case error:
+		return ProgressEvent{Error: m.Error()}
+	case collect.CollectProgress:
+		return ProgressEvent{Message: m.CurrentStatus, Collector: m.CurrentName, Completed: m.CompletedCount, Total: m.TotalCount}
+	case string:
+		return ProgressEvent{Message: m}
+	}
+	return ProgressEvent{Message: fmt.Sprintf("%v", msg)}
+}
+
+// parseSupportBundleSpec merges the support bundle, collector and host collector specs into a
+// single support bundle spec, and the redactor specs into a single redactor. Host collectors are
+// set to run in pods.
+func parseSupportBundleSpec(ctx context.Context, raw string) (*troubleshootv1beta2.SupportBundleSpec, *troubleshootv1beta2.Redactor, error) {
+	kinds, err := loader.LoadSpecs(ctx, loader.LoadOptions{RawSpec: raw, Strict: true})
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to load specs")
+	}
+
+	bundle := &troubleshootv1beta2.SupportBundle{}
+	for _, sb := range kinds.SupportBundlesV1Beta2 {
+		sb := sb
+		bundle = supportbundle.ConcatSpec(bundle, &sb)
+	}
+	for _, c := range kinds.CollectorsV1Beta2 {
+		bundle.Spec.Collectors = util.Append(bundle.Spec.Collectors, c.Spec.Collectors)
+	}
+	for _, hc := range kinds.HostCollectorsV1Beta2 {
+		bundle.Spec.HostCollectors = util.Append(bundle.Spec.HostCollectors, hc.Spec.Collectors)
+	}
+	if len(bundle.Spec.Collectors) == 0 && len(bundle.Spec.HostCollectors) == 0 {
+		return nil, nil, errors.New("no collectors specified to run")
+	}
+	// host collectors run in pods on the nodes of the cluster, never on the host of the server
+	bundle.Spec.RunHostCollectorsInPod = true
+
+	redactors := &troubleshootv1beta2.Redactor{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "additional-redactors",
+		},
+	}
+	for _, r := range kinds.RedactorsV1Beta2 {
+		redactors.Spec.Redactors = util.Append(redactors.Spec.Redactors, r.Spec.Redactors)
+	}
+
+	return &bundle.Spec, redactors, nil
+}
+
+func (s *Server) createCollection(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxSpecSize))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, errors.Wrap(err, "failed to read spec"))
+		return
+	}
+
+	spec, redactors, err := parseSupportBundleSpec(r.Context(), string(body))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if !s.opts.ApproveOperations && len(collect.SpecOperations(spec, "")) > 0 {
+		writeError(w, http.StatusForbidden, errors.New("the collectors run commands or copy files, which the server does not approve"))
+		return
+	}
+
+	id, err := newID()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, errors.Wrap(err, "failed to generate id"))
+		return
+	}
+
+	c := newCollection(id)
+	s.mu.Lock()
+	s.collections[id] = c
+	s.mu.Unlock()
+	s.evictCollections()
+
+	state := c.snapshot()
+	go s.runCollection(c, spec, redactors)
+
+	writeJSON(w, http.StatusAccepted, state)
+}
+
+func (s *Server) runCollection(c *collection, spec *troubleshootv1beta2.SupportBundleSpec, redactors *troubleshootv1beta2.Redactor) {
+	// collections that finished while the limit was reached are evicted once this one is done
+	defer s.evictCollections()
+	defer c.finish()
+
+	s.collectMu.Lock()
+	defer s.collectMu.Unlock()
+
+	c.update(func(state *Collection) {
+		state.Status = CollectionRunning
+	})
+
+	progressChan := make(chan interface{})
+	progressDone := make(chan struct{})
+	go func() {
+		defer close(progressDone)
+		for msg := range progressChan {
+			c.addEvent(progressEvent(msg))
+		}
+	}()
+
+	id := c.snapshot().ID
+	response, err := s.collect(spec, redactors, s.bundlePath(id), progressChan)
+	close(progressChan)
+	<-progressDone
+
+	completedAt := time.Now()
+	c.update(func(state *Collection) {
+		state.CompletedAt = &completedAt
+		if err != nil {
+			state.Error = err.Error()
+		}
+		if response == nil {
+			state.Status = CollectionFailed
+			return
+		}
+		state.Status = CollectionCompleted
+		state.BundleID = id
+		state.Analysis = response.AnalyzerResults
+	})
+
+	if err != nil {
+		klog.Errorf("Collection %s failed: %v", id, err)
+	}
+}
+
+// evictCollections forgets the oldest finished collections beyond the maximum number of
+// collections, and deletes their bundles. Running and pending collections are kept, so the
+// maximum can be exceeded while they run.
+func (s *Server) evictCollections() {
+	s.mu.Lock()
+	excess := len(s.collections) - s.opts.MaxCollections
+	if excess <= 0 {
+		s.mu.Unlock()
+		return
+	}
+	finished := []Collection{}
+	for _, c := range s.collections {
+		if c.isDone() {
+			finished = append(finished, c.snapshot())
+		}
+	}
+	sort.Slice(finished, func(i, j int) bool {
+		return finished[i].CreatedAt.Before(finished[j].CreatedAt)
+	})
+	if excess > len(finished) {
+		excess = len(finished)
+	}
+	evicted := finished[:excess]
+	for _, c := range evicted {
+		delete(s.collections, c.ID)
+	}
+	s.mu.Unlock()
+
+	// the bundle of a collection has its id, and failed collections may have left part of one
+	for _, c := range evicted {
+		if err := os.Remove(s.bundlePath(c.ID)); err != nil && !os.IsNotExist(err) {
+			klog.Errorf("Failed to delete bundle of evicted collection %s: %v", c.ID, err)
+		}
+		s.removeBundleIndex(c.ID)
+	}
+}
+
+func (s *Server) collectFromCluster(spec *troubleshootv1beta2.SupportBundleSpec, redactors *troubleshootv1beta2.Redactor, outputPath string, progressChan chan interface{}) (*supportbundle.SupportBundleResponse, error) {
+	return supportbundle.CollectSupportBundleFromSpec(spec, redactors, supportbundle.SupportBundleCreateOpts{
+		CollectorProgressCallback: func(c chan interface{}, msg string) { c <- msg },
+		CollectWithoutPermissions: true,
+		KubernetesRestConfig:      s.opts.RestConfig,
+		ProgressChan:              progressChan,
+		OutputPath:                outputPath,
+		Redact:                    true,
+		RunHostCollectorsInPod:    true,
+	})
+}
+
+func (s *Server) getCollectionByID(id string) (*collection, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c, ok := s.collections[id]
+	return c, ok
+}
+
+func (s *Server) listCollections(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	collections := make([]Collection, 0, len(s.collections))
+	for _, c := range s.collections {
+		collections = append(collections, c.snapshot())
+	}
+	s.mu.Unlock()
+
+	sort.Slice(collections, func(i, j int) bool {
+		return collections[i].CreatedAt.Before(collections[j].CreatedAt)
+	})
+	writeJSON(w, http.StatusOK, collections)
+}
+
+func (s *Server) getCollection(w http.ResponseWriter, r *http.Request) {
+	c, ok := s.getCollectionByID(r.PathValue("id"))
+	if !ok {
+		writeError(w, http.StatusNotFound, errors.New("collection not found"))
+		return
+	}
+	writeJSON(w, http.StatusOK, c.snapshot())
+}
+
+// streamProgress sends the progress events of a collection as server-sent events, starting
+// from the first one, and a final "done" event with the state of the collection.
+func (s *Server) streamProgress(w http.ResponseWriter, r *http.Request) {
+	c, ok := s.getCollectionByID(r.PathValue("id"))
+	if !ok {
+		writeError(w, http.StatusNotFound, errors.New("collection not found"))
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, errors.New("streaming is not supported"))
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+
+	sent := 0
+	for {
+		events, done, changed := c.eventsSince(sent)
+		for _, event := range events {
+			if err := writeEvent(w, "progress", event); err != nil {
+				return
+			}
+		}
+		sent += len(events)
+
+		if done {
+			writeEvent(w, "done", c.snapshot())
+			flusher.Flush()
+			return
+		}
+		flusher.Flush()
+
+		select {
+		case <-r.Context().Done():
+			return
+		case <-changed:
+		}
+	}
+}
+
+func writeEvent(w io.Writer, name string, data interface{}) error {
+	b, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", name, b)
+	return err
+}
@@ -0,0 +1,218 @@
+package serve
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/bundleindex"
+	"github.com/replicatedhq/troubleshoot/pkg/supportbundle"
+	"k8s.io/client-go/rest"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// DefaultMaxUploadSize is the largest bundle that can be uploaded, 1GiB.
+	DefaultMaxUploadSize = 1 << 30
+	// DefaultMaxCollections is how many collections are kept with their bundles.
+	DefaultMaxCollections = 100
+	// DefaultMaxBundles is how many uploaded bundles are kept.
+	DefaultMaxBundles = 100
+	// maxSpecSize is the largest spec that can be submitted, 10MiB.
+	maxSpecSize = 10 << 20
+)
+
+var idPattern = regexp.MustCompile(`^[a-f0-9]{32}$`)
+
+type Options struct {
+	// Token is the bearer token that clients authenticate with.
+	Token string
+	// DataDir is where collected and uploaded bundles are kept.
+	DataDir string
+	// RestConfig is the config of the cluster that collections run against.
+	RestConfig    *rest.Config
+	MaxUploadSize int64
+	// MaxCollections is how many collections are kept. Beyond it, the oldest finished collections
+	// are forgotten and their bundles deleted.
+	MaxCollections int
+	// MaxBundles is how many uploaded bundles are kept. Beyond it, the oldest uploaded bundles are
+	// deleted.
+	MaxBundles int
+	// ApproveOperations runs the commands and copies the files of the collectors of submitted
+	// specs, and the plugins and WASM modules of submitted analyzers. Without it, specs that run
+	// commands, copy files, or run plugins or WASM modules are rejected.
+	ApproveOperations bool
+}
+
+// collectFunc runs a collection, writing the bundle to outputPath and sending progress to progressChan.
+type collectFunc func(spec *troubleshootv1beta2.SupportBundleSpec, redactors *troubleshootv1beta2.Redactor, outputPath string, progressChan chan interface{}) (*supportbundle.SupportBundleResponse, error)
+
+// Server exposes collection and analysis over an authenticated REST API, so that platforms can
+// embed troubleshoot without running the CLI:
+//
+//	POST   /v1/collections               submit specs (YAML) and start a collection
+//	GET    /v1/collections               list collections
+//	GET    /v1/collections/{id}          get the status and analysis of a collection
+//	GET    /v1/collections/{id}/progress stream the progress of a collection as server-sent events
+//	POST   /v1/bundles                   upload a support bundle archive
+//	GET    /v1/bundles/{id}              download a support bundle archive
+//	DELETE /v1/bundles/{id}              delete a support bundle archive
+//	POST   /v1/bundles/{id}/analyze      run analyzers (YAML, or the defaults when empty) against a bundle
+//	GET    /v1/bundles/{id}/search?q=    find the lines of a bundle with all of the words of q
+type Server struct {
+	opts    Options
+	collect collectFunc
+
+	mu          sync.Mutex
+	collections map[string]*collection
+	// ids of the uploaded bundles, oldest first
+	uploads []string
+	// collections run one at a time, the collectors share global state such as tracing
+	collectMu sync.Mutex
+
+	// indexes of the searched bundles, built one at a time and saved next to the bundles
+	indexMu sync.Mutex
+	indexes map[string]*bundleindex.Index
+}
+
+func New(opts Options) (*Server, error) {
+	if opts.Token == "" {
+		return nil, errors.New("token is required")
+	}
+	if opts.DataDir == "" {
+		return nil, errors.New("data dir is required")
+	}
+	if opts.MaxUploadSize <= 0 {
+		opts.MaxUploadSize = DefaultMaxUploadSize
+	}
+	if opts.MaxCollections <= 0 {
+		opts.MaxCollections = DefaultMaxCollections
+	}
+	if opts.MaxBundles <= 0 {
+		opts.MaxBundles = DefaultMaxBundles
+	}
+
+	dataDir, err := filepath.Abs(opts.DataDir)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get absolute path of data dir")
+	}
+	opts.DataDir = dataDir
+
+	if err := os.MkdirAll(filepath.Join(opts.DataDir, "bundles"), 0700); err != nil {
+		return nil, errors.Wrap(err, "failed to create bundles dir")
+	}
+
+	s := &Server{
+		opts:        opts,
+		collections: map[string]*collection{},
+		indexes:     map[string]*bundleindex.Index{},
+	}
+	s.collect = s.collectFromCluster
+	return s, nil
+}
+
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /v1/collections", s.createCollection)
+	mux.HandleFunc("GET /v1/collections", s.listCollections)
+	mux.HandleFunc("GET /v1/collections/{id}", s.getCollection)
+	mux.HandleFunc("GET /v1/collections/{id}/progress", s.streamProgress)
+	mux.HandleFunc("POST /v1/bundles", s.uploadBundle)
+	mux.HandleFunc("GET /v1/bundles/{id}", s.downloadBundle)
+	mux.HandleFunc("DELETE /v1/bundles/{id}", s.deleteBundle)
+	mux.HandleFunc("POST /v1/bundles/{id}/analyze", s.analyzeBundle)
+	mux.HandleFunc("GET /v1/bundles/{id}/search", s.searchBundle)
+
+	root := http.NewServeMux()
+	root.HandleFunc("GET /healthz", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	root.Handle("/v1/", s.authenticate(mux))
+	return root
+}
+
+func (s *Server) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(s.opts.Token)) != 1 {
+			writeError(w, http.StatusUnauthorized, errors.New("unauthorized"))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// ListenAndServe serves the API on address until the context is cancelled. TLS is used when
+// both the certificate and key files are set.
+func ListenAndServe(ctx context.Context, s *Server, address string, tlsCertFile string, tlsKeyFile string) error {
+	httpServer := &http.Server{
+		Addr:              address,
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := httpServer.Shutdown(shutdownCtx); err != nil {
+			klog.Errorf("Failed to shut down server: %v", err)
+		}
+	}()
+
+	klog.Infof("Serving troubleshoot API on %s", address)
+
+	var err error
+	if tlsCertFile != "" && tlsKeyFile != "" {
+		err = httpServer.ListenAndServeTLS(tlsCertFile, tlsKeyFile)
+	} else {
+		err = httpServer.ListenAndServe()
+	}
+	if err != nil && err != http.ErrServerClosed {
+		return errors.Wrap(err, "failed to serve")
+	}
+	return nil
+}
+
+func (s *Server) bundlePath(id string) string {
+	return filepath.Join(s.opts.DataDir, "bundles", id+".tar.gz")
+}
+
+func (s *Server) bundleIndexPath(id string) string {
+	return filepath.Join(s.opts.DataDir, "bundles", id+".index")
+}
+
+func newID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		klog.Errorf("Failed to write response: %v", err)
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, errorResponse{Error: err.Error()})
+}
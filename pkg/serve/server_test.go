@@ -0,0 +1,412 @@
+package serve
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	analyzer "github.com/replicatedhq/troubleshoot/pkg/analyze"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/supportbundle"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testToken = "secret"
+
+func newTestServer(t *testing.T) (*Server, *httptest.Server) {
+	t.Helper()
+
+	s, err := New(Options{Token: testToken, DataDir: t.TempDir()})
+	require.NoError(t, err)
+
+	ts := httptest.NewServer(s.Handler())
+	t.Cleanup(ts.Close)
+	return s, ts
+}
+
+func doRequest(t *testing.T, method string, url string, body io.Reader) *http.Response {
+	t.Helper()
+
+	req, err := http.NewRequest(method, url, body)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer "+testToken)
+
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+func testBundle(t *testing.T) []byte {
+	t.Helper()
+
+	buf := &bytes.Buffer{}
+	gzw := gzip.NewWriter(buf)
+	tw := tar.NewWriter(gzw)
+	files := map[string]string{
+		"support-bundle/version.yaml": "apiVersion: troubleshoot.sh/v1beta2\nkind: Version\n",
+		"support-bundle/hello.txt":    "hello world",
+	}
+	for name, contents := range files {
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(contents))}))
+		_, err := tw.Write([]byte(contents))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	require.NoError(t, gzw.Close())
+	return buf.Bytes()
+}
+
+func TestNew(t *testing.T) {
+	_, err := New(Options{DataDir: t.TempDir()})
+	assert.EqualError(t, err, "token is required")
+
+	_, err = New(Options{Token: testToken})
+	assert.EqualError(t, err, "data dir is required")
+}
+
+func TestServer_Authentication(t *testing.T) {
+	_, ts := newTestServer(t)
+
+	resp, err := http.Get(ts.URL + "/healthz")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	for _, header := range []string{"", "Bearer wrong", testToken} {
+		req, err := http.NewRequest(http.MethodGet, ts.URL+"/v1/collections", nil)
+		require.NoError(t, err)
+		if header != "" {
+			req.Header.Set("Authorization", header)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode, header)
+	}
+}
+
+func TestServer_Collection(t *testing.T) {
+	s, ts := newTestServer(t)
+
+	release := make(chan struct{})
+	s.collect = func(spec *troubleshootv1beta2.SupportBundleSpec, redactors *troubleshootv1beta2.Redactor, outputPath string, progressChan chan interface{}) (*supportbundle.SupportBundleResponse, error) {
+		assert.Len(t, spec.Collectors, 1)
+		assert.Len(t, redactors.Spec.Redactors, 1)
+
+		<-release
+		progressChan <- "collecting cluster info"
+		if err := os.WriteFile(outputPath, testBundle(t), 0600); err != nil {
+			return nil, err
+		}
+		return &supportbundle.SupportBundleResponse{
+			ArchivePath:     outputPath,
+			AnalyzerResults: []*analyzer.AnalyzeResult{{Title: "Cluster Version", IsPass: true}},
+		}, nil
+	}
+
+	spec := `apiVersion: troubleshoot.sh/v1beta2
+kind: SupportBundle
+metadata:
+  name: test
+spec:
+  collectors:
+    - clusterInfo: {}
+---
+apiVersion: troubleshoot.sh/v1beta2
+kind: Redactor
+metadata:
+  name: test
+spec:
+  redactors:
+    - name: passwords
+      removals:
+        regex:
+          - redactor: 'password=(?P<mask>.*)'
+`
+	resp := doRequest(t, http.MethodPost, ts.URL+"/v1/collections", strings.NewReader(spec))
+	require.Equal(t, http.StatusAccepted, resp.StatusCode)
+
+	created := Collection{}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&created))
+	assert.Equal(t, CollectionPending, created.Status)
+
+	stream := doRequest(t, http.MethodGet, ts.URL+"/v1/collections/"+created.ID+"/progress", nil)
+	require.Equal(t, http.StatusOK, stream.StatusCode)
+	assert.Equal(t, "text/event-stream", stream.Header.Get("Content-Type"))
+	close(release)
+
+	events := []string{}
+	scanner := bufio.NewScanner(stream.Body)
+	for scanner.Scan() {
+		if line, ok := strings.CutPrefix(scanner.Text(), "data: "); ok {
+			events = append(events, line)
+		}
+	}
+	require.Len(t, events, 2)
+	assert.JSONEq(t, `{"message":"collecting cluster info"}`, events[0])
+
+	done := Collection{}
+	require.NoError(t, json.Unmarshal([]byte(events[1]), &done))
+	assert.Equal(t, CollectionCompleted, done.Status)
+	assert.Equal(t, created.ID, done.BundleID)
+	assert.Equal(t, "Cluster Version", done.Analysis[0].Title)
+
+	resp = doRequest(t, http.MethodGet, ts.URL+"/v1/collections/"+created.ID, nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	resp = doRequest(t, http.MethodGet, ts.URL+"/v1/bundles/"+done.BundleID, nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	// gzip magic number
+	assert.Equal(t, []byte{0x1f, 0x8b}, body[:2])
+}
+
+func TestServer_CollectionOperations(t *testing.T) {
+	s, ts := newTestServer(t)
+	s.collect = func(spec *troubleshootv1beta2.SupportBundleSpec, redactors *troubleshootv1beta2.Redactor, outputPath string, progressChan chan interface{}) (*supportbundle.SupportBundleResponse, error) {
+		return nil, errors.New("not collected")
+	}
+
+	spec := `apiVersion: troubleshoot.sh/v1beta2
+kind: SupportBundle
+spec:
+  collectors:
+    - exec:
+        collectorName: pg-schema
+        selector:
+          - app=postgres
+        command: ["pg_dump"]
+`
+	resp := doRequest(t, http.MethodPost, ts.URL+"/v1/collections", strings.NewReader(spec))
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+	s.opts.ApproveOperations = true
+	resp = doRequest(t, http.MethodPost, ts.URL+"/v1/collections", strings.NewReader(spec))
+	assert.Equal(t, http.StatusAccepted, resp.StatusCode)
+}
+
+func TestServer_CollectionEviction(t *testing.T) {
+	s, ts := newTestServer(t)
+	s.opts.MaxCollections = 2
+	s.collect = func(spec *troubleshootv1beta2.SupportBundleSpec, redactors *troubleshootv1beta2.Redactor, outputPath string, progressChan chan interface{}) (*supportbundle.SupportBundleResponse, error) {
+		if err := os.WriteFile(outputPath, testBundle(t), 0600); err != nil {
+			return nil, err
+		}
+		return &supportbundle.SupportBundleResponse{ArchivePath: outputPath}, nil
+	}
+
+	spec := "apiVersion: troubleshoot.sh/v1beta2\nkind: SupportBundle\nspec:\n  collectors:\n    - clusterInfo: {}\n"
+	ids := []string{}
+	for i := 0; i < 3; i++ {
+		resp := doRequest(t, http.MethodPost, ts.URL+"/v1/collections", strings.NewReader(spec))
+		require.Equal(t, http.StatusAccepted, resp.StatusCode)
+		created := Collection{}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&created))
+		ids = append(ids, created.ID)
+
+		require.Eventually(t, func() bool {
+			c, ok := s.getCollectionByID(created.ID)
+			return ok && c.isDone()
+		}, 5*time.Second, 10*time.Millisecond)
+	}
+
+	require.Eventually(t, func() bool {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return len(s.collections) == 2
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// the oldest collection is forgotten and its bundle deleted
+	resp := doRequest(t, http.MethodGet, ts.URL+"/v1/collections/"+ids[0], nil)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	assert.NoFileExists(t, s.bundlePath(ids[0]))
+
+	for _, id := range ids[1:] {
+		resp := doRequest(t, http.MethodGet, ts.URL+"/v1/collections/"+id, nil)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.FileExists(t, s.bundlePath(id))
+	}
+}
+
+func TestServer_InvalidCollection(t *testing.T) {
+	_, ts := newTestServer(t)
+
+	resp := doRequest(t, http.MethodPost, ts.URL+"/v1/collections", strings.NewReader("apiVersion: troubleshoot.sh/v1beta2\nkind: Redactor\n"))
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	resp = doRequest(t, http.MethodGet, ts.URL+"/v1/collections/missing", nil)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func Test_parseSupportBundleSpec(t *testing.T) {
+	spec := `apiVersion: troubleshoot.sh/v1beta2
+kind: HostCollector
+metadata:
+  name: test
+spec:
+  collectors:
+    - run:
+        command: "cat"
+        args: ["/etc/shadow"]
+`
+	parsed, _, err := parseSupportBundleSpec(context.Background(), spec)
+	require.NoError(t, err)
+	assert.Len(t, parsed.HostCollectors, 1)
+	// host collectors must not run on the host of the server
+	assert.True(t, parsed.RunHostCollectorsInPod)
+}
+
+func TestServer_Bundles(t *testing.T) {
+	s, ts := newTestServer(t)
+	s.opts.MaxUploadSize = 1 << 20
+
+	resp := doRequest(t, http.MethodPost, ts.URL+"/v1/bundles", bytes.NewReader(testBundle(t)))
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	bundle := Bundle{}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&bundle))
+	assert.Regexp(t, idPattern, bundle.ID)
+
+	spec := `apiVersion: troubleshoot.sh/v1beta2
+kind: Analyzer
+metadata:
+  name: test
+spec:
+  analyzers:
+    - textAnalyze:
+        checkName: Greeting
+        fileName: hello.txt
+        regex: hello
+        outcomes:
+          - pass:
+              when: "true"
+              message: Found the greeting
+          - fail:
+              when: "false"
+              message: No greeting
+`
+	resp = doRequest(t, http.MethodPost, ts.URL+"/v1/bundles/"+bundle.ID+"/analyze", strings.NewReader(spec))
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	results := []analyzer.AnalyzeResult{}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&results))
+	require.Len(t, results, 1)
+	assert.Equal(t, "Greeting", results[0].Title)
+	assert.True(t, results[0].IsPass)
+
+	resp = doRequest(t, http.MethodGet, ts.URL+"/v1/bundles/"+bundle.ID+"/search?q=World", nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	searchResults := SearchResults{}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&searchResults))
+	assert.Equal(t, 1, searchResults.Total)
+	require.Len(t, searchResults.Matches, 1)
+	assert.Equal(t, "hello.txt", searchResults.Matches[0].File)
+	assert.Equal(t, "hello world", searchResults.Matches[0].Text)
+	assert.FileExists(t, s.bundleIndexPath(bundle.ID))
+
+	resp = doRequest(t, http.MethodGet, ts.URL+"/v1/bundles/"+bundle.ID+"/search?q=hello&limit=0", nil)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	resp = doRequest(t, http.MethodDelete, ts.URL+"/v1/bundles/"+bundle.ID, nil)
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	assert.NoFileExists(t, s.bundleIndexPath(bundle.ID))
+
+	resp = doRequest(t, http.MethodGet, ts.URL+"/v1/bundles/"+bundle.ID, nil)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	resp = doRequest(t, http.MethodGet, ts.URL+"/v1/bundles/..%2Fserver", nil)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	resp = doRequest(t, http.MethodPost, ts.URL+"/v1/bundles", bytes.NewReader(make([]byte, 2<<20)))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, resp.StatusCode)
+}
+
+func TestServer_AnalyzeOperations(t *testing.T) {
+	s, ts := newTestServer(t)
+
+	resp := doRequest(t, http.MethodPost, ts.URL+"/v1/bundles", bytes.NewReader(testBundle(t)))
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	bundle := Bundle{}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&bundle))
+
+	specs := []string{
+		`apiVersion: troubleshoot.sh/v1beta2
+kind: Analyzer
+spec:
+  analyzers:
+    - plugin:
+        name: example
+        fileName: hello.txt
+`,
+		`apiVersion: troubleshoot.sh/v1beta2
+kind: SupportBundle
+spec:
+  analyzers:
+    - wasm:
+        image: registry.example.com/analyzers/hello:1.0.0
+        fileName: hello.txt
+`,
+	}
+	for _, spec := range specs {
+		resp := doRequest(t, http.MethodPost, ts.URL+"/v1/bundles/"+bundle.ID+"/analyze", strings.NewReader(spec))
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	}
+
+	s.opts.ApproveOperations = true
+	resp = doRequest(t, http.MethodPost, ts.URL+"/v1/bundles/"+bundle.ID+"/analyze", strings.NewReader(specs[0]))
+	assert.NotEqual(t, http.StatusForbidden, resp.StatusCode)
+}
+
+func TestServer_BundleEviction(t *testing.T) {
+	s, ts := newTestServer(t)
+	s.opts.MaxBundles = 2
+
+	ids := []string{}
+	for i := 0; i < 3; i++ {
+		resp := doRequest(t, http.MethodPost, ts.URL+"/v1/bundles", bytes.NewReader(testBundle(t)))
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		bundle := Bundle{}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&bundle))
+		ids = append(ids, bundle.ID)
+	}
+
+	// the oldest uploaded bundle is deleted
+	resp := doRequest(t, http.MethodGet, ts.URL+"/v1/bundles/"+ids[0], nil)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	assert.NoFileExists(t, s.bundlePath(ids[0]))
+	for _, id := range ids[1:] {
+		assert.FileExists(t, s.bundlePath(id))
+	}
+
+	// deleted bundles no longer count
+	resp = doRequest(t, http.MethodDelete, ts.URL+"/v1/bundles/"+ids[1], nil)
+	require.Equal(t, http.StatusNoContent, resp.StatusCode)
+	resp = doRequest(t, http.MethodPost, ts.URL+"/v1/bundles", bytes.NewReader(testBundle(t)))
+	require.Equal(t, http.StatusCreated, resp.StatusCode)
+	assert.FileExists(t, s.bundlePath(ids[2]))
+}
+
+func Test_collection_eventsSince(t *testing.T) {
+	c := newCollection("id")
+	c.addEvent(ProgressEvent{Message: "one"})
+	c.finish()
+
+	events, done, _ := c.eventsSince(0)
+	assert.True(t, done)
+	assert.Equal(t, []ProgressEvent{{Message: "one"}}, events)
+}
@@ -0,0 +1,228 @@
+package supportbundle
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"io"
+	"os"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	analyzer "github.com/replicatedhq/troubleshoot/pkg/analyze"
+	"github.com/replicatedhq/troubleshoot/pkg/bundleindex"
+	"github.com/replicatedhq/troubleshoot/pkg/collect"
+)
+
+// maxBrowsedFileSize limits how much of each file in the archive is held in memory.
+const maxBrowsedFileSize = 100 * 1024 * 1024
+
+// BundleBrowser reads the files of a support bundle archive into memory, so that they can be
+// listed and searched without extracting the archive to disk.
+type BundleBrowser struct {
+	files map[string][]byte
+	names []string
+	// index is built on the first search
+	index *bundleindex.Index
+}
+
+type GrepMatch struct {
+	File string
+	Line int
+	Text string
+}
+
+// NewBundleBrowser reads all regular files from a support bundle archive. File names are
+// relative to the root directory of the bundle.
+func NewBundleBrowser(archivePath string) (*BundleBrowser, error) {
+	f, err := os.Open(archivePath)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open archive")
+	}
+	defer f.Close()
+
+	ar, err := collect.NewBundleArchiveReader(f)
+	if err != nil {
+		return nil, err
+	}
+	defer ar.Close()
+
+	return newBundleBrowser(ar)
+}
+
+func newBundleBrowser(tr *collect.BundleArchiveReader) (*BundleBrowser, error) {
+	files := map[string][]byte{}
+
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read tar header")
+		}
+
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		// Skip paths that escape the archive, like unarchive does
+		name := path.Clean(header.Name)
+		if strings.HasPrefix(name, "../") || strings.HasPrefix(name, "/") {
+			continue
+		}
+
+		content, err := io.ReadAll(io.LimitReader(tr, maxBrowsedFileSize))
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read %s", header.Name)
+		}
+
+		files[name] = content
+	}
+
+	root := bundleRootDir(files)
+
+	b := &BundleBrowser{
+		files: map[string][]byte{},
+		names: []string{},
+	}
+	for name, content := range files {
+		name = strings.TrimPrefix(name, root)
+		b.files[name] = content
+		b.names = append(b.names, name)
+	}
+	sort.Strings(b.names)
+
+	return b, nil
+}
+
+// bundleRootDir returns the directory all of the files in the archive are in, including the
+// trailing slash, or an empty string if there is none.
+func bundleRootDir[T any](files map[string]T) string {
+	root := ""
+	for name := range files {
+		dir, _, found := strings.Cut(name, "/")
+		if !found || (root != "" && root != dir+"/") {
+			return ""
+		}
+		root = dir + "/"
+	}
+	return root
+}
+
+// Files returns the names of the files in the bundle in lexical order.
+func (b *BundleBrowser) Files() []string {
+	return b.names
+}
+
+func (b *BundleBrowser) ReadFile(name string) ([]byte, error) {
+	content, ok := b.files[name]
+	if !ok {
+		return nil, errors.Errorf("file %s not found in bundle", name)
+	}
+	return content, nil
+}
+
+// RelatedFiles returns the files that are likely to have been used to produce an analyzer
+// result. When the result refers to a Kubernetes object, these are the files that mention the
+// object in their path, otherwise all of the files in the bundle are returned.
+func (b *BundleBrowser) RelatedFiles(result *analyzer.AnalyzeResult) []string {
+	if result == nil || result.InvolvedObject == nil || result.InvolvedObject.Name == "" {
+		return b.names
+	}
+
+	related := []string{}
+	for _, name := range b.names {
+		if strings.Contains(name, result.InvolvedObject.Name) {
+			related = append(related, name)
+		}
+	}
+	if len(related) == 0 {
+		return b.names
+	}
+	return related
+}
+
+// Grep returns the lines of the files in the bundle that match the regular expression, in the
+// order of the files and lines. Files whose names do not match filesPattern are skipped, an empty
+// pattern searches all files.
+func (b *BundleBrowser) Grep(expression string, filesPattern string) ([]GrepMatch, error) {
+	re, err := regexp.Compile(expression)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid expression %q", expression)
+	}
+
+	matches := []GrepMatch{}
+	for _, name := range b.names {
+		if filesPattern != "" {
+			ok, err := path.Match(filesPattern, name)
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid pattern %q", filesPattern)
+			}
+			if !ok {
+				continue
+			}
+		}
+
+		scanner := bufio.NewScanner(bytes.NewReader(b.files[name]))
+		scanner.Buffer(make([]byte, 0, 64*1024), maxBrowsedFileSize)
+		line := 0
+		for scanner.Scan() {
+			line++
+			if re.Match(scanner.Bytes()) {
+				matches = append(matches, GrepMatch{
+					File: name,
+					Line: line,
+					Text: scanner.Text(),
+				})
+			}
+		}
+	}
+
+	return matches, nil
+}
+
+// Search returns the lines of the files in the bundle that have all of the words of the query,
+// ignoring case, in the order of the files and lines. Unlike Grep, the files are indexed on the
+// first search, so that later searches do not read all of the bundle again.
+func (b *BundleBrowser) Search(query string) ([]GrepMatch, error) {
+	if b.index == nil {
+		index := bundleindex.New()
+		for _, name := range b.names {
+			if err := index.Add(name, bytes.NewReader(b.files[name])); err != nil {
+				return nil, errors.Wrap(err, "failed to index bundle")
+			}
+		}
+		b.index = index
+	}
+
+	hits := b.index.Search(query)
+	linesByFile := map[string][]int{}
+	for _, hit := range hits {
+		linesByFile[hit.File] = append(linesByFile[hit.File], hit.Line)
+	}
+
+	matches := make([]GrepMatch, 0, len(hits))
+	texts := map[string]map[int]string{}
+	for _, hit := range hits {
+		fileTexts, ok := texts[hit.File]
+		if !ok {
+			var err error
+			fileTexts, err = bundleindex.ReadLines(bytes.NewReader(b.files[hit.File]), linesByFile[hit.File])
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to read %s", hit.File)
+			}
+			texts[hit.File] = fileTexts
+		}
+		matches = append(matches, GrepMatch{
+			File: hit.File,
+			Line: hit.Line,
+			Text: fileTexts[hit.Line],
+		})
+	}
+
+	return matches, nil
+}
@@ -0,0 +1,95 @@
+package supportbundle
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	analyzer "github.com/replicatedhq/troubleshoot/pkg/analyze"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func writeTestBundle(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	archivePath := filepath.Join(t.TempDir(), "bundle.tar.gz")
+	f, err := os.Create(archivePath)
+	require.NoError(t, err)
+	defer f.Close()
+
+	gzw := gzip.NewWriter(f)
+	defer gzw.Close()
+	tw := tar.NewWriter(gzw)
+	defer tw.Close()
+
+	for name, content := range files {
+		require.NoError(t, tw.WriteHeader(&tar.Header{
+			Name:     name,
+			Mode:     0644,
+			Size:     int64(len(content)),
+			Typeflag: tar.TypeReg,
+		}))
+		_, err := tw.Write([]byte(content))
+		require.NoError(t, err)
+	}
+
+	return archivePath
+}
+
+func TestBundleBrowser(t *testing.T) {
+	archivePath := writeTestBundle(t, map[string]string{
+		"support-bundle-2024-01-01T00_00_00/version.yaml":                                      "apiVersion: troubleshoot.sh/v1beta2\n",
+		"support-bundle-2024-01-01T00_00_00/cluster-resources/pods/default.json":               `{"items": []}`,
+		"support-bundle-2024-01-01T00_00_00/cluster-resources/pods/logs/default/api-0/api.log": "starting\nERROR: connection refused\nretrying\nERROR: timeout\n",
+		"../escape.txt": "ERROR: outside",
+	})
+
+	browser, err := NewBundleBrowser(archivePath)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"cluster-resources/pods/default.json",
+		"cluster-resources/pods/logs/default/api-0/api.log",
+		"version.yaml",
+	}, browser.Files())
+
+	content, err := browser.ReadFile("version.yaml")
+	require.NoError(t, err)
+	assert.Equal(t, "apiVersion: troubleshoot.sh/v1beta2\n", string(content))
+
+	_, err = browser.ReadFile("missing.json")
+	assert.Error(t, err)
+
+	matches, err := browser.Grep("ERROR", "")
+	require.NoError(t, err)
+	assert.Equal(t, []GrepMatch{
+		{File: "cluster-resources/pods/logs/default/api-0/api.log", Line: 2, Text: "ERROR: connection refused"},
+		{File: "cluster-resources/pods/logs/default/api-0/api.log", Line: 4, Text: "ERROR: timeout"},
+	}, matches)
+
+	matches, err = browser.Grep("ERROR", "*.yaml")
+	require.NoError(t, err)
+	assert.Empty(t, matches)
+
+	_, err = browser.Grep("(", "")
+	assert.Error(t, err)
+
+	matches, err = browser.Search("connection refused")
+	require.NoError(t, err)
+	assert.Equal(t, []GrepMatch{
+		{File: "cluster-resources/pods/logs/default/api-0/api.log", Line: 2, Text: "ERROR: connection refused"},
+	}, matches)
+
+	matches, err = browser.Search("error")
+	require.NoError(t, err)
+	assert.Len(t, matches, 2)
+
+	assert.Equal(t, []string{"cluster-resources/pods/logs/default/api-0/api.log"}, browser.RelatedFiles(&analyzer.AnalyzeResult{
+		InvolvedObject: &corev1.ObjectReference{Kind: "Pod", Namespace: "default", Name: "api-0"},
+	}))
+	assert.Equal(t, browser.Files(), browser.RelatedFiles(&analyzer.AnalyzeResult{}))
+}
@@ -7,9 +7,9 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
 	"reflect"
 	"strings"
-	"sync"
 	"time"
 
 	"github.com/pkg/errors"
@@ -18,43 +18,40 @@ import (
 	"github.com/replicatedhq/troubleshoot/pkg/collect"
 	"github.com/replicatedhq/troubleshoot/pkg/constants"
 	"github.com/replicatedhq/troubleshoot/pkg/convert"
+	"github.com/replicatedhq/troubleshoot/pkg/facts"
+	"github.com/replicatedhq/troubleshoot/pkg/k8sutil"
+	"github.com/replicatedhq/troubleshoot/pkg/metrics"
 	"github.com/replicatedhq/troubleshoot/pkg/version"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
-	"golang.org/x/sync/errgroup"
-	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
-	v1 "k8s.io/api/core/v1"
-	kuberneteserrors "k8s.io/apimachinery/pkg/api/errors"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/runtime"
-	"k8s.io/apiserver/pkg/storage/names"
 	"k8s.io/client-go/kubernetes"
-	"k8s.io/client-go/rest"
-	"k8s.io/client-go/tools/remotecommand"
 	"k8s.io/klog/v2"
-	"k8s.io/utils/ptr"
 )
 
-const (
-	selectorLabelKey   = "ds-selector-label"
-	selectorLabelValue = "remote-host-collector"
-	defaultTimeout     = 30
-)
-
-func runHostCollectors(ctx context.Context, hostCollectors []*troubleshootv1beta2.HostCollect, additionalRedactors *troubleshootv1beta2.Redactor, bundlePath string, opts SupportBundleCreateOpts) (collect.CollectorResult, error) {
+func runHostCollectors(ctx context.Context, hostCollectors []*troubleshootv1beta2.HostCollect, additionalRedactors *troubleshootv1beta2.Redactor, bundlePath string, opts SupportBundleCreateOpts, skipped *collect.SkippedCollectors) (collect.CollectorResult, error) {
 
 	var err error
 	var collectResult map[string][]byte
 
-	if opts.RunHostCollectorsInPod {
-		collectResult, err = runRemoteHostCollectors(ctx, hostCollectors, bundlePath, opts)
+	if opts.RunHostCollectorsInPod && opts.namespacedScope != nil {
+		// running host collectors in pods needs to list nodes and create daemonsets
+		opts.CollectorProgressCallback(opts.ProgressChan, "skipping host collectors, running them in pods is not possible in namespaced scope")
+		for _, collectorSpec := range hostCollectors {
+			skipped.AddHostCollector(collectorSpec, collect.SkipReasonClusterScoped)
+		}
+		return collect.NewResult(), nil
+	} else if opts.RunHostCollectorsInPod {
+		collectResult, err = runRemoteHostCollectors(ctx, hostCollectors, bundlePath, opts, skipped)
 		if err != nil {
 			return collectResult, err
 		}
 	} else {
-		collectResult = runLocalHostCollectors(ctx, hostCollectors, bundlePath, opts)
+		collectResult, err = runLocalHostCollectors(ctx, hostCollectors, bundlePath, opts, skipped)
+		if err != nil {
+			return collectResult, err
+		}
 	}
 
 	// redact result if any
@@ -64,12 +61,13 @@ func runHostCollectors(ctx context.Context, hostCollectors []*troubleshootv1beta
 	}
 
 	if opts.Redact {
-		_, span := otel.Tracer(constants.LIB_TRACER_NAME).Start(ctx, "Host collectors")
+		redactCtx, span := otel.Tracer(constants.LIB_TRACER_NAME).Start(ctx, "Host collectors")
 		span.SetAttributes(attribute.String("type", "Redactors"))
-		err := collect.RedactResult(bundlePath, collectResult, globalRedactors)
+		err := collect.RedactResultWithTokenizer(redactCtx, bundlePath, collectResult, globalRedactors, opts.redactionTokens)
 		if err != nil {
 			err = errors.Wrap(err, "failed to redact host collector results")
 			span.SetStatus(codes.Error, err.Error())
+			span.End()
 			return collectResult, err
 		}
 		span.End()
@@ -78,19 +76,17 @@ func runHostCollectors(ctx context.Context, hostCollectors []*troubleshootv1beta
 	return collectResult, nil
 }
 
-func runCollectors(ctx context.Context, collectors []*troubleshootv1beta2.Collect, additionalRedactors *troubleshootv1beta2.Redactor, bundlePath string, opts SupportBundleCreateOpts) (collect.CollectorResult, error) {
+func runCollectors(ctx context.Context, collectors []*troubleshootv1beta2.Collect, additionalRedactors *troubleshootv1beta2.Redactor, bundlePath string, opts SupportBundleCreateOpts, skipped *collect.SkippedCollectors) (collect.CollectorResult, error) {
 	var allCollectors []collect.Collector
 	var foundForbidden bool
 
-	collectSpecs := make([]*troubleshootv1beta2.Collect, 0)
-	collectSpecs = append(collectSpecs, collectors...)
-	collectSpecs = collect.EnsureCollectorInList(collectSpecs, troubleshootv1beta2.Collect{ClusterInfo: &troubleshootv1beta2.ClusterInfo{}})
-	collectSpecs = collect.EnsureCollectorInList(collectSpecs, troubleshootv1beta2.Collect{ClusterResources: &troubleshootv1beta2.ClusterResources{}})
-	collectSpecs = collect.DedupCollectors(collectSpecs)
-	collectSpecs = collect.EnsureClusterResourcesFirst(collectSpecs)
+	collectSpecs := collectorSpecs(collectors)
 
-	opts.KubernetesRestConfig.QPS = constants.DEFAULT_CLIENT_QPS
-	opts.KubernetesRestConfig.Burst = constants.DEFAULT_CLIENT_BURST
+	// keep the throttling of clients configured by the caller, e.g. in-cluster
+	if opts.KubernetesRestConfig.QPS == 0 {
+		opts.KubernetesRestConfig.QPS = constants.DEFAULT_CLIENT_QPS
+		opts.KubernetesRestConfig.Burst = constants.DEFAULT_CLIENT_BURST
+	}
 	opts.KubernetesRestConfig.UserAgent = fmt.Sprintf("%s/%s", constants.DEFAULT_CLIENT_USER_AGENT, version.Version())
 
 	k8sClient, err := kubernetes.NewForConfig(opts.KubernetesRestConfig)
@@ -98,15 +94,40 @@ func runCollectors(ctx context.Context, collectors []*troubleshootv1beta2.Collec
 		return nil, errors.Wrap(err, "failed to instantiate Kubernetes client")
 	}
 
+	collectSpecs, err = facts.ResolveCollectorExcludes(collectSpecs, opts.facts)
+	if err != nil {
+		opts.ProgressChan <- err
+	}
+
+	// the collectors of the run share the port-forwards to the same pods
+	portForwards, err := k8sutil.NewPortForwardPool(opts.KubernetesRestConfig)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create port-forward pool")
+	}
+	defer portForwards.Close()
+
 	allCollectorsMap := make(map[reflect.Type][]collect.Collector)
-	allCollectedData := make(map[string][]byte)
+
+	store := collect.NewResultStore(bundlePath, 0)
+	defer store.Close()
 
 	for _, desiredCollector := range collectSpecs {
-		if collectorInterface, ok := collect.GetCollector(desiredCollector, bundlePath, opts.Namespace, opts.KubernetesRestConfig, k8sClient, opts.SinceTime); ok {
+		if collectorInterface, ok := collect.GetCollectorWithContext(ctx, desiredCollector, bundlePath, opts.Namespace, opts.KubernetesRestConfig, k8sClient, opts.SinceTime); ok {
 			if collector, ok := collectorInterface.(collect.Collector); ok {
-				err := collector.CheckRBAC(ctx, collector, desiredCollector, opts.KubernetesRestConfig, opts.Namespace)
-				if err != nil {
-					return nil, errors.Wrap(err, "failed to check RBAC for collectors")
+				if c, ok := collector.(collect.PortForwardingCollector); ok {
+					c.SetPortForwardPool(portForwards)
+				}
+				if opts.namespacedScope != nil && !opts.namespacedScope.Restrict(collector, skipped) {
+					msg := fmt.Sprintf("skipping collector %q outside of the namespaced scope", collector.Title())
+					opts.CollectorProgressCallback(opts.ProgressChan, msg)
+					continue
+				}
+				// cluster resources checks for cluster-wide access, which is not expected in namespaced scope
+				if _, ok := collector.(*collect.CollectClusterResources); !ok || opts.namespacedScope == nil {
+					err := collector.CheckRBAC(ctx, collector, desiredCollector, opts.KubernetesRestConfig, opts.Namespace)
+					if err != nil {
+						return nil, errors.Wrap(err, "failed to check RBAC for collectors")
+					}
 				}
 				collectorType := reflect.TypeOf(collector)
 				allCollectorsMap[collectorType] = append(allCollectorsMap[collectorType], collector)
@@ -135,6 +156,15 @@ func runCollectors(ctx context.Context, collectors []*troubleshootv1beta2.Collec
 		}
 	}
 
+	if opts.namespacedScope != nil {
+		msg := fmt.Sprintf("collecting from namespaces %s, skipping cluster-scoped resources", strings.Join(opts.namespacedScope.Namespaces, ", "))
+		opts.CollectorProgressCallback(opts.ProgressChan, msg)
+		skipped.AddResources("cluster-resources", collect.ClusterScopedResources, collect.SkipReasonClusterScoped)
+	}
+	if opts.metadataOnly {
+		opts.CollectorProgressCallback(opts.ProgressChan, "collecting metadata only, payloads are replaced with their size, line count and sha256")
+	}
+
 	if foundForbidden && !opts.CollectWithoutPermissions {
 		return nil, collect.ErrInsufficientPermissionsToRun
 	}
@@ -143,6 +173,9 @@ func runCollectors(ctx context.Context, collectors []*troubleshootv1beta2.Collec
 	allCollectors = collect.EnsureCopyLast(allCollectors)
 
 	for _, collector := range allCollectors {
+		if ctx.Err() != nil {
+			break
+		}
 		_, span := otel.Tracer(constants.LIB_TRACER_NAME).Start(ctx, collector.Title())
 		span.SetAttributes(attribute.String("type", reflect.TypeOf(collector).String()))
 
@@ -150,6 +183,7 @@ func runCollectors(ctx context.Context, collectors []*troubleshootv1beta2.Collec
 		if isExcluded {
 			msg := fmt.Sprintf("excluding %q collector", collector.Title())
 			opts.CollectorProgressCallback(opts.ProgressChan, msg)
+			skipped.AddCollector(collector, collect.SkipReasonExcluded)
 			span.SetAttributes(attribute.Bool(constants.EXCLUDED, true))
 			span.End()
 			continue
@@ -160,25 +194,49 @@ func runCollectors(ctx context.Context, collectors []*troubleshootv1beta2.Collec
 			if _, ok := collector.(*collect.CollectClusterResources); !ok {
 				msg := fmt.Sprintf("skipping collector %q with insufficient RBAC permissions", collector.Title())
 				opts.CollectorProgressCallback(opts.ProgressChan, msg)
+				skipped.AddCollector(collector, collect.SkipReasonInsufficientRBAC)
 				span.SetStatus(codes.Error, "skipping collector, insufficient RBAC permissions")
 				span.End()
 				continue
 			}
 		}
 		opts.CollectorProgressCallback(opts.ProgressChan, collector.Title())
-		result, err := collector.Collect(opts.ProgressChan)
+		startedAt := time.Now()
+		result, attempts, err := collect.CollectWithRetries(ctx, collector, opts.ProgressChan)
 		if err != nil {
 			span.SetStatus(codes.Error, err.Error())
 			opts.ProgressChan <- errors.Errorf("failed to run collector: %s: %v", collector.Title(), err)
 		}
+		span.SetAttributes(attribute.Int("attempts", len(attempts)))
+		if opts.retried != nil {
+			opts.retried.AddCollector(collector, attempts)
+		}
+		if opts.metadataOnly {
+			if err := collect.StripPayloads(collector, result, bundlePath); err != nil {
+				opts.ProgressChan <- errors.Errorf("failed to strip payloads of collector: %s: %v", collector.Title(), err)
+			}
+		}
+		if opts.bundleIndex != nil {
+			opts.bundleIndex.AddCollector(collector, result, startedAt, err)
+		}
+		if maxSize, err := collect.GetCollectorMaxSize(collector); err != nil {
+			opts.ProgressChan <- errors.Errorf("failed to apply maxSize of collector: %s: %v", collector.Title(), err)
+		} else if err := truncateToMaxSize(opts, collector.Title(), result, bundlePath, maxSize); err != nil {
+			opts.ProgressChan <- err
+		}
+		observeCollector(collect.CollectorKind(collector), startedAt, result, bundlePath, err)
+		span.SetAttributes(attribute.Int("files", len(result)))
 
-		for k, v := range result {
-			allCollectedData[k] = v
+		if err := store.AddResult(result); err != nil {
+			opts.ProgressChan <- errors.Wrapf(err, "failed to save results of collector: %s", collector.Title())
 		}
 		span.End()
 	}
 
-	collectResult := allCollectedData
+	collectResult, err := store.Result()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read collector results")
+	}
 
 	globalRedactors := []*troubleshootv1beta2.Redact{}
 	if additionalRedactors != nil {
@@ -187,9 +245,9 @@ func runCollectors(ctx context.Context, collectors []*troubleshootv1beta2.Collec
 
 	if opts.Redact {
 		// TODO: Should we record how long each redactor takes?
-		_, span := otel.Tracer(constants.LIB_TRACER_NAME).Start(ctx, "In-cluster collectors")
+		redactCtx, span := otel.Tracer(constants.LIB_TRACER_NAME).Start(ctx, "In-cluster collectors")
 		span.SetAttributes(attribute.String("type", "Redactors"))
-		err := collect.RedactResult(bundlePath, collectResult, globalRedactors)
+		err := collect.RedactResultWithTokenizer(redactCtx, bundlePath, collectResult, globalRedactors, opts.redactionTokens)
 		if err != nil {
 			err := errors.Wrap(err, "failed to redact in cluster collector results")
 			span.SetStatus(codes.Error, err.Error())
@@ -202,6 +260,100 @@ func runCollectors(ctx context.Context, collectors []*troubleshootv1beta2.Collec
 	return collectResult, nil
 }
 
+// collectorSpecs returns the collectors to run: the collectors of the spec, deduplicated, along with
+// the cluster info and cluster resources collectors that always run.
+func collectorSpecs(collectors []*troubleshootv1beta2.Collect) []*troubleshootv1beta2.Collect {
+	collectSpecs := make([]*troubleshootv1beta2.Collect, 0)
+	collectSpecs = append(collectSpecs, collectors...)
+	collectSpecs = collect.EnsureCollectorInList(collectSpecs, troubleshootv1beta2.Collect{ClusterInfo: &troubleshootv1beta2.ClusterInfo{}})
+	collectSpecs = collect.EnsureCollectorInList(collectSpecs, troubleshootv1beta2.Collect{ClusterResources: &troubleshootv1beta2.ClusterResources{}})
+	collectSpecs = collect.DedupCollectors(collectSpecs)
+	return collect.EnsureClusterResourcesFirst(collectSpecs)
+}
+
+// runCustomCollectors saves the results of the custom collectors in the bundle. A failing
+// collector is reported on the progress channel and does not stop the others.
+func runCustomCollectors(ctx context.Context, collectors []CustomCollector, additionalRedactors *troubleshootv1beta2.Redactor, bundlePath string, opts SupportBundleCreateOpts) (collect.CollectorResult, error) {
+	collectResult := collect.NewResult()
+
+	for _, collector := range collectors {
+		_, span := otel.Tracer(constants.LIB_TRACER_NAME).Start(ctx, collector.Title())
+		span.SetAttributes(attribute.String("type", reflect.TypeOf(collector).String()))
+
+		opts.CollectorProgressCallback(opts.ProgressChan, collector.Title())
+		startedAt := time.Now()
+		files, err := collector.Collect(ctx)
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+			opts.ProgressChan <- errors.Errorf("failed to run collector: %s: %v", collector.Title(), err)
+		}
+
+		saved := collect.NewResult()
+		for name, data := range files {
+			if !filepath.IsLocal(name) {
+				opts.ProgressChan <- errors.Errorf("collector %s returned file %q outside of the bundle", collector.Title(), name)
+				continue
+			}
+			if err := saved.SaveResult(bundlePath, name, bytes.NewReader(data)); err != nil {
+				span.End()
+				return collectResult, errors.Wrapf(err, "failed to save result of collector %s", collector.Title())
+			}
+		}
+		collectResult.AddResult(saved)
+		if opts.bundleIndex != nil {
+			opts.bundleIndex.AddFiles(collector.Title(), false, saved, startedAt, err)
+		}
+		observeCollector(collector.Title(), startedAt, saved, bundlePath, err)
+		span.SetAttributes(attribute.Int("files", len(saved)))
+		span.End()
+	}
+
+	if opts.Redact {
+		globalRedactors := []*troubleshootv1beta2.Redact{}
+		if additionalRedactors != nil {
+			globalRedactors = additionalRedactors.Spec.Redactors
+		}
+
+		redactCtx, span := otel.Tracer(constants.LIB_TRACER_NAME).Start(ctx, "Custom collectors")
+		span.SetAttributes(attribute.String("type", "Redactors"))
+		defer span.End()
+		if err := collect.RedactResultWithTokenizer(redactCtx, bundlePath, collectResult, globalRedactors, opts.redactionTokens); err != nil {
+			err = errors.Wrap(err, "failed to redact custom collector results")
+			span.SetStatus(codes.Error, err.Error())
+			return collectResult, err
+		}
+	}
+
+	return collectResult, nil
+}
+
+// observeCollector records the run of a collector in the metrics, with the size of the files it saved
+func observeCollector(kind string, startedAt time.Time, result collect.CollectorResult, bundlePath string, err error) {
+	size, sizeErr := collect.ResultSize(result, bundlePath)
+	if sizeErr != nil {
+		klog.V(2).Infof("Failed to get size of %s collector results: %v", kind, sizeErr)
+	}
+	metrics.ObserveCollector(kind, time.Since(startedAt), size, err)
+}
+
+// truncateToMaxSize truncates the files of a result to fit maxSize, and records the truncated
+// files in the bundle index. A maxSize of 0 means there is no limit.
+func truncateToMaxSize(opts SupportBundleCreateOpts, title string, result collect.CollectorResult, bundlePath string, maxSize int64) error {
+	truncated, err := collect.TruncateResult(result, bundlePath, maxSize)
+	if err != nil {
+		return errors.Wrapf(err, "failed to truncate %s to maxSize", title)
+	}
+	if len(truncated) == 0 {
+		return nil
+	}
+
+	opts.ProgressChan <- fmt.Sprintf("[%s] Truncated %d files to fit maxSize", title, len(truncated))
+	if opts.bundleIndex != nil {
+		opts.bundleIndex.AddTruncated(truncated)
+	}
+	return nil
+}
+
 func findFileName(basename, extension string) (string, error) {
 	n := 1
 	name := basename
@@ -228,137 +380,90 @@ func getAnalysisFile(analyzeResults []*analyze.AnalyzeResult) (io.Reader, error)
 	return bytes.NewBuffer(analysis), nil
 }
 
-func runLocalHostCollectors(ctx context.Context, hostCollectors []*troubleshootv1beta2.HostCollect, bundlePath string, opts SupportBundleCreateOpts) map[string][]byte {
+func runLocalHostCollectors(ctx context.Context, hostCollectors []*troubleshootv1beta2.HostCollect, bundlePath string, opts SupportBundleCreateOpts, skipped *collect.SkippedCollectors) (collect.CollectorResult, error) {
 	collectSpecs := make([]*troubleshootv1beta2.HostCollect, 0)
 	collectSpecs = append(collectSpecs, hostCollectors...)
 
-	allCollectedData := make(map[string][]byte)
+	store := collect.NewResultStore(bundlePath, 0)
+	defer store.Close()
 
 	var collectors []collect.HostCollector
+	var specs []*troubleshootv1beta2.HostCollect
 	for _, desiredCollector := range collectSpecs {
-		collector, ok := collect.GetHostCollector(desiredCollector, bundlePath)
+		collector, ok := collect.GetHostCollectorWithContext(ctx, desiredCollector, bundlePath)
 		if ok {
 			collectors = append(collectors, collector)
+			specs = append(specs, desiredCollector)
 		}
 	}
 
-	for _, collector := range collectors {
-		// TODO: Add context to host collectors
+	privileges := collect.DetectHostPrivileges(os.DirFS("/"))
+	for i, collector := range collectors {
+		if ctx.Err() != nil {
+			break
+		}
 		_, span := otel.Tracer(constants.LIB_TRACER_NAME).Start(ctx, collector.Title())
 		span.SetAttributes(attribute.String("type", reflect.TypeOf(collector).String()))
 
 		isExcluded, _ := collector.IsExcluded()
 		if isExcluded {
 			opts.ProgressChan <- fmt.Sprintf("[%s] Excluding host collector", collector.Title())
+			skipped.AddHostCollector(specs[i], collect.SkipReasonExcluded)
 			span.SetAttributes(attribute.Bool(constants.EXCLUDED, true))
 			span.End()
 			continue
 		}
 
+		if missing := privileges.Missing(collect.RequiredHostPrivileges(specs[i])); len(missing) > 0 {
+			opts.ProgressChan <- fmt.Sprintf("[%s] Collecting without %s", collector.Title(), strings.Join(missing, ", "))
+			skipped.AddHostPrivileges(specs[i], missing)
+		}
+
 		opts.ProgressChan <- fmt.Sprintf("[%s] Running host collector...", collector.Title())
+		startedAt := time.Now()
 		result, err := collector.Collect(opts.ProgressChan)
 		if err != nil {
 			span.SetStatus(codes.Error, err.Error())
 			opts.ProgressChan <- errors.Errorf("failed to run host collector: %s: %v", collector.Title(), err)
 		}
+		if opts.bundleIndex != nil {
+			opts.bundleIndex.AddHostCollector(specs[i], result, startedAt, err)
+		}
+		if maxSize, err := collect.GetHostCollectorMaxSize(specs[i]); err != nil {
+			opts.ProgressChan <- errors.Errorf("failed to apply maxSize of host collector: %s: %v", collector.Title(), err)
+		} else if err := truncateToMaxSize(opts, collector.Title(), result, bundlePath, maxSize); err != nil {
+			opts.ProgressChan <- err
+		}
+		kind, _ := collect.GetSpecKind(specs[i])
+		observeCollector(kind, startedAt, result, bundlePath, err)
+		span.SetAttributes(attribute.Int("files", len(result)))
 		span.End()
-		for k, v := range result {
-			allCollectedData[k] = v
+		if err := store.AddResult(result); err != nil {
+			opts.ProgressChan <- errors.Wrapf(err, "failed to save results of host collector: %s", collector.Title())
 		}
 	}
 
-	return allCollectedData
-}
-
-// getExecOutputs executes `collect -` with collector data passed to stdin and returns stdout, stderr and error
-func getExecOutputs(
-	ctx context.Context, clientConfig *rest.Config, client *kubernetes.Clientset, pod corev1.Pod, collectorData []byte,
-) ([]byte, []byte, error) {
-	container := pod.Spec.Containers[0].Name
-
-	req := client.CoreV1().RESTClient().Post().Resource("pods").Name(pod.Name).Namespace(pod.Namespace).SubResource("exec")
-	scheme := runtime.NewScheme()
-	if err := corev1.AddToScheme(scheme); err != nil {
-		return nil, nil, err
-	}
-
-	parameterCodec := runtime.NewParameterCodec(scheme)
-	req.VersionedParams(&corev1.PodExecOptions{
-		Command:   []string{"/troubleshoot/collect", "-", "--chroot", "/host", "--format", "raw"},
-		Container: container,
-		Stdin:     true,
-		Stdout:    true,
-		Stderr:    true,
-		TTY:       false,
-	}, parameterCodec)
-
-	exec, err := remotecommand.NewSPDYExecutor(clientConfig, "POST", req.URL())
-	if err != nil {
-		return nil, nil, err
-	}
-
-	stdout := new(bytes.Buffer)
-	stderr := new(bytes.Buffer)
-
-	err = exec.StreamWithContext(ctx, remotecommand.StreamOptions{
-		Stdin:  bytes.NewBuffer(collectorData),
-		Stdout: stdout,
-		Stderr: stderr,
-		Tty:    false,
-	})
-
+	collectResult, err := store.Result()
 	if err != nil {
-		return stdout.Bytes(), stderr.Bytes(), err
+		return nil, errors.Wrap(err, "failed to read host collector results")
 	}
-	return stdout.Bytes(), stderr.Bytes(), nil
+	return collectResult, nil
 }
 
-func runRemoteHostCollectors(ctx context.Context, hostCollectors []*troubleshootv1beta2.HostCollect, bundlePath string, opts SupportBundleCreateOpts) (map[string][]byte, error) {
-	output := collect.NewResult()
+// remoteHostCollectorsKind identifies the remote host collectors, which run together in a single pass
+const remoteHostCollectorsKind = "remote-host-collectors"
 
-	clientset, err := kubernetes.NewForConfig(opts.KubernetesRestConfig)
-	if err != nil {
-		return nil, err
-	}
+func runRemoteHostCollectors(ctx context.Context, hostCollectors []*troubleshootv1beta2.HostCollect, bundlePath string, opts SupportBundleCreateOpts, skipped *collect.SkippedCollectors) (map[string][]byte, error) {
+	output := collect.NewResult()
 
 	// TODO: rbac check
 
-	// create remote pod for each node
-	labels := map[string]string{
-		"troubleshoot.sh/remote-collector": "true",
-	}
-
-	var mu sync.Mutex
-	nodeLogs := make(map[string]map[string][]byte)
-
-	ds, err := createHostCollectorDS(ctx, clientset, labels, "default")
-	if err != nil {
-		return nil, err
-	}
-
-	// wait for at least one pod to be scheduled
-	err = waitForDS(ctx, clientset, ds)
-	if err != nil {
-		return nil, err
-	}
-
-	klog.V(2).Infof("Created Remote Host Collector Daemonset %s", ds.Name)
-	pods, err := clientset.CoreV1().Pods(ds.Namespace).List(ctx, metav1.ListOptions{
-		LabelSelector: selectorLabelKey + "=" + ds.Name,
-		// use the default logs collector timeout for now
-		TimeoutSeconds: ptr.To(int64(defaultTimeout)),
-		Limit:          0,
-	})
-	if err != nil {
-		return nil, err
-	}
-
-	var eg errgroup.Group
-
 	if err := saveNodeList(output, opts, bundlePath); err != nil {
 		return nil, err
 	}
 
+	collectSpecs := []*troubleshootv1beta2.HostCollect{}
+	titles := []string{}
 	for _, collectorSpec := range hostCollectors {
 		collector, ok := collect.GetHostCollector(collectorSpec, bundlePath)
 		if !ok {
@@ -366,96 +471,62 @@ func runRemoteHostCollectors(ctx context.Context, hostCollectors []*troubleshoot
 			continue
 		}
 
-		// Start a span for tracing
-		_, span := otel.Tracer(constants.LIB_TRACER_NAME).Start(ctx, collector.Title())
-		span.SetAttributes(attribute.String("type", "Collect"))
-
 		isExcluded, _ := collector.IsExcluded()
 		if isExcluded {
 			msg := fmt.Sprintf("[%s] Excluding host collector", collector.Title())
 			opts.CollectorProgressCallback(opts.ProgressChan, msg)
-			span.SetAttributes(attribute.Bool(constants.EXCLUDED, true))
-			span.End()
+			skipped.AddHostCollector(collectorSpec, collect.SkipReasonExcluded)
 			continue
 		}
 
-		// Send progress event: starting the collector
-		msg := fmt.Sprintf("[%s] Running host collector...", collector.Title())
-		opts.CollectorProgressCallback(opts.ProgressChan, msg)
-
-		// convert host collectors into a HostCollector spec
-		spec := createHostCollectorsSpec([]*troubleshootv1beta2.HostCollect{collectorSpec})
-		specJSON, err := json.Marshal(spec)
-		if err != nil {
-			return nil, err
-		}
-		klog.V(2).Infof("HostCollector spec: %s", specJSON)
-		for _, pod := range pods.Items {
-			eg.Go(func() error {
-				if err := waitForPodRunning(ctx, clientset, &pod); err != nil {
-					return err
-				}
+		collectSpecs = append(collectSpecs, collectorSpec)
+		titles = append(titles, collector.Title())
+	}
 
-				stdout, _, err := getExecOutputs(ctx, opts.KubernetesRestConfig, clientset, pod, specJSON)
-				if err != nil {
-					// span.SetStatus(codes.Error, err.Error())
-					msg := fmt.Sprintf("[%s] Error: %v", collector.Title(), err)
-					opts.CollectorProgressCallback(opts.ProgressChan, msg)
-					return errors.Wrap(err, "failed to run remote host collector")
-				}
+	if len(collectSpecs) == 0 {
+		return output, nil
+	}
 
-				result := map[string]string{}
-				if err := json.Unmarshal(stdout, &result); err != nil {
-					return err
-				}
+	// Start a span for tracing
+	_, span := otel.Tracer(constants.LIB_TRACER_NAME).Start(ctx, "RemoteHostCollectors")
+	span.SetAttributes(attribute.String("type", "Collect"))
+	defer span.End()
 
-				// Send progress event: completed successfully
-				msg = fmt.Sprintf("[%s] Completed host collector", collector.Title())
-				opts.CollectorProgressCallback(opts.ProgressChan, msg)
+	// Send progress event: starting the collectors
+	for _, title := range titles {
+		msg := fmt.Sprintf("[%s] Running host collector...", title)
+		opts.CollectorProgressCallback(opts.ProgressChan, msg)
+	}
 
-				// Aggregate the results
-				mu.Lock()
-				for file, data := range result {
-					if nodeLogs[pod.Spec.NodeName] == nil {
-						nodeLogs[pod.Spec.NodeName] = make(map[string][]byte)
-					}
-					nodeLogs[pod.Spec.NodeName][file] = []byte(data)
-				}
-				mu.Unlock()
-				return nil
-			})
+	// all collectors are run on every node in a single pass
+	startedAt := time.Now()
+	nodeResults, err := collect.RemoteHostCollectNodes(ctx, collect.RemoteCollectParams{
+		ProgressChan: opts.ProgressChan,
+		ClientConfig: opts.KubernetesRestConfig,
+		PullPolicy:   string(corev1.PullIfNotPresent),
+		NamePrefix:   "remote-host-collector",
+		Title:        "Remote Host Collectors",
+	}, collectSpecs)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		observeCollector(remoteHostCollectorsKind, startedAt, nil, bundlePath, err)
+		for _, title := range titles {
+			msg := fmt.Sprintf("[%s] Error: %v", title, err)
+			opts.CollectorProgressCallback(opts.ProgressChan, msg)
 		}
+		return nil, errors.Wrap(err, "failed to run remote host collectors")
+	}
 
-		err = eg.Wait()
-		if err != nil {
-			return nil, err
-		}
-		span.End()
+	// Send progress event: completed successfully
+	for _, title := range titles {
+		msg := fmt.Sprintf("[%s] Completed host collector", title)
+		opts.CollectorProgressCallback(opts.ProgressChan, msg)
 	}
 
 	klog.V(2).Infof("All remote host collectors completed")
 
-	defer func() {
-		// TODO:
-		// delete the config map
-		// delete the remote pods
-		// check if the daemonset still exists
-		if ds == nil || ds.Name == "" {
-			return
-		}
-
-		if err := clientset.AppsV1().DaemonSets(ds.Namespace).Delete(ctx, ds.Name, metav1.DeleteOptions{}); err != nil {
-			if kuberneteserrors.IsNotFound(err) {
-				klog.Errorf("Remote host collector daemonset %s not found", ds.Name)
-			} else {
-				klog.Errorf("Failed to delete remote host collector daemonset %s: %v", ds.Name, err)
-			}
-			return
-		}
-	}()
-
-	for node, logs := range nodeLogs {
-		for file, data := range logs {
+	for node, files := range nodeResults {
+		for file, data := range files {
 			// trim host-collectors/ prefix
 			file = strings.TrimPrefix(file, "host-collectors/")
 			err := output.SaveResult(bundlePath, fmt.Sprintf("host-collectors/%s/%s", node, file), bytes.NewBuffer(data))
@@ -466,150 +537,13 @@ func runRemoteHostCollectors(ctx context.Context, hostCollectors []*troubleshoot
 		}
 	}
 
-	return output, nil
-}
-
-func createHostCollectorsSpec(hostCollectors []*troubleshootv1beta2.HostCollect) *troubleshootv1beta2.HostCollector {
-	return &troubleshootv1beta2.HostCollector{
-		TypeMeta: metav1.TypeMeta{
-			APIVersion: "troubleshoot.sh/v1beta2",
-			Kind:       "HostCollector",
-		},
-		ObjectMeta: metav1.ObjectMeta{
-			Name: "remoteHostCollector",
-		},
-		Spec: troubleshootv1beta2.HostCollectorSpec{
-			Collectors: hostCollectors,
-		},
-	}
-}
-
-func createHostCollectorDS(ctx context.Context, clientset kubernetes.Interface, labels map[string]string, ns string) (*appsv1.DaemonSet, error) {
-	dsName := names.SimpleNameGenerator.GenerateName("remote-host-collector" + "-")
-	imageName := "replicated/troubleshoot:latest"
-	imagePullPolicy := corev1.PullIfNotPresent
-
-	labels[selectorLabelKey] = dsName
-
-	podSpec := corev1.PodSpec{
-		HostNetwork: true,
-		HostPID:     true,
-		HostIPC:     true,
-		Containers: []corev1.Container{
-			{
-				Image:           imageName,
-				ImagePullPolicy: imagePullPolicy,
-				Name:            "remote-collector",
-				Command:         []string{"tail", "-f", "/dev/null"},
-				SecurityContext: &corev1.SecurityContext{
-					Privileged: ptr.To(true),
-				},
-				VolumeMounts: []corev1.VolumeMount{
-					{
-						Name:      "host-root",
-						MountPath: "/host",
-					},
-				},
-			},
-		},
-		Volumes: []corev1.Volume{
-			{
-				Name: "host-root",
-				VolumeSource: corev1.VolumeSource{
-					HostPath: &corev1.HostPathVolumeSource{
-						Path: "/",
-					},
-				},
-			},
-		},
-	}
-
-	ds := &appsv1.DaemonSet{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      dsName,
-			Namespace: ns,
-			Labels:    labels,
-		},
-		Spec: appsv1.DaemonSetSpec{
-			Selector: &metav1.LabelSelector{
-				MatchLabels: labels,
-				MatchExpressions: []metav1.LabelSelectorRequirement{
-					{
-						Key:      selectorLabelKey,
-						Operator: "In",
-						Values:   []string{dsName},
-					},
-				},
-			},
-			Template: v1.PodTemplateSpec{
-				ObjectMeta: metav1.ObjectMeta{
-					Labels: labels,
-				},
-				Spec: podSpec,
-			},
-		},
-	}
-
-	createdDS, err := clientset.AppsV1().DaemonSets(ns).Create(ctx, ds, metav1.CreateOptions{})
-
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to create Remote Host Collector Pod")
-	}
-
-	return createdDS, nil
-}
-
-func waitForPodRunning(ctx context.Context, clientset kubernetes.Interface, pod *corev1.Pod) error {
-	timeoutCh := time.After(defaultTimeout * time.Second)
-	ticker := time.NewTicker(100 * time.Millisecond)
-	defer ticker.Stop()
-
-	for {
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		case <-timeoutCh:
-			return fmt.Errorf("timed out waiting for pod %s to be running", pod.Name)
-		case <-ticker.C:
-			currentPod, err := clientset.CoreV1().Pods(pod.Namespace).Get(ctx, pod.Name, metav1.GetOptions{})
-			if err != nil {
-				return fmt.Errorf("failed to get pod %s: %w", pod.Name, err)
-			}
-
-			// Check container status
-			for _, containerStatus := range currentPod.Status.ContainerStatuses {
-				if containerStatus.Name == "remote-collector" {
-					if containerStatus.State.Running != nil && containerStatus.State.Terminated == nil && containerStatus.Ready {
-						return nil
-					}
-				}
-			}
-		}
+	if opts.bundleIndex != nil {
+		// files of the remote host collectors can't be told apart, they are recorded together
+		opts.bundleIndex.AddFiles(remoteHostCollectorsKind, true, output, startedAt, nil)
 	}
-}
-
-func waitForDS(ctx context.Context, clientset kubernetes.Interface, ds *appsv1.DaemonSet) error {
-	timeoutCh := time.After(defaultTimeout * time.Second)
-	ticker := time.NewTicker(100 * time.Millisecond)
-	defer ticker.Stop()
+	observeCollector(remoteHostCollectorsKind, startedAt, output, bundlePath, nil)
 
-	for {
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		case <-timeoutCh:
-			return fmt.Errorf("timed out waiting for DaemonSet %s to be ready", ds.Name)
-		case <-ticker.C:
-			currentDS, err := clientset.AppsV1().DaemonSets(ds.Namespace).Get(ctx, ds.Name, metav1.GetOptions{})
-			if err != nil {
-				return fmt.Errorf("failed to get DaemonSet %s: %w", ds.Name, err)
-			}
-
-			if currentDS.Status.NumberReady > 0 && currentDS.Status.DesiredNumberScheduled == currentDS.Status.NumberReady {
-				return nil
-			}
-		}
-	}
+	return output, nil
 }
 
 func saveNodeList(result collect.CollectorResult, opts SupportBundleCreateOpts, bundlePath string) error {
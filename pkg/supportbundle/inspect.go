@@ -0,0 +1,438 @@
+package supportbundle
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/replicatedhq/troubleshoot/pkg/constants"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+)
+
+// InspectTable describes a table that can be queried in a bundle index.
+type InspectTable struct {
+	Name        string
+	Description string
+	Columns     []string
+}
+
+// InspectResult holds the rows returned by a query, with values in the order of the columns.
+type InspectResult struct {
+	Columns []string
+	Rows    [][]string
+}
+
+// BundleIndex indexes the cluster resources and pod logs of a support bundle into tables that
+// can be queried without knowing where they are stored in the bundle. See Query for the query
+// language.
+type BundleIndex struct {
+	tables map[string]*inspectTable
+	names  []string
+}
+
+type inspectRow map[string]string
+
+type inspectTable struct {
+	InspectTable
+	rows []inspectRow
+	// scan, when set, produces the rows on demand instead of rows. Rows that prefilter rejects
+	// may be skipped, and scanning stops when yield returns false.
+	scan func(prefilter func(inspectRow) bool, yield func(inspectRow) bool)
+}
+
+var (
+	podsFilesPattern        = path.Join(constants.CLUSTER_RESOURCES_DIR, constants.CLUSTER_RESOURCES_PODS, "*.json")
+	replicaSetsFilesPattern = path.Join(constants.CLUSTER_RESOURCES_DIR, constants.CLUSTER_RESOURCES_REPLICASETS, "*.json")
+	deploymentsFilesPattern = path.Join(constants.CLUSTER_RESOURCES_DIR, constants.CLUSTER_RESOURCES_DEPLOYMENTS, "*.json")
+	eventsFilesPattern      = path.Join(constants.CLUSTER_RESOURCES_DIR, constants.CLUSTER_RESOURCES_EVENTS, "*.json")
+	nodesFile               = path.Join(constants.CLUSTER_RESOURCES_DIR, constants.CLUSTER_RESOURCES_NODES+".json")
+	logsFilesPattern        = path.Join(constants.CLUSTER_RESOURCES_DIR, constants.CLUSTER_RESOURCES_PODS_LOGS, "*", "*", "*.log")
+)
+
+// NewBundleIndex indexes the files of the bundle. Files that cannot be decoded are skipped.
+func NewBundleIndex(browser *BundleBrowser) *BundleIndex {
+	i := &BundleIndex{
+		tables: map[string]*inspectTable{},
+	}
+
+	pods := decodeBundleItems[corev1.Pod](browser, podsFilesPattern)
+	deploymentsByReplicaSet := map[string]string{}
+	for _, rs := range decodeBundleItems[appsv1.ReplicaSet](browser, replicaSetsFilesPattern) {
+		if owner := metav1.GetControllerOf(&rs); owner != nil && owner.Kind == "Deployment" {
+			deploymentsByReplicaSet[rs.Namespace+"/"+rs.Name] = owner.Name
+		}
+	}
+	deploymentsByPod := map[string]string{}
+	for _, pod := range pods {
+		if owner := metav1.GetControllerOf(&pod); owner != nil && owner.Kind == "ReplicaSet" {
+			deploymentsByPod[pod.Namespace+"/"+pod.Name] = deploymentsByReplicaSet[pod.Namespace+"/"+owner.Name]
+		}
+	}
+
+	i.addTable(podsTable(pods, deploymentsByPod))
+	i.addTable(containersTable(pods, deploymentsByPod))
+	i.addTable(imagesTable(pods))
+	i.addTable(deploymentsTable(decodeBundleItems[appsv1.Deployment](browser, deploymentsFilesPattern)))
+	i.addTable(nodesTable(decodeBundleItems[corev1.Node](browser, nodesFile)))
+	i.addTable(eventsTable(decodeBundleItems[corev1.Event](browser, eventsFilesPattern)))
+	i.addTable(logsTable(browser, deploymentsByPod))
+
+	return i
+}
+
+func (i *BundleIndex) addTable(table *inspectTable) {
+	i.tables[table.Name] = table
+	i.names = append(i.names, table.Name)
+}
+
+// Tables returns the tables of the index.
+func (i *BundleIndex) Tables() []InspectTable {
+	tables := make([]InspectTable, 0, len(i.names))
+	for _, name := range i.names {
+		tables = append(tables, i.tables[name].InspectTable)
+	}
+	return tables
+}
+
+func (t *inspectTable) each(prefilter func(inspectRow) bool, yield func(inspectRow) bool) {
+	if t.scan != nil {
+		t.scan(prefilter, yield)
+		return
+	}
+	for _, row := range t.rows {
+		if !yield(row) {
+			return
+		}
+	}
+}
+
+// decodeBundleItems decodes the objects in the files matching the pattern, which are either
+// lists or arrays of objects depending on the version of troubleshoot that collected them.
+// bundleFiles lists and reads the files of a bundle, such as a BundleBrowser or a
+// PostCollectionBundle
+type bundleFiles interface {
+	Files() []string
+	ReadFile(name string) ([]byte, error)
+}
+
+func decodeBundleItems[T any](browser bundleFiles, pattern string) []T {
+	items := []T{}
+	for _, name := range browser.Files() {
+		if ok, _ := path.Match(pattern, name); !ok {
+			continue
+		}
+		content, err := browser.ReadFile(name)
+		if err != nil {
+			continue
+		}
+
+		var list struct {
+			Items []T `json:"items"`
+		}
+		if err := json.Unmarshal(content, &list); err == nil {
+			items = append(items, list.Items...)
+			continue
+		}
+		var arr []T
+		if err := json.Unmarshal(content, &arr); err != nil {
+			klog.V(2).Infof("Skipping %s, failed to decode it: %v", name, err)
+			continue
+		}
+		items = append(items, arr...)
+	}
+	return items
+}
+
+func podsTable(pods []corev1.Pod, deploymentsByPod map[string]string) *inspectTable {
+	table := &inspectTable{
+		InspectTable: InspectTable{
+			Name:        "pods",
+			Description: "pods and their status",
+			Columns:     []string{"namespace", "name", "phase", "ready", "restarts", "node", "owner", "deployment"},
+		},
+	}
+
+	for _, pod := range pods {
+		owner := ""
+		if ref := metav1.GetControllerOf(&pod); ref != nil {
+			owner = ref.Kind + "/" + ref.Name
+		}
+		restarts := int32(0)
+		for _, status := range pod.Status.ContainerStatuses {
+			restarts += status.RestartCount
+		}
+		table.rows = append(table.rows, inspectRow{
+			"namespace":  pod.Namespace,
+			"name":       pod.Name,
+			"phase":      string(pod.Status.Phase),
+			"ready":      strconv.FormatBool(isPodReady(pod)),
+			"restarts":   strconv.Itoa(int(restarts)),
+			"node":       pod.Spec.NodeName,
+			"owner":      owner,
+			"deployment": deploymentsByPod[pod.Namespace+"/"+pod.Name],
+		})
+	}
+	return table
+}
+
+func isPodReady(pod corev1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+func containersTable(pods []corev1.Pod, deploymentsByPod map[string]string) *inspectTable {
+	table := &inspectTable{
+		InspectTable: InspectTable{
+			Name:        "containers",
+			Description: "containers of pods, their images and status",
+			Columns:     []string{"namespace", "pod", "deployment", "name", "image", "init", "ready", "restarts", "state"},
+		},
+	}
+
+	for _, pod := range pods {
+		statuses := map[string]corev1.ContainerStatus{}
+		for _, status := range pod.Status.InitContainerStatuses {
+			statuses[status.Name] = status
+		}
+		for _, status := range pod.Status.ContainerStatuses {
+			statuses[status.Name] = status
+		}
+
+		addContainers := func(containers []corev1.Container, init bool) {
+			for _, container := range containers {
+				status := statuses[container.Name]
+				table.rows = append(table.rows, inspectRow{
+					"namespace":  pod.Namespace,
+					"pod":        pod.Name,
+					"deployment": deploymentsByPod[pod.Namespace+"/"+pod.Name],
+					"name":       container.Name,
+					"image":      container.Image,
+					"init":       strconv.FormatBool(init),
+					"ready":      strconv.FormatBool(status.Ready),
+					"restarts":   strconv.Itoa(int(status.RestartCount)),
+					"state":      containerState(status.State),
+				})
+			}
+		}
+		addContainers(pod.Spec.InitContainers, true)
+		addContainers(pod.Spec.Containers, false)
+	}
+	return table
+}
+
+// containerState returns the state of a container, with the reason when it is not running.
+func containerState(state corev1.ContainerState) string {
+	switch {
+	case state.Running != nil:
+		return "running"
+	case state.Waiting != nil:
+		return strings.TrimSuffix("waiting:"+state.Waiting.Reason, ":")
+	case state.Terminated != nil:
+		return strings.TrimSuffix("terminated:"+state.Terminated.Reason, ":")
+	default:
+		return ""
+	}
+}
+
+func imagesTable(pods []corev1.Pod) *inspectTable {
+	table := &inspectTable{
+		InspectTable: InspectTable{
+			Name:        "images",
+			Description: "images used in each namespace and the number of pods using them",
+			Columns:     []string{"namespace", "image", "pods"},
+		},
+	}
+
+	type key struct{ namespace, image string }
+	podsByImage := map[key]map[string]bool{}
+	for _, pod := range pods {
+		containers := []corev1.Container{}
+		containers = append(containers, pod.Spec.InitContainers...)
+		containers = append(containers, pod.Spec.Containers...)
+		for _, container := range containers {
+			k := key{pod.Namespace, container.Image}
+			if podsByImage[k] == nil {
+				podsByImage[k] = map[string]bool{}
+			}
+			podsByImage[k][pod.Name] = true
+		}
+	}
+
+	keys := make([]key, 0, len(podsByImage))
+	for k := range podsByImage {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(a, b int) bool {
+		if keys[a].namespace != keys[b].namespace {
+			return keys[a].namespace < keys[b].namespace
+		}
+		return keys[a].image < keys[b].image
+	})
+	for _, k := range keys {
+		table.rows = append(table.rows, inspectRow{
+			"namespace": k.namespace,
+			"image":     k.image,
+			"pods":      strconv.Itoa(len(podsByImage[k])),
+		})
+	}
+	return table
+}
+
+func deploymentsTable(deployments []appsv1.Deployment) *inspectTable {
+	table := &inspectTable{
+		InspectTable: InspectTable{
+			Name:        "deployments",
+			Description: "deployments and their replicas",
+			Columns:     []string{"namespace", "name", "replicas", "ready", "available", "updated"},
+		},
+	}
+
+	for _, deployment := range deployments {
+		replicas := int32(1)
+		if deployment.Spec.Replicas != nil {
+			replicas = *deployment.Spec.Replicas
+		}
+		table.rows = append(table.rows, inspectRow{
+			"namespace": deployment.Namespace,
+			"name":      deployment.Name,
+			"replicas":  strconv.Itoa(int(replicas)),
+			"ready":     strconv.Itoa(int(deployment.Status.ReadyReplicas)),
+			"available": strconv.Itoa(int(deployment.Status.AvailableReplicas)),
+			"updated":   strconv.Itoa(int(deployment.Status.UpdatedReplicas)),
+		})
+	}
+	return table
+}
+
+func nodesTable(nodes []corev1.Node) *inspectTable {
+	table := &inspectTable{
+		InspectTable: InspectTable{
+			Name:        "nodes",
+			Description: "nodes and their status",
+			Columns:     []string{"name", "ready", "roles", "version", "os", "arch", "unschedulable"},
+		},
+	}
+
+	for _, node := range nodes {
+		ready := false
+		for _, condition := range node.Status.Conditions {
+			if condition.Type == corev1.NodeReady {
+				ready = condition.Status == corev1.ConditionTrue
+			}
+		}
+		roles := []string{}
+		for label := range node.Labels {
+			if role, ok := strings.CutPrefix(label, "node-role.kubernetes.io/"); ok && role != "" {
+				roles = append(roles, role)
+			}
+		}
+		sort.Strings(roles)
+		table.rows = append(table.rows, inspectRow{
+			"name":          node.Name,
+			"ready":         strconv.FormatBool(ready),
+			"roles":         strings.Join(roles, ","),
+			"version":       node.Status.NodeInfo.KubeletVersion,
+			"os":            node.Status.NodeInfo.OperatingSystem,
+			"arch":          node.Status.NodeInfo.Architecture,
+			"unschedulable": strconv.FormatBool(node.Spec.Unschedulable),
+		})
+	}
+	return table
+}
+
+func eventsTable(events []corev1.Event) *inspectTable {
+	table := &inspectTable{
+		InspectTable: InspectTable{
+			Name:        "events",
+			Description: "events and the objects they are about",
+			Columns:     []string{"namespace", "object", "type", "reason", "count", "last", "message"},
+		},
+	}
+
+	for _, event := range events {
+		last := event.LastTimestamp.Time
+		if last.IsZero() {
+			last = event.EventTime.Time
+		}
+		lastSeen := ""
+		if !last.IsZero() {
+			lastSeen = last.UTC().Format(time.RFC3339)
+		}
+		table.rows = append(table.rows, inspectRow{
+			"namespace": event.Namespace,
+			"object":    event.InvolvedObject.Kind + "/" + event.InvolvedObject.Name,
+			"type":      event.Type,
+			"reason":    event.Reason,
+			"count":     strconv.Itoa(int(event.Count)),
+			"last":      lastSeen,
+			"message":   event.Message,
+		})
+	}
+	return table
+}
+
+// logsTable has a row for each line of the pod logs. Lines are only read from the files whose
+// pod and container match the prefilter.
+func logsTable(browser *BundleBrowser, deploymentsByPod map[string]string) *inspectTable {
+	table := &inspectTable{
+		InspectTable: InspectTable{
+			Name:        "logs",
+			Description: "lines of the pod logs",
+			Columns:     []string{"namespace", "pod", "container", "deployment", "previous", "file", "line", "text"},
+		},
+	}
+
+	table.scan = func(prefilter func(inspectRow) bool, yield func(inspectRow) bool) {
+		for _, name := range browser.Files() {
+			if ok, _ := path.Match(logsFilesPattern, name); !ok || strings.HasSuffix(name, "-logs-errors.log") {
+				continue
+			}
+
+			elements := strings.Split(name, "/")
+			namespace, pod := elements[len(elements)-3], elements[len(elements)-2]
+			container, previous := strings.CutSuffix(strings.TrimSuffix(elements[len(elements)-1], ".log"), "-previous")
+			fileRow := inspectRow{
+				"namespace":  namespace,
+				"pod":        pod,
+				"container":  container,
+				"deployment": deploymentsByPod[namespace+"/"+pod],
+				"previous":   strconv.FormatBool(previous),
+				"file":       name,
+			}
+			if !prefilter(fileRow) {
+				continue
+			}
+
+			content, err := browser.ReadFile(name)
+			if err != nil {
+				continue
+			}
+			scanner := bufio.NewScanner(bytes.NewReader(content))
+			scanner.Buffer(make([]byte, 0, 64*1024), maxBrowsedFileSize)
+			line := 0
+			for scanner.Scan() {
+				line++
+				row := inspectRow{"line": strconv.Itoa(line), "text": scanner.Text()}
+				for column, value := range fileRow {
+					row[column] = value
+				}
+				if !yield(row) {
+					return
+				}
+			}
+		}
+	}
+	return table
+}
@@ -0,0 +1,355 @@
+package supportbundle
+
+import (
+	"cmp"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+type inspectQuery struct {
+	table      *inspectTable
+	conditions []queryCondition
+	columns    []string
+	sortColumn string
+	sortDesc   bool
+	limit      int
+}
+
+type queryCondition struct {
+	column string
+	op     string
+	value  string
+	re     *regexp.Regexp
+}
+
+type queryToken struct {
+	value  string
+	quoted bool
+}
+
+// Query runs a query against the index. Queries have the form
+//
+//	<table> [where <column> <op> <value> [and ...]] [select <column>[,<column>...]] [sort <column> [desc]] [limit <n>]
+//
+// where op is one of = != ~ !~ < <= > >=. ~ and !~ match regular expressions, and the ordering
+// operators compare numbers when both sides are numbers. Values with spaces or operator
+// characters are quoted with double or single quotes. For example:
+//
+//	pods where ready = false
+//	logs where deployment = api and text ~ (?i)error
+//	images select namespace,image sort namespace
+func (i *BundleIndex) Query(query string) (*InspectResult, error) {
+	q, err := i.parseQuery(query)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := []inspectRow{}
+	prefilter := func(row inspectRow) bool {
+		return q.matches(row, true)
+	}
+	q.table.each(prefilter, func(row inspectRow) bool {
+		if q.matches(row, false) {
+			rows = append(rows, row)
+		}
+		return q.sortColumn != "" || q.limit == 0 || len(rows) < q.limit
+	})
+
+	if q.sortColumn != "" {
+		sort.SliceStable(rows, func(a, b int) bool {
+			c := compareValues(rows[a][q.sortColumn], rows[b][q.sortColumn])
+			if q.sortDesc {
+				return c > 0
+			}
+			return c < 0
+		})
+	}
+	if q.limit > 0 && len(rows) > q.limit {
+		rows = rows[:q.limit]
+	}
+
+	columns := q.columns
+	if len(columns) == 0 {
+		columns = q.table.Columns
+	}
+	result := &InspectResult{
+		Columns: columns,
+		Rows:    make([][]string, 0, len(rows)),
+	}
+	for _, row := range rows {
+		values := make([]string, 0, len(columns))
+		for _, column := range columns {
+			values = append(values, row[column])
+		}
+		result.Rows = append(result.Rows, values)
+	}
+	return result, nil
+}
+
+// matches returns whether the row meets all of the conditions. Partial rows only have some of
+// the columns, and conditions on the other columns are ignored.
+func (q *inspectQuery) matches(row inspectRow, partial bool) bool {
+	for _, condition := range q.conditions {
+		value, ok := row[condition.column]
+		if !ok && partial {
+			continue
+		}
+		if !condition.matches(value) {
+			return false
+		}
+	}
+	return true
+}
+
+func (c queryCondition) matches(value string) bool {
+	switch c.op {
+	case "=":
+		return value == c.value
+	case "!=":
+		return value != c.value
+	case "~":
+		return c.re.MatchString(value)
+	case "!~":
+		return !c.re.MatchString(value)
+	case "<":
+		return compareValues(value, c.value) < 0
+	case "<=":
+		return compareValues(value, c.value) <= 0
+	case ">":
+		return compareValues(value, c.value) > 0
+	case ">=":
+		return compareValues(value, c.value) >= 0
+	}
+	return false
+}
+
+// compareValues compares numbers numerically and anything else lexically.
+func compareValues(a string, b string) int {
+	fa, errA := strconv.ParseFloat(a, 64)
+	fb, errB := strconv.ParseFloat(b, 64)
+	if errA == nil && errB == nil {
+		return cmp.Compare(fa, fb)
+	}
+	return strings.Compare(a, b)
+}
+
+func (i *BundleIndex) parseQuery(query string) (*inspectQuery, error) {
+	tokens, err := tokenizeQuery(query)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, errors.New("query is empty")
+	}
+
+	table, ok := i.tables[tokens[0].value]
+	if !ok || tokens[0].quoted {
+		return nil, errors.Errorf("unknown table %q, tables are %s", tokens[0].value, strings.Join(i.names, ", "))
+	}
+	q := &inspectQuery{table: table}
+	p := &queryParser{tokens: tokens[1:], query: q}
+
+	seen := map[string]bool{}
+	for !p.done() {
+		keyword := p.next()
+		clause := strings.ToLower(keyword.value)
+		if keyword.quoted || seen[clause] {
+			return nil, errors.Errorf("unexpected %q", keyword.value)
+		}
+		seen[clause] = true
+
+		switch clause {
+		case "where":
+			err = p.parseWhere()
+		case "select":
+			err = p.parseSelect()
+		case "sort":
+			err = p.parseSort()
+		case "limit":
+			err = p.parseLimit()
+		default:
+			err = errors.Errorf("unexpected %q, expected where, select, sort or limit", keyword.value)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return q, nil
+}
+
+type queryParser struct {
+	tokens []queryToken
+	pos    int
+	query  *inspectQuery
+}
+
+func (p *queryParser) done() bool {
+	return p.pos >= len(p.tokens)
+}
+
+func (p *queryParser) next() queryToken {
+	if p.done() {
+		return queryToken{}
+	}
+	token := p.tokens[p.pos]
+	p.pos++
+	return token
+}
+
+// accept consumes the next token if it is the unquoted keyword.
+func (p *queryParser) accept(keyword string) bool {
+	if p.done() || p.tokens[p.pos].quoted || !strings.EqualFold(p.tokens[p.pos].value, keyword) {
+		return false
+	}
+	p.pos++
+	return true
+}
+
+func (p *queryParser) column() (string, error) {
+	if p.done() {
+		return "", errors.New("expected a column at the end of the query")
+	}
+	name := p.next().value
+	for _, column := range p.query.table.Columns {
+		if column == name {
+			return name, nil
+		}
+	}
+	return "", errors.Errorf("unknown column %q, columns of %s are %s", name, p.query.table.Name, strings.Join(p.query.table.Columns, ", "))
+}
+
+func (p *queryParser) parseWhere() error {
+	for {
+		column, err := p.column()
+		if err != nil {
+			return err
+		}
+		op := p.next()
+		if op.quoted || !isQueryOperator(op.value) {
+			return errors.Errorf("expected an operator after %s, got %q", column, op.value)
+		}
+		if p.done() {
+			return errors.Errorf("expected a value after %s %s", column, op.value)
+		}
+		condition := queryCondition{
+			column: column,
+			op:     op.value,
+			value:  p.next().value,
+		}
+		if condition.op == "~" || condition.op == "!~" {
+			condition.re, err = regexp.Compile(condition.value)
+			if err != nil {
+				return errors.Wrapf(err, "invalid expression %q", condition.value)
+			}
+		}
+		p.query.conditions = append(p.query.conditions, condition)
+
+		if !p.accept("and") {
+			return nil
+		}
+	}
+}
+
+func (p *queryParser) parseSelect() error {
+	for {
+		column, err := p.column()
+		if err != nil {
+			return err
+		}
+		p.query.columns = append(p.query.columns, column)
+
+		if !p.accept(",") {
+			return nil
+		}
+	}
+}
+
+func (p *queryParser) parseSort() error {
+	column, err := p.column()
+	if err != nil {
+		return err
+	}
+	p.query.sortColumn = column
+	if p.accept("desc") {
+		p.query.sortDesc = true
+	} else {
+		p.accept("asc")
+	}
+	return nil
+}
+
+func (p *queryParser) parseLimit() error {
+	value := p.next().value
+	limit, err := strconv.Atoi(value)
+	if err != nil || limit <= 0 {
+		return errors.Errorf("limit must be a positive number, got %q", value)
+	}
+	p.query.limit = limit
+	return nil
+}
+
+var queryOperators = []string{"!=", "!~", "<=", ">=", "=", "~", "<", ">"}
+
+func isQueryOperator(value string) bool {
+	for _, op := range queryOperators {
+		if value == op {
+			return true
+		}
+	}
+	return false
+}
+
+// tokenizeQuery splits a query into words, quoted strings, operators and commas.
+func tokenizeQuery(query string) ([]queryToken, error) {
+	tokens := []queryToken{}
+	for i := 0; i < len(query); {
+		c := query[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n':
+			i++
+		case c == '"' || c == '\'':
+			value := strings.Builder{}
+			j := i + 1
+			for ; j < len(query) && query[j] != c; j++ {
+				if query[j] == '\\' && j+1 < len(query) && (query[j+1] == c || query[j+1] == '\\') {
+					j++
+				}
+				value.WriteByte(query[j])
+			}
+			if j >= len(query) {
+				return nil, errors.Errorf("unterminated quoted string at %d", i)
+			}
+			tokens = append(tokens, queryToken{value: value.String(), quoted: true})
+			i = j + 1
+		case c == ',':
+			tokens = append(tokens, queryToken{value: ","})
+			i++
+		case strings.IndexByte("=!~<>", c) >= 0:
+			op := ""
+			for _, candidate := range queryOperators {
+				if strings.HasPrefix(query[i:], candidate) {
+					op = candidate
+					break
+				}
+			}
+			if op == "" {
+				return nil, errors.Errorf("invalid operator at %d", i)
+			}
+			tokens = append(tokens, queryToken{value: op})
+			i += len(op)
+		default:
+			j := i
+			for j < len(query) && strings.IndexByte(" \t\n,=!~<>\"'", query[j]) < 0 {
+				j++
+			}
+			tokens = append(tokens, queryToken{value: query[i:j]})
+			i = j
+		}
+	}
+	return tokens, nil
+}
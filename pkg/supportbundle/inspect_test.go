@@ -0,0 +1,180 @@
+package supportbundle
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testBundleIndex(t *testing.T) *BundleIndex {
+	t.Helper()
+
+	archivePath := writeTestBundle(t, map[string]string{
+		"support-bundle/cluster-resources/pods/default.json": `{"kind": "PodList", "items": [
+			{
+				"metadata": {"name": "api-7d9f-abcde", "namespace": "default", "ownerReferences": [{"kind": "ReplicaSet", "name": "api-7d9f", "controller": true}]},
+				"spec": {"nodeName": "node-1", "containers": [{"name": "api", "image": "example/api:1.0"}, {"name": "proxy", "image": "envoy:1.29"}]},
+				"status": {
+					"phase": "Running",
+					"conditions": [{"type": "Ready", "status": "False"}],
+					"containerStatuses": [
+						{"name": "api", "ready": false, "restartCount": 3, "state": {"waiting": {"reason": "CrashLoopBackOff"}}},
+						{"name": "proxy", "ready": true, "restartCount": 0, "state": {"running": {}}}
+					]
+				}
+			},
+			{
+				"metadata": {"name": "db-0", "namespace": "default"},
+				"spec": {"nodeName": "node-1", "containers": [{"name": "db", "image": "postgres:16"}]},
+				"status": {"phase": "Running", "conditions": [{"type": "Ready", "status": "True"}], "containerStatuses": [{"name": "db", "ready": true, "state": {"running": {}}}]}
+			}
+		]}`,
+		"support-bundle/cluster-resources/pods/kube-system.json": `[
+			{"metadata": {"name": "proxy-xyz", "namespace": "kube-system"}, "spec": {"containers": [{"name": "proxy", "image": "envoy:1.29"}]}, "status": {"phase": "Pending"}}
+		]`,
+		"support-bundle/cluster-resources/pods-errors.json": `["forbidden"]`,
+		"support-bundle/cluster-resources/replicasets/default.json": `{"items": [
+			{"metadata": {"name": "api-7d9f", "namespace": "default", "ownerReferences": [{"kind": "Deployment", "name": "api", "controller": true}]}}
+		]}`,
+		"support-bundle/cluster-resources/deployments/default.json": `{"items": [
+			{"metadata": {"name": "api", "namespace": "default"}, "spec": {"replicas": 2}, "status": {"readyReplicas": 1, "availableReplicas": 1, "updatedReplicas": 2}}
+		]}`,
+		"support-bundle/cluster-resources/nodes.json": `{"items": [
+			{"metadata": {"name": "node-1", "labels": {"node-role.kubernetes.io/control-plane": ""}}, "status": {"conditions": [{"type": "Ready", "status": "True"}], "nodeInfo": {"kubeletVersion": "v1.30.1", "operatingSystem": "linux", "architecture": "amd64"}}}
+		]}`,
+		"support-bundle/cluster-resources/events/default.json": `{"items": [
+			{"metadata": {"name": "api.1", "namespace": "default"}, "involvedObject": {"kind": "Pod", "name": "api-7d9f-abcde"}, "type": "Warning", "reason": "BackOff", "count": 12, "lastTimestamp": "2024-01-01T00:00:00Z", "message": "Back-off restarting failed container"}
+		]}`,
+		"support-bundle/cluster-resources/pods/logs/default/api-7d9f-abcde/api.log":          "starting\nERROR: connection refused\nretrying\n",
+		"support-bundle/cluster-resources/pods/logs/default/api-7d9f-abcde/api-previous.log": "starting\nerror: timeout\n",
+		"support-bundle/cluster-resources/pods/logs/default/db-0/db.log":                     "ERROR: relation does not exist\n",
+	})
+
+	browser, err := NewBundleBrowser(archivePath)
+	require.NoError(t, err)
+	return NewBundleIndex(browser)
+}
+
+func TestBundleIndex_Query(t *testing.T) {
+	index := testBundleIndex(t)
+
+	tests := []struct {
+		name  string
+		query string
+		want  *InspectResult
+	}{
+		{
+			name:  "pods not ready",
+			query: "pods where ready = false select namespace,name,phase,restarts,deployment",
+			want: &InspectResult{
+				Columns: []string{"namespace", "name", "phase", "restarts", "deployment"},
+				Rows: [][]string{
+					{"default", "api-7d9f-abcde", "Running", "3", "api"},
+					{"kube-system", "proxy-xyz", "Pending", "0", ""},
+				},
+			},
+		},
+		{
+			name:  "errors in the logs of a deployment",
+			query: `logs where deployment = api and text ~ "(?i)error"`,
+			want: &InspectResult{
+				Columns: []string{"namespace", "pod", "container", "deployment", "previous", "file", "line", "text"},
+				Rows: [][]string{
+					{"default", "api-7d9f-abcde", "api", "api", "true", "cluster-resources/pods/logs/default/api-7d9f-abcde/api-previous.log", "2", "error: timeout"},
+					{"default", "api-7d9f-abcde", "api", "api", "false", "cluster-resources/pods/logs/default/api-7d9f-abcde/api.log", "2", "ERROR: connection refused"},
+				},
+			},
+		},
+		{
+			name:  "images by namespace",
+			query: "images sort namespace desc",
+			want: &InspectResult{
+				Columns: []string{"namespace", "image", "pods"},
+				Rows: [][]string{
+					{"kube-system", "envoy:1.29", "1"},
+					{"default", "envoy:1.29", "1"},
+					{"default", "example/api:1.0", "1"},
+					{"default", "postgres:16", "1"},
+				},
+			},
+		},
+		{
+			name:  "numeric comparison and limit",
+			query: "containers where restarts >= 2 and state !~ ^running select pod,name,state limit 1",
+			want: &InspectResult{
+				Columns: []string{"pod", "name", "state"},
+				Rows:    [][]string{{"api-7d9f-abcde", "api", "waiting:CrashLoopBackOff"}},
+			},
+		},
+		{
+			name:  "deployments",
+			query: "deployments where available < 2",
+			want: &InspectResult{
+				Columns: []string{"namespace", "name", "replicas", "ready", "available", "updated"},
+				Rows:    [][]string{{"default", "api", "2", "1", "1", "2"}},
+			},
+		},
+		{
+			name:  "nodes",
+			query: "nodes select name,ready,roles,version",
+			want: &InspectResult{
+				Columns: []string{"name", "ready", "roles", "version"},
+				Rows:    [][]string{{"node-1", "true", "control-plane", "v1.30.1"}},
+			},
+		},
+		{
+			name:  "events",
+			query: "events where type = Warning select object,reason,count,last,message",
+			want: &InspectResult{
+				Columns: []string{"object", "reason", "count", "last", "message"},
+				Rows:    [][]string{{"Pod/api-7d9f-abcde", "BackOff", "12", "2024-01-01T00:00:00Z", "Back-off restarting failed container"}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := index.Query(tt.query)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestBundleIndex_QueryErrors(t *testing.T) {
+	index := testBundleIndex(t)
+
+	tests := []struct {
+		query   string
+		wantErr string
+	}{
+		{query: "", wantErr: "query is empty"},
+		{query: "secrets", wantErr: `unknown table "secrets"`},
+		{query: "pods where color = red", wantErr: `unknown column "color"`},
+		{query: "pods where name", wantErr: "expected an operator after name"},
+		{query: "pods where name =", wantErr: "expected a value after name ="},
+		{query: "pods where name ~ (", wantErr: "invalid expression"},
+		{query: `pods where name = "api`, wantErr: "unterminated quoted string"},
+		{query: "pods limit 0", wantErr: "limit must be a positive number"},
+		{query: "pods limit 1 limit 2", wantErr: `unexpected "limit"`},
+		{query: "pods group by name", wantErr: `unexpected "group"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			_, err := index.Query(tt.query)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestBundleIndex_Tables(t *testing.T) {
+	index := testBundleIndex(t)
+
+	names := []string{}
+	for _, table := range index.Tables() {
+		names = append(names, table.Name)
+		assert.NotEmpty(t, table.Columns)
+	}
+	assert.Equal(t, []string{"pods", "containers", "images", "deployments", "nodes", "events", "logs"}, names)
+}
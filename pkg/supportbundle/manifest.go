@@ -0,0 +1,390 @@
+package supportbundle
+
+import (
+	"archive/tar"
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/collect"
+	"github.com/replicatedhq/troubleshoot/pkg/constants"
+	"github.com/replicatedhq/troubleshoot/pkg/version"
+)
+
+// BundleManifest records where a support bundle comes from and the checksum of each of its files,
+// so that recipients can check that the bundle they received is the one that was collected. It
+// is the last file written to the bundle, and is signed when the bundle is collected with
+// SupportBundleCreateOpts.SigningKey.
+type BundleManifest struct {
+	// ToolVersion and GitSHA identify the build of troubleshoot that collected the bundle
+	ToolVersion string `json:"toolVersion"`
+	GitSHA      string `json:"gitSHA,omitempty"`
+	// SpecSHA256 is the checksum of the spec the bundle was collected with, see SpecChecksum
+	SpecSHA256  string              `json:"specSha256"`
+	StartedAt   time.Time           `json:"startedAt"`
+	CompletedAt time.Time           `json:"completedAt"`
+	Collectors  []ManifestCollector `json:"collectors"`
+	Files       []ManifestFile      `json:"files"`
+}
+
+// ManifestCollector is a collector that produced files of the bundle. The collectors of specs are
+// part of troubleshoot and have its version, custom collectors have the version they return from
+// a Version() string method, if they have one.
+type ManifestCollector struct {
+	Collector string `json:"collector"`
+	Name      string `json:"name,omitempty"`
+	Host      bool   `json:"host,omitempty"`
+	Version   string `json:"version,omitempty"`
+}
+
+// ManifestFile is a file of the bundle. Symlinks have the path they point to, relative to their
+// directory as in the archive, instead of a size and checksum.
+type ManifestFile struct {
+	Path   string `json:"path"`
+	Size   int64  `json:"size,omitempty"`
+	SHA256 string `json:"sha256,omitempty"`
+	Link   string `json:"link,omitempty"`
+}
+
+// versionedCollector is implemented by custom collectors that report their version
+type versionedCollector interface {
+	Version() string
+}
+
+// SpecChecksum returns the sha256 of the spec as json, which recipients of a bundle can compare
+// to the SpecSHA256 of its manifest
+func SpecChecksum(spec *troubleshootv1beta2.SupportBundleSpec) (string, error) {
+	b, err := json.Marshal(spec)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to marshal spec")
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// newBundleManifest lists the files of the result, which are saved in bundlePath, and the
+// collectors the index records them from
+func newBundleManifest(
+	result collect.CollectorResult, bundlePath string, index *collect.BundleIndex,
+	spec *troubleshootv1beta2.SupportBundleSpec, customCollectors []CustomCollector, startedAt time.Time,
+) (*BundleManifest, error) {
+	specSHA256, err := SpecChecksum(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	build := version.GetBuild()
+	manifest := &BundleManifest{
+		ToolVersion: build.Version,
+		GitSHA:      build.GitSHA,
+		SpecSHA256:  specSHA256,
+		StartedAt:   startedAt.UTC(),
+		Collectors:  []ManifestCollector{},
+		Files:       []ManifestFile{},
+	}
+
+	customVersions := map[string]string{}
+	for _, collector := range customCollectors {
+		if versioned, ok := collector.(versionedCollector); ok {
+			customVersions[collector.Title()] = versioned.Version()
+		}
+	}
+	seen := map[ManifestCollector]bool{}
+	for _, file := range index.Files {
+		if file.Collector == "" {
+			continue
+		}
+		collector := ManifestCollector{Collector: file.Collector, Name: file.Name, Host: file.Host, Version: build.Version}
+		if customVersion, ok := customVersions[file.Collector]; ok {
+			collector.Version = customVersion
+		}
+		if !seen[collector] {
+			seen[collector] = true
+			manifest.Collectors = append(manifest.Collectors, collector)
+		}
+	}
+
+	paths := make([]string, 0, len(result))
+	for name := range result {
+		if name != constants.MANIFEST_FILENAME && name != constants.MANIFEST_SIGNATURE_FILENAME {
+			paths = append(paths, name)
+		}
+	}
+	sort.Strings(paths)
+
+	for _, name := range paths {
+		file, ok, err := manifestFile(bundlePath, name)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to checksum %s", name)
+		}
+		if ok {
+			manifest.Files = append(manifest.Files, file)
+		}
+	}
+
+	manifest.CompletedAt = time.Now().UTC()
+	return manifest, nil
+}
+
+// manifestFile checksums a file of the bundle as it is archived. ok is false for the files the
+// archive does not have, such as directories.
+func manifestFile(bundlePath string, name string) (file ManifestFile, ok bool, err error) {
+	filename := filepath.Join(bundlePath, name)
+	info, err := os.Lstat(filename)
+	if err != nil {
+		return ManifestFile{}, false, errors.Wrap(err, "failed to stat file")
+	}
+
+	file = ManifestFile{Path: name}
+	switch {
+	case info.Mode().Type() == os.ModeSymlink:
+		target, err := os.Readlink(filename)
+		if err != nil {
+			return ManifestFile{}, false, errors.Wrap(err, "failed to read symlink")
+		}
+		if filepath.IsAbs(target) {
+			if target, err = filepath.Rel(filepath.Dir(filename), target); err != nil {
+				return ManifestFile{}, false, errors.Wrap(err, "failed to make symlink relative")
+			}
+		}
+		file.Link = target
+	case info.Mode().IsRegular():
+		f, err := os.Open(filename)
+		if err != nil {
+			return ManifestFile{}, false, errors.Wrap(err, "failed to open file")
+		}
+		defer f.Close()
+		hash := sha256.New()
+		if file.Size, err = io.Copy(hash, f); err != nil {
+			return ManifestFile{}, false, errors.Wrap(err, "failed to read file")
+		}
+		file.SHA256 = hex.EncodeToString(hash.Sum(nil))
+	default:
+		return ManifestFile{}, false, nil
+	}
+	return file, true, nil
+}
+
+// saveBundleManifest writes the manifest to the bundle, and its signature when there is a key
+func saveBundleManifest(result collect.CollectorResult, bundlePath string, manifest *BundleManifest, key ed25519.PrivateKey) error {
+	b, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal manifest")
+	}
+	if err := result.SaveResult(bundlePath, constants.MANIFEST_FILENAME, bytes.NewBuffer(b)); err != nil {
+		return errors.Wrap(err, "failed to write manifest")
+	}
+	if key == nil {
+		return nil
+	}
+
+	signature := base64.StdEncoding.EncodeToString(ed25519.Sign(key, b))
+	if err := result.SaveResult(bundlePath, constants.MANIFEST_SIGNATURE_FILENAME, strings.NewReader(signature)); err != nil {
+		return errors.Wrap(err, "failed to write manifest signature")
+	}
+	return nil
+}
+
+// ParseSigningKey reads an ed25519 private key in PEM encoded PKCS #8 form, as written by
+// openssl genpkey -algorithm ed25519
+func ParseSigningKey(b []byte) (ed25519.PrivateKey, error) {
+	block, _ := pem.Decode(b)
+	if block == nil {
+		return nil, errors.New("signing key is not PEM encoded")
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse signing key")
+	}
+	ed25519Key, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, errors.Errorf("signing key is a %T, not an ed25519 key", key)
+	}
+	return ed25519Key, nil
+}
+
+// ParseVerificationKey reads an ed25519 public key in PEM encoded PKIX form, as written by
+// openssl pkey -pubout
+func ParseVerificationKey(b []byte) (ed25519.PublicKey, error) {
+	block, _ := pem.Decode(b)
+	if block == nil {
+		return nil, errors.New("public key is not PEM encoded")
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse public key")
+	}
+	ed25519Key, ok := key.(ed25519.PublicKey)
+	if !ok {
+		return nil, errors.Errorf("public key is a %T, not an ed25519 key", key)
+	}
+	return ed25519Key, nil
+}
+
+// BundleVerification is how a bundle compares to its manifest
+type BundleVerification struct {
+	Manifest *BundleManifest `json:"manifest"`
+	// Signed is true when the signature of the manifest was verified with a public key
+	Signed bool `json:"signed"`
+	// Modified files do not have the size, checksum or symlink target of the manifest
+	Modified []string `json:"modified"`
+	// Missing files are in the manifest but not in the archive
+	Missing []string `json:"missing"`
+	// Unexpected files are in the archive but not in the manifest
+	Unexpected []string `json:"unexpected"`
+}
+
+// Err returns an error listing the files that do not match the manifest, or nil when they all do
+func (v *BundleVerification) Err() error {
+	problems := []string{}
+	if len(v.Modified) > 0 {
+		problems = append(problems, fmt.Sprintf("modified: %s", strings.Join(v.Modified, ", ")))
+	}
+	if len(v.Missing) > 0 {
+		problems = append(problems, fmt.Sprintf("missing: %s", strings.Join(v.Missing, ", ")))
+	}
+	if len(v.Unexpected) > 0 {
+		problems = append(problems, fmt.Sprintf("not in the manifest: %s", strings.Join(v.Unexpected, ", ")))
+	}
+	if len(problems) == 0 {
+		return nil
+	}
+	return errors.Errorf("bundle does not match its manifest, %s", strings.Join(problems, "; "))
+}
+
+// archivedFile is a file of a bundle archive as it is compared to the manifest
+type archivedFile struct {
+	size   int64
+	sha256 string
+	link   string
+}
+
+// VerifyBundle checks the files of a support bundle archive against its manifest, which must be
+// signed with the private key of publicKey when it is set. An error is returned when the archive
+// cannot be read, as when it is truncated, when the signature is missing or not valid, and when
+// the files do not match the manifest. The verification is returned with it once the manifest
+// has been read.
+func VerifyBundle(archivePath string, publicKey ed25519.PublicKey) (*BundleVerification, error) {
+	f, err := os.Open(archivePath)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open archive")
+	}
+	defer f.Close()
+
+	ar, err := collect.NewBundleArchiveReader(f)
+	if err != nil {
+		return nil, err
+	}
+	defer ar.Close()
+
+	return verifyBundle(ar, publicKey)
+}
+
+func verifyBundle(tr *collect.BundleArchiveReader, publicKey ed25519.PublicKey) (*BundleVerification, error) {
+	files := map[string]archivedFile{}
+	// the manifest and its signature are read whole, they are small
+	contents := map[string][]byte{}
+
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read archive, it may be truncated")
+		}
+
+		name := path.Clean(header.Name)
+		switch header.Typeflag {
+		case tar.TypeSymlink:
+			files[name] = archivedFile{link: header.Linkname}
+		case tar.TypeReg:
+			hash := sha256.New()
+			var w io.Writer = hash
+			var content bytes.Buffer
+			if base := path.Base(name); base == constants.MANIFEST_FILENAME || base == constants.MANIFEST_SIGNATURE_FILENAME {
+				w = io.MultiWriter(hash, &content)
+			}
+			size, err := io.Copy(w, tr)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to read %s, the archive may be truncated", header.Name)
+			}
+			files[name] = archivedFile{size: size, sha256: hex.EncodeToString(hash.Sum(nil))}
+			if content.Len() > 0 {
+				contents[name] = content.Bytes()
+			}
+		}
+	}
+
+	root := bundleRootDir(files)
+	manifestBytes := contents[root+constants.MANIFEST_FILENAME]
+	signature := contents[root+constants.MANIFEST_SIGNATURE_FILENAME]
+	relative := map[string]archivedFile{}
+	for name, file := range files {
+		name = strings.TrimPrefix(name, root)
+		if name != constants.MANIFEST_FILENAME && name != constants.MANIFEST_SIGNATURE_FILENAME {
+			relative[name] = file
+		}
+	}
+
+	if len(manifestBytes) == 0 {
+		return nil, errors.Errorf("bundle has no %s, it was collected by a version of troubleshoot that does not write one", constants.MANIFEST_FILENAME)
+	}
+	manifest := &BundleManifest{}
+	if err := json.Unmarshal(manifestBytes, manifest); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal manifest")
+	}
+	verification := &BundleVerification{
+		Manifest:   manifest,
+		Modified:   []string{},
+		Missing:    []string{},
+		Unexpected: []string{},
+	}
+
+	if publicKey != nil {
+		if len(signature) == 0 {
+			return verification, errors.Errorf("bundle is not signed, it has no %s", constants.MANIFEST_SIGNATURE_FILENAME)
+		}
+		decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(signature)))
+		if err != nil {
+			return verification, errors.Wrap(err, "failed to decode manifest signature")
+		}
+		if !ed25519.Verify(publicKey, manifestBytes, decoded) {
+			return verification, errors.New("manifest signature is not valid for the public key")
+		}
+		verification.Signed = true
+	}
+
+	for _, file := range manifest.Files {
+		archived, ok := relative[file.Path]
+		if !ok {
+			verification.Missing = append(verification.Missing, file.Path)
+			continue
+		}
+		delete(relative, file.Path)
+		if archived.link != file.Link || archived.size != file.Size || (file.Link == "" && archived.sha256 != file.SHA256) {
+			verification.Modified = append(verification.Modified, file.Path)
+		}
+	}
+	for name := range relative {
+		verification.Unexpected = append(verification.Unexpected, name)
+	}
+	sort.Strings(verification.Unexpected)
+
+	return verification, verification.Err()
+}
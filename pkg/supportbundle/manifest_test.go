@@ -0,0 +1,136 @@
+package supportbundle
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/collect"
+	"github.com/replicatedhq/troubleshoot/pkg/constants"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeSignedBundle archives a bundle with a manifest signed with key, and returns the archive
+// and the files of the bundle, manifest included
+func writeSignedBundle(t *testing.T, key ed25519.PrivateKey) (string, map[string]string) {
+	t.Helper()
+
+	bundlePath := filepath.Join(t.TempDir(), "support-bundle")
+	require.NoError(t, os.MkdirAll(bundlePath, 0777))
+
+	result := collect.NewResult()
+	require.NoError(t, result.SaveResult(bundlePath, "cluster-info/cluster_version.json", strings.NewReader(`{"major":"1"}`)))
+	require.NoError(t, result.SaveResult(bundlePath, constants.VERSION_FILENAME, strings.NewReader("version")))
+
+	index := &collect.BundleIndex{}
+	index.AddFiles("clusterInfo", false, collect.CollectorResult{"cluster-info/cluster_version.json": nil}, time.Now(), nil)
+	require.NoError(t, index.SaveResult(result, bundlePath))
+
+	spec := &troubleshootv1beta2.SupportBundleSpec{Collectors: []*troubleshootv1beta2.Collect{{ClusterInfo: &troubleshootv1beta2.ClusterInfo{}}}}
+	manifest, err := newBundleManifest(result, bundlePath, index, spec, nil, time.Now())
+	require.NoError(t, err)
+	require.NoError(t, saveBundleManifest(result, bundlePath, manifest, key))
+
+	archivePath := filepath.Join(t.TempDir(), "support-bundle.tar.gz")
+	require.NoError(t, result.ArchiveBundle(bundlePath, archivePath))
+
+	files := map[string]string{}
+	for name := range result {
+		b, err := os.ReadFile(filepath.Join(bundlePath, name))
+		require.NoError(t, err)
+		files["support-bundle/"+name] = string(b)
+	}
+	return archivePath, files
+}
+
+func TestVerifyBundle(t *testing.T) {
+	publicKey, key, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	archivePath, files := writeSignedBundle(t, key)
+
+	verification, err := VerifyBundle(archivePath, publicKey)
+	require.NoError(t, err)
+	assert.True(t, verification.Signed)
+	assert.Len(t, verification.Manifest.Files, 3)
+	assert.Equal(t, []ManifestCollector{{Collector: "clusterInfo", Version: verification.Manifest.ToolVersion}}, verification.Manifest.Collectors)
+	specSHA256, err := SpecChecksum(&troubleshootv1beta2.SupportBundleSpec{Collectors: []*troubleshootv1beta2.Collect{{ClusterInfo: &troubleshootv1beta2.ClusterInfo{}}}})
+	require.NoError(t, err)
+	assert.Equal(t, specSHA256, verification.Manifest.SpecSHA256)
+
+	t.Run("modified files", func(t *testing.T) {
+		tampered := map[string]string{}
+		for name, content := range files {
+			tampered[name] = content
+		}
+		tampered["support-bundle/cluster-info/cluster_version.json"] = `{"major":"2"}`
+		delete(tampered, "support-bundle/"+constants.VERSION_FILENAME)
+		tampered["support-bundle/extra.txt"] = "extra"
+
+		verification, err := VerifyBundle(writeTestBundle(t, tampered), publicKey)
+		require.Error(t, err)
+		assert.True(t, verification.Signed)
+		assert.Equal(t, []string{"cluster-info/cluster_version.json"}, verification.Modified)
+		assert.Equal(t, []string{constants.VERSION_FILENAME}, verification.Missing)
+		assert.Equal(t, []string{"extra.txt"}, verification.Unexpected)
+	})
+
+	t.Run("other key", func(t *testing.T) {
+		otherKey, _, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+		_, err = VerifyBundle(archivePath, otherKey)
+		assert.EqualError(t, err, "manifest signature is not valid for the public key")
+	})
+
+	t.Run("unsigned", func(t *testing.T) {
+		unsigned := map[string]string{}
+		for name, content := range files {
+			unsigned[name] = content
+		}
+		delete(unsigned, "support-bundle/"+constants.MANIFEST_SIGNATURE_FILENAME)
+
+		_, err := VerifyBundle(writeTestBundle(t, unsigned), publicKey)
+		assert.Error(t, err)
+		verification, err := VerifyBundle(writeTestBundle(t, unsigned), nil)
+		require.NoError(t, err)
+		assert.False(t, verification.Signed)
+	})
+
+	t.Run("truncated", func(t *testing.T) {
+		b, err := os.ReadFile(archivePath)
+		require.NoError(t, err)
+		truncated := filepath.Join(t.TempDir(), "truncated.tar.gz")
+		require.NoError(t, os.WriteFile(truncated, b[:len(b)/2], 0644))
+
+		_, err = VerifyBundle(truncated, publicKey)
+		assert.Error(t, err)
+	})
+}
+
+func TestParseVerificationKey(t *testing.T) {
+	publicKey, key, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	parsedKey, err := ParseSigningKey(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+	require.NoError(t, err)
+	assert.True(t, bytes.Equal(key, parsedKey))
+
+	der, err = x509.MarshalPKIXPublicKey(publicKey)
+	require.NoError(t, err)
+	parsedPublicKey, err := ParseVerificationKey(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+	require.NoError(t, err)
+	assert.True(t, bytes.Equal(publicKey, parsedPublicKey))
+
+	_, err = ParseVerificationKey([]byte("not a key"))
+	assert.Error(t, err)
+}
@@ -2,7 +2,6 @@ package supportbundle
 
 import (
 	"archive/tar"
-	"compress/gzip"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -12,6 +11,7 @@ import (
 	"strings"
 
 	"github.com/pkg/errors"
+	"github.com/replicatedhq/troubleshoot/pkg/collect"
 	"github.com/replicatedhq/troubleshoot/pkg/constants"
 	types "github.com/replicatedhq/troubleshoot/pkg/supportbundle/types"
 	corev1 "k8s.io/api/core/v1"
@@ -170,7 +170,7 @@ func GetFilesContents(bundleArchive string, filenames []string) (map[string][]by
 	return files, nil
 }
 
-// unarchive extracts a tar.gz archive to the specified destination directory
+// unarchive extracts a bundle archive to the specified destination directory
 func unarchive(archivePath, destDir string) error {
 	// Open the archive file
 	f, err := os.Open(archivePath)
@@ -179,15 +179,12 @@ func unarchive(archivePath, destDir string) error {
 	}
 	defer f.Close()
 
-	// Create a gzip reader
-	gzr, err := gzip.NewReader(f)
+	// Create a reader of the archive, tar.gz or with per-file compression
+	tr, err := collect.NewBundleArchiveReader(f)
 	if err != nil {
-		return errors.Wrap(err, "failed to create gzip reader")
+		return err
 	}
-	defer gzr.Close()
-
-	// Create a tar reader
-	tr := tar.NewReader(gzr)
+	defer tr.Close()
 
 	// Extract each file from the archive
 	for {
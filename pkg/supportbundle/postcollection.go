@@ -0,0 +1,370 @@
+package supportbundle
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/gobwas/glob"
+	"github.com/pkg/errors"
+	analyzer "github.com/replicatedhq/troubleshoot/pkg/analyze"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/collect"
+	"github.com/replicatedhq/troubleshoot/pkg/constants"
+)
+
+const (
+	defaultSummaryPath    = "summary.md"
+	defaultStatisticsPath = "statistics.json"
+	// summaryLargestFiles is the number of largest files listed in the summary
+	summaryLargestFiles = 10
+)
+
+// PostProcessor processes a bundle once it has been collected and analyzed, before it is
+// archived. Programs that collect bundles register processors by name in
+// SupportBundleCreateOpts.PostProcessors, and specs run them with a processor postCollection hook.
+type PostProcessor interface {
+	Process(ctx context.Context, bundle *PostCollectionBundle, args map[string]string) error
+}
+
+// PostProcessorFunc is a function that is a PostProcessor
+type PostProcessorFunc func(ctx context.Context, bundle *PostCollectionBundle, args map[string]string) error
+
+func (f PostProcessorFunc) Process(ctx context.Context, bundle *PostCollectionBundle, args map[string]string) error {
+	return f(ctx, bundle, args)
+}
+
+// PostCollectionBundle is the bundle post-collection processors read and change. Files are named
+// by their path relative to the root of the bundle.
+type PostCollectionBundle struct {
+	// AnalyzerResults are the results of the analyzers of the spec
+	AnalyzerResults []*analyzer.AnalyzeResult
+
+	bundlePath string
+	result     collect.CollectorResult
+}
+
+// Files returns the names of the files of the bundle, sorted
+func (b *PostCollectionBundle) Files() []string {
+	names := make([]string, 0, len(b.result))
+	for name := range b.result {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ReadFile returns the contents of a file of the bundle
+func (b *PostCollectionBundle) ReadFile(name string) ([]byte, error) {
+	if _, ok := b.result[name]; !ok {
+		return nil, errors.Errorf("file %s is not in the bundle", name)
+	}
+	reader, err := b.result.GetReader(b.bundlePath, name)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	return io.ReadAll(reader)
+}
+
+// FileSize returns the size of a file of the bundle
+func (b *PostCollectionBundle) FileSize(name string) (int64, error) {
+	data, ok := b.result[name]
+	if !ok {
+		return 0, errors.Errorf("file %s is not in the bundle", name)
+	}
+	if data != nil || b.bundlePath == "" {
+		return int64(len(data)), nil
+	}
+	info, err := os.Stat(filepath.Join(b.bundlePath, name))
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to stat %s", name)
+	}
+	return info.Size(), nil
+}
+
+// WriteFile adds a file to the bundle, or replaces it
+func (b *PostCollectionBundle) WriteFile(name string, data []byte) error {
+	if !filepath.IsLocal(name) {
+		return errors.Errorf("file %q is outside of the bundle", name)
+	}
+	return b.result.SaveResult(b.bundlePath, name, bytes.NewReader(data))
+}
+
+// RemoveFile removes a file from the bundle
+func (b *PostCollectionBundle) RemoveFile(name string) error {
+	if _, ok := b.result[name]; !ok {
+		return nil
+	}
+	if b.bundlePath != "" {
+		if err := os.Remove(filepath.Join(b.bundlePath, name)); err != nil && !os.IsNotExist(err) {
+			return errors.Wrapf(err, "failed to remove %s", name)
+		}
+	}
+	delete(b.result, name)
+	return nil
+}
+
+// validatePostCollection checks the postCollection hooks of a spec before anything is collected
+func validatePostCollection(hooks []*troubleshootv1beta2.PostCollection, processors map[string]PostProcessor) error {
+	for i, hook := range hooks {
+		if hook == nil {
+			continue
+		}
+
+		set := 0
+		if hook.Summary != nil {
+			set++
+		}
+		if hook.Report != nil {
+			set++
+			switch hook.Report.Format {
+			case "", ReportFormatMarkdown, ReportFormatHTML:
+			default:
+				return errors.Errorf("postCollection %d: unsupported report format %q, must be markdown or html", i, hook.Report.Format)
+			}
+		}
+		if hook.Statistics != nil {
+			set++
+		}
+		if hook.StripFiles != nil {
+			set++
+			if len(hook.StripFiles.Files) == 0 {
+				return errors.Errorf("postCollection %d: stripFiles requires files", i)
+			}
+			if _, err := compileStripFiles(hook.StripFiles.Files); err != nil {
+				return errors.Wrapf(err, "postCollection %d", i)
+			}
+		}
+		if hook.Processor != nil {
+			set++
+			if _, ok := processors[hook.Processor.Name]; !ok {
+				return errors.Errorf("postCollection %d: processor %q is not registered", i, hook.Processor.Name)
+			}
+		}
+		if set != 1 {
+			return errors.Errorf("postCollection %d: exactly one of summary, report, statistics, stripFiles and processor must be set", i)
+		}
+	}
+	return nil
+}
+
+// runPostCollection runs the postCollection hooks of a spec in order. It stops at the first hook
+// that fails.
+func runPostCollection(ctx context.Context, hooks []*troubleshootv1beta2.PostCollection, processors map[string]PostProcessor, bundle *PostCollectionBundle) error {
+	for i, hook := range hooks {
+		if hook == nil {
+			continue
+		}
+
+		var err error
+		switch {
+		case hook.Summary != nil:
+			err = writeBundleSummary(bundle, hook.Summary)
+		case hook.Report != nil:
+			err = writeBundleReport(bundle, hook.Report)
+		case hook.Statistics != nil:
+			err = writeBundleStatistics(bundle, hook.Statistics)
+		case hook.StripFiles != nil:
+			err = stripBundleFiles(bundle, hook.StripFiles)
+		case hook.Processor != nil:
+			processor, ok := processors[hook.Processor.Name]
+			if !ok {
+				err = errors.Errorf("processor %q is not registered", hook.Processor.Name)
+			} else {
+				err = processor.Process(ctx, bundle, hook.Processor.Args)
+			}
+		}
+		if err != nil {
+			return errors.Wrapf(err, "postCollection %d", i)
+		}
+	}
+	return nil
+}
+
+func compileStripFiles(patterns []string) ([]glob.Glob, error) {
+	globs := []glob.Glob{}
+	for _, pattern := range patterns {
+		g, err := glob.Compile(pattern, '/')
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid file glob %q", pattern)
+		}
+		globs = append(globs, g)
+	}
+	return globs, nil
+}
+
+// stripBundleFiles removes the files of the bundle that match one of the patterns. The version
+// file is kept, it identifies the archive as a support bundle.
+func stripBundleFiles(bundle *PostCollectionBundle, strip *troubleshootv1beta2.PostCollectionStripFiles) error {
+	globs, err := compileStripFiles(strip.Files)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range bundle.Files() {
+		if name == constants.VERSION_FILENAME {
+			continue
+		}
+		for _, g := range globs {
+			if !g.Match(name) {
+				continue
+			}
+			if err := bundle.RemoveFile(name); err != nil {
+				return err
+			}
+			break
+		}
+	}
+	return nil
+}
+
+// BundleStatistics counts the files of a bundle and their size
+type BundleStatistics struct {
+	Files       int                       `json:"files"`
+	Size        int64                     `json:"size"`
+	Directories map[string]BundleFileStat `json:"directories"`
+	Extensions  map[string]BundleFileStat `json:"extensions"`
+}
+
+type BundleFileStat struct {
+	Files int   `json:"files"`
+	Size  int64 `json:"size"`
+}
+
+// bundleStatistics counts the files of the bundle by top level directory, and by extension
+func bundleStatistics(bundle *PostCollectionBundle) (*BundleStatistics, error) {
+	statistics := &BundleStatistics{
+		Directories: map[string]BundleFileStat{},
+		Extensions:  map[string]BundleFileStat{},
+	}
+
+	for _, name := range bundle.Files() {
+		size, err := bundle.FileSize(name)
+		if err != nil {
+			return nil, err
+		}
+		statistics.Files++
+		statistics.Size += size
+
+		directory := "."
+		if dir, _, ok := strings.Cut(name, "/"); ok {
+			directory = dir
+		}
+		stat := statistics.Directories[directory]
+		stat.Files++
+		stat.Size += size
+		statistics.Directories[directory] = stat
+
+		extension := path.Ext(name)
+		if extension == "" {
+			extension = "none"
+		}
+		stat = statistics.Extensions[extension]
+		stat.Files++
+		stat.Size += size
+		statistics.Extensions[extension] = stat
+	}
+
+	return statistics, nil
+}
+
+func writeBundleStatistics(bundle *PostCollectionBundle, options *troubleshootv1beta2.PostCollectionStatistics) error {
+	statistics, err := bundleStatistics(bundle)
+	if err != nil {
+		return err
+	}
+
+	b, err := json.MarshalIndent(statistics, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal statistics")
+	}
+
+	filename := options.Path
+	if filename == "" {
+		filename = defaultStatisticsPath
+	}
+	return bundle.WriteFile(filename, b)
+}
+
+// writeBundleSummary writes a markdown summary of the bundle: the number and size of its files,
+// the counts of the analysis results with the failures and warnings, and its largest files
+func writeBundleSummary(bundle *PostCollectionBundle, options *troubleshootv1beta2.PostCollectionSummary) error {
+	statistics, err := bundleStatistics(bundle)
+	if err != nil {
+		return err
+	}
+
+	type fileSize struct {
+		name string
+		size int64
+	}
+	files := []fileSize{}
+	for _, name := range bundle.Files() {
+		size, err := bundle.FileSize(name)
+		if err != nil {
+			return err
+		}
+		files = append(files, fileSize{name: name, size: size})
+	}
+	sort.SliceStable(files, func(i, j int) bool {
+		return files[i].size > files[j].size
+	})
+	if len(files) > summaryLargestFiles {
+		files = files[:summaryLargestFiles]
+	}
+
+	var pass, warn, fail int
+	issues := []*analyzer.AnalyzeResult{}
+	for _, result := range bundle.AnalyzerResults {
+		if result == nil {
+			continue
+		}
+		switch {
+		case result.IsFail:
+			fail++
+			issues = append(issues, result)
+		case result.IsWarn:
+			warn++
+			issues = append(issues, result)
+		case result.IsPass:
+			pass++
+		}
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "# Support bundle summary\n\n")
+	fmt.Fprintf(&buf, "%d files, %d bytes\n\n", statistics.Files, statistics.Size)
+
+	fmt.Fprintf(&buf, "## Analysis\n\n")
+	fmt.Fprintf(&buf, "%d passed, %d warnings, %d failed\n", pass, warn, fail)
+	if len(issues) > 0 {
+		fmt.Fprintln(&buf)
+		for _, result := range issues {
+			outcome := "warn"
+			if result.IsFail {
+				outcome = "fail"
+			}
+			fmt.Fprintf(&buf, "- **%s** %s: %s\n", outcome, result.Title, result.Message)
+		}
+	}
+
+	fmt.Fprintf(&buf, "\n## Largest files\n\n")
+	fmt.Fprintf(&buf, "| File | Bytes |\n|---|---|\n")
+	for _, file := range files {
+		fmt.Fprintf(&buf, "| %s | %d |\n", file.name, file.size)
+	}
+
+	filename := options.Path
+	if filename == "" {
+		filename = defaultSummaryPath
+	}
+	return bundle.WriteFile(filename, buf.Bytes())
+}
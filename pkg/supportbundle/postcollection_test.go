@@ -0,0 +1,154 @@
+package supportbundle
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	analyzer "github.com/replicatedhq/troubleshoot/pkg/analyze"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/collect"
+	"github.com/replicatedhq/troubleshoot/pkg/constants"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestPostCollectionBundle(t *testing.T, files map[string]string) *PostCollectionBundle {
+	bundlePath := t.TempDir()
+	result := collect.NewResult()
+	for name, data := range files {
+		require.NoError(t, result.SaveResult(bundlePath, name, bytes.NewBufferString(data)))
+	}
+	return &PostCollectionBundle{bundlePath: bundlePath, result: result}
+}
+
+func Test_runPostCollection(t *testing.T) {
+	bundle := newTestPostCollectionBundle(t, map[string]string{
+		constants.VERSION_FILENAME:                                 "version",
+		"cluster-resources/pods/logs/default/web/app.log":          "line\n",
+		"cluster-resources/pods/logs/default/web/app-previous.log": "previous line\n",
+		"cluster-resources/nodes.json":                             "[]",
+	})
+	bundle.AnalyzerResults = []*analyzer.AnalyzeResult{
+		{IsPass: true, Title: "Kubernetes version", Message: "ok"},
+		{IsFail: true, Title: "Node resources", Message: "not enough nodes"},
+	}
+
+	processors := map[string]PostProcessor{
+		"marker": PostProcessorFunc(func(ctx context.Context, bundle *PostCollectionBundle, args map[string]string) error {
+			return bundle.WriteFile("marker.txt", []byte(args["text"]))
+		}),
+	}
+	hooks := []*troubleshootv1beta2.PostCollection{
+		{StripFiles: &troubleshootv1beta2.PostCollectionStripFiles{Files: []string{"cluster-resources/pods/logs/**/*-previous.log", constants.VERSION_FILENAME}}},
+		{Statistics: &troubleshootv1beta2.PostCollectionStatistics{}},
+		{Summary: &troubleshootv1beta2.PostCollectionSummary{Path: "summary/bundle.md"}},
+		{Processor: &troubleshootv1beta2.PostCollectionProcessor{Name: "marker", Args: map[string]string{"text": "processed"}}},
+	}
+	require.NoError(t, validatePostCollection(hooks, processors))
+	require.NoError(t, runPostCollection(context.Background(), hooks, processors, bundle))
+
+	assert.Equal(t, []string{
+		"cluster-resources/nodes.json",
+		"cluster-resources/pods/logs/default/web/app.log",
+		"marker.txt",
+		"statistics.json",
+		"summary/bundle.md",
+		constants.VERSION_FILENAME,
+	}, bundle.Files())
+	assert.NoFileExists(t, bundle.bundlePath+"/cluster-resources/pods/logs/default/web/app-previous.log")
+
+	b, err := bundle.ReadFile("statistics.json")
+	require.NoError(t, err)
+	statistics := BundleStatistics{}
+	require.NoError(t, json.Unmarshal(b, &statistics))
+	assert.Equal(t, BundleStatistics{
+		Files: 3,
+		Size:  int64(len("[]") + len("line\n") + len("version")),
+		Directories: map[string]BundleFileStat{
+			"cluster-resources": {Files: 2, Size: int64(len("[]") + len("line\n"))},
+			".":                 {Files: 1, Size: int64(len("version"))},
+		},
+		Extensions: map[string]BundleFileStat{
+			".json": {Files: 1, Size: int64(len("[]"))},
+			".log":  {Files: 1, Size: int64(len("line\n"))},
+			".yaml": {Files: 1, Size: int64(len("version"))},
+		},
+	}, statistics)
+
+	b, err = bundle.ReadFile("summary/bundle.md")
+	require.NoError(t, err)
+	summary := string(b)
+	assert.Contains(t, summary, "1 passed, 0 warnings, 1 failed")
+	assert.Contains(t, summary, "- **fail** Node resources: not enough nodes")
+	assert.True(t, strings.Contains(summary, "| cluster-resources/pods/logs/default/web/app.log | 5 |"), summary)
+
+	b, err = bundle.ReadFile("marker.txt")
+	require.NoError(t, err)
+	assert.Equal(t, "processed", string(b))
+}
+
+func Test_validatePostCollection(t *testing.T) {
+	tests := []struct {
+		name    string
+		hooks   []*troubleshootv1beta2.PostCollection
+		wantErr string
+	}{
+		{
+			name:  "valid",
+			hooks: []*troubleshootv1beta2.PostCollection{{Summary: &troubleshootv1beta2.PostCollectionSummary{}}},
+		},
+		{
+			name:    "no hook",
+			hooks:   []*troubleshootv1beta2.PostCollection{{}},
+			wantErr: "exactly one of summary, report, statistics, stripFiles and processor must be set",
+		},
+		{
+			name: "two hooks",
+			hooks: []*troubleshootv1beta2.PostCollection{{
+				Summary:    &troubleshootv1beta2.PostCollectionSummary{},
+				Statistics: &troubleshootv1beta2.PostCollectionStatistics{},
+			}},
+			wantErr: "exactly one of summary, report, statistics, stripFiles and processor must be set",
+		},
+		{
+			name:    "unsupported report format",
+			hooks:   []*troubleshootv1beta2.PostCollection{{Report: &troubleshootv1beta2.PostCollectionReport{Format: "pdf"}}},
+			wantErr: `unsupported report format "pdf"`,
+		},
+		{
+			name:    "unregistered processor",
+			hooks:   []*troubleshootv1beta2.PostCollection{{Processor: &troubleshootv1beta2.PostCollectionProcessor{Name: "missing"}}},
+			wantErr: `processor "missing" is not registered`,
+		},
+		{
+			name:    "invalid glob",
+			hooks:   []*troubleshootv1beta2.PostCollection{{StripFiles: &troubleshootv1beta2.PostCollectionStripFiles{Files: []string{"logs/["}}}},
+			wantErr: "invalid file glob",
+		},
+		{
+			name:    "no files to strip",
+			hooks:   []*troubleshootv1beta2.PostCollection{{StripFiles: &troubleshootv1beta2.PostCollectionStripFiles{}}},
+			wantErr: "stripFiles requires files",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validatePostCollection(tt.hooks, nil)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestPostCollectionBundle_WriteFileOutsideBundle(t *testing.T) {
+	bundle := newTestPostCollectionBundle(t, nil)
+	assert.Error(t, bundle.WriteFile("../escape.txt", []byte("data")))
+}
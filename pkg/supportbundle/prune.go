@@ -0,0 +1,148 @@
+package supportbundle
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// RetentionPolicy limits the support bundle archives that are kept. Archives are considered from
+// the most recent to the oldest, and an archive is pruned as soon as it exceeds one of the
+// limits. Limits that are not set do not prune anything.
+type RetentionPolicy struct {
+	// MaxAge prunes the archives that are older than this
+	MaxAge time.Duration
+	// MaxCount is the number of most recent archives to keep
+	MaxCount int
+	// MaxTotalSize is the total size in bytes of the archives to keep. The most recent archive is
+	// always kept, even when it is larger than the limit on its own.
+	MaxTotalSize int64
+	// DryRun returns the archives that would be pruned without deleting them
+	DryRun bool
+}
+
+// BundleArchive is a support bundle archive kept in a directory, or by a scheduled collection
+type BundleArchive struct {
+	Path    string    `json:"path"`
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"modTime"`
+}
+
+// IsLimited returns true when the policy has at least one limit
+func (p RetentionPolicy) IsLimited() bool {
+	return p.MaxAge > 0 || p.MaxCount > 0 || p.MaxTotalSize > 0
+}
+
+// Select splits the archives into those the policy keeps and those it prunes, both sorted from the
+// most recent to the oldest.
+func (p RetentionPolicy) Select(archives []BundleArchive, now time.Time) ([]BundleArchive, []BundleArchive) {
+	sorted := append([]BundleArchive{}, archives...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].ModTime.After(sorted[j].ModTime)
+	})
+
+	kept := []BundleArchive{}
+	pruned := []BundleArchive{}
+	var totalSize int64
+	for _, archive := range sorted {
+		switch {
+		case p.MaxAge > 0 && now.Sub(archive.ModTime) > p.MaxAge:
+			pruned = append(pruned, archive)
+		case p.MaxCount > 0 && len(kept) >= p.MaxCount:
+			pruned = append(pruned, archive)
+		case p.MaxTotalSize > 0 && len(kept) > 0 && totalSize+archive.Size > p.MaxTotalSize:
+			pruned = append(pruned, archive)
+		default:
+			kept = append(kept, archive)
+			totalSize += archive.Size
+		}
+	}
+
+	return kept, pruned
+}
+
+// Prune deletes the support bundle archives in dir that the policy does not keep, and returns
+// them. Only the .tar.gz and .tgz files directly in dir are considered, anything else is left
+// alone. When an archive cannot be deleted, the archives deleted until then are returned with the
+// error.
+func Prune(dir string, policy RetentionPolicy) ([]BundleArchive, error) {
+	if !policy.IsLimited() {
+		return nil, errors.New("at least one of the maximum age, count or total size is required")
+	}
+
+	archives, err := ListBundleArchives(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	_, pruned := policy.Select(archives, time.Now())
+	if policy.DryRun {
+		return pruned, nil
+	}
+
+	removed := []BundleArchive{}
+	for _, archive := range pruned {
+		if err := os.Remove(archive.Path); err != nil && !os.IsNotExist(err) {
+			return removed, errors.Wrapf(err, "failed to remove %s", archive.Path)
+		}
+		removed = append(removed, archive)
+	}
+
+	return removed, nil
+}
+
+// ListBundleArchives returns the support bundle archives directly in dir
+func ListBundleArchives(dir string) ([]BundleArchive, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read %s", dir)
+	}
+
+	archives := []BundleArchive{}
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() || !isBundleArchive(entry.Name()) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, errors.Wrapf(err, "failed to stat %s", entry.Name())
+		}
+		archives = append(archives, BundleArchive{
+			Path:    filepath.Join(dir, entry.Name()),
+			Size:    info.Size(),
+			ModTime: info.ModTime(),
+		})
+	}
+
+	return archives, nil
+}
+
+// ParseRetentionSize parses the maximum total size of a retention policy, a quantity such as
+// 10Gi. An empty size means there is no limit, and 0 is returned.
+func ParseRetentionSize(size string) (int64, error) {
+	if size == "" {
+		return 0, nil
+	}
+
+	quantity, err := resource.ParseQuantity(size)
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to parse size %q", size)
+	}
+	bytes, ok := quantity.AsInt64()
+	if !ok || bytes <= 0 {
+		return 0, errors.Errorf("size %q must be a number of bytes greater than 0", size)
+	}
+	return bytes, nil
+}
+
+func isBundleArchive(name string) bool {
+	return strings.HasSuffix(name, ".tar.gz") || strings.HasSuffix(name, ".tgz")
+}
@@ -0,0 +1,139 @@
+package supportbundle
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetentionPolicy_Select(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	archive := func(name string, age time.Duration, size int64) BundleArchive {
+		return BundleArchive{Path: name, Size: size, ModTime: now.Add(-age)}
+	}
+
+	archives := []BundleArchive{
+		archive("c", 48*time.Hour, 300),
+		archive("a", time.Hour, 100),
+		archive("d", 30*24*time.Hour, 100),
+		archive("b", 24*time.Hour, 200),
+	}
+
+	tests := []struct {
+		name       string
+		policy     RetentionPolicy
+		wantKept   []string
+		wantPruned []string
+	}{
+		{
+			name:       "no limits",
+			policy:     RetentionPolicy{},
+			wantKept:   []string{"a", "b", "c", "d"},
+			wantPruned: []string{},
+		},
+		{
+			name:       "max age",
+			policy:     RetentionPolicy{MaxAge: 7 * 24 * time.Hour},
+			wantKept:   []string{"a", "b", "c"},
+			wantPruned: []string{"d"},
+		},
+		{
+			name:       "max count",
+			policy:     RetentionPolicy{MaxCount: 2},
+			wantKept:   []string{"a", "b"},
+			wantPruned: []string{"c", "d"},
+		},
+		{
+			name:       "max total size",
+			policy:     RetentionPolicy{MaxTotalSize: 400},
+			wantKept:   []string{"a", "b", "d"},
+			wantPruned: []string{"c"},
+		},
+		{
+			name:       "most recent archive larger than max total size",
+			policy:     RetentionPolicy{MaxTotalSize: 50},
+			wantKept:   []string{"a"},
+			wantPruned: []string{"b", "c", "d"},
+		},
+		{
+			name:       "combined limits",
+			policy:     RetentionPolicy{MaxAge: 7 * 24 * time.Hour, MaxCount: 3, MaxTotalSize: 400},
+			wantKept:   []string{"a", "b"},
+			wantPruned: []string{"c", "d"},
+		},
+	}
+
+	paths := func(archives []BundleArchive) []string {
+		names := []string{}
+		for _, archive := range archives {
+			names = append(names, archive.Path)
+		}
+		return names
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kept, pruned := tt.policy.Select(archives, now)
+			assert.Equal(t, tt.wantKept, paths(kept))
+			assert.Equal(t, tt.wantPruned, paths(pruned))
+		})
+	}
+}
+
+func TestPrune(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+
+	write := func(name string, age time.Duration) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(name), 0644))
+		require.NoError(t, os.Chtimes(path, now.Add(-age), now.Add(-age)))
+		return path
+	}
+
+	newest := write("support-bundle-3.tar.gz", time.Hour)
+	middle := write("support-bundle-2.tgz", 2*time.Hour)
+	oldest := write("support-bundle-1.tar.gz", 3*time.Hour)
+	notes := write("notes.txt", 4*time.Hour)
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "extracted.tar.gz"), 0755))
+
+	pruned, err := Prune(dir, RetentionPolicy{MaxCount: 1, DryRun: true})
+	require.NoError(t, err)
+	require.Len(t, pruned, 2)
+	assert.Equal(t, middle, pruned[0].Path)
+	assert.Equal(t, oldest, pruned[1].Path)
+	assert.FileExists(t, middle)
+	assert.FileExists(t, oldest)
+
+	pruned, err = Prune(dir, RetentionPolicy{MaxCount: 1})
+	require.NoError(t, err)
+	require.Len(t, pruned, 2)
+	assert.FileExists(t, newest)
+	assert.FileExists(t, notes)
+	assert.DirExists(t, filepath.Join(dir, "extracted.tar.gz"))
+	assert.NoFileExists(t, middle)
+	assert.NoFileExists(t, oldest)
+
+	_, err = Prune(dir, RetentionPolicy{})
+	assert.Error(t, err)
+}
+
+func TestParseRetentionSize(t *testing.T) {
+	size, err := ParseRetentionSize("")
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), size)
+
+	size, err = ParseRetentionSize("1Gi")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1<<30), size)
+
+	_, err = ParseRetentionSize("lots")
+	assert.Error(t, err)
+
+	_, err = ParseRetentionSize("0")
+	assert.Error(t, err)
+}
@@ -0,0 +1,273 @@
+package supportbundle
+
+import (
+	"bytes"
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/collect"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/yaml"
+)
+
+// RBACCheckReport lists the permissions each collector of a spec is missing.
+type RBACCheckReport struct {
+	Collectors []RBACCheckResult `json:"collectors"`
+}
+
+// RBACCheckResult is the outcome of the access reviews of a collector. Skipped is set when the
+// collector will not run, and Reason says why. The cluster resources collector runs without some
+// of its permissions, and skips the resources it cannot read.
+type RBACCheckResult struct {
+	Collector string           `json:"collector"`
+	Skipped   bool             `json:"skipped"`
+	Reason    string           `json:"reason,omitempty"`
+	Missing   []RBACPermission `json:"missing,omitempty"`
+}
+
+// RBACPermission is a verb on a resource, in a namespace or at the cluster scope when the
+// namespace is empty.
+type RBACPermission struct {
+	Namespace string `json:"namespace,omitempty"`
+	Group     string `json:"group,omitempty"`
+	Resource  string `json:"resource"`
+	Verb      string `json:"verb"`
+}
+
+// CheckRBAC runs the access reviews of every collector of the spec, as the collection would
+// before it starts, without collecting anything.
+func CheckRBAC(ctx context.Context, spec *troubleshootv1beta2.SupportBundleSpec, opts SupportBundleCreateOpts) (*RBACCheckReport, error) {
+	if opts.KubernetesRestConfig == nil {
+		return nil, errors.New("did not receive kube rest config")
+	}
+
+	scope, err := collect.NewNamespacedScope(spec, opts.Namespace)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid scope")
+	}
+	if scope != nil {
+		opts.Namespace = scope.Namespace
+	}
+
+	client, err := kubernetes.NewForConfig(opts.KubernetesRestConfig)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to instantiate Kubernetes client")
+	}
+
+	report := &RBACCheckReport{Collectors: []RBACCheckResult{}}
+	skipped := collect.SkippedCollectors{}
+
+	for _, desiredCollector := range collectorSpecs(spec.Collectors) {
+		collectorInterface, ok := collect.GetCollectorWithContext(ctx, desiredCollector, "", opts.Namespace, opts.KubernetesRestConfig, client, nil)
+		if !ok {
+			continue
+		}
+		collector, ok := collectorInterface.(collect.Collector)
+		if !ok {
+			continue
+		}
+
+		result := RBACCheckResult{Collector: collector.Title()}
+		if excluded, _ := collector.IsExcluded(); excluded {
+			result.Skipped = true
+			result.Reason = collect.SkipReasonExcluded
+			report.Collectors = append(report.Collectors, result)
+			continue
+		}
+		if scope != nil && !scope.Restrict(collector, &skipped) {
+			result.Skipped = true
+			result.Reason = skipped[len(skipped)-1].Reason
+			report.Collectors = append(report.Collectors, result)
+			continue
+		}
+
+		_, isClusterResources := collector.(*collect.CollectClusterResources)
+		// in namespaced scope, cluster resources are not expected to be readable at the cluster scope
+		if !isClusterResources || scope == nil {
+			err := collector.CheckRBAC(ctx, collector, desiredCollector, opts.KubernetesRestConfig, opts.Namespace)
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to check RBAC for collectors")
+			}
+		}
+
+		result.Missing = rbacPermissions(collector.GetRBACErrors())
+		if len(result.Missing) > 0 && !isClusterResources {
+			result.Skipped = true
+			result.Reason = collect.SkipReasonInsufficientRBAC
+		}
+		report.Collectors = append(report.Collectors, result)
+	}
+
+	if len(spec.HostCollectors) > 0 && spec.RunHostCollectorsInPod {
+		result := RBACCheckResult{Collector: "host collectors"}
+		if scope != nil {
+			result.Skipped = true
+			result.Reason = collect.SkipReasonClusterScoped
+		} else {
+			err := checkRemoteCollectorRBAC(ctx, opts.KubernetesRestConfig, result.Collector, opts.Namespace)
+			var permissionErr *RBACPermissionError
+			if errors.As(err, &permissionErr) {
+				result.Missing = rbacPermissions(permissionErr.Forbidden)
+			} else if err != nil {
+				return nil, errors.Wrap(err, "failed to check RBAC for host collectors")
+			}
+		}
+		report.Collectors = append(report.Collectors, result)
+	}
+
+	return report, nil
+}
+
+func rbacPermissions(rbacErrors []error) []RBACPermission {
+	permissions := []RBACPermission{}
+	for _, err := range rbacErrors {
+		rbacErr, ok := errors.Cause(err).(collect.RBACError)
+		if !ok {
+			continue
+		}
+		resource := rbacErr.Resource
+		if rbacErr.Subresource != "" {
+			resource = resource + "/" + rbacErr.Subresource
+		}
+		permissions = append(permissions, RBACPermission{
+			Namespace: rbacErr.Namespace,
+			Group:     rbacErr.Group,
+			Resource:  resource,
+			Verb:      rbacErr.Verb,
+		})
+	}
+	if len(permissions) == 0 {
+		return nil
+	}
+	return permissions
+}
+
+// HasMissingPermissions returns whether any collector is missing permissions.
+func (r *RBACCheckReport) HasMissingPermissions() bool {
+	for _, result := range r.Collectors {
+		if len(result.Missing) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// RequiredRoles returns the missing permissions as a Role for each namespace, and a ClusterRole for
+// the permissions at the cluster scope. The ClusterRole is nil when no permission is missing at the
+// cluster scope.
+func (r *RBACCheckReport) RequiredRoles(name string) ([]*rbacv1.Role, *rbacv1.ClusterRole) {
+	permissionsByNamespace := map[string][]RBACPermission{}
+	for _, result := range r.Collectors {
+		for _, permission := range result.Missing {
+			permissionsByNamespace[permission.Namespace] = append(permissionsByNamespace[permission.Namespace], permission)
+		}
+	}
+
+	namespaces := []string{}
+	for namespace := range permissionsByNamespace {
+		if namespace != "" {
+			namespaces = append(namespaces, namespace)
+		}
+	}
+	sort.Strings(namespaces)
+
+	roles := []*rbacv1.Role{}
+	for _, namespace := range namespaces {
+		roles = append(roles, &rbacv1.Role{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "rbac.authorization.k8s.io/v1", Kind: "Role"},
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Rules:      policyRules(permissionsByNamespace[namespace]),
+		})
+	}
+
+	var clusterRole *rbacv1.ClusterRole
+	if permissions, ok := permissionsByNamespace[""]; ok {
+		clusterRole = &rbacv1.ClusterRole{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "rbac.authorization.k8s.io/v1", Kind: "ClusterRole"},
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Rules:      policyRules(permissions),
+		}
+	}
+
+	return roles, clusterRole
+}
+
+// RequiredRolesYAML returns the roles of RequiredRoles as a multi-document YAML that can be
+// applied with kubectl. The roles still need to be bound to the user collecting the bundle.
+func (r *RBACCheckReport) RequiredRolesYAML(name string) ([]byte, error) {
+	roles, clusterRole := r.RequiredRoles(name)
+
+	objects := []interface{}{}
+	if clusterRole != nil {
+		objects = append(objects, clusterRole)
+	}
+	for _, role := range roles {
+		objects = append(objects, role)
+	}
+
+	docs := [][]byte{}
+	for _, object := range objects {
+		b, err := yaml.Marshal(object)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to marshal role")
+		}
+		docs = append(docs, b)
+	}
+	return bytes.Join(docs, []byte("---\n")), nil
+}
+
+// policyRules merges the permissions on resources of the same group that need the same verbs
+// into a single rule.
+func policyRules(permissions []RBACPermission) []rbacv1.PolicyRule {
+	type resourceKey struct {
+		group    string
+		resource string
+	}
+	verbsByResource := map[resourceKey]map[string]bool{}
+	for _, permission := range permissions {
+		key := resourceKey{permission.Group, permission.Resource}
+		if verbsByResource[key] == nil {
+			verbsByResource[key] = map[string]bool{}
+		}
+		verbsByResource[key][permission.Verb] = true
+	}
+
+	type ruleKey struct {
+		group string
+		verbs string
+	}
+	rulesByKey := map[ruleKey]*rbacv1.PolicyRule{}
+	for key, verbSet := range verbsByResource {
+		verbs := []string{}
+		for verb := range verbSet {
+			verbs = append(verbs, verb)
+		}
+		sort.Strings(verbs)
+
+		rk := ruleKey{key.group, strings.Join(verbs, ",")}
+		rule, ok := rulesByKey[rk]
+		if !ok {
+			rule = &rbacv1.PolicyRule{APIGroups: []string{key.group}, Verbs: verbs}
+			rulesByKey[rk] = rule
+		}
+		rule.Resources = append(rule.Resources, key.resource)
+	}
+
+	rules := []rbacv1.PolicyRule{}
+	for _, rule := range rulesByKey {
+		sort.Strings(rule.Resources)
+		rules = append(rules, *rule)
+	}
+	sort.Slice(rules, func(i, j int) bool {
+		if rules[i].APIGroups[0] != rules[j].APIGroups[0] {
+			return rules[i].APIGroups[0] < rules[j].APIGroups[0]
+		}
+		return rules[i].Resources[0] < rules[j].Resources[0]
+	})
+	return rules
+}
@@ -0,0 +1,164 @@
+package supportbundle
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/collect"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/client-go/rest"
+)
+
+// accessReviewServer answers SelfSubjectAccessReviews, denying access to the resources listed.
+func accessReviewServer(t *testing.T, denied ...string) *rest.Config {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		review := authorizationv1.SelfSubjectAccessReview{}
+		if err := json.NewDecoder(r.Body).Decode(&review); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		review.APIVersion = "authorization.k8s.io/v1"
+		review.Kind = "SelfSubjectAccessReview"
+		review.Status.Allowed = true
+		for _, resource := range denied {
+			if review.Spec.ResourceAttributes != nil && review.Spec.ResourceAttributes.Resource == resource {
+				review.Status.Allowed = false
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(review)
+	}))
+	t.Cleanup(server.Close)
+
+	// the server only decodes JSON reviews
+	return &rest.Config{Host: server.URL, ContentConfig: rest.ContentConfig{ContentType: "application/json"}}
+}
+
+func TestCheckRBAC(t *testing.T) {
+	spec := &troubleshootv1beta2.SupportBundleSpec{
+		Collectors: []*troubleshootv1beta2.Collect{
+			{Logs: &troubleshootv1beta2.Logs{CollectorMeta: troubleshootv1beta2.CollectorMeta{CollectorName: "api"}, Name: "api", Namespace: "app", Selector: []string{"app=api"}}},
+			{Secret: &troubleshootv1beta2.Secret{CollectorMeta: troubleshootv1beta2.CollectorMeta{CollectorName: "db"}, Name: "db-creds", Namespace: "app"}},
+		},
+	}
+
+	report, err := CheckRBAC(context.Background(), spec, SupportBundleCreateOpts{
+		KubernetesRestConfig: accessReviewServer(t, "secrets", "nodes"),
+	})
+	require.NoError(t, err)
+
+	results := map[string]RBACCheckResult{}
+	for _, result := range report.Collectors {
+		results[result.Collector] = result
+	}
+	assert.Equal(t, RBACCheckResult{Collector: "cluster-info"}, results["cluster-info"])
+	assert.Equal(t, RBACCheckResult{Collector: "logs/api"}, results["logs/api"])
+	assert.Equal(t, RBACCheckResult{
+		Collector: "cluster-resources",
+		Missing:   []RBACPermission{{Resource: "nodes", Verb: "list"}},
+	}, results["cluster-resources"])
+	assert.Equal(t, RBACCheckResult{
+		Collector: "secret/db",
+		Skipped:   true,
+		Reason:    collect.SkipReasonInsufficientRBAC,
+		Missing:   []RBACPermission{{Namespace: "app", Resource: "secrets", Verb: "get"}},
+	}, results["secret/db"])
+	assert.True(t, report.HasMissingPermissions())
+
+	roles, clusterRole := report.RequiredRoles("support-bundle")
+	require.NotNil(t, clusterRole)
+	assert.Equal(t, []rbacv1.PolicyRule{{APIGroups: []string{""}, Resources: []string{"nodes"}, Verbs: []string{"list"}}}, clusterRole.Rules)
+	require.Len(t, roles, 1)
+	assert.Equal(t, "app", roles[0].Namespace)
+	assert.Equal(t, []rbacv1.PolicyRule{{APIGroups: []string{""}, Resources: []string{"secrets"}, Verbs: []string{"get"}}}, roles[0].Rules)
+}
+
+func TestCheckRBAC_NamespacedScope(t *testing.T) {
+	spec := &troubleshootv1beta2.SupportBundleSpec{
+		Scope:      troubleshootv1beta2.CollectionScopeNamespaced,
+		Namespaces: []string{"app"},
+		Collectors: []*troubleshootv1beta2.Collect{
+			{Logs: &troubleshootv1beta2.Logs{CollectorMeta: troubleshootv1beta2.CollectorMeta{CollectorName: "proxy"}, Name: "proxy", Namespace: "kube-system", Selector: []string{"app=proxy"}}},
+		},
+	}
+
+	report, err := CheckRBAC(context.Background(), spec, SupportBundleCreateOpts{
+		KubernetesRestConfig: accessReviewServer(t, "nodes"),
+	})
+	require.NoError(t, err)
+
+	results := map[string]RBACCheckResult{}
+	for _, result := range report.Collectors {
+		results[result.Collector] = result
+	}
+	assert.Equal(t, RBACCheckResult{Collector: "cluster-resources"}, results["cluster-resources"])
+	assert.Equal(t, RBACCheckResult{Collector: "logs/proxy", Skipped: true, Reason: collect.SkipReasonNamespaceOutOfScope}, results["logs/proxy"])
+	assert.False(t, report.HasMissingPermissions())
+}
+
+func TestRBACCheckReport_RequiredRolesYAML(t *testing.T) {
+	report := &RBACCheckReport{
+		Collectors: []RBACCheckResult{
+			{
+				Collector: "logs/api",
+				Missing: []RBACPermission{
+					{Namespace: "app", Resource: "pods", Verb: "list"},
+					{Namespace: "app", Resource: "pods/log", Verb: "get"},
+				},
+			},
+			{
+				Collector: "copy/config",
+				Missing: []RBACPermission{
+					{Namespace: "app", Resource: "pods", Verb: "get"},
+					{Namespace: "app", Resource: "pods/exec", Verb: "create"},
+				},
+			},
+			{
+				Collector: "secret/tls",
+				Missing: []RBACPermission{
+					{Namespace: "app", Resource: "secrets", Verb: "get"},
+				},
+			},
+		},
+	}
+
+	got, err := report.RequiredRolesYAML("support-bundle")
+	require.NoError(t, err)
+	assert.Equal(t, `apiVersion: rbac.authorization.k8s.io/v1
+kind: Role
+metadata:
+  creationTimestamp: null
+  name: support-bundle
+  namespace: app
+rules:
+- apiGroups:
+  - ""
+  resources:
+  - pods
+  verbs:
+  - get
+  - list
+- apiGroups:
+  - ""
+  resources:
+  - pods/exec
+  verbs:
+  - create
+- apiGroups:
+  - ""
+  resources:
+  - pods/log
+  - secrets
+  verbs:
+  - get
+`, string(got))
+}
@@ -0,0 +1,34 @@
+package supportbundle
+
+import (
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/troubleshoot/pkg/redact"
+)
+
+// writeRedactionTokens encrypts the value of each redaction token and writes it to path, readable
+// by its owner only
+func writeRedactionTokens(path, passphrase string, mapping map[string]string) error {
+	if mapping == nil {
+		mapping = map[string]string{}
+	}
+	encrypted, err := redact.EncryptTokenMapping(mapping, passphrase)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, encrypted, 0600); err != nil {
+		return errors.Wrapf(err, "failed to write %s", path)
+	}
+	return nil
+}
+
+// ReadRedactionTokens decrypts the redaction tokens written when a bundle was collected with
+// SupportBundleCreateOpts.RedactionTokensPath, and returns the value of each token.
+func ReadRedactionTokens(path, passphrase string) (map[string]string, error) {
+	encrypted, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read %s", path)
+	}
+	return redact.DecryptTokenMapping(encrypted, passphrase)
+}
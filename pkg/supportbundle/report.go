@@ -0,0 +1,402 @@
+package supportbundle
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	htmltemplate "html/template"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/pkg/errors"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/collect"
+	"github.com/replicatedhq/troubleshoot/pkg/constants"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	ReportFormatMarkdown = "markdown"
+	ReportFormatHTML     = "html"
+
+	defaultMarkdownReportPath = "report.md"
+	defaultHTMLReportPath     = "report.html"
+
+	// reportLogFiles is the number of log files excerpts are taken from
+	reportLogFiles = 20
+	// reportLogLines is the number of lines excerpted from each log file
+	reportLogLines = 5
+)
+
+var (
+	clusterVersionFile = path.Join("cluster-info", "cluster_version.json")
+	// reportLogLineRegex matches the log lines worth excerpting in a report
+	reportLogLineRegex = regexp.MustCompile(`(?i)\b(error|fatal|panic|exception)\b`)
+)
+
+// bundleReport is what a report is rendered from
+type bundleReport struct {
+	Files             int
+	Size              int64
+	Passed            int
+	Warnings          int
+	Failed            int
+	Results           []reportResult
+	KubernetesVersion string
+	Nodes             []reportNode
+	Namespaces        []reportNamespace
+	Issues            []string
+	LogExcerpts       []reportLogExcerpt
+}
+
+type reportResult struct {
+	Outcome string
+	Title   string
+	Message string
+}
+
+type reportNode struct {
+	Name    string
+	Ready   string
+	Roles   string
+	Version string
+}
+
+type reportNamespace struct {
+	Name       string
+	Pods       int
+	NotRunning int
+}
+
+type reportLogExcerpt struct {
+	File  string
+	Lines []reportLogLine
+}
+
+type reportLogLine struct {
+	Line int
+	Text string
+}
+
+// buildBundleReport gathers the analysis results, the cluster inventory, the warnings recorded
+// during the collection and excerpts of the error lines of the pod logs. Files that are missing or
+// cannot be decoded are left out of the report.
+func buildBundleReport(bundle *PostCollectionBundle) (*bundleReport, error) {
+	statistics, err := bundleStatistics(bundle)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &bundleReport{
+		Files: statistics.Files,
+		Size:  statistics.Size,
+	}
+
+	for _, result := range bundle.AnalyzerResults {
+		if result == nil {
+			continue
+		}
+		outcome := ""
+		switch {
+		case result.IsFail:
+			outcome = "fail"
+			report.Failed++
+		case result.IsWarn:
+			outcome = "warn"
+			report.Warnings++
+		case result.IsPass:
+			outcome = "pass"
+			report.Passed++
+		default:
+			continue
+		}
+		report.Results = append(report.Results, reportResult{Outcome: outcome, Title: result.Title, Message: result.Message})
+	}
+	// Failures first, then warnings, so that what needs attention is at the top
+	order := map[string]int{"fail": 0, "warn": 1, "pass": 2}
+	sort.SliceStable(report.Results, func(i, j int) bool {
+		return order[report.Results[i].Outcome] < order[report.Results[j].Outcome]
+	})
+
+	if b, err := bundle.ReadFile(clusterVersionFile); err == nil {
+		clusterVersion := collect.ClusterVersion{}
+		if err := json.Unmarshal(b, &clusterVersion); err == nil {
+			report.KubernetesVersion = clusterVersion.String
+		}
+	}
+
+	for _, row := range nodesTable(decodeBundleItems[corev1.Node](bundle, nodesFile)).rows {
+		report.Nodes = append(report.Nodes, reportNode{
+			Name:    row["name"],
+			Ready:   row["ready"],
+			Roles:   row["roles"],
+			Version: row["version"],
+		})
+	}
+
+	namespaces := map[string]*reportNamespace{}
+	for _, pod := range decodeBundleItems[corev1.Pod](bundle, podsFilesPattern) {
+		namespace, ok := namespaces[pod.Namespace]
+		if !ok {
+			namespace = &reportNamespace{Name: pod.Namespace}
+			namespaces[pod.Namespace] = namespace
+		}
+		namespace.Pods++
+		if pod.Status.Phase != corev1.PodRunning && pod.Status.Phase != corev1.PodSucceeded {
+			namespace.NotRunning++
+		}
+	}
+	for _, namespace := range namespaces {
+		report.Namespaces = append(report.Namespaces, *namespace)
+	}
+	sort.Slice(report.Namespaces, func(i, j int) bool {
+		return report.Namespaces[i].Name < report.Namespaces[j].Name
+	})
+
+	report.Issues = collectionIssues(bundle)
+	report.LogExcerpts = logExcerpts(bundle)
+
+	return report, nil
+}
+
+// collectionIssues returns the collectors that were skipped, and the errors collectors recorded in
+// -errors.json files
+func collectionIssues(bundle *PostCollectionBundle) []string {
+	issues := []string{}
+
+	if b, err := bundle.ReadFile(constants.SKIPPED_COLLECTORS_FILENAME); err == nil {
+		skipped := collect.SkippedCollectors{}
+		if err := json.Unmarshal(b, &skipped); err == nil {
+			for _, s := range skipped {
+				collector := s.Collector
+				if s.Name != "" {
+					collector += " " + s.Name
+				}
+				issue := collector + " skipped: " + s.Reason
+				if len(s.Resources) > 0 {
+					issue += " (" + strings.Join(s.Resources, ", ") + ")"
+				}
+				issues = append(issues, issue)
+			}
+		}
+	}
+
+	for _, name := range bundle.Files() {
+		if !strings.HasSuffix(name, "-errors.json") {
+			continue
+		}
+		b, err := bundle.ReadFile(name)
+		if err != nil {
+			continue
+		}
+		collectionErrors := []string{}
+		if err := json.Unmarshal(b, &collectionErrors); err != nil {
+			continue
+		}
+		for _, collectionError := range collectionErrors {
+			issues = append(issues, name+": "+collectionError)
+		}
+	}
+
+	return issues
+}
+
+// logExcerpts returns the first error lines of the pod logs of the bundle
+func logExcerpts(bundle *PostCollectionBundle) []reportLogExcerpt {
+	excerpts := []reportLogExcerpt{}
+	for _, name := range bundle.Files() {
+		if len(excerpts) >= reportLogFiles {
+			break
+		}
+		if ok, _ := path.Match(logsFilesPattern, name); !ok || strings.HasSuffix(name, "-logs-errors.log") {
+			continue
+		}
+		content, err := bundle.ReadFile(name)
+		if err != nil {
+			continue
+		}
+
+		excerpt := reportLogExcerpt{File: name}
+		scanner := bufio.NewScanner(bytes.NewReader(content))
+		scanner.Buffer(make([]byte, 0, 64*1024), maxBrowsedFileSize)
+		line := 0
+		for scanner.Scan() && len(excerpt.Lines) < reportLogLines {
+			line++
+			if reportLogLineRegex.MatchString(scanner.Text()) {
+				excerpt.Lines = append(excerpt.Lines, reportLogLine{Line: line, Text: scanner.Text()})
+			}
+		}
+		if len(excerpt.Lines) > 0 {
+			excerpts = append(excerpts, excerpt)
+		}
+	}
+	return excerpts
+}
+
+var markdownReportTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"cell": markdownCell,
+}).Parse(`# Support bundle report
+
+{{.Files}} files, {{.Size}} bytes
+
+## Analysis
+
+{{.Passed}} passed, {{.Warnings}} warnings, {{.Failed}} failed
+{{- if .Results}}
+
+| Outcome | Title | Message |
+|---|---|---|
+{{- range .Results}}
+| {{.Outcome}} | {{cell .Title}} | {{cell .Message}} |
+{{- end}}
+{{- end}}
+
+## Cluster inventory
+
+Kubernetes version: {{if .KubernetesVersion}}{{.KubernetesVersion}}{{else}}unknown{{end}}
+{{- if .Nodes}}
+
+| Node | Ready | Roles | Version |
+|---|---|---|---|
+{{- range .Nodes}}
+| {{cell .Name}} | {{.Ready}} | {{cell .Roles}} | {{cell .Version}} |
+{{- end}}
+{{- end}}
+{{- if .Namespaces}}
+
+| Namespace | Pods | Not running |
+|---|---|---|
+{{- range .Namespaces}}
+| {{cell .Name}} | {{.Pods}} | {{.NotRunning}} |
+{{- end}}
+{{- end}}
+
+## Warnings
+{{if .Issues}}
+{{range .Issues}}- {{.}}
+{{end}}{{else}}
+No collectors were skipped or reported errors
+{{end}}
+## Log excerpts
+{{if .LogExcerpts}}{{range .LogExcerpts}}
+### {{.File}}
+
+` + "```" + `
+{{range .Lines}}{{.Line}}: {{.Text}}
+{{end}}` + "```" + `
+{{end}}{{else}}
+No errors found in the pod logs
+{{end}}`))
+
+var htmlReportTemplate = htmltemplate.Must(htmltemplate.New("report").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Support bundle report</title>
+<style>
+body { font-family: sans-serif; margin: 2em; color: #222; }
+table { border-collapse: collapse; margin: 1em 0; }
+th, td { border: 1px solid #ccc; padding: 4px 8px; text-align: left; vertical-align: top; }
+th { background: #f4f4f4; }
+pre { background: #f4f4f4; padding: 8px; overflow-x: auto; }
+.fail { color: #b00020; font-weight: bold; }
+.warn { color: #b26a00; font-weight: bold; }
+.pass { color: #2e7d32; }
+</style>
+</head>
+<body>
+<h1>Support bundle report</h1>
+<p>{{.Files}} files, {{.Size}} bytes</p>
+
+<h2>Analysis</h2>
+<p>{{.Passed}} passed, {{.Warnings}} warnings, {{.Failed}} failed</p>
+{{- if .Results}}
+<table>
+<tr><th>Outcome</th><th>Title</th><th>Message</th></tr>
+{{- range .Results}}
+<tr><td class="{{.Outcome}}">{{.Outcome}}</td><td>{{.Title}}</td><td>{{.Message}}</td></tr>
+{{- end}}
+</table>
+{{- end}}
+
+<h2>Cluster inventory</h2>
+<p>Kubernetes version: {{if .KubernetesVersion}}{{.KubernetesVersion}}{{else}}unknown{{end}}</p>
+{{- if .Nodes}}
+<table>
+<tr><th>Node</th><th>Ready</th><th>Roles</th><th>Version</th></tr>
+{{- range .Nodes}}
+<tr><td>{{.Name}}</td><td>{{.Ready}}</td><td>{{.Roles}}</td><td>{{.Version}}</td></tr>
+{{- end}}
+</table>
+{{- end}}
+{{- if .Namespaces}}
+<table>
+<tr><th>Namespace</th><th>Pods</th><th>Not running</th></tr>
+{{- range .Namespaces}}
+<tr><td>{{.Name}}</td><td>{{.Pods}}</td><td>{{.NotRunning}}</td></tr>
+{{- end}}
+</table>
+{{- end}}
+
+<h2>Warnings</h2>
+{{- if .Issues}}
+<ul>
+{{- range .Issues}}
+<li>{{.}}</li>
+{{- end}}
+</ul>
+{{- else}}
+<p>No collectors were skipped or reported errors</p>
+{{- end}}
+
+<h2>Log excerpts</h2>
+{{- range .LogExcerpts}}
+<h3>{{.File}}</h3>
+<pre>{{range .Lines}}{{.Line}}: {{.Text}}
+{{end}}</pre>
+{{- else}}
+<p>No errors found in the pod logs</p>
+{{- end}}
+</body>
+</html>
+`))
+
+// markdownCell escapes a value so that it fits in a single markdown table cell
+func markdownCell(value string) string {
+	value = strings.ReplaceAll(value, "|", `\|`)
+	return strings.Join(strings.Fields(value), " ")
+}
+
+// writeBundleReport renders the report of the bundle as markdown or HTML. The HTML report embeds
+// its styles, so that it can be opened on its own once extracted from the archive.
+func writeBundleReport(bundle *PostCollectionBundle, options *troubleshootv1beta2.PostCollectionReport) error {
+	report, err := buildBundleReport(bundle)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	filename := options.Path
+	switch options.Format {
+	case "", ReportFormatMarkdown:
+		err = markdownReportTemplate.Execute(&buf, report)
+		if filename == "" {
+			filename = defaultMarkdownReportPath
+		}
+	case ReportFormatHTML:
+		err = htmlReportTemplate.Execute(&buf, report)
+		if filename == "" {
+			filename = defaultHTMLReportPath
+		}
+	default:
+		return errors.Errorf("unsupported report format %q", options.Format)
+	}
+	if err != nil {
+		return errors.Wrap(err, "failed to render report")
+	}
+
+	return bundle.WriteFile(filename, buf.Bytes())
+}
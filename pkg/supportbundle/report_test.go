@@ -0,0 +1,82 @@
+package supportbundle
+
+import (
+	"testing"
+
+	analyzer "github.com/replicatedhq/troubleshoot/pkg/analyze"
+	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/constants"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestReportBundle(t *testing.T) *PostCollectionBundle {
+	bundle := newTestPostCollectionBundle(t, map[string]string{
+		constants.VERSION_FILENAME:          "version",
+		"cluster-info/cluster_version.json": `{"string": "v1.29.2"}`,
+		"cluster-resources/nodes.json": `{"items": [{
+			"metadata": {"name": "node-1", "labels": {"node-role.kubernetes.io/control-plane": ""}},
+			"status": {"conditions": [{"type": "Ready", "status": "True"}], "nodeInfo": {"kubeletVersion": "v1.29.2"}}
+		}]}`,
+		"cluster-resources/pods/default.json": `{"items": [
+			{"metadata": {"name": "web", "namespace": "default"}, "status": {"phase": "Running"}},
+			{"metadata": {"name": "worker", "namespace": "default"}, "status": {"phase": "Pending"}}
+		]}`,
+		"cluster-resources/pods-errors.json":                 `["failed to list pods in kube-system: forbidden"]`,
+		constants.SKIPPED_COLLECTORS_FILENAME:                `[{"collector": "secret", "name": "db", "reason": "insufficient RBAC permissions"}]`,
+		"cluster-resources/pods/logs/default/web/app.log":    "starting\nERROR connecting to <db>\nready\n",
+		"cluster-resources/pods/logs/default/worker/app.log": "all good\n",
+	})
+	bundle.AnalyzerResults = []*analyzer.AnalyzeResult{
+		{IsPass: true, Title: "Kubernetes version", Message: "ok"},
+		{IsFail: true, Title: "Node resources", Message: "needs | 3 nodes"},
+	}
+	return bundle
+}
+
+func Test_writeBundleReport_markdown(t *testing.T) {
+	bundle := newTestReportBundle(t)
+	require.NoError(t, writeBundleReport(bundle, &troubleshootv1beta2.PostCollectionReport{}))
+
+	b, err := bundle.ReadFile("report.md")
+	require.NoError(t, err)
+	report := string(b)
+
+	assert.Contains(t, report, "1 passed, 0 warnings, 1 failed")
+	assert.Contains(t, report, "| fail | Node resources | needs \\| 3 nodes |\n| pass | Kubernetes version | ok |")
+	assert.Contains(t, report, "Kubernetes version: v1.29.2")
+	assert.Contains(t, report, "| node-1 | true | control-plane | v1.29.2 |")
+	assert.Contains(t, report, "| default | 2 | 1 |")
+	assert.Contains(t, report, "- secret db skipped: insufficient RBAC permissions")
+	assert.Contains(t, report, "- cluster-resources/pods-errors.json: failed to list pods in kube-system: forbidden")
+	assert.Contains(t, report, "### cluster-resources/pods/logs/default/web/app.log\n\n```\n2: ERROR connecting to <db>\n```")
+	assert.NotContains(t, report, "worker/app.log")
+}
+
+func Test_writeBundleReport_html(t *testing.T) {
+	bundle := newTestReportBundle(t)
+	require.NoError(t, writeBundleReport(bundle, &troubleshootv1beta2.PostCollectionReport{Format: ReportFormatHTML, Path: "reports/bundle.html"}))
+
+	b, err := bundle.ReadFile("reports/bundle.html")
+	require.NoError(t, err)
+	report := string(b)
+
+	assert.Contains(t, report, "<style>")
+	assert.Contains(t, report, `<td class="fail">fail</td><td>Node resources</td><td>needs | 3 nodes</td>`)
+	assert.Contains(t, report, "<tr><td>node-1</td><td>true</td><td>control-plane</td><td>v1.29.2</td></tr>")
+	assert.Contains(t, report, "2: ERROR connecting to &lt;db&gt;")
+	assert.NotContains(t, report, "<db>")
+}
+
+func Test_writeBundleReport_emptyBundle(t *testing.T) {
+	bundle := newTestPostCollectionBundle(t, nil)
+	require.NoError(t, writeBundleReport(bundle, &troubleshootv1beta2.PostCollectionReport{}))
+
+	b, err := bundle.ReadFile("report.md")
+	require.NoError(t, err)
+	assert.Contains(t, string(b), "Kubernetes version: unknown")
+	assert.Contains(t, string(b), "No collectors were skipped or reported errors")
+	assert.Contains(t, string(b), "No errors found in the pod logs")
+
+	assert.Error(t, writeBundleReport(bundle, &troubleshootv1beta2.PostCollectionReport{Format: "pdf"}))
+}
@@ -0,0 +1,102 @@
+package supportbundle
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/pprof"
+	"runtime/trace"
+
+	"github.com/pkg/errors"
+	"github.com/replicatedhq/troubleshoot/pkg/collect"
+	"github.com/replicatedhq/troubleshoot/pkg/constants"
+)
+
+// selfProfiler records a CPU profile and an execution trace of troubleshoot itself while it
+// collects, redacts and analyzes a bundle. The profiles are written to a directory outside of the
+// bundle while they are recorded, and saved in the bundle with a heap profile once they stop.
+type selfProfiler struct {
+	cpuPath   string
+	tracePath string
+	files     []*os.File
+	stopped   bool
+}
+
+// startSelfProfiler starts recording the profiles to files in dir. It fails when the CPU profile
+// is already recorded, such as with the --cpuprofile flag.
+func startSelfProfiler(dir string) (*selfProfiler, error) {
+	p := &selfProfiler{
+		cpuPath:   filepath.Join(dir, "cpu.pprof"),
+		tracePath: filepath.Join(dir, "trace.out"),
+	}
+
+	cpu, err := os.Create(p.cpuPath)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create CPU profile")
+	}
+	if err := pprof.StartCPUProfile(cpu); err != nil {
+		cpu.Close()
+		return nil, errors.Wrap(err, "failed to start CPU profile")
+	}
+	p.files = append(p.files, cpu)
+
+	tr, err := os.Create(p.tracePath)
+	if err != nil {
+		p.stop()
+		return nil, errors.Wrap(err, "failed to create execution trace")
+	}
+	p.files = append(p.files, tr)
+	if err := trace.Start(tr); err != nil {
+		p.stop()
+		return nil, errors.Wrap(err, "failed to start execution trace")
+	}
+
+	return p, nil
+}
+
+// stop stops recording the profiles. It does nothing on a nil or stopped profiler, so that it can
+// be deferred in case the bundle is not saved.
+func (p *selfProfiler) stop() {
+	if p == nil || p.stopped {
+		return
+	}
+	p.stopped = true
+
+	trace.Stop()
+	pprof.StopCPUProfile()
+	for _, f := range p.files {
+		f.Close()
+	}
+}
+
+// save stops recording the profiles and saves them to the execution-data directory of the bundle,
+// along with a heap profile of the allocations of the run
+func (p *selfProfiler) save(result collect.CollectorResult, bundlePath string) error {
+	p.stop()
+
+	for _, profile := range []struct{ path, filename string }{
+		{p.cpuPath, constants.CPU_PROFILE_FILENAME},
+		{p.tracePath, constants.EXECUTION_TRACE_FILENAME},
+	} {
+		f, err := os.Open(profile.path)
+		if err != nil {
+			return errors.Wrapf(err, "failed to open %s", profile.filename)
+		}
+		err = result.SaveResult(bundlePath, profile.filename, f)
+		f.Close()
+		if err != nil {
+			return errors.Wrapf(err, "failed to save %s", profile.filename)
+		}
+	}
+
+	runtime.GC() // the heap profile is as of the last garbage collection
+	heap := bytes.Buffer{}
+	if err := pprof.Lookup("heap").WriteTo(&heap, 0); err != nil {
+		return errors.Wrap(err, "failed to write heap profile")
+	}
+	if err := result.SaveResult(bundlePath, constants.HEAP_PROFILE_FILENAME, &heap); err != nil {
+		return errors.Wrapf(err, "failed to save %s", constants.HEAP_PROFILE_FILENAME)
+	}
+	return nil
+}
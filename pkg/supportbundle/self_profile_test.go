@@ -0,0 +1,39 @@
+package supportbundle
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/replicatedhq/troubleshoot/pkg/collect"
+	"github.com/replicatedhq/troubleshoot/pkg/constants"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelfProfiler(t *testing.T) {
+	bundlePath := t.TempDir()
+
+	profiler, err := startSelfProfiler(t.TempDir())
+	require.NoError(t, err)
+	defer profiler.stop()
+
+	_, err = startSelfProfiler(t.TempDir())
+	assert.Error(t, err, "the profiles are already recorded")
+
+	result := collect.NewResult()
+	require.NoError(t, profiler.save(result, bundlePath))
+	profiler.stop()
+
+	for _, filename := range []string{constants.CPU_PROFILE_FILENAME, constants.HEAP_PROFILE_FILENAME, constants.EXECUTION_TRACE_FILENAME} {
+		assert.Contains(t, result, filename)
+		info, err := os.Stat(filepath.Join(bundlePath, filename))
+		require.NoError(t, err)
+		assert.NotZero(t, info.Size(), filename)
+	}
+
+	// profiling can start again once the profiles are saved
+	profiler, err = startSelfProfiler(t.TempDir())
+	require.NoError(t, err)
+	profiler.stop()
+}
@@ -3,6 +3,8 @@ package supportbundle
 import (
 	"bytes"
 	"context"
+	"crypto/ed25519"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"os"
@@ -20,6 +22,10 @@ import (
 	"github.com/replicatedhq/troubleshoot/pkg/collect"
 	"github.com/replicatedhq/troubleshoot/pkg/constants"
 	"github.com/replicatedhq/troubleshoot/pkg/convert"
+	"github.com/replicatedhq/troubleshoot/pkg/facts"
+	"github.com/replicatedhq/troubleshoot/pkg/metrics"
+	"github.com/replicatedhq/troubleshoot/pkg/notify"
+	"github.com/replicatedhq/troubleshoot/pkg/redact"
 	"github.com/replicatedhq/troubleshoot/pkg/version"
 	"go.opentelemetry.io/otel"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -40,12 +46,63 @@ type SupportBundleCreateOpts struct {
 	Redact                    bool
 	FromCLI                   bool
 	RunHostCollectorsInPod    bool
+	// CustomCollectors run after the collectors in the spec, their results are redacted and
+	// analyzed with the rest of the bundle.
+	CustomCollectors []CustomCollector
+	// EmbedTraces saves the spans of the collection and analysis in the bundle, so that slow
+	// collections can be profiled. Spans are only recorded when the exporter of the traces
+	// package is registered with the trace provider.
+	EmbedTraces bool
+	// EmbedProfiles saves CPU and heap profiles and a Go execution trace of troubleshoot itself,
+	// recorded while it collects, redacts and analyzes, in the execution-data directory of the
+	// bundle, to debug slow or memory hungry collections.
+	EmbedProfiles bool
+	// PerFileCompression archives the bundle as a tar archive of files compressed one by one with
+	// the codec of their type, rather than as a tar.gz archive. Files compressed already, such as
+	// gzipped logs, are stored as they are and other files are compressed with zstd.
+	PerFileCompression bool
+	// PostProcessors are the processors the postCollection hooks of the spec can run, by name.
+	PostProcessors map[string]PostProcessor
+	// RedactionTokensPath, when set, replaces the values redactors remove with stable tokens such
+	// as ***TOKEN_42***, and writes the value of each token to this file once the bundle is
+	// archived. The file is encrypted with RedactionTokensPassphrase and is not part of the
+	// bundle, so that the bundle can be shared while the file is kept.
+	RedactionTokensPath       string
+	RedactionTokensPassphrase string
+	// SigningKey, when set, signs the manifest of the bundle, which lists the checksum of each of
+	// its files, so that recipients can verify the bundle with VerifyBundle and the public key.
+	// The manifest is written to every bundle, signed or not.
+	SigningKey ed25519.PrivateKey
+
+	// namespacedScope is set from the spec when it is namespaced scoped
+	namespacedScope *collect.NamespacedScope
+	// metadataOnly is set from the spec when it collects with the metadataOnly profile
+	metadataOnly bool
+	// bundleIndex records the collector of each file of the bundle
+	bundleIndex *collect.BundleIndex
+	// retried records the attempts of the collectors that were retried
+	retried *collect.RetriedCollectors
+	// facts are read from the cluster before the collectors run, to evaluate exclude templates with,
+	// when a template references them
+	facts *facts.Facts
+	// redactionTokens assigns the tokens that replace redacted values when RedactionTokensPath
+	// is set
+	redactionTokens *redact.Tokenizer
+}
+
+// CustomCollector is a collector implemented outside of troubleshoot. Collect returns the
+// contents of the files to add to the bundle, keyed by their path relative to the bundle root.
+type CustomCollector interface {
+	Title() string
+	Collect(ctx context.Context) (map[string][]byte, error)
 }
 
 type SupportBundleResponse struct {
 	AnalyzerResults []*analyzer.AnalyzeResult
 	ArchivePath     string
 	FileUploaded    bool
+	// RedactionTokensPath is the file the redaction tokens were written to, if any
+	RedactionTokensPath string
 }
 
 // NodeList is a list of remote nodes to collect data from in a support bundle
@@ -60,8 +117,26 @@ type NodeList struct {
 func CollectSupportBundleFromSpec(
 	spec *troubleshootv1beta2.SupportBundleSpec, additionalRedactors *troubleshootv1beta2.Redactor, opts SupportBundleCreateOpts,
 ) (*SupportBundleResponse, error) {
+	return CollectSupportBundleFromSpecWithContext(context.Background(), spec, additionalRedactors, opts)
+}
+
+// CollectSupportBundleFromSpecWithContext is CollectSupportBundleFromSpec with a context that is
+// the parent of the collection traces and is passed to custom collectors.
+func CollectSupportBundleFromSpecWithContext(
+	ctx context.Context, spec *troubleshootv1beta2.SupportBundleSpec, additionalRedactors *troubleshootv1beta2.Redactor, opts SupportBundleCreateOpts,
+) (*SupportBundleResponse, error) {
+	startedAt := time.Now()
+	response, err := collectSupportBundleFromSpec(ctx, spec, additionalRedactors, opts)
+	metrics.ObserveCollection(time.Since(startedAt), response != nil, err)
+	return response, err
+}
+
+func collectSupportBundleFromSpec(
+	ctx context.Context, spec *troubleshootv1beta2.SupportBundleSpec, additionalRedactors *troubleshootv1beta2.Redactor, opts SupportBundleCreateOpts,
+) (*SupportBundleResponse, error) {
 
 	resultsResponse := SupportBundleResponse{}
+	startedAt := time.Now()
 
 	if opts.KubernetesRestConfig == nil {
 		return nil, errors.New("did not receive kube rest config")
@@ -71,6 +146,45 @@ func CollectSupportBundleFromSpec(
 		return nil, errors.New("did not receive collector progress chan")
 	}
 
+	scope, err := collect.NewNamespacedScope(spec, opts.Namespace)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid scope")
+	}
+	if scope != nil {
+		opts.namespacedScope = scope
+		opts.Namespace = scope.Namespace
+	}
+
+	if err := collect.CheckAllowedOperations(spec, collect.SpecOperations(spec, opts.Namespace)); err != nil {
+		return nil, err
+	}
+
+	docFiles, err := analyzer.DocFiles(spec.Documentation)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read documentation")
+	}
+
+	opts.metadataOnly, err = collect.IsMetadataOnly(spec)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid profile")
+	}
+
+	maxSize, err := collect.ParseMaxSize(spec.MaxSize)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid bundle maxSize")
+	}
+
+	if err := validatePostCollection(spec.PostCollection, opts.PostProcessors); err != nil {
+		return nil, errors.Wrap(err, "invalid postCollection")
+	}
+
+	if opts.RedactionTokensPath != "" {
+		if opts.RedactionTokensPassphrase == "" {
+			return nil, errors.New("a passphrase is required to encrypt the redaction tokens")
+		}
+		opts.redactionTokens = redact.NewTokenizer()
+	}
+
 	tmpDir, err := os.MkdirTemp("", "supportbundle")
 	if err != nil {
 		return nil, errors.Wrap(err, "create temp dir")
@@ -78,6 +192,11 @@ func CollectSupportBundleFromSpec(
 	defer os.RemoveAll(tmpDir)
 	klog.V(2).Infof("Support bundle created in temporary directory: %s", tmpDir)
 
+	extension := "tar.gz"
+	if opts.PerFileCompression {
+		extension = "tar"
+	}
+
 	basename := ""
 	if opts.OutputPath != "" {
 		// use override output path
@@ -85,7 +204,7 @@ func CollectSupportBundleFromSpec(
 		if err != nil {
 			return nil, errors.Wrap(err, "override output file path")
 		}
-		basename = strings.TrimSuffix(overridePath, ".tar.gz")
+		basename = strings.TrimSuffix(strings.TrimSuffix(overridePath, ".tar.gz"), ".tar")
 	} else {
 		// use default output path
 		basename = fmt.Sprintf("support-bundle-%s", time.Now().Format("2006-01-02T15_04_05"))
@@ -94,21 +213,31 @@ func CollectSupportBundleFromSpec(
 		}
 	}
 
-	filename, err := findFileName(basename, "tar.gz")
+	filename, err := findFileName(basename, extension)
 	if err != nil {
 		return nil, errors.Wrap(err, "find file name")
 	}
 	resultsResponse.ArchivePath = filename
 
-	bundlePath := filepath.Join(tmpDir, strings.TrimSuffix(filename, ".tar.gz"))
+	bundlePath := filepath.Join(tmpDir, strings.TrimSuffix(filename, "."+extension))
 	if err := os.MkdirAll(bundlePath, 0777); err != nil {
 		return nil, errors.Wrap(err, "create bundle dir")
 	}
 
 	result := make(collect.CollectorResult)
 
+	var profiler *selfProfiler
+	if opts.EmbedProfiles {
+		// Profiling troubleshoot should not fail the bundle
+		profiler, err = startSelfProfiler(tmpDir)
+		if err != nil {
+			opts.ProgressChan <- errors.Wrap(err, "failed to start profiling")
+		}
+		defer profiler.stop()
+	}
+
 	ctx, root := otel.Tracer(constants.LIB_TRACER_NAME).Start(
-		context.Background(), constants.TROUBLESHOOT_ROOT_SPAN_NAME,
+		ctx, constants.TROUBLESHOOT_ROOT_SPAN_NAME,
 	)
 	defer func() {
 		// If this function returns an error, root.End() may not be called.
@@ -121,11 +250,23 @@ func CollectSupportBundleFromSpec(
 	// so as to have a chance to run analyzers and archive the support bundle after.
 	// If both host and in cluster collectors fail, the errors will be wrapped
 	collectorsErrs := []string{}
-	var files, hostFiles collect.CollectorResult
+	var files, hostFiles, customFiles collect.CollectorResult
+	skipped := collect.SkippedCollectors{}
+	opts.bundleIndex = &collect.BundleIndex{}
+	opts.retried = &collect.RetriedCollectors{}
+
+	// The facts phase reads the facts of the cluster the exclude templates of collectors and
+	// analyzers are evaluated against, before anything is collected, when a template references them
+	if spec.Collectors != nil && facts.Referenced(spec.Collectors, spec.Analyzers) {
+		opts.facts, err = gatherFacts(ctx, opts)
+		if err != nil {
+			collectorsErrs = append(collectorsErrs, fmt.Sprintf("failed to read cluster facts: %s", err))
+		}
+	}
 
 	if spec.HostCollectors != nil {
 		// Run host collectors
-		hostFiles, err = runHostCollectors(ctx, spec.HostCollectors, additionalRedactors, bundlePath, opts)
+		hostFiles, err = runHostCollectors(ctx, spec.HostCollectors, additionalRedactors, bundlePath, opts, &skipped)
 		if err != nil {
 			collectorsErrs = append(collectorsErrs, fmt.Sprintf("failed to run host collectors: %s", err))
 		}
@@ -133,13 +274,26 @@ func CollectSupportBundleFromSpec(
 
 	if spec.Collectors != nil {
 		// Run collectors
-		files, err = runCollectors(ctx, spec.Collectors, additionalRedactors, bundlePath, opts)
+		files, err = runCollectors(ctx, spec.Collectors, additionalRedactors, bundlePath, opts, &skipped)
 		if err != nil {
 			collectorsErrs = append(collectorsErrs, fmt.Sprintf("failed to run collectors: %s", err))
 		}
 	}
 
-	// merge in-cluster and host collectors results
+	if len(opts.CustomCollectors) > 0 {
+		customFiles, err = runCustomCollectors(ctx, opts.CustomCollectors, additionalRedactors, bundlePath, opts)
+		if err != nil {
+			collectorsErrs = append(collectorsErrs, fmt.Sprintf("failed to run custom collectors: %s", err))
+		}
+	}
+
+	// the collectors stop and delete what they created in the cluster when the context is done, a
+	// canceled collection is not archived
+	if err := ctx.Err(); err != nil {
+		return nil, errors.Wrap(err, "collection canceled")
+	}
+
+	// merge in-cluster, host and custom collectors results
 	for k, v := range files {
 		result[k] = v
 	}
@@ -148,6 +302,14 @@ func CollectSupportBundleFromSpec(
 		result[k] = v
 	}
 
+	for k, v := range customFiles {
+		result[k] = v
+	}
+
+	if err := truncateToMaxSize(opts, "support bundle", result, bundlePath, maxSize); err != nil {
+		collectorsErrs = append(collectorsErrs, err.Error())
+	}
+
 	if len(result) == 0 {
 		if len(collectorsErrs) > 0 {
 			return nil, fmt.Errorf("failed to generate support bundle: %s", strings.Join(collectorsErrs, "\n"))
@@ -165,8 +327,45 @@ func CollectSupportBundleFromSpec(
 		return nil, errors.Wrap(err, "failed to write version")
 	}
 
-	// Run Analyzers
-	analyzeResults, err := AnalyzeSupportBundle(ctx, spec, bundlePath)
+	if opts.facts != nil {
+		b, err := json.MarshalIndent(opts.facts, "", "  ")
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to marshal cluster facts")
+		}
+		if err := result.SaveResult(bundlePath, constants.FACTS_FILENAME, bytes.NewBuffer(b)); err != nil {
+			return nil, errors.Wrap(err, "failed to write cluster facts")
+		}
+	}
+
+	// Embed the documentation shipped with the spec, so air-gapped users get the guidance outcomes reference
+	for docPath, doc := range docFiles {
+		if err := result.SaveResult(bundlePath, docPath, bytes.NewBuffer(doc)); err != nil {
+			return nil, errors.Wrap(err, "failed to write documentation")
+		}
+	}
+
+	// Record collectors that did not run so analyzers depending on them can report it
+	if err := skipped.SaveResult(result, bundlePath); err != nil {
+		return nil, errors.Wrap(err, "failed to write skipped collectors")
+	}
+
+	// Record the attempts of collectors that failed and were retried
+	if err := opts.retried.SaveResult(result, bundlePath); err != nil {
+		return nil, errors.Wrap(err, "failed to write collector attempts")
+	}
+
+	// Index the collected files so analyzers and other tools don't have to rely on the layout of the bundle
+	if err := opts.bundleIndex.SaveResult(result, bundlePath); err != nil {
+		return nil, errors.Wrap(err, "failed to write bundle index")
+	}
+
+	// Run Analyzers, with the exclude templates evaluated against the facts of the collection
+	analyzeSpec := spec.DeepCopy()
+	analyzeSpec.Analyzers, err = facts.ResolveAnalyzerExcludes(spec.Analyzers, opts.facts)
+	if err != nil {
+		opts.ProgressChan <- err
+	}
+	analyzeResults, err := AnalyzeSupportBundle(ctx, analyzeSpec, bundlePath)
 	if err != nil {
 		if opts.FromCLI {
 			c := color.New(color.FgHiRed)
@@ -178,6 +377,11 @@ func CollectSupportBundleFromSpec(
 	}
 	resultsResponse.AnalyzerResults = analyzeResults
 
+	// Outcomes referencing the documentation point at its files in the bundle
+	if err := analyzer.ResolveDocs(analyzeResults, spec.Documentation); err != nil {
+		opts.ProgressChan <- err
+	}
+
 	analysis, err := getAnalysisFile(analyzeResults)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to get analysis file")
@@ -188,6 +392,28 @@ func CollectSupportBundleFromSpec(
 		return nil, errors.Wrap(err, "failed to write analysis")
 	}
 
+	// Post-collection hooks can add and remove files, so the index is written again after them.
+	// A hook that fails fails the bundle, which may otherwise keep files the spec strips.
+	if len(spec.PostCollection) > 0 {
+		bundle := &PostCollectionBundle{
+			AnalyzerResults: analyzeResults,
+			bundlePath:      bundlePath,
+			result:          result,
+		}
+		if err := runPostCollection(ctx, spec.PostCollection, opts.PostProcessors, bundle); err != nil {
+			return nil, errors.Wrap(err, "failed to run post collection")
+		}
+		if err := opts.bundleIndex.SaveResult(result, bundlePath); err != nil {
+			return nil, errors.Wrap(err, "failed to write bundle index")
+		}
+	}
+
+	if profiler != nil {
+		if err := profiler.save(result, bundlePath); err != nil {
+			klog.Errorf("failed to save profiles in the support bundle: %v", err)
+		}
+	}
+
 	// Complete tracing by ending the root span and collecting
 	// the summary of the traces. Store them in the support bundle.
 	root.End()
@@ -197,12 +423,41 @@ func CollectSupportBundleFromSpec(
 		// Don't fail the support bundle if we can't save the execution summary
 		klog.Errorf("failed to save execution summary file in the support bundle: %v", err)
 	}
+	if opts.EmbedTraces {
+		spans, err := traces.GetExporterInstance().GetSpans()
+		if err == nil {
+			err = result.SaveResult(bundlePath, constants.TRACES_FILENAME, bytes.NewReader(spans))
+		}
+		if err != nil {
+			klog.Errorf("failed to save traces file in the support bundle: %v", err)
+		}
+	}
+
+	// The manifest is written last, with the checksums of the files as they are archived
+	manifest, err := newBundleManifest(result, bundlePath, opts.bundleIndex, spec, opts.CustomCollectors, startedAt)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create bundle manifest")
+	}
+	if err := saveBundleManifest(result, bundlePath, manifest, opts.SigningKey); err != nil {
+		return nil, err
+	}
 
 	// Archive Support Bundle
-	if err := result.ArchiveBundle(bundlePath, filename); err != nil {
+	archive := result.ArchiveBundle
+	if opts.PerFileCompression {
+		archive = result.ArchiveBundleWithCodecs
+	}
+	if err := archive(bundlePath, filename); err != nil {
 		return nil, errors.Wrap(err, "create bundle file")
 	}
 
+	if opts.RedactionTokensPath != "" {
+		if err := writeRedactionTokens(opts.RedactionTokensPath, opts.RedactionTokensPassphrase, opts.redactionTokens.Mapping()); err != nil {
+			return nil, errors.Wrap(err, "failed to write redaction tokens")
+		}
+		resultsResponse.RedactionTokensPath = opts.RedactionTokensPath
+	}
+
 	fileUploaded, err := ProcessSupportBundleAfterCollection(spec, filename)
 	if err != nil {
 		if opts.FromCLI {
@@ -215,6 +470,21 @@ func CollectSupportBundleFromSpec(
 	}
 	resultsResponse.FileUploaded = fileUploaded
 
+	// A notification that cannot be sent doesn't fail the bundle, which has been saved
+	if len(spec.Notifications) > 0 {
+		summary := notify.NewSummary(notify.KindSupportBundle, "", analyzeResults)
+		summary.BundlePath = filename
+		summary.Uploaded = fileUploaded
+		if err := notify.Send(ctx, spec.Notifications, summary); err != nil {
+			if opts.FromCLI {
+				c := color.New(color.FgHiRed)
+				c.Printf("%s\r * %v\n", cursor.ClearEntireLine(), err)
+			} else {
+				klog.Errorf("%v", err)
+			}
+		}
+	}
+
 	if len(collectorsErrs) > 0 {
 		// TODO: Consider a collectors error type
 		// TODO: use errors.Join in go 1.20 (https://pkg.go.dev/errors#Join)
@@ -224,6 +494,22 @@ func CollectSupportBundleFromSpec(
 	return &resultsResponse, nil
 }
 
+// gatherFacts reads the facts of the cluster. Facts that cannot be read, such as the nodes of
+// clusters the collection is scoped to a namespace of, are logged and left empty.
+func gatherFacts(ctx context.Context, opts SupportBundleCreateOpts) (*facts.Facts, error) {
+	client, err := kubernetes.NewForConfig(opts.KubernetesRestConfig)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to instantiate Kubernetes client")
+	}
+
+	opts.CollectorProgressCallback(opts.ProgressChan, "reading cluster facts")
+	clusterFacts, errs := facts.Gather(ctx, client)
+	for _, err := range errs {
+		klog.Warningf("failed to read a cluster fact for exclude templates: %v", err)
+	}
+	return clusterFacts, nil
+}
+
 // CollectSupportBundleFromURI collects support bundle from start to finish, including running
 // collectors, analyzers and after collection steps. Input arguments are the URIs of the support bundle and redactor specs.
 // The support bundle is archived in the OS temp folder (os.TempDir()).
@@ -290,9 +576,22 @@ func ConcatSpec(target *troubleshootv1beta2.SupportBundle, source *troubleshootv
 		newBundle = target.DeepCopy()
 		newBundle.Spec.Collectors = util.Append(target.Spec.Collectors, source.Spec.Collectors)
 		newBundle.Spec.AfterCollection = util.Append(target.Spec.AfterCollection, source.Spec.AfterCollection)
+		newBundle.Spec.Notifications = util.Append(target.Spec.Notifications, source.Spec.Notifications)
+		newBundle.Spec.PostCollection = util.Append(target.Spec.PostCollection, source.Spec.PostCollection)
 		newBundle.Spec.HostCollectors = util.Append(target.Spec.HostCollectors, source.Spec.HostCollectors)
 		newBundle.Spec.HostAnalyzers = util.Append(target.Spec.HostAnalyzers, source.Spec.HostAnalyzers)
 		newBundle.Spec.Analyzers = util.Append(target.Spec.Analyzers, source.Spec.Analyzers)
+		if source.Spec.Scope != "" {
+			newBundle.Spec.Scope = source.Spec.Scope
+		}
+		newBundle.Spec.Namespaces = util.Append(target.Spec.Namespaces, source.Spec.Namespaces)
+		newBundle.Spec.Documentation = util.Append(target.Spec.Documentation, source.Spec.Documentation)
+		if source.Spec.MaxSize != "" {
+			newBundle.Spec.MaxSize = source.Spec.MaxSize
+		}
+		if source.Spec.Profile != "" {
+			newBundle.Spec.Profile = source.Spec.Profile
+		}
 		// TODO: What to do with the Uri field?
 	}
 	return newBundle
@@ -8,6 +8,7 @@ import (
 
 	"github.com/pkg/errors"
 	troubleshootv1beta2 "github.com/replicatedhq/troubleshoot/pkg/apis/troubleshoot/v1beta2"
+	"github.com/replicatedhq/troubleshoot/pkg/constants"
 	"gopkg.in/yaml.v2"
 )
 
@@ -109,6 +110,7 @@ func GetVersionFile() (string, error) {
 		Kind:       "SupportBundle",
 		Spec: troubleshootv1beta2.SupportBundleVersionSpec{
 			VersionNumber: Version(),
+			LayoutVersion: constants.BUNDLE_LAYOUT_VERSION,
 		},
 	}
 	b, err := yaml.Marshal(version)
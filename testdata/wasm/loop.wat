@@ -0,0 +1,5 @@
+;; Never returns. Build with: wat2wasm loop.wat
+(module
+  (memory (export "memory") 1)
+  (func (export "_start")
+    (loop (br 0))))
@@ -0,0 +1,9 @@
+;; Writes a fixed analyzer response to stdout. Build with: wat2wasm result.wat
+(module
+  (import "wasi_snapshot_preview1" "fd_write" (func $fd_write (param i32 i32 i32 i32) (result i32)))
+  (memory (export "memory") 1)
+  (data (i32.const 16) "{\"results\":[{\"title\":\"Queue depth\",\"isWarn\":true,\"message\":\"Queue is filling up\"}]}")
+  (func (export "_start")
+    (i32.store (i32.const 0) (i32.const 16))
+    (i32.store (i32.const 4) (i32.const 83))
+    (drop (call $fd_write (i32.const 1) (i32.const 0) (i32.const 1) (i32.const 8)))))